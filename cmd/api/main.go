@@ -11,54 +11,111 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
-	swaggerFiles "github.com/swaggo/files"
-	ginSwagger "github.com/swaggo/gin-swagger"
+	"gorm.io/gorm"
 
+	"github.com/umar5678/go-backend/internal/apidocs"
 	"github.com/umar5678/go-backend/internal/config"
 	"github.com/umar5678/go-backend/internal/database"
 	_ "github.com/umar5678/go-backend/internal/docs"
+	"github.com/umar5678/go-backend/internal/jobs"
 	"github.com/umar5678/go-backend/internal/middleware"
+	"github.com/umar5678/go-backend/internal/modules/addresses"
 	"github.com/umar5678/go-backend/internal/modules/admin"
 	"github.com/umar5678/go-backend/internal/modules/admin_support_chat"
+	"github.com/umar5678/go-backend/internal/modules/announcements"
+	"github.com/umar5678/go-backend/internal/modules/audit"
 	"github.com/umar5678/go-backend/internal/modules/auth"
 	"github.com/umar5678/go-backend/internal/modules/batching"
+	"github.com/umar5678/go-backend/internal/modules/calling"
+	"github.com/umar5678/go-backend/internal/modules/cancellation"
+	"github.com/umar5678/go-backend/internal/modules/catalog"
+	"github.com/umar5678/go-backend/internal/modules/cities"
+	"github.com/umar5678/go-backend/internal/modules/commissions"
+	"github.com/umar5678/go-backend/internal/modules/currency"
+	"github.com/umar5678/go-backend/internal/modules/disputes"
 	"github.com/umar5678/go-backend/internal/modules/documents"
 	"github.com/umar5678/go-backend/internal/modules/drivers"
+	"github.com/umar5678/go-backend/internal/modules/emails"
+	"github.com/umar5678/go-backend/internal/modules/eventlog"
+	"github.com/umar5678/go-backend/internal/modules/favorites"
+	"github.com/umar5678/go-backend/internal/modules/featureflags"
 	"github.com/umar5678/go-backend/internal/modules/fraud"
 	"github.com/umar5678/go-backend/internal/modules/homeservices"
 	homeservicesAdmin "github.com/umar5678/go-backend/internal/modules/homeservices/admin"
 	homeservicesCustomer "github.com/umar5678/go-backend/internal/modules/homeservices/customer"
 	_ "github.com/umar5678/go-backend/internal/modules/homeservices/dto"
 	homeservicesProvider "github.com/umar5678/go-backend/internal/modules/homeservices/provider"
+	"github.com/umar5678/go-backend/internal/modules/incentives"
+	"github.com/umar5678/go-backend/internal/modules/insurance"
 	"github.com/umar5678/go-backend/internal/modules/laundry"
+	"github.com/umar5678/go-backend/internal/modules/lostfound"
+	"github.com/umar5678/go-backend/internal/modules/loyalty"
+	"github.com/umar5678/go-backend/internal/modules/media"
 	"github.com/umar5678/go-backend/internal/modules/messages"
 	"github.com/umar5678/go-backend/internal/modules/notifications"
 	notificationcontroller "github.com/umar5678/go-backend/internal/modules/notifications/controller"
+	"github.com/umar5678/go-backend/internal/modules/ordertimeline"
+	"github.com/umar5678/go-backend/internal/modules/partners"
+	"github.com/umar5678/go-backend/internal/modules/payments"
+	"github.com/umar5678/go-backend/internal/modules/places"
 	"github.com/umar5678/go-backend/internal/modules/pricing"
+	"github.com/umar5678/go-backend/internal/modules/privacy"
 	"github.com/umar5678/go-backend/internal/modules/profile"
 	"github.com/umar5678/go-backend/internal/modules/promotions"
+	"github.com/umar5678/go-backend/internal/modules/proofs"
 	"github.com/umar5678/go-backend/internal/modules/ratings"
+	"github.com/umar5678/go-backend/internal/modules/receipts"
+	"github.com/umar5678/go-backend/internal/modules/referrals"
+	"github.com/umar5678/go-backend/internal/modules/rideanalytics"
 	"github.com/umar5678/go-backend/internal/modules/ridepin"
 	"github.com/umar5678/go-backend/internal/modules/riders"
 	"github.com/umar5678/go-backend/internal/modules/rides"
 	"github.com/umar5678/go-backend/internal/modules/serviceproviders"
+	"github.com/umar5678/go-backend/internal/modules/settlements"
 	"github.com/umar5678/go-backend/internal/modules/sos"
+	"github.com/umar5678/go-backend/internal/modules/tasks"
 
 	"github.com/umar5678/go-backend/internal/modules/tracking"
 	"github.com/umar5678/go-backend/internal/modules/vehicles"
 	_ "github.com/umar5678/go-backend/internal/modules/vehicles/dto"
 	"github.com/umar5678/go-backend/internal/modules/wallet"
+	"github.com/umar5678/go-backend/internal/modules/webhooks"
 	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/services/captcha"
+	"github.com/umar5678/go-backend/internal/services/eventbus"
+	"github.com/umar5678/go-backend/internal/services/geocoding"
+	"github.com/umar5678/go-backend/internal/services/health"
+	"github.com/umar5678/go-backend/internal/services/i18n"
+	"github.com/umar5678/go-backend/internal/services/routing"
+	"github.com/umar5678/go-backend/internal/services/storage"
+	"github.com/umar5678/go-backend/internal/services/tracing"
+	"github.com/umar5678/go-backend/internal/startup"
 	"github.com/umar5678/go-backend/internal/utils/logger"
 	"github.com/umar5678/go-backend/internal/websocket"
+	"github.com/umar5678/go-backend/migrations"
 
 	"github.com/umar5678/go-backend/internal/websocket/handlers"
 	"github.com/umar5678/go-backend/internal/websocket/websocketutils"
 )
 
+const (
+	// wsDrainTimeout bounds how long shutdown waits for websocket clients to
+	// receive their reconnect notice before the connections are closed.
+	wsDrainTimeout = 5 * time.Second
+	// dispatchOverdueAfter is how long a due ride may sit unclaimed in the
+	// dispatch queue before the dispatch check reports degraded.
+	dispatchOverdueAfter = 60 * time.Second
+)
+
 // @title supr booking server in go
 // @version 1.0
 // @description Go backend API
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @securityDefinitions.apikey PartnerAPIKey
+// @in header
+// @name X-API-Key
 
 func main() {
 	_ = godotenv.Load()
@@ -86,90 +143,249 @@ func main() {
 		"version", cfg.App.Version,
 	)
 
-	db, err := database.ConnectPostgres(&cfg.Database)
-	if err != nil {
-		logger.Fatal("failed to connect to database", "error", err)
-	}
-	defer database.Close(db)
+	var (
+		db                 *gorm.DB
+		replicas           *database.ReplicaPlugin
+		wsManager          *websocket.Manager
+		wsServer           *websocket.Server
+		notificationSystem *notifications.NotificationSystem
+		stopTracing        func(context.Context) error
+	)
 
-	if err := cache.ConnectRedis(&cfg.Redis); err != nil {
-		logger.Fatal("failed to connect to redis", "error", err)
-	}
-	defer cache.CloseRedis()
-
-	wsConfig := &websocket.Config{
-		JWTSecret:           cfg.JWT.Secret,
-		MaxConnections:      cfg.WebSocket.MaxConnections,
-		MessageBufferSize:   cfg.WebSocket.MessageBufferSize,
-		HeartbeatInterval:   cfg.WebSocket.PingPeriod,
-		ConnectionTimeout:   cfg.WebSocket.PongWait,
-		EnablePresence:      cfg.WebSocket.EnablePresence,
-		EnableMessageStore:  cfg.WebSocket.EnableMessageStore,
-		PersistenceEnabled:  cfg.WebSocket.PersistenceEnabled,
-		PersistenceMode:     cfg.WebSocket.PersistenceMode,
-		RDBSnapshotInterval: cfg.WebSocket.RDBSnapshotInterval,
-		AOFSyncPolicy:       cfg.WebSocket.AOFSyncPolicy,
-	}
+	orchestrator := startup.NewOrchestrator(cfg.Startup)
 
-	wsManager := websocket.NewManager(wsConfig, db)
-	wsServer := websocket.NewServer(wsManager)
+	orchestrator.Add(startup.Component{
+		Name: "tracing",
+		Start: func(ctx context.Context) error {
+			shutdownTracing, err := tracing.Initialize(&cfg.Tracing, &cfg.App)
+			if err != nil {
+				return err
+			}
+			stopTracing = shutdownTracing
+			return nil
+		},
+		Stop: func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			return stopTracing(ctx)
+		},
+	})
 
-	handlers.RegisterAllHandlers(wsManager)
+	orchestrator.Add(startup.Component{
+		Name:     "database",
+		Required: true,
+		Retry:    true,
+		Start: func(ctx context.Context) error {
+			conn, err := database.ConnectPostgres(&cfg.Database)
+			if err != nil {
+				return err
+			}
+			if cfg.Tracing.Enabled {
+				if err := conn.Use(database.NewTracingPlugin()); err != nil {
+					database.Close(conn)
+					return fmt.Errorf("register database tracing: %w", err)
+				}
+			}
+			if err := conn.Use(audit.NewMoneyMovementPlugin()); err != nil {
+				database.Close(conn)
+				return fmt.Errorf("register money movement audit: %w", err)
+			}
+			if err := conn.Use(webhooks.NewWalletTransactionPlugin()); err != nil {
+				database.Close(conn)
+				return fmt.Errorf("register wallet transaction webhooks: %w", err)
+			}
+			if len(cfg.Database.ReplicaDSNs) > 0 {
+				replicas = database.NewReplicaPlugin(&cfg.Database)
+				if err := conn.Use(replicas); err != nil {
+					replicas.Close()
+					database.Close(conn)
+					return fmt.Errorf("register database replicas: %w", err)
+				}
+			}
+			db = conn
+			i18n.Initialize(conn)
+			return nil
+		},
+		Stop: func() error {
+			if replicas != nil {
+				replicas.Close()
+			}
+			database.Close(db)
+			return nil
+		},
+	})
 
-	if err := wsManager.Start(); err != nil {
-		logger.Fatal("failed to start websocket manager", "error", err)
+	if cfg.Database.SchemaCheck {
+		orchestrator.Add(startup.Component{
+			Name:      "schema_check",
+			DependsOn: []string{"database"},
+			Required:  true,
+			Start: func(ctx context.Context) error {
+				sqlDB, err := db.DB()
+				if err != nil {
+					return err
+				}
+				migrator, err := database.NewMigrator(sqlDB, migrations.FS)
+				if err != nil {
+					return err
+				}
+				return migrator.CheckSchema(ctx)
+			},
+		})
 	}
 
-	logger.Info("websocket system initialized successfully")
+	orchestrator.Add(startup.Component{
+		Name:     "redis",
+		Required: true,
+		Retry:    true,
+		Start: func(ctx context.Context) error {
+			if err := cache.ConnectRedis(&cfg.Redis); err != nil {
+				cache.CloseRedis()
+				return err
+			}
+			if cfg.Tracing.Enabled {
+				cache.EnableTracing()
+			}
+			return nil
+		},
+		Stop: func() error {
+			cache.CloseRedis()
+			return nil
+		},
+	})
 
-	notificationSystem, err := notifications.NewNotificationSystem(
-		context.Background(),
-		db,
-		cfg.Kafka,
-		cfg.Firebase.CredentialsFile,
-		cfg.Firebase,
-		wsManager,
-	)
-	if err != nil {
-		logger.Fatal("failed to initialize notification system", "error", err)
-	}
-	defer func() {
-		if err := notificationSystem.Stop(); err != nil {
-			logger.Error("error stopping notification system", "error", err)
-		}
-	}()
+	orchestrator.Add(startup.Component{
+		Name:      "websocket",
+		DependsOn: []string{"database", "redis"},
+		Required:  true,
+		Start: func(ctx context.Context) error {
+			wsConfig := &websocket.Config{
+				JWTSecret:              cfg.JWT.Secret,
+				MaxConnections:         cfg.WebSocket.MaxConnections,
+				MessageBufferSize:      cfg.WebSocket.MessageBufferSize,
+				HeartbeatInterval:      cfg.WebSocket.PingPeriod,
+				ConnectionTimeout:      cfg.WebSocket.PongWait,
+				EnablePresence:         cfg.WebSocket.EnablePresence,
+				EnableMessageStore:     cfg.WebSocket.EnableMessageStore,
+				PersistenceEnabled:     cfg.WebSocket.PersistenceEnabled,
+				PersistenceMode:        cfg.WebSocket.PersistenceMode,
+				RDBSnapshotInterval:    cfg.WebSocket.RDBSnapshotInterval,
+				AOFSyncPolicy:          cfg.WebSocket.AOFSyncPolicy,
+				ResumeTicketTTL:        cfg.WebSocket.ResumeTicketTTL,
+				AuditEnabled:           cfg.WebSocket.AuditEnabled,
+				AuditSampleRate:        cfg.WebSocket.AuditSampleRate,
+				AuditMaxEntries:        cfg.WebSocket.AuditMaxEntries,
+				AuditTTL:               cfg.WebSocket.AuditTTL,
+				AuditRedactFields:      cfg.WebSocket.AuditRedactFields,
+				OfflineQueueEnabled:    cfg.WebSocket.OfflineQueueEnabled,
+				OfflineQueueTTL:        cfg.WebSocket.OfflineQueueTTL,
+				OfflineQueueMaxPerUser: cfg.WebSocket.OfflineQueueMaxPerUser,
+			}
 
-	if err := notificationSystem.Start(context.Background()); err != nil {
-		logger.Fatal("failed to start notification system", "error", err)
-	}
+			wsManager = websocket.NewManager(wsConfig, db)
+			wsServer = websocket.NewServer(wsManager)
 
-	logger.Info("notification system initialized and started successfully")
+			if err := wsManager.Start(); err != nil {
+				return err
+			}
+			logger.Info("websocket system initialized successfully")
+			return nil
+		},
+	})
 
-	orderExpirationService := homeservices.NewOrderExpirationService(db)
-	go func() {
-		ticker := time.NewTicker(1 * time.Minute)
-		defer ticker.Stop()
+	orchestrator.Add(startup.Component{
+		Name:      "websocket_handlers",
+		DependsOn: []string{"websocket"},
+		Start: func(ctx context.Context) error {
+			return handlers.RegisterAllHandlers(wsManager)
+		},
+	})
 
-		for range ticker.C {
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-			if err := orderExpirationService.ExpireUnacceptedOrders(ctx); err != nil {
-				logger.Error("order expiration job failed", "error", err)
+	orchestrator.Add(startup.Component{
+		Name:      "notifications",
+		DependsOn: []string{"database", "websocket"},
+		Required:  true,
+		Retry:     true,
+		Start: func(ctx context.Context) error {
+			system, err := notifications.NewNotificationSystem(
+				ctx,
+				db,
+				cfg.Kafka,
+				cfg.Firebase.CredentialsFile,
+				cfg.Firebase,
+				wsManager,
+			)
+			if err != nil {
+				return err
 			}
-			cancel()
-		}
-	}()
+			if cfg.Worker.RunJobsInAPI {
+				if err := system.Start(ctx); err != nil {
+					system.Stop()
+					return err
+				}
+			}
+			notificationSystem = system
+			logger.Info("notification system initialized and started successfully")
+			return nil
+		},
+		Stop: func() error {
+			return notificationSystem.Stop()
+		},
+	})
+
+	if cfg.Worker.RunJobsInAPI {
+		orchestrator.Add(startup.Component{
+			Name:      "maintenance_jobs",
+			DependsOn: []string{"database"},
+			Start: func(ctx context.Context) error {
+				publisher := webhooks.NewService(webhooks.NewRepository(db), cfg.Webhooks)
+				return jobs.StartMaintenance(context.Background(), db, cfg, publisher)
+			},
+		})
+	}
+
+	if err := orchestrator.Run(context.Background()); err != nil {
+		orchestrator.Shutdown()
+		logger.Fatal("startup failed", "error", err)
+	}
+	defer orchestrator.Shutdown()
+
+	eventBus, err := eventbus.New(cfg.EventBus, cfg.Kafka)
+	if err != nil {
+		logger.Fatal("failed to create event bus", "error", err)
+	}
 
-	logger.Info("order expiration job started")
+	var ridesService rides.Service
+
+	checker := health.NewChecker(orchestrator)
+	checker.Add(health.Database(db))
+	checker.Add(health.Redis())
+	if wsManager != nil {
+		checker.Add(health.Check{Name: "websocket", Critical: true, Run: wsManager.HealthCheck})
+	}
+	checker.Add(health.Heartbeats())
+	checker.Add(health.Check{Name: "ride_dispatch", Run: func(ctx context.Context) (interface{}, error) {
+		return dispatchHealth(ctx, ridesService)
+	}})
+	if !cfg.Worker.RunJobsInAPI {
+		checker.Add(health.Processes("worker", true))
+	}
 
 	if cfg.App.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
+	auditService := audit.NewService(audit.NewRepository(db), cfg.AuditLog)
+
 	router := gin.New()
 
 	router.Use(middleware.RequestContext(cfg.App.Version))
+	router.Use(middleware.Locale())
+	router.Use(middleware.Tracing())
 	router.Use(middleware.Logger())
 	router.Use(middleware.Recovery())
+	// Outside the error handler so it records the status actually returned.
+	router.Use(admin.AuditImpersonation(auditService))
 	router.Use(middleware.ErrorHandler())
 	router.Use(middleware.CORS(cfg.Server.CORS))
 
@@ -177,37 +393,91 @@ func main() {
 		router.Use(middleware.DevelopmentLogger())
 	}
 
-	router.GET("/health", healthCheck)
-	router.GET("/ready", readyCheck(db))
+	// /live is the liveness probe and /ready the readiness probe; /health is
+	// kept as an alias of /live for existing monitors.
+	router.GET("/live", gin.WrapH(checker.LiveHandler()))
+	router.GET("/health", gin.WrapH(checker.LiveHandler()))
+	router.GET("/ready", gin.WrapH(checker.ReadyHandler()))
+	router.GET("/health/details", gin.WrapH(checker.DetailsHandler()))
+
+	// Registered after the health checks so probes are never throttled.
+	router.Use(middleware.RateLimit(cfg))
 
 	v1 := router.Group("/api/v1")
 	{
-		v1.Use(middleware.RateLimit(cfg.Server.RateLimit))
-
 		ridersRepo := riders.NewRepository(db)
 		ridersService := riders.NewServiceWithNotifications(ridersRepo, notificationSystem.GetProducer())
 		ridersHandler := riders.NewHandler(ridersService)
 
 		spRepo := serviceproviders.NewRepository(db)
-		spService := serviceproviders.NewService(spRepo)
+		spService := serviceproviders.NewServiceWithNotifications(spRepo, notificationSystem.GetProducer())
+
+		emailsService := emails.NewService(emails.NewRepository(db), cfg.Email)
+		emailsService.SetPreferenceChecker(notificationSystem.GetPreferenceService())
 
 		authRepo := auth.NewRepository(db)
 		authService := auth.NewServiceWithNotifications(authRepo, cfg, ridersService, spService, notificationSystem.GetProducer())
+		authService.SetMailer(emailsService)
 		authHandler := auth.NewHandler(authService)
 		authMiddleware := middleware.Auth(cfg)
 		auth.RegisterRoutes(v1, authHandler, authMiddleware)
+		emails.RegisterRoutes(v1, emails.NewHandler(emailsService), authMiddleware)
 
 		riders.RegisterRoutes(v1, ridersHandler, authMiddleware)
 
+		currencyRepo := currency.NewRepository(db)
+		currencyService := currency.NewService(currencyRepo, cfg.Currency)
+		currency.NewRateRefresher(currencyService, cfg.Currency).Start(context.Background())
+		currencyHandler := currency.NewHandler(currencyService)
+		currency.RegisterRoutes(v1, currencyHandler, authMiddleware)
+
+		auditHandler := audit.NewHandler(auditService)
+		audit.RegisterRoutes(v1, auditHandler, authMiddleware)
+
+		featureFlagsService := featureflags.NewService(featureflags.NewRepository(db))
+		featureFlagsService.SetAuditLogger(auditService)
+		featureflags.NewWatcher(featureFlagsService, cfg.FeatureFlags).Start(context.Background())
+		featureFlagsHandler := featureflags.NewHandler(featureFlagsService)
+		featureflags.RegisterRoutes(v1, featureFlagsHandler, authMiddleware)
+
+		webhooksService := webhooks.NewService(webhooks.NewRepository(db), cfg.Webhooks)
+		webhooksService.SetAuditLogger(auditService)
+		webhooksHandler := webhooks.NewHandler(webhooksService)
+		webhooks.RegisterRoutes(v1, webhooksHandler, authMiddleware)
+
 		walletRepo := wallet.NewRepository(db)
 		walletService := wallet.NewServiceWithNotifications(walletRepo, db, notificationSystem.GetProducer())
+		walletService.ConfigurePayoutRetry(cfg.PayoutRetry)
+		walletService.SetCurrencies(currencyService)
+		walletService.SetAuditLogger(auditService)
+		walletService.ConfigureDriverCredit(cfg.DriverCredit)
+		if err := walletService.SyncDriverCreditLimits(context.Background()); err != nil {
+			logger.Error("failed to sync driver credit limits", "error", err)
+		}
 		walletHandler := wallet.NewHandler(walletService)
 		wallet.RegisterRoutes(v1, walletHandler, authMiddleware)
 
+		settlementsService := settlements.NewService(settlements.NewRepository(db), walletService, cfg.Settlement)
+		settlementsService.SetAuditLogger(auditService)
+		settlementsService.SetMailer(emailsService)
+		if cfg.Settlement.Enabled {
+			walletService.SetPayoutAccruer(settlementsService)
+		}
+		settlementsHandler := settlements.NewHandler(settlementsService)
+		settlements.RegisterRoutes(v1, settlementsHandler, authMiddleware)
+
+		if cfg.Payments.Enabled {
+			paymentsRepo := payments.NewRepository(db)
+			paymentsService := payments.NewService(paymentsRepo, payments.NewStripeGateway(cfg.Payments), cfg.Payments, notificationSystem.GetProducer())
+			paymentsHandler := payments.NewHandler(paymentsService)
+			payments.RegisterRoutes(v1, paymentsHandler, authMiddleware)
+		}
+
 		vehiclesRepo := vehicles.NewRepository(db)
 		vehiclesService := vehicles.NewServiceWithNotifications(vehiclesRepo, notificationSystem.GetProducer())
+		vehiclesService.ConfigureInspections(cfg)
 		vehiclesHandler := vehicles.NewHandler(vehiclesService)
-		vehicles.RegisterRoutes(v1, vehiclesHandler)
+		vehicles.RegisterRoutes(v1, vehiclesHandler, authMiddleware)
 
 		driversRepo := drivers.NewRepository(db)
 		driversService := drivers.NewServiceWithNotifications(driversRepo, walletService, db, notificationSystem.GetProducer())
@@ -216,24 +486,82 @@ func main() {
 
 		trackingRepo := tracking.NewRepository(db)
 		trackingService := tracking.NewServiceWithNotifications(trackingRepo, notificationSystem.GetProducer())
+		trackingService.ConfigureRatings(cfg.Ratings)
+		trackingService.ConfigureArrival(cfg.Arrival)
 		trackingHandler := tracking.NewHandler(trackingService)
 		tracking.RegisterRoutes(v1, trackingHandler, authMiddleware)
 
+		pricing.ConfigureFeeDisplay(cfg.Fees)
+		pricing.ConfigureFareLock(cfg.FareLock)
 		pricingRepo := pricing.NewRepository(db)
+		routingService := routing.NewService(cfg.Routing)
+		geocodingService := geocoding.NewService(cfg.Geocoding)
 		pricingService := pricing.NewServiceWithNotifications(pricingRepo, db, vehiclesRepo, notificationSystem.GetProducer())
+		pricingService.SetRouter(routingService)
+		pricingService.SetCurrencies(currencyService)
+
+		citiesService := cities.NewService(cities.NewRepository(db), vehiclesRepo)
+		citiesHandler := cities.NewHandler(citiesService)
+		cities.RegisterRoutes(v1, citiesHandler, authMiddleware)
+		pricingService.SetCities(citiesService)
+
+		commissionsService := commissions.NewService(commissions.NewRepository(db))
+		commissionsService.SetServiceAreas(citiesService)
+		commissionsService.SetAuditLogger(auditService)
+		commissionsHandler := commissions.NewHandler(commissionsService)
+		commissions.RegisterRoutes(v1, commissionsHandler, authMiddleware)
+		pricingService.SetCommissionRates(commissionsService)
+
+		rideAnalyticsHandler := rideanalytics.NewHandler(rideanalytics.NewService(rideanalytics.NewRepository(db)))
+		rideanalytics.RegisterRoutes(v1, rideAnalyticsHandler, authMiddleware)
+		pricingService.SetFeatureFlags(featureFlagsService)
+
 		pricingHandler := pricing.NewHandler(pricingService)
 		pricing.RegisterRoutes(v1, pricingHandler, authMiddleware)
 
+		if cfg.PublicEstimate.Enabled {
+			pricing.ConfigurePublicEstimate(cfg.PublicEstimate)
+			pricing.RegisterPublicRoutes(v1, pricingHandler,
+				middleware.PublicAPIKey(cfg.PublicEstimate.APIKeys),
+				middleware.RateLimitPerMinute(func(c *gin.Context) string { return c.ClientIP() }, cfg.PublicEstimate.IPRequestsPerMinute),
+				middleware.RateLimitPerMinute(middleware.PublicAPIKeyFromContext, cfg.PublicEstimate.KeyRequestsPerMinute),
+				middleware.Captcha(captcha.NewVerifier(cfg.PublicEstimate.CaptchaSecret, cfg.PublicEstimate.CaptchaVerifyURL)),
+			)
+		}
+
 		adminRepo := admin.NewRepository(db)
 		adminService := admin.NewServiceWithNotifications(adminRepo, spRepo, driversRepo, notificationSystem.GetProducer())
+		adminService.SetAuditLogger(auditService)
+		if cfg.Impersonation.Enabled {
+			adminService.ConfigureImpersonation(cfg.JWT, cfg.Impersonation)
+		}
+		adminService.SetWalletHolds(walletService)
+		adminService.SetWebhookPublisher(webhooksService)
+		adminService.SetMailer(emailsService)
 		adminHandler := admin.NewHandler(adminService)
 		admin.RegisterRoutes(v1, adminHandler, authMiddleware)
+		admin.NewLiveMetricsStreamer(adminRepo).Start(context.Background())
+		admin.NewSuspensionSweeper(adminService).Start(context.Background())
 
 		profileRepo := profile.NewRepository(db)
 		profileService := profile.NewServiceWithNotifications(profileRepo, walletService, notificationSystem.GetProducer())
 		profileHandler := profile.NewHandler(profileService)
 		profile.RegisterRoutes(v1, profileHandler, authMiddleware)
 
+		referralsService := referrals.NewService(referrals.NewRepository(db), walletService, cfg.Referrals)
+		authService.SetReferrals(referralsService)
+		profileService.SetReferrals(referralsService)
+		referralsHandler := referrals.NewHandler(referralsService)
+		referrals.RegisterRoutes(v1, referralsHandler, authMiddleware)
+
+		loyaltyService := loyalty.NewService(loyalty.NewRepository(db), walletService, cfg.Loyalty)
+		loyaltyHandler := loyalty.NewHandler(loyaltyService)
+		loyalty.RegisterRoutes(v1, loyaltyHandler, authMiddleware)
+
+		incentivesService := incentives.NewService(incentives.NewRepository(db), walletService, cfg.Incentives)
+		incentivesHandler := incentives.NewHandler(incentivesService)
+		incentives.RegisterRoutes(v1, incentivesHandler, authMiddleware)
+
 		promotionsRepo := promotions.NewRepository(db)
 		promotionsService := promotions.NewServiceWithNotifications(promotionsRepo, notificationSystem.GetProducer())
 		promotionsHandler := promotions.NewHandler(promotionsService)
@@ -246,6 +574,9 @@ func main() {
 
 		sosRepo := sos.NewRepository(db)
 		sosService := sos.NewServiceWithNotifications(sosRepo, db, notificationSystem.GetProducer())
+		if smsSender := sos.NewSMSSender(cfg.Safety); smsSender != nil {
+			sosService.SetSMSSender(smsSender, cfg.Safety.SafetyTeamPhones)
+		}
 		sosHandler := sos.NewHandler(sosService)
 		sos.RegisterRoutes(v1, sosHandler, authMiddleware)
 
@@ -262,20 +593,43 @@ func main() {
 		notifController := notificationcontroller.NewNotificationController(
 			notificationSystem.GetNotificationService(),
 			notificationSystem.GetPushService(),
+			notificationSystem.GetPreferenceService(),
 			cfg,
 		)
 		notifController.RegisterRoutes(v1, authMiddleware)
 
 		homeServicesRepo := homeservices.NewRepository(db)
 		homeServicesService := homeservices.NewServiceWithNotifications(homeServicesRepo, walletService, cfg, notificationSystem.GetProducer())
+		homeServicesService.SetEventPublisher(eventBus)
+		proofsService := proofs.NewService(proofs.NewRepository(db))
+		homeServicesService.SetProofChecker(proofsService)
+		homeServicesService.SetLocationSharing(trackingService)
 		homeServicesHandler := homeservices.NewHandler(homeServicesService)
 		homeservices.RegisterRoutes(v1, homeServicesHandler, authMiddleware)
 
 		ratingsRepo := ratings.NewRepository(db)
 		ratingsService := ratings.NewService(ratingsRepo, db, homeServicesRepo)
+		ratingsService.ConfigureRideRatings(cfg.Ratings)
 		ratingsHandler := ratings.NewHandler(ratingsService)
 		ratings.RegisterRoutes(v1, ratingsHandler, authMiddleware)
 
+		if cfg.Worker.RunJobsInAPI {
+			webhooks.NewWorker(webhooksService, cfg.Webhooks).Start(context.Background())
+			wallet.NewPayoutRetryWorker(walletService, cfg.PayoutRetry).Start(context.Background())
+			if cfg.Settlement.Enabled {
+				settlements.NewWorker(settlementsService, cfg.Settlement).Start(context.Background())
+			}
+			wallet.NewHoldExpirySweeper(walletService, cfg.Worker.HoldExpiryInterval).Start(context.Background())
+			wallet.NewLedgerReconciler(walletService, cfg.Worker.LedgerReconcileInterval).Start(context.Background())
+			vehicles.NewInspectionSweeper(vehiclesService, cfg.Inspections).Start(context.Background())
+			ratings.NewRatingReconciler(ratingsService, cfg.Ratings).Start(context.Background())
+			loyalty.NewSweeper(loyaltyService, cfg.Loyalty).Start(context.Background())
+			incentives.NewSweeper(incentivesService, cfg.Incentives).Start(context.Background())
+			if err := jobs.Subscribe(eventBus, webhooksService, homeServicesService, referralsService, loyaltyService, incentivesService); err != nil {
+				logger.Fatal("failed to subscribe background jobs", "error", err)
+			}
+		}
+
 		batchingService := batching.NewService(
 			driversRepo,
 			ratingsService,
@@ -283,9 +637,36 @@ func main() {
 			10*time.Second,
 			20,
 		)
+		batchingService.SetMatchingRadii(citiesService)
+
+		favoritesService := favorites.NewService(favorites.NewRepository(db), cfg.Favorites)
+		favoritesHandler := favorites.NewHandler(favoritesService)
+		favorites.RegisterRoutes(v1, favoritesHandler, authMiddleware)
+		batchingService.SetFavoriteDrivers(favoritesService)
+
+		addressesService := addresses.NewService(addresses.NewRepository(db))
+		addressesHandler := addresses.NewHandler(addressesService)
+		addresses.RegisterRoutes(v1, addressesHandler, authMiddleware)
+		if geocodingService.Enabled() {
+			addressesService.SetGeocoder(geocodingService)
+		}
+
+		placesHandler := places.NewHandler(places.NewService(geocodingService))
+		places.RegisterRoutes(v1, placesHandler, authMiddleware, cfg.Geocoding.UserRequestsPerMinute)
+
+		eventLogService := eventlog.NewService(eventlog.NewRepository(db))
+		eventLogHandler := eventlog.NewHandler(eventLogService)
+		eventlog.RegisterRoutes(v1, eventLogHandler, authMiddleware)
+		if wsManager != nil {
+			wsManager.Hub().SetEventRecorder(eventLogService)
+		}
+
+		cancellationService := cancellation.NewService(cancellation.NewRepository(db))
+		cancellationHandler := cancellation.NewHandler(cancellationService)
+		cancellation.RegisterRoutes(v1, cancellationHandler, authMiddleware)
 
 		ridesRepo := rides.NewRepository(db)
-		ridesService := rides.NewServiceWithNotifications(
+		ridesService = rides.NewServiceWithNotifications(
 			ridesRepo,
 			driversRepo,
 			ridersRepo,
@@ -302,13 +683,98 @@ func main() {
 			adminRepo,
 			notificationSystem.GetProducer(),
 		)
+		ridesService.SetRouter(routingService)
+		ridesService.ConfigureTripVerification(cfg.TripCheck)
+		ridesService.ConfigureTripSharing(cfg.TripSharing)
+		ridesService.ConfigureDispatch(cfg.Dispatch)
+		ridesService.ConfigureFareLock(cfg.FareLock)
+		ridesService.SetCancellationPolicies(cancellationService)
+		ridesService.SetServiceAreas(citiesService)
+		ridesService.SetFavoriteDrivers(favoritesService)
+		ridesService.SetLoyalty(loyaltyService)
+		ridesService.SetAddressBook(addressesService)
+		ridesService.SetAddressNormalizer(geocodingService)
+		ridesService.SetEventPublisher(eventBus)
+		ridesService.SetFeatureFlags(featureFlagsService)
 		ridesHandler := rides.NewHandler(ridesService)
 		rides.RegisterRoutes(v1, ridesHandler, authMiddleware)
+		adminService.SetRideMatcher(ridesService)
+
+		lostFoundRepo := lostfound.NewRepository(db)
+		lostFoundService := lostfound.NewServiceWithNotifications(lostFoundRepo, ridesRepo, walletService, notificationSystem.GetProducer())
+		lostFoundHandler := lostfound.NewHandler(lostFoundService)
+		lostfound.RegisterRoutes(v1, lostFoundHandler, authMiddleware)
+
+		disputesRepo := disputes.NewRepository(db)
+		disputesService := disputes.NewServiceWithNotifications(disputesRepo, walletService, notificationSystem.GetProducer())
+		disputesService.SetAuditLogger(auditService)
+		disputesService.SetMailer(emailsService)
+		disputesHandler := disputes.NewHandler(disputesService)
+		disputes.RegisterRoutes(v1, disputesHandler, authMiddleware)
+
+		tasksService := tasks.NewServiceWithNotifications(tasks.NewRepository(db), notificationSystem.GetProducer())
+		tasksHandler := tasks.NewHandler(tasksService)
+		tasks.RegisterRoutes(v1, tasksHandler, authMiddleware)
+
+		announcementsService := announcements.NewService(announcements.NewRepository(db))
+		announcementsService.SetCityResolver(citiesService)
+		announcementsHandler := announcements.NewHandler(announcementsService)
+		announcements.RegisterRoutes(v1, announcementsHandler, authMiddleware)
+		announcements.NewPublisher(announcementsService).Start(context.Background())
+
+		if cfg.Insurance.Enabled {
+			insuranceRepo := insurance.NewRepository(db)
+			insuranceService := insurance.NewService(insuranceRepo, insurance.NewProvider(cfg.Insurance), cfg.Insurance)
+			insuranceHandler := insurance.NewHandler(insuranceService)
+			insurance.RegisterRoutes(v1, insuranceHandler, authMiddleware)
+			ridesService.SetInsurer(insuranceService)
+		}
+
+		if cfg.MaskedCalling.Enabled {
+			callingService := calling.NewService(calling.NewRepository(db), calling.NewProvider(cfg.MaskedCalling), cfg.MaskedCalling)
+			if cfg.Worker.RunJobsInAPI {
+				calling.NewCallSessionSweeper(callingService, cfg.MaskedCalling).Start(context.Background())
+			}
+			callingHandler := calling.NewHandler(callingService)
+			calling.RegisterRoutes(v1, callingHandler, authMiddleware)
+		}
+
+		if cfg.Media.Enabled {
+			store, err := storage.NewClient(cfg.Upload)
+			if err != nil {
+				logger.Fatal("failed to configure media storage", "error", err)
+			}
+			mediaService := media.NewService(media.NewRepository(db), store, cfg.Media)
+			mediaHandler := media.NewHandler(mediaService)
+			media.RegisterRoutes(v1, mediaHandler, authMiddleware)
+		}
+
+		var privacyStore privacy.ObjectStore
+		if store, err := storage.NewClient(cfg.Upload); err == nil {
+			privacyStore = store
+		} else {
+			logger.Warn("object storage not configured, data exports disabled", "error", err)
+		}
+		privacyService := privacy.NewService(privacy.NewRepository(db), privacyStore, cfg.Privacy)
+		privacyService.SetSessionRevoker(authService)
+		privacy.NewWorker(privacyService, cfg.Privacy).Start(context.Background())
+		privacyHandler := privacy.NewHandler(privacyService)
+		privacy.RegisterRoutes(v1, privacyHandler, authMiddleware)
+
+		receiptsRepo := receipts.NewRepository(db)
+		receiptsService := receipts.NewService(receiptsRepo, emailsService, cfg.Receipts)
+		receiptsService.SetPreferenceChecker(notificationSystem.GetPreferenceService())
+		receiptsHandler := receipts.NewHandler(receiptsService)
+		receipts.RegisterRoutes(v1, receiptsHandler, authMiddleware)
+		ridesService.SetReceiptIssuer(receiptsService)
 
 		websocket.RegisterRoutes(router, cfg, wsServer)
 
 		homeservicesAdminRepo := homeservicesAdmin.NewRepository(db)
 		homeservicesAdminService := homeservicesAdmin.NewService(homeservicesAdminRepo, walletService)
+		homeservicesAdminService.SetAuditLogger(auditService)
+		homeservicesAdminService.SetWebhookPublisher(webhooksService)
+		homeservicesAdminService.SetEventPublisher(eventBus)
 		homeservicesAdminHandler := homeservicesAdmin.NewHandler(homeservicesAdminService)
 		adminGroup := v1.Group("/admin")
 		homeservicesAdmin.RegisterRoutes(
@@ -317,18 +783,61 @@ func main() {
 			authMiddleware,
 		)
 
+		catalogService := catalog.NewService(catalog.NewRepository(db))
+		catalogService.SetAuditLogger(auditService)
+		catalogHandler := catalog.NewHandler(catalogService)
+		catalog.RegisterRoutes(v1, catalogHandler, authMiddleware)
+
 		homeservicesCustomerRepo := homeservicesCustomer.NewRepository(db)
 		homeservicesCustomerService := homeservicesCustomer.NewService(homeservicesCustomerRepo, homeservicesCustomerRepo, walletService)
+		homeservicesCustomerService.SetTaxCalculator(pricingService)
+		homeservicesCustomerService.SetCurrencies(currencyService)
+		homeservicesCustomerService.SetServiceAreas(citiesService)
+		homeservicesCustomerService.SetCommissionRates(commissionsService)
+		homeservicesCustomerService.SetRatingAggregator(ratingsService)
+		homeservicesCustomerService.SetCancellationPolicies(cancellationService)
+		homeservicesCustomerService.SetAddressBook(addressesService)
+		homeservicesCustomerService.SetAddressNormalizer(geocodingService)
+		homeservicesCustomerService.SetWebhookPublisher(webhooksService)
+		homeservicesCustomerService.SetEventPublisher(eventBus)
+		homeservicesCustomerService.ConfigureQuotes(cfg.Quotes, cfg.Receipts)
+		homeservicesCustomerService.ConfigureRecurringOrders(cfg.Recurring)
+		homeservicesCustomerService.ConfigureRescheduling(cfg.Reschedule)
+		if cfg.Worker.RunJobsInAPI {
+			homeservicesCustomer.NewRecurringOrderScheduler(homeservicesCustomerService, cfg.Recurring).Start(context.Background())
+		}
 		homeservicesCustomerHandler := homeservicesCustomer.NewHandler(homeservicesCustomerService)
 
 		homeservicesCustomer.RegisterRoutes(v1, homeservicesCustomerHandler, authMiddleware)
+		handlers.RegisterOrderAdjustmentHandlers(wsManager, homeservicesCustomerService)
+
+		partnersRepo := partners.NewRepository(db)
+		partnersService, err := partners.NewService(partnersRepo, cfg.Partners)
+		if err != nil {
+			logger.Fatal("failed to configure partner API", "error", err)
+		}
+		partnersService.SetRideBooker(ridesService)
+		partnersService.SetOrderBooker(homeservicesCustomerService)
+		partnersService.SetAuditLogger(auditService)
+		partnersUsage := partners.NewUsageRecorder(partnersRepo)
+		partnersUsage.Start(context.Background(), cfg.Partners.UsageFlushInterval)
+		partnersHandler := partners.NewHandler(partnersService)
+		partners.RegisterRoutes(v1, partnersHandler, partnersService, partnersUsage, authMiddleware)
 
 		homeservicesProviderRepo := homeservicesProvider.NewRepository(db)
 		homeservicesProviderService := homeservicesProvider.NewService(
 			homeservicesProviderRepo,
 			walletService,
 			ridePinService,
+			spService,
 		)
+		homeservicesProviderService.SetRatingAggregator(ratingsService)
+		homeservicesProviderService.SetEventPublisher(eventBus)
+		homeservicesProviderService.SetProofChecker(proofsService)
+		homeservicesProviderService.SetLocationSharing(trackingService)
+		trackingService.SetArrivalMarkers(ridesService, homeservicesProviderService)
+		homeservicesProviderService.ConfigurePreferredProviders(cfg.Favorites)
+		homeservicesProviderService.ConfigureRescheduling(cfg.Reschedule)
 		homeservicesProviderHandler := homeservicesProvider.NewHandler(homeservicesProviderService)
 
 		homeservicesProvider.RegisterRoutes(
@@ -337,7 +846,12 @@ func main() {
 			authMiddleware,
 		)
 
-		laundry.RegisterRoutesWithNotifications(router, db, cfg, walletService, ridePinService, notificationSystem.GetProducer())
+		laundry.RegisterRoutesWithNotifications(router, db, cfg, walletService, ridePinService, notificationSystem.GetProducer(), addressesService, geocodingService, commissionsService, proofsService)
+
+		orderTimelineHandler := ordertimeline.NewHandler(ordertimeline.NewService(ordertimeline.NewRepository(db)))
+		ordertimeline.RegisterRoutes(v1, orderTimelineHandler, authMiddleware)
+
+		proofs.RegisterRoutes(v1, proofs.NewHandler(proofsService), authMiddleware)
 
 		adminSupportRepo := admin_support_chat.NewRepository(db)
 		adminSupportService := admin_support_chat.NewService(adminSupportRepo, notificationSystem.GetProducer())
@@ -358,7 +872,18 @@ func main() {
 		// Add other modules here...
 	}
 
-	router.GET("/docs/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	if err := eventBus.Start(context.Background()); err != nil {
+		logger.Fatal("failed to start event bus", "error", err)
+	}
+	ridesService.StartDispatch(context.Background())
+
+	announceCtx, stopAnnounce := context.WithCancel(context.Background())
+	health.Announce(announceCtx, "api", cfg.EventBus.ConsumerName)
+
+	if err := apidocs.Register(); err != nil {
+		logger.Error("failed to register api docs", "error", err)
+	}
+	apidocs.RegisterRoutes(router, middleware.Auth(cfg))
 
 	srv := &http.Server{
 		Addr:         fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port),
@@ -384,48 +909,43 @@ func main() {
 
 	logger.Info("shutting down server...")
 
+	// Fail readiness first so the load balancer stops routing here while the
+	// clients told to reconnect look for another instance.
+	checker.Drain()
+	stopAnnounce()
+
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer shutdownCancel()
 
+	if wsManager != nil {
+		wsDrainCtx, wsDrainCancel := context.WithTimeout(shutdownCtx, wsDrainTimeout)
+		wsManager.Drain(wsDrainCtx)
+		wsDrainCancel()
+	}
 	if err := srv.Shutdown(shutdownCtx); err != nil {
 		logger.Error("server forced to shutdown", "error", err)
 	}
+	// In-flight matches finish or are handed off through the dispatch queue.
+	if err := ridesService.DrainDispatch(shutdownCtx); err != nil {
+		logger.Warn("ride dispatch did not drain in time", "error", err)
+	}
+	if err := eventBus.Close(shutdownCtx); err != nil {
+		logger.Error("failed to close event bus", "error", err)
+	}
 
 	logger.Info("server stopped gracefully")
 }
 
-func healthCheck(c *gin.Context) {
-
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
-	defer cancel()
-
-	redisStatus := "healthy"
-	if err := cache.HealthCheck(ctx); err != nil {
-		redisStatus = "unhealthy"
+func dispatchHealth(ctx context.Context, ridesService rides.Service) (interface{}, error) {
+	if ridesService == nil {
+		return nil, fmt.Errorf("%w: rides service not started", health.ErrDegraded)
 	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"status": "ok",
-		"time":   time.Now().Format(time.RFC3339),
-		"redis":  redisStatus,
-	})
-}
-
-func readyCheck(db interface{}) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
-		defer cancel()
-
-		redisStatus := "connected"
-		if err := cache.HealthCheck(ctx); err != nil {
-			redisStatus = "disconnected"
-		}
-
-		c.JSON(http.StatusOK, gin.H{
-			"status":   "ready",
-			"database": "connected",
-			"redis":    redisStatus,
-			"time":     time.Now().Format(time.RFC3339),
-		})
+	stats, err := ridesService.DispatchStats(ctx)
+	if err != nil {
+		return stats, err
+	}
+	if time.Duration(stats.OverdueSeconds)*time.Second > dispatchOverdueAfter {
+		return stats, fmt.Errorf("%w: %d rides overdue for dispatch", health.ErrDegraded, stats.Due)
 	}
+	return stats, nil
 }
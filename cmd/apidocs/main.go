@@ -0,0 +1,44 @@
+// Command apidocs splits the generated Swagger spec into one spec per API
+// audience (rider, driver, provider, admin) for client SDK generation.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/umar5678/go-backend/internal/apidocs"
+)
+
+func main() {
+	in := flag.String("in", "internal/docs/swagger.json", "generated swagger spec")
+	out := flag.String("out", "docs/api", "directory to write the per-audience specs to")
+	flag.Parse()
+
+	spec, err := os.ReadFile(*in)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to read spec: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create output directory: %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, audience := range apidocs.Audiences {
+		split, err := apidocs.Split(spec, audience)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to split spec for %s: %v\n", audience.Name, err)
+			os.Exit(1)
+		}
+
+		path := filepath.Join(*out, audience.Name+".json")
+		if err := os.WriteFile(path, split, 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write %s: %v\n", path, err)
+			os.Exit(1)
+		}
+		fmt.Printf("wrote %s\n", path)
+	}
+}
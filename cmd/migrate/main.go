@@ -0,0 +1,175 @@
+// Command migrate applies the versioned SQL migrations in ./migrations.
+//
+//	migrate up [N]        apply all (or the next N) pending migrations
+//	migrate down N|all    roll back the last N (or all) migrations
+//	migrate version       print the current schema version
+//	migrate force V       mark version V as applied and clean
+//	migrate create NAME   add an empty up/down pair for the next version
+//
+// By default the migrations embedded at build time are used; -dir points it
+// at a directory on disk instead.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/joho/godotenv"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/database"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/migrations"
+)
+
+func main() {
+	dir := flag.String("dir", "", "read migrations from this directory instead of the embedded set")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: migrate [-dir path] up [N] | down N|all | version | force V | create NAME")
+	}
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if args[0] == "create" {
+		if len(args) != 2 {
+			fail("create needs a migration name")
+		}
+		path := *dir
+		if path == "" {
+			path = "migrations"
+		}
+		if err := create(path, args[1]); err != nil {
+			fail(err.Error())
+		}
+		return
+	}
+
+	_ = godotenv.Load()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fail(fmt.Sprintf("failed to load config: %v", err))
+	}
+	if err := logger.Initialize(&cfg.Logger); err != nil {
+		fail(fmt.Sprintf("failed to initialize logger: %v", err))
+	}
+	defer logger.Sync()
+
+	var source fs.FS = migrations.FS
+	if *dir != "" {
+		source = os.DirFS(*dir)
+	}
+
+	db, err := database.ConnectPostgres(&cfg.Database)
+	if err != nil {
+		fail(err.Error())
+	}
+	defer database.Close(db)
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		fail(err.Error())
+	}
+	migrator, err := database.NewMigrator(sqlDB, source)
+	if err != nil {
+		fail(err.Error())
+	}
+
+	ctx := context.Background()
+	switch args[0] {
+	case "up":
+		steps := 0
+		if len(args) > 1 {
+			steps = parseCount(args[1])
+		}
+		err = migrator.Up(ctx, steps)
+	case "down":
+		if len(args) != 2 {
+			fail("down needs a step count or \"all\"")
+		}
+		steps := 0
+		if args[1] != "all" {
+			steps = parseCount(args[1])
+		}
+		err = migrator.Down(ctx, steps)
+	case "force":
+		if len(args) != 2 {
+			fail("force needs a version")
+		}
+		version, convErr := strconv.ParseUint(args[1], 10, 64)
+		if convErr != nil {
+			fail(fmt.Sprintf("invalid version %q", args[1]))
+		}
+		err = migrator.Force(ctx, uint(version))
+	case "version":
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fail(err.Error())
+	}
+
+	version, dirty, err := migrator.Version(ctx)
+	if err != nil {
+		fail(err.Error())
+	}
+	fmt.Printf("version %d (latest %d)", version, migrator.Latest())
+	if dirty {
+		fmt.Print(" dirty")
+	}
+	fmt.Println()
+}
+
+// create writes an empty up/down pair numbered after the newest file in dir.
+func create(dir, name string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	var next uint64 = 1
+	for _, entry := range entries {
+		prefix, _, ok := strings.Cut(entry.Name(), "_")
+		if !ok {
+			continue
+		}
+		if version, err := strconv.ParseUint(prefix, 10, 64); err == nil && version >= next {
+			next = version + 1
+		}
+	}
+
+	name = strings.ReplaceAll(strings.TrimSpace(name), " ", "_")
+	for _, direction := range []string{"up", "down"} {
+		path := filepath.Join(dir, fmt.Sprintf("%06d_%s.%s.sql", next, name, direction))
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+		fmt.Printf("created %s\n", path)
+	}
+	return nil
+}
+
+func parseCount(arg string) int {
+	n, err := strconv.Atoi(arg)
+	if err != nil || n <= 0 {
+		fail(fmt.Sprintf("invalid step count %q", arg))
+	}
+	return n
+}
+
+func fail(msg string) {
+	fmt.Fprintln(os.Stderr, msg)
+	os.Exit(1)
+}
@@ -0,0 +1,325 @@
+// Command worker runs the background jobs outside the API process, so they
+// survive API deploys and can be scaled on their own. Run the API with
+// RUN_JOBS_IN_API=false and both with a shared event bus
+// (EVENT_BUS_DRIVER=redis or kafka).
+//
+// It consumes queued work from the event bus (provider matching, webhook
+// fan-out, referral rewards, loyalty points and driver incentives) and the
+// notification topics on Kafka, and runs the scheduled jobs: order and wallet
+// hold expiry, webhook delivery, payout retries, rating reconciliation,
+// loyalty point expiry, incentive payout retries, recurring home service
+// bookings, inspection and call session sweeps, and log, archive and media
+// maintenance.
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gorm.io/gorm"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/database"
+	"github.com/umar5678/go-backend/internal/jobs"
+	"github.com/umar5678/go-backend/internal/modules/addresses"
+	"github.com/umar5678/go-backend/internal/modules/audit"
+	"github.com/umar5678/go-backend/internal/modules/calling"
+	"github.com/umar5678/go-backend/internal/modules/cancellation"
+	"github.com/umar5678/go-backend/internal/modules/cities"
+	"github.com/umar5678/go-backend/internal/modules/commissions"
+	"github.com/umar5678/go-backend/internal/modules/currency"
+	"github.com/umar5678/go-backend/internal/modules/emails"
+	"github.com/umar5678/go-backend/internal/modules/homeservices"
+	homeservicesCustomer "github.com/umar5678/go-backend/internal/modules/homeservices/customer"
+	"github.com/umar5678/go-backend/internal/modules/incentives"
+	"github.com/umar5678/go-backend/internal/modules/loyalty"
+	"github.com/umar5678/go-backend/internal/modules/notifications"
+	"github.com/umar5678/go-backend/internal/modules/pricing"
+	"github.com/umar5678/go-backend/internal/modules/ratings"
+	"github.com/umar5678/go-backend/internal/modules/referrals"
+	"github.com/umar5678/go-backend/internal/modules/settlements"
+	"github.com/umar5678/go-backend/internal/modules/vehicles"
+	"github.com/umar5678/go-backend/internal/modules/wallet"
+	"github.com/umar5678/go-backend/internal/modules/webhooks"
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/services/eventbus"
+	"github.com/umar5678/go-backend/internal/services/geocoding"
+	"github.com/umar5678/go-backend/internal/services/health"
+	"github.com/umar5678/go-backend/internal/services/i18n"
+	"github.com/umar5678/go-backend/internal/services/routing"
+	"github.com/umar5678/go-backend/internal/startup"
+	"github.com/umar5678/go-backend/internal/utils/helpers"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+func main() {
+	_ = godotenv.Load()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		fmt.Printf("Failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		fmt.Printf("Invalid configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := logger.Initialize(&cfg.Logger); err != nil {
+		fmt.Printf("Failed to initialize logger: %v\n", err)
+		os.Exit(1)
+	}
+	defer logger.Sync()
+
+	logger.Info("starting worker",
+		"name", cfg.App.Name,
+		"environment", cfg.App.Environment,
+		"version", cfg.App.Version,
+		"eventBus", cfg.EventBus.Driver,
+	)
+	if cfg.Worker.RunJobsInAPI {
+		logger.Warn("RUN_JOBS_IN_API is set, so the API runs these jobs too")
+	}
+
+	var (
+		db                 *gorm.DB
+		notificationSystem *notifications.NotificationSystem
+	)
+
+	orchestrator := startup.NewOrchestrator(cfg.Startup)
+
+	orchestrator.Add(startup.Component{
+		Name:     "database",
+		Required: true,
+		Retry:    true,
+		Start: func(ctx context.Context) error {
+			conn, err := database.ConnectPostgres(&cfg.Database)
+			if err != nil {
+				return err
+			}
+			if err := conn.Use(audit.NewMoneyMovementPlugin()); err != nil {
+				database.Close(conn)
+				return fmt.Errorf("register money movement audit: %w", err)
+			}
+			if err := conn.Use(webhooks.NewWalletTransactionPlugin()); err != nil {
+				database.Close(conn)
+				return fmt.Errorf("register wallet transaction webhooks: %w", err)
+			}
+			db = conn
+			i18n.Initialize(conn)
+			return nil
+		},
+		Stop: func() error {
+			database.Close(db)
+			return nil
+		},
+	})
+
+	orchestrator.Add(startup.Component{
+		Name:     "redis",
+		Required: true,
+		Retry:    true,
+		Start: func(ctx context.Context) error {
+			if err := cache.ConnectRedis(&cfg.Redis); err != nil {
+				cache.CloseRedis()
+				return err
+			}
+			return nil
+		},
+		Stop: func() error {
+			cache.CloseRedis()
+			return nil
+		},
+	})
+
+	orchestrator.Add(startup.Component{
+		Name:      "notifications",
+		DependsOn: []string{"database", "redis"},
+		Required:  true,
+		Retry:     true,
+		Start: func(ctx context.Context) error {
+			system, err := notifications.NewNotificationSystem(
+				ctx,
+				db,
+				cfg.Kafka,
+				cfg.Firebase.CredentialsFile,
+				cfg.Firebase,
+				broadcastNotifier{},
+			)
+			if err != nil {
+				return err
+			}
+			if err := system.Start(ctx); err != nil {
+				system.Stop()
+				return err
+			}
+			notificationSystem = system
+			return nil
+		},
+		Stop: func() error {
+			return notificationSystem.Stop()
+		},
+	})
+
+	if err := orchestrator.Run(context.Background()); err != nil {
+		orchestrator.Shutdown()
+		logger.Fatal("startup failed", "error", err)
+	}
+	defer orchestrator.Shutdown()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	eventBus, err := eventbus.New(cfg.EventBus, cfg.Kafka)
+	if err != nil {
+		logger.Fatal("failed to create event bus", "error", err)
+	}
+
+	producer := notificationSystem.GetProducer()
+
+	webhooksService := webhooks.NewService(webhooks.NewRepository(db), cfg.Webhooks)
+
+	currencyService := currency.NewService(currency.NewRepository(db), cfg.Currency)
+	walletService := wallet.NewServiceWithNotifications(wallet.NewRepository(db), db, producer)
+	walletService.ConfigurePayoutRetry(cfg.PayoutRetry)
+	walletService.SetCurrencies(currencyService)
+	walletService.ConfigureDriverCredit(cfg.DriverCredit)
+
+	emailsService := emails.NewService(emails.NewRepository(db), cfg.Email)
+	emailsService.SetPreferenceChecker(notificationSystem.GetPreferenceService())
+
+	settlementsService := settlements.NewService(settlements.NewRepository(db), walletService, cfg.Settlement)
+	settlementsService.SetMailer(emailsService)
+	if cfg.Settlement.Enabled {
+		walletService.SetPayoutAccruer(settlementsService)
+	}
+
+	homeServicesRepo := homeservices.NewRepository(db)
+	homeServicesService := homeservices.NewServiceWithNotifications(homeServicesRepo, walletService, cfg, producer)
+
+	ratingsService := ratings.NewService(ratings.NewRepository(db), db, homeServicesRepo)
+	ratingsService.ConfigureRideRatings(cfg.Ratings)
+
+	referralsService := referrals.NewService(referrals.NewRepository(db), walletService, cfg.Referrals)
+	loyaltyService := loyalty.NewService(loyalty.NewRepository(db), walletService, cfg.Loyalty)
+	incentivesService := incentives.NewService(incentives.NewRepository(db), walletService, cfg.Incentives)
+
+	vehiclesRepo := vehicles.NewRepository(db)
+	vehiclesService := vehicles.NewServiceWithNotifications(vehiclesRepo, producer)
+	vehiclesService.ConfigureInspections(cfg)
+
+	// Recurring home service bookings are placed like any customer order, so
+	// the customer service gets the same pricing and area lookups as in the API.
+	pricing.ConfigureFeeDisplay(cfg.Fees)
+	pricing.ConfigureFareLock(cfg.FareLock)
+	pricingService := pricing.NewServiceWithNotifications(pricing.NewRepository(db), db, vehiclesRepo, producer)
+	pricingService.SetRouter(routing.NewService(cfg.Routing))
+	pricingService.SetCurrencies(currencyService)
+	citiesService := cities.NewService(cities.NewRepository(db), vehiclesRepo)
+	pricingService.SetCities(citiesService)
+	commissionsService := commissions.NewService(commissions.NewRepository(db))
+	commissionsService.SetServiceAreas(citiesService)
+	pricingService.SetCommissionRates(commissionsService)
+
+	homeservicesCustomerRepo := homeservicesCustomer.NewRepository(db)
+	homeservicesCustomerService := homeservicesCustomer.NewService(homeservicesCustomerRepo, homeservicesCustomerRepo, walletService)
+	homeservicesCustomerService.SetTaxCalculator(pricingService)
+	homeservicesCustomerService.SetCurrencies(currencyService)
+	homeservicesCustomerService.SetServiceAreas(citiesService)
+	homeservicesCustomerService.SetCommissionRates(commissionsService)
+	homeservicesCustomerService.SetRatingAggregator(ratingsService)
+	homeservicesCustomerService.SetCancellationPolicies(cancellation.NewService(cancellation.NewRepository(db)))
+	homeservicesCustomerService.SetAddressBook(addresses.NewService(addresses.NewRepository(db)))
+	homeservicesCustomerService.SetAddressNormalizer(geocoding.NewService(cfg.Geocoding))
+	homeservicesCustomerService.SetWebhookPublisher(webhooksService)
+	homeservicesCustomerService.SetEventPublisher(eventBus)
+	homeservicesCustomerService.ConfigureQuotes(cfg.Quotes, cfg.Receipts)
+	homeservicesCustomerService.ConfigureRecurringOrders(cfg.Recurring)
+	homeservicesCustomerService.ConfigureRescheduling(cfg.Reschedule)
+
+	if err := jobs.StartMaintenance(ctx, db, cfg, webhooksService); err != nil {
+		logger.Fatal("failed to start maintenance jobs", "error", err)
+	}
+	webhooks.NewWorker(webhooksService, cfg.Webhooks).Start(ctx)
+	wallet.NewPayoutRetryWorker(walletService, cfg.PayoutRetry).Start(ctx)
+	wallet.NewHoldExpirySweeper(walletService, cfg.Worker.HoldExpiryInterval).Start(ctx)
+	wallet.NewLedgerReconciler(walletService, cfg.Worker.LedgerReconcileInterval).Start(ctx)
+	if cfg.Settlement.Enabled {
+		settlements.NewWorker(settlementsService, cfg.Settlement).Start(ctx)
+	}
+	vehicles.NewInspectionSweeper(vehiclesService, cfg.Inspections).Start(ctx)
+	ratings.NewRatingReconciler(ratingsService, cfg.Ratings).Start(ctx)
+	loyalty.NewSweeper(loyaltyService, cfg.Loyalty).Start(ctx)
+	incentives.NewSweeper(incentivesService, cfg.Incentives).Start(ctx)
+	homeservicesCustomer.NewRecurringOrderScheduler(homeservicesCustomerService, cfg.Recurring).Start(ctx)
+	if cfg.MaskedCalling.Enabled {
+		callingService := calling.NewService(calling.NewRepository(db), calling.NewProvider(cfg.MaskedCalling), cfg.MaskedCalling)
+		calling.NewCallSessionSweeper(callingService, cfg.MaskedCalling).Start(ctx)
+	}
+
+	if err := jobs.Subscribe(eventBus, webhooksService, homeServicesService, referralsService, loyaltyService, incentivesService); err != nil {
+		logger.Fatal("failed to subscribe background jobs", "error", err)
+	}
+	// Stopped by Close at shutdown, so it can finish what is in flight.
+	if err := eventBus.Start(context.Background()); err != nil {
+		logger.Fatal("failed to start event bus", "error", err)
+	}
+
+	checker := health.NewChecker(orchestrator)
+	checker.Add(health.Database(db))
+	checker.Add(health.Redis())
+	checker.Add(health.Heartbeats())
+	health.Announce(ctx, "worker", cfg.EventBus.ConsumerName)
+
+	srv := &http.Server{
+		Addr:    fmt.Sprintf(":%d", cfg.Worker.HealthPort),
+		Handler: healthHandler(checker),
+	}
+	go func() {
+		logger.Info("worker health check listening", "port", cfg.Worker.HealthPort)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Fatal("failed to start worker health check", "error", err)
+		}
+	}()
+
+	<-ctx.Done()
+	logger.Info("shutting down worker...")
+	checker.Drain()
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer shutdownCancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Error("health check forced to shutdown", "error", err)
+	}
+	if err := eventBus.Close(shutdownCtx); err != nil {
+		logger.Error("failed to close event bus", "error", err)
+	}
+
+	logger.Info("worker stopped gracefully")
+}
+
+// broadcastNotifier hands in-app notifications to the API instances over
+// Redis, since the worker holds no websocket connections itself.
+type broadcastNotifier struct{}
+
+func (broadcastNotifier) SendNotification(userID string, notification interface{}) error {
+	return helpers.SendNotification(userID, notification)
+}
+
+func healthHandler(checker *health.Checker) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/live", checker.LiveHandler())
+	mux.Handle("/health", checker.LiveHandler())
+	mux.Handle("/ready", checker.ReadyHandler())
+	mux.Handle("/health/details", checker.DetailsHandler())
+	return mux
+}
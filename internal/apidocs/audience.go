@@ -0,0 +1,122 @@
+package apidocs
+
+import "strings"
+
+// Audience is one client of the API that gets its own spec and SDK. An
+// operation belongs to an audience when its path falls under one of the
+// audience's prefixes; admin and provider operations are kept out of the
+// other audiences even where the prefixes overlap.
+type Audience struct {
+	Name     string
+	Title    string
+	Prefixes []string
+	// All audiences see every operation and are only served to admins.
+	All bool
+}
+
+// Audiences lists the published specs. Shared modules such as auth, wallet
+// and messages appear in each app's spec.
+var Audiences = []Audience{
+	{
+		Name:  "rider",
+		Title: "Rider and customer API",
+		Prefixes: []string{
+			"/auth", "/riders", "/rides", "/trips", "/profile", "/wallet", "/payments", "/pricing", "/public/pricing",
+			"/currencies", "/cities", "/promotions", "/ratings", "/sos", "/messages", "/notifications", "/calls", "/insurance",
+			"/receipts", "/lost-items", "/vehicles", "/homeservices", "/services", "/laundry", "/favorites",
+		},
+	},
+	{
+		Name:  "driver",
+		Title: "Driver API",
+		Prefixes: []string{
+			"/auth", "/drivers", "/rides", "/tracking", "/vehicles", "/documents", "/wallet", "/payments",
+			"/pricing", "/ratings", "/sos", "/messages", "/notifications", "/calls", "/insurance",
+			"/receipts", "/lost-items", "/currencies", "/cities",
+		},
+	},
+	{
+		Name:  "provider",
+		Title: "Service provider API",
+		Prefixes: []string{
+			"/auth", "/provider", "/services/provider", "/services/category-slugs", "/laundry/provider",
+			"/documents", "/wallet", "/payments", "/ratings", "/messages", "/notifications", "/calls",
+			"/currencies", "/cities",
+		},
+	},
+	{
+		Name:  "admin",
+		Title: "Admin API",
+		All:   true,
+	},
+}
+
+// FindAudience looks an audience up by name.
+func FindAudience(name string) (Audience, bool) {
+	for _, a := range Audiences {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Audience{}, false
+}
+
+// Includes reports whether the operation at path, tagged with tags, belongs
+// in the audience's spec.
+func (a Audience) Includes(path string, tags []string) bool {
+	if a.All {
+		return true
+	}
+
+	path = strings.TrimPrefix(path, "/api/v1")
+	if isAdminOperation(path, tags) {
+		return false
+	}
+	if a.Name != "provider" && hasSegment(path, "provider") {
+		return false
+	}
+
+	for _, prefix := range a.Prefixes {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// adminPrefixes are admin-only routes without an admin segment to spot them by.
+var adminPrefixes = []string{
+	"/fraud", "/cancellation-policies", "/pricing/tax",
+	"/documents/driver", "/documents/service-provider", "/documents/verify", "/documents/pending",
+}
+
+// isAdminOperation catches admin endpoints by route (an admin segment or an
+// admin-only module) and by tag, since some modules mount admin operations
+// next to their public ones.
+func isAdminOperation(path string, tags []string) bool {
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "admin" || strings.HasPrefix(segment, "admin-") {
+			return true
+		}
+	}
+	for _, prefix := range adminPrefixes {
+		if path == prefix || strings.HasPrefix(path, prefix+"/") {
+			return true
+		}
+	}
+	for _, tag := range tags {
+		if strings.Contains(strings.ToLower(tag), "admin") {
+			return true
+		}
+	}
+	return false
+}
+
+func hasSegment(path, segment string) bool {
+	for _, s := range strings.Split(path, "/") {
+		if s == segment {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package apidocs
+
+import (
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+	"github.com/swaggo/swag"
+	"github.com/umar5678/go-backend/internal/middleware"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type staticDoc string
+
+func (d staticDoc) ReadDoc() string {
+	return string(d)
+}
+
+// Register splits the generated spec by audience and registers each part as
+// its own swag instance, named after the audience.
+func Register() error {
+	doc, err := swag.ReadDoc()
+	if err != nil {
+		return err
+	}
+
+	for _, audience := range Audiences {
+		spec, err := Split([]byte(doc), audience)
+		if err != nil {
+			return err
+		}
+		swag.Register(audience.Name, staticDoc(spec))
+	}
+	return nil
+}
+
+// RegisterRoutes serves each audience's spec and Swagger UI at
+// /docs/<audience>. The admin spec covers every endpoint, so it is only
+// served to admins. The error code catalog, with the localization key of
+// each code, is public at /docs/error-codes.
+func RegisterRoutes(router *gin.Engine, authMiddleware gin.HandlerFunc) {
+	for _, audience := range Audiences {
+		handlers := []gin.HandlerFunc{}
+		if audience.All {
+			handlers = append(handlers, authMiddleware, middleware.RequireAdmin())
+		}
+		handlers = append(handlers, ginSwagger.WrapHandler(
+			swaggerFiles.NewHandler(),
+			ginSwagger.InstanceName(audience.Name),
+			ginSwagger.PersistAuthorization(true),
+		))
+
+		router.GET("/docs/"+audience.Name+"/*any", handlers...)
+	}
+
+	router.GET("/docs/error-codes", func(c *gin.Context) {
+		response.Success(c, response.Catalog(), "Error codes retrieved successfully")
+	})
+}
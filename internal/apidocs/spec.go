@@ -0,0 +1,122 @@
+package apidocs
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+var operationMethods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true, "options": true, "head": true, "patch": true,
+}
+
+// Split returns the part of a Swagger 2.0 spec that belongs to the audience:
+// its operations and only the definitions they reference, so generated SDKs
+// carry no models for endpoints they cannot call.
+func Split(spec []byte, audience Audience) ([]byte, error) {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("invalid swagger spec: %w", err)
+	}
+
+	paths, _ := doc["paths"].(map[string]interface{})
+	kept := make(map[string]interface{}, len(paths))
+	for path, raw := range paths {
+		item, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		filtered := make(map[string]interface{}, len(item))
+		operations := 0
+		for key, value := range item {
+			if !operationMethods[key] {
+				filtered[key] = value
+				continue
+			}
+			if audience.Includes(path, operationTags(value)) {
+				filtered[key] = value
+				operations++
+			}
+		}
+		if operations > 0 {
+			kept[path] = filtered
+		}
+	}
+	doc["paths"] = kept
+
+	if definitions, ok := doc["definitions"].(map[string]interface{}); ok {
+		doc["definitions"] = referencedDefinitions(kept, definitions)
+	}
+
+	if info, ok := doc["info"].(map[string]interface{}); ok {
+		if title, _ := info["title"].(string); title != "" {
+			info["title"] = title + " - " + audience.Title
+		} else {
+			info["title"] = audience.Title
+		}
+	}
+
+	return json.MarshalIndent(doc, "", "    ")
+}
+
+func operationTags(operation interface{}) []string {
+	op, ok := operation.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	raw, _ := op["tags"].([]interface{})
+	tags := make([]string, 0, len(raw))
+	for _, tag := range raw {
+		if s, ok := tag.(string); ok {
+			tags = append(tags, s)
+		}
+	}
+	return tags
+}
+
+// referencedDefinitions follows $ref links from the kept paths, including
+// definitions that refer to other definitions.
+func referencedDefinitions(paths map[string]interface{}, definitions map[string]interface{}) map[string]interface{} {
+	const prefix = "#/definitions/"
+
+	result := make(map[string]interface{})
+	queue := collectRefs(paths, nil)
+	for len(queue) > 0 {
+		ref := queue[0]
+		queue = queue[1:]
+
+		if !strings.HasPrefix(ref, prefix) {
+			continue
+		}
+		name := strings.TrimPrefix(ref, prefix)
+		if _, done := result[name]; done {
+			continue
+		}
+		definition, ok := definitions[name]
+		if !ok {
+			continue
+		}
+		result[name] = definition
+		queue = collectRefs(definition, queue)
+	}
+	return result
+}
+
+func collectRefs(node interface{}, refs []string) []string {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if ref, ok := value.(string); ok && key == "$ref" {
+				refs = append(refs, ref)
+				continue
+			}
+			refs = collectRefs(value, refs)
+		}
+	case []interface{}:
+		for _, value := range v {
+			refs = collectRefs(value, refs)
+		}
+	}
+	return refs
+}
@@ -2,6 +2,8 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
 	"time"
 
@@ -55,21 +57,73 @@ func LoadConfig() (*Config, error) {
 	if headersStr != "" {
 		cfg.Server.CORS.AllowedHeaders = strings.Split(headersStr, ",")
 	} else {
-		cfg.Server.CORS.AllowedHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization"}
+		cfg.Server.CORS.AllowedHeaders = []string{"Origin", "Content-Type", "Accept", "Authorization", "X-API-Key", "X-Captcha-Token"}
 	}
 
 	cfg.Server.CORS.AllowCredentials = v.GetBool("CORS_ALLOW_CREDENTIALS")
 
 	cfg.Server.CORS.Enabled = v.GetBool("ENABLE_CORS_MIDDLEWARE")
 
-	cfg.Server.RateLimit.RequestsPerSecond = v.GetInt("RATE_LIMIT_REQUESTS_PER_SECOND")
-	cfg.Server.RateLimit.Burst = v.GetInt("RATE_LIMIT_BURST")
-
-	if cfg.Server.RateLimit.RequestsPerSecond == 0 {
-		cfg.Server.RateLimit.RequestsPerSecond = 100
+	cfg.Server.RateLimit.Enabled = true
+	if v.IsSet("RATE_LIMIT_ENABLED") {
+		cfg.Server.RateLimit.Enabled = v.GetBool("RATE_LIMIT_ENABLED")
+	}
+	cfg.Server.RateLimit.Default = RateLimitPolicy{Requests: 600, Window: time.Minute}
+	if perMinute := v.GetInt("RATE_LIMIT_DEFAULT_PER_MINUTE"); perMinute > 0 {
+		cfg.Server.RateLimit.Default.Requests = perMinute
+	}
+	cfg.Server.RateLimit.Classes = []RateLimitClass{
+		{
+			Name:   "auth",
+			Policy: RateLimitPolicy{Requests: 10, Window: time.Minute},
+			Routes: []string{
+				"POST /api/v1/auth/phone/*",
+				"POST /api/v1/auth/email/*",
+				"POST /api/v1/auth/refresh",
+				"POST /api/v1/promotions/validate",
+				"POST /api/v1/profile/referral/apply",
+			},
+		},
+		{
+			Name:   "order_create",
+			Policy: RateLimitPolicy{Requests: 20, Window: time.Minute},
+			Routes: []string{
+				"POST /api/v1/rides",
+				"POST /api/v1/homeservices/orders",
+				"POST /api/v1/homeservices/quotes",
+				"POST /api/v1/services/orders",
+				"POST /api/v1/laundry/orders",
+			},
+		},
+		{
+			Name:   "catalog_read",
+			Policy: RateLimitPolicy{Requests: 1200, Window: time.Minute},
+			Routes: []string{
+				"GET /api/v1/homeservices/categories*",
+				"GET /api/v1/homeservices/services*",
+				"GET /api/v1/homeservices/addons*",
+				"GET /api/v1/homeservices/search",
+				"GET /api/v1/services/categor*",
+				"GET /api/v1/laundry/services",
+			},
+		},
+		{
+			// Partners are limited per API key by the partner module; this
+			// only caps a single source address.
+			Name:   "partner",
+			Policy: RateLimitPolicy{Requests: 6000, Window: time.Minute},
+			Routes: []string{"* /api/v1/partner/*"},
+		},
 	}
-	if cfg.Server.RateLimit.Burst == 0 {
-		cfg.Server.RateLimit.Burst = 200
+	for i := range cfg.Server.RateLimit.Classes {
+		class := &cfg.Server.RateLimit.Classes[i]
+		prefix := "RATE_LIMIT_" + strings.ToUpper(class.Name)
+		if perMinute := v.GetInt(prefix + "_PER_MINUTE"); perMinute > 0 {
+			class.Policy.Requests = perMinute
+		}
+		if routes := v.GetString(prefix + "_ROUTES"); routes != "" {
+			class.Routes = strings.Split(routes, ",")
+		}
 	}
 
 	cfg.Database.Host = v.GetString("DB_HOST")
@@ -82,6 +136,15 @@ func LoadConfig() (*Config, error) {
 	cfg.Database.MaxIdleConns = v.GetInt("DB_MAX_IDLE_CONNS")
 	cfg.Database.MaxLifetime = v.GetDuration("DB_MAX_LIFETIME") * time.Minute
 	cfg.Database.LogLevel = logger.LogLevel(v.GetInt("DB_LOG_LEVEL"))
+	for _, dsn := range strings.Split(v.GetString("DB_REPLICA_DSNS"), ",") {
+		if dsn = strings.TrimSpace(dsn); dsn != "" {
+			cfg.Database.ReplicaDSNs = append(cfg.Database.ReplicaDSNs, dsn)
+		}
+	}
+	cfg.Database.SchemaCheck = true
+	if v.IsSet("DB_SCHEMA_CHECK") {
+		cfg.Database.SchemaCheck = v.GetBool("DB_SCHEMA_CHECK")
+	}
 
 	cfg.Redis.Host = v.GetString("REDIS_HOST")
 	cfg.Redis.Port = v.GetInt("REDIS_PORT")
@@ -121,6 +184,8 @@ func LoadConfig() (*Config, error) {
 	cfg.Upload.S3.Region = v.GetString("S3_REGION")
 	cfg.Upload.S3.AccessKey = v.GetString("S3_ACCESS_KEY")
 	cfg.Upload.S3.SecretKey = v.GetString("S3_SECRET_KEY")
+	cfg.Upload.S3.Endpoint = v.GetString("S3_ENDPOINT")
+	cfg.Upload.S3.PublicURL = v.GetString("S3_PUBLIC_URL")
 
 	cfg.Upload.ImageKit.PublicKey = v.GetString("IMAGEKIT_PUBLIC_KEY")
 	cfg.Upload.ImageKit.PrivateKey = v.GetString("IMAGEKIT_PRIVATE_KEY")
@@ -231,10 +296,858 @@ func LoadConfig() (*Config, error) {
 	if aofSync := v.GetString("WEBSOCKET_AOF_SYNC_POLICY"); aofSync != "" {
 		cfg.WebSocket.AOFSyncPolicy = aofSync
 	}
+	if resumeTTL := v.GetDuration("WEBSOCKET_RESUME_TICKET_TTL"); resumeTTL > 0 {
+		cfg.WebSocket.ResumeTicketTTL = resumeTTL * time.Second
+	}
+	cfg.WebSocket.AuditEnabled = v.GetBool("WEBSOCKET_AUDIT_ENABLED")
+	if v.IsSet("WEBSOCKET_AUDIT_SAMPLE_RATE") {
+		cfg.WebSocket.AuditSampleRate = v.GetFloat64("WEBSOCKET_AUDIT_SAMPLE_RATE")
+	}
+	if auditMax := v.GetInt("WEBSOCKET_AUDIT_MAX_ENTRIES"); auditMax > 0 {
+		cfg.WebSocket.AuditMaxEntries = auditMax
+	}
+	if auditTTL := v.GetDuration("WEBSOCKET_AUDIT_TTL"); auditTTL > 0 {
+		cfg.WebSocket.AuditTTL = auditTTL * time.Second
+	}
+	if redact := v.GetString("WEBSOCKET_AUDIT_REDACT_FIELDS"); redact != "" {
+		cfg.WebSocket.AuditRedactFields = strings.Split(redact, ",")
+	}
+	if v.IsSet("WEBSOCKET_OFFLINE_QUEUE_ENABLED") {
+		cfg.WebSocket.OfflineQueueEnabled = v.GetBool("WEBSOCKET_OFFLINE_QUEUE_ENABLED")
+	}
+	if offlineTTL := v.GetDuration("WEBSOCKET_OFFLINE_QUEUE_TTL"); offlineTTL > 0 {
+		cfg.WebSocket.OfflineQueueTTL = offlineTTL * time.Second
+	}
+	if offlineMax := v.GetInt("WEBSOCKET_OFFLINE_QUEUE_MAX_PER_USER"); offlineMax > 0 {
+		cfg.WebSocket.OfflineQueueMaxPerUser = offlineMax
+	}
 
 	cfg.Firebase.CredentialsFile = v.GetString("FIREBASE_CREDENTIALS_FILE")
 	cfg.Firebase.CredentialsJSON = v.GetString("FIREBASE_CREDENTIALS_JSON")
 
+	cfg.Fees.ShowPlatformFee = true
+	if v.IsSet("FEE_DISPLAY_SHOW_PLATFORM_FEE") {
+		cfg.Fees.ShowPlatformFee = v.GetBool("FEE_DISPLAY_SHOW_PLATFORM_FEE")
+	}
+	cfg.Fees.ShowEarnerShare = v.GetBool("FEE_DISPLAY_SHOW_EARNER_SHARE")
+	cfg.Fees.PlatformFeeLabel = v.GetString("FEE_DISPLAY_PLATFORM_FEE_LABEL")
+	if cfg.Fees.PlatformFeeLabel == "" {
+		cfg.Fees.PlatformFeeLabel = "Platform fee"
+	}
+	cfg.Fees.Currency = v.GetString("FEE_DISPLAY_CURRENCY")
+	if cfg.Fees.Currency == "" {
+		cfg.Fees.Currency = "INR"
+	}
+
+	cfg.Currency.Default = strings.ToUpper(v.GetString("CURRENCY_DEFAULT"))
+	if cfg.Currency.Default == "" {
+		cfg.Currency.Default = strings.ToUpper(cfg.Fees.Currency)
+	}
+	cfg.Fees.Currency = cfg.Currency.Default
+	cfg.Currency.RateProvider = strings.ToLower(v.GetString("EXCHANGE_RATE_PROVIDER"))
+	if cfg.Currency.RateProvider == "" {
+		cfg.Currency.RateProvider = "none"
+	}
+	cfg.Currency.RateAPIURL = strings.TrimSuffix(v.GetString("EXCHANGE_RATE_API_URL"), "/")
+	cfg.Currency.RateAPIKey = v.GetString("EXCHANGE_RATE_API_KEY")
+	cfg.Currency.RefreshInterval = 6 * time.Hour
+	if interval := v.GetDuration("EXCHANGE_RATE_REFRESH_INTERVAL"); interval > 0 {
+		cfg.Currency.RefreshInterval = interval * time.Second
+	}
+	cfg.Currency.Timeout = 10 * time.Second
+	if timeout := v.GetDuration("EXCHANGE_RATE_TIMEOUT"); timeout > 0 {
+		cfg.Currency.Timeout = timeout * time.Second
+	}
+
+	cfg.Tracing.Enabled = v.GetBool("TRACING_ENABLED")
+	cfg.Tracing.ServiceName = v.GetString("TRACING_SERVICE_NAME")
+	if cfg.Tracing.ServiceName == "" {
+		cfg.Tracing.ServiceName = cfg.App.Name
+	}
+	cfg.Tracing.OTLPEndpoint = v.GetString("OTEL_EXPORTER_OTLP_ENDPOINT")
+	if cfg.Tracing.OTLPEndpoint == "" {
+		cfg.Tracing.OTLPEndpoint = "http://localhost:4318"
+	}
+	cfg.Tracing.OTLPHeaders = make(map[string]string)
+	if headersStr := v.GetString("OTEL_EXPORTER_OTLP_HEADERS"); headersStr != "" {
+		for _, pair := range strings.Split(headersStr, ",") {
+			if key, value, ok := strings.Cut(pair, "="); ok {
+				cfg.Tracing.OTLPHeaders[strings.TrimSpace(key)] = strings.TrimSpace(value)
+			}
+		}
+	}
+	cfg.Tracing.SampleRatio = 1.0
+	if v.IsSet("TRACING_SAMPLE_RATIO") {
+		cfg.Tracing.SampleRatio = v.GetFloat64("TRACING_SAMPLE_RATIO")
+	}
+	cfg.Tracing.ExportTimeout = 10 * time.Second
+	if exportTimeout := v.GetDuration("TRACING_EXPORT_TIMEOUT"); exportTimeout > 0 {
+		cfg.Tracing.ExportTimeout = exportTimeout * time.Second
+	}
+
+	cfg.PublicEstimate.Enabled = v.GetBool("PUBLIC_ESTIMATE_ENABLED")
+	if keys := v.GetString("PUBLIC_ESTIMATE_API_KEYS"); keys != "" {
+		for _, key := range strings.Split(keys, ",") {
+			if key = strings.TrimSpace(key); key != "" {
+				cfg.PublicEstimate.APIKeys = append(cfg.PublicEstimate.APIKeys, key)
+			}
+		}
+	}
+	cfg.PublicEstimate.CaptchaSecret = v.GetString("PUBLIC_ESTIMATE_CAPTCHA_SECRET")
+	cfg.PublicEstimate.CaptchaVerifyURL = v.GetString("PUBLIC_ESTIMATE_CAPTCHA_VERIFY_URL")
+	if cfg.PublicEstimate.CaptchaVerifyURL == "" {
+		cfg.PublicEstimate.CaptchaVerifyURL = "https://www.google.com/recaptcha/api/siteverify"
+	}
+	cfg.PublicEstimate.IPRequestsPerMinute = 10
+	if perMinute := v.GetInt("PUBLIC_ESTIMATE_IP_RATE_PER_MINUTE"); perMinute > 0 {
+		cfg.PublicEstimate.IPRequestsPerMinute = perMinute
+	}
+	cfg.PublicEstimate.KeyRequestsPerMinute = 600
+	if perMinute := v.GetInt("PUBLIC_ESTIMATE_KEY_RATE_PER_MINUTE"); perMinute > 0 {
+		cfg.PublicEstimate.KeyRequestsPerMinute = perMinute
+	}
+	cfg.PublicEstimate.RoundTo = 5
+	if roundTo := v.GetFloat64("PUBLIC_ESTIMATE_ROUND_TO"); roundTo > 0 {
+		cfg.PublicEstimate.RoundTo = roundTo
+	}
+	cfg.PublicEstimate.Currency = cfg.Fees.Currency
+
+	cfg.Insurance.Enabled = v.GetBool("INSURANCE_ENABLED")
+	cfg.Insurance.ProviderURL = v.GetString("INSURANCE_PROVIDER_URL")
+	cfg.Insurance.ProviderAPIKey = v.GetString("INSURANCE_PROVIDER_API_KEY")
+	cfg.Insurance.ProviderName = v.GetString("INSURANCE_PROVIDER_NAME")
+	if cfg.Insurance.ProviderName == "" {
+		cfg.Insurance.ProviderName = "internal"
+	}
+	cfg.Insurance.PolicyPrefix = v.GetString("INSURANCE_POLICY_PREFIX")
+	if cfg.Insurance.PolicyPrefix == "" {
+		cfg.Insurance.PolicyPrefix = "SUPR"
+	}
+	cfg.Insurance.CoverageAmount = 500000
+	if coverage := v.GetFloat64("INSURANCE_COVERAGE_AMOUNT"); coverage > 0 {
+		cfg.Insurance.CoverageAmount = coverage
+	}
+	cfg.Insurance.PremiumPerTrip = v.GetFloat64("INSURANCE_PREMIUM_PER_TRIP")
+	cfg.Insurance.Currency = cfg.Fees.Currency
+	cfg.Insurance.ClaimWindow = 30 * 24 * time.Hour
+	if days := v.GetInt("INSURANCE_CLAIM_WINDOW_DAYS"); days > 0 {
+		cfg.Insurance.ClaimWindow = time.Duration(days) * 24 * time.Hour
+	}
+
+	cfg.Payments.Enabled = v.GetBool("PAYMENTS_ENABLED")
+	cfg.Payments.Gateway = "stripe"
+	cfg.Payments.StripeSecretKey = v.GetString("STRIPE_SECRET_KEY")
+	cfg.Payments.StripePublishableKey = v.GetString("STRIPE_PUBLISHABLE_KEY")
+	cfg.Payments.StripeWebhookSecret = v.GetString("STRIPE_WEBHOOK_SECRET")
+	cfg.Payments.StripeAPIURL = v.GetString("STRIPE_API_URL")
+	if cfg.Payments.StripeAPIURL == "" {
+		cfg.Payments.StripeAPIURL = "https://api.stripe.com"
+	}
+	cfg.Payments.Currency = cfg.Fees.Currency
+	cfg.Payments.MinTopUp = 50
+	if minTopUp := v.GetFloat64("WALLET_TOPUP_MIN_AMOUNT"); minTopUp > 0 {
+		cfg.Payments.MinTopUp = minTopUp
+	}
+	cfg.Payments.MaxTopUp = 10000
+	if maxTopUp := v.GetFloat64("WALLET_TOPUP_MAX_AMOUNT"); maxTopUp > 0 {
+		cfg.Payments.MaxTopUp = maxTopUp
+	}
+	cfg.Payments.WebhookTolerance = 5 * time.Minute
+
+	cfg.Receipts.CompanyName = v.GetString("RECEIPT_COMPANY_NAME")
+	if cfg.Receipts.CompanyName == "" {
+		cfg.Receipts.CompanyName = cfg.App.Name
+	}
+	cfg.Receipts.CompanyAddress = v.GetString("RECEIPT_COMPANY_ADDRESS")
+	cfg.Receipts.TaxID = v.GetString("RECEIPT_TAX_ID")
+	cfg.Receipts.NumberPrefix = v.GetString("RECEIPT_NUMBER_PREFIX")
+	if cfg.Receipts.NumberPrefix == "" {
+		cfg.Receipts.NumberPrefix = "RCP"
+	}
+	cfg.Receipts.EmailOnComplete = v.GetBool("RECEIPT_EMAIL_ON_COMPLETE")
+
+	cfg.Quotes.NumberPrefix = v.GetString("QUOTE_NUMBER_PREFIX")
+	if cfg.Quotes.NumberPrefix == "" {
+		cfg.Quotes.NumberPrefix = "QT"
+	}
+	cfg.Quotes.Validity = 14 * 24 * time.Hour
+	if days := v.GetInt("QUOTE_VALIDITY_DAYS"); days > 0 {
+		cfg.Quotes.Validity = time.Duration(days) * 24 * time.Hour
+	}
+	cfg.Quotes.LinkTTL = 72 * time.Hour
+	if hours := v.GetInt("QUOTE_LINK_TTL_HOURS"); hours > 0 {
+		cfg.Quotes.LinkTTL = time.Duration(hours) * time.Hour
+	}
+	cfg.Quotes.SigningSecret = v.GetString("QUOTE_SIGNING_SECRET")
+	if cfg.Quotes.SigningSecret == "" {
+		cfg.Quotes.SigningSecret = cfg.JWT.Secret
+	}
+	cfg.Quotes.PublicBaseURL = strings.TrimSuffix(v.GetString("QUOTE_PUBLIC_BASE_URL"), "/")
+	if terms := v.GetString("QUOTE_TERMS"); terms != "" {
+		cfg.Quotes.Terms = strings.Split(terms, "|")
+	}
+
+	cfg.Routing.Provider = strings.ToLower(v.GetString("ROUTING_PROVIDER"))
+	if cfg.Routing.Provider == "" {
+		cfg.Routing.Provider = "none"
+	}
+	cfg.Routing.OSRMURL = v.GetString("OSRM_URL")
+	cfg.Routing.GoogleAPIKey = v.GetString("GOOGLE_MAPS_API_KEY")
+	cfg.Routing.GoogleAPIURL = v.GetString("GOOGLE_MAPS_API_URL")
+	cfg.Routing.Timeout = 3 * time.Second
+	if timeout := v.GetDuration("ROUTING_TIMEOUT"); timeout > 0 {
+		cfg.Routing.Timeout = timeout * time.Second
+	}
+	cfg.Routing.CacheTTL = 10 * time.Minute
+	if ttl := v.GetDuration("ROUTING_CACHE_TTL"); ttl > 0 {
+		cfg.Routing.CacheTTL = ttl * time.Second
+	}
+
+	cfg.Geocoding.Provider = strings.ToLower(v.GetString("GEOCODING_PROVIDER"))
+	if cfg.Geocoding.Provider == "" {
+		cfg.Geocoding.Provider = "none"
+	}
+	cfg.Geocoding.GoogleAPIKey = cfg.Routing.GoogleAPIKey
+	cfg.Geocoding.GoogleAPIURL = cfg.Routing.GoogleAPIURL
+	cfg.Geocoding.MapboxToken = v.GetString("MAPBOX_ACCESS_TOKEN")
+	cfg.Geocoding.MapboxURL = v.GetString("MAPBOX_API_URL")
+	cfg.Geocoding.NominatimURL = v.GetString("NOMINATIM_URL")
+	cfg.Geocoding.UserAgent = v.GetString("GEOCODING_USER_AGENT")
+	if cfg.Geocoding.UserAgent == "" {
+		cfg.Geocoding.UserAgent = cfg.App.Name
+	}
+	if countries := v.GetString("GEOCODING_COUNTRIES"); countries != "" {
+		for _, country := range strings.Split(countries, ",") {
+			if country = strings.ToLower(strings.TrimSpace(country)); country != "" {
+				cfg.Geocoding.Countries = append(cfg.Geocoding.Countries, country)
+			}
+		}
+	}
+	cfg.Geocoding.Language = v.GetString("GEOCODING_LANGUAGE")
+	cfg.Geocoding.Timeout = 3 * time.Second
+	if timeout := v.GetDuration("GEOCODING_TIMEOUT"); timeout > 0 {
+		cfg.Geocoding.Timeout = timeout * time.Second
+	}
+	cfg.Geocoding.CacheTTL = 24 * time.Hour
+	if ttl := v.GetDuration("GEOCODING_CACHE_TTL"); ttl > 0 {
+		cfg.Geocoding.CacheTTL = ttl * time.Second
+	}
+	cfg.Geocoding.UserRequestsPerMinute = 60
+	if perMinute := v.GetInt("GEOCODING_USER_RATE_PER_MINUTE"); perMinute > 0 {
+		cfg.Geocoding.UserRequestsPerMinute = perMinute
+	}
+
+	cfg.PayoutRetry.MaxAttempts = 5
+	if attempts := v.GetInt("PAYOUT_RETRY_MAX_ATTEMPTS"); attempts > 0 {
+		cfg.PayoutRetry.MaxAttempts = attempts
+	}
+	cfg.PayoutRetry.InitialBackoff = time.Minute
+	if backoff := v.GetDuration("PAYOUT_RETRY_INITIAL_BACKOFF"); backoff > 0 {
+		cfg.PayoutRetry.InitialBackoff = backoff * time.Second
+	}
+	cfg.PayoutRetry.MaxBackoff = time.Hour
+	if maxBackoff := v.GetDuration("PAYOUT_RETRY_MAX_BACKOFF"); maxBackoff > 0 {
+		cfg.PayoutRetry.MaxBackoff = maxBackoff * time.Second
+	}
+	cfg.PayoutRetry.Interval = time.Minute
+	if interval := v.GetDuration("PAYOUT_RETRY_INTERVAL"); interval > 0 {
+		cfg.PayoutRetry.Interval = interval * time.Second
+	}
+
+	cfg.DriverCredit.DefaultTier = "standard"
+	if tier := strings.TrimSpace(v.GetString("DRIVER_CREDIT_DEFAULT_TIER")); tier != "" {
+		cfg.DriverCredit.DefaultTier = tier
+	}
+	cfg.DriverCredit.TierLimits = map[string]float64{"new": 500, "standard": 2000, "trusted": 5000}
+	if limits := v.GetString("DRIVER_CREDIT_TIER_LIMITS"); limits != "" {
+		cfg.DriverCredit.TierLimits = make(map[string]float64)
+		for _, pair := range strings.Split(limits, ",") {
+			tier, value, ok := strings.Cut(pair, "=")
+			if !ok {
+				continue
+			}
+			if limit, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && limit >= 0 {
+				cfg.DriverCredit.TierLimits[strings.TrimSpace(tier)] = limit
+			}
+		}
+	}
+	if _, ok := cfg.DriverCredit.TierLimits[cfg.DriverCredit.DefaultTier]; !ok {
+		cfg.DriverCredit.TierLimits[cfg.DriverCredit.DefaultTier] = 2000
+	}
+
+	cfg.Settlement.Enabled = v.GetBool("SETTLEMENT_ENABLED")
+	cfg.Settlement.MinPayout = 50
+	if minPayout := v.GetFloat64("SETTLEMENT_MIN_PAYOUT"); minPayout > 0 {
+		cfg.Settlement.MinPayout = minPayout
+	}
+	cfg.Settlement.Interval = time.Hour
+	if interval := v.GetDuration("SETTLEMENT_INTERVAL"); interval > 0 {
+		cfg.Settlement.Interval = interval * time.Second
+	}
+
+	cfg.Dispatch.Mode = "batch"
+	switch mode := strings.ToLower(strings.TrimSpace(v.GetString("DISPATCH_MODE"))); mode {
+	case "sequential", "batch", "broadcast":
+		cfg.Dispatch.Mode = mode
+	}
+	cfg.Dispatch.BatchSize = 3
+	if size := v.GetInt("DISPATCH_BATCH_SIZE"); size > 0 {
+		cfg.Dispatch.BatchSize = size
+	}
+	cfg.Dispatch.OfferTimeout = 10 * time.Second
+	if timeout := v.GetDuration("DISPATCH_OFFER_TIMEOUT"); timeout > 0 {
+		cfg.Dispatch.OfferTimeout = timeout * time.Second
+	}
+	cfg.Dispatch.VehicleOfferTimeouts = make(map[string]time.Duration)
+	if timeouts := v.GetString("DISPATCH_VEHICLE_OFFER_TIMEOUTS"); timeouts != "" {
+		for _, pair := range strings.Split(timeouts, ",") {
+			name, value, ok := strings.Cut(pair, "=")
+			if !ok {
+				continue
+			}
+			if seconds, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && seconds > 0 {
+				cfg.Dispatch.VehicleOfferTimeouts[strings.TrimSpace(name)] = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+
+	cfg.TripCheck.DistanceTolerancePct = 20
+	if pct := v.GetFloat64("TRIP_CHECK_DISTANCE_TOLERANCE_PCT"); pct > 0 {
+		cfg.TripCheck.DistanceTolerancePct = pct
+	}
+	cfg.TripCheck.DistanceToleranceKm = 1
+	if km := v.GetFloat64("TRIP_CHECK_DISTANCE_TOLERANCE_KM"); km > 0 {
+		cfg.TripCheck.DistanceToleranceKm = km
+	}
+	cfg.TripCheck.DurationTolerancePct = 25
+	if pct := v.GetFloat64("TRIP_CHECK_DURATION_TOLERANCE_PCT"); pct > 0 {
+		cfg.TripCheck.DurationTolerancePct = pct
+	}
+	cfg.TripCheck.DurationTolerance = 3 * time.Minute
+	if tolerance := v.GetDuration("TRIP_CHECK_DURATION_TOLERANCE"); tolerance > 0 {
+		cfg.TripCheck.DurationTolerance = tolerance * time.Second
+	}
+	cfg.TripCheck.MinTrackPoints = 5
+	if points := v.GetInt("TRIP_CHECK_MIN_TRACK_POINTS"); points > 0 {
+		cfg.TripCheck.MinTrackPoints = points
+	}
+
+	cfg.MaskedCalling.Enabled = v.GetBool("MASKED_CALLING_ENABLED")
+	cfg.MaskedCalling.Provider = strings.ToLower(v.GetString("MASKED_CALLING_PROVIDER"))
+	if cfg.MaskedCalling.Provider == "" {
+		cfg.MaskedCalling.Provider = "twilio"
+	}
+	cfg.MaskedCalling.TwilioAccountSID = v.GetString("TWILIO_ACCOUNT_SID")
+	cfg.MaskedCalling.TwilioAuthToken = v.GetString("TWILIO_AUTH_TOKEN")
+	cfg.MaskedCalling.TwilioProxyServiceSID = v.GetString("TWILIO_PROXY_SERVICE_SID")
+	cfg.MaskedCalling.TwilioProxyAPIURL = v.GetString("TWILIO_PROXY_API_URL")
+	if cfg.MaskedCalling.TwilioProxyAPIURL == "" {
+		cfg.MaskedCalling.TwilioProxyAPIURL = "https://proxy.twilio.com"
+	}
+	cfg.MaskedCalling.WebhookURL = v.GetString("MASKED_CALLING_WEBHOOK_URL")
+	cfg.MaskedCalling.PostTripWindow = 30 * time.Minute
+	if window := v.GetDuration("MASKED_CALLING_POST_TRIP_WINDOW"); window > 0 {
+		cfg.MaskedCalling.PostTripWindow = window * time.Second
+	}
+	cfg.MaskedCalling.SessionTTL = 12 * time.Hour
+	if ttl := v.GetDuration("MASKED_CALLING_SESSION_TTL"); ttl > 0 {
+		cfg.MaskedCalling.SessionTTL = ttl * time.Second
+	}
+	cfg.MaskedCalling.SweepInterval = time.Minute
+	if interval := v.GetDuration("MASKED_CALLING_SWEEP_INTERVAL"); interval > 0 {
+		cfg.MaskedCalling.SweepInterval = interval * time.Second
+	}
+
+	cfg.Safety.SMSEnabled = v.GetBool("SOS_SMS_ENABLED")
+	cfg.Safety.TwilioAccountSID = v.GetString("TWILIO_ACCOUNT_SID")
+	cfg.Safety.TwilioAuthToken = v.GetString("TWILIO_AUTH_TOKEN")
+	cfg.Safety.TwilioAPIURL = v.GetString("TWILIO_API_URL")
+	if cfg.Safety.TwilioAPIURL == "" {
+		cfg.Safety.TwilioAPIURL = "https://api.twilio.com"
+	}
+	cfg.Safety.SMSFrom = v.GetString("SOS_SMS_FROM")
+	if phones := v.GetString("SOS_SAFETY_TEAM_PHONES"); phones != "" {
+		for _, phone := range strings.Split(phones, ",") {
+			if phone = strings.TrimSpace(phone); phone != "" {
+				cfg.Safety.SafetyTeamPhones = append(cfg.Safety.SafetyTeamPhones, phone)
+			}
+		}
+	}
+
+	cfg.TripSharing.LinkTTL = 4 * time.Hour
+	if hours := v.GetInt("TRIP_SHARE_LINK_TTL_HOURS"); hours > 0 {
+		cfg.TripSharing.LinkTTL = time.Duration(hours) * time.Hour
+	}
+	cfg.TripSharing.SigningSecret = v.GetString("TRIP_SHARE_SIGNING_SECRET")
+	if cfg.TripSharing.SigningSecret == "" {
+		cfg.TripSharing.SigningSecret = cfg.JWT.Secret
+	}
+	cfg.TripSharing.PublicBaseURL = strings.TrimSuffix(v.GetString("TRIP_SHARE_PUBLIC_BASE_URL"), "/")
+	if cfg.TripSharing.PublicBaseURL == "" {
+		cfg.TripSharing.PublicBaseURL = cfg.Quotes.PublicBaseURL
+	}
+	cfg.TripSharing.UpdateInterval = 5 * time.Second
+	if interval := v.GetDuration("TRIP_SHARE_UPDATE_INTERVAL"); interval > 0 {
+		cfg.TripSharing.UpdateInterval = interval * time.Second
+	}
+
+	cfg.Arrival.Enabled = true
+	if v.IsSet("ARRIVAL_GEOFENCE_ENABLED") {
+		cfg.Arrival.Enabled = v.GetBool("ARRIVAL_GEOFENCE_ENABLED")
+	}
+	cfg.Arrival.DriverRadiusMeters = 75
+	if radius := v.GetFloat64("ARRIVAL_DRIVER_RADIUS_METERS"); radius > 0 {
+		cfg.Arrival.DriverRadiusMeters = radius
+	}
+	cfg.Arrival.ProviderRadiusMeters = 100
+	if radius := v.GetFloat64("ARRIVAL_PROVIDER_RADIUS_METERS"); radius > 0 {
+		cfg.Arrival.ProviderRadiusMeters = radius
+	}
+	cfg.Arrival.MaxAccuracyMeters = 50
+	if accuracy := v.GetFloat64("ARRIVAL_MAX_ACCURACY_METERS"); accuracy > 0 {
+		cfg.Arrival.MaxAccuracyMeters = accuracy
+	}
+
+	cfg.FareLock.Required = true
+	if v.IsSet("FARE_LOCK_REQUIRED") {
+		cfg.FareLock.Required = v.GetBool("FARE_LOCK_REQUIRED")
+	}
+	cfg.FareLock.TTL = 3 * time.Minute
+	if seconds := v.GetInt("FARE_LOCK_TTL_SECONDS"); seconds > 0 {
+		cfg.FareLock.TTL = time.Duration(seconds) * time.Second
+	}
+	cfg.FareLock.MaxIncreasePercent = 5
+	if percent := v.GetFloat64("FARE_LOCK_MAX_INCREASE_PERCENT"); percent > 0 {
+		cfg.FareLock.MaxIncreasePercent = percent
+	}
+	cfg.FareLock.MaxSurgeChange = 0.2
+	if change := v.GetFloat64("FARE_LOCK_MAX_SURGE_CHANGE"); change > 0 {
+		cfg.FareLock.MaxSurgeChange = change
+	}
+
+	cfg.Inspections.Validity = 180 * 24 * time.Hour
+	if days := v.GetInt("VEHICLE_INSPECTION_VALIDITY_DAYS"); days > 0 {
+		cfg.Inspections.Validity = time.Duration(days) * 24 * time.Hour
+	}
+	cfg.Inspections.ReminderLead = 14 * 24 * time.Hour
+	if days := v.GetInt("VEHICLE_INSPECTION_REMINDER_DAYS"); days > 0 {
+		cfg.Inspections.ReminderLead = time.Duration(days) * 24 * time.Hour
+	}
+	cfg.Inspections.SweepInterval = time.Hour
+	if interval := v.GetDuration("VEHICLE_INSPECTION_SWEEP_INTERVAL"); interval > 0 {
+		cfg.Inspections.SweepInterval = interval * time.Second
+	}
+	cfg.Inspections.MaxFiles = 10
+	if files := v.GetInt("VEHICLE_INSPECTION_MAX_FILES"); files > 0 {
+		cfg.Inspections.MaxFiles = files
+	}
+
+	cfg.Ratings.RollingWindow = 100
+	if window := v.GetInt("RATINGS_ROLLING_WINDOW"); window > 0 {
+		cfg.Ratings.RollingWindow = window
+	}
+	cfg.Ratings.MinDriverRating = 4.0
+	if minRating := v.GetFloat64("RATINGS_MIN_DRIVER_RATING"); minRating > 0 {
+		cfg.Ratings.MinDriverRating = minRating
+	}
+	cfg.Ratings.MinRatingsForMatching = 10
+	if count := v.GetInt("RATINGS_MIN_RATINGS_FOR_MATCHING"); count > 0 {
+		cfg.Ratings.MinRatingsForMatching = count
+	}
+	cfg.Ratings.ReconcileHour = 3
+	if v.GetString("RATINGS_RECONCILE_HOUR") != "" {
+		if hour := v.GetInt("RATINGS_RECONCILE_HOUR"); hour >= 0 && hour < 24 {
+			cfg.Ratings.ReconcileHour = hour
+		}
+	}
+
+	cfg.Favorites.MaxPerUser = 20
+	if max := v.GetInt("FAVORITES_MAX_PER_USER"); max > 0 {
+		cfg.Favorites.MaxPerUser = max
+	}
+	cfg.Favorites.ProviderHeadStart = 2 * time.Minute
+	if v.GetString("FAVORITES_PROVIDER_HEAD_START") != "" {
+		if headStart := v.GetDuration("FAVORITES_PROVIDER_HEAD_START"); headStart >= 0 {
+			cfg.Favorites.ProviderHeadStart = headStart * time.Second
+		}
+	}
+
+	cfg.Referrals.Enabled = true
+	if v.GetString("REFERRALS_ENABLED") != "" {
+		cfg.Referrals.Enabled = v.GetBool("REFERRALS_ENABLED")
+	}
+	cfg.Referrals.ReferrerReward = 200
+	if reward := v.GetFloat64("REFERRAL_REFERRER_REWARD"); reward > 0 {
+		cfg.Referrals.ReferrerReward = reward
+	}
+	cfg.Referrals.RefereeReward = 200
+	if reward := v.GetFloat64("REFERRAL_REFEREE_REWARD"); reward > 0 {
+		cfg.Referrals.RefereeReward = reward
+	}
+	cfg.Referrals.QualifyWindow = 30 * 24 * time.Hour
+	if days := v.GetInt("REFERRAL_QUALIFY_WINDOW_DAYS"); days > 0 {
+		cfg.Referrals.QualifyWindow = time.Duration(days) * 24 * time.Hour
+	}
+	cfg.Referrals.ApplyWindow = 7 * 24 * time.Hour
+	if days := v.GetInt("REFERRAL_APPLY_WINDOW_DAYS"); days > 0 {
+		cfg.Referrals.ApplyWindow = time.Duration(days) * 24 * time.Hour
+	}
+	cfg.Referrals.MaxRewardsPerUser = 50
+	if max := v.GetInt("REFERRAL_MAX_REWARDS_PER_USER"); max > 0 {
+		cfg.Referrals.MaxRewardsPerUser = max
+	}
+
+	cfg.Loyalty.Enabled = true
+	if v.GetString("LOYALTY_ENABLED") != "" {
+		cfg.Loyalty.Enabled = v.GetBool("LOYALTY_ENABLED")
+	}
+	cfg.Loyalty.PointsPerUnit = 0.1
+	if rate := v.GetFloat64("LOYALTY_POINTS_PER_UNIT"); rate > 0 {
+		cfg.Loyalty.PointsPerUnit = rate
+	}
+	cfg.Loyalty.PointValue = 0.25
+	if value := v.GetFloat64("LOYALTY_POINT_VALUE"); value > 0 {
+		cfg.Loyalty.PointValue = value
+	}
+	cfg.Loyalty.MinRedeemPoints = 100
+	if points := v.GetInt64("LOYALTY_MIN_REDEEM_POINTS"); points > 0 {
+		cfg.Loyalty.MinRedeemPoints = points
+	}
+	cfg.Loyalty.PointsExpiry = 365 * 24 * time.Hour
+	if days := v.GetInt("LOYALTY_POINTS_EXPIRY_DAYS"); days > 0 {
+		cfg.Loyalty.PointsExpiry = time.Duration(days) * 24 * time.Hour
+	}
+	cfg.Loyalty.TierWindow = 365 * 24 * time.Hour
+	if days := v.GetInt("LOYALTY_TIER_WINDOW_DAYS"); days > 0 {
+		cfg.Loyalty.TierWindow = time.Duration(days) * 24 * time.Hour
+	}
+	cfg.Loyalty.SilverPoints = 1000
+	if points := v.GetInt64("LOYALTY_SILVER_POINTS"); points > 0 {
+		cfg.Loyalty.SilverPoints = points
+	}
+	cfg.Loyalty.GoldPoints = 5000
+	if points := v.GetInt64("LOYALTY_GOLD_POINTS"); points > 0 {
+		cfg.Loyalty.GoldPoints = points
+	}
+	cfg.Loyalty.SilverFeeDiscount = 50
+	if v.GetString("LOYALTY_SILVER_FEE_DISCOUNT") != "" {
+		if discount := v.GetFloat64("LOYALTY_SILVER_FEE_DISCOUNT"); discount >= 0 && discount <= 100 {
+			cfg.Loyalty.SilverFeeDiscount = discount
+		}
+	}
+	cfg.Loyalty.GoldFeeDiscount = 100
+	if v.GetString("LOYALTY_GOLD_FEE_DISCOUNT") != "" {
+		if discount := v.GetFloat64("LOYALTY_GOLD_FEE_DISCOUNT"); discount >= 0 && discount <= 100 {
+			cfg.Loyalty.GoldFeeDiscount = discount
+		}
+	}
+	cfg.Loyalty.GoldPriorityMatching = true
+	if v.GetString("LOYALTY_GOLD_PRIORITY_MATCHING") != "" {
+		cfg.Loyalty.GoldPriorityMatching = v.GetBool("LOYALTY_GOLD_PRIORITY_MATCHING")
+	}
+	cfg.Loyalty.SweepInterval = time.Hour
+	if interval := v.GetDuration("LOYALTY_SWEEP_INTERVAL"); interval > 0 {
+		cfg.Loyalty.SweepInterval = interval * time.Second
+	}
+
+	cfg.Incentives.Enabled = true
+	if v.GetString("INCENTIVES_ENABLED") != "" {
+		cfg.Incentives.Enabled = v.GetBool("INCENTIVES_ENABLED")
+	}
+	cfg.Incentives.SweepInterval = 5 * time.Minute
+	if interval := v.GetDuration("INCENTIVES_SWEEP_INTERVAL"); interval > 0 {
+		cfg.Incentives.SweepInterval = interval * time.Second
+	}
+	cfg.Incentives.Currency = cfg.Currency.Default
+
+	cfg.Recurring.LeadTime = 48 * time.Hour
+	if hours := v.GetInt("RECURRING_ORDERS_LEAD_HOURS"); hours > 0 {
+		cfg.Recurring.LeadTime = time.Duration(hours) * time.Hour
+	}
+	cfg.Recurring.SweepInterval = 15 * time.Minute
+	if interval := v.GetDuration("RECURRING_ORDERS_SWEEP_INTERVAL"); interval > 0 {
+		cfg.Recurring.SweepInterval = interval * time.Second
+	}
+
+	cfg.Reschedule.MinNotice = 12 * time.Hour
+	if hours := v.GetInt("ORDER_RESCHEDULE_MIN_NOTICE_HOURS"); hours > 0 {
+		cfg.Reschedule.MinNotice = time.Duration(hours) * time.Hour
+	}
+	cfg.Reschedule.MaxPerOrder = 2
+	if max := v.GetInt("ORDER_RESCHEDULE_MAX_PER_ORDER"); max > 0 {
+		cfg.Reschedule.MaxPerOrder = max
+	}
+
+	cfg.LaundryRequote.ApprovalPercent = 10
+	if percent := v.GetFloat64("LAUNDRY_REQUOTE_APPROVAL_PERCENT"); percent > 0 {
+		cfg.LaundryRequote.ApprovalPercent = percent
+	}
+
+	cfg.Deliveries.MaxReschedules = 3
+	if max := v.GetInt("LAUNDRY_DELIVERY_MAX_RESCHEDULES"); max > 0 {
+		cfg.Deliveries.MaxReschedules = max
+	}
+	cfg.Deliveries.MinNotice = 2 * time.Hour
+	if hours := v.GetInt("LAUNDRY_DELIVERY_MIN_NOTICE_HOURS"); hours > 0 {
+		cfg.Deliveries.MinNotice = time.Duration(hours) * time.Hour
+	}
+	cfg.Deliveries.MaxAhead = 14 * 24 * time.Hour
+	if days := v.GetInt("LAUNDRY_DELIVERY_MAX_AHEAD_DAYS"); days > 0 {
+		cfg.Deliveries.MaxAhead = time.Duration(days) * 24 * time.Hour
+	}
+	cfg.Deliveries.SlotMinutes = 30
+	if minutes := v.GetInt("LAUNDRY_DELIVERY_SLOT_MINUTES"); minutes > 0 {
+		cfg.Deliveries.SlotMinutes = minutes
+	}
+	cfg.Deliveries.SlotStartHour = 8
+	if v.IsSet("LAUNDRY_DELIVERY_SLOT_START_HOUR") {
+		cfg.Deliveries.SlotStartHour = v.GetInt("LAUNDRY_DELIVERY_SLOT_START_HOUR")
+	}
+	cfg.Deliveries.SlotEndHour = 21
+	if hour := v.GetInt("LAUNDRY_DELIVERY_SLOT_END_HOUR"); hour > 0 {
+		cfg.Deliveries.SlotEndHour = hour
+	}
+	cfg.Deliveries.FreeFailedAttempts = 1
+	if v.IsSet("LAUNDRY_DELIVERY_FREE_FAILED_ATTEMPTS") {
+		cfg.Deliveries.FreeFailedAttempts = v.GetInt("LAUNDRY_DELIVERY_FREE_FAILED_ATTEMPTS")
+	}
+	cfg.Deliveries.FailedAttemptFee = 5
+	if v.IsSet("LAUNDRY_DELIVERY_FAILED_ATTEMPT_FEE") {
+		cfg.Deliveries.FailedAttemptFee = v.GetFloat64("LAUNDRY_DELIVERY_FAILED_ATTEMPT_FEE")
+	}
+
+	cfg.AuditLog.Retention = 365 * 24 * time.Hour
+	if days := v.GetInt("AUDIT_LOG_RETENTION_DAYS"); days > 0 {
+		cfg.AuditLog.Retention = time.Duration(days) * 24 * time.Hour
+	}
+	cfg.AuditLog.FinancialRetention = 7 * 365 * 24 * time.Hour
+	if days := v.GetInt("AUDIT_LOG_FINANCIAL_RETENTION_DAYS"); days > 0 {
+		cfg.AuditLog.FinancialRetention = time.Duration(days) * 24 * time.Hour
+	}
+
+	cfg.Impersonation.Enabled = true
+	if v.IsSet("IMPERSONATION_ENABLED") {
+		cfg.Impersonation.Enabled = v.GetBool("IMPERSONATION_ENABLED")
+	}
+	cfg.Impersonation.DefaultTTL = 15 * time.Minute
+	if minutes := v.GetInt("IMPERSONATION_DEFAULT_TTL_MINUTES"); minutes > 0 {
+		cfg.Impersonation.DefaultTTL = time.Duration(minutes) * time.Minute
+	}
+	cfg.Impersonation.MaxTTL = time.Hour
+	if minutes := v.GetInt("IMPERSONATION_MAX_TTL_MINUTES"); minutes > 0 {
+		cfg.Impersonation.MaxTTL = time.Duration(minutes) * time.Minute
+	}
+	if cfg.Impersonation.DefaultTTL > cfg.Impersonation.MaxTTL {
+		cfg.Impersonation.DefaultTTL = cfg.Impersonation.MaxTTL
+	}
+
+	cfg.Email.SendGridAPIKey = v.GetString("SENDGRID_API_KEY")
+	cfg.Email.SendGridAPIURL = strings.TrimSuffix(v.GetString("SENDGRID_API_URL"), "/")
+	if cfg.Email.SendGridAPIURL == "" {
+		cfg.Email.SendGridAPIURL = "https://api.sendgrid.com"
+	}
+	cfg.Email.SESRegion = v.GetString("SES_REGION")
+	if cfg.Email.SESRegion == "" {
+		cfg.Email.SESRegion = "us-east-1"
+	}
+	cfg.Email.SESAccessKey = v.GetString("SES_ACCESS_KEY_ID")
+	cfg.Email.SESSecretKey = v.GetString("SES_SECRET_ACCESS_KEY")
+	cfg.Email.SESEndpoint = strings.TrimSuffix(v.GetString("SES_ENDPOINT"), "/")
+	cfg.Email.Provider = strings.ToLower(v.GetString("EMAIL_PROVIDER"))
+	if cfg.Email.Provider == "" {
+		switch {
+		case cfg.Email.SendGridAPIKey != "":
+			// Deployments that only set the SendGrid key for receipts keep
+			// sending through SendGrid.
+			cfg.Email.Provider = "sendgrid"
+		case cfg.App.Environment == "development":
+			cfg.Email.Provider = "mock"
+		default:
+			cfg.Email.Provider = "none"
+		}
+	}
+	cfg.Email.FromEmail = v.GetString("EMAIL_FROM_ADDRESS")
+	if cfg.Email.FromEmail == "" {
+		cfg.Email.FromEmail = v.GetString("SENDGRID_FROM_EMAIL")
+	}
+	cfg.Email.FromName = v.GetString("EMAIL_FROM_NAME")
+	if cfg.Email.FromName == "" {
+		cfg.Email.FromName = v.GetString("SENDGRID_FROM_NAME")
+	}
+	if cfg.Email.FromName == "" {
+		cfg.Email.FromName = cfg.Receipts.CompanyName
+	}
+	cfg.Email.WebhookSecret = v.GetString("EMAIL_WEBHOOK_SECRET")
+	cfg.Email.PublicBaseURL = strings.TrimSuffix(v.GetString("EMAIL_PUBLIC_BASE_URL"), "/")
+	if cfg.Email.PublicBaseURL == "" {
+		cfg.Email.PublicBaseURL = cfg.Quotes.PublicBaseURL
+	}
+	cfg.Email.VerificationTTL = 24 * time.Hour
+	if hours := v.GetInt("EMAIL_VERIFICATION_TTL_HOURS"); hours > 0 {
+		cfg.Email.VerificationTTL = time.Duration(hours) * time.Hour
+	}
+
+	cfg.Archive.Enabled = true
+	if v.IsSet("ARCHIVE_ENABLED") {
+		cfg.Archive.Enabled = v.GetBool("ARCHIVE_ENABLED")
+	}
+	cfg.Archive.After = 180 * 24 * time.Hour
+	if days := v.GetInt("ARCHIVE_AFTER_DAYS"); days > 0 {
+		cfg.Archive.After = time.Duration(days) * 24 * time.Hour
+	}
+	cfg.Archive.BatchSize = 500
+	if size := v.GetInt("ARCHIVE_BATCH_SIZE"); size > 0 {
+		cfg.Archive.BatchSize = size
+	}
+	cfg.Archive.Interval = 6 * time.Hour
+	if minutes := v.GetInt("ARCHIVE_INTERVAL_MINUTES"); minutes > 0 {
+		cfg.Archive.Interval = time.Duration(minutes) * time.Minute
+	}
+
+	cfg.Media.Enabled = cfg.Upload.S3.Bucket != ""
+	if v.IsSet("MEDIA_ENABLED") {
+		cfg.Media.Enabled = v.GetBool("MEDIA_ENABLED")
+	}
+	cfg.Media.MaxSize = 10 << 20
+	if mb := v.GetInt("MEDIA_MAX_SIZE_MB"); mb > 0 {
+		cfg.Media.MaxSize = int64(mb) << 20
+	}
+	cfg.Media.UploadExpiry = 15 * time.Minute
+	if minutes := v.GetInt("MEDIA_UPLOAD_EXPIRY_MINUTES"); minutes > 0 {
+		cfg.Media.UploadExpiry = time.Duration(minutes) * time.Minute
+	}
+	cfg.Media.ThumbnailSize = 320
+	if size := v.GetInt("MEDIA_THUMBNAIL_SIZE"); size > 0 {
+		cfg.Media.ThumbnailSize = size
+	}
+	cfg.Media.OrphanAfter = 24 * time.Hour
+	if hours := v.GetInt("MEDIA_ORPHAN_AFTER_HOURS"); hours > 0 {
+		cfg.Media.OrphanAfter = time.Duration(hours) * time.Hour
+	}
+	cfg.Media.CleanupInterval = time.Hour
+	if minutes := v.GetInt("MEDIA_CLEANUP_INTERVAL_MINUTES"); minutes > 0 {
+		cfg.Media.CleanupInterval = time.Duration(minutes) * time.Minute
+	}
+
+	cfg.Privacy.DeletionGracePeriod = 30 * 24 * time.Hour
+	if days := v.GetInt("PRIVACY_DELETION_GRACE_DAYS"); days > 0 {
+		cfg.Privacy.DeletionGracePeriod = time.Duration(days) * 24 * time.Hour
+	}
+	cfg.Privacy.ExportTTL = 7 * 24 * time.Hour
+	if hours := v.GetInt("PRIVACY_EXPORT_TTL_HOURS"); hours > 0 {
+		cfg.Privacy.ExportTTL = time.Duration(hours) * time.Hour
+	}
+	cfg.Privacy.WorkerInterval = 5 * time.Minute
+	if minutes := v.GetInt("PRIVACY_WORKER_INTERVAL_MINUTES"); minutes > 0 {
+		cfg.Privacy.WorkerInterval = time.Duration(minutes) * time.Minute
+	}
+
+	cfg.Partners.SigningKey = v.GetString("PARTNER_SIGNING_KEY")
+	cfg.Partners.MaxClockSkew = 5 * time.Minute
+	if seconds := v.GetInt("PARTNER_MAX_CLOCK_SKEW_SECONDS"); seconds > 0 {
+		cfg.Partners.MaxClockSkew = time.Duration(seconds) * time.Second
+	}
+	cfg.Partners.DefaultRateLimitPerMinute = 120
+	if perMinute := v.GetInt("PARTNER_DEFAULT_RATE_LIMIT_PER_MINUTE"); perMinute > 0 {
+		cfg.Partners.DefaultRateLimitPerMinute = perMinute
+	}
+	cfg.Partners.UsageFlushInterval = time.Minute
+	if seconds := v.GetInt("PARTNER_USAGE_FLUSH_INTERVAL_SECONDS"); seconds > 0 {
+		cfg.Partners.UsageFlushInterval = time.Duration(seconds) * time.Second
+	}
+
+	cfg.Webhooks.WorkerInterval = 5 * time.Second
+	if seconds := v.GetInt("WEBHOOK_WORKER_INTERVAL_SECONDS"); seconds > 0 {
+		cfg.Webhooks.WorkerInterval = time.Duration(seconds) * time.Second
+	}
+	cfg.Webhooks.BatchSize = 50
+	if size := v.GetInt("WEBHOOK_BATCH_SIZE"); size > 0 {
+		cfg.Webhooks.BatchSize = size
+	}
+	cfg.Webhooks.MaxAttempts = 10
+	if attempts := v.GetInt("WEBHOOK_MAX_ATTEMPTS"); attempts > 0 {
+		cfg.Webhooks.MaxAttempts = attempts
+	}
+	cfg.Webhooks.InitialBackoff = 30 * time.Second
+	if seconds := v.GetInt("WEBHOOK_INITIAL_BACKOFF_SECONDS"); seconds > 0 {
+		cfg.Webhooks.InitialBackoff = time.Duration(seconds) * time.Second
+	}
+	cfg.Webhooks.MaxBackoff = 6 * time.Hour
+	if minutes := v.GetInt("WEBHOOK_MAX_BACKOFF_MINUTES"); minutes > 0 {
+		cfg.Webhooks.MaxBackoff = time.Duration(minutes) * time.Minute
+	}
+	cfg.Webhooks.RequestTimeout = 10 * time.Second
+	if seconds := v.GetInt("WEBHOOK_REQUEST_TIMEOUT_SECONDS"); seconds > 0 {
+		cfg.Webhooks.RequestTimeout = time.Duration(seconds) * time.Second
+	}
+	cfg.Webhooks.AllowInsecureURLs = v.GetBool("WEBHOOK_ALLOW_INSECURE_URLS")
+
+	cfg.EventBus.Driver = strings.ToLower(v.GetString("EVENT_BUS_DRIVER"))
+	if cfg.EventBus.Driver == "" {
+		cfg.EventBus.Driver = "memory"
+	}
+	cfg.EventBus.Prefix = v.GetString("EVENT_BUS_PREFIX")
+	if cfg.EventBus.Prefix == "" {
+		cfg.EventBus.Prefix = "events"
+	}
+	cfg.EventBus.MaxRetries = 5
+	if retries := v.GetInt("EVENT_BUS_MAX_RETRIES"); retries > 0 {
+		cfg.EventBus.MaxRetries = retries
+	}
+	cfg.EventBus.RetryBackoff = time.Second
+	if ms := v.GetInt("EVENT_BUS_RETRY_BACKOFF_MS"); ms > 0 {
+		cfg.EventBus.RetryBackoff = time.Duration(ms) * time.Millisecond
+	}
+	cfg.EventBus.StreamMaxLen = 100000
+	if maxLen := v.GetInt64("EVENT_BUS_STREAM_MAX_LEN"); maxLen > 0 {
+		cfg.EventBus.StreamMaxLen = maxLen
+	}
+	cfg.EventBus.ConsumerName = v.GetString("EVENT_BUS_CONSUMER_NAME")
+	if cfg.EventBus.ConsumerName == "" {
+		cfg.EventBus.ConsumerName, _ = os.Hostname()
+	}
+
+	cfg.Worker.RunJobsInAPI = true
+	if v.IsSet("RUN_JOBS_IN_API") {
+		cfg.Worker.RunJobsInAPI = v.GetBool("RUN_JOBS_IN_API")
+	}
+	cfg.Worker.HealthPort = v.GetInt("WORKER_HEALTH_PORT")
+	if cfg.Worker.HealthPort == 0 {
+		cfg.Worker.HealthPort = 8081
+	}
+	cfg.Worker.HoldExpiryInterval = time.Minute
+	if seconds := v.GetInt("WALLET_HOLD_EXPIRY_INTERVAL_SECONDS"); seconds > 0 {
+		cfg.Worker.HoldExpiryInterval = time.Duration(seconds) * time.Second
+	}
+	cfg.Worker.LedgerReconcileInterval = time.Hour
+	if seconds := v.GetInt("WALLET_LEDGER_RECONCILE_INTERVAL_SECONDS"); seconds > 0 {
+		cfg.Worker.LedgerReconcileInterval = time.Duration(seconds) * time.Second
+	}
+
+	cfg.FeatureFlags.RefreshInterval = 30 * time.Second
+	if seconds := v.GetInt("FEATURE_FLAGS_REFRESH_SECONDS"); seconds > 0 {
+		cfg.FeatureFlags.RefreshInterval = time.Duration(seconds) * time.Second
+	}
+
+	cfg.Startup.MaxAttempts = 5
+	if attempts := v.GetInt("STARTUP_MAX_ATTEMPTS"); attempts > 0 {
+		cfg.Startup.MaxAttempts = attempts
+	}
+	cfg.Startup.InitialBackoff = time.Second
+	if backoff := v.GetDuration("STARTUP_INITIAL_BACKOFF"); backoff > 0 {
+		cfg.Startup.InitialBackoff = backoff * time.Second
+	}
+	cfg.Startup.MaxBackoff = 15 * time.Second
+	if maxBackoff := v.GetDuration("STARTUP_MAX_BACKOFF"); maxBackoff > 0 {
+		cfg.Startup.MaxBackoff = maxBackoff * time.Second
+	}
+	if required := v.GetString("STARTUP_REQUIRED_COMPONENTS"); required != "" {
+		cfg.Startup.RequiredComponents = strings.Split(required, ",")
+	}
+	if optional := v.GetString("STARTUP_OPTIONAL_COMPONENTS"); optional != "" {
+		cfg.Startup.OptionalComponents = strings.Split(optional, ",")
+	}
+
 	return &cfg, nil
 }
 
@@ -263,5 +1176,117 @@ func (c *Config) Validate() error {
 	if c.Redis.Host == "" {
 		return fmt.Errorf("REDIS_HOST is required")
 	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		return fmt.Errorf("TRACING_SAMPLE_RATIO must be between 0 and 1")
+	}
+	if c.PublicEstimate.Enabled {
+		if len(c.PublicEstimate.APIKeys) == 0 {
+			return fmt.Errorf("PUBLIC_ESTIMATE_API_KEYS is required when PUBLIC_ESTIMATE_ENABLED is set")
+		}
+		if c.PublicEstimate.CaptchaSecret == "" {
+			return fmt.Errorf("PUBLIC_ESTIMATE_CAPTCHA_SECRET is required when PUBLIC_ESTIMATE_ENABLED is set")
+		}
+	}
+	if c.Insurance.Enabled && c.Insurance.ProviderURL != "" && c.Insurance.ProviderAPIKey == "" {
+		return fmt.Errorf("INSURANCE_PROVIDER_API_KEY is required when INSURANCE_PROVIDER_URL is set")
+	}
+	if c.Payments.Enabled {
+		if c.Payments.StripeSecretKey == "" {
+			return fmt.Errorf("STRIPE_SECRET_KEY is required when PAYMENTS_ENABLED is set")
+		}
+		if c.Payments.StripeWebhookSecret == "" {
+			return fmt.Errorf("STRIPE_WEBHOOK_SECRET is required when PAYMENTS_ENABLED is set")
+		}
+		if c.Payments.MinTopUp > c.Payments.MaxTopUp {
+			return fmt.Errorf("WALLET_TOPUP_MIN_AMOUNT must not exceed WALLET_TOPUP_MAX_AMOUNT")
+		}
+	}
+	if c.Safety.SMSEnabled {
+		if c.Safety.TwilioAccountSID == "" || c.Safety.TwilioAuthToken == "" || c.Safety.SMSFrom == "" {
+			return fmt.Errorf("TWILIO_ACCOUNT_SID, TWILIO_AUTH_TOKEN and SOS_SMS_FROM are required when SOS_SMS_ENABLED is set")
+		}
+	}
+	if c.MaskedCalling.Enabled {
+		if c.MaskedCalling.Provider != "twilio" {
+			return fmt.Errorf("MASKED_CALLING_PROVIDER must be twilio")
+		}
+		if c.MaskedCalling.TwilioAccountSID == "" || c.MaskedCalling.TwilioAuthToken == "" || c.MaskedCalling.TwilioProxyServiceSID == "" {
+			return fmt.Errorf("TWILIO_ACCOUNT_SID, TWILIO_AUTH_TOKEN and TWILIO_PROXY_SERVICE_SID are required when MASKED_CALLING_ENABLED is set")
+		}
+		if c.MaskedCalling.WebhookURL == "" {
+			return fmt.Errorf("MASKED_CALLING_WEBHOOK_URL is required when MASKED_CALLING_ENABLED is set")
+		}
+	}
+	switch c.Email.Provider {
+	case "none", "mock":
+	case "sendgrid":
+		if c.Email.SendGridAPIKey == "" {
+			return fmt.Errorf("SENDGRID_API_KEY is required when EMAIL_PROVIDER is sendgrid")
+		}
+	case "ses":
+		if c.Email.SESAccessKey == "" || c.Email.SESSecretKey == "" {
+			return fmt.Errorf("SES_ACCESS_KEY_ID and SES_SECRET_ACCESS_KEY are required when EMAIL_PROVIDER is ses")
+		}
+	default:
+		return fmt.Errorf("EMAIL_PROVIDER must be one of none, mock, sendgrid, ses")
+	}
+	if c.Email.Provider != "none" && c.Email.Provider != "mock" && c.Email.FromEmail == "" {
+		return fmt.Errorf("EMAIL_FROM_ADDRESS is required when EMAIL_PROVIDER is %s", c.Email.Provider)
+	}
+	switch c.Routing.Provider {
+	case "none", "osrm":
+	case "google":
+		if c.Routing.GoogleAPIKey == "" {
+			return fmt.Errorf("GOOGLE_MAPS_API_KEY is required when ROUTING_PROVIDER is google")
+		}
+	default:
+		return fmt.Errorf("ROUTING_PROVIDER must be one of none, osrm, google")
+	}
+	switch c.Geocoding.Provider {
+	case "none", "nominatim":
+	case "google":
+		if c.Geocoding.GoogleAPIKey == "" {
+			return fmt.Errorf("GOOGLE_MAPS_API_KEY is required when GEOCODING_PROVIDER is google")
+		}
+	case "mapbox":
+		if c.Geocoding.MapboxToken == "" {
+			return fmt.Errorf("MAPBOX_ACCESS_TOKEN is required when GEOCODING_PROVIDER is mapbox")
+		}
+	default:
+		return fmt.Errorf("GEOCODING_PROVIDER must be one of none, google, mapbox, nominatim")
+	}
+	switch c.EventBus.Driver {
+	case "memory", "redis":
+	case "kafka":
+		if len(c.Kafka.Brokers) == 0 {
+			return fmt.Errorf("KAFKA_BROKERS is required when EVENT_BUS_DRIVER is kafka")
+		}
+	default:
+		return fmt.Errorf("EVENT_BUS_DRIVER must be one of memory, redis, kafka")
+	}
+	if !c.Worker.RunJobsInAPI && c.EventBus.Driver == "memory" {
+		return fmt.Errorf("EVENT_BUS_DRIVER must be redis or kafka when RUN_JOBS_IN_API is false")
+	}
+	if c.AuditLog.FinancialRetention < c.AuditLog.Retention {
+		return fmt.Errorf("AUDIT_LOG_FINANCIAL_RETENTION_DAYS must not be shorter than AUDIT_LOG_RETENTION_DAYS")
+	}
+	if c.Archive.Enabled && c.Archive.After < 30*24*time.Hour {
+		return fmt.Errorf("ARCHIVE_AFTER_DAYS must be at least 30")
+	}
+	if c.Media.Enabled {
+		if c.Upload.S3.Bucket == "" || c.Upload.S3.AccessKey == "" || c.Upload.S3.SecretKey == "" {
+			return fmt.Errorf("S3_BUCKET, S3_ACCESS_KEY and S3_SECRET_KEY are required when media uploads are enabled")
+		}
+		if c.Media.OrphanAfter <= c.Media.UploadExpiry {
+			return fmt.Errorf("MEDIA_ORPHAN_AFTER_HOURS must be longer than the upload URL expiry")
+		}
+	}
+	for _, required := range c.Startup.RequiredComponents {
+		for _, optional := range c.Startup.OptionalComponents {
+			if strings.TrimSpace(required) == strings.TrimSpace(optional) {
+				return fmt.Errorf("startup component %q cannot be both required and optional", strings.TrimSpace(required))
+			}
+		}
+	}
 	return nil
 }
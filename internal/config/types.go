@@ -17,6 +17,49 @@ type Config struct {
 	WebSocket WebSocketConfig
 	Kafka     KafkaConfig
 	Firebase  FirebaseConfig
+	Fees      FeeDisplayConfig
+	Currency  CurrencyConfig
+	Tracing   TracingConfig
+
+	PublicEstimate PublicEstimateConfig
+	Insurance      InsuranceConfig
+	Payments       PaymentsConfig
+	Receipts       ReceiptsConfig
+	Quotes         QuotesConfig
+	Routing        RoutingConfig
+	Geocoding      GeocodingConfig
+	PayoutRetry    PayoutRetryConfig
+	DriverCredit   DriverCreditConfig
+	Settlement     SettlementConfig
+	TripCheck      TripVerificationConfig
+	Dispatch       DispatchConfig
+	MaskedCalling  MaskedCallingConfig
+	Safety         SafetyConfig
+	TripSharing    TripSharingConfig
+	Arrival        ArrivalConfig
+	FareLock       FareLockConfig
+	Inspections    VehicleInspectionConfig
+	Ratings        RatingsConfig
+	Favorites      FavoritesConfig
+	Referrals      ReferralConfig
+	Loyalty        LoyaltyConfig
+	Incentives     IncentivesConfig
+	Recurring      RecurringOrdersConfig
+	Reschedule     RescheduleConfig
+	LaundryRequote LaundryRequoteConfig
+	Deliveries     LaundryDeliveryConfig
+	AuditLog       AuditLogConfig
+	Impersonation  ImpersonationConfig
+	Email          EmailConfig
+	Archive        ArchiveConfig
+	Media          MediaConfig
+	Privacy        PrivacyConfig
+	Partners       PartnersConfig
+	Webhooks       WebhooksConfig
+	EventBus       EventBusConfig
+	Worker         WorkerConfig
+	FeatureFlags   FeatureFlagsConfig
+	Startup        StartupConfig
 }
 
 type AppConfig struct {
@@ -44,9 +87,28 @@ type CORSConfig struct {
 	Enabled          bool
 }
 
+// RateLimitConfig sets per-minute request allowances, counted per user for
+// authenticated requests and per client IP otherwise. Requests matching one of
+// the Classes count against that class's allowance; everything else counts
+// against Default.
 type RateLimitConfig struct {
-	RequestsPerSecond int
-	Burst             int
+	Enabled bool
+	Default RateLimitPolicy
+	Classes []RateLimitClass
+}
+
+type RateLimitPolicy struct {
+	Requests int
+	Window   time.Duration
+}
+
+// RateLimitClass groups routes sharing one allowance. Routes are "METHOD
+// /path" patterns matched against the registered route; a trailing * matches
+// any path suffix and a * method matches every method.
+type RateLimitClass struct {
+	Name   string
+	Policy RateLimitPolicy
+	Routes []string
 }
 
 type DatabaseConfig struct {
@@ -60,6 +122,12 @@ type DatabaseConfig struct {
 	MaxIdleConns int
 	MaxLifetime  time.Duration
 	LogLevel     logger.LogLevel
+	// ReplicaDSNs are read replicas for queries marked safe to read from a
+	// replica. Empty means everything goes to the primary.
+	ReplicaDSNs []string
+	// SchemaCheck refuses to start when the database has not been migrated
+	// to the version this binary was built with.
+	SchemaCheck bool
 }
 
 type RedisConfig struct {
@@ -94,6 +162,10 @@ type S3Config struct {
 	Region    string
 	AccessKey string
 	SecretKey string
+	// Endpoint overrides the provider's default host, e.g. for MinIO.
+	Endpoint string
+	// PublicURL is the CDN or bucket URL media links are built from.
+	PublicURL string
 }
 
 type ImageKitConfig struct {
@@ -106,6 +178,505 @@ type ImageKitConfig struct {
 	BannersMaxSize   int64
 }
 
+// FeeDisplayConfig controls which parts of a fee breakdown customers see.
+// Drivers, providers and admins always receive the full split.
+type FeeDisplayConfig struct {
+	ShowPlatformFee  bool
+	ShowEarnerShare  bool
+	PlatformFeeLabel string
+	Currency         string
+}
+
+// CurrencyConfig sets the platform's base currency, used wherever no city
+// currency applies, and the feed exchange rates are refreshed from. Provider
+// "none" leaves rates to be maintained by admins.
+type CurrencyConfig struct {
+	Default         string
+	RateProvider    string
+	RateAPIURL      string
+	RateAPIKey      string
+	RefreshInterval time.Duration
+	Timeout         time.Duration
+}
+
+type TracingConfig struct {
+	Enabled       bool
+	ServiceName   string
+	OTLPEndpoint  string
+	OTLPHeaders   map[string]string
+	SampleRatio   float64
+	ExportTimeout time.Duration
+}
+
+// PublicEstimateConfig guards the unauthenticated fare estimate used by the
+// marketing web widget.
+type PublicEstimateConfig struct {
+	Enabled              bool
+	APIKeys              []string
+	CaptchaSecret        string
+	CaptchaVerifyURL     string
+	IPRequestsPerMinute  int
+	KeyRequestsPerMinute int
+	RoundTo              float64
+	Currency             string
+}
+
+// InsuranceConfig controls per-trip policy issuance. Without a ProviderURL
+// policy numbers are generated internally.
+type InsuranceConfig struct {
+	Enabled        bool
+	ProviderName   string
+	ProviderURL    string
+	ProviderAPIKey string
+	PolicyPrefix   string
+	CoverageAmount float64
+	PremiumPerTrip float64
+	Currency       string
+	ClaimWindow    time.Duration
+}
+
+// PaymentsConfig configures the card gateway used for wallet top-ups.
+type PaymentsConfig struct {
+	Enabled              bool
+	Gateway              string
+	StripeSecretKey      string
+	StripePublishableKey string
+	StripeWebhookSecret  string
+	StripeAPIURL         string
+	Currency             string
+	MinTopUp             float64
+	MaxTopUp             float64
+	WebhookTolerance     time.Duration
+}
+
+// ReceiptsConfig sets the issuer details printed on ride receipts. Receipts
+// are only emailed when an email provider is configured.
+type ReceiptsConfig struct {
+	CompanyName     string
+	CompanyAddress  string
+	TaxID           string
+	NumberPrefix    string
+	EmailOnComplete bool
+}
+
+// QuotesConfig controls home service quotes. Share links are signed with
+// SigningSecret and are only valid until the link TTL runs out; PublicBaseURL
+// makes them absolute.
+type QuotesConfig struct {
+	NumberPrefix  string
+	Validity      time.Duration
+	LinkTTL       time.Duration
+	SigningSecret string
+	PublicBaseURL string
+	Terms         []string
+}
+
+// RoutingConfig selects the directions backend used for ETAs and fare
+// distances. Provider "none" keeps the straight-line estimate.
+type RoutingConfig struct {
+	Provider     string
+	OSRMURL      string
+	GoogleAPIKey string
+	GoogleAPIURL string
+	Timeout      time.Duration
+	CacheTTL     time.Duration
+}
+
+// GeocodingConfig selects the backend behind place search, reverse geocoding
+// and address normalization. Provider "none" turns them off. Countries, as
+// ISO 3166 alpha-2 codes, restrict search results.
+type GeocodingConfig struct {
+	Provider              string
+	GoogleAPIKey          string
+	GoogleAPIURL          string
+	MapboxToken           string
+	MapboxURL             string
+	NominatimURL          string
+	UserAgent             string
+	Countries             []string
+	Language              string
+	Timeout               time.Duration
+	CacheTTL              time.Duration
+	UserRequestsPerMinute int
+}
+
+// PayoutRetryConfig controls the queue of provider payout credits that failed
+// on the first try. A credit still failing after MaxAttempts is left for an
+// admin to resolve.
+type PayoutRetryConfig struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Interval       time.Duration
+}
+
+// DriverCreditConfig sets how far into debt a driver wallet may go, mostly
+// through commission on cash rides. Limits are positive amounts per driver
+// tier; a driver whose debt exceeds their limit is restricted and cannot go
+// online. Drivers in a tier missing from TierLimits get DefaultTier's limit.
+type DriverCreditConfig struct {
+	DefaultTier string
+	TierLimits  map[string]float64
+}
+
+// Limit is the credit limit of tier.
+func (c DriverCreditConfig) Limit(tier string) float64 {
+	if limit, ok := c.TierLimits[tier]; ok {
+		return limit
+	}
+	return c.TierLimits[c.DefaultTier]
+}
+
+// SettlementConfig turns on weekly provider settlements. When enabled, order
+// payouts accrue instead of being credited on completion, and each week's
+// total is credited once an admin approves it. A provider whose total is
+// below MinPayout carries it over to the next week.
+type SettlementConfig struct {
+	Enabled   bool
+	MinPayout float64
+	Interval  time.Duration
+}
+
+// DispatchConfig sets how a ride is offered to the drivers found for it:
+// one at a time ("sequential"), BatchSize at a time ("batch") or all at once
+// ("broadcast"). Each offer waits OfferTimeout for an answer, or the timeout
+// set for the ride's vehicle type, keyed by vehicle type name. Cities can
+// override the mode, batch size and per-vehicle timeouts.
+type DispatchConfig struct {
+	Mode                 string
+	BatchSize            int
+	OfferTimeout         time.Duration
+	VehicleOfferTimeouts map[string]time.Duration
+}
+
+// TripVerificationConfig sets how far a driver's reported ride distance and
+// duration may stray from the server's measurement before the ride is flagged.
+// A value is only out of tolerance when it exceeds both the percentage and the
+// absolute allowance, so short rides are not flagged over a few hundred metres.
+type TripVerificationConfig struct {
+	DistanceTolerancePct float64
+	DistanceToleranceKm  float64
+	DurationTolerancePct float64
+	DurationTolerance    time.Duration
+	MinTrackPoints       int
+}
+
+// MaskedCallingConfig connects customers with their driver or provider
+// through proxy numbers so neither side sees the other's phone number.
+// Sessions stay open for PostTripWindow after the ride or order ends and are
+// never kept longer than SessionTTL.
+type MaskedCallingConfig struct {
+	Enabled               bool
+	Provider              string
+	TwilioAccountSID      string
+	TwilioAuthToken       string
+	TwilioProxyServiceSID string
+	TwilioProxyAPIURL     string
+	WebhookURL            string
+	PostTripWindow        time.Duration
+	SessionTTL            time.Duration
+	SweepInterval         time.Duration
+}
+
+// SafetyConfig controls the SMS side of SOS alerts. Without it, incidents
+// still reach admins over WebSocket.
+type SafetyConfig struct {
+	SMSEnabled       bool
+	TwilioAccountSID string
+	TwilioAuthToken  string
+	TwilioAPIURL     string
+	SMSFrom          string
+	SafetyTeamPhones []string
+}
+
+// TripSharingConfig controls the public links riders send to friends to
+// follow a trip. Links are signed with SigningSecret and stop working after
+// LinkTTL; live viewers are sent an update every UpdateInterval.
+type TripSharingConfig struct {
+	LinkTTL        time.Duration
+	SigningSecret  string
+	PublicBaseURL  string
+	UpdateInterval time.Duration
+}
+
+// ArrivalConfig controls geofenced arrival detection. A driver within
+// DriverRadiusMeters of the pickup, or a provider within ProviderRadiusMeters
+// of the service address, is marked arrived from their location updates.
+// Fixes less accurate than MaxAccuracyMeters are not trusted.
+type ArrivalConfig struct {
+	Enabled              bool
+	DriverRadiusMeters   float64
+	ProviderRadiusMeters float64
+	MaxAccuracyMeters    float64
+}
+
+// FareLockConfig controls the fare lock handed out with each estimate. A
+// ride must be booked with a lock when Required is set. The locked fare is
+// honoured for TTL, unless the fare has since risen more than
+// MaxIncreasePercent or surge rose by MaxSurgeChange or more, in which case
+// the rider is asked to confirm a new quote.
+type FareLockConfig struct {
+	Required           bool
+	TTL                time.Duration
+	MaxIncreasePercent float64
+	MaxSurgeChange     float64
+}
+
+// VehicleInspectionConfig sets how long an approved vehicle inspection lasts
+// and how far ahead of the due date drivers are reminded. The sweep blocks
+// vehicles whose inspection has lapsed or gone overdue.
+type VehicleInspectionConfig struct {
+	Validity      time.Duration
+	ReminderLead  time.Duration
+	SweepInterval time.Duration
+	MaxFiles      int
+}
+
+// RatingsConfig sets how ride ratings are averaged and when a driver is too
+// low rated to be offered rides. A profile's rating is the mean of its last
+// RollingWindow ratings; drivers with fewer than MinRatingsForMatching are
+// never held back, so one bad first trip does not bench a new driver.
+// Stored provider and customer ratings are reconciled against orders daily at
+// ReconcileHour, in UTC.
+type RatingsConfig struct {
+	RollingWindow         int
+	MinDriverRating       float64
+	MinRatingsForMatching int
+	ReconcileHour         int
+}
+
+// FavoritesConfig limits how many favorite drivers and preferred providers a
+// user can keep. A new home-service order is shown only to the customer's
+// preferred providers for ProviderHeadStart, provided one of them is free to
+// take it.
+type FavoritesConfig struct {
+	MaxPerUser        int
+	ProviderHeadStart time.Duration
+}
+
+// ReferralConfig sets the referral program's rewards. Both sides are credited
+// once the referred user completes their first ride or order, provided that
+// happens within QualifyWindow of signing up. A code can still be applied up
+// to ApplyWindow after signup. A referrer earns for at most MaxRewardsPerUser
+// referrals.
+type ReferralConfig struct {
+	Enabled           bool
+	ReferrerReward    float64
+	RefereeReward     float64
+	QualifyWindow     time.Duration
+	ApplyWindow       time.Duration
+	MaxRewardsPerUser int
+}
+
+// LoyaltyConfig sets how customers earn and spend loyalty points. Completed
+// rides and orders earn PointsPerUnit points per unit of currency spent, and
+// points lapse PointsExpiry after they were earned. A customer's tier follows
+// the points they earned in the last TierWindow: SilverPoints and GoldPoints
+// are the thresholds. Silver and gold take a percentage off the ride booking
+// fee, and gold riders can be given priority matching. Redeemed points are
+// credited to the wallet at PointValue each.
+type LoyaltyConfig struct {
+	Enabled              bool
+	PointsPerUnit        float64
+	PointValue           float64
+	MinRedeemPoints      int64
+	PointsExpiry         time.Duration
+	TierWindow           time.Duration
+	SilverPoints         int64
+	GoldPoints           int64
+	SilverFeeDiscount    float64
+	GoldFeeDiscount      float64
+	GoldPriorityMatching bool
+	SweepInterval        time.Duration
+}
+
+// IncentivesConfig turns driver incentive campaigns on and sets how often
+// rewards whose wallet credit failed are retried. Currency is what campaigns
+// pay in when they do not name one.
+type IncentivesConfig struct {
+	Enabled       bool
+	SweepInterval time.Duration
+	Currency      string
+}
+
+// RecurringOrdersConfig sets how far ahead occurrences of recurring home
+// service bookings are turned into orders and how often the scheduler looks
+// for due ones.
+type RecurringOrdersConfig struct {
+	LeadTime      time.Duration
+	SweepInterval time.Duration
+}
+
+// RescheduleConfig limits how home-service bookings can be moved: both the
+// current and the new slot must be at least MinNotice away, and an order can
+// be moved at most MaxPerOrder times.
+type RescheduleConfig struct {
+	MinNotice   time.Duration
+	MaxPerOrder int
+}
+
+// LaundryRequoteConfig sets when a re-weighed laundry order needs the
+// customer's approval: increases above ApprovalPercent of the booked total.
+type LaundryRequoteConfig struct {
+	ApprovalPercent float64
+}
+
+// LaundryDeliveryConfig limits how laundry deliveries can be moved. A new
+// slot starts on a SlotMinutes boundary between SlotStartHour and SlotEndHour,
+// at least MinNotice and at most MaxAhead away, and a delivery can be moved
+// MaxReschedules times. Failed delivery attempts beyond FreeFailedAttempts
+// each add FailedAttemptFee to the order.
+type LaundryDeliveryConfig struct {
+	MaxReschedules     int
+	MinNotice          time.Duration
+	MaxAhead           time.Duration
+	SlotMinutes        int
+	SlotStartHour      int
+	SlotEndHour        int
+	FreeFailedAttempts int
+	FailedAttemptFee   float64
+}
+
+// AuditLogConfig sets how long audit records are kept. Money movements fall
+// under FinancialRetention, which is usually dictated by bookkeeping rules and
+// is longer than the window for other admin actions.
+type AuditLogConfig struct {
+	Retention          time.Duration
+	FinancialRetention time.Duration
+}
+
+// ImpersonationConfig bounds the tokens support admins get to act as a
+// user. A session lasts DefaultTTL unless the admin asks for less or more,
+// and never longer than MaxTTL.
+type ImpersonationConfig struct {
+	Enabled    bool
+	DefaultTTL time.Duration
+	MaxTTL     time.Duration
+}
+
+// EmailConfig selects how transactional email is sent. Provider "mock" only
+// logs messages, for local development, and "none" turns email off. Links in
+// emails point at PublicBaseURL, and delivery webhooks must carry
+// WebhookSecret.
+type EmailConfig struct {
+	Provider        string
+	FromEmail       string
+	FromName        string
+	SendGridAPIKey  string
+	SendGridAPIURL  string
+	SESRegion       string
+	SESAccessKey    string
+	SESSecretKey    string
+	SESEndpoint     string
+	WebhookSecret   string
+	PublicBaseURL   string
+	VerificationTTL time.Duration
+}
+
+// ArchiveConfig controls the job that moves finished rides and orders out of
+// the live tables. Records older than After are moved in batches of
+// BatchSize every Interval.
+type ArchiveConfig struct {
+	Enabled   bool
+	After     time.Duration
+	BatchSize int
+	Interval  time.Duration
+}
+
+// MediaConfig controls direct-to-bucket uploads. Clients get a presigned URL
+// valid for UploadExpiry; images get a thumbnail fitting ThumbnailSize pixels.
+// Uploads never completed or attached, and media whose entity is gone, are
+// deleted after OrphanAfter by a job running every CleanupInterval.
+type MediaConfig struct {
+	Enabled         bool
+	MaxSize         int64
+	UploadExpiry    time.Duration
+	ThumbnailSize   int
+	OrphanAfter     time.Duration
+	CleanupInterval time.Duration
+}
+
+// PrivacyConfig controls data exports and account deletion. Deletion requests
+// are carried out after DeletionGracePeriod unless the user cancels; export
+// archives can be downloaded until ExportTTL has passed. The worker picks up
+// due requests every WorkerInterval.
+type PrivacyConfig struct {
+	DeletionGracePeriod time.Duration
+	ExportTTL           time.Duration
+	WorkerInterval      time.Duration
+}
+
+// PartnersConfig controls API keys for server-to-server partner integrations.
+// SigningKey encrypts the secrets of keys that must sign their requests; such
+// keys cannot be issued without it. Signed requests are rejected when their
+// timestamp is more than MaxClockSkew off. Usage counts are written out every
+// UsageFlushInterval.
+type PartnersConfig struct {
+	SigningKey                string
+	MaxClockSkew              time.Duration
+	DefaultRateLimitPerMinute int
+	UsageFlushInterval        time.Duration
+}
+
+// WebhooksConfig controls delivery of events to webhook subscriptions. Due
+// deliveries are picked up every WorkerInterval, BatchSize at a time. A failed
+// delivery is retried after InitialBackoff, doubling up to MaxBackoff, and
+// moves to the dead-letter list after MaxAttempts. AllowInsecureURLs permits
+// plain http endpoints, for local development.
+type WebhooksConfig struct {
+	WorkerInterval    time.Duration
+	BatchSize         int
+	MaxAttempts       int
+	InitialBackoff    time.Duration
+	MaxBackoff        time.Duration
+	RequestTimeout    time.Duration
+	AllowInsecureURLs bool
+}
+
+// EventBusConfig selects how domain events travel between modules. The
+// "memory" driver delivers within the process; "redis" (streams) and "kafka"
+// let consumers in other processes share the work. Names are prefixed with
+// Prefix. A failing handler is retried MaxRetries times, starting
+// RetryBackoff apart. Redis streams are trimmed to about StreamMaxLen entries.
+// ConsumerName identifies this process within Redis consumer groups.
+type EventBusConfig struct {
+	Driver       string
+	Prefix       string
+	MaxRetries   int
+	RetryBackoff time.Duration
+	StreamMaxLen int64
+	ConsumerName string
+}
+
+// WorkerConfig decides where background jobs run. With RunJobsInAPI set the
+// API process runs them itself; otherwise they are left to cmd/worker, which
+// serves its health check on HealthPort. HoldExpiryInterval is how often
+// expired wallet holds are released, and LedgerReconcileInterval how often
+// the wallet ledger is checked for drift.
+type WorkerConfig struct {
+	RunJobsInAPI            bool
+	HealthPort              int
+	HoldExpiryInterval      time.Duration
+	LedgerReconcileInterval time.Duration
+}
+
+// FeatureFlagsConfig controls how often each process reloads feature flags as
+// a fallback to the change notifications sent over Redis.
+type FeatureFlagsConfig struct {
+	RefreshInterval time.Duration
+}
+
+// StartupConfig controls how the server brings up its dependencies. Components
+// listed as optional let the server start degraded when they fail; required
+// ones abort startup once their retries are exhausted.
+type StartupConfig struct {
+	MaxAttempts        int
+	InitialBackoff     time.Duration
+	MaxBackoff         time.Duration
+	RequiredComponents []string
+	OptionalComponents []string
+}
+
 type LoggerConfig struct {
 	Level    string
 	Format   string
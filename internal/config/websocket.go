@@ -19,25 +19,43 @@ type WebSocketConfig struct {
 	PersistenceMode     string        `mapstructure:"WEBSOCKET_PERSISTENCE_MODE"`      // "rdb", "aof", or "both"
 	RDBSnapshotInterval time.Duration `mapstructure:"WEBSOCKET_RDB_SNAPSHOT_INTERVAL"` // e.g., "5m"
 	AOFSyncPolicy       string        `mapstructure:"WEBSOCKET_AOF_SYNC_POLICY"`       // "always", "everysec", or "no"
+	ResumeTicketTTL     time.Duration `mapstructure:"WEBSOCKET_RESUME_TICKET_TTL"`
+	AuditEnabled        bool          `mapstructure:"WEBSOCKET_AUDIT_ENABLED"`
+	AuditSampleRate     float64       `mapstructure:"WEBSOCKET_AUDIT_SAMPLE_RATE"` // 0..1 share of messages recorded
+	AuditMaxEntries     int           `mapstructure:"WEBSOCKET_AUDIT_MAX_ENTRIES"` // per user ring buffer size
+	AuditTTL            time.Duration `mapstructure:"WEBSOCKET_AUDIT_TTL"`
+	AuditRedactFields   []string      `mapstructure:"WEBSOCKET_AUDIT_REDACT_FIELDS"`
+	// Critical events (ride offers, order assignments, cancellations) are
+	// held per user until acknowledged or the TTL passes.
+	OfflineQueueEnabled    bool          `mapstructure:"WEBSOCKET_OFFLINE_QUEUE_ENABLED"`
+	OfflineQueueTTL        time.Duration `mapstructure:"WEBSOCKET_OFFLINE_QUEUE_TTL"`
+	OfflineQueueMaxPerUser int           `mapstructure:"WEBSOCKET_OFFLINE_QUEUE_MAX_PER_USER"`
 }
 
 func DefaultWebSocketConfig() WebSocketConfig {
 	return WebSocketConfig{
-		Enabled:             true,
-		ReadBufferSize:      1024,
-		WriteBufferSize:     1024,
-		MaxMessageSize:      512 * 1024,
-		HandshakeTimeout:    10 * time.Second,
-		WriteWait:           10 * time.Second,
-		PongWait:            60 * time.Second,
-		PingPeriod:          (60 * time.Second * 9) / 10,
-		MaxConnections:      10000,
-		MessageBufferSize:   256,
-		EnablePresence:      true,
-		EnableMessageStore:  true,
-		PersistenceEnabled:  true,
-		PersistenceMode:     "both",         
-		RDBSnapshotInterval: 5 * time.Minute,
-		AOFSyncPolicy:       "everysec",     
+		Enabled:                true,
+		ReadBufferSize:         1024,
+		WriteBufferSize:        1024,
+		MaxMessageSize:         512 * 1024,
+		HandshakeTimeout:       10 * time.Second,
+		WriteWait:              10 * time.Second,
+		PongWait:               60 * time.Second,
+		PingPeriod:             (60 * time.Second * 9) / 10,
+		MaxConnections:         10000,
+		MessageBufferSize:      256,
+		EnablePresence:         true,
+		EnableMessageStore:     true,
+		PersistenceEnabled:     true,
+		PersistenceMode:        "both",
+		RDBSnapshotInterval:    5 * time.Minute,
+		AOFSyncPolicy:          "everysec",
+		ResumeTicketTTL:        2 * time.Minute,
+		AuditSampleRate:        0.05,
+		AuditMaxEntries:        200,
+		AuditTTL:               24 * time.Hour,
+		OfflineQueueEnabled:    true,
+		OfflineQueueTTL:        2 * time.Minute,
+		OfflineQueueMaxPerUser: 50,
 	}
 }
@@ -0,0 +1,292 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+// The migrator keeps its state in the same schema_migrations table as the
+// golang-migrate CLI (one row: version, dirty), so databases migrated with
+// `make migrate-up` and with cmd/migrate are interchangeable.
+const (
+	schemaMigrationsTable = "schema_migrations"
+
+	// migrationLockID is the Postgres advisory lock key held while migrating.
+	migrationLockID = 7243019461
+)
+
+var migrationFileRe = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// ErrDirtySchema means a previous migration failed halfway. The schema has to
+// be fixed by hand and the version forced before migrating again.
+var ErrDirtySchema = errors.New("database schema is dirty")
+
+type migration struct {
+	Version uint
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Migrator struct {
+	db         *sql.DB
+	migrations []migration
+}
+
+// NewMigrator reads the migrations in source. Every version needs exactly
+// one up and one down file.
+func NewMigrator(db *sql.DB, source fs.FS) (*Migrator, error) {
+	entries, err := fs.ReadDir(source, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[uint]*migration)
+	for _, entry := range entries {
+		match := migrationFileRe.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+		m, ok := byVersion[uint(version)]
+		if !ok {
+			m = &migration{Version: uint(version), Name: match[2]}
+			byVersion[uint(version)] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", version, m.Name, match[2])
+		}
+
+		body, err := fs.ReadFile(source, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+		}
+		if match[3] == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrations := make([]migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Latest is the newest version available in the source, or 0 if there is none.
+func (m *Migrator) Latest() uint {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Version returns the version recorded in the database. A database that was
+// never migrated reports version 0.
+func (m *Migrator) Version(ctx context.Context) (uint, bool, error) {
+	if err := m.ensureTable(ctx, m.db); err != nil {
+		return 0, false, err
+	}
+	return m.version(ctx, m.db)
+}
+
+// Up applies up to steps pending migrations; steps <= 0 applies all of them.
+func (m *Migrator) Up(ctx context.Context, steps int) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		current, dirty, err := m.version(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("%w at version %d", ErrDirtySchema, current)
+		}
+
+		applied := 0
+		for _, mig := range m.migrations {
+			if mig.Version <= current {
+				continue
+			}
+			if steps > 0 && applied == steps {
+				break
+			}
+			if err := m.apply(ctx, conn, mig.Version, mig.Up, mig.Version); err != nil {
+				return fmt.Errorf("migration %d_%s up failed: %w", mig.Version, mig.Name, err)
+			}
+			logger.Info("applied migration", "version", mig.Version, "name", mig.Name)
+			applied++
+		}
+		return nil
+	})
+}
+
+// Down rolls back up to steps applied migrations; steps <= 0 rolls back all
+// of them.
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		current, dirty, err := m.version(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if dirty {
+			return fmt.Errorf("%w at version %d", ErrDirtySchema, current)
+		}
+
+		reverted := 0
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			mig := m.migrations[i]
+			if mig.Version > current {
+				continue
+			}
+			if steps > 0 && reverted == steps {
+				break
+			}
+
+			var previous uint
+			if i > 0 {
+				previous = m.migrations[i-1].Version
+			}
+			if err := m.apply(ctx, conn, mig.Version, mig.Down, previous); err != nil {
+				return fmt.Errorf("migration %d_%s down failed: %w", mig.Version, mig.Name, err)
+			}
+			logger.Info("reverted migration", "version", mig.Version, "name", mig.Name)
+			reverted++
+		}
+		return nil
+	})
+}
+
+// Force records version as applied and clean without running anything. It is
+// the way out of a dirty schema once it has been repaired by hand.
+func (m *Migrator) Force(ctx context.Context, version uint) error {
+	return m.withLock(ctx, func(conn *sql.Conn) error {
+		return m.setVersion(ctx, conn, version, false)
+	})
+}
+
+// CheckSchema compares the database with the migrations this binary was
+// built with. A dirty schema or one behind the binary is an error; a schema
+// ahead of it is only logged, since that is normal while an older instance
+// is still draining during a deploy.
+func (m *Migrator) CheckSchema(ctx context.Context) error {
+	current, dirty, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+	latest := m.Latest()
+
+	switch {
+	case dirty:
+		return fmt.Errorf("%w at version %d", ErrDirtySchema, current)
+	case current < latest:
+		return fmt.Errorf("database schema is at version %d, expected %d: run the migrations first", current, latest)
+	case current > latest:
+		logger.Warn("database schema is newer than this build", "version", current, "expected", latest)
+	}
+	return nil
+}
+
+// apply runs one migration file. Files are executed as a whole, outside a
+// transaction, like golang-migrate does, so they may contain statements such
+// as CREATE INDEX CONCURRENTLY. The version is marked dirty until the file
+// has run, leaving a visible trace if it fails halfway.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, version uint, body string, target uint) error {
+	if err := m.setVersion(ctx, conn, version, true); err != nil {
+		return err
+	}
+	if _, err := conn.ExecContext(ctx, body); err != nil {
+		return err
+	}
+	return m.setVersion(ctx, conn, target, false)
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func (m *Migrator) ensureTable(ctx context.Context, db execer) error {
+	_, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+schemaMigrationsTable+` (
+		version BIGINT NOT NULL PRIMARY KEY,
+		dirty BOOLEAN NOT NULL
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", schemaMigrationsTable, err)
+	}
+	return nil
+}
+
+func (m *Migrator) version(ctx context.Context, db execer) (uint, bool, error) {
+	var version int64
+	var dirty bool
+	err := db.QueryRowContext(ctx, `SELECT version, dirty FROM `+schemaMigrationsTable+` LIMIT 1`).Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return uint(version), dirty, nil
+}
+
+func (m *Migrator) setVersion(ctx context.Context, conn *sql.Conn, version uint, dirty bool) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to record schema version: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `TRUNCATE `+schemaMigrationsTable); err != nil {
+		return fmt.Errorf("failed to record schema version: %w", err)
+	}
+	// golang-migrate leaves the table empty when everything is rolled back.
+	if version > 0 || dirty {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO `+schemaMigrationsTable+` (version, dirty) VALUES ($1, $2)`, int64(version), dirty); err != nil {
+			return fmt.Errorf("failed to record schema version: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to record schema version: %w", err)
+	}
+	return nil
+}
+
+// withLock runs fn on a single connection holding the migration advisory
+// lock, so two deploys cannot migrate the same database at once.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockID)
+
+	if err := m.ensureTable(ctx, conn); err != nil {
+		return err
+	}
+	return fn(conn)
+}
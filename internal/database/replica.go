@@ -0,0 +1,109 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+const replicaSettingKey = "database:replica"
+
+// ReadReplica marks a query as safe to serve from a read replica. Use it as a
+// scope on read-heavy paths that tolerate replication lag:
+//
+//	r.db.WithContext(ctx).Scopes(database.ReadReplica).Find(&services)
+//
+// Queries inside a transaction always stay on the primary.
+func ReadReplica(db *gorm.DB) *gorm.DB {
+	return db.Set(replicaSettingKey, true)
+}
+
+// ReplicaPlugin sends queries marked with ReadReplica to the configured
+// replicas, round robin. Everything else, and all writes, keep using the
+// primary connection.
+type ReplicaPlugin struct {
+	replicas []*sql.DB
+	next     atomic.Uint32
+}
+
+// NewReplicaPlugin connects to every replica DSN in cfg, using the primary's
+// pool settings. Replicas that cannot be reached are skipped with a warning so
+// a lagging or down replica never blocks startup.
+func NewReplicaPlugin(cfg *config.DatabaseConfig) *ReplicaPlugin {
+	p := &ReplicaPlugin{}
+	for i, dsn := range cfg.ReplicaDSNs {
+		replica, err := connectReplica(cfg, dsn)
+		if err != nil {
+			logger.Warn("skipping database replica", "index", i, "error", err)
+			continue
+		}
+		p.replicas = append(p.replicas, replica)
+	}
+
+	logger.Info("database replicas connected", "count", len(p.replicas), "configured", len(cfg.ReplicaDSNs))
+	return p
+}
+
+func connectReplica(cfg *config.DatabaseConfig, dsn string) (*sql.DB, error) {
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to replica: %w", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get replica instance: %w", err)
+	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.MaxLifetime)
+
+	if err := sqlDB.Ping(); err != nil {
+		sqlDB.Close()
+		return nil, fmt.Errorf("failed to ping replica: %w", err)
+	}
+	return sqlDB, nil
+}
+
+func (p *ReplicaPlugin) Name() string {
+	return "replica_router"
+}
+
+func (p *ReplicaPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := cb.Query().Before("gorm:query").Register("replica:route_query", p.route); err != nil {
+		return err
+	}
+	return cb.Row().Before("gorm:row").Register("replica:route_row", p.route)
+}
+
+func (p *ReplicaPlugin) route(db *gorm.DB) {
+	if len(p.replicas) == 0 || db.Statement == nil {
+		return
+	}
+	if marked, ok := db.Get(replicaSettingKey); !ok || marked != true {
+		return
+	}
+	if _, inTx := db.Statement.ConnPool.(gorm.TxCommitter); inTx {
+		return
+	}
+
+	n := p.next.Add(1)
+	db.Statement.ConnPool = p.replicas[int(n)%len(p.replicas)]
+}
+
+// Close closes the replica connections.
+func (p *ReplicaPlugin) Close() {
+	for _, replica := range p.replicas {
+		replica.Close()
+	}
+}
@@ -0,0 +1,42 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/modules/incentives"
+	"github.com/umar5678/go-backend/internal/modules/loyalty"
+	"github.com/umar5678/go-backend/internal/modules/referrals"
+	"github.com/umar5678/go-backend/internal/modules/webhooks"
+	"github.com/umar5678/go-backend/internal/services/eventbus"
+)
+
+// ProviderMatcher finds a provider for a home service order. It is satisfied
+// by homeservices.Service.
+type ProviderMatcher interface {
+	FindAndNotifyNextProvider(orderID string)
+}
+
+// Subscribe registers the event bus consumers: webhook fan-out, referral
+// rewards, loyalty points, driver incentives and provider matching.
+func Subscribe(bus eventbus.Bus, webhooksService webhooks.Service, matcher ProviderMatcher, referralsService referrals.Service, loyaltyService loyalty.Service, incentivesService incentives.Service) error {
+	if err := webhooks.Subscribe(bus, webhooksService); err != nil {
+		return err
+	}
+	if err := referrals.Subscribe(bus, referralsService); err != nil {
+		return err
+	}
+	if err := loyalty.Subscribe(bus, loyaltyService); err != nil {
+		return err
+	}
+	if err := incentives.Subscribe(bus, incentivesService); err != nil {
+		return err
+	}
+	return bus.Subscribe("provider_matching", func(ctx context.Context, event eventbus.Event) error {
+		var payload eventbus.ProviderMatchingRequestedPayload
+		if err := event.Decode(&payload); err != nil {
+			return err
+		}
+		matcher.FindAndNotifyNextProvider(payload.OrderID)
+		return nil
+	}, eventbus.ProviderMatchingRequested)
+}
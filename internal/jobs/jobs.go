@@ -0,0 +1,37 @@
+// Package jobs holds the background work shared by cmd/api, which runs it
+// in-process unless RUN_JOBS_IN_API is false, and cmd/worker.
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/services/health"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+// every runs fn each interval, bounded by timeout, until ctx is done.
+func every(ctx context.Context, name string, interval, timeout time.Duration, fn func(ctx context.Context) error) {
+	heartbeat := health.NewHeartbeat(name, interval)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				runCtx, cancel := context.WithTimeout(ctx, timeout)
+				if err := fn(runCtx); err != nil {
+					logger.Error(name+" failed", "error", err)
+				}
+				cancel()
+				heartbeat.Beat()
+			}
+		}
+	}()
+
+	logger.Info(name+" started", "interval", interval)
+}
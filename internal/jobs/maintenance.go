@@ -0,0 +1,68 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/modules/archive"
+	"github.com/umar5678/go-backend/internal/modules/audit"
+	"github.com/umar5678/go-backend/internal/modules/eventlog"
+	"github.com/umar5678/go-backend/internal/modules/homeservices"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+	"github.com/umar5678/go-backend/internal/modules/media"
+	"github.com/umar5678/go-backend/internal/modules/wallet"
+	"github.com/umar5678/go-backend/internal/services/storage"
+)
+
+// StartMaintenance starts the periodic jobs that only need the database:
+// expiring unaccepted orders, purging the event and audit logs and, when
+// enabled, archiving and orphaned media cleanup. publisher may be nil.
+func StartMaintenance(ctx context.Context, db *gorm.DB, cfg *config.Config, publisher shared.WebhookPublisher) error {
+	var mediaService media.Service
+	if cfg.Media.Enabled {
+		store, err := storage.NewClient(cfg.Upload)
+		if err != nil {
+			return err
+		}
+		mediaService = media.NewService(media.NewRepository(db), store, cfg.Media)
+	}
+
+	walletService := wallet.NewService(wallet.NewRepository(db), db)
+	orderExpirationService := homeservices.NewOrderExpirationService(db, walletService)
+	if publisher != nil {
+		orderExpirationService.SetWebhookPublisher(publisher)
+	}
+	every(ctx, "order expiration job", time.Minute, 30*time.Second, orderExpirationService.ExpireUnacceptedOrders)
+
+	eventLogService := eventlog.NewService(eventlog.NewRepository(db))
+	every(ctx, "event log purge job", time.Hour, 5*time.Minute, func(ctx context.Context) error {
+		_, err := eventLogService.PurgeExpired(ctx)
+		return err
+	})
+
+	auditService := audit.NewService(audit.NewRepository(db), cfg.AuditLog)
+	every(ctx, "audit log purge job", 24*time.Hour, 10*time.Minute, func(ctx context.Context) error {
+		_, err := auditService.PurgeExpired(ctx)
+		return err
+	})
+
+	if cfg.Archive.Enabled {
+		archiveService := archive.NewService(archive.NewRepository(db), cfg.Archive)
+		every(ctx, "archive job", cfg.Archive.Interval, 30*time.Minute, func(ctx context.Context) error {
+			_, err := archiveService.Run(ctx)
+			return err
+		})
+	}
+
+	if mediaService != nil {
+		every(ctx, "media cleanup job", cfg.Media.CleanupInterval, 10*time.Minute, func(ctx context.Context) error {
+			_, err := mediaService.CleanupOrphans(ctx)
+			return err
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+const (
+	ErrCodeAccountSuspended = "ACCOUNT_SUSPENDED"
+	ErrCodeAccountBanned    = "ACCOUNT_BANNED"
+)
+
+// AccountBlockedError tells a suspended or banned user why the request was
+// refused, with a distinct code so clients can show the right screen.
+func AccountBlockedError(block *cache.UserBlock) *response.AppError {
+	if block.Kind == "ban" {
+		return response.NewAppError(http.StatusForbidden, "Your account has been banned", ErrCodeAccountBanned, nil, nil)
+	}
+
+	message := "Your account has been suspended"
+	if block.Until != nil {
+		message = fmt.Sprintf("Your account is suspended until %s", block.Until.UTC().Format("2006-01-02 15:04 MST"))
+	}
+	return response.NewAppError(http.StatusForbidden, message, ErrCodeAccountSuspended, nil, nil)
+}
+
+func isUserBlocked(c *gin.Context, userID string) bool {
+	_, blocked := cache.GetUserBlock(c.Request.Context(), userID)
+	return blocked
+}
@@ -2,9 +2,11 @@
 package middleware
 
 import (
+	"net/http"
 	"strings"
 
 	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/services/cache"
 	"github.com/umar5678/go-backend/internal/utils/jwt"
 	"github.com/umar5678/go-backend/internal/utils/response"
 
@@ -15,37 +17,76 @@ func Auth(cfg *config.Config) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.Error(response.UnauthorizedError("Authorization header required"))
+			c.Error(response.CodedError(response.CodeAuthRequired, "Authorization header required"))
 			c.Abort()
 			return
 		}
 
 		parts := strings.SplitN(authHeader, " ", 2)
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			c.Error(response.UnauthorizedError("Invalid authorization header format"))
+			c.Error(response.CodedError(response.CodeTokenInvalid, "Invalid authorization header format"))
 			c.Abort()
 			return
 		}
 
 		claims, err := jwt.ValidateToken(parts[1], cfg.JWT.Secret, cfg.JWT.Issuer)
 		if err != nil {
-			c.Error(response.UnauthorizedError("Invalid or expired token"))
+			c.Error(response.CodedError(response.CodeTokenInvalid, ""))
+			c.Abort()
+			return
+		}
+
+		if cache.IsAuthSessionRevoked(c.Request.Context(), claims.SessionID) {
+			c.Error(response.CodedError(response.CodeSessionRevoked, ""))
+			c.Abort()
+			return
+		}
+
+		if block, blocked := cache.GetUserBlock(c.Request.Context(), claims.UserID); blocked {
+			c.Error(AccountBlockedError(block))
 			c.Abort()
 			return
 		}
 
 		c.Set("userID", claims.UserID)
 		c.Set("role", claims.Role)
+		c.Set("sessionID", claims.SessionID)
+
+		if !allowImpersonation(c, claims) {
+			c.Abort()
+			return
+		}
 
 		c.Next()
 	}
 }
 
+// allowImpersonation marks requests made by an admin acting as the user and
+// turns away ones that would change data unless the token was granted write
+// access. Reads are always allowed.
+func allowImpersonation(c *gin.Context, claims *jwt.Claims) bool {
+	if !claims.IsImpersonation() {
+		return true
+	}
+
+	c.Set("impersonatorID", claims.ImpersonatorID)
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	if claims.HasScope(jwt.ScopeWrite) {
+		return true
+	}
+
+	c.Error(response.CodedError(response.CodeImpersonationReadOnly, ""))
+	return false
+}
+
 func RequireRole(roles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userRole, exists := c.Get("role")
 		if !exists {
-			c.Error(response.ForbiddenError("Insufficient permissions"))
+			c.Error(response.CodedError(response.CodeInsufficientRole, ""))
 			c.Abort()
 			return
 		}
@@ -58,7 +99,7 @@ func RequireRole(roles ...string) gin.HandlerFunc {
 			}
 		}
 
-		c.Error(response.ForbiddenError("Insufficient permissions"))
+		c.Error(response.CodedError(response.CodeInsufficientRole, ""))
 		c.Abort()
 	}
 }
@@ -78,9 +119,14 @@ func OptionalAuth(cfg *config.Config) gin.HandlerFunc {
 		}
 
 		claims, err := jwt.ValidateToken(parts[1], cfg.JWT.Secret, cfg.JWT.Issuer)
-		if err == nil {
+		if err == nil && !cache.IsAuthSessionRevoked(c.Request.Context(), claims.SessionID) && !isUserBlocked(c, claims.UserID) {
 			c.Set("userID", claims.UserID)
 			c.Set("role", claims.Role)
+			c.Set("sessionID", claims.SessionID)
+			if !allowImpersonation(c, claims) {
+				c.Abort()
+				return
+			}
 		}
 
 		c.Next()
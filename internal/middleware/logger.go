@@ -20,7 +20,7 @@ func Logger() gin.HandlerFunc {
 
 		requestID, _ := c.Get("requestID")
 
-		logger.Info("request completed",
+		fields := []interface{}{
 			"requestID", requestID,
 			"method", c.Request.Method,
 			"path", path,
@@ -29,6 +29,14 @@ func Logger() gin.HandlerFunc {
 			"duration", duration.Milliseconds(),
 			"ip", c.ClientIP(),
 			"userAgent", c.Request.UserAgent(),
-		)
+		}
+		// Requests made by an admin acting as a user carry both identities so
+		// they can be told apart from the user's own activity.
+		if impersonatorID, ok := c.Get("impersonatorID"); ok {
+			userID, _ := c.Get("userID")
+			fields = append(fields, "impersonated", true, "impersonatorID", impersonatorID, "userID", userID)
+		}
+
+		logger.Info("request completed", fields...)
 	}
 }
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"crypto/subtle"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/services/captcha"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+const publicAPIKeyContextKey = "publicApiKey"
+
+// PublicAPIKey admits requests carrying one of the configured widget keys in X-API-Key.
+func PublicAPIKey(keys []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provided := c.GetHeader("X-API-Key")
+
+		for _, key := range keys {
+			if provided != "" && subtle.ConstantTimeCompare([]byte(provided), []byte(key)) == 1 {
+				c.Set(publicAPIKeyContextKey, key)
+				c.Next()
+				return
+			}
+		}
+
+		c.Error(response.UnauthorizedError("Invalid API key"))
+		c.Abort()
+	}
+}
+
+// PublicAPIKeyFromContext returns the key accepted by PublicAPIKey, for per-key rate limits.
+func PublicAPIKeyFromContext(c *gin.Context) string {
+	return c.GetString(publicAPIKeyContextKey)
+}
+
+// Captcha requires a valid captcha token in X-Captcha-Token.
+func Captcha(verifier *captcha.Verifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ok, err := verifier.Verify(c.Request.Context(), c.GetHeader("X-Captcha-Token"), c.ClientIP())
+		if err != nil {
+			logger.Error("captcha verification failed", "error", err, "ip", c.ClientIP())
+			c.Error(response.CodedError(response.CodeCaptchaUnavailable, ""))
+			c.Abort()
+			return
+		}
+		if !ok {
+			c.Error(response.CodedError(response.CodeCaptchaFailed, ""))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
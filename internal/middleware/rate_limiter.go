@@ -1,13 +1,22 @@
 package middleware
 
 import (
+	"context"
 	"fmt"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/utils/jwt"
+	"github.com/umar5678/go-backend/internal/utils/logger"
 	"github.com/umar5678/go-backend/internal/utils/response"
 
 	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
 	"golang.org/x/time/rate"
 )
 
@@ -39,21 +48,140 @@ func (rl *rateLimiter) getLimiter(key string) *rate.Limiter {
 	return limiter
 }
 
-func RateLimit(cfg config.RateLimitConfig) gin.HandlerFunc {
-	if cfg.RequestsPerSecond <= 0 {
-		cfg.RequestsPerSecond = 100
+// rateLimitScript counts a request in the key's current window and returns the
+// count and the milliseconds left until the window resets.
+var rateLimitScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+local ttl = redis.call("PTTL", KEYS[1])
+if ttl < 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+	ttl = tonumber(ARGV[1])
+end
+return {count, ttl}
+`)
+
+type routePattern struct {
+	method string
+	path   string
+	prefix bool
+}
+
+type limitClass struct {
+	name     string
+	policy   config.RateLimitPolicy
+	patterns []routePattern
+	// local takes over when Redis cannot be reached, so each instance still
+	// enforces the allowance on its own.
+	local *rateLimiter
+}
+
+func newLimitClass(name string, policy config.RateLimitPolicy, routes []string) *limitClass {
+	if policy.Window <= 0 {
+		policy.Window = time.Minute
+	}
+	if policy.Requests <= 0 {
+		policy.Requests = 60
+	}
+
+	class := &limitClass{
+		name:   name,
+		policy: policy,
+		local: &rateLimiter{
+			limiters: make(map[string]*rate.Limiter),
+			rate:     rate.Every(policy.Window / time.Duration(policy.Requests)),
+			burst:    policy.Requests,
+		},
+	}
+	for _, route := range routes {
+		method, path, ok := strings.Cut(strings.TrimSpace(route), " ")
+		if !ok {
+			continue
+		}
+		path = strings.TrimSpace(path)
+		pattern := routePattern{method: strings.ToUpper(method), path: path}
+		if strings.HasSuffix(path, "*") {
+			pattern.path = strings.TrimSuffix(path, "*")
+			pattern.prefix = true
+		}
+		class.patterns = append(class.patterns, pattern)
+	}
+	return class
+}
+
+func (lc *limitClass) matches(method, path string) bool {
+	for _, p := range lc.patterns {
+		if p.method != "*" && p.method != method {
+			continue
+		}
+		if p.path == path || (p.prefix && strings.HasPrefix(path, p.path)) {
+			return true
+		}
+	}
+	return false
+}
+
+// RateLimit enforces the configured allowances in Redis so every instance
+// shares the same counters. Authenticated requests are counted per user,
+// anonymous ones per client IP, and each route class has its own counter.
+// Responses carry RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset.
+func RateLimit(cfg *config.Config) gin.HandlerFunc {
+	limits := cfg.Server.RateLimit
+	if !limits.Enabled {
+		return func(c *gin.Context) {
+			c.Next()
+		}
 	}
-	if cfg.Burst <= 0 {
-		cfg.Burst = 200
+
+	defaultClass := newLimitClass("default", limits.Default, nil)
+	classes := make([]*limitClass, 0, len(limits.Classes))
+	for _, class := range limits.Classes {
+		classes = append(classes, newLimitClass(class.Name, class.Policy, class.Routes))
+	}
+
+	return func(c *gin.Context) {
+		class := defaultClass
+		if path := c.FullPath(); path != "" {
+			for _, candidate := range classes {
+				if candidate.matches(c.Request.Method, path) {
+					class = candidate
+					break
+				}
+			}
+		}
+
+		if !class.allow(c, rateLimitIdentity(c, cfg.JWT)) {
+			c.Error(response.TooManyRequests("Rate limit exceeded"))
+			c.Abort()
+			return
+		}
+
+		c.Next()
 	}
+}
 
-	rl := newRateLimiter(cfg.RequestsPerSecond, cfg.Burst)
+// RateLimitWithPolicy gives every key returned by keyFunc its own per-minute
+// allowance, counted in Redis like RateLimit. Requests without a key are let
+// through.
+func RateLimitWithPolicy(name string, keyFunc func(*gin.Context) (key string, perMinute int)) gin.HandlerFunc {
+	var mu sync.Mutex
+	classes := make(map[int]*limitClass)
 
 	return func(c *gin.Context) {
-		ip := c.ClientIP()
-		limiter := rl.getLimiter(ip)
+		key, perMinute := keyFunc(c)
+		if key == "" || perMinute <= 0 {
+			c.Next()
+			return
+		}
 
-		if !limiter.Allow() {
+		mu.Lock()
+		class, exists := classes[perMinute]
+		if !exists {
+			class = newLimitClass(name, config.RateLimitPolicy{Requests: perMinute, Window: time.Minute}, nil)
+			classes[perMinute] = class
+		}
+		mu.Unlock()
+
+		if !class.allow(c, key) {
 			c.Error(response.TooManyRequests("Rate limit exceeded"))
 			c.Abort()
 			return
@@ -63,6 +191,75 @@ func RateLimit(cfg config.RateLimitConfig) gin.HandlerFunc {
 	}
 }
 
+var lastRedisWarning atomic.Int64
+
+// allow counts the request against identity's allowance and sets the
+// RateLimit headers.
+func (lc *limitClass) allow(c *gin.Context, identity string) bool {
+	window := lc.policy.Window
+	c.Header("RateLimit-Limit", strconv.Itoa(lc.policy.Requests))
+	c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d", lc.policy.Requests, int(window.Seconds())))
+
+	count, resetIn, err := countRequest(c.Request.Context(), "ratelimit:"+lc.name+":"+identity, window)
+	if err != nil {
+		now := time.Now().Unix()
+		if last := lastRedisWarning.Load(); now-last >= 60 && lastRedisWarning.CompareAndSwap(last, now) {
+			logger.Warn("rate limiter falling back to local counters", "error", err)
+		}
+		if !lc.local.getLimiter(identity).Allow() {
+			c.Header("Retry-After", strconv.Itoa(int(window.Seconds())))
+			return false
+		}
+		return true
+	}
+
+	remaining := lc.policy.Requests - int(count)
+	if remaining < 0 {
+		remaining = 0
+	}
+	resetSeconds := int((resetIn + time.Second - 1) / time.Second)
+	c.Header("RateLimit-Remaining", strconv.Itoa(remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(resetSeconds))
+
+	if count > int64(lc.policy.Requests) {
+		c.Header("Retry-After", strconv.Itoa(resetSeconds))
+		return false
+	}
+	return true
+}
+
+func countRequest(ctx context.Context, key string, window time.Duration) (int64, time.Duration, error) {
+	if cache.MainClient == nil {
+		return 0, 0, fmt.Errorf("redis is not connected")
+	}
+
+	result, err := rateLimitScript.Run(ctx, cache.MainClient, []string{key}, window.Milliseconds()).Int64Slice()
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(result) != 2 {
+		return 0, 0, fmt.Errorf("unexpected rate limit result %v", result)
+	}
+	return result[0], time.Duration(result[1]) * time.Millisecond, nil
+}
+
+// rateLimitIdentity names who a request is counted against. Limits run before
+// the auth middleware, so a bearer token is checked here; an invalid one is
+// counted by IP and rejected later by Auth.
+func rateLimitIdentity(c *gin.Context, jwtCfg config.JWTConfig) string {
+	if userID, exists := c.Get("userID"); exists {
+		return "user:" + userID.(string)
+	}
+
+	if token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok {
+		if claims, err := jwt.ValidateToken(token, jwtCfg.Secret, jwtCfg.Issuer); err == nil {
+			return "user:" + claims.UserID
+		}
+	}
+
+	return "ip:" + c.ClientIP()
+}
+
 func RateLimitByKey(keyFunc func(*gin.Context) string, requestsPerSecond int, burst int) gin.HandlerFunc {
 	if requestsPerSecond <= 0 {
 		requestsPerSecond = 100
@@ -85,4 +282,29 @@ func RateLimitByKey(keyFunc func(*gin.Context) string, requestsPerSecond int, bu
 
 		c.Next()
 	}
-}
\ No newline at end of file
+}
+// RateLimitPerMinute is a coarse limiter for public endpoints. The whole
+// per-minute allowance is available as burst, then refills evenly.
+func RateLimitPerMinute(keyFunc func(*gin.Context) string, perMinute int) gin.HandlerFunc {
+	if perMinute <= 0 {
+		perMinute = 60
+	}
+
+	rl := &rateLimiter{
+		limiters: make(map[string]*rate.Limiter),
+		rate:     rate.Every(time.Minute / time.Duration(perMinute)),
+		burst:    perMinute,
+	}
+
+	return func(c *gin.Context) {
+		limiter := rl.getLimiter(keyFunc(c))
+
+		if !limiter.Allow() {
+			c.Error(response.TooManyRequests("Rate limit exceeded"))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
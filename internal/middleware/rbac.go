@@ -46,7 +46,7 @@ func RequireAdminOrSelf() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userRole, exists := c.Get("role")
 		if !exists {
-			c.Error(response.UnauthorizedError("Authentication required"))
+			c.Error(response.CodedError(response.CodeAuthRequired, ""))
 			c.Abort()
 			return
 		}
@@ -83,7 +83,7 @@ func RequireServiceProviderOrAdmin() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		userRole, exists := c.Get("role")
 		if !exists {
-			c.Error(response.UnauthorizedError("Authentication required"))
+			c.Error(response.CodedError(response.CodeAuthRequired, ""))
 			c.Abort()
 			return
 		}
@@ -23,7 +23,7 @@ func Recovery() gin.HandlerFunc {
 				)
 
 				if appErr, ok := err.(*response.AppError); ok {
-					response.SendError(c, appErr.StatusCode, appErr.Message, appErr.Errors, appErr.Code)
+					appErr.ToResponse(c)
 					return
 				}
 
@@ -59,7 +59,7 @@ func ErrorHandler() gin.HandlerFunc {
 
 			if appErr, ok := err.(*response.AppError); ok {
 				logger.Info("ErrorHandler: AppError detected, sending error response", "statusCode", appErr.StatusCode, "message", appErr.Message)
-				response.SendError(c, appErr.StatusCode, appErr.Message, appErr.Errors, appErr.Code)
+				appErr.ToResponse(c)
 				return
 			}
 
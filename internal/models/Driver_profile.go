@@ -14,6 +14,7 @@ type DriverProfile struct {
 	CurrentLocation  *string `gorm:"type:geometry(Point,4326)" json:"currentLocation"`
 	Heading          int     `gorm:"default:0" json:"heading"`
 	Rating           float64 `gorm:"type:decimal(3,2);default:5.0" json:"rating"`
+	RatingCount      int     `gorm:"default:0" json:"ratingCount"`
 	TotalTrips       int     `gorm:"default:0" json:"totalTrips"`
 	TotalEarnings    float64 `gorm:"type:decimal(10,2);default:0" json:"totalEarnings"`
 	AcceptanceRate   float64 `gorm:"type:decimal(5,2);default:100.0" json:"acceptanceRate"`
@@ -27,21 +28,43 @@ type DriverProfile struct {
 	RestrictionReason   *string    `gorm:"type:varchar(255)" json:"restrictionReason,omitempty"`
 	MinBalanceThreshold float64    `gorm:"type:decimal(10,2);default:-2000.00" json:"minBalanceThreshold"`
 
+	// CreditTier picks the driver's credit limit from the configured tiers
+	// unless an admin set CreditLimitOverride. MinBalanceThreshold is that
+	// limit as a (negative) balance.
+	CreditTier          string   `gorm:"type:varchar(30);not null;default:'standard'" json:"creditTier"`
+	CreditLimitOverride *float64 `gorm:"type:decimal(10,2)" json:"creditLimitOverride,omitempty"`
+	// Until CreditOverrideUntil the driver may go online however much they
+	// owe, for example while an agreed repayment plan runs.
+	CreditOverrideUntil  *time.Time `json:"creditOverrideUntil,omitempty"`
+	CreditOverrideReason *string    `gorm:"type:varchar(255)" json:"creditOverrideReason,omitempty"`
+
+	// ActiveVehicleID is the one of the driver's vehicles they are driving
+	// now. Rides are matched on its type.
+	ActiveVehicleID *string `gorm:"type:uuid" json:"activeVehicleId,omitempty"`
+
 	CreatedAt time.Time      `gorm:"autoCreateTime" json:"createdAt"`
 	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 
-	User    User     `gorm:"foreignKey:UserID" json:"user,omitempty"`
-	Vehicle *Vehicle `gorm:"foreignKey:DriverID" json:"vehicle,omitempty"`
+	User User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	// Vehicle is the active vehicle; Vehicles are all the driver has registered.
+	Vehicle  *Vehicle  `gorm:"foreignKey:ActiveVehicleID" json:"vehicle,omitempty"`
+	Vehicles []Vehicle `gorm:"foreignKey:DriverID" json:"vehicles,omitempty"`
 }
 
 func (DriverProfile) TableName() string {
 	return "driver_profiles"
 }
 
+// CreditOverrideActive reports whether an admin currently lets the driver
+// work past their credit limit.
+func (d *DriverProfile) CreditOverrideActive(now time.Time) bool {
+	return d.CreditOverrideUntil != nil && now.Before(*d.CreditOverrideUntil)
+}
+
 type Vehicle struct {
 	ID            string         `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
-	DriverID      string         `gorm:"type:uuid;uniqueIndex;not null" json:"driverId"`
+	DriverID      string         `gorm:"type:uuid;index;not null" json:"driverId"`
 	VehicleTypeID string         `gorm:"type:uuid;not null" json:"vehicleTypeId"`
 	Make          string         `gorm:"type:varchar(100);not null" json:"make"`
 	Model         string         `gorm:"type:varchar(100);not null" json:"model"`
@@ -54,9 +77,22 @@ type Vehicle struct {
 	UpdatedAt     time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
 	DeletedAt     gorm.DeletedAt `gorm:"index" json:"-"`
 
+	// An approved inspection keeps the vehicle on the road until
+	// InspectionValidUntil. Once it lapses, or a scheduled inspection goes
+	// overdue, the vehicle is blocked from rides until a new one is approved.
+	InspectionValidUntil *time.Time `json:"inspectionValidUntil,omitempty"`
+	InspectionBlocked    bool       `gorm:"default:false;index" json:"inspectionBlocked"`
+	InspectionBlockedAt  *time.Time `json:"inspectionBlockedAt,omitempty"`
+
 	VehicleType VehicleType `gorm:"foreignKey:VehicleTypeID" json:"vehicleType,omitempty"`
 }
 
+// InspectionLapsed reports whether the vehicle may not take rides, either
+// because it was blocked or because its inspection ran out since the last sweep.
+func (v *Vehicle) InspectionLapsed(now time.Time) bool {
+	return v.InspectionBlocked || (v.InspectionValidUntil != nil && now.After(*v.InspectionValidUntil))
+}
+
 func (Vehicle) TableName() string {
 	return "vehicles"
 }
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+type SuspensionKind string
+
+const (
+	SuspensionKindSuspension SuspensionKind = "suspension"
+	SuspensionKindBan        SuspensionKind = "ban"
+)
+
+// AccountSuspension is one suspension or ban of a user. Suspensions may run
+// until EndsAt; bans and open-ended suspensions last until an admin lifts them.
+type AccountSuspension struct {
+	ID             string         `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	UserID         string         `gorm:"type:uuid;not null;index" json:"userId"`
+	Kind           SuspensionKind `gorm:"type:varchar(20);not null" json:"kind"`
+	Reason         string         `gorm:"type:text;not null" json:"reason"`
+	PreviousStatus UserStatus     `gorm:"type:varchar(30);not null" json:"previousStatus"`
+	StartsAt       time.Time      `gorm:"not null" json:"startsAt"`
+	EndsAt         *time.Time     `json:"endsAt,omitempty"`
+	CreatedBy      string         `gorm:"type:uuid;not null" json:"createdBy"`
+	LiftedAt       *time.Time     `json:"liftedAt,omitempty"`
+	LiftedBy       *string        `gorm:"type:uuid" json:"liftedBy,omitempty"`
+	LiftReason     string         `gorm:"type:text" json:"liftReason,omitempty"`
+	CreatedAt      time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt      time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+func (AccountSuspension) TableName() string {
+	return "account_suspensions"
+}
+
+func (s *AccountSuspension) IsActive(now time.Time) bool {
+	return s.LiftedAt == nil && (s.EndsAt == nil || s.EndsAt.After(now))
+}
+
+// UserStatus is the account status the user holds while this is in force.
+func (s *AccountSuspension) UserStatus() UserStatus {
+	if s.Kind == SuspensionKindBan {
+		return StatusBanned
+	}
+	return StatusSuspended
+}
@@ -52,3 +52,17 @@ func (a *Addon) DiscountPercentage() float64 {
 	}
 	return ((*a.StrikethroughPrice - a.Price) / *a.StrikethroughPrice) * 100
 }
+
+// AddonServiceCompatibility restricts an addon to specific services in its
+// category. An addon without any rows is compatible with every service there.
+type AddonServiceCompatibility struct {
+	ID           string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	AddonSlug    string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_addon_service_compat" json:"addonSlug"`
+	ServiceSlug  string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_addon_service_compat;index" json:"serviceSlug"`
+	CategorySlug string    `gorm:"type:varchar(255);not null;index" json:"categorySlug"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (AddonServiceCompatibility) TableName() string {
+	return "addon_service_compatibility"
+}
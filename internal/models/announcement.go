@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Where an announcement is shown in the apps.
+const (
+	AnnouncementPlacementFeed   = "feed"
+	AnnouncementPlacementBanner = "banner"
+)
+
+// Who an announcement is for.
+const (
+	AnnouncementAudienceAll       = "all"
+	AnnouncementAudienceRiders    = "riders"
+	AnnouncementAudienceDrivers   = "drivers"
+	AnnouncementAudienceProviders = "providers"
+)
+
+// AnnouncementAudienceFor is the audience a user of the role belongs to, or
+// "" for roles announcements are not shown to.
+func AnnouncementAudienceFor(role UserRole) string {
+	switch role {
+	case RoleRider:
+		return AnnouncementAudienceRiders
+	case RoleDriver:
+		return AnnouncementAudienceDrivers
+	case RoleServiceProvider, RoleHandyman, RoleDeliveryPerson:
+		return AnnouncementAudienceProviders
+	}
+	return ""
+}
+
+// Announcement is a message from the operations team shown in the apps
+// between StartsAt and EndsAt. CityID narrows it to users in that city and
+// ServiceCategory, for providers only, to providers of that category.
+// PublishedAt records when it was pushed to connected users.
+type Announcement struct {
+	ID              string         `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	Title           string         `gorm:"type:varchar(200);not null" json:"title"`
+	Body            string         `gorm:"type:text;not null" json:"body"`
+	Placement       string         `gorm:"type:varchar(20);not null;default:'feed'" json:"placement"`
+	Priority        int            `gorm:"not null;default:0" json:"priority"`
+	ImageURL        *string        `gorm:"type:varchar(500)" json:"imageUrl,omitempty"`
+	ActionURL       *string        `gorm:"type:varchar(500)" json:"actionUrl,omitempty"`
+	Audience        string         `gorm:"type:varchar(20);not null;default:'all'" json:"audience"`
+	CityID          *string        `gorm:"type:uuid" json:"cityId,omitempty"`
+	ServiceCategory *string        `gorm:"type:varchar(100)" json:"serviceCategory,omitempty"`
+	StartsAt        time.Time      `gorm:"not null" json:"startsAt"`
+	EndsAt          *time.Time     `json:"endsAt,omitempty"`
+	PublishedAt     *time.Time     `json:"publishedAt,omitempty"`
+	CreatedBy       string         `gorm:"type:uuid;not null" json:"createdBy"`
+	CreatedAt       time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt       time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt       gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+func (Announcement) TableName() string {
+	return "announcements"
+}
+
+// IsLive reports whether the announcement is within its schedule window.
+func (a *Announcement) IsLive(now time.Time) bool {
+	return !now.Before(a.StartsAt) && (a.EndsAt == nil || now.Before(*a.EndsAt))
+}
+
+// AnnouncementRead records that a user has read an announcement.
+type AnnouncementRead struct {
+	AnnouncementID string    `gorm:"type:uuid;primaryKey" json:"announcementId"`
+	UserID         string    `gorm:"type:uuid;primaryKey" json:"userId"`
+	ReadAt         time.Time `gorm:"autoCreateTime" json:"readAt"`
+}
+
+func (AnnouncementRead) TableName() string {
+	return "announcement_reads"
+}
@@ -0,0 +1,8 @@
+package models
+
+// Arrival methods record how a driver's arrival at pickup, or a provider's
+// arrival at the service address, was detected.
+const (
+	ArrivalMethodGeofence = "geofence"
+	ArrivalMethodManual   = "manual"
+)
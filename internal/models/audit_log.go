@@ -0,0 +1,69 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	AuditCategoryAdmin     = "admin"
+	AuditCategoryFinancial = "financial"
+
+	AuditActorSystem = "system"
+)
+
+type AuditData map[string]interface{}
+
+func (d AuditData) Value() (driver.Value, error) {
+	if d == nil {
+		return nil, nil
+	}
+	return json.Marshal(d)
+}
+
+func (d *AuditData) Scan(value interface{}) error {
+	if value == nil {
+		*d = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, d)
+}
+
+// AuditLog records who changed what. Before and After hold the entity as it
+// was serialized on either side of the change; Changes keeps only the fields
+// that differ as {"field": {"from": x, "to": y}}.
+type AuditLog struct {
+	ID         string    `gorm:"type:uuid;primaryKey" json:"id"`
+	ActorID    *string   `gorm:"type:uuid;index" json:"actorId,omitempty"`
+	ActorRole  string    `gorm:"type:varchar(50);not null;default:'system'" json:"actorRole"`
+	Action     string    `gorm:"type:varchar(100);not null;index" json:"action"`
+	Category   string    `gorm:"type:varchar(20);not null;default:'admin'" json:"category"`
+	EntityType string    `gorm:"type:varchar(50);not null" json:"entityType"`
+	EntityID   string    `gorm:"type:varchar(64);not null" json:"entityId"`
+	Before     AuditData `gorm:"type:jsonb" json:"before,omitempty"`
+	After      AuditData `gorm:"type:jsonb" json:"after,omitempty"`
+	Changes    AuditData `gorm:"type:jsonb" json:"changes,omitempty"`
+	Metadata   AuditData `gorm:"type:jsonb" json:"metadata,omitempty"`
+	Reason     string    `gorm:"type:text" json:"reason,omitempty"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+func (l *AuditLog) BeforeCreate(tx *gorm.DB) error {
+	if l.ID == "" {
+		l.ID = uuid.New().String()
+	}
+	return nil
+}
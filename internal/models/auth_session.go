@@ -0,0 +1,41 @@
+package models
+
+import "time"
+
+// AuthSession is one signed-in device. The refresh token is stored only as a
+// hash and is rotated on every refresh; presenting a superseded token revokes
+// the whole session.
+type AuthSession struct {
+	ID               string     `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	UserID           string     `gorm:"type:uuid;not null;index" json:"userId"`
+	DeviceID         string     `gorm:"type:varchar(255);not null" json:"deviceId"`
+	DeviceName       string     `gorm:"type:varchar(255)" json:"deviceName"`
+	UserAgent        string     `gorm:"type:text" json:"userAgent"`
+	IPAddress        string     `gorm:"type:varchar(64)" json:"ipAddress"`
+	RefreshTokenHash string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	RotationCount    int        `gorm:"default:0" json:"rotationCount"`
+	ExpiresAt        time.Time  `gorm:"not null" json:"expiresAt"`
+	LastUsedAt       time.Time  `gorm:"not null" json:"lastUsedAt"`
+	RevokedAt        *time.Time `json:"revokedAt,omitempty"`
+	RevokedReason    string     `gorm:"type:varchar(50)" json:"revokedReason,omitempty"`
+	CreatedAt        time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt        time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
+
+	User *User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+func (AuthSession) TableName() string {
+	return "auth_sessions"
+}
+
+func (s *AuthSession) IsActive() bool {
+	return s.RevokedAt == nil && time.Now().Before(s.ExpiresAt)
+}
+
+const (
+	SessionRevokedLogout    = "logout"
+	SessionRevokedLogoutAll = "logout_all"
+	SessionRevokedReplaced  = "replaced"
+	SessionRevokedReuse     = "token_reuse"
+	SessionRevokedByUser    = "revoked_by_user"
+)
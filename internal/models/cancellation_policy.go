@@ -0,0 +1,104 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	CancellationProductRides        = "rides"
+	CancellationProductHomeServices = "home_services"
+)
+
+// CancellationFee is charged as a flat amount plus a percentage of the fare or
+// order total, and never more than that total.
+type CancellationFee struct {
+	Flat    float64 `json:"flat"`
+	Percent float64 `json:"percent"`
+}
+
+func (f CancellationFee) Amount(total float64) float64 {
+	fee := f.Flat + total*f.Percent/100
+	if total > 0 && fee > total {
+		fee = total
+	}
+	return math.Round(fee*100) / 100
+}
+
+func (f CancellationFee) Value() (driver.Value, error) {
+	return json.Marshal(f)
+}
+
+func (f *CancellationFee) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, f)
+}
+
+// CancellationFeeSchedule maps a ride or order status to the fee owed when it
+// is cancelled in that status. Statuses without an entry are free.
+type CancellationFeeSchedule map[string]CancellationFee
+
+func (s CancellationFeeSchedule) Value() (driver.Value, error) {
+	return json.Marshal(s)
+}
+
+func (s *CancellationFeeSchedule) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, s)
+}
+
+// CancellationPolicy decides what cancelling a ride or home service order
+// costs. A policy can be narrowed to a vehicle type or service category and to
+// a city, given as the circle around its centre; the most specific active
+// policy that matches wins.
+type CancellationPolicy struct {
+	ID            string  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	Name          string  `gorm:"type:varchar(255);not null" json:"name"`
+	Product       string  `gorm:"type:varchar(30);not null;index" json:"product"`
+	VehicleTypeID *string `gorm:"type:uuid" json:"vehicleTypeId,omitempty"`
+	CategorySlug  *string `gorm:"type:varchar(255)" json:"categorySlug,omitempty"`
+	City          string  `gorm:"type:varchar(100)" json:"city,omitempty"`
+	CenterLat     float64 `gorm:"type:decimal(10,8);default:0" json:"centerLat"`
+	CenterLon     float64 `gorm:"type:decimal(11,8);default:0" json:"centerLon"`
+	RadiusKm      float64 `gorm:"type:decimal(8,2);default:0" json:"radiusKm"`
+
+	// FreeWindowSeconds is how long after acceptance the rider or customer may
+	// still cancel without a fee.
+	FreeWindowSeconds int `gorm:"not null;default:0" json:"freeWindowSeconds"`
+
+	CustomerFees    CancellationFeeSchedule `gorm:"type:jsonb;not null" json:"customerFees"`
+	DriverPenalties CancellationFeeSchedule `gorm:"type:jsonb;not null" json:"driverPenalties"`
+
+	// A driver or provider who has not turned up this many minutes after
+	// accepting (or after the booked slot, for orders) is a no-show: the
+	// customer cancels for free and the driver pays DriverNoShowPenalty.
+	DriverNoShowMinutes int             `gorm:"not null;default:0" json:"driverNoShowMinutes"`
+	DriverNoShowPenalty CancellationFee `gorm:"type:jsonb" json:"driverNoShowPenalty"`
+
+	// A rider who keeps an arrived driver waiting this many minutes is a
+	// no-show: the driver cancels without penalty and the rider pays
+	// RiderNoShowFee.
+	RiderNoShowMinutes int             `gorm:"not null;default:0" json:"riderNoShowMinutes"`
+	RiderNoShowFee     CancellationFee `gorm:"type:jsonb" json:"riderNoShowFee"`
+
+	Priority  int            `gorm:"not null;default:0" json:"priority"`
+	IsActive  bool           `gorm:"default:true;index" json:"isActive"`
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+func (CancellationPolicy) TableName() string {
+	return "cancellation_policies"
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	SurchargeKindEnvironmental = "environmental"
+	SurchargeKindDisposal      = "disposal"
+	SurchargeKindOther         = "other"
+
+	SurchargeTypeFixed      = "fixed"
+	SurchargeTypePercentage = "percentage"
+)
+
+// CategorySurcharge is an order-level fee applied to every order in a category,
+// e.g. waste disposal for pest control or renovation. Surcharges are passed
+// through to the authority or contractor they are owed to, so they are kept out
+// of the provider payout and the platform commission base.
+type CategorySurcharge struct {
+	ID           string         `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CategorySlug string         `gorm:"type:varchar(255);not null;uniqueIndex:idx_category_surcharge_code" json:"categorySlug"`
+	Code         string         `gorm:"type:varchar(50);not null;uniqueIndex:idx_category_surcharge_code" json:"code"`
+	Name         string         `gorm:"type:varchar(255);not null" json:"name"`
+	Description  string         `gorm:"type:text" json:"description"`
+	Kind         string         `gorm:"type:varchar(30);not null;default:'other'" json:"kind"`
+	Type         string         `gorm:"type:varchar(20);not null;default:'fixed'" json:"type"`
+	Amount       float64        `gorm:"type:decimal(10,2);not null" json:"amount"`
+	MinAmount    *float64       `gorm:"type:decimal(10,2)" json:"minAmount,omitempty"`
+	MaxAmount    *float64       `gorm:"type:decimal(10,2)" json:"maxAmount,omitempty"`
+	IsActive     bool           `gorm:"default:true;index" json:"isActive"`
+	CreatedAt    time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt    time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+func (CategorySurcharge) TableName() string {
+	return "category_surcharges"
+}
+
+// Calculate returns the surcharge for an order with the given subtotal.
+// Percentage surcharges are clamped to the optional min/max.
+func (s *CategorySurcharge) Calculate(subtotal float64) float64 {
+	amount := s.Amount
+	if s.Type == SurchargeTypePercentage {
+		amount = subtotal * s.Amount / 100
+		if s.MinAmount != nil && amount < *s.MinAmount {
+			amount = *s.MinAmount
+		}
+		if s.MaxAmount != nil && amount > *s.MaxAmount {
+			amount = *s.MaxAmount
+		}
+	}
+	return amount
+}
+
+// ServiceOrderSurcharge is the accounting line for a surcharge charged on an
+// order. Lines are collectable once the order completes and are marked
+// remitted when the money is paid over.
+type ServiceOrderSurcharge struct {
+	ID            string     `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	OrderID       string     `gorm:"type:uuid;not null;index" json:"orderId"`
+	SurchargeID   string     `gorm:"type:uuid;not null;index" json:"surchargeId"`
+	CategorySlug  string     `gorm:"type:varchar(255);not null;index" json:"categorySlug"`
+	Code          string     `gorm:"type:varchar(50);not null" json:"code"`
+	Name          string     `gorm:"type:varchar(255);not null" json:"name"`
+	Kind          string     `gorm:"type:varchar(30);not null" json:"kind"`
+	Amount        float64    `gorm:"type:decimal(10,2);not null" json:"amount"`
+	RemittedAt    *time.Time `gorm:"index" json:"remittedAt,omitempty"`
+	RemittedBy    *string    `gorm:"type:uuid" json:"remittedBy,omitempty"`
+	RemittanceRef string     `gorm:"type:varchar(100)" json:"remittanceRef,omitempty"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (ServiceOrderSurcharge) TableName() string {
+	return "service_order_surcharges"
+}
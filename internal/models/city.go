@@ -0,0 +1,157 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/utils/location"
+	"gorm.io/gorm"
+)
+
+// CityBoundary outlines a city's service area as a ring of points; the last
+// point joins back to the first.
+type CityBoundary []location.Point
+
+func (b CityBoundary) Value() (driver.Value, error) {
+	return json.Marshal(b)
+}
+
+func (b *CityBoundary) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, b)
+}
+
+// MatchingRadii are the distances, in km and smallest first, searched in turn
+// for a driver.
+type MatchingRadii []float64
+
+func (r MatchingRadii) Value() (driver.Value, error) {
+	return json.Marshal(r)
+}
+
+func (r *MatchingRadii) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, r)
+}
+
+// Dispatch modes decide how many drivers a ride is offered to at once.
+const (
+	DispatchModeSequential = "sequential"
+	DispatchModeBatch      = "batch"
+	DispatchModeBroadcast  = "broadcast"
+)
+
+// DispatchSettings are a city's overrides of how rides are offered to
+// drivers. Zero values leave the platform default in place.
+type DispatchSettings struct {
+	Mode         string
+	BatchSize    int
+	OfferTimeout time.Duration
+}
+
+// City is an operating city. Rides and orders are only taken inside the
+// boundary of an active city, and each city can adjust fares, cap surge and
+// set how far to look for drivers.
+type City struct {
+	ID       string       `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	Code     string       `gorm:"type:varchar(50);not null;uniqueIndex" json:"code"`
+	Name     string       `gorm:"type:varchar(255);not null" json:"name"`
+	Timezone string       `gorm:"type:varchar(64)" json:"timezone,omitempty"`
+	Boundary CityBoundary `gorm:"type:jsonb;not null" json:"boundary"`
+
+	// FareMultiplier scales every vehicle type's rates in the city, before any
+	// per-vehicle override.
+	FareMultiplier float64 `gorm:"type:decimal(5,2);not null;default:1" json:"fareMultiplier"`
+	// MaxSurgeMultiplier caps surge in the city; zero leaves it uncapped.
+	MaxSurgeMultiplier float64       `gorm:"type:decimal(4,2);not null;default:0" json:"maxSurgeMultiplier"`
+	MatchingRadiiKm    MatchingRadii `gorm:"type:jsonb" json:"matchingRadiiKm"`
+	// DispatchMode and DispatchBatchSize override the platform's dispatch
+	// settings for rides picked up in the city.
+	DispatchMode      *string `gorm:"type:varchar(20)" json:"dispatchMode,omitempty"`
+	DispatchBatchSize *int    `json:"dispatchBatchSize,omitempty"`
+	// RestrictVehicleTypes offers only the vehicle types enabled for the city.
+	// Otherwise every active type is offered unless the city disables it.
+	RestrictVehicleTypes bool `gorm:"not null;default:false" json:"restrictVehicleTypes"`
+
+	IsActive  bool           `gorm:"default:true;index" json:"isActive"`
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	VehicleTypes []CityVehicleType `gorm:"foreignKey:CityID" json:"vehicleTypes,omitempty"`
+}
+
+func (City) TableName() string {
+	return "cities"
+}
+
+// Dispatch returns the city's dispatch overrides for a vehicle type.
+func (c *City) Dispatch(vehicleTypeID string) DispatchSettings {
+	var settings DispatchSettings
+	if c.DispatchMode != nil {
+		settings.Mode = *c.DispatchMode
+	}
+	if c.DispatchBatchSize != nil {
+		settings.BatchSize = *c.DispatchBatchSize
+	}
+	for _, vehicleType := range c.VehicleTypes {
+		if vehicleType.VehicleTypeID == vehicleTypeID && vehicleType.OfferTimeoutSec != nil {
+			settings.OfferTimeout = time.Duration(*vehicleType.OfferTimeoutSec) * time.Second
+			break
+		}
+	}
+	return settings
+}
+
+// OffersVehicleType reports whether riders can book a vehicle type in the
+// city, and returns the city's configuration of it, if any.
+func (c *City) OffersVehicleType(vehicleTypeID string) (*CityVehicleType, bool) {
+	for i := range c.VehicleTypes {
+		if c.VehicleTypes[i].VehicleTypeID == vehicleTypeID {
+			return &c.VehicleTypes[i], c.VehicleTypes[i].IsAvailable
+		}
+	}
+	return nil, !c.RestrictVehicleTypes
+}
+
+// Contains reports whether a coordinate lies inside the city's boundary.
+func (c *City) Contains(lat, lon float64) bool {
+	return location.PointInPolygon(lat, lon, c.Boundary)
+}
+
+// CityVehicleType configures one vehicle type in a city. Rates left nil keep
+// the vehicle type's own, scaled by the city's fare multiplier.
+// OfferTimeoutSec is how long a driver has to answer a ride offer for the
+// vehicle type in the city. SeatCapacity and LuggageCapacity, when set,
+// replace the vehicle type's own, e.g. where local regulations allow fewer
+// passengers.
+type CityVehicleType struct {
+	ID                 string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CityID             string    `gorm:"type:uuid;not null;uniqueIndex:idx_city_vehicle_type" json:"cityId"`
+	VehicleTypeID      string    `gorm:"type:uuid;not null;uniqueIndex:idx_city_vehicle_type" json:"vehicleTypeId"`
+	IsAvailable        bool      `gorm:"not null;default:true" json:"isAvailable"`
+	BaseFare           *float64  `gorm:"type:decimal(10,2)" json:"baseFare,omitempty"`
+	PerKmRate          *float64  `gorm:"type:decimal(10,2)" json:"perKmRate,omitempty"`
+	PerMinuteRate      *float64  `gorm:"type:decimal(10,2)" json:"perMinuteRate,omitempty"`
+	BookingFee         *float64  `gorm:"type:decimal(10,2)" json:"bookingFee,omitempty"`
+	MaxSurgeMultiplier *float64  `gorm:"type:decimal(4,2)" json:"maxSurgeMultiplier,omitempty"`
+	OfferTimeoutSec    *int      `json:"offerTimeoutSec,omitempty"`
+	SeatCapacity       *int      `json:"seatCapacity,omitempty"`
+	LuggageCapacity    *int      `json:"luggageCapacity,omitempty"`
+	CreatedAt          time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt          time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+
+	VehicleType *VehicleType `gorm:"foreignKey:VehicleTypeID" json:"vehicleType,omitempty"`
+}
+
+func (CityVehicleType) TableName() string {
+	return "city_vehicle_types"
+}
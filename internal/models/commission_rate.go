@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+const (
+	CommissionKindRide        = "ride"
+	CommissionKindHomeService = "homeservice"
+)
+
+// CommissionRate is the platform's cut, in percent, of rides of a vehicle
+// type or orders of a home-service category. Rows are never edited: a new
+// rate is a new row with a later EffectiveFrom. VehicleTypeID, CategorySlug
+// and CityID narrow the rate; a row with none of them is the default.
+type CommissionRate struct {
+	ID            string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	Kind          string    `gorm:"type:varchar(20);not null" json:"kind"`
+	VehicleTypeID *string   `gorm:"type:uuid" json:"vehicleTypeId,omitempty"`
+	CategorySlug  *string   `gorm:"type:varchar(100)" json:"categorySlug,omitempty"`
+	CityID        *string   `gorm:"type:uuid" json:"cityId,omitempty"`
+	Rate          float64   `gorm:"type:decimal(5,2);not null" json:"rate"`
+	EffectiveFrom time.Time `gorm:"not null" json:"effectiveFrom"`
+	Note          string    `gorm:"type:text" json:"note"`
+	CreatedBy     *string   `gorm:"type:uuid" json:"createdBy,omitempty"`
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (CommissionRate) TableName() string {
+	return "commission_rates"
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CityCurrency sets the currency prices are quoted and charged in within a
+// city, given as the circle around its centre. Where circles overlap the
+// smallest one wins; outside all of them the platform default applies.
+type CityCurrency struct {
+	ID        string  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	City      string  `gorm:"type:varchar(100);not null" json:"city"`
+	Currency  string  `gorm:"type:varchar(3);not null" json:"currency"`
+	CenterLat float64 `gorm:"type:decimal(10,8);not null" json:"centerLat"`
+	CenterLon float64 `gorm:"type:decimal(11,8);not null" json:"centerLon"`
+	RadiusKm  float64 `gorm:"type:decimal(8,2);not null" json:"radiusKm"`
+
+	IsActive  bool           `gorm:"default:true;index" json:"isActive"`
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+func (CityCurrency) TableName() string {
+	return "city_currencies"
+}
+
+const (
+	ExchangeRateSourceManual = "manual"
+)
+
+// ExchangeRate is how many units of Currency one unit of BaseCurrency buys.
+// Rates are kept against the platform default only; conversions between two
+// other currencies go through it.
+type ExchangeRate struct {
+	BaseCurrency string    `gorm:"type:varchar(3);primaryKey" json:"baseCurrency"`
+	Currency     string    `gorm:"type:varchar(3);primaryKey" json:"currency"`
+	Rate         float64   `gorm:"type:decimal(18,8);not null" json:"rate"`
+	Source       string    `gorm:"type:varchar(30);not null" json:"source"`
+	UpdatedBy    *string   `gorm:"type:uuid" json:"updatedBy,omitempty"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (ExchangeRate) TableName() string {
+	return "exchange_rates"
+}
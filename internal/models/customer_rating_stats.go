@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// CustomerRatingStats is the running average of the ratings providers gave a
+// customer on completed home-service orders. It is kept up to date on every
+// rating and corrected by the nightly reconciliation.
+type CustomerRatingStats struct {
+	UserID       string    `gorm:"type:uuid;primaryKey" json:"userId"`
+	Rating       float64   `gorm:"type:decimal(3,2);not null;default:0" json:"rating"`
+	TotalReviews int       `gorm:"not null;default:0" json:"totalReviews"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (CustomerRatingStats) TableName() string {
+	return "customer_rating_stats"
+}
@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+type DisputeStatus string
+
+const (
+	DisputeStatusOpen      DisputeStatus = "open"
+	DisputeStatusResponded DisputeStatus = "responded"
+	DisputeStatusResolved  DisputeStatus = "resolved"
+	DisputeStatusWithdrawn DisputeStatus = "withdrawn"
+)
+
+type DisputeOutcome string
+
+const (
+	DisputeOutcomeRefund        DisputeOutcome = "refund"
+	DisputeOutcomePartialRefund DisputeOutcome = "partial_refund"
+	DisputeOutcomeRejected      DisputeOutcome = "rejected"
+)
+
+// What a dispute can be opened against.
+const (
+	DisputeSubjectRide         = "ride"
+	DisputeSubjectServiceOrder = "service_order"
+)
+
+// Dispute is a customer's complaint about a completed ride or home-service
+// order. The driver or provider may answer it once, and an admin closes it
+// with an outcome; refunds are credited to the customer's wallet.
+type Dispute struct {
+	ID             string          `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	SubjectType    string          `gorm:"type:varchar(20);not null" json:"subjectType"`
+	SubjectID      string          `gorm:"type:uuid;not null" json:"subjectId"`
+	Reference      string          `gorm:"type:varchar(50)" json:"reference"`
+	OpenedBy       string          `gorm:"type:uuid;not null;index" json:"openedBy"`
+	RespondentID   *string         `gorm:"type:uuid;index" json:"respondentId,omitempty"`
+	Reason         string          `gorm:"type:varchar(30);not null" json:"reason"`
+	Description    string          `gorm:"type:text;not null" json:"description"`
+	AmountPaid     float64         `gorm:"type:decimal(10,2);not null;default:0" json:"amountPaid"`
+	Currency       string          `gorm:"type:varchar(3)" json:"currency"`
+	Status         DisputeStatus   `gorm:"type:varchar(20);not null;default:'open';index" json:"status"`
+	Response       string          `gorm:"type:text" json:"response"`
+	RespondedAt    *time.Time      `json:"respondedAt,omitempty"`
+	Outcome        *DisputeOutcome `gorm:"type:varchar(20)" json:"outcome,omitempty"`
+	RefundAmount   float64         `gorm:"type:decimal(10,2);not null;default:0" json:"refundAmount"`
+	RefundTxnID    *string         `gorm:"type:uuid" json:"refundTxnId,omitempty"`
+	ResolvedBy     *string         `gorm:"type:uuid" json:"resolvedBy,omitempty"`
+	ResolutionNote string          `gorm:"type:text" json:"resolutionNote"`
+	ResolvedAt     *time.Time      `json:"resolvedAt,omitempty"`
+	WithdrawnAt    *time.Time      `json:"withdrawnAt,omitempty"`
+	CreatedAt      time.Time       `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt      time.Time       `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (Dispute) TableName() string {
+	return "disputes"
+}
+
+func (d *Dispute) IsOpen() bool {
+	return d.Status == DisputeStatusOpen || d.Status == DisputeStatusResponded
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// DriverDebtRepayment is the part of a credit to a driver wallet that paid
+// down a negative balance. A credit that leaves the wallet in the red still
+// counts in full.
+type DriverDebtRepayment struct {
+	ID            string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	UserID        string    `gorm:"type:uuid;not null;index" json:"userId"`
+	WalletID      string    `gorm:"type:uuid;not null" json:"walletId"`
+	TransactionID string    `gorm:"type:uuid;not null;uniqueIndex" json:"transactionId"`
+	Amount        float64   `gorm:"type:decimal(12,2);not null" json:"amount"`
+	DebtBefore    float64   `gorm:"type:decimal(12,2);not null" json:"debtBefore"`
+	DebtAfter     float64   `gorm:"type:decimal(12,2);not null" json:"debtAfter"`
+	Source        string    `gorm:"type:varchar(50);not null" json:"source"`
+	Currency      string    `gorm:"type:varchar(3);not null" json:"currency"`
+	CreatedAt     time.Time `gorm:"autoCreateTime;index" json:"createdAt"`
+}
+
+func (DriverDebtRepayment) TableName() string {
+	return "driver_debt_repayments"
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// DriverOnlineSession is a span during which a driver was signed on, in any
+// status but offline. Rows are written by a database trigger on
+// driver_profiles.status; EndedAt is nil while the driver is still on.
+type DriverOnlineSession struct {
+	ID        string     `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	DriverID  string     `gorm:"type:uuid;not null;index" json:"driverId"`
+	StartedAt time.Time  `gorm:"not null" json:"startedAt"`
+	EndedAt   *time.Time `json:"endedAt,omitempty"`
+}
+
+func (DriverOnlineSession) TableName() string {
+	return "driver_online_sessions"
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/utils/location"
+)
+
+// PreferredArea is a circle a driver prefers to work in.
+type PreferredArea struct {
+	Name      string  `json:"name"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	RadiusKm  float64 `json:"radiusKm"`
+}
+
+// Contains reports whether a coordinate lies inside the area.
+func (a PreferredArea) Contains(lat, lon float64) bool {
+	return location.HaversineDistance(a.Latitude, a.Longitude, lat, lon) <= a.RadiusKm
+}
+
+type PreferredAreas []PreferredArea
+
+func (a PreferredAreas) Value() (driver.Value, error) {
+	return json.Marshal(a)
+}
+
+func (a *PreferredAreas) Scan(value interface{}) error {
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, a)
+}
+
+// DriverDispatchPreferences are a driver's own rules for the ride offers they
+// get. With auto-accept on, an offer within AutoAcceptMaxPickupKm paying at
+// least AutoAcceptMinFare is accepted for them; a limit left nil does not
+// apply. Rides picked up in one of the PreferredAreas are offered to the
+// driver ahead of other drivers. DriverID is the driver's profile ID.
+type DriverDispatchPreferences struct {
+	ID                    string         `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	DriverID              string         `gorm:"type:uuid;uniqueIndex;not null" json:"driverId"`
+	AutoAcceptEnabled     bool           `gorm:"not null;default:false" json:"autoAcceptEnabled"`
+	AutoAcceptMaxPickupKm *float64       `gorm:"type:decimal(5,2)" json:"autoAcceptMaxPickupKm,omitempty"`
+	AutoAcceptMinFare     *float64       `gorm:"type:decimal(10,2)" json:"autoAcceptMinFare,omitempty"`
+	PreferredAreas        PreferredAreas `gorm:"type:jsonb" json:"preferredAreas"`
+	CreatedAt             time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt             time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (DriverDispatchPreferences) TableName() string {
+	return "driver_dispatch_preferences"
+}
+
+// AutoAccepts reports whether an offer with the given pickup distance and
+// fare is accepted without asking the driver. It is safe to call on nil.
+func (p *DriverDispatchPreferences) AutoAccepts(pickupKm, fare float64) bool {
+	if p == nil || !p.AutoAcceptEnabled {
+		return false
+	}
+	if p.AutoAcceptMaxPickupKm != nil && pickupKm > *p.AutoAcceptMaxPickupKm {
+		return false
+	}
+	if p.AutoAcceptMinFare != nil && fare < *p.AutoAcceptMinFare {
+		return false
+	}
+	return true
+}
+
+// Prefers reports whether a pickup lies in one of the driver's preferred
+// areas. It is safe to call on nil.
+func (p *DriverDispatchPreferences) Prefers(lat, lon float64) bool {
+	if p == nil {
+		return false
+	}
+	for _, area := range p.PreferredAreas {
+		if area.Contains(lat, lon) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+type EmailStatus string
+
+const (
+	EmailStatusQueued    EmailStatus = "queued"
+	EmailStatusSent      EmailStatus = "sent"
+	EmailStatusFailed    EmailStatus = "failed"
+	EmailStatusDelivered EmailStatus = "delivered"
+	EmailStatusBounced   EmailStatus = "bounced"
+	EmailStatusDropped   EmailStatus = "dropped"
+	EmailStatusComplaint EmailStatus = "complaint"
+)
+
+// IsFinal reports whether the provider will report nothing further for an
+// email in this status.
+func (s EmailStatus) IsFinal() bool {
+	switch s {
+	case EmailStatusFailed, EmailStatusBounced, EmailStatusDropped, EmailStatusComplaint:
+		return true
+	}
+	return false
+}
+
+// EmailLog records one transactional email: who it went to, which template
+// it was rendered from, and the last delivery status the provider reported.
+// ReferenceType and ReferenceID point at what the email was about, such as a
+// receipt or a dispute.
+type EmailLog struct {
+	ID                string      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	UserID            *string     `gorm:"type:uuid;index" json:"userId,omitempty"`
+	ToEmail           string      `gorm:"type:varchar(255);not null" json:"toEmail"`
+	Template          string      `gorm:"type:varchar(50);not null" json:"template"`
+	Subject           string      `gorm:"type:varchar(255);not null" json:"subject"`
+	Provider          string      `gorm:"type:varchar(20);not null" json:"provider"`
+	ProviderMessageID *string     `gorm:"type:varchar(255);index" json:"providerMessageId,omitempty"`
+	Status            EmailStatus `gorm:"type:varchar(20);not null;default:'queued'" json:"status"`
+	Error             string      `gorm:"type:text" json:"error,omitempty"`
+	ReferenceType     string      `gorm:"type:varchar(50)" json:"referenceType,omitempty"`
+	ReferenceID       string      `gorm:"type:varchar(100)" json:"referenceId,omitempty"`
+	SentAt            *time.Time  `json:"sentAt,omitempty"`
+	StatusUpdatedAt   *time.Time  `json:"statusUpdatedAt,omitempty"`
+	CreatedAt         time.Time   `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt         time.Time   `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (EmailLog) TableName() string {
+	return "email_logs"
+}
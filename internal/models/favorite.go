@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// FavoriteDriver is a driver a rider has marked as a favorite. DriverID is the
+// driver's user ID, as shown on rides. Favorites are offered a rider's rides
+// before other nearby drivers.
+type FavoriteDriver struct {
+	ID        string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	RiderID   string    `gorm:"type:uuid;not null;uniqueIndex:idx_favorite_driver" json:"riderId"`
+	DriverID  string    `gorm:"type:uuid;not null;uniqueIndex:idx_favorite_driver;index" json:"driverId"`
+	Note      string    `gorm:"type:varchar(255)" json:"note,omitempty"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
+
+	Driver        *User          `gorm:"foreignKey:DriverID" json:"driver,omitempty"`
+	DriverProfile *DriverProfile `gorm:"foreignKey:UserID;references:DriverID" json:"driverProfile,omitempty"`
+}
+
+func (FavoriteDriver) TableName() string {
+	return "favorite_drivers"
+}
+
+// PreferredProvider is a home-service provider a customer prefers. When one is
+// free, the customer's new orders are shown to them ahead of other providers.
+type PreferredProvider struct {
+	ID         string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CustomerID string    `gorm:"type:uuid;not null;uniqueIndex:idx_preferred_provider" json:"customerId"`
+	ProviderID string    `gorm:"type:uuid;not null;uniqueIndex:idx_preferred_provider;index" json:"providerId"`
+	Note       string    `gorm:"type:varchar(255)" json:"note,omitempty"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"createdAt"`
+
+	Provider *ServiceProviderProfile `gorm:"foreignKey:ProviderID" json:"provider,omitempty"`
+}
+
+func (PreferredProvider) TableName() string {
+	return "preferred_providers"
+}
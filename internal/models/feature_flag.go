@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+const (
+	FeatureFlagBoolean = "boolean"
+	FeatureFlagNumber  = "number"
+	FeatureFlagString  = "string"
+)
+
+// FeatureFlag is a runtime setting services read without a redeploy. Value
+// holds the JSON encoding of a value of Type. RolloutPercent only applies to
+// boolean flags read for a subject: the flag is on for that share of
+// subjects.
+type FeatureFlag struct {
+	Key            string    `gorm:"type:varchar(64);primaryKey" json:"key"`
+	Type           string    `gorm:"type:varchar(20);not null" json:"type"`
+	Value          string    `gorm:"type:jsonb;not null" json:"value"`
+	RolloutPercent int       `gorm:"not null;default:100" json:"rolloutPercent"`
+	Description    string    `gorm:"type:text;not null;default:''" json:"description"`
+	UpdatedBy      *string   `gorm:"type:uuid" json:"updatedBy,omitempty"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (FeatureFlag) TableName() string {
+	return "feature_flags"
+}
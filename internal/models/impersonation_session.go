@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// ImpersonationSession is a support admin acting as a user. Its ID is the
+// session bound into the impersonation token, so ending the session revokes
+// the token. The token is read-only unless AllowWrites was granted.
+type ImpersonationSession struct {
+	ID          string     `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	AdminID     string     `gorm:"type:uuid;not null;index" json:"adminId"`
+	UserID      string     `gorm:"type:uuid;not null;index" json:"userId"`
+	Reason      string     `gorm:"type:text;not null" json:"reason"`
+	AllowWrites bool       `gorm:"not null;default:false" json:"allowWrites"`
+	IPAddress   string     `gorm:"type:varchar(45)" json:"ipAddress"`
+	ExpiresAt   time.Time  `gorm:"not null" json:"expiresAt"`
+	EndedAt     *time.Time `json:"endedAt,omitempty"`
+	EndedBy     *string    `gorm:"type:uuid" json:"endedBy,omitempty"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+func (ImpersonationSession) TableName() string {
+	return "impersonation_sessions"
+}
+
+func (s *ImpersonationSession) IsActive(now time.Time) bool {
+	return s.EndedAt == nil && s.ExpiresAt.After(now)
+}
@@ -0,0 +1,173 @@
+package models
+
+import (
+	"time"
+
+	"github.com/umar5678/go-backend/internal/utils/location"
+	"gorm.io/gorm"
+)
+
+type IncentiveCampaignType string
+
+const (
+	// IncentiveQuest pays RewardAmount once a driver completes TargetRides
+	// qualifying rides within the campaign.
+	IncentiveQuest IncentiveCampaignType = "quest"
+	// IncentiveBoost pays RewardAmount on top of the fare for every
+	// qualifying ride, up to MaxRewardsPerDriver rides when it is set.
+	IncentiveBoost IncentiveCampaignType = "boost"
+)
+
+type IncentiveCampaignStatus string
+
+const (
+	IncentiveStatusActive    IncentiveCampaignStatus = "active"
+	IncentiveStatusPaused    IncentiveCampaignStatus = "paused"
+	IncentiveStatusCancelled IncentiveCampaignStatus = "cancelled"
+)
+
+// IncentiveCampaign rewards drivers for rides completed between StartsAt and
+// EndsAt. A ride qualifies when its pickup is in the city and zone, it used
+// the vehicle type, and it was completed within the daily hours, for
+// whichever of those the campaign sets.
+type IncentiveCampaign struct {
+	ID          string                  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	Name        string                  `gorm:"type:varchar(200);not null" json:"name"`
+	Description string                  `gorm:"type:text" json:"description"`
+	Type        IncentiveCampaignType   `gorm:"type:varchar(10);not null" json:"type"`
+	Status      IncentiveCampaignStatus `gorm:"type:varchar(20);not null;default:'active'" json:"status"`
+
+	CityID       *string  `gorm:"type:uuid" json:"cityId,omitempty"`
+	ZoneName     string   `gorm:"type:varchar(255)" json:"zoneName,omitempty"`
+	ZoneLat      *float64 `gorm:"type:decimal(10,8)" json:"zoneLat,omitempty"`
+	ZoneLon      *float64 `gorm:"type:decimal(11,8)" json:"zoneLon,omitempty"`
+	ZoneRadiusKm *float64 `gorm:"type:decimal(6,2)" json:"zoneRadiusKm,omitempty"`
+
+	VehicleTypeID *string `gorm:"type:uuid" json:"vehicleTypeId,omitempty"`
+
+	StartsAt time.Time `gorm:"not null" json:"startsAt"`
+	EndsAt   time.Time `gorm:"not null" json:"endsAt"`
+	// DailyStart and DailyEnd ("15:04", in Timezone) limit rides to those
+	// hours each day; the window may run past midnight.
+	DailyStart *string `gorm:"type:varchar(5)" json:"dailyStart,omitempty"`
+	DailyEnd   *string `gorm:"type:varchar(5)" json:"dailyEnd,omitempty"`
+	Timezone   string  `gorm:"type:varchar(64);not null;default:'UTC'" json:"timezone"`
+
+	TargetRides         int     `gorm:"not null;default:0" json:"targetRides"`
+	RewardAmount        float64 `gorm:"type:decimal(10,2);not null" json:"rewardAmount"`
+	MaxRewardsPerDriver int     `gorm:"not null;default:0" json:"maxRewardsPerDriver"`
+	Currency            string  `gorm:"type:varchar(3);not null" json:"currency"`
+
+	CreatedBy string         `gorm:"type:uuid;not null" json:"createdBy"`
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+func (IncentiveCampaign) TableName() string {
+	return "incentive_campaigns"
+}
+
+// IsRunning reports whether rides completed at t count towards the campaign.
+func (c *IncentiveCampaign) IsRunning(t time.Time) bool {
+	return c.Status == IncentiveStatusActive && !t.Before(c.StartsAt) && t.Before(c.EndsAt)
+}
+
+// InZone reports whether a pickup is inside the campaign's zone. Campaigns
+// without a zone cover everywhere.
+func (c *IncentiveCampaign) InZone(lat, lon float64) bool {
+	if c.ZoneLat == nil || c.ZoneLon == nil || c.ZoneRadiusKm == nil {
+		return true
+	}
+	return location.IsWithinRadius(*c.ZoneLat, *c.ZoneLon, lat, lon, *c.ZoneRadiusKm)
+}
+
+// InDailyHours reports whether t falls within the campaign's daily hours.
+// Campaigns without them cover the whole day.
+func (c *IncentiveCampaign) InDailyHours(t time.Time) bool {
+	if c.DailyStart == nil || c.DailyEnd == nil {
+		return true
+	}
+	start, errStart := time.Parse("15:04", *c.DailyStart)
+	end, errEnd := time.Parse("15:04", *c.DailyEnd)
+	if errStart != nil || errEnd != nil || *c.DailyStart == *c.DailyEnd {
+		return true
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+
+	local := t.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	startMinute := start.Hour()*60 + start.Minute()
+	endMinute := end.Hour()*60 + end.Minute()
+	if startMinute < endMinute {
+		return minute >= startMinute && minute < endMinute
+	}
+	return minute >= startMinute || minute < endMinute
+}
+
+// IncentiveProgress is a driver's standing in a campaign. CompletedAt is
+// set when a quest's target is reached.
+type IncentiveProgress struct {
+	ID           string     `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CampaignID   string     `gorm:"type:uuid;not null;uniqueIndex:uq_incentive_progress_driver" json:"campaignId"`
+	DriverID     string     `gorm:"type:uuid;not null;uniqueIndex:uq_incentive_progress_driver" json:"driverId"`
+	RideCount    int        `gorm:"not null;default:0" json:"rideCount"`
+	RewardCount  int        `gorm:"not null;default:0" json:"rewardCount"`
+	EarnedAmount float64    `gorm:"type:decimal(10,2);not null;default:0" json:"earnedAmount"`
+	CompletedAt  *time.Time `json:"completedAt,omitempty"`
+	LastRideAt   *time.Time `json:"lastRideAt,omitempty"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (IncentiveProgress) TableName() string {
+	return "incentive_progress"
+}
+
+// IncentiveRide records a ride counted towards a campaign, so a ride is
+// never counted twice.
+type IncentiveRide struct {
+	CampaignID string    `gorm:"type:uuid;primaryKey" json:"campaignId"`
+	RideID     string    `gorm:"type:uuid;primaryKey" json:"rideId"`
+	DriverID   string    `gorm:"type:uuid;not null" json:"driverId"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (IncentiveRide) TableName() string {
+	return "incentive_rides"
+}
+
+type IncentiveRewardStatus string
+
+const (
+	IncentiveRewardPending    IncentiveRewardStatus = "pending"
+	IncentiveRewardProcessing IncentiveRewardStatus = "processing"
+	IncentiveRewardPaid       IncentiveRewardStatus = "paid"
+)
+
+// IncentiveReward is money a campaign owes a driver: a quest bonus, or a
+// boost on RideID. It is claimed while the wallet is credited so it is paid
+// once even when the sweeper retries it.
+type IncentiveReward struct {
+	ID          string                `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CampaignID  string                `gorm:"type:uuid;not null" json:"campaignId"`
+	DriverID    string                `gorm:"type:uuid;not null;index" json:"driverId"`
+	RideID      *string               `gorm:"type:uuid" json:"rideId,omitempty"`
+	Amount      float64               `gorm:"type:decimal(10,2);not null" json:"amount"`
+	Currency    string                `gorm:"type:varchar(3);not null" json:"currency"`
+	Status      IncentiveRewardStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	ClaimedAt   *time.Time            `json:"claimedAt,omitempty"`
+	WalletTxnID *string               `gorm:"type:uuid" json:"walletTxnId,omitempty"`
+	PaidAt      *time.Time            `json:"paidAt,omitempty"`
+	CreatedAt   time.Time             `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt   time.Time             `gorm:"autoUpdateTime" json:"updatedAt"`
+
+	Campaign *IncentiveCampaign `gorm:"foreignKey:CampaignID" json:"campaign,omitempty"`
+}
+
+func (IncentiveReward) TableName() string {
+	return "incentive_rewards"
+}
@@ -69,6 +69,7 @@ type LaundryOrderItem struct {
 	ItemType         string     `gorm:"type:varchar(100)" json:"itemType"`
 	Quantity         int        `gorm:"default:1" json:"quantity"`
 	Weight           *float64   `gorm:"type:decimal(8,3)" json:"weight,omitempty"`
+	MeasuredWeight   *float64   `gorm:"type:decimal(8,3)" json:"measuredWeight,omitempty"`
 	QRCode           string     `gorm:"type:varchar(255);uniqueIndex" json:"qrCode"`
 	Status           string     `gorm:"type:varchar(50);default:'pending'" json:"status"`
 	HasIssue         bool       `gorm:"default:false" json:"hasIssue"`
@@ -134,6 +135,7 @@ type LaundryDelivery struct {
 	RecipientSignature *string    `gorm:"type:text" json:"recipientSignature,omitempty"`
 	Notes              string     `gorm:"type:text" json:"notes"`
 	RescheduleCount    int        `gorm:"default:0" json:"rescheduleCount"`
+	FailedAttempts     int        `gorm:"default:0" json:"failedAttempts"`
 	CreatedAt          time.Time  `json:"createdAt"`
 	UpdatedAt          time.Time  `json:"updatedAt"`
 }
@@ -190,6 +192,9 @@ type LaundryOrder struct {
 	Latitude  float64 `gorm:"type:decimal(10,8)" json:"lat"`
 	Longitude float64 `gorm:"type:decimal(11,8)" json:"lng"`
 
+	NormalizedAddress string `gorm:"type:text" json:"normalizedAddress,omitempty"`
+	AddressScript     string `gorm:"type:varchar(20)" json:"addressScript,omitempty"`
+
 	PersonCount   int  `gorm:"default:1" json:"personCount"`
 
 	ServiceDate *time.Time `json:"serviceDate,omitempty"`
@@ -197,11 +202,19 @@ type LaundryOrder struct {
 	Tip         *float64   `gorm:"type:decimal(10,2)" json:"tip,omitempty"`
 	IsExpress   bool       `gorm:"type:boolean;default:false" json:"isExpress"`
 
+	// CommissionRate is the platform's cut in percent when the order was placed.
+	CommissionRate float64 `gorm:"type:decimal(5,2);not null;default:10" json:"commissionRate"`
+
 	ProviderID *string `gorm:"type:uuid;index" json:"providerId,omitempty"`
+	FacilityID *string `gorm:"type:uuid;index" json:"facilityId,omitempty"`
+
+	WalletHoldID     *string `gorm:"type:uuid" json:"walletHoldId,omitempty"`
+	SettlementStatus *string `gorm:"type:varchar(20)" json:"settlementStatus,omitempty"`
 
 	CreatedAt time.Time  `gorm:"autoCreateTime" json:"createdAt"`
 	UpdatedAt time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
 	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	Version   int64      `gorm:"not null;default:0" json:"version"`
 }
 
 func (LaundryOrder) TableName() string {
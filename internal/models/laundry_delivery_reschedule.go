@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	LaundryRescheduleByCustomer = "customer"
+	LaundryRescheduleByProvider = "provider"
+)
+
+// LaundryDeliveryReschedule records one move of a laundry delivery to a new
+// slot. FailedAttempt marks moves the provider made because the laundry could
+// not be handed over; Fee is what that attempt added to the order.
+type LaundryDeliveryReschedule struct {
+	ID                  string    `gorm:"type:uuid;primaryKey" json:"id"`
+	OrderID             string    `gorm:"type:uuid;not null;index" json:"orderId"`
+	DeliveryID          string    `gorm:"type:uuid;not null" json:"deliveryId"`
+	RequestedBy         string    `gorm:"type:varchar(20);not null" json:"requestedBy"`
+	RequestedByID       string    `gorm:"type:uuid;not null" json:"requestedById"`
+	PreviousScheduledAt time.Time `gorm:"not null" json:"previousScheduledAt"`
+	NewScheduledAt      time.Time `gorm:"not null" json:"newScheduledAt"`
+	Reason              string    `gorm:"type:text" json:"reason,omitempty"`
+	FailedAttempt       bool      `gorm:"not null;default:false" json:"failedAttempt"`
+	Fee                 float64   `gorm:"type:decimal(10,2);not null;default:0" json:"fee"`
+	CreatedAt           time.Time `json:"createdAt"`
+}
+
+func (r *LaundryDeliveryReschedule) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (LaundryDeliveryReschedule) TableName() string {
+	return "laundry_delivery_reschedules"
+}
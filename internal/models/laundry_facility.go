@@ -0,0 +1,100 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type LaundryStationType string
+
+const (
+	LaundryStationWash  LaundryStationType = "wash"
+	LaundryStationDry   LaundryStationType = "dry"
+	LaundryStationPress LaundryStationType = "press"
+	LaundryStationPack  LaundryStationType = "pack"
+)
+
+// LaundryStationForItemStatus maps the processing statuses of a garment to
+// the kind of workstation that handles them. Other statuses are not spent at
+// a station.
+var LaundryStationForItemStatus = map[string]LaundryStationType{
+	"washing":  LaundryStationWash,
+	"drying":   LaundryStationDry,
+	"pressing": LaundryStationPress,
+	"packed":   LaundryStationPack,
+}
+
+// LaundryFacility is a provider's processing site. Orders assigned to one are
+// routed through its workstations.
+type LaundryFacility struct {
+	ID         string    `gorm:"type:uuid;primaryKey" json:"id"`
+	ProviderID string    `gorm:"type:uuid;not null;index" json:"providerId"`
+	Name       string    `gorm:"type:varchar(255);not null" json:"name"`
+	Address    string    `gorm:"type:text" json:"address"`
+	Latitude   float64   `gorm:"type:decimal(10,8)" json:"lat"`
+	Longitude  float64   `gorm:"type:decimal(11,8)" json:"lng"`
+	IsActive   bool      `gorm:"default:true" json:"isActive"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+
+	Workstations []*LaundryWorkstation `gorm:"foreignKey:FacilityID" json:"workstations,omitempty"`
+}
+
+func (LaundryFacility) TableName() string {
+	return "laundry_facilities"
+}
+
+func (f *LaundryFacility) BeforeCreate(tx *gorm.DB) error {
+	if f.ID == "" {
+		f.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// LaundryWorkstation is a washer, dryer, pressing table or packing bench.
+// Capacity is how many items it can hold at once.
+type LaundryWorkstation struct {
+	ID         string             `gorm:"type:uuid;primaryKey" json:"id"`
+	FacilityID string             `gorm:"type:uuid;not null;index" json:"facilityId"`
+	Name       string             `gorm:"type:varchar(100);not null" json:"name"`
+	Type       LaundryStationType `gorm:"type:varchar(20);not null" json:"type"`
+	Capacity   int                `gorm:"not null" json:"capacity"`
+	IsActive   bool               `gorm:"default:true" json:"isActive"`
+	CreatedAt  time.Time          `json:"createdAt"`
+	UpdatedAt  time.Time          `json:"updatedAt"`
+}
+
+func (LaundryWorkstation) TableName() string {
+	return "laundry_workstations"
+}
+
+func (w *LaundryWorkstation) BeforeCreate(tx *gorm.DB) error {
+	if w.ID == "" {
+		w.ID = uuid.New().String()
+	}
+	return nil
+}
+
+// LaundryStationVisit is the time an item spent at a workstation. Visits
+// without an exit time are the station's current load.
+type LaundryStationVisit struct {
+	ID            string     `gorm:"type:uuid;primaryKey" json:"id"`
+	WorkstationID string     `gorm:"type:uuid;not null;index" json:"workstationId"`
+	ItemID        string     `gorm:"type:uuid;not null;index" json:"itemId"`
+	OrderID       string     `gorm:"type:uuid;not null;index" json:"orderId"`
+	EnteredAt     time.Time  `gorm:"not null" json:"enteredAt"`
+	ExitedAt      *time.Time `json:"exitedAt,omitempty"`
+}
+
+func (LaundryStationVisit) TableName() string {
+	return "laundry_station_visits"
+}
+
+func (v *LaundryStationVisit) BeforeCreate(tx *gorm.DB) error {
+	if v.ID == "" {
+		v.ID = uuid.New().String()
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LaundryItemScan records a garment's QR label being scanned at a station,
+// moving the item from one processing status to the next.
+type LaundryItemScan struct {
+	ID         string    `gorm:"type:uuid;primaryKey" json:"id"`
+	ItemID     string    `gorm:"type:uuid;not null;index" json:"itemId"`
+	OrderID    string    `gorm:"type:uuid;not null;index" json:"orderId"`
+	QRCode     string    `gorm:"type:varchar(255);not null" json:"qrCode"`
+	FromStatus string    `gorm:"type:varchar(50);not null" json:"fromStatus"`
+	ToStatus   string    `gorm:"type:varchar(50);not null" json:"toStatus"`
+	ScannedBy  string    `gorm:"type:uuid;not null" json:"scannedBy"`
+	Station    *string   `gorm:"type:varchar(100)" json:"station,omitempty"`
+	Note       *string   `gorm:"type:text" json:"note,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+func (LaundryItemScan) TableName() string {
+	return "laundry_item_scans"
+}
+
+func (s *LaundryItemScan) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	return nil
+}
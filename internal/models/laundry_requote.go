@@ -0,0 +1,80 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type LaundryRequoteStatus string
+
+const (
+	// LaundryRequoteAwaitingApproval is an increase large enough that the
+	// customer has to agree to it before it is charged.
+	LaundryRequoteAwaitingApproval LaundryRequoteStatus = "awaiting_approval"
+	LaundryRequoteApplied          LaundryRequoteStatus = "applied"
+	LaundryRequoteRejected         LaundryRequoteStatus = "rejected"
+)
+
+// LaundryRequoteLine is one re-weighed item: the price it was booked at and
+// the price for the weight measured at the facility.
+type LaundryRequoteLine struct {
+	ItemID         string   `json:"itemId"`
+	ProductSlug    string   `json:"productSlug"`
+	Quantity       int      `json:"quantity"`
+	BookedWeight   *float64 `json:"bookedWeight,omitempty"`
+	MeasuredWeight float64  `json:"measuredWeight"`
+	PreviousPrice  float64  `json:"previousPrice"`
+	NewPrice       float64  `json:"newPrice"`
+}
+
+type LaundryRequoteLines []LaundryRequoteLine
+
+func (l LaundryRequoteLines) Value() (driver.Value, error) {
+	return json.Marshal(l)
+}
+
+func (l *LaundryRequoteLines) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, l)
+}
+
+// LaundryRequote reprices a laundry order from the weights measured at the
+// facility. Decreases and small increases are applied straight away; larger
+// increases wait for the customer.
+type LaundryRequote struct {
+	ID            string               `gorm:"type:uuid;primaryKey" json:"id"`
+	OrderID       string               `gorm:"type:uuid;not null;index" json:"orderId"`
+	ProviderID    string               `gorm:"type:uuid;not null" json:"providerId"`
+	Lines         LaundryRequoteLines  `gorm:"type:jsonb;not null" json:"lines"`
+	PreviousTotal float64              `gorm:"type:decimal(10,2);not null" json:"previousTotal"`
+	NewTotal      float64              `gorm:"type:decimal(10,2);not null" json:"newTotal"`
+	Difference    float64              `gorm:"type:decimal(10,2);not null" json:"difference"`
+	Status        LaundryRequoteStatus `gorm:"type:varchar(30);not null;index" json:"status"`
+	Note          string               `gorm:"type:text" json:"note,omitempty"`
+	CustomerNote  string               `gorm:"type:text" json:"customerNote,omitempty"`
+	RespondedAt   *time.Time           `json:"respondedAt,omitempty"`
+	CreatedAt     time.Time            `json:"createdAt"`
+	UpdatedAt     time.Time            `json:"updatedAt"`
+}
+
+func (q *LaundryRequote) BeforeCreate(tx *gorm.DB) error {
+	if q.ID == "" {
+		q.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (LaundryRequote) TableName() string {
+	return "laundry_requotes"
+}
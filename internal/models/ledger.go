@@ -0,0 +1,93 @@
+package models
+
+import "time"
+
+// LedgerAccountKind says what an account stands for. Wallet accounts hold
+// what the platform owes a user; the others are the platform's side of each
+// movement, one account per kind and currency.
+type LedgerAccountKind string
+
+const (
+	LedgerAccountWalletAvailable LedgerAccountKind = "wallet_available"
+	LedgerAccountWalletHeld      LedgerAccountKind = "wallet_held"
+	// LedgerAccountCash is money entering or leaving the platform: top-ups,
+	// withdrawals and cash collected by drivers.
+	LedgerAccountCash LedgerAccountKind = "cash"
+	// LedgerAccountClearing carries ride and order payments between the
+	// customers who pay them and the drivers and providers who earn them.
+	LedgerAccountClearing LedgerAccountKind = "clearing"
+	// LedgerAccountRevenue collects commission, penalties and subscriptions.
+	LedgerAccountRevenue LedgerAccountKind = "revenue"
+	// LedgerAccountExchange balances transfers between wallets in different
+	// currencies.
+	LedgerAccountExchange LedgerAccountKind = "exchange"
+	// LedgerAccountOpening balances the wallet balances carried over when
+	// the ledger was introduced.
+	LedgerAccountOpening LedgerAccountKind = "opening"
+)
+
+type NormalBalance string
+
+const (
+	NormalBalanceDebit  NormalBalance = "debit"
+	NormalBalanceCredit NormalBalance = "credit"
+)
+
+// LedgerAccount is one account of the double-entry ledger. Balance is kept in
+// the account's normal direction and is always derivable from its postings.
+type LedgerAccount struct {
+	ID            string            `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	Code          string            `gorm:"type:varchar(100);not null;uniqueIndex" json:"code"`
+	Kind          LedgerAccountKind `gorm:"type:varchar(30);not null" json:"kind"`
+	NormalBalance NormalBalance     `gorm:"type:varchar(10);not null" json:"normalBalance"`
+	WalletID      *string           `gorm:"type:uuid;index" json:"walletId,omitempty"`
+	Currency      string            `gorm:"type:varchar(3);not null" json:"currency"`
+	Balance       float64           `gorm:"type:decimal(14,2);not null;default:0" json:"balance"`
+	CreatedAt     time.Time         `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt     time.Time         `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (LedgerAccount) TableName() string {
+	return "ledger_accounts"
+}
+
+// BalanceChange is how much a posting of amount (debits positive) moves the
+// account's balance.
+func (a *LedgerAccount) BalanceChange(amount float64) float64 {
+	if a.NormalBalance == NormalBalanceCredit {
+		return -amount
+	}
+	return amount
+}
+
+// JournalEntry is one money movement. Its postings sum to zero in every
+// currency.
+type JournalEntry struct {
+	ID            string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	Kind          string    `gorm:"type:varchar(50);not null;index" json:"kind"`
+	ReferenceType string    `gorm:"type:varchar(50)" json:"referenceType,omitempty"`
+	ReferenceID   string    `gorm:"type:varchar(100)" json:"referenceId,omitempty"`
+	Description   string    `gorm:"type:text" json:"description,omitempty"`
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"createdAt"`
+
+	Postings []LedgerPosting `gorm:"foreignKey:EntryID" json:"postings,omitempty"`
+}
+
+func (JournalEntry) TableName() string {
+	return "journal_entries"
+}
+
+// LedgerPosting moves Amount into (positive, a debit) or out of (negative, a
+// credit) an account.
+type LedgerPosting struct {
+	ID        string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	EntryID   string    `gorm:"type:uuid;not null;index" json:"entryId"`
+	AccountID string    `gorm:"type:uuid;not null;index" json:"accountId"`
+	Amount    float64   `gorm:"type:decimal(14,2);not null" json:"amount"`
+	Currency  string    `gorm:"type:varchar(3);not null" json:"currency"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (LedgerPosting) TableName() string {
+	return "ledger_postings"
+}
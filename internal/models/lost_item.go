@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type LostItemStatus string
+
+const (
+	LostItemStatusReported        LostItemStatus = "reported"
+	LostItemStatusFound           LostItemStatus = "found"
+	LostItemStatusNotFound        LostItemStatus = "not_found"
+	LostItemStatusReturnScheduled LostItemStatus = "return_scheduled"
+	LostItemStatusReturned        LostItemStatus = "returned"
+	LostItemStatusEscalated       LostItemStatus = "escalated"
+	LostItemStatusResolved        LostItemStatus = "resolved"
+	LostItemStatusClosed          LostItemStatus = "closed"
+)
+
+type LostItemCase struct {
+	ID               string         `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	RideID           string         `gorm:"type:uuid;not null;index" json:"rideId"`
+	RiderID          string         `gorm:"type:uuid;not null;index" json:"riderId"`
+	DriverID         string         `gorm:"type:uuid;not null;index" json:"driverId"`
+	ItemDescription  string         `gorm:"type:text;not null" json:"itemDescription"`
+	ItemCategory     string         `gorm:"type:varchar(50)" json:"itemCategory"`
+	ContactNote      string         `gorm:"type:text" json:"contactNote"`
+	Status           LostItemStatus `gorm:"type:varchar(30);not null;default:'reported';index" json:"status"`
+	DriverResponse   string         `gorm:"type:text" json:"driverResponse"`
+	ReturnFee        float64        `gorm:"type:decimal(10,2);default:0" json:"returnFee"`
+	ReturnFeeTxnID   *string        `gorm:"type:uuid" json:"returnFeeTxnId,omitempty"`
+	ReturnAddress    string         `gorm:"type:text" json:"returnAddress"`
+	ReturnAt         *time.Time     `json:"returnAt,omitempty"`
+	EscalatedBy      *string        `gorm:"type:uuid" json:"escalatedBy,omitempty"`
+	EscalationNote   string         `gorm:"type:text" json:"escalationNote"`
+	EscalatedAt      *time.Time     `json:"escalatedAt,omitempty"`
+	ResolvedBy       *string        `gorm:"type:uuid" json:"resolvedBy,omitempty"`
+	ResolutionNote   string         `gorm:"type:text" json:"resolutionNote"`
+	DriverNotifiedAt *time.Time     `json:"driverNotifiedAt,omitempty"`
+	RespondedAt      *time.Time     `json:"respondedAt,omitempty"`
+	ReturnedAt       *time.Time     `json:"returnedAt,omitempty"`
+	ClosedAt         *time.Time     `json:"closedAt,omitempty"`
+	CreatedAt        time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt        time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
+
+	Ride   *Ride `gorm:"foreignKey:RideID" json:"ride,omitempty"`
+	Rider  *User `gorm:"foreignKey:RiderID" json:"rider,omitempty"`
+	Driver *User `gorm:"foreignKey:DriverID" json:"driver,omitempty"`
+}
+
+func (LostItemCase) TableName() string {
+	return "lost_item_cases"
+}
+
+func (c *LostItemCase) IsOpen() bool {
+	switch c.Status {
+	case LostItemStatusReturned, LostItemStatusResolved, LostItemStatusClosed:
+		return false
+	}
+	return true
+}
+
+// LostItemMessage is relayed between rider and driver through the case so neither
+// side ever sees the other's phone number.
+type LostItemMessage struct {
+	ID         string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CaseID     string    `gorm:"type:uuid;not null;index" json:"caseId"`
+	SenderID   string    `gorm:"type:uuid;not null" json:"senderId"`
+	SenderRole string    `gorm:"type:varchar(20);not null" json:"senderRole"`
+	Content    string    `gorm:"type:text;not null" json:"content"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (LostItemMessage) TableName() string {
+	return "lost_item_messages"
+}
@@ -0,0 +1,66 @@
+package models
+
+import "time"
+
+const (
+	LoyaltyTierBronze = "bronze"
+	LoyaltyTierSilver = "silver"
+	LoyaltyTierGold   = "gold"
+)
+
+const (
+	LoyaltyTransactionEarn   = "earn"
+	LoyaltyTransactionRedeem = "redeem"
+	LoyaltyTransactionExpire = "expire"
+)
+
+const (
+	LoyaltySourceRide  = "ride"
+	LoyaltySourceOrder = "order"
+)
+
+// LoyaltyAccount is a customer's points balance and tier. TierPoints are the
+// points earned within the tier window when the tier was last worked out.
+type LoyaltyAccount struct {
+	UserID         string     `gorm:"type:uuid;primaryKey" json:"userId"`
+	Balance        int64      `gorm:"not null;default:0" json:"balance"`
+	LifetimePoints int64      `gorm:"not null;default:0" json:"lifetimePoints"`
+	Tier           string     `gorm:"type:varchar(20);not null;default:'bronze'" json:"tier"`
+	TierPoints     int64      `gorm:"not null;default:0" json:"tierPoints"`
+	TierUpdatedAt  *time.Time `json:"tierUpdatedAt,omitempty"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (LoyaltyAccount) TableName() string {
+	return "loyalty_accounts"
+}
+
+// LoyaltyTransaction is one movement of a customer's points. Earned points are
+// positive and carry the date they lapse; redeemed and expired points are
+// negative. Points are spent oldest first, so those that lapse are whatever
+// earned points are past their date and not yet used up.
+type LoyaltyTransaction struct {
+	ID           string     `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	UserID       string     `gorm:"type:uuid;not null;index" json:"userId"`
+	Type         string     `gorm:"type:varchar(20);not null" json:"type"`
+	Points       int64      `gorm:"not null" json:"points"`
+	SourceType   *string    `gorm:"type:varchar(20)" json:"sourceType,omitempty"`
+	SourceID     *string    `gorm:"type:uuid" json:"sourceId,omitempty"`
+	Spend        float64    `gorm:"type:decimal(10,2);not null;default:0" json:"spend"`
+	WalletAmount float64    `gorm:"type:decimal(10,2);not null;default:0" json:"walletAmount"`
+	ExpiresAt    *time.Time `json:"expiresAt,omitempty"`
+	Description  string     `gorm:"type:text" json:"description"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (LoyaltyTransaction) TableName() string {
+	return "loyalty_transactions"
+}
+
+// LoyaltyPerks are what a tier unlocks. BookingFeeDiscount is a percentage
+// off the ride booking fee.
+type LoyaltyPerks struct {
+	BookingFeeDiscount float64 `json:"bookingFeeDiscount"`
+	PriorityMatching   bool    `json:"priorityMatching"`
+}
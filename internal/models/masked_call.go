@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+const (
+	MaskedCallReferenceRide  = "ride"
+	MaskedCallReferenceOrder = "service_order"
+)
+
+type MaskedCallSessionStatus string
+
+const (
+	MaskedCallSessionActive MaskedCallSessionStatus = "active"
+	MaskedCallSessionClosed MaskedCallSessionStatus = "closed"
+	MaskedCallSessionFailed MaskedCallSessionStatus = "failed"
+)
+
+// MaskedCallSession pairs a customer with their driver or provider through
+// proxy numbers for one ride or order. Each side dials the proxy number it
+// was given and never learns the other's real number.
+type MaskedCallSession struct {
+	ID                string `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	ReferenceType     string `gorm:"type:varchar(20);not null;index:idx_masked_call_sessions_reference" json:"referenceType"`
+	ReferenceID       string `gorm:"type:uuid;not null;index:idx_masked_call_sessions_reference" json:"referenceId"`
+	Provider          string `gorm:"type:varchar(30);not null" json:"provider"`
+	ProviderSessionID string `gorm:"type:varchar(100);index" json:"providerSessionId"`
+	CustomerID        string `gorm:"type:uuid;not null;index" json:"customerId"`
+	PartnerID         string `gorm:"type:uuid;not null;index" json:"partnerId"`
+	// The provider's participant IDs identify which side placed a call.
+	CustomerParticipantID string                  `gorm:"type:varchar(100)" json:"-"`
+	PartnerParticipantID  string                  `gorm:"type:varchar(100)" json:"-"`
+	CustomerProxy         string                  `gorm:"type:varchar(20)" json:"customerProxy"`
+	PartnerProxy          string                  `gorm:"type:varchar(20)" json:"partnerProxy"`
+	Status                MaskedCallSessionStatus `gorm:"type:varchar(20);not null;index" json:"status"`
+	FailureReason         string                  `gorm:"type:text" json:"failureReason,omitempty"`
+	ExpiresAt             time.Time               `gorm:"not null" json:"expiresAt"`
+	ClosedAt              *time.Time              `json:"closedAt,omitempty"`
+	CreatedAt             time.Time               `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt             time.Time               `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (MaskedCallSession) TableName() string {
+	return "masked_call_sessions"
+}
+
+// MaskedCallEvent is a call or call status change reported by the provider,
+// kept so support can see who called whom when a ride or order is disputed.
+type MaskedCallEvent struct {
+	ID              string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	SessionID       string    `gorm:"type:uuid;not null;index" json:"sessionId"`
+	ProviderEventID string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_masked_call_events_provider_event" json:"providerEventId"`
+	CallerID        *string   `gorm:"type:uuid" json:"callerId,omitempty"`
+	CallerRole      string    `gorm:"type:varchar(20)" json:"callerRole,omitempty"`
+	Status          string    `gorm:"type:varchar(30);not null;uniqueIndex:idx_masked_call_events_provider_event" json:"status"`
+	DurationSec     int       `gorm:"not null;default:0" json:"durationSec"`
+	OccurredAt      time.Time `gorm:"not null" json:"occurredAt"`
+	CreatedAt       time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (MaskedCallEvent) TableName() string {
+	return "masked_call_events"
+}
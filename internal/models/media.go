@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type MediaStatus string
+
+const (
+	MediaStatusPending MediaStatus = "pending"
+	MediaStatusReady   MediaStatus = "ready"
+)
+
+// Entities media can be attached to.
+const (
+	MediaEntityService  = "service"
+	MediaEntityAddon    = "addon"
+	MediaEntityVehicle  = "vehicle"
+	MediaEntityDocument = "document"
+	MediaEntityDispute  = "dispute"
+)
+
+// Media is a file uploaded straight to object storage. It starts pending with
+// a presigned upload URL and becomes ready once the server has checked the
+// object and, for images, stored a thumbnail next to it.
+type Media struct {
+	ID           string         `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	UploaderID   string         `gorm:"type:uuid;not null;index" json:"uploaderId"`
+	EntityType   string         `gorm:"type:varchar(30);not null" json:"entityType"`
+	EntityID     *string        `gorm:"type:uuid" json:"entityId,omitempty"`
+	Status       MediaStatus    `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	FileName     string         `gorm:"type:varchar(255);not null" json:"fileName"`
+	ContentType  string         `gorm:"type:varchar(100);not null" json:"contentType"`
+	Size         int64          `gorm:"not null;default:0" json:"size"`
+	StorageKey   string         `gorm:"type:varchar(500);not null;uniqueIndex" json:"-"`
+	ThumbnailKey *string        `gorm:"type:varchar(500)" json:"-"`
+	URL          string         `gorm:"type:varchar(1000);not null" json:"url"`
+	ThumbnailURL *string        `gorm:"type:varchar(1000)" json:"thumbnailUrl,omitempty"`
+	Width        *int           `json:"width,omitempty"`
+	Height       *int           `json:"height,omitempty"`
+	CompletedAt  *time.Time     `json:"completedAt,omitempty"`
+	AttachedAt   *time.Time     `json:"attachedAt,omitempty"`
+	CreatedAt    time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt    time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+func (Media) TableName() string {
+	return "media"
+}
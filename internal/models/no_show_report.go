@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Parties a no-show can be reported against.
+const (
+	NoShowPartyRider    = "rider"
+	NoShowPartyProvider = "provider"
+)
+
+// NoShowReport records a ride or home services order closed because the
+// other side never turned up: a rider who did not come out to a driver
+// waiting at the pickup, or a provider who did not arrive for the booking.
+// Product is one of the cancellation products and ReferenceID the ride or
+// order. The evidence (note, photos and where the reporter was) is kept with
+// the fee or penalty that was applied.
+type NoShowReport struct {
+	ID          string         `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	Product     string         `gorm:"type:varchar(20);not null" json:"product"`
+	ReferenceID string         `gorm:"type:uuid;not null" json:"referenceId"`
+	ReportedBy  string         `gorm:"type:uuid;not null" json:"reportedBy"`
+	AbsentParty string         `gorm:"type:varchar(20);not null" json:"absentParty"`
+	Note        string         `gorm:"type:text" json:"note,omitempty"`
+	PhotoURLs   pq.StringArray `gorm:"type:text[];not null;default:'{}'" json:"photoUrls"`
+	Latitude    *float64       `gorm:"type:decimal(10,8)" json:"latitude,omitempty"`
+	Longitude   *float64       `gorm:"type:decimal(11,8)" json:"longitude,omitempty"`
+	// DistanceMeters is how far the reporter was from the pickup or service
+	// address when reporting.
+	DistanceMeters  *float64  `gorm:"type:decimal(10,2)" json:"distanceMeters,omitempty"`
+	WaitedMinutes   int       `gorm:"not null;default:0" json:"waitedMinutes"`
+	CustomerFee     float64   `gorm:"type:decimal(10,2);not null;default:0" json:"customerFee"`
+	ProviderPenalty float64   `gorm:"type:decimal(10,2);not null;default:0" json:"providerPenalty"`
+	RefundAmount    float64   `gorm:"type:decimal(10,2);not null;default:0" json:"refundAmount"`
+	PolicyName      string    `gorm:"type:varchar(255)" json:"policyName,omitempty"`
+	CreatedAt       time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (NoShowReport) TableName() string {
+	return "no_show_reports"
+}
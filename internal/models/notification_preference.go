@@ -0,0 +1,155 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationCategory groups notifications so users can choose which kinds
+// they receive on which channels.
+type NotificationCategory string
+
+const (
+	// Safety notifications (SOS, fraud and security alerts) ignore both the
+	// channel toggles and quiet hours.
+	NotificationCategorySafety        NotificationCategory = "safety"
+	NotificationCategoryTransactional NotificationCategory = "transactional"
+	NotificationCategoryAccount       NotificationCategory = "account"
+	NotificationCategoryInformational NotificationCategory = "informational"
+	NotificationCategoryMarketing     NotificationCategory = "marketing"
+)
+
+// NotificationCategories lists every category in the order they are shown
+// to the user.
+var NotificationCategories = []NotificationCategory{
+	NotificationCategorySafety,
+	NotificationCategoryTransactional,
+	NotificationCategoryAccount,
+	NotificationCategoryInformational,
+	NotificationCategoryMarketing,
+}
+
+func (c NotificationCategory) IsValid() bool {
+	for _, category := range NotificationCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// IsCritical reports whether notifications in the category are always
+// delivered, whatever the user's preferences.
+func (c NotificationCategory) IsCritical() bool {
+	return c == NotificationCategorySafety
+}
+
+// IsDeferrable reports whether notifications in the category wait for quiet
+// hours to end. Ride and payment updates are time sensitive and go out
+// straight away.
+func (c NotificationCategory) IsDeferrable() bool {
+	return c == NotificationCategoryInformational || c == NotificationCategoryMarketing
+}
+
+// NotificationPreference holds a user's channel toggles for one category.
+// A category without a row has every channel on.
+type NotificationPreference struct {
+	UserID    uuid.UUID            `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Category  NotificationCategory `gorm:"type:varchar(20);primaryKey" json:"category"`
+	Push      bool                 `gorm:"not null;default:true" json:"push"`
+	SMS       bool                 `gorm:"column:sms;not null;default:true" json:"sms"`
+	Email     bool                 `gorm:"not null;default:true" json:"email"`
+	UpdatedAt time.Time            `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (NotificationPreference) TableName() string {
+	return "notification_preferences"
+}
+
+// Allows reports whether the user wants notifications on channel. In-app
+// notifications cannot be turned off.
+func (p *NotificationPreference) Allows(channel NotificationChannel) bool {
+	switch channel {
+	case ChannelPush:
+		return p.Push
+	case ChannelSMS:
+		return p.SMS
+	case ChannelEmail:
+		return p.Email
+	default:
+		return true
+	}
+}
+
+// NotificationQuietHours is the daily window in which a user's deferrable
+// notifications are held back. Start and End are "HH:MM" in Timezone; a
+// window whose end is before its start runs past midnight.
+type NotificationQuietHours struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Enabled   bool      `gorm:"not null;default:false" json:"enabled"`
+	StartTime string    `gorm:"type:varchar(5);not null;default:'22:00'" json:"start_time"`
+	EndTime   string    `gorm:"type:varchar(5);not null;default:'07:00'" json:"end_time"`
+	Timezone  string    `gorm:"type:varchar(64);not null;default:'UTC'" json:"timezone"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (NotificationQuietHours) TableName() string {
+	return "notification_quiet_hours"
+}
+
+// EndsAt returns when the quiet hours that now falls in end, and false when
+// now is outside them.
+func (q *NotificationQuietHours) EndsAt(now time.Time) (time.Time, bool) {
+	if !q.Enabled {
+		return time.Time{}, false
+	}
+	loc, err := time.LoadLocation(q.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	start, errStart := time.Parse("15:04", q.StartTime)
+	end, errEnd := time.Parse("15:04", q.EndTime)
+	if errStart != nil || errEnd != nil || q.StartTime == q.EndTime {
+		return time.Time{}, false
+	}
+
+	local := now.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	startMinute := start.Hour()*60 + start.Minute()
+	endMinute := end.Hour()*60 + end.Minute()
+
+	var inside bool
+	if startMinute < endMinute {
+		inside = minute >= startMinute && minute < endMinute
+	} else {
+		inside = minute >= startMinute || minute < endMinute
+	}
+	if !inside {
+		return time.Time{}, false
+	}
+
+	endsAt := time.Date(local.Year(), local.Month(), local.Day(), end.Hour(), end.Minute(), 0, 0, loc)
+	if !endsAt.After(local) {
+		endsAt = endsAt.AddDate(0, 0, 1)
+	}
+	return endsAt, true
+}
+
+// DeferredNotification is a push held back by quiet hours and sent once
+// DeliverAfter has passed.
+type DeferredNotification struct {
+	ID           uuid.UUID            `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID       uuid.UUID            `gorm:"type:uuid;not null;index" json:"user_id"`
+	Category     NotificationCategory `gorm:"type:varchar(20);not null" json:"category"`
+	Title        string               `gorm:"type:varchar(255);not null" json:"title"`
+	Body         string               `gorm:"type:text;not null" json:"body"`
+	Data         []byte               `gorm:"type:jsonb" json:"data,omitempty"`
+	DeliverAfter time.Time            `gorm:"not null" json:"deliver_after"`
+	DeliveredAt  *time.Time           `json:"delivered_at,omitempty"`
+	CreatedAt    time.Time            `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (DeferredNotification) TableName() string {
+	return "deferred_notifications"
+}
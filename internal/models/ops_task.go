@@ -0,0 +1,124 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type OpsTaskStatus string
+
+const (
+	OpsTaskStatusOpen       OpsTaskStatus = "open"
+	OpsTaskStatusInProgress OpsTaskStatus = "in_progress"
+	OpsTaskStatusBlocked    OpsTaskStatus = "blocked"
+	OpsTaskStatusDone       OpsTaskStatus = "done"
+	OpsTaskStatusCancelled  OpsTaskStatus = "cancelled"
+)
+
+type OpsTaskPriority string
+
+const (
+	OpsTaskPriorityLow    OpsTaskPriority = "low"
+	OpsTaskPriorityNormal OpsTaskPriority = "normal"
+	OpsTaskPriorityHigh   OpsTaskPriority = "high"
+	OpsTaskPriorityUrgent OpsTaskPriority = "urgent"
+)
+
+// What an ops task is for.
+const (
+	OpsTaskKindGeneral         = "general"
+	OpsTaskKindDisputeFollowUp = "dispute_follow_up"
+	OpsTaskKindOnboarding      = "onboarding"
+	OpsTaskKindInvestigation   = "investigation"
+)
+
+// What an ops task can be about.
+const (
+	OpsTaskEntityRide         = "ride"
+	OpsTaskEntityServiceOrder = "service_order"
+	OpsTaskEntityProvider     = "provider"
+	OpsTaskEntityDriver       = "driver"
+	OpsTaskEntityUser         = "user"
+	OpsTaskEntityDispute      = "dispute"
+)
+
+// OpsTask is a piece of work for the operations team, such as following up
+// a dispute or walking a provider through onboarding. It may point at the
+// ride, order, account or dispute it is about, carries an optional checklist,
+// and its watchers are told whenever it changes.
+type OpsTask struct {
+	ID          string          `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	Title       string          `gorm:"type:varchar(200);not null" json:"title"`
+	Description string          `gorm:"type:text" json:"description"`
+	Kind        string          `gorm:"type:varchar(30);not null;default:'general'" json:"kind"`
+	EntityType  *string         `gorm:"type:varchar(30)" json:"entityType,omitempty"`
+	EntityID    *string         `gorm:"type:uuid" json:"entityId,omitempty"`
+	Status      OpsTaskStatus   `gorm:"type:varchar(20);not null;default:'open'" json:"status"`
+	Priority    OpsTaskPriority `gorm:"type:varchar(10);not null;default:'normal'" json:"priority"`
+	AssigneeID  *string         `gorm:"type:uuid;index" json:"assigneeId,omitempty"`
+	CreatedBy   string          `gorm:"type:uuid;not null" json:"createdBy"`
+	DueAt       *time.Time      `json:"dueAt,omitempty"`
+	CompletedBy *string         `gorm:"type:uuid" json:"completedBy,omitempty"`
+	CompletedAt *time.Time      `json:"completedAt,omitempty"`
+	CreatedAt   time.Time       `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt   time.Time       `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt   gorm.DeletedAt  `gorm:"index" json:"-"`
+
+	Checklist []OpsTaskChecklistItem `gorm:"foreignKey:TaskID" json:"checklist,omitempty"`
+	Watchers  []OpsTaskWatcher       `gorm:"foreignKey:TaskID" json:"watchers,omitempty"`
+}
+
+func (OpsTask) TableName() string {
+	return "ops_tasks"
+}
+
+// IsOpen reports whether work on the task is still outstanding.
+func (t *OpsTask) IsOpen() bool {
+	return t.Status != OpsTaskStatusDone && t.Status != OpsTaskStatusCancelled
+}
+
+// IsOverdue reports whether an open task is past its due date.
+func (t *OpsTask) IsOverdue(now time.Time) bool {
+	return t.IsOpen() && t.DueAt != nil && t.DueAt.Before(now)
+}
+
+type OpsTaskChecklistItem struct {
+	ID        string     `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	TaskID    string     `gorm:"type:uuid;not null;index" json:"taskId"`
+	Position  int        `gorm:"not null;default:0" json:"position"`
+	Title     string     `gorm:"type:varchar(200);not null" json:"title"`
+	Done      bool       `gorm:"not null;default:false" json:"done"`
+	DoneBy    *string    `gorm:"type:uuid" json:"doneBy,omitempty"`
+	DoneAt    *time.Time `json:"doneAt,omitempty"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (OpsTaskChecklistItem) TableName() string {
+	return "ops_task_checklist_items"
+}
+
+type OpsTaskComment struct {
+	ID        string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	TaskID    string    `gorm:"type:uuid;not null;index" json:"taskId"`
+	AuthorID  string    `gorm:"type:uuid;not null" json:"authorId"`
+	Body      string    `gorm:"type:text;not null" json:"body"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
+
+	Author *User `gorm:"foreignKey:AuthorID" json:"author,omitempty"`
+}
+
+func (OpsTaskComment) TableName() string {
+	return "ops_task_comments"
+}
+
+type OpsTaskWatcher struct {
+	TaskID    string    `gorm:"type:uuid;primaryKey" json:"taskId"`
+	UserID    string    `gorm:"type:uuid;primaryKey" json:"userId"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (OpsTaskWatcher) TableName() string {
+	return "ops_task_watchers"
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+type OrderAdjustmentStatus string
+
+const (
+	// AdjustmentStatusPending waits for the customer to approve.
+	AdjustmentStatusPending  OrderAdjustmentStatus = "pending"
+	AdjustmentStatusApproved OrderAdjustmentStatus = "approved"
+	AdjustmentStatusRejected OrderAdjustmentStatus = "rejected"
+	// AdjustmentStatusWithdrawn is a proposal overtaken by the order being
+	// completed or cancelled before the customer answered.
+	AdjustmentStatusWithdrawn OrderAdjustmentStatus = "withdrawn"
+)
+
+const (
+	AdjustmentActionAdd    = "add"
+	AdjustmentActionRemove = "remove"
+
+	AdjustmentItemService = "service"
+	AdjustmentItemAddon   = "addon"
+)
+
+// OrderAdjustmentLine adds or removes a quantity of one service or addon.
+// Added items are priced by the provider; removed items are credited at the
+// price they were booked at.
+type OrderAdjustmentLine struct {
+	Action   string  `json:"action"`
+	ItemType string  `json:"itemType"`
+	Slug     string  `json:"slug"`
+	Title    string  `json:"title"`
+	Price    float64 `json:"price"`
+	Quantity int     `json:"quantity"`
+}
+
+// Amount is what the line adds to the order; negative for removals.
+func (l OrderAdjustmentLine) Amount() float64 {
+	amount := l.Price * float64(l.Quantity)
+	if l.Action == AdjustmentActionRemove {
+		return -amount
+	}
+	return amount
+}
+
+type OrderAdjustmentLines []OrderAdjustmentLine
+
+func (l OrderAdjustmentLines) Value() (driver.Value, error) {
+	return json.Marshal(l)
+}
+
+func (l *OrderAdjustmentLines) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, l)
+}
+
+// OrderAdjustment changes the scope of a home-service order on site. The
+// provider proposes it and the order is only repriced once the customer
+// approves.
+type OrderAdjustment struct {
+	ID            string                `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	OrderID       string                `gorm:"type:uuid;not null;index" json:"orderId"`
+	ProviderID    string                `gorm:"type:uuid;not null" json:"providerId"`
+	Lines         OrderAdjustmentLines  `gorm:"type:jsonb;not null" json:"lines"`
+	PreviousTotal float64               `gorm:"type:decimal(10,2);not null" json:"previousTotal"`
+	NewTotal      float64               `gorm:"type:decimal(10,2);not null" json:"newTotal"`
+	Difference    float64               `gorm:"type:decimal(10,2);not null" json:"difference"`
+	Status        OrderAdjustmentStatus `gorm:"type:varchar(20);not null;index" json:"status"`
+	Note          string                `gorm:"type:text" json:"note,omitempty"`
+	CustomerNote  string                `gorm:"type:text" json:"customerNote,omitempty"`
+	RespondedAt   *time.Time            `json:"respondedAt,omitempty"`
+	CreatedAt     time.Time             `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt     time.Time             `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (OrderAdjustment) TableName() string {
+	return "order_adjustments"
+}
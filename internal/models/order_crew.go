@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+type OrderCrewStatus string
+
+const (
+	// CrewStatusInvited waits for the invited provider to answer.
+	CrewStatusInvited  OrderCrewStatus = "invited"
+	CrewStatusAccepted OrderCrewStatus = "accepted"
+	CrewStatusDeclined OrderCrewStatus = "declined"
+	// CrewStatusRemoved is a member the lead provider took off the crew.
+	CrewStatusRemoved OrderCrewStatus = "removed"
+	// CrewStatusExpired is an invitation still open when the job started or
+	// the order was given up.
+	CrewStatusExpired OrderCrewStatus = "expired"
+)
+
+// OrderCrewMember is a provider working on a multi-pro order alongside the
+// lead provider the order is assigned to. Members are paid PayoutPercent of
+// the provider payout; the lead keeps the rest.
+type OrderCrewMember struct {
+	ID            string          `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	OrderID       string          `gorm:"type:uuid;not null;index" json:"orderId"`
+	ProviderID    string          `gorm:"type:uuid;not null;index" json:"providerId"`
+	InvitedBy     string          `gorm:"type:uuid;not null" json:"invitedBy"`
+	PayoutPercent float64         `gorm:"type:decimal(5,2);not null" json:"payoutPercent"`
+	Status        OrderCrewStatus `gorm:"type:varchar(20);not null" json:"status"`
+	RespondedAt   *time.Time      `json:"respondedAt,omitempty"`
+	CheckedInAt   *time.Time      `json:"checkedInAt,omitempty"`
+	PayoutAmount  *float64        `gorm:"type:decimal(10,2)" json:"payoutAmount,omitempty"`
+	CreatedAt     time.Time       `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt     time.Time       `gorm:"autoUpdateTime" json:"updatedAt"`
+
+	Provider *ServiceProviderProfile `gorm:"foreignKey:ProviderID" json:"provider,omitempty"`
+	Order    *ServiceOrderNew        `gorm:"foreignKey:OrderID" json:"order,omitempty"`
+}
+
+// IsOpen reports whether the member still holds a seat on the crew.
+func (m *OrderCrewMember) IsOpen() bool {
+	return m.Status == CrewStatusInvited || m.Status == CrewStatusAccepted
+}
+
+func (OrderCrewMember) TableName() string {
+	return "order_crew_members"
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+const (
+	OrderQuoteStatusIssued    = "issued"
+	OrderQuoteStatusConverted = "converted"
+)
+
+// OrderQuote is a priced snapshot of a home service order handed to the
+// customer before they book. Prices and terms are frozen at issue time so the
+// document always shows what the customer was quoted.
+type OrderQuote struct {
+	ID          string `gorm:"type:uuid;primaryKey" json:"id"`
+	QuoteNumber string `gorm:"type:varchar(50);uniqueIndex;not null" json:"quoteNumber"`
+	CustomerID  string `gorm:"type:uuid;not null;index" json:"customerId"`
+
+	CategorySlug     string           `gorm:"type:varchar(255);not null" json:"categorySlug"`
+	SelectedServices SelectedServices `gorm:"type:jsonb;not null" json:"selectedServices"`
+	SelectedAddons   SelectedAddons   `gorm:"type:jsonb" json:"selectedAddons"`
+	Surcharges       OrderSurcharges  `gorm:"type:jsonb" json:"surcharges"`
+	Taxes            TaxLines         `gorm:"type:jsonb" json:"taxes"`
+
+	ServicesTotal   float64 `gorm:"type:decimal(10,2);not null" json:"servicesTotal"`
+	AddonsTotal     float64 `gorm:"type:decimal(10,2);default:0" json:"addonsTotal"`
+	Subtotal        float64 `gorm:"type:decimal(10,2);not null" json:"subtotal"`
+	SurchargesTotal float64 `gorm:"type:decimal(10,2);default:0" json:"surchargesTotal"`
+	TaxTotal        float64 `gorm:"type:decimal(10,2);default:0" json:"taxTotal"`
+	TotalPrice      float64 `gorm:"type:decimal(10,2);not null" json:"totalPrice"`
+	TaxIncluded     bool    `gorm:"default:false" json:"taxIncluded"`
+
+	Terms pq.StringArray `gorm:"type:text[]" json:"terms"`
+
+	Status           string     `gorm:"type:varchar(20);not null;default:'issued';index" json:"status"`
+	ValidUntil       time.Time  `gorm:"not null" json:"validUntil"`
+	ConvertedOrderID *string    `gorm:"type:uuid" json:"convertedOrderId,omitempty"`
+	ConvertedAt      *time.Time `json:"convertedAt,omitempty"`
+
+	ShareCount     int        `gorm:"default:0" json:"shareCount"`
+	DownloadCount  int        `gorm:"default:0" json:"downloadCount"`
+	LastAccessedAt *time.Time `json:"lastAccessedAt,omitempty"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (OrderQuote) TableName() string {
+	return "order_quotes"
+}
+
+func (q *OrderQuote) BeforeCreate(tx *gorm.DB) error {
+	if q.ID == "" {
+		q.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (q *OrderQuote) IsExpired(at time.Time) bool {
+	return q.Status == OrderQuoteStatusIssued && at.After(q.ValidUntil)
+}
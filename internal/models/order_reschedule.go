@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+type OrderRescheduleStatus string
+
+const (
+	// RescheduleStatusPending waits for the other party to accept.
+	RescheduleStatusPending  OrderRescheduleStatus = "pending"
+	RescheduleStatusApplied  OrderRescheduleStatus = "applied"
+	RescheduleStatusDeclined OrderRescheduleStatus = "declined"
+	// RescheduleStatusWithdrawn is a request overtaken by the order being
+	// cancelled or rescheduled another way.
+	RescheduleStatusWithdrawn OrderRescheduleStatus = "withdrawn"
+)
+
+// OrderReschedule records a request to move a home-service booking. Moves
+// the customer makes before a provider is assigned apply at once; once a
+// provider is assigned the other side has to accept them.
+type OrderReschedule struct {
+	ID            string                `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	OrderID       string                `gorm:"type:uuid;not null;index" json:"orderId"`
+	RequestedBy   string                `gorm:"type:varchar(20);not null" json:"requestedBy"`
+	RequestedByID string                `gorm:"type:uuid;not null" json:"requestedById"`
+	PreviousDate  string                `gorm:"type:varchar(10);not null" json:"previousDate"`
+	PreviousTime  string                `gorm:"type:varchar(5);not null" json:"previousTime"`
+	NewDate       string                `gorm:"type:varchar(10);not null" json:"newDate"`
+	NewTime       string                `gorm:"type:varchar(5);not null" json:"newTime"`
+	Reason        string                `gorm:"type:text" json:"reason,omitempty"`
+	Status        OrderRescheduleStatus `gorm:"type:varchar(20);not null;index" json:"status"`
+	ResponseNote  string                `gorm:"type:text" json:"responseNote,omitempty"`
+	RespondedAt   *time.Time            `json:"respondedAt,omitempty"`
+	CreatedAt     time.Time             `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt     time.Time             `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (OrderReschedule) TableName() string {
+	return "order_reschedules"
+}
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type PartnerStatus string
+
+const (
+	PartnerStatusActive   PartnerStatus = "active"
+	PartnerStatusDisabled PartnerStatus = "disabled"
+)
+
+// Scopes an API key can be granted.
+const (
+	PartnerScopeRidesRead   = "rides:read"
+	PartnerScopeRidesWrite  = "rides:write"
+	PartnerScopeOrdersRead  = "orders:read"
+	PartnerScopeOrdersWrite = "orders:write"
+)
+
+var PartnerScopes = []string{PartnerScopeRidesRead, PartnerScopeRidesWrite, PartnerScopeOrdersRead, PartnerScopeOrdersWrite}
+
+// Partner is a business integrating server to server, such as a corporate
+// booking portal, that books rides and orders for users linked to it.
+type Partner struct {
+	ID           string        `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	Name         string        `gorm:"type:varchar(255);not null" json:"name"`
+	ContactEmail string        `gorm:"type:varchar(255)" json:"contactEmail,omitempty"`
+	Status       PartnerStatus `gorm:"type:varchar(20);not null;default:'active'" json:"status"`
+	CreatedBy    string        `gorm:"type:uuid;not null" json:"createdBy"`
+	CreatedAt    time.Time     `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt    time.Time     `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (Partner) TableName() string {
+	return "partners"
+}
+
+// PartnerAPIKey is a credential of the form "<KeyID>.<secret>". Only a hash of
+// the secret is kept, except for keys that must sign requests, whose secret
+// is also stored encrypted so signatures can be checked.
+type PartnerAPIKey struct {
+	ID                 string         `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	PartnerID          string         `gorm:"type:uuid;not null;index" json:"partnerId"`
+	Name               string         `gorm:"type:varchar(100);not null" json:"name"`
+	KeyID              string         `gorm:"type:varchar(40);not null;uniqueIndex" json:"keyId"`
+	SecretHash         string         `gorm:"type:varchar(64);not null" json:"-"`
+	EncryptedSecret    *string        `gorm:"type:text" json:"-"`
+	Scopes             pq.StringArray `gorm:"type:text[];not null;default:'{}'" json:"scopes"`
+	RequireSignature   bool           `gorm:"not null;default:false" json:"requireSignature"`
+	RateLimitPerMinute int            `gorm:"not null" json:"rateLimitPerMinute"`
+	ExpiresAt          *time.Time     `json:"expiresAt,omitempty"`
+	LastUsedAt         *time.Time     `json:"lastUsedAt,omitempty"`
+	RevokedAt          *time.Time     `json:"revokedAt,omitempty"`
+	RevokedBy          *string        `gorm:"type:uuid" json:"revokedBy,omitempty"`
+	CreatedBy          string         `gorm:"type:uuid;not null" json:"createdBy"`
+	CreatedAt          time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt          time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (PartnerAPIKey) TableName() string {
+	return "partner_api_keys"
+}
+
+func (k *PartnerAPIKey) IsUsable(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || k.ExpiresAt.After(now))
+}
+
+func (k *PartnerAPIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// PartnerUser links a user to a partner allowed to book on their behalf.
+// ExternalRef is the partner's own identifier for the user.
+type PartnerUser struct {
+	PartnerID   string    `gorm:"type:uuid;primaryKey" json:"partnerId"`
+	UserID      string    `gorm:"type:uuid;primaryKey" json:"userId"`
+	ExternalRef *string   `gorm:"type:varchar(100)" json:"externalRef,omitempty"`
+	CreatedBy   string    `gorm:"type:uuid;not null" json:"createdBy"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"createdAt"`
+
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+func (PartnerUser) TableName() string {
+	return "partner_users"
+}
+
+// PartnerBooking records a ride or order a partner created, so the partner
+// can only read and cancel its own bookings.
+type PartnerBooking struct {
+	ID          string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	PartnerID   string    `gorm:"type:uuid;not null;index" json:"partnerId"`
+	APIKeyID    string    `gorm:"type:uuid;not null" json:"apiKeyId"`
+	UserID      string    `gorm:"type:uuid;not null" json:"userId"`
+	SubjectType string    `gorm:"type:varchar(20);not null" json:"subjectType"`
+	SubjectID   string    `gorm:"type:uuid;not null" json:"subjectId"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (PartnerBooking) TableName() string {
+	return "partner_bookings"
+}
+
+// PartnerAPIUsage counts requests per key, day, route and status class.
+type PartnerAPIUsage struct {
+	APIKeyID    string    `gorm:"type:uuid;primaryKey" json:"apiKeyId"`
+	Day         time.Time `gorm:"type:date;primaryKey" json:"day"`
+	Route       string    `gorm:"type:varchar(200);primaryKey" json:"route"`
+	StatusClass string    `gorm:"type:varchar(3);primaryKey" json:"statusClass"`
+	PartnerID   string    `gorm:"type:uuid;not null" json:"partnerId"`
+	Requests    int64     `gorm:"not null;default:0" json:"requests"`
+}
+
+func (PartnerAPIUsage) TableName() string {
+	return "partner_api_usage"
+}
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+type PayoutCreditStatus string
+
+const (
+	PayoutCreditStatusPending  PayoutCreditStatus = "pending"
+	PayoutCreditStatusCredited PayoutCreditStatus = "credited"
+	PayoutCreditStatusStuck    PayoutCreditStatus = "stuck"
+	PayoutCreditStatusResolved PayoutCreditStatus = "resolved"
+	// PayoutCreditStatusAccrued is never stored in payout_credits; it tells
+	// the caller the payout was held for settlement instead.
+	PayoutCreditStatusAccrued PayoutCreditStatus = "accrued"
+)
+
+// Payout states shown to providers on the order the payout belongs to.
+const (
+	OrderPayoutStatusPending = "pending"
+	OrderPayoutStatusPaid    = "paid"
+)
+
+// PayoutCredit is a provider wallet credit that failed when the work was
+// completed and is retried in the background until it lands or an admin
+// resolves it by hand.
+type PayoutCredit struct {
+	ID              string             `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	ReferenceType   string             `gorm:"type:varchar(50);not null" json:"referenceType"`
+	ReferenceID     string             `gorm:"type:uuid;not null;index" json:"referenceId"`
+	ReferenceNumber string             `gorm:"type:varchar(50)" json:"referenceNumber,omitempty"`
+	ProviderID      *string            `gorm:"type:uuid" json:"providerId,omitempty"`
+	ProviderUserID  string             `gorm:"type:uuid;not null;index" json:"providerUserId"`
+	Amount          float64            `gorm:"type:decimal(12,2);not null" json:"amount"`
+	TransactionType string             `gorm:"type:varchar(50);not null" json:"transactionType"`
+	Description     string             `gorm:"type:text" json:"description"`
+	Metadata        JSONBMap           `gorm:"type:jsonb" json:"metadata,omitempty"`
+	Status          PayoutCreditStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	Attempts        int                `gorm:"not null;default:0" json:"attempts"`
+	LastError       string             `gorm:"type:text" json:"lastError,omitempty"`
+	NextAttemptAt   *time.Time         `json:"nextAttemptAt,omitempty"`
+	AlertedAt       *time.Time         `json:"alertedAt,omitempty"`
+	TransactionID   *string            `gorm:"type:uuid" json:"transactionId,omitempty"`
+	CreditedAt      *time.Time         `json:"creditedAt,omitempty"`
+	ResolvedBy      *string            `gorm:"type:uuid" json:"resolvedBy,omitempty"`
+	ResolutionNote  string             `gorm:"type:text" json:"resolutionNote,omitempty"`
+	ResolvedAt      *time.Time         `json:"resolvedAt,omitempty"`
+	CreatedAt       time.Time          `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt       time.Time          `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (PayoutCredit) TableName() string {
+	return "payout_credits"
+}
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+type PrivacyRequestType string
+
+const (
+	PrivacyRequestExport   PrivacyRequestType = "export"
+	PrivacyRequestDeletion PrivacyRequestType = "deletion"
+)
+
+type PrivacyRequestStatus string
+
+const (
+	PrivacyRequestPending    PrivacyRequestStatus = "pending"
+	PrivacyRequestProcessing PrivacyRequestStatus = "processing"
+	PrivacyRequestCompleted  PrivacyRequestStatus = "completed"
+	PrivacyRequestFailed     PrivacyRequestStatus = "failed"
+	PrivacyRequestCancelled  PrivacyRequestStatus = "cancelled"
+)
+
+// PrivacyRequest is a user's request for a copy of their data or for their
+// account to be deleted. Requests are carried out by a background worker once
+// ScheduledFor has passed; deletions are scheduled after a grace period during
+// which the user can cancel.
+type PrivacyRequest struct {
+	ID           string               `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	UserID       string               `gorm:"type:uuid;not null;index" json:"userId"`
+	Type         PrivacyRequestType   `gorm:"type:varchar(20);not null" json:"type"`
+	Status       PrivacyRequestStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	Reason       string               `gorm:"type:text" json:"reason,omitempty"`
+	ScheduledFor time.Time            `gorm:"not null" json:"scheduledFor"`
+	Attempts     int                  `gorm:"not null;default:0" json:"attempts"`
+	LastError    string               `gorm:"type:text" json:"-"`
+
+	// Set on completed exports; the archive is removed once ExpiresAt passes.
+	ArchiveKey  *string    `gorm:"type:varchar(500)" json:"-"`
+	ArchiveSize int64      `gorm:"not null;default:0" json:"archiveSize,omitempty"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+
+	StartedAt   *time.Time `json:"startedAt,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	CancelledAt *time.Time `json:"cancelledAt,omitempty"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (PrivacyRequest) TableName() string {
+	return "privacy_requests"
+}
+
+func (r *PrivacyRequest) IsOpen() bool {
+	return r.Status == PrivacyRequestPending || r.Status == PrivacyRequestProcessing
+}
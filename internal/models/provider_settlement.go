@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+type SettlementStatus string
+
+const (
+	SettlementStatusPendingApproval SettlementStatus = "pending_approval"
+	// SettlementStatusApproved means the wallet credit has been queued for
+	// retry and has not landed yet.
+	SettlementStatusApproved SettlementStatus = "approved"
+	SettlementStatusPaid     SettlementStatus = "paid"
+	SettlementStatusRejected SettlementStatus = "rejected"
+)
+
+// Settlement states shown to providers on the order a payout belongs to.
+const (
+	OrderSettlementStatusUnsettled = "unsettled"
+	OrderSettlementStatusBatched   = "batched"
+	OrderSettlementStatusSettled   = "settled"
+)
+
+// PayoutReferenceSettlement is the payout credit reference type of a
+// settlement; such credits go straight to the wallet instead of accruing.
+const PayoutReferenceSettlement = "provider_settlement"
+
+// ProviderSettlement batches a provider's accrued order payouts for one week
+// into a single wallet credit that an admin approves.
+type ProviderSettlement struct {
+	ID             string           `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	ProviderUserID string           `gorm:"type:uuid;not null;index" json:"providerUserId"`
+	ProviderID     *string          `gorm:"type:uuid" json:"providerId,omitempty"`
+	PeriodStart    time.Time        `gorm:"not null" json:"periodStart"`
+	PeriodEnd      time.Time        `gorm:"not null" json:"periodEnd"`
+	ItemCount      int              `gorm:"not null;default:0" json:"itemCount"`
+	TotalAmount    float64          `gorm:"type:decimal(12,2);not null;default:0" json:"totalAmount"`
+	Status         SettlementStatus `gorm:"type:varchar(20);not null;default:'pending_approval'" json:"status"`
+	ReviewedBy     *string          `gorm:"type:uuid" json:"reviewedBy,omitempty"`
+	ReviewNote     string           `gorm:"type:text" json:"reviewNote,omitempty"`
+	ReviewedAt     *time.Time       `json:"reviewedAt,omitempty"`
+	PaidAt         *time.Time       `json:"paidAt,omitempty"`
+	CreatedAt      time.Time        `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt      time.Time        `gorm:"autoUpdateTime" json:"updatedAt"`
+
+	Items []SettlementItem `gorm:"foreignKey:SettlementID" json:"items,omitempty"`
+}
+
+func (ProviderSettlement) TableName() string {
+	return "provider_settlements"
+}
+
+// SettlementItem is one order payout held for settlement. It has no
+// settlement until the weekly run picks it up, and goes back to none when
+// its settlement is rejected.
+type SettlementItem struct {
+	ID              string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	SettlementID    *string   `gorm:"type:uuid;index" json:"settlementId,omitempty"`
+	ReferenceType   string    `gorm:"type:varchar(50);not null" json:"referenceType"`
+	ReferenceID     string    `gorm:"type:uuid;not null" json:"referenceId"`
+	ReferenceNumber string    `gorm:"type:varchar(50)" json:"referenceNumber,omitempty"`
+	ProviderID      *string   `gorm:"type:uuid" json:"providerId,omitempty"`
+	ProviderUserID  string    `gorm:"type:uuid;not null" json:"providerUserId"`
+	Amount          float64   `gorm:"type:decimal(12,2);not null" json:"amount"`
+	TransactionType string    `gorm:"type:varchar(50);not null" json:"transactionType"`
+	Description     string    `gorm:"type:text" json:"description"`
+	Metadata        JSONBMap  `gorm:"type:jsonb" json:"metadata,omitempty"`
+	EarnedAt        time.Time `gorm:"not null" json:"earnedAt"`
+	CreatedAt       time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (SettlementItem) TableName() string {
+	return "settlement_items"
+}
@@ -0,0 +1,137 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type RecurrenceFrequency string
+
+const (
+	RecurrenceWeekly   RecurrenceFrequency = "weekly"
+	RecurrenceBiweekly RecurrenceFrequency = "biweekly"
+	RecurrenceMonthly  RecurrenceFrequency = "monthly"
+)
+
+type RecurringPlanStatus string
+
+const (
+	RecurringPlanActive    RecurringPlanStatus = "active"
+	RecurringPlanPaused    RecurringPlanStatus = "paused"
+	RecurringPlanCancelled RecurringPlanStatus = "cancelled"
+	RecurringPlanEnded     RecurringPlanStatus = "ended"
+)
+
+// RecurringOrderItem is a service or addon booked on every occurrence.
+type RecurringOrderItem struct {
+	Slug     string `json:"slug"`
+	Quantity int    `json:"quantity"`
+}
+
+// RecurringOrderTemplate is the booking repeated on each occurrence. Prices
+// are worked out again whenever an occurrence is booked.
+type RecurringOrderTemplate struct {
+	CustomerInfo   CustomerInfo         `json:"customerInfo"`
+	Time           string               `json:"time"`
+	PreferredTime  string               `json:"preferredTime,omitempty"`
+	QuantityOfPros int                  `json:"quantityOfPros"`
+	ToolsRequired  bool                 `json:"toolsRequired"`
+	PersonCount    int                  `json:"personCount"`
+	CategorySlug   string               `json:"categorySlug"`
+	Services       []RecurringOrderItem `json:"services"`
+	Addons         []RecurringOrderItem `json:"addons,omitempty"`
+	SpecialNotes   string               `json:"specialNotes,omitempty"`
+	PaymentMethod  string               `json:"paymentMethod"`
+}
+
+func (t RecurringOrderTemplate) Value() (driver.Value, error) {
+	return json.Marshal(t)
+}
+
+func (t *RecurringOrderTemplate) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, t)
+}
+
+// RecurringOrderPlan repeats a home-service booking on a schedule. Each
+// occurrence becomes an ordinary order linked back to the plan; occurrences
+// are numbered from 0, the order the plan was created with.
+type RecurringOrderPlan struct {
+	ID         string                 `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	CustomerID string                 `gorm:"type:uuid;not null;index" json:"customerId"`
+	Frequency  RecurrenceFrequency    `gorm:"type:varchar(20);not null" json:"frequency"`
+	Status     RecurringPlanStatus    `gorm:"type:varchar(20);not null;default:'active';index" json:"status"`
+	Template   RecurringOrderTemplate `gorm:"type:jsonb;not null" json:"template"`
+
+	StartDate      string  `gorm:"type:varchar(10);not null" json:"startDate"`
+	EndDate        *string `gorm:"type:varchar(10)" json:"endDate,omitempty"`
+	MaxOccurrences *int    `json:"maxOccurrences,omitempty"`
+
+	// NextOccurrence is the index of the next occurrence to book and
+	// NextDate its date.
+	NextOccurrence int            `gorm:"not null;default:1" json:"nextOccurrence"`
+	NextDate       string         `gorm:"type:varchar(10);not null;index" json:"nextDate"`
+	SkippedDates   pq.StringArray `gorm:"type:text[];not null;default:'{}'" json:"skippedDates"`
+	OrdersCreated  int            `gorm:"not null;default:0" json:"ordersCreated"`
+	LastError      string         `gorm:"type:text" json:"lastError,omitempty"`
+
+	PausedAt    *time.Time `json:"pausedAt,omitempty"`
+	CancelledAt *time.Time `json:"cancelledAt,omitempty"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (RecurringOrderPlan) TableName() string {
+	return "recurring_order_plans"
+}
+
+// OccurrenceDate gives the date of the nth occurrence. Monthly plans keep
+// the start's day of month, falling back to the month's last day.
+func (p *RecurringOrderPlan) OccurrenceDate(n int) (time.Time, error) {
+	start, err := time.Parse("2006-01-02", p.StartDate)
+	if err != nil {
+		return time.Time{}, err
+	}
+	switch p.Frequency {
+	case RecurrenceWeekly:
+		return start.AddDate(0, 0, 7*n), nil
+	case RecurrenceBiweekly:
+		return start.AddDate(0, 0, 14*n), nil
+	case RecurrenceMonthly:
+		first := time.Date(start.Year(), start.Month()+time.Month(n), 1, 0, 0, 0, 0, time.UTC)
+		lastDay := first.AddDate(0, 1, -1).Day()
+		day := start.Day()
+		if day > lastDay {
+			day = lastDay
+		}
+		return first.AddDate(0, 0, day-1), nil
+	}
+	return time.Time{}, errors.New("unknown recurrence frequency")
+}
+
+// HasEnded reports whether the nth occurrence falls past the plan's end.
+func (p *RecurringOrderPlan) HasEnded(n int, date time.Time) bool {
+	if p.MaxOccurrences != nil && n >= *p.MaxOccurrences {
+		return true
+	}
+	return p.EndDate != nil && date.Format("2006-01-02") > *p.EndDate
+}
+
+func (p *RecurringOrderPlan) IsSkipped(date string) bool {
+	for _, d := range p.SkippedDates {
+		if d == date {
+			return true
+		}
+	}
+	return false
+}
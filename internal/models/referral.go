@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+const (
+	ReferralStatusPending  = "pending"
+	ReferralStatusRewarded = "rewarded"
+	ReferralStatusRejected = "rejected"
+	ReferralStatusExpired  = "expired"
+)
+
+// Reasons a referral is rejected instead of left pending.
+const (
+	ReferralRejectedSelf         = "self_referral"
+	ReferralRejectedDeviceReused = "device_reused"
+	ReferralRejectedPhoneReused  = "phone_reused"
+)
+
+// Referral attributes a user's signup to the user whose code they used. It
+// stays pending until the referee completes their first ride or order, when
+// both sides are credited, and is rejected at once when the signup looks like
+// the referrer or an earlier account coming back. The referee's phone is kept
+// only as a hash, so a number can be recognised after its account is erased.
+type Referral struct {
+	ID              string     `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	ReferrerID      string     `gorm:"type:uuid;not null;index" json:"referrerId"`
+	RefereeID       string     `gorm:"type:uuid;not null;uniqueIndex" json:"refereeId"`
+	Code            string     `gorm:"type:varchar(20);not null" json:"code"`
+	Status          string     `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	RejectionReason *string    `gorm:"type:varchar(50)" json:"rejectionReason,omitempty"`
+	DeviceID        *string    `gorm:"type:varchar(255);index" json:"deviceId,omitempty"`
+	PhoneHash       *string    `gorm:"type:varchar(64);index" json:"-"`
+	QualifyBy       time.Time  `gorm:"not null" json:"qualifyBy"`
+	QualifyingType  *string    `gorm:"type:varchar(20)" json:"qualifyingType,omitempty"`
+	QualifyingID    *string    `gorm:"type:uuid" json:"qualifyingId,omitempty"`
+	ReferrerReward  float64    `gorm:"type:decimal(10,2);not null;default:0" json:"referrerReward"`
+	RefereeReward   float64    `gorm:"type:decimal(10,2);not null;default:0" json:"refereeReward"`
+	RewardedAt      *time.Time `json:"rewardedAt,omitempty"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt       time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
+
+	Referrer *User `gorm:"foreignKey:ReferrerID" json:"referrer,omitempty"`
+	Referee  *User `gorm:"foreignKey:RefereeID" json:"referee,omitempty"`
+}
+
+func (Referral) TableName() string {
+	return "referrals"
+}
@@ -6,6 +6,11 @@ import (
 	"gorm.io/gorm"
 )
 
+const (
+	RidePaymentWallet = "wallet"
+	RidePaymentCash   = "cash"
+)
+
 type Ride struct {
 	ID            string  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
 	RiderID       string  `gorm:"type:uuid;not null;index" json:"riderId"`
@@ -27,6 +32,9 @@ type Ride struct {
 	EstimatedDuration int     `json:"estimatedDuration"`                           
 	EstimatedFare     float64 `gorm:"type:decimal(10,2)" json:"estimatedFare"`
 
+	// Currency is the pickup city's, fixed when the ride is requested.
+	Currency string `gorm:"type:varchar(3)" json:"currency"`
+
 	ActualDistance *float64 `gorm:"type:decimal(10,2)" json:"actualDistance"`
 	ActualDuration *int     `json:"actualDuration"`                          
 	ActualFare     *float64 `gorm:"type:decimal(10,2)" json:"actualFare"`
@@ -46,11 +54,22 @@ type Ride struct {
 
 	WalletHoldID *string `gorm:"type:uuid" json:"walletHoldId"`
 
+	// Cash rides skip the wallet hold; the driver collects the fare and the
+	// platform's share is debited from the driver wallet on completion.
+	PaymentMethod       string   `gorm:"type:varchar(20);not null;default:'wallet';index" json:"paymentMethod"`
+	CashCollected       *float64 `gorm:"type:decimal(10,2)" json:"cashCollected,omitempty"`
+	CashCommission      *float64 `gorm:"type:decimal(10,2)" json:"cashCommission,omitempty"`
+	CashCommissionTxnID *string  `gorm:"type:uuid" json:"cashCommissionTxnId,omitempty"`
+
 	RiderNotes         string  `gorm:"type:text" json:"riderNotes"`
 	CancellationReason string  `gorm:"type:text" json:"cancellationReason"`
 	CancelledBy        *string `gorm:"type:varchar(50)" json:"cancelledBy"` 
 	IsScheduled        bool    `gorm:"default:false" json:"isScheduled"`
 
+	// PickupETAMinutes is the pickup ETA the rider was shown when the driver
+	// accepted.
+	PickupETAMinutes *int `json:"pickupEtaMinutes,omitempty"`
+
 	ScheduledAt *time.Time `json:"scheduledAt"`
 	RequestedAt time.Time  `gorm:"not null" json:"requestedAt"`
 	AcceptedAt  *time.Time `json:"acceptedAt"`
@@ -59,6 +78,9 @@ type Ride struct {
 	CompletedAt *time.Time `json:"completedAt"`
 	CancelledAt *time.Time `json:"cancelledAt"`
 
+	// ArrivalMethod records how the arrival at pickup was detected.
+	ArrivalMethod *string `gorm:"type:varchar(20)" json:"arrivalMethod,omitempty"`
+
 	CreatedAt time.Time      `gorm:"autoCreateTime" json:"createdAt"`
 	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
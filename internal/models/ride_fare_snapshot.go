@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// RideFareSnapshot freezes the pricing components used to charge a ride at
+// completion so later breakdown requests never re-price against current rates.
+type RideFareSnapshot struct {
+	ID                      string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	RideID                  string    `gorm:"type:uuid;not null;uniqueIndex" json:"rideId"`
+	VehicleTypeID           string    `gorm:"type:uuid;not null" json:"vehicleTypeId"`
+	Currency                string    `gorm:"type:varchar(10);not null;default:'INR'" json:"currency"`
+	BaseFare                float64   `gorm:"type:decimal(10,2);default:0" json:"baseFare"`
+	DistanceFare            float64   `gorm:"type:decimal(10,2);default:0" json:"distanceFare"`
+	DurationFare            float64   `gorm:"type:decimal(10,2);default:0" json:"durationFare"`
+	BookingFee              float64   `gorm:"type:decimal(10,2);default:0" json:"bookingFee"`
+	SurgeMultiplier         float64   `gorm:"type:decimal(4,2);default:1.0" json:"surgeMultiplier"`
+	SurgeAmount             float64   `gorm:"type:decimal(10,2);default:0" json:"surgeAmount"`
+	WaitTimeCharge          float64   `gorm:"type:decimal(10,2);default:0" json:"waitTimeCharge"`
+	DestinationChangeCharge float64   `gorm:"type:decimal(10,2);default:0" json:"destinationChangeCharge"`
+	PriceCapAdjustment      float64   `gorm:"type:decimal(10,2);default:0" json:"priceCapAdjustment"`
+	PromoDiscount           float64   `gorm:"type:decimal(10,2);default:0" json:"promoDiscount"`
+	LoyaltyDiscount         float64   `gorm:"type:decimal(10,2);default:0" json:"loyaltyDiscount"`
+	TaxAmount               float64   `gorm:"type:decimal(10,2);default:0" json:"taxAmount"`
+	TaxLines                TaxLines  `gorm:"type:jsonb" json:"taxLines"`
+	TotalCharged            float64   `gorm:"type:decimal(10,2);not null" json:"totalCharged"`
+	DriverShare             float64   `gorm:"type:decimal(10,2);not null" json:"driverShare"`
+	PlatformFee             float64   `gorm:"type:decimal(10,2);default:0" json:"platformFee"`
+	PlatformAbsorbed        float64   `gorm:"type:decimal(10,2);default:0" json:"platformAbsorbed"`
+	PriceCapped             bool      `gorm:"default:false" json:"priceCapped"`
+	CreatedAt               time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (RideFareSnapshot) TableName() string {
+	return "ride_fare_snapshots"
+}
@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+type FareSplitStatus string
+
+const (
+	// FareSplitStatusInvited waits for the co-rider to answer.
+	FareSplitStatusInvited  FareSplitStatus = "invited"
+	FareSplitStatusAccepted FareSplitStatus = "accepted"
+	FareSplitStatusDeclined FareSplitStatus = "declined"
+	// FareSplitStatusRemoved is a co-rider the organizer took off the split.
+	FareSplitStatusRemoved FareSplitStatus = "removed"
+	// FareSplitStatusExpired is an invitation still open when the ride
+	// ended, or an accepted share released because the ride was cancelled.
+	FareSplitStatusExpired FareSplitStatus = "expired"
+	// FareSplitStatusCharged is a share captured from the co-rider's wallet
+	// when the ride completed. It is final and never charged again.
+	FareSplitStatusCharged FareSplitStatus = "charged"
+)
+
+// RideFareSplit is a co-rider sharing the fare of a ride booked by the
+// organizer. Once accepted the co-rider's wallet holds SharePercent of the
+// estimated fare, and on completion that share of the final fare is captured
+// from it. The organizer pays the rest, and pays the co-rider's share too if
+// it could not be captured (PaidByOrganizer).
+type RideFareSplit struct {
+	ID              string          `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	RideID          string          `gorm:"type:uuid;not null;index" json:"rideId"`
+	UserID          string          `gorm:"type:uuid;not null;index" json:"userId"`
+	InvitedBy       string          `gorm:"type:uuid;not null" json:"invitedBy"`
+	SharePercent    float64         `gorm:"type:decimal(5,2);not null" json:"sharePercent"`
+	Status          FareSplitStatus `gorm:"type:varchar(20);not null" json:"status"`
+	HoldID          *string         `gorm:"type:uuid" json:"holdId,omitempty"`
+	HoldAmount      *float64        `gorm:"type:decimal(10,2)" json:"holdAmount,omitempty"`
+	ChargedAmount   *float64        `gorm:"type:decimal(10,2)" json:"chargedAmount,omitempty"`
+	PaidByOrganizer bool            `gorm:"not null;default:false" json:"paidByOrganizer"`
+	RespondedAt     *time.Time      `json:"respondedAt,omitempty"`
+	CreatedAt       time.Time       `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt       time.Time       `gorm:"autoUpdateTime" json:"updatedAt"`
+
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// IsOpen reports whether the co-rider still holds a share of the fare.
+func (s *RideFareSplit) IsOpen() bool {
+	return s.Status == FareSplitStatusInvited || s.Status == FareSplitStatusAccepted
+}
+
+func (RideFareSplit) TableName() string {
+	return "ride_fare_splits"
+}
@@ -0,0 +1,75 @@
+package models
+
+import "time"
+
+type InsurancePolicyStatus string
+
+const (
+	InsurancePolicyActive InsurancePolicyStatus = "active"
+	InsurancePolicyClosed InsurancePolicyStatus = "closed"
+	InsurancePolicyVoided InsurancePolicyStatus = "voided"
+)
+
+// RideInsurancePolicy is the per-trip cover issued when a ride starts. Coverage
+// ends when the trip completes; the policy stays claimable afterwards.
+type RideInsurancePolicy struct {
+	ID                string                `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	RideID            string                `gorm:"type:uuid;not null;uniqueIndex" json:"rideId"`
+	RiderID           string                `gorm:"type:uuid;not null;index" json:"riderId"`
+	DriverID          string                `gorm:"type:uuid;not null;index" json:"driverId"`
+	Provider          string                `gorm:"type:varchar(100);not null" json:"provider"`
+	PolicyNumber      string                `gorm:"type:varchar(100);not null;uniqueIndex" json:"policyNumber"`
+	ProviderReference string                `gorm:"type:varchar(255)" json:"providerReference,omitempty"`
+	CoverageAmount    float64               `gorm:"type:decimal(12,2);not null" json:"coverageAmount"`
+	Premium           float64               `gorm:"type:decimal(10,2);default:0" json:"premium"`
+	Currency          string                `gorm:"type:varchar(3);not null" json:"currency"`
+	Status            InsurancePolicyStatus `gorm:"type:varchar(20);not null;default:'active';index" json:"status"`
+	CoverageStartAt   time.Time             `gorm:"not null" json:"coverageStartAt"`
+	CoverageEndAt     *time.Time            `json:"coverageEndAt,omitempty"`
+	CreatedAt         time.Time             `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt         time.Time             `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (RideInsurancePolicy) TableName() string {
+	return "ride_insurance_policies"
+}
+
+type InsuranceClaimStatus string
+
+const (
+	InsuranceClaimSubmitted   InsuranceClaimStatus = "submitted"
+	InsuranceClaimUnderReview InsuranceClaimStatus = "under_review"
+	InsuranceClaimApproved    InsuranceClaimStatus = "approved"
+	InsuranceClaimRejected    InsuranceClaimStatus = "rejected"
+	InsuranceClaimPaid        InsuranceClaimStatus = "paid"
+)
+
+type InsuranceClaim struct {
+	ID             string               `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	ClaimNumber    string               `gorm:"type:varchar(50);not null;uniqueIndex" json:"claimNumber"`
+	PolicyID       string               `gorm:"type:uuid;not null;index" json:"policyId"`
+	RideID         string               `gorm:"type:uuid;not null;index" json:"rideId"`
+	ClaimantID     string               `gorm:"type:uuid;not null;index" json:"claimantId"`
+	ClaimantRole   string               `gorm:"type:varchar(20);not null" json:"claimantRole"`
+	ClaimType      string               `gorm:"type:varchar(30);not null" json:"claimType"`
+	Description    string               `gorm:"type:text;not null" json:"description"`
+	IncidentAt     time.Time            `gorm:"not null" json:"incidentAt"`
+	AmountClaimed  float64              `gorm:"type:decimal(12,2);default:0" json:"amountClaimed"`
+	AmountApproved *float64             `gorm:"type:decimal(12,2)" json:"amountApproved,omitempty"`
+	Status         InsuranceClaimStatus `gorm:"type:varchar(20);not null;default:'submitted';index" json:"status"`
+	ReviewedBy     *string              `gorm:"type:uuid" json:"reviewedBy,omitempty"`
+	ReviewNotes    string               `gorm:"type:text" json:"reviewNotes,omitempty"`
+	ReviewedAt     *time.Time           `json:"reviewedAt,omitempty"`
+	CreatedAt      time.Time            `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt      time.Time            `gorm:"autoUpdateTime" json:"updatedAt"`
+
+	Policy *RideInsurancePolicy `gorm:"foreignKey:PolicyID" json:"policy,omitempty"`
+}
+
+func (InsuranceClaim) TableName() string {
+	return "insurance_claims"
+}
+
+func (c *InsuranceClaim) IsOpen() bool {
+	return c.Status == InsuranceClaimSubmitted || c.Status == InsuranceClaimUnderReview
+}
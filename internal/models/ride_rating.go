@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type RideRatingDirection string
+
+const (
+	RideRatingOfDriver RideRatingDirection = "rider_to_driver"
+	RideRatingOfRider  RideRatingDirection = "driver_to_rider"
+)
+
+// Tags riders and drivers can attach to a rating to say what it was about.
+var (
+	DriverRatingTags = []string{"cleanliness", "driving", "behaviour", "navigation", "punctuality"}
+	RiderRatingTags  = []string{"behaviour", "punctuality", "cleanliness", "communication"}
+)
+
+// RideRating is one side's rating of the other after a ride. Each ride is
+// rated at most once in each direction. The ride row keeps a copy of the
+// score for older clients; profile averages are computed from these records.
+type RideRating struct {
+	ID        string              `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	RideID    string              `gorm:"type:uuid;not null;uniqueIndex:idx_ride_rating_direction" json:"rideId"`
+	Direction RideRatingDirection `gorm:"type:varchar(20);not null;uniqueIndex:idx_ride_rating_direction" json:"direction"`
+	RaterID   string              `gorm:"type:uuid;not null" json:"raterId"`
+	RateeID   string              `gorm:"type:uuid;not null;index" json:"rateeId"`
+	Score     int                 `gorm:"not null" json:"score"`
+	Tags      pq.StringArray      `gorm:"type:text[];not null;default:'{}'" json:"tags"`
+	Comment   string              `gorm:"type:text" json:"comment,omitempty"`
+	CreatedAt time.Time           `gorm:"autoCreateTime;index" json:"createdAt"`
+}
+
+func (RideRating) TableName() string {
+	return "ride_ratings"
+}
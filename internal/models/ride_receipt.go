@@ -0,0 +1,80 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+type ReceiptLineItem struct {
+	Code   string  `json:"code"`
+	Label  string  `json:"label"`
+	Amount float64 `json:"amount"`
+}
+
+type ReceiptLineItems []ReceiptLineItem
+
+func (l ReceiptLineItems) Value() (driver.Value, error) {
+	if l == nil {
+		return json.Marshal([]ReceiptLineItem{})
+	}
+	return json.Marshal(l)
+}
+
+func (l *ReceiptLineItems) Scan(value interface{}) error {
+	if value == nil {
+		*l = ReceiptLineItems{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, l)
+}
+
+// RideReceipt is the itemized receipt issued to the rider when a trip
+// completes. Amounts are copied from the fare snapshot so the receipt never
+// changes after it is issued.
+type RideReceipt struct {
+	ID            string  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	ReceiptNumber string  `gorm:"type:varchar(50);not null;uniqueIndex" json:"receiptNumber"`
+	RideID        string  `gorm:"type:uuid;not null;uniqueIndex" json:"rideId"`
+	RiderID       string  `gorm:"type:uuid;not null;index" json:"riderId"`
+	DriverID      *string `gorm:"type:uuid" json:"driverId,omitempty"`
+	DriverName    string  `gorm:"type:varchar(255)" json:"driverName"`
+	VehicleType   string  `gorm:"type:varchar(100)" json:"vehicleType"`
+
+	PickupAddress  string  `gorm:"type:text" json:"pickupAddress"`
+	DropoffAddress string  `gorm:"type:text" json:"dropoffAddress"`
+	DistanceKm     float64 `gorm:"type:decimal(10,2)" json:"distanceKm"`
+	DurationMins   int     `json:"durationMins"`
+
+	Currency        string           `gorm:"type:varchar(10);not null" json:"currency"`
+	Charges         ReceiptLineItems `gorm:"type:jsonb" json:"charges"`
+	Surcharges      ReceiptLineItems `gorm:"type:jsonb" json:"surcharges"`
+	Discounts       ReceiptLineItems `gorm:"type:jsonb" json:"discounts"`
+	Taxes           ReceiptLineItems `gorm:"type:jsonb" json:"taxes"`
+	Subtotal        float64          `gorm:"type:decimal(10,2)" json:"subtotal"`
+	SurchargesTotal float64          `gorm:"type:decimal(10,2)" json:"surchargesTotal"`
+	DiscountsTotal  float64          `gorm:"type:decimal(10,2)" json:"discountsTotal"`
+	TaxesTotal      float64          `gorm:"type:decimal(10,2)" json:"taxesTotal"`
+	Total           float64          `gorm:"type:decimal(10,2);not null" json:"total"`
+	PaymentMethod   string           `gorm:"type:varchar(20)" json:"paymentMethod"`
+	PolicyNumber    string           `gorm:"type:varchar(100)" json:"policyNumber,omitempty"`
+
+	TripStartedAt   *time.Time `json:"tripStartedAt,omitempty"`
+	TripCompletedAt *time.Time `json:"tripCompletedAt,omitempty"`
+	IssuedAt        time.Time  `gorm:"not null" json:"issuedAt"`
+
+	EmailedTo *string    `gorm:"type:varchar(255)" json:"emailedTo,omitempty"`
+	EmailedAt *time.Time `json:"emailedAt,omitempty"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (RideReceipt) TableName() string {
+	return "ride_receipts"
+}
@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// RideTrackPoint is a driver position recorded while a ride is in progress.
+// The points are what a completed ride's reported distance is checked against.
+type RideTrackPoint struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement" json:"id"`
+	RideID     string    `gorm:"type:uuid;not null;index" json:"rideId"`
+	DriverID   string    `gorm:"type:uuid;not null" json:"driverId"`
+	Latitude   float64   `gorm:"type:decimal(10,8);not null" json:"latitude"`
+	Longitude  float64   `gorm:"type:decimal(11,8);not null" json:"longitude"`
+	Speed      float64   `gorm:"type:decimal(6,2);default:0" json:"speed"`
+	Accuracy   float64   `gorm:"type:decimal(6,2);default:0" json:"accuracy"`
+	RecordedAt time.Time `gorm:"not null" json:"recordedAt"`
+}
+
+func (RideTrackPoint) TableName() string {
+	return "ride_track_points"
+}
+
+type TripVerificationStatus string
+
+const (
+	TripVerificationVerified     TripVerificationStatus = "verified"
+	TripVerificationFlagged      TripVerificationStatus = "flagged"
+	TripVerificationInsufficient TripVerificationStatus = "insufficient_data"
+	TripVerificationReviewed     TripVerificationStatus = "reviewed"
+)
+
+// RideTripVerification compares the distance and duration a driver reported
+// when completing a ride with what the server measured from the ride's track
+// points and start time. Flagged rides wait for an admin to review them.
+type RideTripVerification struct {
+	ID                   string                 `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	RideID               string                 `gorm:"type:uuid;not null;uniqueIndex" json:"rideId"`
+	DriverID             string                 `gorm:"type:uuid;not null;index" json:"driverId"`
+	ReportedDistanceKm   float64                `gorm:"type:decimal(10,2);not null" json:"reportedDistanceKm"`
+	ReportedDurationSec  int                    `gorm:"not null" json:"reportedDurationSec"`
+	TrackedDistanceKm    float64                `gorm:"type:decimal(10,2);not null;default:0" json:"trackedDistanceKm"`
+	TrackedDurationSec   int                    `gorm:"not null;default:0" json:"trackedDurationSec"`
+	TrackPointCount      int                    `gorm:"not null;default:0" json:"trackPointCount"`
+	DistanceDeviationPct float64                `gorm:"type:decimal(8,2);default:0" json:"distanceDeviationPct"`
+	DurationDeviationPct float64                `gorm:"type:decimal(8,2);default:0" json:"durationDeviationPct"`
+	Status               TripVerificationStatus `gorm:"type:varchar(20);not null;index" json:"status"`
+	Reasons              pq.StringArray         `gorm:"type:text[]" json:"reasons"`
+	ReviewedBy           *string                `gorm:"type:uuid" json:"reviewedBy,omitempty"`
+	ReviewNote           string                 `gorm:"type:text" json:"reviewNote,omitempty"`
+	ReviewedAt           *time.Time             `json:"reviewedAt,omitempty"`
+	CreatedAt            time.Time              `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt            time.Time              `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (RideTripVerification) TableName() string {
+	return "ride_trip_verifications"
+}
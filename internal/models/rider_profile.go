@@ -39,6 +39,7 @@ type RiderProfile struct {
 	WorkAddress          *Address       `gorm:"type:jsonb" json:"workAddress,omitempty"`
 	PreferredVehicleType *string        `gorm:"type:varchar(50)" json:"preferredVehicleType,omitempty"`
 	Rating               float64        `gorm:"type:decimal(3,2);not null;default:5.0" json:"rating"`
+	RatingCount          int            `gorm:"not null;default:0" json:"ratingCount"`
 	TotalRides           int            `gorm:"not null;default:0" json:"totalRides"`
 	TotalSpent           float64        `gorm:"type:decimal(10,2);default:0" json:"totalSpent"`
 	CancellationRate     float64        `gorm:"type:decimal(5,2);default:0" json:"cancellationRate"`
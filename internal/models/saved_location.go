@@ -12,6 +12,9 @@ type SavedLocation struct {
     Label      string         `gorm:"type:varchar(50);not null" json:"label"` 
     CustomName string         `gorm:"type:varchar(100)" json:"customName,omitempty"`
     Address    string         `gorm:"type:varchar(500);not null" json:"address"`
+    Details      string       `gorm:"type:varchar(255)" json:"details,omitempty"`
+    Instructions string       `gorm:"type:text" json:"instructions,omitempty"`
+    Geocoded     bool         `gorm:"default:false" json:"geocoded"`
     Latitude   float64        `gorm:"type:decimal(10,8);not null" json:"latitude"`
     Longitude  float64        `gorm:"type:decimal(11,8);not null" json:"longitude"`
     IsDefault  bool           `gorm:"default:false" json:"isDefault"`
@@ -4,6 +4,7 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,6 +18,9 @@ type CustomerInfo struct {
 	Address string  `json:"address"`
 	Lat     float64 `json:"lat"`
 	Lng     float64 `json:"lng"`
+
+	NormalizedAddress string `json:"normalizedAddress,omitempty"`
+	AddressScript     string `json:"addressScript,omitempty"`
 }
 
 func (c CustomerInfo) Value() (driver.Value, error) {
@@ -113,6 +117,36 @@ func (s *SelectedAddons) Scan(value interface{}) error {
 	return json.Unmarshal(bytes, s)
 }
 
+// OrderSurchargeItem is the price snapshot of a category surcharge applied to an order.
+type OrderSurchargeItem struct {
+	SurchargeID string  `json:"surchargeId"`
+	Code        string  `json:"code"`
+	Name        string  `json:"name"`
+	Kind        string  `json:"kind"`
+	Amount      float64 `json:"amount"`
+}
+
+type OrderSurcharges []OrderSurchargeItem
+
+func (s OrderSurcharges) Value() (driver.Value, error) {
+	if s == nil {
+		return nil, nil
+	}
+	return json.Marshal(s)
+}
+
+func (s *OrderSurcharges) Scan(value interface{}) error {
+	if value == nil {
+		*s = OrderSurcharges{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, s)
+}
+
 type PaymentInfo struct {
 	Method        string  `json:"method"`
 	Status        string  `json:"status"`
@@ -143,6 +177,7 @@ type CancellationInfo struct {
 	Reason          string    `json:"reason"`
 	CancellationFee float64   `json:"cancellationFee"`
 	RefundAmount    float64   `json:"refundAmount"`
+	Policy          string    `json:"policy,omitempty"`
 }
 
 func (c CancellationInfo) Value() (driver.Value, error) {
@@ -179,19 +214,42 @@ type ServiceOrderNew struct {
 	AddonsTotal        float64 `gorm:"type:decimal(10,2);default:0" json:"addonsTotal"`
 	Subtotal           float64 `gorm:"type:decimal(10,2);not null" json:"subtotal"`
 	PlatformCommission float64 `gorm:"type:decimal(10,2);not null" json:"platformCommission"`
+	CommissionRate     float64 `gorm:"type:decimal(5,2);not null;default:10" json:"commissionRate"`
+	SurchargesTotal    float64 `gorm:"type:decimal(10,2);default:0" json:"surchargesTotal"`
+	TaxTotal           float64 `gorm:"type:decimal(10,2);default:0" json:"taxTotal"`
 	TotalPrice         float64 `gorm:"type:decimal(10,2);not null" json:"totalPrice"`
+	Currency           string  `gorm:"type:varchar(3)" json:"currency"`
+
+	Surcharges OrderSurcharges `gorm:"type:jsonb" json:"surcharges"`
+	Taxes      TaxLines        `gorm:"type:jsonb" json:"taxes"`
+
+	PaymentInfo      *PaymentInfo `gorm:"type:jsonb" json:"paymentInfo"`
+	WalletHoldID     *string      `gorm:"type:uuid" json:"walletHoldId,omitempty"`
+	PayoutStatus     *string      `gorm:"type:varchar(20)" json:"payoutStatus,omitempty"`
+	SettlementStatus *string      `gorm:"type:varchar(20)" json:"settlementStatus,omitempty"`
 
-	PaymentInfo  *PaymentInfo `gorm:"type:jsonb" json:"paymentInfo"`
-	WalletHoldID *string      `gorm:"type:uuid" json:"walletHoldId,omitempty"`
+	QuoteID         *string `gorm:"type:uuid;index" json:"quoteId,omitempty"`
+	RecurringPlanID *string `gorm:"type:uuid;index" json:"recurringPlanId,omitempty"`
+	// RecurringDate is the plan occurrence the order was booked for; it
+	// stays put when the order is rescheduled.
+	RecurringDate *string `gorm:"type:varchar(10)" json:"recurringDate,omitempty"`
 
 	AssignedProviderID  *string                 `gorm:"type:uuid;index" json:"assignedProviderId"`
 	AssignedProvider    *ServiceProviderProfile `gorm:"foreignKey:AssignedProviderID;references:ID" json:"assignedProvider,omitempty"`
 	ProviderAcceptedAt  *time.Time              `json:"providerAcceptedAt"`
+	ProviderArrivedAt   *time.Time              `json:"providerArrivedAt,omitempty"`
+	ArrivalMethod       *string                 `gorm:"type:varchar(20)" json:"arrivalMethod,omitempty"`
 	ProviderStartedAt   *time.Time              `json:"providerStartedAt"`
 	ProviderCompletedAt *time.Time              `json:"providerCompletedAt"`
 
 	Status string `gorm:"type:varchar(50);not null;default:'pending';index" json:"status"`
 
+	IsMultiSession  bool `gorm:"default:false" json:"isMultiSession"`
+	SessionCount    int  `gorm:"default:1" json:"sessionCount"`
+	ProgressPercent int  `gorm:"default:0" json:"progressPercent"`
+
+	RescheduleCount int `gorm:"default:0" json:"rescheduleCount"`
+
 	CancellationInfo *CancellationInfo `gorm:"type:jsonb" json:"cancellationInfo,omitempty"`
 
 	CustomerRating  *int       `gorm:"type:int" json:"customerRating"`
@@ -207,6 +265,10 @@ type ServiceOrderNew struct {
 	ExpiresAt   *time.Time `json:"expiresAt"`
 	CompletedAt *time.Time `json:"completedAt"`
 
+	// Version is bumped by every update, which only applies if the row is
+	// still at the version it was read with.
+	Version int64 `gorm:"not null;default:0" json:"version"`
+
 	StatusHistory []OrderStatusHistory `gorm:"foreignKey:OrderID" json:"statusHistory,omitempty"`
 }
 
@@ -221,6 +283,18 @@ func (ServiceOrderNew) TableName() string {
 	return "service_orders"
 }
 
+// ServiceAmount is the part of the total shared between provider and platform;
+// surcharges and taxes are passed through and excluded.
+func (o *ServiceOrderNew) ServiceAmount() float64 {
+	return o.TotalPrice - o.SurchargesTotal - o.TaxTotal
+}
+
+// ProviderPayout is the provider's share of the service amount at the
+// commission rate the order was placed with.
+func (o *ServiceOrderNew) ProviderPayout() float64 {
+	return math.Round(o.ServiceAmount()*(100-o.CommissionRate)) / 100
+}
+
 func (o *ServiceOrderNew) CanBeCancelled() bool {
 	cancelableStatuses := []string{
 		"pending",
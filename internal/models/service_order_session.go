@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ServiceSessionStatus string
+
+const (
+	SessionStatusScheduled        ServiceSessionStatus = "scheduled"
+	SessionStatusCheckedIn        ServiceSessionStatus = "checked_in"
+	SessionStatusAwaitingApproval ServiceSessionStatus = "awaiting_approval"
+	SessionStatusChangesRequested ServiceSessionStatus = "changes_requested"
+	SessionStatusApproved         ServiceSessionStatus = "approved"
+	SessionStatusCancelled        ServiceSessionStatus = "cancelled"
+)
+
+// ServiceOrderSession is one working day of a multi-day home-service order.
+// Each session carries a payment milestone that is released to the provider
+// when the customer approves the session's work.
+type ServiceOrderSession struct {
+	ID            string               `gorm:"type:uuid;primaryKey" json:"id"`
+	OrderID       string               `gorm:"type:uuid;not null;index;uniqueIndex:idx_order_session_number,priority:1" json:"orderId"`
+	SessionNumber int                  `gorm:"not null;uniqueIndex:idx_order_session_number,priority:2" json:"sessionNumber"`
+	ScheduledDate string               `gorm:"type:varchar(10);not null" json:"scheduledDate"`
+	ScheduledTime string               `gorm:"type:varchar(5);not null" json:"scheduledTime"`
+	DurationHours float64              `gorm:"type:decimal(4,1);not null" json:"durationHours"`
+	Status        ServiceSessionStatus `gorm:"type:varchar(30);not null;default:'scheduled';index" json:"status"`
+
+	CheckedInAt     *time.Time `json:"checkedInAt,omitempty"`
+	CheckedOutAt    *time.Time `json:"checkedOutAt,omitempty"`
+	WorkSummary     string     `gorm:"type:text" json:"workSummary,omitempty"`
+	ProgressPercent int        `gorm:"default:0" json:"progressPercent"`
+
+	MilestoneAmount float64    `gorm:"type:decimal(10,2);not null" json:"milestoneAmount"`
+	ProviderPayout  float64    `gorm:"type:decimal(10,2);default:0" json:"providerPayout"`
+	MilestonePaidAt *time.Time `json:"milestonePaidAt,omitempty"`
+
+	CustomerApprovedAt *time.Time `json:"customerApprovedAt,omitempty"`
+	CustomerFeedback   string     `gorm:"type:text" json:"customerFeedback,omitempty"`
+
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (s *ServiceOrderSession) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (ServiceOrderSession) TableName() string {
+	return "service_order_sessions"
+}
+
+func (s *ServiceOrderSession) IsApproved() bool {
+	return s.Status == SessionStatusApproved
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Points in an order at which proof of service is captured. Home services
+// use before_service and after_service; laundry uses pickup and delivery.
+const (
+	ProofStageBeforeService = "before_service"
+	ProofStageAfterService  = "after_service"
+	ProofStagePickup        = "pickup"
+	ProofStageDelivery      = "delivery"
+)
+
+const (
+	ProofKindPhoto     = "photo"
+	ProofKindSignature = "signature"
+)
+
+// ProofRequirement is the proof of service providers must capture for orders
+// in a category before they can complete them. PhotoBefore is a photo before
+// the service starts, or at pickup for laundry; PhotoAfter one once it is
+// done, or at delivery; Signature is the customer's signature on completion
+// or delivery. Categories without a requirement need no proof.
+type ProofRequirement struct {
+	CategorySlug string    `gorm:"type:varchar(255);primaryKey" json:"categorySlug"`
+	PhotoBefore  bool      `gorm:"not null;default:false" json:"photoBefore"`
+	PhotoAfter   bool      `gorm:"not null;default:false" json:"photoAfter"`
+	Signature    bool      `gorm:"not null;default:false" json:"signature"`
+	UpdatedBy    *string   `gorm:"type:uuid" json:"updatedBy,omitempty"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (ProofRequirement) TableName() string {
+	return "proof_requirements"
+}
+
+// ServiceProof is a photo or signature captured by the provider at a stage
+// of a home services or laundry order. OrderType is "service_order" or
+// "laundry_order", as used for wallet references.
+type ServiceProof struct {
+	ID         string    `gorm:"type:uuid;primaryKey" json:"id"`
+	OrderType  string    `gorm:"type:varchar(20);not null" json:"orderType"`
+	OrderID    string    `gorm:"type:uuid;not null;index" json:"orderId"`
+	Stage      string    `gorm:"type:varchar(20);not null" json:"stage"`
+	Kind       string    `gorm:"type:varchar(20);not null" json:"kind"`
+	URL        string    `gorm:"type:varchar(1000);not null" json:"url"`
+	SignedBy   *string   `gorm:"type:varchar(255)" json:"signedBy,omitempty"`
+	Note       string    `gorm:"type:text" json:"note,omitempty"`
+	UploadedBy string    `gorm:"type:uuid;not null" json:"uploadedBy"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (p *ServiceProof) BeforeCreate(tx *gorm.DB) error {
+	if p.ID == "" {
+		p.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (ServiceProof) TableName() string {
+	return "service_proofs"
+}
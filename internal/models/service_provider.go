@@ -13,6 +13,17 @@ const (
 	SPStatusActive          ServiceProviderStatus = "active"
 	SPStatusSuspended       ServiceProviderStatus = "suspended"
 	SPStatusBanned          ServiceProviderStatus = "banned"
+	SPStatusRejected        ServiceProviderStatus = "rejected"
+)
+
+type BackgroundCheckStatus string
+
+const (
+	BackgroundCheckNotStarted BackgroundCheckStatus = "not_started"
+	BackgroundCheckPending    BackgroundCheckStatus = "pending"
+	BackgroundCheckClear      BackgroundCheckStatus = "clear"
+	BackgroundCheckFlagged    BackgroundCheckStatus = "flagged"
+	BackgroundCheckFailed     BackgroundCheckStatus = "failed"
 )
 
 type ServiceProviderProfile struct {
@@ -40,6 +51,16 @@ type ServiceProviderProfile struct {
 	HourlyRate *float64 `gorm:"type:decimal(10,2)" json:"hourlyRate,omitempty"`
 	Currency   string   `gorm:"type:varchar(3);default:'INR'" json:"currency"`
 
+	PreferredAddressScript string `gorm:"type:varchar(20);default:'latin'" json:"preferredAddressScript"`
+
+	BackgroundCheckStatus    BackgroundCheckStatus `gorm:"type:varchar(30);not null;default:'not_started'" json:"backgroundCheckStatus"`
+	BackgroundCheckReference string                `gorm:"type:varchar(255)" json:"backgroundCheckReference,omitempty"`
+	BackgroundCheckUpdatedAt *time.Time            `json:"backgroundCheckUpdatedAt,omitempty"`
+	SubmittedForReviewAt     *time.Time            `json:"submittedForReviewAt,omitempty"`
+	ReviewedBy               *string               `gorm:"type:uuid" json:"reviewedBy,omitempty"`
+	ReviewedAt               *time.Time            `json:"reviewedAt,omitempty"`
+	RejectionReason          string                `gorm:"type:text" json:"rejectionReason,omitempty"`
+
 	CreatedAt time.Time      `gorm:"autoCreateTime" json:"createdAt"`
 	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
@@ -48,3 +69,9 @@ type ServiceProviderProfile struct {
 func (ServiceProviderProfile) TableName() string {
 	return "service_provider_profiles"
 }
+
+// CanReceiveOrders reports whether the provider has been approved by an admin
+// and may be shown or assigned orders.
+func (p *ServiceProviderProfile) CanReceiveOrders() bool {
+	return p.Status == SPStatusActive && p.IsVerified
+}
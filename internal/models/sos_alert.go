@@ -22,6 +22,18 @@ type SOSAlert struct {
     ResolvedAt              *time.Time     `json:"resolvedAt,omitempty"`
     ResolvedBy              *string        `gorm:"type:uuid" json:"resolvedBy,omitempty"`
     Notes                   string         `gorm:"type:text" json:"notes,omitempty"`
+
+    // Ride incidents record who raised them and where both parties were last
+    // seen, so the safety team is not relying on one device's location.
+    ReporterRole            string         `gorm:"type:varchar(20)" json:"reporterRole,omitempty"`
+    RiderLatitude           *float64       `gorm:"type:decimal(10,8)" json:"riderLatitude,omitempty"`
+    RiderLongitude          *float64       `gorm:"type:decimal(11,8)" json:"riderLongitude,omitempty"`
+    RiderLocationSource     string         `gorm:"type:varchar(20)" json:"riderLocationSource,omitempty"`
+    DriverLatitude          *float64       `gorm:"type:decimal(10,8)" json:"driverLatitude,omitempty"`
+    DriverLongitude         *float64       `gorm:"type:decimal(11,8)" json:"driverLongitude,omitempty"`
+    DriverLocationAt        *time.Time     `json:"driverLocationAt,omitempty"`
+    AcknowledgedBy          *string        `gorm:"type:uuid" json:"acknowledgedBy,omitempty"`
+    AcknowledgedAt          *time.Time     `json:"acknowledgedAt,omitempty"`
     CreatedAt               time.Time      `gorm:"autoCreateTime" json:"createdAt"`
     UpdatedAt               time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
     DeletedAt               gorm.DeletedAt `gorm:"index" json:"-"`
@@ -30,3 +42,41 @@ type SOSAlert struct {
 func (SOSAlert) TableName() string {
     return "sos_alerts"
 }
+
+// SOS incidents move active -> acknowledged -> responding and close as
+// resolved or false_alarm; the user who raised one can cancel it while open.
+const (
+    SOSStatusActive       = "active"
+    SOSStatusAcknowledged = "acknowledged"
+    SOSStatusResponding   = "responding"
+    SOSStatusResolved     = "resolved"
+    SOSStatusFalseAlarm   = "false_alarm"
+    SOSStatusCancelled    = "cancelled"
+)
+
+// SOSOpenStatuses are the statuses an incident still needs attention in.
+var SOSOpenStatuses = []string{SOSStatusActive, SOSStatusAcknowledged, SOSStatusResponding}
+
+func (a *SOSAlert) IsOpen() bool {
+    for _, status := range SOSOpenStatuses {
+        if a.Status == status {
+            return true
+        }
+    }
+    return false
+}
+
+// SOSIncidentEvent is one entry in an incident's timeline.
+type SOSIncidentEvent struct {
+    ID         string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+    AlertID    string    `gorm:"type:uuid;not null;index" json:"alertId"`
+    ActorID    *string   `gorm:"type:uuid" json:"actorId,omitempty"`
+    FromStatus string    `gorm:"type:varchar(50)" json:"fromStatus,omitempty"`
+    ToStatus   string    `gorm:"type:varchar(50);not null" json:"toStatus"`
+    Note       string    `gorm:"type:text" json:"note,omitempty"`
+    CreatedAt  time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (SOSIncidentEvent) TableName() string {
+    return "sos_incident_events"
+}
@@ -111,3 +111,26 @@ type SurgeHistory struct {
 func (SurgeHistory) TableName() string {
 	return "surge_history"
 }
+
+// SurgeSnapshot is a sampled reading of the combined surge for a geohash cell
+// and vehicle type. The surge engine writes them as it prices so drivers can
+// look back at how surge moved in their area.
+type SurgeSnapshot struct {
+	ID            string `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	Geohash       string `gorm:"type:varchar(20);not null;index:idx_surge_snapshots_geohash_recorded,priority:1" json:"geohash"`
+	VehicleTypeID *string `gorm:"type:uuid" json:"vehicleTypeId,omitempty"`
+
+	Lat float64 `gorm:"type:decimal(10,8)" json:"lat"`
+	Lon float64 `gorm:"type:decimal(11,8)" json:"lon"`
+
+	Multiplier       float64 `gorm:"type:decimal(4,2);not null" json:"multiplier"`
+	TimeMultiplier   float64 `gorm:"type:decimal(4,2)" json:"timeMultiplier"`
+	DemandMultiplier float64 `gorm:"type:decimal(4,2)" json:"demandMultiplier"`
+	Reason           string  `gorm:"type:varchar(50)" json:"reason"`
+
+	RecordedAt time.Time `gorm:"not null;index:idx_surge_snapshots_geohash_recorded,priority:2" json:"recordedAt"`
+}
+
+func (SurgeSnapshot) TableName() string {
+	return "surge_snapshots"
+}
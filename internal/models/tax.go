@@ -0,0 +1,122 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	TaxAppliesToAll          = "all"
+	TaxAppliesToRides        = "rides"
+	TaxAppliesToHomeServices = "home_services"
+)
+
+// TaxRegion is the area a set of tax rates applies to. A region covers the
+// circle around its centre; the default region, if any, covers everything no
+// other region does.
+type TaxRegion struct {
+	ID        string         `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	Code      string         `gorm:"type:varchar(50);not null;uniqueIndex" json:"code"`
+	Name      string         `gorm:"type:varchar(255);not null" json:"name"`
+	CenterLat float64        `gorm:"type:decimal(10,8);default:0" json:"centerLat"`
+	CenterLon float64        `gorm:"type:decimal(11,8);default:0" json:"centerLon"`
+	RadiusKm  float64        `gorm:"type:decimal(8,2);default:0" json:"radiusKm"`
+	IsDefault bool           `gorm:"default:false" json:"isDefault"`
+	IsActive  bool           `gorm:"default:true;index" json:"isActive"`
+	CreatedAt time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+
+	Rates []TaxRate `gorm:"foreignKey:RegionID" json:"rates,omitempty"`
+}
+
+func (TaxRegion) TableName() string {
+	return "tax_regions"
+}
+
+// TaxRate is one tax levied in a region, e.g. VAT, or the state and central
+// halves of GST. All active rates that apply to a product are added together.
+type TaxRate struct {
+	ID             string         `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	RegionID       string         `gorm:"type:uuid;not null;index" json:"regionId"`
+	Code           string         `gorm:"type:varchar(50);not null" json:"code"`
+	Name           string         `gorm:"type:varchar(255);not null" json:"name"`
+	Rate           float64        `gorm:"type:decimal(6,3);not null" json:"rate"`
+	AppliesTo      string         `gorm:"type:varchar(30);not null;default:'all'" json:"appliesTo"`
+	IsActive       bool           `gorm:"default:true;index" json:"isActive"`
+	EffectiveFrom  *time.Time     `json:"effectiveFrom,omitempty"`
+	EffectiveUntil *time.Time     `json:"effectiveUntil,omitempty"`
+	CreatedAt      time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt      time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+	DeletedAt      gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+func (TaxRate) TableName() string {
+	return "tax_rates"
+}
+
+// AppliesAt reports whether the rate is in force for the product at the given time.
+func (r *TaxRate) AppliesAt(product string, at time.Time) bool {
+	if !r.IsActive {
+		return false
+	}
+	if r.AppliesTo != TaxAppliesToAll && r.AppliesTo != product {
+		return false
+	}
+	if r.EffectiveFrom != nil && at.Before(*r.EffectiveFrom) {
+		return false
+	}
+	if r.EffectiveUntil != nil && !at.Before(*r.EffectiveUntil) {
+		return false
+	}
+	return true
+}
+
+// Calculate returns the tax owed on the taxable amount, rounded to the cent.
+func (r *TaxRate) Calculate(taxable float64) float64 {
+	return math.Round(taxable*r.Rate) / 100
+}
+
+// TaxLine is the price snapshot of one tax charged on a ride or order.
+type TaxLine struct {
+	Code          string  `json:"code"`
+	Name          string  `json:"name"`
+	Rate          float64 `json:"rate"`
+	TaxableAmount float64 `json:"taxableAmount"`
+	Amount        float64 `json:"amount"`
+	RegionCode    string  `json:"regionCode,omitempty"`
+}
+
+type TaxLines []TaxLine
+
+func (t TaxLines) Value() (driver.Value, error) {
+	if t == nil {
+		return nil, nil
+	}
+	return json.Marshal(t)
+}
+
+func (t *TaxLines) Scan(value interface{}) error {
+	if value == nil {
+		*t = TaxLines{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, t)
+}
+
+func (t TaxLines) Total() float64 {
+	total := 0.0
+	for _, line := range t {
+		total += line.Amount
+	}
+	return math.Round(total*100) / 100
+}
@@ -31,6 +31,7 @@ type User struct {
 	ID                    string         `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
 	Name                  string         `gorm:"type:varchar(255);not null" json:"name"`
 	Email                 *string        `gorm:"type:varchar(255);uniqueIndex" json:"email,omitempty"`
+	EmailVerifiedAt       *time.Time     `json:"emailVerifiedAt,omitempty"`
 	Phone                 *string        `gorm:"type:varchar(20);uniqueIndex" json:"phone,omitempty"`
 	Gender                *string        `gorm:"type:varchar(10)" json:"gender,omitempty"`
 	DOB                   *time.Time     `json:"dob,omitempty"`
@@ -43,6 +44,7 @@ type User struct {
 	EmergencyContactPhone string         `gorm:"type:varchar(20)" json:"emergencyContactPhone,omitempty"`
 	LastLoginAt           *time.Time     `json:"lastLoginAt,omitempty"`
 	ReferralCode          *string        `gorm:"type:varchar(20);uniqueIndex:,where:referral_code IS NOT NULL" json:"referralCode,omitempty"`
+	Language              string         `gorm:"type:varchar(8);not null;default:'en'" json:"language"`
 	CreatedAt             time.Time      `gorm:"autoCreateTime" json:"createdAt"`
 	UpdatedAt             time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
 	DeletedAt             gorm.DeletedAt `gorm:"index" json:"-"`
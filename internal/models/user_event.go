@@ -0,0 +1,44 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+type UserEventPayload map[string]interface{}
+
+func (p UserEventPayload) Value() (driver.Value, error) {
+	if p == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(p)
+}
+
+func (p *UserEventPayload) Scan(value interface{}) error {
+	if value == nil {
+		*p = UserEventPayload{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, p)
+}
+
+// UserEvent is a persisted copy of a ride or order event pushed to a user over
+// the websocket. The auto-increment ID doubles as the replay cursor.
+type UserEvent struct {
+	ID        int64            `gorm:"primaryKey;autoIncrement" json:"id"`
+	UserID    string           `gorm:"type:uuid;not null;index:idx_user_events_user_id_id,priority:1" json:"userId"`
+	Type      string           `gorm:"type:varchar(64);not null" json:"type"`
+	MessageID string           `gorm:"type:varchar(64)" json:"messageId,omitempty"`
+	Payload   UserEventPayload `gorm:"type:jsonb;not null;default:'{}'" json:"payload"`
+	CreatedAt time.Time        `gorm:"autoCreateTime;index" json:"createdAt"`
+}
+
+func (UserEvent) TableName() string {
+	return "user_events"
+}
@@ -0,0 +1,91 @@
+package models
+
+import "time"
+
+type VehicleInspectionStatus string
+
+const (
+	VehicleInspectionScheduled VehicleInspectionStatus = "scheduled"
+	VehicleInspectionSubmitted VehicleInspectionStatus = "submitted"
+	VehicleInspectionApproved  VehicleInspectionStatus = "approved"
+	VehicleInspectionRejected  VehicleInspectionStatus = "rejected"
+	VehicleInspectionOverdue   VehicleInspectionStatus = "overdue"
+)
+
+// VehicleInspectionOpenStatuses are inspections still waiting on the driver.
+// A vehicle has at most one inspection in one of these statuses or submitted.
+var VehicleInspectionOpenStatuses = []VehicleInspectionStatus{
+	VehicleInspectionScheduled,
+	VehicleInspectionRejected,
+	VehicleInspectionOverdue,
+}
+
+const (
+	VehicleInspectionFileDocument = "document"
+	VehicleInspectionFilePhoto    = "photo"
+)
+
+// VehicleInspection is one periodic roadworthiness check of a driver's
+// vehicle. It is scheduled with a due date, submitted by the driver with the
+// inspection certificate and photos, and approved or rejected by an admin. An
+// approved inspection keeps the vehicle on the road until ValidUntil.
+type VehicleInspection struct {
+	ID          string                  `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	VehicleID   string                  `gorm:"type:uuid;not null;index" json:"vehicleId"`
+	DriverID    string                  `gorm:"type:uuid;not null;index" json:"driverId"`
+	Status      VehicleInspectionStatus `gorm:"type:varchar(20);not null;index" json:"status"`
+	DueAt       *time.Time              `gorm:"index" json:"dueAt,omitempty"`
+	RemindedAt  *time.Time              `json:"remindedAt,omitempty"`
+	InspectedAt *time.Time              `json:"inspectedAt,omitempty"`
+	SubmittedAt *time.Time              `json:"submittedAt,omitempty"`
+	OdometerKm  *int                    `json:"odometerKm,omitempty"`
+	Notes       string                  `gorm:"type:text" json:"notes,omitempty"`
+	ValidUntil  *time.Time              `json:"validUntil,omitempty"`
+	ReviewedBy  *string                 `gorm:"type:uuid" json:"reviewedBy,omitempty"`
+	ReviewNote  string                  `gorm:"type:text" json:"reviewNote,omitempty"`
+	ReviewedAt  *time.Time              `json:"reviewedAt,omitempty"`
+	CreatedAt   time.Time               `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt   time.Time               `gorm:"autoUpdateTime" json:"updatedAt"`
+
+	Vehicle *Vehicle                `gorm:"foreignKey:VehicleID" json:"vehicle,omitempty"`
+	Files   []VehicleInspectionFile `gorm:"foreignKey:InspectionID" json:"files,omitempty"`
+}
+
+func (VehicleInspection) TableName() string {
+	return "vehicle_inspections"
+}
+
+// VehicleInspectionFile is a certificate or photo uploaded with an inspection.
+type VehicleInspectionFile struct {
+	ID             string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	InspectionID   string    `gorm:"type:uuid;not null;index" json:"inspectionId"`
+	Kind           string    `gorm:"type:varchar(20);not null" json:"kind"`
+	FileName       string    `gorm:"type:varchar(255);not null" json:"fileName"`
+	FileURL        string    `gorm:"type:varchar(1000);not null" json:"fileUrl"`
+	FileSize       int64     `gorm:"type:bigint" json:"fileSize"`
+	MimeType       string    `gorm:"type:varchar(50)" json:"mimeType"`
+	ImageKitFileID string    `gorm:"type:varchar(255)" json:"-"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (VehicleInspectionFile) TableName() string {
+	return "vehicle_inspection_files"
+}
+
+// VehicleMaintenanceRecord is servicing a driver logged for their vehicle,
+// such as an oil change or new tyres.
+type VehicleMaintenanceRecord struct {
+	ID          string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	VehicleID   string    `gorm:"type:uuid;not null;index" json:"vehicleId"`
+	DriverID    string    `gorm:"type:uuid;not null;index" json:"driverId"`
+	ServiceType string    `gorm:"type:varchar(50);not null" json:"serviceType"`
+	Description string    `gorm:"type:text" json:"description,omitempty"`
+	OdometerKm  *int      `json:"odometerKm,omitempty"`
+	Cost        float64   `gorm:"type:decimal(10,2);default:0" json:"cost"`
+	PerformedAt time.Time `gorm:"not null" json:"performedAt"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (VehicleMaintenanceRecord) TableName() string {
+	return "vehicle_maintenance_records"
+}
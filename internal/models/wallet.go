@@ -46,6 +46,9 @@ type Wallet struct {
 	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"createdAt"`
 	UpdatedAt       time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
 	FreeRideCredits float64    `gorm:"type:decimal(12,2);not null;default:0.00" json:"freeRideCredits"`
+	// Version is bumped on every write so concurrent updates can detect
+	// each other instead of overwriting.
+	Version int64 `gorm:"not null;default:0" json:"version"`
 
 	User         User                `gorm:"foreignKey:UserID" json:"user,omitempty"`
 	Transactions []WalletTransaction `gorm:"foreignKey:WalletID" json:"transactions,omitempty"`
@@ -65,6 +68,7 @@ type WalletTransaction struct {
 	WalletID      string                 `gorm:"type:uuid;not null;index" json:"walletId"`
 	Type          TransactionType        `gorm:"type:transaction_type;not null" json:"type"`
 	Amount        float64                `gorm:"type:decimal(12,2);not null" json:"amount"`
+	Currency      string                 `gorm:"type:varchar(3)" json:"currency"`
 	BalanceBefore float64                `gorm:"type:decimal(12,2);not null" json:"balanceBefore"`
 	BalanceAfter  float64                `gorm:"type:decimal(12,2);not null" json:"balanceAfter"`
 	Status        TransactionStatus      `gorm:"type:transaction_status;not null;default:'pending'" json:"status"`
@@ -76,6 +80,15 @@ type WalletTransaction struct {
 	ProcessedAt   *time.Time             `json:"processedAt,omitempty"`
 	CreatedAt     time.Time              `gorm:"autoCreateTime" json:"createdAt"`
 
+	// Set when the amount was given in another currency and converted into
+	// the wallet's; ExchangeRate is units of Currency per OriginalCurrency.
+	OriginalAmount   *float64 `gorm:"type:decimal(12,2)" json:"originalAmount,omitempty"`
+	OriginalCurrency *string  `gorm:"type:varchar(3)" json:"originalCurrency,omitempty"`
+	ExchangeRate     *float64 `gorm:"type:decimal(18,8)" json:"exchangeRate,omitempty"`
+
+	// JournalEntryID is the ledger entry that moved the balance.
+	JournalEntryID *string `gorm:"type:uuid" json:"journalEntryId,omitempty"`
+
 	Wallet Wallet `gorm:"foreignKey:WalletID" json:"wallet,omitempty"`
 }
 
@@ -87,12 +100,14 @@ type WalletHold struct {
 	ID            string            `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
 	WalletID      string            `gorm:"type:uuid;not null;index" json:"walletId"`
 	Amount        float64           `gorm:"type:decimal(12,2);not null" json:"amount"`
+	Currency      string            `gorm:"type:varchar(3)" json:"currency"`
 	ReferenceType string            `gorm:"type:varchar(50);not null" json:"referenceType"`
 	ReferenceID   string            `gorm:"type:uuid;not null" json:"referenceId"`
 	Status        TransactionStatus `gorm:"type:transaction_status;not null;default:'held'" json:"status"`
 	ExpiresAt     time.Time         `gorm:"not null" json:"expiresAt"`
 	ReleasedAt    *time.Time        `json:"releasedAt,omitempty"`
 	CreatedAt     time.Time         `gorm:"autoCreateTime" json:"createdAt"`
+	Version       int64             `gorm:"not null;default:0" json:"version"`
 
 	Wallet Wallet `gorm:"foreignKey:WalletID" json:"wallet,omitempty"`
 }
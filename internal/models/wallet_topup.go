@@ -0,0 +1,86 @@
+package models
+
+import "time"
+
+type TopUpStatus string
+
+const (
+	TopUpStatusPending   TopUpStatus = "pending"
+	TopUpStatusSucceeded TopUpStatus = "succeeded"
+	TopUpStatusFailed    TopUpStatus = "failed"
+	TopUpStatusCanceled  TopUpStatus = "canceled"
+)
+
+// WalletTopUp tracks one gateway payment that adds money to a wallet. The
+// wallet is credited at most once, when the gateway confirms the payment.
+type WalletTopUp struct {
+	ID              string      `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	UserID          string      `gorm:"type:uuid;not null;index;uniqueIndex:idx_wallet_topups_user_idempotency,priority:1" json:"userId"`
+	WalletID        string      `gorm:"type:uuid;not null;index" json:"walletId"`
+	Gateway         string      `gorm:"type:varchar(30);not null" json:"gateway"`
+	PaymentIntentID string      `gorm:"type:varchar(255);uniqueIndex" json:"paymentIntentId"`
+	IdempotencyKey  string      `gorm:"type:varchar(255);not null;uniqueIndex:idx_wallet_topups_user_idempotency,priority:2" json:"-"`
+	Amount          float64     `gorm:"type:decimal(12,2);not null" json:"amount"`
+	Currency        string      `gorm:"type:varchar(3);not null" json:"currency"`
+	Status          TopUpStatus `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	PaymentMethodID string      `gorm:"type:varchar(255)" json:"paymentMethodId,omitempty"`
+	SaveMethod      bool        `gorm:"default:false" json:"saveMethod"`
+	TransactionID   *string     `gorm:"type:uuid" json:"transactionId,omitempty"`
+	FailureReason   string      `gorm:"type:text" json:"failureReason,omitempty"`
+	CreditedAt      *time.Time  `json:"creditedAt,omitempty"`
+	CreatedAt       time.Time   `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt       time.Time   `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (WalletTopUp) TableName() string {
+	return "wallet_topups"
+}
+
+// PaymentCustomer maps a user to their customer record at the gateway, which
+// owns the user's saved cards.
+type PaymentCustomer struct {
+	ID         string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	UserID     string    `gorm:"type:uuid;not null;uniqueIndex:idx_payment_customers_user_gateway,priority:1" json:"userId"`
+	Gateway    string    `gorm:"type:varchar(30);not null;uniqueIndex:idx_payment_customers_user_gateway,priority:2" json:"gateway"`
+	CustomerID string    `gorm:"type:varchar(255);not null" json:"customerId"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (PaymentCustomer) TableName() string {
+	return "payment_customers"
+}
+
+type SavedPaymentMethod struct {
+	ID              string    `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	UserID          string    `gorm:"type:uuid;not null;index" json:"userId"`
+	Gateway         string    `gorm:"type:varchar(30);not null" json:"gateway"`
+	PaymentMethodID string    `gorm:"type:varchar(255);not null;uniqueIndex" json:"paymentMethodId"`
+	Brand           string    `gorm:"type:varchar(30)" json:"brand"`
+	Last4           string    `gorm:"type:varchar(4)" json:"last4"`
+	ExpMonth        int       `json:"expMonth"`
+	ExpYear         int       `json:"expYear"`
+	IsDefault       bool      `gorm:"default:false" json:"isDefault"`
+	CreatedAt       time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (SavedPaymentMethod) TableName() string {
+	return "saved_payment_methods"
+}
+
+// PaymentWebhookEvent records every gateway event received, so redelivered
+// events are acknowledged without being processed twice.
+type PaymentWebhookEvent struct {
+	ID          string     `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	Gateway     string     `gorm:"type:varchar(30);not null" json:"gateway"`
+	EventID     string     `gorm:"type:varchar(255);not null;uniqueIndex" json:"eventId"`
+	EventType   string     `gorm:"type:varchar(100);not null;index" json:"eventType"`
+	ObjectID    string     `gorm:"type:varchar(255);index" json:"objectId"`
+	Status      string     `gorm:"type:varchar(20);not null" json:"status"`
+	Error       string     `gorm:"type:text" json:"error,omitempty"`
+	ProcessedAt *time.Time `json:"processedAt,omitempty"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+func (PaymentWebhookEvent) TableName() string {
+	return "payment_webhook_events"
+}
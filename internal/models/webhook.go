@@ -0,0 +1,96 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Events webhook subscriptions can receive.
+const (
+	WebhookEventRideCompleted            = "ride.completed"
+	WebhookEventOrderAssigned            = "order.assigned"
+	WebhookEventOrderCancelled           = "order.cancelled"
+	WebhookEventWalletTransactionCreated = "wallet.transaction.created"
+)
+
+var WebhookEventTypes = []string{WebhookEventRideCompleted, WebhookEventOrderAssigned, WebhookEventOrderCancelled, WebhookEventWalletTransactionCreated}
+
+// WebhookSubscription sends the events it lists to URL, signed with Secret.
+type WebhookSubscription struct {
+	ID          string         `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	Name        string         `gorm:"type:varchar(100);not null" json:"name"`
+	URL         string         `gorm:"type:varchar(2048);not null" json:"url"`
+	Secret      string         `gorm:"type:varchar(100);not null" json:"-"`
+	EventTypes  pq.StringArray `gorm:"type:text[];not null;default:'{}'" json:"eventTypes"`
+	Active      bool           `gorm:"not null;default:true" json:"active"`
+	Description string         `gorm:"type:text" json:"description,omitempty"`
+	CreatedBy   string         `gorm:"type:uuid;not null" json:"createdBy"`
+	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt   time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+type WebhookPayload map[string]interface{}
+
+func (p WebhookPayload) Value() (driver.Value, error) {
+	if p == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(p)
+}
+
+func (p *WebhookPayload) Scan(value interface{}) error {
+	if value == nil {
+		*p = WebhookPayload{}
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, p)
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	// WebhookDeliveryDead deliveries ran out of attempts. They stay on the
+	// dead-letter list until an admin retries them.
+	WebhookDeliveryDead WebhookDeliveryStatus = "dead"
+)
+
+// WebhookDelivery is one event on its way to one subscription. Every
+// subscription gets its own delivery of an event, all sharing EventID so
+// receivers can drop duplicates.
+type WebhookDelivery struct {
+	ID             string                `gorm:"type:uuid;primaryKey;default:uuid_generate_v4()" json:"id"`
+	SubscriptionID string                `gorm:"type:uuid;not null;index" json:"subscriptionId"`
+	EventID        string                `gorm:"type:uuid;not null" json:"eventId"`
+	EventType      string                `gorm:"type:varchar(64);not null" json:"eventType"`
+	Payload        WebhookPayload        `gorm:"type:jsonb;not null;default:'{}'" json:"payload"`
+	OccurredAt     time.Time             `gorm:"not null" json:"occurredAt"`
+	Status         WebhookDeliveryStatus `gorm:"type:varchar(20);not null;default:'pending'" json:"status"`
+	Attempts       int                   `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  time.Time             `gorm:"not null" json:"nextAttemptAt"`
+	LastAttemptAt  *time.Time            `json:"lastAttemptAt,omitempty"`
+	LastStatusCode *int                  `json:"lastStatusCode,omitempty"`
+	LastError      string                `gorm:"type:text" json:"lastError,omitempty"`
+	DeliveredAt    *time.Time            `json:"deliveredAt,omitempty"`
+	CreatedAt      time.Time             `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt      time.Time             `gorm:"autoUpdateTime" json:"updatedAt"`
+
+	Subscription *WebhookSubscription `gorm:"foreignKey:SubscriptionID" json:"subscription,omitempty"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
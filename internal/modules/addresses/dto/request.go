@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	LabelHome   = "home"
+	LabelWork   = "work"
+	LabelCustom = "custom"
+)
+
+// CreateAddressRequest saves an address. Coordinates may be left out when a
+// geocoder is configured, in which case the address text is looked up.
+type CreateAddressRequest struct {
+	Label        string   `json:"label" binding:"required,oneof=home work custom"`
+	CustomName   string   `json:"customName" binding:"omitempty,max=100"`
+	Address      string   `json:"address" binding:"required,max=500"`
+	Details      string   `json:"details" binding:"omitempty,max=255"`
+	Instructions string   `json:"instructions" binding:"omitempty,max=1000"`
+	Latitude     *float64 `json:"latitude" binding:"omitempty,latitude"`
+	Longitude    *float64 `json:"longitude" binding:"omitempty,longitude"`
+	IsDefault    bool     `json:"isDefault"`
+}
+
+func (r *CreateAddressRequest) Validate() error {
+	r.Address = strings.TrimSpace(r.Address)
+	r.CustomName = strings.TrimSpace(r.CustomName)
+	if r.Address == "" {
+		return errors.New("address is required")
+	}
+	if r.Label == LabelCustom && r.CustomName == "" {
+		return errors.New("custom name is required for custom addresses")
+	}
+	if (r.Latitude == nil) != (r.Longitude == nil) {
+		return errors.New("latitude and longitude must be given together")
+	}
+	return nil
+}
+
+// UpdateAddressRequest changes only the fields that are set. Changing the
+// address text without new coordinates geocodes it again.
+type UpdateAddressRequest struct {
+	Label        *string  `json:"label" binding:"omitempty,oneof=home work custom"`
+	CustomName   *string  `json:"customName" binding:"omitempty,max=100"`
+	Address      *string  `json:"address" binding:"omitempty,max=500"`
+	Details      *string  `json:"details" binding:"omitempty,max=255"`
+	Instructions *string  `json:"instructions" binding:"omitempty,max=1000"`
+	Latitude     *float64 `json:"latitude" binding:"omitempty,latitude"`
+	Longitude    *float64 `json:"longitude" binding:"omitempty,longitude"`
+}
+
+func (r *UpdateAddressRequest) Validate() error {
+	if r.Address != nil {
+		trimmed := strings.TrimSpace(*r.Address)
+		if trimmed == "" {
+			return errors.New("address cannot be empty")
+		}
+		r.Address = &trimmed
+	}
+	if (r.Latitude == nil) != (r.Longitude == nil) {
+		return errors.New("latitude and longitude must be given together")
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+type AddressResponse struct {
+	ID           string    `json:"id"`
+	Label        string    `json:"label"`
+	CustomName   string    `json:"customName,omitempty"`
+	Address      string    `json:"address"`
+	Details      string    `json:"details,omitempty"`
+	Instructions string    `json:"instructions,omitempty"`
+	Latitude     float64   `json:"latitude"`
+	Longitude    float64   `json:"longitude"`
+	Geocoded     bool      `json:"geocoded"`
+	IsDefault    bool      `json:"isDefault"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+func ToAddressResponse(loc *models.SavedLocation) *AddressResponse {
+	return &AddressResponse{
+		ID:           loc.ID,
+		Label:        loc.Label,
+		CustomName:   loc.CustomName,
+		Address:      loc.Address,
+		Details:      loc.Details,
+		Instructions: loc.Instructions,
+		Latitude:     loc.Latitude,
+		Longitude:    loc.Longitude,
+		Geocoded:     loc.Geocoded,
+		IsDefault:    loc.IsDefault,
+		CreatedAt:    loc.CreatedAt,
+		UpdatedAt:    loc.UpdatedAt,
+	}
+}
+
+func ToAddressResponses(locs []*models.SavedLocation) []*AddressResponse {
+	result := make([]*AddressResponse, len(locs))
+	for i, loc := range locs {
+		result[i] = ToAddressResponse(loc)
+	}
+	return result
+}
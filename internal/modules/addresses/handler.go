@@ -0,0 +1,170 @@
+package addresses
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/modules/addresses/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// ListAddresses godoc
+// @Summary List saved addresses
+// @Description The default address comes first
+// @Tags addresses
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=[]dto.AddressResponse}
+// @Router /addresses [get]
+func (h *Handler) ListAddresses(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	addresses, err := h.service.ListAddresses(c.Request.Context(), userID.(string))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, addresses, "Addresses retrieved successfully")
+}
+
+// CreateAddress godoc
+// @Summary Save an address
+// @Description Latitude and longitude may be left out to geocode the address. The first saved address becomes the default
+// @Tags addresses
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateAddressRequest true "Address"
+// @Success 200 {object} response.Response{data=dto.AddressResponse}
+// @Router /addresses [post]
+func (h *Handler) CreateAddress(c *gin.Context) {
+	var req dto.CreateAddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+
+	address, err := h.service.CreateAddress(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, address, "Address saved successfully")
+}
+
+// GetDefaultAddress godoc
+// @Summary Get the default pickup address
+// @Tags addresses
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=dto.AddressResponse}
+// @Router /addresses/default [get]
+func (h *Handler) GetDefaultAddress(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	address, err := h.service.GetDefaultAddress(c.Request.Context(), userID.(string))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, address, "Default address retrieved successfully")
+}
+
+// GetAddress godoc
+// @Summary Get a saved address
+// @Tags addresses
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Address ID"
+// @Success 200 {object} response.Response{data=dto.AddressResponse}
+// @Router /addresses/{id} [get]
+func (h *Handler) GetAddress(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	address, err := h.service.GetAddress(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, address, "Address retrieved successfully")
+}
+
+// UpdateAddress godoc
+// @Summary Update a saved address
+// @Description Changing the address without new coordinates geocodes it again
+// @Tags addresses
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Address ID"
+// @Param request body dto.UpdateAddressRequest true "Fields to change"
+// @Success 200 {object} response.Response{data=dto.AddressResponse}
+// @Router /addresses/{id} [put]
+func (h *Handler) UpdateAddress(c *gin.Context) {
+	var req dto.UpdateAddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+
+	address, err := h.service.UpdateAddress(c.Request.Context(), userID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, address, "Address updated successfully")
+}
+
+// DeleteAddress godoc
+// @Summary Delete a saved address
+// @Tags addresses
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Address ID"
+// @Success 200 {object} response.Response
+// @Router /addresses/{id} [delete]
+func (h *Handler) DeleteAddress(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	if err := h.service.DeleteAddress(c.Request.Context(), userID.(string), c.Param("id")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, nil, "Address deleted successfully")
+}
+
+// SetDefaultAddress godoc
+// @Summary Make an address the default pickup address
+// @Tags addresses
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Address ID"
+// @Success 200 {object} response.Response{data=dto.AddressResponse}
+// @Router /addresses/{id}/default [post]
+func (h *Handler) SetDefaultAddress(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	address, err := h.service.SetDefaultAddress(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, address, "Default address updated successfully")
+}
@@ -0,0 +1,118 @@
+package addresses
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Create(ctx context.Context, address *models.SavedLocation) error
+	Update(ctx context.Context, address *models.SavedLocation) error
+	Delete(ctx context.Context, userID, addressID string) error
+	GetByID(ctx context.Context, userID, addressID string) (*models.SavedLocation, error)
+	GetDefault(ctx context.Context, userID string) (*models.SavedLocation, error)
+	ListByUser(ctx context.Context, userID string) ([]*models.SavedLocation, error)
+	CountByUser(ctx context.Context, userID string) (int64, error)
+	FindByLabel(ctx context.Context, userID, label string) (*models.SavedLocation, error)
+	SetDefault(ctx context.Context, userID, addressID string) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// Create saves the address and, when it is the default, clears the flag on
+// the user's other addresses in the same transaction.
+func (r *repository) Create(ctx context.Context, address *models.SavedLocation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if address.IsDefault {
+			if err := tx.Model(&models.SavedLocation{}).
+				Where("user_id = ? AND is_default = ?", address.UserID, true).
+				Update("is_default", false).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(address).Error
+	})
+}
+
+func (r *repository) Update(ctx context.Context, address *models.SavedLocation) error {
+	return r.db.WithContext(ctx).Omit("User").Save(address).Error
+}
+
+func (r *repository) Delete(ctx context.Context, userID, addressID string) error {
+	return r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", addressID, userID).
+		Delete(&models.SavedLocation{}).Error
+}
+
+func (r *repository) GetByID(ctx context.Context, userID, addressID string) (*models.SavedLocation, error) {
+	var address models.SavedLocation
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", addressID, userID).
+		First(&address).Error
+	if err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+func (r *repository) GetDefault(ctx context.Context, userID string) (*models.SavedLocation, error) {
+	var address models.SavedLocation
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND is_default = ?", userID, true).
+		Order("updated_at DESC").
+		First(&address).Error
+	if err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+func (r *repository) ListByUser(ctx context.Context, userID string) ([]*models.SavedLocation, error) {
+	var addresses []*models.SavedLocation
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("is_default DESC, created_at ASC").
+		Find(&addresses).Error
+	return addresses, err
+}
+
+func (r *repository) CountByUser(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.SavedLocation{}).
+		Where("user_id = ?", userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *repository) FindByLabel(ctx context.Context, userID, label string) (*models.SavedLocation, error) {
+	var address models.SavedLocation
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND label = ?", userID, label).
+		First(&address).Error
+	if err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+func (r *repository) SetDefault(ctx context.Context, userID, addressID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.SavedLocation{}).
+			Where("user_id = ? AND is_default = ? AND id <> ?", userID, true, addressID).
+			Update("is_default", false).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.SavedLocation{}).
+			Where("id = ? AND user_id = ?", addressID, userID).
+			Update("is_default", true).Error
+	})
+}
@@ -0,0 +1,18 @@
+package addresses
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
+	addresses := router.Group("/addresses", authMiddleware)
+	{
+		addresses.GET("", handler.ListAddresses)
+		addresses.POST("", handler.CreateAddress)
+		addresses.GET("/default", handler.GetDefaultAddress)
+		addresses.GET("/:id", handler.GetAddress)
+		addresses.PUT("/:id", handler.UpdateAddress)
+		addresses.DELETE("/:id", handler.DeleteAddress)
+		addresses.POST("/:id/default", handler.SetDefaultAddress)
+	}
+}
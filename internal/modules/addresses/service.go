@@ -0,0 +1,259 @@
+package addresses
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/addresses/dto"
+	"github.com/umar5678/go-backend/internal/services/geocoding"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+const maxAddressesPerUser = 20
+
+// Geocoder looks up the coordinates of an address saved without them.
+type Geocoder interface {
+	Geocode(ctx context.Context, address string) (*geocoding.Place, error)
+}
+
+type Service interface {
+	ListAddresses(ctx context.Context, userID string) ([]*dto.AddressResponse, error)
+	GetAddress(ctx context.Context, userID, addressID string) (*dto.AddressResponse, error)
+	GetDefaultAddress(ctx context.Context, userID string) (*dto.AddressResponse, error)
+	CreateAddress(ctx context.Context, userID string, req dto.CreateAddressRequest) (*dto.AddressResponse, error)
+	UpdateAddress(ctx context.Context, userID, addressID string, req dto.UpdateAddressRequest) (*dto.AddressResponse, error)
+	DeleteAddress(ctx context.Context, userID, addressID string) error
+	SetDefaultAddress(ctx context.Context, userID, addressID string) (*dto.AddressResponse, error)
+
+	// ResolveAddress returns one of the user's saved addresses, for orders
+	// and rides that reference it by ID instead of repeating it.
+	ResolveAddress(ctx context.Context, userID, addressID string) (*models.SavedLocation, error)
+
+	SetGeocoder(geocoder Geocoder)
+}
+
+type service struct {
+	repo     Repository
+	geocoder Geocoder
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) SetGeocoder(geocoder Geocoder) {
+	s.geocoder = geocoder
+}
+
+func (s *service) ListAddresses(ctx context.Context, userID string) ([]*dto.AddressResponse, error) {
+	addresses, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to list addresses", err)
+	}
+	return dto.ToAddressResponses(addresses), nil
+}
+
+func (s *service) GetAddress(ctx context.Context, userID, addressID string) (*dto.AddressResponse, error) {
+	address, err := s.ResolveAddress(ctx, userID, addressID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToAddressResponse(address), nil
+}
+
+func (s *service) GetDefaultAddress(ctx context.Context, userID string) (*dto.AddressResponse, error) {
+	address, err := s.repo.GetDefault(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Default address")
+		}
+		return nil, response.InternalServerError("Failed to get default address", err)
+	}
+	return dto.ToAddressResponse(address), nil
+}
+
+func (s *service) ResolveAddress(ctx context.Context, userID, addressID string) (*models.SavedLocation, error) {
+	address, err := s.repo.GetByID(ctx, userID, addressID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Address")
+		}
+		return nil, response.InternalServerError("Failed to get address", err)
+	}
+	return address, nil
+}
+
+// CreateAddress saves a new address. A user has at most one home and one
+// work address, and their first address becomes the default pickup address.
+func (s *service) CreateAddress(ctx context.Context, userID string, req dto.CreateAddressRequest) (*dto.AddressResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	count, err := s.repo.CountByUser(ctx, userID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to save address", err)
+	}
+	if count >= maxAddressesPerUser {
+		return nil, response.BadRequest(fmt.Sprintf("You can save at most %d addresses", maxAddressesPerUser))
+	}
+	if err := s.checkLabelFree(ctx, userID, req.Label, ""); err != nil {
+		return nil, err
+	}
+
+	address := &models.SavedLocation{
+		UserID:       userID,
+		Label:        req.Label,
+		Address:      req.Address,
+		Details:      req.Details,
+		Instructions: req.Instructions,
+		IsDefault:    req.IsDefault || count == 0,
+	}
+	if req.Label == dto.LabelCustom {
+		address.CustomName = req.CustomName
+	}
+	if req.Latitude != nil {
+		address.Latitude, address.Longitude = *req.Latitude, *req.Longitude
+	} else if err := s.geocode(ctx, address); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, address); err != nil {
+		logger.Error("failed to save address", "error", err, "userID", userID)
+		return nil, response.InternalServerError("Failed to save address", err)
+	}
+
+	logger.Info("address saved", "userID", userID, "addressID", address.ID, "label", address.Label)
+	return dto.ToAddressResponse(address), nil
+}
+
+func (s *service) UpdateAddress(ctx context.Context, userID, addressID string, req dto.UpdateAddressRequest) (*dto.AddressResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	address, err := s.ResolveAddress(ctx, userID, addressID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Label != nil && *req.Label != address.Label {
+		if err := s.checkLabelFree(ctx, userID, *req.Label, address.ID); err != nil {
+			return nil, err
+		}
+		address.Label = *req.Label
+	}
+	if req.CustomName != nil {
+		address.CustomName = *req.CustomName
+	}
+	if address.Label != dto.LabelCustom {
+		address.CustomName = ""
+	} else if address.CustomName == "" {
+		return nil, response.BadRequest("custom name is required for custom addresses")
+	}
+	if req.Details != nil {
+		address.Details = *req.Details
+	}
+	if req.Instructions != nil {
+		address.Instructions = *req.Instructions
+	}
+
+	addressChanged := req.Address != nil && *req.Address != address.Address
+	if req.Address != nil {
+		address.Address = *req.Address
+	}
+	if req.Latitude != nil {
+		address.Latitude, address.Longitude = *req.Latitude, *req.Longitude
+		address.Geocoded = false
+	} else if addressChanged {
+		if err := s.geocode(ctx, address); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.Update(ctx, address); err != nil {
+		logger.Error("failed to update address", "error", err, "addressID", addressID)
+		return nil, response.InternalServerError("Failed to update address", err)
+	}
+
+	return dto.ToAddressResponse(address), nil
+}
+
+// DeleteAddress removes an address. When it was the default, the oldest
+// remaining address takes its place.
+func (s *service) DeleteAddress(ctx context.Context, userID, addressID string) error {
+	address, err := s.ResolveAddress(ctx, userID, addressID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, userID, address.ID); err != nil {
+		return response.InternalServerError("Failed to delete address", err)
+	}
+
+	if address.IsDefault {
+		remaining, err := s.repo.ListByUser(ctx, userID)
+		if err == nil && len(remaining) > 0 {
+			if err := s.repo.SetDefault(ctx, userID, remaining[0].ID); err != nil {
+				logger.Warn("failed to move default address", "error", err, "userID", userID)
+			}
+		}
+	}
+
+	logger.Info("address deleted", "userID", userID, "addressID", addressID)
+	return nil
+}
+
+func (s *service) SetDefaultAddress(ctx context.Context, userID, addressID string) (*dto.AddressResponse, error) {
+	address, err := s.ResolveAddress(ctx, userID, addressID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !address.IsDefault {
+		if err := s.repo.SetDefault(ctx, userID, address.ID); err != nil {
+			return nil, response.InternalServerError("Failed to set default address", err)
+		}
+		address.IsDefault = true
+	}
+
+	return dto.ToAddressResponse(address), nil
+}
+
+func (s *service) checkLabelFree(ctx context.Context, userID, label, exceptID string) error {
+	if label == dto.LabelCustom {
+		return nil
+	}
+	existing, err := s.repo.FindByLabel(ctx, userID, label)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return response.InternalServerError("Failed to save address", err)
+	}
+	if existing.ID != exceptID {
+		return response.ConflictError(fmt.Sprintf("You already have a %s address", label))
+	}
+	return nil
+}
+
+// geocode fills in the coordinates of an address given without them.
+func (s *service) geocode(ctx context.Context, address *models.SavedLocation) error {
+	if s.geocoder == nil {
+		return response.BadRequest("latitude and longitude are required")
+	}
+
+	result, err := s.geocoder.Geocode(ctx, address.Address)
+	if err != nil {
+		logger.Warn("failed to geocode address", "error", err, "userID", address.UserID)
+		return response.BadRequest("Could not find this address, please pick it on the map")
+	}
+
+	address.Latitude, address.Longitude = result.Latitude, result.Longitude
+	address.Geocoded = true
+	return nil
+}
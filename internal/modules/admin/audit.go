@@ -0,0 +1,28 @@
+package admin
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/audit"
+)
+
+func (s *service) SetAuditLogger(auditLogger audit.Recorder) {
+	s.auditLogger = auditLogger
+}
+
+func (s *service) recordAudit(ctx context.Context, adminID, action, entityType, entityID string, before, after map[string]interface{}, reason string) {
+	if s.auditLogger == nil {
+		return
+	}
+	s.auditLogger.Record(ctx, audit.Entry{
+		ActorID:    adminID,
+		ActorRole:  string(models.RoleAdmin),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     before,
+		After:      after,
+		Reason:     reason,
+	})
+}
@@ -0,0 +1,533 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	dto "github.com/umar5678/go-backend/internal/modules/admin/dto"
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/services/livemetrics"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+// Stuck-entity doctor: each check detects one class of inconsistency and,
+// when run in fix mode, repairs it with the same writes the normal flow would
+// have made. Dry runs only report.
+const (
+	DoctorCheckCapturedHolds     = "rides_with_captured_hold"
+	DoctorCheckBusyDrivers       = "drivers_busy_without_ride"
+	DoctorCheckInactiveProviders = "orders_with_inactive_provider"
+	DoctorCheckCacheMismatch     = "cache_db_mismatch"
+
+	doctorModeDryRun = "dry_run"
+	doctorModeFix    = "fix"
+
+	doctorDefaultLimit = 100
+	doctorMaxLimit     = 1000
+
+	doctorReportKey = "admin:doctor:last_report"
+	doctorReportTTL = 7 * 24 * time.Hour
+)
+
+type doctorCheck struct {
+	info dto.DoctorCheckInfo
+	run  func(s *service, ctx context.Context, run *doctorRun) (*dto.DoctorCheckResult, error)
+}
+
+type doctorRun struct {
+	adminID string
+	fix     bool
+	limit   int
+}
+
+// Checks run in this order so a ride closed by the captured-hold check frees
+// its driver before the busy-driver check looks at it.
+var doctorChecks = []doctorCheck{
+	{
+		info: dto.DoctorCheckInfo{
+			Name:        DoctorCheckCapturedHolds,
+			Description: "Rides still searching/accepted/arrived/started whose wallet hold was already captured",
+			Fixable:     true,
+		},
+		run: (*service).doctorCapturedHolds,
+	},
+	{
+		info: dto.DoctorCheckInfo{
+			Name:        DoctorCheckBusyDrivers,
+			Description: "Drivers marked busy/on_trip with no accepted, arrived or started ride",
+			Fixable:     true,
+		},
+		run: (*service).doctorBusyDrivers,
+	},
+	{
+		info: dto.DoctorCheckInfo{
+			Name:        DoctorCheckInactiveProviders,
+			Description: "Assigned, accepted or in-progress orders whose provider is suspended or banned",
+			Fixable:     true,
+		},
+		run: (*service).doctorInactiveProviders,
+	},
+	{
+		info: dto.DoctorCheckInfo{
+			Name:        DoctorCheckCacheMismatch,
+			Description: "Driver online/busy and active ride cache entries that disagree with the database",
+			Fixable:     true,
+		},
+		run: (*service).doctorCacheMismatch,
+	},
+}
+
+func (s *service) ListDoctorChecks() []dto.DoctorCheckInfo {
+	checks := make([]dto.DoctorCheckInfo, 0, len(doctorChecks))
+	for _, check := range doctorChecks {
+		checks = append(checks, check.info)
+	}
+	return checks
+}
+
+func (s *service) RunDoctor(ctx context.Context, adminID string, req dto.RunDoctorRequest) (*dto.DoctorReport, error) {
+	selected := make(map[string]bool, len(req.Checks))
+	for _, name := range req.Checks {
+		selected[name] = true
+	}
+	for name := range selected {
+		if !isDoctorCheck(name) {
+			return nil, response.BadRequest(fmt.Sprintf("Unknown doctor check '%s'", name))
+		}
+	}
+
+	run := &doctorRun{adminID: adminID, fix: req.Fix, limit: req.Limit}
+	if run.limit <= 0 {
+		run.limit = doctorDefaultLimit
+	}
+	if run.limit > doctorMaxLimit {
+		run.limit = doctorMaxLimit
+	}
+
+	report := &dto.DoctorReport{
+		ID:        uuid.New().String(),
+		Mode:      doctorModeDryRun,
+		RunBy:     adminID,
+		StartedAt: time.Now().UTC(),
+		Checks:    make([]dto.DoctorCheckResult, 0, len(doctorChecks)),
+	}
+	if run.fix {
+		report.Mode = doctorModeFix
+	}
+
+	for _, check := range doctorChecks {
+		if len(selected) > 0 && !selected[check.info.Name] {
+			continue
+		}
+
+		result, err := check.run(s, ctx, run)
+		if err != nil {
+			logger.Error("doctor check failed", "error", err, "check", check.info.Name)
+			result = &dto.DoctorCheckResult{Error: err.Error()}
+		}
+		result.Name = check.info.Name
+		if result.Findings == nil {
+			result.Findings = []dto.DoctorFinding{}
+		}
+
+		report.TotalFound += result.Found
+		report.TotalFixed += result.Fixed
+		report.TotalFailed += result.Failed
+		report.Checks = append(report.Checks, *result)
+	}
+
+	report.FinishedAt = time.Now().UTC()
+
+	if err := cache.SetJSON(ctx, doctorReportKey, report, doctorReportTTL); err != nil {
+		logger.Warn("failed to store doctor report", "error", err, "reportId", report.ID)
+	}
+
+	logger.Info("doctor run completed",
+		"reportId", report.ID,
+		"adminId", adminID,
+		"mode", report.Mode,
+		"found", report.TotalFound,
+		"fixed", report.TotalFixed,
+		"failed", report.TotalFailed,
+	)
+
+	return report, nil
+}
+
+func (s *service) GetLastDoctorReport(ctx context.Context) (*dto.DoctorReport, error) {
+	var report dto.DoctorReport
+	if err := cache.GetJSON(ctx, doctorReportKey, &report); err != nil {
+		if err == redis.Nil {
+			return nil, response.NotFoundError("Doctor report")
+		}
+		return nil, response.InternalServerError("Failed to fetch doctor report", err)
+	}
+	return &report, nil
+}
+
+func isDoctorCheck(name string) bool {
+	for _, check := range doctorChecks {
+		if check.info.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// record appends a finding and applies its fix when the run is in fix mode.
+// A nil fix marks the finding as needing manual review.
+func (r *doctorRun) record(result *dto.DoctorCheckResult, finding dto.DoctorFinding, fix func() error) {
+	result.Found++
+
+	if r.fix {
+		switch {
+		case fix == nil:
+			finding.Skipped = true
+		default:
+			if err := fix(); err != nil {
+				finding.Error = err.Error()
+				result.Failed++
+			} else {
+				finding.Fixed = true
+				result.Fixed++
+			}
+		}
+	}
+
+	result.Findings = append(result.Findings, finding)
+}
+
+func (s *service) doctorCapturedHolds(ctx context.Context, run *doctorRun) (*dto.DoctorCheckResult, error) {
+	rows, err := s.repo.FindOpenRidesWithCapturedHold(ctx, run.limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.DoctorCheckResult{}
+	for _, row := range rows {
+		row := row
+		details := map[string]interface{}{
+			"rideStatus": row.RideStatus,
+			"riderId":    row.RiderID,
+			"holdId":     row.HoldID,
+			"holdAmount": row.HoldAmount,
+			"capturedAt": row.CapturedAt,
+		}
+		if row.DriverID != nil {
+			details["driverId"] = *row.DriverID
+		}
+
+		run.record(result, dto.DoctorFinding{
+			EntityType: "ride",
+			EntityID:   row.RideID,
+			Issue:      fmt.Sprintf("wallet hold captured but ride is still %s", row.RideStatus),
+			Details:    details,
+			Action:     "mark ride completed at capture time",
+		}, func() error {
+			updated, err := s.repo.CompleteRideWithCapturedHold(ctx, row.RideID, row.CapturedAt)
+			if err != nil {
+				return err
+			}
+			if !updated {
+				return fmt.Errorf("ride status changed since detection")
+			}
+
+			livemetrics.RideEnded(ctx, row.RideID)
+			cache.Delete(ctx, fmt.Sprintf("ride:active:%s", row.RideID))
+
+			logger.Info("doctor completed ride with captured hold",
+				"rideID", row.RideID,
+				"holdID", row.HoldID,
+				"adminID", run.adminID,
+			)
+			return nil
+		})
+	}
+
+	return result, nil
+}
+
+func (s *service) doctorBusyDrivers(ctx context.Context, run *doctorRun) (*dto.DoctorCheckResult, error) {
+	rows, err := s.repo.FindBusyDriversWithoutRide(ctx, run.limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.DoctorCheckResult{}
+	for _, row := range rows {
+		row := row
+
+		// Drivers still present on a socket go back to online, everyone else offline.
+		target := "offline"
+		if present, _ := cache.SessionClient.SIsMember(ctx, livemetrics.OnlineDriversKey, row.DriverID).Result(); present {
+			target = "online"
+		}
+
+		run.record(result, dto.DoctorFinding{
+			EntityType: "driver",
+			EntityID:   row.DriverID,
+			Issue:      fmt.Sprintf("driver status is %s but no open ride exists", row.Status),
+			Details: map[string]interface{}{
+				"userId":        row.UserID,
+				"status":        row.Status,
+				"statusSinceAt": row.UpdatedAt,
+			},
+			Action: fmt.Sprintf("set driver status to %s and clear busy cache", target),
+		}, func() error {
+			if err := s.drvRepo.UpdateDriverStatus(ctx, row.DriverID, target); err != nil {
+				return err
+			}
+
+			cache.Delete(ctx, fmt.Sprintf("driver:busy:%s", row.DriverID))
+			cache.Delete(ctx, fmt.Sprintf("driver:active:ride:%s", row.DriverID))
+			cache.Delete(ctx, fmt.Sprintf("driver:profile:%s", row.UserID))
+
+			logger.Info("doctor reset stuck driver status",
+				"driverID", row.DriverID,
+				"from", row.Status,
+				"to", target,
+				"adminID", run.adminID,
+			)
+			return nil
+		})
+	}
+
+	return result, nil
+}
+
+func (s *service) doctorInactiveProviders(ctx context.Context, run *doctorRun) (*dto.DoctorCheckResult, error) {
+	rows, err := s.repo.FindOrdersWithInactiveProvider(ctx, run.limit)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dto.DoctorCheckResult{}
+	for _, row := range rows {
+		row := row
+		finding := dto.DoctorFinding{
+			EntityType: "service_order",
+			EntityID:   row.OrderID,
+			Issue:      fmt.Sprintf("order is %s with a %s provider", row.OrderStatus, row.ProviderStatus),
+			Details: map[string]interface{}{
+				"orderNumber":    row.OrderNumber,
+				"orderStatus":    row.OrderStatus,
+				"providerId":     row.ProviderID,
+				"providerStatus": row.ProviderStatus,
+			},
+		}
+
+		// Work already under way is left for an operator to settle with the customer.
+		if row.OrderStatus == "in_progress" {
+			finding.Action = "manual review: work already in progress"
+			run.record(result, finding, nil)
+			continue
+		}
+
+		finding.Action = "unassign provider and return order to searching"
+		run.record(result, finding, func() error {
+			notes := fmt.Sprintf("Provider %s is %s; order returned to search by doctor", row.ProviderID, row.ProviderStatus)
+			released, err := s.repo.ReleaseOrderToSearch(ctx, row.OrderID, row.OrderStatus, row.ProviderID, run.adminID, notes)
+			if err != nil {
+				return err
+			}
+			if !released {
+				return fmt.Errorf("order status changed since detection")
+			}
+
+			livemetrics.OrderSearching(ctx, row.OrderID)
+
+			logger.Info("doctor released order from inactive provider",
+				"orderID", row.OrderID,
+				"providerID", row.ProviderID,
+				"adminID", run.adminID,
+			)
+			return nil
+		})
+	}
+
+	return result, nil
+}
+
+func (s *service) doctorCacheMismatch(ctx context.Context, run *doctorRun) (*dto.DoctorCheckResult, error) {
+	result := &dto.DoctorCheckResult{}
+
+	if err := s.doctorOnlineDriverSet(ctx, run, result); err != nil {
+		return nil, err
+	}
+	if err := s.doctorBusyDriverKeys(ctx, run, result); err != nil {
+		return nil, err
+	}
+	if err := s.doctorActiveRideKeys(ctx, run, result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *service) doctorOnlineDriverSet(ctx context.Context, run *doctorRun, result *dto.DoctorCheckResult) error {
+	members, err := cache.SessionClient.SMembers(ctx, livemetrics.OnlineDriversKey).Result()
+	if err != nil {
+		return err
+	}
+
+	statuses, err := s.repo.FindDriverStatuses(ctx, members)
+	if err != nil {
+		return err
+	}
+
+	for _, driverID := range members {
+		if result.Found >= run.limit {
+			return nil
+		}
+		status, ok := statuses[driverID]
+		if ok && status != "offline" {
+			continue
+		}
+		if !ok {
+			status = "missing"
+		}
+
+		driverID := driverID
+		run.record(result, dto.DoctorFinding{
+			EntityType: "driver",
+			EntityID:   driverID,
+			Issue:      fmt.Sprintf("driver is in the online set but database status is %s", status),
+			Details:    map[string]interface{}{"key": livemetrics.OnlineDriversKey, "dbStatus": status},
+			Action:     "remove driver from online set",
+		}, func() error {
+			return cache.SessionClient.SRem(ctx, livemetrics.OnlineDriversKey, driverID).Err()
+		})
+	}
+
+	onlineIDs, err := s.repo.FindOnlineDriverIDs(ctx)
+	if err != nil {
+		return err
+	}
+
+	inSet := make(map[string]bool, len(members))
+	for _, id := range members {
+		inSet[id] = true
+	}
+
+	for _, driverID := range onlineIDs {
+		if result.Found >= run.limit {
+			return nil
+		}
+		if inSet[driverID] {
+			continue
+		}
+
+		driverID := driverID
+		run.record(result, dto.DoctorFinding{
+			EntityType: "driver",
+			EntityID:   driverID,
+			Issue:      "driver is online in the database but missing from the online set",
+			Details:    map[string]interface{}{"key": livemetrics.OnlineDriversKey, "dbStatus": "online"},
+			Action:     "add driver to online set",
+		}, func() error {
+			return cache.SessionClient.SAdd(ctx, livemetrics.OnlineDriversKey, driverID).Err()
+		})
+	}
+
+	return nil
+}
+
+func (s *service) doctorBusyDriverKeys(ctx context.Context, run *doctorRun, result *dto.DoctorCheckResult) error {
+	keys, ids, err := scanKeyIDs(ctx, "driver:busy:")
+	if err != nil {
+		return err
+	}
+
+	statuses, err := s.repo.FindDriverStatuses(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for i, driverID := range ids {
+		if result.Found >= run.limit {
+			return nil
+		}
+		status := statuses[driverID]
+		if status == "busy" || status == "on_trip" {
+			continue
+		}
+		if status == "" {
+			status = "missing"
+		}
+
+		key := keys[i]
+		run.record(result, dto.DoctorFinding{
+			EntityType: "driver",
+			EntityID:   driverID,
+			Issue:      fmt.Sprintf("busy cache key present but database status is %s", status),
+			Details:    map[string]interface{}{"key": key, "dbStatus": status},
+			Action:     "delete busy cache key",
+		}, func() error {
+			return cache.Delete(ctx, key)
+		})
+	}
+
+	return nil
+}
+
+func (s *service) doctorActiveRideKeys(ctx context.Context, run *doctorRun, result *dto.DoctorCheckResult) error {
+	keys, ids, err := scanKeyIDs(ctx, "ride:active:")
+	if err != nil {
+		return err
+	}
+
+	statuses, err := s.repo.FindRideStatuses(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for i, rideID := range ids {
+		if result.Found >= run.limit {
+			return nil
+		}
+		status := statuses[rideID]
+		if status != "" && status != "completed" && status != "cancelled" {
+			continue
+		}
+		if status == "" {
+			status = "missing"
+		}
+
+		key := keys[i]
+		run.record(result, dto.DoctorFinding{
+			EntityType: "ride",
+			EntityID:   rideID,
+			Issue:      fmt.Sprintf("active ride cache entry present but ride is %s", status),
+			Details:    map[string]interface{}{"key": key, "dbStatus": status},
+			Action:     "delete active ride cache entry",
+		}, func() error {
+			return cache.Delete(ctx, key)
+		})
+	}
+
+	return nil
+}
+
+// scanKeyIDs walks the cache keyspace for prefix* and returns the keys along
+// with the ID suffix of each.
+func scanKeyIDs(ctx context.Context, prefix string) ([]string, []string, error) {
+	var keys, ids []string
+
+	iter := cache.CacheClient.Scan(ctx, 0, prefix+"*", 200).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		id := strings.TrimPrefix(key, prefix)
+		if _, err := uuid.Parse(id); err != nil {
+			continue
+		}
+		keys = append(keys, key)
+		ids = append(ids, id)
+	}
+
+	return keys, ids, iter.Err()
+}
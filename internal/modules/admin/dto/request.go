@@ -9,8 +9,62 @@ type ListUsersQueryParams struct {
 	Limit  string `form:"limit" example:"20"`
 }
 
+// SuspendUserRequest suspends a user. Without a duration the suspension lasts
+// until an admin reinstates the user.
 type SuspendUserRequest struct {
-	Reason string `json:"reason" binding:"required" example:"Violation of terms of service"`
+	Reason        string `json:"reason" binding:"required,max=1000" example:"Violation of terms of service"`
+	DurationHours int    `json:"durationHours" binding:"omitempty,min=1,max=8760" example:"72"`
+}
+
+type BanUserRequest struct {
+	Reason string `json:"reason" binding:"required,max=1000" example:"Repeated fraud"`
+}
+
+type ReinstateUserRequest struct {
+	Reason string `json:"reason" binding:"required,max=1000" example:"Appeal accepted"`
+}
+
+type ListSuspensionsRequest struct {
+	UserID     string `form:"userId" binding:"omitempty,uuid"`
+	Kind       string `form:"kind" binding:"omitempty,oneof=suspension ban"`
+	ActiveOnly bool   `form:"activeOnly"`
+	Page       int    `form:"page" binding:"omitempty,min=1"`
+	Limit      int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (r *ListSuspensionsRequest) SetDefaults() {
+	if r.Page == 0 {
+		r.Page = 1
+	}
+	if r.Limit == 0 {
+		r.Limit = 20
+	}
+}
+
+// StartImpersonationRequest opens an impersonation session. The token is
+// read-only unless AllowWrites is set; without a duration it lasts the
+// configured default.
+type StartImpersonationRequest struct {
+	Reason          string `json:"reason" binding:"required,min=10,max=1000" example:"Ticket #4821: rider cannot see their receipts"`
+	DurationMinutes int    `json:"durationMinutes" binding:"omitempty,min=1,max=1440" example:"15"`
+	AllowWrites     bool   `json:"allowWrites" example:"false"`
+}
+
+type ListImpersonationsRequest struct {
+	AdminID    string `form:"adminId" binding:"omitempty,uuid"`
+	UserID     string `form:"userId" binding:"omitempty,uuid"`
+	ActiveOnly bool   `form:"activeOnly"`
+	Page       int    `form:"page" binding:"omitempty,min=1"`
+	Limit      int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (r *ListImpersonationsRequest) SetDefaults() {
+	if r.Page == 0 {
+		r.Page = 1
+	}
+	if r.Limit == 0 {
+		r.Limit = 20
+	}
 }
 
 type UpdateUserStatusRequest struct {
@@ -21,6 +75,40 @@ type ApproveServiceProviderParams struct {
 	ID string `uri:"id" binding:"required,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
 }
 
+type RejectServiceProviderRequest struct {
+	Reason string `json:"reason" binding:"required" example:"ID document is unreadable"`
+}
+
+type UpdateBackgroundCheckRequest struct {
+	Status    models.BackgroundCheckStatus `json:"status" binding:"required" example:"clear" enums:"pending,clear,flagged,failed"`
+	Reference string                       `json:"reference" example:"BGV-2024-00931"`
+}
+
 type UserIDParams struct {
 	ID string `uri:"id" binding:"required,uuid" example:"550e8400-e29b-41d4-a716-446655440000"`
 }
+
+type RunDoctorRequest struct {
+	Checks []string `json:"checks" example:"drivers_busy_without_ride,orders_with_inactive_provider"`
+	Fix    bool     `json:"fix" example:"false"`
+	Limit  int      `json:"limit" example:"100"`
+}
+
+type DriverCashOwedQuery struct {
+	Days           int  `form:"days" binding:"omitempty,min=1,max=365"`
+	OnlyRestricted bool `form:"onlyRestricted"`
+	Page           int  `form:"page" binding:"omitempty,min=1"`
+	Limit          int  `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (q *DriverCashOwedQuery) SetDefaults() {
+	if q.Days == 0 {
+		q.Days = 30
+	}
+	if q.Page == 0 {
+		q.Page = 1
+	}
+	if q.Limit == 0 {
+		q.Limit = 20
+	}
+}
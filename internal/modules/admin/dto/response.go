@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/serviceproviders"
 )
 
 type UserResponse struct {
@@ -48,10 +49,64 @@ type ApproveServiceProviderResponse struct {
 	UserID     string `json:"userId" example:"660e8400-e29b-41d4-a716-446655440001"`
 }
 
-type SuspendUserResponse struct {
-	Message string `json:"message" example:"User suspended successfully"`
-	UserID  string `json:"userId" example:"550e8400-e29b-41d4-a716-446655440000"`
-	Reason  string `json:"reason" example:"Violation of terms of service"`
+type ProviderOnboardingResponse struct {
+	Onboarding               *serviceproviders.OnboardingStatus `json:"onboarding"`
+	BackgroundCheckReference string                             `json:"backgroundCheckReference,omitempty"`
+	ReviewedBy               *string                            `json:"reviewedBy,omitempty"`
+	Documents                []*models.Document                 `json:"documents"`
+}
+
+type SuspensionResponse struct {
+	ID         string                `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	UserID     string                `json:"userId" example:"660e8400-e29b-41d4-a716-446655440001"`
+	Kind       models.SuspensionKind `json:"kind" example:"suspension" enums:"suspension,ban"`
+	Reason     string                `json:"reason" example:"Violation of terms of service"`
+	Active     bool                  `json:"active" example:"true"`
+	StartsAt   time.Time             `json:"startsAt" example:"2024-01-15T10:30:00Z"`
+	EndsAt     *time.Time            `json:"endsAt,omitempty" example:"2024-01-18T10:30:00Z"`
+	CreatedBy  string                `json:"createdBy" example:"770e8400-e29b-41d4-a716-446655440002"`
+	LiftedAt   *time.Time            `json:"liftedAt,omitempty"`
+	LiftedBy   *string               `json:"liftedBy,omitempty"`
+	LiftReason string                `json:"liftReason,omitempty" example:"expired"`
+	CreatedAt  time.Time             `json:"createdAt" example:"2024-01-15T10:30:00Z"`
+
+	Enforcement *SuspensionEnforcement `json:"enforcement,omitempty"`
+}
+
+// SuspensionEnforcement reports what was done with the user's in-flight rides
+// and orders when the suspension or ban took effect.
+type SuspensionEnforcement struct {
+	CancelledRides   int                    `json:"cancelledRides" example:"1"`
+	ReassignedRides  int                    `json:"reassignedRides" example:"0"`
+	CancelledOrders  int                    `json:"cancelledOrders" example:"0"`
+	ReassignedOrders int                    `json:"reassignedOrders" example:"2"`
+	ReleasedHolds    int                    `json:"releasedHolds" example:"1"`
+	NeedsReview      []SuspensionReviewItem `json:"needsReview"`
+}
+
+// SuspensionReviewItem is work that was already under way and is left for an
+// operator to settle.
+type SuspensionReviewItem struct {
+	EntityType string `json:"entityType" example:"ride"`
+	EntityID   string `json:"entityId" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Status     string `json:"status" example:"started"`
+}
+
+func ToSuspensionResponse(s *models.AccountSuspension) *SuspensionResponse {
+	return &SuspensionResponse{
+		ID:         s.ID,
+		UserID:     s.UserID,
+		Kind:       s.Kind,
+		Reason:     s.Reason,
+		Active:     s.IsActive(time.Now()),
+		StartsAt:   s.StartsAt,
+		EndsAt:     s.EndsAt,
+		CreatedBy:  s.CreatedBy,
+		LiftedAt:   s.LiftedAt,
+		LiftedBy:   s.LiftedBy,
+		LiftReason: s.LiftReason,
+		CreatedAt:  s.CreatedAt,
+	}
 }
 
 type UpdateUserStatusResponse struct {
@@ -59,3 +114,98 @@ type UpdateUserStatusResponse struct {
 	UserID    string            `json:"userId" example:"550e8400-e29b-41d4-a716-446655440000"`
 	NewStatus models.UserStatus `json:"newStatus" example:"active"`
 }
+
+type DoctorCheckInfo struct {
+	Name        string `json:"name" example:"drivers_busy_without_ride"`
+	Description string `json:"description" example:"Drivers marked busy/on_trip with no accepted, arrived or started ride"`
+	Fixable     bool   `json:"fixable" example:"true"`
+}
+
+type DoctorFinding struct {
+	EntityType string                 `json:"entityType" example:"driver"`
+	EntityID   string                 `json:"entityId" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Issue      string                 `json:"issue" example:"driver status is busy but no open ride exists"`
+	Details    map[string]interface{} `json:"details,omitempty"`
+	Action     string                 `json:"action" example:"set driver status to online"`
+	Fixed      bool                   `json:"fixed"`
+	Skipped    bool                   `json:"skipped,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+}
+
+type DoctorCheckResult struct {
+	Name     string          `json:"name" example:"drivers_busy_without_ride"`
+	Found    int             `json:"found" example:"3"`
+	Fixed    int             `json:"fixed" example:"3"`
+	Failed   int             `json:"failed" example:"0"`
+	Error    string          `json:"error,omitempty"`
+	Findings []DoctorFinding `json:"findings"`
+}
+
+type DoctorReport struct {
+	ID          string              `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	Mode        string              `json:"mode" example:"dry_run"`
+	RunBy       string              `json:"runBy" example:"550e8400-e29b-41d4-a716-446655440000"`
+	StartedAt   time.Time           `json:"startedAt"`
+	FinishedAt  time.Time           `json:"finishedAt"`
+	TotalFound  int                 `json:"totalFound" example:"5"`
+	TotalFixed  int                 `json:"totalFixed" example:"0"`
+	TotalFailed int                 `json:"totalFailed" example:"0"`
+	Checks      []DoctorCheckResult `json:"checks"`
+}
+
+// DriverCashOwedResponse is one row of the cash-owed report: a driver whose
+// wallet is negative because the platform's share of cash fares is outstanding.
+type DriverCashOwedResponse struct {
+	DriverID             string     `json:"driverId"`
+	UserID               string     `json:"userId"`
+	Name                 string     `json:"name"`
+	Phone                *string    `json:"phone,omitempty"`
+	AmountOwed           float64    `json:"amountOwed"`
+	WalletBalance        float64    `json:"walletBalance"`
+	Currency             string     `json:"currency"`
+	NegativeBalanceLimit float64    `json:"negativeBalanceLimit"`
+	LimitUsedPercent     float64    `json:"limitUsedPercent"`
+	IsRestricted         bool       `json:"isRestricted"`
+	CashRides            int        `json:"cashRides"`
+	CashCollected        float64    `json:"cashCollected"`
+	Commission           float64    `json:"commission"`
+	LastCashRideAt       *time.Time `json:"lastCashRideAt,omitempty"`
+}
+
+type ImpersonationSessionResponse struct {
+	ID          string     `json:"id" example:"550e8400-e29b-41d4-a716-446655440000"`
+	AdminID     string     `json:"adminId" example:"770e8400-e29b-41d4-a716-446655440002"`
+	UserID      string     `json:"userId" example:"660e8400-e29b-41d4-a716-446655440001"`
+	Reason      string     `json:"reason" example:"Ticket #4821: rider cannot see their receipts"`
+	AllowWrites bool       `json:"allowWrites" example:"false"`
+	Active      bool       `json:"active" example:"true"`
+	ExpiresAt   time.Time  `json:"expiresAt" example:"2024-01-15T10:45:00Z"`
+	EndedAt     *time.Time `json:"endedAt,omitempty"`
+	EndedBy     *string    `json:"endedBy,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt" example:"2024-01-15T10:30:00Z"`
+}
+
+// ImpersonationTokenResponse carries the token to send as the user. It cannot
+// be refreshed or used to open a WebSocket.
+type ImpersonationTokenResponse struct {
+	Session     *ImpersonationSessionResponse `json:"session"`
+	AccessToken string                        `json:"accessToken"`
+	TokenType   string                        `json:"tokenType" example:"Bearer"`
+	ExpiresIn   int                           `json:"expiresIn" example:"900"`
+	ReadOnly    bool                          `json:"readOnly" example:"true"`
+}
+
+func ToImpersonationSessionResponse(s *models.ImpersonationSession) *ImpersonationSessionResponse {
+	return &ImpersonationSessionResponse{
+		ID:          s.ID,
+		AdminID:     s.AdminID,
+		UserID:      s.UserID,
+		Reason:      s.Reason,
+		AllowWrites: s.AllowWrites,
+		Active:      s.IsActive(time.Now()),
+		ExpiresAt:   s.ExpiresAt,
+		EndedAt:     s.EndedAt,
+		EndedBy:     s.EndedBy,
+		CreatedAt:   s.CreatedAt,
+	}
+}
@@ -49,7 +49,7 @@ func (h *Handler) ListUsers(c *gin.Context) {
 
 // ApproveServiceProvider godoc
 // @Summary Approve a service provider (Admin)
-// @Description Approve a pending service provider registration
+// @Description Approve a provider whose documents are verified and background check is clear
 // @Tags Admin routes
 // @Accept json
 // @Produce json
@@ -58,13 +58,15 @@ func (h *Handler) ListUsers(c *gin.Context) {
 // @Failure 400 {object} response.Response "Bad request"
 // @Failure 401 {object} response.Response "Unauthorized"
 // @Failure 404 {object} response.Response "Service provider not found"
+// @Failure 409 {object} response.Response "Onboarding incomplete"
 // @Failure 500 {object} response.Response "Internal server error"
 // @Router /admin/service-providers/{id}/approve [post]
 // @Security BearerAuth
 func (h *Handler) ApproveServiceProvider(c *gin.Context) {
 	providerID := c.Param("id")
+	adminID, _ := c.Get("userID")
 
-	if err := h.service.ApproveServiceProvider(c.Request.Context(), providerID); err != nil {
+	if err := h.service.ApproveServiceProvider(c.Request.Context(), adminID.(string), providerID); err != nil {
 		c.Error(err)
 		return
 	}
@@ -72,18 +74,101 @@ func (h *Handler) ApproveServiceProvider(c *gin.Context) {
 	response.Success(c, nil, "Service provider approved")
 }
 
+// RejectServiceProvider godoc
+// @Summary Reject a service provider application (Admin)
+// @Description Reject a submitted provider application; the provider can fix documents and resubmit
+// @Tags Admin routes
+// @Accept json
+// @Produce json
+// @Param id path string true "Service Provider ID"
+// @Param request body dto.RejectServiceProviderRequest true "Rejection reason"
+// @Success 200 {object} response.Response "Service provider rejected"
+// @Failure 400 {object} response.Response "Bad request"
+// @Failure 404 {object} response.Response "Service provider not found"
+// @Router /admin/service-providers/{id}/reject [post]
+// @Security BearerAuth
+func (h *Handler) RejectServiceProvider(c *gin.Context) {
+	providerID := c.Param("id")
+	adminID, _ := c.Get("userID")
+
+	var req dto.RejectServiceProviderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request"))
+		return
+	}
+
+	if err := h.service.RejectServiceProvider(c.Request.Context(), adminID.(string), providerID, req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, nil, "Service provider rejected")
+}
+
+// UpdateBackgroundCheck godoc
+// @Summary Record background check result (Admin)
+// @Description Record the outcome of a provider's background verification
+// @Tags Admin routes
+// @Accept json
+// @Produce json
+// @Param id path string true "Service Provider ID"
+// @Param request body dto.UpdateBackgroundCheckRequest true "Background check result"
+// @Success 200 {object} response.Response{data=serviceproviders.OnboardingStatus}
+// @Failure 400 {object} response.Response "Bad request"
+// @Failure 404 {object} response.Response "Service provider not found"
+// @Router /admin/service-providers/{id}/background-check [put]
+// @Security BearerAuth
+func (h *Handler) UpdateBackgroundCheck(c *gin.Context) {
+	providerID := c.Param("id")
+
+	var req dto.UpdateBackgroundCheckRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request"))
+		return
+	}
+
+	status, err := h.service.UpdateBackgroundCheck(c.Request.Context(), providerID, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, status, "Background check updated")
+}
+
+// GetProviderOnboarding godoc
+// @Summary Get provider onboarding review (Admin)
+// @Description Onboarding checklist, background check and uploaded documents for a provider
+// @Tags Admin routes
+// @Produce json
+// @Param id path string true "Service Provider ID"
+// @Success 200 {object} response.Response{data=dto.ProviderOnboardingResponse}
+// @Failure 404 {object} response.Response "Service provider not found"
+// @Router /admin/service-providers/{id}/onboarding [get]
+// @Security BearerAuth
+func (h *Handler) GetProviderOnboarding(c *gin.Context) {
+	result, err := h.service.GetProviderOnboarding(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Provider onboarding retrieved")
+}
+
 // SuspendUser godoc
 // @Summary Suspend a user (Admin)
-// @Description Suspend a user account with a reason
+// @Description Suspend a rider, driver or provider with a reason and an optional duration. The user is signed out of the API and WebSocket immediately; their unstarted rides and orders are cancelled with holds released, and rides or orders they were serving go back to matching.
 // @Tags Admin routes
 // @Accept json
 // @Produce json
 // @Param id path string true "User ID"
-// @Param request body dto.SuspendUserRequest true "Suspension reason"
-// @Success 200 {object} response.Response "User suspended successfully"
+// @Param request body dto.SuspendUserRequest true "Suspension reason and duration"
+// @Success 200 {object} response.Response{data=dto.SuspensionResponse} "User suspended successfully"
 // @Failure 400 {object} response.Response "Bad request - Invalid input"
 // @Failure 401 {object} response.Response "Unauthorized"
 // @Failure 404 {object} response.Response "User not found"
+// @Failure 409 {object} response.Response "User already suspended or banned"
 // @Failure 500 {object} response.Response "Internal server error"
 // @Router /admin/users/{id}/suspend [post]
 // @Security BearerAuth
@@ -92,16 +177,201 @@ func (h *Handler) SuspendUser(c *gin.Context) {
 
 	var req dto.SuspendUserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.Error(response.BadRequest("Invalid request"))
+		c.Error(response.BadRequest("Invalid request: " + err.Error()))
+		return
+	}
+
+	adminID, _ := c.Get("userID")
+
+	suspension, err := h.service.SuspendUser(c.Request.Context(), adminID.(string), userID, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, suspension, "User suspended")
+}
+
+// BanUser godoc
+// @Summary Ban a user (Admin)
+// @Description Ban a user until reinstated. A running suspension is replaced by the ban. In-flight work is settled as for a suspension.
+// @Tags Admin routes
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param request body dto.BanUserRequest true "Ban reason"
+// @Success 200 {object} response.Response{data=dto.SuspensionResponse} "User banned successfully"
+// @Failure 400 {object} response.Response "Bad request - Invalid input"
+// @Failure 404 {object} response.Response "User not found"
+// @Failure 409 {object} response.Response "User already banned"
+// @Router /admin/users/{id}/ban [post]
+// @Security BearerAuth
+func (h *Handler) BanUser(c *gin.Context) {
+	userID := c.Param("id")
+
+	var req dto.BanUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request: " + err.Error()))
+		return
+	}
+
+	adminID, _ := c.Get("userID")
+
+	suspension, err := h.service.BanUser(c.Request.Context(), adminID.(string), userID, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, suspension, "User banned")
+}
+
+// ReinstateUser godoc
+// @Summary Lift a suspension or ban (Admin)
+// @Description Restores the status the user had before the active suspension or ban
+// @Tags Admin routes
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param request body dto.ReinstateUserRequest true "Reason for reinstating"
+// @Success 200 {object} response.Response{data=dto.SuspensionResponse} "User reinstated successfully"
+// @Failure 404 {object} response.Response "No active suspension"
+// @Router /admin/users/{id}/reinstate [post]
+// @Security BearerAuth
+func (h *Handler) ReinstateUser(c *gin.Context) {
+	userID := c.Param("id")
+
+	var req dto.ReinstateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request: " + err.Error()))
+		return
+	}
+
+	adminID, _ := c.Get("userID")
+
+	suspension, err := h.service.ReinstateUser(c.Request.Context(), adminID.(string), userID, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, suspension, "User reinstated")
+}
+
+// ListSuspensions godoc
+// @Summary List suspensions and bans (Admin)
+// @Tags Admin routes
+// @Produce json
+// @Param userId query string false "Filter by user ID"
+// @Param kind query string false "Filter by kind (suspension, ban)"
+// @Param activeOnly query bool false "Only suspensions still in force"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Response{data=[]dto.SuspensionResponse}
+// @Router /admin/suspensions [get]
+// @Security BearerAuth
+func (h *Handler) ListSuspensions(c *gin.Context) {
+	var req dto.ListSuspensionsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+	req.SetDefaults()
+
+	suspensions, total, err := h.service.ListSuspensions(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pagination := response.NewPaginationMeta(total, req.Page, req.Limit)
+	response.Paginated(c, suspensions, pagination, "Suspensions retrieved successfully")
+}
+
+// StartImpersonation godoc
+// @Summary Impersonate a user (Admin)
+// @Description Issues a short-lived token that acts as the user so support can see what they see. The token is read-only unless allowWrites is set, cannot be refreshed or open a WebSocket, and every request made with it is audited. Admin accounts cannot be impersonated.
+// @Tags Admin routes
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param request body dto.StartImpersonationRequest true "Reason, duration and write access"
+// @Success 200 {object} response.Response{data=dto.ImpersonationTokenResponse} "Impersonation started"
+// @Failure 403 {object} response.Response "Impersonation disabled or user is an admin"
+// @Failure 404 {object} response.Response "User not found"
+// @Router /admin/users/{id}/impersonate [post]
+// @Security BearerAuth
+func (h *Handler) StartImpersonation(c *gin.Context) {
+	userID := c.Param("id")
+
+	var req dto.StartImpersonationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request: " + err.Error()))
+		return
+	}
+
+	adminID, _ := c.Get("userID")
+
+	token, err := h.service.StartImpersonation(c.Request.Context(), adminID.(string), userID, c.ClientIP(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, token, "Impersonation started")
+}
+
+// EndImpersonation godoc
+// @Summary End an impersonation session (Admin)
+// @Description Revokes the session's token before it expires
+// @Tags Admin routes
+// @Produce json
+// @Param id path string true "Impersonation session ID"
+// @Success 200 {object} response.Response{data=dto.ImpersonationSessionResponse} "Impersonation ended"
+// @Failure 404 {object} response.Response "Session not found"
+// @Failure 409 {object} response.Response "Session already ended"
+// @Router /admin/impersonations/{id}/end [post]
+// @Security BearerAuth
+func (h *Handler) EndImpersonation(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	session, err := h.service.EndImpersonation(c.Request.Context(), adminID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	if err := h.service.SuspendUser(c.Request.Context(), userID, req.Reason); err != nil {
+	response.Success(c, session, "Impersonation ended")
+}
+
+// ListImpersonations godoc
+// @Summary List impersonation sessions (Admin)
+// @Tags Admin routes
+// @Produce json
+// @Param adminId query string false "Filter by admin ID"
+// @Param userId query string false "Filter by impersonated user ID"
+// @Param activeOnly query bool false "Only sessions still in force"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Response{data=[]dto.ImpersonationSessionResponse}
+// @Router /admin/impersonations [get]
+// @Security BearerAuth
+func (h *Handler) ListImpersonations(c *gin.Context) {
+	var req dto.ListImpersonationsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+	req.SetDefaults()
+
+	sessions, total, err := h.service.ListImpersonations(c.Request.Context(), req)
+	if err != nil {
 		c.Error(err)
 		return
 	}
 
-	response.Success(c, nil, "User suspended")
+	pagination := response.NewPaginationMeta(total, req.Page, req.Limit)
+	response.Paginated(c, sessions, pagination, "Impersonation sessions retrieved successfully")
 }
 
 // UpdateUserStatus godoc
@@ -128,7 +398,9 @@ func (h *Handler) UpdateUserStatus(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.UpdateUserStatus(c.Request.Context(), userID, req.Status); err != nil {
+	adminID, _ := c.Get("userID")
+
+	if err := h.service.UpdateUserStatus(c.Request.Context(), adminID.(string), userID, req.Status); err != nil {
 		c.Error(err)
 		return
 	}
@@ -157,6 +429,26 @@ func (h *Handler) GetDashboardStats(c *gin.Context) {
 	response.Success(c, stats, "Dashboard stats retrieved")
 }
 
+// GetLiveMetrics godoc
+// @Summary Get live dashboard counters (Admin)
+// @Description Current active rides, searching orders, online drivers/providers and today's revenue. The same payload is pushed to admins over WebSocket as admin_live_metrics.
+// @Tags Admin routes
+// @Produce json
+// @Success 200 {object} response.Response "Live metrics retrieved successfully"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /admin/dashboard/live [get]
+// @Security BearerAuth
+func (h *Handler) GetLiveMetrics(c *gin.Context) {
+	snapshot, err := h.service.GetLiveMetrics(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, snapshot, "Live metrics retrieved")
+}
+
 // GetAllDriverProfiles godoc
 // @Summary List all driver profiles (Admin)
 // @Description Retrieve a paginated list of driver profiles with optional filtering
@@ -199,6 +491,35 @@ func (h *Handler) GetAllDriverProfiles(c *gin.Context) {
 	response.Success(c, result, "Driver profiles retrieved")
 }
 
+// ListDriversCashOwed godoc
+// @Summary Drivers owing cash to the platform (Admin)
+// @Description Drivers with a negative wallet balance from cash-ride commission, largest debt first, with their cash rides over the period
+// @Tags Admin routes
+// @Produce json
+// @Param days query int false "Cash ride lookback in days" default(30)
+// @Param onlyRestricted query boolean false "Only drivers restricted for exceeding their limit"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(20)
+// @Success 200 {object} response.Response{data=[]dto.DriverCashOwedResponse}
+// @Router /admin/drivers/cash-owed [get]
+// @Security BearerAuth
+func (h *Handler) ListDriversCashOwed(c *gin.Context) {
+	var query dto.DriverCashOwedQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+	query.SetDefaults()
+
+	rows, total, err := h.service.ListDriversCashOwed(c.Request.Context(), query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Paginated(c, rows, response.NewPaginationMeta(total, query.Page, query.Limit), "Cash owed report retrieved")
+}
+
 // GetAllServiceProviderProfiles godoc
 // @Summary List all service provider profiles (Admin)
 // @Description Retrieve a paginated list of service provider profiles with optional filtering
@@ -240,3 +561,69 @@ func (h *Handler) GetAllServiceProviderProfiles(c *gin.Context) {
 
 	response.Success(c, result, "Service provider profiles retrieved")
 }
+
+// ListDoctorChecks godoc
+// @Summary List stuck-entity doctor checks (Admin)
+// @Description Consistency checks the doctor can run, with a short description of each
+// @Tags Admin routes
+// @Produce json
+// @Success 200 {object} response.Response{data=[]dto.DoctorCheckInfo} "Doctor checks retrieved successfully"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Router /admin/doctor/checks [get]
+// @Security BearerAuth
+func (h *Handler) ListDoctorChecks(c *gin.Context) {
+	response.Success(c, h.service.ListDoctorChecks(), "Doctor checks retrieved")
+}
+
+// RunDoctor godoc
+// @Summary Run stuck-entity doctor (Admin)
+// @Description Detect busy drivers without a ride, open rides with captured holds, orders held by suspended providers and cache/DB mismatches. Runs as a dry run unless fix is true. An empty checks list runs every check.
+// @Tags Admin routes
+// @Accept json
+// @Produce json
+// @Param request body dto.RunDoctorRequest false "Checks to run and mode"
+// @Success 200 {object} response.Response{data=dto.DoctorReport} "Doctor report"
+// @Failure 400 {object} response.Response "Bad request - Unknown check"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 500 {object} response.Response "Internal server error"
+// @Router /admin/doctor/run [post]
+// @Security BearerAuth
+func (h *Handler) RunDoctor(c *gin.Context) {
+	var req dto.RunDoctorRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(response.BadRequest("Invalid request"))
+			return
+		}
+	}
+
+	adminID, _ := c.Get("userID")
+
+	report, err := h.service.RunDoctor(c.Request.Context(), adminID.(string), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, report, "Doctor run completed")
+}
+
+// GetLastDoctorReport godoc
+// @Summary Get last doctor report (Admin)
+// @Description The most recent stuck-entity doctor report, kept for seven days
+// @Tags Admin routes
+// @Produce json
+// @Success 200 {object} response.Response{data=dto.DoctorReport} "Doctor report retrieved successfully"
+// @Failure 401 {object} response.Response "Unauthorized"
+// @Failure 404 {object} response.Response "No report available"
+// @Router /admin/doctor/report [get]
+// @Security BearerAuth
+func (h *Handler) GetLastDoctorReport(c *gin.Context) {
+	report, err := h.service.GetLastDoctorReport(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, report, "Doctor report retrieved")
+}
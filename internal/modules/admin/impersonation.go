@@ -0,0 +1,191 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	dto "github.com/umar5678/go-backend/internal/modules/admin/dto"
+	"github.com/umar5678/go-backend/internal/modules/audit"
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/utils/jwt"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+func (s *service) ConfigureImpersonation(jwtCfg config.JWTConfig, cfg config.ImpersonationConfig) {
+	s.jwtCfg = jwtCfg
+	s.impersonation = &cfg
+}
+
+// StartImpersonation gives the admin a short-lived token that acts as the
+// user. The token is read-only unless writes were granted, and the session,
+// its reason and every request made with it are kept in the audit log.
+func (s *service) StartImpersonation(ctx context.Context, adminID, userID, ipAddress string, req dto.StartImpersonationRequest) (*dto.ImpersonationTokenResponse, error) {
+	if s.impersonation == nil {
+		return nil, response.ForbiddenError("Impersonation is disabled")
+	}
+	if adminID == userID {
+		return nil, response.BadRequest("You cannot impersonate your own account")
+	}
+
+	user, err := s.repo.FindUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("User")
+		}
+		return nil, response.InternalServerError("Failed to fetch user", err)
+	}
+	if user.Role == models.RoleAdmin {
+		return nil, response.ForbiddenError("Admin accounts cannot be impersonated")
+	}
+	if user.Status == models.StatusSuspended || user.Status == models.StatusBanned {
+		return nil, response.BadRequest("Suspended and banned accounts cannot be impersonated")
+	}
+
+	ttl := s.impersonation.DefaultTTL
+	if req.DurationMinutes > 0 {
+		ttl = time.Duration(req.DurationMinutes) * time.Minute
+	}
+	if ttl > s.impersonation.MaxTTL {
+		ttl = s.impersonation.MaxTTL
+	}
+
+	session := &models.ImpersonationSession{
+		AdminID:     adminID,
+		UserID:      user.ID,
+		Reason:      req.Reason,
+		AllowWrites: req.AllowWrites,
+		IPAddress:   ipAddress,
+		ExpiresAt:   time.Now().Add(ttl),
+	}
+	if err := s.repo.CreateImpersonationSession(ctx, session); err != nil {
+		logger.Error("failed to create impersonation session", "error", err, "adminID", adminID, "userID", userID)
+		return nil, response.InternalServerError("Failed to start impersonation", err)
+	}
+
+	var scopes []string
+	if req.AllowWrites {
+		scopes = append(scopes, jwt.ScopeWrite)
+	}
+	token, err := jwt.GenerateImpersonationToken(user.ID, string(user.Role), adminID, session.ID, scopes, s.jwtCfg.Secret, s.jwtCfg.Issuer, ttl)
+	if err != nil {
+		now := time.Now()
+		session.EndedAt = &now
+		session.EndedBy = &adminID
+		s.repo.EndImpersonationSession(ctx, session)
+		return nil, response.InternalServerError("Failed to start impersonation", err)
+	}
+
+	logger.Warn("impersonation started",
+		"sessionID", session.ID,
+		"adminID", adminID,
+		"userID", user.ID,
+		"allowWrites", req.AllowWrites,
+		"expiresAt", session.ExpiresAt,
+	)
+
+	s.recordAudit(ctx, adminID, "user.impersonate.start", "user", user.ID, nil,
+		map[string]interface{}{
+			"sessionId":   session.ID,
+			"allowWrites": session.AllowWrites,
+			"expiresAt":   session.ExpiresAt,
+			"ipAddress":   ipAddress,
+		}, req.Reason)
+
+	return &dto.ImpersonationTokenResponse{
+		Session:     dto.ToImpersonationSessionResponse(session),
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   int(ttl.Seconds()),
+		ReadOnly:    !req.AllowWrites,
+	}, nil
+}
+
+// EndImpersonation revokes the session's token before it expires. Any admin
+// can end any session.
+func (s *service) EndImpersonation(ctx context.Context, adminID, sessionID string) (*dto.ImpersonationSessionResponse, error) {
+	session, err := s.repo.FindImpersonationSession(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Impersonation session")
+		}
+		return nil, response.InternalServerError("Failed to fetch impersonation session", err)
+	}
+
+	now := time.Now()
+	if !session.IsActive(now) {
+		return nil, response.ConflictError("Impersonation session has already ended")
+	}
+
+	session.EndedAt = &now
+	session.EndedBy = &adminID
+	ended, err := s.repo.EndImpersonationSession(ctx, session)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to end impersonation", err)
+	}
+	if !ended {
+		return nil, response.ConflictError("Impersonation session has already ended")
+	}
+
+	if err := cache.MarkAuthSessionRevoked(ctx, session.ID, time.Until(session.ExpiresAt)); err != nil {
+		logger.Error("failed to revoke impersonation token", "error", err, "sessionID", session.ID)
+	}
+
+	logger.Warn("impersonation ended", "sessionID", session.ID, "adminID", session.AdminID, "userID", session.UserID, "endedBy", adminID)
+
+	s.recordAudit(ctx, adminID, "user.impersonate.end", "user", session.UserID,
+		map[string]interface{}{"sessionId": session.ID, "active": true},
+		map[string]interface{}{"sessionId": session.ID, "active": false}, "")
+
+	return dto.ToImpersonationSessionResponse(session), nil
+}
+
+func (s *service) ListImpersonations(ctx context.Context, req dto.ListImpersonationsRequest) ([]*dto.ImpersonationSessionResponse, int64, error) {
+	sessions, total, err := s.repo.ListImpersonationSessions(ctx, req.AdminID, req.UserID, req.ActiveOnly, req.Page, req.Limit)
+	if err != nil {
+		return nil, 0, response.InternalServerError("Failed to fetch impersonation sessions", err)
+	}
+
+	result := make([]*dto.ImpersonationSessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, dto.ToImpersonationSessionResponse(session))
+	}
+	return result, total, nil
+}
+
+// AuditImpersonation writes an audit entry for every request made with an
+// impersonation token, including those refused for lack of write access. It
+// has to wrap the error handler to see the status the request ended with.
+func AuditImpersonation(auditLogger audit.Recorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		impersonatorID, ok := c.Get("impersonatorID")
+		if !ok {
+			return
+		}
+		userID := c.GetString("userID")
+		sessionID, _ := c.Get("sessionID")
+		requestID, _ := c.Get("requestID")
+
+		auditLogger.Record(c.Request.Context(), audit.Entry{
+			ActorID:    impersonatorID.(string),
+			ActorRole:  string(models.RoleAdmin),
+			Action:     "user.impersonate.request",
+			EntityType: "user",
+			EntityID:   userID,
+			Metadata: map[string]interface{}{
+				"sessionId": sessionID,
+				"requestId": requestID,
+				"method":    c.Request.Method,
+				"path":      c.Request.URL.Path,
+				"status":    c.Writer.Status(),
+			},
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package admin
+
+import (
+	"context"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/services/livemetrics"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/websocket"
+	"github.com/umar5678/go-backend/internal/websocket/websocketutils"
+)
+
+const (
+	liveMetricsBroadcastInterval = 5 * time.Second
+	liveMetricsReconcileInterval = time.Minute
+)
+
+// LiveMetricsStreamer pushes the live dashboard counters to connected admins and
+// periodically reconciles the Redis sets against the database.
+type LiveMetricsStreamer struct {
+	repo Repository
+}
+
+func NewLiveMetricsStreamer(repo Repository) *LiveMetricsStreamer {
+	return &LiveMetricsStreamer{repo: repo}
+}
+
+func (s *LiveMetricsStreamer) Start(ctx context.Context) {
+	s.reconcile(ctx)
+
+	go func() {
+		broadcastTicker := time.NewTicker(liveMetricsBroadcastInterval)
+		reconcileTicker := time.NewTicker(liveMetricsReconcileInterval)
+		defer broadcastTicker.Stop()
+		defer reconcileTicker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-reconcileTicker.C:
+				s.reconcile(ctx)
+			case <-broadcastTicker.C:
+				s.broadcast(ctx)
+			}
+		}
+	}()
+
+	logger.Info("admin live metrics streamer started")
+}
+
+func (s *LiveMetricsStreamer) broadcast(ctx context.Context) {
+	if websocketutils.GetConnectedAdmins() == 0 {
+		return
+	}
+
+	snapshot, err := livemetrics.GetSnapshot(ctx)
+	if err != nil {
+		logger.Warn("failed to read live metrics", "error", err)
+		return
+	}
+
+	websocketutils.BroadcastToRole("admin", websocket.TypeAdminLiveMetrics, snapshot.ToMap())
+}
+
+func (s *LiveMetricsStreamer) reconcile(ctx context.Context) {
+	reconcileCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	now := time.Now().UTC()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	seed, err := s.repo.GetLiveMetricsSeed(reconcileCtx, startOfDay)
+	if err != nil {
+		logger.Warn("failed to load live metrics seed", "error", err)
+		return
+	}
+
+	if err := livemetrics.ReplaceSet(reconcileCtx, livemetrics.ActiveRidesKey, seed.ActiveRideIDs); err != nil {
+		logger.Warn("failed to reconcile active rides", "error", err)
+	}
+	if err := livemetrics.ReplaceSet(reconcileCtx, livemetrics.SearchingOrdersKey, seed.SearchingOrderIDs); err != nil {
+		logger.Warn("failed to reconcile searching orders", "error", err)
+	}
+	if err := livemetrics.SetRevenue(reconcileCtx, livemetrics.RevenueSourceRides, seed.RideRevenue); err != nil {
+		logger.Warn("failed to reconcile ride revenue", "error", err)
+	}
+	if err := livemetrics.SetRevenue(reconcileCtx, livemetrics.RevenueSourceHomeServices, seed.HomeServiceRevenue); err != nil {
+		logger.Warn("failed to reconcile home service revenue", "error", err)
+	}
+}
@@ -0,0 +1,205 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	dto "github.com/umar5678/go-backend/internal/modules/admin/dto"
+	"github.com/umar5678/go-backend/internal/modules/emails"
+	"github.com/umar5678/go-backend/internal/modules/notifications"
+	"github.com/umar5678/go-backend/internal/modules/serviceproviders"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+	websocketutil "github.com/umar5678/go-backend/internal/websocket/websocketutils"
+)
+
+func (s *service) SetMailer(mailer emails.Mailer) {
+	s.mailer = mailer
+}
+
+// emailProviderDecision tells the provider the outcome of their application.
+// It runs in the background so a slow mail provider does not hold up the review.
+func (s *service) emailProviderDecision(userID, providerID, template string, data map[string]interface{}) {
+	if s.mailer == nil || !s.mailer.Enabled() {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		_, err := s.mailer.SendToUser(ctx, userID, emails.SendRequest{
+			Template:      template,
+			Data:          data,
+			Category:      models.NotificationCategoryAccount,
+			ReferenceType: "service_provider",
+			ReferenceID:   providerID,
+		})
+		if err != nil && !errors.Is(err, emails.ErrOptedOut) && !errors.Is(err, emails.ErrNoAddress) {
+			logger.Error("failed to email provider decision", "error", err, "providerID", providerID, "template", template)
+		}
+	}()
+}
+
+func (s *service) loadOnboarding(ctx context.Context, providerID string) (*models.ServiceProviderProfile, []*models.Document, *serviceproviders.OnboardingStatus, error) {
+	profile, err := s.spRepo.FindByID(ctx, providerID)
+	if err != nil {
+		return nil, nil, nil, response.NotFoundError("Service provider")
+	}
+
+	docs, err := s.spRepo.FindDocuments(ctx, profile.ID, profile.UserID)
+	if err != nil {
+		return nil, nil, nil, response.InternalServerError("Failed to load provider documents", err)
+	}
+
+	return profile, docs, serviceproviders.EvaluateOnboarding(profile, docs), nil
+}
+
+func (s *service) ApproveServiceProvider(ctx context.Context, adminID, providerID string) error {
+	profile, _, status, err := s.loadOnboarding(ctx, providerID)
+	if err != nil {
+		return err
+	}
+
+	if profile.Status == models.SPStatusActive && profile.IsVerified {
+		return response.ConflictError("Service provider is already approved")
+	}
+	if !status.ReadyForApproval {
+		return response.ConflictError("Provider has not submitted verified documents and a clear background check")
+	}
+
+	if err := s.repo.UpdateUserStatus(ctx, profile.UserID, models.StatusActive); err != nil {
+		return response.InternalServerError("Failed to update user status", err)
+	}
+
+	now := time.Now()
+	if err := s.spRepo.UpdateOnboarding(ctx, providerID, map[string]interface{}{
+		"status":           models.SPStatusActive,
+		"is_verified":      true,
+		"reviewed_by":      adminID,
+		"reviewed_at":      now,
+		"rejection_reason": "",
+	}); err != nil {
+		return response.InternalServerError("Failed to update provider status", err)
+	}
+
+	s.publishAdminEvent(ctx, notifications.EventServiceProviderApproved, map[string]interface{}{
+		"provider_id": providerID,
+		"user_id":     profile.UserID,
+		"admin_id":    adminID,
+		"timestamp":   now,
+	})
+	websocketutil.SendToUser(profile.UserID, websocket.TypeProviderOnboardingUpdate, map[string]interface{}{
+		"providerId": providerID,
+		"status":     models.SPStatusActive,
+		"message":    "Your provider account has been approved",
+	})
+	s.emailProviderDecision(profile.UserID, providerID, emails.TemplateProviderApproved, nil)
+
+	logger.Info("service provider approved", "providerID", providerID, "userID", profile.UserID, "adminID", adminID)
+
+	s.recordAudit(ctx, adminID, "service_provider.approve", "service_provider", providerID,
+		map[string]interface{}{"status": profile.Status, "isVerified": profile.IsVerified},
+		map[string]interface{}{"status": models.SPStatusActive, "isVerified": true}, "")
+	return nil
+}
+
+func (s *service) RejectServiceProvider(ctx context.Context, adminID, providerID string, req dto.RejectServiceProviderRequest) error {
+	profile, err := s.spRepo.FindByID(ctx, providerID)
+	if err != nil {
+		return response.NotFoundError("Service provider")
+	}
+
+	if profile.Status != models.SPStatusPendingApproval {
+		return response.ConflictError("Only applications pending approval can be rejected")
+	}
+
+	now := time.Now()
+	if err := s.spRepo.UpdateOnboarding(ctx, providerID, map[string]interface{}{
+		"status":           models.SPStatusRejected,
+		"is_verified":      false,
+		"is_available":     false,
+		"reviewed_by":      adminID,
+		"reviewed_at":      now,
+		"rejection_reason": req.Reason,
+	}); err != nil {
+		return response.InternalServerError("Failed to update provider status", err)
+	}
+
+	s.publishAdminEvent(ctx, notifications.EventServiceProviderRejected, map[string]interface{}{
+		"provider_id": providerID,
+		"user_id":     profile.UserID,
+		"admin_id":    adminID,
+		"reason":      req.Reason,
+		"timestamp":   now,
+	})
+	websocketutil.SendToUser(profile.UserID, websocket.TypeProviderOnboardingUpdate, map[string]interface{}{
+		"providerId": providerID,
+		"status":     models.SPStatusRejected,
+		"reason":     req.Reason,
+		"message":    "Your provider application needs changes before it can be approved",
+	})
+	s.emailProviderDecision(profile.UserID, providerID, emails.TemplateProviderRejected, map[string]interface{}{
+		"Reason": req.Reason,
+	})
+
+	logger.Info("service provider rejected", "providerID", providerID, "adminID", adminID, "reason", req.Reason)
+
+	s.recordAudit(ctx, adminID, "service_provider.reject", "service_provider", providerID,
+		map[string]interface{}{"status": profile.Status, "isVerified": profile.IsVerified},
+		map[string]interface{}{"status": models.SPStatusRejected, "isVerified": false}, req.Reason)
+	return nil
+}
+
+func (s *service) UpdateBackgroundCheck(ctx context.Context, providerID string, req dto.UpdateBackgroundCheckRequest) (*serviceproviders.OnboardingStatus, error) {
+	switch req.Status {
+	case models.BackgroundCheckPending, models.BackgroundCheckClear, models.BackgroundCheckFlagged, models.BackgroundCheckFailed:
+	default:
+		return nil, response.BadRequest("status must be one of pending, clear, flagged, failed")
+	}
+
+	profile, err := s.spRepo.FindByID(ctx, providerID)
+	if err != nil {
+		return nil, response.NotFoundError("Service provider")
+	}
+
+	updates := map[string]interface{}{
+		"background_check_status":     req.Status,
+		"background_check_updated_at": time.Now(),
+	}
+	if req.Reference != "" {
+		updates["background_check_reference"] = req.Reference
+	}
+	if err := s.spRepo.UpdateOnboarding(ctx, providerID, updates); err != nil {
+		return nil, response.InternalServerError("Failed to update background check", err)
+	}
+
+	if req.Status == models.BackgroundCheckFailed || req.Status == models.BackgroundCheckFlagged {
+		websocketutil.SendToUser(profile.UserID, websocket.TypeProviderOnboardingUpdate, map[string]interface{}{
+			"providerId":            providerID,
+			"status":                profile.Status,
+			"backgroundCheckStatus": req.Status,
+			"message":               "Your background verification needs attention; our team will contact you",
+		})
+	}
+
+	logger.Info("provider background check updated", "providerID", providerID, "status", req.Status)
+
+	_, _, status, err := s.loadOnboarding(ctx, providerID)
+	return status, err
+}
+
+func (s *service) GetProviderOnboarding(ctx context.Context, providerID string) (*dto.ProviderOnboardingResponse, error) {
+	profile, docs, status, err := s.loadOnboarding(ctx, providerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ProviderOnboardingResponse{
+		Onboarding:               status,
+		BackgroundCheckReference: profile.BackgroundCheckReference,
+		ReviewedBy:               profile.ReviewedBy,
+		Documents:                docs,
+	}, nil
+}
@@ -2,7 +2,9 @@ package admin
 
 import (
 	"context"
+	"time"
 
+	"github.com/umar5678/go-backend/internal/database"
 	"github.com/umar5678/go-backend/internal/models"
 	"gorm.io/gorm"
 )
@@ -13,8 +15,115 @@ type Repository interface {
 	UpdateUserStatus(ctx context.Context, userID string, status models.UserStatus) error
 	DeleteUser(ctx context.Context, userID string) error
 	GetDashboardStats(ctx context.Context) (map[string]interface{}, error)
+	GetLiveMetricsSeed(ctx context.Context, since time.Time) (*LiveMetricsSeed, error)
+
+	FindBusyDriversWithoutRide(ctx context.Context, limit int) ([]DoctorDriverRow, error)
+	FindOpenRidesWithCapturedHold(ctx context.Context, limit int) ([]DoctorRideHoldRow, error)
+	CompleteRideWithCapturedHold(ctx context.Context, rideID string, capturedAt time.Time) (bool, error)
+	FindOrdersWithInactiveProvider(ctx context.Context, limit int) ([]DoctorOrderRow, error)
+	ReleaseOrderToSearch(ctx context.Context, orderID, fromStatus, providerID, adminID, notes string) (bool, error)
+	FindDriverStatuses(ctx context.Context, driverIDs []string) (map[string]string, error)
+	FindOnlineDriverIDs(ctx context.Context) ([]string, error)
+	FindRideStatuses(ctx context.Context, rideIDs []string) (map[string]string, error)
+
+	ListDriversCashOwed(ctx context.Context, since time.Time, onlyRestricted bool, page, limit int) ([]DriverCashOwedRow, int64, error)
+
+	CreateSuspension(ctx context.Context, suspension *models.AccountSuspension, supersede *models.AccountSuspension) error
+	FindActiveSuspension(ctx context.Context, userID string) (*models.AccountSuspension, error)
+	LiftSuspension(ctx context.Context, suspension *models.AccountSuspension) (bool, error)
+	ListSuspensions(ctx context.Context, userID, kind string, activeOnly bool, page, limit int) ([]*models.AccountSuspension, int64, error)
+	FindExpiredSuspensions(ctx context.Context, now time.Time, limit int) ([]*models.AccountSuspension, error)
+	ListActiveSuspensions(ctx context.Context) ([]*models.AccountSuspension, error)
+	FindOpenRidesForUser(ctx context.Context, userID string) ([]SuspensionRideRow, error)
+	CancelRideForSuspension(ctx context.Context, rideID, fromStatus, reason string) (bool, error)
+	ReturnRideToSearch(ctx context.Context, rideID, fromStatus string) (bool, error)
+	FindOpenOrdersForUser(ctx context.Context, customerID, providerID string) ([]SuspensionOrderRow, error)
+	CancelOrderForSuspension(ctx context.Context, orderID, fromStatus, adminID, reason string, refundAmount float64) (bool, error)
+
+	CreateImpersonationSession(ctx context.Context, session *models.ImpersonationSession) error
+	FindImpersonationSession(ctx context.Context, id string) (*models.ImpersonationSession, error)
+	EndImpersonationSession(ctx context.Context, session *models.ImpersonationSession) (bool, error)
+	ListImpersonationSessions(ctx context.Context, adminID, userID string, activeOnly bool, page, limit int) ([]*models.ImpersonationSession, int64, error)
 }
 
+// SuspensionRideRow is an open ride of a user being suspended, either as the
+// rider or as the driver.
+type SuspensionRideRow struct {
+	RideID       string
+	Status       string
+	RiderID      string
+	DriverID     *string
+	WalletHoldID *string
+}
+
+// SuspensionOrderRow is an open service order of a user being suspended,
+// either as the customer or as the assigned provider.
+type SuspensionOrderRow struct {
+	OrderID            string
+	OrderNumber        string
+	Status             string
+	CustomerID         string
+	AssignedProviderID *string
+	WalletHoldID       *string
+	TotalPrice         float64
+}
+
+// DriverCashOwedRow is a driver whose wallet is negative, i.e. who holds cash
+// that belongs to the platform, with their cash rides since the report start.
+type DriverCashOwedRow struct {
+	DriverID            string
+	UserID              string
+	Name                string
+	Phone               *string
+	Balance             float64
+	Currency            string
+	MinBalanceThreshold float64
+	IsRestricted        bool
+	CashRides           int
+	CashCollected       float64
+	Commission          float64
+	LastCashRideAt      *time.Time
+}
+
+// LiveMetricsSeed is the database view of the live dashboard counters, used to
+// correct drift in the Redis sets.
+type LiveMetricsSeed struct {
+	ActiveRideIDs      []string
+	SearchingOrderIDs  []string
+	RideRevenue        float64
+	HomeServiceRevenue float64
+}
+
+// DoctorDriverRow is a driver whose profile says busy/on_trip with no open ride.
+type DoctorDriverRow struct {
+	DriverID  string
+	UserID    string
+	Status    string
+	UpdatedAt time.Time
+}
+
+// DoctorRideHoldRow is an open ride whose wallet hold has already been captured.
+type DoctorRideHoldRow struct {
+	RideID     string
+	RideStatus string
+	RiderID    string
+	DriverID   *string
+	HoldID     string
+	HoldAmount float64
+	CapturedAt time.Time
+}
+
+// DoctorOrderRow is an in-flight order still assigned to a suspended or banned provider.
+type DoctorOrderRow struct {
+	OrderID        string
+	OrderNumber    string
+	OrderStatus    string
+	ProviderID     string
+	ProviderStatus string
+}
+
+var doctorOpenRideStatuses = []string{"searching", "accepted", "arrived", "started"}
+
 type repository struct {
 	db *gorm.DB
 }
@@ -69,7 +178,7 @@ func (r *repository) GetDashboardStats(ctx context.Context) (map[string]interfac
 		Role  string
 		Count int64
 	}
-	r.db.WithContext(ctx).
+	r.db.WithContext(ctx).Scopes(database.ReadReplica).
 		Model(&models.User{}).
 		Select("role, COUNT(*) as count").
 		Group("role").
@@ -81,7 +190,7 @@ func (r *repository) GetDashboardStats(ctx context.Context) (map[string]interfac
 		Status string
 		Count  int64
 	}
-	r.db.WithContext(ctx).
+	r.db.WithContext(ctx).Scopes(database.ReadReplica).
 		Model(&models.User{}).
 		Select("status, COUNT(*) as count").
 		Group("status").
@@ -90,8 +199,474 @@ func (r *repository) GetDashboardStats(ctx context.Context) (map[string]interfac
 	stats["usersByStatus"] = statusCounts
 
 	var totalUsers int64
-	r.db.WithContext(ctx).Model(&models.User{}).Count(&totalUsers)
+	r.db.WithContext(ctx).Scopes(database.ReadReplica).Model(&models.User{}).Count(&totalUsers)
 	stats["totalUsers"] = totalUsers
 
 	return stats, nil
 }
+
+func (r *repository) GetLiveMetricsSeed(ctx context.Context, since time.Time) (*LiveMetricsSeed, error) {
+	seed := &LiveMetricsSeed{}
+
+	if err := r.db.WithContext(ctx).
+		Model(&models.Ride{}).
+		Where("status IN ?", []string{"searching", "accepted", "arrived", "started"}).
+		Pluck("id", &seed.ActiveRideIDs).Error; err != nil {
+		return nil, err
+	}
+
+	if err := r.db.WithContext(ctx).
+		Model(&models.ServiceOrderNew{}).
+		Where("status = ?", "searching_provider").
+		Pluck("id", &seed.SearchingOrderIDs).Error; err != nil {
+		return nil, err
+	}
+
+	if err := r.db.WithContext(ctx).
+		Model(&models.Ride{}).
+		Select("COALESCE(SUM(rider_fare), 0)").
+		Where("status = ? AND completed_at >= ?", "completed", since).
+		Scan(&seed.RideRevenue).Error; err != nil {
+		return nil, err
+	}
+
+	if err := r.db.WithContext(ctx).
+		Model(&models.ServiceOrderNew{}).
+		Select("COALESCE(SUM(total_price), 0)").
+		Where("status = ? AND completed_at >= ?", "completed", since).
+		Scan(&seed.HomeServiceRevenue).Error; err != nil {
+		return nil, err
+	}
+
+	return seed, nil
+}
+
+func (r *repository) FindBusyDriversWithoutRide(ctx context.Context, limit int) ([]DoctorDriverRow, error) {
+	var rows []DoctorDriverRow
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT dp.id AS driver_id, dp.user_id, dp.status, dp.updated_at
+		FROM driver_profiles dp
+		WHERE dp.status IN ('busy', 'on_trip')
+		  AND NOT EXISTS (
+			SELECT 1 FROM rides r
+			WHERE r.driver_id = dp.user_id
+			  AND r.status IN ('accepted', 'arrived', 'started')
+			  AND r.deleted_at IS NULL
+		  )
+		ORDER BY dp.updated_at
+		LIMIT ?
+	`, limit).Scan(&rows).Error
+	return rows, err
+}
+
+func (r *repository) FindOpenRidesWithCapturedHold(ctx context.Context, limit int) ([]DoctorRideHoldRow, error) {
+	var rows []DoctorRideHoldRow
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT r.id AS ride_id, r.status AS ride_status, r.rider_id, r.driver_id,
+		       h.id AS hold_id, h.amount AS hold_amount, h.created_at AS captured_at
+		FROM rides r
+		JOIN wallet_holds h ON h.id = r.wallet_hold_id
+		WHERE h.status::text = 'captured'
+		  AND r.status IN ?
+		  AND r.deleted_at IS NULL
+		ORDER BY h.created_at
+		LIMIT ?
+	`, doctorOpenRideStatuses, limit).Scan(&rows).Error
+	return rows, err
+}
+
+func (r *repository) CompleteRideWithCapturedHold(ctx context.Context, rideID string, capturedAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.Ride{}).
+		Where("id = ? AND status IN ?", rideID, doctorOpenRideStatuses).
+		Updates(map[string]interface{}{
+			"status":       "completed",
+			"completed_at": capturedAt,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *repository) FindOrdersWithInactiveProvider(ctx context.Context, limit int) ([]DoctorOrderRow, error) {
+	var rows []DoctorOrderRow
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT o.id AS order_id, o.order_number, o.status AS order_status,
+		       p.id AS provider_id, p.status AS provider_status
+		FROM service_orders o
+		JOIN service_provider_profiles p ON p.id = o.assigned_provider_id
+		WHERE p.status IN ?
+		  AND o.status IN ('assigned', 'accepted', 'in_progress')
+		ORDER BY o.updated_at
+		LIMIT ?
+	`, []string{string(models.SPStatusSuspended), string(models.SPStatusBanned), string(models.SPStatusRejected)}, limit).Scan(&rows).Error
+	return rows, err
+}
+
+// ReleaseOrderToSearch unassigns the provider and puts the order back into the
+// searching pool, recording the transition in the order history.
+func (r *repository) ReleaseOrderToSearch(ctx context.Context, orderID, fromStatus, providerID, adminID, notes string) (bool, error) {
+	released := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.ServiceOrderNew{}).
+			Where("id = ? AND status = ?", orderID, fromStatus).
+			Updates(map[string]interface{}{
+				"status":               "searching_provider",
+				"assigned_provider_id": nil,
+				"provider_accepted_at": nil,
+				"version":              gorm.Expr("version + 1"),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		released = true
+
+		history := models.NewOrderStatusHistory(orderID, fromStatus, "searching_provider", &adminID, "admin", notes,
+			models.StatusHistoryMetadata{"oldProviderId": providerID})
+		return tx.Create(history).Error
+	})
+	return released, err
+}
+
+func (r *repository) FindDriverStatuses(ctx context.Context, driverIDs []string) (map[string]string, error) {
+	statuses := make(map[string]string, len(driverIDs))
+	if len(driverIDs) == 0 {
+		return statuses, nil
+	}
+
+	var rows []struct {
+		ID     string
+		Status string
+	}
+	if err := r.db.WithContext(ctx).
+		Model(&models.DriverProfile{}).
+		Select("id, status").
+		Where("id IN ?", driverIDs).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		statuses[row.ID] = row.Status
+	}
+	return statuses, nil
+}
+
+func (r *repository) FindOnlineDriverIDs(ctx context.Context) ([]string, error) {
+	var ids []string
+	err := r.db.WithContext(ctx).
+		Model(&models.DriverProfile{}).
+		Where("status = ?", "online").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+func (r *repository) FindRideStatuses(ctx context.Context, rideIDs []string) (map[string]string, error) {
+	statuses := make(map[string]string, len(rideIDs))
+	if len(rideIDs) == 0 {
+		return statuses, nil
+	}
+
+	var rows []struct {
+		ID     string
+		Status string
+	}
+	if err := r.db.WithContext(ctx).
+		Model(&models.Ride{}).
+		Select("id, status").
+		Where("id IN ?", rideIDs).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		statuses[row.ID] = row.Status
+	}
+	return statuses, nil
+}
+
+func (r *repository) ListDriversCashOwed(ctx context.Context, since time.Time, onlyRestricted bool, page, limit int) ([]DriverCashOwedRow, int64, error) {
+	where := "w.balance < 0 AND dp.deleted_at IS NULL"
+	if onlyRestricted {
+		where += " AND dp.is_restricted = true"
+	}
+
+	var total int64
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT COUNT(*)
+		FROM driver_profiles dp
+		JOIN wallets w ON w.user_id = dp.user_id AND w.wallet_type = 'driver'
+		WHERE ` + where).Scan(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var rows []DriverCashOwedRow
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT dp.id AS driver_id, dp.user_id, u.name, u.phone,
+		       w.balance, w.currency, dp.min_balance_threshold, dp.is_restricted,
+		       COALESCE(cr.cash_rides, 0) AS cash_rides,
+		       COALESCE(cr.cash_collected, 0) AS cash_collected,
+		       COALESCE(cr.commission, 0) AS commission,
+		       cr.last_cash_ride_at
+		FROM driver_profiles dp
+		JOIN wallets w ON w.user_id = dp.user_id AND w.wallet_type = 'driver'
+		JOIN users u ON u.id = dp.user_id
+		LEFT JOIN (
+			SELECT driver_id,
+			       COUNT(*) AS cash_rides,
+			       SUM(cash_collected) AS cash_collected,
+			       SUM(cash_commission) AS commission,
+			       MAX(completed_at) AS last_cash_ride_at
+			FROM rides
+			WHERE payment_method = 'cash' AND status = 'completed'
+			  AND completed_at >= ? AND deleted_at IS NULL
+			GROUP BY driver_id
+		) cr ON cr.driver_id = dp.user_id
+		WHERE `+where+`
+		ORDER BY w.balance ASC
+		OFFSET ? LIMIT ?
+	`, since, (page-1)*limit, limit).Scan(&rows).Error
+	return rows, total, err
+}
+
+// CreateSuspension saves a new suspension or ban and applies its status to the
+// user. A suspension being superseded, e.g. by a ban, is lifted in the same
+// transaction.
+func (r *repository) CreateSuspension(ctx context.Context, suspension *models.AccountSuspension, supersede *models.AccountSuspension) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if supersede != nil {
+			if err := tx.Model(&models.AccountSuspension{}).
+				Where("id = ? AND lifted_at IS NULL", supersede.ID).
+				Updates(map[string]interface{}{
+					"lifted_at":   supersede.LiftedAt,
+					"lifted_by":   supersede.LiftedBy,
+					"lift_reason": supersede.LiftReason,
+				}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Create(suspension).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.User{}).
+			Where("id = ?", suspension.UserID).
+			Update("status", suspension.UserStatus()).Error
+	})
+}
+
+func (r *repository) FindActiveSuspension(ctx context.Context, userID string) (*models.AccountSuspension, error) {
+	var suspension models.AccountSuspension
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND lifted_at IS NULL", userID).
+		First(&suspension).Error
+	if err != nil {
+		return nil, err
+	}
+	return &suspension, nil
+}
+
+// LiftSuspension ends the suspension and gives the user back the status held
+// before it, unless an admin changed the status in the meantime.
+func (r *repository) LiftSuspension(ctx context.Context, suspension *models.AccountSuspension) (bool, error) {
+	lifted := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.AccountSuspension{}).
+			Where("id = ? AND lifted_at IS NULL", suspension.ID).
+			Updates(map[string]interface{}{
+				"lifted_at":   suspension.LiftedAt,
+				"lifted_by":   suspension.LiftedBy,
+				"lift_reason": suspension.LiftReason,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		lifted = true
+
+		return tx.Model(&models.User{}).
+			Where("id = ? AND status = ?", suspension.UserID, suspension.UserStatus()).
+			Update("status", suspension.PreviousStatus).Error
+	})
+	return lifted, err
+}
+
+func (r *repository) ListSuspensions(ctx context.Context, userID, kind string, activeOnly bool, page, limit int) ([]*models.AccountSuspension, int64, error) {
+	var suspensions []*models.AccountSuspension
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.AccountSuspension{})
+	if userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	if activeOnly {
+		query = query.Where("lifted_at IS NULL")
+	}
+
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&suspensions).Error
+	return suspensions, total, err
+}
+
+func (r *repository) FindExpiredSuspensions(ctx context.Context, now time.Time, limit int) ([]*models.AccountSuspension, error) {
+	var suspensions []*models.AccountSuspension
+	err := r.db.WithContext(ctx).
+		Where("lifted_at IS NULL AND ends_at IS NOT NULL AND ends_at <= ?", now).
+		Order("ends_at").
+		Limit(limit).
+		Find(&suspensions).Error
+	return suspensions, err
+}
+
+func (r *repository) ListActiveSuspensions(ctx context.Context) ([]*models.AccountSuspension, error) {
+	var suspensions []*models.AccountSuspension
+	err := r.db.WithContext(ctx).
+		Where("lifted_at IS NULL").
+		Find(&suspensions).Error
+	return suspensions, err
+}
+
+func (r *repository) FindOpenRidesForUser(ctx context.Context, userID string) ([]SuspensionRideRow, error) {
+	var rows []SuspensionRideRow
+	err := r.db.WithContext(ctx).
+		Model(&models.Ride{}).
+		Select("id AS ride_id, status, rider_id, driver_id, wallet_hold_id").
+		Where("(rider_id = ? OR driver_id = ?) AND status IN ?", userID, userID, doctorOpenRideStatuses).
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *repository) CancelRideForSuspension(ctx context.Context, rideID, fromStatus, reason string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.Ride{}).
+		Where("id = ? AND status = ?", rideID, fromStatus).
+		Updates(map[string]interface{}{
+			"status":              "cancelled",
+			"cancelled_by":        "admin",
+			"cancellation_reason": reason,
+			"cancelled_at":        time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ReturnRideToSearch takes an accepted ride away from its driver so it can be
+// matched again.
+func (r *repository) ReturnRideToSearch(ctx context.Context, rideID, fromStatus string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.Ride{}).
+		Where("id = ? AND status = ?", rideID, fromStatus).
+		Updates(map[string]interface{}{
+			"status":      "searching",
+			"driver_id":   nil,
+			"accepted_at": nil,
+			"arrived_at":  nil,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *repository) FindOpenOrdersForUser(ctx context.Context, customerID, providerID string) ([]SuspensionOrderRow, error) {
+	var rows []SuspensionOrderRow
+	query := r.db.WithContext(ctx).
+		Model(&models.ServiceOrderNew{}).
+		Select("id AS order_id, order_number, status, customer_id, assigned_provider_id, wallet_hold_id, total_price").
+		Where("customer_id = ? AND status IN ?", customerID,
+			[]string{"pending", "searching_provider", "assigned", "accepted", "in_progress"})
+	if providerID != "" {
+		query = query.Or("assigned_provider_id = ? AND status IN ?", providerID,
+			[]string{"assigned", "accepted", "in_progress"})
+	}
+	err := query.Scan(&rows).Error
+	return rows, err
+}
+
+// CancelOrderForSuspension cancels a customer's order without a fee and
+// records the transition in the order history.
+func (r *repository) CancelOrderForSuspension(ctx context.Context, orderID, fromStatus, adminID, reason string, refundAmount float64) (bool, error) {
+	cancelled := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.ServiceOrderNew{}).
+			Where("id = ? AND status = ?", orderID, fromStatus).
+			Updates(map[string]interface{}{
+				"status": "cancelled",
+				"cancellation_info": models.CancellationInfo{
+					CancelledBy:  "admin",
+					CancelledAt:  time.Now(),
+					Reason:       reason,
+					RefundAmount: refundAmount,
+				},
+				"version": gorm.Expr("version + 1"),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		cancelled = true
+
+		history := models.NewOrderStatusHistory(orderID, fromStatus, "cancelled", &adminID, "admin", reason,
+			models.StatusHistoryMetadata{"refundAmount": refundAmount})
+		return tx.Create(history).Error
+	})
+	return cancelled, err
+}
+
+func (r *repository) CreateImpersonationSession(ctx context.Context, session *models.ImpersonationSession) error {
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+func (r *repository) FindImpersonationSession(ctx context.Context, id string) (*models.ImpersonationSession, error) {
+	var session models.ImpersonationSession
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// EndImpersonationSession reports false when the session had already ended.
+func (r *repository) EndImpersonationSession(ctx context.Context, session *models.ImpersonationSession) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.ImpersonationSession{}).
+		Where("id = ? AND ended_at IS NULL", session.ID).
+		Updates(map[string]interface{}{
+			"ended_at": session.EndedAt,
+			"ended_by": session.EndedBy,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *repository) ListImpersonationSessions(ctx context.Context, adminID, userID string, activeOnly bool, page, limit int) ([]*models.ImpersonationSession, int64, error) {
+	var sessions []*models.ImpersonationSession
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.ImpersonationSession{})
+	if adminID != "" {
+		query = query.Where("admin_id = ?", adminID)
+	}
+	if userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+	if activeOnly {
+		query = query.Where("ended_at IS NULL AND expires_at > ?", time.Now())
+	}
+
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&sessions).Error
+	return sessions, total, err
+}
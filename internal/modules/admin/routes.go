@@ -13,9 +13,24 @@ func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gi
 		admin.GET("/users", handler.ListUsers)
 		admin.PUT("/users/:id/status", handler.UpdateUserStatus)
 		admin.POST("/service-providers/:id/approve", handler.ApproveServiceProvider)
+		admin.POST("/service-providers/:id/reject", handler.RejectServiceProvider)
+		admin.PUT("/service-providers/:id/background-check", handler.UpdateBackgroundCheck)
+		admin.GET("/service-providers/:id/onboarding", handler.GetProviderOnboarding)
 		admin.POST("/users/:id/suspend", handler.SuspendUser)
+		admin.POST("/users/:id/ban", handler.BanUser)
+		admin.POST("/users/:id/reinstate", handler.ReinstateUser)
+		admin.GET("/suspensions", handler.ListSuspensions)
+		admin.POST("/users/:id/impersonate", handler.StartImpersonation)
+		admin.GET("/impersonations", handler.ListImpersonations)
+		admin.POST("/impersonations/:id/end", handler.EndImpersonation)
 		admin.GET("/dashboard/stats", handler.GetDashboardStats)
+		admin.GET("/dashboard/live", handler.GetLiveMetrics)
 		admin.GET("/drivers", handler.GetAllDriverProfiles)
+		admin.GET("/drivers/cash-owed", handler.ListDriversCashOwed)
 		admin.GET("/service-providers", handler.GetAllServiceProviderProfiles)
+
+		admin.GET("/doctor/checks", handler.ListDoctorChecks)
+		admin.POST("/doctor/run", handler.RunDoctor)
+		admin.GET("/doctor/report", handler.GetLastDoctorReport)
 	}
 }
@@ -2,25 +2,57 @@ package admin
 
 import (
 	"context"
+	"math"
 	"strconv"
 	"time"
 
+	"github.com/umar5678/go-backend/internal/config"
 	"github.com/umar5678/go-backend/internal/models"
+	dto "github.com/umar5678/go-backend/internal/modules/admin/dto"
+	"github.com/umar5678/go-backend/internal/modules/audit"
 	"github.com/umar5678/go-backend/internal/modules/drivers"
+	"github.com/umar5678/go-backend/internal/modules/emails"
 	"github.com/umar5678/go-backend/internal/modules/notifications"
 	"github.com/umar5678/go-backend/internal/modules/serviceproviders"
+	"github.com/umar5678/go-backend/internal/services/livemetrics"
 	"github.com/umar5678/go-backend/internal/utils/logger"
 	"github.com/umar5678/go-backend/internal/utils/response"
 )
 
 type Service interface {
 	ListUsers(ctx context.Context, role, status, page, limit string) (map[string]interface{}, error)
-	ApproveServiceProvider(ctx context.Context, providerID string) error
-	SuspendUser(ctx context.Context, userID, reason string) error
-	UpdateUserStatus(ctx context.Context, userID string, status models.UserStatus) error
+	ApproveServiceProvider(ctx context.Context, adminID, providerID string) error
+	RejectServiceProvider(ctx context.Context, adminID, providerID string, req dto.RejectServiceProviderRequest) error
+	UpdateBackgroundCheck(ctx context.Context, providerID string, req dto.UpdateBackgroundCheckRequest) (*serviceproviders.OnboardingStatus, error)
+	GetProviderOnboarding(ctx context.Context, providerID string) (*dto.ProviderOnboardingResponse, error)
+	UpdateUserStatus(ctx context.Context, adminID, userID string, status models.UserStatus) error
 	GetDashboardStats(ctx context.Context) (map[string]interface{}, error)
+	GetLiveMetrics(ctx context.Context) (*livemetrics.Snapshot, error)
 	ListDriverProfiles(ctx context.Context, filters map[string]interface{}, page, limit int) (map[string]interface{}, error)
 	ListServiceProviderProfiles(ctx context.Context, filters map[string]interface{}, page, limit int) (map[string]interface{}, error)
+	ListDriversCashOwed(ctx context.Context, query dto.DriverCashOwedQuery) ([]dto.DriverCashOwedResponse, int64, error)
+
+	ListDoctorChecks() []dto.DoctorCheckInfo
+	RunDoctor(ctx context.Context, adminID string, req dto.RunDoctorRequest) (*dto.DoctorReport, error)
+	GetLastDoctorReport(ctx context.Context) (*dto.DoctorReport, error)
+
+	SuspendUser(ctx context.Context, adminID, userID string, req dto.SuspendUserRequest) (*dto.SuspensionResponse, error)
+	BanUser(ctx context.Context, adminID, userID string, req dto.BanUserRequest) (*dto.SuspensionResponse, error)
+	ReinstateUser(ctx context.Context, adminID, userID string, req dto.ReinstateUserRequest) (*dto.SuspensionResponse, error)
+	ListSuspensions(ctx context.Context, req dto.ListSuspensionsRequest) ([]*dto.SuspensionResponse, int64, error)
+	ExpireSuspensions(ctx context.Context) error
+	SyncUserBlocks(ctx context.Context) error
+
+	StartImpersonation(ctx context.Context, adminID, userID, ipAddress string, req dto.StartImpersonationRequest) (*dto.ImpersonationTokenResponse, error)
+	EndImpersonation(ctx context.Context, adminID, sessionID string) (*dto.ImpersonationSessionResponse, error)
+	ListImpersonations(ctx context.Context, req dto.ListImpersonationsRequest) ([]*dto.ImpersonationSessionResponse, int64, error)
+
+	SetAuditLogger(auditLogger audit.Recorder)
+	SetWalletHolds(walletHolds WalletHolds)
+	SetRideMatcher(rideMatcher RideMatcher)
+	SetWebhookPublisher(publisher WebhookPublisher)
+	SetMailer(mailer emails.Mailer)
+	ConfigureImpersonation(jwtCfg config.JWTConfig, cfg config.ImpersonationConfig)
 }
 
 type service struct {
@@ -28,6 +60,13 @@ type service struct {
 	spRepo        serviceproviders.Repository
 	drvRepo       drivers.Repository
 	eventProducer notifications.EventProducer
+	auditLogger   audit.Recorder
+	walletHolds   WalletHolds
+	rideMatcher   RideMatcher
+	webhooks      WebhookPublisher
+	mailer        emails.Mailer
+	jwtCfg        config.JWTConfig
+	impersonation *config.ImpersonationConfig
 }
 
 func NewService(repo Repository, spRepo serviceproviders.Repository, drvRepo drivers.Repository) Service {
@@ -75,51 +114,28 @@ func (s *service) ListUsers(ctx context.Context, role, status, page, limit strin
 	}, nil
 }
 
-func (s *service) ApproveServiceProvider(ctx context.Context, providerID string) error {
-
-	profile, err := s.spRepo.FindByID(ctx, providerID)
-	if err != nil {
-		return response.NotFoundError("Service provider")
+func (s *service) UpdateUserStatus(ctx context.Context, adminID, userID string, status models.UserStatus) error {
+	// Suspensions and bans carry a reason, a duration and side effects, so they
+	// only go through their own endpoints.
+	if status == models.StatusSuspended || status == models.StatusBanned {
+		return response.BadRequest("Use the suspend or ban endpoint to block a user")
 	}
-
-	if err := s.repo.UpdateUserStatus(ctx, profile.UserID, models.StatusActive); err != nil {
-		return response.InternalServerError("Failed to update user status", err)
+	if _, err := s.repo.FindActiveSuspension(ctx, userID); err == nil {
+		return response.ConflictError("User has an active suspension; reinstate the user instead")
 	}
 
-	if err := s.spRepo.UpdateStatus(ctx, providerID, models.SPStatusActive); err != nil {
-		return response.InternalServerError("Failed to update provider status", err)
+	var before map[string]interface{}
+	if user, err := s.repo.FindUserByID(ctx, userID); err == nil {
+		before = map[string]interface{}{"status": user.Status}
 	}
 
-	logger.Info("service provider approved", "providerID", providerID, "userID", profile.UserID)
-	return nil
-}
-
-func (s *service) SuspendUser(ctx context.Context, userID, reason string) error {
-	user, err := s.repo.FindUserByID(ctx, userID)
-	if err != nil {
-		return response.NotFoundError("User")
-	}
-
-	if err := s.repo.UpdateUserStatus(ctx, userID, models.StatusSuspended); err != nil {
-		return response.InternalServerError("Failed to suspend user", err)
-	}
-
-	s.publishAdminEvent(ctx, notifications.EventUserSuspended, map[string]interface{}{
-		"user_id":   userID,
-		"reason":    reason,
-		"timestamp": time.Now(),
-	})
-
-	logger.Info("user suspended", "userID", userID, "reason", reason, user)
-	return nil
-}
-
-func (s *service) UpdateUserStatus(ctx context.Context, userID string, status models.UserStatus) error {
 	if err := s.repo.UpdateUserStatus(ctx, userID, status); err != nil {
 		return response.InternalServerError("Failed to update user status", err)
 	}
 
 	logger.Info("user status updated", "userID", userID, "status", status)
+
+	s.recordAudit(ctx, adminID, "user.update_status", "user", userID, before, map[string]interface{}{"status": status}, "")
 	return nil
 }
 
@@ -131,6 +147,14 @@ func (s *service) GetDashboardStats(ctx context.Context) (map[string]interface{}
 	return stats, nil
 }
 
+func (s *service) GetLiveMetrics(ctx context.Context) (*livemetrics.Snapshot, error) {
+	snapshot, err := livemetrics.GetSnapshot(ctx)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to fetch live metrics", err)
+	}
+	return snapshot, nil
+}
+
 func (s *service) ListDriverProfiles(ctx context.Context, filters map[string]interface{}, page, limit int) (map[string]interface{}, error) {
 	if page < 1 {
 		page = 1
@@ -153,6 +177,42 @@ func (s *service) ListDriverProfiles(ctx context.Context, filters map[string]int
 	}, nil
 }
 
+func (s *service) ListDriversCashOwed(ctx context.Context, query dto.DriverCashOwedQuery) ([]dto.DriverCashOwedResponse, int64, error) {
+	query.SetDefaults()
+
+	since := time.Now().AddDate(0, 0, -query.Days)
+	rows, total, err := s.repo.ListDriversCashOwed(ctx, since, query.OnlyRestricted, query.Page, query.Limit)
+	if err != nil {
+		logger.Error("failed to list drivers with cash owed", "error", err)
+		return nil, 0, response.InternalServerError("Failed to fetch cash owed report", err)
+	}
+
+	result := make([]dto.DriverCashOwedResponse, len(rows))
+	for i, row := range rows {
+		limitUsed := 0.0
+		if row.MinBalanceThreshold < 0 {
+			limitUsed = math.Round(row.Balance/row.MinBalanceThreshold*10000) / 100
+		}
+		result[i] = dto.DriverCashOwedResponse{
+			DriverID:             row.DriverID,
+			UserID:               row.UserID,
+			Name:                 row.Name,
+			Phone:                row.Phone,
+			AmountOwed:           -row.Balance,
+			WalletBalance:        row.Balance,
+			Currency:             row.Currency,
+			NegativeBalanceLimit: row.MinBalanceThreshold,
+			LimitUsedPercent:     limitUsed,
+			IsRestricted:         row.IsRestricted,
+			CashRides:            row.CashRides,
+			CashCollected:        row.CashCollected,
+			Commission:           row.Commission,
+			LastCashRideAt:       row.LastCashRideAt,
+		}
+	}
+	return result, total, nil
+}
+
 func (s *service) ListServiceProviderProfiles(ctx context.Context, filters map[string]interface{}, page, limit int) (map[string]interface{}, error) {
 	if page < 1 {
 		page = 1
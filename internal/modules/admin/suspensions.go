@@ -0,0 +1,551 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	dto "github.com/umar5678/go-backend/internal/modules/admin/dto"
+	"github.com/umar5678/go-backend/internal/modules/notifications"
+	walletdto "github.com/umar5678/go-backend/internal/modules/wallet/dto"
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/services/health"
+	"github.com/umar5678/go-backend/internal/services/livemetrics"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+	websocketutil "github.com/umar5678/go-backend/internal/websocket/websocketutils"
+	"gorm.io/gorm"
+)
+
+const (
+	suspensionSweepInterval = time.Minute
+	suspensionSweepBatch    = 200
+
+	liftReasonExpired    = "expired"
+	liftReasonSuperseded = "superseded by ban"
+)
+
+// WalletHolds releases the customer's hold on rides and orders cancelled by a
+// suspension. It is satisfied by wallet.Service.
+type WalletHolds interface {
+	ReleaseHold(ctx context.Context, userID string, req walletdto.ReleaseHoldRequest) error
+}
+
+// RideMatcher finds a new driver for a ride taken from a suspended driver. It
+// is satisfied by rides.Service.
+type RideMatcher interface {
+	FindDriverForRide(ctx context.Context, rideID string) error
+}
+
+// WebhookPublisher queues events for external webhook subscribers. It is
+// satisfied by webhooks.Service and is optional.
+type WebhookPublisher interface {
+	Publish(ctx context.Context, eventType string, data map[string]interface{}) error
+}
+
+func (s *service) SetWalletHolds(walletHolds WalletHolds) {
+	s.walletHolds = walletHolds
+}
+
+func (s *service) SetRideMatcher(rideMatcher RideMatcher) {
+	s.rideMatcher = rideMatcher
+}
+
+func (s *service) SetWebhookPublisher(publisher WebhookPublisher) {
+	s.webhooks = publisher
+}
+
+func (s *service) SuspendUser(ctx context.Context, adminID, userID string, req dto.SuspendUserRequest) (*dto.SuspensionResponse, error) {
+	var endsAt *time.Time
+	if req.DurationHours > 0 {
+		until := time.Now().Add(time.Duration(req.DurationHours) * time.Hour)
+		endsAt = &until
+	}
+	return s.blockUser(ctx, adminID, userID, models.SuspensionKindSuspension, req.Reason, endsAt)
+}
+
+func (s *service) BanUser(ctx context.Context, adminID, userID string, req dto.BanUserRequest) (*dto.SuspensionResponse, error) {
+	return s.blockUser(ctx, adminID, userID, models.SuspensionKindBan, req.Reason, nil)
+}
+
+// blockUser records the suspension or ban, shuts the user out of the API and
+// their sockets, and settles the rides and orders they were part of.
+func (s *service) blockUser(ctx context.Context, adminID, userID string, kind models.SuspensionKind, reason string, endsAt *time.Time) (*dto.SuspensionResponse, error) {
+	if adminID == userID {
+		return nil, response.BadRequest("You cannot suspend or ban your own account")
+	}
+
+	user, err := s.repo.FindUserByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("User")
+		}
+		return nil, response.InternalServerError("Failed to fetch user", err)
+	}
+
+	now := time.Now()
+	suspension := &models.AccountSuspension{
+		UserID:         userID,
+		Kind:           kind,
+		Reason:         reason,
+		PreviousStatus: user.Status,
+		StartsAt:       now,
+		EndsAt:         endsAt,
+		CreatedBy:      adminID,
+	}
+
+	// A ban replaces a running suspension; anything else has to be lifted first.
+	active, err := s.repo.FindActiveSuspension(ctx, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, response.InternalServerError("Failed to check existing suspension", err)
+	}
+	var supersede *models.AccountSuspension
+	if active != nil {
+		if kind != models.SuspensionKindBan || active.Kind == models.SuspensionKindBan {
+			return nil, response.ConflictError(fmt.Sprintf("User already has an active %s", active.Kind))
+		}
+		active.LiftedAt = &now
+		active.LiftedBy = &adminID
+		active.LiftReason = liftReasonSuperseded
+		supersede = active
+		suspension.PreviousStatus = active.PreviousStatus
+	}
+
+	if err := s.repo.CreateSuspension(ctx, suspension, supersede); err != nil {
+		logger.Error("failed to create suspension", "error", err, "userID", userID, "kind", kind)
+		return nil, response.InternalServerError(fmt.Sprintf("Failed to apply %s", kind), err)
+	}
+
+	s.applyBlock(ctx, suspension)
+	s.disconnectUser(ctx, userID)
+
+	enforcement := s.releaseInFlightWork(ctx, adminID, user, suspension)
+
+	eventType := notifications.EventUserSuspended
+	action := "user.suspend"
+	if kind == models.SuspensionKindBan {
+		eventType = notifications.EventUserBanned
+		action = "user.ban"
+	}
+	s.publishAdminEvent(ctx, eventType, map[string]interface{}{
+		"user_id":   userID,
+		"reason":    reason,
+		"ends_at":   endsAt,
+		"timestamp": now,
+	})
+
+	logger.Info("user access blocked",
+		"userID", userID,
+		"kind", kind,
+		"endsAt", endsAt,
+		"adminID", adminID,
+		"cancelledRides", enforcement.CancelledRides,
+		"reassignedRides", enforcement.ReassignedRides,
+		"cancelledOrders", enforcement.CancelledOrders,
+		"reassignedOrders", enforcement.ReassignedOrders,
+		"needsReview", len(enforcement.NeedsReview),
+	)
+
+	s.recordAudit(ctx, adminID, action, "user", userID,
+		map[string]interface{}{"status": user.Status},
+		map[string]interface{}{"status": suspension.UserStatus(), "suspensionId": suspension.ID, "endsAt": endsAt}, reason)
+
+	resp := dto.ToSuspensionResponse(suspension)
+	resp.Enforcement = enforcement
+	return resp, nil
+}
+
+// ReinstateUser lifts the user's active suspension or ban ahead of time.
+func (s *service) ReinstateUser(ctx context.Context, adminID, userID string, req dto.ReinstateUserRequest) (*dto.SuspensionResponse, error) {
+	suspension, err := s.repo.FindActiveSuspension(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Active suspension")
+		}
+		return nil, response.InternalServerError("Failed to fetch suspension", err)
+	}
+
+	now := time.Now()
+	suspension.LiftedAt = &now
+	suspension.LiftedBy = &adminID
+	suspension.LiftReason = req.Reason
+
+	lifted, err := s.liftSuspension(ctx, suspension)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to reinstate user", err)
+	}
+	if !lifted {
+		return nil, response.ConflictError("Suspension was already lifted")
+	}
+
+	s.recordAudit(ctx, adminID, "user.reinstate", "user", userID,
+		map[string]interface{}{"status": suspension.UserStatus(), "suspensionId": suspension.ID},
+		map[string]interface{}{"status": suspension.PreviousStatus}, req.Reason)
+
+	return dto.ToSuspensionResponse(suspension), nil
+}
+
+func (s *service) ListSuspensions(ctx context.Context, req dto.ListSuspensionsRequest) ([]*dto.SuspensionResponse, int64, error) {
+	suspensions, total, err := s.repo.ListSuspensions(ctx, req.UserID, req.Kind, req.ActiveOnly, req.Page, req.Limit)
+	if err != nil {
+		return nil, 0, response.InternalServerError("Failed to fetch suspensions", err)
+	}
+
+	result := make([]*dto.SuspensionResponse, 0, len(suspensions))
+	for _, suspension := range suspensions {
+		result = append(result, dto.ToSuspensionResponse(suspension))
+	}
+	return result, total, nil
+}
+
+// ExpireSuspensions lifts timed suspensions whose end has passed.
+func (s *service) ExpireSuspensions(ctx context.Context) error {
+	now := time.Now()
+	expired, err := s.repo.FindExpiredSuspensions(ctx, now, suspensionSweepBatch)
+	if err != nil {
+		return err
+	}
+
+	for _, suspension := range expired {
+		suspension.LiftedAt = &now
+		suspension.LiftReason = liftReasonExpired
+		if _, err := s.liftSuspension(ctx, suspension); err != nil {
+			logger.Error("failed to expire suspension", "error", err, "suspensionID", suspension.ID, "userID", suspension.UserID)
+		}
+	}
+
+	if len(expired) > 0 {
+		logger.Info("expired suspensions lifted", "count", len(expired))
+	}
+	return nil
+}
+
+// SyncUserBlocks rebuilds the cached blocks from the database, so a Redis
+// flush cannot let a suspended user back in.
+func (s *service) SyncUserBlocks(ctx context.Context) error {
+	suspensions, err := s.repo.ListActiveSuspensions(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, suspension := range suspensions {
+		if suspension.IsActive(now) {
+			s.applyBlock(ctx, suspension)
+		}
+	}
+	return nil
+}
+
+func (s *service) liftSuspension(ctx context.Context, suspension *models.AccountSuspension) (bool, error) {
+	lifted, err := s.repo.LiftSuspension(ctx, suspension)
+	if err != nil || !lifted {
+		return lifted, err
+	}
+
+	if err := cache.ClearUserBlocked(ctx, suspension.UserID); err != nil {
+		logger.Warn("failed to clear cached user block", "error", err, "userID", suspension.UserID)
+	}
+
+	if profile, err := s.spRepo.FindByUserID(ctx, suspension.UserID); err == nil &&
+		profile.Status == models.ServiceProviderStatus(suspension.UserStatus()) {
+		if err := s.spRepo.UpdateStatus(ctx, profile.ID, models.SPStatusActive); err != nil {
+			logger.Warn("failed to restore provider status", "error", err, "providerID", profile.ID)
+		}
+	}
+
+	s.publishAdminEvent(ctx, notifications.EventUserReinstated, map[string]interface{}{
+		"user_id":   suspension.UserID,
+		"reason":    suspension.LiftReason,
+		"timestamp": time.Now(),
+	})
+
+	logger.Info("user access restored",
+		"userID", suspension.UserID,
+		"suspensionID", suspension.ID,
+		"kind", suspension.Kind,
+		"reason", suspension.LiftReason,
+	)
+	return true, nil
+}
+
+func (s *service) applyBlock(ctx context.Context, suspension *models.AccountSuspension) {
+	if err := cache.MarkUserBlocked(ctx, suspension.UserID, cache.UserBlock{
+		Kind:   string(suspension.Kind),
+		Reason: suspension.Reason,
+		Until:  suspension.EndsAt,
+	}); err != nil {
+		logger.Error("failed to cache user block", "error", err, "userID", suspension.UserID)
+	}
+}
+
+// disconnectUser drops the user's sockets on every instance. Login sessions
+// are kept so a timed suspension ends without forcing a new login; the block
+// turns their tokens away until then.
+func (s *service) disconnectUser(ctx context.Context, userID string) {
+	if err := websocket.RevokeAuthSession(ctx, userID, ""); err != nil {
+		logger.Warn("failed to disconnect websockets of blocked user", "error", err, "userID", userID)
+	}
+	if _, err := websocket.RevokeResumeTickets(ctx, userID, ""); err != nil {
+		logger.Warn("failed to revoke websocket resume tickets", "error", err, "userID", userID)
+	}
+}
+
+// releaseInFlightWork settles everything the user was part of when access was
+// blocked. Their own unstarted rides and orders are cancelled free of charge
+// and the holds released; rides and orders they were serving go back to
+// matching. Work already under way is left for an operator, as the doctor does.
+func (s *service) releaseInFlightWork(ctx context.Context, adminID string, user *models.User, suspension *models.AccountSuspension) *dto.SuspensionEnforcement {
+	result := &dto.SuspensionEnforcement{NeedsReview: []dto.SuspensionReviewItem{}}
+	reason := fmt.Sprintf("Account %s: %s", suspension.UserStatus(), suspension.Reason)
+
+	rides, err := s.repo.FindOpenRidesForUser(ctx, user.ID)
+	if err != nil {
+		logger.Error("failed to fetch open rides of blocked user", "error", err, "userID", user.ID)
+	}
+	for _, ride := range rides {
+		switch {
+		case ride.Status == "started":
+			result.NeedsReview = append(result.NeedsReview, dto.SuspensionReviewItem{EntityType: "ride", EntityID: ride.RideID, Status: ride.Status})
+		case ride.RiderID == user.ID:
+			s.cancelRideForSuspension(ctx, ride, reason, result)
+		default:
+			s.reassignRide(ctx, ride, reason, result)
+		}
+	}
+
+	if driver, err := s.drvRepo.FindDriverByUserID(ctx, user.ID); err == nil && driver != nil {
+		s.takeDriverOffline(ctx, driver.ID, user.ID)
+	}
+
+	providerID := ""
+	if profile, err := s.spRepo.FindByUserID(ctx, user.ID); err == nil && profile != nil {
+		providerID = profile.ID
+		if err := s.spRepo.UpdateStatus(ctx, profile.ID, models.ServiceProviderStatus(suspension.UserStatus())); err != nil {
+			logger.Warn("failed to update provider status", "error", err, "providerID", profile.ID)
+		}
+	}
+
+	orders, err := s.repo.FindOpenOrdersForUser(ctx, user.ID, providerID)
+	if err != nil {
+		logger.Error("failed to fetch open orders of blocked user", "error", err, "userID", user.ID)
+	}
+	for _, order := range orders {
+		switch {
+		case order.Status == "in_progress":
+			result.NeedsReview = append(result.NeedsReview, dto.SuspensionReviewItem{EntityType: "service_order", EntityID: order.OrderID, Status: order.Status})
+		case order.CustomerID == user.ID:
+			s.cancelOrderForSuspension(ctx, adminID, order, reason, result)
+		default:
+			notes := fmt.Sprintf("Provider %s is %s; order returned to search", providerID, suspension.UserStatus())
+			released, err := s.repo.ReleaseOrderToSearch(ctx, order.OrderID, order.Status, providerID, adminID, notes)
+			if err != nil {
+				logger.Error("failed to release order of blocked provider", "error", err, "orderID", order.OrderID)
+				continue
+			}
+			if released {
+				livemetrics.OrderSearching(ctx, order.OrderID)
+				result.ReassignedOrders++
+			}
+		}
+	}
+
+	return result
+}
+
+func (s *service) cancelRideForSuspension(ctx context.Context, ride SuspensionRideRow, reason string, result *dto.SuspensionEnforcement) {
+	cancelled, err := s.repo.CancelRideForSuspension(ctx, ride.RideID, ride.Status, reason)
+	if err != nil {
+		logger.Error("failed to cancel ride of blocked rider", "error", err, "rideID", ride.RideID)
+		return
+	}
+	if !cancelled {
+		return
+	}
+	result.CancelledRides++
+
+	livemetrics.RideEnded(ctx, ride.RideID)
+	cache.Delete(ctx, fmt.Sprintf("ride:active:%s", ride.RideID))
+
+	if s.releaseHold(ctx, ride.RiderID, ride.WalletHoldID, "ride", ride.RideID) {
+		result.ReleasedHolds++
+	}
+
+	if ride.DriverID != nil {
+		if driver, err := s.drvRepo.FindDriverByUserID(ctx, *ride.DriverID); err == nil && driver != nil {
+			if err := s.drvRepo.UpdateDriverStatus(ctx, driver.ID, "online"); err != nil {
+				logger.Warn("failed to free driver of cancelled ride", "error", err, "driverID", driver.ID)
+			}
+			cache.Delete(ctx, fmt.Sprintf("driver:busy:%s", driver.ID))
+			cache.Delete(ctx, fmt.Sprintf("driver:active:ride:%s", driver.ID))
+		}
+		websocketutil.SendToUser(*ride.DriverID, websocket.TypeRideCancelled, map[string]interface{}{
+			"rideId":    ride.RideID,
+			"message":   "Ride was cancelled by support",
+			"timestamp": time.Now().UTC(),
+		})
+	}
+}
+
+// reassignRide takes a not yet started ride away from a blocked driver and
+// sends it back to matching. Without a matcher the ride is cancelled instead,
+// so the rider is not left waiting.
+func (s *service) reassignRide(ctx context.Context, ride SuspensionRideRow, reason string, result *dto.SuspensionEnforcement) {
+	if s.rideMatcher == nil {
+		cancelled, err := s.repo.CancelRideForSuspension(ctx, ride.RideID, ride.Status, reason)
+		if err != nil || !cancelled {
+			logger.Error("failed to cancel ride of blocked driver", "error", err, "rideID", ride.RideID)
+			return
+		}
+		result.CancelledRides++
+		livemetrics.RideEnded(ctx, ride.RideID)
+		cache.Delete(ctx, fmt.Sprintf("ride:active:%s", ride.RideID))
+		if s.releaseHold(ctx, ride.RiderID, ride.WalletHoldID, "ride", ride.RideID) {
+			result.ReleasedHolds++
+		}
+		websocketutil.SendToUser(ride.RiderID, websocket.TypeRideCancelled, map[string]interface{}{
+			"rideId":    ride.RideID,
+			"message":   "Your driver is no longer available and the ride was cancelled free of charge",
+			"timestamp": time.Now().UTC(),
+		})
+		return
+	}
+
+	returned, err := s.repo.ReturnRideToSearch(ctx, ride.RideID, ride.Status)
+	if err != nil {
+		logger.Error("failed to return ride to search", "error", err, "rideID", ride.RideID)
+		return
+	}
+	if !returned {
+		return
+	}
+	result.ReassignedRides++
+	cache.Delete(ctx, fmt.Sprintf("ride:active:%s", ride.RideID))
+
+	websocketutil.SendToUser(ride.RiderID, websocket.TypeRideStatusUpdate, map[string]interface{}{
+		"rideId":    ride.RideID,
+		"status":    "searching",
+		"message":   "Your driver is no longer available. Finding you another driver",
+		"timestamp": time.Now().UTC(),
+	})
+
+	rideID := ride.RideID
+	go func() {
+		bgCtx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+		if err := s.rideMatcher.FindDriverForRide(bgCtx, rideID); err != nil {
+			logger.Error("failed to rematch ride of blocked driver", "error", err, "rideID", rideID)
+		}
+	}()
+}
+
+func (s *service) cancelOrderForSuspension(ctx context.Context, adminID string, order SuspensionOrderRow, reason string, result *dto.SuspensionEnforcement) {
+	cancelled, err := s.repo.CancelOrderForSuspension(ctx, order.OrderID, order.Status, adminID, reason, order.TotalPrice)
+	if err != nil {
+		logger.Error("failed to cancel order of blocked customer", "error", err, "orderID", order.OrderID)
+		return
+	}
+	if !cancelled {
+		return
+	}
+	result.CancelledOrders++
+
+	livemetrics.OrderNotSearching(ctx, order.OrderID)
+
+	if s.releaseHold(ctx, order.CustomerID, order.WalletHoldID, "service_order", order.OrderID) {
+		result.ReleasedHolds++
+	}
+
+	s.publishSuspensionCancellation(ctx, order, reason)
+}
+
+func (s *service) publishSuspensionCancellation(ctx context.Context, order SuspensionOrderRow, reason string) {
+	if s.webhooks == nil {
+		return
+	}
+	data := map[string]interface{}{
+		"orderId":         order.OrderID,
+		"orderNumber":     order.OrderNumber,
+		"customerId":      order.CustomerID,
+		"totalPrice":      order.TotalPrice,
+		"previousStatus":  order.Status,
+		"cancelledBy":     "admin",
+		"cancelledAt":     time.Now(),
+		"reason":          reason,
+		"cancellationFee": 0,
+		"refundAmount":    order.TotalPrice,
+	}
+	if order.AssignedProviderID != nil {
+		data["providerId"] = *order.AssignedProviderID
+	}
+	if err := s.webhooks.Publish(ctx, models.WebhookEventOrderCancelled, data); err != nil {
+		logger.Warn("failed to publish order cancelled webhook", "error", err, "orderID", order.OrderID)
+	}
+}
+
+func (s *service) releaseHold(ctx context.Context, userID string, holdID *string, entityType, entityID string) bool {
+	if holdID == nil {
+		return false
+	}
+	if s.walletHolds == nil {
+		logger.Warn("wallet holds not configured, hold left active", "holdID", *holdID, "entityType", entityType, "entityID", entityID)
+		return false
+	}
+	if err := s.walletHolds.ReleaseHold(ctx, userID, walletdto.ReleaseHoldRequest{HoldID: *holdID}); err != nil {
+		logger.Error("failed to release hold of cancelled work", "error", err, "holdID", *holdID, "entityType", entityType, "entityID", entityID)
+		return false
+	}
+	return true
+}
+
+func (s *service) takeDriverOffline(ctx context.Context, driverID, userID string) {
+	if err := s.drvRepo.UpdateDriverStatus(ctx, driverID, "offline"); err != nil {
+		logger.Warn("failed to take blocked driver offline", "error", err, "driverID", driverID)
+	}
+	cache.SessionClient.SRem(ctx, livemetrics.OnlineDriversKey, driverID)
+	cache.Delete(ctx, fmt.Sprintf("driver:busy:%s", driverID))
+	cache.Delete(ctx, fmt.Sprintf("driver:active:ride:%s", driverID))
+	cache.Delete(ctx, fmt.Sprintf("driver:profile:%s", userID))
+}
+
+// SuspensionSweeper lifts timed suspensions once they run out and keeps the
+// cached blocks in line with the database.
+type SuspensionSweeper struct {
+	service Service
+}
+
+func NewSuspensionSweeper(service Service) *SuspensionSweeper {
+	return &SuspensionSweeper{service: service}
+}
+
+func (w *SuspensionSweeper) Start(ctx context.Context) {
+	if err := w.service.SyncUserBlocks(ctx); err != nil {
+		logger.Error("failed to sync user blocks", "error", err)
+	}
+
+	heartbeat := health.NewHeartbeat("suspension_sweeper", suspensionSweepInterval)
+
+	go func() {
+		ticker := time.NewTicker(suspensionSweepInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				runCtx, cancel := context.WithTimeout(ctx, time.Minute)
+				if err := w.service.ExpireSuspensions(runCtx); err != nil {
+					logger.Error("suspension sweep failed", "error", err)
+				}
+				cancel()
+				heartbeat.Beat()
+			}
+		}
+	}()
+
+	logger.Info("suspension sweeper started", "interval", suspensionSweepInterval)
+}
@@ -0,0 +1,110 @@
+package dto
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+type CreateAnnouncementRequest struct {
+	Title     string  `json:"title" binding:"required,min=3,max=200"`
+	Body      string  `json:"body" binding:"required,min=1,max=5000"`
+	Placement string  `json:"placement" binding:"omitempty,oneof=feed banner"`
+	Priority  int     `json:"priority" binding:"omitempty,min=0,max=100"`
+	ImageURL  *string `json:"imageUrl" binding:"omitempty,url,max=500"`
+	ActionURL *string `json:"actionUrl" binding:"omitempty,max=500"`
+	Audience  string  `json:"audience" binding:"omitempty,oneof=all riders drivers providers"`
+	// CityID limits the announcement to users in the city.
+	CityID *string `json:"cityId" binding:"omitempty,uuid"`
+	// ServiceCategory limits a providers announcement to one category.
+	ServiceCategory *string `json:"serviceCategory" binding:"omitempty,max=100"`
+	// StartsAt defaults to now.
+	StartsAt *time.Time `json:"startsAt"`
+	EndsAt   *time.Time `json:"endsAt"`
+}
+
+func (r *CreateAnnouncementRequest) Validate() error {
+	if strings.TrimSpace(r.Title) == "" || strings.TrimSpace(r.Body) == "" {
+		return errors.New("title and body are required")
+	}
+	if r.Placement == "" {
+		r.Placement = "feed"
+	}
+	if r.Audience == "" {
+		r.Audience = "all"
+	}
+	if r.ServiceCategory != nil && r.Audience != "providers" {
+		return errors.New("serviceCategory can only be used with the providers audience")
+	}
+	if r.StartsAt != nil && r.EndsAt != nil && !r.EndsAt.After(*r.StartsAt) {
+		return errors.New("endsAt must be after startsAt")
+	}
+	if r.EndsAt != nil && !r.EndsAt.After(time.Now()) {
+		return errors.New("endsAt must be in the future")
+	}
+	return nil
+}
+
+// UpdateAnnouncementRequest changes the fields that are set. The Clear
+// flags remove the optional targeting and the end of the window.
+type UpdateAnnouncementRequest struct {
+	Title                *string    `json:"title" binding:"omitempty,min=3,max=200"`
+	Body                 *string    `json:"body" binding:"omitempty,min=1,max=5000"`
+	Placement            *string    `json:"placement" binding:"omitempty,oneof=feed banner"`
+	Priority             *int       `json:"priority" binding:"omitempty,min=0,max=100"`
+	ImageURL             *string    `json:"imageUrl" binding:"omitempty,url,max=500"`
+	ActionURL            *string    `json:"actionUrl" binding:"omitempty,max=500"`
+	Audience             *string    `json:"audience" binding:"omitempty,oneof=all riders drivers providers"`
+	CityID               *string    `json:"cityId" binding:"omitempty,uuid"`
+	ClearCity            bool       `json:"clearCity"`
+	ServiceCategory      *string    `json:"serviceCategory" binding:"omitempty,max=100"`
+	ClearServiceCategory bool       `json:"clearServiceCategory"`
+	StartsAt             *time.Time `json:"startsAt"`
+	EndsAt               *time.Time `json:"endsAt"`
+	ClearEndsAt          bool       `json:"clearEndsAt"`
+}
+
+func (r *UpdateAnnouncementRequest) Validate() error {
+	if r.CityID != nil && r.ClearCity {
+		return errors.New("cityId and clearCity cannot be used together")
+	}
+	if r.ServiceCategory != nil && r.ClearServiceCategory {
+		return errors.New("serviceCategory and clearServiceCategory cannot be used together")
+	}
+	if r.EndsAt != nil && r.ClearEndsAt {
+		return errors.New("endsAt and clearEndsAt cannot be used together")
+	}
+	return nil
+}
+
+type ListAnnouncementsRequest struct {
+	// Status is where the announcement is in its window.
+	Status    string `form:"status" binding:"omitempty,oneof=scheduled live ended"`
+	Audience  string `form:"audience" binding:"omitempty,oneof=all riders drivers providers"`
+	Placement string `form:"placement" binding:"omitempty,oneof=feed banner"`
+	CityID    string `form:"cityId" binding:"omitempty,uuid"`
+	Page      int    `form:"page" binding:"omitempty,min=1"`
+	Limit     int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (r *ListAnnouncementsRequest) SetDefaults() {
+	if r.Page <= 0 {
+		r.Page = 1
+	}
+	if r.Limit <= 0 {
+		r.Limit = 20
+	}
+	if r.Limit > 100 {
+		r.Limit = 100
+	}
+}
+
+// ActiveAnnouncementsRequest is how a client fetches what to show. The
+// location picks the city; drivers without one are placed by their last
+// reported position.
+type ActiveAnnouncementsRequest struct {
+	Placement  string   `form:"placement" binding:"omitempty,oneof=feed banner"`
+	Latitude   *float64 `form:"lat" binding:"omitempty,min=-90,max=90"`
+	Longitude  *float64 `form:"lng" binding:"omitempty,min=-180,max=180"`
+	UnreadOnly bool     `form:"unreadOnly"`
+}
@@ -0,0 +1,96 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+// AnnouncementResponse is an announcement as the apps show it.
+type AnnouncementResponse struct {
+	ID        string     `json:"id"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	Placement string     `json:"placement"`
+	Priority  int        `json:"priority"`
+	ImageURL  *string    `json:"imageUrl,omitempty"`
+	ActionURL *string    `json:"actionUrl,omitempty"`
+	StartsAt  time.Time  `json:"startsAt"`
+	EndsAt    *time.Time `json:"endsAt,omitempty"`
+	Read      bool       `json:"read"`
+	ReadAt    *time.Time `json:"readAt,omitempty"`
+}
+
+// AdminAnnouncementResponse adds the targeting and delivery details.
+type AdminAnnouncementResponse struct {
+	ID              string     `json:"id"`
+	Title           string     `json:"title"`
+	Body            string     `json:"body"`
+	Placement       string     `json:"placement"`
+	Priority        int        `json:"priority"`
+	ImageURL        *string    `json:"imageUrl,omitempty"`
+	ActionURL       *string    `json:"actionUrl,omitempty"`
+	Audience        string     `json:"audience"`
+	CityID          *string    `json:"cityId,omitempty"`
+	ServiceCategory *string    `json:"serviceCategory,omitempty"`
+	StartsAt        time.Time  `json:"startsAt"`
+	EndsAt          *time.Time `json:"endsAt,omitempty"`
+	Status          string     `json:"status"`
+	PublishedAt     *time.Time `json:"publishedAt,omitempty"`
+	ReadCount       int64      `json:"readCount"`
+	CreatedBy       string     `json:"createdBy"`
+	CreatedAt       time.Time  `json:"createdAt"`
+	UpdatedAt       time.Time  `json:"updatedAt"`
+}
+
+// ToAnnouncementResponse marks the announcement read when readAt is set.
+func ToAnnouncementResponse(a *models.Announcement, readAt *time.Time) *AnnouncementResponse {
+	return &AnnouncementResponse{
+		ID:        a.ID,
+		Title:     a.Title,
+		Body:      a.Body,
+		Placement: a.Placement,
+		Priority:  a.Priority,
+		ImageURL:  a.ImageURL,
+		ActionURL: a.ActionURL,
+		StartsAt:  a.StartsAt,
+		EndsAt:    a.EndsAt,
+		Read:      readAt != nil,
+		ReadAt:    readAt,
+	}
+}
+
+func ToAdminAnnouncementResponse(a *models.Announcement, readCount int64) *AdminAnnouncementResponse {
+	return &AdminAnnouncementResponse{
+		ID:              a.ID,
+		Title:           a.Title,
+		Body:            a.Body,
+		Placement:       a.Placement,
+		Priority:        a.Priority,
+		ImageURL:        a.ImageURL,
+		ActionURL:       a.ActionURL,
+		Audience:        a.Audience,
+		CityID:          a.CityID,
+		ServiceCategory: a.ServiceCategory,
+		StartsAt:        a.StartsAt,
+		EndsAt:          a.EndsAt,
+		Status:          AnnouncementStatus(a, time.Now()),
+		PublishedAt:     a.PublishedAt,
+		ReadCount:       readCount,
+		CreatedBy:       a.CreatedBy,
+		CreatedAt:       a.CreatedAt,
+		UpdatedAt:       a.UpdatedAt,
+	}
+}
+
+// AnnouncementStatus is scheduled, live or ended.
+func AnnouncementStatus(a *models.Announcement, now time.Time) string {
+	switch {
+	case now.Before(a.StartsAt):
+		return "scheduled"
+	case a.IsLive(now):
+		return "live"
+	default:
+		return "ended"
+	}
+}
@@ -0,0 +1,189 @@
+package announcements
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/announcements/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// ListActiveAnnouncements godoc
+// @Summary List active announcements
+// @Description Live announcements and banners for the caller's role, city and provider category, most important first
+// @Tags announcements
+// @Security BearerAuth
+// @Produce json
+// @Param placement query string false "feed or banner"
+// @Param lat query number false "Latitude used to find the caller's city"
+// @Param lng query number false "Longitude used to find the caller's city"
+// @Param unreadOnly query bool false "Only announcements the caller has not read"
+// @Success 200 {object} response.Response{data=[]dto.AnnouncementResponse}
+// @Router /announcements [get]
+func (h *Handler) ListActiveAnnouncements(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+
+	var req dto.ActiveAnnouncementsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+
+	items, err := h.service.ListActive(c.Request.Context(), userID.(string), models.UserRole(role.(string)), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, items, "Announcements retrieved successfully")
+}
+
+// MarkAnnouncementRead godoc
+// @Summary Mark an announcement read
+// @Tags announcements
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Announcement ID"
+// @Success 200 {object} response.Response
+// @Router /announcements/{id}/read [post]
+func (h *Handler) MarkAnnouncementRead(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+
+	if err := h.service.MarkRead(c.Request.Context(), userID.(string), models.UserRole(role.(string)), c.Param("id")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, nil, "Announcement marked as read")
+}
+
+// CreateAnnouncement godoc
+// @Summary Create an announcement
+// @Description Announcements starting now are pushed to connected users straight away; scheduled ones when their window opens
+// @Tags admin-announcements
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateAnnouncementRequest true "Announcement details"
+// @Success 200 {object} response.Response{data=dto.AdminAnnouncementResponse}
+// @Router /admin/announcements [post]
+func (h *Handler) CreateAnnouncement(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req dto.CreateAnnouncementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	announcement, err := h.service.CreateAnnouncement(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, announcement, "Announcement created successfully")
+}
+
+// ListAnnouncements godoc
+// @Summary List announcements
+// @Tags admin-announcements
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "scheduled, live or ended"
+// @Param audience query string false "Filter by audience"
+// @Param placement query string false "Filter by placement"
+// @Param cityId query string false "Filter by city"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Response{data=[]dto.AdminAnnouncementResponse}
+// @Router /admin/announcements [get]
+func (h *Handler) ListAnnouncements(c *gin.Context) {
+	var req dto.ListAnnouncementsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+	req.SetDefaults()
+
+	items, total, err := h.service.ListAnnouncements(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pagination := response.NewPaginationMeta(total, req.Page, req.Limit)
+	response.Paginated(c, items, pagination, "Announcements retrieved successfully")
+}
+
+// GetAnnouncement godoc
+// @Summary Get an announcement
+// @Tags admin-announcements
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Announcement ID"
+// @Success 200 {object} response.Response{data=dto.AdminAnnouncementResponse}
+// @Router /admin/announcements/{id} [get]
+func (h *Handler) GetAnnouncement(c *gin.Context) {
+	announcement, err := h.service.GetAnnouncement(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, announcement, "Announcement retrieved successfully")
+}
+
+// UpdateAnnouncement godoc
+// @Summary Update an announcement
+// @Description Changes to a live announcement are pushed to connected users
+// @Tags admin-announcements
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Announcement ID"
+// @Param request body dto.UpdateAnnouncementRequest true "Fields to change"
+// @Success 200 {object} response.Response{data=dto.AdminAnnouncementResponse}
+// @Router /admin/announcements/{id} [patch]
+func (h *Handler) UpdateAnnouncement(c *gin.Context) {
+	var req dto.UpdateAnnouncementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	announcement, err := h.service.UpdateAnnouncement(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, announcement, "Announcement updated successfully")
+}
+
+// DeleteAnnouncement godoc
+// @Summary Delete an announcement
+// @Description Connected users are told to stop showing it
+// @Tags admin-announcements
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Announcement ID"
+// @Success 200 {object} response.Response
+// @Router /admin/announcements/{id} [delete]
+func (h *Handler) DeleteAnnouncement(c *gin.Context) {
+	if err := h.service.DeleteAnnouncement(c.Request.Context(), c.Param("id")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, nil, "Announcement deleted successfully")
+}
@@ -0,0 +1,146 @@
+package announcements
+
+import (
+	"context"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/announcements/dto"
+	"github.com/umar5678/go-backend/internal/services/health"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/websocket"
+	websocketutil "github.com/umar5678/go-backend/internal/websocket/websocketutils"
+)
+
+const publishInterval = 30 * time.Second
+
+// audienceRoles are the user roles an untargeted announcement is broadcast to.
+var audienceRoles = map[string][]models.UserRole{
+	models.AnnouncementAudienceAll:       {models.RoleRider, models.RoleDriver, models.RoleServiceProvider, models.RoleHandyman, models.RoleDeliveryPerson},
+	models.AnnouncementAudienceRiders:    {models.RoleRider},
+	models.AnnouncementAudienceDrivers:   {models.RoleDriver},
+	models.AnnouncementAudienceProviders: {models.RoleServiceProvider, models.RoleHandyman, models.RoleDeliveryPerson},
+}
+
+func (s *service) pushAsync(a *models.Announcement) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		s.push(ctx, a)
+	}()
+}
+
+// push sends the announcement to the connected users it is for, who show it
+// or replace the copy they already have.
+func (s *service) push(ctx context.Context, a *models.Announcement) {
+	resp := dto.ToAnnouncementResponse(a, nil)
+	s.deliver(ctx, a, websocket.TypeAnnouncement, map[string]interface{}{
+		"id":        resp.ID,
+		"title":     resp.Title,
+		"body":      resp.Body,
+		"placement": resp.Placement,
+		"priority":  resp.Priority,
+		"imageUrl":  resp.ImageURL,
+		"actionUrl": resp.ActionURL,
+		"startsAt":  resp.StartsAt,
+		"endsAt":    resp.EndsAt,
+	})
+}
+
+// withdraw tells connected users to stop showing the announcement.
+func (s *service) withdraw(ctx context.Context, a *models.Announcement) {
+	s.deliver(ctx, a, websocket.TypeAnnouncementWithdrawn, map[string]interface{}{
+		"id": a.ID,
+	})
+}
+
+// deliver reaches the users an announcement targets. Drivers are placed in a
+// city by their last position and providers are looked up by category. The
+// server does not know which city riders or providers are in, so those
+// announcements are not pushed; the apps pick them up on their next fetch.
+func (s *service) deliver(ctx context.Context, a *models.Announcement, messageType websocket.MessageType, data map[string]interface{}) {
+	switch {
+	case a.CityID != nil && (a.Audience == models.AnnouncementAudienceDrivers || a.Audience == models.AnnouncementAudienceAll):
+		s.deliverToDriversIn(ctx, *a.CityID, messageType, data)
+	case a.CityID != nil:
+		return
+	case a.ServiceCategory != nil:
+		userIDs, err := s.repo.ProviderUserIDs(ctx, *a.ServiceCategory)
+		if err != nil {
+			logger.Error("failed to find providers for announcement", "error", err, "announcementID", a.ID)
+			return
+		}
+		s.sendToOnline(userIDs, messageType, data)
+	default:
+		for _, role := range audienceRoles[a.Audience] {
+			websocketutil.BroadcastToRole(string(role), messageType, data)
+		}
+	}
+}
+
+func (s *service) deliverToDriversIn(ctx context.Context, cityID string, messageType websocket.MessageType, data map[string]interface{}) {
+	city, err := s.repo.FindCity(ctx, cityID)
+	if err != nil {
+		logger.Error("failed to load city for announcement", "error", err, "cityID", cityID)
+		return
+	}
+	positions, err := s.repo.ActiveDriverPositions(ctx)
+	if err != nil {
+		logger.Error("failed to find drivers for announcement", "error", err, "cityID", cityID)
+		return
+	}
+
+	userIDs := make([]string, 0, len(positions))
+	for _, pos := range positions {
+		if city.Contains(pos.Lat, pos.Lon) {
+			userIDs = append(userIDs, pos.UserID)
+		}
+	}
+	s.sendToOnline(userIDs, messageType, data)
+}
+
+func (s *service) sendToOnline(userIDs []string, messageType websocket.MessageType, data map[string]interface{}) {
+	for _, userID := range userIDs {
+		if !websocketutil.IsUserOnline(userID) {
+			continue
+		}
+		if err := websocketutil.SendToUser(userID, messageType, data); err != nil {
+			logger.Warn("failed to push announcement", "error", err, "userID", userID)
+		}
+	}
+}
+
+// Publisher pushes scheduled announcements once their window opens. It runs
+// in the API, where the WebSocket connections are.
+type Publisher struct {
+	service Service
+}
+
+func NewPublisher(service Service) *Publisher {
+	return &Publisher{service: service}
+}
+
+func (w *Publisher) Start(ctx context.Context) {
+	heartbeat := health.NewHeartbeat("announcement_publisher", publishInterval)
+
+	go func() {
+		ticker := time.NewTicker(publishInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				runCtx, cancel := context.WithTimeout(ctx, time.Minute)
+				if _, err := w.service.PublishDue(runCtx); err != nil {
+					logger.Error("announcement publish failed", "error", err)
+				}
+				cancel()
+				heartbeat.Beat()
+			}
+		}
+	}()
+
+	logger.Info("announcement publisher started", "interval", publishInterval)
+}
@@ -0,0 +1,259 @@
+package announcements
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/announcements/dto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Audience narrows the announcements a user sees. An empty CityID or
+// ServiceCategory only matches announcements not targeted by it.
+type Audience struct {
+	Audience        string
+	CityID          string
+	ServiceCategory string
+}
+
+type driverPosition struct {
+	UserID string
+	Lat    float64
+	Lon    float64
+}
+
+type Repository interface {
+	Create(ctx context.Context, a *models.Announcement) error
+	Update(ctx context.Context, a *models.Announcement) error
+	Delete(ctx context.Context, id string) error
+	FindByID(ctx context.Context, id string) (*models.Announcement, error)
+	List(ctx context.Context, req dto.ListAnnouncementsRequest, now time.Time) ([]*models.Announcement, int64, error)
+	ListLive(ctx context.Context, audience Audience, placement string, now time.Time) ([]*models.Announcement, error)
+
+	// ClaimUnpublished marks live announcements that have not been pushed
+	// yet as published and returns them, so each is pushed once.
+	ClaimUnpublished(ctx context.Context, now time.Time, limit int) ([]*models.Announcement, error)
+
+	MarkRead(ctx context.Context, announcementID, userID string) error
+	ReadTimes(ctx context.Context, userID string, announcementIDs []string) (map[string]time.Time, error)
+	CountReads(ctx context.Context, announcementIDs []string) (map[string]int64, error)
+
+	FindCity(ctx context.Context, id string) (*models.City, error)
+	DriverPosition(ctx context.Context, userID string) (*driverPosition, error)
+	ActiveDriverPositions(ctx context.Context) ([]driverPosition, error)
+	ProviderCategory(ctx context.Context, userID string) (string, error)
+	ProviderUserIDs(ctx context.Context, category string) ([]string, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Create(ctx context.Context, a *models.Announcement) error {
+	return r.db.WithContext(ctx).Create(a).Error
+}
+
+func (r *repository) Update(ctx context.Context, a *models.Announcement) error {
+	return r.db.WithContext(ctx).Save(a).Error
+}
+
+func (r *repository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.Announcement{}).Error
+}
+
+func (r *repository) FindByID(ctx context.Context, id string) (*models.Announcement, error) {
+	var a models.Announcement
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&a).Error; err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *repository) List(ctx context.Context, req dto.ListAnnouncementsRequest, now time.Time) ([]*models.Announcement, int64, error) {
+	var items []*models.Announcement
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Announcement{})
+	switch req.Status {
+	case "scheduled":
+		query = query.Where("starts_at > ?", now)
+	case "live":
+		query = query.Where("starts_at <= ? AND (ends_at IS NULL OR ends_at > ?)", now, now)
+	case "ended":
+		query = query.Where("ends_at <= ?", now)
+	}
+	if req.Audience != "" {
+		query = query.Where("audience = ?", req.Audience)
+	}
+	if req.Placement != "" {
+		query = query.Where("placement = ?", req.Placement)
+	}
+	if req.CityID != "" {
+		query = query.Where("city_id = ?", req.CityID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count announcements: %w", err)
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Order("starts_at DESC").Offset(offset).Limit(req.Limit).Find(&items).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch announcements: %w", err)
+	}
+	return items, total, nil
+}
+
+func (r *repository) ListLive(ctx context.Context, audience Audience, placement string, now time.Time) ([]*models.Announcement, error) {
+	query := r.db.WithContext(ctx).
+		Where("starts_at <= ? AND (ends_at IS NULL OR ends_at > ?)", now, now).
+		Where("audience IN ?", []string{models.AnnouncementAudienceAll, audience.Audience})
+
+	if audience.CityID != "" {
+		query = query.Where("(city_id IS NULL OR city_id = ?)", audience.CityID)
+	} else {
+		query = query.Where("city_id IS NULL")
+	}
+	if audience.ServiceCategory != "" {
+		query = query.Where("(service_category IS NULL OR service_category = ?)", audience.ServiceCategory)
+	} else {
+		query = query.Where("service_category IS NULL")
+	}
+	if placement != "" {
+		query = query.Where("placement = ?", placement)
+	}
+
+	var items []*models.Announcement
+	err := query.Order("priority DESC, starts_at DESC").Limit(50).Find(&items).Error
+	return items, err
+}
+
+func (r *repository) ClaimUnpublished(ctx context.Context, now time.Time, limit int) ([]*models.Announcement, error) {
+	var items []*models.Announcement
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("published_at IS NULL AND starts_at <= ? AND (ends_at IS NULL OR ends_at > ?)", now, now).
+			Order("starts_at").
+			Limit(limit).
+			Find(&items).Error; err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+
+		ids := make([]string, len(items))
+		for i, a := range items {
+			ids[i] = a.ID
+			a.PublishedAt = &now
+		}
+		return tx.Model(&models.Announcement{}).Where("id IN ?", ids).Update("published_at", now).Error
+	})
+	return items, err
+}
+
+func (r *repository) MarkRead(ctx context.Context, announcementID, userID string) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.AnnouncementRead{AnnouncementID: announcementID, UserID: userID}).Error
+}
+
+func (r *repository) ReadTimes(ctx context.Context, userID string, announcementIDs []string) (map[string]time.Time, error) {
+	result := make(map[string]time.Time, len(announcementIDs))
+	if len(announcementIDs) == 0 {
+		return result, nil
+	}
+
+	var reads []models.AnnouncementRead
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND announcement_id IN ?", userID, announcementIDs).
+		Find(&reads).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, read := range reads {
+		result[read.AnnouncementID] = read.ReadAt
+	}
+	return result, nil
+}
+
+func (r *repository) CountReads(ctx context.Context, announcementIDs []string) (map[string]int64, error) {
+	result := make(map[string]int64, len(announcementIDs))
+	if len(announcementIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		AnnouncementID string
+		Count          int64
+	}
+	err := r.db.WithContext(ctx).
+		Model(&models.AnnouncementRead{}).
+		Select("announcement_id, COUNT(*) AS count").
+		Where("announcement_id IN ?", announcementIDs).
+		Group("announcement_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		result[row.AnnouncementID] = row.Count
+	}
+	return result, nil
+}
+
+func (r *repository) FindCity(ctx context.Context, id string) (*models.City, error) {
+	var city models.City
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&city).Error; err != nil {
+		return nil, err
+	}
+	return &city, nil
+}
+
+func (r *repository) DriverPosition(ctx context.Context, userID string) (*driverPosition, error) {
+	var pos driverPosition
+	err := r.db.WithContext(ctx).
+		Model(&models.DriverProfile{}).
+		Select("user_id, ST_Y(current_location) AS lat, ST_X(current_location) AS lon").
+		Where("user_id = ? AND current_location IS NOT NULL", userID).
+		Take(&pos).Error
+	if err != nil {
+		return nil, err
+	}
+	return &pos, nil
+}
+
+func (r *repository) ActiveDriverPositions(ctx context.Context) ([]driverPosition, error) {
+	var positions []driverPosition
+	err := r.db.WithContext(ctx).
+		Model(&models.DriverProfile{}).
+		Select("user_id, ST_Y(current_location) AS lat, ST_X(current_location) AS lon").
+		Where("status <> ? AND current_location IS NOT NULL", "offline").
+		Scan(&positions).Error
+	return positions, err
+}
+
+func (r *repository) ProviderCategory(ctx context.Context, userID string) (string, error) {
+	var category string
+	err := r.db.WithContext(ctx).
+		Model(&models.ServiceProviderProfile{}).
+		Select("service_category").
+		Where("user_id = ?", userID).
+		Take(&category).Error
+	return category, err
+}
+
+func (r *repository) ProviderUserIDs(ctx context.Context, category string) ([]string, error) {
+	var ids []string
+	err := r.db.WithContext(ctx).
+		Model(&models.ServiceProviderProfile{}).
+		Where("service_category = ?", category).
+		Pluck("user_id", &ids).Error
+	return ids, err
+}
@@ -0,0 +1,26 @@
+package announcements
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/middleware"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
+	announcements := router.Group("/announcements")
+	announcements.Use(authMiddleware)
+	{
+		announcements.GET("", handler.ListActiveAnnouncements)
+		announcements.POST("/:id/read", handler.MarkAnnouncementRead)
+	}
+
+	admin := router.Group("/admin/announcements")
+	admin.Use(authMiddleware)
+	admin.Use(middleware.RequireAdmin())
+	{
+		admin.POST("", handler.CreateAnnouncement)
+		admin.GET("", handler.ListAnnouncements)
+		admin.GET("/:id", handler.GetAnnouncement)
+		admin.PATCH("/:id", handler.UpdateAnnouncement)
+		admin.DELETE("/:id", handler.DeleteAnnouncement)
+	}
+}
@@ -0,0 +1,386 @@
+package announcements
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/announcements/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+const publishBatchSize = 50
+
+// CityResolver finds the operating city a location falls in. It is
+// satisfied by cities.Service; without it city-targeted announcements are
+// only pushed, never listed.
+type CityResolver interface {
+	ResolveServiceArea(ctx context.Context, lat, lon float64) (*models.City, error)
+}
+
+type Service interface {
+	CreateAnnouncement(ctx context.Context, adminID string, req dto.CreateAnnouncementRequest) (*dto.AdminAnnouncementResponse, error)
+	UpdateAnnouncement(ctx context.Context, id string, req dto.UpdateAnnouncementRequest) (*dto.AdminAnnouncementResponse, error)
+	DeleteAnnouncement(ctx context.Context, id string) error
+	GetAnnouncement(ctx context.Context, id string) (*dto.AdminAnnouncementResponse, error)
+	ListAnnouncements(ctx context.Context, req dto.ListAnnouncementsRequest) ([]*dto.AdminAnnouncementResponse, int64, error)
+
+	// ListActive returns the live announcements for the user, most
+	// important first, with whether they have read each one.
+	ListActive(ctx context.Context, userID string, role models.UserRole, req dto.ActiveAnnouncementsRequest) ([]*dto.AnnouncementResponse, error)
+	MarkRead(ctx context.Context, userID string, role models.UserRole, id string) error
+
+	// PublishDue pushes announcements whose window has opened since they
+	// were scheduled, and returns how many it pushed.
+	PublishDue(ctx context.Context) (int, error)
+
+	SetCityResolver(resolver CityResolver)
+}
+
+type service struct {
+	repo   Repository
+	cities CityResolver
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) SetCityResolver(resolver CityResolver) {
+	s.cities = resolver
+}
+
+func (s *service) CreateAnnouncement(ctx context.Context, adminID string, req dto.CreateAnnouncementRequest) (*dto.AdminAnnouncementResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+	if err := s.checkCity(ctx, req.CityID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	a := &models.Announcement{
+		Title:           req.Title,
+		Body:            req.Body,
+		Placement:       req.Placement,
+		Priority:        req.Priority,
+		ImageURL:        req.ImageURL,
+		ActionURL:       req.ActionURL,
+		Audience:        req.Audience,
+		CityID:          req.CityID,
+		ServiceCategory: req.ServiceCategory,
+		StartsAt:        now,
+		EndsAt:          req.EndsAt,
+		CreatedBy:       adminID,
+	}
+	if req.StartsAt != nil && req.StartsAt.After(now) {
+		a.StartsAt = *req.StartsAt
+	}
+	live := a.IsLive(now)
+	if live {
+		a.PublishedAt = &now
+	}
+
+	if err := s.repo.Create(ctx, a); err != nil {
+		return nil, response.InternalServerError("Failed to create announcement", err)
+	}
+	if live {
+		s.pushAsync(a)
+	}
+
+	logger.Info("announcement created", "announcementID", a.ID, "audience", a.Audience, "startsAt", a.StartsAt, "adminID", adminID)
+	return dto.ToAdminAnnouncementResponse(a, 0), nil
+}
+
+func (s *service) UpdateAnnouncement(ctx context.Context, id string, req dto.UpdateAnnouncementRequest) (*dto.AdminAnnouncementResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	a, err := s.findAnnouncement(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkCity(ctx, req.CityID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	before := *a
+	wasShown := a.PublishedAt != nil && a.IsLive(now)
+
+	if req.Title != nil {
+		a.Title = *req.Title
+	}
+	if req.Body != nil {
+		a.Body = *req.Body
+	}
+	if req.Placement != nil {
+		a.Placement = *req.Placement
+	}
+	if req.Priority != nil {
+		a.Priority = *req.Priority
+	}
+	if req.ImageURL != nil {
+		a.ImageURL = req.ImageURL
+	}
+	if req.ActionURL != nil {
+		a.ActionURL = req.ActionURL
+	}
+	if req.Audience != nil {
+		a.Audience = *req.Audience
+	}
+	if req.CityID != nil {
+		a.CityID = req.CityID
+	} else if req.ClearCity {
+		a.CityID = nil
+	}
+	if req.ServiceCategory != nil {
+		a.ServiceCategory = req.ServiceCategory
+	} else if req.ClearServiceCategory {
+		a.ServiceCategory = nil
+	}
+	if req.StartsAt != nil {
+		a.StartsAt = *req.StartsAt
+	}
+	if req.EndsAt != nil {
+		a.EndsAt = req.EndsAt
+	} else if req.ClearEndsAt {
+		a.EndsAt = nil
+	}
+
+	if a.ServiceCategory != nil && a.Audience != models.AnnouncementAudienceProviders {
+		return nil, response.BadRequest("serviceCategory can only be used with the providers audience")
+	}
+	if a.EndsAt != nil && !a.EndsAt.After(a.StartsAt) {
+		return nil, response.BadRequest("endsAt must be after startsAt")
+	}
+
+	// Moving the window forward makes it due for pushing again; a live
+	// announcement is pushed now with its changes.
+	live := a.IsLive(now)
+	switch {
+	case live:
+		a.PublishedAt = &now
+	case now.Before(a.StartsAt):
+		a.PublishedAt = nil
+	}
+
+	if err := s.repo.Update(ctx, a); err != nil {
+		return nil, response.InternalServerError("Failed to update announcement", err)
+	}
+
+	updated := *a
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if wasShown && (!live || !sameTargeting(&before, &updated)) {
+			s.withdraw(ctx, &before)
+		}
+		if live {
+			s.push(ctx, &updated)
+		}
+	}()
+
+	logger.Info("announcement updated", "announcementID", a.ID)
+	return s.toAdminResponse(ctx, a), nil
+}
+
+func (s *service) DeleteAnnouncement(ctx context.Context, id string) error {
+	a, err := s.findAnnouncement(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return response.InternalServerError("Failed to delete announcement", err)
+	}
+
+	if a.PublishedAt != nil && a.IsLive(time.Now()) {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+			s.withdraw(ctx, a)
+		}()
+	}
+
+	logger.Info("announcement deleted", "announcementID", id)
+	return nil
+}
+
+func (s *service) GetAnnouncement(ctx context.Context, id string) (*dto.AdminAnnouncementResponse, error) {
+	a, err := s.findAnnouncement(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.toAdminResponse(ctx, a), nil
+}
+
+func (s *service) ListAnnouncements(ctx context.Context, req dto.ListAnnouncementsRequest) ([]*dto.AdminAnnouncementResponse, int64, error) {
+	req.SetDefaults()
+
+	items, total, err := s.repo.List(ctx, req, time.Now())
+	if err != nil {
+		return nil, 0, response.InternalServerError("Failed to fetch announcements", err)
+	}
+
+	ids := make([]string, len(items))
+	for i, a := range items {
+		ids[i] = a.ID
+	}
+	counts, err := s.repo.CountReads(ctx, ids)
+	if err != nil {
+		return nil, 0, response.InternalServerError("Failed to count announcement reads", err)
+	}
+
+	result := make([]*dto.AdminAnnouncementResponse, 0, len(items))
+	for _, a := range items {
+		result = append(result, dto.ToAdminAnnouncementResponse(a, counts[a.ID]))
+	}
+	return result, total, nil
+}
+
+func (s *service) ListActive(ctx context.Context, userID string, role models.UserRole, req dto.ActiveAnnouncementsRequest) ([]*dto.AnnouncementResponse, error) {
+	audience, ok := s.audienceOf(ctx, userID, role, req.Latitude, req.Longitude)
+	if !ok {
+		return []*dto.AnnouncementResponse{}, nil
+	}
+
+	items, err := s.repo.ListLive(ctx, audience, req.Placement, time.Now())
+	if err != nil {
+		return nil, response.InternalServerError("Failed to fetch announcements", err)
+	}
+
+	ids := make([]string, len(items))
+	for i, a := range items {
+		ids[i] = a.ID
+	}
+	reads, err := s.repo.ReadTimes(ctx, userID, ids)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to fetch announcements", err)
+	}
+
+	result := make([]*dto.AnnouncementResponse, 0, len(items))
+	for _, a := range items {
+		var readAt *time.Time
+		if at, ok := reads[a.ID]; ok {
+			readAt = &at
+		}
+		if req.UnreadOnly && readAt != nil {
+			continue
+		}
+		result = append(result, dto.ToAnnouncementResponse(a, readAt))
+	}
+	return result, nil
+}
+
+func (s *service) MarkRead(ctx context.Context, userID string, role models.UserRole, id string) error {
+	a, err := s.findAnnouncement(ctx, id)
+	if err != nil {
+		return err
+	}
+	audience := models.AnnouncementAudienceFor(role)
+	if audience == "" || (a.Audience != models.AnnouncementAudienceAll && a.Audience != audience) {
+		return response.NotFoundError("Announcement")
+	}
+
+	if err := s.repo.MarkRead(ctx, id, userID); err != nil {
+		return response.InternalServerError("Failed to mark announcement read", err)
+	}
+	return nil
+}
+
+func (s *service) PublishDue(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		items, err := s.repo.ClaimUnpublished(ctx, time.Now(), publishBatchSize)
+		if err != nil {
+			return total, err
+		}
+		for _, a := range items {
+			s.push(ctx, a)
+			logger.Info("scheduled announcement published", "announcementID", a.ID, "audience", a.Audience)
+		}
+		total += len(items)
+		if len(items) < publishBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// audienceOf works out which announcements the user can see. ok is false for
+// roles announcements are not shown to.
+func (s *service) audienceOf(ctx context.Context, userID string, role models.UserRole, lat, lon *float64) (Audience, bool) {
+	audience := Audience{Audience: models.AnnouncementAudienceFor(role)}
+	if audience.Audience == "" {
+		return audience, false
+	}
+
+	if lat == nil || lon == nil {
+		if audience.Audience == models.AnnouncementAudienceDrivers {
+			if pos, err := s.repo.DriverPosition(ctx, userID); err == nil {
+				lat, lon = &pos.Lat, &pos.Lon
+			}
+		}
+	}
+	if lat != nil && lon != nil && s.cities != nil {
+		// Outside every city the user only sees untargeted announcements.
+		if city, err := s.cities.ResolveServiceArea(ctx, *lat, *lon); err == nil && city != nil {
+			audience.CityID = city.ID
+		}
+	}
+
+	if audience.Audience == models.AnnouncementAudienceProviders {
+		if category, err := s.repo.ProviderCategory(ctx, userID); err == nil {
+			audience.ServiceCategory = category
+		}
+	}
+	return audience, true
+}
+
+func (s *service) checkCity(ctx context.Context, cityID *string) error {
+	if cityID == nil {
+		return nil
+	}
+	if _, err := s.repo.FindCity(ctx, *cityID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.NotFoundError("City")
+		}
+		return response.InternalServerError("Failed to fetch city", err)
+	}
+	return nil
+}
+
+func (s *service) findAnnouncement(ctx context.Context, id string) (*models.Announcement, error) {
+	a, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Announcement")
+		}
+		return nil, response.InternalServerError("Failed to fetch announcement", err)
+	}
+	return a, nil
+}
+
+func (s *service) toAdminResponse(ctx context.Context, a *models.Announcement) *dto.AdminAnnouncementResponse {
+	counts, err := s.repo.CountReads(ctx, []string{a.ID})
+	if err != nil {
+		logger.Error("failed to count announcement reads", "error", err, "announcementID", a.ID)
+	}
+	return dto.ToAdminAnnouncementResponse(a, counts[a.ID])
+}
+
+func sameTargeting(a, b *models.Announcement) bool {
+	return a.Audience == b.Audience &&
+		equalOptional(a.CityID, b.CityID) &&
+		equalOptional(a.ServiceCategory, b.ServiceCategory)
+}
+
+func equalOptional(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
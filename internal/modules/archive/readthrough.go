@@ -0,0 +1,82 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm"
+)
+
+const archiveSuffix = "_archive"
+
+// Table names of the archived copies, for lookups that go straight to them.
+const (
+	RidesArchiveTable         = "rides" + archiveSuffix
+	ServiceOrdersArchiveTable = "service_orders" + archiveSuffix
+)
+
+// sharedColumns caches, per live table, the columns that also exist in its
+// archive. Both the archival job and the read-through queries select exactly
+// these, so a column added to the live table later only needs a matching
+// migration on the archive to be carried over.
+var sharedColumns sync.Map
+
+func columnsOf(ctx context.Context, db *gorm.DB, table string) ([]string, error) {
+	if cached, ok := sharedColumns.Load(table); ok {
+		return cached.([]string), nil
+	}
+
+	var columns []string
+	err := db.WithContext(ctx).Raw(`
+		SELECT l.column_name
+		FROM information_schema.columns l
+		JOIN information_schema.columns a
+			ON a.table_schema = l.table_schema
+			AND a.table_name = ?
+			AND a.column_name = l.column_name
+		WHERE l.table_schema = current_schema() AND l.table_name = ?
+		ORDER BY l.ordinal_position
+	`, table+archiveSuffix, table).Scan(&columns).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to read archive columns for %s: %w", table, err)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("archive table for %s not found", table)
+	}
+
+	sharedColumns.Store(table, columns)
+	return columns, nil
+}
+
+func columnList(columns []string) string {
+	quoted := make([]string, len(columns))
+	for i, column := range columns {
+		quoted[i] = `"` + column + `"`
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// WithArchived points the query at the live table and its archive combined,
+// aliased to the live table's name so existing Where and Order clauses keep
+// working. History listings use it; writes must keep going to the live table.
+func WithArchived(db *gorm.DB, table string) *gorm.DB {
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	columns, err := columnsOf(ctx, db, table)
+	if err != nil {
+		db.AddError(err)
+		return db
+	}
+
+	list := columnList(columns)
+	union := db.Session(&gorm.Session{NewDB: true}).Raw(fmt.Sprintf(
+		"SELECT %s FROM %s UNION ALL SELECT %s FROM %s",
+		list, table, list, table+archiveSuffix,
+	))
+	return db.Table("(?) AS "+table, union)
+}
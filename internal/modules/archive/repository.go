@@ -0,0 +1,110 @@
+package archive
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// reference is a single-column foreign key from another table into an
+// archived table.
+type reference struct {
+	SourceTable string
+	ColumnName  string
+}
+
+type Repository interface {
+	ArchiveBatch(ctx context.Context, p policy, cutoff time.Time, limit int) (int64, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// ArchiveBatch moves up to limit finished rows older than cutoff into the
+// archive table. Rows in other tables that reference them are snapshotted
+// into archived_related_rows first, since deleting the parent cascades to
+// most of them. Everything happens in one transaction, so a batch is either
+// fully archived or left untouched.
+func (r *repository) ArchiveBatch(ctx context.Context, p policy, cutoff time.Time, limit int) (int64, error) {
+	columns, err := columnsOf(ctx, r.db, p.Table)
+	if err != nil {
+		return 0, err
+	}
+	refs, err := r.references(ctx, p.Table)
+	if err != nil {
+		return 0, err
+	}
+
+	var moved int64
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []string
+		err := tx.Raw(fmt.Sprintf(`
+			SELECT id FROM %s
+			WHERE status IN ? AND %s < ?
+			ORDER BY %s
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		`, p.Table, p.FinishedAt, p.FinishedAt), p.Statuses, cutoff, limit).Scan(&ids).Error
+		if err != nil {
+			return fmt.Errorf("failed to select %s to archive: %w", p.Table, err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		for _, ref := range refs {
+			err := tx.Exec(fmt.Sprintf(`
+				INSERT INTO archived_related_rows (parent_table, parent_id, source_table, row_data)
+				SELECT ?, t."%s", ?, to_jsonb(t) FROM %s t WHERE t."%s" IN ?
+			`, ref.ColumnName, ref.SourceTable, ref.ColumnName), p.Table, ref.SourceTable, ids).Error
+			if err != nil {
+				return fmt.Errorf("failed to archive %s rows for %s: %w", ref.SourceTable, p.Table, err)
+			}
+		}
+
+		list := columnList(columns)
+		err = tx.Exec(fmt.Sprintf(`
+			INSERT INTO %s (%s) SELECT %s FROM %s WHERE id IN ?
+			ON CONFLICT (id) DO NOTHING
+		`, p.Table+archiveSuffix, list, list, p.Table), ids).Error
+		if err != nil {
+			return fmt.Errorf("failed to copy %s to archive: %w", p.Table, err)
+		}
+
+		result := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE id IN ?", p.Table), ids)
+		if result.Error != nil {
+			return fmt.Errorf("failed to remove archived %s: %w", p.Table, result.Error)
+		}
+		moved = result.RowsAffected
+		return nil
+	})
+	return moved, err
+}
+
+// references lists the foreign keys pointing at table. It is read from the
+// catalog on every batch so tables added by later migrations are covered
+// without touching the job.
+func (r *repository) references(ctx context.Context, table string) ([]reference, error) {
+	var refs []reference
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT c.conrelid::regclass::text AS source_table, a.attname AS column_name
+		FROM pg_constraint c
+		JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = c.conkey[1]
+		WHERE c.contype = 'f'
+			AND c.confrelid = ?::regclass
+			AND array_length(c.conkey, 1) = 1
+			AND c.conrelid <> c.confrelid
+		ORDER BY 1, 2
+	`, table).Scan(&refs).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to read references to %s: %w", table, err)
+	}
+	return refs, nil
+}
@@ -0,0 +1,74 @@
+package archive
+
+import (
+	"context"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+// policy describes which rows of a live table are eligible for archival.
+// FinishedAt is the SQL expression the archive age is measured from.
+type policy struct {
+	Table      string
+	Statuses   []string
+	FinishedAt string
+}
+
+var policies = []policy{
+	{
+		Table:      "rides",
+		Statuses:   []string{"completed", "cancelled"},
+		FinishedAt: "COALESCE(completed_at, cancelled_at, requested_at)",
+	},
+	{
+		Table:      "service_orders",
+		Statuses:   []string{"completed", "cancelled", "expired"},
+		FinishedAt: "COALESCE(completed_at, updated_at)",
+	},
+}
+
+type Service interface {
+	// Run archives every eligible row, batch by batch, and returns how many
+	// rows were moved per table.
+	Run(ctx context.Context) (map[string]int64, error)
+}
+
+type service struct {
+	repo Repository
+	cfg  config.ArchiveConfig
+}
+
+func NewService(repo Repository, cfg config.ArchiveConfig) Service {
+	return &service{repo: repo, cfg: cfg}
+}
+
+func (s *service) Run(ctx context.Context) (map[string]int64, error) {
+	moved := make(map[string]int64, len(policies))
+	cutoff := time.Now().Add(-s.cfg.After)
+
+	for _, p := range policies {
+		for {
+			if err := ctx.Err(); err != nil {
+				return moved, err
+			}
+
+			n, err := s.repo.ArchiveBatch(ctx, p, cutoff, s.cfg.BatchSize)
+			if err != nil {
+				logger.Error("archive batch failed", "error", err, "table", p.Table)
+				return moved, err
+			}
+			moved[p.Table] += n
+			if n < int64(s.cfg.BatchSize) {
+				break
+			}
+		}
+
+		if moved[p.Table] > 0 {
+			logger.Info("archived finished records", "table", p.Table, "count", moved[p.Table])
+		}
+	}
+
+	return moved, nil
+}
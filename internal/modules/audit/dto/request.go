@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"errors"
+	"time"
+)
+
+type ListAuditLogsQuery struct {
+	ActorID    string     `form:"actorId" binding:"omitempty,uuid"`
+	Action     string     `form:"action" binding:"omitempty,max=100"`
+	Category   string     `form:"category" binding:"omitempty,oneof=admin financial"`
+	EntityType string     `form:"entityType" binding:"omitempty,max=50"`
+	EntityID   string     `form:"entityId" binding:"omitempty,max=64"`
+	From       *time.Time `form:"from" time_format:"2006-01-02"`
+	To         *time.Time `form:"to" time_format:"2006-01-02"`
+	Page       int        `form:"page" binding:"omitempty,min=1"`
+	Limit      int        `form:"limit" binding:"omitempty,min=1,max=200"`
+}
+
+func (q *ListAuditLogsQuery) SetDefaults() {
+	if q.Page == 0 {
+		q.Page = 1
+	}
+	if q.Limit == 0 {
+		q.Limit = 50
+	}
+}
+
+func (q *ListAuditLogsQuery) Validate() error {
+	if q.From != nil && q.To != nil && q.To.Before(*q.From) {
+		return errors.New("to must not be before from")
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+type AuditLogResponse struct {
+	ID         string                 `json:"id"`
+	ActorID    *string                `json:"actorId,omitempty"`
+	ActorRole  string                 `json:"actorRole"`
+	Action     string                 `json:"action"`
+	Category   string                 `json:"category"`
+	EntityType string                 `json:"entityType"`
+	EntityID   string                 `json:"entityId"`
+	Before     map[string]interface{} `json:"before,omitempty"`
+	After      map[string]interface{} `json:"after,omitempty"`
+	Changes    map[string]interface{} `json:"changes,omitempty"`
+	Metadata   map[string]interface{} `json:"metadata,omitempty"`
+	Reason     string                 `json:"reason,omitempty"`
+	CreatedAt  time.Time              `json:"createdAt"`
+}
+
+func ToAuditLogResponse(log *models.AuditLog) *AuditLogResponse {
+	return &AuditLogResponse{
+		ID:         log.ID,
+		ActorID:    log.ActorID,
+		ActorRole:  log.ActorRole,
+		Action:     log.Action,
+		Category:   log.Category,
+		EntityType: log.EntityType,
+		EntityID:   log.EntityID,
+		Before:     log.Before,
+		After:      log.After,
+		Changes:    log.Changes,
+		Metadata:   log.Metadata,
+		Reason:     log.Reason,
+		CreatedAt:  log.CreatedAt,
+	}
+}
+
+func ToAuditLogResponses(logs []*models.AuditLog) []*AuditLogResponse {
+	responses := make([]*AuditLogResponse, len(logs))
+	for i, log := range logs {
+		responses[i] = ToAuditLogResponse(log)
+	}
+	return responses
+}
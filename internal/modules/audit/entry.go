@@ -0,0 +1,100 @@
+package audit
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+// Entry describes one audited change. Before and After may be any value that
+// serializes to a JSON object (models, DTOs or maps); either may be nil for
+// creations and deletions.
+type Entry struct {
+	ActorID    string
+	ActorRole  string
+	Action     string
+	Category   string
+	EntityType string
+	EntityID   string
+	Before     interface{}
+	After      interface{}
+	Reason     string
+	Metadata   map[string]interface{}
+}
+
+func (e Entry) toModel() *models.AuditLog {
+	log := &models.AuditLog{
+		ActorRole:  e.ActorRole,
+		Action:     e.Action,
+		Category:   e.Category,
+		EntityType: e.EntityType,
+		EntityID:   e.EntityID,
+		Before:     toAuditData(e.Before),
+		After:      toAuditData(e.After),
+		Reason:     e.Reason,
+	}
+	if e.ActorID != "" {
+		actorID := e.ActorID
+		log.ActorID = &actorID
+	}
+	if log.ActorRole == "" {
+		log.ActorRole = models.AuditActorSystem
+	}
+	if log.Category == "" {
+		log.Category = models.AuditCategoryAdmin
+	}
+	if len(e.Metadata) > 0 {
+		log.Metadata = models.AuditData(e.Metadata)
+	}
+	log.Changes = diff(log.Before, log.After)
+	return log
+}
+
+func toAuditData(value interface{}) models.AuditData {
+	if value == nil {
+		return nil
+	}
+	if data, ok := value.(map[string]interface{}); ok {
+		return models.AuditData(data)
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return models.AuditData{"_error": err.Error()}
+	}
+	var data models.AuditData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return models.AuditData{"value": string(raw)}
+	}
+	return data
+}
+
+// diff compares the top-level fields of two serialized snapshots. Nested
+// objects are compared as a whole.
+func diff(before, after models.AuditData) models.AuditData {
+	if before == nil || after == nil {
+		return nil
+	}
+
+	changes := models.AuditData{}
+	for key, from := range before {
+		to, ok := after[key]
+		if !ok {
+			changes[key] = map[string]interface{}{"from": from, "to": nil}
+			continue
+		}
+		if !reflect.DeepEqual(from, to) {
+			changes[key] = map[string]interface{}{"from": from, "to": to}
+		}
+	}
+	for key, to := range after {
+		if _, ok := before[key]; !ok {
+			changes[key] = map[string]interface{}{"from": nil, "to": to}
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	return changes
+}
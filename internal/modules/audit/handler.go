@@ -0,0 +1,86 @@
+package audit
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/modules/audit/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// ListAuditLogs godoc
+// @Summary List audit logs
+// @Description Newest first. Filter by actor, action, category (admin or financial), entity and date range
+// @Tags admin-audit
+// @Security BearerAuth
+// @Produce json
+// @Param actorId query string false "Actor user ID"
+// @Param action query string false "Action, e.g. order.reassign or wallet.credit"
+// @Param category query string false "admin or financial"
+// @Param entityType query string false "Entity type"
+// @Param entityId query string false "Entity ID"
+// @Param from query string false "From date (YYYY-MM-DD)"
+// @Param to query string false "To date, inclusive (YYYY-MM-DD)"
+// @Param page query int false "Page"
+// @Param limit query int false "Page size (max 200)"
+// @Success 200 {object} response.Response{data=[]dto.AuditLogResponse}
+// @Router /admin/audit-logs [get]
+func (h *Handler) ListAuditLogs(c *gin.Context) {
+	var query dto.ListAuditLogsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+
+	logs, pagination, err := h.service.ListLogs(c.Request.Context(), query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Paginated(c, logs, *pagination, "Audit logs retrieved successfully")
+}
+
+// GetAuditLog godoc
+// @Summary Get an audit log entry
+// @Tags admin-audit
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Audit log ID"
+// @Success 200 {object} response.Response{data=dto.AuditLogResponse}
+// @Router /admin/audit-logs/{id} [get]
+func (h *Handler) GetAuditLog(c *gin.Context) {
+	log, err := h.service.GetLog(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, log, "Audit log retrieved successfully")
+}
+
+// GetEntityHistory godoc
+// @Summary Get the audit trail of one entity
+// @Description Newest first, up to 200 entries
+// @Tags admin-audit
+// @Security BearerAuth
+// @Produce json
+// @Param entityType path string true "Entity type, e.g. service_order"
+// @Param entityId path string true "Entity ID"
+// @Success 200 {object} response.Response{data=[]dto.AuditLogResponse}
+// @Router /admin/audit-logs/entity/{entityType}/{entityId} [get]
+func (h *Handler) GetEntityHistory(c *gin.Context) {
+	logs, err := h.service.GetEntityHistory(c.Request.Context(), c.Param("entityType"), c.Param("entityId"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, logs, "Audit history retrieved successfully")
+}
@@ -0,0 +1,109 @@
+package audit
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+const (
+	moneyMovementCallback  = "audit:money_movement"
+	moneyMovementSavePoint = "audit_money_movement"
+)
+
+// MoneyMovementPlugin writes a financial audit record for every wallet
+// transaction inserted through GORM. The record is created on the same
+// connection, so inside a transaction it commits or rolls back with the
+// movement it describes. Wallet code therefore does not have to remember to
+// audit each of its many credit and debit paths.
+type MoneyMovementPlugin struct{}
+
+func NewMoneyMovementPlugin() *MoneyMovementPlugin {
+	return &MoneyMovementPlugin{}
+}
+
+func (p *MoneyMovementPlugin) Name() string {
+	return "audit_money_movement"
+}
+
+func (p *MoneyMovementPlugin) Initialize(db *gorm.DB) error {
+	return db.Callback().Create().After("gorm:create").Register(moneyMovementCallback, p.afterCreate)
+}
+
+func (p *MoneyMovementPlugin) afterCreate(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil || db.Statement.Schema.Table != (models.WalletTransaction{}).TableName() {
+		return
+	}
+
+	var transactions []*models.WalletTransaction
+	switch dest := db.Statement.Dest.(type) {
+	case *models.WalletTransaction:
+		transactions = append(transactions, dest)
+	case []*models.WalletTransaction:
+		transactions = dest
+	case *[]*models.WalletTransaction:
+		transactions = *dest
+	case *[]models.WalletTransaction:
+		for i := range *dest {
+			transactions = append(transactions, &(*dest)[i])
+		}
+	default:
+		return
+	}
+
+	session := db.Session(&gorm.Session{NewDB: true})
+	_, inTx := db.Statement.ConnPool.(gorm.TxCommitter)
+	for _, txn := range transactions {
+		log := Entry{
+			Action:     "wallet." + string(txn.Type),
+			Category:   models.AuditCategoryFinancial,
+			EntityType: "wallet_transaction",
+			EntityID:   txn.ID,
+			After:      moneyMovementSnapshot(txn),
+			Metadata:   map[string]interface{}{"walletId": txn.WalletID},
+		}.toModel()
+
+		// A failed insert would abort the surrounding Postgres transaction and
+		// take the movement down with it. The wallet ledger stays the source
+		// of truth, so isolate the audit write behind a savepoint.
+		if inTx {
+			if err := session.SavePoint(moneyMovementSavePoint).Error; err != nil {
+				logger.Error("failed to audit wallet transaction", "error", err, "transactionID", txn.ID)
+				continue
+			}
+		}
+		if err := session.Create(log).Error; err != nil {
+			logger.Error("failed to audit wallet transaction", "error", err, "transactionID", txn.ID)
+			if inTx {
+				session.RollbackTo(moneyMovementSavePoint)
+			}
+		}
+	}
+}
+
+func moneyMovementSnapshot(txn *models.WalletTransaction) map[string]interface{} {
+	snapshot := map[string]interface{}{
+		"walletId":      txn.WalletID,
+		"type":          txn.Type,
+		"amount":        txn.Amount,
+		"currency":      txn.Currency,
+		"balanceBefore": txn.BalanceBefore,
+		"balanceAfter":  txn.BalanceAfter,
+		"status":        txn.Status,
+	}
+	if txn.ReferenceType != nil {
+		snapshot["referenceType"] = *txn.ReferenceType
+	}
+	if txn.ReferenceID != nil {
+		snapshot["referenceId"] = *txn.ReferenceID
+	}
+	if txn.Description != nil {
+		snapshot["description"] = *txn.Description
+	}
+	if txn.OriginalAmount != nil && txn.OriginalCurrency != nil {
+		snapshot["originalAmount"] = *txn.OriginalAmount
+		snapshot["originalCurrency"] = *txn.OriginalCurrency
+	}
+	return snapshot
+}
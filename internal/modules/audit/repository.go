@@ -0,0 +1,93 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/audit/dto"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Create(ctx context.Context, log *models.AuditLog) error
+	GetByID(ctx context.Context, id string) (*models.AuditLog, error)
+	List(ctx context.Context, query dto.ListAuditLogsQuery) ([]*models.AuditLog, int64, error)
+	ListByEntity(ctx context.Context, entityType, entityID string, limit int) ([]*models.AuditLog, error)
+	DeleteBefore(ctx context.Context, category string, before time.Time) (int64, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Create(ctx context.Context, log *models.AuditLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
+
+func (r *repository) GetByID(ctx context.Context, id string) (*models.AuditLog, error) {
+	var log models.AuditLog
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&log).Error; err != nil {
+		return nil, err
+	}
+	return &log, nil
+}
+
+func (r *repository) List(ctx context.Context, query dto.ListAuditLogsQuery) ([]*models.AuditLog, int64, error) {
+	db := r.db.WithContext(ctx).Model(&models.AuditLog{})
+
+	if query.ActorID != "" {
+		db = db.Where("actor_id = ?", query.ActorID)
+	}
+	if query.Action != "" {
+		db = db.Where("action = ?", query.Action)
+	}
+	if query.Category != "" {
+		db = db.Where("category = ?", query.Category)
+	}
+	if query.EntityType != "" {
+		db = db.Where("entity_type = ?", query.EntityType)
+	}
+	if query.EntityID != "" {
+		db = db.Where("entity_id = ?", query.EntityID)
+	}
+	if query.From != nil {
+		db = db.Where("created_at >= ?", *query.From)
+	}
+	if query.To != nil {
+		db = db.Where("created_at < ?", query.To.AddDate(0, 0, 1))
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var logs []*models.AuditLog
+	err := db.Order("created_at DESC").
+		Offset((query.Page - 1) * query.Limit).
+		Limit(query.Limit).
+		Find(&logs).Error
+	return logs, total, err
+}
+
+func (r *repository) ListByEntity(ctx context.Context, entityType, entityID string, limit int) ([]*models.AuditLog, error) {
+	var logs []*models.AuditLog
+	err := r.db.WithContext(ctx).
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&logs).Error
+	return logs, err
+}
+
+func (r *repository) DeleteBefore(ctx context.Context, category string, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("category = ? AND created_at < ?", category, before).
+		Delete(&models.AuditLog{})
+	return result.RowsAffected, result.Error
+}
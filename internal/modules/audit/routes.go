@@ -0,0 +1,17 @@
+package audit
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/middleware"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
+	logs := router.Group("/admin/audit-logs")
+	logs.Use(authMiddleware)
+	logs.Use(middleware.RequireAdmin())
+	{
+		logs.GET("", handler.ListAuditLogs)
+		logs.GET("/entity/:entityType/:entityId", handler.GetEntityHistory)
+		logs.GET("/:id", handler.GetAuditLog)
+	}
+}
@@ -0,0 +1,115 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/audit/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+const entityHistoryLimit = 200
+
+// Recorder writes audit entries. Modules that audit their own admin actions
+// depend on it rather than on the whole Service.
+type Recorder interface {
+	Record(ctx context.Context, entry Entry) error
+}
+
+type Service interface {
+	Recorder
+	ListLogs(ctx context.Context, query dto.ListAuditLogsQuery) ([]*dto.AuditLogResponse, *response.PaginationMeta, error)
+	GetLog(ctx context.Context, id string) (*dto.AuditLogResponse, error)
+	GetEntityHistory(ctx context.Context, entityType, entityID string) ([]*dto.AuditLogResponse, error)
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+type service struct {
+	repo Repository
+	cfg  config.AuditLogConfig
+}
+
+func NewService(repo Repository, cfg config.AuditLogConfig) Service {
+	return &service{repo: repo, cfg: cfg}
+}
+
+// Record writes the entry. Failures are logged and returned, but callers
+// usually ignore them: a missing audit row should not undo the change itself.
+func (s *service) Record(ctx context.Context, entry Entry) error {
+	log := entry.toModel()
+	if err := s.repo.Create(ctx, log); err != nil {
+		logger.Error("failed to write audit log",
+			"error", err,
+			"action", entry.Action,
+			"entityType", entry.EntityType,
+			"entityID", entry.EntityID,
+		)
+		return err
+	}
+	return nil
+}
+
+func (s *service) ListLogs(ctx context.Context, query dto.ListAuditLogsQuery) ([]*dto.AuditLogResponse, *response.PaginationMeta, error) {
+	query.SetDefaults()
+	if err := query.Validate(); err != nil {
+		return nil, nil, response.BadRequest(err.Error())
+	}
+
+	logs, total, err := s.repo.List(ctx, query)
+	if err != nil {
+		logger.Error("failed to list audit logs", "error", err)
+		return nil, nil, response.InternalServerError("Failed to get audit logs", err)
+	}
+
+	pagination := response.NewPaginationMeta(total, query.Page, query.Limit)
+	return dto.ToAuditLogResponses(logs), &pagination, nil
+}
+
+func (s *service) GetLog(ctx context.Context, id string) (*dto.AuditLogResponse, error) {
+	log, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, response.NotFoundError("Audit log")
+		}
+		return nil, response.InternalServerError("Failed to get audit log", err)
+	}
+	return dto.ToAuditLogResponse(log), nil
+}
+
+func (s *service) GetEntityHistory(ctx context.Context, entityType, entityID string) ([]*dto.AuditLogResponse, error) {
+	logs, err := s.repo.ListByEntity(ctx, entityType, entityID, entityHistoryLimit)
+	if err != nil {
+		logger.Error("failed to get entity audit history", "error", err, "entityType", entityType, "entityID", entityID)
+		return nil, response.InternalServerError("Failed to get audit history", err)
+	}
+	return dto.ToAuditLogResponses(logs), nil
+}
+
+// PurgeExpired applies the retention policy per category.
+func (s *service) PurgeExpired(ctx context.Context) (int64, error) {
+	retention := map[string]time.Duration{
+		models.AuditCategoryAdmin:     s.cfg.Retention,
+		models.AuditCategoryFinancial: s.cfg.FinancialRetention,
+	}
+
+	var total int64
+	for category, keep := range retention {
+		if keep <= 0 {
+			continue
+		}
+		deleted, err := s.repo.DeleteBefore(ctx, category, time.Now().Add(-keep))
+		if err != nil {
+			return total, err
+		}
+		total += deleted
+	}
+	if total > 0 {
+		logger.Info("purged expired audit logs", "count", total)
+	}
+	return total, nil
+}
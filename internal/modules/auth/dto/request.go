@@ -11,9 +11,10 @@ import (
 var phoneRegex = regexp.MustCompile(`^\+?[1-9]\d{1,14}$`)
 
 type PhoneSignupRequest struct {
-	Name  string          `json:"name" binding:"required,min=2,max=255"`
-	Phone string          `json:"phone" binding:"required"`
-	Role  models.UserRole `json:"role" binding:"required,oneof=rider driver service_provider"`
+	Name         string          `json:"name" binding:"required,min=2,max=255"`
+	Phone        string          `json:"phone" binding:"required"`
+	Role         models.UserRole `json:"role" binding:"required,oneof=rider driver service_provider"`
+	ReferralCode string          `json:"referralCode" binding:"omitempty,max=20"`
 }
 
 func (r *PhoneSignupRequest) Validate() error {
@@ -51,10 +52,11 @@ func (r *PhoneLoginRequest) Validate() error {
 }
 
 type EmailSignupRequest struct {
-	Name     string          `json:"name" binding:"required,min=2,max=255"`
-	Email    string          `json:"email" binding:"required,email"`
-	Password string          `json:"password" binding:"required,min=8"`
-	Role     models.UserRole `json:"role" binding:"required"`
+	Name         string          `json:"name" binding:"required,min=2,max=255"`
+	Email        string          `json:"email" binding:"required,email"`
+	Password     string          `json:"password" binding:"required,min=8"`
+	Role         models.UserRole `json:"role" binding:"required"`
+	ReferralCode string          `json:"referralCode" binding:"omitempty,max=20"`
 }
 
 func (r *EmailSignupRequest) Validate() error {
@@ -109,6 +111,10 @@ type RefreshTokenRequest struct {
 	RefreshToken string `json:"refreshToken" binding:"required"`
 }
 
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
 type UpdateProfileRequest struct {
 	Name            *string `json:"name" binding:"omitempty,min=2,max=255"`
 	Email           *string `json:"email" binding:"omitempty,email"`
@@ -116,6 +122,8 @@ type UpdateProfileRequest struct {
 	Phone           *string `json:"phone" binding:"omitempty"`
 	Gender          *string `json:"gender" binding:"omitempty,oneof=male female other"`
 	DOB             *string `json:"dob" binding:"omitempty,datetime=2006-01-02"`
+	// Language is used for pushes and WebSocket messages; see i18n.Supported.
+	Language *string `json:"language" binding:"omitempty,max=8"`
 }
 
 func (r *UpdateProfileRequest) ParseDOB() (*time.Time, error) {
@@ -128,3 +136,12 @@ func (r *UpdateProfileRequest) ParseDOB() (*time.Time, error) {
 	}
 	return &dob, nil
 }
+
+// DeviceInfo identifies the client a session is issued to. Handlers fill it from
+// the X-Device-ID / X-Device-Name headers and the request itself.
+type DeviceInfo struct {
+	DeviceID   string
+	DeviceName string
+	UserAgent  string
+	IPAddress  string
+}
@@ -9,6 +9,7 @@ import (
 type AuthResponse struct {
 	AccessToken  string        `json:"accessToken"`
 	RefreshToken string        `json:"refreshToken"`
+	SessionID    string        `json:"sessionId,omitempty"`
 	User         *UserResponse `json:"user"`
 }
 
@@ -16,8 +17,10 @@ type UserResponse struct {
 	ID                    string            `json:"id"`
 	Name                  string            `json:"name"`
 	Email                 *string           `json:"email,omitempty"`
+	EmailVerifiedAt       *time.Time        `json:"emailVerifiedAt,omitempty"`
 	Phone                 *string           `json:"phone,omitempty"`
 	Gender                *string           `json:"gender,omitempty"`
+	Language              string            `json:"language"`
 	Role                  models.UserRole   `json:"role"`
 	RidePIN               string            `json:"ridePin"`
 	EmergencyContactName  string            `json:"emergencyContactName,omitempty"`
@@ -35,6 +38,7 @@ type DriverResponse struct {
 	Email                 *string           `json:"email,omitempty"`
 	Phone                 *string           `json:"phone,omitempty"`
 	Gender                *string           `json:"gender,omitempty"`
+	Language              string            `json:"language"`
 	Role                  models.UserRole   `json:"role"`
 	RidePIN               string            `json:"ridePin"`
 	LicenseNumber         string            `json:"licenseNumber,omitempty"`
@@ -57,9 +61,11 @@ func ToUserResponse(user *models.User) *UserResponse {
 		ID:                    user.ID,
 		Name:                  user.Name,
 		Email:                 user.Email,
+		EmailVerifiedAt:       user.EmailVerifiedAt,
 		Phone:                 user.Phone,
 		Role:                  user.Role,
 		Gender:                user.Gender,
+		Language:              user.Language,
 		RidePIN:               user.RidePIN,
 		EmergencyContactName:  user.EmergencyContactName,
 		EmergencyContactPhone: user.EmergencyContactPhone,
@@ -93,6 +99,7 @@ func ToDriverResponse(user *models.User, driverProfile *models.DriverProfile) *D
 		LicenseNumber:         licenseNumber,
 		LicensePlate:          licensePlate,
 		Gender:                user.Gender,
+		Language:              user.Language,
 		RidePIN:               user.RidePIN,
 		EmergencyContactName:  user.EmergencyContactName,
 		EmergencyContactPhone: user.EmergencyContactPhone,
@@ -103,3 +110,33 @@ func ToDriverResponse(user *models.User, driverProfile *models.DriverProfile) *D
 		CreatedAt:             user.CreatedAt,
 	}
 }
+
+type SessionResponse struct {
+	ID         string    `json:"id"`
+	DeviceID   string    `json:"deviceId"`
+	DeviceName string    `json:"deviceName,omitempty"`
+	UserAgent  string    `json:"userAgent,omitempty"`
+	IPAddress  string    `json:"ipAddress,omitempty"`
+	Current    bool      `json:"current"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+func ToSessionResponse(session *models.AuthSession, currentSessionID string) *SessionResponse {
+	return &SessionResponse{
+		ID:         session.ID,
+		DeviceID:   session.DeviceID,
+		DeviceName: session.DeviceName,
+		UserAgent:  session.UserAgent,
+		IPAddress:  session.IPAddress,
+		Current:    session.ID == currentSessionID,
+		LastUsedAt: session.LastUsedAt,
+		ExpiresAt:  session.ExpiresAt,
+		CreatedAt:  session.CreatedAt,
+	}
+}
+
+type LogoutAllResponse struct {
+	RevokedSessions int `json:"revokedSessions"`
+}
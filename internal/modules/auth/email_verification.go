@@ -0,0 +1,118 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"math"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/emails"
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+// emailVerification is what a verification token stands for. The address is
+// kept so a link stops working once the user changes their email.
+type emailVerification struct {
+	UserID string `json:"userId"`
+	Email  string `json:"email"`
+}
+
+func emailVerificationKey(token string) string {
+	return "auth:email_verify:" + hashRefreshToken(token)
+}
+
+func (s *service) SetMailer(mailer emails.Mailer) {
+	s.mailer = mailer
+}
+
+// sendVerificationEmailAsync emails the user a verification link without
+// holding up the request that triggered it.
+func (s *service) sendVerificationEmailAsync(user *models.User) {
+	if s.mailer == nil || !s.mailer.Enabled() || user.Email == nil || *user.Email == "" {
+		return
+	}
+	userID, email := user.ID, *user.Email
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := s.sendVerificationEmail(ctx, userID, email); err != nil {
+			logger.Error("failed to send verification email", "error", err, "userId", userID)
+		}
+	}()
+}
+
+func (s *service) sendVerificationEmail(ctx context.Context, userID, email string) error {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return err
+	}
+	token := hex.EncodeToString(buf)
+
+	ttl := s.cfg.Email.VerificationTTL
+	if err := cache.SetJSON(ctx, emailVerificationKey(token), emailVerification{UserID: userID, Email: email}, ttl); err != nil {
+		return err
+	}
+
+	verifyURL := strings.TrimRight(s.cfg.Email.PublicBaseURL, "/") + "/verify-email?token=" + url.QueryEscape(token)
+	_, err := s.mailer.SendToUser(ctx, userID, emails.SendRequest{
+		Template: emails.TemplateSignupVerification,
+		Data: map[string]interface{}{
+			"VerifyURL":      verifyURL,
+			"ExpiresInHours": int(math.Ceil(ttl.Hours())),
+		},
+		ReferenceType: "user",
+		ReferenceID:   userID,
+	})
+	return err
+}
+
+func (s *service) VerifyEmail(ctx context.Context, token string) error {
+	var pending emailVerification
+	if err := cache.GetJSON(ctx, emailVerificationKey(token), &pending); err != nil {
+		return response.BadRequest("Verification link is invalid or has expired")
+	}
+
+	verified, err := s.repo.MarkEmailVerified(ctx, pending.UserID, pending.Email)
+	if err != nil {
+		return response.InternalServerError("Failed to verify email", err)
+	}
+	cache.Delete(ctx, emailVerificationKey(token))
+	if !verified {
+		return response.BadRequest("Verification link is invalid or has expired")
+	}
+
+	cache.Delete(ctx, "user:profile:"+pending.UserID)
+	logger.Info("email verified", "userId", pending.UserID)
+	return nil
+}
+
+func (s *service) ResendVerification(ctx context.Context, userID string) error {
+	user, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return response.NotFoundError("User")
+	}
+	if user.Email == nil || *user.Email == "" {
+		return response.BadRequest("No email address on the account")
+	}
+	if user.EmailVerifiedAt != nil {
+		return response.ConflictError("Email is already verified")
+	}
+	if s.mailer == nil || !s.mailer.Enabled() {
+		return response.ServiceUnavailable("Email delivery is not available")
+	}
+
+	if err := s.sendVerificationEmail(ctx, user.ID, *user.Email); err != nil {
+		if errors.Is(err, emails.ErrNoAddress) {
+			return response.BadRequest("No email address on the account")
+		}
+		return response.InternalServerError("Failed to send verification email", err)
+	}
+	return nil
+}
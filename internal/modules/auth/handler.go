@@ -14,11 +14,21 @@ func NewHandler(service Service) *Handler {
 	return &Handler{service: service}
 }
 
+func deviceInfo(c *gin.Context) authdto.DeviceInfo {
+	return authdto.DeviceInfo{
+		DeviceID:   c.GetHeader("X-Device-ID"),
+		DeviceName: c.GetHeader("X-Device-Name"),
+		UserAgent:  c.Request.UserAgent(),
+		IPAddress:  c.ClientIP(),
+	}
+}
+
 // PhoneSignup godoc
 // @Summary Signup with phone (riders / drivers / service providers)
 // @Tags auth
 // @Accept json
 // @Produce json
+// @Param X-Device-ID header string false "Stable device identifier"
 // @Param request body authdto.PhoneSignupRequest true "Signup data"
 // @Success 201 {object} response.Response{data=authdto.AuthResponse}
 // @Router /auth/phone/signup [post]
@@ -29,7 +39,7 @@ func (h *Handler) PhoneSignup(c *gin.Context) {
 		return
 	}
 
-	authResp, err := h.service.PhoneSignup(c.Request.Context(), req)
+	authResp, err := h.service.PhoneSignup(c.Request.Context(), req, deviceInfo(c))
 	if err != nil {
 		c.Error(err)
 		return
@@ -43,6 +53,7 @@ func (h *Handler) PhoneSignup(c *gin.Context) {
 // @Tags auth
 // @Accept json
 // @Produce json
+// @Param X-Device-ID header string false "Stable device identifier"
 // @Param request body authdto.PhoneLoginRequest true "Login data"
 // @Success 200 {object} response.Response{data=authdto.AuthResponse}
 // @Router /auth/phone/login [post]
@@ -53,7 +64,7 @@ func (h *Handler) PhoneLogin(c *gin.Context) {
 		return
 	}
 
-	authResp, err := h.service.PhoneLogin(c.Request.Context(), req)
+	authResp, err := h.service.PhoneLogin(c.Request.Context(), req, deviceInfo(c))
 	if err != nil {
 		c.Error(err)
 		return
@@ -67,6 +78,7 @@ func (h *Handler) PhoneLogin(c *gin.Context) {
 // @Tags auth
 // @Accept json
 // @Produce json
+// @Param X-Device-ID header string false "Stable device identifier"
 // @Param request body authdto.EmailSignupRequest true "Signup data"
 // @Success 201 {object} response.Response{data=authdto.AuthResponse}
 // @Router /auth/email/signup [post]
@@ -77,7 +89,7 @@ func (h *Handler) EmailSignup(c *gin.Context) {
 		return
 	}
 
-	authResp, err := h.service.EmailSignup(c.Request.Context(), req)
+	authResp, err := h.service.EmailSignup(c.Request.Context(), req, deviceInfo(c))
 	if err != nil {
 		c.Error(err)
 		return
@@ -91,6 +103,7 @@ func (h *Handler) EmailSignup(c *gin.Context) {
 // @Tags auth
 // @Accept json
 // @Produce json
+// @Param X-Device-ID header string false "Stable device identifier"
 // @Param request body authdto.EmailLoginRequest true "Login data"
 // @Success 200 {object} response.Response{data=authdto.AuthResponse}
 // @Router /auth/email/login [post]
@@ -102,7 +115,7 @@ func (h *Handler) EmailLogin(c *gin.Context) {
 		return
 	}
 
-	authResp, err := h.service.EmailLogin(c.Request.Context(), req)
+	authResp, err := h.service.EmailLogin(c.Request.Context(), req, deviceInfo(c))
 	if err != nil {
 		c.Error(err)
 		return
@@ -111,8 +124,51 @@ func (h *Handler) EmailLogin(c *gin.Context) {
 	response.Success(c, authResp, "Login successful")
 }
 
+// VerifyEmail godoc
+// @Summary Verify email address
+// @Description Confirms the address using the token from the verification email
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body authdto.VerifyEmailRequest true "Verification token"
+// @Success 200 {object} response.Response
+// @Router /auth/email/verify [post]
+func (h *Handler) VerifyEmail(c *gin.Context) {
+	var req authdto.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	if err := h.service.VerifyEmail(c.Request.Context(), req.Token); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, nil, "Email verified successfully")
+}
+
+// ResendVerification godoc
+// @Summary Resend verification email
+// @Tags auth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response
+// @Router /auth/email/verify/resend [post]
+func (h *Handler) ResendVerification(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	if err := h.service.ResendVerification(c.Request.Context(), userID.(string)); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, nil, "Verification email sent")
+}
+
 // RefreshToken godoc
 // @Summary Refresh access token
+// @Description Rotates the refresh token. Presenting an already-rotated token revokes the session.
 // @Tags auth
 // @Accept json
 // @Produce json
@@ -126,7 +182,7 @@ func (h *Handler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	authResp, err := h.service.RefreshToken(c.Request.Context(), req.RefreshToken)
+	authResp, err := h.service.RefreshToken(c.Request.Context(), req.RefreshToken, deviceInfo(c))
 	if err != nil {
 		c.Error(err)
 		return
@@ -153,7 +209,7 @@ func (h *Handler) Logout(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.Logout(c.Request.Context(), userID.(string), req.RefreshToken); err != nil {
+	if err := h.service.Logout(c.Request.Context(), userID.(string), c.GetString("sessionID"), req.RefreshToken); err != nil {
 		c.Error(err)
 		return
 	}
@@ -161,6 +217,64 @@ func (h *Handler) Logout(c *gin.Context) {
 	response.Success(c, nil, "Logged out successfully")
 }
 
+// LogoutAll godoc
+// @Summary Logout from all devices
+// @Description Revokes every session of the user and closes their WebSocket connections
+// @Tags auth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=authdto.LogoutAllResponse}
+// @Router /auth/logout-all [post]
+func (h *Handler) LogoutAll(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	count, err := h.service.LogoutAll(c.Request.Context(), userID.(string))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, authdto.LogoutAllResponse{RevokedSessions: count}, "Logged out from all devices")
+}
+
+// ListSessions godoc
+// @Summary List active sessions
+// @Tags auth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=[]authdto.SessionResponse}
+// @Router /auth/sessions [get]
+func (h *Handler) ListSessions(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	sessions, err := h.service.ListSessions(c.Request.Context(), userID.(string), c.GetString("sessionID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, sessions, "Sessions retrieved successfully")
+}
+
+// RevokeSession godoc
+// @Summary Revoke a session
+// @Tags auth
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Session ID"
+// @Success 200 {object} response.Response
+// @Router /auth/sessions/{id} [delete]
+func (h *Handler) RevokeSession(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	if err := h.service.RevokeSession(c.Request.Context(), userID.(string), c.Param("id")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, nil, "Session revoked successfully")
+}
+
 // GetProfile godoc
 // @Summary Get user profile
 // @Tags auth
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"time"
 
 	"github.com/umar5678/go-backend/internal/models"
 	"gorm.io/gorm"
@@ -14,8 +15,17 @@ type Repository interface {
 	FindByPhone(ctx context.Context, phone string) (*models.User, error)
 	Update(ctx context.Context, user *models.User) error
 	UpdateLastLogin(ctx context.Context, userID string) error
+	MarkEmailVerified(ctx context.Context, userID, email string) (bool, error)
 
 	CreateWallet(ctx context.Context, wallet *models.Wallet) error
+
+	CreateSession(ctx context.Context, session *models.AuthSession) error
+	FindSessionByID(ctx context.Context, id string) (*models.AuthSession, error)
+	FindActiveSessionByDevice(ctx context.Context, userID, deviceID string) (*models.AuthSession, error)
+	ListActiveSessions(ctx context.Context, userID string) ([]*models.AuthSession, error)
+	RotateSessionToken(ctx context.Context, sessionID, oldHash, newHash, ipAddress string, expiresAt time.Time) (bool, error)
+	RevokeSession(ctx context.Context, sessionID, reason string) (bool, error)
+	RevokeAllSessions(ctx context.Context, userID, reason string) ([]string, error)
 }
 
 type repository struct {
@@ -59,6 +69,91 @@ func (r *repository) UpdateLastLogin(ctx context.Context, userID string) error {
 		Update("last_login_at", gorm.Expr("NOW()")).Error
 }
 
+// MarkEmailVerified sets email_verified_at, provided the user still has the
+// address the verification was sent to.
+func (r *repository) MarkEmailVerified(ctx context.Context, userID, email string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.User{}).
+		Where("id = ? AND email = ?", userID, email).
+		Update("email_verified_at", gorm.Expr("COALESCE(email_verified_at, NOW())"))
+	return result.RowsAffected > 0, result.Error
+}
+
 func (r *repository) CreateWallet(ctx context.Context, wallet *models.Wallet) error {
 	return r.db.WithContext(ctx).Create(wallet).Error
 }
+
+func (r *repository) CreateSession(ctx context.Context, session *models.AuthSession) error {
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+func (r *repository) FindSessionByID(ctx context.Context, id string) (*models.AuthSession, error) {
+	var session models.AuthSession
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&session).Error
+	return &session, err
+}
+
+func (r *repository) FindActiveSessionByDevice(ctx context.Context, userID, deviceID string) (*models.AuthSession, error) {
+	var session models.AuthSession
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND device_id = ? AND revoked_at IS NULL", userID, deviceID).
+		First(&session).Error
+	return &session, err
+}
+
+func (r *repository) ListActiveSessions(ctx context.Context, userID string) ([]*models.AuthSession, error) {
+	var sessions []*models.AuthSession
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > NOW()", userID).
+		Order("last_used_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// RotateSessionToken swaps the refresh token hash only if the caller still holds
+// the current one, so two racing refreshes cannot both succeed.
+func (r *repository) RotateSessionToken(ctx context.Context, sessionID, oldHash, newHash, ipAddress string, expiresAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.AuthSession{}).
+		Where("id = ? AND refresh_token_hash = ? AND revoked_at IS NULL", sessionID, oldHash).
+		Updates(map[string]interface{}{
+			"refresh_token_hash": newHash,
+			"rotation_count":     gorm.Expr("rotation_count + 1"),
+			"ip_address":         ipAddress,
+			"expires_at":         expiresAt,
+			"last_used_at":       time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *repository) RevokeSession(ctx context.Context, sessionID, reason string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.AuthSession{}).
+		Where("id = ? AND revoked_at IS NULL", sessionID).
+		Updates(map[string]interface{}{
+			"revoked_at":     time.Now(),
+			"revoked_reason": reason,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *repository) RevokeAllSessions(ctx context.Context, userID, reason string) ([]string, error) {
+	var ids []string
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.AuthSession{}).
+			Where("user_id = ? AND revoked_at IS NULL", userID).
+			Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		return tx.Model(&models.AuthSession{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"revoked_at":     time.Now(),
+				"revoked_reason": reason,
+			}).Error
+	})
+	return ids, err
+}
@@ -17,6 +17,7 @@ func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gi
 		{
 			email.POST("/signup", handler.EmailSignup)
 			email.POST("/login", handler.EmailLogin)
+			email.POST("/verify", handler.VerifyEmail)
 		}
 
 		auth.POST("/refresh", handler.RefreshToken)
@@ -24,8 +25,12 @@ func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gi
 		protected.Use(authMiddleware)
 		{
 			protected.POST("/logout", handler.Logout)
+			protected.POST("/logout-all", handler.LogoutAll)
+			protected.GET("/sessions", handler.ListSessions)
+			protected.DELETE("/sessions/:id", handler.RevokeSession)
 			protected.GET("/profile", handler.GetProfile)
 			protected.PUT("/profile", handler.UpdateProfile)
+			protected.POST("/email/verify/resend", handler.ResendVerification)
 		}
 	}
 }
@@ -2,34 +2,54 @@ package auth
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/umar5678/go-backend/internal/config"
 	"github.com/umar5678/go-backend/internal/models"
 	authdto "github.com/umar5678/go-backend/internal/modules/auth/dto"
+	"github.com/umar5678/go-backend/internal/modules/emails"
 	"github.com/umar5678/go-backend/internal/modules/notifications"
 	"github.com/umar5678/go-backend/internal/modules/riders"
 	"github.com/umar5678/go-backend/internal/modules/serviceproviders"
 	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/services/i18n"
 	"github.com/umar5678/go-backend/internal/utils/codegen"
 	"github.com/umar5678/go-backend/internal/utils/jwt"
 	"github.com/umar5678/go-backend/internal/utils/logger"
 	"github.com/umar5678/go-backend/internal/utils/password"
 	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
 	"gorm.io/gorm"
 )
 
 type Service interface {
-	PhoneSignup(ctx context.Context, req authdto.PhoneSignupRequest) (*authdto.AuthResponse, error)
-	PhoneLogin(ctx context.Context, req authdto.PhoneLoginRequest) (*authdto.AuthResponse, error)
+	PhoneSignup(ctx context.Context, req authdto.PhoneSignupRequest, device authdto.DeviceInfo) (*authdto.AuthResponse, error)
+	PhoneLogin(ctx context.Context, req authdto.PhoneLoginRequest, device authdto.DeviceInfo) (*authdto.AuthResponse, error)
 
-	EmailSignup(ctx context.Context, req authdto.EmailSignupRequest) (*authdto.AuthResponse, error)
-	EmailLogin(ctx context.Context, req authdto.EmailLoginRequest) (*authdto.AuthResponse, error)
+	EmailSignup(ctx context.Context, req authdto.EmailSignupRequest, device authdto.DeviceInfo) (*authdto.AuthResponse, error)
+	EmailLogin(ctx context.Context, req authdto.EmailLoginRequest, device authdto.DeviceInfo) (*authdto.AuthResponse, error)
 
-	RefreshToken(ctx context.Context, refreshToken string) (*authdto.AuthResponse, error)
-	Logout(ctx context.Context, userID, refreshToken string) error
+	RefreshToken(ctx context.Context, refreshToken string, device authdto.DeviceInfo) (*authdto.AuthResponse, error)
+	Logout(ctx context.Context, userID, sessionID, refreshToken string) error
+	LogoutAll(ctx context.Context, userID string) (int, error)
+	ListSessions(ctx context.Context, userID, currentSessionID string) ([]*authdto.SessionResponse, error)
+	RevokeSession(ctx context.Context, userID, sessionID string) error
 	GetProfile(ctx context.Context, userID string) (*authdto.UserResponse, error)
 	UpdateProfile(ctx context.Context, userID string, req authdto.UpdateProfileRequest) (*authdto.UserResponse, error)
+
+	VerifyEmail(ctx context.Context, token string) error
+	ResendVerification(ctx context.Context, userID string) error
+
+	SetReferrals(referrals ReferralAttributor)
+	SetMailer(mailer emails.Mailer)
+}
+
+// ReferralAttributor records the referral code a user signed up with. It is
+// satisfied by referrals.Service.
+type ReferralAttributor interface {
+	AttributeSignup(ctx context.Context, user *models.User, code, deviceID string) error
 }
 
 type service struct {
@@ -38,6 +58,8 @@ type service struct {
 	riderService           riders.Service
 	serviceProviderService serviceproviders.Service
 	eventProducer          notifications.EventProducer
+	referrals              ReferralAttributor
+	mailer                 emails.Mailer
 }
 
 func NewService(
@@ -65,7 +87,11 @@ func NewServiceWithNotifications(
 	}
 }
 
-func (s *service) PhoneSignup(ctx context.Context, req authdto.PhoneSignupRequest) (*authdto.AuthResponse, error) {
+func (s *service) SetReferrals(referrals ReferralAttributor) {
+	s.referrals = referrals
+}
+
+func (s *service) PhoneSignup(ctx context.Context, req authdto.PhoneSignupRequest, device authdto.DeviceInfo) (*authdto.AuthResponse, error) {
 	if err := req.Validate(); err != nil {
 		return nil, response.BadRequest(err.Error())
 	}
@@ -76,7 +102,7 @@ func (s *service) PhoneSignup(ctx context.Context, req authdto.PhoneSignupReques
 		if existingUser.Role == req.Role {
 			return s.PhoneLogin(ctx, authdto.PhoneLoginRequest{
 				Phone: &req.Phone,
-			})
+			}, device)
 		}
 		existingUser.Role = req.Role
 		if err := s.repo.Update(ctx, existingUser); err != nil {
@@ -85,7 +111,7 @@ func (s *service) PhoneSignup(ctx context.Context, req authdto.PhoneSignupReques
 		}
 		logger.Info("user role updated", "userId", existingUser.ID, "phone", req.Phone, "newRole", req.Role)
 		s.repo.UpdateLastLogin(ctx, existingUser.ID)
-		authResp, err := s.generateAuthResponse(existingUser)
+		authResp, err := s.generateAuthResponse(ctx, existingUser, device)
 		if err != nil {
 			return nil, err
 		}
@@ -107,6 +133,7 @@ func (s *service) PhoneSignup(ctx context.Context, req authdto.PhoneSignupReques
 	user := &models.User{
 		Name:         req.Name,
 		Phone:        &req.Phone,
+		Language:     i18n.FromContext(ctx),
 		Role:         req.Role,
 		Status:       models.StatusActive,
 		ReferralCode: &referralCode,
@@ -131,6 +158,8 @@ func (s *service) PhoneSignup(ctx context.Context, req authdto.PhoneSignupReques
 		}
 	}
 
+	s.attributeReferral(ctx, user, req.ReferralCode, device)
+
 	s.repo.UpdateLastLogin(ctx, user.ID)
 
 	s.publishAuthEvent(ctx, notifications.EventUserRegistered, map[string]interface{}{
@@ -140,7 +169,7 @@ func (s *service) PhoneSignup(ctx context.Context, req authdto.PhoneSignupReques
 		"timestamp": time.Now(),
 	})
 
-	authResp, err := s.generateAuthResponse(user)
+	authResp, err := s.generateAuthResponse(ctx, user, device)
 	if err != nil {
 		return nil, err
 	}
@@ -150,7 +179,7 @@ func (s *service) PhoneSignup(ctx context.Context, req authdto.PhoneSignupReques
 	return authResp, nil
 }
 
-func (s *service) PhoneLogin(ctx context.Context, req authdto.PhoneLoginRequest) (*authdto.AuthResponse, error) {
+func (s *service) PhoneLogin(ctx context.Context, req authdto.PhoneLoginRequest, device authdto.DeviceInfo) (*authdto.AuthResponse, error) {
 	if err := req.Validate(); err != nil {
 		return nil, response.BadRequest(err.Error())
 	}
@@ -164,7 +193,7 @@ func (s *service) PhoneLogin(ctx context.Context, req authdto.PhoneLoginRequest)
 	}
 
 	if user.Status != models.StatusActive {
-		return nil, response.ForbiddenError("Account is not active")
+		return nil, response.CodedError(response.CodeAccountInactive, "")
 	}
 	if req.Role != "" {
 		user.Role = req.Role
@@ -179,7 +208,7 @@ func (s *service) PhoneLogin(ctx context.Context, req authdto.PhoneLoginRequest)
 		"timestamp": time.Now(),
 	})
 
-	authResp, err := s.generateAuthResponse(user)
+	authResp, err := s.generateAuthResponse(ctx, user, device)
 	if err != nil {
 		return nil, err
 	}
@@ -189,7 +218,7 @@ func (s *service) PhoneLogin(ctx context.Context, req authdto.PhoneLoginRequest)
 	return authResp, nil
 }
 
-func (s *service) EmailSignup(ctx context.Context, req authdto.EmailSignupRequest) (*authdto.AuthResponse, error) {
+func (s *service) EmailSignup(ctx context.Context, req authdto.EmailSignupRequest, device authdto.DeviceInfo) (*authdto.AuthResponse, error) {
 	if err := req.Validate(); err != nil {
 		return nil, response.BadRequest(err.Error())
 	}
@@ -198,7 +227,7 @@ func (s *service) EmailSignup(ctx context.Context, req authdto.EmailSignupReques
 	if err == nil && existingByEmail != nil {
 
 		if existingByEmail.Role == req.Role {
-			return nil, response.ConflictError("Email already registered for this role")
+			return nil, response.CodedError(response.CodeEmailInUse, "Email already registered for this role")
 		}
 
 		existingByEmail.Role = req.Role
@@ -209,7 +238,7 @@ func (s *service) EmailSignup(ctx context.Context, req authdto.EmailSignupReques
 		logger.Info("user role updated", "userId", existingByEmail.ID, "email", req.Email, "newRole", req.Role)
 
 		s.repo.UpdateLastLogin(ctx, existingByEmail.ID)
-		authResp, err := s.generateAuthResponse(existingByEmail)
+		authResp, err := s.generateAuthResponse(ctx, existingByEmail, device)
 		if err != nil {
 			return nil, err
 		}
@@ -242,6 +271,7 @@ func (s *service) EmailSignup(ctx context.Context, req authdto.EmailSignupReques
 	user := &models.User{
 		Name:         req.Name,
 		Email:        &req.Email,
+		Language:     i18n.FromContext(ctx),
 		Password:     &hashedPassword,
 		Role:         req.Role,
 		Status:       initialStatus,
@@ -269,9 +299,12 @@ func (s *service) EmailSignup(ctx context.Context, req authdto.EmailSignupReques
 		logger.Error("failed to create wallet", "error", err, "userId", user.ID)
 	}
 
+	s.attributeReferral(ctx, user, req.ReferralCode, device)
+	s.sendVerificationEmailAsync(user)
+
 	s.repo.UpdateLastLogin(ctx, user.ID)
 
-	authResp, err := s.generateAuthResponse(user)
+	authResp, err := s.generateAuthResponse(ctx, user, device)
 	if err != nil {
 		return nil, err
 	}
@@ -281,7 +314,7 @@ func (s *service) EmailSignup(ctx context.Context, req authdto.EmailSignupReques
 	return authResp, nil
 }
 
-func (s *service) EmailLogin(ctx context.Context, req authdto.EmailLoginRequest) (*authdto.AuthResponse, error) {
+func (s *service) EmailLogin(ctx context.Context, req authdto.EmailLoginRequest, device authdto.DeviceInfo) (*authdto.AuthResponse, error) {
 	if err := req.Validate(); err != nil {
 		return nil, response.BadRequest(err.Error())
 	}
@@ -289,7 +322,7 @@ func (s *service) EmailLogin(ctx context.Context, req authdto.EmailLoginRequest)
 	user, err := s.repo.FindByEmail(ctx, req.Email)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, response.UnauthorizedError("Invalid email or password")
+			return nil, response.CodedError(response.CodeInvalidCredentials, "Invalid email or password")
 		}
 		return nil, response.InternalServerError("Failed to find user", err)
 	}
@@ -299,11 +332,11 @@ func (s *service) EmailLogin(ctx context.Context, req authdto.EmailLoginRequest)
 	}
 
 	if !password.Verify(req.Password, *user.Password) {
-		return nil, response.UnauthorizedError("Invalid credentials")
+		return nil, response.CodedError(response.CodeInvalidCredentials, "")
 	}
 
 	if user.Status != models.StatusActive {
-		return nil, response.ForbiddenError("Account is not active")
+		return nil, response.CodedError(response.CodeAccountInactive, "")
 	}
 
 	if req.Role != "" {
@@ -312,7 +345,7 @@ func (s *service) EmailLogin(ctx context.Context, req authdto.EmailLoginRequest)
 
 	s.repo.UpdateLastLogin(ctx, user.ID)
 
-	authResp, err := s.generateAuthResponse(user)
+	authResp, err := s.generateAuthResponse(ctx, user, device)
 	if err != nil {
 		return nil, err
 	}
@@ -337,16 +370,79 @@ func (s *service) mapRoleToCategory(role models.UserRole) string {
 	}
 }
 
-func (s *service) RefreshToken(ctx context.Context, refreshToken string) (*authdto.AuthResponse, error) {
+func (s *service) RefreshToken(ctx context.Context, refreshToken string, device authdto.DeviceInfo) (*authdto.AuthResponse, error) {
 
 	claims, err := jwt.ValidateToken(refreshToken, s.cfg.JWT.Secret, s.cfg.JWT.Issuer)
+	if err != nil || claims.IsImpersonation() {
+		return nil, response.CodedError(response.CodeTokenInvalid, "Invalid refresh token")
+	}
+
+	// Tokens issued before sessions existed carry no sid; honour them once and
+	// move the client onto a session.
+	if claims.SessionID == "" {
+		return s.refreshLegacyToken(ctx, refreshToken, claims, device)
+	}
+
+	session, err := s.repo.FindSessionByID(ctx, claims.SessionID)
+	if err != nil || session.UserID != claims.UserID {
+		return nil, response.CodedError(response.CodeTokenInvalid, "Invalid refresh token")
+	}
+
+	if session.RevokedAt != nil {
+		return nil, response.CodedError(response.CodeSessionRevoked, "")
+	}
+
+	currentHash := hashRefreshToken(refreshToken)
+	if currentHash != session.RefreshTokenHash {
+		s.handleTokenReuse(ctx, session)
+		return nil, response.CodedError(response.CodeRefreshTokenReused, "")
+	}
+
+	if !session.IsActive() {
+		return nil, response.CodedError(response.CodeSessionExpired, "")
+	}
+
+	user, err := s.repo.FindByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, response.NotFoundError("User")
+	}
+
+	if user.Status != models.StatusActive {
+		return nil, response.CodedError(response.CodeAccountInactive, "")
+	}
+
+	// Keep the role the session was signed in with.
+	user.Role = models.UserRole(claims.Role)
+
+	accessToken, newRefreshToken, err := s.issueTokens(user, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	rotated, err := s.repo.RotateSessionToken(ctx, session.ID, currentHash, hashRefreshToken(newRefreshToken), device.IPAddress, time.Now().Add(s.cfg.JWT.RefreshExpiry))
 	if err != nil {
-		return nil, response.UnauthorizedError("Invalid refresh token")
+		return nil, response.InternalServerError("Failed to rotate refresh token", err)
 	}
+	if !rotated {
+		// Another refresh with the same token won the race.
+		s.handleTokenReuse(ctx, session)
+		return nil, response.CodedError(response.CodeRefreshTokenReused, "")
+	}
+
+	logger.Info("token refreshed", "userId", user.ID, "sessionId", session.ID)
+
+	return &authdto.AuthResponse{
+		AccessToken:  accessToken,
+		RefreshToken: newRefreshToken,
+		SessionID:    session.ID,
+		User:         authdto.ToUserResponse(user),
+	}, nil
+}
 
+func (s *service) refreshLegacyToken(ctx context.Context, refreshToken string, claims *jwt.Claims, device authdto.DeviceInfo) (*authdto.AuthResponse, error) {
 	isBlacklisted, _ := cache.Get(ctx, "blacklist:"+refreshToken)
 	if isBlacklisted != "" {
-		return nil, response.UnauthorizedError("Token has been revoked")
+		return nil, response.CodedError(response.CodeTokenRevoked, "")
 	}
 
 	user, err := s.repo.FindByID(ctx, claims.UserID)
@@ -355,22 +451,38 @@ func (s *service) RefreshToken(ctx context.Context, refreshToken string) (*authd
 	}
 
 	if user.Status != models.StatusActive {
-		return nil, response.ForbiddenError("Account is not active")
+		return nil, response.CodedError(response.CodeAccountInactive, "")
 	}
 
-	authResp, err := s.generateAuthResponse(user)
+	user.Role = models.UserRole(claims.Role)
+
+	authResp, err := s.generateAuthResponse(ctx, user, device)
 	if err != nil {
 		return nil, err
 	}
 
 	cache.Set(ctx, "blacklist:"+refreshToken, "1", s.cfg.JWT.RefreshExpiry)
 
-	logger.Info("token refreshed", "userId", user.ID)
+	logger.Info("legacy token refreshed into session", "userId", user.ID, "sessionId", authResp.SessionID)
 
 	return authResp, nil
 }
 
-func (s *service) Logout(ctx context.Context, userID, refreshToken string) error {
+func (s *service) Logout(ctx context.Context, userID, sessionID, refreshToken string) error {
+
+	if sessionID == "" && refreshToken != "" {
+		if claims, err := jwt.ValidateToken(refreshToken, s.cfg.JWT.Secret, s.cfg.JWT.Issuer); err == nil && claims.UserID == userID {
+			sessionID = claims.SessionID
+		}
+	}
+
+	if sessionID != "" {
+		if err := s.revokeSession(ctx, userID, sessionID, models.SessionRevokedLogout); err != nil {
+			return response.InternalServerError("Failed to revoke session", err)
+		}
+	} else if _, err := websocket.RevokeResumeTickets(ctx, userID, ""); err != nil {
+		logger.Warn("failed to revoke websocket resume tickets", "error", err, "userId", userID)
+	}
 
 	if refreshToken != "" {
 		cache.Set(ctx, "blacklist:"+refreshToken, "1", s.cfg.JWT.RefreshExpiry)
@@ -378,7 +490,7 @@ func (s *service) Logout(ctx context.Context, userID, refreshToken string) error
 
 	cache.Delete(ctx, "user:profile:"+userID)
 
-	logger.Info("user logged out", "userId", userID)
+	logger.Info("user logged out", "userId", userID, "sessionId", sessionID)
 
 	return nil
 }
@@ -405,11 +517,16 @@ func (s *service) UpdateProfile(ctx context.Context, userID string, req authdto.
 	if req.Name != nil {
 		user.Name = *req.Name
 	}
+	emailChanged := false
 	if req.Email != nil {
 
 		existingUser, err := s.repo.FindByEmail(ctx, *req.Email)
 		if err == nil && existingUser.ID != userID {
-			return nil, response.ConflictError("Email already in use")
+			return nil, response.CodedError(response.CodeEmailInUse, "")
+		}
+		if user.Email == nil || !strings.EqualFold(*user.Email, *req.Email) {
+			emailChanged = true
+			user.EmailVerifiedAt = nil
 		}
 		user.Email = req.Email
 	}
@@ -421,7 +538,7 @@ func (s *service) UpdateProfile(ctx context.Context, userID string, req authdto.
 
 		existingUser, err := s.repo.FindByPhone(ctx, *req.Phone)
 		if err == nil && existingUser.ID != userID {
-			return nil, response.ConflictError("Phone number already in use")
+			return nil, response.CodedError(response.CodePhoneInUse, "")
 		}
 		user.Phone = req.Phone
 	}
@@ -438,11 +555,24 @@ func (s *service) UpdateProfile(ctx context.Context, userID string, req authdto.
 		user.DOB = dob
 	}
 
+	if req.Language != nil {
+		if !i18n.IsSupported(*req.Language) {
+			return nil, response.BadRequest(fmt.Sprintf("Unsupported language. Supported: %s", strings.Join(i18n.Supported(), ", ")))
+		}
+		user.Language = *req.Language
+	}
+
 	if err := s.repo.Update(ctx, user); err != nil {
 		return nil, response.InternalServerError("Failed to update profile", err)
 	}
 
 	cache.Delete(ctx, "user:profile:"+userID)
+	if req.Language != nil {
+		i18n.ForgetUser(ctx, userID)
+	}
+	if emailChanged {
+		s.sendVerificationEmailAsync(user)
+	}
 
 	logger.Info("profile updated", "userId", userID)
 
@@ -454,35 +584,67 @@ func (s *service) UpdateProfile(ctx context.Context, userID string, req authdto.
 	return authdto.ToUserResponse(user), nil
 }
 
-func (s *service) generateAuthResponse(user *models.User) (*authdto.AuthResponse, error) {
+func (s *service) generateAuthResponse(ctx context.Context, user *models.User, device authdto.DeviceInfo) (*authdto.AuthResponse, error) {
+	session, err := s.startSession(ctx, user.ID, device)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, refreshToken, err := s.issueTokens(user, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	session.RefreshTokenHash = hashRefreshToken(refreshToken)
+	if err := s.repo.CreateSession(ctx, session); err != nil {
+		return nil, response.InternalServerError("Failed to create session", err)
+	}
+
+	return &authdto.AuthResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		SessionID:    session.ID,
+		User:         authdto.ToUserResponse(user),
+	}, nil
+}
 
-	accessToken, err := jwt.GenerateToken(
+func (s *service) issueTokens(user *models.User, sessionID string) (string, string, error) {
+	accessToken, err := jwt.GenerateSessionToken(
 		user.ID,
 		string(user.Role),
+		sessionID,
 		s.cfg.JWT.Secret,
 		s.cfg.JWT.Issuer,
 		s.cfg.JWT.AccessExpiry,
 	)
 	if err != nil {
-		return nil, response.InternalServerError("Failed to generate access token", err)
+		return "", "", response.InternalServerError("Failed to generate access token", err)
 	}
 
-	refreshToken, err := jwt.GenerateToken(
+	refreshToken, err := jwt.GenerateSessionToken(
 		user.ID,
 		string(user.Role),
+		sessionID,
 		s.cfg.JWT.Secret,
 		s.cfg.JWT.Issuer,
 		s.cfg.JWT.RefreshExpiry,
 	)
 	if err != nil {
-		return nil, response.InternalServerError("Failed to generate refresh token", err)
+		return "", "", response.InternalServerError("Failed to generate refresh token", err)
 	}
 
-	return &authdto.AuthResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
-		User:         authdto.ToUserResponse(user),
-	}, nil
+	return accessToken, refreshToken, nil
+}
+
+// attributeReferral records the referral code the user signed up with. A code
+// that cannot be used does not fail the signup.
+func (s *service) attributeReferral(ctx context.Context, user *models.User, code string, device authdto.DeviceInfo) {
+	if s.referrals == nil || code == "" {
+		return
+	}
+	if err := s.referrals.AttributeSignup(ctx, user, code, device.DeviceID); err != nil {
+		logger.Warn("referral code not applied at signup", "error", err, "userId", user.ID, "code", code)
+	}
 }
 
 func (s *service) createUserWallet(ctx context.Context, user *models.User) error {
@@ -508,7 +670,7 @@ func (s *service) createUserWallet(ctx context.Context, user *models.User) error
 		WalletType:  walletType,
 		Balance:     initialBalance,
 		HeldBalance: 0.00,
-		Currency:    "INR",
+		Currency:    s.cfg.Currency.Default,
 		IsActive:    true,
 	}
 
@@ -0,0 +1,151 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/umar5678/go-backend/internal/models"
+	authdto "github.com/umar5678/go-backend/internal/modules/auth/dto"
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+	"gorm.io/gorm"
+)
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// startSession prepares a new session for the device. Signing in again from a
+// device that already holds a session replaces it.
+func (s *service) startSession(ctx context.Context, userID string, device authdto.DeviceInfo) (*models.AuthSession, error) {
+	if device.DeviceID == "" {
+		device.DeviceID = uuid.New().String()
+	}
+
+	existing, err := s.repo.FindActiveSessionByDevice(ctx, userID, device.DeviceID)
+	if err == nil {
+		if err := s.revokeSession(ctx, userID, existing.ID, models.SessionRevokedReplaced); err != nil {
+			return nil, response.InternalServerError("Failed to replace existing session", err)
+		}
+	} else if err != gorm.ErrRecordNotFound {
+		return nil, response.InternalServerError("Failed to look up session", err)
+	}
+
+	now := time.Now()
+	return &models.AuthSession{
+		ID:         uuid.New().String(),
+		UserID:     userID,
+		DeviceID:   device.DeviceID,
+		DeviceName: device.DeviceName,
+		UserAgent:  device.UserAgent,
+		IPAddress:  device.IPAddress,
+		ExpiresAt:  now.Add(s.cfg.JWT.RefreshExpiry),
+		LastUsedAt: now,
+	}, nil
+}
+
+func (s *service) revokeSession(ctx context.Context, userID, sessionID, reason string) error {
+	revoked, err := s.repo.RevokeSession(ctx, sessionID, reason)
+	if err != nil {
+		return err
+	}
+	if revoked {
+		s.afterSessionRevoked(ctx, userID, sessionID, reason)
+	}
+	return nil
+}
+
+// afterSessionRevoked makes the revocation take effect immediately: access
+// tokens for the session stop working and its sockets are dropped.
+func (s *service) afterSessionRevoked(ctx context.Context, userID, sessionID, reason string) {
+	if err := cache.MarkAuthSessionRevoked(ctx, sessionID, s.cfg.JWT.RefreshExpiry); err != nil {
+		logger.Warn("failed to cache session revocation", "error", err, "sessionId", sessionID)
+	}
+
+	if err := websocket.RevokeAuthSession(ctx, userID, sessionID); err != nil {
+		logger.Warn("failed to disconnect websocket for revoked session", "error", err, "sessionId", sessionID)
+	}
+
+	logger.Info("auth session revoked", "userId", userID, "sessionId", sessionID, "reason", reason)
+}
+
+// handleTokenReuse treats a superseded refresh token as stolen and kills the
+// session for both the attacker and the legitimate holder.
+func (s *service) handleTokenReuse(ctx context.Context, session *models.AuthSession) {
+	logger.Warn("refresh token reuse detected",
+		"userId", session.UserID,
+		"sessionId", session.ID,
+		"deviceId", session.DeviceID,
+	)
+
+	if err := s.revokeSession(ctx, session.UserID, session.ID, models.SessionRevokedReuse); err != nil {
+		logger.Error("failed to revoke session after token reuse", "error", err, "sessionId", session.ID)
+	}
+}
+
+func (s *service) LogoutAll(ctx context.Context, userID string) (int, error) {
+	ids, err := s.repo.RevokeAllSessions(ctx, userID, models.SessionRevokedLogoutAll)
+	if err != nil {
+		return 0, response.InternalServerError("Failed to revoke sessions", err)
+	}
+
+	for _, id := range ids {
+		if err := cache.MarkAuthSessionRevoked(ctx, id, s.cfg.JWT.RefreshExpiry); err != nil {
+			logger.Warn("failed to cache session revocation", "error", err, "sessionId", id)
+		}
+	}
+
+	if err := websocket.RevokeAuthSession(ctx, userID, ""); err != nil {
+		logger.Warn("failed to disconnect websockets on logout-all", "error", err, "userId", userID)
+	}
+
+	if _, err := websocket.RevokeResumeTickets(ctx, userID, ""); err != nil {
+		logger.Warn("failed to revoke websocket resume tickets", "error", err, "userId", userID)
+	}
+
+	cache.Delete(ctx, "user:profile:"+userID)
+
+	logger.Info("user logged out of all sessions", "userId", userID, "sessions", len(ids))
+
+	return len(ids), nil
+}
+
+func (s *service) ListSessions(ctx context.Context, userID, currentSessionID string) ([]*authdto.SessionResponse, error) {
+	sessions, err := s.repo.ListActiveSessions(ctx, userID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to list sessions", err)
+	}
+
+	result := make([]*authdto.SessionResponse, len(sessions))
+	for i, session := range sessions {
+		result[i] = authdto.ToSessionResponse(session, currentSessionID)
+	}
+
+	return result, nil
+}
+
+func (s *service) RevokeSession(ctx context.Context, userID, sessionID string) error {
+	session, err := s.repo.FindSessionByID(ctx, sessionID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return response.NotFoundError("Session")
+		}
+		return response.InternalServerError("Failed to find session", err)
+	}
+
+	if session.UserID != userID {
+		return response.NotFoundError("Session")
+	}
+
+	if err := s.revokeSession(ctx, userID, sessionID, models.SessionRevokedByUser); err != nil {
+		return response.InternalServerError("Failed to revoke session", err)
+	}
+
+	return nil
+}
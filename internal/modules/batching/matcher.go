@@ -19,10 +19,15 @@ func NewMatcher(ranker *DriverRanker) *Matcher {
 	}
 }
 
+// favoriteDriverBonus is added, in score points, to a driver's confidence for
+// requests from riders who marked them as a favorite.
+const favoriteDriverBonus = 10.0
+
 func (m *Matcher) MatchRequestsToDrivers(
 	ctx context.Context,
 	requests []dto.RideRequestInfo,
 	rankedDrivers []dto.DriverRankingScore,
+	favorites map[string]map[string]bool,
 	acceptanceThreshold float64,
 ) *dto.BatchMatchingResult {
 	result := &dto.BatchMatchingResult{
@@ -59,6 +64,11 @@ func (m *Matcher) MatchRequestsToDrivers(
 				distanceBonus = -5.0
 			}
 
+			// A rider's favorite drivers win close calls against other nearby drivers.
+			if favorites[request.RiderID][driver.DriverID] {
+				distanceBonus += favoriteDriverBonus
+			}
+
 			baseConfidence := driver.TotalScore / 100.0
 			adjustedConfidence := baseConfidence + (distanceBonus / 100.0)
 
@@ -9,7 +9,9 @@ import (
 	"github.com/umar5678/go-backend/internal/modules/ratings"
 	"github.com/umar5678/go-backend/internal/modules/tracking"
 	trackingdto "github.com/umar5678/go-backend/internal/modules/tracking/dto"
+	"github.com/umar5678/go-backend/internal/services/tracing"
 	"github.com/umar5678/go-backend/internal/utils/logger"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type Service interface {
@@ -21,6 +23,8 @@ type Service interface {
 	GetMatchingResult(batchID string) *dto.BatchMatchingResult
 
 	SetBatchExpireCallback(callback func(batchID string))
+	SetMatchingRadii(radii MatchingRadii)
+	SetFavoriteDrivers(favorites FavoriteDrivers)
 
 	RankDriversForRequest(ctx context.Context, driverIDs []string, pickupLat, pickupLon float64) ([]dto.DriverRankingScore, error)
 	GetDriverBreakdown(ctx context.Context, driverID string) (*dto.DriverRankingBreakdown, error)
@@ -36,6 +40,20 @@ type service struct {
 	matcher         *Matcher
 	stats           *batchingStats
 	trackingService tracking.Service
+	matchingRadii   MatchingRadii
+	favoriteDrivers FavoriteDrivers
+}
+
+// MatchingRadii gives the driver search radii, in km, configured for the city
+// around a point, or nil where the defaults apply.
+type MatchingRadii interface {
+	MatchingRadii(ctx context.Context, lat, lon float64) []float64
+}
+
+// FavoriteDrivers knows which drivers, by profile ID, a rider has marked as
+// favorites.
+type FavoriteDrivers interface {
+	FavoriteDriverProfileIDs(ctx context.Context, riderID string) map[string]bool
 }
 
 type batchingStats struct {
@@ -79,6 +97,14 @@ func (s *service) SetBatchExpireCallback(callback func(batchID string)) {
 	s.collector.SetBatchExpireCallback(callback)
 }
 
+func (s *service) SetMatchingRadii(radii MatchingRadii) {
+	s.matchingRadii = radii
+}
+
+func (s *service) SetFavoriteDrivers(favorites FavoriteDrivers) {
+	s.favoriteDrivers = favorites
+}
+
 func (s *service) AddRequestToBatch(ctx context.Context, req dto.RideRequestInfo) (string, error) {
 	return s.collector.AddRequest(ctx, req)
 }
@@ -98,7 +124,10 @@ func (s *service) GetBatchStatistics() *dto.BatchStatistics {
 	}
 }
 
-func (s *service) ProcessBatch(ctx context.Context, batchID string) (*dto.BatchMatchingResult, error) {
+func (s *service) ProcessBatch(ctx context.Context, batchID string) (_ *dto.BatchMatchingResult, err error) {
+	ctx, span := tracing.StartSpan(ctx, "batching.ProcessBatch", attribute.String("batch.id", batchID))
+	defer func() { tracing.End(span, err) }()
+
 	requests, err := s.collector.GetBatchRequests(batchID)
 	if err != nil {
 		return nil, err
@@ -116,15 +145,21 @@ func (s *service) ProcessBatch(ctx context.Context, batchID string) (*dto.BatchM
 	centroid := calculateCentroid(requests)
 
 	radii := []float64{3.0, 5.0, 8.0}
+	if s.matchingRadii != nil {
+		if cityRadii := s.matchingRadii.MatchingRadii(ctx, centroid.Latitude, centroid.Longitude); len(cityRadii) > 0 {
+			radii = cityRadii
+		}
+	}
 	var nearbyDriverIDs []string
 
 	for _, radiusKm := range radii {
 		resp, err := s.trackingService.FindNearbyDrivers(ctx, trackingdto.FindNearbyDriversRequest{
-			Latitude:      centroid.Latitude,
-			Longitude:     centroid.Longitude,
-			RadiusKm:      radiusKm,
-			OnlyAvailable: true, 
-			Limit:         50,   
+			Latitude:        centroid.Latitude,
+			Longitude:       centroid.Longitude,
+			RadiusKm:        radiusKm,
+			OnlyAvailable:   true,
+			ExcludeLowRated: true,
+			Limit:           50,
 		})
 		if err == nil && resp != nil && len(resp.Drivers) > 0 {
 			for _, driver := range resp.Drivers {
@@ -170,6 +205,7 @@ func (s *service) ProcessBatch(ctx context.Context, batchID string) (*dto.BatchM
 		ctx,
 		requests,
 		rankedDrivers,
+		s.ridersFavorites(ctx, requests),
 		0.6,
 	)
 
@@ -186,6 +222,19 @@ func (s *service) ProcessBatch(ctx context.Context, batchID string) (*dto.BatchM
 	return result, nil
 }
 
+func (s *service) ridersFavorites(ctx context.Context, requests []dto.RideRequestInfo) map[string]map[string]bool {
+	if s.favoriteDrivers == nil {
+		return nil
+	}
+	favorites := make(map[string]map[string]bool, len(requests))
+	for _, req := range requests {
+		if _, ok := favorites[req.RiderID]; !ok {
+			favorites[req.RiderID] = s.favoriteDrivers.FavoriteDriverProfileIDs(ctx, req.RiderID)
+		}
+	}
+	return favorites
+}
+
 func (s *service) GetMatchingResult(batchID string) *dto.BatchMatchingResult {
 	return nil
 }
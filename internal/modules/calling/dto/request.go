@@ -0,0 +1,18 @@
+package dto
+
+type ListSessionsRequest struct {
+	ReferenceType string `form:"referenceType" binding:"omitempty,oneof=ride service_order"`
+	ReferenceID   string `form:"referenceId" binding:"omitempty,uuid"`
+	UserID        string `form:"userId" binding:"omitempty,uuid"`
+	Page          int    `form:"page" binding:"omitempty,min=1"`
+	Limit         int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (r *ListSessionsRequest) SetDefaults() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+	if r.Limit < 1 {
+		r.Limit = 20
+	}
+}
@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+// CallSessionResponse is what a caller needs to tap-to-call: the proxy
+// number that rings the other side of their ride or order.
+type CallSessionResponse struct {
+	SessionID     string    `json:"sessionId"`
+	ReferenceType string    `json:"referenceType"`
+	ReferenceID   string    `json:"referenceId"`
+	ProxyNumber   string    `json:"proxyNumber"`
+	ExpiresAt     time.Time `json:"expiresAt"`
+}
+
+// ToCallSessionResponse picks the proxy number handed to userID.
+func ToCallSessionResponse(session *models.MaskedCallSession, userID string) *CallSessionResponse {
+	proxy := session.PartnerProxy
+	if session.CustomerID == userID {
+		proxy = session.CustomerProxy
+	}
+	return &CallSessionResponse{
+		SessionID:     session.ID,
+		ReferenceType: session.ReferenceType,
+		ReferenceID:   session.ReferenceID,
+		ProxyNumber:   proxy,
+		ExpiresAt:     session.ExpiresAt,
+	}
+}
+
+type CallEventResponse struct {
+	ID          string    `json:"id"`
+	CallerID    *string   `json:"callerId,omitempty"`
+	CallerRole  string    `json:"callerRole,omitempty"`
+	Status      string    `json:"status"`
+	DurationSec int       `json:"durationSec"`
+	OccurredAt  time.Time `json:"occurredAt"`
+}
+
+type SessionLogResponse struct {
+	*models.MaskedCallSession
+	Events []CallEventResponse `json:"events"`
+}
+
+func ToSessionLogResponse(session *models.MaskedCallSession, events []*models.MaskedCallEvent) *SessionLogResponse {
+	resp := &SessionLogResponse{MaskedCallSession: session, Events: make([]CallEventResponse, 0, len(events))}
+	for _, e := range events {
+		resp.Events = append(resp.Events, CallEventResponse{
+			ID:          e.ID,
+			CallerID:    e.CallerID,
+			CallerRole:  e.CallerRole,
+			Status:      e.Status,
+			DurationSec: e.DurationSec,
+			OccurredAt:  e.OccurredAt,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,109 @@
+package calling
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/modules/calling/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// StartRideCall godoc
+// @Summary Get a masked number to call the other side of a ride
+// @Description Riders get a number that rings their driver and drivers one that rings their rider; neither sees the other's real number
+// @Tags calls
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Ride ID"
+// @Success 200 {object} response.Response{data=dto.CallSessionResponse}
+// @Router /calls/rides/{id} [post]
+func (h *Handler) StartRideCall(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	session, err := h.service.StartRideCall(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, session, "Call session ready")
+}
+
+// StartOrderCall godoc
+// @Summary Get a masked number to call the other side of a home service order
+// @Description Customers get a number that rings their provider and providers one that rings their customer
+// @Tags calls
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Order ID"
+// @Success 200 {object} response.Response{data=dto.CallSessionResponse}
+// @Router /calls/orders/{id} [post]
+func (h *Handler) StartOrderCall(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	session, err := h.service.StartOrderCall(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, session, "Call session ready")
+}
+
+// TwilioCallback godoc
+// @Summary Receive Twilio Proxy call callbacks
+// @Description Verifies the X-Twilio-Signature header and logs the call against its session.
+// @Tags calls
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Success 200 {object} response.Response
+// @Router /calls/webhooks/twilio [post]
+func (h *Handler) TwilioCallback(c *gin.Context) {
+	if err := c.Request.ParseForm(); err != nil {
+		c.Error(response.BadRequest("Invalid callback body"))
+		return
+	}
+
+	if err := h.service.HandleCallback(c.Request.Context(), c.Request.PostForm, c.GetHeader("X-Twilio-Signature")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, nil, "Callback processed")
+}
+
+// ListSessions godoc
+// @Summary List masked call sessions and their call log
+// @Description Admin view of who called whom for a ride or order, for dispute handling
+// @Tags calls
+// @Security BearerAuth
+// @Produce json
+// @Param referenceType query string false "ride or service_order"
+// @Param referenceId query string false "Ride or order ID"
+// @Param userId query string false "Either party's user ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Response{data=[]dto.SessionLogResponse}
+// @Router /calls/admin/sessions [get]
+func (h *Handler) ListSessions(c *gin.Context) {
+	var req dto.ListSessionsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+	req.SetDefaults()
+
+	sessions, total, err := h.service.ListSessions(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Paginated(c, sessions, response.NewPaginationMeta(total, req.Page, req.Limit), "Call sessions retrieved successfully")
+}
@@ -0,0 +1,58 @@
+package calling
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+)
+
+var ErrInvalidCallbackSignature = errors.New("invalid callback signature")
+
+type Participant struct {
+	Name  string
+	Phone string
+}
+
+type SessionRequest struct {
+	// Reference is unique per ride or order and lets the provider reject a
+	// duplicate session for it.
+	Reference string
+	TTL       time.Duration
+	Customer  Participant
+	Partner   Participant
+}
+
+type ProxySession struct {
+	ID                    string
+	CustomerParticipantID string
+	CustomerProxy         string
+	PartnerParticipantID  string
+	PartnerProxy          string
+}
+
+// CallEvent is one status change of a call placed through a proxy session.
+type CallEvent struct {
+	ID        string
+	SessionID string
+	// CallerParticipantID is the participant who dialled the proxy number.
+	CallerParticipantID string
+	Status              string
+	DurationSec         int
+	OccurredAt          time.Time
+}
+
+// Provider opens proxy sessions between two phone numbers and reports the
+// calls made through them.
+type Provider interface {
+	Name() string
+	CreateSession(ctx context.Context, req SessionRequest) (*ProxySession, error)
+	CloseSession(ctx context.Context, sessionID string) error
+	ParseCallback(callbackURL string, form url.Values, signature string) (*CallEvent, error)
+}
+
+func NewProvider(cfg config.MaskedCallingConfig) Provider {
+	return NewTwilioProvider(cfg)
+}
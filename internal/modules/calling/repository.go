@@ -0,0 +1,139 @@
+package calling
+
+import (
+	"context"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository interface {
+	FindRideByID(ctx context.Context, rideID string) (*models.Ride, error)
+	FindOrderByID(ctx context.Context, orderID string) (*models.ServiceOrderNew, error)
+	FindProviderUserID(ctx context.Context, providerID string) (string, error)
+	FindUserByID(ctx context.Context, userID string) (*models.User, error)
+
+	CreateSession(ctx context.Context, session *models.MaskedCallSession) error
+	UpdateSession(ctx context.Context, session *models.MaskedCallSession) error
+	FindActiveSession(ctx context.Context, referenceType, referenceID string) (*models.MaskedCallSession, error)
+	FindSessionByProviderID(ctx context.Context, providerSessionID string) (*models.MaskedCallSession, error)
+	ListActiveSessions(ctx context.Context, limit int) ([]*models.MaskedCallSession, error)
+	ListSessions(ctx context.Context, referenceType, referenceID, userID string, page, limit int) ([]*models.MaskedCallSession, int64, error)
+
+	CreateEvent(ctx context.Context, event *models.MaskedCallEvent) error
+	ListEvents(ctx context.Context, sessionIDs []string) ([]*models.MaskedCallEvent, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) FindRideByID(ctx context.Context, rideID string) (*models.Ride, error) {
+	var ride models.Ride
+	err := r.db.WithContext(ctx).Where("id = ?", rideID).First(&ride).Error
+	return &ride, err
+}
+
+func (r *repository) FindOrderByID(ctx context.Context, orderID string) (*models.ServiceOrderNew, error) {
+	var order models.ServiceOrderNew
+	err := r.db.WithContext(ctx).Where("id = ?", orderID).First(&order).Error
+	return &order, err
+}
+
+func (r *repository) FindProviderUserID(ctx context.Context, providerID string) (string, error) {
+	var profile models.ServiceProviderProfile
+	err := r.db.WithContext(ctx).Select("id", "user_id").Where("id = ?", providerID).First(&profile).Error
+	return profile.UserID, err
+}
+
+func (r *repository) FindUserByID(ctx context.Context, userID string) (*models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).Where("id = ?", userID).First(&user).Error
+	return &user, err
+}
+
+func (r *repository) CreateSession(ctx context.Context, session *models.MaskedCallSession) error {
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+func (r *repository) UpdateSession(ctx context.Context, session *models.MaskedCallSession) error {
+	return r.db.WithContext(ctx).Save(session).Error
+}
+
+func (r *repository) FindActiveSession(ctx context.Context, referenceType, referenceID string) (*models.MaskedCallSession, error) {
+	var session models.MaskedCallSession
+	err := r.db.WithContext(ctx).
+		Where("reference_type = ? AND reference_id = ? AND status = ?", referenceType, referenceID, models.MaskedCallSessionActive).
+		First(&session).Error
+	return &session, err
+}
+
+func (r *repository) FindSessionByProviderID(ctx context.Context, providerSessionID string) (*models.MaskedCallSession, error) {
+	var session models.MaskedCallSession
+	err := r.db.WithContext(ctx).
+		Where("provider_session_id = ?", providerSessionID).
+		Order("created_at DESC").
+		First(&session).Error
+	return &session, err
+}
+
+func (r *repository) ListActiveSessions(ctx context.Context, limit int) ([]*models.MaskedCallSession, error) {
+	var sessions []*models.MaskedCallSession
+	err := r.db.WithContext(ctx).
+		Where("status = ?", models.MaskedCallSessionActive).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&sessions).Error
+	return sessions, err
+}
+
+func (r *repository) ListSessions(ctx context.Context, referenceType, referenceID, userID string, page, limit int) ([]*models.MaskedCallSession, int64, error) {
+	var sessions []*models.MaskedCallSession
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.MaskedCallSession{})
+	if referenceType != "" {
+		query = query.Where("reference_type = ?", referenceType)
+	}
+	if referenceID != "" {
+		query = query.Where("reference_id = ?", referenceID)
+	}
+	if userID != "" {
+		query = query.Where("customer_id = ? OR partner_id = ?", userID, userID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&sessions).Error
+	return sessions, total, err
+}
+
+// CreateEvent ignores a status the provider has already reported for the
+// same call, since callbacks are retried.
+func (r *repository) CreateEvent(ctx context.Context, event *models.MaskedCallEvent) error {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(event).Error
+}
+
+func (r *repository) ListEvents(ctx context.Context, sessionIDs []string) ([]*models.MaskedCallEvent, error) {
+	var events []*models.MaskedCallEvent
+	if len(sessionIDs) == 0 {
+		return events, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("session_id IN ?", sessionIDs).
+		Order("occurred_at ASC").
+		Find(&events).Error
+	return events, err
+}
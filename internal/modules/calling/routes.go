@@ -0,0 +1,24 @@
+package calling
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/middleware"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
+	// Signed by the call provider, not the user.
+	router.POST("/calls/webhooks/twilio", handler.TwilioCallback)
+
+	calls := router.Group("/calls")
+	calls.Use(authMiddleware)
+	{
+		calls.POST("/rides/:id", handler.StartRideCall)
+		calls.POST("/orders/:id", handler.StartOrderCall)
+
+		admin := calls.Group("/admin")
+		admin.Use(middleware.RequireAdmin())
+		{
+			admin.GET("/sessions", handler.ListSessions)
+		}
+	}
+}
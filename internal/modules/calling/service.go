@@ -0,0 +1,394 @@
+package calling
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/calling/dto"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+	"github.com/umar5678/go-backend/internal/services/health"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+const sweepBatchSize = 200
+
+type Service interface {
+	StartRideCall(ctx context.Context, userID, rideID string) (*dto.CallSessionResponse, error)
+	StartOrderCall(ctx context.Context, userID, orderID string) (*dto.CallSessionResponse, error)
+	HandleCallback(ctx context.Context, form url.Values, signature string) error
+	ListSessions(ctx context.Context, req dto.ListSessionsRequest) ([]*dto.SessionLogResponse, int64, error)
+	CloseEndedSessions(ctx context.Context) error
+}
+
+type service struct {
+	repo     Repository
+	provider Provider
+	cfg      config.MaskedCallingConfig
+}
+
+func NewService(repo Repository, provider Provider, cfg config.MaskedCallingConfig) Service {
+	return &service{
+		repo:     repo,
+		provider: provider,
+		cfg:      cfg,
+	}
+}
+
+// callReference is the ride or order a call is placed for, reduced to the two
+// parties and whether they may still reach each other.
+type callReference struct {
+	Type       string
+	ID         string
+	CustomerID string
+	PartnerID  string
+	Active     bool
+	EndedAt    *time.Time
+}
+
+// open reports whether the parties may call: while the ride or order is in
+// progress, and for the post-trip window after it completes.
+func (r *callReference) open(window time.Duration, now time.Time) bool {
+	if r.Active {
+		return true
+	}
+	return r.EndedAt != nil && now.Sub(*r.EndedAt) <= window
+}
+
+func (s *service) StartRideCall(ctx context.Context, userID, rideID string) (*dto.CallSessionResponse, error) {
+	ref, err := s.rideReference(ctx, rideID)
+	if err != nil {
+		return nil, err
+	}
+	return s.startCall(ctx, userID, ref)
+}
+
+func (s *service) StartOrderCall(ctx context.Context, userID, orderID string) (*dto.CallSessionResponse, error) {
+	ref, err := s.orderReference(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return s.startCall(ctx, userID, ref)
+}
+
+func (s *service) rideReference(ctx context.Context, rideID string) (*callReference, error) {
+	ride, err := s.repo.FindRideByID(ctx, rideID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Ride")
+		}
+		return nil, response.InternalServerError("Failed to get ride", err)
+	}
+	if ride.DriverID == nil {
+		return nil, response.BadRequest("No driver has been assigned to this ride yet")
+	}
+
+	ref := &callReference{
+		Type:       models.MaskedCallReferenceRide,
+		ID:         ride.ID,
+		CustomerID: ride.RiderID,
+		PartnerID:  *ride.DriverID,
+	}
+	switch ride.Status {
+	case "accepted", "arrived", "started":
+		ref.Active = true
+	case "completed":
+		ref.EndedAt = ride.CompletedAt
+	}
+	return ref, nil
+}
+
+func (s *service) orderReference(ctx context.Context, orderID string) (*callReference, error) {
+	order, err := s.repo.FindOrderByID(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Order")
+		}
+		return nil, response.InternalServerError("Failed to get order", err)
+	}
+	if order.AssignedProviderID == nil {
+		return nil, response.BadRequest("No provider has been assigned to this order yet")
+	}
+
+	providerUserID, err := s.repo.FindProviderUserID(ctx, *order.AssignedProviderID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get order provider", err)
+	}
+
+	ref := &callReference{
+		Type:       models.MaskedCallReferenceOrder,
+		ID:         order.ID,
+		CustomerID: order.CustomerID,
+		PartnerID:  providerUserID,
+	}
+	switch order.Status {
+	case shared.OrderStatusAssigned, shared.OrderStatusAccepted, shared.OrderStatusInProgress:
+		ref.Active = true
+	case shared.OrderStatusCompleted:
+		ref.EndedAt = order.CompletedAt
+	}
+	return ref, nil
+}
+
+// startCall returns the caller's proxy number for the ride or order, opening
+// a proxy session the first time either side taps to call.
+func (s *service) startCall(ctx context.Context, userID string, ref *callReference) (*dto.CallSessionResponse, error) {
+	if userID != ref.CustomerID && userID != ref.PartnerID {
+		return nil, response.ForbiddenError("You are not part of this trip")
+	}
+
+	now := time.Now()
+	if !ref.open(s.cfg.PostTripWindow, now) {
+		return nil, response.BadRequest("Calling is only available during an active trip or shortly after it ends")
+	}
+
+	existing, err := s.repo.FindActiveSession(ctx, ref.Type, ref.ID)
+	if err == nil && existing.ExpiresAt.After(now) {
+		return dto.ToCallSessionResponse(existing, userID), nil
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, response.InternalServerError("Failed to get call session", err)
+	}
+	if err == nil {
+		s.closeSession(ctx, existing)
+	}
+
+	customer, err := s.repo.FindUserByID(ctx, ref.CustomerID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get customer", err)
+	}
+	partner, err := s.repo.FindUserByID(ctx, ref.PartnerID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get partner", err)
+	}
+	if customer.Phone == nil || *customer.Phone == "" || partner.Phone == nil || *partner.Phone == "" {
+		return nil, response.BadRequest("Both parties need a phone number on their account to call")
+	}
+
+	session := &models.MaskedCallSession{
+		ReferenceType: ref.Type,
+		ReferenceID:   ref.ID,
+		Provider:      s.provider.Name(),
+		CustomerID:    ref.CustomerID,
+		PartnerID:     ref.PartnerID,
+		ExpiresAt:     now.Add(s.cfg.SessionTTL),
+	}
+
+	proxy, err := s.provider.CreateSession(ctx, SessionRequest{
+		Reference: fmt.Sprintf("%s-%s-%d", ref.Type, ref.ID, now.Unix()),
+		TTL:       s.cfg.SessionTTL,
+		Customer:  Participant{Name: customer.Name, Phone: *customer.Phone},
+		Partner:   Participant{Name: partner.Name, Phone: *partner.Phone},
+	})
+	if err != nil {
+		logger.Error("failed to open masked call session", "error", err, "referenceType", ref.Type, "referenceID", ref.ID)
+		session.Status = models.MaskedCallSessionFailed
+		session.FailureReason = err.Error()
+		closedAt := now
+		session.ClosedAt = &closedAt
+		if err := s.repo.CreateSession(ctx, session); err != nil {
+			logger.Error("failed to record failed call session", "error", err, "referenceID", ref.ID)
+		}
+		return nil, response.ServiceUnavailable("Unable to connect the call right now, please try again")
+	}
+
+	session.Status = models.MaskedCallSessionActive
+	session.ProviderSessionID = proxy.ID
+	session.CustomerParticipantID = proxy.CustomerParticipantID
+	session.CustomerProxy = proxy.CustomerProxy
+	session.PartnerParticipantID = proxy.PartnerParticipantID
+	session.PartnerProxy = proxy.PartnerProxy
+
+	if err := s.repo.CreateSession(ctx, session); err != nil {
+		// The other party opened a session at the same moment; use theirs.
+		if winner, findErr := s.repo.FindActiveSession(ctx, ref.Type, ref.ID); findErr == nil {
+			if closeErr := s.provider.CloseSession(ctx, proxy.ID); closeErr != nil {
+				logger.Warn("failed to close duplicate proxy session", "error", closeErr, "providerSessionID", proxy.ID)
+			}
+			return dto.ToCallSessionResponse(winner, userID), nil
+		}
+		return nil, response.InternalServerError("Failed to save call session", err)
+	}
+
+	logger.Info("masked call session opened",
+		"sessionID", session.ID,
+		"referenceType", ref.Type,
+		"referenceID", ref.ID,
+		"provider", session.Provider,
+	)
+
+	return dto.ToCallSessionResponse(session, userID), nil
+}
+
+// HandleCallback logs a call status reported by the provider against its
+// session, noting which side placed the call. The signature is checked
+// against the configured webhook URL, since that is the URL the provider
+// signed rather than the one the request arrived on behind the proxy.
+func (s *service) HandleCallback(ctx context.Context, form url.Values, signature string) error {
+	event, err := s.provider.ParseCallback(s.cfg.WebhookURL, form, signature)
+	if err != nil {
+		if errors.Is(err, ErrInvalidCallbackSignature) {
+			return response.UnauthorizedError("Invalid callback signature")
+		}
+		return response.BadRequest(err.Error())
+	}
+	if event == nil {
+		return nil
+	}
+
+	session, err := s.repo.FindSessionByProviderID(ctx, event.SessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Warn("call callback for unknown session", "providerSessionID", event.SessionID)
+			return nil
+		}
+		return response.InternalServerError("Failed to get call session", err)
+	}
+
+	record := &models.MaskedCallEvent{
+		SessionID:       session.ID,
+		ProviderEventID: event.ID,
+		Status:          strings.ToLower(event.Status),
+		DurationSec:     event.DurationSec,
+		OccurredAt:      event.OccurredAt,
+	}
+	switch event.CallerParticipantID {
+	case "":
+	case session.CustomerParticipantID:
+		record.CallerID = &session.CustomerID
+		record.CallerRole = "customer"
+	case session.PartnerParticipantID:
+		record.CallerID = &session.PartnerID
+		record.CallerRole = "partner"
+	}
+
+	if err := s.repo.CreateEvent(ctx, record); err != nil {
+		return response.InternalServerError("Failed to record call event", err)
+	}
+	return nil
+}
+
+func (s *service) ListSessions(ctx context.Context, req dto.ListSessionsRequest) ([]*dto.SessionLogResponse, int64, error) {
+	sessions, total, err := s.repo.ListSessions(ctx, req.ReferenceType, req.ReferenceID, req.UserID, req.Page, req.Limit)
+	if err != nil {
+		return nil, 0, response.InternalServerError("Failed to list call sessions", err)
+	}
+
+	ids := make([]string, 0, len(sessions))
+	for _, session := range sessions {
+		ids = append(ids, session.ID)
+	}
+	events, err := s.repo.ListEvents(ctx, ids)
+	if err != nil {
+		return nil, 0, response.InternalServerError("Failed to list call events", err)
+	}
+
+	bySession := make(map[string][]*models.MaskedCallEvent, len(sessions))
+	for _, event := range events {
+		bySession[event.SessionID] = append(bySession[event.SessionID], event)
+	}
+
+	result := make([]*dto.SessionLogResponse, 0, len(sessions))
+	for _, session := range sessions {
+		result = append(result, dto.ToSessionLogResponse(session, bySession[session.ID]))
+	}
+	return result, total, nil
+}
+
+// CloseEndedSessions closes sessions that have expired or whose ride or order
+// is no longer open for calls, so the proxy numbers stop ringing through.
+func (s *service) CloseEndedSessions(ctx context.Context) error {
+	sessions, err := s.repo.ListActiveSessions(ctx, sweepBatchSize)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, session := range sessions {
+		if session.ExpiresAt.After(now) {
+			ref, err := s.referenceFor(ctx, session)
+			if err != nil {
+				logger.Warn("failed to check call session reference", "error", err, "sessionID", session.ID)
+				continue
+			}
+			if ref.open(s.cfg.PostTripWindow, now) {
+				continue
+			}
+		}
+		s.closeSession(ctx, session)
+	}
+	return nil
+}
+
+func (s *service) referenceFor(ctx context.Context, session *models.MaskedCallSession) (*callReference, error) {
+	if session.ReferenceType == models.MaskedCallReferenceOrder {
+		return s.orderReference(ctx, session.ReferenceID)
+	}
+	return s.rideReference(ctx, session.ReferenceID)
+}
+
+func (s *service) closeSession(ctx context.Context, session *models.MaskedCallSession) {
+	// The provider expires sessions on its own TTL, so a failed close is not
+	// retried.
+	if session.ProviderSessionID != "" {
+		if err := s.provider.CloseSession(ctx, session.ProviderSessionID); err != nil {
+			logger.Warn("failed to close proxy session", "error", err, "sessionID", session.ID)
+		}
+	}
+
+	now := time.Now()
+	session.Status = models.MaskedCallSessionClosed
+	session.ClosedAt = &now
+	if err := s.repo.UpdateSession(ctx, session); err != nil {
+		logger.Error("failed to close call session", "error", err, "sessionID", session.ID)
+		return
+	}
+
+	logger.Info("masked call session closed", "sessionID", session.ID, "referenceID", session.ReferenceID)
+}
+
+// CallSessionSweeper closes ended call sessions on a fixed interval.
+type CallSessionSweeper struct {
+	service  Service
+	interval time.Duration
+}
+
+func NewCallSessionSweeper(service Service, cfg config.MaskedCallingConfig) *CallSessionSweeper {
+	interval := cfg.SweepInterval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &CallSessionSweeper{service: service, interval: interval}
+}
+
+func (w *CallSessionSweeper) Start(ctx context.Context) {
+	heartbeat := health.NewHeartbeat("call_session_sweeper", w.interval)
+
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				runCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+				if err := w.service.CloseEndedSessions(runCtx); err != nil {
+					logger.Error("call session sweep failed", "error", err)
+				}
+				cancel()
+				heartbeat.Beat()
+			}
+		}
+	}()
+
+	logger.Info("call session sweeper started", "interval", w.interval)
+}
@@ -0,0 +1,222 @@
+package calling
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+)
+
+// twilioProvider uses Twilio Proxy. Each ride or order gets a session with
+// the customer and the driver or provider as participants; Twilio hands each
+// of them a proxy number that rings the other.
+type twilioProvider struct {
+	apiURL     string
+	accountSID string
+	authToken  string
+	serviceSID string
+	client     *http.Client
+}
+
+func NewTwilioProvider(cfg config.MaskedCallingConfig) Provider {
+	return &twilioProvider{
+		apiURL:     strings.TrimRight(cfg.TwilioProxyAPIURL, "/"),
+		accountSID: cfg.TwilioAccountSID,
+		authToken:  cfg.TwilioAuthToken,
+		serviceSID: cfg.TwilioProxyServiceSID,
+		client:     &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (p *twilioProvider) Name() string {
+	return "twilio"
+}
+
+type twilioError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (p *twilioProvider) do(ctx context.Context, method, path string, form url.Values, out interface{}) error {
+	body := strings.NewReader("")
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.apiURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(p.accountSID, p.authToken)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr twilioError
+		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err == nil && apiErr.Message != "" {
+			return fmt.Errorf("twilio %d: %s", apiErr.Code, apiErr.Message)
+		}
+		return fmt.Errorf("twilio returned status %d", resp.StatusCode)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode twilio response: %w", err)
+	}
+	return nil
+}
+
+func (p *twilioProvider) sessionPath(sessionID string) string {
+	path := "/v1/Services/" + url.PathEscape(p.serviceSID) + "/Sessions"
+	if sessionID != "" {
+		path += "/" + url.PathEscape(sessionID)
+	}
+	return path
+}
+
+func (p *twilioProvider) CreateSession(ctx context.Context, req SessionRequest) (*ProxySession, error) {
+	form := url.Values{}
+	form.Set("UniqueName", req.Reference)
+	form.Set("Mode", "voice-only")
+	if req.TTL > 0 {
+		form.Set("Ttl", strconv.Itoa(int(req.TTL.Seconds())))
+	}
+
+	var created struct {
+		SID string `json:"sid"`
+	}
+	if err := p.do(ctx, http.MethodPost, p.sessionPath(""), form, &created); err != nil {
+		return nil, err
+	}
+
+	session := &ProxySession{ID: created.SID}
+
+	customer, err := p.addParticipant(ctx, created.SID, req.Customer)
+	if err != nil {
+		_ = p.CloseSession(ctx, created.SID)
+		return nil, fmt.Errorf("failed to add customer to proxy session: %w", err)
+	}
+	session.CustomerParticipantID = customer.SID
+	session.CustomerProxy = customer.ProxyIdentifier
+
+	partner, err := p.addParticipant(ctx, created.SID, req.Partner)
+	if err != nil {
+		_ = p.CloseSession(ctx, created.SID)
+		return nil, fmt.Errorf("failed to add partner to proxy session: %w", err)
+	}
+	session.PartnerParticipantID = partner.SID
+	session.PartnerProxy = partner.ProxyIdentifier
+
+	return session, nil
+}
+
+type twilioParticipant struct {
+	SID             string `json:"sid"`
+	ProxyIdentifier string `json:"proxy_identifier"`
+}
+
+func (p *twilioProvider) addParticipant(ctx context.Context, sessionID string, participant Participant) (*twilioParticipant, error) {
+	form := url.Values{}
+	form.Set("Identifier", participant.Phone)
+	if participant.Name != "" {
+		form.Set("FriendlyName", participant.Name)
+	}
+
+	var created twilioParticipant
+	if err := p.do(ctx, http.MethodPost, p.sessionPath(sessionID)+"/Participants", form, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+func (p *twilioProvider) CloseSession(ctx context.Context, sessionID string) error {
+	form := url.Values{}
+	form.Set("Status", "closed")
+	return p.do(ctx, http.MethodPost, p.sessionPath(sessionID), form, nil)
+}
+
+// ParseCallback verifies the X-Twilio-Signature header and reads a Proxy
+// interaction callback. Message interactions are ignored by returning nil.
+func (p *twilioProvider) ParseCallback(callbackURL string, form url.Values, signature string) (*CallEvent, error) {
+	if !p.validSignature(callbackURL, form, signature) {
+		return nil, ErrInvalidCallbackSignature
+	}
+
+	if form.Get("interactionType") != "" && !strings.EqualFold(form.Get("interactionType"), "Voice") {
+		return nil, nil
+	}
+
+	event := &CallEvent{
+		ID:                  form.Get("interactionSid"),
+		SessionID:           form.Get("interactionSessionSid"),
+		CallerParticipantID: form.Get("interactionInboundParticipantSid"),
+		Status:              form.Get("outboundResourceStatus"),
+		OccurredAt:          time.Now(),
+	}
+	if event.Status == "" {
+		event.Status = form.Get("inboundResourceStatus")
+	}
+	if event.ID == "" || event.SessionID == "" || event.Status == "" {
+		return nil, fmt.Errorf("incomplete proxy callback")
+	}
+
+	var data struct {
+		Duration string `json:"duration"`
+	}
+	if raw := form.Get("interactionData"); raw != "" && json.Unmarshal([]byte(raw), &data) == nil {
+		event.DurationSec, _ = strconv.Atoi(data.Duration)
+	}
+	return event, nil
+}
+
+// validSignature checks Twilio's base64 HMAC-SHA1 of the callback URL followed
+// by each POST parameter name and value in name order.
+func (p *twilioProvider) validSignature(callbackURL string, form url.Values, signature string) bool {
+	if signature == "" {
+		return false
+	}
+
+	keys := make([]string, 0, len(form))
+	for k := range form {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(callbackURL)
+	for _, k := range keys {
+		for _, v := range form[k] {
+			b.WriteString(k)
+			b.WriteString(v)
+		}
+	}
+
+	mac := hmac.New(sha1.New, []byte(p.authToken))
+	mac.Write([]byte(b.String()))
+	expected := mac.Sum(nil)
+
+	decoded, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(decoded, expected)
+}
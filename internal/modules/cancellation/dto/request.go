@@ -0,0 +1,121 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+)
+
+var rideStatuses = []string{"searching", "accepted", "arrived", "started"}
+
+var orderStatuses = []string{
+	shared.OrderStatusPending,
+	shared.OrderStatusSearchingProvider,
+	shared.OrderStatusAssigned,
+	shared.OrderStatusAccepted,
+	shared.OrderStatusInProgress,
+}
+
+type ListPoliciesRequest struct {
+	Product string `form:"product" binding:"omitempty,oneof=rides home_services"`
+}
+
+type CreatePolicyRequest struct {
+	Name                string                         `json:"name" binding:"required,max=255"`
+	Product             string                         `json:"product" binding:"required,oneof=rides home_services"`
+	VehicleTypeID       *string                        `json:"vehicleTypeId" binding:"omitempty,uuid"`
+	CategorySlug        *string                        `json:"categorySlug" binding:"omitempty,max=255"`
+	City                string                         `json:"city" binding:"omitempty,max=100"`
+	CenterLat           float64                        `json:"centerLat" binding:"min=-90,max=90"`
+	CenterLon           float64                        `json:"centerLon" binding:"min=-180,max=180"`
+	RadiusKm            float64                        `json:"radiusKm" binding:"min=0,max=5000"`
+	FreeWindowSeconds   int                            `json:"freeWindowSeconds" binding:"min=0"`
+	CustomerFees        models.CancellationFeeSchedule `json:"customerFees"`
+	DriverPenalties     models.CancellationFeeSchedule `json:"driverPenalties"`
+	DriverNoShowMinutes int                            `json:"driverNoShowMinutes" binding:"min=0"`
+	DriverNoShowPenalty models.CancellationFee         `json:"driverNoShowPenalty"`
+	RiderNoShowMinutes  int                            `json:"riderNoShowMinutes" binding:"min=0"`
+	RiderNoShowFee      models.CancellationFee         `json:"riderNoShowFee"`
+	Priority            int                            `json:"priority"`
+	IsActive            *bool                          `json:"isActive"`
+}
+
+type UpdatePolicyRequest struct {
+	Name                *string                         `json:"name" binding:"omitempty,max=255"`
+	VehicleTypeID       *string                         `json:"vehicleTypeId"`
+	CategorySlug        *string                         `json:"categorySlug" binding:"omitempty,max=255"`
+	City                *string                         `json:"city" binding:"omitempty,max=100"`
+	CenterLat           *float64                        `json:"centerLat" binding:"omitempty,min=-90,max=90"`
+	CenterLon           *float64                        `json:"centerLon" binding:"omitempty,min=-180,max=180"`
+	RadiusKm            *float64                        `json:"radiusKm" binding:"omitempty,min=0,max=5000"`
+	FreeWindowSeconds   *int                            `json:"freeWindowSeconds" binding:"omitempty,min=0"`
+	CustomerFees        *models.CancellationFeeSchedule `json:"customerFees"`
+	DriverPenalties     *models.CancellationFeeSchedule `json:"driverPenalties"`
+	DriverNoShowMinutes *int                            `json:"driverNoShowMinutes" binding:"omitempty,min=0"`
+	DriverNoShowPenalty *models.CancellationFee         `json:"driverNoShowPenalty"`
+	RiderNoShowMinutes  *int                            `json:"riderNoShowMinutes" binding:"omitempty,min=0"`
+	RiderNoShowFee      *models.CancellationFee         `json:"riderNoShowFee"`
+	Priority            *int                            `json:"priority"`
+	IsActive            *bool                           `json:"isActive"`
+}
+
+// ValidatePolicy checks a policy as it will be saved, after create or update
+// fields have been applied.
+func ValidatePolicy(policy *models.CancellationPolicy) error {
+	statuses := rideStatuses
+	if policy.Product == models.CancellationProductHomeServices {
+		statuses = orderStatuses
+		if policy.VehicleTypeID != nil {
+			return errors.New("vehicle type only applies to ride policies")
+		}
+		if policy.RiderNoShowMinutes > 0 {
+			return errors.New("rider no-show rules only apply to ride policies")
+		}
+	} else if policy.CategorySlug != nil {
+		return errors.New("category only applies to home services policies")
+	}
+
+	if err := validateSchedule("customerFees", policy.CustomerFees, statuses); err != nil {
+		return err
+	}
+	if err := validateSchedule("driverPenalties", policy.DriverPenalties, statuses); err != nil {
+		return err
+	}
+	if err := validateFee("driverNoShowPenalty", policy.DriverNoShowPenalty); err != nil {
+		return err
+	}
+	return validateFee("riderNoShowFee", policy.RiderNoShowFee)
+}
+
+func validateSchedule(field string, schedule models.CancellationFeeSchedule, statuses []string) error {
+	for status, fee := range schedule {
+		if !contains(statuses, status) {
+			return fmt.Errorf("%s: '%s' is not a cancellable status", field, status)
+		}
+		if err := validateFee(fmt.Sprintf("%s.%s", field, status), fee); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateFee(field string, fee models.CancellationFee) error {
+	if fee.Flat < 0 {
+		return fmt.Errorf("%s: flat fee cannot be negative", field)
+	}
+	if fee.Percent < 0 || fee.Percent > 100 {
+		return fmt.Errorf("%s: percent must be between 0 and 100", field)
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+type PolicyResponse struct {
+	ID                  string                         `json:"id"`
+	Name                string                         `json:"name"`
+	Product             string                         `json:"product"`
+	VehicleTypeID       *string                        `json:"vehicleTypeId,omitempty"`
+	CategorySlug        *string                        `json:"categorySlug,omitempty"`
+	City                string                         `json:"city,omitempty"`
+	CenterLat           float64                        `json:"centerLat"`
+	CenterLon           float64                        `json:"centerLon"`
+	RadiusKm            float64                        `json:"radiusKm"`
+	FreeWindowSeconds   int                            `json:"freeWindowSeconds"`
+	CustomerFees        models.CancellationFeeSchedule `json:"customerFees"`
+	DriverPenalties     models.CancellationFeeSchedule `json:"driverPenalties"`
+	DriverNoShowMinutes int                            `json:"driverNoShowMinutes"`
+	DriverNoShowPenalty models.CancellationFee         `json:"driverNoShowPenalty"`
+	RiderNoShowMinutes  int                            `json:"riderNoShowMinutes"`
+	RiderNoShowFee      models.CancellationFee         `json:"riderNoShowFee"`
+	Priority            int                            `json:"priority"`
+	IsActive            bool                           `json:"isActive"`
+	CreatedAt           time.Time                      `json:"createdAt"`
+	UpdatedAt           time.Time                      `json:"updatedAt"`
+}
+
+func ToPolicyResponse(policy *models.CancellationPolicy) *PolicyResponse {
+	return &PolicyResponse{
+		ID:                  policy.ID,
+		Name:                policy.Name,
+		Product:             policy.Product,
+		VehicleTypeID:       policy.VehicleTypeID,
+		CategorySlug:        policy.CategorySlug,
+		City:                policy.City,
+		CenterLat:           policy.CenterLat,
+		CenterLon:           policy.CenterLon,
+		RadiusKm:            policy.RadiusKm,
+		FreeWindowSeconds:   policy.FreeWindowSeconds,
+		CustomerFees:        policy.CustomerFees,
+		DriverPenalties:     policy.DriverPenalties,
+		DriverNoShowMinutes: policy.DriverNoShowMinutes,
+		DriverNoShowPenalty: policy.DriverNoShowPenalty,
+		RiderNoShowMinutes:  policy.RiderNoShowMinutes,
+		RiderNoShowFee:      policy.RiderNoShowFee,
+		Priority:            policy.Priority,
+		IsActive:            policy.IsActive,
+		CreatedAt:           policy.CreatedAt,
+		UpdatedAt:           policy.UpdatedAt,
+	}
+}
+
+func ToPolicyResponses(policies []*models.CancellationPolicy) []*PolicyResponse {
+	result := make([]*PolicyResponse, 0, len(policies))
+	for _, policy := range policies {
+		result = append(result, ToPolicyResponse(policy))
+	}
+	return result
+}
@@ -0,0 +1,126 @@
+package cancellation
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/modules/cancellation/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// ListPolicies godoc
+// @Summary List cancellation policies
+// @Tags cancellation - admin
+// @Security BearerAuth
+// @Produce json
+// @Param product query string false "rides or home_services"
+// @Success 200 {object} response.Response{data=[]dto.PolicyResponse}
+// @Router /cancellation-policies [get]
+func (h *Handler) ListPolicies(c *gin.Context) {
+	var req dto.ListPoliciesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+
+	policies, err := h.service.ListPolicies(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, policies, "Cancellation policies retrieved successfully")
+}
+
+// GetPolicy godoc
+// @Summary Get a cancellation policy
+// @Tags cancellation - admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Policy ID"
+// @Success 200 {object} response.Response{data=dto.PolicyResponse}
+// @Router /cancellation-policies/{id} [get]
+func (h *Handler) GetPolicy(c *gin.Context) {
+	policy, err := h.service.GetPolicy(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, policy, "Cancellation policy retrieved successfully")
+}
+
+// CreatePolicy godoc
+// @Summary Create a cancellation policy
+// @Description Fee schedules are keyed by ride or order status; each fee is a flat amount plus a percentage of the fare or order total
+// @Tags cancellation - admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreatePolicyRequest true "Policy details"
+// @Success 200 {object} response.Response{data=dto.PolicyResponse}
+// @Router /cancellation-policies [post]
+func (h *Handler) CreatePolicy(c *gin.Context) {
+	var req dto.CreatePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	policy, err := h.service.CreatePolicy(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, policy, "Cancellation policy created successfully")
+}
+
+// UpdatePolicy godoc
+// @Summary Update a cancellation policy
+// @Tags cancellation - admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Policy ID"
+// @Param request body dto.UpdatePolicyRequest true "Fields to update"
+// @Success 200 {object} response.Response{data=dto.PolicyResponse}
+// @Router /cancellation-policies/{id} [put]
+func (h *Handler) UpdatePolicy(c *gin.Context) {
+	var req dto.UpdatePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	policy, err := h.service.UpdatePolicy(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, policy, "Cancellation policy updated successfully")
+}
+
+// DeletePolicy godoc
+// @Summary Delete a cancellation policy
+// @Tags cancellation - admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Policy ID"
+// @Success 200 {object} response.Response
+// @Router /cancellation-policies/{id} [delete]
+func (h *Handler) DeletePolicy(c *gin.Context) {
+	if err := h.service.DeletePolicy(c.Request.Context(), c.Param("id")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, nil, "Cancellation policy deleted successfully")
+}
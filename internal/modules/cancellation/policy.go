@@ -0,0 +1,215 @@
+package cancellation
+
+import (
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+	"github.com/umar5678/go-backend/internal/utils/location"
+)
+
+// Cancellation describes a ride or home service order at the moment it is
+// cancelled.
+type Cancellation struct {
+	Product       string
+	VehicleTypeID string
+	CategorySlug  string
+	Lat           float64
+	Lon           float64
+	Status        string
+	// Total is the fare or order total that percentage fees are taken from.
+	Total float64
+	// ByCustomer is set when the rider or customer cancels, and clear when the
+	// driver or provider does.
+	ByCustomer  bool
+	RequestedAt time.Time
+	AcceptedAt  *time.Time
+	ArrivedAt   *time.Time
+	// AwaitingArrival is set while the driver or provider has accepted but not
+	// yet turned up.
+	AwaitingArrival bool
+	// ScheduledAt is the booked start of an order; no-shows are counted from it.
+	ScheduledAt *time.Time
+	At          time.Time
+}
+
+const (
+	RuleNoFee        = "no_fee"
+	RuleFreeWindow   = "free_window"
+	RuleFeeSchedule  = "fee_schedule"
+	RuleDriverNoShow = "driver_no_show"
+	RuleRiderNoShow  = "rider_no_show"
+)
+
+// Outcome is what a cancellation costs under the policy that applied to it.
+// PolicyID is empty when the built-in default was used.
+type Outcome struct {
+	PolicyID      string  `json:"policyId,omitempty"`
+	PolicyName    string  `json:"policyName"`
+	Rule          string  `json:"rule"`
+	CustomerFee   float64 `json:"customerFee"`
+	DriverPenalty float64 `json:"driverPenalty"`
+}
+
+// defaultPolicies keep the fees charged before policies were configurable and
+// apply whenever no configured policy matches. Their no-show terms let drivers
+// and customers report a no-show without a configured policy.
+var defaultPolicies = map[string]*models.CancellationPolicy{
+	models.CancellationProductRides: {
+		Name:    "Default ride policy",
+		Product: models.CancellationProductRides,
+		CustomerFees: models.CancellationFeeSchedule{
+			"accepted": {Flat: 2},
+			"arrived":  {Flat: 2},
+			"started":  {Flat: 5},
+		},
+		DriverPenalties: models.CancellationFeeSchedule{
+			"accepted": {Flat: 3},
+			"arrived":  {Flat: 3},
+			"started":  {Flat: 10},
+		},
+		RiderNoShowMinutes: 5,
+		RiderNoShowFee:     models.CancellationFee{Flat: 3},
+	},
+	models.CancellationProductHomeServices: {
+		Name:    "Default home services policy",
+		Product: models.CancellationProductHomeServices,
+		CustomerFees: models.CancellationFeeSchedule{
+			shared.OrderStatusPending:           {Percent: shared.CancellationFeeBeforeAcceptance * 100},
+			shared.OrderStatusSearchingProvider: {Percent: shared.CancellationFeeBeforeAcceptance * 100},
+			shared.OrderStatusAssigned:          {Percent: shared.CancellationFeeAfterAcceptance * 100},
+			shared.OrderStatusAccepted:          {Percent: shared.CancellationFeeAfterAcceptance * 100},
+			shared.OrderStatusInProgress:        {Percent: shared.CancellationFeeAfterStart * 100},
+		},
+		DriverPenalties:     models.CancellationFeeSchedule{},
+		DriverNoShowMinutes: 30,
+		DriverNoShowPenalty: models.CancellationFee{Percent: 10},
+	},
+}
+
+// ApplyDefault prices a cancellation under the built-in policy for its product.
+func ApplyDefault(c Cancellation) *Outcome {
+	policy, ok := defaultPolicies[c.Product]
+	if !ok {
+		return &Outcome{Rule: RuleNoFee}
+	}
+	return Apply(policy, c)
+}
+
+// Apply prices a cancellation under the given policy. A driver no-show or the
+// free window excuses the customer; otherwise whoever cancelled pays the fee
+// scheduled for the status the ride or order was in.
+func Apply(policy *models.CancellationPolicy, c Cancellation) *Outcome {
+	at := c.At
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	outcome := &Outcome{PolicyID: policy.ID, PolicyName: policy.Name, Rule: RuleNoFee}
+
+	if c.ByCustomer {
+		if driverNoShow(policy, c, at) {
+			outcome.Rule = RuleDriverNoShow
+			outcome.DriverPenalty = policy.DriverNoShowPenalty.Amount(c.Total)
+			return outcome
+		}
+		if inFreeWindow(policy, c, at) {
+			outcome.Rule = RuleFreeWindow
+			return outcome
+		}
+		if fee, ok := policy.CustomerFees[c.Status]; ok {
+			outcome.CustomerFee = fee.Amount(c.Total)
+		}
+	} else {
+		if riderNoShow(policy, c, at) {
+			outcome.Rule = RuleRiderNoShow
+			outcome.CustomerFee = policy.RiderNoShowFee.Amount(c.Total)
+			return outcome
+		}
+		if penalty, ok := policy.DriverPenalties[c.Status]; ok {
+			outcome.DriverPenalty = penalty.Amount(c.Total)
+		}
+	}
+
+	if outcome.CustomerFee > 0 || outcome.DriverPenalty > 0 {
+		outcome.Rule = RuleFeeSchedule
+	}
+	return outcome
+}
+
+// inFreeWindow counts the window from acceptance, or from the request while
+// nobody has accepted yet.
+func inFreeWindow(policy *models.CancellationPolicy, c Cancellation, at time.Time) bool {
+	if policy.FreeWindowSeconds <= 0 {
+		return false
+	}
+	from := c.RequestedAt
+	if c.AcceptedAt != nil {
+		from = *c.AcceptedAt
+	}
+	return !from.IsZero() && at.Sub(from) <= time.Duration(policy.FreeWindowSeconds)*time.Second
+}
+
+func driverNoShow(policy *models.CancellationPolicy, c Cancellation, at time.Time) bool {
+	if policy.DriverNoShowMinutes <= 0 || !c.AwaitingArrival {
+		return false
+	}
+	from := c.AcceptedAt
+	if c.ScheduledAt != nil {
+		from = c.ScheduledAt
+	}
+	return from != nil && at.Sub(*from) >= time.Duration(policy.DriverNoShowMinutes)*time.Minute
+}
+
+func riderNoShow(policy *models.CancellationPolicy, c Cancellation, at time.Time) bool {
+	if policy.RiderNoShowMinutes <= 0 || c.ArrivedAt == nil || c.Status != "arrived" {
+		return false
+	}
+	return at.Sub(*c.ArrivedAt) >= time.Duration(policy.RiderNoShowMinutes)*time.Minute
+}
+
+func matches(policy *models.CancellationPolicy, c Cancellation) bool {
+	if policy.Product != c.Product {
+		return false
+	}
+	if policy.VehicleTypeID != nil && *policy.VehicleTypeID != c.VehicleTypeID {
+		return false
+	}
+	if policy.CategorySlug != nil && *policy.CategorySlug != c.CategorySlug {
+		return false
+	}
+	if policy.RadiusKm > 0 && location.HaversineDistance(policy.CenterLat, policy.CenterLon, c.Lat, c.Lon) > policy.RadiusKm {
+		return false
+	}
+	return true
+}
+
+// specificity ranks a vehicle type or category match above a city match, and
+// both above a catch-all policy.
+func specificity(policy *models.CancellationPolicy) int {
+	score := 0
+	if policy.VehicleTypeID != nil || policy.CategorySlug != nil {
+		score += 2
+	}
+	if policy.RadiusKm > 0 {
+		score++
+	}
+	return score
+}
+
+// selectPolicy picks the most specific matching policy, breaking ties on
+// priority.
+func selectPolicy(policies []*models.CancellationPolicy, c Cancellation) *models.CancellationPolicy {
+	var best *models.CancellationPolicy
+	for _, policy := range policies {
+		if !matches(policy, c) {
+			continue
+		}
+		if best == nil ||
+			specificity(policy) > specificity(best) ||
+			(specificity(policy) == specificity(best) && policy.Priority > best.Priority) {
+			best = policy
+		}
+	}
+	return best
+}
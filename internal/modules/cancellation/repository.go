@@ -0,0 +1,57 @@
+package cancellation
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	ListPolicies(ctx context.Context, product string, activeOnly bool) ([]*models.CancellationPolicy, error)
+	GetPolicy(ctx context.Context, id string) (*models.CancellationPolicy, error)
+	CreatePolicy(ctx context.Context, policy *models.CancellationPolicy) error
+	UpdatePolicy(ctx context.Context, policy *models.CancellationPolicy) error
+	DeletePolicy(ctx context.Context, id string) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) ListPolicies(ctx context.Context, product string, activeOnly bool) ([]*models.CancellationPolicy, error) {
+	var policies []*models.CancellationPolicy
+
+	db := r.db.WithContext(ctx)
+	if product != "" {
+		db = db.Where("product = ?", product)
+	}
+	if activeOnly {
+		db = db.Where("is_active = ?", true)
+	}
+
+	err := db.Order("product ASC, priority DESC, name ASC").Find(&policies).Error
+	return policies, err
+}
+
+func (r *repository) GetPolicy(ctx context.Context, id string) (*models.CancellationPolicy, error) {
+	var policy models.CancellationPolicy
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&policy).Error
+	return &policy, err
+}
+
+func (r *repository) CreatePolicy(ctx context.Context, policy *models.CancellationPolicy) error {
+	return r.db.WithContext(ctx).Create(policy).Error
+}
+
+func (r *repository) UpdatePolicy(ctx context.Context, policy *models.CancellationPolicy) error {
+	return r.db.WithContext(ctx).Save(policy).Error
+}
+
+func (r *repository) DeletePolicy(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.CancellationPolicy{}).Error
+}
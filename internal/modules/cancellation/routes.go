@@ -0,0 +1,17 @@
+package cancellation
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/middleware"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
+	policies := router.Group("/cancellation-policies", authMiddleware, middleware.RequireAdmin())
+	{
+		policies.GET("", handler.ListPolicies)
+		policies.POST("", handler.CreatePolicy)
+		policies.GET("/:id", handler.GetPolicy)
+		policies.PUT("/:id", handler.UpdatePolicy)
+		policies.DELETE("/:id", handler.DeletePolicy)
+	}
+}
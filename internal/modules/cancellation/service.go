@@ -0,0 +1,219 @@
+package cancellation
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/cancellation/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	Evaluate(ctx context.Context, c Cancellation) *Outcome
+
+	ListPolicies(ctx context.Context, req dto.ListPoliciesRequest) ([]*dto.PolicyResponse, error)
+	GetPolicy(ctx context.Context, id string) (*dto.PolicyResponse, error)
+	CreatePolicy(ctx context.Context, req dto.CreatePolicyRequest) (*dto.PolicyResponse, error)
+	UpdatePolicy(ctx context.Context, id string, req dto.UpdatePolicyRequest) (*dto.PolicyResponse, error)
+	DeletePolicy(ctx context.Context, id string) error
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+// Evaluate prices a cancellation under the most specific active policy for
+// it. Cancelling is never blocked on the policy store; if it cannot be read
+// the built-in default applies.
+func (s *service) Evaluate(ctx context.Context, c Cancellation) *Outcome {
+	policies, err := s.repo.ListPolicies(ctx, c.Product, true)
+	if err != nil {
+		logger.Error("failed to load cancellation policies", "error", err, "product", c.Product)
+		return ApplyDefault(c)
+	}
+
+	policy := selectPolicy(policies, c)
+	if policy == nil {
+		return ApplyDefault(c)
+	}
+	return Apply(policy, c)
+}
+
+func (s *service) ListPolicies(ctx context.Context, req dto.ListPoliciesRequest) ([]*dto.PolicyResponse, error) {
+	policies, err := s.repo.ListPolicies(ctx, req.Product, false)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to list cancellation policies", err)
+	}
+	return dto.ToPolicyResponses(policies), nil
+}
+
+func (s *service) GetPolicy(ctx context.Context, id string) (*dto.PolicyResponse, error) {
+	policy, err := s.getPolicy(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToPolicyResponse(policy), nil
+}
+
+func (s *service) CreatePolicy(ctx context.Context, req dto.CreatePolicyRequest) (*dto.PolicyResponse, error) {
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	policy := &models.CancellationPolicy{
+		Name:                strings.TrimSpace(req.Name),
+		Product:             req.Product,
+		VehicleTypeID:       req.VehicleTypeID,
+		CategorySlug:        req.CategorySlug,
+		City:                strings.TrimSpace(req.City),
+		CenterLat:           req.CenterLat,
+		CenterLon:           req.CenterLon,
+		RadiusKm:            req.RadiusKm,
+		FreeWindowSeconds:   req.FreeWindowSeconds,
+		CustomerFees:        req.CustomerFees,
+		DriverPenalties:     req.DriverPenalties,
+		DriverNoShowMinutes: req.DriverNoShowMinutes,
+		DriverNoShowPenalty: req.DriverNoShowPenalty,
+		RiderNoShowMinutes:  req.RiderNoShowMinutes,
+		RiderNoShowFee:      req.RiderNoShowFee,
+		Priority:            req.Priority,
+		IsActive:            isActive,
+	}
+	if policy.CustomerFees == nil {
+		policy.CustomerFees = models.CancellationFeeSchedule{}
+	}
+	if policy.DriverPenalties == nil {
+		policy.DriverPenalties = models.CancellationFeeSchedule{}
+	}
+
+	if err := dto.ValidatePolicy(policy); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	if err := s.repo.CreatePolicy(ctx, policy); err != nil {
+		return nil, response.InternalServerError("Failed to create cancellation policy", err)
+	}
+	// The column defaults to active, so an inactive policy needs a second write.
+	if !isActive {
+		policy.IsActive = false
+		if err := s.repo.UpdatePolicy(ctx, policy); err != nil {
+			return nil, response.InternalServerError("Failed to create cancellation policy", err)
+		}
+	}
+
+	logger.Info("cancellation policy created", "policyID", policy.ID, "name", policy.Name, "product", policy.Product)
+
+	return dto.ToPolicyResponse(policy), nil
+}
+
+func (s *service) UpdatePolicy(ctx context.Context, id string, req dto.UpdatePolicyRequest) (*dto.PolicyResponse, error) {
+	policy, err := s.getPolicy(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		policy.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.VehicleTypeID != nil {
+		policy.VehicleTypeID = emptyToNil(*req.VehicleTypeID)
+	}
+	if req.CategorySlug != nil {
+		policy.CategorySlug = emptyToNil(*req.CategorySlug)
+	}
+	if req.City != nil {
+		policy.City = strings.TrimSpace(*req.City)
+	}
+	if req.CenterLat != nil {
+		policy.CenterLat = *req.CenterLat
+	}
+	if req.CenterLon != nil {
+		policy.CenterLon = *req.CenterLon
+	}
+	if req.RadiusKm != nil {
+		policy.RadiusKm = *req.RadiusKm
+	}
+	if req.FreeWindowSeconds != nil {
+		policy.FreeWindowSeconds = *req.FreeWindowSeconds
+	}
+	if req.CustomerFees != nil {
+		policy.CustomerFees = *req.CustomerFees
+	}
+	if req.DriverPenalties != nil {
+		policy.DriverPenalties = *req.DriverPenalties
+	}
+	if req.DriverNoShowMinutes != nil {
+		policy.DriverNoShowMinutes = *req.DriverNoShowMinutes
+	}
+	if req.DriverNoShowPenalty != nil {
+		policy.DriverNoShowPenalty = *req.DriverNoShowPenalty
+	}
+	if req.RiderNoShowMinutes != nil {
+		policy.RiderNoShowMinutes = *req.RiderNoShowMinutes
+	}
+	if req.RiderNoShowFee != nil {
+		policy.RiderNoShowFee = *req.RiderNoShowFee
+	}
+	if req.Priority != nil {
+		policy.Priority = *req.Priority
+	}
+	if req.IsActive != nil {
+		policy.IsActive = *req.IsActive
+	}
+
+	if err := dto.ValidatePolicy(policy); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	if err := s.repo.UpdatePolicy(ctx, policy); err != nil {
+		return nil, response.InternalServerError("Failed to update cancellation policy", err)
+	}
+
+	logger.Info("cancellation policy updated", "policyID", policy.ID, "name", policy.Name)
+
+	return dto.ToPolicyResponse(policy), nil
+}
+
+func (s *service) DeletePolicy(ctx context.Context, id string) error {
+	policy, err := s.getPolicy(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeletePolicy(ctx, policy.ID); err != nil {
+		return response.InternalServerError("Failed to delete cancellation policy", err)
+	}
+
+	logger.Info("cancellation policy deleted", "policyID", policy.ID, "name", policy.Name)
+
+	return nil
+}
+
+func (s *service) getPolicy(ctx context.Context, id string) (*models.CancellationPolicy, error) {
+	policy, err := s.repo.GetPolicy(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Cancellation policy")
+		}
+		return nil, response.InternalServerError("Failed to load cancellation policy", err)
+	}
+	return policy, nil
+}
+
+// emptyToNil lets an update clear an optional match by sending an empty string.
+func emptyToNil(value string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
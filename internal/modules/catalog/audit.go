@@ -0,0 +1,31 @@
+package catalog
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/audit"
+	"github.com/umar5678/go-backend/internal/modules/catalog/dto"
+)
+
+func (s *service) SetAuditLogger(auditLogger audit.Recorder) {
+	s.auditLogger = auditLogger
+}
+
+func (s *service) recordImport(ctx context.Context, adminID string, result *dto.ImportResult) {
+	if s.auditLogger == nil {
+		return
+	}
+	s.auditLogger.Record(ctx, audit.Entry{
+		ActorID:    adminID,
+		ActorRole:  string(models.RoleAdmin),
+		Action:     "catalog.import",
+		EntityType: "catalog",
+		EntityID:   result.Kind,
+		After: map[string]interface{}{
+			"rows":    result.Rows,
+			"created": result.Created,
+			"updated": result.Updated,
+		},
+	})
+}
@@ -0,0 +1,17 @@
+package dto
+
+type ExportQuery struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv xlsx"`
+}
+
+func (q *ExportQuery) SetDefaults() {
+	if q.Format == "" {
+		q.Format = "csv"
+	}
+}
+
+type ImportQuery struct {
+	// Format defaults to the uploaded file's extension.
+	Format string `form:"format" binding:"omitempty,oneof=csv xlsx"`
+	DryRun bool   `form:"dryRun"`
+}
@@ -0,0 +1,27 @@
+package dto
+
+// RowError points at one problem in an uploaded sheet. Row is the spreadsheet
+// row number, counting the header as row 1.
+type RowError struct {
+	Row     int    `json:"row"`
+	Key     string `json:"key,omitempty"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+type ImportResult struct {
+	Kind    string     `json:"kind"`
+	DryRun  bool       `json:"dryRun"`
+	Applied bool       `json:"applied"`
+	Rows    int        `json:"rows"`
+	Created int        `json:"created"`
+	Updated int        `json:"updated"`
+	Errors  []RowError `json:"errors"`
+}
+
+// ExportFile is a rendered catalog sheet ready to be sent as a download.
+type ExportFile struct {
+	FileName    string
+	ContentType string
+	Data        []byte
+}
@@ -0,0 +1,120 @@
+package catalog
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/modules/catalog/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// ExportCatalog godoc
+// @Summary Export a catalog sheet
+// @Description Download services, add-ons or laundry products as CSV or XLSX. The file can be edited and re-imported
+// @Tags admin-catalog
+// @Security BearerAuth
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param kind path string true "services, addons or laundry-products"
+// @Param format query string false "csv (default) or xlsx"
+// @Success 200 {file} file
+// @Router /admin/catalog/{kind}/export [get]
+func (h *Handler) ExportCatalog(c *gin.Context) {
+	var query dto.ExportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+	query.SetDefaults()
+
+	file, err := h.service.Export(c.Request.Context(), c.Param("kind"), query.Format)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.FileName))
+	c.Data(http.StatusOK, file.ContentType, file.Data)
+}
+
+// ImportCatalog godoc
+// @Summary Import a catalog sheet
+// @Description Upsert services (by service_slug), add-ons (by addon_slug) or laundry products (by service_slug and slug) from CSV or XLSX. Every row is validated first; if any row fails nothing is written and all problems are listed per row. Use dryRun=true to validate only
+// @Tags admin-catalog
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param kind path string true "services, addons or laundry-products"
+// @Param format query string false "csv or xlsx, defaults to the file extension"
+// @Param dryRun query bool false "Validate without saving"
+// @Param file formData file true "Catalog sheet"
+// @Success 200 {object} response.Response{data=dto.ImportResult}
+// @Router /admin/catalog/{kind}/import [post]
+func (h *Handler) ImportCatalog(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	var query dto.ImportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.Error(response.BadRequest("File is required"))
+		return
+	}
+	if fileHeader.Size > maxImportBytes {
+		c.Error(response.BadRequest(fmt.Sprintf("File is larger than %d MB", maxImportBytes>>20)))
+		return
+	}
+
+	format := query.Format
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(fileHeader.Filename)), ".")
+	}
+	if _, ok := contentTypes[format]; !ok {
+		c.Error(response.BadRequest("File must be .csv or .xlsx"))
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.Error(response.BadRequest("Could not read uploaded file"))
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxImportBytes+1))
+	if err != nil {
+		c.Error(response.BadRequest("Could not read uploaded file"))
+		return
+	}
+
+	result, err := h.service.Import(c.Request.Context(), adminID.(string), c.Param("kind"), format, data, query.DryRun)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	message := "Catalog imported successfully"
+	switch {
+	case len(result.Errors) > 0:
+		message = fmt.Sprintf("Import rejected: %d problem(s) found, nothing was saved", len(result.Errors))
+	case !result.Applied:
+		message = "Catalog validated, nothing was saved"
+	}
+	response.Success(c, result, message)
+}
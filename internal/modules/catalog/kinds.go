@@ -0,0 +1,161 @@
+package catalog
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+const (
+	KindServices        = "services"
+	KindAddons          = "addons"
+	KindLaundryProducts = "laundry-products"
+)
+
+// Column order is the order of the exported sheet. Imports match columns by
+// header name, so operators may reorder or drop optional columns.
+var (
+	serviceColumns = []string{
+		"service_slug", "title", "long_title", "category_slug", "description", "long_description",
+		"highlights", "whats_included", "terms_and_conditions", "banner_image", "thumbnail",
+		"duration", "is_frequent", "frequency", "sort_order", "is_active", "is_available", "base_price",
+	}
+	addonColumns = []string{
+		"addon_slug", "title", "category_slug", "description", "whats_included", "notes", "image",
+		"price", "strikethrough_price", "is_active", "is_available", "sort_order",
+	}
+	laundryProductColumns = []string{
+		"service_slug", "slug", "name", "description", "icon_url", "price", "pricing_unit",
+		"typical_weight", "requires_special_care", "special_care_fee", "display_order",
+		"category_slug", "is_active",
+	}
+
+	requiredColumns = map[string][]string{
+		KindServices:        {"service_slug", "title", "category_slug"},
+		KindAddons:          {"addon_slug", "title", "category_slug", "price"},
+		KindLaundryProducts: {"service_slug", "slug", "name", "price"},
+	}
+
+	laundryPricingUnits = map[string]bool{"item": true, "kg": true}
+)
+
+func columnsFor(kind string) []string {
+	switch kind {
+	case KindServices:
+		return serviceColumns
+	case KindAddons:
+		return addonColumns
+	case KindLaundryProducts:
+		return laundryProductColumns
+	}
+	return nil
+}
+
+func serviceRow(s *models.ServiceNew) []string {
+	return []string{
+		s.ServiceSlug, s.Title, s.LongTitle, s.CategorySlug, s.Description, s.LongDescription,
+		s.Highlights, strings.Join(s.WhatsIncluded, listSeparator), strings.Join(s.TermsAndConditions, listSeparator),
+		s.BannerImage, s.Thumbnail, formatOptionalInt(s.Duration), strconv.FormatBool(s.IsFrequent), s.Frequency,
+		strconv.Itoa(s.SortOrder), strconv.FormatBool(s.IsActive), strconv.FormatBool(s.IsAvailable),
+		formatOptionalFloat(s.BasePrice),
+	}
+}
+
+func parseServiceRow(r *rowReader) *models.ServiceNew {
+	r.key = r.values["service_slug"]
+	return &models.ServiceNew{
+		ServiceSlug:        r.slug("service_slug", true),
+		Title:              r.text("title", true, 255),
+		LongTitle:          r.text("long_title", false, 500),
+		CategorySlug:       r.slug("category_slug", true),
+		Description:        r.text("description", false, 0),
+		LongDescription:    r.text("long_description", false, 0),
+		Highlights:         r.text("highlights", false, 0),
+		WhatsIncluded:      pq.StringArray(r.list("whats_included")),
+		TermsAndConditions: pq.StringArray(r.list("terms_and_conditions")),
+		BannerImage:        r.text("banner_image", false, 500),
+		Thumbnail:          r.text("thumbnail", false, 500),
+		Duration:           r.optionalInt("duration"),
+		IsFrequent:         r.bool("is_frequent", false),
+		Frequency:          r.text("frequency", false, 100),
+		SortOrder:          r.int("sort_order"),
+		IsActive:           r.bool("is_active", true),
+		IsAvailable:        r.bool("is_available", true),
+		BasePrice:          r.optionalFloat("base_price"),
+	}
+}
+
+func addonRow(a *models.Addon) []string {
+	return []string{
+		a.AddonSlug, a.Title, a.CategorySlug, a.Description,
+		strings.Join(a.WhatsIncluded, listSeparator), strings.Join(a.Notes, listSeparator), a.Image,
+		formatFloat(a.Price), formatOptionalFloat(a.StrikethroughPrice),
+		strconv.FormatBool(a.IsActive), strconv.FormatBool(a.IsAvailable), strconv.Itoa(a.SortOrder),
+	}
+}
+
+func parseAddonRow(r *rowReader) *models.Addon {
+	r.key = r.values["addon_slug"]
+	addon := &models.Addon{
+		AddonSlug:          r.slug("addon_slug", true),
+		Title:              r.text("title", true, 255),
+		CategorySlug:       r.slug("category_slug", true),
+		Description:        r.text("description", false, 0),
+		WhatsIncluded:      pq.StringArray(r.list("whats_included")),
+		Notes:              pq.StringArray(r.list("notes")),
+		Image:              r.text("image", false, 500),
+		Price:              r.float("price", true),
+		StrikethroughPrice: r.optionalFloat("strikethrough_price"),
+		IsActive:           r.bool("is_active", true),
+		IsAvailable:        r.bool("is_available", true),
+		SortOrder:          r.int("sort_order"),
+	}
+	if addon.StrikethroughPrice != nil && *addon.StrikethroughPrice <= addon.Price {
+		r.fail("strikethrough_price", "must be higher than price")
+	}
+	return addon
+}
+
+func laundryProductRow(p *models.LaundryServiceProduct) []string {
+	return []string{
+		p.ServiceSlug, p.Slug, p.Name, p.Description, formatOptionalText(p.IconURL),
+		formatOptionalFloat(p.Price), formatOptionalText(p.PricingUnit), formatOptionalFloat(p.TypicalWeight),
+		strconv.FormatBool(p.RequiresSpecialCare), formatFloat(p.SpecialCareFee), strconv.Itoa(p.DisplayOrder),
+		p.CategorySlug, strconv.FormatBool(p.IsActive),
+	}
+}
+
+func parseLaundryProductRow(r *rowReader) *models.LaundryServiceProduct {
+	r.key = r.values["service_slug"] + "/" + r.values["slug"]
+	product := &models.LaundryServiceProduct{
+		ServiceSlug:         r.slug("service_slug", true),
+		Slug:                r.slug("slug", true),
+		Name:                r.text("name", true, 255),
+		Description:         r.text("description", false, 0),
+		IconURL:             r.optionalText("icon_url", 500),
+		Price:               r.optionalFloat("price"),
+		PricingUnit:         r.optionalText("pricing_unit", 20),
+		TypicalWeight:       r.optionalFloat("typical_weight"),
+		RequiresSpecialCare: r.bool("requires_special_care", false),
+		SpecialCareFee:      r.float("special_care_fee", false),
+		DisplayOrder:        r.int("display_order"),
+		CategorySlug:        r.slug("category_slug", false),
+		IsActive:            r.bool("is_active", true),
+	}
+	if product.Price == nil && r.values["price"] == "" {
+		r.fail("price", "is required")
+	}
+	if product.PricingUnit == nil {
+		unit := "item"
+		product.PricingUnit = &unit
+	} else if !laundryPricingUnits[*product.PricingUnit] {
+		r.fail("pricing_unit", "must be item or kg")
+	}
+	if product.CategorySlug == "" {
+		product.CategorySlug = "laundry"
+	}
+	return product
+}
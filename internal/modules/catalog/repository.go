@@ -0,0 +1,125 @@
+package catalog
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+type Repository interface {
+	ListServices(ctx context.Context) ([]*models.ServiceNew, error)
+	ListAddons(ctx context.Context) ([]*models.Addon, error)
+	ListLaundryProducts(ctx context.Context) ([]*models.LaundryServiceProduct, error)
+
+	ServiceSlugs(ctx context.Context) (map[string]bool, error)
+	AddonSlugs(ctx context.Context) (map[string]bool, error)
+	LaundryProductKeys(ctx context.Context) (map[string]bool, error)
+	LaundryServiceSlugs(ctx context.Context) (map[string]bool, error)
+
+	UpsertServices(ctx context.Context, services []*models.ServiceNew, updateColumns []string) error
+	UpsertAddons(ctx context.Context, addons []*models.Addon, updateColumns []string) error
+	UpsertLaundryProducts(ctx context.Context, products []*models.LaundryServiceProduct, updateColumns []string) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) ListServices(ctx context.Context) ([]*models.ServiceNew, error) {
+	var services []*models.ServiceNew
+	err := r.db.WithContext(ctx).
+		Order("category_slug ASC, sort_order ASC, service_slug ASC").
+		Find(&services).Error
+	return services, err
+}
+
+func (r *repository) ListAddons(ctx context.Context) ([]*models.Addon, error) {
+	var addons []*models.Addon
+	err := r.db.WithContext(ctx).
+		Order("category_slug ASC, sort_order ASC, addon_slug ASC").
+		Find(&addons).Error
+	return addons, err
+}
+
+func (r *repository) ListLaundryProducts(ctx context.Context) ([]*models.LaundryServiceProduct, error) {
+	var products []*models.LaundryServiceProduct
+	err := r.db.WithContext(ctx).
+		Order("service_slug ASC, display_order ASC, slug ASC").
+		Find(&products).Error
+	return products, err
+}
+
+// ServiceSlugs includes soft-deleted services: importing one of those
+// restores it rather than colliding with its unique slug.
+func (r *repository) ServiceSlugs(ctx context.Context) (map[string]bool, error) {
+	var slugs []string
+	err := r.db.WithContext(ctx).Unscoped().Model(&models.ServiceNew{}).Pluck("service_slug", &slugs).Error
+	return toSet(slugs), err
+}
+
+func (r *repository) AddonSlugs(ctx context.Context) (map[string]bool, error) {
+	var slugs []string
+	err := r.db.WithContext(ctx).Unscoped().Model(&models.Addon{}).Pluck("addon_slug", &slugs).Error
+	return toSet(slugs), err
+}
+
+// LaundryProductKeys returns "service_slug/slug" for every product.
+func (r *repository) LaundryProductKeys(ctx context.Context) (map[string]bool, error) {
+	var keys []string
+	err := r.db.WithContext(ctx).Model(&models.LaundryServiceProduct{}).
+		Pluck("service_slug || '/' || slug", &keys).Error
+	return toSet(keys), err
+}
+
+func (r *repository) LaundryServiceSlugs(ctx context.Context) (map[string]bool, error) {
+	var slugs []string
+	err := r.db.WithContext(ctx).Model(&models.LaundryServiceCatalog{}).Pluck("slug", &slugs).Error
+	return toSet(slugs), err
+}
+
+// The upserts insert every column of new rows (Select("*") keeps false and
+// zero values from being replaced by column defaults) and, for existing
+// slugs, overwrite only updateColumns, i.e. the columns present in the
+// uploaded sheet.
+
+func (r *repository) UpsertServices(ctx context.Context, services []*models.ServiceNew, updateColumns []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.Select("*").Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "service_slug"}},
+			DoUpdates: clause.AssignmentColumns(append(updateColumns, "updated_at", "deleted_at")),
+		}).CreateInBatches(services, 200).Error
+	})
+}
+
+func (r *repository) UpsertAddons(ctx context.Context, addons []*models.Addon, updateColumns []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.Select("*").Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "addon_slug"}},
+			DoUpdates: clause.AssignmentColumns(append(updateColumns, "updated_at", "deleted_at")),
+		}).CreateInBatches(addons, 200).Error
+	})
+}
+
+func (r *repository) UpsertLaundryProducts(ctx context.Context, products []*models.LaundryServiceProduct, updateColumns []string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.Select("*").Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "service_slug"}, {Name: "slug"}},
+			DoUpdates: clause.AssignmentColumns(append(updateColumns, "updated_at")),
+		}).CreateInBatches(products, 200).Error
+	})
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
@@ -0,0 +1,16 @@
+package catalog
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/middleware"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
+	catalog := router.Group("/admin/catalog")
+	catalog.Use(authMiddleware)
+	catalog.Use(middleware.RequireAdmin())
+	{
+		catalog.GET("/:kind/export", handler.ExportCatalog)
+		catalog.POST("/:kind/import", handler.ImportCatalog)
+	}
+}
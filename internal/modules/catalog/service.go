@@ -0,0 +1,314 @@
+package catalog
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/audit"
+	"github.com/umar5678/go-backend/internal/modules/catalog/dto"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+	"github.com/umar5678/go-backend/internal/modules/laundry"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Service interface {
+	Export(ctx context.Context, kind, format string) (*dto.ExportFile, error)
+	// Import validates every row of the sheet and, unless dryRun is set or any
+	// row is invalid, upserts all rows by slug in a single transaction.
+	Import(ctx context.Context, adminID, kind, format string, data []byte, dryRun bool) (*dto.ImportResult, error)
+	SetAuditLogger(auditLogger audit.Recorder)
+}
+
+type service struct {
+	repo        Repository
+	auditLogger audit.Recorder
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) Export(ctx context.Context, kind, format string) (*dto.ExportFile, error) {
+	columns := columnsFor(kind)
+	if columns == nil {
+		return nil, response.NotFoundError("Catalog")
+	}
+
+	rows := [][]string{columns}
+	switch kind {
+	case KindServices:
+		services, err := s.repo.ListServices(ctx)
+		if err != nil {
+			return nil, response.InternalServerError("Failed to load services", err)
+		}
+		for _, svc := range services {
+			rows = append(rows, serviceRow(svc))
+		}
+	case KindAddons:
+		addons, err := s.repo.ListAddons(ctx)
+		if err != nil {
+			return nil, response.InternalServerError("Failed to load add-ons", err)
+		}
+		for _, addon := range addons {
+			rows = append(rows, addonRow(addon))
+		}
+	case KindLaundryProducts:
+		products, err := s.repo.ListLaundryProducts(ctx)
+		if err != nil {
+			return nil, response.InternalServerError("Failed to load laundry products", err)
+		}
+		for _, product := range products {
+			rows = append(rows, laundryProductRow(product))
+		}
+	}
+
+	data, err := encodeRows(format, kind, rows)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to render catalog export", err)
+	}
+
+	return &dto.ExportFile{
+		FileName:    fmt.Sprintf("%s-%s.%s", kind, time.Now().UTC().Format("20060102"), format),
+		ContentType: contentTypes[format],
+		Data:        data,
+	}, nil
+}
+
+func (s *service) Import(ctx context.Context, adminID, kind, format string, data []byte, dryRun bool) (*dto.ImportResult, error) {
+	columns := columnsFor(kind)
+	if columns == nil {
+		return nil, response.NotFoundError("Catalog")
+	}
+	if len(data) > maxImportBytes {
+		return nil, response.BadRequest(fmt.Sprintf("File is larger than %d MB", maxImportBytes>>20))
+	}
+
+	sheet, err := decodeRows(format, data)
+	if err != nil {
+		return nil, response.BadRequest(fmt.Sprintf("Could not read %s file: %v", format, err))
+	}
+	if len(sheet) == 0 {
+		return nil, response.BadRequest("File is empty")
+	}
+
+	result := &dto.ImportResult{Kind: kind, DryRun: dryRun, Errors: []dto.RowError{}}
+
+	header, headerErrors := checkHeader(kind, columns, sheet[0])
+	if len(headerErrors) > 0 {
+		result.Errors = headerErrors
+		return result, nil
+	}
+
+	var readers []*rowReader
+	for i, values := range sheet[1:] {
+		if isBlankRow(values) {
+			continue
+		}
+		if len(readers) == maxImportRows {
+			return nil, response.BadRequest(fmt.Sprintf("File has more than %d rows", maxImportRows))
+		}
+		readers = append(readers, newRowReader(i+2, header, values))
+	}
+	result.Rows = len(readers)
+
+	switch kind {
+	case KindServices:
+		err = s.importServices(ctx, result, header, readers)
+	case KindAddons:
+		err = s.importAddons(ctx, result, header, readers)
+	case KindLaundryProducts:
+		err = s.importLaundryProducts(ctx, result, header, readers)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Applied {
+		s.recordImport(ctx, adminID, result)
+		logger.Info("catalog imported",
+			"kind", kind,
+			"adminID", adminID,
+			"created", result.Created,
+			"updated", result.Updated,
+		)
+	}
+
+	return result, nil
+}
+
+func (s *service) importServices(ctx context.Context, result *dto.ImportResult, header []string, readers []*rowReader) error {
+	existing, err := s.repo.ServiceSlugs(ctx)
+	if err != nil {
+		return response.InternalServerError("Failed to load services", err)
+	}
+
+	services := make([]*models.ServiceNew, 0, len(readers))
+	seen := make(map[string]int, len(readers))
+	for _, r := range readers {
+		svc := parseServiceRow(r)
+		checkDuplicate(r, seen, "service_slug")
+		result.Errors = append(result.Errors, r.errors...)
+		countRow(result, existing[r.key])
+		services = append(services, svc)
+	}
+
+	if !shouldApply(result) {
+		return nil
+	}
+	if err := s.repo.UpsertServices(ctx, services, updateColumns(header, "service_slug")); err != nil {
+		return response.InternalServerError("Failed to import services", err)
+	}
+	result.Applied = true
+	shared.InvalidateCatalogCache(ctx)
+	return nil
+}
+
+func (s *service) importAddons(ctx context.Context, result *dto.ImportResult, header []string, readers []*rowReader) error {
+	existing, err := s.repo.AddonSlugs(ctx)
+	if err != nil {
+		return response.InternalServerError("Failed to load add-ons", err)
+	}
+
+	addons := make([]*models.Addon, 0, len(readers))
+	seen := make(map[string]int, len(readers))
+	for _, r := range readers {
+		addon := parseAddonRow(r)
+		checkDuplicate(r, seen, "addon_slug")
+		result.Errors = append(result.Errors, r.errors...)
+		countRow(result, existing[r.key])
+		addons = append(addons, addon)
+	}
+
+	if !shouldApply(result) {
+		return nil
+	}
+	if err := s.repo.UpsertAddons(ctx, addons, updateColumns(header, "addon_slug")); err != nil {
+		return response.InternalServerError("Failed to import add-ons", err)
+	}
+	result.Applied = true
+	shared.InvalidateCatalogCache(ctx)
+	return nil
+}
+
+func (s *service) importLaundryProducts(ctx context.Context, result *dto.ImportResult, header []string, readers []*rowReader) error {
+	existing, err := s.repo.LaundryProductKeys(ctx)
+	if err != nil {
+		return response.InternalServerError("Failed to load laundry products", err)
+	}
+	laundryServices, err := s.repo.LaundryServiceSlugs(ctx)
+	if err != nil {
+		return response.InternalServerError("Failed to load laundry services", err)
+	}
+
+	products := make([]*models.LaundryServiceProduct, 0, len(readers))
+	seen := make(map[string]int, len(readers))
+	for _, r := range readers {
+		product := parseLaundryProductRow(r)
+		if product.ServiceSlug != "" && !laundryServices[product.ServiceSlug] {
+			r.fail("service_slug", "unknown laundry service %q", product.ServiceSlug)
+		}
+		checkDuplicate(r, seen, "slug")
+		result.Errors = append(result.Errors, r.errors...)
+		countRow(result, existing[r.key])
+		products = append(products, product)
+	}
+
+	if !shouldApply(result) {
+		return nil
+	}
+	if err := s.repo.UpsertLaundryProducts(ctx, products, updateColumns(header, "service_slug", "slug")); err != nil {
+		return response.InternalServerError("Failed to import laundry products", err)
+	}
+	result.Applied = true
+	laundry.InvalidateCatalogCache(ctx)
+	return nil
+}
+
+// checkHeader normalises the header row and reports unknown, repeated and
+// missing required columns against row 1.
+func checkHeader(kind string, columns, raw []string) ([]string, []dto.RowError) {
+	known := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		known[column] = true
+	}
+
+	var errs []dto.RowError
+	header := make([]string, len(raw))
+	present := make(map[string]bool, len(raw))
+	for i, name := range raw {
+		column := strings.ToLower(strings.Join(strings.Fields(name), "_"))
+		header[i] = column
+		switch {
+		case column == "":
+			// Untitled columns (often trailing) are ignored.
+		case !known[column]:
+			errs = append(errs, dto.RowError{Row: 1, Column: name, Message: "unknown column"})
+		case present[column]:
+			errs = append(errs, dto.RowError{Row: 1, Column: column, Message: "column appears more than once"})
+		}
+		present[column] = true
+	}
+
+	for _, column := range requiredColumns[kind] {
+		if !present[column] {
+			errs = append(errs, dto.RowError{Row: 1, Column: column, Message: "required column is missing"})
+		}
+	}
+	return header, errs
+}
+
+func checkDuplicate(r *rowReader, seen map[string]int, column string) {
+	if r.key == "" {
+		return
+	}
+	if first, ok := seen[r.key]; ok {
+		r.fail(column, "duplicates row %d", first)
+		return
+	}
+	seen[r.key] = r.line
+}
+
+func countRow(result *dto.ImportResult, exists bool) {
+	if exists {
+		result.Updated++
+	} else {
+		result.Created++
+	}
+}
+
+// Imports are all-or-nothing: a single invalid row rejects the whole sheet so
+// the catalog never ends up half-updated.
+func shouldApply(result *dto.ImportResult) bool {
+	return !result.DryRun && len(result.Errors) == 0 && result.Rows > 0
+}
+
+// updateColumns lists the sheet's columns other than the upsert key, so
+// existing rows keep their current value for any column left out of the sheet.
+func updateColumns(header []string, keys ...string) []string {
+	skip := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		skip[key] = true
+	}
+
+	var columns []string
+	for _, column := range header {
+		if column != "" && !skip[column] {
+			skip[column] = true
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+func isBlankRow(values []string) bool {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,219 @@
+package catalog
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/umar5678/go-backend/internal/modules/catalog/dto"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+
+	maxImportBytes = 5 << 20
+	maxImportRows  = 5000
+
+	// listSeparator joins multi-value cells such as what's-included bullets.
+	listSeparator = "|"
+)
+
+var (
+	contentTypes = map[string]string{
+		FormatCSV:  "text/csv; charset=utf-8",
+		FormatXLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	}
+
+	slugPattern = regexp.MustCompile(`^[A-Za-z0-9]+(?:[-_][A-Za-z0-9]+)*$`)
+)
+
+func encodeRows(format, sheetName string, rows [][]string) ([]byte, error) {
+	if format == FormatXLSX {
+		return writeXLSX(sheetName, rows)
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeRows(format string, data []byte) ([][]string, error) {
+	if format == FormatXLSX {
+		return readXLSX(data)
+	}
+
+	// Excel prefixes UTF-8 CSV exports with a byte order mark.
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv: %w", err)
+	}
+	return rows, nil
+}
+
+// rowReader reads typed values from one sheet row by column name and collects
+// every problem it finds instead of stopping at the first.
+type rowReader struct {
+	line   int
+	key    string
+	values map[string]string
+	errors []dto.RowError
+}
+
+func newRowReader(line int, header, values []string) *rowReader {
+	r := &rowReader{line: line, values: make(map[string]string, len(header))}
+	for i, column := range header {
+		if i < len(values) {
+			r.values[column] = strings.TrimSpace(values[i])
+		}
+	}
+	return r
+}
+
+func (r *rowReader) fail(column, format string, args ...interface{}) {
+	r.errors = append(r.errors, dto.RowError{
+		Row:     r.line,
+		Key:     r.key,
+		Column:  column,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (r *rowReader) text(column string, required bool, maxLen int) string {
+	value := r.values[column]
+	if value == "" && required {
+		r.fail(column, "is required")
+	}
+	if maxLen > 0 && len(value) > maxLen {
+		r.fail(column, "must be at most %d characters", maxLen)
+	}
+	return value
+}
+
+func (r *rowReader) slug(column string, required bool) string {
+	value := r.text(column, required, 100)
+	if value != "" && !slugPattern.MatchString(value) {
+		r.fail(column, "%q is not a valid slug (letters, digits, - and _)", value)
+	}
+	return value
+}
+
+func (r *rowReader) optionalText(column string, maxLen int) *string {
+	value := r.text(column, false, maxLen)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+func (r *rowReader) list(column string) []string {
+	raw := r.values[column]
+	if raw == "" {
+		return []string{}
+	}
+	var items []string
+	for _, item := range strings.Split(raw, listSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (r *rowReader) optionalFloat(column string) *float64 {
+	raw := r.values[column]
+	if raw == "" {
+		return nil
+	}
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		r.fail(column, "%q is not a number", raw)
+		return nil
+	}
+	if value < 0 {
+		r.fail(column, "must not be negative")
+	}
+	return &value
+}
+
+func (r *rowReader) float(column string, required bool) float64 {
+	value := r.optionalFloat(column)
+	if value == nil {
+		if required && r.values[column] == "" {
+			r.fail(column, "is required")
+		}
+		return 0
+	}
+	return *value
+}
+
+func (r *rowReader) optionalInt(column string) *int {
+	raw := r.values[column]
+	if raw == "" {
+		return nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		r.fail(column, "%q is not a whole number", raw)
+		return nil
+	}
+	if value < 0 {
+		r.fail(column, "must not be negative")
+	}
+	return &value
+}
+
+func (r *rowReader) int(column string) int {
+	if value := r.optionalInt(column); value != nil {
+		return *value
+	}
+	return 0
+}
+
+func (r *rowReader) bool(column string, fallback bool) bool {
+	raw := strings.ToLower(r.values[column])
+	switch raw {
+	case "":
+		return fallback
+	case "true", "yes", "y", "1":
+		return true
+	case "false", "no", "n", "0":
+		return false
+	}
+	r.fail(column, "%q is not a yes/no value", r.values[column])
+	return fallback
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+func formatOptionalFloat(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return formatFloat(*value)
+}
+
+func formatOptionalInt(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
+
+func formatOptionalText(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,187 @@
+package cities
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/utils/location"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+const (
+	activeCitiesCacheKey = "cities:active"
+	activeCitiesCacheTTL = 10 * time.Minute
+)
+
+// FareConfig is how a vehicle type is priced at a pickup point. City is nil
+// while no city is active, and VehicleType then carries its own rates.
+type FareConfig struct {
+	City               *models.City
+	VehicleType        *models.VehicleType
+	MaxSurgeMultiplier float64
+}
+
+// CapSurge holds a surge multiplier to the city's cap, if it has one.
+func (f *FareConfig) CapSurge(multiplier float64) float64 {
+	if f.MaxSurgeMultiplier > 0 && multiplier > f.MaxSurgeMultiplier {
+		return f.MaxSurgeMultiplier
+	}
+	return multiplier
+}
+
+// ResolveServiceArea finds the city a location falls in. While no city is
+// active every location is served and the city is nil. Bookings are never
+// blocked on the city store; if it cannot be read the location is served.
+func (s *service) ResolveServiceArea(ctx context.Context, lat, lon float64) (*models.City, error) {
+	cities, err := s.activeCities(ctx)
+	if err != nil {
+		logger.Error("failed to load cities", "error", err)
+		return nil, nil
+	}
+	if len(cities) == 0 {
+		return nil, nil
+	}
+
+	if city := cityAt(cities, lat, lon); city != nil {
+		return city, nil
+	}
+
+	nearest, distance := nearestCity(cities, lat, lon)
+	return nil, response.BadRequest("This location is outside our service area",
+		response.NewErrorDetail(outsideMessage(nearest, distance), "OUTSIDE_SERVICE_AREA"))
+}
+
+// MatchingRadii returns the driver search radii of the city a pickup falls
+// in, or nil to use the defaults.
+func (s *service) MatchingRadii(ctx context.Context, lat, lon float64) []float64 {
+	cities, err := s.activeCities(ctx)
+	if err != nil {
+		logger.Error("failed to load cities", "error", err)
+		return nil
+	}
+	if city := cityAt(cities, lat, lon); city != nil {
+		return city.MatchingRadiiKm
+	}
+	return nil
+}
+
+// DispatchSettings returns how the city a pickup falls in offers rides of a
+// vehicle type to drivers. Outside every city nothing is overridden.
+func (s *service) DispatchSettings(ctx context.Context, lat, lon float64, vehicleTypeID string) models.DispatchSettings {
+	cities, err := s.activeCities(ctx)
+	if err != nil {
+		logger.Error("failed to load cities", "error", err)
+		return models.DispatchSettings{}
+	}
+	if city := cityAt(cities, lat, lon); city != nil {
+		return city.Dispatch(vehicleTypeID)
+	}
+	return models.DispatchSettings{}
+}
+
+// FareConfig applies the pickup city's rates to a vehicle type. It fails when
+// the pickup is outside every city or the vehicle type is not offered there.
+func (s *service) FareConfig(ctx context.Context, lat, lon float64, vehicleType *models.VehicleType) (*FareConfig, error) {
+	city, err := s.ResolveServiceArea(ctx, lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	if city == nil {
+		return &FareConfig{VehicleType: vehicleType}, nil
+	}
+
+	priced := *vehicleType
+	priced.BaseFare = roundRate(vehicleType.BaseFare * city.FareMultiplier)
+	priced.PerKmRate = roundRate(vehicleType.PerKmRate * city.FareMultiplier)
+	priced.PerMinuteRate = roundRate(vehicleType.PerMinuteRate * city.FareMultiplier)
+	priced.BookingFee = roundRate(vehicleType.BookingFee * city.FareMultiplier)
+
+	config := &FareConfig{City: city, VehicleType: &priced, MaxSurgeMultiplier: city.MaxSurgeMultiplier}
+
+	override, offered := city.OffersVehicleType(vehicleType.ID)
+	if !offered {
+		return nil, vehicleTypeUnavailable(vehicleType, city)
+	}
+	if override == nil {
+		return config, nil
+	}
+	if override.BaseFare != nil {
+		priced.BaseFare = *override.BaseFare
+	}
+	if override.PerKmRate != nil {
+		priced.PerKmRate = *override.PerKmRate
+	}
+	if override.PerMinuteRate != nil {
+		priced.PerMinuteRate = *override.PerMinuteRate
+	}
+	if override.BookingFee != nil {
+		priced.BookingFee = *override.BookingFee
+	}
+	if override.MaxSurgeMultiplier != nil {
+		config.MaxSurgeMultiplier = *override.MaxSurgeMultiplier
+	}
+
+	return config, nil
+}
+
+func (s *service) activeCities(ctx context.Context) ([]*models.City, error) {
+	var cities []*models.City
+	if err := cache.GetJSON(ctx, activeCitiesCacheKey, &cities); err == nil {
+		return cities, nil
+	}
+
+	cities, err := s.repo.ListCities(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	cache.SetJSON(ctx, activeCitiesCacheKey, cities, activeCitiesCacheTTL)
+	return cities, nil
+}
+
+func (s *service) invalidateCities(ctx context.Context) {
+	cache.Delete(ctx, activeCitiesCacheKey)
+}
+
+// cityAt returns the first city, by name, whose boundary holds the point.
+// Boundaries are not expected to overlap.
+func cityAt(cities []*models.City, lat, lon float64) *models.City {
+	for _, city := range cities {
+		if city.Contains(lat, lon) {
+			return city
+		}
+	}
+	return nil
+}
+
+// nearestCity measures to each city's closest boundary point, which is close
+// enough to tell someone how far they are from service.
+func nearestCity(cities []*models.City, lat, lon float64) (*models.City, float64) {
+	var nearest *models.City
+	best := math.MaxFloat64
+	for _, city := range cities {
+		for _, p := range city.Boundary {
+			if d := location.HaversineDistance(lat, lon, p.Latitude, p.Longitude); d < best {
+				best, nearest = d, city
+			}
+		}
+	}
+	return nearest, math.Round(best*10) / 10
+}
+
+func outsideMessage(nearest *models.City, distanceKm float64) string {
+	if nearest == nil {
+		return "We don't operate here yet"
+	}
+	return fmt.Sprintf("We don't operate here yet. The nearest city we serve is %s, about %s km away",
+		nearest.Name, strings.TrimSuffix(fmt.Sprintf("%.1f", distanceKm), ".0"))
+}
+
+func roundRate(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,110 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/utils/location"
+)
+
+const (
+	maxBoundaryPoints = 500
+	maxMatchingRadii  = 5
+	maxMatchingRadius = 50
+	maxDispatchBatch  = 20
+)
+
+type ServiceAreaRequest struct {
+	Lat float64 `form:"lat" binding:"required,latitude"`
+	Lon float64 `form:"lon" binding:"required,longitude"`
+}
+
+type CreateCityRequest struct {
+	Code                 string              `json:"code" binding:"required,max=50"`
+	Name                 string              `json:"name" binding:"required,max=255"`
+	Timezone             string              `json:"timezone" binding:"omitempty,max=64"`
+	Boundary             models.CityBoundary `json:"boundary" binding:"required"`
+	FareMultiplier       *float64            `json:"fareMultiplier"`
+	MaxSurgeMultiplier   float64             `json:"maxSurgeMultiplier"`
+	MatchingRadiiKm      []float64           `json:"matchingRadiiKm"`
+	DispatchMode         *string             `json:"dispatchMode" binding:"omitempty,oneof=sequential batch broadcast"`
+	DispatchBatchSize    *int                `json:"dispatchBatchSize"`
+	RestrictVehicleTypes bool                `json:"restrictVehicleTypes"`
+	IsActive             *bool               `json:"isActive"`
+}
+
+type UpdateCityRequest struct {
+	Code                 *string              `json:"code" binding:"omitempty,max=50"`
+	Name                 *string              `json:"name" binding:"omitempty,max=255"`
+	Timezone             *string              `json:"timezone" binding:"omitempty,max=64"`
+	Boundary             *models.CityBoundary `json:"boundary"`
+	FareMultiplier       *float64             `json:"fareMultiplier"`
+	MaxSurgeMultiplier   *float64             `json:"maxSurgeMultiplier"`
+	MatchingRadiiKm      *[]float64           `json:"matchingRadiiKm"`
+	DispatchMode         *string              `json:"dispatchMode" binding:"omitempty,oneof=sequential batch broadcast"`
+	DispatchBatchSize    *int                 `json:"dispatchBatchSize"`
+	RestrictVehicleTypes *bool                `json:"restrictVehicleTypes"`
+	IsActive             *bool                `json:"isActive"`
+}
+
+// SetCityVehicleTypeRequest replaces a vehicle type's configuration in a city.
+// Rates and capacities left out fall back to the vehicle type's own, and a
+// missing offer timeout to the platform's.
+type SetCityVehicleTypeRequest struct {
+	IsAvailable        *bool    `json:"isAvailable"`
+	BaseFare           *float64 `json:"baseFare" binding:"omitempty,min=0"`
+	PerKmRate          *float64 `json:"perKmRate" binding:"omitempty,min=0"`
+	PerMinuteRate      *float64 `json:"perMinuteRate" binding:"omitempty,min=0"`
+	BookingFee         *float64 `json:"bookingFee" binding:"omitempty,min=0"`
+	MaxSurgeMultiplier *float64 `json:"maxSurgeMultiplier" binding:"omitempty,min=1"`
+	OfferTimeoutSec    *int     `json:"offerTimeoutSec" binding:"omitempty,min=5,max=120"`
+	SeatCapacity       *int     `json:"seatCapacity" binding:"omitempty,min=1,max=20"`
+	LuggageCapacity    *int     `json:"luggageCapacity" binding:"omitempty,min=0,max=20"`
+}
+
+// ValidateCity checks a city as it will be saved, after create or update
+// fields have been applied.
+func ValidateCity(city *models.City) error {
+	if len(city.Boundary) < 3 {
+		return errors.New("boundary needs at least 3 points")
+	}
+	if len(city.Boundary) > maxBoundaryPoints {
+		return fmt.Errorf("boundary cannot have more than %d points", maxBoundaryPoints)
+	}
+	for i, p := range city.Boundary {
+		if err := location.ValidateCoordinates(p.Latitude, p.Longitude); err != nil {
+			return fmt.Errorf("boundary[%d]: %v", i, err)
+		}
+	}
+
+	if city.Timezone != "" {
+		if _, err := time.LoadLocation(city.Timezone); err != nil {
+			return fmt.Errorf("unknown timezone '%s'", city.Timezone)
+		}
+	}
+	if city.FareMultiplier <= 0 || city.FareMultiplier > 10 {
+		return errors.New("fareMultiplier must be above 0 and at most 10")
+	}
+	if city.MaxSurgeMultiplier != 0 && city.MaxSurgeMultiplier < 1 {
+		return errors.New("maxSurgeMultiplier must be at least 1, or 0 for no cap")
+	}
+
+	if len(city.MatchingRadiiKm) > maxMatchingRadii {
+		return fmt.Errorf("matchingRadiiKm cannot have more than %d entries", maxMatchingRadii)
+	}
+	for i, radius := range city.MatchingRadiiKm {
+		if radius <= 0 || radius > maxMatchingRadius {
+			return fmt.Errorf("matchingRadiiKm[%d] must be above 0 and at most %d km", i, maxMatchingRadius)
+		}
+		if i > 0 && radius <= city.MatchingRadiiKm[i-1] {
+			return errors.New("matchingRadiiKm must be in increasing order")
+		}
+	}
+
+	if city.DispatchBatchSize != nil && (*city.DispatchBatchSize < 1 || *city.DispatchBatchSize > maxDispatchBatch) {
+		return fmt.Errorf("dispatchBatchSize must be between 1 and %d", maxDispatchBatch)
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/utils/location"
+)
+
+// CitySummaryResponse is what riders, drivers and providers see of a city.
+type CitySummaryResponse struct {
+	ID       string              `json:"id"`
+	Code     string              `json:"code"`
+	Name     string              `json:"name"`
+	Timezone string              `json:"timezone,omitempty"`
+	Center   location.Point      `json:"center"`
+	Boundary models.CityBoundary `json:"boundary"`
+}
+
+type CityVehicleTypeResponse struct {
+	VehicleTypeID      string    `json:"vehicleTypeId"`
+	VehicleTypeName    string    `json:"vehicleTypeName,omitempty"`
+	IsAvailable        bool      `json:"isAvailable"`
+	BaseFare           *float64  `json:"baseFare,omitempty"`
+	PerKmRate          *float64  `json:"perKmRate,omitempty"`
+	PerMinuteRate      *float64  `json:"perMinuteRate,omitempty"`
+	BookingFee         *float64  `json:"bookingFee,omitempty"`
+	MaxSurgeMultiplier *float64  `json:"maxSurgeMultiplier,omitempty"`
+	OfferTimeoutSec    *int      `json:"offerTimeoutSec,omitempty"`
+	SeatCapacity       *int      `json:"seatCapacity,omitempty"`
+	LuggageCapacity    *int      `json:"luggageCapacity,omitempty"`
+	UpdatedAt          time.Time `json:"updatedAt"`
+}
+
+type CityResponse struct {
+	ID                   string                    `json:"id"`
+	Code                 string                    `json:"code"`
+	Name                 string                    `json:"name"`
+	Timezone             string                    `json:"timezone,omitempty"`
+	Center               location.Point            `json:"center"`
+	Boundary             models.CityBoundary       `json:"boundary"`
+	FareMultiplier       float64                   `json:"fareMultiplier"`
+	MaxSurgeMultiplier   float64                   `json:"maxSurgeMultiplier"`
+	MatchingRadiiKm      []float64                 `json:"matchingRadiiKm"`
+	DispatchMode         *string                   `json:"dispatchMode,omitempty"`
+	DispatchBatchSize    *int                      `json:"dispatchBatchSize,omitempty"`
+	RestrictVehicleTypes bool                      `json:"restrictVehicleTypes"`
+	VehicleTypes         []CityVehicleTypeResponse `json:"vehicleTypes"`
+	IsActive             bool                      `json:"isActive"`
+	CreatedAt            time.Time                 `json:"createdAt"`
+	UpdatedAt            time.Time                 `json:"updatedAt"`
+}
+
+// ServiceAreaResponse says whether a location is served. Outside every city,
+// NearestCity and DistanceKm point to the closest one.
+type ServiceAreaResponse struct {
+	Serviceable bool                 `json:"serviceable"`
+	City        *CitySummaryResponse `json:"city,omitempty"`
+	NearestCity *CitySummaryResponse `json:"nearestCity,omitempty"`
+	DistanceKm  *float64             `json:"distanceKm,omitempty"`
+	Message     string               `json:"message"`
+}
+
+func ToCitySummaryResponse(city *models.City) *CitySummaryResponse {
+	return &CitySummaryResponse{
+		ID:       city.ID,
+		Code:     city.Code,
+		Name:     city.Name,
+		Timezone: city.Timezone,
+		Center:   location.PolygonCentroid(city.Boundary),
+		Boundary: city.Boundary,
+	}
+}
+
+func ToCitySummaryResponses(cities []*models.City) []*CitySummaryResponse {
+	result := make([]*CitySummaryResponse, 0, len(cities))
+	for _, city := range cities {
+		result = append(result, ToCitySummaryResponse(city))
+	}
+	return result
+}
+
+func ToCityVehicleTypeResponse(config *models.CityVehicleType) CityVehicleTypeResponse {
+	resp := CityVehicleTypeResponse{
+		VehicleTypeID:      config.VehicleTypeID,
+		IsAvailable:        config.IsAvailable,
+		BaseFare:           config.BaseFare,
+		PerKmRate:          config.PerKmRate,
+		PerMinuteRate:      config.PerMinuteRate,
+		BookingFee:         config.BookingFee,
+		MaxSurgeMultiplier: config.MaxSurgeMultiplier,
+		OfferTimeoutSec:    config.OfferTimeoutSec,
+		SeatCapacity:       config.SeatCapacity,
+		LuggageCapacity:    config.LuggageCapacity,
+		UpdatedAt:          config.UpdatedAt,
+	}
+	if config.VehicleType != nil {
+		resp.VehicleTypeName = config.VehicleType.Name
+	}
+	return resp
+}
+
+func ToCityResponse(city *models.City) *CityResponse {
+	radii := []float64(city.MatchingRadiiKm)
+	if radii == nil {
+		radii = []float64{}
+	}
+
+	resp := &CityResponse{
+		ID:                   city.ID,
+		Code:                 city.Code,
+		Name:                 city.Name,
+		Timezone:             city.Timezone,
+		Center:               location.PolygonCentroid(city.Boundary),
+		Boundary:             city.Boundary,
+		FareMultiplier:       city.FareMultiplier,
+		MaxSurgeMultiplier:   city.MaxSurgeMultiplier,
+		MatchingRadiiKm:      radii,
+		DispatchMode:         city.DispatchMode,
+		DispatchBatchSize:    city.DispatchBatchSize,
+		RestrictVehicleTypes: city.RestrictVehicleTypes,
+		VehicleTypes:         make([]CityVehicleTypeResponse, 0, len(city.VehicleTypes)),
+		IsActive:             city.IsActive,
+		CreatedAt:            city.CreatedAt,
+		UpdatedAt:            city.UpdatedAt,
+	}
+	for i := range city.VehicleTypes {
+		resp.VehicleTypes = append(resp.VehicleTypes, ToCityVehicleTypeResponse(&city.VehicleTypes[i]))
+	}
+	return resp
+}
+
+func ToCityResponses(cities []*models.City) []*CityResponse {
+	result := make([]*CityResponse, 0, len(cities))
+	for _, city := range cities {
+		result = append(result, ToCityResponse(city))
+	}
+	return result
+}
+
+// OfferedVehicleTypeResponse is a vehicle type riders can book at a location
+// and what it carries there.
+type OfferedVehicleTypeResponse struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	DisplayName     string `json:"displayName"`
+	Description     string `json:"description,omitempty"`
+	IconURL         string `json:"iconUrl,omitempty"`
+	SeatCapacity    int    `json:"seatCapacity"`
+	LuggageCapacity int    `json:"luggageCapacity"`
+}
+
+func ToOfferedVehicleTypeResponse(vehicleType *models.VehicleType, seats, luggage int) *OfferedVehicleTypeResponse {
+	return &OfferedVehicleTypeResponse{
+		ID:              vehicleType.ID,
+		Name:            vehicleType.Name,
+		DisplayName:     vehicleType.DisplayName,
+		Description:     vehicleType.Description,
+		IconURL:         vehicleType.IconURL,
+		SeatCapacity:    seats,
+		LuggageCapacity: luggage,
+	}
+}
+
+// VehicleTypeMatrixResponse shows every city against every vehicle type.
+type VehicleTypeMatrixResponse struct {
+	VehicleTypes []VehicleTypeColumn `json:"vehicleTypes"`
+	Cities       []VehicleTypeRow    `json:"cities"`
+}
+
+// VehicleTypeColumn is a vehicle type with its platform-wide settings.
+type VehicleTypeColumn struct {
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	DisplayName     string `json:"displayName"`
+	IsActive        bool   `json:"isActive"`
+	SeatCapacity    int    `json:"seatCapacity"`
+	LuggageCapacity int    `json:"luggageCapacity"`
+}
+
+// VehicleTypeRow is one city's vehicle types, in the order of the matrix's
+// columns.
+type VehicleTypeRow struct {
+	CityID               string            `json:"cityId"`
+	Code                 string            `json:"code"`
+	Name                 string            `json:"name"`
+	IsActive             bool              `json:"isActive"`
+	RestrictVehicleTypes bool              `json:"restrictVehicleTypes"`
+	VehicleTypes         []VehicleTypeCell `json:"vehicleTypes"`
+}
+
+// VehicleTypeCell says whether a vehicle type can be booked in a city and
+// what it carries there. Configured is set when the city has its own entry
+// for the type rather than inheriting the defaults.
+type VehicleTypeCell struct {
+	VehicleTypeID   string `json:"vehicleTypeId"`
+	Offered         bool   `json:"offered"`
+	Configured      bool   `json:"configured"`
+	SeatCapacity    int    `json:"seatCapacity"`
+	LuggageCapacity int    `json:"luggageCapacity"`
+}
+
+func ToVehicleTypeColumn(vehicleType *models.VehicleType) VehicleTypeColumn {
+	return VehicleTypeColumn{
+		ID:              vehicleType.ID,
+		Name:            vehicleType.Name,
+		DisplayName:     vehicleType.DisplayName,
+		IsActive:        vehicleType.IsActive,
+		SeatCapacity:    vehicleType.Capacity,
+		LuggageCapacity: vehicleType.LuggageCapacity,
+	}
+}
@@ -0,0 +1,251 @@
+package cities
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/modules/cities/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// ListActiveCities godoc
+// @Summary List the cities we operate in
+// @Description Active cities with the boundary of their service area
+// @Tags cities
+// @Produce json
+// @Success 200 {object} response.Response{data=[]dto.CitySummaryResponse}
+// @Router /cities [get]
+func (h *Handler) ListActiveCities(c *gin.Context) {
+	cities, err := h.service.ListActiveCities(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, cities, "Cities retrieved successfully")
+}
+
+// CheckServiceArea godoc
+// @Summary Check whether a location is served
+// @Description Outside every city the nearest one and its distance are returned
+// @Tags cities
+// @Produce json
+// @Param lat query number true "Latitude"
+// @Param lon query number true "Longitude"
+// @Success 200 {object} response.Response{data=dto.ServiceAreaResponse}
+// @Router /cities/service-area [get]
+func (h *Handler) CheckServiceArea(c *gin.Context) {
+	var req dto.ServiceAreaRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+
+	area, err := h.service.CheckServiceArea(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, area, "Service area checked successfully")
+}
+
+// ListVehicleTypesAt godoc
+// @Summary List the vehicle types offered at a location
+// @Description Active vehicle types riders can book at the location, with the seats and bags each takes there
+// @Tags cities
+// @Produce json
+// @Param lat query number true "Latitude"
+// @Param lon query number true "Longitude"
+// @Success 200 {object} response.Response{data=[]dto.OfferedVehicleTypeResponse}
+// @Router /cities/vehicle-types [get]
+func (h *Handler) ListVehicleTypesAt(c *gin.Context) {
+	var req dto.ServiceAreaRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+
+	vehicleTypes, err := h.service.ListVehicleTypesAt(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, vehicleTypes, "Vehicle types retrieved successfully")
+}
+
+// ListCities godoc
+// @Summary List cities
+// @Tags cities - admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=[]dto.CityResponse}
+// @Router /cities/admin [get]
+func (h *Handler) ListCities(c *gin.Context) {
+	cities, err := h.service.ListCities(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, cities, "Cities retrieved successfully")
+}
+
+// GetVehicleTypeMatrix godoc
+// @Summary Vehicle types by city
+// @Description Every city against every vehicle type: whether riders can book it there and the seats and bags it takes
+// @Tags cities - admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=dto.VehicleTypeMatrixResponse}
+// @Router /cities/admin/vehicle-types [get]
+func (h *Handler) GetVehicleTypeMatrix(c *gin.Context) {
+	matrix, err := h.service.GetVehicleTypeMatrix(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, matrix, "Vehicle type matrix retrieved successfully")
+}
+
+// GetCity godoc
+// @Summary Get a city
+// @Tags cities - admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "City ID"
+// @Success 200 {object} response.Response{data=dto.CityResponse}
+// @Router /cities/admin/{id} [get]
+func (h *Handler) GetCity(c *gin.Context) {
+	city, err := h.service.GetCity(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, city, "City retrieved successfully")
+}
+
+// CreateCity godoc
+// @Summary Create a city
+// @Description The boundary is a ring of at least 3 points; rides and orders are only taken inside an active city once one exists
+// @Tags cities - admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateCityRequest true "City details"
+// @Success 200 {object} response.Response{data=dto.CityResponse}
+// @Router /cities/admin [post]
+func (h *Handler) CreateCity(c *gin.Context) {
+	var req dto.CreateCityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	city, err := h.service.CreateCity(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, city, "City created successfully")
+}
+
+// UpdateCity godoc
+// @Summary Update a city
+// @Tags cities - admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "City ID"
+// @Param request body dto.UpdateCityRequest true "Fields to update"
+// @Success 200 {object} response.Response{data=dto.CityResponse}
+// @Router /cities/admin/{id} [put]
+func (h *Handler) UpdateCity(c *gin.Context) {
+	var req dto.UpdateCityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	city, err := h.service.UpdateCity(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, city, "City updated successfully")
+}
+
+// DeleteCity godoc
+// @Summary Delete a city
+// @Tags cities - admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "City ID"
+// @Success 200 {object} response.Response
+// @Router /cities/admin/{id} [delete]
+func (h *Handler) DeleteCity(c *gin.Context) {
+	if err := h.service.DeleteCity(c.Request.Context(), c.Param("id")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, nil, "City deleted successfully")
+}
+
+// SetCityVehicleType godoc
+// @Summary Configure a vehicle type in a city
+// @Description Rates given here replace the vehicle type's own in the city; rates left out are the vehicle type's scaled by the city's fare multiplier. Seat and luggage capacities left out are the vehicle type's own
+// @Tags cities - admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "City ID"
+// @Param vehicleTypeId path string true "Vehicle type ID"
+// @Param request body dto.SetCityVehicleTypeRequest true "Vehicle type configuration"
+// @Success 200 {object} response.Response{data=dto.CityVehicleTypeResponse}
+// @Router /cities/admin/{id}/vehicle-types/{vehicleTypeId} [put]
+func (h *Handler) SetCityVehicleType(c *gin.Context) {
+	var req dto.SetCityVehicleTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	config, err := h.service.SetCityVehicleType(c.Request.Context(), c.Param("id"), c.Param("vehicleTypeId"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, config, "City vehicle type saved successfully")
+}
+
+// RemoveCityVehicleType godoc
+// @Summary Remove a vehicle type's city configuration
+// @Description In a city that restricts vehicle types this stops the type being offered there
+// @Tags cities - admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "City ID"
+// @Param vehicleTypeId path string true "Vehicle type ID"
+// @Success 200 {object} response.Response
+// @Router /cities/admin/{id}/vehicle-types/{vehicleTypeId} [delete]
+func (h *Handler) RemoveCityVehicleType(c *gin.Context) {
+	if err := h.service.RemoveCityVehicleType(c.Request.Context(), c.Param("id"), c.Param("vehicleTypeId")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, nil, "City vehicle type removed successfully")
+}
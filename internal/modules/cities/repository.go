@@ -0,0 +1,98 @@
+package cities
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository interface {
+	ListCities(ctx context.Context, activeOnly bool) ([]*models.City, error)
+	GetCity(ctx context.Context, id string) (*models.City, error)
+	CreateCity(ctx context.Context, city *models.City) error
+	UpdateCity(ctx context.Context, city *models.City) error
+	DeleteCity(ctx context.Context, id string) error
+	CityCodeExists(ctx context.Context, code, excludeID string) (bool, error)
+
+	GetCityVehicleType(ctx context.Context, cityID, vehicleTypeID string) (*models.CityVehicleType, error)
+	UpsertCityVehicleType(ctx context.Context, config *models.CityVehicleType) error
+	DeleteCityVehicleType(ctx context.Context, cityID, vehicleTypeID string) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) ListCities(ctx context.Context, activeOnly bool) ([]*models.City, error) {
+	var cities []*models.City
+
+	db := r.db.WithContext(ctx).Preload("VehicleTypes.VehicleType")
+	if activeOnly {
+		db = db.Where("is_active = ?", true)
+	}
+
+	err := db.Order("name ASC").Find(&cities).Error
+	return cities, err
+}
+
+func (r *repository) GetCity(ctx context.Context, id string) (*models.City, error) {
+	var city models.City
+	err := r.db.WithContext(ctx).
+		Preload("VehicleTypes.VehicleType").
+		Where("id = ?", id).
+		First(&city).Error
+	return &city, err
+}
+
+func (r *repository) CreateCity(ctx context.Context, city *models.City) error {
+	return r.db.WithContext(ctx).Omit("VehicleTypes").Create(city).Error
+}
+
+func (r *repository) UpdateCity(ctx context.Context, city *models.City) error {
+	return r.db.WithContext(ctx).Omit("VehicleTypes").Save(city).Error
+}
+
+func (r *repository) DeleteCity(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.City{}).Error
+}
+
+func (r *repository) CityCodeExists(ctx context.Context, code, excludeID string) (bool, error) {
+	var count int64
+	db := r.db.WithContext(ctx).Model(&models.City{}).Where("code = ?", code)
+	if excludeID != "" {
+		db = db.Where("id <> ?", excludeID)
+	}
+	err := db.Count(&count).Error
+	return count > 0, err
+}
+
+func (r *repository) GetCityVehicleType(ctx context.Context, cityID, vehicleTypeID string) (*models.CityVehicleType, error) {
+	var config models.CityVehicleType
+	err := r.db.WithContext(ctx).
+		Preload("VehicleType").
+		Where("city_id = ? AND vehicle_type_id = ?", cityID, vehicleTypeID).
+		First(&config).Error
+	return &config, err
+}
+
+func (r *repository) UpsertCityVehicleType(ctx context.Context, config *models.CityVehicleType) error {
+	return r.db.WithContext(ctx).Omit("VehicleType").Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "city_id"}, {Name: "vehicle_type_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"is_available", "base_fare", "per_km_rate", "per_minute_rate", "booking_fee",
+			"max_surge_multiplier", "offer_timeout_sec", "seat_capacity", "luggage_capacity", "updated_at",
+		}),
+	}).Create(config).Error
+}
+
+func (r *repository) DeleteCityVehicleType(ctx context.Context, cityID, vehicleTypeID string) error {
+	return r.db.WithContext(ctx).
+		Where("city_id = ? AND vehicle_type_id = ?", cityID, vehicleTypeID).
+		Delete(&models.CityVehicleType{}).Error
+}
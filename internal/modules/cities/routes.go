@@ -0,0 +1,28 @@
+package cities
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/middleware"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
+	cities := router.Group("/cities")
+	{
+		cities.GET("", handler.ListActiveCities)
+		cities.GET("/service-area", handler.CheckServiceArea)
+		cities.GET("/vehicle-types", handler.ListVehicleTypesAt)
+	}
+
+	admin := cities.Group("/admin", authMiddleware, middleware.RequireAdmin())
+	{
+		admin.GET("", handler.ListCities)
+		admin.GET("/vehicle-types", handler.GetVehicleTypeMatrix)
+		admin.POST("", handler.CreateCity)
+		admin.GET("/:id", handler.GetCity)
+		admin.PUT("/:id", handler.UpdateCity)
+		admin.DELETE("/:id", handler.DeleteCity)
+
+		admin.PUT("/:id/vehicle-types/:vehicleTypeId", handler.SetCityVehicleType)
+		admin.DELETE("/:id/vehicle-types/:vehicleTypeId", handler.RemoveCityVehicleType)
+	}
+}
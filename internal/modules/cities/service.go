@@ -0,0 +1,304 @@
+package cities
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/cities/dto"
+	vehiclesrepo "github.com/umar5678/go-backend/internal/modules/vehicles"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	ResolveServiceArea(ctx context.Context, lat, lon float64) (*models.City, error)
+	MatchingRadii(ctx context.Context, lat, lon float64) []float64
+	DispatchSettings(ctx context.Context, lat, lon float64, vehicleTypeID string) models.DispatchSettings
+	FareConfig(ctx context.Context, lat, lon float64, vehicleType *models.VehicleType) (*FareConfig, error)
+	VehicleCapacity(ctx context.Context, lat, lon float64, vehicleTypeID string) (seats, luggage int, err error)
+
+	ListActiveCities(ctx context.Context) ([]*dto.CitySummaryResponse, error)
+	CheckServiceArea(ctx context.Context, req dto.ServiceAreaRequest) (*dto.ServiceAreaResponse, error)
+	ListVehicleTypesAt(ctx context.Context, req dto.ServiceAreaRequest) ([]*dto.OfferedVehicleTypeResponse, error)
+
+	ListCities(ctx context.Context) ([]*dto.CityResponse, error)
+	GetCity(ctx context.Context, id string) (*dto.CityResponse, error)
+	CreateCity(ctx context.Context, req dto.CreateCityRequest) (*dto.CityResponse, error)
+	UpdateCity(ctx context.Context, id string, req dto.UpdateCityRequest) (*dto.CityResponse, error)
+	DeleteCity(ctx context.Context, id string) error
+	SetCityVehicleType(ctx context.Context, cityID, vehicleTypeID string, req dto.SetCityVehicleTypeRequest) (*dto.CityVehicleTypeResponse, error)
+	RemoveCityVehicleType(ctx context.Context, cityID, vehicleTypeID string) error
+	GetVehicleTypeMatrix(ctx context.Context) (*dto.VehicleTypeMatrixResponse, error)
+}
+
+type service struct {
+	repo         Repository
+	vehiclesRepo vehiclesrepo.Repository
+}
+
+func NewService(repo Repository, vehiclesRepo vehiclesrepo.Repository) Service {
+	return &service{repo: repo, vehiclesRepo: vehiclesRepo}
+}
+
+func (s *service) ListActiveCities(ctx context.Context) ([]*dto.CitySummaryResponse, error) {
+	cities, err := s.activeCities(ctx)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to list cities", err)
+	}
+	return dto.ToCitySummaryResponses(cities), nil
+}
+
+func (s *service) CheckServiceArea(ctx context.Context, req dto.ServiceAreaRequest) (*dto.ServiceAreaResponse, error) {
+	cities, err := s.activeCities(ctx)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to check service area", err)
+	}
+
+	if len(cities) == 0 {
+		return &dto.ServiceAreaResponse{Serviceable: true, Message: "This location is served"}, nil
+	}
+	if city := cityAt(cities, req.Lat, req.Lon); city != nil {
+		return &dto.ServiceAreaResponse{
+			Serviceable: true,
+			City:        dto.ToCitySummaryResponse(city),
+			Message:     fmt.Sprintf("This location is served in %s", city.Name),
+		}, nil
+	}
+
+	nearest, distance := nearestCity(cities, req.Lat, req.Lon)
+	return &dto.ServiceAreaResponse{
+		Serviceable: false,
+		NearestCity: dto.ToCitySummaryResponse(nearest),
+		DistanceKm:  &distance,
+		Message:     outsideMessage(nearest, distance),
+	}, nil
+}
+
+func (s *service) ListCities(ctx context.Context) ([]*dto.CityResponse, error) {
+	cities, err := s.repo.ListCities(ctx, false)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to list cities", err)
+	}
+	return dto.ToCityResponses(cities), nil
+}
+
+func (s *service) GetCity(ctx context.Context, id string) (*dto.CityResponse, error) {
+	city, err := s.getCity(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToCityResponse(city), nil
+}
+
+func (s *service) CreateCity(ctx context.Context, req dto.CreateCityRequest) (*dto.CityResponse, error) {
+	code := strings.ToLower(strings.TrimSpace(req.Code))
+	exists, err := s.repo.CityCodeExists(ctx, code, "")
+	if err != nil {
+		return nil, response.InternalServerError("Failed to create city", err)
+	}
+	if exists {
+		return nil, response.ConflictError(fmt.Sprintf("City '%s' already exists", code))
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+	fareMultiplier := 1.0
+	if req.FareMultiplier != nil {
+		fareMultiplier = *req.FareMultiplier
+	}
+
+	city := &models.City{
+		Code:               code,
+		Name:               strings.TrimSpace(req.Name),
+		Timezone:           strings.TrimSpace(req.Timezone),
+		Boundary:           req.Boundary,
+		FareMultiplier:     fareMultiplier,
+		MaxSurgeMultiplier: req.MaxSurgeMultiplier,
+		MatchingRadiiKm:    req.MatchingRadiiKm,
+		DispatchMode:       req.DispatchMode,
+		DispatchBatchSize:  req.DispatchBatchSize,
+		IsActive:           isActive,
+
+		RestrictVehicleTypes: req.RestrictVehicleTypes,
+	}
+
+	if err := dto.ValidateCity(city); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	if err := s.repo.CreateCity(ctx, city); err != nil {
+		return nil, response.InternalServerError("Failed to create city", err)
+	}
+	// The column defaults to active, so an inactive city needs a second write.
+	if !isActive {
+		city.IsActive = false
+		if err := s.repo.UpdateCity(ctx, city); err != nil {
+			return nil, response.InternalServerError("Failed to create city", err)
+		}
+	}
+
+	s.invalidateCities(ctx)
+	logger.Info("city created", "cityID", city.ID, "code", city.Code, "name", city.Name)
+
+	return dto.ToCityResponse(city), nil
+}
+
+func (s *service) UpdateCity(ctx context.Context, id string, req dto.UpdateCityRequest) (*dto.CityResponse, error) {
+	city, err := s.getCity(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Code != nil {
+		code := strings.ToLower(strings.TrimSpace(*req.Code))
+		exists, err := s.repo.CityCodeExists(ctx, code, city.ID)
+		if err != nil {
+			return nil, response.InternalServerError("Failed to update city", err)
+		}
+		if exists {
+			return nil, response.ConflictError(fmt.Sprintf("City '%s' already exists", code))
+		}
+		city.Code = code
+	}
+	if req.Name != nil {
+		city.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Timezone != nil {
+		city.Timezone = strings.TrimSpace(*req.Timezone)
+	}
+	if req.Boundary != nil {
+		city.Boundary = *req.Boundary
+	}
+	if req.FareMultiplier != nil {
+		city.FareMultiplier = *req.FareMultiplier
+	}
+	if req.MaxSurgeMultiplier != nil {
+		city.MaxSurgeMultiplier = *req.MaxSurgeMultiplier
+	}
+	if req.MatchingRadiiKm != nil {
+		city.MatchingRadiiKm = *req.MatchingRadiiKm
+	}
+	if req.DispatchMode != nil {
+		city.DispatchMode = req.DispatchMode
+	}
+	if req.DispatchBatchSize != nil {
+		city.DispatchBatchSize = req.DispatchBatchSize
+	}
+	if req.RestrictVehicleTypes != nil {
+		city.RestrictVehicleTypes = *req.RestrictVehicleTypes
+	}
+	if req.IsActive != nil {
+		city.IsActive = *req.IsActive
+	}
+
+	if err := dto.ValidateCity(city); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	if err := s.repo.UpdateCity(ctx, city); err != nil {
+		return nil, response.InternalServerError("Failed to update city", err)
+	}
+
+	s.invalidateCities(ctx)
+	logger.Info("city updated", "cityID", city.ID, "code", city.Code, "isActive", city.IsActive)
+
+	return dto.ToCityResponse(city), nil
+}
+
+func (s *service) DeleteCity(ctx context.Context, id string) error {
+	city, err := s.getCity(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteCity(ctx, city.ID); err != nil {
+		return response.InternalServerError("Failed to delete city", err)
+	}
+
+	s.invalidateCities(ctx)
+	logger.Info("city deleted", "cityID", city.ID, "code", city.Code)
+
+	return nil
+}
+
+func (s *service) SetCityVehicleType(ctx context.Context, cityID, vehicleTypeID string, req dto.SetCityVehicleTypeRequest) (*dto.CityVehicleTypeResponse, error) {
+	city, err := s.getCity(ctx, cityID)
+	if err != nil {
+		return nil, err
+	}
+	vehicleType, err := s.vehiclesRepo.FindByID(ctx, vehicleTypeID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Vehicle type")
+		}
+		return nil, response.InternalServerError("Failed to load vehicle type", err)
+	}
+
+	isAvailable := true
+	if req.IsAvailable != nil {
+		isAvailable = *req.IsAvailable
+	}
+
+	config := &models.CityVehicleType{
+		CityID:             city.ID,
+		VehicleTypeID:      vehicleType.ID,
+		IsAvailable:        isAvailable,
+		BaseFare:           req.BaseFare,
+		PerKmRate:          req.PerKmRate,
+		PerMinuteRate:      req.PerMinuteRate,
+		BookingFee:         req.BookingFee,
+		MaxSurgeMultiplier: req.MaxSurgeMultiplier,
+		OfferTimeoutSec:    req.OfferTimeoutSec,
+		SeatCapacity:       req.SeatCapacity,
+		LuggageCapacity:    req.LuggageCapacity,
+	}
+	if err := s.repo.UpsertCityVehicleType(ctx, config); err != nil {
+		return nil, response.InternalServerError("Failed to save city vehicle type", err)
+	}
+
+	saved, err := s.repo.GetCityVehicleType(ctx, city.ID, vehicleType.ID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to load city vehicle type", err)
+	}
+
+	s.invalidateCities(ctx)
+	logger.Info("city vehicle type set", "cityID", city.ID, "vehicleTypeID", vehicleType.ID, "isAvailable", isAvailable)
+
+	resp := dto.ToCityVehicleTypeResponse(saved)
+	return &resp, nil
+}
+
+func (s *service) RemoveCityVehicleType(ctx context.Context, cityID, vehicleTypeID string) error {
+	if _, err := s.repo.GetCityVehicleType(ctx, cityID, vehicleTypeID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.NotFoundError("City vehicle type")
+		}
+		return response.InternalServerError("Failed to load city vehicle type", err)
+	}
+
+	if err := s.repo.DeleteCityVehicleType(ctx, cityID, vehicleTypeID); err != nil {
+		return response.InternalServerError("Failed to remove city vehicle type", err)
+	}
+
+	s.invalidateCities(ctx)
+	logger.Info("city vehicle type removed", "cityID", cityID, "vehicleTypeID", vehicleTypeID)
+
+	return nil
+}
+
+func (s *service) getCity(ctx context.Context, id string) (*models.City, error) {
+	city, err := s.repo.GetCity(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("City")
+		}
+		return nil, response.InternalServerError("Failed to load city", err)
+	}
+	return city, nil
+}
@@ -0,0 +1,136 @@
+package cities
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/cities/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+// VehicleCapacity checks that a vehicle type can be booked at a pickup point
+// and returns how many passengers and bags it takes there.
+func (s *service) VehicleCapacity(ctx context.Context, lat, lon float64, vehicleTypeID string) (int, int, error) {
+	vehicleType, err := s.vehiclesRepo.FindByID(ctx, vehicleTypeID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, 0, response.NotFoundError("Vehicle type")
+		}
+		return 0, 0, response.InternalServerError("Failed to load vehicle type", err)
+	}
+	if !vehicleType.IsActive {
+		return 0, 0, response.CodedError(response.CodeVehicleTypeUnavailable,
+			fmt.Sprintf("%s is not available", vehicleType.DisplayName))
+	}
+
+	city, err := s.ResolveServiceArea(ctx, lat, lon)
+	if err != nil {
+		return 0, 0, err
+	}
+	if city == nil {
+		return vehicleType.Capacity, vehicleType.LuggageCapacity, nil
+	}
+
+	override, offered := city.OffersVehicleType(vehicleType.ID)
+	if !offered {
+		return 0, 0, vehicleTypeUnavailable(vehicleType, city)
+	}
+	seats, luggage := vehicleCapacity(vehicleType, override)
+	return seats, luggage, nil
+}
+
+func (s *service) ListVehicleTypesAt(ctx context.Context, req dto.ServiceAreaRequest) ([]*dto.OfferedVehicleTypeResponse, error) {
+	city, err := s.ResolveServiceArea(ctx, req.Lat, req.Lon)
+	if err != nil {
+		return nil, err
+	}
+
+	vehicleTypes, err := s.vehiclesRepo.FindActive(ctx)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to list vehicle types", err)
+	}
+
+	result := make([]*dto.OfferedVehicleTypeResponse, 0, len(vehicleTypes))
+	for _, vehicleType := range vehicleTypes {
+		var override *models.CityVehicleType
+		if city != nil {
+			var offered bool
+			if override, offered = city.OffersVehicleType(vehicleType.ID); !offered {
+				continue
+			}
+		}
+		seats, luggage := vehicleCapacity(vehicleType, override)
+		result = append(result, dto.ToOfferedVehicleTypeResponse(vehicleType, seats, luggage))
+	}
+	return result, nil
+}
+
+// GetVehicleTypeMatrix lays out, for every city, which vehicle types riders
+// can book and what each carries there.
+func (s *service) GetVehicleTypeMatrix(ctx context.Context) (*dto.VehicleTypeMatrixResponse, error) {
+	cities, err := s.repo.ListCities(ctx, false)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to list cities", err)
+	}
+	vehicleTypes, err := s.vehiclesRepo.FindAll(ctx)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to list vehicle types", err)
+	}
+
+	matrix := &dto.VehicleTypeMatrixResponse{
+		VehicleTypes: make([]dto.VehicleTypeColumn, 0, len(vehicleTypes)),
+		Cities:       make([]dto.VehicleTypeRow, 0, len(cities)),
+	}
+	for _, vehicleType := range vehicleTypes {
+		matrix.VehicleTypes = append(matrix.VehicleTypes, dto.ToVehicleTypeColumn(vehicleType))
+	}
+
+	for _, city := range cities {
+		row := dto.VehicleTypeRow{
+			CityID:               city.ID,
+			Code:                 city.Code,
+			Name:                 city.Name,
+			IsActive:             city.IsActive,
+			RestrictVehicleTypes: city.RestrictVehicleTypes,
+			VehicleTypes:         make([]dto.VehicleTypeCell, 0, len(vehicleTypes)),
+		}
+		for _, vehicleType := range vehicleTypes {
+			override, offered := city.OffersVehicleType(vehicleType.ID)
+			seats, luggage := vehicleCapacity(vehicleType, override)
+			row.VehicleTypes = append(row.VehicleTypes, dto.VehicleTypeCell{
+				VehicleTypeID:   vehicleType.ID,
+				Offered:         offered && vehicleType.IsActive,
+				Configured:      override != nil,
+				SeatCapacity:    seats,
+				LuggageCapacity: luggage,
+			})
+		}
+		matrix.Cities = append(matrix.Cities, row)
+	}
+
+	return matrix, nil
+}
+
+// vehicleCapacity is what a vehicle type carries in a city: the city's own
+// capacities where it sets them, otherwise the type's.
+func vehicleCapacity(vehicleType *models.VehicleType, override *models.CityVehicleType) (seats, luggage int) {
+	seats, luggage = vehicleType.Capacity, vehicleType.LuggageCapacity
+	if override == nil {
+		return seats, luggage
+	}
+	if override.SeatCapacity != nil {
+		seats = *override.SeatCapacity
+	}
+	if override.LuggageCapacity != nil {
+		luggage = *override.LuggageCapacity
+	}
+	return seats, luggage
+}
+
+func vehicleTypeUnavailable(vehicleType *models.VehicleType, city *models.City) error {
+	return response.CodedError(response.CodeVehicleTypeUnavailable,
+		fmt.Sprintf("%s is not available in %s", vehicleType.DisplayName, city.Name))
+}
@@ -0,0 +1,31 @@
+package commissions
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/audit"
+)
+
+func (s *service) SetAuditLogger(auditLogger audit.Recorder) {
+	s.auditLogger = auditLogger
+}
+
+func (s *service) record(ctx context.Context, adminID, action string, rate *models.CommissionRate, deleted bool) {
+	if s.auditLogger == nil {
+		return
+	}
+	entry := audit.Entry{
+		ActorID:    adminID,
+		ActorRole:  string(models.RoleAdmin),
+		Action:     action,
+		EntityType: "commission_rate",
+		EntityID:   rate.ID,
+	}
+	if deleted {
+		entry.Before = rate
+	} else {
+		entry.After = rate
+	}
+	s.auditLogger.Record(ctx, entry)
+}
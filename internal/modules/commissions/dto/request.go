@@ -0,0 +1,50 @@
+package dto
+
+import (
+	"errors"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+type ListRatesQuery struct {
+	Kind          string `form:"kind" binding:"omitempty,oneof=ride homeservice"`
+	VehicleTypeID string `form:"vehicleTypeId" binding:"omitempty,uuid"`
+	CategorySlug  string `form:"categorySlug" binding:"omitempty,max=100"`
+	CityID        string `form:"cityId" binding:"omitempty,uuid"`
+}
+
+// CreateRateRequest adds a rate version. Leaving vehicleTypeId, categorySlug
+// and cityId empty sets the default for the kind.
+type CreateRateRequest struct {
+	Kind          string     `json:"kind" binding:"required,oneof=ride homeservice"`
+	VehicleTypeID *string    `json:"vehicleTypeId" binding:"omitempty,uuid"`
+	CategorySlug  *string    `json:"categorySlug" binding:"omitempty,min=1,max=100"`
+	CityID        *string    `json:"cityId" binding:"omitempty,uuid"`
+	Rate          float64    `json:"rate" binding:"min=0,max=100"`
+	EffectiveFrom *time.Time `json:"effectiveFrom"`
+	Note          string     `json:"note" binding:"max=500"`
+}
+
+func (r *CreateRateRequest) Validate(now time.Time) error {
+	if r.Kind == models.CommissionKindRide && r.CategorySlug != nil {
+		return errors.New("categorySlug only applies to homeservice rates")
+	}
+	if r.Kind == models.CommissionKindHomeService && r.VehicleTypeID != nil {
+		return errors.New("vehicleTypeId only applies to ride rates")
+	}
+	// A minute of slack covers clock skew between the admin panel and the API.
+	if r.EffectiveFrom != nil && r.EffectiveFrom.Before(now.Add(-time.Minute)) {
+		return errors.New("effectiveFrom cannot be in the past")
+	}
+	return nil
+}
+
+// ResolveQuery previews the rate that would apply to a ride or order.
+type ResolveQuery struct {
+	Kind          string     `form:"kind" binding:"required,oneof=ride homeservice"`
+	VehicleTypeID string     `form:"vehicleTypeId" binding:"omitempty,uuid"`
+	CategorySlug  string     `form:"categorySlug" binding:"omitempty,max=100"`
+	CityID        string     `form:"cityId" binding:"omitempty,uuid"`
+	At            *time.Time `form:"at" time_format:"2006-01-02T15:04:05Z07:00"`
+}
@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+type CommissionRateResponse struct {
+	ID            string    `json:"id"`
+	Kind          string    `json:"kind"`
+	VehicleTypeID *string   `json:"vehicleTypeId,omitempty"`
+	CategorySlug  *string   `json:"categorySlug,omitempty"`
+	CityID        *string   `json:"cityId,omitempty"`
+	Rate          float64   `json:"rate"`
+	EffectiveFrom time.Time `json:"effectiveFrom"`
+	// Scheduled is true for versions that have not taken effect yet; only
+	// these can be deleted.
+	Scheduled bool      `json:"scheduled"`
+	Note      string    `json:"note"`
+	CreatedBy *string   `json:"createdBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type ResolvedRateResponse struct {
+	Rate float64 `json:"rate"`
+	// Source is the matching version, or nil when the built-in default applies.
+	Source *CommissionRateResponse `json:"source,omitempty"`
+}
+
+func ToCommissionRateResponse(rate *models.CommissionRate, now time.Time) CommissionRateResponse {
+	return CommissionRateResponse{
+		ID:            rate.ID,
+		Kind:          rate.Kind,
+		VehicleTypeID: rate.VehicleTypeID,
+		CategorySlug:  rate.CategorySlug,
+		CityID:        rate.CityID,
+		Rate:          rate.Rate,
+		EffectiveFrom: rate.EffectiveFrom,
+		Scheduled:     rate.EffectiveFrom.After(now),
+		Note:          rate.Note,
+		CreatedBy:     rate.CreatedBy,
+		CreatedAt:     rate.CreatedAt,
+	}
+}
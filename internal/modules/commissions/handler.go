@@ -0,0 +1,120 @@
+package commissions
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/modules/commissions/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// ListRates godoc
+// @Summary List commission rates
+// @Description All commission rate versions, including scheduled ones. Rates are percentages of the ride fare or of the order's service amount
+// @Tags admin-commissions
+// @Security BearerAuth
+// @Produce json
+// @Param kind query string false "ride or homeservice"
+// @Param vehicleTypeId query string false "Vehicle type ID"
+// @Param categorySlug query string false "Home-service or laundry category"
+// @Param cityId query string false "City ID"
+// @Success 200 {object} response.Response{data=[]dto.CommissionRateResponse}
+// @Router /admin/commissions [get]
+func (h *Handler) ListRates(c *gin.Context) {
+	var query dto.ListRatesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+
+	rates, err := h.service.ListRates(c.Request.Context(), query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, rates, "Commission rates retrieved successfully")
+}
+
+// CreateRate godoc
+// @Summary Add a commission rate version
+// @Description Adds a rate for a kind, optionally narrowed to a vehicle type (rides) or category (home services and laundry) and a city. It takes effect at effectiveFrom, or immediately when omitted. Rides and orders keep the rate in force when they were priced
+// @Tags admin-commissions
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateRateRequest true "Commission rate"
+// @Success 200 {object} response.Response{data=dto.CommissionRateResponse}
+// @Router /admin/commissions [post]
+func (h *Handler) CreateRate(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	var req dto.CreateRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	rate, err := h.service.CreateRate(c.Request.Context(), adminID.(string), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, rate, "Commission rate created successfully")
+}
+
+// DeleteRate godoc
+// @Summary Delete a scheduled commission rate
+// @Description Only versions that have not taken effect yet can be deleted
+// @Tags admin-commissions
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Commission rate ID"
+// @Success 200 {object} response.Response
+// @Router /admin/commissions/{id} [delete]
+func (h *Handler) DeleteRate(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	if err := h.service.DeleteRate(c.Request.Context(), adminID.(string), c.Param("id")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, nil, "Commission rate deleted successfully")
+}
+
+// ResolveRate godoc
+// @Summary Preview the applicable commission rate
+// @Description Returns the rate that applies to a ride or order with the given scope at a point in time, and the version it comes from
+// @Tags admin-commissions
+// @Security BearerAuth
+// @Produce json
+// @Param kind query string true "ride or homeservice"
+// @Param vehicleTypeId query string false "Vehicle type ID"
+// @Param categorySlug query string false "Category slug"
+// @Param cityId query string false "City ID"
+// @Param at query string false "RFC3339 time, defaults to now"
+// @Success 200 {object} response.Response{data=dto.ResolvedRateResponse}
+// @Router /admin/commissions/resolve [get]
+func (h *Handler) ResolveRate(c *gin.Context) {
+	var query dto.ResolveQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+
+	rate, err := h.service.Resolve(c.Request.Context(), query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, rate, "Commission rate resolved successfully")
+}
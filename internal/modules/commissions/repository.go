@@ -0,0 +1,111 @@
+package commissions
+
+import (
+	"context"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/commissions/dto"
+	"gorm.io/gorm"
+)
+
+// Scope narrows a rate lookup. Empty fields match only rows that leave the
+// field unset.
+type Scope struct {
+	Kind          string
+	VehicleTypeID string
+	CategorySlug  string
+	CityID        string
+}
+
+type Repository interface {
+	List(ctx context.Context, query dto.ListRatesQuery) ([]*models.CommissionRate, error)
+	FindByID(ctx context.Context, id string) (*models.CommissionRate, error)
+	Create(ctx context.Context, rate *models.CommissionRate) error
+	Delete(ctx context.Context, id string) error
+	// Resolve returns the most specific version in effect at the given time.
+	Resolve(ctx context.Context, scope Scope, at time.Time) (*models.CommissionRate, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) List(ctx context.Context, query dto.ListRatesQuery) ([]*models.CommissionRate, error) {
+	db := r.db.WithContext(ctx).Model(&models.CommissionRate{})
+	if query.Kind != "" {
+		db = db.Where("kind = ?", query.Kind)
+	}
+	if query.VehicleTypeID != "" {
+		db = db.Where("vehicle_type_id = ?", query.VehicleTypeID)
+	}
+	if query.CategorySlug != "" {
+		db = db.Where("category_slug = ?", query.CategorySlug)
+	}
+	if query.CityID != "" {
+		db = db.Where("city_id = ?", query.CityID)
+	}
+
+	var rates []*models.CommissionRate
+	err := db.
+		Order("kind ASC, vehicle_type_id ASC NULLS FIRST, category_slug ASC NULLS FIRST, city_id ASC NULLS FIRST, effective_from DESC").
+		Find(&rates).Error
+	return rates, err
+}
+
+func (r *repository) FindByID(ctx context.Context, id string) (*models.CommissionRate, error) {
+	var rate models.CommissionRate
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&rate).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+func (r *repository) Create(ctx context.Context, rate *models.CommissionRate) error {
+	return r.db.WithContext(ctx).Create(rate).Error
+}
+
+func (r *repository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.CommissionRate{}).Error
+}
+
+func (r *repository) Resolve(ctx context.Context, scope Scope, at time.Time) (*models.CommissionRate, error) {
+	db := r.db.WithContext(ctx).
+		Where("kind = ? AND effective_from <= ?", scope.Kind, at)
+
+	// vehicle_type_id and city_id are uuid columns, so an empty string must
+	// not reach the comparison.
+	if scope.VehicleTypeID != "" {
+		db = db.Where("(vehicle_type_id = ? OR vehicle_type_id IS NULL)", scope.VehicleTypeID)
+	} else {
+		db = db.Where("vehicle_type_id IS NULL")
+	}
+	if scope.CategorySlug != "" {
+		db = db.Where("(category_slug = ? OR category_slug IS NULL)", scope.CategorySlug)
+	} else {
+		db = db.Where("category_slug IS NULL")
+	}
+	if scope.CityID != "" {
+		db = db.Where("(city_id = ? OR city_id IS NULL)", scope.CityID)
+	} else {
+		db = db.Where("city_id IS NULL")
+	}
+
+	// Vehicle type or category outranks city, and within the same scope the
+	// newest version wins.
+	var rate models.CommissionRate
+	err := db.
+		Order("(vehicle_type_id IS NOT NULL OR category_slug IS NOT NULL) DESC").
+		Order("(city_id IS NOT NULL) DESC").
+		Order("effective_from DESC").
+		First(&rate).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
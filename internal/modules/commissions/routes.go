@@ -0,0 +1,18 @@
+package commissions
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/middleware"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
+	commissions := router.Group("/admin/commissions")
+	commissions.Use(authMiddleware)
+	commissions.Use(middleware.RequireAdmin())
+	{
+		commissions.GET("", handler.ListRates)
+		commissions.POST("", handler.CreateRate)
+		commissions.GET("/resolve", handler.ResolveRate)
+		commissions.DELETE("/:id", handler.DeleteRate)
+	}
+}
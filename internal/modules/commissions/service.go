@@ -0,0 +1,217 @@
+package commissions
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/audit"
+	"github.com/umar5678/go-backend/internal/modules/commissions/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+// Built-in rates, in percent, used when no version matches or the lookup
+// fails. They match the defaults seeded by the migration.
+const (
+	DefaultRideRate        = 20.0
+	DefaultHomeServiceRate = 10.0
+)
+
+// ServiceAreas resolves the city a point falls in. It is satisfied by
+// cities.Service.
+type ServiceAreas interface {
+	ResolveServiceArea(ctx context.Context, lat, lon float64) (*models.City, error)
+}
+
+type Service interface {
+	// RideRate is the platform's cut, in percent, of a ride fare.
+	RideRate(ctx context.Context, vehicleTypeID string, lat, lon float64, at time.Time) float64
+	// ServiceRate is the platform's cut, in percent, of a home-service or
+	// laundry order in the given category.
+	ServiceRate(ctx context.Context, categorySlug string, lat, lng float64, at time.Time) float64
+
+	ListRates(ctx context.Context, query dto.ListRatesQuery) ([]dto.CommissionRateResponse, error)
+	CreateRate(ctx context.Context, adminID string, req dto.CreateRateRequest) (*dto.CommissionRateResponse, error)
+	DeleteRate(ctx context.Context, adminID, id string) error
+	Resolve(ctx context.Context, query dto.ResolveQuery) (*dto.ResolvedRateResponse, error)
+
+	SetServiceAreas(serviceAreas ServiceAreas)
+	SetAuditLogger(auditLogger audit.Recorder)
+}
+
+type service struct {
+	repo         Repository
+	serviceAreas ServiceAreas
+	auditLogger  audit.Recorder
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) SetServiceAreas(serviceAreas ServiceAreas) {
+	s.serviceAreas = serviceAreas
+}
+
+func (s *service) RideRate(ctx context.Context, vehicleTypeID string, lat, lon float64, at time.Time) float64 {
+	return s.rateAt(ctx, Scope{
+		Kind:          models.CommissionKindRide,
+		VehicleTypeID: vehicleTypeID,
+		CityID:        s.cityID(ctx, lat, lon),
+	}, at)
+}
+
+func (s *service) ServiceRate(ctx context.Context, categorySlug string, lat, lng float64, at time.Time) float64 {
+	return s.rateAt(ctx, Scope{
+		Kind:         models.CommissionKindHomeService,
+		CategorySlug: categorySlug,
+		CityID:       s.cityID(ctx, lat, lng),
+	}, at)
+}
+
+func (s *service) rateAt(ctx context.Context, scope Scope, at time.Time) float64 {
+	rate, err := s.repo.Resolve(ctx, scope, at)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error("failed to resolve commission rate", "error", err, "kind", scope.Kind)
+		}
+		return defaultRate(scope.Kind)
+	}
+	return rate.Rate
+}
+
+// cityID returns the service area containing the point, or "" when there is
+// none or cities are not configured.
+func (s *service) cityID(ctx context.Context, lat, lon float64) string {
+	if s.serviceAreas == nil || (lat == 0 && lon == 0) {
+		return ""
+	}
+	city, err := s.serviceAreas.ResolveServiceArea(ctx, lat, lon)
+	if err != nil || city == nil {
+		return ""
+	}
+	return city.ID
+}
+
+func defaultRate(kind string) float64 {
+	if kind == models.CommissionKindRide {
+		return DefaultRideRate
+	}
+	return DefaultHomeServiceRate
+}
+
+func (s *service) ListRates(ctx context.Context, query dto.ListRatesQuery) ([]dto.CommissionRateResponse, error) {
+	rates, err := s.repo.List(ctx, query)
+	if err != nil {
+		logger.Error("failed to list commission rates", "error", err)
+		return nil, response.InternalServerError("Failed to list commission rates", err)
+	}
+
+	now := time.Now()
+	result := make([]dto.CommissionRateResponse, 0, len(rates))
+	for _, rate := range rates {
+		result = append(result, dto.ToCommissionRateResponse(rate, now))
+	}
+	return result, nil
+}
+
+func (s *service) CreateRate(ctx context.Context, adminID string, req dto.CreateRateRequest) (*dto.CommissionRateResponse, error) {
+	now := time.Now()
+	if err := req.Validate(now); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	effectiveFrom := now
+	if req.EffectiveFrom != nil && req.EffectiveFrom.After(now) {
+		effectiveFrom = *req.EffectiveFrom
+	}
+
+	rate := &models.CommissionRate{
+		Kind:          req.Kind,
+		VehicleTypeID: req.VehicleTypeID,
+		CategorySlug:  req.CategorySlug,
+		CityID:        req.CityID,
+		Rate:          req.Rate,
+		EffectiveFrom: effectiveFrom.UTC(),
+		Note:          req.Note,
+	}
+	if adminID != "" {
+		rate.CreatedBy = &adminID
+	}
+
+	if err := s.repo.Create(ctx, rate); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "idx_commission_rates_version") {
+			return nil, response.ConflictError("A rate for this scope already takes effect at that time")
+		}
+		if errors.Is(err, gorm.ErrForeignKeyViolated) || strings.Contains(err.Error(), "foreign key") {
+			return nil, response.BadRequest("Unknown vehicle type or city")
+		}
+		logger.Error("failed to create commission rate", "error", err)
+		return nil, response.InternalServerError("Failed to create commission rate", err)
+	}
+
+	logger.Info("commission rate created",
+		"rateId", rate.ID,
+		"kind", rate.Kind,
+		"rate", rate.Rate,
+		"effectiveFrom", rate.EffectiveFrom,
+		"adminId", adminID,
+	)
+	s.record(ctx, adminID, "commission_rate.create", rate, false)
+
+	result := dto.ToCommissionRateResponse(rate, now)
+	return &result, nil
+}
+
+func (s *service) DeleteRate(ctx context.Context, adminID, id string) error {
+	rate, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.NotFoundError("Commission rate")
+		}
+		return response.InternalServerError("Failed to load commission rate", err)
+	}
+
+	// Rates that have applied to rides or orders stay for the record; they
+	// are replaced by adding a newer version.
+	if !rate.EffectiveFrom.After(time.Now()) {
+		return response.BadRequest("Rates already in effect cannot be deleted; add a new version instead")
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		logger.Error("failed to delete commission rate", "error", err, "rateId", id)
+		return response.InternalServerError("Failed to delete commission rate", err)
+	}
+
+	logger.Info("scheduled commission rate deleted", "rateId", id, "adminId", adminID)
+	s.record(ctx, adminID, "commission_rate.delete", rate, true)
+	return nil
+}
+
+func (s *service) Resolve(ctx context.Context, query dto.ResolveQuery) (*dto.ResolvedRateResponse, error) {
+	at := time.Now()
+	if query.At != nil {
+		at = *query.At
+	}
+	scope := Scope{
+		Kind:          query.Kind,
+		VehicleTypeID: query.VehicleTypeID,
+		CategorySlug:  query.CategorySlug,
+		CityID:        query.CityID,
+	}
+
+	rate, err := s.repo.Resolve(ctx, scope, at)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &dto.ResolvedRateResponse{Rate: defaultRate(query.Kind)}, nil
+		}
+		return nil, response.InternalServerError("Failed to resolve commission rate", err)
+	}
+
+	source := dto.ToCommissionRateResponse(rate, time.Now())
+	return &dto.ResolvedRateResponse{Rate: rate.Rate, Source: &source}, nil
+}
@@ -0,0 +1,75 @@
+package currency
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// Info describes a currency the platform can price and charge in.
+// MinorUnits is the number of decimal places amounts are rounded to.
+type Info struct {
+	Code       string `json:"code"`
+	Name       string `json:"name"`
+	Symbol     string `json:"symbol"`
+	MinorUnits int    `json:"minorUnits"`
+}
+
+var supported = map[string]Info{
+	"AED": {Code: "AED", Name: "UAE Dirham", Symbol: "د.إ", MinorUnits: 2},
+	"BDT": {Code: "BDT", Name: "Bangladeshi Taka", Symbol: "৳", MinorUnits: 2},
+	"BHD": {Code: "BHD", Name: "Bahraini Dinar", Symbol: "BD", MinorUnits: 3},
+	"EUR": {Code: "EUR", Name: "Euro", Symbol: "€", MinorUnits: 2},
+	"GBP": {Code: "GBP", Name: "British Pound", Symbol: "£", MinorUnits: 2},
+	"INR": {Code: "INR", Name: "Indian Rupee", Symbol: "₹", MinorUnits: 2},
+	"JPY": {Code: "JPY", Name: "Japanese Yen", Symbol: "¥", MinorUnits: 0},
+	"KES": {Code: "KES", Name: "Kenyan Shilling", Symbol: "KSh", MinorUnits: 2},
+	"KWD": {Code: "KWD", Name: "Kuwaiti Dinar", Symbol: "KD", MinorUnits: 3},
+	"LKR": {Code: "LKR", Name: "Sri Lankan Rupee", Symbol: "Rs", MinorUnits: 2},
+	"MYR": {Code: "MYR", Name: "Malaysian Ringgit", Symbol: "RM", MinorUnits: 2},
+	"NGN": {Code: "NGN", Name: "Nigerian Naira", Symbol: "₦", MinorUnits: 2},
+	"NPR": {Code: "NPR", Name: "Nepalese Rupee", Symbol: "Rs", MinorUnits: 2},
+	"OMR": {Code: "OMR", Name: "Omani Rial", Symbol: "OMR", MinorUnits: 3},
+	"PKR": {Code: "PKR", Name: "Pakistani Rupee", Symbol: "Rs", MinorUnits: 2},
+	"QAR": {Code: "QAR", Name: "Qatari Riyal", Symbol: "QR", MinorUnits: 2},
+	"SAR": {Code: "SAR", Name: "Saudi Riyal", Symbol: "SR", MinorUnits: 2},
+	"SGD": {Code: "SGD", Name: "Singapore Dollar", Symbol: "S$", MinorUnits: 2},
+	"USD": {Code: "USD", Name: "US Dollar", Symbol: "$", MinorUnits: 2},
+	"ZAR": {Code: "ZAR", Name: "South African Rand", Symbol: "R", MinorUnits: 2},
+}
+
+// Normalize upper-cases and trims a currency code.
+func Normalize(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func Lookup(code string) (Info, bool) {
+	info, ok := supported[Normalize(code)]
+	return info, ok
+}
+
+func IsSupported(code string) bool {
+	_, ok := Lookup(code)
+	return ok
+}
+
+// Supported lists every supported currency ordered by code.
+func Supported() []Info {
+	list := make([]Info, 0, len(supported))
+	for _, info := range supported {
+		list = append(list, info)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Code < list[j].Code })
+	return list
+}
+
+// Round rounds an amount to the minor units of its currency. Unknown
+// currencies are rounded to two places.
+func Round(amount float64, code string) float64 {
+	units := 2
+	if info, ok := Lookup(code); ok {
+		units = info.MinorUnits
+	}
+	factor := math.Pow(10, float64(units))
+	return math.Round(amount*factor) / factor
+}
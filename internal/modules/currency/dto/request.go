@@ -0,0 +1,36 @@
+package dto
+
+type ResolveCurrencyRequest struct {
+	Lat float64 `form:"lat" binding:"required,latitude"`
+	Lon float64 `form:"lon" binding:"required,longitude"`
+}
+
+type ConvertRequest struct {
+	Amount float64 `form:"amount" binding:"required,gt=0"`
+	From   string  `form:"from" binding:"required,len=3"`
+	To     string  `form:"to" binding:"required,len=3"`
+}
+
+type CreateCityCurrencyRequest struct {
+	City      string  `json:"city" binding:"required,max=100"`
+	Currency  string  `json:"currency" binding:"required,len=3"`
+	CenterLat float64 `json:"centerLat" binding:"min=-90,max=90"`
+	CenterLon float64 `json:"centerLon" binding:"min=-180,max=180"`
+	RadiusKm  float64 `json:"radiusKm" binding:"required,gt=0,max=5000"`
+	IsActive  *bool   `json:"isActive"`
+}
+
+type UpdateCityCurrencyRequest struct {
+	City      *string  `json:"city" binding:"omitempty,max=100"`
+	Currency  *string  `json:"currency" binding:"omitempty,len=3"`
+	CenterLat *float64 `json:"centerLat" binding:"omitempty,min=-90,max=90"`
+	CenterLon *float64 `json:"centerLon" binding:"omitempty,min=-180,max=180"`
+	RadiusKm  *float64 `json:"radiusKm" binding:"omitempty,gt=0,max=5000"`
+	IsActive  *bool    `json:"isActive"`
+}
+
+// SetRateRequest pins how many units of the currency one unit of the
+// platform's default currency buys, until the next provider refresh.
+type SetRateRequest struct {
+	Rate float64 `json:"rate" binding:"required,gt=0"`
+}
@@ -0,0 +1,108 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+type CurrencyResponse struct {
+	Code       string `json:"code"`
+	Name       string `json:"name"`
+	Symbol     string `json:"symbol"`
+	MinorUnits int    `json:"minorUnits"`
+	IsDefault  bool   `json:"isDefault"`
+	// Rate is how many units one unit of the default currency buys; it is
+	// omitted while no rate is known.
+	Rate          *float64   `json:"rate,omitempty"`
+	RateUpdatedAt *time.Time `json:"rateUpdatedAt,omitempty"`
+}
+
+type CurrenciesResponse struct {
+	Default    string             `json:"default"`
+	Currencies []CurrencyResponse `json:"currencies"`
+}
+
+type ResolvedCurrencyResponse struct {
+	Currency   string `json:"currency"`
+	Name       string `json:"name"`
+	Symbol     string `json:"symbol"`
+	MinorUnits int    `json:"minorUnits"`
+	City       string `json:"city,omitempty"`
+}
+
+type ConversionResponse struct {
+	Amount          float64 `json:"amount"`
+	From            string  `json:"from"`
+	ConvertedAmount float64 `json:"convertedAmount"`
+	To              string  `json:"to"`
+	Rate            float64 `json:"rate"`
+}
+
+type CityCurrencyResponse struct {
+	ID        string    `json:"id"`
+	City      string    `json:"city"`
+	Currency  string    `json:"currency"`
+	CenterLat float64   `json:"centerLat"`
+	CenterLon float64   `json:"centerLon"`
+	RadiusKm  float64   `json:"radiusKm"`
+	IsActive  bool      `json:"isActive"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type ExchangeRateResponse struct {
+	BaseCurrency string    `json:"baseCurrency"`
+	Currency     string    `json:"currency"`
+	Rate         float64   `json:"rate"`
+	Source       string    `json:"source"`
+	UpdatedBy    *string   `json:"updatedBy,omitempty"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+type RateRefreshResponse struct {
+	Provider  string    `json:"provider"`
+	Updated   int       `json:"updated"`
+	Refreshed time.Time `json:"refreshedAt"`
+}
+
+func ToCityCurrencyResponse(city *models.CityCurrency) *CityCurrencyResponse {
+	return &CityCurrencyResponse{
+		ID:        city.ID,
+		City:      city.City,
+		Currency:  city.Currency,
+		CenterLat: city.CenterLat,
+		CenterLon: city.CenterLon,
+		RadiusKm:  city.RadiusKm,
+		IsActive:  city.IsActive,
+		CreatedAt: city.CreatedAt,
+		UpdatedAt: city.UpdatedAt,
+	}
+}
+
+func ToCityCurrencyResponses(cities []*models.CityCurrency) []*CityCurrencyResponse {
+	result := make([]*CityCurrencyResponse, 0, len(cities))
+	for _, city := range cities {
+		result = append(result, ToCityCurrencyResponse(city))
+	}
+	return result
+}
+
+func ToExchangeRateResponse(rate *models.ExchangeRate) *ExchangeRateResponse {
+	return &ExchangeRateResponse{
+		BaseCurrency: rate.BaseCurrency,
+		Currency:     rate.Currency,
+		Rate:         rate.Rate,
+		Source:       rate.Source,
+		UpdatedBy:    rate.UpdatedBy,
+		UpdatedAt:    rate.UpdatedAt,
+	}
+}
+
+func ToExchangeRateResponses(rates []*models.ExchangeRate) []*ExchangeRateResponse {
+	result := make([]*ExchangeRateResponse, 0, len(rates))
+	for _, rate := range rates {
+		result = append(result, ToExchangeRateResponse(rate))
+	}
+	return result
+}
@@ -0,0 +1,248 @@
+package currency
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/modules/currency/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// ListCurrencies godoc
+// @Summary List supported currencies
+// @Description Every currency the platform can price in, with its rate against the default currency where one is known
+// @Tags currencies
+// @Produce json
+// @Success 200 {object} response.Response{data=dto.CurrenciesResponse}
+// @Router /currencies [get]
+func (h *Handler) ListCurrencies(c *gin.Context) {
+	currencies, err := h.service.ListCurrencies(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, currencies, "Currencies retrieved successfully")
+}
+
+// ResolveCurrency godoc
+// @Summary Get the currency used at a location
+// @Tags currencies
+// @Produce json
+// @Param lat query number true "Latitude"
+// @Param lon query number true "Longitude"
+// @Success 200 {object} response.Response{data=dto.ResolvedCurrencyResponse}
+// @Router /currencies/resolve [get]
+func (h *Handler) ResolveCurrency(c *gin.Context) {
+	var req dto.ResolveCurrencyRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+
+	resolved, err := h.service.ResolveCurrency(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, resolved, "Currency resolved successfully")
+}
+
+// ConvertAmount godoc
+// @Summary Convert an amount between currencies
+// @Tags currencies
+// @Produce json
+// @Param amount query number true "Amount"
+// @Param from query string true "Currency code to convert from"
+// @Param to query string true "Currency code to convert to"
+// @Success 200 {object} response.Response{data=dto.ConversionResponse}
+// @Router /currencies/convert [get]
+func (h *Handler) ConvertAmount(c *gin.Context) {
+	var req dto.ConvertRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+
+	conversion, err := h.service.ConvertAmount(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, conversion, "Amount converted successfully")
+}
+
+// ListCityCurrencies godoc
+// @Summary List city currencies
+// @Tags currencies - admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=[]dto.CityCurrencyResponse}
+// @Router /currencies/admin/cities [get]
+func (h *Handler) ListCityCurrencies(c *gin.Context) {
+	cities, err := h.service.ListCityCurrencies(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, cities, "City currencies retrieved successfully")
+}
+
+// GetCityCurrency godoc
+// @Summary Get a city currency
+// @Tags currencies - admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "City currency ID"
+// @Success 200 {object} response.Response{data=dto.CityCurrencyResponse}
+// @Router /currencies/admin/cities/{id} [get]
+func (h *Handler) GetCityCurrency(c *gin.Context) {
+	city, err := h.service.GetCityCurrency(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, city, "City currency retrieved successfully")
+}
+
+// CreateCityCurrency godoc
+// @Summary Create a city currency
+// @Description Prices within radiusKm of the centre are quoted and charged in the given currency; the smallest matching city wins
+// @Tags currencies - admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateCityCurrencyRequest true "City currency details"
+// @Success 200 {object} response.Response{data=dto.CityCurrencyResponse}
+// @Router /currencies/admin/cities [post]
+func (h *Handler) CreateCityCurrency(c *gin.Context) {
+	var req dto.CreateCityCurrencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	city, err := h.service.CreateCityCurrency(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, city, "City currency created successfully")
+}
+
+// UpdateCityCurrency godoc
+// @Summary Update a city currency
+// @Tags currencies - admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "City currency ID"
+// @Param request body dto.UpdateCityCurrencyRequest true "Fields to change"
+// @Success 200 {object} response.Response{data=dto.CityCurrencyResponse}
+// @Router /currencies/admin/cities/{id} [put]
+func (h *Handler) UpdateCityCurrency(c *gin.Context) {
+	var req dto.UpdateCityCurrencyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	city, err := h.service.UpdateCityCurrency(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, city, "City currency updated successfully")
+}
+
+// DeleteCityCurrency godoc
+// @Summary Delete a city currency
+// @Tags currencies - admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "City currency ID"
+// @Success 200 {object} response.Response
+// @Router /currencies/admin/cities/{id} [delete]
+func (h *Handler) DeleteCityCurrency(c *gin.Context) {
+	if err := h.service.DeleteCityCurrency(c.Request.Context(), c.Param("id")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, nil, "City currency deleted successfully")
+}
+
+// ListRates godoc
+// @Summary List exchange rates
+// @Tags currencies - admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=[]dto.ExchangeRateResponse}
+// @Router /currencies/admin/rates [get]
+func (h *Handler) ListRates(c *gin.Context) {
+	rates, err := h.service.ListRates(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, rates, "Exchange rates retrieved successfully")
+}
+
+// SetRate godoc
+// @Summary Set an exchange rate by hand
+// @Description The rate is units of the currency per unit of the default currency. A configured provider overwrites it on the next refresh
+// @Tags currencies - admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param code path string true "Currency code"
+// @Param request body dto.SetRateRequest true "Rate"
+// @Success 200 {object} response.Response{data=dto.ExchangeRateResponse}
+// @Router /currencies/admin/rates/{code} [put]
+func (h *Handler) SetRate(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	var req dto.SetRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	rate, err := h.service.SetRate(c.Request.Context(), adminID.(string), c.Param("code"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, rate, "Exchange rate saved successfully")
+}
+
+// RefreshRates godoc
+// @Summary Refresh exchange rates from the provider
+// @Tags currencies - admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=dto.RateRefreshResponse}
+// @Router /currencies/admin/rates/refresh [post]
+func (h *Handler) RefreshRates(c *gin.Context) {
+	result, err := h.service.RefreshRates(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Exchange rates refreshed successfully")
+}
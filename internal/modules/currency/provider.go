@@ -0,0 +1,89 @@
+package currency
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	ProviderNone = "none"
+	ProviderHTTP = "http"
+)
+
+// RateProvider is a source of live exchange rates.
+type RateProvider interface {
+	Name() string
+	// Latest returns how many units of each currency one unit of base buys.
+	Latest(ctx context.Context, base string) (map[string]float64, error)
+}
+
+// HTTPRateProvider reads the "latest rates" endpoint shared by
+// exchangerate.host, Open Exchange Rates and most of their clones:
+// GET {baseURL}/latest?base=XXX answering {"base": "XXX", "rates": {...}}.
+type HTTPRateProvider struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+func NewHTTPRateProvider(baseURL, apiKey string, client *http.Client) *HTTPRateProvider {
+	if baseURL == "" {
+		baseURL = "https://api.exchangerate.host"
+	}
+	return &HTTPRateProvider{baseURL: strings.TrimSuffix(baseURL, "/"), apiKey: apiKey, client: client}
+}
+
+func (p *HTTPRateProvider) Name() string {
+	return ProviderHTTP
+}
+
+type latestRatesResponse struct {
+	Base  string             `json:"base"`
+	Rates map[string]float64 `json:"rates"`
+	Error interface{}        `json:"error,omitempty"`
+}
+
+func (p *HTTPRateProvider) Latest(ctx context.Context, base string) (map[string]float64, error) {
+	query := url.Values{"base": {base}}
+	if p.apiKey != "" {
+		query.Set("access_key", p.apiKey)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/latest?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("exchange rate provider returned status %d", resp.StatusCode)
+	}
+
+	var result latestRatesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode exchange rates: %w", err)
+	}
+	if result.Error != nil {
+		return nil, fmt.Errorf("exchange rate provider returned an error: %v", result.Error)
+	}
+	if result.Base != "" && Normalize(result.Base) != base {
+		return nil, fmt.Errorf("exchange rate provider answered for base %s, not %s", result.Base, base)
+	}
+	if len(result.Rates) == 0 {
+		return nil, fmt.Errorf("exchange rate provider returned no rates")
+	}
+
+	return result.Rates, nil
+}
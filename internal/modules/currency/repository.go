@@ -0,0 +1,77 @@
+package currency
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository interface {
+	ListCityCurrencies(ctx context.Context, activeOnly bool) ([]*models.CityCurrency, error)
+	GetCityCurrency(ctx context.Context, id string) (*models.CityCurrency, error)
+	CreateCityCurrency(ctx context.Context, city *models.CityCurrency) error
+	UpdateCityCurrency(ctx context.Context, city *models.CityCurrency) error
+	DeleteCityCurrency(ctx context.Context, id string) error
+
+	ListRates(ctx context.Context, base string) ([]*models.ExchangeRate, error)
+	UpsertRates(ctx context.Context, rates []*models.ExchangeRate) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) ListCityCurrencies(ctx context.Context, activeOnly bool) ([]*models.CityCurrency, error) {
+	var cities []*models.CityCurrency
+
+	db := r.db.WithContext(ctx)
+	if activeOnly {
+		db = db.Where("is_active = ?", true)
+	}
+
+	err := db.Order("city ASC").Find(&cities).Error
+	return cities, err
+}
+
+func (r *repository) GetCityCurrency(ctx context.Context, id string) (*models.CityCurrency, error) {
+	var city models.CityCurrency
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&city).Error
+	return &city, err
+}
+
+func (r *repository) CreateCityCurrency(ctx context.Context, city *models.CityCurrency) error {
+	return r.db.WithContext(ctx).Create(city).Error
+}
+
+func (r *repository) UpdateCityCurrency(ctx context.Context, city *models.CityCurrency) error {
+	return r.db.WithContext(ctx).Save(city).Error
+}
+
+func (r *repository) DeleteCityCurrency(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.CityCurrency{}).Error
+}
+
+func (r *repository) ListRates(ctx context.Context, base string) ([]*models.ExchangeRate, error) {
+	var rates []*models.ExchangeRate
+	err := r.db.WithContext(ctx).
+		Where("base_currency = ?", base).
+		Order("currency ASC").
+		Find(&rates).Error
+	return rates, err
+}
+
+func (r *repository) UpsertRates(ctx context.Context, rates []*models.ExchangeRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "base_currency"}, {Name: "currency"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "source", "updated_by", "updated_at"}),
+	}).Create(&rates).Error
+}
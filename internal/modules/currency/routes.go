@@ -0,0 +1,28 @@
+package currency
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/middleware"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
+	currencies := router.Group("/currencies")
+	{
+		currencies.GET("", handler.ListCurrencies)
+		currencies.GET("/resolve", handler.ResolveCurrency)
+		currencies.GET("/convert", handler.ConvertAmount)
+	}
+
+	admin := currencies.Group("/admin", authMiddleware, middleware.RequireAdmin())
+	{
+		admin.GET("/cities", handler.ListCityCurrencies)
+		admin.POST("/cities", handler.CreateCityCurrency)
+		admin.GET("/cities/:id", handler.GetCityCurrency)
+		admin.PUT("/cities/:id", handler.UpdateCityCurrency)
+		admin.DELETE("/cities/:id", handler.DeleteCityCurrency)
+
+		admin.GET("/rates", handler.ListRates)
+		admin.POST("/rates/refresh", handler.RefreshRates)
+		admin.PUT("/rates/:code", handler.SetRate)
+	}
+}
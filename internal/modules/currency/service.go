@@ -0,0 +1,506 @@
+package currency
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/currency/dto"
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/services/health"
+	"github.com/umar5678/go-backend/internal/utils/location"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+const (
+	citiesCacheKey      = "currency:cities"
+	ratesCacheKeyPrefix = "currency:rates:"
+	lookupCacheTTL      = 10 * time.Minute
+)
+
+// Conversion is an amount moved from one currency into another. Rate is units
+// of Currency per unit of OriginalCurrency, and is 1 when nothing changed.
+type Conversion struct {
+	Amount           float64
+	Currency         string
+	OriginalAmount   float64
+	OriginalCurrency string
+	Rate             float64
+}
+
+// Converted reports whether the amount actually changed currency.
+func (c *Conversion) Converted() bool {
+	return c.Currency != c.OriginalCurrency
+}
+
+type Service interface {
+	DefaultCurrency() string
+	ForLocation(ctx context.Context, lat, lon float64) string
+	Convert(ctx context.Context, amount float64, from, to string) (*Conversion, error)
+
+	ListCurrencies(ctx context.Context) (*dto.CurrenciesResponse, error)
+	ResolveCurrency(ctx context.Context, req dto.ResolveCurrencyRequest) (*dto.ResolvedCurrencyResponse, error)
+	ConvertAmount(ctx context.Context, req dto.ConvertRequest) (*dto.ConversionResponse, error)
+
+	ListCityCurrencies(ctx context.Context) ([]*dto.CityCurrencyResponse, error)
+	GetCityCurrency(ctx context.Context, id string) (*dto.CityCurrencyResponse, error)
+	CreateCityCurrency(ctx context.Context, req dto.CreateCityCurrencyRequest) (*dto.CityCurrencyResponse, error)
+	UpdateCityCurrency(ctx context.Context, id string, req dto.UpdateCityCurrencyRequest) (*dto.CityCurrencyResponse, error)
+	DeleteCityCurrency(ctx context.Context, id string) error
+
+	ListRates(ctx context.Context) ([]*dto.ExchangeRateResponse, error)
+	SetRate(ctx context.Context, adminID, code string, req dto.SetRateRequest) (*dto.ExchangeRateResponse, error)
+	RefreshRates(ctx context.Context) (*dto.RateRefreshResponse, error)
+	HasRateProvider() bool
+}
+
+type service struct {
+	repo            Repository
+	defaultCurrency string
+	provider        RateProvider
+}
+
+func NewService(repo Repository, cfg config.CurrencyConfig) Service {
+	var provider RateProvider
+	switch cfg.RateProvider {
+	case ProviderHTTP:
+		provider = NewHTTPRateProvider(cfg.RateAPIURL, cfg.RateAPIKey, &http.Client{Timeout: cfg.Timeout})
+	}
+
+	defaultCurrency := Normalize(cfg.Default)
+	if !IsSupported(defaultCurrency) {
+		logger.Warn("default currency is not supported, using INR", "currency", cfg.Default)
+		defaultCurrency = "INR"
+	}
+
+	return &service{repo: repo, defaultCurrency: defaultCurrency, provider: provider}
+}
+
+func (s *service) DefaultCurrency() string {
+	return s.defaultCurrency
+}
+
+func (s *service) HasRateProvider() bool {
+	return s.provider != nil
+}
+
+// ForLocation returns the currency of the city a coordinate falls in, or the
+// default when it is in none of them. Pricing never fails on a lookup error.
+func (s *service) ForLocation(ctx context.Context, lat, lon float64) string {
+	if city := s.cityAt(ctx, lat, lon); city != nil {
+		return city.Currency
+	}
+	return s.defaultCurrency
+}
+
+// Convert moves an amount between currencies through the default currency
+// and rounds the result to the target currency's minor units.
+func (s *service) Convert(ctx context.Context, amount float64, from, to string) (*Conversion, error) {
+	from, to = Normalize(from), Normalize(to)
+	if from == "" {
+		from = s.defaultCurrency
+	}
+	if to == "" {
+		to = s.defaultCurrency
+	}
+
+	if from == to {
+		return &Conversion{Amount: amount, Currency: to, OriginalAmount: amount, OriginalCurrency: from, Rate: 1}, nil
+	}
+	if !IsSupported(from) {
+		return nil, response.BadRequest("Unsupported currency: " + from)
+	}
+	if !IsSupported(to) {
+		return nil, response.BadRequest("Unsupported currency: " + to)
+	}
+
+	rates := s.rates(ctx)
+	fromRate, ok := rates.rate(from, s.defaultCurrency)
+	if !ok {
+		return nil, response.ServiceUnavailable("No exchange rate available for " + from)
+	}
+	toRate, ok := rates.rate(to, s.defaultCurrency)
+	if !ok {
+		return nil, response.ServiceUnavailable("No exchange rate available for " + to)
+	}
+
+	rate := toRate / fromRate
+	return &Conversion{
+		Amount:           Round(amount*rate, to),
+		Currency:         to,
+		OriginalAmount:   amount,
+		OriginalCurrency: from,
+		Rate:             rate,
+	}, nil
+}
+
+func (s *service) ListCurrencies(ctx context.Context) (*dto.CurrenciesResponse, error) {
+	rates := s.rates(ctx)
+
+	resp := &dto.CurrenciesResponse{Default: s.defaultCurrency}
+	for _, info := range Supported() {
+		item := dto.CurrencyResponse{
+			Code:       info.Code,
+			Name:       info.Name,
+			Symbol:     info.Symbol,
+			MinorUnits: info.MinorUnits,
+			IsDefault:  info.Code == s.defaultCurrency,
+		}
+		if rate, ok := rates.rate(info.Code, s.defaultCurrency); ok {
+			item.Rate = &rate
+			if stored, ok := rates[info.Code]; ok {
+				updatedAt := stored.UpdatedAt
+				item.RateUpdatedAt = &updatedAt
+			}
+		}
+		resp.Currencies = append(resp.Currencies, item)
+	}
+
+	return resp, nil
+}
+
+func (s *service) ResolveCurrency(ctx context.Context, req dto.ResolveCurrencyRequest) (*dto.ResolvedCurrencyResponse, error) {
+	code := s.defaultCurrency
+	resp := &dto.ResolvedCurrencyResponse{}
+	if city := s.cityAt(ctx, req.Lat, req.Lon); city != nil {
+		code = city.Currency
+		resp.City = city.City
+	}
+
+	info, _ := Lookup(code)
+	resp.Currency = info.Code
+	resp.Name = info.Name
+	resp.Symbol = info.Symbol
+	resp.MinorUnits = info.MinorUnits
+	return resp, nil
+}
+
+func (s *service) ConvertAmount(ctx context.Context, req dto.ConvertRequest) (*dto.ConversionResponse, error) {
+	conversion, err := s.Convert(ctx, req.Amount, req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.ConversionResponse{
+		Amount:          conversion.OriginalAmount,
+		From:            conversion.OriginalCurrency,
+		ConvertedAmount: conversion.Amount,
+		To:              conversion.Currency,
+		Rate:            conversion.Rate,
+	}, nil
+}
+
+func (s *service) ListCityCurrencies(ctx context.Context) ([]*dto.CityCurrencyResponse, error) {
+	cities, err := s.repo.ListCityCurrencies(ctx, false)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to list city currencies", err)
+	}
+	return dto.ToCityCurrencyResponses(cities), nil
+}
+
+func (s *service) GetCityCurrency(ctx context.Context, id string) (*dto.CityCurrencyResponse, error) {
+	city, err := s.getCityCurrency(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToCityCurrencyResponse(city), nil
+}
+
+func (s *service) CreateCityCurrency(ctx context.Context, req dto.CreateCityCurrencyRequest) (*dto.CityCurrencyResponse, error) {
+	code := Normalize(req.Currency)
+	if !IsSupported(code) {
+		return nil, response.BadRequest("Unsupported currency: " + code)
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	city := &models.CityCurrency{
+		City:      strings.TrimSpace(req.City),
+		Currency:  code,
+		CenterLat: req.CenterLat,
+		CenterLon: req.CenterLon,
+		RadiusKm:  req.RadiusKm,
+		IsActive:  isActive,
+	}
+
+	if err := s.repo.CreateCityCurrency(ctx, city); err != nil {
+		return nil, response.InternalServerError("Failed to create city currency", err)
+	}
+	// The column defaults to active, so an inactive city needs a second write.
+	if !isActive {
+		city.IsActive = false
+		if err := s.repo.UpdateCityCurrency(ctx, city); err != nil {
+			return nil, response.InternalServerError("Failed to create city currency", err)
+		}
+	}
+
+	s.invalidateCities(ctx)
+	logger.Info("city currency created", "id", city.ID, "city", city.City, "currency", city.Currency)
+
+	return dto.ToCityCurrencyResponse(city), nil
+}
+
+func (s *service) UpdateCityCurrency(ctx context.Context, id string, req dto.UpdateCityCurrencyRequest) (*dto.CityCurrencyResponse, error) {
+	city, err := s.getCityCurrency(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.City != nil {
+		city.City = strings.TrimSpace(*req.City)
+	}
+	if req.Currency != nil {
+		code := Normalize(*req.Currency)
+		if !IsSupported(code) {
+			return nil, response.BadRequest("Unsupported currency: " + code)
+		}
+		city.Currency = code
+	}
+	if req.CenterLat != nil {
+		city.CenterLat = *req.CenterLat
+	}
+	if req.CenterLon != nil {
+		city.CenterLon = *req.CenterLon
+	}
+	if req.RadiusKm != nil {
+		city.RadiusKm = *req.RadiusKm
+	}
+	if req.IsActive != nil {
+		city.IsActive = *req.IsActive
+	}
+
+	if err := s.repo.UpdateCityCurrency(ctx, city); err != nil {
+		return nil, response.InternalServerError("Failed to update city currency", err)
+	}
+
+	s.invalidateCities(ctx)
+	logger.Info("city currency updated", "id", city.ID, "city", city.City, "currency", city.Currency)
+
+	return dto.ToCityCurrencyResponse(city), nil
+}
+
+func (s *service) DeleteCityCurrency(ctx context.Context, id string) error {
+	city, err := s.getCityCurrency(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteCityCurrency(ctx, city.ID); err != nil {
+		return response.InternalServerError("Failed to delete city currency", err)
+	}
+
+	s.invalidateCities(ctx)
+	logger.Info("city currency deleted", "id", city.ID, "city", city.City)
+
+	return nil
+}
+
+func (s *service) ListRates(ctx context.Context) ([]*dto.ExchangeRateResponse, error) {
+	rates, err := s.repo.ListRates(ctx, s.defaultCurrency)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to list exchange rates", err)
+	}
+	return dto.ToExchangeRateResponses(rates), nil
+}
+
+// SetRate pins a rate by hand. With a provider configured it holds until the
+// next refresh.
+func (s *service) SetRate(ctx context.Context, adminID, code string, req dto.SetRateRequest) (*dto.ExchangeRateResponse, error) {
+	code = Normalize(code)
+	if !IsSupported(code) {
+		return nil, response.BadRequest("Unsupported currency: " + code)
+	}
+	if code == s.defaultCurrency {
+		return nil, response.BadRequest("The default currency always has a rate of 1")
+	}
+
+	rate := &models.ExchangeRate{
+		BaseCurrency: s.defaultCurrency,
+		Currency:     code,
+		Rate:         req.Rate,
+		Source:       models.ExchangeRateSourceManual,
+		UpdatedBy:    &adminID,
+		UpdatedAt:    time.Now(),
+	}
+	if err := s.repo.UpsertRates(ctx, []*models.ExchangeRate{rate}); err != nil {
+		return nil, response.InternalServerError("Failed to save exchange rate", err)
+	}
+
+	s.invalidateRates(ctx)
+	logger.Info("exchange rate set", "base", s.defaultCurrency, "currency", code, "rate", req.Rate, "adminID", adminID)
+
+	return dto.ToExchangeRateResponse(rate), nil
+}
+
+// RefreshRates pulls the latest rates for every supported currency from the
+// configured provider.
+func (s *service) RefreshRates(ctx context.Context) (*dto.RateRefreshResponse, error) {
+	if s.provider == nil {
+		return nil, response.ServiceUnavailable("Exchange rate provider is not configured")
+	}
+
+	latest, err := s.provider.Latest(ctx, s.defaultCurrency)
+	if err != nil {
+		logger.Error("failed to fetch exchange rates", "error", err, "provider", s.provider.Name())
+		return nil, response.ServiceUnavailable("Exchange rate provider is unavailable")
+	}
+
+	now := time.Now()
+	var rates []*models.ExchangeRate
+	for code, value := range latest {
+		code = Normalize(code)
+		if code == s.defaultCurrency || !IsSupported(code) || value <= 0 {
+			continue
+		}
+		rates = append(rates, &models.ExchangeRate{
+			BaseCurrency: s.defaultCurrency,
+			Currency:     code,
+			Rate:         value,
+			Source:       s.provider.Name(),
+			UpdatedAt:    now,
+		})
+	}
+
+	if err := s.repo.UpsertRates(ctx, rates); err != nil {
+		return nil, response.InternalServerError("Failed to save exchange rates", err)
+	}
+
+	s.invalidateRates(ctx)
+	logger.Info("exchange rates refreshed", "provider", s.provider.Name(), "base", s.defaultCurrency, "updated", len(rates))
+
+	return &dto.RateRefreshResponse{Provider: s.provider.Name(), Updated: len(rates), Refreshed: now}, nil
+}
+
+func (s *service) getCityCurrency(ctx context.Context, id string) (*models.CityCurrency, error) {
+	city, err := s.repo.GetCityCurrency(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("City currency")
+		}
+		return nil, response.InternalServerError("Failed to load city currency", err)
+	}
+	return city, nil
+}
+
+// cityAt picks the smallest active city circle containing the coordinate.
+func (s *service) cityAt(ctx context.Context, lat, lon float64) *models.CityCurrency {
+	var cities []*models.CityCurrency
+	if err := cache.GetJSON(ctx, citiesCacheKey, &cities); err != nil {
+		cities, err = s.repo.ListCityCurrencies(ctx, true)
+		if err != nil {
+			logger.Error("failed to load city currencies", "error", err)
+			return nil
+		}
+		cache.SetJSON(ctx, citiesCacheKey, cities, lookupCacheTTL)
+	}
+
+	var best *models.CityCurrency
+	for _, city := range cities {
+		if location.HaversineDistance(city.CenterLat, city.CenterLon, lat, lon) > city.RadiusKm {
+			continue
+		}
+		if best == nil || city.RadiusKm < best.RadiusKm {
+			best = city
+		}
+	}
+	return best
+}
+
+type rateTable map[string]models.ExchangeRate
+
+func (t rateTable) rate(code, base string) (float64, bool) {
+	if code == base {
+		return 1, true
+	}
+	stored, ok := t[code]
+	if !ok || stored.Rate <= 0 {
+		return 0, false
+	}
+	return stored.Rate, true
+}
+
+func (s *service) rates(ctx context.Context) rateTable {
+	key := ratesCacheKeyPrefix + s.defaultCurrency
+
+	table := rateTable{}
+	if err := cache.GetJSON(ctx, key, &table); err == nil {
+		return table
+	}
+
+	rates, err := s.repo.ListRates(ctx, s.defaultCurrency)
+	if err != nil {
+		logger.Error("failed to load exchange rates", "error", err)
+		return table
+	}
+	for _, rate := range rates {
+		table[rate.Currency] = *rate
+	}
+	cache.SetJSON(ctx, key, table, lookupCacheTTL)
+	return table
+}
+
+func (s *service) invalidateCities(ctx context.Context) {
+	cache.Delete(ctx, citiesCacheKey)
+}
+
+func (s *service) invalidateRates(ctx context.Context) {
+	cache.Delete(ctx, ratesCacheKeyPrefix+s.defaultCurrency)
+}
+
+// RateRefresher pulls exchange rates from the provider on a fixed interval.
+type RateRefresher struct {
+	service  Service
+	interval time.Duration
+}
+
+func NewRateRefresher(service Service, cfg config.CurrencyConfig) *RateRefresher {
+	interval := cfg.RefreshInterval
+	if interval <= 0 {
+		interval = 6 * time.Hour
+	}
+	return &RateRefresher{service: service, interval: interval}
+}
+
+// Start refreshes once straight away so conversions work from boot, then on
+// every tick. It does nothing without a provider.
+func (w *RateRefresher) Start(ctx context.Context) {
+	if !w.service.HasRateProvider() {
+		return
+	}
+
+	heartbeat := health.NewHeartbeat("exchange_rate_refresh", w.interval)
+	refresh := func() {
+		runCtx, cancel := context.WithTimeout(ctx, time.Minute)
+		defer cancel()
+		if _, err := w.service.RefreshRates(runCtx); err != nil {
+			logger.Error("exchange rate refresh failed", "error", err)
+		}
+		heartbeat.Beat()
+	}
+
+	go func() {
+		refresh()
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				refresh()
+			}
+		}
+	}()
+
+	logger.Info("exchange rate refresher started", "interval", w.interval)
+}
@@ -0,0 +1,45 @@
+package disputes
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/audit"
+)
+
+func (s *service) SetAuditLogger(auditLogger audit.Recorder) {
+	s.auditLogger = auditLogger
+}
+
+func (s *service) recordResolution(ctx context.Context, adminID string, before, after *models.Dispute, note string) {
+	if s.auditLogger == nil {
+		return
+	}
+	s.auditLogger.Record(ctx, audit.Entry{
+		ActorID:    adminID,
+		ActorRole:  string(models.RoleAdmin),
+		Action:     "dispute.resolve",
+		EntityType: "dispute",
+		EntityID:   after.ID,
+		Before:     disputeAuditSnapshot(before),
+		After:      disputeAuditSnapshot(after),
+		Reason:     note,
+	})
+}
+
+func disputeAuditSnapshot(d *models.Dispute) map[string]interface{} {
+	snapshot := map[string]interface{}{
+		"status":       d.Status,
+		"subjectType":  d.SubjectType,
+		"subjectId":    d.SubjectID,
+		"amountPaid":   d.AmountPaid,
+		"refundAmount": d.RefundAmount,
+	}
+	if d.Outcome != nil {
+		snapshot["outcome"] = *d.Outcome
+	}
+	if d.RefundTxnID != nil {
+		snapshot["refundTxnId"] = *d.RefundTxnID
+	}
+	return snapshot
+}
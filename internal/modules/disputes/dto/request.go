@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"errors"
+	"strings"
+)
+
+type OpenDisputeRequest struct {
+	SubjectType string `json:"subjectType" binding:"required,oneof=ride service_order"`
+	SubjectID   string `json:"subjectId" binding:"required,uuid"`
+	Reason      string `json:"reason" binding:"required,oneof=overcharged wrong_route service_not_provided poor_quality damage misconduct other"`
+	Description string `json:"description" binding:"required,min=10,max=2000"`
+	// EvidenceMediaIDs are dispute uploads from the media module that are not
+	// attached to anything yet.
+	EvidenceMediaIDs []string `json:"evidenceMediaIds" binding:"omitempty,max=10,dive,uuid"`
+}
+
+func (r *OpenDisputeRequest) Validate() error {
+	if strings.TrimSpace(r.Description) == "" {
+		return errors.New("description is required")
+	}
+	return nil
+}
+
+type RespondDisputeRequest struct {
+	Response         string   `json:"response" binding:"required,min=3,max=2000"`
+	EvidenceMediaIDs []string `json:"evidenceMediaIds" binding:"omitempty,max=10,dive,uuid"`
+}
+
+// ResolveDisputeRequest closes a dispute. RefundAmount is required for a
+// partial refund; a full refund returns everything the customer paid.
+type ResolveDisputeRequest struct {
+	Outcome      string   `json:"outcome" binding:"required,oneof=refund partial_refund rejected"`
+	RefundAmount *float64 `json:"refundAmount" binding:"omitempty,gt=0"`
+	Note         string   `json:"note" binding:"required,max=2000"`
+}
+
+func (r *ResolveDisputeRequest) Validate() error {
+	if r.Outcome == "partial_refund" && r.RefundAmount == nil {
+		return errors.New("refundAmount is required for a partial refund")
+	}
+	if r.Outcome != "partial_refund" && r.RefundAmount != nil {
+		return errors.New("refundAmount only applies to a partial refund")
+	}
+	return nil
+}
+
+type ListDisputesRequest struct {
+	Status      string `form:"status" binding:"omitempty,oneof=open responded resolved withdrawn"`
+	SubjectType string `form:"subjectType" binding:"omitempty,oneof=ride service_order"`
+	Page        int    `form:"page" binding:"omitempty,min=1"`
+	Limit       int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (r *ListDisputesRequest) SetDefaults() {
+	if r.Page <= 0 {
+		r.Page = 1
+	}
+	if r.Limit <= 0 {
+		r.Limit = 20
+	}
+	if r.Limit > 100 {
+		r.Limit = 100
+	}
+}
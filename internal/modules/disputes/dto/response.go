@@ -0,0 +1,93 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+type DisputeResponse struct {
+	ID             string         `json:"id"`
+	SubjectType    string         `json:"subjectType"`
+	SubjectID      string         `json:"subjectId"`
+	Reference      string         `json:"reference"`
+	OpenedBy       string         `json:"openedBy"`
+	RespondentID   *string        `json:"respondentId,omitempty"`
+	Reason         string         `json:"reason"`
+	Description    string         `json:"description"`
+	AmountPaid     float64        `json:"amountPaid"`
+	Currency       string         `json:"currency"`
+	Status         string         `json:"status"`
+	Response       string         `json:"response,omitempty"`
+	RespondedAt    *time.Time     `json:"respondedAt,omitempty"`
+	Outcome        string         `json:"outcome,omitempty"`
+	RefundAmount   float64        `json:"refundAmount"`
+	ResolutionNote string         `json:"resolutionNote,omitempty"`
+	ResolvedAt     *time.Time     `json:"resolvedAt,omitempty"`
+	WithdrawnAt    *time.Time     `json:"withdrawnAt,omitempty"`
+	Evidence       []EvidenceItem `json:"evidence,omitempty"`
+	CreatedAt      time.Time      `json:"createdAt"`
+	UpdatedAt      time.Time      `json:"updatedAt"`
+}
+
+type EvidenceItem struct {
+	ID           string  `json:"id"`
+	FileName     string  `json:"fileName"`
+	ContentType  string  `json:"contentType"`
+	URL          string  `json:"url"`
+	ThumbnailURL *string `json:"thumbnailUrl,omitempty"`
+	// UploadedBy is "customer", "respondent" or "support".
+	UploadedBy string    `json:"uploadedBy"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+func ToDisputeResponse(d *models.Dispute) *DisputeResponse {
+	resp := &DisputeResponse{
+		ID:             d.ID,
+		SubjectType:    d.SubjectType,
+		SubjectID:      d.SubjectID,
+		Reference:      d.Reference,
+		OpenedBy:       d.OpenedBy,
+		RespondentID:   d.RespondentID,
+		Reason:         d.Reason,
+		Description:    d.Description,
+		AmountPaid:     d.AmountPaid,
+		Currency:       d.Currency,
+		Status:         string(d.Status),
+		Response:       d.Response,
+		RespondedAt:    d.RespondedAt,
+		RefundAmount:   d.RefundAmount,
+		ResolutionNote: d.ResolutionNote,
+		ResolvedAt:     d.ResolvedAt,
+		WithdrawnAt:    d.WithdrawnAt,
+		CreatedAt:      d.CreatedAt,
+		UpdatedAt:      d.UpdatedAt,
+	}
+	if d.Outcome != nil {
+		resp.Outcome = string(*d.Outcome)
+	}
+	return resp
+}
+
+func ToEvidenceItems(d *models.Dispute, media []*models.Media) []EvidenceItem {
+	items := make([]EvidenceItem, 0, len(media))
+	for _, m := range media {
+		uploadedBy := "support"
+		switch {
+		case m.UploaderID == d.OpenedBy:
+			uploadedBy = "customer"
+		case d.RespondentID != nil && m.UploaderID == *d.RespondentID:
+			uploadedBy = "respondent"
+		}
+		items = append(items, EvidenceItem{
+			ID:           m.ID,
+			FileName:     m.FileName,
+			ContentType:  m.ContentType,
+			URL:          m.URL,
+			ThumbnailURL: m.ThumbnailURL,
+			UploadedBy:   uploadedBy,
+			CreatedAt:    m.CreatedAt,
+		})
+	}
+	return items
+}
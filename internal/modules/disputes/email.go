@@ -0,0 +1,52 @@
+package disputes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/emails"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+func (s *service) SetMailer(mailer emails.Mailer) {
+	s.mailer = mailer
+}
+
+// emailParticipant sends the same update a participant gets in the app by
+// email, so they hear about it without opening the app.
+func (s *service) emailParticipant(dispute *models.Dispute, userID, message string) {
+	if s.mailer == nil || !s.mailer.Enabled() {
+		return
+	}
+
+	data := map[string]interface{}{
+		"Reference": dispute.Reference,
+		"Message":   message,
+		"Status":    strings.ReplaceAll(string(dispute.Status), "_", " "),
+	}
+	if dispute.Outcome != nil {
+		data["Outcome"] = strings.ReplaceAll(string(*dispute.Outcome), "_", " ")
+	}
+	if dispute.RefundAmount > 0 {
+		data["RefundAmount"] = strings.TrimSpace(fmt.Sprintf("%.2f %s", dispute.RefundAmount, dispute.Currency))
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		_, err := s.mailer.SendToUser(ctx, userID, emails.SendRequest{
+			Template:      emails.TemplateDisputeUpdate,
+			Data:          data,
+			Category:      models.NotificationCategoryTransactional,
+			ReferenceType: "dispute",
+			ReferenceID:   dispute.ID,
+		})
+		if err != nil && !errors.Is(err, emails.ErrOptedOut) && !errors.Is(err, emails.ErrNoAddress) {
+			logger.Error("failed to email dispute update", "error", err, "disputeID", dispute.ID, "userID", userID)
+		}
+	}()
+}
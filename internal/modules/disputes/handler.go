@@ -0,0 +1,179 @@
+package disputes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/modules/disputes/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// OpenDispute godoc
+// @Summary Dispute a completed ride or service order
+// @Description Evidence must be uploaded through the media module with entity type "dispute" first
+// @Tags disputes
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.OpenDisputeRequest true "Dispute details"
+// @Success 201 {object} response.Response{data=dto.DisputeResponse}
+// @Router /disputes [post]
+func (h *Handler) OpenDispute(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req dto.OpenDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	dispute, err := h.service.OpenDispute(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dispute, "Dispute opened successfully")
+}
+
+// ListDisputes godoc
+// @Summary List disputes
+// @Description Customers, drivers and providers see disputes they are part of, admins see all disputes
+// @Tags disputes
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "Filter by status"
+// @Param subjectType query string false "Filter by subject type (ride, service_order)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Response{data=[]dto.DisputeResponse}
+// @Router /disputes [get]
+func (h *Handler) ListDisputes(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+
+	filterUserID := ""
+	if role != "admin" {
+		filterUserID = userID.(string)
+	}
+
+	var req dto.ListDisputesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+	req.SetDefaults()
+
+	disputes, total, err := h.service.ListDisputes(c.Request.Context(), filterUserID, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pagination := response.NewPaginationMeta(total, req.Page, req.Limit)
+	response.Paginated(c, disputes, pagination, "Disputes retrieved successfully")
+}
+
+// GetDispute godoc
+// @Summary Get dispute details with evidence
+// @Tags disputes
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Dispute ID"
+// @Success 200 {object} response.Response{data=dto.DisputeResponse}
+// @Router /disputes/{id} [get]
+func (h *Handler) GetDispute(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+
+	dispute, err := h.service.GetDispute(c.Request.Context(), userID.(string), c.Param("id"), role == "admin")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dispute, "Dispute retrieved successfully")
+}
+
+// RespondToDispute godoc
+// @Summary Respond to a dispute as the driver or provider
+// @Tags disputes
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Dispute ID"
+// @Param request body dto.RespondDisputeRequest true "Response"
+// @Success 200 {object} response.Response{data=dto.DisputeResponse}
+// @Router /disputes/{id}/respond [post]
+func (h *Handler) RespondToDispute(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req dto.RespondDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	dispute, err := h.service.RespondToDispute(c.Request.Context(), userID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dispute, "Response submitted successfully")
+}
+
+// WithdrawDispute godoc
+// @Summary Withdraw an open dispute
+// @Tags disputes
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Dispute ID"
+// @Success 200 {object} response.Response{data=dto.DisputeResponse}
+// @Router /disputes/{id}/withdraw [post]
+func (h *Handler) WithdrawDispute(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	dispute, err := h.service.WithdrawDispute(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dispute, "Dispute withdrawn successfully")
+}
+
+// ResolveDispute godoc
+// @Summary Resolve a dispute (admin)
+// @Description Refund outcomes credit the customer's wallet
+// @Tags disputes
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Dispute ID"
+// @Param request body dto.ResolveDisputeRequest true "Resolution"
+// @Success 200 {object} response.Response{data=dto.DisputeResponse}
+// @Router /disputes/{id}/resolve [post]
+func (h *Handler) ResolveDispute(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	var req dto.ResolveDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	dispute, err := h.service.ResolveDispute(c.Request.Context(), adminID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, dispute, "Dispute resolved successfully")
+}
@@ -0,0 +1,205 @@
+package disputes
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Create(ctx context.Context, d *models.Dispute) error
+	FindByID(ctx context.Context, id string) (*models.Dispute, error)
+	FindActiveBySubject(ctx context.Context, subjectType, subjectID string) (*models.Dispute, error)
+	Update(ctx context.Context, d *models.Dispute) error
+	// ClaimResolution writes d's resolution only if the dispute is still
+	// open, so it is resolved (and refunded) once.
+	ClaimResolution(ctx context.Context, d *models.Dispute) (bool, error)
+	ReleaseResolution(ctx context.Context, disputeID string, status models.DisputeStatus) error
+	SetRefundTxn(ctx context.Context, disputeID, txnID string) error
+	List(ctx context.Context, userID, status, subjectType string, page, limit int) ([]*models.Dispute, int64, error)
+
+	FindRide(ctx context.Context, id string) (*models.Ride, error)
+	FindServiceOrder(ctx context.Context, id string) (*models.ServiceOrderNew, error)
+	FindProviderUserID(ctx context.Context, providerID string) (string, error)
+
+	// CountAttachableEvidence counts the given media that are ready dispute
+	// uploads of uploaderID not yet attached to a dispute.
+	CountAttachableEvidence(ctx context.Context, uploaderID string, mediaIDs []string) (int64, error)
+	AttachEvidence(ctx context.Context, disputeID, uploaderID string, mediaIDs []string) error
+	ListEvidence(ctx context.Context, disputeID string) ([]*models.Media, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Create(ctx context.Context, d *models.Dispute) error {
+	return r.db.WithContext(ctx).Create(d).Error
+}
+
+func (r *repository) FindByID(ctx context.Context, id string) (*models.Dispute, error) {
+	var d models.Dispute
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&d).Error
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *repository) FindActiveBySubject(ctx context.Context, subjectType, subjectID string) (*models.Dispute, error) {
+	var d models.Dispute
+	err := r.db.WithContext(ctx).
+		Where("subject_type = ? AND subject_id = ? AND status <> ?", subjectType, subjectID, models.DisputeStatusWithdrawn).
+		First(&d).Error
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *repository) Update(ctx context.Context, d *models.Dispute) error {
+	return r.db.WithContext(ctx).Save(d).Error
+}
+
+func (r *repository) ClaimResolution(ctx context.Context, d *models.Dispute) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.Dispute{}).
+		Where("id = ? AND status IN ?", d.ID,
+			[]models.DisputeStatus{models.DisputeStatusOpen, models.DisputeStatusResponded}).
+		Updates(map[string]interface{}{
+			"status":          models.DisputeStatusResolved,
+			"outcome":         d.Outcome,
+			"refund_amount":   d.RefundAmount,
+			"resolved_by":     d.ResolvedBy,
+			"resolution_note": d.ResolutionNote,
+			"resolved_at":     d.ResolvedAt,
+			"updated_at":      time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ReleaseResolution reopens a claimed dispute at status when its refund
+// could not be paid.
+func (r *repository) ReleaseResolution(ctx context.Context, disputeID string, status models.DisputeStatus) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Dispute{}).
+		Where("id = ? AND status = ?", disputeID, models.DisputeStatusResolved).
+		Updates(map[string]interface{}{
+			"status":          status,
+			"outcome":         nil,
+			"refund_amount":   0,
+			"resolved_by":     nil,
+			"resolution_note": "",
+			"resolved_at":     nil,
+			"updated_at":      time.Now(),
+		}).Error
+}
+
+func (r *repository) SetRefundTxn(ctx context.Context, disputeID, txnID string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Dispute{}).
+		Where("id = ?", disputeID).
+		Updates(map[string]interface{}{
+			"refund_txn_id": txnID,
+			"updated_at":    time.Now(),
+		}).Error
+}
+
+func (r *repository) List(ctx context.Context, userID, status, subjectType string, page, limit int) ([]*models.Dispute, int64, error) {
+	var disputes []*models.Dispute
+	var total int64
+
+	base := r.db.WithContext(ctx).Model(&models.Dispute{})
+	if userID != "" {
+		base = base.Where("opened_by = ? OR respondent_id = ?", userID, userID)
+	}
+	if status != "" {
+		base = base.Where("status = ?", status)
+	}
+	if subjectType != "" {
+		base = base.Where("subject_type = ?", subjectType)
+	}
+
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count disputes: %w", err)
+	}
+
+	if total == 0 {
+		return []*models.Dispute{}, 0, nil
+	}
+
+	offset := (page - 1) * limit
+	if err := base.
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&disputes).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch disputes: %w", err)
+	}
+
+	return disputes, total, nil
+}
+
+func (r *repository) FindRide(ctx context.Context, id string) (*models.Ride, error) {
+	var ride models.Ride
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&ride).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ride, nil
+}
+
+func (r *repository) FindServiceOrder(ctx context.Context, id string) (*models.ServiceOrderNew, error) {
+	var order models.ServiceOrderNew
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&order).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *repository) FindProviderUserID(ctx context.Context, providerID string) (string, error) {
+	var provider models.ServiceProviderProfile
+	err := r.db.WithContext(ctx).Select("id", "user_id").Where("id = ?", providerID).First(&provider).Error
+	if err != nil {
+		return "", err
+	}
+	return provider.UserID, nil
+}
+
+func (r *repository) attachableEvidence(ctx context.Context, uploaderID string, mediaIDs []string) *gorm.DB {
+	return r.db.WithContext(ctx).
+		Model(&models.Media{}).
+		Where("id IN ? AND uploader_id = ? AND entity_type = ? AND entity_id IS NULL AND status = ?",
+			mediaIDs, uploaderID, models.MediaEntityDispute, models.MediaStatusReady)
+}
+
+func (r *repository) CountAttachableEvidence(ctx context.Context, uploaderID string, mediaIDs []string) (int64, error) {
+	var count int64
+	err := r.attachableEvidence(ctx, uploaderID, mediaIDs).Count(&count).Error
+	return count, err
+}
+
+func (r *repository) AttachEvidence(ctx context.Context, disputeID, uploaderID string, mediaIDs []string) error {
+	return r.attachableEvidence(ctx, uploaderID, mediaIDs).
+		Updates(map[string]interface{}{
+			"entity_id":   disputeID,
+			"attached_at": time.Now(),
+		}).Error
+}
+
+func (r *repository) ListEvidence(ctx context.Context, disputeID string) ([]*models.Media, error) {
+	var media []*models.Media
+	err := r.db.WithContext(ctx).
+		Where("entity_type = ? AND entity_id = ? AND status = ?", models.MediaEntityDispute, disputeID, models.MediaStatusReady).
+		Order("created_at ASC").
+		Find(&media).Error
+	return media, err
+}
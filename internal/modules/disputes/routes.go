@@ -0,0 +1,20 @@
+package disputes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/middleware"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
+	disputes := router.Group("/disputes")
+	disputes.Use(authMiddleware)
+	{
+		disputes.POST("", handler.OpenDispute)
+		disputes.GET("", handler.ListDisputes)
+		disputes.GET("/:id", handler.GetDispute)
+
+		disputes.POST("/:id/respond", handler.RespondToDispute)
+		disputes.POST("/:id/withdraw", handler.WithdrawDispute)
+		disputes.POST("/:id/resolve", middleware.RequireAdmin(), handler.ResolveDispute)
+	}
+}
@@ -0,0 +1,480 @@
+package disputes
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/audit"
+	"github.com/umar5678/go-backend/internal/modules/disputes/dto"
+	"github.com/umar5678/go-backend/internal/modules/emails"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+	notificationsmodule "github.com/umar5678/go-backend/internal/modules/notifications"
+	walletservice "github.com/umar5678/go-backend/internal/modules/wallet"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+	websocketutil "github.com/umar5678/go-backend/internal/websocket/websocketutils"
+	"gorm.io/gorm"
+)
+
+// Customers can only dispute rides and orders completed within this window.
+const disputeWindow = 14 * 24 * time.Hour
+
+type Service interface {
+	OpenDispute(ctx context.Context, userID string, req dto.OpenDisputeRequest) (*dto.DisputeResponse, error)
+	GetDispute(ctx context.Context, userID, disputeID string, isAdmin bool) (*dto.DisputeResponse, error)
+	ListDisputes(ctx context.Context, userID string, req dto.ListDisputesRequest) ([]*dto.DisputeResponse, int64, error)
+
+	RespondToDispute(ctx context.Context, userID, disputeID string, req dto.RespondDisputeRequest) (*dto.DisputeResponse, error)
+	WithdrawDispute(ctx context.Context, userID, disputeID string) (*dto.DisputeResponse, error)
+	ResolveDispute(ctx context.Context, adminID, disputeID string, req dto.ResolveDisputeRequest) (*dto.DisputeResponse, error)
+
+	SetAuditLogger(auditLogger audit.Recorder)
+	SetMailer(mailer emails.Mailer)
+}
+
+type service struct {
+	repo          Repository
+	walletService walletservice.Service
+	eventProducer notificationsmodule.EventProducer
+	auditLogger   audit.Recorder
+	mailer        emails.Mailer
+}
+
+func NewService(repo Repository, walletService walletservice.Service) Service {
+	return NewServiceWithNotifications(repo, walletService, nil)
+}
+
+func NewServiceWithNotifications(repo Repository, walletService walletservice.Service, eventProducer notificationsmodule.EventProducer) Service {
+	return &service{
+		repo:          repo,
+		walletService: walletService,
+		eventProducer: eventProducer,
+	}
+}
+
+func (s *service) OpenDispute(ctx context.Context, userID string, req dto.OpenDisputeRequest) (*dto.DisputeResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	var dispute *models.Dispute
+	var err error
+	switch req.SubjectType {
+	case models.DisputeSubjectRide:
+		dispute, err = s.disputeForRide(ctx, userID, req.SubjectID)
+	case models.DisputeSubjectServiceOrder:
+		dispute, err = s.disputeForServiceOrder(ctx, userID, req.SubjectID)
+	default:
+		return nil, response.BadRequest("Unsupported dispute subject")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if existing, err := s.repo.FindActiveBySubject(ctx, dispute.SubjectType, dispute.SubjectID); err == nil && existing != nil {
+		return nil, response.ConflictError("A dispute has already been opened for this " + subjectLabel(dispute.SubjectType))
+	}
+	if err := s.checkEvidence(ctx, userID, req.EvidenceMediaIDs); err != nil {
+		return nil, err
+	}
+
+	dispute.Reason = req.Reason
+	dispute.Description = strings.TrimSpace(req.Description)
+	dispute.Status = models.DisputeStatusOpen
+
+	if err := s.repo.Create(ctx, dispute); err != nil {
+		if strings.Contains(err.Error(), "idx_disputes_subject") {
+			return nil, response.ConflictError("A dispute has already been opened for this " + subjectLabel(dispute.SubjectType))
+		}
+		logger.Error("failed to create dispute", "error", err, "subjectType", dispute.SubjectType, "subjectID", dispute.SubjectID)
+		return nil, response.InternalServerError("Failed to open dispute", err)
+	}
+	s.attachEvidence(ctx, dispute, userID, req.EvidenceMediaIDs)
+
+	if dispute.RespondentID != nil {
+		s.notifyParticipant(dispute, *dispute.RespondentID, "A customer opened a dispute. You can respond with your side of the story")
+	}
+	websocketutil.BroadcastToRole("admin", websocket.TypeDisputeUpdate, map[string]interface{}{
+		"disputeId":   dispute.ID,
+		"subjectType": dispute.SubjectType,
+		"reference":   dispute.Reference,
+		"reason":      dispute.Reason,
+		"status":      dispute.Status,
+	})
+	s.publishDisputeEvent(notificationsmodule.EventDisputeOpened, dispute, map[string]interface{}{
+		"reason": dispute.Reason,
+	})
+
+	logger.Info("dispute opened",
+		"disputeID", dispute.ID,
+		"subjectType", dispute.SubjectType,
+		"subjectID", dispute.SubjectID,
+		"openedBy", userID,
+	)
+	return s.toResponse(ctx, dispute), nil
+}
+
+func (s *service) disputeForRide(ctx context.Context, userID, rideID string) (*models.Dispute, error) {
+	ride, err := s.repo.FindRide(ctx, rideID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Ride")
+		}
+		return nil, response.InternalServerError("Failed to fetch ride", err)
+	}
+
+	if ride.RiderID != userID {
+		return nil, response.ForbiddenError("You can only dispute your own rides")
+	}
+	if ride.Status != "completed" || ride.DriverID == nil {
+		return nil, response.BadRequest("Only completed rides can be disputed")
+	}
+	if err := checkWindow(ride.CompletedAt); err != nil {
+		return nil, err
+	}
+
+	amountPaid := 0.0
+	switch {
+	case ride.RiderFare != nil:
+		amountPaid = *ride.RiderFare
+	case ride.ActualFare != nil:
+		amountPaid = *ride.ActualFare
+	}
+
+	return &models.Dispute{
+		SubjectType:  models.DisputeSubjectRide,
+		SubjectID:    ride.ID,
+		Reference:    ride.ID,
+		OpenedBy:     userID,
+		RespondentID: ride.DriverID,
+		AmountPaid:   amountPaid,
+		Currency:     ride.Currency,
+	}, nil
+}
+
+func (s *service) disputeForServiceOrder(ctx context.Context, userID, orderID string) (*models.Dispute, error) {
+	order, err := s.repo.FindServiceOrder(ctx, orderID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Order")
+		}
+		return nil, response.InternalServerError("Failed to fetch order", err)
+	}
+
+	if order.CustomerID != userID {
+		return nil, response.ForbiddenError("You can only dispute your own orders")
+	}
+	if order.Status != shared.OrderStatusCompleted {
+		return nil, response.BadRequest("Only completed orders can be disputed")
+	}
+	if err := checkWindow(order.CompletedAt); err != nil {
+		return nil, err
+	}
+
+	dispute := &models.Dispute{
+		SubjectType: models.DisputeSubjectServiceOrder,
+		SubjectID:   order.ID,
+		Reference:   order.OrderNumber,
+		OpenedBy:    userID,
+		AmountPaid:  order.TotalPrice,
+		Currency:    order.Currency,
+	}
+	if order.AssignedProviderID != nil {
+		providerUserID, err := s.repo.FindProviderUserID(ctx, *order.AssignedProviderID)
+		if err != nil {
+			logger.Warn("provider of disputed order not found", "error", err, "orderID", order.ID)
+		} else {
+			dispute.RespondentID = &providerUserID
+		}
+	}
+	return dispute, nil
+}
+
+func checkWindow(completedAt *time.Time) error {
+	if completedAt != nil && time.Since(*completedAt) > disputeWindow {
+		return response.BadRequest(fmt.Sprintf("Disputes must be opened within %d days of completion", int(disputeWindow.Hours()/24)))
+	}
+	return nil
+}
+
+func (s *service) GetDispute(ctx context.Context, userID, disputeID string, isAdmin bool) (*dto.DisputeResponse, error) {
+	dispute, err := s.getDisputeForParticipant(ctx, userID, disputeID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(ctx, dispute), nil
+}
+
+func (s *service) ListDisputes(ctx context.Context, userID string, req dto.ListDisputesRequest) ([]*dto.DisputeResponse, int64, error) {
+	disputes, total, err := s.repo.List(ctx, userID, req.Status, req.SubjectType, req.Page, req.Limit)
+	if err != nil {
+		logger.Error("failed to list disputes", "error", err, "userID", userID)
+		return nil, 0, response.InternalServerError("Failed to fetch disputes", err)
+	}
+
+	result := make([]*dto.DisputeResponse, 0, len(disputes))
+	for _, d := range disputes {
+		result = append(result, dto.ToDisputeResponse(d))
+	}
+	return result, total, nil
+}
+
+func (s *service) RespondToDispute(ctx context.Context, userID, disputeID string, req dto.RespondDisputeRequest) (*dto.DisputeResponse, error) {
+	dispute, err := s.getDisputeForParticipant(ctx, userID, disputeID, false)
+	if err != nil {
+		return nil, err
+	}
+	if dispute.RespondentID == nil || *dispute.RespondentID != userID {
+		return nil, response.ForbiddenError("Only the driver or provider can respond to this dispute")
+	}
+	if dispute.Status != models.DisputeStatusOpen {
+		return nil, response.BadRequest(fmt.Sprintf("Dispute cannot be answered, current status: %s", dispute.Status))
+	}
+	if err := s.checkEvidence(ctx, userID, req.EvidenceMediaIDs); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	dispute.Response = strings.TrimSpace(req.Response)
+	dispute.RespondedAt = &now
+	dispute.Status = models.DisputeStatusResponded
+
+	if err := s.repo.Update(ctx, dispute); err != nil {
+		return nil, response.InternalServerError("Failed to update dispute", err)
+	}
+	s.attachEvidence(ctx, dispute, userID, req.EvidenceMediaIDs)
+
+	s.notifyParticipant(dispute, dispute.OpenedBy, "The "+respondentLabel(dispute.SubjectType)+" responded to your dispute")
+	s.publishDisputeEvent(notificationsmodule.EventDisputeResponded, dispute, nil)
+
+	return s.toResponse(ctx, dispute), nil
+}
+
+func (s *service) WithdrawDispute(ctx context.Context, userID, disputeID string) (*dto.DisputeResponse, error) {
+	dispute, err := s.getDisputeForParticipant(ctx, userID, disputeID, false)
+	if err != nil {
+		return nil, err
+	}
+	if dispute.OpenedBy != userID {
+		return nil, response.ForbiddenError("Only the customer can withdraw this dispute")
+	}
+	if !dispute.IsOpen() {
+		return nil, response.BadRequest("Dispute is already closed")
+	}
+
+	now := time.Now()
+	dispute.Status = models.DisputeStatusWithdrawn
+	dispute.WithdrawnAt = &now
+
+	if err := s.repo.Update(ctx, dispute); err != nil {
+		return nil, response.InternalServerError("Failed to withdraw dispute", err)
+	}
+
+	if dispute.RespondentID != nil {
+		s.notifyParticipant(dispute, *dispute.RespondentID, "The customer withdrew the dispute")
+	}
+	return s.toResponse(ctx, dispute), nil
+}
+
+func (s *service) ResolveDispute(ctx context.Context, adminID, disputeID string, req dto.ResolveDisputeRequest) (*dto.DisputeResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	dispute, err := s.getDisputeForParticipant(ctx, adminID, disputeID, true)
+	if err != nil {
+		return nil, err
+	}
+	if !dispute.IsOpen() {
+		return nil, response.BadRequest(fmt.Sprintf("Dispute cannot be resolved, current status: %s", dispute.Status))
+	}
+
+	outcome := models.DisputeOutcome(req.Outcome)
+	var refundAmount float64
+	switch outcome {
+	case models.DisputeOutcomeRefund:
+		refundAmount = dispute.AmountPaid
+	case models.DisputeOutcomePartialRefund:
+		refundAmount = *req.RefundAmount
+		if refundAmount >= dispute.AmountPaid {
+			return nil, response.BadRequest(fmt.Sprintf("A partial refund must be less than the %.2f paid; use a full refund instead", dispute.AmountPaid))
+		}
+	}
+	if outcome != models.DisputeOutcomeRejected && refundAmount <= 0 {
+		return nil, response.BadRequest("Nothing was paid for this " + subjectLabel(dispute.SubjectType) + " to refund")
+	}
+
+	before := *dispute
+
+	now := time.Now()
+	dispute.Status = models.DisputeStatusResolved
+	dispute.Outcome = &outcome
+	dispute.RefundAmount = refundAmount
+	dispute.ResolvedBy = &adminID
+	dispute.ResolutionNote = req.Note
+	dispute.ResolvedAt = &now
+
+	claimed, err := s.repo.ClaimResolution(ctx, dispute)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to resolve dispute", err)
+	}
+	if !claimed {
+		return nil, response.ConflictError("Dispute has already been resolved")
+	}
+
+	if refundAmount > 0 {
+		metadata := map[string]interface{}{
+			"disputeId":   dispute.ID,
+			"subjectType": dispute.SubjectType,
+			"subjectId":   dispute.SubjectID,
+			"resolvedBy":  adminID,
+		}
+		txn, err := s.walletService.CreditWallet(ctx, dispute.OpenedBy, refundAmount,
+			"dispute_refund", dispute.ID,
+			fmt.Sprintf("Refund for disputed %s %s", subjectLabel(dispute.SubjectType), dispute.Reference), metadata)
+		if err != nil {
+			logger.Error("failed to refund dispute", "error", err, "disputeID", dispute.ID)
+			if releaseErr := s.repo.ReleaseResolution(ctx, dispute.ID, before.Status); releaseErr != nil {
+				logger.Error("failed to reopen dispute after refund failed", "error", releaseErr, "disputeID", dispute.ID)
+			}
+			return nil, response.InternalServerError("Failed to refund dispute", err)
+		}
+		dispute.RefundTxnID = &txn.ID
+		if err := s.repo.SetRefundTxn(ctx, dispute.ID, txn.ID); err != nil {
+			logger.Error("failed to record dispute refund", "error", err, "disputeID", dispute.ID, "refundTxnID", txn.ID)
+		}
+	}
+
+	s.notifyParticipant(dispute, dispute.OpenedBy, "Support resolved your dispute")
+	if dispute.RespondentID != nil {
+		s.notifyParticipant(dispute, *dispute.RespondentID, "Support resolved the dispute")
+	}
+	s.publishDisputeEvent(notificationsmodule.EventDisputeResolved, dispute, map[string]interface{}{
+		"outcome":      outcome,
+		"refundAmount": refundAmount,
+		"resolvedBy":   adminID,
+	})
+	s.recordResolution(ctx, adminID, &before, dispute, req.Note)
+
+	logger.Info("dispute resolved",
+		"disputeID", dispute.ID,
+		"outcome", outcome,
+		"refundAmount", refundAmount,
+		"adminID", adminID,
+	)
+	return s.toResponse(ctx, dispute), nil
+}
+
+func (s *service) getDisputeForParticipant(ctx context.Context, userID, disputeID string, isAdmin bool) (*models.Dispute, error) {
+	if disputeID == "" {
+		return nil, response.BadRequest("Dispute ID is required")
+	}
+
+	dispute, err := s.repo.FindByID(ctx, disputeID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Dispute")
+		}
+		return nil, response.InternalServerError("Failed to fetch dispute", err)
+	}
+
+	isRespondent := dispute.RespondentID != nil && *dispute.RespondentID == userID
+	if !isAdmin && dispute.OpenedBy != userID && !isRespondent {
+		return nil, response.ForbiddenError("You are not part of this dispute")
+	}
+
+	return dispute, nil
+}
+
+// checkEvidence makes sure every media ID is a ready dispute upload of the
+// caller that is not attached yet, before anything is saved.
+func (s *service) checkEvidence(ctx context.Context, userID string, mediaIDs []string) error {
+	if len(mediaIDs) == 0 {
+		return nil
+	}
+	count, err := s.repo.CountAttachableEvidence(ctx, userID, mediaIDs)
+	if err != nil {
+		return response.InternalServerError("Failed to check evidence", err)
+	}
+	if count != int64(len(mediaIDs)) {
+		return response.BadRequest("Evidence must be your own completed dispute uploads that are not attached yet")
+	}
+	return nil
+}
+
+func (s *service) attachEvidence(ctx context.Context, dispute *models.Dispute, userID string, mediaIDs []string) {
+	if len(mediaIDs) == 0 {
+		return
+	}
+	if err := s.repo.AttachEvidence(ctx, dispute.ID, userID, mediaIDs); err != nil {
+		logger.Error("failed to attach dispute evidence", "error", err, "disputeID", dispute.ID)
+	}
+}
+
+func (s *service) toResponse(ctx context.Context, dispute *models.Dispute) *dto.DisputeResponse {
+	resp := dto.ToDisputeResponse(dispute)
+	evidence, err := s.repo.ListEvidence(ctx, dispute.ID)
+	if err != nil {
+		logger.Error("failed to list dispute evidence", "error", err, "disputeID", dispute.ID)
+		return resp
+	}
+	resp.Evidence = dto.ToEvidenceItems(dispute, evidence)
+	return resp
+}
+
+func subjectLabel(subjectType string) string {
+	if subjectType == models.DisputeSubjectRide {
+		return "ride"
+	}
+	return "order"
+}
+
+func respondentLabel(subjectType string) string {
+	if subjectType == models.DisputeSubjectRide {
+		return "driver"
+	}
+	return "provider"
+}
+
+func (s *service) notifyParticipant(dispute *models.Dispute, userID, message string) {
+	websocketutil.SendToUser(userID, websocket.TypeDisputeUpdate, map[string]interface{}{
+		"disputeId":   dispute.ID,
+		"subjectType": dispute.SubjectType,
+		"subjectId":   dispute.SubjectID,
+		"reference":   dispute.Reference,
+		"status":      dispute.Status,
+		"message":     message,
+	})
+	s.emailParticipant(dispute, userID, message)
+}
+
+func (s *service) publishDisputeEvent(eventType notificationsmodule.EventType, dispute *models.Dispute, data map[string]interface{}) {
+	if s.eventProducer == nil {
+		logger.Debug("event producer not available, skipping dispute event", "eventType", eventType, "disputeID", dispute.ID)
+		return
+	}
+
+	payload := map[string]interface{}{
+		"dispute_id":    dispute.ID,
+		"subject_type":  dispute.SubjectType,
+		"subject_id":    dispute.SubjectID,
+		"opened_by":     dispute.OpenedBy,
+		"respondent_id": dispute.RespondentID,
+		"status":        dispute.Status,
+		"timestamp":     time.Now().UTC(),
+	}
+	for k, v := range data {
+		payload[k] = v
+	}
+
+	go func() {
+		if err := s.eventProducer.PublishEventWithKey(context.Background(), eventType, dispute.ID, payload); err != nil {
+			logger.Error("failed to publish dispute event", "error", err, "eventType", eventType, "disputeID", dispute.ID)
+		}
+	}()
+}
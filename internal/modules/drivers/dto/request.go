@@ -1,6 +1,9 @@
 package driverdto
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 type RegisterDriverRequest struct {
 	LicenseNumber string       `json:"licenseNumber" binding:"required,min=5,max=100"`
@@ -114,3 +117,38 @@ type PaymentRequest struct {
 	PaymentMethod string
 	Reference     *string
 }
+
+type SurgeHistoryQuery struct {
+	Hours int `form:"hours" binding:"omitempty,min=1,max=168"`
+}
+
+func (q *SurgeHistoryQuery) SetDefaults() {
+	if q.Hours == 0 {
+		q.Hours = 24
+	}
+}
+
+const maxPreferredAreas = 5
+
+type PreferredAreaInput struct {
+	Name      string  `json:"name" binding:"required,max=100"`
+	Latitude  float64 `json:"latitude" binding:"min=-90,max=90"`
+	Longitude float64 `json:"longitude" binding:"min=-180,max=180"`
+	RadiusKm  float64 `json:"radiusKm" binding:"required,gt=0,max=50"`
+}
+
+// UpdateDispatchPreferencesRequest replaces a driver's auto-accept rules and
+// preferred areas. A limit left out does not restrict auto-accept.
+type UpdateDispatchPreferencesRequest struct {
+	AutoAcceptEnabled     bool                 `json:"autoAcceptEnabled"`
+	AutoAcceptMaxPickupKm *float64             `json:"autoAcceptMaxPickupKm" binding:"omitempty,gt=0,max=50"`
+	AutoAcceptMinFare     *float64             `json:"autoAcceptMinFare" binding:"omitempty,min=0"`
+	PreferredAreas        []PreferredAreaInput `json:"preferredAreas" binding:"omitempty,dive"`
+}
+
+func (r *UpdateDispatchPreferencesRequest) Validate() error {
+	if len(r.PreferredAreas) > maxPreferredAreas {
+		return fmt.Errorf("at most %d preferred areas can be set", maxPreferredAreas)
+	}
+	return nil
+}
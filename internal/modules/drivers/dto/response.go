@@ -45,6 +45,7 @@ type VehicleResponse struct {
 	LicensePlate  string                          `json:"licensePlate"`
 	Capacity      int                             `json:"capacity"`
 	IsActive      bool                            `json:"isActive"`
+	IsCurrent     bool                            `json:"isCurrent"`
 	CreatedAt     time.Time                       `json:"createdAt"`
 	UpdatedAt     time.Time                       `json:"updatedAt"`
 }
@@ -100,6 +101,7 @@ func ToDriverProfileResponse(driver *models.DriverProfile) *DriverProfileRespons
 
 	if driver.Vehicle != nil {
 		resp.Vehicle = ToVehicleResponse(driver.Vehicle)
+		resp.Vehicle.IsCurrent = true
 	}
 
 	return resp
@@ -174,4 +176,71 @@ type PaymentResult struct {
 	TransactionID string
 	Provider      string
 	Error         string
-}
\ No newline at end of file
+}
+
+// SurgeHistoryResponse shows a driver the surge applied to their own trips
+// next to the surge readings for the cells they drive in.
+type SurgeHistoryResponse struct {
+	WindowHours int                `json:"windowHours"`
+	From        time.Time          `json:"from"`
+	To          time.Time          `json:"to"`
+	Summary     SurgeRideSummary   `json:"summary"`
+	Rides       []SurgeRideEntry   `json:"rides"`
+	Zones       []SurgeZoneHistory `json:"zones"`
+}
+
+type SurgeRideSummary struct {
+	CompletedRides    int     `json:"completedRides"`
+	SurgedRides       int     `json:"surgedRides"`
+	SurgeAmount       float64 `json:"surgeAmount"`
+	AverageMultiplier float64 `json:"averageMultiplier"`
+	MaxMultiplier     float64 `json:"maxMultiplier"`
+}
+
+type SurgeRideEntry struct {
+	RideID          string    `json:"rideId"`
+	CompletedAt     time.Time `json:"completedAt"`
+	PickupAddress   string    `json:"pickupAddress"`
+	Geohash         string    `json:"geohash"`
+	SurgeMultiplier float64   `json:"surgeMultiplier"`
+	SurgeAmount     float64   `json:"surgeAmount"`
+	DriverEarnings  float64   `json:"driverEarnings"`
+}
+
+type SurgeZoneHistory struct {
+	Geohash       string            `json:"geohash"`
+	IsCurrentZone bool              `json:"isCurrentZone"`
+	RideCount     int               `json:"rideCount"`
+	Timeline      []SurgeZonePeriod `json:"timeline"`
+}
+
+type SurgeZonePeriod struct {
+	PeriodStart       time.Time `json:"periodStart"`
+	AverageMultiplier float64   `json:"averageMultiplier"`
+	MaxMultiplier     float64   `json:"maxMultiplier"`
+	Samples           int       `json:"samples"`
+}
+
+type DispatchPreferencesResponse struct {
+	AutoAcceptEnabled     bool                   `json:"autoAcceptEnabled"`
+	AutoAcceptMaxPickupKm *float64               `json:"autoAcceptMaxPickupKm,omitempty"`
+	AutoAcceptMinFare     *float64               `json:"autoAcceptMinFare,omitempty"`
+	PreferredAreas        []models.PreferredArea `json:"preferredAreas"`
+	UpdatedAt             *time.Time             `json:"updatedAt,omitempty"`
+}
+
+func ToDispatchPreferencesResponse(prefs *models.DriverDispatchPreferences) *DispatchPreferencesResponse {
+	resp := &DispatchPreferencesResponse{
+		AutoAcceptEnabled:     prefs.AutoAcceptEnabled,
+		AutoAcceptMaxPickupKm: prefs.AutoAcceptMaxPickupKm,
+		AutoAcceptMinFare:     prefs.AutoAcceptMinFare,
+		PreferredAreas:        prefs.PreferredAreas,
+	}
+	if resp.PreferredAreas == nil {
+		resp.PreferredAreas = []models.PreferredArea{}
+	}
+	if !prefs.UpdatedAt.IsZero() {
+		resp.UpdatedAt = &prefs.UpdatedAt
+	}
+	return resp
+}
@@ -89,6 +89,7 @@ func (h *Handler) UpdateProfile(c *gin.Context) {
 
 // UpdateVehicle godoc
 // @Summary Update vehicle information
+// @Description Updates the driver's active vehicle
 // @Tags drivers
 // @Security BearerAuth
 // @Accept json
@@ -114,6 +115,76 @@ func (h *Handler) UpdateVehicle(c *gin.Context) {
 	response.Success(c, vehicle, "Vehicle updated successfully")
 }
 
+// ListVehicles godoc
+// @Summary List the driver's vehicles
+// @Description Every vehicle the driver has registered; isCurrent marks the one rides are matched on
+// @Tags drivers
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=[]driverdto.VehicleResponse}
+// @Router /drivers/vehicles [get]
+func (h *Handler) ListVehicles(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	vehicles, err := h.service.ListVehicles(c.Request.Context(), userID.(string))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, vehicles, "Vehicles retrieved successfully")
+}
+
+// AddVehicle godoc
+// @Summary Register another vehicle
+// @Description The vehicle becomes the active one only if the driver has none
+// @Tags drivers
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body driverdto.VehicleInput true "Vehicle details"
+// @Success 200 {object} response.Response{data=driverdto.VehicleResponse}
+// @Router /drivers/vehicles [post]
+func (h *Handler) AddVehicle(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req driverdto.VehicleInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	vehicle, err := h.service.AddVehicle(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, vehicle, "Vehicle registered successfully")
+}
+
+// SwitchVehicle godoc
+// @Summary Switch the active vehicle
+// @Description Rides are matched on the active vehicle's type. Not allowed during a ride
+// @Tags drivers
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Vehicle ID"
+// @Success 200 {object} response.Response{data=driverdto.VehicleResponse}
+// @Failure 409 {object} response.Response "Driver has a ride in progress"
+// @Router /drivers/vehicles/{id}/activate [post]
+func (h *Handler) SwitchVehicle(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	vehicle, err := h.service.SwitchVehicle(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, vehicle, "Active vehicle switched successfully")
+}
+
 // UpdateStatus godoc
 // @Summary Update driver status (online/offline)
 // @Tags drivers
@@ -205,6 +276,86 @@ func (h *Handler) GetDashboard(c *gin.Context) {
 	response.Success(c, dashboard, "Dashboard retrieved successfully")
 }
 
+// GetSurgeHistory godoc
+// @Summary Get surge history for the driver
+// @Description Surge applied to the driver's completed rides and the hourly surge timeline of the zones they operate in
+// @Tags drivers
+// @Security BearerAuth
+// @Produce json
+// @Param hours query int false "Look-back window in hours (default 24, max 168)"
+// @Success 200 {object} response.Response{data=driverdto.SurgeHistoryResponse}
+// @Failure 400 {object} response.Response "Invalid query parameters"
+// @Failure 404 {object} response.Response "Driver profile not found"
+// @Router /drivers/me/surge-history [get]
+func (h *Handler) GetSurgeHistory(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var query driverdto.SurgeHistoryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+
+	history, err := h.service.GetSurgeHistory(c.Request.Context(), userID.(string), query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, history, "Surge history retrieved successfully")
+}
+
+// GetDispatchPreferences godoc
+// @Summary Get the driver's dispatch preferences
+// @Description Auto-accept rules and preferred operating areas
+// @Tags drivers
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=driverdto.DispatchPreferencesResponse}
+// @Failure 404 {object} response.Response "Driver profile not found"
+// @Router /drivers/me/dispatch-preferences [get]
+func (h *Handler) GetDispatchPreferences(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	prefs, err := h.service.GetDispatchPreferences(c.Request.Context(), userID.(string))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, prefs, "Dispatch preferences retrieved successfully")
+}
+
+// UpdateDispatchPreferences godoc
+// @Summary Update the driver's dispatch preferences
+// @Description With auto-accept on, ride offers within the pickup distance and fare limits are accepted without confirmation. Rides picked up in a preferred area are offered to the driver first.
+// @Tags drivers
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body driverdto.UpdateDispatchPreferencesRequest true "Dispatch preferences"
+// @Success 200 {object} response.Response{data=driverdto.DispatchPreferencesResponse}
+// @Failure 400 {object} response.Response "Invalid request"
+// @Failure 404 {object} response.Response "Driver profile not found"
+// @Router /drivers/me/dispatch-preferences [put]
+func (h *Handler) UpdateDispatchPreferences(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req driverdto.UpdateDispatchPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	prefs, err := h.service.UpdateDispatchPreferences(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, prefs, "Dispatch preferences updated successfully")
+}
+
 // TopUpWallet godoc
 // @Summary Add funds to driver wallet (balance top-up)
 // @Description Driver can add funds to wallet for commissions and penalties and subscriptions
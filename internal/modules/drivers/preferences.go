@@ -0,0 +1,75 @@
+package drivers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/umar5678/go-backend/internal/models"
+	driverdto "github.com/umar5678/go-backend/internal/modules/drivers/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+// GetDispatchPreferences returns the driver's auto-accept rules and preferred
+// areas. A driver who never set any gets auto-accept off and no areas.
+func (s *service) GetDispatchPreferences(ctx context.Context, userID string) (*driverdto.DispatchPreferencesResponse, error) {
+	driver, err := s.repo.FindDriverByUserID(ctx, userID)
+	if err != nil {
+		return nil, response.NotFoundError("Driver profile")
+	}
+
+	prefs, err := s.repo.GetDispatchPreferences(ctx, driver.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return driverdto.ToDispatchPreferencesResponse(&models.DriverDispatchPreferences{DriverID: driver.ID}), nil
+		}
+		return nil, response.InternalServerError("Failed to get dispatch preferences", err)
+	}
+	return driverdto.ToDispatchPreferencesResponse(prefs), nil
+}
+
+func (s *service) UpdateDispatchPreferences(ctx context.Context, userID string, req driverdto.UpdateDispatchPreferencesRequest) (*driverdto.DispatchPreferencesResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	driver, err := s.repo.FindDriverByUserID(ctx, userID)
+	if err != nil {
+		return nil, response.NotFoundError("Driver profile")
+	}
+
+	areas := make(models.PreferredAreas, 0, len(req.PreferredAreas))
+	for _, area := range req.PreferredAreas {
+		areas = append(areas, models.PreferredArea{
+			Name:      area.Name,
+			Latitude:  area.Latitude,
+			Longitude: area.Longitude,
+			RadiusKm:  area.RadiusKm,
+		})
+	}
+
+	prefs := &models.DriverDispatchPreferences{
+		DriverID:              driver.ID,
+		AutoAcceptEnabled:     req.AutoAcceptEnabled,
+		AutoAcceptMaxPickupKm: req.AutoAcceptMaxPickupKm,
+		AutoAcceptMinFare:     req.AutoAcceptMinFare,
+		PreferredAreas:        areas,
+	}
+	if err := s.repo.SaveDispatchPreferences(ctx, prefs); err != nil {
+		logger.Error("failed to save dispatch preferences", "error", err, "driverID", driver.ID)
+		return nil, response.InternalServerError("Failed to update dispatch preferences", err)
+	}
+
+	saved, err := s.repo.GetDispatchPreferences(ctx, driver.ID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get dispatch preferences", err)
+	}
+
+	logger.Info("driver dispatch preferences updated",
+		"driverID", driver.ID,
+		"autoAccept", saved.AutoAcceptEnabled,
+		"preferredAreas", len(saved.PreferredAreas),
+	)
+	return driverdto.ToDispatchPreferencesResponse(saved), nil
+}
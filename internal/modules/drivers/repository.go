@@ -8,6 +8,7 @@ import (
 	"github.com/umar5678/go-backend/internal/models"
 	"github.com/umar5678/go-backend/internal/utils/logger"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repository interface {
@@ -20,11 +21,16 @@ type Repository interface {
 	UpdateDriverLocation(ctx context.Context, driverID string, lat, lng float64, heading int) error
 	GetDriverLocationHistory(ctx context.Context, driverID string, limit int) ([]*models.DriverLocation, error)
 	GetLatestDriverLocation(ctx context.Context, driverID string) (*models.DriverLocation, error)
+	SaveRideTrackPoint(ctx context.Context, point *models.RideTrackPoint) error
 
 	CreateVehicle(ctx context.Context, vehicle *models.Vehicle) error
-	FindVehicleByDriverID(ctx context.Context, driverID string) (*models.Vehicle, error)
+	FindActiveVehicle(ctx context.Context, driverID string) (*models.Vehicle, error)
+	FindDriverVehicle(ctx context.Context, driverID, vehicleID string) (*models.Vehicle, error)
+	ListDriverVehicles(ctx context.Context, driverID string) ([]*models.Vehicle, error)
 	FindVehicleByPlate(ctx context.Context, licensePlate string) (*models.Vehicle, error)
 	UpdateVehicle(ctx context.Context, vehicle *models.Vehicle) error
+	SetActiveVehicle(ctx context.Context, driverID, vehicleID string) error
+	HasActiveRide(ctx context.Context, driverUserID string) (bool, error)
 
 	IncrementTrips(ctx context.Context, driverID string) error
 	UpdateEarnings(ctx context.Context, driverID string, amount float64) error
@@ -32,6 +38,35 @@ type Repository interface {
 
 	FindNearbyDrivers(ctx context.Context, lat, lng, radiusKm float64, vehicleTypeID string) ([]*models.DriverProfile, error)
 	ListDriverProfiles(ctx context.Context, filters map[string]interface{}, page, limit int) ([]*models.DriverProfile, int64, error)
+
+	GetDriverPosition(ctx context.Context, driverID string) (lat, lon float64, ok bool, err error)
+	GetCompletedRideSurges(ctx context.Context, driverUserID string, since time.Time) ([]RideSurge, error)
+	GetSurgeTimeline(ctx context.Context, geohashes []string, vehicleTypeID string, since time.Time) ([]SurgePeriod, error)
+
+	GetDispatchPreferences(ctx context.Context, driverID string) (*models.DriverDispatchPreferences, error)
+	SaveDispatchPreferences(ctx context.Context, prefs *models.DriverDispatchPreferences) error
+	FindDispatchPreferences(ctx context.Context, driverIDs []string) (map[string]*models.DriverDispatchPreferences, error)
+}
+
+// RideSurge is the surge a completed ride was charged, preferring the fare
+// snapshot taken at completion over the multiplier quoted at booking.
+type RideSurge struct {
+	RideID          string
+	CompletedAt     time.Time
+	PickupAddress   string
+	PickupLat       float64
+	PickupLon       float64
+	SurgeMultiplier float64
+	SurgeAmount     float64
+	DriverEarnings  float64
+}
+
+type SurgePeriod struct {
+	Geohash           string
+	PeriodStart       time.Time
+	AverageMultiplier float64
+	MaxMultiplier     float64
+	Samples           int
 }
 
 type repository struct {
@@ -100,6 +135,10 @@ func (r *repository) UpdateDriverLocation(ctx context.Context, driverID string,
 		}).Error
 }
 
+func (r *repository) SaveRideTrackPoint(ctx context.Context, point *models.RideTrackPoint) error {
+	return r.db.WithContext(ctx).Create(point).Error
+}
+
 func (r *repository) GetLatestDriverLocation(ctx context.Context, driverID string) (*models.DriverLocation, error) {
 	var location models.DriverLocation
 
@@ -119,15 +158,34 @@ func (r *repository) CreateVehicle(ctx context.Context, vehicle *models.Vehicle)
 	return r.db.WithContext(ctx).Create(vehicle).Error
 }
 
-func (r *repository) FindVehicleByDriverID(ctx context.Context, driverID string) (*models.Vehicle, error) {
+func (r *repository) FindActiveVehicle(ctx context.Context, driverID string) (*models.Vehicle, error) {
 	var vehicle models.Vehicle
 	err := r.db.WithContext(ctx).
 		Preload("VehicleType").
-		Where("driver_id = ?", driverID).
+		Where("id = (SELECT active_vehicle_id FROM driver_profiles WHERE id = ?)", driverID).
+		First(&vehicle).Error
+	return &vehicle, err
+}
+
+func (r *repository) FindDriverVehicle(ctx context.Context, driverID, vehicleID string) (*models.Vehicle, error) {
+	var vehicle models.Vehicle
+	err := r.db.WithContext(ctx).
+		Preload("VehicleType").
+		Where("id = ? AND driver_id = ?", vehicleID, driverID).
 		First(&vehicle).Error
 	return &vehicle, err
 }
 
+func (r *repository) ListDriverVehicles(ctx context.Context, driverID string) ([]*models.Vehicle, error) {
+	var vehicles []*models.Vehicle
+	err := r.db.WithContext(ctx).
+		Preload("VehicleType").
+		Where("driver_id = ?", driverID).
+		Order("created_at ASC").
+		Find(&vehicles).Error
+	return vehicles, err
+}
+
 func (r *repository) FindVehicleByPlate(ctx context.Context, licensePlate string) (*models.Vehicle, error) {
 	var vehicle models.Vehicle
 	err := r.db.WithContext(ctx).
@@ -140,6 +198,24 @@ func (r *repository) UpdateVehicle(ctx context.Context, vehicle *models.Vehicle)
 	return r.db.WithContext(ctx).Save(vehicle).Error
 }
 
+func (r *repository) SetActiveVehicle(ctx context.Context, driverID, vehicleID string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.DriverProfile{}).
+		Where("id = ?", driverID).
+		Update("active_vehicle_id", vehicleID).Error
+}
+
+// HasActiveRide reports whether the driver has a ride they have accepted and
+// not yet finished.
+func (r *repository) HasActiveRide(ctx context.Context, driverUserID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Ride{}).
+		Where("driver_id = ? AND status IN ?", driverUserID, []string{"accepted", "arrived", "started"}).
+		Count(&count).Error
+	return count > 0, err
+}
+
 func (r *repository) IncrementTrips(ctx context.Context, driverID string) error {
 	return r.db.WithContext(ctx).
 		Model(&models.DriverProfile{}).
@@ -181,7 +257,7 @@ func (r *repository) FindNearbyDrivers(ctx context.Context, lat, lng, radiusKm f
 			locationStr, radiusMeters)
 
 	if vehicleTypeID != "" {
-		query = query.Joins("JOIN vehicles ON vehicles.driver_id = driver_profiles.id").
+		query = query.Joins("JOIN vehicles ON vehicles.id = driver_profiles.active_vehicle_id").
 			Where("vehicles.vehicle_type_id = ?", vehicleTypeID)
 	}
 
@@ -237,3 +313,95 @@ func (r *repository) ListDriverProfiles(ctx context.Context, filters map[string]
 
 	return drivers, total, err
 }
+
+func (r *repository) GetDriverPosition(ctx context.Context, driverID string) (float64, float64, bool, error) {
+	var position struct {
+		Lat float64
+		Lon float64
+	}
+	result := r.db.WithContext(ctx).
+		Model(&models.DriverProfile{}).
+		Select("ST_Y(current_location) AS lat, ST_X(current_location) AS lon").
+		Where("id = ? AND current_location IS NOT NULL", driverID).
+		Scan(&position)
+	if result.Error != nil {
+		return 0, 0, false, result.Error
+	}
+	return position.Lat, position.Lon, result.RowsAffected > 0, nil
+}
+
+func (r *repository) GetCompletedRideSurges(ctx context.Context, driverUserID string, since time.Time) ([]RideSurge, error) {
+	var surges []RideSurge
+	err := r.db.WithContext(ctx).
+		Table("rides r").
+		Select(`r.id AS ride_id, r.completed_at, r.pickup_address, r.pickup_lat, r.pickup_lon,
+			COALESCE(s.surge_multiplier, r.surge_multiplier, 1) AS surge_multiplier,
+			COALESCE(s.surge_amount, 0) AS surge_amount,
+			COALESCE(s.driver_share, r.driver_fare, r.actual_fare, 0) AS driver_earnings`).
+		Joins("LEFT JOIN ride_fare_snapshots s ON s.ride_id = r.id").
+		Where("r.driver_id = ? AND r.status = ? AND r.completed_at >= ?", driverUserID, "completed", since).
+		Order("r.completed_at DESC").
+		Scan(&surges).Error
+	return surges, err
+}
+
+// GetSurgeTimeline buckets the surge engine's snapshots by hour. Snapshots
+// taken for other vehicle types are left out; ones without a type apply to all.
+func (r *repository) GetSurgeTimeline(ctx context.Context, geohashes []string, vehicleTypeID string, since time.Time) ([]SurgePeriod, error) {
+	var periods []SurgePeriod
+	if len(geohashes) == 0 {
+		return periods, nil
+	}
+
+	query := r.db.WithContext(ctx).
+		Model(&models.SurgeSnapshot{}).
+		Select(`geohash, date_trunc('hour', recorded_at) AS period_start,
+			AVG(multiplier) AS average_multiplier, MAX(multiplier) AS max_multiplier, COUNT(*) AS samples`).
+		Where("geohash IN ? AND recorded_at >= ?", geohashes, since)
+	if vehicleTypeID != "" {
+		query = query.Where("vehicle_type_id IS NULL OR vehicle_type_id = ?", vehicleTypeID)
+	}
+
+	err := query.
+		Group("geohash, period_start").
+		Order("geohash, period_start").
+		Scan(&periods).Error
+	return periods, err
+}
+
+func (r *repository) GetDispatchPreferences(ctx context.Context, driverID string) (*models.DriverDispatchPreferences, error) {
+	var prefs models.DriverDispatchPreferences
+	err := r.db.WithContext(ctx).Where("driver_id = ?", driverID).First(&prefs).Error
+	if err != nil {
+		return nil, err
+	}
+	return &prefs, nil
+}
+
+func (r *repository) SaveDispatchPreferences(ctx context.Context, prefs *models.DriverDispatchPreferences) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "driver_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"auto_accept_enabled", "auto_accept_max_pickup_km", "auto_accept_min_fare",
+			"preferred_areas", "updated_at",
+		}),
+	}).Create(prefs).Error
+}
+
+// FindDispatchPreferences loads the preferences of the given drivers, keyed by
+// driver profile ID. Drivers who never set any are left out.
+func (r *repository) FindDispatchPreferences(ctx context.Context, driverIDs []string) (map[string]*models.DriverDispatchPreferences, error) {
+	result := make(map[string]*models.DriverDispatchPreferences, len(driverIDs))
+	if len(driverIDs) == 0 {
+		return result, nil
+	}
+
+	var prefs []*models.DriverDispatchPreferences
+	if err := r.db.WithContext(ctx).Where("driver_id IN ?", driverIDs).Find(&prefs).Error; err != nil {
+		return nil, err
+	}
+	for _, p := range prefs {
+		result[p.DriverID] = p
+	}
+	return result, nil
+}
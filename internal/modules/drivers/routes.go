@@ -12,10 +12,16 @@ func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gi
 		drivers.GET("/profile", handler.GetProfile)
 		drivers.PUT("/profile", handler.UpdateProfile)
 		drivers.PUT("/vehicle", handler.UpdateVehicle)
+		drivers.GET("/vehicles", handler.ListVehicles)
+		drivers.POST("/vehicles", handler.AddVehicle)
+		drivers.POST("/vehicles/:id/activate", handler.SwitchVehicle)
 		drivers.POST("/status", handler.UpdateStatus)
 		drivers.POST("/location", handler.UpdateLocation)
 		drivers.GET("/wallet", handler.GetWallet)
 		drivers.GET("/dashboard", handler.GetDashboard)
+		drivers.GET("/me/surge-history", handler.GetSurgeHistory)
+		drivers.GET("/me/dispatch-preferences", handler.GetDispatchPreferences)
+		drivers.PUT("/me/dispatch-preferences", handler.UpdateDispatchPreferences)
 
 		drivers.POST("/wallet/topup", handler.TopUpWallet)
 		drivers.GET("/wallet/status", handler.GetWalletStatus)
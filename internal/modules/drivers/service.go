@@ -24,6 +24,9 @@ type Service interface {
 	GetProfile(ctx context.Context, userID string) (*driverdto.DriverProfileResponse, error)
 	UpdateProfile(ctx context.Context, userID string, req driverdto.UpdateDriverProfileRequest) (*driverdto.DriverProfileResponse, error)
 	UpdateVehicle(ctx context.Context, userID string, req driverdto.UpdateVehicleRequest) (*driverdto.VehicleResponse, error)
+	ListVehicles(ctx context.Context, userID string) ([]*driverdto.VehicleResponse, error)
+	AddVehicle(ctx context.Context, userID string, req driverdto.VehicleInput) (*driverdto.VehicleResponse, error)
+	SwitchVehicle(ctx context.Context, userID, vehicleID string) (*driverdto.VehicleResponse, error)
 	UpdateStatus(ctx context.Context, userID string, req driverdto.UpdateStatusRequest) (*driverdto.DriverProfileResponse, error)
 	GetWallet(ctx context.Context, userID string) (*driverdto.WalletResponse, error)
 	GetDashboard(ctx context.Context, userID string) (*driverdto.DriverDashboardResponse, error)
@@ -34,6 +37,10 @@ type Service interface {
 
 	UpdateLocation(ctx context.Context, userID string, req driverdto.UpdateLocationRequest) error
 	ListDriverProfiles(ctx context.Context, filters map[string]interface{}, page, limit int) ([]*driverdto.DriverProfileResponse, int64, error)
+	GetSurgeHistory(ctx context.Context, userID string, query driverdto.SurgeHistoryQuery) (*driverdto.SurgeHistoryResponse, error)
+
+	GetDispatchPreferences(ctx context.Context, userID string) (*driverdto.DispatchPreferencesResponse, error)
+	UpdateDispatchPreferences(ctx context.Context, userID string, req driverdto.UpdateDispatchPreferencesRequest) (*driverdto.DispatchPreferencesResponse, error)
 }
 
 type service struct {
@@ -110,6 +117,10 @@ func (s *service) RegisterDriver(ctx context.Context, userID string, req driverd
 		logger.Error("failed to create vehicle", "error", err, "driverID", driver.ID)
 		return nil, response.InternalServerError("Failed to register vehicle", err)
 	}
+	if err := s.repo.SetActiveVehicle(ctx, driver.ID, vehicle.ID); err != nil {
+		logger.Error("failed to set active vehicle", "error", err, "driverID", driver.ID)
+		return nil, response.InternalServerError("Failed to register vehicle", err)
+	}
 
 	driver, _ = s.repo.FindDriverByID(ctx, driver.ID)
 
@@ -186,7 +197,7 @@ func (s *service) UpdateVehicle(ctx context.Context, userID string, req driverdt
 		return nil, response.NotFoundError("Driver profile")
 	}
 
-	vehicle, err := s.repo.FindVehicleByDriverID(ctx, driver.ID)
+	vehicle, err := s.repo.FindActiveVehicle(ctx, driver.ID)
 	if err != nil {
 		return nil, response.NotFoundError("Vehicle")
 	}
@@ -227,11 +238,13 @@ func (s *service) UpdateVehicle(ctx context.Context, userID string, req driverdt
 
 	cache.Delete(ctx, fmt.Sprintf("driver:profile:%s", userID))
 
-	vehicle, _ = s.repo.FindVehicleByDriverID(ctx, driver.ID)
+	vehicle, _ = s.repo.FindActiveVehicle(ctx, driver.ID)
 
 	logger.Info("vehicle updated", "vehicleID", vehicle.ID, "driverID", driver.ID)
 
-	return driverdto.ToVehicleResponse(vehicle), nil
+	resp := driverdto.ToVehicleResponse(vehicle)
+	resp.IsCurrent = true
+	return resp, nil
 }
 
 func (s *service) UpdateStatus(ctx context.Context, userID string, req driverdto.UpdateStatusRequest) (*driverdto.DriverProfileResponse, error) {
@@ -249,13 +262,26 @@ func (s *service) UpdateStatus(ctx context.Context, userID string, req driverdto
 			return nil, response.BadRequest("Driver is not verified yet")
 		}
 
-		vehicle, err := s.repo.FindVehicleByDriverID(ctx, driver.ID)
+		vehicle, err := s.repo.FindActiveVehicle(ctx, driver.ID)
 		if err != nil {
 			return nil, response.BadRequest("Vehicle information is required to go online")
 		}
 		if !vehicle.IsActive {
 			return nil, response.BadRequest("Vehicle is not active")
 		}
+
+		restricted, _, err := s.walletService.CheckAndEnforceAccountRestriction(ctx, driver.ID)
+		if err != nil {
+			return nil, err
+		}
+		if restricted {
+			credit, err := s.walletService.GetDriverCredit(ctx, driver.ID)
+			if err != nil {
+				return nil, err
+			}
+			return nil, response.CodedError(response.CodeDriverCreditExceeded,
+				fmt.Sprintf("Your wallet debt exceeds your credit limit. Add %.2f %s to go online", credit.AmountNeededToGoLive, credit.Currency))
+		}
 	}
 
 	oldStatus := driver.Status
@@ -460,6 +486,17 @@ func (s *service) UpdateLocation(ctx context.Context, userID string, req driverd
 				"riderUserID", riderID,
 			)
 
+			point := &models.RideTrackPoint{
+				RideID:     rideID,
+				DriverID:   driver.ID,
+				Latitude:   req.Latitude,
+				Longitude:  req.Longitude,
+				RecordedAt: time.Now(),
+			}
+			if err := s.repo.SaveRideTrackPoint(ctx, point); err != nil {
+				logger.Warn("failed to save ride track point", "error", err, "rideID", rideID, "driverID", driver.ID)
+			}
+
 			logger.Info("Starting location stream to rider...")
 
 			go func() {
@@ -0,0 +1,158 @@
+package drivers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	driverdto "github.com/umar5678/go-backend/internal/modules/drivers/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+// maxSurgeHistoryZones caps how many cells the timeline covers; beyond a
+// handful the extra zones are places the driver rarely picks up from.
+const maxSurgeHistoryZones = 5
+
+// surgeZone matches the cell key the ride and pricing modules use when they
+// ask the surge engine for a multiplier.
+func surgeZone(lat, lon float64) string {
+	return fmt.Sprintf("%.1f_%.1f", lat, lon)
+}
+
+// GetSurgeHistory shows the surge applied to the driver's completed rides in
+// the window next to the surge engine's readings for the zones they operate
+// in: the zone they are in now, then the zones they picked up from most.
+func (s *service) GetSurgeHistory(ctx context.Context, userID string, query driverdto.SurgeHistoryQuery) (*driverdto.SurgeHistoryResponse, error) {
+	query.SetDefaults()
+
+	driver, err := s.repo.FindDriverByUserID(ctx, userID)
+	if err != nil {
+		return nil, response.NotFoundError("Driver profile")
+	}
+
+	now := time.Now()
+	since := now.Add(-time.Duration(query.Hours) * time.Hour)
+
+	rides, err := s.repo.GetCompletedRideSurges(ctx, userID, since)
+	if err != nil {
+		logger.Error("failed to get ride surges", "error", err, "driverID", driver.ID)
+		return nil, response.InternalServerError("Failed to get surge history", err)
+	}
+
+	result := &driverdto.SurgeHistoryResponse{
+		WindowHours: query.Hours,
+		From:        since,
+		To:          now,
+		Rides:       make([]driverdto.SurgeRideEntry, 0, len(rides)),
+		Zones:       []driverdto.SurgeZoneHistory{},
+	}
+
+	rideCounts := make(map[string]int)
+	multiplierSum := 0.0
+	for _, ride := range rides {
+		zone := surgeZone(ride.PickupLat, ride.PickupLon)
+		rideCounts[zone]++
+
+		result.Rides = append(result.Rides, driverdto.SurgeRideEntry{
+			RideID:          ride.RideID,
+			CompletedAt:     ride.CompletedAt,
+			PickupAddress:   ride.PickupAddress,
+			Geohash:         zone,
+			SurgeMultiplier: ride.SurgeMultiplier,
+			SurgeAmount:     ride.SurgeAmount,
+			DriverEarnings:  ride.DriverEarnings,
+		})
+
+		multiplierSum += ride.SurgeMultiplier
+		if ride.SurgeMultiplier > 1.0 {
+			result.Summary.SurgedRides++
+			result.Summary.SurgeAmount += ride.SurgeAmount
+		}
+		if ride.SurgeMultiplier > result.Summary.MaxMultiplier {
+			result.Summary.MaxMultiplier = ride.SurgeMultiplier
+		}
+	}
+	result.Summary.CompletedRides = len(rides)
+	if len(rides) > 0 {
+		result.Summary.AverageMultiplier = multiplierSum / float64(len(rides))
+	}
+
+	currentZone := ""
+	lat, lon, ok, err := s.repo.GetDriverPosition(ctx, driver.ID)
+	if err != nil {
+		logger.Warn("failed to get driver position for surge history", "error", err, "driverID", driver.ID)
+	} else if ok {
+		currentZone = surgeZone(lat, lon)
+	}
+
+	zones := operatingZones(currentZone, rideCounts)
+	if len(zones) == 0 {
+		return result, nil
+	}
+
+	vehicleTypeID := ""
+	if driver.Vehicle != nil {
+		vehicleTypeID = driver.Vehicle.VehicleTypeID
+	}
+
+	periods, err := s.repo.GetSurgeTimeline(ctx, zones, vehicleTypeID, since)
+	if err != nil {
+		logger.Error("failed to get surge timeline", "error", err, "driverID", driver.ID)
+		return nil, response.InternalServerError("Failed to get surge history", err)
+	}
+
+	timelines := make(map[string][]driverdto.SurgeZonePeriod)
+	for _, period := range periods {
+		timelines[period.Geohash] = append(timelines[period.Geohash], driverdto.SurgeZonePeriod{
+			PeriodStart:       period.PeriodStart,
+			AverageMultiplier: period.AverageMultiplier,
+			MaxMultiplier:     period.MaxMultiplier,
+			Samples:           period.Samples,
+		})
+	}
+
+	for _, zone := range zones {
+		timeline := timelines[zone]
+		if timeline == nil {
+			timeline = []driverdto.SurgeZonePeriod{}
+		}
+		result.Zones = append(result.Zones, driverdto.SurgeZoneHistory{
+			Geohash:       zone,
+			IsCurrentZone: zone == currentZone,
+			RideCount:     rideCounts[zone],
+			Timeline:      timeline,
+		})
+	}
+
+	return result, nil
+}
+
+func operatingZones(currentZone string, rideCounts map[string]int) []string {
+	zones := make([]string, 0, maxSurgeHistoryZones)
+	if currentZone != "" {
+		zones = append(zones, currentZone)
+	}
+
+	ranked := make([]string, 0, len(rideCounts))
+	for zone := range rideCounts {
+		if zone != currentZone {
+			ranked = append(ranked, zone)
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if rideCounts[ranked[i]] != rideCounts[ranked[j]] {
+			return rideCounts[ranked[i]] > rideCounts[ranked[j]]
+		}
+		return ranked[i] < ranked[j]
+	})
+
+	for _, zone := range ranked {
+		if len(zones) >= maxSurgeHistoryZones {
+			break
+		}
+		zones = append(zones, zone)
+	}
+	return zones
+}
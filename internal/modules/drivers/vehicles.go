@@ -0,0 +1,145 @@
+package drivers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	driverdto "github.com/umar5678/go-backend/internal/modules/drivers/dto"
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+// ListVehicles returns every vehicle the driver has registered, marking the
+// one they are driving now.
+func (s *service) ListVehicles(ctx context.Context, userID string) ([]*driverdto.VehicleResponse, error) {
+	driver, err := s.repo.FindDriverByUserID(ctx, userID)
+	if err != nil {
+		return nil, response.NotFoundError("Driver profile")
+	}
+
+	vehicles, err := s.repo.ListDriverVehicles(ctx, driver.ID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to list vehicles", err)
+	}
+
+	result := make([]*driverdto.VehicleResponse, 0, len(vehicles))
+	for _, vehicle := range vehicles {
+		result = append(result, toVehicleResponse(driver, vehicle))
+	}
+	return result, nil
+}
+
+// AddVehicle registers another vehicle for the driver. It only becomes the
+// active vehicle if the driver has none; otherwise the driver switches to it.
+func (s *service) AddVehicle(ctx context.Context, userID string, req driverdto.VehicleInput) (*driverdto.VehicleResponse, error) {
+	driver, err := s.repo.FindDriverByUserID(ctx, userID)
+	if err != nil {
+		return nil, response.NotFoundError("Driver profile")
+	}
+
+	if _, err := s.repo.FindVehicleByPlate(ctx, req.LicensePlate); err == nil {
+		return nil, response.BadRequest("License plate is already registered")
+	}
+
+	vehicle := &models.Vehicle{
+		DriverID:      driver.ID,
+		VehicleTypeID: req.VehicleTypeID,
+		Make:          req.Make,
+		Model:         req.Model,
+		Year:          req.Year,
+		Color:         req.Color,
+		LicensePlate:  req.LicensePlate,
+		Capacity:      req.Capacity,
+		IsActive:      true,
+	}
+	if err := s.repo.CreateVehicle(ctx, vehicle); err != nil {
+		logger.Error("failed to create vehicle", "error", err, "driverID", driver.ID)
+		return nil, response.InternalServerError("Failed to register vehicle", err)
+	}
+
+	if driver.ActiveVehicleID == nil {
+		if err := s.repo.SetActiveVehicle(ctx, driver.ID, vehicle.ID); err != nil {
+			logger.Error("failed to set active vehicle", "error", err, "driverID", driver.ID)
+			return nil, response.InternalServerError("Failed to register vehicle", err)
+		}
+		driver.ActiveVehicleID = &vehicle.ID
+		cache.Delete(ctx, fmt.Sprintf("driver:profile:%s", userID))
+	}
+
+	saved, err := s.repo.FindDriverVehicle(ctx, driver.ID, vehicle.ID)
+	if err != nil {
+		saved = vehicle
+	}
+
+	logger.Info("vehicle added", "vehicleID", vehicle.ID, "driverID", driver.ID, "vehicleType", vehicle.VehicleTypeID)
+
+	return toVehicleResponse(driver, saved), nil
+}
+
+// SwitchVehicle makes another of the driver's vehicles the one they are
+// driving, so they are offered rides for its type from then on. It is refused
+// while the driver has a ride in progress.
+func (s *service) SwitchVehicle(ctx context.Context, userID, vehicleID string) (*driverdto.VehicleResponse, error) {
+	driver, err := s.repo.FindDriverByUserID(ctx, userID)
+	if err != nil {
+		return nil, response.NotFoundError("Driver profile")
+	}
+
+	vehicle, err := s.repo.FindDriverVehicle(ctx, driver.ID, vehicleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Vehicle")
+		}
+		return nil, response.InternalServerError("Failed to load vehicle", err)
+	}
+	if driver.ActiveVehicleID != nil && *driver.ActiveVehicleID == vehicle.ID {
+		return toVehicleResponse(driver, vehicle), nil
+	}
+
+	if !vehicle.IsActive {
+		return nil, response.BadRequest("Vehicle is not active")
+	}
+	if vehicle.InspectionLapsed(time.Now()) {
+		return nil, response.CodedError(response.CodeDriverInspectionLapsed, "This vehicle's inspection has lapsed. Submit a new inspection before driving it")
+	}
+
+	busy, err := s.repo.HasActiveRide(ctx, userID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to check active rides", err)
+	}
+	if busy {
+		return nil, response.ConflictError("You cannot switch vehicles during a ride")
+	}
+
+	var previous string
+	if driver.ActiveVehicleID != nil {
+		previous = *driver.ActiveVehicleID
+	}
+	if err := s.repo.SetActiveVehicle(ctx, driver.ID, vehicle.ID); err != nil {
+		logger.Error("failed to switch vehicle", "error", err, "driverID", driver.ID, "vehicleID", vehicle.ID)
+		return nil, response.InternalServerError("Failed to switch vehicle", err)
+	}
+	driver.ActiveVehicleID = &vehicle.ID
+
+	cache.Delete(ctx, fmt.Sprintf("driver:profile:%s", userID))
+
+	logger.Info("driver switched vehicle",
+		"driverID", driver.ID,
+		"fromVehicleID", previous,
+		"toVehicleID", vehicle.ID,
+		"vehicleType", vehicle.VehicleTypeID,
+	)
+
+	return toVehicleResponse(driver, vehicle), nil
+}
+
+func toVehicleResponse(driver *models.DriverProfile, vehicle *models.Vehicle) *driverdto.VehicleResponse {
+	resp := driverdto.ToVehicleResponse(vehicle)
+	resp.IsCurrent = driver.ActiveVehicleID != nil && *driver.ActiveVehicleID == vehicle.ID
+	return resp
+}
@@ -0,0 +1,21 @@
+package dto
+
+type ListEmailLogsRequest struct {
+	Status        string `form:"status" binding:"omitempty,oneof=queued sent failed delivered bounced dropped complaint"`
+	Template      string `form:"template"`
+	UserID        string `form:"userId" binding:"omitempty,uuid"`
+	To            string `form:"to"`
+	ReferenceType string `form:"referenceType"`
+	ReferenceID   string `form:"referenceId"`
+	Page          int    `form:"page" binding:"omitempty,min=1"`
+	Limit         int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (r *ListEmailLogsRequest) SetDefaults() {
+	if r.Page < 1 {
+		r.Page = 1
+	}
+	if r.Limit < 1 {
+		r.Limit = 20
+	}
+}
@@ -0,0 +1,43 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+type EmailLogResponse struct {
+	ID                string             `json:"id"`
+	UserID            *string            `json:"userId,omitempty"`
+	ToEmail           string             `json:"toEmail"`
+	Template          string             `json:"template"`
+	Subject           string             `json:"subject"`
+	Provider          string             `json:"provider"`
+	ProviderMessageID *string            `json:"providerMessageId,omitempty"`
+	Status            models.EmailStatus `json:"status"`
+	Error             string             `json:"error,omitempty"`
+	ReferenceType     string             `json:"referenceType,omitempty"`
+	ReferenceID       string             `json:"referenceId,omitempty"`
+	SentAt            *time.Time         `json:"sentAt,omitempty"`
+	StatusUpdatedAt   *time.Time         `json:"statusUpdatedAt,omitempty"`
+	CreatedAt         time.Time          `json:"createdAt"`
+}
+
+func ToEmailLogResponse(log *models.EmailLog) *EmailLogResponse {
+	return &EmailLogResponse{
+		ID:                log.ID,
+		UserID:            log.UserID,
+		ToEmail:           log.ToEmail,
+		Template:          log.Template,
+		Subject:           log.Subject,
+		Provider:          log.Provider,
+		ProviderMessageID: log.ProviderMessageID,
+		Status:            log.Status,
+		Error:             log.Error,
+		ReferenceType:     log.ReferenceType,
+		ReferenceID:       log.ReferenceID,
+		SentAt:            log.SentAt,
+		StatusUpdatedAt:   log.StatusUpdatedAt,
+		CreatedAt:         log.CreatedAt,
+	}
+}
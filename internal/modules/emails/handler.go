@@ -0,0 +1,92 @@
+package emails
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/modules/emails/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// DeliveryWebhook godoc
+// @Summary Email delivery webhook
+// @Description Receives delivery, bounce and complaint events from SendGrid or from SES through SNS
+// @Tags emails
+// @Accept json
+// @Produce json
+// @Param provider path string true "sendgrid or ses"
+// @Param token query string true "Shared webhook secret"
+// @Success 200 {object} response.Response
+// @Router /emails/webhooks/{provider} [post]
+func (h *Handler) DeliveryWebhook(c *gin.Context) {
+	payload, err := c.GetRawData()
+	if err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	if err := h.service.HandleWebhook(c.Request.Context(), c.Param("provider"), c.Query("token"), payload); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, nil, "Webhook processed")
+}
+
+// ListEmailLogs godoc
+// @Summary List sent emails
+// @Description The email send log, newest first, with the delivery status each provider reported
+// @Tags admin-emails
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "Filter by delivery status"
+// @Param template query string false "Filter by template"
+// @Param userId query string false "Filter by recipient user"
+// @Param to query string false "Search recipient address"
+// @Param referenceType query string false "Filter by what the email was about"
+// @Param referenceId query string false "Filter by the ID of what the email was about"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Response{data=[]dto.EmailLogResponse}
+// @Router /admin/emails [get]
+func (h *Handler) ListEmailLogs(c *gin.Context) {
+	var req dto.ListEmailLogsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+	req.SetDefaults()
+
+	logs, total, err := h.service.ListLogs(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pagination := response.NewPaginationMeta(total, req.Page, req.Limit)
+	response.Paginated(c, logs, pagination, "Emails retrieved successfully")
+}
+
+// GetEmailLog godoc
+// @Summary Get a sent email
+// @Tags admin-emails
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Email log ID"
+// @Success 200 {object} response.Response{data=dto.EmailLogResponse}
+// @Router /admin/emails/{id} [get]
+func (h *Handler) GetEmailLog(c *gin.Context) {
+	log, err := h.service.GetLog(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, log, "Email retrieved successfully")
+}
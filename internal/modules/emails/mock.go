@@ -0,0 +1,37 @@
+package emails
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+// mockTransport is for local development: instead of sending, it writes the
+// message to the log, where verification links and the like can be copied
+// from. Every message counts as sent.
+type mockTransport struct{}
+
+func newMockTransport() *mockTransport {
+	return &mockTransport{}
+}
+
+func (t *mockTransport) Name() string {
+	return ProviderMock
+}
+
+func (t *mockTransport) Send(ctx context.Context, msg Message) (string, error) {
+	attachments := make([]string, len(msg.Attachments))
+	for i, a := range msg.Attachments {
+		attachments[i] = a.Filename
+	}
+
+	logger.Info("email (mock transport)",
+		"logID", msg.LogID,
+		"to", msg.To,
+		"subject", msg.Subject,
+		"attachments", attachments,
+		"text", msg.Text,
+	)
+	return "mock-" + uuid.NewString(), nil
+}
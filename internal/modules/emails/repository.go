@@ -0,0 +1,98 @@
+package emails
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/emails/dto"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	CreateLog(ctx context.Context, log *models.EmailLog) error
+	UpdateLog(ctx context.Context, log *models.EmailLog) error
+	FindLogByID(ctx context.Context, id string) (*models.EmailLog, error)
+	FindLogByProviderMessageID(ctx context.Context, provider, messageID string) (*models.EmailLog, error)
+	ListLogs(ctx context.Context, req dto.ListEmailLogsRequest) ([]*models.EmailLog, int64, error)
+
+	FindUserByID(ctx context.Context, userID string) (*models.User, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) CreateLog(ctx context.Context, log *models.EmailLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
+
+func (r *repository) UpdateLog(ctx context.Context, log *models.EmailLog) error {
+	return r.db.WithContext(ctx).Save(log).Error
+}
+
+func (r *repository) FindLogByID(ctx context.Context, id string) (*models.EmailLog, error) {
+	var log models.EmailLog
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&log).Error; err != nil {
+		return nil, err
+	}
+	return &log, nil
+}
+
+func (r *repository) FindLogByProviderMessageID(ctx context.Context, provider, messageID string) (*models.EmailLog, error) {
+	var log models.EmailLog
+	err := r.db.WithContext(ctx).
+		Where("provider = ? AND provider_message_id = ?", provider, messageID).
+		First(&log).Error
+	if err != nil {
+		return nil, err
+	}
+	return &log, nil
+}
+
+func (r *repository) ListLogs(ctx context.Context, req dto.ListEmailLogsRequest) ([]*models.EmailLog, int64, error) {
+	var logs []*models.EmailLog
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.EmailLog{})
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.Template != "" {
+		query = query.Where("template = ?", req.Template)
+	}
+	if req.UserID != "" {
+		query = query.Where("user_id = ?", req.UserID)
+	}
+	if req.To != "" {
+		query = query.Where("to_email ILIKE ?", "%"+req.To+"%")
+	}
+	if req.ReferenceType != "" {
+		query = query.Where("reference_type = ?", req.ReferenceType)
+	}
+	if req.ReferenceID != "" {
+		query = query.Where("reference_id = ?", req.ReferenceID)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count email logs: %w", err)
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Order("created_at DESC").Offset(offset).Limit(req.Limit).Find(&logs).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch email logs: %w", err)
+	}
+	return logs, total, nil
+}
+
+func (r *repository) FindUserByID(ctx context.Context, userID string) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).Where("id = ?", userID).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
@@ -0,0 +1,19 @@
+package emails
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/middleware"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
+	// Posted by the email provider, authenticated by the token query parameter.
+	router.POST("/emails/webhooks/:provider", handler.DeliveryWebhook)
+
+	admin := router.Group("/admin/emails")
+	admin.Use(authMiddleware)
+	admin.Use(middleware.RequireAdmin())
+	{
+		admin.GET("", handler.ListEmailLogs)
+		admin.GET("/:id", handler.GetEmailLog)
+	}
+}
@@ -0,0 +1,101 @@
+package emails
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+type sendGridTransport struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+func newSendGridTransport(baseURL, apiKey string, client *http.Client) *sendGridTransport {
+	return &sendGridTransport{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		client:  client,
+	}
+}
+
+type sendGridAddress struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+type sendGridAttachment struct {
+	Content     string `json:"content"`
+	Type        string `json:"type"`
+	Filename    string `json:"filename"`
+	Disposition string `json:"disposition"`
+}
+
+func (t *sendGridTransport) Name() string {
+	return ProviderSendGrid
+}
+
+func (t *sendGridTransport) Send(ctx context.Context, msg Message) (string, error) {
+	content := []map[string]string{{"type": "text/plain", "value": msg.Text}}
+	if msg.HTML != "" {
+		content = append(content, map[string]string{"type": "text/html", "value": msg.HTML})
+	}
+
+	personalization := map[string]interface{}{
+		"to": []sendGridAddress{{Email: msg.To, Name: msg.ToName}},
+	}
+	if msg.LogID != "" {
+		// Echoed back on every event webhook for the message.
+		personalization["custom_args"] = map[string]string{logIDTag: msg.LogID}
+	}
+
+	payload := map[string]interface{}{
+		"personalizations": []map[string]interface{}{personalization},
+		"from":             sendGridAddress{Email: msg.From, Name: msg.FromName},
+		"subject":          msg.Subject,
+		"content":          content,
+	}
+	if len(msg.Attachments) > 0 {
+		attachments := make([]sendGridAttachment, len(msg.Attachments))
+		for i, a := range msg.Attachments {
+			attachments[i] = sendGridAttachment{
+				Content:     base64.StdEncoding.EncodeToString(a.Content),
+				Type:        a.ContentType,
+				Filename:    a.Filename,
+				Disposition: "attachment",
+			}
+		}
+		payload["attachments"] = attachments
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.baseURL+"/v3/mail/send", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+t.apiKey)
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return "", fmt.Errorf("sendgrid returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	return resp.Header.Get("X-Message-Id"), nil
+}
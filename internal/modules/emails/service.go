@@ -0,0 +1,220 @@
+package emails
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/emails/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+const logIDTag = "email_log_id"
+
+var (
+	ErrNotConfigured = errors.New("email delivery is not configured")
+	// ErrOptedOut is returned when the user turned email off for the
+	// request's category. Nothing is sent or logged.
+	ErrOptedOut  = errors.New("user has turned off email for this category")
+	ErrNoAddress = errors.New("user has no email address")
+)
+
+// PreferenceChecker reports whether a user wants notifications of a category
+// on a channel.
+type PreferenceChecker interface {
+	Allows(ctx context.Context, userID uuid.UUID, category models.NotificationCategory, channel models.NotificationChannel) bool
+}
+
+// SendRequest is one templated email. Data fills the template; the
+// recipient's name is available as .Name and the sender's as .AppName. When
+// Category is set and the recipient is a user, their email preference for it
+// is honoured; leave it empty for mail the user asked for or must get.
+type SendRequest struct {
+	UserID        string
+	To            string
+	ToName        string
+	Template      string
+	Data          map[string]interface{}
+	Attachments   []Attachment
+	Category      models.NotificationCategory
+	ReferenceType string
+	ReferenceID   string
+}
+
+// Mailer is the part of Service other modules send email through.
+type Mailer interface {
+	// Enabled reports whether a transport is configured.
+	Enabled() bool
+	// Send renders and sends req, recording the attempt in the send log.
+	// The log entry is returned even when sending failed.
+	Send(ctx context.Context, req SendRequest) (*models.EmailLog, error)
+	// SendToUser fills in the user's address and name before sending.
+	SendToUser(ctx context.Context, userID string, req SendRequest) (*models.EmailLog, error)
+}
+
+type Service interface {
+	Mailer
+
+	ListLogs(ctx context.Context, req dto.ListEmailLogsRequest) ([]*dto.EmailLogResponse, int64, error)
+	GetLog(ctx context.Context, id string) (*dto.EmailLogResponse, error)
+	HandleWebhook(ctx context.Context, provider, token string, payload []byte) error
+
+	SetPreferenceChecker(checker PreferenceChecker)
+}
+
+type service struct {
+	repo      Repository
+	transport Transport
+	cfg       config.EmailConfig
+	prefs     PreferenceChecker
+}
+
+func NewService(repo Repository, cfg config.EmailConfig) Service {
+	transport := NewTransport(cfg)
+	if transport != nil {
+		logger.Info("email transport configured", "provider", transport.Name())
+	}
+	return &service{repo: repo, transport: transport, cfg: cfg}
+}
+
+func (s *service) SetPreferenceChecker(checker PreferenceChecker) {
+	s.prefs = checker
+}
+
+func (s *service) Enabled() bool {
+	return s.transport != nil
+}
+
+func (s *service) Send(ctx context.Context, req SendRequest) (*models.EmailLog, error) {
+	if s.transport == nil {
+		return nil, ErrNotConfigured
+	}
+	if strings.TrimSpace(req.To) == "" {
+		return nil, ErrNoAddress
+	}
+	if req.Category != "" && req.UserID != "" && s.prefs != nil {
+		if id, err := uuid.Parse(req.UserID); err == nil && !s.prefs.Allows(ctx, id, req.Category, models.ChannelEmail) {
+			logger.Info("email skipped by user preference", "userID", req.UserID, "template", req.Template, "category", req.Category)
+			return nil, ErrOptedOut
+		}
+	}
+
+	data := make(map[string]interface{}, len(req.Data)+2)
+	for k, v := range req.Data {
+		data[k] = v
+	}
+	data["AppName"] = s.cfg.FromName
+	data["Name"] = req.ToName
+	if req.ToName == "" {
+		data["Name"] = "there"
+	}
+
+	rendered, err := renderTemplate(req.Template, data)
+	if err != nil {
+		logger.Error("failed to render email", "error", err, "template", req.Template)
+		return nil, err
+	}
+
+	log := &models.EmailLog{
+		ToEmail:       req.To,
+		Template:      req.Template,
+		Subject:       rendered.Subject,
+		Provider:      s.transport.Name(),
+		Status:        models.EmailStatusQueued,
+		ReferenceType: req.ReferenceType,
+		ReferenceID:   req.ReferenceID,
+	}
+	if req.UserID != "" {
+		log.UserID = &req.UserID
+	}
+	if err := s.repo.CreateLog(ctx, log); err != nil {
+		// Losing the log entry is no reason to hold back the email.
+		logger.Error("failed to record email", "error", err, "template", req.Template)
+	}
+
+	messageID, sendErr := s.transport.Send(ctx, Message{
+		LogID:       log.ID,
+		From:        s.cfg.FromEmail,
+		FromName:    s.cfg.FromName,
+		To:          req.To,
+		ToName:      req.ToName,
+		Subject:     rendered.Subject,
+		Text:        rendered.Text,
+		HTML:        rendered.HTML,
+		Attachments: req.Attachments,
+	})
+
+	now := time.Now()
+	log.StatusUpdatedAt = &now
+	if sendErr != nil {
+		log.Status = models.EmailStatusFailed
+		log.Error = sendErr.Error()
+		logger.Error("failed to send email", "error", sendErr, "template", req.Template, "provider", log.Provider, "logID", log.ID)
+	} else {
+		log.Status = models.EmailStatusSent
+		log.SentAt = &now
+		if messageID != "" {
+			log.ProviderMessageID = &messageID
+		}
+		logger.Info("email sent", "template", req.Template, "provider", log.Provider, "logID", log.ID)
+	}
+	if log.ID != "" {
+		if err := s.repo.UpdateLog(ctx, log); err != nil {
+			logger.Error("failed to update email log", "error", err, "logID", log.ID)
+		}
+	}
+
+	return log, sendErr
+}
+
+func (s *service) SendToUser(ctx context.Context, userID string, req SendRequest) (*models.EmailLog, error) {
+	if s.transport == nil {
+		return nil, ErrNotConfigured
+	}
+	user, err := s.repo.FindUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Email == nil || *user.Email == "" {
+		return nil, ErrNoAddress
+	}
+
+	req.UserID = user.ID
+	req.To = *user.Email
+	if req.ToName == "" {
+		req.ToName = user.Name
+	}
+	return s.Send(ctx, req)
+}
+
+func (s *service) ListLogs(ctx context.Context, req dto.ListEmailLogsRequest) ([]*dto.EmailLogResponse, int64, error) {
+	req.SetDefaults()
+
+	logs, total, err := s.repo.ListLogs(ctx, req)
+	if err != nil {
+		return nil, 0, response.InternalServerError("Failed to fetch email logs", err)
+	}
+
+	result := make([]*dto.EmailLogResponse, 0, len(logs))
+	for _, log := range logs {
+		result = append(result, dto.ToEmailLogResponse(log))
+	}
+	return result, total, nil
+}
+
+func (s *service) GetLog(ctx context.Context, id string) (*dto.EmailLogResponse, error) {
+	log, err := s.repo.FindLogByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Email")
+		}
+		return nil, response.InternalServerError("Failed to fetch email", err)
+	}
+	return dto.ToEmailLogResponse(log), nil
+}
@@ -0,0 +1,88 @@
+package emails
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"path"
+	"strings"
+	texttemplate "text/template"
+)
+
+const (
+	TemplateSignupVerification = "signup_verification"
+	TemplateRideReceipt        = "ride_receipt"
+	TemplateProviderApproved   = "provider_approved"
+	TemplateProviderRejected   = "provider_rejected"
+	TemplatePayoutStatement    = "payout_statement"
+	TemplateDisputeUpdate      = "dispute_update"
+)
+
+// Each template file defines "subject", "text" and "html". layout.tmpl holds
+// the parts every HTML email shares.
+//
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+type emailTemplate struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+var templates = map[string]*emailTemplate{}
+
+func init() {
+	entries, err := templateFiles.ReadDir("templates")
+	if err != nil {
+		panic(fmt.Sprintf("emails: read templates: %v", err))
+	}
+	layout := path.Join("templates", "layout.tmpl")
+	for _, entry := range entries {
+		if entry.Name() == "layout.tmpl" {
+			continue
+		}
+		name := strings.TrimSuffix(entry.Name(), ".tmpl")
+		file := path.Join("templates", entry.Name())
+
+		text, err := texttemplate.New(name).ParseFS(templateFiles, file)
+		if err != nil {
+			panic(fmt.Sprintf("emails: parse %s: %v", entry.Name(), err))
+		}
+		html, err := htmltemplate.New(name).ParseFS(templateFiles, layout, file)
+		if err != nil {
+			panic(fmt.Sprintf("emails: parse %s: %v", entry.Name(), err))
+		}
+		templates[name] = &emailTemplate{text: text, html: html}
+	}
+}
+
+type renderedEmail struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+func renderTemplate(name string, data map[string]interface{}) (*renderedEmail, error) {
+	tmpl, ok := templates[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown email template %q", name)
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := tmpl.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, fmt.Errorf("render %s subject: %w", name, err)
+	}
+	if err := tmpl.text.ExecuteTemplate(&text, "text", data); err != nil {
+		return nil, fmt.Errorf("render %s text: %w", name, err)
+	}
+	if err := tmpl.html.ExecuteTemplate(&html, "html", data); err != nil {
+		return nil, fmt.Errorf("render %s html: %w", name, err)
+	}
+
+	return &renderedEmail{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+		HTML:    html.String(),
+	}, nil
+}
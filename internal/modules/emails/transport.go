@@ -0,0 +1,60 @@
+package emails
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+)
+
+const (
+	ProviderNone     = "none"
+	ProviderMock     = "mock"
+	ProviderSendGrid = "sendgrid"
+	ProviderSES      = "ses"
+)
+
+// Message is a rendered email ready to hand to a transport. LogID is the
+// send log entry it belongs to; transports tag the message with it so
+// delivery webhooks can be matched back to the log.
+type Message struct {
+	LogID       string
+	From        string
+	FromName    string
+	To          string
+	ToName      string
+	Subject     string
+	Text        string
+	HTML        string
+	Attachments []Attachment
+}
+
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+// Transport delivers email through one provider. Send returns the
+// provider's id for the message when it reports one.
+type Transport interface {
+	Name() string
+	Send(ctx context.Context, msg Message) (string, error)
+}
+
+// NewTransport returns nil when email is turned off.
+func NewTransport(cfg config.EmailConfig) Transport {
+	client := &http.Client{Timeout: 15 * time.Second}
+
+	switch cfg.Provider {
+	case ProviderSendGrid:
+		return newSendGridTransport(cfg.SendGridAPIURL, cfg.SendGridAPIKey, client)
+	case ProviderSES:
+		return newSESTransport(cfg.SESRegion, cfg.SESEndpoint, cfg.SESAccessKey, cfg.SESSecretKey, client)
+	case ProviderMock:
+		return newMockTransport()
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,247 @@
+package emails
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+// deliveryEvent is a status change reported by a provider. LogID comes from
+// the tag set when sending; MessageID is the fallback for older messages.
+type deliveryEvent struct {
+	LogID     string
+	MessageID string
+	Status    models.EmailStatus
+	Detail    string
+	At        time.Time
+}
+
+// HandleWebhook applies the delivery events a provider posts back. Providers
+// cannot sign these requests in a way both support, so the endpoint is
+// protected by a shared secret in the URL. Events for unknown emails are
+// ignored rather than failed so the provider does not keep retrying them.
+func (s *service) HandleWebhook(ctx context.Context, provider, token string, payload []byte) error {
+	if s.cfg.WebhookSecret == "" {
+		return response.ServiceUnavailable("Email webhooks are not configured")
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.cfg.WebhookSecret)) != 1 {
+		return response.UnauthorizedError("Invalid webhook token")
+	}
+
+	var events []deliveryEvent
+	var err error
+	switch provider {
+	case ProviderSendGrid:
+		events, err = parseSendGridEvents(payload)
+	case ProviderSES:
+		events, err = s.parseSESNotification(ctx, payload)
+	default:
+		return response.NotFoundError("Email provider")
+	}
+	if err != nil {
+		return response.BadRequest("Invalid webhook payload")
+	}
+
+	for _, event := range events {
+		s.applyDeliveryEvent(ctx, provider, event)
+	}
+	return nil
+}
+
+func (s *service) applyDeliveryEvent(ctx context.Context, provider string, event deliveryEvent) {
+	var log *models.EmailLog
+	var err error
+	switch {
+	case event.LogID != "":
+		log, err = s.repo.FindLogByID(ctx, event.LogID)
+	case event.MessageID != "":
+		log, err = s.repo.FindLogByProviderMessageID(ctx, provider, event.MessageID)
+	default:
+		return
+	}
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Error("failed to look up email for delivery event", "error", err, "provider", provider, "logID", event.LogID)
+		}
+		return
+	}
+
+	// A complaint can follow delivery; nothing else changes a final status.
+	if log.Status.IsFinal() && event.Status != models.EmailStatusComplaint {
+		return
+	}
+	if log.StatusUpdatedAt != nil && event.At.Before(*log.StatusUpdatedAt) && !event.Status.IsFinal() {
+		return
+	}
+
+	log.Status = event.Status
+	log.StatusUpdatedAt = &event.At
+	if event.Detail != "" {
+		log.Error = event.Detail
+	}
+	if err := s.repo.UpdateLog(ctx, log); err != nil {
+		logger.Error("failed to update email status", "error", err, "logID", log.ID)
+		return
+	}
+	logger.Info("email status updated", "logID", log.ID, "status", event.Status, "provider", provider)
+}
+
+var sendGridStatuses = map[string]models.EmailStatus{
+	"delivered":  models.EmailStatusDelivered,
+	"bounce":     models.EmailStatusBounced,
+	"blocked":    models.EmailStatusBounced,
+	"dropped":    models.EmailStatusDropped,
+	"spamreport": models.EmailStatusComplaint,
+}
+
+// parseSendGridEvents reads a SendGrid event webhook batch. Custom args set
+// when sending appear as top-level fields of each event.
+func parseSendGridEvents(payload []byte) ([]deliveryEvent, error) {
+	var raw []map[string]interface{}
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil, err
+	}
+
+	events := make([]deliveryEvent, 0, len(raw))
+	for _, item := range raw {
+		name, _ := item["event"].(string)
+		status, ok := sendGridStatuses[name]
+		if !ok {
+			continue
+		}
+
+		event := deliveryEvent{Status: status, At: time.Now()}
+		event.LogID, _ = item[logIDTag].(string)
+		if messageID, ok := item["sg_message_id"].(string); ok {
+			// sg_message_id is the X-Message-Id returned on send plus a
+			// per-recipient suffix.
+			event.MessageID, _, _ = strings.Cut(messageID, ".")
+		}
+		if ts, ok := item["timestamp"].(float64); ok {
+			event.At = time.Unix(int64(ts), 0)
+		}
+		if reason, ok := item["reason"].(string); ok {
+			event.Detail = reason
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+type snsEnvelope struct {
+	Type         string `json:"Type"`
+	Message      string `json:"Message"`
+	SubscribeURL string `json:"SubscribeURL"`
+}
+
+type sesEvent struct {
+	EventType        string `json:"eventType"`
+	NotificationType string `json:"notificationType"`
+	Mail             struct {
+		MessageID string              `json:"messageId"`
+		Tags      map[string][]string `json:"tags"`
+	} `json:"mail"`
+	Bounce struct {
+		BounceType string `json:"bounceType"`
+		Timestamp  string `json:"timestamp"`
+	} `json:"bounce"`
+	Complaint struct {
+		Timestamp string `json:"timestamp"`
+	} `json:"complaint"`
+	Delivery struct {
+		Timestamp string `json:"timestamp"`
+	} `json:"delivery"`
+	Reject struct {
+		Reason string `json:"reason"`
+	} `json:"reject"`
+}
+
+// parseSESNotification reads an SES event published through SNS. The SNS
+// subscription is confirmed the first time it posts here.
+func (s *service) parseSESNotification(ctx context.Context, payload []byte) ([]deliveryEvent, error) {
+	var envelope snsEnvelope
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return nil, err
+	}
+
+	switch envelope.Type {
+	case "SubscriptionConfirmation":
+		confirmSNSSubscription(ctx, envelope.SubscribeURL)
+		return nil, nil
+	case "Notification":
+	default:
+		return nil, nil
+	}
+
+	var event sesEvent
+	if err := json.Unmarshal([]byte(envelope.Message), &event); err != nil {
+		return nil, err
+	}
+
+	kind := event.EventType
+	if kind == "" {
+		kind = event.NotificationType
+	}
+
+	result := deliveryEvent{MessageID: event.Mail.MessageID, At: time.Now()}
+	if tags := event.Mail.Tags[logIDTag]; len(tags) > 0 {
+		result.LogID = tags[0]
+	}
+
+	var timestamp string
+	switch kind {
+	case "Delivery":
+		result.Status = models.EmailStatusDelivered
+		timestamp = event.Delivery.Timestamp
+	case "Bounce":
+		// Transient bounces are retried by SES and may still be delivered.
+		if event.Bounce.BounceType != "Permanent" {
+			return nil, nil
+		}
+		result.Status = models.EmailStatusBounced
+		result.Detail = "permanent bounce"
+		timestamp = event.Bounce.Timestamp
+	case "Complaint":
+		result.Status = models.EmailStatusComplaint
+		timestamp = event.Complaint.Timestamp
+	case "Reject":
+		result.Status = models.EmailStatusDropped
+		result.Detail = event.Reject.Reason
+	default:
+		return nil, nil
+	}
+	if at, err := time.Parse(time.RFC3339, timestamp); err == nil {
+		result.At = at
+	}
+	return []deliveryEvent{result}, nil
+}
+
+func confirmSNSSubscription(ctx context.Context, subscribeURL string) {
+	u, err := url.Parse(subscribeURL)
+	if err != nil || u.Scheme != "https" || !strings.HasSuffix(u.Hostname(), ".amazonaws.com") {
+		logger.Warn("ignoring sns subscription with unexpected url", "url", subscribeURL)
+		return
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return
+	}
+	resp, err := (&http.Client{Timeout: 10 * time.Second}).Do(req)
+	if err != nil {
+		logger.Error("failed to confirm sns subscription", "error", err)
+		return
+	}
+	resp.Body.Close()
+	logger.Info("sns subscription confirmed for email events", "status", resp.StatusCode)
+}
@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"errors"
+	"strconv"
+)
+
+type ListEventsRequest struct {
+	Since string `form:"since" binding:"omitempty,max=20"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=500"`
+}
+
+func (r *ListEventsRequest) Validate() error {
+	if _, err := r.SinceID(); err != nil {
+		return errors.New("since must be a cursor returned by a previous call or websocket event")
+	}
+	return nil
+}
+
+// SinceID parses the cursor. An empty cursor starts from the oldest retained
+// event.
+func (r *ListEventsRequest) SinceID() (int64, error) {
+	if r.Since == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseInt(r.Since, 10, 64)
+	if err != nil || id < 0 {
+		return 0, errors.New("invalid cursor")
+	}
+	return id, nil
+}
@@ -0,0 +1,40 @@
+package dto
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+type EventResponse struct {
+	Cursor    string                 `json:"cursor"`
+	Type      string                 `json:"type"`
+	MessageID string                 `json:"messageId,omitempty"`
+	Data      map[string]interface{} `json:"data"`
+	CreatedAt time.Time              `json:"createdAt"`
+}
+
+type EventPageResponse struct {
+	Events     []*EventResponse `json:"events"`
+	NextCursor string           `json:"nextCursor"`
+	HasMore    bool             `json:"hasMore"`
+	// ResyncRequired is set when the cursor points before the retention
+	// window, so events may have been purged and the client should refetch
+	// ride and order state instead of relying on the log.
+	ResyncRequired bool `json:"resyncRequired"`
+}
+
+func ToEventResponse(event *models.UserEvent) *EventResponse {
+	return &EventResponse{
+		Cursor:    FormatCursor(event.ID),
+		Type:      event.Type,
+		MessageID: event.MessageID,
+		Data:      event.Payload,
+		CreatedAt: event.CreatedAt,
+	}
+}
+
+func FormatCursor(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
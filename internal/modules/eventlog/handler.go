@@ -0,0 +1,43 @@
+package eventlog
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/modules/eventlog/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// ListEvents godoc
+// @Summary Replay ride and order events
+// @Description Returns events pushed to the user over the websocket after the given cursor, oldest first. Pass nextCursor back as since to page. When resyncRequired is true the cursor is older than the retention window and state should be refetched
+// @Tags events
+// @Security BearerAuth
+// @Produce json
+// @Param since query string false "Cursor from a previous page or websocket event"
+// @Param limit query int false "Page size (1-500, default 100)"
+// @Success 200 {object} response.Response{data=dto.EventPageResponse}
+// @Router /events [get]
+func (h *Handler) ListEvents(c *gin.Context) {
+	var req dto.ListEventsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+
+	page, err := h.service.ListEvents(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, page, "Events retrieved successfully")
+}
@@ -0,0 +1,58 @@
+package eventlog
+
+import (
+	"context"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Create(ctx context.Context, event *models.UserEvent) error
+	ListSince(ctx context.Context, userID string, sinceID int64, limit int) ([]*models.UserEvent, error)
+	OldestID(ctx context.Context) (int64, error)
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Create(ctx context.Context, event *models.UserEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+func (r *repository) ListSince(ctx context.Context, userID string, sinceID int64, limit int) ([]*models.UserEvent, error) {
+	var events []*models.UserEvent
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND id > ?", userID, sinceID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&events).Error
+	return events, err
+}
+
+// OldestID returns the smallest ID still in the log, or 0 when it is empty.
+func (r *repository) OldestID(ctx context.Context) (int64, error) {
+	var id *int64
+	err := r.db.WithContext(ctx).
+		Model(&models.UserEvent{}).
+		Select("MIN(id)").
+		Scan(&id).Error
+	if err != nil || id == nil {
+		return 0, err
+	}
+	return *id, nil
+}
+
+func (r *repository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("created_at < ?", before).
+		Delete(&models.UserEvent{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,12 @@
+package eventlog
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
+	events := router.Group("/events", authMiddleware)
+	{
+		events.GET("", handler.ListEvents)
+	}
+}
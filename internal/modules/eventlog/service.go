@@ -0,0 +1,124 @@
+package eventlog
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/eventlog/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+)
+
+const (
+	defaultPageSize = 100
+	eventRetention  = 7 * 24 * time.Hour
+)
+
+// Only ride and order state changes are logged. Chat, presence and location
+// streams are high volume and have their own history endpoints.
+var replayablePrefixes = []string{"ride_", "order_", "laundry_"}
+
+type Service interface {
+	Record(ctx context.Context, userID string, msg *websocket.Message) (string, error)
+	ListEvents(ctx context.Context, userID string, req dto.ListEventsRequest) (*dto.EventPageResponse, error)
+	PurgeExpired(ctx context.Context) (int64, error)
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+func isReplayable(msgType websocket.MessageType) bool {
+	for _, prefix := range replayablePrefixes {
+		if strings.HasPrefix(string(msgType), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Record implements websocket.EventRecorder.
+func (s *service) Record(ctx context.Context, userID string, msg *websocket.Message) (string, error) {
+	if !isReplayable(msg.Type) {
+		return "", nil
+	}
+
+	event := &models.UserEvent{
+		UserID:    userID,
+		Type:      string(msg.Type),
+		MessageID: msg.MessageID,
+		Payload:   models.UserEventPayload(msg.Data),
+	}
+	if err := s.repo.Create(ctx, event); err != nil {
+		return "", err
+	}
+
+	return dto.FormatCursor(event.ID), nil
+}
+
+func (s *service) ListEvents(ctx context.Context, userID string, req dto.ListEventsRequest) (*dto.EventPageResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	sinceID, _ := req.SinceID()
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+
+	resync := false
+	if sinceID > 0 {
+		oldestID, err := s.repo.OldestID(ctx)
+		if err != nil {
+			logger.Error("failed to read event log bounds", "error", err)
+			return nil, response.InternalServerError("Failed to get events", err)
+		}
+		resync = oldestID > 0 && sinceID < oldestID-1
+	}
+
+	// Fetch one extra row to know whether another page follows.
+	events, err := s.repo.ListSince(ctx, userID, sinceID, limit+1)
+	if err != nil {
+		logger.Error("failed to list events", "error", err, "userID", userID, "since", sinceID)
+		return nil, response.InternalServerError("Failed to get events", err)
+	}
+
+	hasMore := len(events) > limit
+	if hasMore {
+		events = events[:limit]
+	}
+
+	page := &dto.EventPageResponse{
+		Events:         make([]*dto.EventResponse, 0, len(events)),
+		NextCursor:     req.Since,
+		HasMore:        hasMore,
+		ResyncRequired: resync,
+	}
+	for _, event := range events {
+		page.Events = append(page.Events, dto.ToEventResponse(event))
+	}
+	if len(events) > 0 {
+		page.NextCursor = dto.FormatCursor(events[len(events)-1].ID)
+	}
+
+	return page, nil
+}
+
+func (s *service) PurgeExpired(ctx context.Context) (int64, error) {
+	deleted, err := s.repo.DeleteBefore(ctx, time.Now().Add(-eventRetention))
+	if err != nil {
+		return 0, err
+	}
+	if deleted > 0 {
+		logger.Info("purged expired user events", "count", deleted)
+	}
+	return deleted, nil
+}
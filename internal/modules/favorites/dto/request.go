@@ -0,0 +1,11 @@
+package dto
+
+type AddFavoriteDriverRequest struct {
+	DriverID string `json:"driverId" binding:"required,uuid"`
+	Note     string `json:"note" binding:"omitempty,max=255"`
+}
+
+type AddPreferredProviderRequest struct {
+	ProviderID string `json:"providerId" binding:"required,uuid"`
+	Note       string `json:"note" binding:"omitempty,max=255"`
+}
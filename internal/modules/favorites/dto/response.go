@@ -0,0 +1,81 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+type FavoriteDriverResponse struct {
+	DriverID        string    `json:"driverId"`
+	Name            string    `json:"name"`
+	ProfilePhotoURL *string   `json:"profilePhotoUrl,omitempty"`
+	Rating          float64   `json:"rating"`
+	TotalTrips      int       `json:"totalTrips"`
+	Note            string    `json:"note,omitempty"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+type PreferredProviderResponse struct {
+	ProviderID      string    `json:"providerId"`
+	Name            string    `json:"name"`
+	BusinessName    *string   `json:"businessName,omitempty"`
+	ServiceCategory string    `json:"serviceCategory"`
+	Rating          float64   `json:"rating"`
+	CompletedJobs   int       `json:"completedJobs"`
+	IsAvailable     bool      `json:"isAvailable"`
+	Note            string    `json:"note,omitempty"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+func ToFavoriteDriverResponse(fav *models.FavoriteDriver) *FavoriteDriverResponse {
+	resp := &FavoriteDriverResponse{
+		DriverID:  fav.DriverID,
+		Note:      fav.Note,
+		CreatedAt: fav.CreatedAt,
+	}
+	if fav.Driver != nil {
+		resp.Name = fav.Driver.Name
+		resp.ProfilePhotoURL = fav.Driver.ProfilePhotoURL
+	}
+	if fav.DriverProfile != nil {
+		resp.Rating = fav.DriverProfile.Rating
+		resp.TotalTrips = fav.DriverProfile.TotalTrips
+	}
+	return resp
+}
+
+func ToFavoriteDriverResponses(favs []*models.FavoriteDriver) []*FavoriteDriverResponse {
+	responses := make([]*FavoriteDriverResponse, len(favs))
+	for i, fav := range favs {
+		responses[i] = ToFavoriteDriverResponse(fav)
+	}
+	return responses
+}
+
+func ToPreferredProviderResponse(pref *models.PreferredProvider) *PreferredProviderResponse {
+	resp := &PreferredProviderResponse{
+		ProviderID: pref.ProviderID,
+		Note:       pref.Note,
+		CreatedAt:  pref.CreatedAt,
+	}
+	if p := pref.Provider; p != nil {
+		if p.User != nil {
+			resp.Name = p.User.Name
+		}
+		resp.BusinessName = p.BusinessName
+		resp.ServiceCategory = p.ServiceCategory
+		resp.Rating = p.Rating
+		resp.CompletedJobs = p.CompletedJobs
+		resp.IsAvailable = p.IsAvailable
+	}
+	return resp
+}
+
+func ToPreferredProviderResponses(prefs []*models.PreferredProvider) []*PreferredProviderResponse {
+	responses := make([]*PreferredProviderResponse, len(prefs))
+	for i, pref := range prefs {
+		responses[i] = ToPreferredProviderResponse(pref)
+	}
+	return responses
+}
@@ -0,0 +1,147 @@
+package favorites
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/modules/favorites/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// ListFavoriteDrivers godoc
+// @Summary List favorite drivers
+// @Tags favorites
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=[]dto.FavoriteDriverResponse}
+// @Router /favorites/drivers [get]
+func (h *Handler) ListFavoriteDrivers(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	favorites, err := h.service.ListFavoriteDrivers(c.Request.Context(), userID.(string))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, favorites, "Favorite drivers retrieved successfully")
+}
+
+// AddFavoriteDriver godoc
+// @Summary Add a favorite driver
+// @Description Only drivers the rider has completed a ride with can be added. Favorite drivers nearby are offered the rider's rides first
+// @Tags favorites
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.AddFavoriteDriverRequest true "Driver user ID"
+// @Success 200 {object} response.Response{data=dto.FavoriteDriverResponse}
+// @Router /favorites/drivers [post]
+func (h *Handler) AddFavoriteDriver(c *gin.Context) {
+	var req dto.AddFavoriteDriverRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+
+	favorite, err := h.service.AddFavoriteDriver(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, favorite, "Favorite driver added successfully")
+}
+
+// RemoveFavoriteDriver godoc
+// @Summary Remove a favorite driver
+// @Tags favorites
+// @Security BearerAuth
+// @Produce json
+// @Param driverId path string true "Driver user ID"
+// @Success 200 {object} response.Response
+// @Router /favorites/drivers/{driverId} [delete]
+func (h *Handler) RemoveFavoriteDriver(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	if err := h.service.RemoveFavoriteDriver(c.Request.Context(), userID.(string), c.Param("driverId")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, nil, "Favorite driver removed successfully")
+}
+
+// ListPreferredProviders godoc
+// @Summary List preferred home-service providers
+// @Tags favorites
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=[]dto.PreferredProviderResponse}
+// @Router /favorites/providers [get]
+func (h *Handler) ListPreferredProviders(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	providers, err := h.service.ListPreferredProviders(c.Request.Context(), userID.(string))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, providers, "Preferred providers retrieved successfully")
+}
+
+// AddPreferredProvider godoc
+// @Summary Add a preferred home-service provider
+// @Description Only providers who have completed an order for the customer can be added. New orders are shown to free preferred providers before anyone else
+// @Tags favorites
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.AddPreferredProviderRequest true "Provider ID"
+// @Success 200 {object} response.Response{data=dto.PreferredProviderResponse}
+// @Router /favorites/providers [post]
+func (h *Handler) AddPreferredProvider(c *gin.Context) {
+	var req dto.AddPreferredProviderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+
+	provider, err := h.service.AddPreferredProvider(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, provider, "Preferred provider added successfully")
+}
+
+// RemovePreferredProvider godoc
+// @Summary Remove a preferred home-service provider
+// @Tags favorites
+// @Security BearerAuth
+// @Produce json
+// @Param providerId path string true "Provider ID"
+// @Success 200 {object} response.Response
+// @Router /favorites/providers/{providerId} [delete]
+func (h *Handler) RemovePreferredProvider(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	if err := h.service.RemovePreferredProvider(c.Request.Context(), userID.(string), c.Param("providerId")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, nil, "Preferred provider removed successfully")
+}
@@ -0,0 +1,139 @@
+package favorites
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	ListFavoriteDrivers(ctx context.Context, riderID string) ([]*models.FavoriteDriver, error)
+	GetFavoriteDriver(ctx context.Context, riderID, driverID string) (*models.FavoriteDriver, error)
+	CountFavoriteDrivers(ctx context.Context, riderID string) (int64, error)
+	CreateFavoriteDriver(ctx context.Context, fav *models.FavoriteDriver) error
+	DeleteFavoriteDriver(ctx context.Context, riderID, driverID string) (bool, error)
+	HasCompletedRide(ctx context.Context, riderID, driverID string) (bool, error)
+	GetFavoriteDriverProfileIDs(ctx context.Context, riderID string) ([]string, error)
+
+	ListPreferredProviders(ctx context.Context, customerID string) ([]*models.PreferredProvider, error)
+	GetPreferredProvider(ctx context.Context, customerID, providerID string) (*models.PreferredProvider, error)
+	CountPreferredProviders(ctx context.Context, customerID string) (int64, error)
+	CreatePreferredProvider(ctx context.Context, pref *models.PreferredProvider) error
+	DeletePreferredProvider(ctx context.Context, customerID, providerID string) (bool, error)
+	HasCompletedOrder(ctx context.Context, customerID, providerID string) (bool, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) ListFavoriteDrivers(ctx context.Context, riderID string) ([]*models.FavoriteDriver, error) {
+	var favs []*models.FavoriteDriver
+	err := r.db.WithContext(ctx).
+		Preload("Driver").
+		Preload("DriverProfile").
+		Where("rider_id = ?", riderID).
+		Order("created_at DESC").
+		Find(&favs).Error
+	return favs, err
+}
+
+func (r *repository) GetFavoriteDriver(ctx context.Context, riderID, driverID string) (*models.FavoriteDriver, error) {
+	var fav models.FavoriteDriver
+	err := r.db.WithContext(ctx).
+		Preload("Driver").
+		Preload("DriverProfile").
+		Where("rider_id = ? AND driver_id = ?", riderID, driverID).
+		First(&fav).Error
+	return &fav, err
+}
+
+func (r *repository) CountFavoriteDrivers(ctx context.Context, riderID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.FavoriteDriver{}).
+		Where("rider_id = ?", riderID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *repository) CreateFavoriteDriver(ctx context.Context, fav *models.FavoriteDriver) error {
+	return r.db.WithContext(ctx).Create(fav).Error
+}
+
+func (r *repository) DeleteFavoriteDriver(ctx context.Context, riderID, driverID string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("rider_id = ? AND driver_id = ?", riderID, driverID).
+		Delete(&models.FavoriteDriver{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *repository) HasCompletedRide(ctx context.Context, riderID, driverID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.Ride{}).
+		Where("rider_id = ? AND driver_id = ? AND status = ?", riderID, driverID, "completed").
+		Limit(1).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *repository) GetFavoriteDriverProfileIDs(ctx context.Context, riderID string) ([]string, error) {
+	var ids []string
+	err := r.db.WithContext(ctx).
+		Table("favorite_drivers fd").
+		Joins("JOIN driver_profiles dp ON dp.user_id = fd.driver_id AND dp.deleted_at IS NULL").
+		Where("fd.rider_id = ?", riderID).
+		Pluck("dp.id", &ids).Error
+	return ids, err
+}
+
+func (r *repository) ListPreferredProviders(ctx context.Context, customerID string) ([]*models.PreferredProvider, error) {
+	var prefs []*models.PreferredProvider
+	err := r.db.WithContext(ctx).
+		Preload("Provider.User").
+		Where("customer_id = ?", customerID).
+		Order("created_at DESC").
+		Find(&prefs).Error
+	return prefs, err
+}
+
+func (r *repository) GetPreferredProvider(ctx context.Context, customerID, providerID string) (*models.PreferredProvider, error) {
+	var pref models.PreferredProvider
+	err := r.db.WithContext(ctx).
+		Preload("Provider.User").
+		Where("customer_id = ? AND provider_id = ?", customerID, providerID).
+		First(&pref).Error
+	return &pref, err
+}
+
+func (r *repository) CountPreferredProviders(ctx context.Context, customerID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.PreferredProvider{}).
+		Where("customer_id = ?", customerID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *repository) CreatePreferredProvider(ctx context.Context, pref *models.PreferredProvider) error {
+	return r.db.WithContext(ctx).Create(pref).Error
+}
+
+func (r *repository) DeletePreferredProvider(ctx context.Context, customerID, providerID string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("customer_id = ? AND provider_id = ?", customerID, providerID).
+		Delete(&models.PreferredProvider{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *repository) HasCompletedOrder(ctx context.Context, customerID, providerID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.ServiceOrderNew{}).
+		Where("customer_id = ? AND assigned_provider_id = ? AND status = ?", customerID, providerID, "completed").
+		Limit(1).
+		Count(&count).Error
+	return count > 0, err
+}
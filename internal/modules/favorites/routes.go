@@ -0,0 +1,25 @@
+package favorites
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/middleware"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
+	favorites := router.Group("/favorites", authMiddleware)
+	{
+		drivers := favorites.Group("/drivers", middleware.RequireRider())
+		{
+			drivers.GET("", handler.ListFavoriteDrivers)
+			drivers.POST("", handler.AddFavoriteDriver)
+			drivers.DELETE("/:driverId", handler.RemoveFavoriteDriver)
+		}
+
+		providers := favorites.Group("/providers")
+		{
+			providers.GET("", handler.ListPreferredProviders)
+			providers.POST("", handler.AddPreferredProvider)
+			providers.DELETE("/:providerId", handler.RemovePreferredProvider)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package favorites
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/favorites/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	ListFavoriteDrivers(ctx context.Context, riderID string) ([]*dto.FavoriteDriverResponse, error)
+	AddFavoriteDriver(ctx context.Context, riderID string, req dto.AddFavoriteDriverRequest) (*dto.FavoriteDriverResponse, error)
+	RemoveFavoriteDriver(ctx context.Context, riderID, driverID string) error
+
+	ListPreferredProviders(ctx context.Context, customerID string) ([]*dto.PreferredProviderResponse, error)
+	AddPreferredProvider(ctx context.Context, customerID string, req dto.AddPreferredProviderRequest) (*dto.PreferredProviderResponse, error)
+	RemovePreferredProvider(ctx context.Context, customerID, providerID string) error
+
+	// FavoriteDriverProfileIDs is used by dispatch to favor the rider's
+	// drivers. Lookup failures are logged and treated as having none.
+	FavoriteDriverProfileIDs(ctx context.Context, riderID string) map[string]bool
+}
+
+type service struct {
+	repo Repository
+	cfg  config.FavoritesConfig
+}
+
+func NewService(repo Repository, cfg config.FavoritesConfig) Service {
+	return &service{repo: repo, cfg: cfg}
+}
+
+func (s *service) ListFavoriteDrivers(ctx context.Context, riderID string) ([]*dto.FavoriteDriverResponse, error) {
+	favs, err := s.repo.ListFavoriteDrivers(ctx, riderID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to list favorite drivers", err)
+	}
+	return dto.ToFavoriteDriverResponses(favs), nil
+}
+
+// AddFavoriteDriver favorites a driver the rider has completed a ride with.
+func (s *service) AddFavoriteDriver(ctx context.Context, riderID string, req dto.AddFavoriteDriverRequest) (*dto.FavoriteDriverResponse, error) {
+	if _, err := s.repo.GetFavoriteDriver(ctx, riderID, req.DriverID); err == nil {
+		return nil, response.ConflictError("Driver is already a favorite")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, response.InternalServerError("Failed to add favorite driver", err)
+	}
+
+	rode, err := s.repo.HasCompletedRide(ctx, riderID, req.DriverID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to add favorite driver", err)
+	}
+	if !rode {
+		return nil, response.BadRequest("You can only favorite drivers you have completed a ride with")
+	}
+
+	count, err := s.repo.CountFavoriteDrivers(ctx, riderID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to add favorite driver", err)
+	}
+	if int(count) >= s.cfg.MaxPerUser {
+		return nil, response.BadRequest(fmt.Sprintf("You can have at most %d favorite drivers", s.cfg.MaxPerUser))
+	}
+
+	fav := &models.FavoriteDriver{RiderID: riderID, DriverID: req.DriverID, Note: req.Note}
+	if err := s.repo.CreateFavoriteDriver(ctx, fav); err != nil {
+		return nil, response.InternalServerError("Failed to add favorite driver", err)
+	}
+
+	logger.Info("favorite driver added", "riderID", riderID, "driverID", req.DriverID)
+
+	fav, err = s.repo.GetFavoriteDriver(ctx, riderID, req.DriverID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to add favorite driver", err)
+	}
+	return dto.ToFavoriteDriverResponse(fav), nil
+}
+
+func (s *service) RemoveFavoriteDriver(ctx context.Context, riderID, driverID string) error {
+	removed, err := s.repo.DeleteFavoriteDriver(ctx, riderID, driverID)
+	if err != nil {
+		return response.InternalServerError("Failed to remove favorite driver", err)
+	}
+	if !removed {
+		return response.NotFoundError("Favorite driver")
+	}
+	return nil
+}
+
+func (s *service) ListPreferredProviders(ctx context.Context, customerID string) ([]*dto.PreferredProviderResponse, error) {
+	prefs, err := s.repo.ListPreferredProviders(ctx, customerID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to list preferred providers", err)
+	}
+	return dto.ToPreferredProviderResponses(prefs), nil
+}
+
+// AddPreferredProvider marks a provider who has completed an order for the
+// customer as preferred.
+func (s *service) AddPreferredProvider(ctx context.Context, customerID string, req dto.AddPreferredProviderRequest) (*dto.PreferredProviderResponse, error) {
+	if _, err := s.repo.GetPreferredProvider(ctx, customerID, req.ProviderID); err == nil {
+		return nil, response.ConflictError("Provider is already preferred")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, response.InternalServerError("Failed to add preferred provider", err)
+	}
+
+	served, err := s.repo.HasCompletedOrder(ctx, customerID, req.ProviderID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to add preferred provider", err)
+	}
+	if !served {
+		return nil, response.BadRequest("You can only prefer providers who have completed an order for you")
+	}
+
+	count, err := s.repo.CountPreferredProviders(ctx, customerID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to add preferred provider", err)
+	}
+	if int(count) >= s.cfg.MaxPerUser {
+		return nil, response.BadRequest(fmt.Sprintf("You can have at most %d preferred providers", s.cfg.MaxPerUser))
+	}
+
+	pref := &models.PreferredProvider{CustomerID: customerID, ProviderID: req.ProviderID, Note: req.Note}
+	if err := s.repo.CreatePreferredProvider(ctx, pref); err != nil {
+		return nil, response.InternalServerError("Failed to add preferred provider", err)
+	}
+
+	logger.Info("preferred provider added", "customerID", customerID, "providerID", req.ProviderID)
+
+	pref, err = s.repo.GetPreferredProvider(ctx, customerID, req.ProviderID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to add preferred provider", err)
+	}
+	return dto.ToPreferredProviderResponse(pref), nil
+}
+
+func (s *service) RemovePreferredProvider(ctx context.Context, customerID, providerID string) error {
+	removed, err := s.repo.DeletePreferredProvider(ctx, customerID, providerID)
+	if err != nil {
+		return response.InternalServerError("Failed to remove preferred provider", err)
+	}
+	if !removed {
+		return response.NotFoundError("Preferred provider")
+	}
+	return nil
+}
+
+func (s *service) FavoriteDriverProfileIDs(ctx context.Context, riderID string) map[string]bool {
+	ids, err := s.repo.GetFavoriteDriverProfileIDs(ctx, riderID)
+	if err != nil {
+		logger.Error("failed to get favorite drivers", "error", err, "riderID", riderID)
+		return nil
+	}
+	favorites := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		favorites[id] = true
+	}
+	return favorites
+}
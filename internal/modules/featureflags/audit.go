@@ -0,0 +1,33 @@
+package featureflags
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/audit"
+)
+
+func (s *service) SetAuditLogger(auditLogger audit.Recorder) {
+	s.auditLogger = auditLogger
+}
+
+func (s *service) record(ctx context.Context, adminID, action string, before, after *models.FeatureFlag) {
+	if s.auditLogger == nil {
+		return
+	}
+	entry := audit.Entry{
+		ActorID:    adminID,
+		ActorRole:  string(models.RoleAdmin),
+		Action:     action,
+		EntityType: "feature_flag",
+	}
+	if before != nil {
+		entry.EntityID = before.Key
+		entry.Before = before
+	}
+	if after != nil {
+		entry.EntityID = after.Key
+		entry.After = after
+	}
+	s.auditLogger.Record(ctx, entry)
+}
@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+var keyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]{0,63}$`)
+
+// ValidKey reports whether key is a usable flag key: lowercase letters,
+// digits, dots, dashes and underscores, conventionally "<module>.<setting>".
+func ValidKey(key string) bool {
+	return keyPattern.MatchString(key)
+}
+
+// SetFlagRequest creates a flag or replaces its value. RolloutPercent
+// defaults to 100 and only applies to boolean flags.
+type SetFlagRequest struct {
+	Type           string          `json:"type" binding:"required,oneof=boolean number string"`
+	Value          json.RawMessage `json:"value" binding:"required"`
+	RolloutPercent *int            `json:"rolloutPercent" binding:"omitempty,min=0,max=100"`
+	Description    string          `json:"description" binding:"max=500"`
+}
+
+// Decode checks that Value holds a value of Type and returns it.
+func (r *SetFlagRequest) Decode() (interface{}, error) {
+	if r.RolloutPercent != nil && *r.RolloutPercent != 100 && r.Type != models.FeatureFlagBoolean {
+		return nil, errors.New("rolloutPercent only applies to boolean flags")
+	}
+
+	switch r.Type {
+	case models.FeatureFlagBoolean:
+		var value bool
+		if err := json.Unmarshal(r.Value, &value); err != nil {
+			return nil, errors.New("value must be true or false")
+		}
+		return value, nil
+	case models.FeatureFlagNumber:
+		var value float64
+		if err := json.Unmarshal(r.Value, &value); err != nil {
+			return nil, errors.New("value must be a number")
+		}
+		return value, nil
+	default:
+		var value string
+		if err := json.Unmarshal(r.Value, &value); err != nil {
+			return nil, errors.New("value must be a string")
+		}
+		return value, nil
+	}
+}
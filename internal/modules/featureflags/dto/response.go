@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+type FeatureFlagResponse struct {
+	Key            string      `json:"key"`
+	Type           string      `json:"type"`
+	Value          interface{} `json:"value"`
+	RolloutPercent int         `json:"rolloutPercent"`
+	Description    string      `json:"description"`
+	UpdatedBy      *string     `json:"updatedBy,omitempty"`
+	CreatedAt      time.Time   `json:"createdAt"`
+	UpdatedAt      time.Time   `json:"updatedAt"`
+}
+
+func ToFeatureFlagResponse(flag *models.FeatureFlag) FeatureFlagResponse {
+	var value interface{}
+	_ = json.Unmarshal([]byte(flag.Value), &value)
+	return FeatureFlagResponse{
+		Key:            flag.Key,
+		Type:           flag.Type,
+		Value:          value,
+		RolloutPercent: flag.RolloutPercent,
+		Description:    flag.Description,
+		UpdatedBy:      flag.UpdatedBy,
+		CreatedAt:      flag.CreatedAt,
+		UpdatedAt:      flag.UpdatedAt,
+	}
+}
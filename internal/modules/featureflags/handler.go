@@ -0,0 +1,100 @@
+package featureflags
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/modules/featureflags/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// ListFlags godoc
+// @Summary List feature flags
+// @Description All runtime flags with their current values
+// @Tags admin-feature-flags
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=[]dto.FeatureFlagResponse}
+// @Router /admin/feature-flags [get]
+func (h *Handler) ListFlags(c *gin.Context) {
+	flags, err := h.service.ListFlags(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, flags, "Feature flags retrieved successfully")
+}
+
+// GetFlag godoc
+// @Summary Get a feature flag
+// @Tags admin-feature-flags
+// @Security BearerAuth
+// @Produce json
+// @Param key path string true "Flag key"
+// @Success 200 {object} response.Response{data=dto.FeatureFlagResponse}
+// @Router /admin/feature-flags/{key} [get]
+func (h *Handler) GetFlag(c *gin.Context) {
+	flag, err := h.service.GetFlag(c.Request.Context(), c.Param("key"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, flag, "Feature flag retrieved successfully")
+}
+
+// SetFlag godoc
+// @Summary Create or update a feature flag
+// @Description Sets the flag's value. Every API process picks the change up within seconds, without a redeploy. A rollout below 100 turns a boolean flag on for that percentage of subjects (for example riders)
+// @Tags admin-feature-flags
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param key path string true "Flag key"
+// @Param request body dto.SetFlagRequest true "Flag value"
+// @Success 200 {object} response.Response{data=dto.FeatureFlagResponse}
+// @Router /admin/feature-flags/{key} [put]
+func (h *Handler) SetFlag(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	var req dto.SetFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	flag, err := h.service.SetFlag(c.Request.Context(), adminID.(string), c.Param("key"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, flag, "Feature flag saved successfully")
+}
+
+// DeleteFlag godoc
+// @Summary Delete a feature flag
+// @Description Services fall back to their built-in default for the flag
+// @Tags admin-feature-flags
+// @Security BearerAuth
+// @Produce json
+// @Param key path string true "Flag key"
+// @Success 200 {object} response.Response
+// @Router /admin/feature-flags/{key} [delete]
+func (h *Handler) DeleteFlag(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	if err := h.service.DeleteFlag(c.Request.Context(), adminID.(string), c.Param("key")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, nil, "Feature flag deleted successfully")
+}
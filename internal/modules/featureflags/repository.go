@@ -0,0 +1,59 @@
+package featureflags
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository interface {
+	List(ctx context.Context) ([]*models.FeatureFlag, error)
+	FindByKey(ctx context.Context, key string) (*models.FeatureFlag, error)
+	// Save creates the flag or replaces its type, value, rollout and
+	// description.
+	Save(ctx context.Context, flag *models.FeatureFlag) error
+	// Delete returns gorm.ErrRecordNotFound when there is no such flag.
+	Delete(ctx context.Context, key string) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) List(ctx context.Context) ([]*models.FeatureFlag, error) {
+	var flags []*models.FeatureFlag
+	err := r.db.WithContext(ctx).Order("key").Find(&flags).Error
+	return flags, err
+}
+
+func (r *repository) FindByKey(ctx context.Context, key string) (*models.FeatureFlag, error) {
+	var flag models.FeatureFlag
+	if err := r.db.WithContext(ctx).Where("key = ?", key).First(&flag).Error; err != nil {
+		return nil, err
+	}
+	return &flag, nil
+}
+
+func (r *repository) Save(ctx context.Context, flag *models.FeatureFlag) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"type", "value", "rollout_percent", "description", "updated_by", "updated_at"}),
+	}).Create(flag).Error
+}
+
+func (r *repository) Delete(ctx context.Context, key string) error {
+	result := r.db.WithContext(ctx).Where("key = ?", key).Delete(&models.FeatureFlag{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,18 @@
+package featureflags
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/middleware"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
+	flags := router.Group("/admin/feature-flags")
+	flags.Use(authMiddleware)
+	flags.Use(middleware.RequireAdmin())
+	{
+		flags.GET("", handler.ListFlags)
+		flags.GET("/:key", handler.GetFlag)
+		flags.PUT("/:key", handler.SetFlag)
+		flags.DELETE("/:key", handler.DeleteFlag)
+	}
+}
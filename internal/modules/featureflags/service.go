@@ -0,0 +1,271 @@
+package featureflags
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"sync"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/audit"
+	"github.com/umar5678/go-backend/internal/modules/featureflags/dto"
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+// changedChannel tells every process to reload its flags after an admin
+// changes one.
+const changedChannel = "featureflags:changed"
+
+// Service serves feature flags from an in-memory snapshot, so reading one
+// costs no I/O. The accessors return def for flags that are missing, of
+// another type, or not loaded yet, which keeps callers working before the
+// first load and when a flag is deleted.
+type Service interface {
+	// Bool reads a boolean flag, ignoring its rollout.
+	Bool(ctx context.Context, key string, def bool) bool
+	// BoolFor reads a boolean flag for a subject such as a user ID. With a
+	// rollout below 100 the flag is on for a stable share of subjects.
+	BoolFor(ctx context.Context, key, subject string, def bool) bool
+	Float(ctx context.Context, key string, def float64) float64
+	String(ctx context.Context, key string, def string) string
+	// Reload replaces the snapshot with the flags in the database.
+	Reload(ctx context.Context) error
+
+	ListFlags(ctx context.Context) ([]dto.FeatureFlagResponse, error)
+	GetFlag(ctx context.Context, key string) (*dto.FeatureFlagResponse, error)
+	SetFlag(ctx context.Context, adminID, key string, req dto.SetFlagRequest) (*dto.FeatureFlagResponse, error)
+	DeleteFlag(ctx context.Context, adminID, key string) error
+
+	SetAuditLogger(auditLogger audit.Recorder)
+}
+
+type flag struct {
+	flagType string
+	value    interface{}
+	rollout  int
+}
+
+type service struct {
+	repo        Repository
+	auditLogger audit.Recorder
+
+	mu    sync.RWMutex
+	flags map[string]flag
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) lookup(key, flagType string) (flag, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	f, ok := s.flags[key]
+	if !ok || f.flagType != flagType {
+		return flag{}, false
+	}
+	return f, true
+}
+
+func (s *service) Bool(ctx context.Context, key string, def bool) bool {
+	f, ok := s.lookup(key, models.FeatureFlagBoolean)
+	if !ok {
+		return def
+	}
+	return f.value.(bool)
+}
+
+func (s *service) BoolFor(ctx context.Context, key, subject string, def bool) bool {
+	f, ok := s.lookup(key, models.FeatureFlagBoolean)
+	if !ok {
+		return def
+	}
+	if !f.value.(bool) {
+		return false
+	}
+	return f.rollout >= 100 || bucket(key, subject) < f.rollout
+}
+
+func (s *service) Float(ctx context.Context, key string, def float64) float64 {
+	f, ok := s.lookup(key, models.FeatureFlagNumber)
+	if !ok {
+		return def
+	}
+	return f.value.(float64)
+}
+
+func (s *service) String(ctx context.Context, key string, def string) string {
+	f, ok := s.lookup(key, models.FeatureFlagString)
+	if !ok {
+		return def
+	}
+	return f.value.(string)
+}
+
+// bucket places a subject in [0, 100) for a flag. Hashing the key with the
+// subject keeps rollouts of different flags independent.
+func bucket(key, subject string) int {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	h.Write([]byte{0})
+	h.Write([]byte(subject))
+	return int(h.Sum32() % 100)
+}
+
+func (s *service) Reload(ctx context.Context) error {
+	rows, err := s.repo.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	flags := make(map[string]flag, len(rows))
+	for _, row := range rows {
+		value, err := decodeValue(row)
+		if err != nil {
+			logger.Warn("skipping malformed feature flag", "key", row.Key, "error", err)
+			continue
+		}
+		flags[row.Key] = flag{flagType: row.Type, value: value, rollout: row.RolloutPercent}
+	}
+
+	s.mu.Lock()
+	s.flags = flags
+	s.mu.Unlock()
+	return nil
+}
+
+func decodeValue(row *models.FeatureFlag) (interface{}, error) {
+	switch row.Type {
+	case models.FeatureFlagBoolean:
+		var value bool
+		err := json.Unmarshal([]byte(row.Value), &value)
+		return value, err
+	case models.FeatureFlagNumber:
+		var value float64
+		err := json.Unmarshal([]byte(row.Value), &value)
+		return value, err
+	case models.FeatureFlagString:
+		var value string
+		err := json.Unmarshal([]byte(row.Value), &value)
+		return value, err
+	default:
+		return nil, errors.New("unknown flag type " + row.Type)
+	}
+}
+
+// changed reloads this process and notifies the others.
+func (s *service) changed(ctx context.Context, key string) {
+	if err := s.Reload(ctx); err != nil {
+		logger.Error("failed to reload feature flags", "error", err)
+	}
+	if err := cache.PublishMessage(ctx, changedChannel, map[string]string{"key": key}); err != nil {
+		logger.Warn("failed to announce feature flag change", "key", key, "error", err)
+	}
+}
+
+func (s *service) ListFlags(ctx context.Context) ([]dto.FeatureFlagResponse, error) {
+	flags, err := s.repo.List(ctx)
+	if err != nil {
+		logger.Error("failed to list feature flags", "error", err)
+		return nil, response.InternalServerError("Failed to list feature flags", err)
+	}
+
+	result := make([]dto.FeatureFlagResponse, 0, len(flags))
+	for _, flag := range flags {
+		result = append(result, dto.ToFeatureFlagResponse(flag))
+	}
+	return result, nil
+}
+
+func (s *service) GetFlag(ctx context.Context, key string) (*dto.FeatureFlagResponse, error) {
+	flag, err := s.repo.FindByKey(ctx, key)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Feature flag")
+		}
+		return nil, response.InternalServerError("Failed to load feature flag", err)
+	}
+
+	result := dto.ToFeatureFlagResponse(flag)
+	return &result, nil
+}
+
+func (s *service) SetFlag(ctx context.Context, adminID, key string, req dto.SetFlagRequest) (*dto.FeatureFlagResponse, error) {
+	if !dto.ValidKey(key) {
+		return nil, response.BadRequest("Flag keys use lowercase letters, digits, dots, dashes and underscores")
+	}
+	value, err := req.Decode()
+	if err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	before, err := s.repo.FindByKey(ctx, key)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, response.InternalServerError("Failed to load feature flag", err)
+	}
+
+	encoded, _ := json.Marshal(value)
+	flag := &models.FeatureFlag{
+		Key:            key,
+		Type:           req.Type,
+		Value:          string(encoded),
+		RolloutPercent: 100,
+		Description:    req.Description,
+	}
+	if req.RolloutPercent != nil {
+		flag.RolloutPercent = *req.RolloutPercent
+	}
+	if adminID != "" {
+		flag.UpdatedBy = &adminID
+	}
+
+	if err := s.repo.Save(ctx, flag); err != nil {
+		logger.Error("failed to save feature flag", "error", err, "key", key)
+		return nil, response.InternalServerError("Failed to save feature flag", err)
+	}
+
+	saved, err := s.repo.FindByKey(ctx, key)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to load feature flag", err)
+	}
+
+	logger.Info("feature flag set",
+		"key", key,
+		"type", saved.Type,
+		"value", saved.Value,
+		"rolloutPercent", saved.RolloutPercent,
+		"adminId", adminID,
+	)
+	s.record(ctx, adminID, "feature_flag.set", before, saved)
+	s.changed(ctx, key)
+
+	result := dto.ToFeatureFlagResponse(saved)
+	return &result, nil
+}
+
+func (s *service) DeleteFlag(ctx context.Context, adminID, key string) error {
+	before, err := s.repo.FindByKey(ctx, key)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.NotFoundError("Feature flag")
+		}
+		return response.InternalServerError("Failed to load feature flag", err)
+	}
+
+	if err := s.repo.Delete(ctx, key); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.NotFoundError("Feature flag")
+		}
+		logger.Error("failed to delete feature flag", "error", err, "key", key)
+		return response.InternalServerError("Failed to delete feature flag", err)
+	}
+
+	logger.Info("feature flag deleted", "key", key, "adminId", adminID)
+	s.record(ctx, adminID, "feature_flag.delete", before, nil)
+	s.changed(ctx, key)
+	return nil
+}
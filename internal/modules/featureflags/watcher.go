@@ -0,0 +1,59 @@
+package featureflags
+
+import (
+	"context"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/services/health"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+// Watcher keeps a process's flags current: it reloads them when another
+// process announces a change, and every RefreshInterval in case an
+// announcement was missed.
+type Watcher struct {
+	service Service
+	cfg     config.FeatureFlagsConfig
+}
+
+func NewWatcher(service Service, cfg config.FeatureFlagsConfig) *Watcher {
+	return &Watcher{service: service, cfg: cfg}
+}
+
+// Start loads the flags once before returning, so services see them from
+// their first request.
+func (w *Watcher) Start(ctx context.Context) {
+	if err := w.service.Reload(ctx); err != nil {
+		logger.Error("failed to load feature flags, using defaults", "error", err)
+	}
+
+	pubsub := cache.SubscribeChannel(ctx, changedChannel)
+	heartbeat := health.NewHeartbeat("feature_flag_refresh", w.cfg.RefreshInterval)
+
+	go func() {
+		defer pubsub.Close()
+		ticker := time.NewTicker(w.cfg.RefreshInterval)
+		defer ticker.Stop()
+
+		changes := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-changes:
+			case <-ticker.C:
+			}
+			if err := w.service.Reload(ctx); err != nil {
+				if ctx.Err() == nil {
+					logger.Warn("failed to reload feature flags", "error", err)
+				}
+				continue
+			}
+			heartbeat.Beat()
+		}
+	}()
+
+	logger.Info("feature flag watcher started", "refreshInterval", w.cfg.RefreshInterval)
+}
@@ -0,0 +1,238 @@
+package admin
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/umar5678/go-backend/internal/database"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/admin/dto"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+)
+
+const (
+	VerticalServices = "services"
+	VerticalLaundry  = "laundry"
+)
+
+// AnalyticsFilter selects the orders the analytics queries aggregate: those
+// created in [From, To), from one vertical or, when Vertical is empty, both.
+type AnalyticsFilter struct {
+	From     time.Time
+	To       time.Time
+	Vertical string
+}
+
+// Service and laundry orders are normalised into one row shape so every
+// report is a single aggregate over both tables. Orders are bucketed by
+// creation date in both. Laundry orders carry no rating and are always paid
+// from the wallet.
+const (
+	analyticsServiceOrdersSQL = `
+		SELECT 'services' AS vertical, category_slug, status, created_at,
+			total_price AS amount,
+			COALESCE(platform_commission, 0) AS commission,
+			` + shared.ServiceOrderPayoutSQL + ` AS payout,
+			customer_rating AS rating,
+			payment_info->>'method' AS payment_method,
+			assigned_provider_id AS provider_id
+		FROM service_orders
+		WHERE created_at >= ? AND created_at < ?`
+
+	analyticsLaundryOrdersSQL = `
+		SELECT 'laundry' AS vertical, category_slug, status, created_at,
+			total AS amount,
+			total - ` + shared.LaundryOrderPayoutSQL + ` AS commission,
+			` + shared.LaundryOrderPayoutSQL + ` AS payout,
+			NULL::int AS rating,
+			'wallet' AS payment_method,
+			provider_id
+		FROM laundry_orders
+		WHERE created_at >= ? AND created_at < ?`
+)
+
+// analyticsOrders returns a read-replica query over the normalised orders
+// matching filter, aliased as o.
+func (r *repository) analyticsOrders(ctx context.Context, filter AnalyticsFilter) *gorm.DB {
+	var parts []string
+	var args []interface{}
+	if filter.Vertical != VerticalLaundry {
+		parts = append(parts, analyticsServiceOrdersSQL)
+		args = append(args, filter.From, filter.To)
+	}
+	if filter.Vertical != VerticalServices {
+		parts = append(parts, analyticsLaundryOrdersSQL)
+		args = append(args, filter.From, filter.To)
+	}
+
+	return r.db.WithContext(ctx).Scopes(database.ReadReplica).
+		Table("("+strings.Join(parts, " UNION ALL ")+") AS o", args...)
+}
+
+var pendingOrderStatuses = []string{shared.OrderStatusPending, shared.OrderStatusSearchingProvider}
+
+func (r *repository) GetOrderStats(ctx context.Context, filter AnalyticsFilter) (*OrderStats, error) {
+	stats := &OrderStats{}
+	err := r.analyticsOrders(ctx, filter).
+		Select(`
+			COUNT(*) AS total_orders,
+			COUNT(*) FILTER (WHERE status = ?) AS completed_orders,
+			COUNT(*) FILTER (WHERE status = ?) AS cancelled_orders,
+			COUNT(*) FILTER (WHERE status IN ?) AS pending_orders,
+			COALESCE(SUM(amount) FILTER (WHERE status = ?), 0) AS total_revenue,
+			COALESCE(SUM(commission) FILTER (WHERE status = ?), 0) AS total_commission,
+			COALESCE(SUM(payout) FILTER (WHERE status = ?), 0) AS total_provider_payouts,
+			COUNT(rating) AS total_ratings,
+			COALESCE(SUM(rating), 0) AS total_rating_sum`,
+			shared.OrderStatusCompleted, shared.OrderStatusCancelled, pendingOrderStatuses,
+			shared.OrderStatusCompleted, shared.OrderStatusCompleted, shared.OrderStatusCompleted).
+		Scan(stats).Error
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (r *repository) GetOrdersByStatus(ctx context.Context, filter AnalyticsFilter) ([]StatusStats, error) {
+	var stats []StatusStats
+	err := r.analyticsOrders(ctx, filter).
+		Select("status, COUNT(*) AS count").
+		Group("status").
+		Order("count DESC").
+		Scan(&stats).Error
+	return stats, err
+}
+
+func (r *repository) GetOrdersByCategory(ctx context.Context, filter AnalyticsFilter) ([]CategoryStats, error) {
+	var stats []CategoryStats
+	err := r.analyticsOrders(ctx, filter).
+		Where("status = ?", shared.OrderStatusCompleted).
+		Select("vertical, category_slug, COUNT(*) AS order_count, COALESCE(SUM(amount), 0) AS revenue, COALESCE(SUM(commission), 0) AS commission").
+		Group("vertical, category_slug").
+		Order("revenue DESC").
+		Scan(&stats).Error
+	return stats, err
+}
+
+// analyticsPeriodFormats are the TO_CHAR patterns for each groupBy value.
+// periodLabel must produce the same labels.
+var analyticsPeriodFormats = map[string]string{
+	"day":   "YYYY-MM-DD",
+	"week":  `IYYY-"W"IW`,
+	"month": "YYYY-MM",
+}
+
+func (r *repository) GetRevenueBreakdown(ctx context.Context, filter AnalyticsFilter, groupBy string) ([]RevenueStats, error) {
+	format, ok := analyticsPeriodFormats[groupBy]
+	if !ok {
+		format = analyticsPeriodFormats["day"]
+	}
+
+	var stats []RevenueStats
+	err := r.analyticsOrders(ctx, filter).
+		Where("status = ?", shared.OrderStatusCompleted).
+		Select("TO_CHAR(created_at, ?) AS period, COUNT(*) AS order_count, COALESCE(SUM(amount), 0) AS revenue, COALESCE(SUM(commission), 0) AS commission, COALESCE(SUM(payout), 0) AS provider_payouts", format).
+		Group("period").
+		Order("period ASC").
+		Scan(&stats).Error
+	return stats, err
+}
+
+func (r *repository) GetProviderAnalytics(ctx context.Context, filter AnalyticsFilter, query dto.ProviderAnalyticsQuery) ([]ProviderStats, error) {
+	db := r.analyticsOrders(ctx, filter).
+		Where("provider_id IS NOT NULL")
+
+	if query.CategorySlug != "" {
+		db = db.Where("category_slug = ?", query.CategorySlug)
+	}
+
+	db = db.Select(`
+		provider_id,
+		COUNT(*) FILTER (WHERE status = ?) AS completed_orders,
+		COUNT(*) FILTER (WHERE status = ?) AS cancelled_orders,
+		COALESCE(SUM(payout) FILTER (WHERE status = ?), 0) AS total_earnings,
+		COUNT(rating) AS total_ratings,
+		COALESCE(SUM(rating), 0) AS total_rating_sum,
+		ARRAY_AGG(DISTINCT category_slug) AS categories
+	`, shared.OrderStatusCompleted, shared.OrderStatusCancelled, shared.OrderStatusCompleted).
+		Group("provider_id")
+
+	if query.MinOrders != nil {
+		db = db.Having("COUNT(*) FILTER (WHERE status = ?) >= ?", shared.OrderStatusCompleted, *query.MinOrders)
+	}
+	if query.MinRating != nil {
+		db = db.Having("SUM(rating)::float / NULLIF(COUNT(rating), 0) >= ?", *query.MinRating)
+	}
+
+	direction := "ASC"
+	if query.SortDesc {
+		direction = "DESC"
+	}
+	switch query.SortBy {
+	case "earnings":
+		db = db.Order("total_earnings " + direction)
+	case "rating":
+		db = db.Order("SUM(rating)::float / NULLIF(COUNT(rating), 0) " + direction + " NULLS LAST")
+	default:
+		db = db.Order("completed_orders " + direction)
+	}
+
+	var stats []ProviderStats
+	err := db.Limit(query.Limit).Scan(&stats).Error
+	return stats, err
+}
+
+func (r *repository) GetPaymentMethodStats(ctx context.Context, filter AnalyticsFilter) ([]PaymentStats, error) {
+	var stats []PaymentStats
+	err := r.analyticsOrders(ctx, filter).
+		Where("status = ?", shared.OrderStatusCompleted).
+		Select("COALESCE(payment_method, 'unknown') AS method, COUNT(*) AS order_count, COALESCE(SUM(amount), 0) AS total_amount").
+		Group("COALESCE(payment_method, 'unknown')").
+		Order("total_amount DESC").
+		Scan(&stats).Error
+	return stats, err
+}
+
+// GetProviderIdentities loads the display name and photo of each provider
+// profile in one query, keyed by profile ID.
+func (r *repository) GetProviderIdentities(ctx context.Context, providerIDs []string) (map[string]ProviderIdentity, error) {
+	identities := make(map[string]ProviderIdentity, len(providerIDs))
+	if len(providerIDs) == 0 {
+		return identities, nil
+	}
+
+	var rows []ProviderIdentity
+	err := r.db.WithContext(ctx).Scopes(database.ReadReplica).
+		Model(&models.ServiceProviderProfile{}).
+		Joins("JOIN users ON users.id = service_provider_profiles.user_id").
+		Where("service_provider_profiles.id IN ?", providerIDs).
+		Select("service_provider_profiles.id AS provider_id, users.name AS name, users.profile_photo_url AS profile_photo_url").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		identities[row.ProviderID] = row
+	}
+	return identities, nil
+}
+
+// GetTotalRefunds sums the refunds recorded on cancelled service orders.
+// Laundry cancellations release the wallet hold instead of refunding.
+func (r *repository) GetTotalRefunds(ctx context.Context, filter AnalyticsFilter) (float64, error) {
+	if filter.Vertical == VerticalLaundry {
+		return 0, nil
+	}
+
+	var total float64
+	err := r.db.WithContext(ctx).Scopes(database.ReadReplica).
+		Model(&models.ServiceOrderNew{}).
+		Where("status = ?", shared.OrderStatusCancelled).
+		Where("created_at >= ? AND created_at < ?", filter.From, filter.To).
+		Select("COALESCE(SUM((cancellation_info->>'refundAmount')::numeric), 0)").
+		Scan(&total).Error
+	return total, err
+}
@@ -0,0 +1,83 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/umar5678/go-backend/internal/modules/homeservices/admin/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+const analyticsExportContentType = "text/csv; charset=utf-8"
+
+// ExportRevenueReport renders the revenue breakdown, one row per period, as CSV.
+func (s *service) ExportRevenueReport(ctx context.Context, query dto.AnalyticsQuery) (*dto.AnalyticsExport, error) {
+	report, err := s.GetRevenueReport(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := [][]string{{"period", "order_count", "revenue", "commission", "provider_payouts", "average_order_value"}}
+	for _, rb := range report.Breakdown {
+		rows = append(rows, []string{
+			rb.Period,
+			strconv.Itoa(rb.OrderCount),
+			formatAmount(rb.Revenue),
+			formatAmount(rb.Commission),
+			formatAmount(rb.ProviderPayouts),
+			formatAmount(rb.AverageOrderValue),
+		})
+	}
+
+	return analyticsCSV(fmt.Sprintf("revenue_%s_%s.csv", report.Period.FromDate, report.Period.ToDate), rows)
+}
+
+// ExportProviderAnalytics renders the provider leaderboard as CSV.
+func (s *service) ExportProviderAnalytics(ctx context.Context, query dto.ProviderAnalyticsQuery) (*dto.AnalyticsExport, error) {
+	analytics, err := s.GetProviderAnalytics(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := [][]string{{
+		"provider_id", "provider_name", "completed_orders", "cancelled_orders", "completion_rate",
+		"total_earnings", "average_rating", "total_ratings", "categories",
+	}}
+	for _, p := range analytics.Providers {
+		rows = append(rows, []string{
+			p.ProviderID,
+			p.ProviderName,
+			strconv.Itoa(p.CompletedOrders),
+			strconv.Itoa(p.CancelledOrders),
+			formatAmount(p.CompletionRate),
+			formatAmount(p.TotalEarnings),
+			formatAmount(p.AverageRating),
+			strconv.Itoa(p.TotalRatings),
+			strings.Join(p.Categories, ";"),
+		})
+	}
+
+	return analyticsCSV(fmt.Sprintf("providers_%s_%s.csv", analytics.Period.FromDate, analytics.Period.ToDate), rows)
+}
+
+func analyticsCSV(fileName string, rows [][]string) (*dto.AnalyticsExport, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(rows); err != nil {
+		return nil, response.InternalServerError("Failed to render export", err)
+	}
+
+	return &dto.AnalyticsExport{
+		FileName:    fileName,
+		ContentType: analyticsExportContentType,
+		Data:        buf.Bytes(),
+	}, nil
+}
+
+func formatAmount(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
@@ -0,0 +1,52 @@
+package admin
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/audit"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+)
+
+func (s *service) SetAuditLogger(auditLogger audit.Recorder) {
+	s.auditLogger = auditLogger
+}
+
+func (s *service) recordAudit(ctx context.Context, adminID, action, entityType, entityID string, before, after interface{}, reason string, metadata map[string]interface{}) {
+	if s.auditLogger == nil {
+		return
+	}
+	s.auditLogger.Record(ctx, audit.Entry{
+		ActorID:    adminID,
+		ActorRole:  shared.RoleAdmin,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     before,
+		After:      after,
+		Reason:     reason,
+		Metadata:   metadata,
+	})
+}
+
+// orderAuditSnapshot keeps the order fields admins change; the full order
+// carries customer details that do not belong in the audit trail.
+func orderAuditSnapshot(order *models.ServiceOrderNew) map[string]interface{} {
+	snapshot := map[string]interface{}{
+		"status":     order.Status,
+		"totalPrice": order.TotalPrice,
+	}
+	if order.AssignedProviderID != nil {
+		snapshot["assignedProviderId"] = *order.AssignedProviderID
+	} else {
+		snapshot["assignedProviderId"] = nil
+	}
+	if order.PaymentInfo != nil {
+		snapshot["paymentStatus"] = order.PaymentInfo.Status
+	}
+	if order.CancellationInfo != nil {
+		snapshot["cancellationFee"] = order.CancellationInfo.CancellationFee
+		snapshot["refundAmount"] = order.CancellationInfo.RefundAmount
+	}
+	return snapshot
+}
@@ -114,6 +114,8 @@ type AnalyticsQuery struct {
 	FromDate string `form:"fromDate" binding:"required"` 
 	ToDate   string `form:"toDate" binding:"required"`   
 	GroupBy  string `form:"groupBy" binding:"omitempty,oneof=day week month"`
+	// Vertical limits the figures to one order table; empty covers both.
+	Vertical string `form:"vertical" binding:"omitempty,oneof=services laundry"`
 }
 
 func (q *AnalyticsQuery) SetDefaults() {
@@ -148,6 +150,7 @@ type ProviderAnalyticsQuery struct {
 	FromDate     string   `form:"fromDate" binding:"required"`
 	ToDate       string   `form:"toDate" binding:"required"`
 	CategorySlug string   `form:"category"`
+	Vertical     string   `form:"vertical" binding:"omitempty,oneof=services laundry"`
 	MinOrders    *int     `form:"minOrders"`
 	MinRating    *float64 `form:"minRating"`
 	SortBy       string   `form:"sortBy" binding:"omitempty,oneof=completed_orders earnings rating"`
@@ -305,6 +308,65 @@ func (r *UpdateAddonStatusRequest) Validate() error {
 	return nil
 }
 
+type SetAddonCompatibilityRequest struct {
+	ServiceSlugs []string `json:"serviceSlugs" binding:"omitempty,max=200,dive,min=1,max=255"`
+}
+
+func (r *SetAddonCompatibilityRequest) Validate() error {
+	r.ServiceSlugs = normalizeSlugList(r.ServiceSlugs)
+	return nil
+}
+
+// AddonCompatibilityRule is one line of a bulk edit. "set" replaces the addon's
+// services (an empty list lifts the restriction), "add" and "remove" adjust it.
+type AddonCompatibilityRule struct {
+	AddonSlug    string   `json:"addonSlug" binding:"required"`
+	Action       string   `json:"action" binding:"omitempty,oneof=set add remove"`
+	ServiceSlugs []string `json:"serviceSlugs" binding:"omitempty,max=200,dive,min=1,max=255"`
+}
+
+type BulkAddonCompatibilityRequest struct {
+	Rules []AddonCompatibilityRule `json:"rules" binding:"required,min=1,max=200,dive"`
+}
+
+func (r *BulkAddonCompatibilityRequest) Validate() error {
+	seen := make(map[string]bool)
+	for i := range r.Rules {
+		rule := &r.Rules[i]
+		rule.AddonSlug = strings.ToLower(strings.TrimSpace(rule.AddonSlug))
+		if rule.AddonSlug == "" {
+			return fmt.Errorf("rules[%d]: addonSlug is required", i)
+		}
+		if seen[rule.AddonSlug] {
+			return fmt.Errorf("duplicate addon: %s", rule.AddonSlug)
+		}
+		seen[rule.AddonSlug] = true
+
+		if rule.Action == "" {
+			rule.Action = "set"
+		}
+		rule.ServiceSlugs = normalizeSlugList(rule.ServiceSlugs)
+		if rule.Action != "set" && len(rule.ServiceSlugs) == 0 {
+			return fmt.Errorf("rules[%d]: serviceSlugs is required for action '%s'", i, rule.Action)
+		}
+	}
+	return nil
+}
+
+func normalizeSlugList(slugs []string) []string {
+	seen := make(map[string]bool, len(slugs))
+	result := make([]string, 0, len(slugs))
+	for _, slug := range slugs {
+		slug = strings.ToLower(strings.TrimSpace(slug))
+		if slug == "" || seen[slug] {
+			continue
+		}
+		seen[slug] = true
+		result = append(result, slug)
+	}
+	return result
+}
+
 type ListAddonsQuery struct {
 	shared.PaginationParams
 	CategorySlug string   `form:"categorySlug"`
@@ -494,6 +556,117 @@ func (q *ListServicesQuery) SetDefaults() {
 	}
 }
 
+type CreateSurchargeRequest struct {
+	CategorySlug string   `json:"categorySlug" binding:"required,min=2,max=255"`
+	Code         string   `json:"code" binding:"required,min=2,max=50"`
+	Name         string   `json:"name" binding:"required,min=2,max=255"`
+	Description  string   `json:"description" binding:"omitempty,max=2000"`
+	Kind         string   `json:"kind" binding:"omitempty,oneof=environmental disposal other"`
+	Type         string   `json:"type" binding:"required,oneof=fixed percentage"`
+	Amount       float64  `json:"amount" binding:"required,gt=0"`
+	MinAmount    *float64 `json:"minAmount" binding:"omitempty,min=0"`
+	MaxAmount    *float64 `json:"maxAmount" binding:"omitempty,gt=0"`
+	IsActive     *bool    `json:"isActive"`
+}
+
+func (r *CreateSurchargeRequest) Validate() error {
+	r.CategorySlug = strings.ToLower(strings.TrimSpace(r.CategorySlug))
+	r.Code = strings.ToLower(strings.TrimSpace(r.Code))
+
+	if !isValidSlug(r.CategorySlug) {
+		return fmt.Errorf("categorySlug must contain only lowercase letters, numbers, and hyphens")
+	}
+	if !isValidSlug(r.Code) {
+		return fmt.Errorf("code must contain only lowercase letters, numbers, and hyphens")
+	}
+	if r.Kind == "" {
+		r.Kind = "other"
+	}
+	if err := ValidateSurchargeAmounts(r.Type, r.Amount, r.MinAmount, r.MaxAmount); err != nil {
+		return err
+	}
+
+	if r.IsActive == nil {
+		defaultActive := true
+		r.IsActive = &defaultActive
+	}
+
+	return nil
+}
+
+type UpdateSurchargeRequest struct {
+	Name        *string  `json:"name" binding:"omitempty,min=2,max=255"`
+	Description *string  `json:"description" binding:"omitempty,max=2000"`
+	Kind        *string  `json:"kind" binding:"omitempty,oneof=environmental disposal other"`
+	Type        *string  `json:"type" binding:"omitempty,oneof=fixed percentage"`
+	Amount      *float64 `json:"amount" binding:"omitempty,gt=0"`
+	MinAmount   *float64 `json:"minAmount" binding:"omitempty,min=0"`
+	MaxAmount   *float64 `json:"maxAmount" binding:"omitempty,gt=0"`
+	IsActive    *bool    `json:"isActive"`
+}
+
+type ListSurchargesQuery struct {
+	CategorySlug string `form:"categorySlug"`
+	IsActive     *bool  `form:"isActive"`
+}
+
+// SurchargeReportQuery selects order surcharge lines by order creation date.
+type SurchargeReportQuery struct {
+	FromDate     string `form:"fromDate" binding:"required"`
+	ToDate       string `form:"toDate" binding:"required"`
+	CategorySlug string `form:"categorySlug"`
+}
+
+func (q *SurchargeReportQuery) Validate() error {
+	return validateDateRange(q.FromDate, q.ToDate)
+}
+
+// RemitSurchargesRequest marks the collectable surcharge lines in the range as
+// paid over to the authority or contractor under the given reference.
+type RemitSurchargesRequest struct {
+	FromDate     string `json:"fromDate" binding:"required"`
+	ToDate       string `json:"toDate" binding:"required"`
+	CategorySlug string `json:"categorySlug" binding:"omitempty,max=255"`
+	Code         string `json:"code" binding:"omitempty,max=50"`
+	Reference    string `json:"reference" binding:"required,min=2,max=100"`
+}
+
+func (r *RemitSurchargesRequest) Validate() error {
+	r.CategorySlug = strings.ToLower(strings.TrimSpace(r.CategorySlug))
+	r.Code = strings.ToLower(strings.TrimSpace(r.Code))
+	r.Reference = strings.TrimSpace(r.Reference)
+	return validateDateRange(r.FromDate, r.ToDate)
+}
+
+// ValidateSurchargeAmounts checks a surcharge's amount and clamps against its type.
+func ValidateSurchargeAmounts(surchargeType string, amount float64, minAmount, maxAmount *float64) error {
+	if surchargeType == "percentage" && amount > 100 {
+		return fmt.Errorf("percentage surcharge cannot exceed 100")
+	}
+	if surchargeType == "fixed" && (minAmount != nil || maxAmount != nil) {
+		return fmt.Errorf("minAmount and maxAmount only apply to percentage surcharges")
+	}
+	if minAmount != nil && maxAmount != nil && *minAmount > *maxAmount {
+		return fmt.Errorf("minAmount cannot be greater than maxAmount")
+	}
+	return nil
+}
+
+func validateDateRange(from, to string) error {
+	fromDate, err := time.Parse("2006-01-02", from)
+	if err != nil {
+		return fmt.Errorf("invalid fromDate format")
+	}
+	toDate, err := time.Parse("2006-01-02", to)
+	if err != nil {
+		return fmt.Errorf("invalid toDate format")
+	}
+	if fromDate.After(toDate) {
+		return fmt.Errorf("fromDate cannot be after toDate")
+	}
+	return nil
+}
+
 func isValidSlug(slug string) bool {
 	if len(slug) == 0 {
 		return false
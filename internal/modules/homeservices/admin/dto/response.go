@@ -202,14 +202,18 @@ type AdminOrderAddonItem struct {
 }
 
 type AdminOrderPricing struct {
-	ServicesTotal      float64 `json:"servicesTotal"`
-	AddonsTotal        float64 `json:"addonsTotal"`
-	Subtotal           float64 `json:"subtotal"`
-	PlatformCommission float64 `json:"platformCommission"`
-	CommissionRate     float64 `json:"commissionRate"`
-	TotalPrice         float64 `json:"totalPrice"`
-	ProviderPayout     float64 `json:"providerPayout"`
-	FormattedTotal     string  `json:"formattedTotal"`
+	ServicesTotal      float64                `json:"servicesTotal"`
+	AddonsTotal        float64                `json:"addonsTotal"`
+	Subtotal           float64                `json:"subtotal"`
+	PlatformCommission float64                `json:"platformCommission"`
+	CommissionRate     float64                `json:"commissionRate"`
+	Surcharges         models.OrderSurcharges `json:"surcharges,omitempty"`
+	SurchargesTotal    float64                `json:"surchargesTotal"`
+	Taxes              models.TaxLines        `json:"taxes,omitempty"`
+	TaxTotal           float64                `json:"taxTotal"`
+	TotalPrice         float64                `json:"totalPrice"`
+	ProviderPayout     float64                `json:"providerPayout"`
+	FormattedTotal     string                 `json:"formattedTotal"`
 }
 
 type AdminPaymentInfo struct {
@@ -273,6 +277,7 @@ type AnalyticsPeriod struct {
 	FromDate string `json:"fromDate"`
 	ToDate   string `json:"toDate"`
 	GroupBy  string `json:"groupBy"`
+	Vertical string `json:"vertical,omitempty"`
 }
 
 type AnalyticsSummary struct {
@@ -297,6 +302,7 @@ type StatusCount struct {
 }
 
 type CategoryCount struct {
+	Vertical      string  `json:"vertical"`
 	CategorySlug  string  `json:"categorySlug"`
 	CategoryTitle string  `json:"categoryTitle"`
 	OrderCount    int     `json:"orderCount"`
@@ -313,10 +319,17 @@ type RevenueBreakdown struct {
 	AverageOrderValue float64 `json:"averageOrderValue"`
 }
 
+// AnalyticsTrends compares the requested period with the one of equal length
+// immediately before it.
 type AnalyticsTrends struct {
-	OrdersChange     TrendChange `json:"ordersChange"`
-	RevenueChange    TrendChange `json:"revenueChange"`
-	CompletionChange TrendChange `json:"completionChange"`
+	PreviousFromDate        string      `json:"previousFromDate"`
+	PreviousToDate          string      `json:"previousToDate"`
+	OrdersChange            TrendChange `json:"ordersChange"`
+	RevenueChange           TrendChange `json:"revenueChange"`
+	CommissionChange        TrendChange `json:"commissionChange"`
+	AverageOrderValueChange TrendChange `json:"averageOrderValueChange"`
+	CompletionChange        TrendChange `json:"completionChange"`
+	CancellationChange      TrendChange `json:"cancellationChange"`
 }
 
 type TrendChange struct {
@@ -357,9 +370,11 @@ type RevenueReportResponse struct {
 	Breakdown        []RevenueBreakdown   `json:"breakdown"`
 	ByCategory       []CategoryRevenue    `json:"byCategory"`
 	ByPaymentMethod  []PaymentMethodStats `json:"byPaymentMethod"`
+	Trends           AnalyticsTrends      `json:"trends"`
 }
 
 type CategoryRevenue struct {
+	Vertical      string  `json:"vertical"`
 	CategorySlug  string  `json:"categorySlug"`
 	CategoryTitle string  `json:"categoryTitle"`
 	Revenue       float64 `json:"revenue"`
@@ -375,6 +390,14 @@ type PaymentMethodStats struct {
 	Percentage  float64 `json:"percentage"`
 }
 
+// AnalyticsExport is a rendered analytics report ready to be sent as a
+// download.
+type AnalyticsExport struct {
+	FileName    string
+	ContentType string
+	Data        []byte
+}
+
 type DashboardResponse struct {
 	Today          TodayStats               `json:"today"`
 	RecentOrders   []AdminOrderListResponse `json:"recentOrders"`
@@ -387,6 +410,7 @@ type TodayStats struct {
 	CompletedOrders  int     `json:"completedOrders"`
 	PendingOrders    int     `json:"pendingOrders"`
 	InProgressOrders int     `json:"inProgressOrders"`
+	ExpiredOrders    int     `json:"expiredOrders"`
 	Revenue          float64 `json:"revenue"`
 	Commission       float64 `json:"commission"`
 	NewCustomers     int     `json:"newCustomers"`
@@ -403,6 +427,7 @@ type PendingActions struct {
 type WeeklyStats struct {
 	TotalOrders     int          `json:"totalOrders"`
 	CompletedOrders int          `json:"completedOrders"`
+	ExpiredOrders   int          `json:"expiredOrders"`
 	TotalRevenue    float64      `json:"totalRevenue"`
 	AverageRating   float64      `json:"averageRating"`
 	DailyBreakdown  []DailyStats `json:"dailyBreakdown"`
@@ -466,10 +491,6 @@ func FormatTime(timeStr string) string {
 	return t.Format("3:04 PM")
 }
 
-func CalculateProviderPayout(totalPrice float64) float64 {
-	return shared.RoundToTwoDecimals(totalPrice * (1 - shared.PlatformCommissionRate))
-}
-
 func GetAvailableActions(status string) []string {
 	actions := []string{"view", "view_history"}
 
@@ -492,8 +513,8 @@ func GetAvailableActions(status string) []string {
 }
 
 func ToAdminOrderListResponse(order *models.ServiceOrderNew) AdminOrderListResponse {
-	providerPayout := CalculateProviderPayout(order.TotalPrice)
-	commission := order.TotalPrice - providerPayout
+	providerPayout := order.ProviderPayout()
+	commission := order.ServiceAmount() - providerPayout
 
 	response := AdminOrderListResponse{
 		ID:             order.ID,
@@ -534,7 +555,7 @@ func ToAdminOrderListResponses(orders []*models.ServiceOrderNew) []AdminOrderLis
 }
 
 func ToAdminOrderDetailResponse(order *models.ServiceOrderNew, history []models.OrderStatusHistory) *AdminOrderDetailResponse {
-	providerPayout := CalculateProviderPayout(order.TotalPrice)
+	providerPayout := order.ProviderPayout()
 
 	services := make([]AdminOrderServiceItem, len(order.SelectedServices))
 	for i, s := range order.SelectedServices {
@@ -620,7 +641,11 @@ func ToAdminOrderDetailResponse(order *models.ServiceOrderNew, history []models.
 			AddonsTotal:        order.AddonsTotal,
 			Subtotal:           order.Subtotal,
 			PlatformCommission: order.PlatformCommission,
-			CommissionRate:     shared.PlatformCommissionRate,
+			CommissionRate:     order.CommissionRate / 100,
+			Surcharges:         order.Surcharges,
+			SurchargesTotal:    order.SurchargesTotal,
+			Taxes:              order.Taxes,
+			TaxTotal:           order.TaxTotal,
 			TotalPrice:         order.TotalPrice,
 			ProviderPayout:     providerPayout,
 			FormattedTotal:     FormatPrice(order.TotalPrice),
@@ -793,6 +818,101 @@ func ToServiceListResponses(services []*models.ServiceNew) []*ServiceListRespons
 	return responses
 }
 
+// AddonCompatibilityResponse lists the services an addon may be booked with.
+// Restricted is false when the addon fits every service in its category.
+type AddonCompatibilityResponse struct {
+	AddonSlug          string   `json:"addonSlug"`
+	CategorySlug       string   `json:"categorySlug"`
+	Restricted         bool     `json:"restricted"`
+	CompatibleServices []string `json:"compatibleServices"`
+}
+
+type CategoryCompatibilityResponse struct {
+	CategorySlug string                        `json:"categorySlug"`
+	Services     []string                      `json:"services"`
+	Addons       []*AddonCompatibilityResponse `json:"addons"`
+}
+
+type SurchargeResponse struct {
+	ID           string    `json:"id"`
+	CategorySlug string    `json:"categorySlug"`
+	Code         string    `json:"code"`
+	Name         string    `json:"name"`
+	Description  string    `json:"description"`
+	Kind         string    `json:"kind"`
+	Type         string    `json:"type"`
+	Amount       float64   `json:"amount"`
+	MinAmount    *float64  `json:"minAmount,omitempty"`
+	MaxAmount    *float64  `json:"maxAmount,omitempty"`
+	IsActive     bool      `json:"isActive"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+func ToSurchargeResponse(surcharge *models.CategorySurcharge) *SurchargeResponse {
+	if surcharge == nil {
+		return nil
+	}
+
+	return &SurchargeResponse{
+		ID:           surcharge.ID,
+		CategorySlug: surcharge.CategorySlug,
+		Code:         surcharge.Code,
+		Name:         surcharge.Name,
+		Description:  surcharge.Description,
+		Kind:         surcharge.Kind,
+		Type:         surcharge.Type,
+		Amount:       surcharge.Amount,
+		MinAmount:    surcharge.MinAmount,
+		MaxAmount:    surcharge.MaxAmount,
+		IsActive:     surcharge.IsActive,
+		CreatedAt:    surcharge.CreatedAt,
+		UpdatedAt:    surcharge.UpdatedAt,
+	}
+}
+
+func ToSurchargeResponses(surcharges []*models.CategorySurcharge) []*SurchargeResponse {
+	responses := make([]*SurchargeResponse, len(surcharges))
+	for i, surcharge := range surcharges {
+		responses[i] = ToSurchargeResponse(surcharge)
+	}
+	return responses
+}
+
+// SurchargeReportLine totals one surcharge code in a category. Pending is on
+// orders still in progress, collectable is on completed orders not yet
+// remitted, and voided was charged on orders that were later cancelled.
+type SurchargeReportLine struct {
+	CategorySlug string  `json:"categorySlug"`
+	Code         string  `json:"code"`
+	Name         string  `json:"name"`
+	Kind         string  `json:"kind"`
+	OrderCount   int64   `json:"orderCount"`
+	Charged      float64 `json:"charged"`
+	Pending      float64 `json:"pending"`
+	Collectable  float64 `json:"collectable"`
+	Remitted     float64 `json:"remitted"`
+	Voided       float64 `json:"voided"`
+}
+
+type SurchargeReportResponse struct {
+	FromDate    string                `json:"fromDate"`
+	ToDate      string                `json:"toDate"`
+	Lines       []SurchargeReportLine `json:"lines"`
+	Charged     float64               `json:"charged"`
+	Pending     float64               `json:"pending"`
+	Collectable float64               `json:"collectable"`
+	Remitted    float64               `json:"remitted"`
+	Voided      float64               `json:"voided"`
+}
+
+type RemitSurchargesResponse struct {
+	Reference  string    `json:"reference"`
+	LineCount  int64     `json:"lineCount"`
+	Amount     float64   `json:"amount"`
+	RemittedAt time.Time `json:"remittedAt"`
+}
+
 // CategoryServicesResponse represents services grouped by category
 type CategoryServicesResponse struct {
 	CategorySlug string                 `json:"categorySlug"`
@@ -1,6 +1,9 @@
 package admin
 
 import (
+	"fmt"
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 
 	"github.com/umar5678/go-backend/internal/modules/homeservices/admin/dto"
@@ -95,7 +98,9 @@ func (h *Handler) UpdateService(c *gin.Context) {
 		return
 	}
 
-	svc, err := h.service.UpdateService(c.Request.Context(), slug, req)
+	adminID, _ := c.Get("userID")
+
+	svc, err := h.service.UpdateService(c.Request.Context(), slug, req, adminID.(string))
 	if err != nil {
 		c.Error(err)
 		return
@@ -159,7 +164,9 @@ func (h *Handler) UpdateServiceStatus(c *gin.Context) {
 		return
 	}
 
-	svc, err := h.service.UpdateServiceStatus(c.Request.Context(), slug, req)
+	adminID, _ := c.Get("userID")
+
+	svc, err := h.service.UpdateServiceStatus(c.Request.Context(), slug, req, adminID.(string))
 	if err != nil {
 		c.Error(err)
 		return
@@ -181,8 +188,9 @@ func (h *Handler) UpdateServiceStatus(c *gin.Context) {
 // @Router /admin/homeservices/services/{slug} [delete]
 func (h *Handler) DeleteService(c *gin.Context) {
 	slug := c.Param("slug")
+	adminID, _ := c.Get("userID")
 
-	if err := h.service.DeleteService(c.Request.Context(), slug); err != nil {
+	if err := h.service.DeleteService(c.Request.Context(), slug, adminID.(string)); err != nil {
 		c.Error(err)
 		return
 	}
@@ -401,8 +409,304 @@ func (h *Handler) ListAddons(c *gin.Context) {
 	response.Paginated(c, addons, *pagination, "Addons retrieved successfully")
 }
 
+// ==================== Surcharge Handlers ====================
+
+// CreateSurcharge godoc
+// @Summary Create a category surcharge
+// @Description Create an order-level surcharge (e.g. disposal fee) applied to every order in a category
+// @Tags Admin - Home Services
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateSurchargeRequest true "Surcharge details"
+// @Success 201 {object} response.Response{data=dto.SurchargeResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /admin/homeservices/surcharges [post]
+func (h *Handler) CreateSurcharge(c *gin.Context) {
+	var req dto.CreateSurchargeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	surcharge, err := h.service.CreateSurcharge(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, surcharge, "Surcharge created successfully")
+}
+
+// ListSurcharges godoc
+// @Summary List category surcharges
+// @Description List configured surcharges, optionally filtered by category and status
+// @Tags Admin - Home Services
+// @Produce json
+// @Security BearerAuth
+// @Param categorySlug query string false "Filter by category"
+// @Param isActive query bool false "Filter by active status"
+// @Success 200 {object} response.Response{data=[]dto.SurchargeResponse}
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /admin/homeservices/surcharges [get]
+func (h *Handler) ListSurcharges(c *gin.Context) {
+	var query dto.ListSurchargesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+
+	surcharges, err := h.service.ListSurcharges(c.Request.Context(), query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, surcharges, "Surcharges retrieved successfully")
+}
+
+// GetSurcharge godoc
+// @Summary Get category surcharge
+// @Tags Admin - Home Services
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Surcharge ID"
+// @Success 200 {object} response.Response{data=dto.SurchargeResponse}
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /admin/homeservices/surcharges/{id} [get]
+func (h *Handler) GetSurcharge(c *gin.Context) {
+	surcharge, err := h.service.GetSurcharge(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, surcharge, "Surcharge retrieved successfully")
+}
+
+// UpdateSurcharge godoc
+// @Summary Update category surcharge
+// @Description Changes apply to orders created afterwards; existing orders keep the amount they were charged
+// @Tags Admin - Home Services
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Surcharge ID"
+// @Param request body dto.UpdateSurchargeRequest true "Fields to update"
+// @Success 200 {object} response.Response{data=dto.SurchargeResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /admin/homeservices/surcharges/{id} [put]
+func (h *Handler) UpdateSurcharge(c *gin.Context) {
+	var req dto.UpdateSurchargeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	surcharge, err := h.service.UpdateSurcharge(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, surcharge, "Surcharge updated successfully")
+}
+
+// DeleteSurcharge godoc
+// @Summary Delete category surcharge
+// @Tags Admin - Home Services
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Surcharge ID"
+// @Success 200 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /admin/homeservices/surcharges/{id} [delete]
+func (h *Handler) DeleteSurcharge(c *gin.Context) {
+	if err := h.service.DeleteSurcharge(c.Request.Context(), c.Param("id")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, nil, "Surcharge deleted successfully")
+}
+
+// GetSurchargeReport godoc
+// @Summary Surcharge remittance report
+// @Description Totals charged surcharges by category and code, split into pending, collectable, remitted and voided
+// @Tags Admin - Home Services
+// @Produce json
+// @Security BearerAuth
+// @Param fromDate query string true "Start date (YYYY-MM-DD)"
+// @Param toDate query string true "End date (YYYY-MM-DD)"
+// @Param categorySlug query string false "Filter by category"
+// @Success 200 {object} response.Response{data=dto.SurchargeReportResponse}
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /admin/homeservices/surcharges/report [get]
+func (h *Handler) GetSurchargeReport(c *gin.Context) {
+	var query dto.SurchargeReportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+
+	report, err := h.service.GetSurchargeReport(c.Request.Context(), query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, report, "Surcharge report retrieved successfully")
+}
+
+// RemitSurcharges godoc
+// @Summary Record a surcharge remittance
+// @Description Marks all collectable surcharge lines in the range as remitted under the given reference
+// @Tags Admin - Home Services
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.RemitSurchargesRequest true "Remittance details"
+// @Success 200 {object} response.Response{data=dto.RemitSurchargesResponse}
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /admin/homeservices/surcharges/remit [post]
+func (h *Handler) RemitSurcharges(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	var req dto.RemitSurchargesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	result, err := h.service.RemitSurcharges(c.Request.Context(), req, adminID.(string))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Surcharges remitted successfully")
+}
+
 // ==================== Category Handlers ====================
 
+// GetAddonCompatibility godoc
+// @Summary Get addon compatibility
+// @Description Get the services an addon can be booked with
+// @Tags Admin - Home Services
+// @Produce json
+// @Security BearerAuth
+// @Param slug path string true "Addon slug"
+// @Success 200 {object} response.Response{data=dto.AddonCompatibilityResponse}
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /admin/homeservices/addons/{slug}/compatibility [get]
+func (h *Handler) GetAddonCompatibility(c *gin.Context) {
+	slug := c.Param("slug")
+
+	result, err := h.service.GetAddonCompatibility(c.Request.Context(), slug)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Addon compatibility retrieved successfully")
+}
+
+// SetAddonCompatibility godoc
+// @Summary Set addon compatibility
+// @Description Replace the services an addon can be booked with; an empty list makes it compatible with the whole category
+// @Tags Admin - Home Services
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param slug path string true "Addon slug"
+// @Param request body dto.SetAddonCompatibilityRequest true "Compatible services"
+// @Success 200 {object} response.Response{data=dto.AddonCompatibilityResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /admin/homeservices/addons/{slug}/compatibility [put]
+func (h *Handler) SetAddonCompatibility(c *gin.Context) {
+	slug := c.Param("slug")
+	var req dto.SetAddonCompatibilityRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	result, err := h.service.SetAddonCompatibility(c.Request.Context(), slug, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Addon compatibility updated successfully")
+}
+
+// GetCategoryCompatibility godoc
+// @Summary Get category compatibility matrix
+// @Description Get addon to service compatibility for every addon in a category
+// @Tags Admin - Home Services
+// @Produce json
+// @Security BearerAuth
+// @Param categorySlug path string true "Category slug"
+// @Success 200 {object} response.Response{data=dto.CategoryCompatibilityResponse}
+// @Failure 500 {object} response.Response
+// @Router /admin/homeservices/categories/{categorySlug}/compatibility [get]
+func (h *Handler) GetCategoryCompatibility(c *gin.Context) {
+	categorySlug := c.Param("categorySlug")
+
+	result, err := h.service.GetCategoryCompatibility(c.Request.Context(), categorySlug)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Category compatibility retrieved successfully")
+}
+
+// BulkUpdateCompatibility godoc
+// @Summary Bulk edit addon compatibility
+// @Description Set, add or remove compatible services for several addons of a category in one transaction
+// @Tags Admin - Home Services
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param categorySlug path string true "Category slug"
+// @Param request body dto.BulkAddonCompatibilityRequest true "Compatibility rules"
+// @Success 200 {object} response.Response{data=dto.CategoryCompatibilityResponse}
+// @Failure 400 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /admin/homeservices/categories/{categorySlug}/compatibility [put]
+func (h *Handler) BulkUpdateCompatibility(c *gin.Context) {
+	categorySlug := c.Param("categorySlug")
+	var req dto.BulkAddonCompatibilityRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	result, err := h.service.BulkUpdateCompatibility(c.Request.Context(), categorySlug, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Addon compatibility updated successfully")
+}
+
 // GetCategoryDetails godoc
 // @Summary Get category details
 // @Description Get all services and addons for a category
@@ -700,6 +1004,7 @@ func (h *Handler) BulkUpdateStatus(c *gin.Context) {
 // @Param fromDate query string true "From date (YYYY-MM-DD)"
 // @Param toDate query string true "To date (YYYY-MM-DD)"
 // @Param groupBy query string false "Group by" Enums(day, week, month)
+// @Param vertical query string false "Limit to one vertical, both when empty" Enums(services, laundry)
 // @Success 200 {object} response.Response{data=dto.OverviewAnalyticsResponse}
 // @Failure 400 {object} response.Response
 // @Router /admin/homeservices/analytics/overview [get]
@@ -728,6 +1033,7 @@ func (h *Handler) GetOverviewAnalytics(c *gin.Context) {
 // @Param fromDate query string true "From date (YYYY-MM-DD)"
 // @Param toDate query string true "To date (YYYY-MM-DD)"
 // @Param category query string false "Filter by category"
+// @Param vertical query string false "Limit to one vertical, both when empty" Enums(services, laundry)
 // @Param minOrders query int false "Minimum completed orders"
 // @Param minRating query number false "Minimum rating"
 // @Param sortBy query string false "Sort by" Enums(completed_orders, earnings, rating)
@@ -765,6 +1071,7 @@ func (h *Handler) GetProviderAnalytics(c *gin.Context) {
 // @Param fromDate query string true "From date (YYYY-MM-DD)"
 // @Param toDate query string true "To date (YYYY-MM-DD)"
 // @Param groupBy query string false "Group by" Enums(day, week, month)
+// @Param vertical query string false "Limit to one vertical, both when empty" Enums(services, laundry)
 // @Success 200 {object} response.Response{data=dto.RevenueReportResponse}
 // @Failure 400 {object} response.Response
 // @Router /admin/homeservices/analytics/revenue [get]
@@ -784,6 +1091,75 @@ func (h *Handler) GetRevenueReport(c *gin.Context) {
 	response.Success(c, report, "Revenue report retrieved successfully")
 }
 
+// ExportRevenueReport godoc
+// @Summary Export revenue report
+// @Description Download the revenue breakdown, one row per period, as CSV
+// @Tags Admin - Analytics
+// @Produce text/csv
+// @Security BearerAuth
+// @Param fromDate query string true "From date (YYYY-MM-DD)"
+// @Param toDate query string true "To date (YYYY-MM-DD)"
+// @Param groupBy query string false "Group by" Enums(day, week, month)
+// @Param vertical query string false "Limit to one vertical, both when empty" Enums(services, laundry)
+// @Success 200 {file} file
+// @Failure 400 {object} response.Response
+// @Router /admin/homeservices/analytics/revenue/export [get]
+func (h *Handler) ExportRevenueReport(c *gin.Context) {
+	var query dto.AnalyticsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+
+	file, err := h.service.ExportRevenueReport(c.Request.Context(), query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.FileName))
+	c.Data(http.StatusOK, file.ContentType, file.Data)
+}
+
+// ExportProviderAnalytics godoc
+// @Summary Export provider analytics
+// @Description Download the provider analytics as CSV
+// @Tags Admin - Analytics
+// @Produce text/csv
+// @Security BearerAuth
+// @Param fromDate query string true "From date (YYYY-MM-DD)"
+// @Param toDate query string true "To date (YYYY-MM-DD)"
+// @Param category query string false "Filter by category"
+// @Param vertical query string false "Limit to one vertical, both when empty" Enums(services, laundry)
+// @Param minOrders query int false "Minimum completed orders"
+// @Param minRating query number false "Minimum rating"
+// @Param sortBy query string false "Sort by" Enums(completed_orders, earnings, rating)
+// @Param sortDesc query bool false "Sort descending" default(true)
+// @Param limit query int false "Number of providers" default(20)
+// @Success 200 {file} file
+// @Failure 400 {object} response.Response
+// @Router /admin/homeservices/analytics/providers/export [get]
+func (h *Handler) ExportProviderAnalytics(c *gin.Context) {
+	var query dto.ProviderAnalyticsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+
+	if c.Query("sortDesc") == "" {
+		query.SortDesc = true
+	}
+
+	file, err := h.service.ExportProviderAnalytics(c.Request.Context(), query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.FileName))
+	c.Data(http.StatusOK, file.ContentType, file.Data)
+}
+
 // ==================== Dashboard ====================
 
 // GetDashboard godoc
@@ -5,8 +5,11 @@ import (
 	"strings"
 	"time"
 
+	"github.com/lib/pq"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
+	"github.com/umar5678/go-backend/internal/database"
 	"github.com/umar5678/go-backend/internal/models"
 	"github.com/umar5678/go-backend/internal/modules/homeservices/admin/dto"
 	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
@@ -29,6 +32,19 @@ type Repository interface {
 	ListAddons(ctx context.Context, query dto.ListAddonsQuery) ([]*models.Addon, int64, error)
 	AddonSlugExists(ctx context.Context, slug string, excludeID string) (bool, error)
 
+	GetAddonCompatibility(ctx context.Context, addonSlug string) ([]string, error)
+	GetCategoryCompatibility(ctx context.Context, categorySlug string) ([]models.AddonServiceCompatibility, error)
+	ApplyAddonCompatibility(ctx context.Context, changes []AddonCompatibilityChange) error
+
+	CreateSurcharge(ctx context.Context, surcharge *models.CategorySurcharge) error
+	GetSurchargeByID(ctx context.Context, id string) (*models.CategorySurcharge, error)
+	UpdateSurcharge(ctx context.Context, surcharge *models.CategorySurcharge) error
+	DeleteSurcharge(ctx context.Context, id string) error
+	ListSurcharges(ctx context.Context, query dto.ListSurchargesQuery) ([]*models.CategorySurcharge, error)
+	SurchargeCodeExists(ctx context.Context, categorySlug, code string, excludeID string) (bool, error)
+	GetSurchargeReport(ctx context.Context, fromDate, toDate time.Time, categorySlug string) ([]SurchargeReportRow, error)
+	RemitSurcharges(ctx context.Context, fromDate, toDate time.Time, categorySlug, code, adminID, reference string, remittedAt time.Time) (int64, float64, error)
+
 	GetServicesByCategory(ctx context.Context, categorySlug string) ([]*models.ServiceNew, error)
 	GetAddonsByCategory(ctx context.Context, categorySlug string) ([]*models.Addon, error)
 	GetAllCategories(ctx context.Context) ([]string, error)
@@ -37,16 +53,18 @@ type Repository interface {
 	GetOrderByID(ctx context.Context, id string) (*models.ServiceOrderNew, error)
 	GetOrderByNumber(ctx context.Context, orderNumber string) (*models.ServiceOrderNew, error)
 	UpdateOrder(ctx context.Context, order *models.ServiceOrderNew) error
+	GetProviderProfile(ctx context.Context, id string) (*models.ServiceProviderProfile, error)
 
 	GetOrderStatusHistory(ctx context.Context, orderID string) ([]models.OrderStatusHistory, error)
 	CreateStatusHistory(ctx context.Context, history *models.OrderStatusHistory) error
 
-	GetOrderStats(ctx context.Context, fromDate, toDate time.Time) (*OrderStats, error)
-	GetOrdersByStatus(ctx context.Context, fromDate, toDate time.Time) ([]StatusStats, error)
-	GetOrdersByCategory(ctx context.Context, fromDate, toDate time.Time) ([]CategoryStats, error)
-	GetRevenueBreakdown(ctx context.Context, fromDate, toDate time.Time, groupBy string) ([]RevenueStats, error)
-	GetProviderAnalytics(ctx context.Context, fromDate, toDate time.Time, query dto.ProviderAnalyticsQuery) ([]ProviderStats, error)
-	GetPaymentMethodStats(ctx context.Context, fromDate, toDate time.Time) ([]PaymentStats, error)
+	GetOrderStats(ctx context.Context, filter AnalyticsFilter) (*OrderStats, error)
+	GetOrdersByStatus(ctx context.Context, filter AnalyticsFilter) ([]StatusStats, error)
+	GetOrdersByCategory(ctx context.Context, filter AnalyticsFilter) ([]CategoryStats, error)
+	GetRevenueBreakdown(ctx context.Context, filter AnalyticsFilter, groupBy string) ([]RevenueStats, error)
+	GetProviderAnalytics(ctx context.Context, filter AnalyticsFilter, query dto.ProviderAnalyticsQuery) ([]ProviderStats, error)
+	GetPaymentMethodStats(ctx context.Context, filter AnalyticsFilter) ([]PaymentStats, error)
+	GetProviderIdentities(ctx context.Context, providerIDs []string) (map[string]ProviderIdentity, error)
 
 	GetTodayStats(ctx context.Context) (*TodayStatsData, error)
 	GetWeeklyStats(ctx context.Context) (*WeeklyStatsData, error)
@@ -57,7 +75,7 @@ type Repository interface {
 
 	GetUserByID(ctx context.Context, userID string) (*models.User, error)
 
-	GetTotalRefunds(ctx context.Context, fromDate, toDate time.Time) (float64, error)
+	GetTotalRefunds(ctx context.Context, filter AnalyticsFilter) (float64, error)
 }
 
 type repository struct {
@@ -86,9 +104,32 @@ type StatusStats struct {
 }
 
 type CategoryStats struct {
+	Vertical     string
 	CategorySlug string
 	OrderCount   int64
 	Revenue      float64
+	Commission   float64
+}
+
+func (s *OrderStats) averageOrderValue() float64 {
+	if s.CompletedOrders == 0 {
+		return 0
+	}
+	return s.TotalRevenue / float64(s.CompletedOrders)
+}
+
+func (s *OrderStats) completionRate() float64 {
+	if s.TotalOrders == 0 {
+		return 0
+	}
+	return float64(s.CompletedOrders) / float64(s.TotalOrders) * 100
+}
+
+func (s *OrderStats) cancellationRate() float64 {
+	if s.TotalOrders == 0 {
+		return 0
+	}
+	return float64(s.CancelledOrders) / float64(s.TotalOrders) * 100
 }
 
 type RevenueStats struct {
@@ -106,6 +147,13 @@ type ProviderStats struct {
 	TotalEarnings   float64
 	TotalRatings    int64
 	TotalRatingSum  int64
+	Categories      pq.StringArray `gorm:"type:text[]"`
+}
+
+type ProviderIdentity struct {
+	ProviderID      string
+	Name            string
+	ProfilePhotoURL *string
 }
 
 type PaymentStats struct {
@@ -119,6 +167,7 @@ type TodayStatsData struct {
 	CompletedOrders  int64
 	PendingOrders    int64
 	InProgressOrders int64
+	ExpiredOrders    int64
 	Revenue          float64
 	Commission       float64
 }
@@ -126,6 +175,7 @@ type TodayStatsData struct {
 type WeeklyStatsData struct {
 	TotalOrders     int64
 	CompletedOrders int64
+	ExpiredOrders   int64
 	TotalRevenue    float64
 	TotalRatings    int64
 	TotalRatingSum  int64
@@ -138,6 +188,26 @@ type DailyStatsData struct {
 	Revenue    float64
 }
 
+type AddonCompatibilityChange struct {
+	AddonSlug    string
+	CategorySlug string
+	Action       string
+	ServiceSlugs []string
+}
+
+type SurchargeReportRow struct {
+	CategorySlug string
+	Code         string
+	Name         string
+	Kind         string
+	OrderCount   int64
+	Charged      float64
+	Pending      float64
+	Collectable  float64
+	Remitted     float64
+	Voided       float64
+}
+
 type PendingActionsData struct {
 	OrdersNeedingProvider int64
 	ExpiredOrders         int64
@@ -326,6 +396,194 @@ func (r *repository) AddonSlugExists(ctx context.Context, slug string, excludeID
 	return count > 0, nil
 }
 
+func (r *repository) GetAddonCompatibility(ctx context.Context, addonSlug string) ([]string, error) {
+	var slugs []string
+	err := r.db.WithContext(ctx).
+		Model(&models.AddonServiceCompatibility{}).
+		Where("addon_slug = ?", addonSlug).
+		Order("service_slug ASC").
+		Pluck("service_slug", &slugs).Error
+	return slugs, err
+}
+
+func (r *repository) GetCategoryCompatibility(ctx context.Context, categorySlug string) ([]models.AddonServiceCompatibility, error) {
+	var rules []models.AddonServiceCompatibility
+	err := r.db.WithContext(ctx).
+		Where("category_slug = ?", categorySlug).
+		Order("addon_slug ASC, service_slug ASC").
+		Find(&rules).Error
+	return rules, err
+}
+
+// ApplyAddonCompatibility applies every change in one transaction so a bulk edit
+// never leaves the mapping half written.
+func (r *repository) ApplyAddonCompatibility(ctx context.Context, changes []AddonCompatibilityChange) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, change := range changes {
+			switch change.Action {
+			case "set":
+				if err := tx.Where("addon_slug = ?", change.AddonSlug).
+					Delete(&models.AddonServiceCompatibility{}).Error; err != nil {
+					return err
+				}
+			case "remove":
+				if err := tx.Where("addon_slug = ? AND service_slug IN ?", change.AddonSlug, change.ServiceSlugs).
+					Delete(&models.AddonServiceCompatibility{}).Error; err != nil {
+					return err
+				}
+				continue
+			}
+
+			for _, serviceSlug := range change.ServiceSlugs {
+				if err := tx.Exec(`
+					INSERT INTO addon_service_compatibility (addon_slug, service_slug, category_slug)
+					VALUES (?, ?, ?)
+					ON CONFLICT (addon_slug, service_slug) DO NOTHING
+				`, change.AddonSlug, serviceSlug, change.CategorySlug).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (r *repository) CreateSurcharge(ctx context.Context, surcharge *models.CategorySurcharge) error {
+	return r.db.WithContext(ctx).Create(surcharge).Error
+}
+
+func (r *repository) GetSurchargeByID(ctx context.Context, id string) (*models.CategorySurcharge, error) {
+	var surcharge models.CategorySurcharge
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&surcharge).Error
+	if err != nil {
+		return nil, err
+	}
+	return &surcharge, nil
+}
+
+func (r *repository) UpdateSurcharge(ctx context.Context, surcharge *models.CategorySurcharge) error {
+	return r.db.WithContext(ctx).Save(surcharge).Error
+}
+
+func (r *repository) DeleteSurcharge(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Delete(&models.CategorySurcharge{}, "id = ?", id).Error
+}
+
+func (r *repository) ListSurcharges(ctx context.Context, query dto.ListSurchargesQuery) ([]*models.CategorySurcharge, error) {
+	var surcharges []*models.CategorySurcharge
+	db := r.db.WithContext(ctx).Model(&models.CategorySurcharge{})
+
+	if query.CategorySlug != "" {
+		db = db.Where("category_slug = ?", query.CategorySlug)
+	}
+	if query.IsActive != nil {
+		db = db.Where("is_active = ?", *query.IsActive)
+	}
+
+	err := db.Order("category_slug ASC, code ASC").Find(&surcharges).Error
+	return surcharges, err
+}
+
+func (r *repository) SurchargeCodeExists(ctx context.Context, categorySlug, code string, excludeID string) (bool, error) {
+	var count int64
+	db := r.db.WithContext(ctx).Model(&models.CategorySurcharge{}).
+		Where("category_slug = ? AND code = ?", categorySlug, code)
+
+	if excludeID != "" {
+		db = db.Where("id != ?", excludeID)
+	}
+
+	if err := db.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetSurchargeReport totals surcharge lines by code for orders created in the
+// range. The bucket a line falls in follows its order's status.
+func (r *repository) GetSurchargeReport(ctx context.Context, fromDate, toDate time.Time, categorySlug string) ([]SurchargeReportRow, error) {
+	var rows []SurchargeReportRow
+	toDateEnd := toDate.AddDate(0, 0, 1)
+
+	db := r.db.WithContext(ctx).
+		Table("service_order_surcharges sos").
+		Joins("JOIN service_orders so ON so.id = sos.order_id").
+		Where("so.created_at >= ? AND so.created_at < ?", fromDate, toDateEnd)
+
+	if categorySlug != "" {
+		db = db.Where("sos.category_slug = ?", categorySlug)
+	}
+
+	err := db.Select(`
+			sos.category_slug, sos.code, MAX(sos.name) as name, MAX(sos.kind) as kind,
+			COUNT(DISTINCT sos.order_id) as order_count,
+			COALESCE(SUM(sos.amount), 0) as charged,
+			COALESCE(SUM(CASE WHEN so.status NOT IN (?, ?) THEN sos.amount ELSE 0 END), 0) as pending,
+			COALESCE(SUM(CASE WHEN so.status = ? AND sos.remitted_at IS NULL THEN sos.amount ELSE 0 END), 0) as collectable,
+			COALESCE(SUM(CASE WHEN sos.remitted_at IS NOT NULL THEN sos.amount ELSE 0 END), 0) as remitted,
+			COALESCE(SUM(CASE WHEN so.status = ? AND sos.remitted_at IS NULL THEN sos.amount ELSE 0 END), 0) as voided
+		`, shared.OrderStatusCompleted, shared.OrderStatusCancelled,
+		shared.OrderStatusCompleted, shared.OrderStatusCancelled).
+		Group("sos.category_slug, sos.code").
+		Order("sos.category_slug ASC, sos.code ASC").
+		Scan(&rows).Error
+
+	return rows, err
+}
+
+// RemitSurcharges marks every collectable line in the range as remitted and
+// returns how many lines and how much money the remittance covers.
+func (r *repository) RemitSurcharges(ctx context.Context, fromDate, toDate time.Time, categorySlug, code, adminID, reference string, remittedAt time.Time) (int64, float64, error) {
+	var count int64
+	var amount float64
+	toDateEnd := toDate.AddDate(0, 0, 1)
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []string
+		db := tx.Table("service_order_surcharges sos").
+			Joins("JOIN service_orders so ON so.id = sos.order_id").
+			Where("so.created_at >= ? AND so.created_at < ?", fromDate, toDateEnd).
+			Where("so.status = ? AND sos.remitted_at IS NULL", shared.OrderStatusCompleted)
+
+		if categorySlug != "" {
+			db = db.Where("sos.category_slug = ?", categorySlug)
+		}
+		if code != "" {
+			db = db.Where("sos.code = ?", code)
+		}
+
+		if err := db.Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "sos"}}).
+			Pluck("sos.id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&models.ServiceOrderSurcharge{}).
+			Where("id IN ?", ids).
+			Select("COALESCE(SUM(amount), 0)").
+			Scan(&amount).Error; err != nil {
+			return err
+		}
+
+		result := tx.Model(&models.ServiceOrderSurcharge{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"remitted_at":    remittedAt,
+				"remitted_by":    adminID,
+				"remittance_ref": reference,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		count = result.RowsAffected
+		return nil
+	})
+
+	return count, amount, err
+}
+
 func (r *repository) GetServicesByCategory(ctx context.Context, categorySlug string) ([]*models.ServiceNew, error) {
 	var services []*models.ServiceNew
 	err := r.db.WithContext(ctx).
@@ -463,6 +721,15 @@ func (r *repository) GetOrderByID(ctx context.Context, id string) (*models.Servi
 	return &order, nil
 }
 
+func (r *repository) GetProviderProfile(ctx context.Context, id string) (*models.ServiceProviderProfile, error) {
+	var profile models.ServiceProviderProfile
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&profile).Error
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
 func (r *repository) GetOrderByNumber(ctx context.Context, orderNumber string) (*models.ServiceOrderNew, error) {
 	var order models.ServiceOrderNew
 	err := r.db.WithContext(ctx).Where("order_number = ?", orderNumber).First(&order).Error
@@ -472,8 +739,10 @@ func (r *repository) GetOrderByNumber(ctx context.Context, orderNumber string) (
 	return &order, nil
 }
 
+// UpdateOrder returns shared.ErrOrderConflict if the order changed since it
+// was read.
 func (r *repository) UpdateOrder(ctx context.Context, order *models.ServiceOrderNew) error {
-	return r.db.WithContext(ctx).Save(order).Error
+	return shared.SaveOrderVersioned(r.db.WithContext(ctx), order)
 }
 
 func (r *repository) GetOrderStatusHistory(ctx context.Context, orderID string) ([]models.OrderStatusHistory, error) {
@@ -489,194 +758,17 @@ func (r *repository) CreateStatusHistory(ctx context.Context, history *models.Or
 	return r.db.WithContext(ctx).Create(history).Error
 }
 
-func (r *repository) GetOrderStats(ctx context.Context, fromDate, toDate time.Time) (*OrderStats, error) {
-	stats := &OrderStats{}
-	toDateEnd := toDate.AddDate(0, 0, 1)
-
-	if err := r.db.WithContext(ctx).
-		Model(&models.ServiceOrderNew{}).
-		Where("created_at >= ? AND created_at < ?", fromDate, toDateEnd).
-		Count(&stats.TotalOrders).Error; err != nil {
-		return nil, err
-	}
-
-	row := r.db.WithContext(ctx).
-		Model(&models.ServiceOrderNew{}).
-		Where("created_at >= ? AND created_at < ?", fromDate, toDateEnd).
-		Where("status = ?", shared.OrderStatusCompleted).
-		Select("COUNT(*), COALESCE(SUM(total_price), 0), COALESCE(SUM(platform_commission), 0)").
-		Row()
-	if err := row.Scan(&stats.CompletedOrders, &stats.TotalRevenue, &stats.TotalCommission); err != nil {
-		return nil, err
-	}
-
-	stats.TotalProviderPayouts = stats.TotalRevenue - stats.TotalCommission
-
-	if err := r.db.WithContext(ctx).
-		Model(&models.ServiceOrderNew{}).
-		Where("created_at >= ? AND created_at < ?", fromDate, toDateEnd).
-		Where("status = ?", shared.OrderStatusCancelled).
-		Count(&stats.CancelledOrders).Error; err != nil {
-		return nil, err
-	}
-
-	if err := r.db.WithContext(ctx).
-		Model(&models.ServiceOrderNew{}).
-		Where("created_at >= ? AND created_at < ?", fromDate, toDateEnd).
-		Where("status IN ?", []string{shared.OrderStatusPending, shared.OrderStatusSearchingProvider}).
-		Count(&stats.PendingOrders).Error; err != nil {
-		return nil, err
-	}
-
-	row = r.db.WithContext(ctx).
-		Model(&models.ServiceOrderNew{}).
-		Where("created_at >= ? AND created_at < ?", fromDate, toDateEnd).
-		Where("customer_rating IS NOT NULL").
-		Select("COUNT(*), COALESCE(SUM(customer_rating), 0)").
-		Row()
-	row.Scan(&stats.TotalRatings, &stats.TotalRatingSum)
-
-	return stats, nil
-}
-
-func (r *repository) GetOrdersByStatus(ctx context.Context, fromDate, toDate time.Time) ([]StatusStats, error) {
-	var stats []StatusStats
-	toDateEnd := toDate.AddDate(0, 0, 1)
-
-	err := r.db.WithContext(ctx).
-		Model(&models.ServiceOrderNew{}).
-		Where("created_at >= ? AND created_at < ?", fromDate, toDateEnd).
-		Select("status, COUNT(*) as count").
-		Group("status").
-		Order("count DESC").
-		Find(&stats).Error
-
-	return stats, err
-}
-
-func (r *repository) GetOrdersByCategory(ctx context.Context, fromDate, toDate time.Time) ([]CategoryStats, error) {
-	var stats []CategoryStats
-	toDateEnd := toDate.AddDate(0, 0, 1)
-
-	err := r.db.WithContext(ctx).
-		Model(&models.ServiceOrderNew{}).
-		Where("created_at >= ? AND created_at < ?", fromDate, toDateEnd).
-		Where("status = ?", shared.OrderStatusCompleted).
-		Select("category_slug, COUNT(*) as order_count, COALESCE(SUM(total_price), 0) as revenue").
-		Group("category_slug").
-		Order("revenue DESC").
-		Find(&stats).Error
-
-	return stats, err
-}
-
-func (r *repository) GetRevenueBreakdown(ctx context.Context, fromDate, toDate time.Time, groupBy string) ([]RevenueStats, error) {
-	var stats []RevenueStats
-	toDateEnd := toDate.AddDate(0, 0, 1)
-
-	var dateFormat string
-	switch groupBy {
-	case "week":
-		dateFormat = "YYYY-IW"
-	case "month":
-		dateFormat = "YYYY-MM"
-	default:
-		dateFormat = "YYYY-MM-DD"
-	}
-
-	err := r.db.WithContext(ctx).
-		Model(&models.ServiceOrderNew{}).
-		Where("created_at >= ? AND created_at < ?", fromDate, toDateEnd).
-		Where("status = ?", shared.OrderStatusCompleted).
-		Select("TO_CHAR(completed_at, ?) as period, COUNT(*) as order_count, COALESCE(SUM(total_price), 0) as revenue, COALESCE(SUM(platform_commission), 0) as commission, COALESCE(SUM(total_price - platform_commission), 0) as provider_payouts", dateFormat).
-		Group("period").
-		Order("period ASC").
-		Find(&stats).Error
-
-	return stats, err
-}
-
-func (r *repository) GetProviderAnalytics(ctx context.Context, fromDate, toDate time.Time, query dto.ProviderAnalyticsQuery) ([]ProviderStats, error) {
-	var stats []ProviderStats
-	toDateEnd := toDate.AddDate(0, 0, 1)
-
-	db := r.db.WithContext(ctx).
-		Model(&models.ServiceOrderNew{}).
-		Where("created_at >= ? AND created_at < ?", fromDate, toDateEnd).
-		Where("assigned_provider_id IS NOT NULL")
-
-	if query.CategorySlug != "" {
-		db = db.Where("category_slug = ?", query.CategorySlug)
-	}
-
-	db = db.Select(`
-		assigned_provider_id as provider_id,
-		SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) as completed_orders,
-		SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) as cancelled_orders,
-		COALESCE(SUM(CASE WHEN status = ? THEN total_price * 0.9 ELSE 0 END), 0) as total_earnings,
-		SUM(CASE WHEN customer_rating IS NOT NULL THEN 1 ELSE 0 END) as total_ratings,
-		COALESCE(SUM(customer_rating), 0) as total_rating_sum
-	`, shared.OrderStatusCompleted, shared.OrderStatusCancelled, shared.OrderStatusCompleted).
-		Group("assigned_provider_id")
-
-	if query.MinOrders != nil {
-		db = db.Having("SUM(CASE WHEN status = ? THEN 1 ELSE 0 END) >= ?", shared.OrderStatusCompleted, *query.MinOrders)
-	}
-
-	switch query.SortBy {
-	case "earnings":
-		if query.SortDesc {
-			db = db.Order("total_earnings DESC")
-		} else {
-			db = db.Order("total_earnings ASC")
-		}
-	case "rating":
-		if query.SortDesc {
-			db = db.Order("total_rating_sum / NULLIF(total_ratings, 0) DESC NULLS LAST")
-		} else {
-			db = db.Order("total_rating_sum / NULLIF(total_ratings, 0) ASC NULLS LAST")
-		}
-	default:
-		if query.SortDesc {
-			db = db.Order("completed_orders DESC")
-		} else {
-			db = db.Order("completed_orders ASC")
-		}
-	}
-
-	db = db.Limit(query.Limit)
-
-	err := db.Find(&stats).Error
-	return stats, err
-}
-
-func (r *repository) GetPaymentMethodStats(ctx context.Context, fromDate, toDate time.Time) ([]PaymentStats, error) {
-	var stats []PaymentStats
-	toDateEnd := toDate.AddDate(0, 0, 1)
-
-	err := r.db.WithContext(ctx).
-		Model(&models.ServiceOrderNew{}).
-		Where("created_at >= ? AND created_at < ?", fromDate, toDateEnd).
-		Where("status = ?", shared.OrderStatusCompleted).
-		Select("payment_info->>'method' as method, COUNT(*) as order_count, COALESCE(SUM(total_price), 0) as total_amount").
-		Group("payment_info->>'method'").
-		Order("total_amount DESC").
-		Find(&stats).Error
-
-	return stats, err
-}
-
 func (r *repository) GetTodayStats(ctx context.Context) (*TodayStatsData, error) {
 	stats := &TodayStatsData{}
 	today := time.Now().Truncate(24 * time.Hour)
 	tomorrow := today.AddDate(0, 0, 1)
 
-	r.db.WithContext(ctx).
+	r.db.WithContext(ctx).Scopes(database.ReadReplica).
 		Model(&models.ServiceOrderNew{}).
 		Where("created_at >= ? AND created_at < ?", today, tomorrow).
 		Count(&stats.TotalOrders)
 
-	row := r.db.WithContext(ctx).
+	row := r.db.WithContext(ctx).Scopes(database.ReadReplica).
 		Model(&models.ServiceOrderNew{}).
 		Where("completed_at >= ? AND completed_at < ?", today, tomorrow).
 		Where("status = ?", shared.OrderStatusCompleted).
@@ -684,16 +776,22 @@ func (r *repository) GetTodayStats(ctx context.Context) (*TodayStatsData, error)
 		Row()
 	row.Scan(&stats.CompletedOrders, &stats.Revenue, &stats.Commission)
 
-	r.db.WithContext(ctx).
+	r.db.WithContext(ctx).Scopes(database.ReadReplica).
 		Model(&models.ServiceOrderNew{}).
 		Where("status IN ?", []string{shared.OrderStatusPending, shared.OrderStatusSearchingProvider, shared.OrderStatusAssigned, shared.OrderStatusAccepted}).
 		Count(&stats.PendingOrders)
 
-	r.db.WithContext(ctx).
+	r.db.WithContext(ctx).Scopes(database.ReadReplica).
 		Model(&models.ServiceOrderNew{}).
 		Where("status = ?", shared.OrderStatusInProgress).
 		Count(&stats.InProgressOrders)
 
+	r.db.WithContext(ctx).Scopes(database.ReadReplica).
+		Model(&models.ServiceOrderNew{}).
+		Scopes(expiredOrders).
+		Where("expires_at >= ? AND expires_at < ?", today, tomorrow).
+		Count(&stats.ExpiredOrders)
+
 	return stats, nil
 }
 
@@ -703,7 +801,7 @@ func (r *repository) GetWeeklyStats(ctx context.Context) (*WeeklyStatsData, erro
 	weekAgo := today.AddDate(0, 0, -7)
 	tomorrow := today.AddDate(0, 0, 1)
 
-	row := r.db.WithContext(ctx).
+	row := r.db.WithContext(ctx).Scopes(database.ReadReplica).
 		Model(&models.ServiceOrderNew{}).
 		Where("created_at >= ? AND created_at < ?", weekAgo, tomorrow).
 		Where("status = ?", shared.OrderStatusCompleted).
@@ -711,12 +809,18 @@ func (r *repository) GetWeeklyStats(ctx context.Context) (*WeeklyStatsData, erro
 		Row()
 	row.Scan(&stats.CompletedOrders, &stats.TotalRevenue)
 
-	r.db.WithContext(ctx).
+	r.db.WithContext(ctx).Scopes(database.ReadReplica).
 		Model(&models.ServiceOrderNew{}).
 		Where("created_at >= ? AND created_at < ?", weekAgo, tomorrow).
 		Count(&stats.TotalOrders)
 
-	row = r.db.WithContext(ctx).
+	r.db.WithContext(ctx).Scopes(database.ReadReplica).
+		Model(&models.ServiceOrderNew{}).
+		Scopes(expiredOrders).
+		Where("expires_at >= ? AND expires_at < ?", weekAgo, tomorrow).
+		Count(&stats.ExpiredOrders)
+
+	row = r.db.WithContext(ctx).Scopes(database.ReadReplica).
 		Model(&models.ServiceOrderNew{}).
 		Where("created_at >= ? AND created_at < ?", weekAgo, tomorrow).
 		Where("customer_rating IS NOT NULL").
@@ -724,7 +828,7 @@ func (r *repository) GetWeeklyStats(ctx context.Context) (*WeeklyStatsData, erro
 		Row()
 	row.Scan(&stats.TotalRatings, &stats.TotalRatingSum)
 
-	rows, err := r.db.WithContext(ctx).
+	rows, err := r.db.WithContext(ctx).Scopes(database.ReadReplica).
 		Model(&models.ServiceOrderNew{}).
 		Where("created_at >= ? AND created_at < ?", weekAgo, tomorrow).
 		Where("status = ?", shared.OrderStatusCompleted).
@@ -746,6 +850,13 @@ func (r *repository) GetWeeklyStats(ctx context.Context) (*WeeklyStatsData, erro
 	return stats, nil
 }
 
+// expiredOrders narrows a query to orders the expiry job cancelled.
+func expiredOrders(db *gorm.DB) *gorm.DB {
+	return db.Where("status = ?", shared.OrderStatusCancelled).
+		Where("cancellation_info->>'cancelledBy' = ? AND cancellation_info->>'reason' = ?",
+			shared.CancelledBySystem, shared.CancellationReasonExpired)
+}
+
 func (r *repository) GetPendingActions(ctx context.Context) (*PendingActionsData, error) {
 	data := &PendingActionsData{}
 
@@ -761,6 +872,12 @@ func (r *repository) GetPendingActions(ctx context.Context) (*PendingActionsData
 		Where("expires_at < ?", time.Now()).
 		Count(&data.ExpiredOrders)
 
+	r.db.WithContext(ctx).
+		Model(&models.Dispute{}).
+		Where("subject_type = ?", models.DisputeSubjectServiceOrder).
+		Where("status IN ?", []models.DisputeStatus{models.DisputeStatusOpen, models.DisputeStatusResponded}).
+		Count(&data.DisputedOrders)
+
 	return data, nil
 }
 
@@ -779,6 +896,7 @@ func (r *repository) BulkUpdateStatus(ctx context.Context, orderIDs []string, st
 		Where("id IN ?", orderIDs).
 		Updates(map[string]interface{}{
 			"status":     status,
+			"version":    gorm.Expr("version + 1"),
 			"updated_at": time.Now(),
 		})
 
@@ -809,26 +927,3 @@ func (r *repository) GetUserByID(ctx context.Context, userID string) (*models.Us
 		First(&user).Error
 	return &user, err
 }
-
-// GetTotalRefunds calculates the total refunded amount for cancelled orders within a date range
-func (r *repository) GetTotalRefunds(ctx context.Context, fromDate, toDate time.Time) (float64, error) {
-	var orders []*models.ServiceOrderNew
-	err := r.db.WithContext(ctx).
-		Where("status = ? AND cancelled_at BETWEEN ? AND ?",
-			"cancelled", fromDate, toDate).
-		Select("cancellation_info").
-		Find(&orders).Error
-
-	if err != nil {
-		return 0, err
-	}
-
-	var totalRefunds float64
-	for _, order := range orders {
-		if order.CancellationInfo != nil {
-			totalRefunds += order.CancellationInfo.RefundAmount
-		}
-	}
-
-	return totalRefunds, nil
-}
@@ -30,12 +30,27 @@ func RegisterRoutes(
 			addons.PUT("/:slug", handler.UpdateAddon)
 			addons.PATCH("/:slug/status", handler.UpdateAddonStatus)
 			addons.DELETE("/:slug", handler.DeleteAddon)
+			addons.GET("/:slug/compatibility", handler.GetAddonCompatibility)
+			addons.PUT("/:slug/compatibility", handler.SetAddonCompatibility)
+		}
+
+		surcharges := homeservices.Group("/surcharges")
+		{
+			surcharges.POST("", handler.CreateSurcharge)
+			surcharges.GET("", handler.ListSurcharges)
+			surcharges.GET("/report", handler.GetSurchargeReport)
+			surcharges.POST("/remit", handler.RemitSurcharges)
+			surcharges.GET("/:id", handler.GetSurcharge)
+			surcharges.PUT("/:id", handler.UpdateSurcharge)
+			surcharges.DELETE("/:id", handler.DeleteSurcharge)
 		}
 
 		categories := homeservices.Group("/categories")
 		{
 			categories.GET("", handler.GetAllCategories)
 			categories.GET("/:categorySlug", handler.GetCategoryDetails)
+			categories.GET("/:categorySlug/compatibility", handler.GetCategoryCompatibility)
+			categories.PUT("/:categorySlug/compatibility", handler.BulkUpdateCompatibility)
 		}
 
 		orders := homeservices.Group("/orders")
@@ -56,7 +71,9 @@ func RegisterRoutes(
 		{
 			analytics.GET("/overview", handler.GetOverviewAnalytics)
 			analytics.GET("/providers", handler.GetProviderAnalytics)
+			analytics.GET("/providers/export", handler.ExportProviderAnalytics)
 			analytics.GET("/revenue", handler.GetRevenueReport)
+			analytics.GET("/revenue/export", handler.ExportRevenueReport)
 		}
 
 		homeservices.GET("/dashboard", handler.GetDashboard)
@@ -2,27 +2,33 @@ package admin
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/lib/pq"
 	"gorm.io/gorm"
 
 	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/audit"
 	"github.com/umar5678/go-backend/internal/modules/homeservices/admin/dto"
 	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
 	"github.com/umar5678/go-backend/internal/modules/wallet"
 	walletdto "github.com/umar5678/go-backend/internal/modules/wallet/dto"
+	"github.com/umar5678/go-backend/internal/services/livemetrics"
 	"github.com/umar5678/go-backend/internal/utils/logger"
 	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+	websocketutil "github.com/umar5678/go-backend/internal/websocket/websocketutils"
 )
 
 type Service interface {
 	CreateService(ctx context.Context, req dto.CreateServiceRequest) (*dto.ServiceResponse, error)
 	GetServiceBySlug(ctx context.Context, slug string) (*dto.ServiceResponse, error)
-	UpdateService(ctx context.Context, slug string, req dto.UpdateServiceRequest) (*dto.ServiceResponse, error)
-	UpdateServiceStatus(ctx context.Context, slug string, req dto.UpdateServiceStatusRequest) (*dto.ServiceResponse, error)
-	DeleteService(ctx context.Context, slug string) error
+	UpdateService(ctx context.Context, slug string, req dto.UpdateServiceRequest, adminID string) (*dto.ServiceResponse, error)
+	UpdateServiceStatus(ctx context.Context, slug string, req dto.UpdateServiceStatusRequest, adminID string) (*dto.ServiceResponse, error)
+	DeleteService(ctx context.Context, slug, adminID string) error
 	ListServices(ctx context.Context, query dto.ListServicesQuery) ([]*dto.ServiceListResponse, *response.PaginationMeta, error)
 
 	CreateAddon(ctx context.Context, req dto.CreateAddonRequest) (*dto.AddonResponse, error)
@@ -32,6 +38,19 @@ type Service interface {
 	DeleteAddon(ctx context.Context, slug string) error
 	ListAddons(ctx context.Context, query dto.ListAddonsQuery) ([]*dto.AddonListResponse, *response.PaginationMeta, error)
 
+	GetAddonCompatibility(ctx context.Context, slug string) (*dto.AddonCompatibilityResponse, error)
+	SetAddonCompatibility(ctx context.Context, slug string, req dto.SetAddonCompatibilityRequest) (*dto.AddonCompatibilityResponse, error)
+	GetCategoryCompatibility(ctx context.Context, categorySlug string) (*dto.CategoryCompatibilityResponse, error)
+	BulkUpdateCompatibility(ctx context.Context, categorySlug string, req dto.BulkAddonCompatibilityRequest) (*dto.CategoryCompatibilityResponse, error)
+
+	CreateSurcharge(ctx context.Context, req dto.CreateSurchargeRequest) (*dto.SurchargeResponse, error)
+	GetSurcharge(ctx context.Context, id string) (*dto.SurchargeResponse, error)
+	UpdateSurcharge(ctx context.Context, id string, req dto.UpdateSurchargeRequest) (*dto.SurchargeResponse, error)
+	DeleteSurcharge(ctx context.Context, id string) error
+	ListSurcharges(ctx context.Context, query dto.ListSurchargesQuery) ([]*dto.SurchargeResponse, error)
+	GetSurchargeReport(ctx context.Context, query dto.SurchargeReportQuery) (*dto.SurchargeReportResponse, error)
+	RemitSurcharges(ctx context.Context, req dto.RemitSurchargesRequest, adminID string) (*dto.RemitSurchargesResponse, error)
+
 	GetCategoryDetails(ctx context.Context, categorySlug string) (*dto.CategoryServicesResponse, error)
 	GetAllCategories(ctx context.Context) ([]string, error)
 
@@ -47,13 +66,22 @@ type Service interface {
 	GetOverviewAnalytics(ctx context.Context, query dto.AnalyticsQuery) (*dto.OverviewAnalyticsResponse, error)
 	GetProviderAnalytics(ctx context.Context, query dto.ProviderAnalyticsQuery) (*dto.ProviderAnalyticsResponse, error)
 	GetRevenueReport(ctx context.Context, query dto.AnalyticsQuery) (*dto.RevenueReportResponse, error)
+	ExportRevenueReport(ctx context.Context, query dto.AnalyticsQuery) (*dto.AnalyticsExport, error)
+	ExportProviderAnalytics(ctx context.Context, query dto.ProviderAnalyticsQuery) (*dto.AnalyticsExport, error)
 
 	GetDashboard(ctx context.Context) (*dto.DashboardResponse, error)
+
+	SetAuditLogger(auditLogger audit.Recorder)
+	SetWebhookPublisher(publisher shared.WebhookPublisher)
+	SetEventPublisher(publisher shared.EventPublisher)
 }
 
 type service struct {
 	repo          Repository
 	walletService wallet.Service
+	auditLogger   audit.Recorder
+	webhooks      shared.WebhookPublisher
+	events        shared.EventPublisher
 }
 
 func NewService(repo Repository, walletService wallet.Service) Service {
@@ -103,6 +131,7 @@ func (s *service) CreateService(ctx context.Context, req dto.CreateServiceReques
 		return nil, response.InternalServerError("Failed to create service", err)
 	}
 
+	shared.InvalidateCatalogCache(ctx)
 	logger.Info("service created", "serviceID", svc.ID, "slug", svc.ServiceSlug)
 
 	return dto.ToServiceResponse(svc), nil
@@ -121,7 +150,7 @@ func (s *service) GetServiceBySlug(ctx context.Context, slug string) (*dto.Servi
 	return dto.ToServiceResponse(svc), nil
 }
 
-func (s *service) UpdateService(ctx context.Context, slug string, req dto.UpdateServiceRequest) (*dto.ServiceResponse, error) {
+func (s *service) UpdateService(ctx context.Context, slug string, req dto.UpdateServiceRequest, adminID string) (*dto.ServiceResponse, error) {
 	if err := req.Validate(); err != nil {
 		return nil, response.BadRequest(err.Error())
 	}
@@ -133,6 +162,7 @@ func (s *service) UpdateService(ctx context.Context, slug string, req dto.Update
 		}
 		return nil, response.InternalServerError("Failed to get service", err)
 	}
+	before := dto.ToServiceResponse(svc)
 
 	if req.Title != nil {
 		svc.Title = *req.Title
@@ -191,12 +221,16 @@ func (s *service) UpdateService(ctx context.Context, slug string, req dto.Update
 		return nil, response.InternalServerError("Failed to update service", err)
 	}
 
+	shared.InvalidateCatalogCache(ctx)
 	logger.Info("service updated", "serviceID", svc.ID, "slug", svc.ServiceSlug)
 
-	return dto.ToServiceResponse(svc), nil
+	after := dto.ToServiceResponse(svc)
+	s.recordAudit(ctx, adminID, "service.update", "service", svc.ID, before, after, "", nil)
+
+	return after, nil
 }
 
-func (s *service) UpdateServiceStatus(ctx context.Context, slug string, req dto.UpdateServiceStatusRequest) (*dto.ServiceResponse, error) {
+func (s *service) UpdateServiceStatus(ctx context.Context, slug string, req dto.UpdateServiceStatusRequest, adminID string) (*dto.ServiceResponse, error) {
 	if err := req.Validate(); err != nil {
 		return nil, response.BadRequest(err.Error())
 	}
@@ -208,6 +242,7 @@ func (s *service) UpdateServiceStatus(ctx context.Context, slug string, req dto.
 		}
 		return nil, response.InternalServerError("Failed to get service", err)
 	}
+	before := map[string]interface{}{"isActive": svc.IsActive, "isAvailable": svc.IsAvailable}
 
 	if req.IsActive != nil {
 		svc.IsActive = *req.IsActive
@@ -221,13 +256,17 @@ func (s *service) UpdateServiceStatus(ctx context.Context, slug string, req dto.
 		return nil, response.InternalServerError("Failed to update service status", err)
 	}
 
+	shared.InvalidateCatalogCache(ctx)
 	logger.Info("service status updated", "serviceID", svc.ID, "slug", svc.ServiceSlug,
 		"isActive", svc.IsActive, "isAvailable", svc.IsAvailable)
 
+	s.recordAudit(ctx, adminID, "service.update_status", "service", svc.ID, before,
+		map[string]interface{}{"isActive": svc.IsActive, "isAvailable": svc.IsAvailable}, "", nil)
+
 	return dto.ToServiceResponse(svc), nil
 }
 
-func (s *service) DeleteService(ctx context.Context, slug string) error {
+func (s *service) DeleteService(ctx context.Context, slug, adminID string) error {
 	svc, err := s.repo.GetServiceBySlug(ctx, slug)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -241,8 +280,11 @@ func (s *service) DeleteService(ctx context.Context, slug string) error {
 		return response.InternalServerError("Failed to delete service", err)
 	}
 
+	shared.InvalidateCatalogCache(ctx)
 	logger.Info("service deleted", "serviceID", svc.ID, "slug", slug)
 
+	s.recordAudit(ctx, adminID, "service.delete", "service", svc.ID, dto.ToServiceResponse(svc), nil, "", nil)
+
 	return nil
 }
 
@@ -296,6 +338,7 @@ func (s *service) CreateAddon(ctx context.Context, req dto.CreateAddonRequest) (
 		return nil, response.InternalServerError("Failed to create addon", err)
 	}
 
+	shared.InvalidateCatalogCache(ctx)
 	logger.Info("addon created", "addonID", addon.ID, "slug", addon.AddonSlug)
 
 	return dto.ToAddonResponse(addon), nil
@@ -327,6 +370,8 @@ func (s *service) UpdateAddon(ctx context.Context, slug string, req dto.UpdateAd
 		return nil, response.InternalServerError("Failed to get addon", err)
 	}
 
+	previousCategory := addon.CategorySlug
+
 	if req.Title != nil {
 		addon.Title = *req.Title
 	}
@@ -374,6 +419,17 @@ func (s *service) UpdateAddon(ctx context.Context, slug string, req dto.UpdateAd
 		return nil, response.InternalServerError("Failed to update addon", err)
 	}
 
+	// Compatibility rules point at services of the old category.
+	if previousCategory != addon.CategorySlug {
+		if err := s.repo.ApplyAddonCompatibility(ctx, []AddonCompatibilityChange{{
+			AddonSlug: addon.AddonSlug,
+			Action:    "set",
+		}}); err != nil {
+			logger.Error("failed to clear addon compatibility", "error", err, "slug", slug)
+		}
+	}
+
+	shared.InvalidateCatalogCache(ctx)
 	logger.Info("addon updated", "addonID", addon.ID, "slug", addon.AddonSlug)
 
 	return dto.ToAddonResponse(addon), nil
@@ -404,6 +460,7 @@ func (s *service) UpdateAddonStatus(ctx context.Context, slug string, req dto.Up
 		return nil, response.InternalServerError("Failed to update addon status", err)
 	}
 
+	shared.InvalidateCatalogCache(ctx)
 	logger.Info("addon status updated", "addonID", addon.ID, "slug", addon.AddonSlug,
 		"isActive", addon.IsActive, "isAvailable", addon.IsAvailable)
 
@@ -424,6 +481,7 @@ func (s *service) DeleteAddon(ctx context.Context, slug string) error {
 		return response.InternalServerError("Failed to delete addon", err)
 	}
 
+	shared.InvalidateCatalogCache(ctx)
 	logger.Info("addon deleted", "addonID", addon.ID, "slug", slug)
 
 	return nil
@@ -445,6 +503,412 @@ func (s *service) ListAddons(ctx context.Context, query dto.ListAddonsQuery) ([]
 	return responses, &pagination, nil
 }
 
+func (s *service) GetAddonCompatibility(ctx context.Context, slug string) (*dto.AddonCompatibilityResponse, error) {
+	addon, err := s.repo.GetAddonBySlug(ctx, slug)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, response.NotFoundError("Addon")
+		}
+		return nil, response.InternalServerError("Failed to get addon", err)
+	}
+
+	serviceSlugs, err := s.repo.GetAddonCompatibility(ctx, addon.AddonSlug)
+	if err != nil {
+		logger.Error("failed to get addon compatibility", "error", err, "slug", slug)
+		return nil, response.InternalServerError("Failed to get addon compatibility", err)
+	}
+
+	return toAddonCompatibilityResponse(addon.AddonSlug, addon.CategorySlug, serviceSlugs), nil
+}
+
+func (s *service) SetAddonCompatibility(ctx context.Context, slug string, req dto.SetAddonCompatibilityRequest) (*dto.AddonCompatibilityResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	addon, err := s.repo.GetAddonBySlug(ctx, slug)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, response.NotFoundError("Addon")
+		}
+		return nil, response.InternalServerError("Failed to get addon", err)
+	}
+
+	categoryServices, err := s.categoryServiceSlugs(ctx, addon.CategorySlug)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkServicesInCategory(req.ServiceSlugs, categoryServices, addon.CategorySlug); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.ApplyAddonCompatibility(ctx, []AddonCompatibilityChange{{
+		AddonSlug:    addon.AddonSlug,
+		CategorySlug: addon.CategorySlug,
+		Action:       "set",
+		ServiceSlugs: req.ServiceSlugs,
+	}}); err != nil {
+		logger.Error("failed to set addon compatibility", "error", err, "slug", slug)
+		return nil, response.InternalServerError("Failed to update addon compatibility", err)
+	}
+
+	shared.InvalidateCatalogCache(ctx)
+	logger.Info("addon compatibility updated", "slug", addon.AddonSlug, "services", len(req.ServiceSlugs))
+
+	return s.GetAddonCompatibility(ctx, addon.AddonSlug)
+}
+
+func (s *service) GetCategoryCompatibility(ctx context.Context, categorySlug string) (*dto.CategoryCompatibilityResponse, error) {
+	services, err := s.repo.GetServicesByCategory(ctx, categorySlug)
+	if err != nil {
+		logger.Error("failed to get services by category", "error", err, "category", categorySlug)
+		return nil, response.InternalServerError("Failed to get category compatibility", err)
+	}
+
+	addons, err := s.repo.GetAddonsByCategory(ctx, categorySlug)
+	if err != nil {
+		logger.Error("failed to get addons by category", "error", err, "category", categorySlug)
+		return nil, response.InternalServerError("Failed to get category compatibility", err)
+	}
+
+	rules, err := s.repo.GetCategoryCompatibility(ctx, categorySlug)
+	if err != nil {
+		logger.Error("failed to get category compatibility", "error", err, "category", categorySlug)
+		return nil, response.InternalServerError("Failed to get category compatibility", err)
+	}
+
+	byAddon := make(map[string][]string)
+	for _, rule := range rules {
+		byAddon[rule.AddonSlug] = append(byAddon[rule.AddonSlug], rule.ServiceSlug)
+	}
+
+	result := &dto.CategoryCompatibilityResponse{
+		CategorySlug: categorySlug,
+		Services:     make([]string, len(services)),
+		Addons:       make([]*dto.AddonCompatibilityResponse, len(addons)),
+	}
+	for i, svc := range services {
+		result.Services[i] = svc.ServiceSlug
+	}
+	for i, addon := range addons {
+		result.Addons[i] = toAddonCompatibilityResponse(addon.AddonSlug, categorySlug, byAddon[addon.AddonSlug])
+	}
+
+	return result, nil
+}
+
+func (s *service) BulkUpdateCompatibility(ctx context.Context, categorySlug string, req dto.BulkAddonCompatibilityRequest) (*dto.CategoryCompatibilityResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	addons, err := s.repo.GetAddonsByCategory(ctx, categorySlug)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get addons", err)
+	}
+	categoryAddons := make(map[string]bool, len(addons))
+	for _, addon := range addons {
+		categoryAddons[addon.AddonSlug] = true
+	}
+
+	categoryServices, err := s.categoryServiceSlugs(ctx, categorySlug)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]AddonCompatibilityChange, 0, len(req.Rules))
+	for _, rule := range req.Rules {
+		if !categoryAddons[rule.AddonSlug] {
+			return nil, response.BadRequest(fmt.Sprintf("Addon '%s' does not belong to category '%s'", rule.AddonSlug, categorySlug))
+		}
+		if rule.Action != "remove" {
+			if err := checkServicesInCategory(rule.ServiceSlugs, categoryServices, categorySlug); err != nil {
+				return nil, err
+			}
+		}
+		changes = append(changes, AddonCompatibilityChange{
+			AddonSlug:    rule.AddonSlug,
+			CategorySlug: categorySlug,
+			Action:       rule.Action,
+			ServiceSlugs: rule.ServiceSlugs,
+		})
+	}
+
+	if err := s.repo.ApplyAddonCompatibility(ctx, changes); err != nil {
+		logger.Error("failed to apply bulk addon compatibility", "error", err, "category", categorySlug)
+		return nil, response.InternalServerError("Failed to update addon compatibility", err)
+	}
+
+	shared.InvalidateCatalogCache(ctx)
+	logger.Info("addon compatibility bulk updated", "category", categorySlug, "rules", len(changes))
+
+	return s.GetCategoryCompatibility(ctx, categorySlug)
+}
+
+func (s *service) categoryServiceSlugs(ctx context.Context, categorySlug string) (map[string]bool, error) {
+	services, err := s.repo.GetServicesByCategory(ctx, categorySlug)
+	if err != nil {
+		logger.Error("failed to get services by category", "error", err, "category", categorySlug)
+		return nil, response.InternalServerError("Failed to get services", err)
+	}
+
+	slugs := make(map[string]bool, len(services))
+	for _, svc := range services {
+		slugs[svc.ServiceSlug] = true
+	}
+	return slugs, nil
+}
+
+func checkServicesInCategory(serviceSlugs []string, categoryServices map[string]bool, categorySlug string) error {
+	for _, slug := range serviceSlugs {
+		if !categoryServices[slug] {
+			return response.BadRequest(fmt.Sprintf("Service '%s' does not belong to category '%s'", slug, categorySlug))
+		}
+	}
+	return nil
+}
+
+func toAddonCompatibilityResponse(addonSlug, categorySlug string, serviceSlugs []string) *dto.AddonCompatibilityResponse {
+	if serviceSlugs == nil {
+		serviceSlugs = []string{}
+	}
+	return &dto.AddonCompatibilityResponse{
+		AddonSlug:          addonSlug,
+		CategorySlug:       categorySlug,
+		Restricted:         len(serviceSlugs) > 0,
+		CompatibleServices: serviceSlugs,
+	}
+}
+
+func (s *service) CreateSurcharge(ctx context.Context, req dto.CreateSurchargeRequest) (*dto.SurchargeResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	if err := s.ensureCategoryExists(ctx, req.CategorySlug); err != nil {
+		return nil, err
+	}
+
+	exists, err := s.repo.SurchargeCodeExists(ctx, req.CategorySlug, req.Code, "")
+	if err != nil {
+		logger.Error("failed to check surcharge code existence", "error", err, "category", req.CategorySlug, "code", req.Code)
+		return nil, response.InternalServerError("Failed to create surcharge", err)
+	}
+	if exists {
+		return nil, response.ConflictError(fmt.Sprintf("Surcharge with code '%s' already exists in category '%s'", req.Code, req.CategorySlug))
+	}
+
+	surcharge := &models.CategorySurcharge{
+		CategorySlug: req.CategorySlug,
+		Code:         req.Code,
+		Name:         req.Name,
+		Description:  req.Description,
+		Kind:         req.Kind,
+		Type:         req.Type,
+		Amount:       req.Amount,
+		MinAmount:    req.MinAmount,
+		MaxAmount:    req.MaxAmount,
+		IsActive:     *req.IsActive,
+	}
+
+	if err := s.repo.CreateSurcharge(ctx, surcharge); err != nil {
+		logger.Error("failed to create surcharge", "error", err, "category", req.CategorySlug, "code", req.Code)
+		return nil, response.InternalServerError("Failed to create surcharge", err)
+	}
+
+	logger.Info("surcharge created", "surchargeID", surcharge.ID, "category", surcharge.CategorySlug, "code", surcharge.Code)
+
+	return dto.ToSurchargeResponse(surcharge), nil
+}
+
+func (s *service) GetSurcharge(ctx context.Context, id string) (*dto.SurchargeResponse, error) {
+	surcharge, err := s.getSurcharge(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToSurchargeResponse(surcharge), nil
+}
+
+func (s *service) UpdateSurcharge(ctx context.Context, id string, req dto.UpdateSurchargeRequest) (*dto.SurchargeResponse, error) {
+	surcharge, err := s.getSurcharge(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		surcharge.Name = *req.Name
+	}
+	if req.Description != nil {
+		surcharge.Description = *req.Description
+	}
+	if req.Kind != nil {
+		surcharge.Kind = *req.Kind
+	}
+	if req.Type != nil {
+		surcharge.Type = *req.Type
+		if surcharge.Type == models.SurchargeTypeFixed {
+			surcharge.MinAmount = nil
+			surcharge.MaxAmount = nil
+		}
+	}
+	if req.Amount != nil {
+		surcharge.Amount = *req.Amount
+	}
+	if req.MinAmount != nil {
+		surcharge.MinAmount = req.MinAmount
+	}
+	if req.MaxAmount != nil {
+		surcharge.MaxAmount = req.MaxAmount
+	}
+	if req.IsActive != nil {
+		surcharge.IsActive = *req.IsActive
+	}
+
+	if err := dto.ValidateSurchargeAmounts(surcharge.Type, surcharge.Amount, surcharge.MinAmount, surcharge.MaxAmount); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	if err := s.repo.UpdateSurcharge(ctx, surcharge); err != nil {
+		logger.Error("failed to update surcharge", "error", err, "surchargeID", id)
+		return nil, response.InternalServerError("Failed to update surcharge", err)
+	}
+
+	logger.Info("surcharge updated", "surchargeID", surcharge.ID, "code", surcharge.Code)
+
+	return dto.ToSurchargeResponse(surcharge), nil
+}
+
+// DeleteSurcharge stops the surcharge applying to new orders. Lines already
+// charged on orders keep their own copy of the code and amount.
+func (s *service) DeleteSurcharge(ctx context.Context, id string) error {
+	surcharge, err := s.getSurcharge(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteSurcharge(ctx, surcharge.ID); err != nil {
+		logger.Error("failed to delete surcharge", "error", err, "surchargeID", id)
+		return response.InternalServerError("Failed to delete surcharge", err)
+	}
+
+	logger.Info("surcharge deleted", "surchargeID", surcharge.ID, "code", surcharge.Code)
+
+	return nil
+}
+
+func (s *service) ListSurcharges(ctx context.Context, query dto.ListSurchargesQuery) ([]*dto.SurchargeResponse, error) {
+	query.CategorySlug = strings.ToLower(strings.TrimSpace(query.CategorySlug))
+
+	surcharges, err := s.repo.ListSurcharges(ctx, query)
+	if err != nil {
+		logger.Error("failed to list surcharges", "error", err)
+		return nil, response.InternalServerError("Failed to list surcharges", err)
+	}
+
+	return dto.ToSurchargeResponses(surcharges), nil
+}
+
+func (s *service) GetSurchargeReport(ctx context.Context, query dto.SurchargeReportQuery) (*dto.SurchargeReportResponse, error) {
+	if err := query.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	fromDate, _ := time.Parse("2006-01-02", query.FromDate)
+	toDate, _ := time.Parse("2006-01-02", query.ToDate)
+
+	rows, err := s.repo.GetSurchargeReport(ctx, fromDate, toDate, strings.ToLower(strings.TrimSpace(query.CategorySlug)))
+	if err != nil {
+		logger.Error("failed to get surcharge report", "error", err)
+		return nil, response.InternalServerError("Failed to get surcharge report", err)
+	}
+
+	report := &dto.SurchargeReportResponse{
+		FromDate: query.FromDate,
+		ToDate:   query.ToDate,
+		Lines:    make([]dto.SurchargeReportLine, len(rows)),
+	}
+	for i, row := range rows {
+		report.Lines[i] = dto.SurchargeReportLine{
+			CategorySlug: row.CategorySlug,
+			Code:         row.Code,
+			Name:         row.Name,
+			Kind:         row.Kind,
+			OrderCount:   row.OrderCount,
+			Charged:      row.Charged,
+			Pending:      row.Pending,
+			Collectable:  row.Collectable,
+			Remitted:     row.Remitted,
+			Voided:       row.Voided,
+		}
+		report.Charged += row.Charged
+		report.Pending += row.Pending
+		report.Collectable += row.Collectable
+		report.Remitted += row.Remitted
+		report.Voided += row.Voided
+	}
+
+	return report, nil
+}
+
+func (s *service) RemitSurcharges(ctx context.Context, req dto.RemitSurchargesRequest, adminID string) (*dto.RemitSurchargesResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	fromDate, _ := time.Parse("2006-01-02", req.FromDate)
+	toDate, _ := time.Parse("2006-01-02", req.ToDate)
+	remittedAt := time.Now().UTC()
+
+	count, amount, err := s.repo.RemitSurcharges(ctx, fromDate, toDate, req.CategorySlug, req.Code, adminID, req.Reference, remittedAt)
+	if err != nil {
+		logger.Error("failed to remit surcharges", "error", err, "reference", req.Reference)
+		return nil, response.InternalServerError("Failed to remit surcharges", err)
+	}
+	if count == 0 {
+		return nil, response.BadRequest("No collectable surcharges in the selected range")
+	}
+
+	logger.Info("surcharges remitted",
+		"reference", req.Reference,
+		"lines", count,
+		"amount", amount,
+		"adminID", adminID,
+	)
+
+	return &dto.RemitSurchargesResponse{
+		Reference:  req.Reference,
+		LineCount:  count,
+		Amount:     amount,
+		RemittedAt: remittedAt,
+	}, nil
+}
+
+func (s *service) getSurcharge(ctx context.Context, id string) (*models.CategorySurcharge, error) {
+	surcharge, err := s.repo.GetSurchargeByID(ctx, id)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, response.NotFoundError("Surcharge")
+		}
+		logger.Error("failed to get surcharge", "error", err, "surchargeID", id)
+		return nil, response.InternalServerError("Failed to get surcharge", err)
+	}
+	return surcharge, nil
+}
+
+func (s *service) ensureCategoryExists(ctx context.Context, categorySlug string) error {
+	categories, err := s.repo.GetAllCategories(ctx)
+	if err != nil {
+		logger.Error("failed to get categories", "error", err)
+		return response.InternalServerError("Failed to get categories", err)
+	}
+	for _, c := range categories {
+		if c == categorySlug {
+			return nil
+		}
+	}
+	return response.NotFoundError("Category")
+}
+
 func (s *service) GetCategoryDetails(ctx context.Context, categorySlug string) (*dto.CategoryServicesResponse, error) {
 	services, err := s.repo.GetServicesByCategory(ctx, categorySlug)
 	if err != nil {
@@ -558,48 +1022,64 @@ func (s *service) UpdateOrderStatus(ctx context.Context, orderID string, req dto
 		return nil, response.BadRequest(err.Error())
 	}
 
-	order, err := s.repo.GetOrderByID(ctx, orderID)
-	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, response.NotFoundError("Order")
+	var (
+		order          *models.ServiceOrderNew
+		previousStatus string
+		before         map[string]interface{}
+	)
+	err := shared.RetryOnConflict(func() error {
+		var err error
+		order, err = s.repo.GetOrderByID(ctx, orderID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return response.NotFoundError("Order")
+			}
+			return response.InternalServerError("Failed to get order", err)
 		}
-		return nil, response.InternalServerError("Failed to get order", err)
-	}
 
-	if !shared.CanTransition(order.Status, req.Status) {
-		return nil, response.BadRequest(fmt.Sprintf("Cannot transition from '%s' to '%s'", order.Status, req.Status))
-	}
+		if !shared.CanTransition(order.Status, req.Status) {
+			return response.BadRequest(fmt.Sprintf("Cannot transition from '%s' to '%s'", order.Status, req.Status))
+		}
 
-	previousStatus := order.Status
+		previousStatus = order.Status
+		before = orderAuditSnapshot(order)
 
-	order.Status = req.Status
-	now := time.Now()
+		order.Status = req.Status
+		now := time.Now()
 
-	switch req.Status {
-	case shared.OrderStatusAccepted:
-		if order.ProviderAcceptedAt == nil {
-			order.ProviderAcceptedAt = &now
-		}
-	case shared.OrderStatusInProgress:
-		if order.ProviderStartedAt == nil {
-			order.ProviderStartedAt = &now
-		}
-	case shared.OrderStatusCompleted:
-		if order.CompletedAt == nil {
-			order.CompletedAt = &now
-		}
-		if order.ProviderCompletedAt == nil {
-			order.ProviderCompletedAt = &now
-		}
-		if order.PaymentInfo != nil {
-			order.PaymentInfo.Status = shared.PaymentStatusCompleted
-			order.PaymentInfo.AmountPaid = order.TotalPrice
+		switch req.Status {
+		case shared.OrderStatusAccepted:
+			if order.ProviderAcceptedAt == nil {
+				order.ProviderAcceptedAt = &now
+			}
+		case shared.OrderStatusInProgress:
+			if order.ProviderStartedAt == nil {
+				order.ProviderStartedAt = &now
+			}
+		case shared.OrderStatusCompleted:
+			if order.CompletedAt == nil {
+				order.CompletedAt = &now
+			}
+			if order.ProviderCompletedAt == nil {
+				order.ProviderCompletedAt = &now
+			}
+			if order.PaymentInfo != nil {
+				order.PaymentInfo.Status = shared.PaymentStatusCompleted
+				order.PaymentInfo.AmountPaid = order.TotalPrice
+			}
 		}
-	}
 
-	if err := s.repo.UpdateOrder(ctx, order); err != nil {
-		logger.Error("failed to update order status", "error", err, "orderID", orderID)
-		return nil, response.InternalServerError("Failed to update order status", err)
+		if err := s.repo.UpdateOrder(ctx, order); err != nil {
+			if errors.Is(err, shared.ErrOrderConflict) {
+				return err
+			}
+			logger.Error("failed to update order status", "error", err, "orderID", orderID)
+			return response.InternalServerError("Failed to update order status", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	notes := req.Reason
@@ -628,6 +1108,12 @@ func (s *service) UpdateOrderStatus(ctx context.Context, orderID string, req dto
 		"toStatus", req.Status,
 	)
 
+	s.recordAudit(ctx, adminID, "order.update_status", "service_order", order.ID, before, orderAuditSnapshot(order), notes, nil)
+
+	if req.Status == shared.OrderStatusCompleted {
+		s.publishOrderCompleted(ctx, order)
+	}
+
 	return s.GetOrderByID(ctx, orderID)
 }
 
@@ -636,40 +1122,69 @@ func (s *service) ReassignOrder(ctx context.Context, orderID string, req dto.Rea
 		return nil, response.BadRequest(err.Error())
 	}
 
-	order, err := s.repo.GetOrderByID(ctx, orderID)
+	provider, err := s.repo.GetProviderProfile(ctx, req.ProviderID)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, response.NotFoundError("Order")
+			return nil, response.NotFoundError("Provider")
 		}
-		return nil, response.InternalServerError("Failed to get order", err)
+		return nil, response.InternalServerError("Failed to get provider", err)
 	}
-
-	reassignableStatuses := []string{
-		shared.OrderStatusAssigned,
-		shared.OrderStatusAccepted,
+	if !provider.CanReceiveOrders() {
+		return nil, response.BadRequest("Provider is not approved to receive orders")
 	}
-	canReassign := false
-	for _, status := range reassignableStatuses {
-		if order.Status == status {
-			canReassign = true
-			break
+
+	var (
+		order         *models.ServiceOrderNew
+		oldProviderID *string
+		before        map[string]interface{}
+	)
+	err = shared.RetryOnConflict(func() error {
+		var err error
+		order, err = s.repo.GetOrderByID(ctx, orderID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return response.NotFoundError("Order")
+			}
+			return response.InternalServerError("Failed to get order", err)
 		}
-	}
-	if !canReassign {
-		return nil, response.BadRequest(fmt.Sprintf("Cannot reassign order in '%s' status", order.Status))
-	}
 
-	oldProviderID := order.AssignedProviderID
+		reassignableStatuses := []string{
+			shared.OrderStatusAssigned,
+			shared.OrderStatusAccepted,
+		}
+		canReassign := false
+		for _, status := range reassignableStatuses {
+			if order.Status == status {
+				canReassign = true
+				break
+			}
+		}
+		if !canReassign {
+			return response.BadRequest(fmt.Sprintf("Cannot reassign order in '%s' status", order.Status))
+		}
+
+		oldProviderID = order.AssignedProviderID
+		before = orderAuditSnapshot(order)
 
-	order.AssignedProviderID = &req.ProviderID
-	order.Status = shared.OrderStatusAssigned
-	order.ProviderAcceptedAt = nil
+		order.AssignedProviderID = &req.ProviderID
+		order.Status = shared.OrderStatusAssigned
+		order.ProviderAcceptedAt = nil
 
-	if err := s.repo.UpdateOrder(ctx, order); err != nil {
-		logger.Error("failed to reassign order", "error", err, "orderID", orderID)
-		return nil, response.InternalServerError("Failed to reassign order", err)
+		if err := s.repo.UpdateOrder(ctx, order); err != nil {
+			if errors.Is(err, shared.ErrOrderConflict) {
+				return err
+			}
+			logger.Error("failed to reassign order", "error", err, "orderID", orderID)
+			return response.InternalServerError("Failed to reassign order", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
+	livemetrics.OrderNotSearching(ctx, order.ID)
+
 	metadata := models.StatusHistoryMetadata{
 		"newProviderId": req.ProviderID,
 	}
@@ -694,6 +1209,20 @@ func (s *service) ReassignOrder(ctx context.Context, orderID string, req dto.Rea
 		"newProviderID", req.ProviderID,
 	)
 
+	s.recordAudit(ctx, adminID, "order.reassign", "service_order", order.ID, before, orderAuditSnapshot(order), req.Reason, nil)
+	s.publishOrderAssigned(ctx, order, oldProviderID)
+
+	if err := websocketutil.SendToUser(provider.UserID, websocket.TypeOrderAssigned, map[string]interface{}{
+		"orderId":     order.ID,
+		"orderNumber": order.OrderNumber,
+		"status":      order.Status,
+		"bookingDate": order.BookingInfo.Date,
+		"bookingTime": order.BookingInfo.Time,
+		"reassigned":  true,
+	}); err != nil {
+		logger.Warn("failed to notify provider of assignment", "error", err, "orderID", order.ID)
+	}
+
 	return s.GetOrderByID(ctx, orderID)
 }
 
@@ -702,35 +1231,74 @@ func (s *service) CancelOrder(ctx context.Context, orderID string, req dto.Admin
 		return nil, response.BadRequest(err.Error())
 	}
 
-	order, err := s.repo.GetOrderByID(ctx, orderID)
-	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, response.NotFoundError("Order")
+	var (
+		order           *models.ServiceOrderNew
+		previousStatus  string
+		before          map[string]interface{}
+		refundAmount    float64
+		cancellationFee float64
+	)
+	// The order is marked cancelled before any money moves, so a retry after
+	// a conflicting edit never charges the fee twice.
+	err := shared.RetryOnConflict(func() error {
+		var err error
+		order, err = s.repo.GetOrderByID(ctx, orderID)
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				return response.NotFoundError("Order")
+			}
+			return response.InternalServerError("Failed to get order", err)
 		}
-		return nil, response.InternalServerError("Failed to get order", err)
-	}
 
-	if order.Status == shared.OrderStatusCancelled {
-		return nil, response.BadRequest("Order is already cancelled")
-	}
+		if order.Status == shared.OrderStatusCancelled {
+			return response.BadRequest("Order is already cancelled")
+		}
 
-	if order.Status == shared.OrderStatusCompleted {
-		return nil, response.BadRequest("Cannot cancel a completed order. Use refund instead.")
-	}
+		if order.Status == shared.OrderStatusCompleted {
+			return response.BadRequest("Cannot cancel a completed order. Use refund instead.")
+		}
+
+		if req.RefundAmount != nil {
+			refundAmount = *req.RefundAmount
+			if refundAmount > order.TotalPrice {
+				return response.BadRequest("Refund amount cannot exceed order total")
+			}
+			cancellationFee = order.TotalPrice - refundAmount
+		} else {
+			cancellationFee, refundAmount = shared.CalculateCancellationFee(order.Status, order.TotalPrice)
+		}
+		previousStatus = order.Status
+		before = orderAuditSnapshot(order)
+
+		order.Status = shared.OrderStatusCancelled
+		order.CancellationInfo = &models.CancellationInfo{
+			CancelledBy:     shared.CancelledByAdmin,
+			CancelledAt:     time.Now(),
+			Reason:          req.Reason,
+			CancellationFee: cancellationFee,
+			RefundAmount:    refundAmount,
+		}
 
-	var refundAmount float64
-	var cancellationFee float64
+		if order.PaymentInfo != nil {
+			if refundAmount > 0 {
+				order.PaymentInfo.Status = shared.PaymentStatusRefunded
+			} else {
+				order.PaymentInfo.Status = shared.PaymentStatusCompleted
+			}
+		}
 
-	if req.RefundAmount != nil {
-		refundAmount = *req.RefundAmount
-		if refundAmount > order.TotalPrice {
-			return nil, response.BadRequest("Refund amount cannot exceed order total")
+		if err := s.repo.UpdateOrder(ctx, order); err != nil {
+			if errors.Is(err, shared.ErrOrderConflict) {
+				return err
+			}
+			logger.Error("failed to cancel order", "error", err, "orderID", orderID)
+			return response.InternalServerError("Failed to cancel order", err)
 		}
-		cancellationFee = order.TotalPrice - refundAmount
-	} else {
-		cancellationFee, refundAmount = shared.CalculateCancellationFee(order.Status, order.TotalPrice)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-	previousStatus := order.Status
 
 	if order.WalletHoldID != nil && refundAmount > 0 {
 		releaseReq := walletdto.ReleaseHoldRequest{HoldID: *order.WalletHoldID}
@@ -757,28 +1325,7 @@ func (s *service) CancelOrder(ctx context.Context, orderID string, req dto.Admin
 		}
 	}
 
-	now := time.Now()
-	order.Status = shared.OrderStatusCancelled
-	order.CancellationInfo = &models.CancellationInfo{
-		CancelledBy:     shared.CancelledByAdmin,
-		CancelledAt:     now,
-		Reason:          req.Reason,
-		CancellationFee: cancellationFee,
-		RefundAmount:    refundAmount,
-	}
-
-	if order.PaymentInfo != nil {
-		if refundAmount > 0 {
-			order.PaymentInfo.Status = shared.PaymentStatusRefunded
-		} else {
-			order.PaymentInfo.Status = shared.PaymentStatusCompleted
-		}
-	}
-
-	if err := s.repo.UpdateOrder(ctx, order); err != nil {
-		logger.Error("failed to cancel order", "error", err, "orderID", orderID)
-		return nil, response.InternalServerError("Failed to cancel order", err)
-	}
+	livemetrics.OrderNotSearching(ctx, order.ID)
 
 	history := models.NewOrderStatusHistory(
 		order.ID,
@@ -802,6 +1349,10 @@ func (s *service) CancelOrder(ctx context.Context, orderID string, req dto.Admin
 		"refundAmount", refundAmount,
 	)
 
+	s.recordAudit(ctx, adminID, "order.cancel", "service_order", order.ID, before, orderAuditSnapshot(order), req.Reason,
+		map[string]interface{}{"cancellationFee": cancellationFee, "refundAmount": refundAmount})
+	s.publishOrderCancelled(ctx, order, previousStatus)
+
 	return s.GetOrderByID(ctx, orderID)
 }
 
@@ -823,6 +1374,11 @@ func (s *service) BulkUpdateStatus(ctx context.Context, req dto.BulkUpdateStatus
 		"affected", affected,
 	)
 
+	for _, orderID := range req.OrderIDs {
+		s.recordAudit(ctx, adminID, "order.bulk_update_status", "service_order", orderID, nil,
+			map[string]interface{}{"status": req.Status}, req.Reason, map[string]interface{}{"batchSize": len(req.OrderIDs)})
+	}
+
 	return affected, nil
 }
 
@@ -835,51 +1391,43 @@ func (s *service) GetOverviewAnalytics(ctx context.Context, query dto.AnalyticsQ
 
 	fromDate, _ := time.Parse("2006-01-02", query.FromDate)
 	toDate, _ := time.Parse("2006-01-02", query.ToDate)
+	filter, previousFilter := analyticsFilters(fromDate, toDate, query.Vertical)
 
-	stats, err := s.repo.GetOrderStats(ctx, fromDate, toDate)
+	stats, err := s.repo.GetOrderStats(ctx, filter)
 	if err != nil {
 		logger.Error("failed to get order stats", "error", err)
 		return nil, response.InternalServerError("Failed to get analytics", err)
 	}
 
-	statusStats, err := s.repo.GetOrdersByStatus(ctx, fromDate, toDate)
+	statusStats, err := s.repo.GetOrdersByStatus(ctx, filter)
 	if err != nil {
 		logger.Error("failed to get status stats", "error", err)
 		return nil, response.InternalServerError("Failed to get analytics", err)
 	}
 
-	categoryStats, err := s.repo.GetOrdersByCategory(ctx, fromDate, toDate)
+	categoryStats, err := s.repo.GetOrdersByCategory(ctx, filter)
 	if err != nil {
 		logger.Error("failed to get category stats", "error", err)
 		return nil, response.InternalServerError("Failed to get analytics", err)
 	}
 
-	revenueBreakdown, err := s.repo.GetRevenueBreakdown(ctx, fromDate, toDate, query.GroupBy)
+	revenueBreakdown, err := s.repo.GetRevenueBreakdown(ctx, filter, query.GroupBy)
 	if err != nil {
 		logger.Error("failed to get revenue breakdown", "error", err)
 		return nil, response.InternalServerError("Failed to get analytics", err)
 	}
 
-	var completionRate, cancellationRate, averageRating, averageOrderValue float64
-
-	if stats.TotalOrders > 0 {
-		completionRate = float64(stats.CompletedOrders) / float64(stats.TotalOrders) * 100
-		cancellationRate = float64(stats.CancelledOrders) / float64(stats.TotalOrders) * 100
-	}
-
+	var averageRating float64
 	if stats.TotalRatings > 0 {
 		averageRating = float64(stats.TotalRatingSum) / float64(stats.TotalRatings)
 	}
 
-	if stats.CompletedOrders > 0 {
-		averageOrderValue = stats.TotalRevenue / float64(stats.CompletedOrders)
-	}
-
 	response := &dto.OverviewAnalyticsResponse{
 		Period: dto.AnalyticsPeriod{
 			FromDate: query.FromDate,
 			ToDate:   query.ToDate,
 			GroupBy:  query.GroupBy,
+			Vertical: query.Vertical,
 		},
 		Summary: dto.AnalyticsSummary{
 			TotalOrders:          int(stats.TotalOrders),
@@ -889,11 +1437,12 @@ func (s *service) GetOverviewAnalytics(ctx context.Context, query dto.AnalyticsQ
 			TotalRevenue:         stats.TotalRevenue,
 			TotalCommission:      stats.TotalCommission,
 			TotalProviderPayouts: stats.TotalProviderPayouts,
-			AverageOrderValue:    averageOrderValue,
-			CompletionRate:       completionRate,
-			CancellationRate:     cancellationRate,
+			AverageOrderValue:    stats.averageOrderValue(),
+			CompletionRate:       stats.completionRate(),
+			CancellationRate:     stats.cancellationRate(),
 			AverageRating:        averageRating,
 		},
+		RevenueBreakdown: toRevenueBreakdown(revenueBreakdown, fromDate, toDate, query.GroupBy),
 	}
 
 	for _, ss := range statusStats {
@@ -915,6 +1464,7 @@ func (s *service) GetOverviewAnalytics(ctx context.Context, query dto.AnalyticsQ
 			percentage = cs.Revenue / stats.TotalRevenue * 100
 		}
 		response.OrdersByCategory = append(response.OrdersByCategory, dto.CategoryCount{
+			Vertical:      cs.Vertical,
 			CategorySlug:  cs.CategorySlug,
 			CategoryTitle: dto.GetCategoryTitle(cs.CategorySlug),
 			OrderCount:    int(cs.OrderCount),
@@ -923,58 +1473,47 @@ func (s *service) GetOverviewAnalytics(ctx context.Context, query dto.AnalyticsQ
 		})
 	}
 
-	for _, rb := range revenueBreakdown {
-		avgValue := 0.0
-		if rb.OrderCount > 0 {
-			avgValue = rb.Revenue / float64(rb.OrderCount)
-		}
-		response.RevenueBreakdown = append(response.RevenueBreakdown, dto.RevenueBreakdown{
-			Period:            rb.Period,
-			OrderCount:        int(rb.OrderCount),
-			Revenue:           rb.Revenue,
-			Commission:        rb.Commission,
-			ProviderPayouts:   rb.ProviderPayouts,
-			AverageOrderValue: avgValue,
-		})
-	}
-
-	periodDuration := toDate.Sub(fromDate)
-	previousFromDate := fromDate.Add(-periodDuration)
-	previousToDate := fromDate.AddDate(0, 0, -1)
-
-	previousStats, _ := s.repo.GetOrderStats(ctx, previousFromDate, previousToDate)
-	if previousStats != nil {
-		response.Trends = s.calculateTrends(stats, previousStats)
-	}
+	response.Trends = s.getTrends(ctx, stats, previousFilter)
 
 	return response, nil
 }
 
-func (s *service) calculateTrends(current, previous *OrderStats) dto.AnalyticsTrends {
-	trends := dto.AnalyticsTrends{}
-
-	trends.OrdersChange = s.calculateTrendChange(
-		float64(current.CompletedOrders),
-		float64(previous.CompletedOrders),
-	)
-
-	trends.RevenueChange = s.calculateTrendChange(
-		current.TotalRevenue,
-		previous.TotalRevenue,
-	)
+// analyticsFilters returns the filter for the inclusive date range fromDate to
+// toDate and the filter for the equally long period that ends the day before
+// it, which trends are compared against.
+func analyticsFilters(fromDate, toDate time.Time, vertical string) (current, previous AnalyticsFilter) {
+	days := int(toDate.Sub(fromDate).Hours()/24) + 1
+	current = AnalyticsFilter{From: fromDate, To: toDate.AddDate(0, 0, 1), Vertical: vertical}
+	previous = AnalyticsFilter{From: fromDate.AddDate(0, 0, -days), To: fromDate, Vertical: vertical}
+	return current, previous
+}
 
-	var currentRate, previousRate float64
-	if current.TotalOrders > 0 {
-		currentRate = float64(current.CompletedOrders) / float64(current.TotalOrders) * 100
-	}
-	if previous.TotalOrders > 0 {
-		previousRate = float64(previous.CompletedOrders) / float64(previous.TotalOrders) * 100
+// getTrends compares stats with the previous period. Trends are secondary to
+// the report, so a failed lookup leaves them empty rather than failing it.
+func (s *service) getTrends(ctx context.Context, current *OrderStats, previousFilter AnalyticsFilter) dto.AnalyticsTrends {
+	previous, err := s.repo.GetOrderStats(ctx, previousFilter)
+	if err != nil {
+		logger.Warn("failed to get previous period stats", "error", err)
+		return dto.AnalyticsTrends{}
 	}
-	trends.CompletionChange = s.calculateTrendChange(currentRate, previousRate)
 
+	trends := s.calculateTrends(current, previous)
+	trends.PreviousFromDate = previousFilter.From.Format("2006-01-02")
+	trends.PreviousToDate = previousFilter.To.AddDate(0, 0, -1).Format("2006-01-02")
 	return trends
 }
 
+func (s *service) calculateTrends(current, previous *OrderStats) dto.AnalyticsTrends {
+	return dto.AnalyticsTrends{
+		OrdersChange:            s.calculateTrendChange(float64(current.CompletedOrders), float64(previous.CompletedOrders)),
+		RevenueChange:           s.calculateTrendChange(current.TotalRevenue, previous.TotalRevenue),
+		CommissionChange:        s.calculateTrendChange(current.TotalCommission, previous.TotalCommission),
+		AverageOrderValueChange: s.calculateTrendChange(current.averageOrderValue(), previous.averageOrderValue()),
+		CompletionChange:        s.calculateTrendChange(current.completionRate(), previous.completionRate()),
+		CancellationChange:      s.calculateTrendChange(current.cancellationRate(), previous.cancellationRate()),
+	}
+}
+
 func (s *service) calculateTrendChange(current, previous float64) dto.TrendChange {
 	change := dto.TrendChange{
 		CurrentValue:  current,
@@ -998,6 +1537,53 @@ func (s *service) calculateTrendChange(current, previous float64) dto.TrendChang
 	return change
 }
 
+// toRevenueBreakdown lists every period between fromDate and toDate, with
+// zeroes for periods without completed orders so charts have no gaps.
+func toRevenueBreakdown(rows []RevenueStats, fromDate, toDate time.Time, groupBy string) []dto.RevenueBreakdown {
+	byPeriod := make(map[string]RevenueStats, len(rows))
+	for _, rb := range rows {
+		byPeriod[rb.Period] = rb
+	}
+
+	var breakdown []dto.RevenueBreakdown
+	previousLabel := ""
+	for day := fromDate; !day.After(toDate); day = day.AddDate(0, 0, 1) {
+		label := periodLabel(day, groupBy)
+		if label == previousLabel {
+			continue
+		}
+		previousLabel = label
+
+		rb := byPeriod[label]
+		avgValue := 0.0
+		if rb.OrderCount > 0 {
+			avgValue = rb.Revenue / float64(rb.OrderCount)
+		}
+		breakdown = append(breakdown, dto.RevenueBreakdown{
+			Period:            label,
+			OrderCount:        int(rb.OrderCount),
+			Revenue:           rb.Revenue,
+			Commission:        rb.Commission,
+			ProviderPayouts:   rb.ProviderPayouts,
+			AverageOrderValue: avgValue,
+		})
+	}
+	return breakdown
+}
+
+// periodLabel formats day the way analyticsPeriodFormats does in SQL.
+func periodLabel(day time.Time, groupBy string) string {
+	switch groupBy {
+	case "week":
+		year, week := day.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	case "month":
+		return day.Format("2006-01")
+	default:
+		return day.Format("2006-01-02")
+	}
+}
+
 func (s *service) GetProviderAnalytics(ctx context.Context, query dto.ProviderAnalyticsQuery) (*dto.ProviderAnalyticsResponse, error) {
 	fromDate, err := time.Parse("2006-01-02", query.FromDate)
 	if err != nil {
@@ -1007,15 +1593,28 @@ func (s *service) GetProviderAnalytics(ctx context.Context, query dto.ProviderAn
 	if err != nil {
 		return nil, response.BadRequest("Invalid toDate format")
 	}
+	if fromDate.After(toDate) {
+		return nil, response.BadRequest("fromDate cannot be after toDate")
+	}
 
 	query.SetDefaults()
+	filter, _ := analyticsFilters(fromDate, toDate, query.Vertical)
 
-	stats, err := s.repo.GetProviderAnalytics(ctx, fromDate, toDate, query)
+	stats, err := s.repo.GetProviderAnalytics(ctx, filter, query)
 	if err != nil {
 		logger.Error("failed to get provider analytics", "error", err)
 		return nil, response.InternalServerError("Failed to get analytics", err)
 	}
 
+	providerIDs := make([]string, len(stats))
+	for i, ps := range stats {
+		providerIDs[i] = ps.ProviderID
+	}
+	identities, err := s.repo.GetProviderIdentities(ctx, providerIDs)
+	if err != nil {
+		logger.Warn("failed to fetch provider details for analytics", "error", err)
+	}
+
 	providers := make([]dto.ProviderAnalyticsItem, len(stats))
 	for i, ps := range stats {
 		var avgRating float64
@@ -1028,15 +1627,19 @@ func (s *service) GetProviderAnalytics(ctx context.Context, query dto.ProviderAn
 		if totalOrders > 0 {
 			completionRate = float64(ps.CompletedOrders) / float64(totalOrders) * 100
 		}
+
 		providerName := "Provider"
 		providerPhoto := ""
-		if provider, err := s.repo.GetUserByID(ctx, ps.ProviderID); err == nil && provider != nil {
-			providerName = provider.Name
-			if provider.ProfilePhotoURL != nil {
-				providerPhoto = *provider.ProfilePhotoURL
+		if identity, ok := identities[ps.ProviderID]; ok {
+			providerName = identity.Name
+			if identity.ProfilePhotoURL != nil {
+				providerPhoto = *identity.ProfilePhotoURL
 			}
-		} else {
-			logger.Warn("failed to fetch provider details for analytics", "error", err, "providerID", ps.ProviderID)
+		}
+
+		categories := []string(ps.Categories)
+		if categories == nil {
+			categories = []string{}
 		}
 
 		providers[i] = dto.ProviderAnalyticsItem{
@@ -1049,6 +1652,7 @@ func (s *service) GetProviderAnalytics(ctx context.Context, query dto.ProviderAn
 			AverageRating:   avgRating,
 			TotalRatings:    int(ps.TotalRatings),
 			CompletionRate:  completionRate,
+			Categories:      categories,
 		}
 	}
 
@@ -1056,6 +1660,7 @@ func (s *service) GetProviderAnalytics(ctx context.Context, query dto.ProviderAn
 		Period: dto.AnalyticsPeriod{
 			FromDate: query.FromDate,
 			ToDate:   query.ToDate,
+			Vertical: query.Vertical,
 		},
 		Providers: providers,
 		Total:     len(providers),
@@ -1071,32 +1676,33 @@ func (s *service) GetRevenueReport(ctx context.Context, query dto.AnalyticsQuery
 
 	fromDate, _ := time.Parse("2006-01-02", query.FromDate)
 	toDate, _ := time.Parse("2006-01-02", query.ToDate)
+	filter, previousFilter := analyticsFilters(fromDate, toDate, query.Vertical)
 
-	stats, err := s.repo.GetOrderStats(ctx, fromDate, toDate)
+	stats, err := s.repo.GetOrderStats(ctx, filter)
 	if err != nil {
 		logger.Error("failed to get order stats", "error", err)
 		return nil, response.InternalServerError("Failed to get revenue report", err)
 	}
 
-	revenueBreakdown, err := s.repo.GetRevenueBreakdown(ctx, fromDate, toDate, query.GroupBy)
+	revenueBreakdown, err := s.repo.GetRevenueBreakdown(ctx, filter, query.GroupBy)
 	if err != nil {
 		logger.Error("failed to get revenue breakdown", "error", err)
 		return nil, response.InternalServerError("Failed to get revenue report", err)
 	}
 
-	categoryStats, err := s.repo.GetOrdersByCategory(ctx, fromDate, toDate)
+	categoryStats, err := s.repo.GetOrdersByCategory(ctx, filter)
 	if err != nil {
 		logger.Error("failed to get category stats", "error", err)
 		return nil, response.InternalServerError("Failed to get revenue report", err)
 	}
 
-	paymentStats, err := s.repo.GetPaymentMethodStats(ctx, fromDate, toDate)
+	paymentStats, err := s.repo.GetPaymentMethodStats(ctx, filter)
 	if err != nil {
 		logger.Error("failed to get payment stats", "error", err)
 		return nil, response.InternalServerError("Failed to get revenue report", err)
 	}
 
-	totalRefunds, err := s.repo.GetTotalRefunds(ctx, fromDate, toDate)
+	totalRefunds, err := s.repo.GetTotalRefunds(ctx, filter)
 	if err != nil {
 		logger.Error("failed to get total refunds", "error", err)
 		totalRefunds = 0
@@ -1107,6 +1713,7 @@ func (s *service) GetRevenueReport(ctx context.Context, query dto.AnalyticsQuery
 			FromDate: query.FromDate,
 			ToDate:   query.ToDate,
 			GroupBy:  query.GroupBy,
+			Vertical: query.Vertical,
 		},
 		TotalRevenue:     stats.TotalRevenue,
 		TotalCommission:  stats.TotalCommission,
@@ -1114,21 +1721,7 @@ func (s *service) GetRevenueReport(ctx context.Context, query dto.AnalyticsQuery
 		TotalRefunds:     totalRefunds,
 		NetRevenue:       stats.TotalCommission - totalRefunds,
 		FormattedRevenue: dto.FormatPrice(stats.TotalRevenue),
-	}
-
-	for _, rb := range revenueBreakdown {
-		avgValue := 0.0
-		if rb.OrderCount > 0 {
-			avgValue = rb.Revenue / float64(rb.OrderCount)
-		}
-		response.Breakdown = append(response.Breakdown, dto.RevenueBreakdown{
-			Period:            rb.Period,
-			OrderCount:        int(rb.OrderCount),
-			Revenue:           rb.Revenue,
-			Commission:        rb.Commission,
-			ProviderPayouts:   rb.ProviderPayouts,
-			AverageOrderValue: avgValue,
-		})
+		Breakdown:        toRevenueBreakdown(revenueBreakdown, fromDate, toDate, query.GroupBy),
 	}
 
 	for _, cs := range categoryStats {
@@ -1136,12 +1729,12 @@ func (s *service) GetRevenueReport(ctx context.Context, query dto.AnalyticsQuery
 		if stats.TotalRevenue > 0 {
 			percentage = cs.Revenue / stats.TotalRevenue * 100
 		}
-		commission := cs.Revenue * shared.PlatformCommissionRate
 		response.ByCategory = append(response.ByCategory, dto.CategoryRevenue{
+			Vertical:      cs.Vertical,
 			CategorySlug:  cs.CategorySlug,
 			CategoryTitle: dto.GetCategoryTitle(cs.CategorySlug),
 			Revenue:       cs.Revenue,
-			Commission:    commission,
+			Commission:    cs.Commission,
 			OrderCount:    int(cs.OrderCount),
 			Percentage:    percentage,
 		})
@@ -1160,6 +1753,8 @@ func (s *service) GetRevenueReport(ctx context.Context, query dto.AnalyticsQuery
 		})
 	}
 
+	response.Trends = s.getTrends(ctx, stats, previousFilter)
+
 	return response, nil
 }
 
@@ -1194,6 +1789,7 @@ func (s *service) GetDashboard(ctx context.Context) (*dto.DashboardResponse, err
 			CompletedOrders:  int(todayData.CompletedOrders),
 			PendingOrders:    int(todayData.PendingOrders),
 			InProgressOrders: int(todayData.InProgressOrders),
+			ExpiredOrders:    int(todayData.ExpiredOrders),
 			Revenue:          todayData.Revenue,
 			Commission:       todayData.Commission,
 		},
@@ -1201,6 +1797,7 @@ func (s *service) GetDashboard(ctx context.Context) (*dto.DashboardResponse, err
 		PendingActions: dto.PendingActions{
 			OrdersNeedingProvider: int(pendingData.OrdersNeedingProvider),
 			ExpiredOrders:         int(pendingData.ExpiredOrders),
+			DisputedOrders:        int(pendingData.DisputedOrders),
 		},
 	}
 
@@ -1212,6 +1809,7 @@ func (s *service) GetDashboard(ctx context.Context) (*dto.DashboardResponse, err
 	dashboard.WeeklyStats = dto.WeeklyStats{
 		TotalOrders:     int(weeklyData.TotalOrders),
 		CompletedOrders: int(weeklyData.CompletedOrders),
+		ExpiredOrders:   int(weeklyData.ExpiredOrders),
 		TotalRevenue:    weeklyData.TotalRevenue,
 		AverageRating:   avgRating,
 	}
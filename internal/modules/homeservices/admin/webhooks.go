@@ -0,0 +1,47 @@
+package admin
+
+import (
+	"context"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+	"github.com/umar5678/go-backend/internal/services/eventbus"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+func (s *service) SetWebhookPublisher(publisher shared.WebhookPublisher) {
+	s.webhooks = publisher
+}
+
+func (s *service) publishOrderCancelled(ctx context.Context, order *models.ServiceOrderNew, previousStatus string) {
+	if s.webhooks == nil {
+		return
+	}
+	if err := s.webhooks.Publish(ctx, models.WebhookEventOrderCancelled, shared.OrderCancelledWebhook(order, previousStatus)); err != nil {
+		logger.Warn("failed to publish order cancelled webhook", "error", err, "orderID", order.ID)
+	}
+}
+
+func (s *service) SetEventPublisher(publisher shared.EventPublisher) {
+	s.events = publisher
+}
+
+func (s *service) publishOrderAssigned(ctx context.Context, order *models.ServiceOrderNew, previousProviderID *string) {
+	if s.events == nil {
+		return
+	}
+	payload := shared.OrderAssignedEvent(order, previousProviderID, shared.RoleAdmin, time.Now())
+	if err := s.events.Publish(ctx, eventbus.OrderAssigned, order.ID, payload); err != nil {
+		logger.Warn("failed to publish order assigned event", "error", err, "orderID", order.ID)
+	}
+}
+
+func (s *service) publishOrderCompleted(ctx context.Context, order *models.ServiceOrderNew) {
+	if s.events == nil {
+		return
+	}
+	if err := s.events.Publish(ctx, eventbus.OrderCompleted, order.ID, shared.OrderCompletedEvent(order)); err != nil {
+		logger.Warn("failed to publish order completed event", "error", err, "orderID", order.ID)
+	}
+}
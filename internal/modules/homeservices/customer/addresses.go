@@ -0,0 +1,54 @@
+package customer
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/customer/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+// AddressBook looks up a customer's saved addresses, so an order can be
+// booked with an address ID instead of the address and coordinates.
+type AddressBook interface {
+	ResolveAddress(ctx context.Context, userID, addressID string) (*models.SavedLocation, error)
+}
+
+// AddressNormalizer rewrites an address to the geocoder's canonical form
+// for its coordinates, keeping the given address when it cannot.
+type AddressNormalizer interface {
+	NormalizeAddress(ctx context.Context, address string, lat, lon float64) string
+}
+
+func (s *service) SetAddressBook(book AddressBook) {
+	s.addressBook = book
+}
+
+func (s *service) SetAddressNormalizer(normalizer AddressNormalizer) {
+	s.addressNormalizer = normalizer
+}
+
+func (s *service) normalizeCustomerAddress(ctx context.Context, info *dto.CustomerInfoRequest) {
+	if s.addressNormalizer == nil {
+		return
+	}
+	info.Address = s.addressNormalizer.NormalizeAddress(ctx, info.Address, info.Lat, info.Lng)
+}
+
+// resolveCustomerAddress fills the service address from the saved address
+// the request references.
+func (s *service) resolveCustomerAddress(ctx context.Context, customerID string, info *dto.CustomerInfoRequest) error {
+	if info.AddressID == nil {
+		return nil
+	}
+	if s.addressBook == nil {
+		return response.BadRequest("Saved addresses are not available, send the address instead")
+	}
+
+	address, err := s.addressBook.ResolveAddress(ctx, customerID, *info.AddressID)
+	if err != nil {
+		return err
+	}
+	info.Address, info.Lat, info.Lng = address.Address, address.Latitude, address.Longitude
+	return nil
+}
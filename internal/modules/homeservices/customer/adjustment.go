@@ -0,0 +1,213 @@
+package customer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/customer/dto"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+	walletdto "github.com/umar5678/go-backend/internal/modules/wallet/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+	"gorm.io/gorm"
+)
+
+func (s *service) GetOrderAdjustments(ctx context.Context, customerID, orderID string) (*shared.OrderAdjustmentsResponse, error) {
+	order, err := s.loadCustomerOrder(ctx, customerID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	adjustments, err := s.repo.GetOrderAdjustments(ctx, order.ID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get adjustments", err)
+	}
+	return shared.ToOrderAdjustmentsResponse(order, adjustments), nil
+}
+
+// ApproveAdjustment agrees to the scope the provider proposed. The order is
+// repriced, the payment follows the new total and the provider's payout on
+// completion is worked out from it.
+func (s *service) ApproveAdjustment(ctx context.Context, customerID, orderID, adjustmentID string, req dto.RespondAdjustmentRequest) (*shared.AdjustmentResponse, error) {
+	order, adjustment, err := s.loadPendingAdjustment(ctx, customerID, orderID, adjustmentID)
+	if err != nil {
+		return nil, err
+	}
+	if err := shared.ValidateAdjustable(order); err != nil {
+		return nil, err
+	}
+
+	surcharges, err := s.repo.GetActiveSurcharges(ctx, order.CategorySlug)
+	if err != nil {
+		logger.Error("failed to load category surcharges", "error", err, "category", order.CategorySlug)
+		return nil, response.InternalServerError("Failed to price adjustment", err)
+	}
+
+	previousTotal := order.TotalPrice
+	if err := shared.RepriceOrder(order, adjustment.Lines, surcharges); err != nil {
+		return nil, err
+	}
+	if order.PaymentInfo != nil {
+		order.PaymentInfo.Total = order.TotalPrice
+	}
+
+	now := time.Now()
+	adjustment.PreviousTotal = previousTotal
+	adjustment.NewTotal = order.TotalPrice
+	adjustment.Difference = shared.RoundToTwoDecimals(order.TotalPrice - previousTotal)
+	adjustment.Status = models.AdjustmentStatusApproved
+	adjustment.CustomerNote = req.Note
+	adjustment.RespondedAt = &now
+
+	complete, revert, err := s.chargeAdjustment(ctx, order, adjustment)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, order); err != nil {
+		revert()
+		logger.Error("failed to apply scope adjustment", "error", err, "orderID", order.ID)
+		return nil, shared.OrderUpdateError(err, "Failed to apply adjustment")
+	}
+	complete()
+
+	if err := s.repo.UpdateAdjustment(ctx, adjustment); err != nil {
+		logger.Error("failed to update scope adjustment", "error", err, "adjustmentID", adjustment.ID)
+	}
+
+	s.repo.CreateStatusHistory(ctx, shared.AdjustmentHistory(order, adjustment, customerID, shared.RoleCustomer))
+
+	s.notifyProvider(ctx, order, websocket.TypeOrderAdjustmentApproved, shared.AdjustmentNotification(order, adjustment))
+
+	logger.Info("order scope adjustment approved", "orderID", order.ID, "adjustmentID", adjustment.ID,
+		"previousTotal", adjustment.PreviousTotal, "newTotal", adjustment.NewTotal)
+
+	return shared.ToAdjustmentResponse(adjustment), nil
+}
+
+// RejectAdjustment turns down the provider's proposal; the order keeps its
+// scope and price.
+func (s *service) RejectAdjustment(ctx context.Context, customerID, orderID, adjustmentID string, req dto.RespondAdjustmentRequest) (*shared.AdjustmentResponse, error) {
+	order, adjustment, err := s.loadPendingAdjustment(ctx, customerID, orderID, adjustmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	adjustment.Status = models.AdjustmentStatusRejected
+	adjustment.CustomerNote = req.Note
+	adjustment.RespondedAt = &now
+	if err := s.repo.UpdateAdjustment(ctx, adjustment); err != nil {
+		return nil, response.InternalServerError("Failed to reject adjustment", err)
+	}
+
+	s.notifyProvider(ctx, order, websocket.TypeOrderAdjustmentRejected, shared.AdjustmentNotification(order, adjustment))
+
+	logger.Info("order scope adjustment rejected", "orderID", order.ID, "adjustmentID", adjustment.ID)
+
+	return shared.ToAdjustmentResponse(adjustment), nil
+}
+
+func (s *service) loadPendingAdjustment(ctx context.Context, customerID, orderID, adjustmentID string) (*models.ServiceOrderNew, *models.OrderAdjustment, error) {
+	order, err := s.loadCustomerOrder(ctx, customerID, orderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	adjustment, err := s.repo.GetOrderAdjustment(ctx, order.ID, adjustmentID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, response.NotFoundError("Adjustment")
+		}
+		return nil, nil, response.InternalServerError("Failed to get adjustment", err)
+	}
+	if adjustment.Status != models.AdjustmentStatusPending {
+		return nil, nil, response.BadRequest("This adjustment has already been answered")
+	}
+	return order, adjustment, nil
+}
+
+// chargeAdjustment moves a wallet payment to the adjusted total. While the
+// hold is still active a new one is placed for the new total; once it has
+// been captured the difference is charged or refunded. complete finishes the
+// move after the order is saved and revert undoes it if the save fails.
+func (s *service) chargeAdjustment(ctx context.Context, order *models.ServiceOrderNew, adjustment *models.OrderAdjustment) (complete, revert func(), err error) {
+	complete, revert = func() {}, func() {}
+	if order.WalletHoldID == nil || adjustment.Difference == 0 {
+		return complete, revert, nil
+	}
+	customerID := order.CustomerID
+	metadata := map[string]interface{}{
+		"order_id":      order.ID,
+		"order_number":  order.OrderNumber,
+		"adjustment_id": adjustment.ID,
+	}
+	description := fmt.Sprintf("Scope adjustment for order %s", order.OrderNumber)
+
+	if hold := s.activeHold(ctx, customerID, *order.WalletHoldID); hold != nil {
+		newHold, err := s.walletService.HoldFunds(ctx, customerID, walletdto.HoldFundsRequest{
+			Amount:        order.TotalPrice,
+			Currency:      order.Currency,
+			ReferenceType: "service_order",
+			ReferenceID:   order.ID,
+		})
+		if err != nil {
+			logger.Warn("failed to hold adjusted order total", "error", err, "orderID", order.ID, "amount", order.TotalPrice)
+			return nil, nil, err
+		}
+		if err := s.walletService.ExtendHold(ctx, customerID, newHold.ID, hold.ExpiresAt); err != nil {
+			logger.Warn("failed to extend adjusted order hold", "error", err, "orderID", order.ID, "holdID", newHold.ID)
+		}
+
+		oldHoldID := hold.ID
+		order.WalletHoldID = &newHold.ID
+		complete = func() {
+			if err := s.walletService.ReleaseHold(ctx, customerID, walletdto.ReleaseHoldRequest{HoldID: oldHoldID}); err != nil {
+				logger.Error("failed to release replaced order hold", "error", err, "orderID", order.ID, "holdID", oldHoldID)
+			}
+		}
+		revert = func() {
+			order.WalletHoldID = &oldHoldID
+			if err := s.walletService.ReleaseHold(ctx, customerID, walletdto.ReleaseHoldRequest{HoldID: newHold.ID}); err != nil {
+				logger.Error("failed to release adjusted order hold", "error", err, "orderID", order.ID, "holdID", newHold.ID)
+			}
+		}
+		return complete, revert, nil
+	}
+
+	if adjustment.Difference > 0 {
+		if _, err := s.walletService.DebitWallet(ctx, customerID, adjustment.Difference, "service_order", order.ID, description, metadata); err != nil {
+			logger.Warn("failed to charge order adjustment", "error", err, "orderID", order.ID, "amount", adjustment.Difference)
+			return nil, nil, err
+		}
+		revert = func() {
+			if _, err := s.walletService.CreditWallet(ctx, customerID, adjustment.Difference, "service_order", order.ID, "Reversal: "+description, metadata); err != nil {
+				logger.Error("failed to reverse order adjustment charge", "error", err, "orderID", order.ID, "amount", adjustment.Difference)
+			}
+		}
+		return complete, revert, nil
+	}
+
+	refund := -adjustment.Difference
+	complete = func() {
+		if _, err := s.walletService.CreditWallet(ctx, customerID, refund, "service_order", order.ID, description, metadata); err != nil {
+			logger.Error("failed to refund order adjustment", "error", err, "orderID", order.ID, "amount", refund)
+		}
+	}
+	return complete, revert, nil
+}
+
+// activeHold returns the hold if it still reserves funds, or nil once it has
+// been captured, released or has expired.
+func (s *service) activeHold(ctx context.Context, customerID, holdID string) *walletdto.ActiveHoldResponse {
+	holds, err := s.walletService.GetActiveHolds(ctx, customerID)
+	if err != nil {
+		return nil
+	}
+	for _, hold := range holds.Holds {
+		if hold.ID == holdID {
+			return hold
+		}
+	}
+	return nil
+}
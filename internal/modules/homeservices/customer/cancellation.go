@@ -0,0 +1,54 @@
+package customer
+
+import (
+	"context"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/cancellation"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+)
+
+// CancellationPolicies prices cancellations. Without one the built-in default
+// policy applies.
+type CancellationPolicies interface {
+	Evaluate(ctx context.Context, c cancellation.Cancellation) *cancellation.Outcome
+}
+
+func (s *service) SetCancellationPolicies(policies CancellationPolicies) {
+	s.cancellationPolicies = policies
+}
+
+// cancellationCharge prices the customer cancelling the order now and returns
+// the fee, what is left to refund, and the outcome it came from.
+func (s *service) cancellationCharge(ctx context.Context, order *models.ServiceOrderNew) (float64, float64, *cancellation.Outcome) {
+	awaitingProvider := (order.Status == shared.OrderStatusAssigned || order.Status == shared.OrderStatusAccepted) &&
+		order.ProviderArrivedAt == nil && order.ProviderStartedAt == nil
+
+	c := cancellation.Cancellation{
+		Product:         models.CancellationProductHomeServices,
+		CategorySlug:    order.CategorySlug,
+		Lat:             order.CustomerInfo.Lat,
+		Lon:             order.CustomerInfo.Lng,
+		Status:          order.Status,
+		Total:           order.TotalPrice,
+		ByCustomer:      true,
+		RequestedAt:     order.CreatedAt,
+		AcceptedAt:      order.ProviderAcceptedAt,
+		AwaitingArrival: awaitingProvider,
+		At:              time.Now(),
+	}
+	if !order.BookingInfo.PreferredTime.IsZero() {
+		c.ScheduledAt = &order.BookingInfo.PreferredTime
+	}
+
+	var outcome *cancellation.Outcome
+	if s.cancellationPolicies == nil {
+		outcome = cancellation.ApplyDefault(c)
+	} else {
+		outcome = s.cancellationPolicies.Evaluate(ctx, c)
+	}
+
+	fee := outcome.CustomerFee
+	return fee, shared.RoundToTwoDecimals(order.TotalPrice - fee), outcome
+}
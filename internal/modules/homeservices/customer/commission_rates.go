@@ -0,0 +1,27 @@
+package customer
+
+import (
+	"context"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+)
+
+// CommissionRates resolves the platform's cut of an order, in percent. It is
+// optional; without one every order uses shared.DefaultCommissionRate.
+type CommissionRates interface {
+	ServiceRate(ctx context.Context, categorySlug string, lat, lng float64, at time.Time) float64
+}
+
+func (s *service) SetCommissionRates(commissionRates CommissionRates) {
+	s.commissionRates = commissionRates
+}
+
+// commissionRate is the rate in force now for an order in the category at
+// the given address. Orders keep it for their lifetime.
+func (s *service) commissionRate(ctx context.Context, categorySlug string, lat, lng float64) float64 {
+	if s.commissionRates == nil {
+		return shared.DefaultCommissionRate
+	}
+	return s.commissionRates.ServiceRate(ctx, categorySlug, lat, lng, time.Now())
+}
@@ -0,0 +1,20 @@
+package customer
+
+import "context"
+
+// CurrencyResolver names the currency orders at a service address are charged
+// in. It is optional; without one orders are taken in the wallet's currency.
+type CurrencyResolver interface {
+	ForLocation(ctx context.Context, lat, lon float64) string
+}
+
+func (s *service) SetCurrencies(currencies CurrencyResolver) {
+	s.currencies = currencies
+}
+
+func (s *service) currencyAt(ctx context.Context, lat, lng float64) string {
+	if s.currencies == nil {
+		return ""
+	}
+	return s.currencies.ForLocation(ctx, lat, lng)
+}
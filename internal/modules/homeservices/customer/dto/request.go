@@ -46,24 +46,54 @@ func (q *ListAddonsQuery) SetDefaults() {
 
 type SearchQuery struct {
 	shared.PaginationParams
-	Query        string `form:"q" binding:"required,min=2,max=100"`
-	CategorySlug string `form:"category"`
+	Query        string   `form:"q" binding:"required,min=2,max=100"`
+	CategorySlug string   `form:"category"`
+	Type         string   `form:"type" binding:"omitempty,oneof=service addon"`
+	MinPrice     *float64 `form:"minPrice" binding:"omitempty,gte=0"`
+	MaxPrice     *float64 `form:"maxPrice" binding:"omitempty,gte=0"`
+	MinDuration  *int     `form:"minDuration" binding:"omitempty,gte=0"`
+	MaxDuration  *int     `form:"maxDuration" binding:"omitempty,gte=0"`
 }
 
 func (q *SearchQuery) SetDefaults() {
 	q.PaginationParams.SetDefaults()
 }
 
+func (q *SearchQuery) Validate() error {
+	if q.MinPrice != nil && q.MaxPrice != nil && *q.MinPrice > *q.MaxPrice {
+		return fmt.Errorf("minPrice must not exceed maxPrice")
+	}
+	if q.MinDuration != nil && q.MaxDuration != nil && *q.MinDuration > *q.MaxDuration {
+		return fmt.Errorf("minDuration must not exceed maxDuration")
+	}
+	return nil
+}
+
+// FiltersDuration reports whether a duration bound is set. Add-ons have no
+// duration, so such searches only return services.
+func (q *SearchQuery) FiltersDuration() bool {
+	return q.MinDuration != nil || q.MaxDuration != nil
+}
+
+// CustomerInfoRequest takes either the service address with its coordinates
+// or the ID of one of the customer's saved addresses.
 type CustomerInfoRequest struct {
-	Name    string  `json:"name" binding:"required,min=2,max=100"`
-	Phone   string  `json:"phone" binding:"required"`
-	Email   string  `json:"email" binding:"omitempty,email"`
-	Address string  `json:"address" binding:"required,min=10,max=500"`
-	Lat     float64 `json:"lat" binding:"required,latitude"`
-	Lng     float64 `json:"lng" binding:"required,longitude"`
+	Name      string  `json:"name" binding:"required,min=2,max=100"`
+	Phone     string  `json:"phone" binding:"required"`
+	Email     string  `json:"email" binding:"omitempty,email"`
+	Address   string  `json:"address" binding:"omitempty,min=10,max=500"`
+	Lat       float64 `json:"lat" binding:"omitempty,latitude"`
+	Lng       float64 `json:"lng" binding:"omitempty,longitude"`
+	AddressID *string `json:"addressId" binding:"omitempty,uuid"`
 }
 
 func (c *CustomerInfoRequest) Validate() error {
+	if c.Address == "" {
+		return fmt.Errorf("address is required")
+	}
+	if c.Lat == 0 && c.Lng == 0 {
+		return fmt.Errorf("address location is required")
+	}
 	phoneRegex := regexp.MustCompile(`^\+?[0-9]{10,15}$`)
 	cleanPhone := regexp.MustCompile(`[\s\-\(\)]+`).ReplaceAllString(c.Phone, "")
 	if !phoneRegex.MatchString(cleanPhone) {
@@ -129,6 +159,118 @@ type CreateOrderRequest struct {
 	SelectedAddons   []SelectedAddonRequest   `json:"selectedAddons" binding:"omitempty,dive"`
 	SpecialNotes     string                   `json:"specialNotes" binding:"omitempty,max=1000"`
 	PaymentMethod    string                   `json:"paymentMethod" binding:"required,oneof=wallet cash"`
+	QuoteID          *string                  `json:"quoteId" binding:"omitempty,uuid"`
+
+	// Sessions turns the booking into a multi-day project. When set, the first
+	// session must match bookingInfo and each later session runs on a later day.
+	Sessions []SessionScheduleRequest `json:"sessions" binding:"omitempty,dive"`
+
+	// Recurrence repeats the booking from bookingInfo.date on a schedule.
+	Recurrence *RecurrenceRequest `json:"recurrence" binding:"omitempty"`
+}
+
+// RecurrenceRequest ends the series at EndDate or after Occurrences bookings,
+// whichever comes first; with neither it runs until cancelled.
+type RecurrenceRequest struct {
+	Frequency   string  `json:"frequency" binding:"required,oneof=weekly biweekly monthly"`
+	EndDate     *string `json:"endDate" binding:"omitempty" example:"2024-12-31"`
+	Occurrences *int    `json:"occurrences" binding:"omitempty,min=2,max=104"`
+}
+
+func (r *CreateOrderRequest) validateRecurrence() error {
+	if r.Recurrence == nil {
+		return nil
+	}
+	if len(r.Sessions) > 0 {
+		return fmt.Errorf("multi-day orders cannot repeat")
+	}
+	if r.Recurrence.EndDate != nil {
+		end, err := time.Parse("2006-01-02", *r.Recurrence.EndDate)
+		if err != nil {
+			return fmt.Errorf("invalid endDate format, expected YYYY-MM-DD")
+		}
+		if start, _ := time.Parse("2006-01-02", r.BookingInfo.Date); !end.After(start) {
+			return fmt.Errorf("endDate must be after the first booking")
+		}
+	}
+	return nil
+}
+
+type SkipOccurrenceRequest struct {
+	Date string `json:"date" binding:"required" example:"2024-06-17"`
+}
+
+type SessionScheduleRequest struct {
+	Date             string  `json:"date" binding:"required" example:"2024-06-10"`
+	Time             string  `json:"time" binding:"required" example:"09:00"`
+	Hours            float64 `json:"hours" binding:"required,min=0.5,max=12" example:"6"`
+	MilestonePercent float64 `json:"milestonePercent" binding:"omitempty,gt=0,lte=100" example:"40"`
+}
+
+func (r *CreateOrderRequest) validateSessions() error {
+	if len(r.Sessions) == 0 {
+		return nil
+	}
+	if len(r.Sessions) < shared.MinOrderSessions || len(r.Sessions) > shared.MaxOrderSessions {
+		return fmt.Errorf("multi-day orders need between %d and %d sessions", shared.MinOrderSessions, shared.MaxOrderSessions)
+	}
+
+	first := r.Sessions[0]
+	if first.Date != r.BookingInfo.Date || first.Time != r.BookingInfo.Time {
+		return fmt.Errorf("the first session must match the booking date and time")
+	}
+
+	var prevDate time.Time
+	var percentTotal float64
+	withPercent := 0
+	for i, session := range r.Sessions {
+		date, err := time.Parse("2006-01-02", session.Date)
+		if err != nil {
+			return fmt.Errorf("session %d: invalid date format, expected YYYY-MM-DD", i+1)
+		}
+		if _, err := time.Parse("15:04", session.Time); err != nil {
+			return fmt.Errorf("session %d: invalid time format, expected HH:MM", i+1)
+		}
+		if i > 0 && !date.After(prevDate) {
+			return fmt.Errorf("session %d must be scheduled on a later day than session %d", i+1, i)
+		}
+		if date.After(time.Now().AddDate(0, 0, 120)) {
+			return fmt.Errorf("session %d cannot be more than 120 days in the future", i+1)
+		}
+		if session.Hours != float64(int(session.Hours*2))/2 {
+			return fmt.Errorf("session %d: hours must be in 0.5 hour increments", i+1)
+		}
+		prevDate = date
+
+		if session.MilestonePercent > 0 {
+			withPercent++
+			percentTotal += session.MilestonePercent
+		}
+	}
+
+	if withPercent > 0 {
+		if withPercent != len(r.Sessions) {
+			return fmt.Errorf("milestonePercent must be set on every session or on none")
+		}
+		if percentTotal < 99.99 || percentTotal > 100.01 {
+			return fmt.Errorf("session milestone percentages must add up to 100")
+		}
+	}
+	return nil
+}
+
+// SessionHoursAndPercents returns the per-session hours and, when the
+// customer set them, the milestone percentages.
+func (r *CreateOrderRequest) SessionHoursAndPercents() ([]float64, []float64) {
+	hours := make([]float64, len(r.Sessions))
+	var percents []float64
+	for i, session := range r.Sessions {
+		hours[i] = session.Hours
+		if session.MilestonePercent > 0 {
+			percents = append(percents, session.MilestonePercent)
+		}
+	}
+	return hours, percents
 }
 
 func (r *CreateOrderRequest) Validate() error {
@@ -144,6 +286,14 @@ func (r *CreateOrderRequest) Validate() error {
 		return fmt.Errorf("at least one service must be selected")
 	}
 
+	if err := r.validateSessions(); err != nil {
+		return fmt.Errorf("sessions: %w", err)
+	}
+
+	if err := r.validateRecurrence(); err != nil {
+		return fmt.Errorf("recurrence: %w", err)
+	}
+
 	serviceMap := make(map[string]bool)
 	for _, s := range r.SelectedServices {
 		if serviceMap[s.ServiceSlug] {
@@ -165,6 +315,40 @@ func (r *CreateOrderRequest) Validate() error {
 	return nil
 }
 
+// PreviewOrderRequest prices a cart and checks addon compatibility without
+// creating an order.
+type PreviewOrderRequest struct {
+	CategorySlug     string                   `json:"categorySlug" binding:"required"`
+	SelectedServices []SelectedServiceRequest `json:"selectedServices" binding:"required,min=1,dive"`
+	SelectedAddons   []SelectedAddonRequest   `json:"selectedAddons" binding:"omitempty,dive"`
+	Lat              *float64                 `json:"lat" binding:"omitempty,latitude"`
+	Lng              *float64                 `json:"lng" binding:"omitempty,longitude"`
+}
+
+func (r *PreviewOrderRequest) Validate() error {
+	if len(r.SelectedServices) == 0 {
+		return fmt.Errorf("at least one service must be selected")
+	}
+
+	serviceMap := make(map[string]bool)
+	for _, s := range r.SelectedServices {
+		if serviceMap[s.ServiceSlug] {
+			return fmt.Errorf("duplicate service: %s", s.ServiceSlug)
+		}
+		serviceMap[s.ServiceSlug] = true
+	}
+
+	addonMap := make(map[string]bool)
+	for _, a := range r.SelectedAddons {
+		if addonMap[a.AddonSlug] {
+			return fmt.Errorf("duplicate addon: %s", a.AddonSlug)
+		}
+		addonMap[a.AddonSlug] = true
+	}
+
+	return nil
+}
+
 type CancelOrderRequest struct {
 	Reason string `json:"reason" binding:"required,min=10,max=500"`
 }
@@ -176,6 +360,47 @@ func (r *CancelOrderRequest) Validate() error {
 	return nil
 }
 
+// ReportNoShowRequest is the evidence a customer sends when the provider did
+// not turn up: a note, photos and where the customer is.
+type ReportNoShowRequest struct {
+	Note      string   `json:"note" binding:"omitempty,max=1000"`
+	PhotoURLs []string `json:"photoUrls" binding:"omitempty,max=5,dive,url"`
+	Latitude  *float64 `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" binding:"omitempty,min=-180,max=180"`
+}
+
+func (r *ReportNoShowRequest) Validate() error {
+	if (r.Latitude == nil) != (r.Longitude == nil) {
+		return fmt.Errorf("latitude and longitude must be sent together")
+	}
+	if r.Note == "" && len(r.PhotoURLs) == 0 && r.Latitude == nil {
+		return fmt.Errorf("a note, a photo or the current location is required as evidence")
+	}
+	return nil
+}
+
+type ApproveSessionRequest struct {
+	Feedback string `json:"feedback" binding:"omitempty,max=1000"`
+}
+
+type RequestSessionChangesRequest struct {
+	Reason string `json:"reason" binding:"required,min=5,max=1000"`
+}
+
+type RescheduleOrderRequest struct {
+	Date   string `json:"date" binding:"required" example:"2024-06-12"`
+	Time   string `json:"time" binding:"required" example:"14:00"`
+	Reason string `json:"reason" binding:"omitempty,max=500"`
+}
+
+type RespondRescheduleRequest struct {
+	Note string `json:"note" binding:"omitempty,max=500"`
+}
+
+type RespondAdjustmentRequest struct {
+	Note string `json:"note" binding:"omitempty,max=500"`
+}
+
 type RateOrderRequest struct {
 	Rating int    `json:"rating" binding:"required,min=1,max=5"`
 	Review string `json:"review" binding:"omitempty,max=1000"`
@@ -222,3 +447,12 @@ func (q *ListOrdersQuery) Validate() error {
 
 	return nil
 }
+
+type ListQuotesQuery struct {
+	shared.PaginationParams
+	Status string `form:"status" binding:"omitempty,oneof=issued converted"`
+}
+
+func (q *ListQuotesQuery) SetDefaults() {
+	q.PaginationParams.SetDefaults()
+}
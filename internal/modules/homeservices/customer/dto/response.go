@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/umar5678/go-backend/internal/models"
+	pricingdto "github.com/umar5678/go-backend/internal/modules/pricing/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
 )
 
 type CategoryResponse struct {
@@ -102,21 +104,24 @@ type AddonListResponse struct {
 }
 
 type SearchResultItem struct {
-	Type           string   `json:"type"` 
-	ID             string   `json:"id"`
-	Title          string   `json:"title"`
-	Slug           string   `json:"slug"`
-	CategorySlug   string   `json:"categorySlug"`
-	Description    string   `json:"description"`
-	Image          string   `json:"image"`
-	Price          *float64 `json:"price,omitempty"`
-	FormattedPrice string   `json:"formattedPrice,omitempty"`
+	Type              string   `json:"type"`
+	ID                string   `json:"id"`
+	Title             string   `json:"title"`
+	Slug              string   `json:"slug"`
+	CategorySlug      string   `json:"categorySlug"`
+	Description       string   `json:"description"`
+	Image             string   `json:"image"`
+	Price             *float64 `json:"price,omitempty"`
+	FormattedPrice    string   `json:"formattedPrice,omitempty"`
+	Duration          *int     `json:"duration,omitempty"`
+	FormattedDuration string   `json:"formattedDuration,omitempty"`
 }
 
 type SearchResponse struct {
-	Query   string             `json:"query"`
-	Results []SearchResultItem `json:"results"`
-	Total   int                `json:"total"`
+	Query      string                  `json:"query"`
+	Results    []SearchResultItem      `json:"results"`
+	Total      int64                   `json:"total"`
+	Pagination response.PaginationMeta `json:"pagination"`
 }
 
 func FormatDuration(minutes *int) string {
@@ -281,35 +286,6 @@ func ToAddonResponses(addons []*models.Addon) []AddonResponse {
 	return responses
 }
 
-func ToSearchResultFromService(service *models.ServiceNew) SearchResultItem {
-	return SearchResultItem{
-		Type:           "service",
-		ID:             service.ID,
-		Title:          service.Title,
-		Slug:           service.ServiceSlug,
-		CategorySlug:   service.CategorySlug,
-		Description:    service.Description,
-		Image:          service.Thumbnail,
-		Price:          service.BasePrice,
-		FormattedPrice: FormatPrice(service.BasePrice),
-	}
-}
-
-func ToSearchResultFromAddon(addon *models.Addon) SearchResultItem {
-	price := addon.Price
-	return SearchResultItem{
-		Type:           "addon",
-		ID:             addon.ID,
-		Title:          addon.Title,
-		Slug:           addon.AddonSlug,
-		CategorySlug:   addon.CategorySlug,
-		Description:    addon.Description,
-		Image:          addon.Image,
-		Price:          &price,
-		FormattedPrice: FormatPriceValue(addon.Price),
-	}
-}
-
 type OrderServiceItem struct {
 	ServiceSlug string  `json:"serviceSlug"`
 	Title       string  `json:"title"`
@@ -345,15 +321,21 @@ type OrderBookingInfo struct {
 	QuantityOfPros int    `json:"quantityOfPros"`
 	ToolsRequired  bool   `json:"toolsRequired"`
 	PersonCount    int    `json:"personCount"`
+	Frequency      string `json:"frequency,omitempty"`
 }
 
 type OrderPricing struct {
-	ServicesTotal      float64 `json:"servicesTotal"`
-	AddonsTotal        float64 `json:"addonsTotal"`
-	Subtotal           float64 `json:"subtotal"`
-	PlatformCommission float64 `json:"platformCommission"`
-	TotalPrice         float64 `json:"totalPrice"`
-	FormattedTotal     string  `json:"formattedTotal"`
+	ServicesTotal      float64                     `json:"servicesTotal"`
+	AddonsTotal        float64                     `json:"addonsTotal"`
+	Subtotal           float64                     `json:"subtotal"`
+	Surcharges         []models.OrderSurchargeItem `json:"surcharges"`
+	SurchargesTotal    float64                     `json:"surchargesTotal"`
+	Taxes              []pricingdto.TaxLine        `json:"taxes"`
+	TaxTotal           float64                     `json:"taxTotal"`
+	PlatformCommission float64                     `json:"platformCommission"`
+	TotalPrice         float64                     `json:"totalPrice"`
+	Currency           string                      `json:"currency,omitempty"`
+	FormattedTotal     string                      `json:"formattedTotal"`
 }
 
 type OrderPaymentInfo struct {
@@ -394,6 +376,7 @@ type OrderStatusInfo struct {
 	Current            string     `json:"current"`
 	DisplayStatus      string     `json:"displayStatus"`
 	ProviderAcceptedAt *time.Time `json:"providerAcceptedAt,omitempty"`
+	ProviderArrivedAt  *time.Time `json:"providerArrivedAt,omitempty"`
 	ProviderStartedAt  *time.Time `json:"providerStartedAt,omitempty"`
 	CompletedAt        *time.Time `json:"completedAt,omitempty"`
 	CanCancel          bool       `json:"canCancel"`
@@ -415,10 +398,17 @@ type OrderResponse struct {
 	Status        OrderStatusInfo        `json:"status"`
 	Cancellation  *OrderCancellationInfo `json:"cancellation,omitempty"`
 	Rating        *OrderRatingInfo       `json:"rating,omitempty"`
+	MultiSession  *OrderMultiSessionInfo `json:"multiSession,omitempty"`
+	RecurringPlan *string                `json:"recurringPlanId,omitempty"`
 	CreatedAt     time.Time              `json:"createdAt"`
 	UpdatedAt     time.Time              `json:"updatedAt"`
 }
 
+type OrderMultiSessionInfo struct {
+	SessionCount    int `json:"sessionCount"`
+	ProgressPercent int `json:"progressPercent"`
+}
+
 type OrderListResponse struct {
 	ID             string           `json:"id"`
 	OrderNumber    string           `json:"orderNumber"`
@@ -426,12 +416,14 @@ type OrderListResponse struct {
 	CategoryTitle  string           `json:"categoryTitle"`
 	BookingInfo    OrderBookingInfo `json:"bookingInfo"`
 	TotalPrice     float64          `json:"totalPrice"`
+	Currency       string           `json:"currency,omitempty"`
 	FormattedTotal string           `json:"formattedTotal"`
 	Status         string           `json:"status"`
 	DisplayStatus  string           `json:"displayStatus"`
 	ServiceCount   int              `json:"serviceCount"`
 	CanCancel      bool             `json:"canCancel"`
 	CanRate        bool             `json:"canRate"`
+	RecurringPlan  *string          `json:"recurringPlanId,omitempty"`
 	CreatedAt      time.Time        `json:"createdAt"`
 }
 
@@ -442,9 +434,29 @@ type OrderCreatedResponse struct {
 	DisplayStatus           string           `json:"displayStatus"`
 	BookingInfo             OrderBookingInfo `json:"bookingInfo"`
 	TotalPrice              float64          `json:"totalPrice"`
+	Currency                string           `json:"currency,omitempty"`
 	FormattedTotal          string           `json:"formattedTotal"`
 	EstimatedAssignmentTime string           `json:"estimatedAssignmentTime"`
 	Message                 string           `json:"message"`
+
+	RecurringPlan *RecurringPlanResponse `json:"recurringPlan,omitempty"`
+}
+
+type OrderPreviewResponse struct {
+	CategorySlug     string                       `json:"categorySlug"`
+	SelectedServices []models.SelectedServiceItem `json:"selectedServices"`
+	SelectedAddons   []models.SelectedAddonItem   `json:"selectedAddons"`
+	ServicesTotal    float64                      `json:"servicesTotal"`
+	AddonsTotal      float64                      `json:"addonsTotal"`
+	Subtotal         float64                      `json:"subtotal"`
+	Surcharges       []models.OrderSurchargeItem  `json:"surcharges"`
+	SurchargesTotal  float64                      `json:"surchargesTotal"`
+	Taxes            []pricingdto.TaxLine         `json:"taxes"`
+	TaxTotal         float64                      `json:"taxTotal"`
+	TaxIncluded      bool                         `json:"taxIncluded"`
+	TotalPrice       float64                      `json:"totalPrice"`
+	Currency         string                       `json:"currency,omitempty"`
+	FormattedTotal   string                       `json:"formattedTotal"`
 }
 
 type CancellationPreviewResponse struct {
@@ -458,6 +470,40 @@ type CancellationPreviewResponse struct {
 	Message         string  `json:"message"`
 }
 
+type NoShowReportResponse struct {
+	ID              string    `json:"id"`
+	OrderID         string    `json:"orderId"`
+	AbsentParty     string    `json:"absentParty"`
+	Note            string    `json:"note,omitempty"`
+	PhotoURLs       []string  `json:"photoUrls"`
+	DistanceMeters  *float64  `json:"distanceMeters,omitempty"`
+	WaitedMinutes   int       `json:"waitedMinutes"`
+	RefundAmount    float64   `json:"refundAmount"`
+	ProviderPenalty float64   `json:"providerPenalty"`
+	PolicyName      string    `json:"policyName,omitempty"`
+	CreatedAt       time.Time `json:"createdAt"`
+}
+
+func ToNoShowReportResponse(report *models.NoShowReport) *NoShowReportResponse {
+	photos := []string(report.PhotoURLs)
+	if photos == nil {
+		photos = []string{}
+	}
+	return &NoShowReportResponse{
+		ID:              report.ID,
+		OrderID:         report.ReferenceID,
+		AbsentParty:     report.AbsentParty,
+		Note:            report.Note,
+		PhotoURLs:       photos,
+		DistanceMeters:  report.DistanceMeters,
+		WaitedMinutes:   report.WaitedMinutes,
+		RefundAmount:    report.RefundAmount,
+		ProviderPenalty: report.ProviderPenalty,
+		PolicyName:      report.PolicyName,
+		CreatedAt:       report.CreatedAt,
+	}
+}
+
 func GetDisplayStatus(status string) string {
 	statusMap := map[string]string{
 		"pending":            "Pending",
@@ -506,6 +552,10 @@ func ToOrderBookingInfo(info models.BookingInfo) OrderBookingInfo {
 	dateStr := fmt.Sprint(info.Date)
 	timeStr := fmt.Sprint(info.Time)
 	preferredStr := fmt.Sprint(info.PreferredTime)
+	frequency := ""
+	if info.Frequency != nil {
+		frequency = *info.Frequency
+	}
 
 	return OrderBookingInfo{
 		Day:            fmt.Sprint(info.Day),
@@ -517,6 +567,7 @@ func ToOrderBookingInfo(info models.BookingInfo) OrderBookingInfo {
 		QuantityOfPros: info.QuantityOfPros,
 		ToolsRequired:  info.ToolsRequired,
 		PersonCount:    info.PersonCount,
+		Frequency:      frequency,
 	}
 }
 
@@ -556,8 +607,13 @@ func ToOrderPricing(order *models.ServiceOrderNew) OrderPricing {
 		ServicesTotal:      order.ServicesTotal,
 		AddonsTotal:        order.AddonsTotal,
 		Subtotal:           order.Subtotal,
+		Surcharges:         order.Surcharges,
+		SurchargesTotal:    order.SurchargesTotal,
+		Taxes:              pricingdto.ToTaxLines(order.Taxes),
+		TaxTotal:           order.TaxTotal,
 		PlatformCommission: order.PlatformCommission,
 		TotalPrice:         order.TotalPrice,
+		Currency:           order.Currency,
 		FormattedTotal:     FormatPriceValue(order.TotalPrice),
 	}
 }
@@ -592,6 +648,7 @@ func ToOrderStatusInfo(order *models.ServiceOrderNew) OrderStatusInfo {
 		Current:            order.Status,
 		DisplayStatus:      GetDisplayStatus(order.Status),
 		ProviderAcceptedAt: order.ProviderAcceptedAt,
+		ProviderArrivedAt:  order.ProviderArrivedAt,
 		ProviderStartedAt:  order.ProviderStartedAt,
 		CompletedAt:        order.CompletedAt,
 		CanCancel:          order.CanBeCancelled(),
@@ -643,6 +700,7 @@ func ToOrderResponse(order *models.ServiceOrderNew) *OrderResponse {
 		Status:        ToOrderStatusInfo(order),
 		Cancellation:  ToOrderCancellationInfo(order.CancellationInfo),
 		Rating:        ToOrderRatingInfo(order),
+		RecurringPlan: order.RecurringPlanID,
 		CreatedAt:     order.CreatedAt,
 		UpdatedAt:     order.UpdatedAt,
 	}
@@ -653,6 +711,13 @@ func ToOrderResponse(order *models.ServiceOrderNew) *OrderResponse {
 		}
 	}
 
+	if order.IsMultiSession {
+		response.MultiSession = &OrderMultiSessionInfo{
+			SessionCount:    order.SessionCount,
+			ProgressPercent: order.ProgressPercent,
+		}
+	}
+
 	return response
 }
 
@@ -664,12 +729,14 @@ func ToOrderListResponse(order *models.ServiceOrderNew) OrderListResponse {
 		CategoryTitle:  GetCategoryTitle(order.CategorySlug),
 		BookingInfo:    ToOrderBookingInfo(order.BookingInfo),
 		TotalPrice:     order.TotalPrice,
+		Currency:       order.Currency,
 		FormattedTotal: FormatPriceValue(order.TotalPrice),
 		Status:         order.Status,
 		DisplayStatus:  GetDisplayStatus(order.Status),
 		ServiceCount:   len(order.SelectedServices),
 		CanCancel:      order.CanBeCancelled(),
 		CanRate:        order.CanBeRatedByCustomer(),
+		RecurringPlan:  order.RecurringPlanID,
 		CreatedAt:      order.CreatedAt,
 	}
 }
@@ -693,8 +760,135 @@ func ToOrderCreatedResponse(order *models.ServiceOrderNew) *OrderCreatedResponse
 		DisplayStatus:           GetDisplayStatus(order.Status),
 		BookingInfo:             ToOrderBookingInfo(order.BookingInfo),
 		TotalPrice:              order.TotalPrice,
+		Currency:                order.Currency,
 		FormattedTotal:          FormatPriceValue(order.TotalPrice),
 		EstimatedAssignmentTime: "5-15 minutes",
 		Message:                 "Your booking has been created. We're finding the best provider for you.",
 	}
 }
+
+type QuoteResponse struct {
+	ID               string                       `json:"id"`
+	QuoteNumber      string                       `json:"quoteNumber"`
+	CategorySlug     string                       `json:"categorySlug"`
+	SelectedServices []models.SelectedServiceItem `json:"selectedServices"`
+	SelectedAddons   []models.SelectedAddonItem   `json:"selectedAddons"`
+	ServicesTotal    float64                      `json:"servicesTotal"`
+	AddonsTotal      float64                      `json:"addonsTotal"`
+	Subtotal         float64                      `json:"subtotal"`
+	Surcharges       []models.OrderSurchargeItem  `json:"surcharges"`
+	SurchargesTotal  float64                      `json:"surchargesTotal"`
+	Taxes            []pricingdto.TaxLine         `json:"taxes"`
+	TaxTotal         float64                      `json:"taxTotal"`
+	TaxIncluded      bool                         `json:"taxIncluded"`
+	TotalPrice       float64                      `json:"totalPrice"`
+	FormattedTotal   string                       `json:"formattedTotal"`
+	Terms            []string                     `json:"terms"`
+	Status           string                       `json:"status"`
+	IsExpired        bool                         `json:"isExpired"`
+	ValidUntil       time.Time                    `json:"validUntil"`
+	ConvertedOrderID *string                      `json:"convertedOrderId,omitempty"`
+	ConvertedAt      *time.Time                   `json:"convertedAt,omitempty"`
+	CreatedAt        time.Time                    `json:"createdAt"`
+}
+
+type QuoteShareLinkResponse struct {
+	QuoteID   string    `json:"quoteId"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+func ToQuoteResponse(quote *models.OrderQuote) *QuoteResponse {
+	terms := []string(quote.Terms)
+	if terms == nil {
+		terms = []string{}
+	}
+	return &QuoteResponse{
+		ID:               quote.ID,
+		QuoteNumber:      quote.QuoteNumber,
+		CategorySlug:     quote.CategorySlug,
+		SelectedServices: quote.SelectedServices,
+		SelectedAddons:   quote.SelectedAddons,
+		ServicesTotal:    quote.ServicesTotal,
+		AddonsTotal:      quote.AddonsTotal,
+		Subtotal:         quote.Subtotal,
+		Surcharges:       quote.Surcharges,
+		SurchargesTotal:  quote.SurchargesTotal,
+		Taxes:            pricingdto.ToTaxLines(quote.Taxes),
+		TaxTotal:         quote.TaxTotal,
+		TaxIncluded:      quote.TaxIncluded,
+		TotalPrice:       quote.TotalPrice,
+		FormattedTotal:   FormatPriceValue(quote.TotalPrice),
+		Terms:            terms,
+		Status:           quote.Status,
+		IsExpired:        quote.IsExpired(time.Now()),
+		ValidUntil:       quote.ValidUntil,
+		ConvertedOrderID: quote.ConvertedOrderID,
+		ConvertedAt:      quote.ConvertedAt,
+		CreatedAt:        quote.CreatedAt,
+	}
+}
+
+func ToQuoteResponses(quotes []*models.OrderQuote) []*QuoteResponse {
+	responses := make([]*QuoteResponse, len(quotes))
+	for i, quote := range quotes {
+		responses[i] = ToQuoteResponse(quote)
+	}
+	return responses
+}
+
+type RecurringPlanResponse struct {
+	ID             string   `json:"id"`
+	Frequency      string   `json:"frequency"`
+	Status         string   `json:"status"`
+	CategorySlug   string   `json:"categorySlug"`
+	CategoryTitle  string   `json:"categoryTitle"`
+	Time           string   `json:"time"`
+	StartDate      string   `json:"startDate"`
+	EndDate        *string  `json:"endDate,omitempty"`
+	MaxOccurrences *int     `json:"maxOccurrences,omitempty"`
+	NextDate       *string  `json:"nextDate,omitempty"`
+	UpcomingDates  []string `json:"upcomingDates"`
+	SkippedDates   []string `json:"skippedDates"`
+	OrdersCreated  int      `json:"ordersCreated"`
+	LastError      string   `json:"lastError,omitempty"`
+
+	Orders []OrderListResponse `json:"orders,omitempty"`
+
+	PausedAt    *time.Time `json:"pausedAt,omitempty"`
+	CancelledAt *time.Time `json:"cancelledAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+// ToRecurringPlanResponse describes a plan; upcoming lists the dates still to
+// be booked.
+func ToRecurringPlanResponse(plan *models.RecurringOrderPlan, upcoming []string) *RecurringPlanResponse {
+	resp := &RecurringPlanResponse{
+		ID:             plan.ID,
+		Frequency:      string(plan.Frequency),
+		Status:         string(plan.Status),
+		CategorySlug:   plan.Template.CategorySlug,
+		CategoryTitle:  GetCategoryTitle(plan.Template.CategorySlug),
+		Time:           plan.Template.Time,
+		StartDate:      plan.StartDate,
+		EndDate:        plan.EndDate,
+		MaxOccurrences: plan.MaxOccurrences,
+		UpcomingDates:  upcoming,
+		SkippedDates:   plan.SkippedDates,
+		OrdersCreated:  plan.OrdersCreated,
+		LastError:      plan.LastError,
+		PausedAt:       plan.PausedAt,
+		CancelledAt:    plan.CancelledAt,
+		CreatedAt:      plan.CreatedAt,
+	}
+	if plan.Status == models.RecurringPlanActive || plan.Status == models.RecurringPlanPaused {
+		resp.NextDate = &plan.NextDate
+	}
+	if resp.UpcomingDates == nil {
+		resp.UpcomingDates = []string{}
+	}
+	if resp.SkippedDates == nil {
+		resp.SkippedDates = []string{}
+	}
+	return resp
+}
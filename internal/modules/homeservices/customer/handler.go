@@ -1,10 +1,13 @@
 package customer
 
 import (
+	"fmt"
+	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/umar5678/go-backend/internal/models"
 	"github.com/umar5678/go-backend/internal/modules/homeservices/customer/dto"
 	"github.com/umar5678/go-backend/internal/utils/response"
 )
@@ -223,11 +226,16 @@ func (h *Handler) GetDiscountedAddons(c *gin.Context) {
 
 // Search godoc
 // @Summary Search services and addons
-// @Description Search across services and addons by keyword
+// @Description Full-text search over service and addon titles, descriptions and categories, ranked by relevance. Words match as prefixes and close misspellings of titles still match
 // @Tags Home Services - Customer
 // @Produce json
 // @Param q query string true "Search query (min 2 characters)"
 // @Param category query string false "Filter by category slug"
+// @Param type query string false "service or addon"
+// @Param minPrice query number false "Minimum price"
+// @Param maxPrice query number false "Maximum price"
+// @Param minDuration query int false "Minimum duration in minutes (services only)"
+// @Param maxDuration query int false "Maximum duration in minutes (services only)"
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Items per page" default(20)
 // @Success 200 {object} response.Response{data=dto.SearchResponse}
@@ -315,6 +323,36 @@ func (h *Handler) GetOrder(c *gin.Context) {
 	response.Success(c, order, "Order retrieved successfully")
 }
 
+// GetOrderFeeBreakdown godoc
+// @Summary Get order fee breakdown
+// @Description Get services, add-ons, platform fee and taxes for an order as priced at booking
+// @Tags Home Services - Orders
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /homeservices/orders/{id}/fee-breakdown [get]
+func (h *Handler) GetOrderFeeBreakdown(c *gin.Context) {
+	orderID := c.Param("id")
+
+	customerID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User not authenticated"))
+		return
+	}
+
+	breakdown, err := h.service.GetOrderFeeBreakdown(c.Request.Context(), customerID.(string), orderID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, breakdown, "Fee breakdown retrieved successfully")
+}
+
 // ListOrders godoc
 // @Summary List customer orders
 // @Description Get paginated list of customer's orders with filters
@@ -359,6 +397,35 @@ func (h *Handler) ListOrders(c *gin.Context) {
 	response.Paginated(c, orders, *pagination, "Orders retrieved successfully")
 }
 
+// PreviewOrder godoc
+// @Summary Preview an order
+// @Description Price the selected services and addons and check addon compatibility without booking
+// @Tags Home Services - Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.PreviewOrderRequest true "Cart details"
+// @Success 200 {object} response.Response{data=dto.OrderPreviewResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /homeservices/orders/preview [post]
+func (h *Handler) PreviewOrder(c *gin.Context) {
+	var req dto.PreviewOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	preview, err := h.service.PreviewOrder(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, preview, "Order preview calculated successfully")
+}
+
 // GetCancellationPreview godoc
 // @Summary Preview order cancellation
 // @Description Get cancellation fee preview before cancelling an order
@@ -429,6 +496,45 @@ func (h *Handler) CancelOrder(c *gin.Context) {
 	response.Success(c, order, "Order cancelled successfully")
 }
 
+// ReportProviderNoShow godoc
+// @Summary Report that the provider did not show up
+// @Description Cancels an order whose provider has not arrived within the no-show time after the booked slot. The customer is refunded in full and the provider is penalised.
+// @Tags Home Services - Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param request body dto.ReportNoShowRequest true "No-show evidence"
+// @Success 200 {object} response.Response{data=dto.NoShowReportResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /homeservices/orders/{id}/no-show [post]
+func (h *Handler) ReportProviderNoShow(c *gin.Context) {
+	orderID := c.Param("id")
+
+	var req dto.ReportNoShowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	customerID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User not authenticated"))
+		return
+	}
+
+	report, err := h.service.ReportProviderNoShow(c.Request.Context(), customerID.(string), orderID, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, report, "Provider no-show reported")
+}
+
 // RateOrder godoc
 // @Summary Rate a completed order
 // @Description Submit rating and review for a completed order
@@ -467,3 +573,691 @@ func (h *Handler) RateOrder(c *gin.Context) {
 
 	response.Success(c, order, "Rating submitted successfully")
 }
+
+// GetOrderSessions godoc
+// @Summary Get multi-day order sessions
+// @Description Per-day schedule, check-in/out, payment milestones and overall progress of a multi-day order
+// @Tags Home Services - Orders
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} response.Response{data=shared.OrderSessionsResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /homeservices/orders/{id}/sessions [get]
+func (h *Handler) GetOrderSessions(c *gin.Context) {
+	customerID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User not authenticated"))
+		return
+	}
+
+	result, err := h.service.GetOrderSessions(c.Request.Context(), customerID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Order sessions retrieved successfully")
+}
+
+// ApproveSession godoc
+// @Summary Approve a completed session
+// @Description Approve a session's work, releasing its payment milestone and unlocking the next session. Approving the last session completes the order.
+// @Tags Home Services - Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param sessionId path string true "Session ID"
+// @Param request body dto.ApproveSessionRequest false "Optional feedback"
+// @Success 200 {object} response.Response{data=shared.OrderSessionsResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /homeservices/orders/{id}/sessions/{sessionId}/approve [post]
+func (h *Handler) ApproveSession(c *gin.Context) {
+	var req dto.ApproveSessionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+			return
+		}
+	}
+
+	customerID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User not authenticated"))
+		return
+	}
+
+	result, err := h.service.ApproveSession(c.Request.Context(), customerID.(string), c.Param("id"), c.Param("sessionId"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Session approved")
+}
+
+// RequestSessionChanges godoc
+// @Summary Request changes on a session
+// @Description Send a session back to the provider instead of approving it; the provider must check in again to address the feedback
+// @Tags Home Services - Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param sessionId path string true "Session ID"
+// @Param request body dto.RequestSessionChangesRequest true "What needs to change"
+// @Success 200 {object} response.Response{data=shared.OrderSessionsResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /homeservices/orders/{id}/sessions/{sessionId}/request-changes [post]
+func (h *Handler) RequestSessionChanges(c *gin.Context) {
+	var req dto.RequestSessionChangesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	customerID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User not authenticated"))
+		return
+	}
+
+	result, err := h.service.RequestSessionChanges(c.Request.Context(), customerID.(string), c.Param("id"), c.Param("sessionId"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Changes requested")
+}
+
+// CreateQuote godoc
+// @Summary Create an order quote
+// @Description Price the selected services and addons like the order preview and save the result as a quote with a validity date and terms
+// @Tags Home Services - Quotes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.PreviewOrderRequest true "Cart details"
+// @Success 200 {object} response.Response{data=dto.QuoteResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /homeservices/quotes [post]
+func (h *Handler) CreateQuote(c *gin.Context) {
+	customerID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User not authenticated"))
+		return
+	}
+
+	var req dto.PreviewOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	quote, err := h.service.CreateQuote(c.Request.Context(), customerID.(string), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, quote, "Quote created successfully")
+}
+
+// ListQuotes godoc
+// @Summary List my quotes
+// @Description Get the customer's quotes, newest first
+// @Tags Home Services - Quotes
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param status query string false "Filter by status" Enums(issued, converted)
+// @Success 200 {object} response.Response{data=[]dto.QuoteResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 500 {object} response.Response
+// @Router /homeservices/quotes [get]
+func (h *Handler) ListQuotes(c *gin.Context) {
+	customerID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User not authenticated"))
+		return
+	}
+
+	var query dto.ListQuotesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+
+	quotes, pagination, err := h.service.ListQuotes(c.Request.Context(), customerID.(string), query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Paginated(c, quotes, *pagination, "Quotes retrieved successfully")
+}
+
+// GetQuote godoc
+// @Summary Get quote details
+// @Tags Home Services - Quotes
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Quote ID"
+// @Success 200 {object} response.Response{data=dto.QuoteResponse}
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /homeservices/quotes/{id} [get]
+func (h *Handler) GetQuote(c *gin.Context) {
+	customerID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User not authenticated"))
+		return
+	}
+
+	quote, err := h.service.GetQuote(c.Request.Context(), customerID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, quote, "Quote retrieved successfully")
+}
+
+// DownloadQuote godoc
+// @Summary Download the quote as PDF
+// @Tags Home Services - Quotes
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param id path string true "Quote ID"
+// @Success 200 {file} file
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /homeservices/quotes/{id}/pdf [get]
+func (h *Handler) DownloadQuote(c *gin.Context) {
+	customerID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User not authenticated"))
+		return
+	}
+
+	pdf, filename, err := h.service.GetQuotePDF(c.Request.Context(), customerID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+// ShareQuote godoc
+// @Summary Create a share link for a quote
+// @Description Returns a signed, time-limited link that opens the quote PDF without logging in
+// @Tags Home Services - Quotes
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Quote ID"
+// @Success 200 {object} response.Response{data=dto.QuoteShareLinkResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 503 {object} response.Response
+// @Router /homeservices/quotes/{id}/share [post]
+func (h *Handler) ShareQuote(c *gin.Context) {
+	customerID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User not authenticated"))
+		return
+	}
+
+	link, err := h.service.CreateQuoteShareLink(c.Request.Context(), customerID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, link, "Quote share link created")
+}
+
+// DownloadSharedQuote godoc
+// @Summary Open a shared quote
+// @Description Serves the quote PDF for a signed share link
+// @Tags Home Services - Quotes
+// @Produce application/pdf
+// @Param id path string true "Quote ID"
+// @Param expires query string true "Link expiry (unix seconds)"
+// @Param signature query string true "Link signature"
+// @Success 200 {file} file
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /homeservices/quotes/shared/{id}/pdf [get]
+func (h *Handler) DownloadSharedQuote(c *gin.Context) {
+	pdf, filename, err := h.service.GetSharedQuotePDF(c.Request.Context(), c.Param("id"), c.Query("expires"), c.Query("signature"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", filename))
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+// ListRecurringPlans godoc
+// @Summary List recurring bookings
+// @Description Recurring bookings with their next dates. A booking becomes recurring when it is created with a recurrence
+// @Tags Home Services - Recurring
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]dto.RecurringPlanResponse}
+// @Failure 401 {object} response.Response
+// @Router /homeservices/recurring [get]
+func (h *Handler) ListRecurringPlans(c *gin.Context) {
+	customerID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User not authenticated"))
+		return
+	}
+
+	plans, err := h.service.ListRecurringPlans(c.Request.Context(), customerID.(string))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, plans, "Recurring bookings retrieved successfully")
+}
+
+// GetRecurringPlan godoc
+// @Summary Get a recurring booking
+// @Description The recurring booking with the orders booked for it
+// @Tags Home Services - Recurring
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Recurring booking ID"
+// @Success 200 {object} response.Response{data=dto.RecurringPlanResponse}
+// @Failure 404 {object} response.Response
+// @Router /homeservices/recurring/{id} [get]
+func (h *Handler) GetRecurringPlan(c *gin.Context) {
+	customerID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User not authenticated"))
+		return
+	}
+
+	plan, err := h.service.GetRecurringPlan(c.Request.Context(), customerID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, plan, "Recurring booking retrieved successfully")
+}
+
+// SkipOccurrence godoc
+// @Summary Skip one date of a recurring booking
+// @Description Only dates not yet booked can be skipped; cancel the order of a booked date instead
+// @Tags Home Services - Recurring
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Recurring booking ID"
+// @Param request body dto.SkipOccurrenceRequest true "Date to skip"
+// @Success 200 {object} response.Response{data=dto.RecurringPlanResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /homeservices/recurring/{id}/skip [post]
+func (h *Handler) SkipOccurrence(c *gin.Context) {
+	var req dto.SkipOccurrenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	customerID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User not authenticated"))
+		return
+	}
+
+	plan, err := h.service.SkipOccurrence(c.Request.Context(), customerID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, plan, "Date skipped successfully")
+}
+
+// PauseRecurringPlan godoc
+// @Summary Pause a recurring booking
+// @Description No new dates are booked while paused; orders already booked are kept
+// @Tags Home Services - Recurring
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Recurring booking ID"
+// @Success 200 {object} response.Response{data=dto.RecurringPlanResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /homeservices/recurring/{id}/pause [post]
+func (h *Handler) PauseRecurringPlan(c *gin.Context) {
+	customerID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User not authenticated"))
+		return
+	}
+
+	plan, err := h.service.PauseRecurringPlan(c.Request.Context(), customerID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, plan, "Recurring booking paused successfully")
+}
+
+// ResumeRecurringPlan godoc
+// @Summary Resume a paused recurring booking
+// @Description Booking restarts from the next date that has not passed
+// @Tags Home Services - Recurring
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Recurring booking ID"
+// @Success 200 {object} response.Response{data=dto.RecurringPlanResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /homeservices/recurring/{id}/resume [post]
+func (h *Handler) ResumeRecurringPlan(c *gin.Context) {
+	customerID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User not authenticated"))
+		return
+	}
+
+	plan, err := h.service.ResumeRecurringPlan(c.Request.Context(), customerID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, plan, "Recurring booking resumed successfully")
+}
+
+// CancelRecurringPlan godoc
+// @Summary Cancel a recurring booking
+// @Description Ends the series and cancels its booked orders that no provider has taken yet
+// @Tags Home Services - Recurring
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Recurring booking ID"
+// @Success 200 {object} response.Response{data=dto.RecurringPlanResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /homeservices/recurring/{id}/cancel [post]
+func (h *Handler) CancelRecurringPlan(c *gin.Context) {
+	customerID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User not authenticated"))
+		return
+	}
+
+	plan, err := h.service.CancelRecurringPlan(c.Request.Context(), customerID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, plan, "Recurring booking cancelled successfully")
+}
+
+// RescheduleOrder godoc
+// @Summary Reschedule an order
+// @Description Move a booking to a new date and time. Before a provider is assigned the change applies immediately; afterwards the provider has to confirm it.
+// @Tags Home Services - Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param request body dto.RescheduleOrderRequest true "New date and time"
+// @Success 200 {object} response.Response{data=shared.RescheduleResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /homeservices/orders/{id}/reschedule [post]
+func (h *Handler) RescheduleOrder(c *gin.Context) {
+	var req dto.RescheduleOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	customerID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User not authenticated"))
+		return
+	}
+
+	result, err := h.service.RescheduleOrder(c.Request.Context(), customerID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	message := "Reschedule request sent to the provider"
+	if result.Status == string(models.RescheduleStatusApplied) {
+		message = "Order rescheduled successfully"
+	}
+	response.Success(c, result, message)
+}
+
+// GetOrderReschedules godoc
+// @Summary List an order's reschedules
+// @Description Get the reschedule requests made on an order, newest first
+// @Tags Home Services - Orders
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} response.Response{data=shared.OrderReschedulesResponse}
+// @Failure 404 {object} response.Response
+// @Router /homeservices/orders/{id}/reschedules [get]
+func (h *Handler) GetOrderReschedules(c *gin.Context) {
+	customerID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User not authenticated"))
+		return
+	}
+
+	result, err := h.service.GetOrderReschedules(c.Request.Context(), customerID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Reschedules retrieved successfully")
+}
+
+// AcceptReschedule godoc
+// @Summary Accept a provider's reschedule proposal
+// @Description Agree to the new date and time the provider proposed; the order moves to it
+// @Tags Home Services - Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param rescheduleId path string true "Reschedule ID"
+// @Param request body dto.RespondRescheduleRequest false "Optional note"
+// @Success 200 {object} response.Response{data=shared.RescheduleResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /homeservices/orders/{id}/reschedules/{rescheduleId}/accept [post]
+func (h *Handler) AcceptReschedule(c *gin.Context) {
+	var req dto.RespondRescheduleRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+			return
+		}
+	}
+
+	customerID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User not authenticated"))
+		return
+	}
+
+	result, err := h.service.AcceptReschedule(c.Request.Context(), customerID.(string), c.Param("id"), c.Param("rescheduleId"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Order rescheduled successfully")
+}
+
+// DeclineReschedule godoc
+// @Summary Decline a provider's reschedule proposal
+// @Description Turn down the provider's proposed date and time; the order keeps its current booking
+// @Tags Home Services - Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param rescheduleId path string true "Reschedule ID"
+// @Param request body dto.RespondRescheduleRequest false "Optional note"
+// @Success 200 {object} response.Response{data=shared.RescheduleResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /homeservices/orders/{id}/reschedules/{rescheduleId}/decline [post]
+func (h *Handler) DeclineReschedule(c *gin.Context) {
+	var req dto.RespondRescheduleRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+			return
+		}
+	}
+
+	customerID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User not authenticated"))
+		return
+	}
+
+	result, err := h.service.DeclineReschedule(c.Request.Context(), customerID.(string), c.Param("id"), c.Param("rescheduleId"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Reschedule declined")
+}
+
+// GetOrderAdjustments godoc
+// @Summary List an order's scope adjustments
+// @Description Get the order's current items and the scope adjustments the provider proposed, newest first
+// @Tags Home Services - Orders
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} response.Response{data=shared.OrderAdjustmentsResponse}
+// @Failure 404 {object} response.Response
+// @Router /homeservices/orders/{id}/adjustments [get]
+func (h *Handler) GetOrderAdjustments(c *gin.Context) {
+	customerID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User not authenticated"))
+		return
+	}
+
+	result, err := h.service.GetOrderAdjustments(c.Request.Context(), customerID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Adjustments retrieved successfully")
+}
+
+// ApproveAdjustment godoc
+// @Summary Approve a provider's scope adjustment
+// @Description Agree to the items the provider added or removed on site. The order is repriced, the wallet hold or charge follows the new total, and the provider is paid for the approved scope.
+// @Tags Home Services - Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param adjustmentId path string true "Adjustment ID"
+// @Param request body dto.RespondAdjustmentRequest false "Optional note"
+// @Success 200 {object} response.Response{data=shared.AdjustmentResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /homeservices/orders/{id}/adjustments/{adjustmentId}/approve [post]
+func (h *Handler) ApproveAdjustment(c *gin.Context) {
+	var req dto.RespondAdjustmentRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+			return
+		}
+	}
+
+	customerID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User not authenticated"))
+		return
+	}
+
+	result, err := h.service.ApproveAdjustment(c.Request.Context(), customerID.(string), c.Param("id"), c.Param("adjustmentId"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Adjustment approved")
+}
+
+// RejectAdjustment godoc
+// @Summary Reject a provider's scope adjustment
+// @Description Turn down the items the provider proposed; the order keeps its scope and price
+// @Tags Home Services - Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param adjustmentId path string true "Adjustment ID"
+// @Param request body dto.RespondAdjustmentRequest false "Optional note"
+// @Success 200 {object} response.Response{data=shared.AdjustmentResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /homeservices/orders/{id}/adjustments/{adjustmentId}/reject [post]
+func (h *Handler) RejectAdjustment(c *gin.Context) {
+	var req dto.RespondAdjustmentRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+			return
+		}
+	}
+
+	customerID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User not authenticated"))
+		return
+	}
+
+	result, err := h.service.RejectAdjustment(c.Request.Context(), customerID.(string), c.Param("id"), c.Param("adjustmentId"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Adjustment rejected")
+}
@@ -0,0 +1,223 @@
+package customer
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/cancellation"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/customer/dto"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+	walletdto "github.com/umar5678/go-backend/internal/modules/wallet/dto"
+	"github.com/umar5678/go-backend/internal/services/livemetrics"
+	"github.com/umar5678/go-backend/internal/utils/location"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+)
+
+// ReportProviderNoShow lets the customer give up on a provider who has not
+// arrived by the booked slot plus the cancellation policy's no-show time.
+// The order is cancelled without a fee, the customer gets the full total
+// back, the provider pays the policy's no-show penalty and the customer's
+// evidence is kept with the outcome.
+func (s *service) ReportProviderNoShow(ctx context.Context, customerID, orderID string, req dto.ReportNoShowRequest) (*dto.NoShowReportResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	order, err := s.loadCustomerOrder(ctx, customerID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.AssignedProviderID == nil ||
+		(order.Status != shared.OrderStatusAssigned && order.Status != shared.OrderStatusAccepted) ||
+		order.ProviderArrivedAt != nil || order.ProviderStartedAt != nil {
+		return nil, response.BadRequest("A provider no-show can only be reported while waiting for the provider to arrive")
+	}
+
+	_, _, outcome := s.cancellationCharge(ctx, order)
+	if outcome.Rule != cancellation.RuleDriverNoShow {
+		return nil, response.BadRequest("The provider still has time to arrive")
+	}
+
+	now := time.Now()
+	report := &models.NoShowReport{
+		Product:         models.CancellationProductHomeServices,
+		ReferenceID:     order.ID,
+		ReportedBy:      customerID,
+		AbsentParty:     models.NoShowPartyProvider,
+		Note:            req.Note,
+		PhotoURLs:       req.PhotoURLs,
+		Latitude:        req.Latitude,
+		Longitude:       req.Longitude,
+		ProviderPenalty: outcome.DriverPenalty,
+		RefundAmount:    order.TotalPrice,
+		PolicyName:      outcome.PolicyName,
+	}
+	if from := noShowWaitStart(order); from != nil {
+		report.WaitedMinutes = int(now.Sub(*from).Minutes())
+	}
+	if req.Latitude != nil && (order.CustomerInfo.Lat != 0 || order.CustomerInfo.Lng != 0) {
+		distance := location.HaversineDistance(*req.Latitude, *req.Longitude, order.CustomerInfo.Lat, order.CustomerInfo.Lng) * 1000
+		distance = math.Round(distance*100) / 100
+		report.DistanceMeters = &distance
+	}
+
+	before := *order
+	if order.PaymentInfo != nil {
+		paymentInfo := *order.PaymentInfo
+		before.PaymentInfo = &paymentInfo
+	}
+
+	previousStatus := order.Status
+	order.Status = shared.OrderStatusCancelled
+	order.CancellationInfo = &models.CancellationInfo{
+		CancelledBy:  shared.CancelledByCustomer,
+		CancelledAt:  now,
+		Reason:       cancellation.RuleDriverNoShow,
+		RefundAmount: order.TotalPrice,
+		Policy:       outcome.PolicyName,
+	}
+	if order.PaymentInfo != nil {
+		order.PaymentInfo.Status = shared.PaymentStatusRefunded
+	}
+
+	// Only the report whose versioned cancel wins goes on to refund the
+	// customer and penalize the provider.
+	if err := s.repo.Update(ctx, order); err != nil {
+		logger.Error("failed to update order", "error", err, "orderID", order.ID)
+		return nil, shared.OrderUpdateError(err, "Failed to cancel order")
+	}
+
+	if err := s.refundNoShow(ctx, order); err != nil {
+		before.Version = order.Version
+		if restoreErr := s.repo.Update(ctx, &before); restoreErr != nil {
+			logger.Error("failed to restore order after no-show refund failed", "error", restoreErr, "orderID", order.ID)
+		}
+		return nil, err
+	}
+
+	if order.IsMultiSession {
+		if err := s.repo.CancelOpenSessions(ctx, order.ID); err != nil {
+			logger.Error("failed to cancel order sessions", "error", err, "orderID", order.ID)
+		}
+	}
+
+	s.closeCancelledOrder(ctx, order)
+	s.penalizeNoShow(ctx, order, outcome.DriverPenalty)
+
+	if err := s.repo.CreateNoShowReport(ctx, report); err != nil {
+		logger.Error("failed to record provider no-show", "error", err, "orderID", order.ID)
+	}
+
+	history := models.NewOrderStatusHistory(
+		order.ID,
+		previousStatus,
+		shared.OrderStatusCancelled,
+		&customerID,
+		shared.RoleCustomer,
+		"Cancelled by customer: provider did not show up",
+		models.StatusHistoryMetadata{
+			"noShowReportId":     report.ID,
+			"refundAmount":       report.RefundAmount,
+			"providerPenalty":    report.ProviderPenalty,
+			"cancellationPolicy": outcome.PolicyName,
+			"cancellationRule":   outcome.Rule,
+		},
+	)
+	s.repo.CreateStatusHistory(ctx, history)
+
+	s.notifyProvider(ctx, order, websocket.TypeProviderNoShowReported, map[string]interface{}{
+		"orderId":     order.ID,
+		"orderNumber": order.OrderNumber,
+		"penalty":     report.ProviderPenalty,
+		"message":     "The customer reported that you did not arrive and the order was cancelled",
+	})
+
+	logger.Info("provider no-show reported", "orderID", order.ID, "customerID", customerID,
+		"providerID", *order.AssignedProviderID, "waitedMinutes", report.WaitedMinutes,
+		"refundAmount", report.RefundAmount, "providerPenalty", report.ProviderPenalty)
+	s.publishOrderCancelled(ctx, order, previousStatus)
+
+	return dto.ToNoShowReportResponse(report), nil
+}
+
+// refundNoShow gives the customer the whole order total back. A hold still
+// reserving the funds is released; once the provider accepted it has been
+// captured, so the total is credited back instead.
+func (s *service) refundNoShow(ctx context.Context, order *models.ServiceOrderNew) error {
+	if order.WalletHoldID == nil {
+		return nil
+	}
+	if hold := s.activeHold(ctx, order.CustomerID, *order.WalletHoldID); hold != nil {
+		if err := s.walletService.ReleaseHold(ctx, order.CustomerID, walletdto.ReleaseHoldRequest{HoldID: hold.ID}); err != nil {
+			logger.Error("failed to release wallet hold", "error", err, "holdID", hold.ID)
+			return response.InternalServerError("Failed to process refund", err)
+		}
+		return nil
+	}
+	if order.ProviderAcceptedAt == nil || order.TotalPrice <= 0 {
+		return nil
+	}
+
+	metadata := map[string]interface{}{
+		"order_id":     order.ID,
+		"order_number": order.OrderNumber,
+	}
+	if _, err := s.walletService.CreditWallet(
+		ctx,
+		order.CustomerID,
+		order.TotalPrice,
+		"service_order",
+		order.ID,
+		fmt.Sprintf("Refund for order %s: provider did not show up", order.OrderNumber),
+		metadata,
+	); err != nil {
+		logger.Error("failed to refund no-show order", "error", err, "orderID", order.ID)
+		return response.InternalServerError("Failed to process refund", err)
+	}
+	return nil
+}
+
+// penalizeNoShow deducts the no-show penalty from the provider's wallet.
+func (s *service) penalizeNoShow(ctx context.Context, order *models.ServiceOrderNew, penalty float64) {
+	if penalty <= 0 || order.AssignedProviderID == nil {
+		return
+	}
+	provider, err := s.repo.GetProviderProfile(ctx, *order.AssignedProviderID)
+	if err != nil {
+		logger.Error("failed to load provider for no-show penalty", "error", err, "orderID", order.ID)
+		return
+	}
+	if _, err := s.walletService.DeductPenalty(ctx, provider.UserID, penalty, "provider_no_show", order.ID); err != nil {
+		logger.Error("failed to deduct provider no-show penalty", "error", err, "orderID", order.ID, "providerID", provider.ID)
+	}
+}
+
+// closeCancelledOrder clears what a cancelled order still has open: live
+// search metrics, reschedule requests, scope adjustments and its crew.
+func (s *service) closeCancelledOrder(ctx context.Context, order *models.ServiceOrderNew) {
+	livemetrics.OrderNotSearching(ctx, order.ID)
+
+	if err := s.repo.WithdrawPendingReschedules(ctx, order.ID); err != nil {
+		logger.Error("failed to withdraw reschedule requests", "error", err, "orderID", order.ID)
+	}
+	if err := s.repo.WithdrawPendingAdjustments(ctx, order.ID); err != nil {
+		logger.Error("failed to withdraw scope adjustments", "error", err, "orderID", order.ID)
+	}
+	if err := s.repo.ExpireOpenCrew(ctx, order.ID); err != nil {
+		logger.Error("failed to release order crew", "error", err, "orderID", order.ID)
+	}
+}
+
+// noShowWaitStart is when the wait for the provider began: the booked slot,
+// or acceptance when there is none.
+func noShowWaitStart(order *models.ServiceOrderNew) *time.Time {
+	if !order.BookingInfo.PreferredTime.IsZero() {
+		return &order.BookingInfo.PreferredTime
+	}
+	return order.ProviderAcceptedAt
+}
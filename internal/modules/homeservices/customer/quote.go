@@ -0,0 +1,297 @@
+package customer
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/customer/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultQuoteValidity = 14 * 24 * time.Hour
+	defaultQuoteLinkTTL  = 72 * time.Hour
+)
+
+func (s *service) ConfigureQuotes(cfg config.QuotesConfig, issuer config.ReceiptsConfig) {
+	s.quotes = cfg
+	s.quoteIssuer = issuer
+}
+
+// CreateQuote prices the selection exactly like the order preview and freezes
+// the result, together with the terms of the quoted services, as a quote.
+func (s *service) CreateQuote(ctx context.Context, customerID string, req dto.PreviewOrderRequest) (*dto.QuoteResponse, error) {
+	preview, err := s.PreviewOrder(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	terms, err := s.quoteTerms(ctx, preview.SelectedServices)
+	if err != nil {
+		return nil, err
+	}
+
+	number, err := s.quoteNumber()
+	if err != nil {
+		return nil, response.InternalServerError("Failed to create quote", err)
+	}
+
+	validity := s.quotes.Validity
+	if validity <= 0 {
+		validity = defaultQuoteValidity
+	}
+
+	selectedAddons := models.SelectedAddons(preview.SelectedAddons)
+	if selectedAddons == nil {
+		selectedAddons = models.SelectedAddons{}
+	}
+
+	quote := &models.OrderQuote{
+		QuoteNumber:      number,
+		CustomerID:       customerID,
+		CategorySlug:     preview.CategorySlug,
+		SelectedServices: preview.SelectedServices,
+		SelectedAddons:   selectedAddons,
+		Surcharges:       preview.Surcharges,
+		ServicesTotal:    preview.ServicesTotal,
+		AddonsTotal:      preview.AddonsTotal,
+		Subtotal:         preview.Subtotal,
+		SurchargesTotal:  preview.SurchargesTotal,
+		TaxTotal:         preview.TaxTotal,
+		TotalPrice:       preview.TotalPrice,
+		TaxIncluded:      preview.TaxIncluded,
+		Terms:            terms,
+		Status:           models.OrderQuoteStatusIssued,
+		ValidUntil:       time.Now().Add(validity),
+	}
+	for _, line := range preview.Taxes {
+		quote.Taxes = append(quote.Taxes, models.TaxLine{
+			Code:          line.Code,
+			Name:          line.Name,
+			Rate:          line.Rate,
+			TaxableAmount: line.TaxableAmount,
+			Amount:        line.Amount,
+		})
+	}
+
+	if err := s.repo.CreateQuote(ctx, quote); err != nil {
+		logger.Error("failed to create quote", "error", err, "customerID", customerID)
+		return nil, response.InternalServerError("Failed to create quote", err)
+	}
+
+	logger.Info("order quote issued", "quoteID", quote.ID, "quoteNumber", quote.QuoteNumber, "customerID", customerID, "total", quote.TotalPrice)
+
+	return dto.ToQuoteResponse(quote), nil
+}
+
+func (s *service) ListQuotes(ctx context.Context, customerID string, query dto.ListQuotesQuery) ([]*dto.QuoteResponse, *response.PaginationMeta, error) {
+	query.SetDefaults()
+
+	quotes, total, err := s.repo.GetCustomerQuotes(ctx, customerID, query)
+	if err != nil {
+		logger.Error("failed to list quotes", "error", err, "customerID", customerID)
+		return nil, nil, response.InternalServerError("Failed to list quotes", err)
+	}
+
+	pagination := response.NewPaginationMeta(total, query.Page, query.Limit)
+	return dto.ToQuoteResponses(quotes), &pagination, nil
+}
+
+func (s *service) GetQuote(ctx context.Context, customerID, quoteID string) (*dto.QuoteResponse, error) {
+	quote, err := s.getCustomerQuote(ctx, customerID, quoteID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToQuoteResponse(quote), nil
+}
+
+func (s *service) GetQuotePDF(ctx context.Context, customerID, quoteID string) ([]byte, string, error) {
+	quote, err := s.getCustomerQuote(ctx, customerID, quoteID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := s.repo.TrackQuoteAccess(ctx, quote.ID, false); err != nil {
+		logger.Warn("failed to track quote download", "error", err, "quoteID", quote.ID)
+	}
+
+	return renderQuotePDF(s.quoteIssuer, quote), quoteFilename(quote), nil
+}
+
+// CreateQuoteShareLink signs a link that opens the quote PDF without logging
+// in, so the customer can forward it to whoever approves the spend.
+func (s *service) CreateQuoteShareLink(ctx context.Context, customerID, quoteID string) (*dto.QuoteShareLinkResponse, error) {
+	if s.quotes.SigningSecret == "" {
+		return nil, response.ServiceUnavailable("Quote sharing is not configured")
+	}
+
+	quote, err := s.getCustomerQuote(ctx, customerID, quoteID)
+	if err != nil {
+		return nil, err
+	}
+	if quote.IsExpired(time.Now()) {
+		return nil, response.BadRequest("Quote has expired")
+	}
+
+	ttl := s.quotes.LinkTTL
+	if ttl <= 0 {
+		ttl = defaultQuoteLinkTTL
+	}
+	expiresAt := time.Now().Add(ttl).Truncate(time.Second)
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	url := fmt.Sprintf("%s/api/v1/homeservices/quotes/shared/%s/pdf?expires=%s&signature=%s",
+		s.quotes.PublicBaseURL, quote.ID, expires, s.signQuoteLink(quote.ID, expires))
+
+	return &dto.QuoteShareLinkResponse{
+		QuoteID:   quote.ID,
+		URL:       url,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (s *service) GetSharedQuotePDF(ctx context.Context, quoteID, expires, signature string) ([]byte, string, error) {
+	if s.quotes.SigningSecret == "" {
+		return nil, "", response.ServiceUnavailable("Quote sharing is not configured")
+	}
+	if !s.validQuoteLink(quoteID, expires, signature) {
+		return nil, "", response.ForbiddenError("Quote link is invalid or has expired")
+	}
+
+	quote, err := s.repo.GetQuoteByID(ctx, quoteID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, "", response.NotFoundError("Quote")
+		}
+		return nil, "", response.InternalServerError("Failed to get quote", err)
+	}
+
+	if err := s.repo.TrackQuoteAccess(ctx, quote.ID, true); err != nil {
+		logger.Warn("failed to track shared quote access", "error", err, "quoteID", quote.ID)
+	}
+
+	return renderQuotePDF(s.quoteIssuer, quote), quoteFilename(quote), nil
+}
+
+// getConvertibleQuote checks that an order placed against a quote may count
+// as its conversion. The order itself is always priced afresh.
+func (s *service) getConvertibleQuote(ctx context.Context, customerID, quoteID, categorySlug string) (*models.OrderQuote, error) {
+	quote, err := s.getCustomerQuote(ctx, customerID, quoteID)
+	if err != nil {
+		return nil, err
+	}
+	if quote.Status != models.OrderQuoteStatusIssued {
+		return nil, response.BadRequest("Quote has already been converted to an order")
+	}
+	if quote.IsExpired(time.Now()) {
+		return nil, response.BadRequest("Quote has expired")
+	}
+	if quote.CategorySlug != categorySlug {
+		return nil, response.BadRequest("Quote was issued for a different category")
+	}
+	return quote, nil
+}
+
+func (s *service) convertQuote(ctx context.Context, quote *models.OrderQuote, order *models.ServiceOrderNew) {
+	if err := s.repo.MarkQuoteConverted(ctx, quote.ID, order.ID); err != nil {
+		logger.Warn("failed to mark quote converted", "error", err, "quoteID", quote.ID, "orderID", order.ID)
+		return
+	}
+
+	logger.Info("order quote converted",
+		"quoteID", quote.ID,
+		"quoteNumber", quote.QuoteNumber,
+		"orderID", order.ID,
+		"quotedTotal", quote.TotalPrice,
+		"orderTotal", order.TotalPrice,
+	)
+}
+
+func (s *service) getCustomerQuote(ctx context.Context, customerID, quoteID string) (*models.OrderQuote, error) {
+	quote, err := s.repo.GetCustomerQuoteByID(ctx, customerID, quoteID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, response.NotFoundError("Quote")
+		}
+		return nil, response.InternalServerError("Failed to get quote", err)
+	}
+	return quote, nil
+}
+
+// quoteTerms snapshots the general quote terms followed by the terms of each
+// quoted service, without repeats.
+func (s *service) quoteTerms(ctx context.Context, services []models.SelectedServiceItem) (pq.StringArray, error) {
+	seen := make(map[string]bool)
+	terms := pq.StringArray{}
+	add := func(term string) {
+		term = strings.TrimSpace(term)
+		if term == "" || seen[term] {
+			return
+		}
+		seen[term] = true
+		terms = append(terms, term)
+	}
+
+	for _, term := range s.quotes.Terms {
+		add(term)
+	}
+	for _, item := range services {
+		svc, err := s.serviceRepo.GetActiveServiceBySlug(ctx, item.ServiceSlug)
+		if err != nil {
+			return nil, response.InternalServerError("Failed to create quote", err)
+		}
+		for _, term := range svc.TermsAndConditions {
+			add(term)
+		}
+	}
+
+	return terms, nil
+}
+
+func (s *service) quoteNumber() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	prefix := s.quotes.NumberPrefix
+	if prefix == "" {
+		prefix = "QT"
+	}
+	return fmt.Sprintf("%s-%s-%s", prefix, time.Now().UTC().Format("20060102"), strings.ToUpper(hex.EncodeToString(b))), nil
+}
+
+func (s *service) signQuoteLink(quoteID, expires string) string {
+	mac := hmac.New(sha256.New, []byte(s.quotes.SigningSecret))
+	mac.Write([]byte(quoteID + ":" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *service) validQuoteLink(quoteID, expires, signature string) bool {
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > unix {
+		return false
+	}
+
+	given, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	expected, _ := hex.DecodeString(s.signQuoteLink(quoteID, expires))
+	return hmac.Equal(given, expected)
+}
+
+func quoteFilename(quote *models.OrderQuote) string {
+	return fmt.Sprintf("quote-%s.pdf", quote.QuoteNumber)
+}
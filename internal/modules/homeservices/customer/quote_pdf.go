@@ -0,0 +1,105 @@
+package customer
+
+import (
+	"fmt"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/customer/dto"
+	"github.com/umar5678/go-backend/internal/modules/pricing"
+	"github.com/umar5678/go-backend/internal/utils/pdf"
+)
+
+// renderQuotePDF lays the quote out on a single page; the term list is cut
+// short rather than spilling onto a second one.
+func renderQuotePDF(issuer config.ReceiptsConfig, quote *models.OrderQuote) []byte {
+	const (
+		left   = 50.0
+		right  = pdf.PageWidth - 50.0
+		bottom = pdf.PageHeight - 60.0
+	)
+
+	doc := pdf.NewDocument()
+	y := 60.0
+
+	doc.Text(left, y, 18, true, issuer.CompanyName)
+	doc.TextRight(right, y, 14, true, "SERVICE QUOTE")
+	y += 16
+	if issuer.CompanyAddress != "" {
+		doc.Text(left, y, 9, false, issuer.CompanyAddress)
+		y += 12
+	}
+	if issuer.TaxID != "" {
+		doc.Text(left, y, 9, false, "Tax ID: "+issuer.TaxID)
+		y += 12
+	}
+
+	y += 12
+	doc.Text(left, y, 10, false, "Quote number: "+quote.QuoteNumber)
+	doc.TextRight(right, y, 10, false, "Issued: "+quote.CreatedAt.Format("02 Jan 2006"))
+	y += 14
+	doc.Text(left, y, 10, false, "Category: "+quote.CategorySlug)
+	doc.TextRight(right, y, 10, false, "Valid until: "+quote.ValidUntil.Format("02 Jan 2006"))
+	y += 20
+	doc.Rule(left, right, y)
+	y += 20
+
+	line := func(label string, amount float64, bold bool) {
+		doc.Text(left, y, 10, bold, pdf.Truncate(label, 70))
+		doc.TextRight(right, y, 10, bold, dto.FormatPriceValue(amount))
+		y += 14
+	}
+
+	doc.Text(left, y, 11, true, "Services")
+	y += 16
+	for _, svc := range quote.SelectedServices {
+		line(fmt.Sprintf("%s x%d", svc.Title, svc.Quantity), svc.Price*float64(svc.Quantity), false)
+	}
+	if len(quote.SelectedAddons) > 0 {
+		y += 6
+		doc.Text(left, y, 11, true, "Add-ons")
+		y += 16
+		for _, addon := range quote.SelectedAddons {
+			line(fmt.Sprintf("%s x%d", addon.Title, addon.Quantity), addon.Price*float64(addon.Quantity), false)
+		}
+	}
+
+	y += 4
+	doc.Rule(left, right, y)
+	y += 18
+	line("Subtotal", quote.Subtotal, false)
+	for _, surcharge := range quote.Surcharges {
+		line(surcharge.Name, surcharge.Amount, false)
+	}
+	for _, tax := range quote.Taxes {
+		line(pricing.TaxLabel(tax.Name, tax.Rate), tax.Amount, false)
+	}
+
+	y += 4
+	doc.Rule(left, right, y)
+	y += 18
+	line("Total", quote.TotalPrice, true)
+	if !quote.TaxIncluded {
+		doc.Text(left, y, 9, false, "Applicable taxes are calculated when the service address is confirmed.")
+		y += 14
+	}
+
+	if len(quote.Terms) > 0 {
+		y += 16
+		doc.Text(left, y, 11, true, "Terms and conditions")
+		y += 16
+		for i, term := range quote.Terms {
+			if y > bottom {
+				doc.Text(left, y, 9, false, fmt.Sprintf("... and %d more", len(quote.Terms)-i))
+				break
+			}
+			doc.Text(left, y, 9, false, pdf.Truncate(fmt.Sprintf("%d. %s", i+1, term), 110))
+			y += 12
+		}
+	}
+
+	y += 20
+	doc.Text(left, y, 9, false, "Prices are confirmed when the order is placed and may change after the quote expires.")
+
+	return doc.Bytes()
+}
@@ -0,0 +1,29 @@
+package customer
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+// RatingAggregator keeps providers' stored ratings in step with the ratings
+// customers leave. It is optional; without one they are only corrected by the
+// nightly reconciliation.
+type RatingAggregator interface {
+	RefreshProviderRating(ctx context.Context, providerID, categorySlug string) error
+}
+
+func (s *service) SetRatingAggregator(aggregator RatingAggregator) {
+	s.ratingAggregator = aggregator
+}
+
+func (s *service) refreshProviderRating(providerID, categorySlug string) {
+	if s.ratingAggregator == nil {
+		return
+	}
+	go func() {
+		if err := s.ratingAggregator.RefreshProviderRating(context.Background(), providerID, categorySlug); err != nil {
+			logger.Error("failed to refresh provider rating", "error", err, "providerID", providerID)
+		}
+	}()
+}
@@ -0,0 +1,474 @@
+package customer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/customer/dto"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+	"github.com/umar5678/go-backend/internal/services/health"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+const (
+	dateLayout = "2006-01-02"
+
+	// upcomingOccurrences is how many future dates a plan lists.
+	upcomingOccurrences = 5
+)
+
+// createRecurringOrder books the first occurrence and saves the plan that
+// books the rest.
+func (s *service) createRecurringOrder(ctx context.Context, customerID string, req dto.CreateOrderRequest) (*dto.OrderCreatedResponse, error) {
+	plan := &models.RecurringOrderPlan{
+		CustomerID:     customerID,
+		Frequency:      models.RecurrenceFrequency(req.Recurrence.Frequency),
+		Status:         models.RecurringPlanActive,
+		Template:       recurringTemplate(req),
+		StartDate:      req.BookingInfo.Date,
+		EndDate:        req.Recurrence.EndDate,
+		MaxOccurrences: req.Recurrence.Occurrences,
+		NextOccurrence: 1,
+		SkippedDates:   []string{},
+	}
+	next, err := plan.OccurrenceDate(1)
+	if err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+	plan.NextDate = next.Format(dateLayout)
+	if plan.HasEnded(1, next) {
+		return nil, response.BadRequest("recurrence: the series must include at least two bookings")
+	}
+
+	if err := s.repo.CreateRecurringPlan(ctx, plan); err != nil {
+		logger.Error("failed to create recurring plan", "error", err, "customerID", customerID)
+		return nil, response.InternalServerError("Failed to create order", err)
+	}
+
+	order, err := s.placeOrder(ctx, customerID, req, plan)
+	if err != nil {
+		s.repo.DeleteRecurringPlan(ctx, plan.ID)
+		return nil, err
+	}
+
+	plan.OrdersCreated = 1
+	if err := s.repo.RecordRecurringOccurrence(ctx, plan.ID, true, ""); err != nil {
+		logger.Error("failed to update recurring plan", "error", err, "planID", plan.ID)
+	}
+
+	logger.Info("recurring plan created", "planID", plan.ID, "customerID", customerID, "frequency", plan.Frequency)
+
+	resp := dto.ToOrderCreatedResponse(order)
+	resp.RecurringPlan = dto.ToRecurringPlanResponse(plan, upcomingDates(plan))
+	return resp, nil
+}
+
+func recurringTemplate(req dto.CreateOrderRequest) models.RecurringOrderTemplate {
+	template := models.RecurringOrderTemplate{
+		CustomerInfo: models.CustomerInfo{
+			Name:    req.CustomerInfo.Name,
+			Phone:   req.CustomerInfo.Phone,
+			Email:   req.CustomerInfo.Email,
+			Address: req.CustomerInfo.Address,
+			Lat:     req.CustomerInfo.Lat,
+			Lng:     req.CustomerInfo.Lng,
+		},
+		Time:           req.BookingInfo.Time,
+		PreferredTime:  req.BookingInfo.PreferredTime,
+		QuantityOfPros: req.BookingInfo.QuantityOfPros,
+		ToolsRequired:  req.BookingInfo.ToolsRequired,
+		PersonCount:    req.BookingInfo.PersonCount,
+		CategorySlug:   req.CategorySlug,
+		SpecialNotes:   req.SpecialNotes,
+		PaymentMethod:  req.PaymentMethod,
+	}
+	for _, svc := range req.SelectedServices {
+		template.Services = append(template.Services, models.RecurringOrderItem{Slug: svc.ServiceSlug, Quantity: svc.Quantity})
+	}
+	for _, addon := range req.SelectedAddons {
+		template.Addons = append(template.Addons, models.RecurringOrderItem{Slug: addon.AddonSlug, Quantity: addon.Quantity})
+	}
+	return template
+}
+
+// occurrenceRequest rebuilds the order request for one occurrence.
+func occurrenceRequest(plan *models.RecurringOrderPlan, date string) dto.CreateOrderRequest {
+	t := plan.Template
+	req := dto.CreateOrderRequest{
+		CustomerInfo: dto.CustomerInfoRequest{
+			Name:    t.CustomerInfo.Name,
+			Phone:   t.CustomerInfo.Phone,
+			Email:   t.CustomerInfo.Email,
+			Address: t.CustomerInfo.Address,
+			Lat:     t.CustomerInfo.Lat,
+			Lng:     t.CustomerInfo.Lng,
+		},
+		BookingInfo: dto.BookingInfoRequest{
+			Date:           date,
+			Time:           t.Time,
+			PreferredTime:  t.PreferredTime,
+			QuantityOfPros: t.QuantityOfPros,
+			ToolsRequired:  t.ToolsRequired,
+			PersonCount:    t.PersonCount,
+		},
+		CategorySlug:  t.CategorySlug,
+		SpecialNotes:  t.SpecialNotes,
+		PaymentMethod: t.PaymentMethod,
+	}
+	for _, item := range t.Services {
+		req.SelectedServices = append(req.SelectedServices, dto.SelectedServiceRequest{ServiceSlug: item.Slug, Quantity: item.Quantity})
+	}
+	for _, item := range t.Addons {
+		req.SelectedAddons = append(req.SelectedAddons, dto.SelectedAddonRequest{AddonSlug: item.Slug, Quantity: item.Quantity})
+	}
+	return req
+}
+
+// advancePlan moves the plan to its next occurrence, ending it when the
+// series is over.
+func advancePlan(plan *models.RecurringOrderPlan) error {
+	n := plan.NextOccurrence + 1
+	date, err := plan.OccurrenceDate(n)
+	if err != nil {
+		return err
+	}
+	plan.NextOccurrence = n
+	plan.NextDate = date.Format(dateLayout)
+	if plan.HasEnded(n, date) {
+		plan.Status = models.RecurringPlanEnded
+	}
+	return nil
+}
+
+// upcomingDates lists the next dates the plan will book, leaving out skipped
+// ones.
+func upcomingDates(plan *models.RecurringOrderPlan) []string {
+	if plan.Status != models.RecurringPlanActive && plan.Status != models.RecurringPlanPaused {
+		return nil
+	}
+	var dates []string
+	for n := plan.NextOccurrence; len(dates) < upcomingOccurrences; n++ {
+		date, err := plan.OccurrenceDate(n)
+		if err != nil || plan.HasEnded(n, date) {
+			break
+		}
+		if day := date.Format(dateLayout); !plan.IsSkipped(day) {
+			dates = append(dates, day)
+		}
+	}
+	return dates
+}
+
+// BookDueOccurrences books every occurrence of an active plan that falls
+// within the lead time. An occurrence that cannot be booked, for example
+// because a service was withdrawn, is recorded on the plan and passed over.
+// Each occurrence is claimed by moving the plan past it before it is booked,
+// so schedulers running side by side never book it twice and a plan the
+// customer pauses or cancels meanwhile is left alone.
+func (s *service) BookDueOccurrences(ctx context.Context) (int, error) {
+	now := time.Now()
+	today := now.Format(dateLayout)
+	horizon := now.Add(s.recurring.LeadTime).Format(dateLayout)
+
+	plans, err := s.repo.GetDueRecurringPlans(ctx, horizon)
+	if err != nil {
+		return 0, err
+	}
+
+	booked := 0
+	for _, plan := range plans {
+		for plan.Status == models.RecurringPlanActive && plan.NextDate <= horizon {
+			date := plan.NextDate
+			next := *plan
+			if err := advancePlan(&next); err != nil {
+				logger.Error("failed to advance recurring plan", "error", err, "planID", plan.ID)
+				break
+			}
+			claimed, err := s.repo.ClaimRecurringOccurrence(ctx, plan, &next)
+			if err != nil {
+				logger.Error("failed to claim recurring occurrence", "error", err, "planID", plan.ID, "date", date)
+				break
+			}
+			if !claimed {
+				break
+			}
+
+			if date >= today && !plan.IsSkipped(date) {
+				err := s.bookOccurrence(ctx, plan, date)
+				lastError := ""
+				if err != nil {
+					lastError = fmt.Sprintf("%s: %s", date, err.Error())
+					logger.Warn("failed to book recurring occurrence", "error", err, "planID", plan.ID, "date", date)
+				} else {
+					booked++
+				}
+				if err := s.repo.RecordRecurringOccurrence(ctx, plan.ID, err == nil, lastError); err != nil {
+					logger.Error("failed to update recurring plan", "error", err, "planID", plan.ID)
+				}
+			}
+			plan = &next
+		}
+	}
+	return booked, nil
+}
+
+func (s *service) bookOccurrence(ctx context.Context, plan *models.RecurringOrderPlan, date string) error {
+	req := occurrenceRequest(plan, date)
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	order, err := s.placeOrder(ctx, plan.CustomerID, req, plan)
+	if err != nil {
+		return err
+	}
+	logger.Info("recurring occurrence booked", "planID", plan.ID, "orderID", order.ID, "date", date)
+	return nil
+}
+
+func (s *service) ListRecurringPlans(ctx context.Context, customerID string) ([]*dto.RecurringPlanResponse, error) {
+	plans, err := s.repo.GetCustomerRecurringPlans(ctx, customerID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to list recurring bookings", err)
+	}
+	responses := make([]*dto.RecurringPlanResponse, len(plans))
+	for i, plan := range plans {
+		responses[i] = dto.ToRecurringPlanResponse(plan, upcomingDates(plan))
+	}
+	return responses, nil
+}
+
+func (s *service) GetRecurringPlan(ctx context.Context, customerID, planID string) (*dto.RecurringPlanResponse, error) {
+	plan, err := s.getCustomerRecurringPlan(ctx, customerID, planID)
+	if err != nil {
+		return nil, err
+	}
+	return s.recurringPlanWithOrders(ctx, plan)
+}
+
+// SkipOccurrence leaves a future date out of the series. Dates already booked
+// are cancelled like any other order.
+func (s *service) SkipOccurrence(ctx context.Context, customerID, planID string, req dto.SkipOccurrenceRequest) (*dto.RecurringPlanResponse, error) {
+	plan, err := s.getCustomerRecurringPlan(ctx, customerID, planID)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Status != models.RecurringPlanActive && plan.Status != models.RecurringPlanPaused {
+		return nil, response.BadRequest(fmt.Sprintf("Recurring booking is %s", plan.Status))
+	}
+
+	target, err := time.Parse(dateLayout, req.Date)
+	if err != nil {
+		return nil, response.BadRequest("invalid date format, expected YYYY-MM-DD")
+	}
+	if req.Date < plan.NextDate {
+		return nil, response.BadRequest("This date is already booked; cancel its order instead")
+	}
+	if plan.IsSkipped(req.Date) {
+		return s.recurringPlanWithOrders(ctx, plan)
+	}
+
+	for n := plan.NextOccurrence; ; n++ {
+		date, err := plan.OccurrenceDate(n)
+		if err != nil {
+			return nil, response.InternalServerError("Failed to skip date", err)
+		}
+		if plan.HasEnded(n, date) || date.After(target) {
+			return nil, response.BadRequest("The recurring booking has no visit on this date")
+		}
+		if date.Equal(target) {
+			break
+		}
+	}
+
+	ok, err := s.repo.AddRecurringSkippedDate(ctx, plan.ID, req.Date)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to skip date", err)
+	}
+	if !ok {
+		return nil, response.ConflictError("This date was booked or the recurring booking changed; try again")
+	}
+	plan.SkippedDates = append(plan.SkippedDates, req.Date)
+
+	logger.Info("recurring occurrence skipped", "planID", plan.ID, "date", req.Date)
+	return s.recurringPlanWithOrders(ctx, plan)
+}
+
+// PauseRecurringPlan stops new occurrences from being booked. Orders already
+// booked are kept.
+func (s *service) PauseRecurringPlan(ctx context.Context, customerID, planID string) (*dto.RecurringPlanResponse, error) {
+	plan, err := s.getCustomerRecurringPlan(ctx, customerID, planID)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Status != models.RecurringPlanActive {
+		return nil, response.BadRequest(fmt.Sprintf("Recurring booking is %s", plan.Status))
+	}
+
+	plan.Status = models.RecurringPlanPaused
+	plan.PausedAt = shared.TimePtr(time.Now())
+	ok, err := s.repo.TransitionRecurringPlan(ctx, plan.ID,
+		[]models.RecurringPlanStatus{models.RecurringPlanActive},
+		map[string]interface{}{"status": plan.Status, "paused_at": plan.PausedAt})
+	if err != nil {
+		return nil, response.InternalServerError("Failed to pause recurring booking", err)
+	}
+	if !ok {
+		return nil, response.ConflictError("Recurring booking was changed; try again")
+	}
+
+	logger.Info("recurring plan paused", "planID", plan.ID)
+	return s.recurringPlanWithOrders(ctx, plan)
+}
+
+// ResumeRecurringPlan restarts a paused plan from its first occurrence that
+// has not yet passed; the ones missed while paused are not booked.
+func (s *service) ResumeRecurringPlan(ctx context.Context, customerID, planID string) (*dto.RecurringPlanResponse, error) {
+	plan, err := s.getCustomerRecurringPlan(ctx, customerID, planID)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Status != models.RecurringPlanPaused {
+		return nil, response.BadRequest(fmt.Sprintf("Recurring booking is %s", plan.Status))
+	}
+
+	plan.Status = models.RecurringPlanActive
+	plan.PausedAt = nil
+	today := time.Now().Format(dateLayout)
+	for plan.Status == models.RecurringPlanActive && plan.NextDate < today {
+		if err := advancePlan(plan); err != nil {
+			return nil, response.InternalServerError("Failed to resume recurring booking", err)
+		}
+	}
+
+	ok, err := s.repo.TransitionRecurringPlan(ctx, plan.ID,
+		[]models.RecurringPlanStatus{models.RecurringPlanPaused},
+		map[string]interface{}{
+			"status":          plan.Status,
+			"paused_at":       nil,
+			"next_occurrence": plan.NextOccurrence,
+			"next_date":       plan.NextDate,
+		})
+	if err != nil {
+		return nil, response.InternalServerError("Failed to resume recurring booking", err)
+	}
+	if !ok {
+		return nil, response.ConflictError("Recurring booking was changed; try again")
+	}
+
+	logger.Info("recurring plan resumed", "planID", plan.ID, "status", plan.Status, "nextDate", plan.NextDate)
+	return s.recurringPlanWithOrders(ctx, plan)
+}
+
+// CancelRecurringPlan ends the series and cancels its booked orders that no
+// provider has taken yet.
+func (s *service) CancelRecurringPlan(ctx context.Context, customerID, planID string) (*dto.RecurringPlanResponse, error) {
+	plan, err := s.getCustomerRecurringPlan(ctx, customerID, planID)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Status == models.RecurringPlanCancelled || plan.Status == models.RecurringPlanEnded {
+		return nil, response.BadRequest(fmt.Sprintf("Recurring booking is %s", plan.Status))
+	}
+
+	plan.Status = models.RecurringPlanCancelled
+	plan.CancelledAt = shared.TimePtr(time.Now())
+	ok, err := s.repo.TransitionRecurringPlan(ctx, plan.ID,
+		[]models.RecurringPlanStatus{models.RecurringPlanActive, models.RecurringPlanPaused},
+		map[string]interface{}{"status": plan.Status, "cancelled_at": plan.CancelledAt})
+	if err != nil {
+		return nil, response.InternalServerError("Failed to cancel recurring booking", err)
+	}
+	if !ok {
+		return nil, response.ConflictError("Recurring booking was changed; try again")
+	}
+
+	orders, err := s.repo.GetRecurringPlanOrders(ctx, plan.ID)
+	if err != nil {
+		logger.Error("failed to get recurring plan orders", "error", err, "planID", plan.ID)
+	}
+	cancelReq := dto.CancelOrderRequest{Reason: "Recurring booking cancelled by customer"}
+	for _, order := range orders {
+		if order.AssignedProviderID != nil ||
+			(order.Status != shared.OrderStatusPending && order.Status != shared.OrderStatusSearchingProvider) {
+			continue
+		}
+		if _, err := s.CancelOrder(ctx, customerID, order.ID, cancelReq); err != nil {
+			logger.Error("failed to cancel recurring order", "error", err, "planID", plan.ID, "orderID", order.ID)
+		}
+	}
+
+	logger.Info("recurring plan cancelled", "planID", plan.ID, "customerID", customerID)
+	return s.recurringPlanWithOrders(ctx, plan)
+}
+
+func (s *service) getCustomerRecurringPlan(ctx context.Context, customerID, planID string) (*models.RecurringOrderPlan, error) {
+	plan, err := s.repo.GetCustomerRecurringPlan(ctx, customerID, planID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Recurring booking")
+		}
+		return nil, response.InternalServerError("Failed to get recurring booking", err)
+	}
+	return plan, nil
+}
+
+func (s *service) recurringPlanWithOrders(ctx context.Context, plan *models.RecurringOrderPlan) (*dto.RecurringPlanResponse, error) {
+	orders, err := s.repo.GetRecurringPlanOrders(ctx, plan.ID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get recurring booking", err)
+	}
+	resp := dto.ToRecurringPlanResponse(plan, upcomingDates(plan))
+	resp.Orders = dto.ToOrderListResponses(orders)
+	return resp, nil
+}
+
+func (s *service) ConfigureRecurringOrders(cfg config.RecurringOrdersConfig) {
+	s.recurring = cfg
+}
+
+// RecurringOrderScheduler books upcoming occurrences of recurring plans.
+type RecurringOrderScheduler struct {
+	service  Service
+	interval time.Duration
+}
+
+func NewRecurringOrderScheduler(service Service, cfg config.RecurringOrdersConfig) *RecurringOrderScheduler {
+	interval := cfg.SweepInterval
+	if interval <= 0 {
+		interval = 15 * time.Minute
+	}
+	return &RecurringOrderScheduler{service: service, interval: interval}
+}
+
+func (w *RecurringOrderScheduler) Start(ctx context.Context) {
+	heartbeat := health.NewHeartbeat("recurring_orders", w.interval)
+
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				runCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+				if booked, err := w.service.BookDueOccurrences(runCtx); err != nil {
+					logger.Error("recurring order scheduling failed", "error", err)
+				} else if booked > 0 {
+					logger.Info("recurring occurrences booked", "count", booked)
+				}
+				cancel()
+				heartbeat.Beat()
+			}
+		}
+	}()
+
+	logger.Info("recurring order scheduler started", "interval", w.interval)
+}
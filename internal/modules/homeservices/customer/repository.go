@@ -7,7 +7,9 @@ import (
 
 	"gorm.io/gorm"
 
+	"github.com/umar5678/go-backend/internal/database"
 	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/archive"
 	"github.com/umar5678/go-backend/internal/modules/homeservices/customer/dto"
 	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
 )
@@ -24,11 +26,12 @@ type Repository interface {
 	GetActiveAddonsByCategory(ctx context.Context, categorySlug string) ([]*models.Addon, error)
 	CountActiveAddonsByCategory(ctx context.Context, categorySlug string) (int64, error)
 	GetDiscountedAddons(ctx context.Context, limit int) ([]*models.Addon, error)
+	GetAddonCompatibility(ctx context.Context, addonSlugs []string) ([]models.AddonServiceCompatibility, error)
+	GetActiveSurcharges(ctx context.Context, categorySlug string) ([]*models.CategorySurcharge, error)
 
 	GetAllActiveCategories(ctx context.Context) ([]CategoryInfo, error)
 
-	SearchServices(ctx context.Context, query string, categorySlug string, limit int) ([]*models.ServiceNew, error)
-	SearchAddons(ctx context.Context, query string, categorySlug string, limit int) ([]*models.Addon, error)
+	Search(ctx context.Context, query dto.SearchQuery, tsQuery string) ([]*SearchHit, int64, error)
 
 	Create(ctx context.Context, order *models.ServiceOrderNew) error
 	GetByID(ctx context.Context, id string) (*models.ServiceOrderNew, error)
@@ -38,12 +41,59 @@ type Repository interface {
 
 	GetCustomerOrders(ctx context.Context, customerID string, query dto.ListOrdersQuery) ([]*models.ServiceOrderNew, int64, error)
 	GetCustomerOrderByID(ctx context.Context, customerID, orderID string) (*models.ServiceOrderNew, error)
+	GetArchivedCustomerOrderByID(ctx context.Context, customerID, orderID string) (*models.ServiceOrderNew, error)
 	CountCustomerActiveOrders(ctx context.Context, customerID string) (int64, error)
 
 	UpdateStatus(ctx context.Context, orderID, status string) error
 
 	CreateStatusHistory(ctx context.Context, history *models.OrderStatusHistory) error
 	GetOrderStatusHistory(ctx context.Context, orderID string) ([]models.OrderStatusHistory, error)
+
+	CreateSessions(ctx context.Context, sessions []*models.ServiceOrderSession) error
+	GetOrderSessions(ctx context.Context, orderID string) ([]*models.ServiceOrderSession, error)
+	UpdateSession(ctx context.Context, session *models.ServiceOrderSession) error
+	ClaimSessionApproval(ctx context.Context, session *models.ServiceOrderSession) (bool, error)
+	ReleaseSessionApproval(ctx context.Context, sessionID string) error
+	CancelOpenSessions(ctx context.Context, orderID string) error
+	GetProviderProfile(ctx context.Context, providerID string) (*models.ServiceProviderProfile, error)
+	IncrementProviderCompletedJobs(ctx context.Context, providerID, categorySlug string, earnings float64) error
+
+	CreateQuote(ctx context.Context, quote *models.OrderQuote) error
+	GetQuoteByID(ctx context.Context, quoteID string) (*models.OrderQuote, error)
+	GetCustomerQuoteByID(ctx context.Context, customerID, quoteID string) (*models.OrderQuote, error)
+	GetCustomerQuotes(ctx context.Context, customerID string, query dto.ListQuotesQuery) ([]*models.OrderQuote, int64, error)
+	MarkQuoteConverted(ctx context.Context, quoteID, orderID string) error
+	TrackQuoteAccess(ctx context.Context, quoteID string, shared bool) error
+
+	CreateRecurringPlan(ctx context.Context, plan *models.RecurringOrderPlan) error
+	// ClaimRecurringOccurrence moves an active plan on to next, provided no
+	// one else has booked its current occurrence or paused or cancelled it
+	// since it was read. Only the caller that wins books the occurrence.
+	ClaimRecurringOccurrence(ctx context.Context, plan, next *models.RecurringOrderPlan) (bool, error)
+	RecordRecurringOccurrence(ctx context.Context, planID string, booked bool, lastError string) error
+	// TransitionRecurringPlan applies updates to a plan still in one of the
+	// given statuses.
+	TransitionRecurringPlan(ctx context.Context, planID string, from []models.RecurringPlanStatus, updates map[string]interface{}) (bool, error)
+	AddRecurringSkippedDate(ctx context.Context, planID, date string) (bool, error)
+	DeleteRecurringPlan(ctx context.Context, planID string) error
+	GetCustomerRecurringPlan(ctx context.Context, customerID, planID string) (*models.RecurringOrderPlan, error)
+	GetCustomerRecurringPlans(ctx context.Context, customerID string) ([]*models.RecurringOrderPlan, error)
+	GetDueRecurringPlans(ctx context.Context, throughDate string) ([]*models.RecurringOrderPlan, error)
+	GetRecurringPlanOrders(ctx context.Context, planID string) ([]*models.ServiceOrderNew, error)
+
+	CreateReschedule(ctx context.Context, reschedule *models.OrderReschedule) error
+	UpdateReschedule(ctx context.Context, reschedule *models.OrderReschedule) error
+	GetOrderReschedule(ctx context.Context, orderID, rescheduleID string) (*models.OrderReschedule, error)
+	GetPendingReschedule(ctx context.Context, orderID string) (*models.OrderReschedule, error)
+	GetOrderReschedules(ctx context.Context, orderID string) ([]*models.OrderReschedule, error)
+	WithdrawPendingReschedules(ctx context.Context, orderID string) error
+
+	UpdateAdjustment(ctx context.Context, adjustment *models.OrderAdjustment) error
+	GetOrderAdjustment(ctx context.Context, orderID, adjustmentID string) (*models.OrderAdjustment, error)
+	GetOrderAdjustments(ctx context.Context, orderID string) ([]*models.OrderAdjustment, error)
+	WithdrawPendingAdjustments(ctx context.Context, orderID string) error
+	ExpireOpenCrew(ctx context.Context, orderID string) error
+	CreateNoShowReport(ctx context.Context, report *models.NoShowReport) error
 }
 
 type CategoryInfo struct {
@@ -60,7 +110,6 @@ func NewRepository(db *gorm.DB) Repository {
 	return &repository{db: db}
 }
 
-
 func (r *repository) GetActiveServiceBySlug(ctx context.Context, slug string) (*models.ServiceNew, error) {
 	var service models.ServiceNew
 	err := r.db.WithContext(ctx).
@@ -76,7 +125,7 @@ func (r *repository) ListActiveServices(ctx context.Context, query dto.ListServi
 	var services []*models.ServiceNew
 	var total int64
 
-	db := r.db.WithContext(ctx).Model(&models.ServiceNew{}).
+	db := r.db.WithContext(ctx).Scopes(database.ReadReplica).Model(&models.ServiceNew{}).
 		Where("is_active = true AND is_available = true")
 
 	if query.CategorySlug != "" {
@@ -125,7 +174,7 @@ func (r *repository) ListActiveServices(ctx context.Context, query dto.ListServi
 
 func (r *repository) GetActiveServicesByCategory(ctx context.Context, categorySlug string) ([]*models.ServiceNew, error) {
 	var services []*models.ServiceNew
-	err := r.db.WithContext(ctx).
+	err := r.db.WithContext(ctx).Scopes(database.ReadReplica).
 		Where("category_slug = ? AND is_active = true AND is_available = true", categorySlug).
 		Order("sort_order ASC, title ASC").
 		Find(&services).Error
@@ -134,7 +183,7 @@ func (r *repository) GetActiveServicesByCategory(ctx context.Context, categorySl
 
 func (r *repository) CountActiveServicesByCategory(ctx context.Context, categorySlug string) (int64, error) {
 	var count int64
-	err := r.db.WithContext(ctx).
+	err := r.db.WithContext(ctx).Scopes(database.ReadReplica).
 		Model(&models.ServiceNew{}).
 		Where("category_slug = ? AND is_active = true AND is_available = true", categorySlug).
 		Count(&count).Error
@@ -143,7 +192,7 @@ func (r *repository) CountActiveServicesByCategory(ctx context.Context, category
 
 func (r *repository) GetFrequentServices(ctx context.Context, limit int) ([]*models.ServiceNew, error) {
 	var services []*models.ServiceNew
-	err := r.db.WithContext(ctx).
+	err := r.db.WithContext(ctx).Scopes(database.ReadReplica).
 		Where("is_frequent = true AND is_active = true AND is_available = true").
 		Order("sort_order ASC").
 		Limit(limit).
@@ -162,11 +211,31 @@ func (r *repository) GetActiveAddonBySlug(ctx context.Context, slug string) (*mo
 	return &addon, nil
 }
 
+func (r *repository) GetAddonCompatibility(ctx context.Context, addonSlugs []string) ([]models.AddonServiceCompatibility, error) {
+	var rules []models.AddonServiceCompatibility
+	if len(addonSlugs) == 0 {
+		return rules, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("addon_slug IN ?", addonSlugs).
+		Find(&rules).Error
+	return rules, err
+}
+
+func (r *repository) GetActiveSurcharges(ctx context.Context, categorySlug string) ([]*models.CategorySurcharge, error) {
+	var surcharges []*models.CategorySurcharge
+	err := r.db.WithContext(ctx).
+		Where("category_slug = ? AND is_active = true", categorySlug).
+		Order("code ASC").
+		Find(&surcharges).Error
+	return surcharges, err
+}
+
 func (r *repository) ListActiveAddons(ctx context.Context, query dto.ListAddonsQuery) ([]*models.Addon, int64, error) {
 	var addons []*models.Addon
 	var total int64
 
-	db := r.db.WithContext(ctx).Model(&models.Addon{}).
+	db := r.db.WithContext(ctx).Scopes(database.ReadReplica).Model(&models.Addon{}).
 		Where("is_active = true AND is_available = true")
 
 	if query.CategorySlug != "" {
@@ -212,7 +281,7 @@ func (r *repository) ListActiveAddons(ctx context.Context, query dto.ListAddonsQ
 
 func (r *repository) GetActiveAddonsByCategory(ctx context.Context, categorySlug string) ([]*models.Addon, error) {
 	var addons []*models.Addon
-	err := r.db.WithContext(ctx).
+	err := r.db.WithContext(ctx).Scopes(database.ReadReplica).
 		Where("category_slug = ? AND is_active = true AND is_available = true", categorySlug).
 		Order("sort_order ASC, title ASC").
 		Find(&addons).Error
@@ -221,7 +290,7 @@ func (r *repository) GetActiveAddonsByCategory(ctx context.Context, categorySlug
 
 func (r *repository) CountActiveAddonsByCategory(ctx context.Context, categorySlug string) (int64, error) {
 	var count int64
-	err := r.db.WithContext(ctx).
+	err := r.db.WithContext(ctx).Scopes(database.ReadReplica).
 		Model(&models.Addon{}).
 		Where("category_slug = ? AND is_active = true AND is_available = true", categorySlug).
 		Count(&count).Error
@@ -230,7 +299,7 @@ func (r *repository) CountActiveAddonsByCategory(ctx context.Context, categorySl
 
 func (r *repository) GetDiscountedAddons(ctx context.Context, limit int) ([]*models.Addon, error) {
 	var addons []*models.Addon
-	err := r.db.WithContext(ctx).
+	err := r.db.WithContext(ctx).Scopes(database.ReadReplica).
 		Where("is_active = true AND is_available = true AND strikethrough_price IS NOT NULL AND strikethrough_price > price").
 		Order("(strikethrough_price - price) / strikethrough_price DESC"). // Order by discount percentage
 		Limit(limit).
@@ -246,7 +315,7 @@ func (r *repository) GetAllActiveCategories(ctx context.Context) ([]CategoryInfo
 		Count        int64
 	}
 	var serviceCounts []categoryCount
-	err := r.db.WithContext(ctx).
+	err := r.db.WithContext(ctx).Scopes(database.ReadReplica).
 		Model(&models.ServiceNew{}).
 		Select("category_slug, COUNT(*) as count").
 		Where("is_active = true AND is_available = true").
@@ -257,7 +326,7 @@ func (r *repository) GetAllActiveCategories(ctx context.Context) ([]CategoryInfo
 	}
 
 	var addonCounts []categoryCount
-	err = r.db.WithContext(ctx).
+	err = r.db.WithContext(ctx).Scopes(database.ReadReplica).
 		Model(&models.Addon{}).
 		Select("category_slug, COUNT(*) as count").
 		Where("is_active = true AND is_available = true").
@@ -290,41 +359,120 @@ func (r *repository) GetAllActiveCategories(ctx context.Context) ([]CategoryInfo
 	return categoryInfos, nil
 }
 
-func (r *repository) SearchServices(ctx context.Context, query string, categorySlug string, limit int) ([]*models.ServiceNew, error) {
-	var services []*models.ServiceNew
-	searchPattern := "%" + strings.ToLower(query) + "%"
-
-	db := r.db.WithContext(ctx).
-		Where("is_active = true AND is_available = true").
-		Where("LOWER(title) LIKE ? OR LOWER(description) LIKE ? OR LOWER(long_description) LIKE ?",
-			searchPattern, searchPattern, searchPattern)
+// SearchHit is one ranked service or add-on matched by Search.
+type SearchHit struct {
+	Type         string
+	ID           string
+	Title        string
+	Slug         string
+	CategorySlug string
+	Description  string
+	Image        string
+	Price        *float64
+	Duration     *int
+	Rank         float64
+}
 
-	if categorySlug != "" {
-		db = db.Where("category_slug = ?", categorySlug)
+// searchSimilarity is the minimum pg_trgm word similarity between the query
+// and a title for a fuzzy match, enough to absorb a typo or two.
+const searchSimilarity = 0.35
+
+// Search ranks active services and add-ons by full-text relevance plus title
+// similarity, so misspelt queries still find close titles. tsQuery is a
+// to_tsquery expression; the raw query text drives the fuzzy match. The
+// catalog is small enough that the similarity scan needs no index.
+func (r *repository) Search(ctx context.Context, query dto.SearchQuery, tsQuery string) ([]*SearchHit, int64, error) {
+	term := strings.ToLower(query.Query)
+	rank := "ts_rank_cd(search_vector, to_tsquery('english', ?)) + word_similarity(?, lower(title))"
+	match := "(search_vector @@ to_tsquery('english', ?) OR word_similarity(?, lower(title)) >= ?)"
+
+	var parts []interface{}
+	if query.Type != "addon" {
+		services := r.db.Model(&models.ServiceNew{}).
+			Select("'service' AS type, id, title, service_slug AS slug, category_slug, description, "+
+				"thumbnail AS image, base_price AS price, duration, "+rank+" AS rank", tsQuery, term).
+			Where("is_active = true AND is_available = true").
+			Where(match, tsQuery, term, searchSimilarity)
+		if query.CategorySlug != "" {
+			services = services.Where("category_slug = ?", query.CategorySlug)
+		}
+		if query.MinPrice != nil {
+			services = services.Where("base_price >= ?", *query.MinPrice)
+		}
+		if query.MaxPrice != nil {
+			services = services.Where("base_price <= ?", *query.MaxPrice)
+		}
+		if query.MinDuration != nil {
+			services = services.Where("duration >= ?", *query.MinDuration)
+		}
+		if query.MaxDuration != nil {
+			services = services.Where("duration <= ?", *query.MaxDuration)
+		}
+		parts = append(parts, services)
 	}
 
-	err := db.Order("sort_order ASC").Limit(limit).Find(&services).Error
-	return services, err
-}
+	if query.Type != "service" && !query.FiltersDuration() {
+		addons := r.db.Model(&models.Addon{}).
+			Select("'addon' AS type, id, title, addon_slug AS slug, category_slug, description, "+
+				"image, price, NULL::integer AS duration, "+rank+" AS rank", tsQuery, term).
+			Where("is_active = true AND is_available = true").
+			Where(match, tsQuery, term, searchSimilarity)
+		if query.CategorySlug != "" {
+			addons = addons.Where("category_slug = ?", query.CategorySlug)
+		}
+		if query.MinPrice != nil {
+			addons = addons.Where("price >= ?", *query.MinPrice)
+		}
+		if query.MaxPrice != nil {
+			addons = addons.Where("price <= ?", *query.MaxPrice)
+		}
+		parts = append(parts, addons)
+	}
 
-func (r *repository) SearchAddons(ctx context.Context, query string, categorySlug string, limit int) ([]*models.Addon, error) {
-	var addons []*models.Addon
-	searchPattern := "%" + strings.ToLower(query) + "%"
+	if len(parts) == 0 {
+		return []*SearchHit{}, 0, nil
+	}
 
-	db := r.db.WithContext(ctx).
-		Where("is_active = true AND is_available = true").
-		Where("LOWER(title) LIKE ? OR LOWER(description) LIKE ?", searchPattern, searchPattern)
+	union := r.db.Raw(strings.TrimSuffix(strings.Repeat("? UNION ALL ", len(parts)), " UNION ALL "), parts...)
+	db := r.db.WithContext(ctx).Scopes(database.ReadReplica).Table("(?) AS results", union)
 
-	if categorySlug != "" {
-		db = db.Where("category_slug = ?", categorySlug)
+	var total int64
+	if err := db.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
 	}
 
-	err := db.Order("sort_order ASC").Limit(limit).Find(&addons).Error
-	return addons, err
+	var hits []*SearchHit
+	err := db.Order("rank DESC, title ASC").
+		Offset(query.GetOffset()).
+		Limit(query.Limit).
+		Scan(&hits).Error
+	return hits, total, err
 }
 
+// Create inserts the order together with the accounting lines for its surcharges.
 func (r *repository) Create(ctx context.Context, order *models.ServiceOrderNew) error {
-	return r.db.WithContext(ctx).Create(order).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(order).Error; err != nil {
+			return err
+		}
+		if len(order.Surcharges) == 0 {
+			return nil
+		}
+
+		lines := make([]*models.ServiceOrderSurcharge, len(order.Surcharges))
+		for i, item := range order.Surcharges {
+			lines[i] = &models.ServiceOrderSurcharge{
+				OrderID:      order.ID,
+				SurchargeID:  item.SurchargeID,
+				CategorySlug: order.CategorySlug,
+				Code:         item.Code,
+				Name:         item.Name,
+				Kind:         item.Kind,
+				Amount:       item.Amount,
+			}
+		}
+		return tx.Create(&lines).Error
+	})
 }
 
 func (r *repository) GetByID(ctx context.Context, id string) (*models.ServiceOrderNew, error) {
@@ -345,15 +493,17 @@ func (r *repository) GetByOrderNumber(ctx context.Context, orderNumber string) (
 	return &order, nil
 }
 
+// Update returns shared.ErrOrderConflict if the order changed since it was
+// read.
 func (r *repository) Update(ctx context.Context, order *models.ServiceOrderNew) error {
-	return r.db.WithContext(ctx).Model(&models.ServiceOrderNew{}).Where("id = ?", order.ID).Updates(order).Error
+	return shared.SaveOrderVersioned(r.db.WithContext(ctx), order)
 }
 
 func (r *repository) GetCustomerOrders(ctx context.Context, customerID string, query dto.ListOrdersQuery) ([]*models.ServiceOrderNew, int64, error) {
 	var orders []*models.ServiceOrderNew
 	var total int64
 
-	db := r.db.WithContext(ctx).Model(&models.ServiceOrderNew{}).
+	db := archive.WithArchived(r.db.WithContext(ctx).Model(&models.ServiceOrderNew{}), "service_orders").
 		Where("customer_id = ?", customerID)
 
 	if query.Status != "" {
@@ -405,6 +555,18 @@ func (r *repository) GetCustomerOrderByID(ctx context.Context, customerID, order
 	return &order, nil
 }
 
+func (r *repository) GetArchivedCustomerOrderByID(ctx context.Context, customerID, orderID string) (*models.ServiceOrderNew, error) {
+	var order models.ServiceOrderNew
+	err := r.db.WithContext(ctx).
+		Table(archive.ServiceOrdersArchiveTable).
+		Where("id = ? AND customer_id = ?", orderID, customerID).
+		First(&order).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
 func (r *repository) CountCustomerActiveOrders(ctx context.Context, customerID string) (int64, error) {
 	var count int64
 	err := r.db.WithContext(ctx).
@@ -417,6 +579,7 @@ func (r *repository) CountCustomerActiveOrders(ctx context.Context, customerID s
 func (r *repository) UpdateStatus(ctx context.Context, orderID, status string) error {
 	updates := map[string]interface{}{
 		"status":     status,
+		"version":    gorm.Expr("version + 1"),
 		"updated_at": time.Now(),
 	}
 
@@ -447,3 +610,344 @@ func (r *repository) GetOrderStatusHistory(ctx context.Context, orderID string)
 func (r *repository) Delete(ctx context.Context, orderID string) error {
 	return r.db.WithContext(ctx).Where("id = ?", orderID).Delete(&models.ServiceOrderNew{}).Error
 }
+
+func (r *repository) CreateSessions(ctx context.Context, sessions []*models.ServiceOrderSession) error {
+	return r.db.WithContext(ctx).Create(&sessions).Error
+}
+
+func (r *repository) GetOrderSessions(ctx context.Context, orderID string) ([]*models.ServiceOrderSession, error) {
+	var sessions []*models.ServiceOrderSession
+	err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("session_number ASC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+func (r *repository) UpdateSession(ctx context.Context, session *models.ServiceOrderSession) error {
+	return r.db.WithContext(ctx).Save(session).Error
+}
+
+// ClaimSessionApproval marks the session approved only if it is still
+// awaiting approval, so a milestone is released by one request at most.
+func (r *repository) ClaimSessionApproval(ctx context.Context, session *models.ServiceOrderSession) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.ServiceOrderSession{}).
+		Where("id = ? AND status = ?", session.ID, models.SessionStatusAwaitingApproval).
+		Updates(map[string]interface{}{
+			"status":               models.SessionStatusApproved,
+			"customer_approved_at": session.CustomerApprovedAt,
+			"customer_feedback":    session.CustomerFeedback,
+			"provider_payout":      session.ProviderPayout,
+			"updated_at":           time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ReleaseSessionApproval puts a claimed session back to awaiting approval
+// when its milestone could not be released.
+func (r *repository) ReleaseSessionApproval(ctx context.Context, sessionID string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.ServiceOrderSession{}).
+		Where("id = ? AND status = ?", sessionID, models.SessionStatusApproved).
+		Updates(map[string]interface{}{
+			"status":               models.SessionStatusAwaitingApproval,
+			"customer_approved_at": nil,
+			"provider_payout":      0,
+			"updated_at":           time.Now(),
+		}).Error
+}
+
+func (r *repository) CancelOpenSessions(ctx context.Context, orderID string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.ServiceOrderSession{}).
+		Where("order_id = ? AND status <> ?", orderID, models.SessionStatusApproved).
+		Update("status", models.SessionStatusCancelled).Error
+}
+
+func (r *repository) GetProviderProfile(ctx context.Context, providerID string) (*models.ServiceProviderProfile, error) {
+	var provider models.ServiceProviderProfile
+	err := r.db.WithContext(ctx).Where("id = ?", providerID).First(&provider).Error
+	if err != nil {
+		return nil, err
+	}
+	return &provider, nil
+}
+
+func (r *repository) IncrementProviderCompletedJobs(ctx context.Context, providerID, categorySlug string, earnings float64) error {
+	return r.db.WithContext(ctx).
+		Model(&models.ProviderServiceCategory{}).
+		Where("provider_id = ? AND category_slug = ?", providerID, categorySlug).
+		Updates(map[string]interface{}{
+			"completed_jobs": gorm.Expr("completed_jobs + 1"),
+			"total_earnings": gorm.Expr("total_earnings + ?", earnings),
+		}).Error
+}
+
+func (r *repository) CreateQuote(ctx context.Context, quote *models.OrderQuote) error {
+	return r.db.WithContext(ctx).Create(quote).Error
+}
+
+func (r *repository) GetQuoteByID(ctx context.Context, quoteID string) (*models.OrderQuote, error) {
+	var quote models.OrderQuote
+	err := r.db.WithContext(ctx).Where("id = ?", quoteID).First(&quote).Error
+	if err != nil {
+		return nil, err
+	}
+	return &quote, nil
+}
+
+func (r *repository) GetCustomerQuoteByID(ctx context.Context, customerID, quoteID string) (*models.OrderQuote, error) {
+	var quote models.OrderQuote
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND customer_id = ?", quoteID, customerID).
+		First(&quote).Error
+	if err != nil {
+		return nil, err
+	}
+	return &quote, nil
+}
+
+func (r *repository) GetCustomerQuotes(ctx context.Context, customerID string, query dto.ListQuotesQuery) ([]*models.OrderQuote, int64, error) {
+	var quotes []*models.OrderQuote
+	var total int64
+
+	db := r.db.WithContext(ctx).Model(&models.OrderQuote{}).
+		Where("customer_id = ?", customerID)
+
+	if query.Status != "" {
+		db = db.Where("status = ?", query.Status)
+	}
+
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := query.PaginationParams.GetOffset()
+	if err := db.Order("created_at DESC").Offset(offset).Limit(query.Limit).Find(&quotes).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return quotes, total, nil
+}
+
+// MarkQuoteConverted records the order a quote turned into. A quote converts
+// at most once; a second conversion finds no issued row and fails.
+func (r *repository) MarkQuoteConverted(ctx context.Context, quoteID, orderID string) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.OrderQuote{}).
+		Where("id = ? AND status = ?", quoteID, models.OrderQuoteStatusIssued).
+		Updates(map[string]interface{}{
+			"status":             models.OrderQuoteStatusConverted,
+			"converted_order_id": orderID,
+			"converted_at":       time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *repository) TrackQuoteAccess(ctx context.Context, quoteID string, shared bool) error {
+	column := "download_count"
+	if shared {
+		column = "share_count"
+	}
+	return r.db.WithContext(ctx).
+		Model(&models.OrderQuote{}).
+		Where("id = ?", quoteID).
+		Updates(map[string]interface{}{
+			column:             gorm.Expr(column + " + 1"),
+			"last_accessed_at": time.Now(),
+		}).Error
+}
+
+func (r *repository) CreateRecurringPlan(ctx context.Context, plan *models.RecurringOrderPlan) error {
+	return r.db.WithContext(ctx).Create(plan).Error
+}
+
+func (r *repository) ClaimRecurringOccurrence(ctx context.Context, plan, next *models.RecurringOrderPlan) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.RecurringOrderPlan{}).
+		Where("id = ? AND status = ? AND next_occurrence = ?", plan.ID, models.RecurringPlanActive, plan.NextOccurrence).
+		Updates(map[string]interface{}{
+			"next_occurrence": next.NextOccurrence,
+			"next_date":       next.NextDate,
+			"status":          next.Status,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *repository) RecordRecurringOccurrence(ctx context.Context, planID string, booked bool, lastError string) error {
+	updates := map[string]interface{}{"last_error": lastError}
+	if booked {
+		updates["orders_created"] = gorm.Expr("orders_created + 1")
+	}
+	return r.db.WithContext(ctx).
+		Model(&models.RecurringOrderPlan{}).
+		Where("id = ?", planID).
+		Updates(updates).Error
+}
+
+func (r *repository) TransitionRecurringPlan(ctx context.Context, planID string, from []models.RecurringPlanStatus, updates map[string]interface{}) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.RecurringOrderPlan{}).
+		Where("id = ? AND status IN ?", planID, from).
+		Updates(updates)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *repository) AddRecurringSkippedDate(ctx context.Context, planID, date string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.RecurringOrderPlan{}).
+		Where("id = ? AND status IN ? AND next_date <= ?", planID,
+			[]models.RecurringPlanStatus{models.RecurringPlanActive, models.RecurringPlanPaused}, date).
+		Update("skipped_dates", gorm.Expr("array_append(array_remove(skipped_dates, ?), ?)", date, date))
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *repository) DeleteRecurringPlan(ctx context.Context, planID string) error {
+	return r.db.WithContext(ctx).Where("id = ?", planID).Delete(&models.RecurringOrderPlan{}).Error
+}
+
+func (r *repository) GetCustomerRecurringPlan(ctx context.Context, customerID, planID string) (*models.RecurringOrderPlan, error) {
+	var plan models.RecurringOrderPlan
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND customer_id = ?", planID, customerID).
+		First(&plan).Error
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (r *repository) GetCustomerRecurringPlans(ctx context.Context, customerID string) ([]*models.RecurringOrderPlan, error) {
+	var plans []*models.RecurringOrderPlan
+	err := r.db.WithContext(ctx).
+		Where("customer_id = ?", customerID).
+		Order("created_at DESC").
+		Find(&plans).Error
+	return plans, err
+}
+
+// GetDueRecurringPlans returns active plans with an occurrence to book on or
+// before throughDate.
+func (r *repository) GetDueRecurringPlans(ctx context.Context, throughDate string) ([]*models.RecurringOrderPlan, error) {
+	var plans []*models.RecurringOrderPlan
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND next_date <= ?", models.RecurringPlanActive, throughDate).
+		Order("next_date ASC").
+		Find(&plans).Error
+	return plans, err
+}
+
+func (r *repository) GetRecurringPlanOrders(ctx context.Context, planID string) ([]*models.ServiceOrderNew, error) {
+	var orders []*models.ServiceOrderNew
+	err := r.db.WithContext(ctx).
+		Where("recurring_plan_id = ?", planID).
+		Order("booking_info->>'date' ASC").
+		Find(&orders).Error
+	return orders, err
+}
+
+func (r *repository) CreateReschedule(ctx context.Context, reschedule *models.OrderReschedule) error {
+	return r.db.WithContext(ctx).Create(reschedule).Error
+}
+
+func (r *repository) UpdateReschedule(ctx context.Context, reschedule *models.OrderReschedule) error {
+	return r.db.WithContext(ctx).Save(reschedule).Error
+}
+
+func (r *repository) GetOrderReschedule(ctx context.Context, orderID, rescheduleID string) (*models.OrderReschedule, error) {
+	var reschedule models.OrderReschedule
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND order_id = ?", rescheduleID, orderID).
+		First(&reschedule).Error
+	if err != nil {
+		return nil, err
+	}
+	return &reschedule, nil
+}
+
+func (r *repository) GetPendingReschedule(ctx context.Context, orderID string) (*models.OrderReschedule, error) {
+	var reschedule models.OrderReschedule
+	err := r.db.WithContext(ctx).
+		Where("order_id = ? AND status = ?", orderID, models.RescheduleStatusPending).
+		First(&reschedule).Error
+	if err != nil {
+		return nil, err
+	}
+	return &reschedule, nil
+}
+
+func (r *repository) GetOrderReschedules(ctx context.Context, orderID string) ([]*models.OrderReschedule, error) {
+	var reschedules []*models.OrderReschedule
+	err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("created_at DESC").
+		Find(&reschedules).Error
+	return reschedules, err
+}
+
+func (r *repository) WithdrawPendingReschedules(ctx context.Context, orderID string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.OrderReschedule{}).
+		Where("order_id = ? AND status = ?", orderID, models.RescheduleStatusPending).
+		Updates(map[string]interface{}{
+			"status":     models.RescheduleStatusWithdrawn,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+func (r *repository) UpdateAdjustment(ctx context.Context, adjustment *models.OrderAdjustment) error {
+	return r.db.WithContext(ctx).Save(adjustment).Error
+}
+
+func (r *repository) GetOrderAdjustment(ctx context.Context, orderID, adjustmentID string) (*models.OrderAdjustment, error) {
+	var adjustment models.OrderAdjustment
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND order_id = ?", adjustmentID, orderID).
+		First(&adjustment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &adjustment, nil
+}
+
+func (r *repository) GetOrderAdjustments(ctx context.Context, orderID string) ([]*models.OrderAdjustment, error) {
+	var adjustments []*models.OrderAdjustment
+	err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("created_at DESC").
+		Find(&adjustments).Error
+	return adjustments, err
+}
+
+func (r *repository) WithdrawPendingAdjustments(ctx context.Context, orderID string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.OrderAdjustment{}).
+		Where("order_id = ? AND status = ?", orderID, models.AdjustmentStatusPending).
+		Updates(map[string]interface{}{
+			"status":     models.AdjustmentStatusWithdrawn,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// ExpireOpenCrew releases the crew members still invited to or working on an
+// order that will not go ahead.
+func (r *repository) ExpireOpenCrew(ctx context.Context, orderID string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.OrderCrewMember{}).
+		Where("order_id = ? AND status IN ?", orderID, []models.OrderCrewStatus{models.CrewStatusInvited, models.CrewStatusAccepted}).
+		Updates(map[string]interface{}{
+			"status":     models.CrewStatusExpired,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+func (r *repository) CreateNoShowReport(ctx context.Context, report *models.NoShowReport) error {
+	return r.db.WithContext(ctx).Create(report).Error
+}
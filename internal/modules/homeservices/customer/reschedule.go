@@ -0,0 +1,191 @@
+package customer
+
+import (
+	"context"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/customer/dto"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+	websocketutil "github.com/umar5678/go-backend/internal/websocket/websocketutils"
+	"gorm.io/gorm"
+)
+
+func (s *service) ConfigureRescheduling(cfg config.RescheduleConfig) {
+	s.reschedule = cfg
+}
+
+func (s *service) loadCustomerOrder(ctx context.Context, customerID, orderID string) (*models.ServiceOrderNew, error) {
+	order, err := s.repo.GetCustomerOrderByID(ctx, customerID, orderID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, response.NotFoundError("Order")
+		}
+		return nil, response.InternalServerError("Failed to get order", err)
+	}
+	return order, nil
+}
+
+// RescheduleOrder moves a booking to a new slot. Before a provider is
+// assigned the move applies at once; afterwards the provider has to confirm
+// it and the order keeps its current slot until they do.
+func (s *service) RescheduleOrder(ctx context.Context, customerID, orderID string, req dto.RescheduleOrderRequest) (*shared.RescheduleResponse, error) {
+	order, err := s.loadCustomerOrder(ctx, customerID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := shared.ValidateReschedule(order, req.Date, req.Time, s.reschedule, time.Now()); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetPendingReschedule(ctx, order.ID); err == nil {
+		return nil, response.ConflictError("A reschedule request for this order is already waiting for an answer")
+	}
+
+	reschedule := shared.NewOrderReschedule(order, shared.RoleCustomer, customerID, req.Date, req.Time, req.Reason)
+
+	if order.AssignedProviderID == nil {
+		shared.ApplyReschedule(order, reschedule)
+		if err := s.repo.Update(ctx, order); err != nil {
+			logger.Error("failed to reschedule order", "error", err, "orderID", order.ID)
+			return nil, shared.OrderUpdateError(err, "Failed to reschedule order")
+		}
+		if err := s.repo.CreateReschedule(ctx, reschedule); err != nil {
+			logger.Error("failed to record reschedule", "error", err, "orderID", order.ID)
+		}
+		s.rescheduleApplied(ctx, order, reschedule, customerID)
+		return shared.ToRescheduleResponse(reschedule), nil
+	}
+
+	if err := s.repo.CreateReschedule(ctx, reschedule); err != nil {
+		logger.Error("failed to create reschedule request", "error", err, "orderID", order.ID)
+		return nil, response.InternalServerError("Failed to request reschedule", err)
+	}
+
+	s.notifyProvider(ctx, order, websocket.TypeOrderRescheduleRequested, shared.RescheduleNotification(order, reschedule))
+
+	logger.Info("order reschedule requested", "orderID", order.ID, "rescheduleID", reschedule.ID,
+		"newDate", reschedule.NewDate, "newTime", reschedule.NewTime)
+
+	return shared.ToRescheduleResponse(reschedule), nil
+}
+
+func (s *service) GetOrderReschedules(ctx context.Context, customerID, orderID string) (*shared.OrderReschedulesResponse, error) {
+	order, err := s.loadCustomerOrder(ctx, customerID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	reschedules, err := s.repo.GetOrderReschedules(ctx, order.ID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get reschedules", err)
+	}
+	return shared.ToOrderReschedulesResponse(order, reschedules), nil
+}
+
+// AcceptReschedule agrees to a new slot the provider proposed.
+func (s *service) AcceptReschedule(ctx context.Context, customerID, orderID, rescheduleID string, req dto.RespondRescheduleRequest) (*shared.RescheduleResponse, error) {
+	order, reschedule, err := s.loadProviderProposal(ctx, customerID, orderID, rescheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if err := shared.ValidateReschedule(order, reschedule.NewDate, reschedule.NewTime, s.reschedule, time.Now()); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	shared.ApplyReschedule(order, reschedule)
+	reschedule.ResponseNote = req.Note
+	reschedule.RespondedAt = &now
+
+	if err := s.repo.Update(ctx, order); err != nil {
+		logger.Error("failed to reschedule order", "error", err, "orderID", order.ID)
+		return nil, shared.OrderUpdateError(err, "Failed to reschedule order")
+	}
+	if err := s.repo.UpdateReschedule(ctx, reschedule); err != nil {
+		logger.Error("failed to update reschedule", "error", err, "rescheduleID", reschedule.ID)
+	}
+
+	s.rescheduleApplied(ctx, order, reschedule, customerID)
+	return shared.ToRescheduleResponse(reschedule), nil
+}
+
+// DeclineReschedule turns down a provider's proposal; the booking keeps its
+// current slot.
+func (s *service) DeclineReschedule(ctx context.Context, customerID, orderID, rescheduleID string, req dto.RespondRescheduleRequest) (*shared.RescheduleResponse, error) {
+	order, reschedule, err := s.loadProviderProposal(ctx, customerID, orderID, rescheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	reschedule.Status = models.RescheduleStatusDeclined
+	reschedule.ResponseNote = req.Note
+	reschedule.RespondedAt = &now
+	if err := s.repo.UpdateReschedule(ctx, reschedule); err != nil {
+		return nil, response.InternalServerError("Failed to decline reschedule", err)
+	}
+
+	s.notifyProvider(ctx, order, websocket.TypeOrderRescheduleDeclined, shared.RescheduleNotification(order, reschedule))
+
+	logger.Info("order reschedule declined", "orderID", order.ID, "rescheduleID", reschedule.ID, "by", shared.RoleCustomer)
+
+	return shared.ToRescheduleResponse(reschedule), nil
+}
+
+func (s *service) loadProviderProposal(ctx context.Context, customerID, orderID, rescheduleID string) (*models.ServiceOrderNew, *models.OrderReschedule, error) {
+	order, err := s.loadCustomerOrder(ctx, customerID, orderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	reschedule, err := s.repo.GetOrderReschedule(ctx, order.ID, rescheduleID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, response.NotFoundError("Reschedule request")
+		}
+		return nil, nil, response.InternalServerError("Failed to get reschedule request", err)
+	}
+	if reschedule.RequestedBy != shared.RoleProvider {
+		return nil, nil, response.BadRequest("You cannot answer your own reschedule request")
+	}
+	if reschedule.Status != models.RescheduleStatusPending {
+		return nil, nil, response.BadRequest("This reschedule request has already been answered")
+	}
+	return order, reschedule, nil
+}
+
+// rescheduleApplied does the bookkeeping after an order moved: the payment
+// hold is kept until the new slot, the move is recorded in the status
+// history and the provider, if any, is told.
+func (s *service) rescheduleApplied(ctx context.Context, order *models.ServiceOrderNew, reschedule *models.OrderReschedule, customerID string) {
+	if order.WalletHoldID != nil && order.ProviderAcceptedAt == nil {
+		if until, err := shared.ParseBookingDateTime(order.BookingInfo.Date, order.BookingInfo.Time); err == nil {
+			if err := s.walletService.ExtendHold(ctx, order.CustomerID, *order.WalletHoldID, until); err != nil {
+				logger.Error("failed to extend wallet hold", "error", err, "orderID", order.ID, "holdID", *order.WalletHoldID)
+			}
+		}
+	}
+
+	s.repo.CreateStatusHistory(ctx, shared.RescheduleHistory(order, reschedule, customerID, shared.RoleCustomer))
+
+	s.notifyProvider(ctx, order, websocket.TypeOrderRescheduled, shared.RescheduleNotification(order, reschedule))
+
+	logger.Info("order rescheduled", "orderID", order.ID, "rescheduleID", reschedule.ID,
+		"newDate", order.BookingInfo.Date, "newTime", order.BookingInfo.Time, "rescheduleCount", order.RescheduleCount)
+}
+
+func (s *service) notifyProvider(ctx context.Context, order *models.ServiceOrderNew, messageType websocket.MessageType, data map[string]interface{}) {
+	if order.AssignedProviderID == nil {
+		return
+	}
+	provider, err := s.repo.GetProviderProfile(ctx, *order.AssignedProviderID)
+	if err != nil {
+		logger.Warn("failed to load provider for notification", "error", err, "orderID", order.ID)
+		return
+	}
+	if err := websocketutil.SendToUser(provider.UserID, messageType, data); err != nil {
+		logger.Warn("failed to notify provider", "error", err, "orderID", order.ID, "type", messageType)
+	}
+}
@@ -39,11 +39,50 @@ func RegisterRoutes(
 		orders.Use(authMiddleware)
 		{
 			orders.POST("", handler.CreateOrder)
+			orders.POST("/preview", handler.PreviewOrder)
 			orders.GET("", handler.ListOrders)
 			orders.GET("/:id", handler.GetOrder)
+			orders.GET("/:id/fee-breakdown", handler.GetOrderFeeBreakdown)
 			orders.GET("/:id/cancel/preview", handler.GetCancellationPreview)
 			orders.POST("/:id/cancel", handler.CancelOrder)
+			orders.POST("/:id/no-show", handler.ReportProviderNoShow)
 			orders.POST("/:id/rate", handler.RateOrder)
+
+			orders.GET("/:id/sessions", handler.GetOrderSessions)
+			orders.POST("/:id/sessions/:sessionId/approve", handler.ApproveSession)
+			orders.POST("/:id/sessions/:sessionId/request-changes", handler.RequestSessionChanges)
+
+			orders.POST("/:id/reschedule", handler.RescheduleOrder)
+			orders.GET("/:id/reschedules", handler.GetOrderReschedules)
+			orders.POST("/:id/reschedules/:rescheduleId/accept", handler.AcceptReschedule)
+			orders.POST("/:id/reschedules/:rescheduleId/decline", handler.DeclineReschedule)
+
+			orders.GET("/:id/adjustments", handler.GetOrderAdjustments)
+			orders.POST("/:id/adjustments/:adjustmentId/approve", handler.ApproveAdjustment)
+			orders.POST("/:id/adjustments/:adjustmentId/reject", handler.RejectAdjustment)
+		}
+
+		recurring := homeservices.Group("/recurring")
+		recurring.Use(authMiddleware)
+		{
+			recurring.GET("", handler.ListRecurringPlans)
+			recurring.GET("/:id", handler.GetRecurringPlan)
+			recurring.POST("/:id/skip", handler.SkipOccurrence)
+			recurring.POST("/:id/pause", handler.PauseRecurringPlan)
+			recurring.POST("/:id/resume", handler.ResumeRecurringPlan)
+			recurring.POST("/:id/cancel", handler.CancelRecurringPlan)
+		}
+
+		homeservices.GET("/quotes/shared/:id/pdf", handler.DownloadSharedQuote)
+
+		quotes := homeservices.Group("/quotes")
+		quotes.Use(authMiddleware)
+		{
+			quotes.POST("", handler.CreateQuote)
+			quotes.GET("", handler.ListQuotes)
+			quotes.GET("/:id", handler.GetQuote)
+			quotes.GET("/:id/pdf", handler.DownloadQuote)
+			quotes.POST("/:id/share", handler.ShareQuote)
 		}
 	}
 }
@@ -3,15 +3,22 @@ package customer
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
+	"unicode"
 
+	"github.com/umar5678/go-backend/internal/config"
 	"github.com/umar5678/go-backend/internal/models"
 	"github.com/umar5678/go-backend/internal/modules/homeservices/customer/dto"
 	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+	"github.com/umar5678/go-backend/internal/modules/pricing"
+	pricingdto "github.com/umar5678/go-backend/internal/modules/pricing/dto"
 	"github.com/umar5678/go-backend/internal/modules/wallet"
 	walletdto "github.com/umar5678/go-backend/internal/modules/wallet/dto"
+	"github.com/umar5678/go-backend/internal/services/livemetrics"
 	"github.com/umar5678/go-backend/internal/utils/logger"
 	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/utils/translit"
 	"gorm.io/gorm"
 )
 
@@ -85,18 +92,78 @@ type Service interface {
 
 	CreateOrder(ctx context.Context, customerID string, req dto.CreateOrderRequest) (*dto.OrderCreatedResponse, error)
 	GetOrder(ctx context.Context, customerID, orderID string) (*dto.OrderResponse, error)
+	GetOrderFeeBreakdown(ctx context.Context, customerID, orderID string) (*pricingdto.FeeBreakdownResponse, error)
 	ListOrders(ctx context.Context, customerID string, query dto.ListOrdersQuery) ([]dto.OrderListResponse, *response.PaginationMeta, error)
 
+	PreviewOrder(ctx context.Context, req dto.PreviewOrderRequest) (*dto.OrderPreviewResponse, error)
 	GetCancellationPreview(ctx context.Context, customerID, orderID string) (*dto.CancellationPreviewResponse, error)
 	CancelOrder(ctx context.Context, customerID, orderID string, req dto.CancelOrderRequest) (*dto.OrderResponse, error)
+	ReportProviderNoShow(ctx context.Context, customerID, orderID string, req dto.ReportNoShowRequest) (*dto.NoShowReportResponse, error)
 
 	RateOrder(ctx context.Context, customerID, orderID string, req dto.RateOrderRequest) (*dto.OrderResponse, error)
+
+	GetOrderSessions(ctx context.Context, customerID, orderID string) (*shared.OrderSessionsResponse, error)
+	ApproveSession(ctx context.Context, customerID, orderID, sessionID string, req dto.ApproveSessionRequest) (*shared.OrderSessionsResponse, error)
+	RequestSessionChanges(ctx context.Context, customerID, orderID, sessionID string, req dto.RequestSessionChangesRequest) (*shared.OrderSessionsResponse, error)
+
+	CreateQuote(ctx context.Context, customerID string, req dto.PreviewOrderRequest) (*dto.QuoteResponse, error)
+	ListQuotes(ctx context.Context, customerID string, query dto.ListQuotesQuery) ([]*dto.QuoteResponse, *response.PaginationMeta, error)
+	GetQuote(ctx context.Context, customerID, quoteID string) (*dto.QuoteResponse, error)
+	GetQuotePDF(ctx context.Context, customerID, quoteID string) ([]byte, string, error)
+	CreateQuoteShareLink(ctx context.Context, customerID, quoteID string) (*dto.QuoteShareLinkResponse, error)
+	GetSharedQuotePDF(ctx context.Context, quoteID, expires, signature string) ([]byte, string, error)
+
+	SetTaxCalculator(calculator TaxCalculator)
+	SetCurrencies(currencies CurrencyResolver)
+	SetServiceAreas(areas ServiceAreas)
+	SetCommissionRates(commissionRates CommissionRates)
+	SetRatingAggregator(aggregator RatingAggregator)
+	SetCancellationPolicies(policies CancellationPolicies)
+	SetAddressBook(book AddressBook)
+	SetAddressNormalizer(normalizer AddressNormalizer)
+	SetWebhookPublisher(publisher shared.WebhookPublisher)
+	SetEventPublisher(publisher shared.EventPublisher)
+	ConfigureQuotes(cfg config.QuotesConfig, issuer config.ReceiptsConfig)
+	ConfigureRecurringOrders(cfg config.RecurringOrdersConfig)
+	ConfigureRescheduling(cfg config.RescheduleConfig)
+
+	ListRecurringPlans(ctx context.Context, customerID string) ([]*dto.RecurringPlanResponse, error)
+	GetRecurringPlan(ctx context.Context, customerID, planID string) (*dto.RecurringPlanResponse, error)
+	SkipOccurrence(ctx context.Context, customerID, planID string, req dto.SkipOccurrenceRequest) (*dto.RecurringPlanResponse, error)
+	PauseRecurringPlan(ctx context.Context, customerID, planID string) (*dto.RecurringPlanResponse, error)
+	ResumeRecurringPlan(ctx context.Context, customerID, planID string) (*dto.RecurringPlanResponse, error)
+	CancelRecurringPlan(ctx context.Context, customerID, planID string) (*dto.RecurringPlanResponse, error)
+	BookDueOccurrences(ctx context.Context) (int, error)
+
+	RescheduleOrder(ctx context.Context, customerID, orderID string, req dto.RescheduleOrderRequest) (*shared.RescheduleResponse, error)
+	GetOrderReschedules(ctx context.Context, customerID, orderID string) (*shared.OrderReschedulesResponse, error)
+	AcceptReschedule(ctx context.Context, customerID, orderID, rescheduleID string, req dto.RespondRescheduleRequest) (*shared.RescheduleResponse, error)
+	DeclineReschedule(ctx context.Context, customerID, orderID, rescheduleID string, req dto.RespondRescheduleRequest) (*shared.RescheduleResponse, error)
+
+	GetOrderAdjustments(ctx context.Context, customerID, orderID string) (*shared.OrderAdjustmentsResponse, error)
+	ApproveAdjustment(ctx context.Context, customerID, orderID, adjustmentID string, req dto.RespondAdjustmentRequest) (*shared.AdjustmentResponse, error)
+	RejectAdjustment(ctx context.Context, customerID, orderID, adjustmentID string, req dto.RespondAdjustmentRequest) (*shared.AdjustmentResponse, error)
 }
 
 type service struct {
 	repo          Repository
 	serviceRepo   Repository
 	walletService wallet.Service
+	taxCalculator TaxCalculator
+	currencies    CurrencyResolver
+	quotes        config.QuotesConfig
+	quoteIssuer   config.ReceiptsConfig
+	recurring     config.RecurringOrdersConfig
+	reschedule    config.RescheduleConfig
+
+	cancellationPolicies CancellationPolicies
+	serviceAreas         ServiceAreas
+	commissionRates      CommissionRates
+	ratingAggregator     RatingAggregator
+	addressBook          AddressBook
+	addressNormalizer    AddressNormalizer
+	webhooks             shared.WebhookPublisher
+	events               shared.EventPublisher
 }
 
 func NewService(repo Repository, serviceRepo Repository, walletService wallet.Service) Service {
@@ -108,6 +175,11 @@ func NewService(repo Repository, serviceRepo Repository, walletService wallet.Se
 }
 
 func (s *service) GetAllCategories(ctx context.Context) (*dto.CategoryListResponse, error) {
+	cacheKey := shared.CatalogCacheKey(ctx, "categories", nil)
+	var cached dto.CategoryListResponse
+	if shared.GetCachedCatalog(ctx, cacheKey, &cached) {
+		return &cached, nil
+	}
 
 	categoryInfos, err := s.repo.GetAllActiveCategories(ctx)
 	if err != nil {
@@ -139,13 +211,20 @@ func (s *service) GetAllCategories(ctx context.Context) (*dto.CategoryListRespon
 		})
 	}
 
-	return &dto.CategoryListResponse{
+	result := &dto.CategoryListResponse{
 		Categories: categories,
 		Total:      len(categories),
-	}, nil
+	}
+	shared.SetCachedCatalog(ctx, cacheKey, result)
+	return result, nil
 }
 
 func (s *service) GetCategoryDetail(ctx context.Context, categorySlug string) (*dto.CategoryDetailResponse, error) {
+	cacheKey := shared.CatalogCacheKey(ctx, "category:"+categorySlug, nil)
+	var cached dto.CategoryDetailResponse
+	if shared.GetCachedCatalog(ctx, cacheKey, &cached) {
+		return &cached, nil
+	}
 
 	services, err := s.repo.GetActiveServicesByCategory(ctx, categorySlug)
 	if err != nil {
@@ -172,7 +251,7 @@ func (s *service) GetCategoryDetail(ctx context.Context, categorySlug string) (*
 		}
 	}
 
-	return &dto.CategoryDetailResponse{
+	result := &dto.CategoryDetailResponse{
 		Slug:        categorySlug,
 		Title:       config.Title,
 		Description: config.Description,
@@ -180,7 +259,9 @@ func (s *service) GetCategoryDetail(ctx context.Context, categorySlug string) (*
 		Image:       config.Image,
 		Services:    dto.ToServiceResponses(services),
 		Addons:      dto.ToAddonResponses(addons),
-	}, nil
+	}
+	shared.SetCachedCatalog(ctx, cacheKey, result)
+	return result, nil
 }
 
 func (s *service) GetServiceBySlug(ctx context.Context, slug string) (*dto.ServiceDetailResponse, error) {
@@ -209,6 +290,12 @@ func (s *service) ListServices(ctx context.Context, query dto.ListServicesQuery)
 
 	query.SetDefaults()
 
+	cacheKey := shared.CatalogCacheKey(ctx, "services", query)
+	var cached shared.CatalogPage[dto.ServiceListResponse]
+	if shared.GetCachedCatalog(ctx, cacheKey, &cached) {
+		return cached.Items, &cached.Pagination, nil
+	}
+
 	services, total, err := s.repo.ListActiveServices(ctx, query)
 	if err != nil {
 		logger.Error("failed to list services", "error", err)
@@ -219,6 +306,7 @@ func (s *service) ListServices(ctx context.Context, query dto.ListServicesQuery)
 
 	pagination := response.NewPaginationMeta(total, query.Page, query.Limit)
 
+	shared.SetCachedCatalog(ctx, cacheKey, shared.CatalogPage[dto.ServiceListResponse]{Items: responses, Pagination: pagination})
 	return responses, &pagination, nil
 }
 
@@ -227,13 +315,21 @@ func (s *service) GetFrequentServices(ctx context.Context, limit int) ([]dto.Ser
 		limit = 10
 	}
 
+	cacheKey := shared.CatalogCacheKey(ctx, "frequent-services", limit)
+	var cached []dto.ServiceListResponse
+	if shared.GetCachedCatalog(ctx, cacheKey, &cached) {
+		return cached, nil
+	}
+
 	services, err := s.repo.GetFrequentServices(ctx, limit)
 	if err != nil {
 		logger.Error("failed to get frequent services", "error", err)
 		return nil, response.InternalServerError("Failed to get frequent services", err)
 	}
 
-	return dto.ToServiceListResponses(services), nil
+	result := dto.ToServiceListResponses(services)
+	shared.SetCachedCatalog(ctx, cacheKey, result)
+	return result, nil
 }
 
 func (s *service) GetAddonBySlug(ctx context.Context, slug string) (*dto.AddonResponse, error) {
@@ -252,6 +348,13 @@ func (s *service) GetAddonBySlug(ctx context.Context, slug string) (*dto.AddonRe
 
 func (s *service) ListAddons(ctx context.Context, query dto.ListAddonsQuery) ([]dto.AddonListResponse, *response.PaginationMeta, error) {
 	query.SetDefaults()
+
+	cacheKey := shared.CatalogCacheKey(ctx, "addons", query)
+	var cached shared.CatalogPage[dto.AddonListResponse]
+	if shared.GetCachedCatalog(ctx, cacheKey, &cached) {
+		return cached.Items, &cached.Pagination, nil
+	}
+
 	addons, total, err := s.repo.ListActiveAddons(ctx, query)
 	if err != nil {
 		logger.Error("failed to list addons", "error", err)
@@ -262,6 +365,7 @@ func (s *service) ListAddons(ctx context.Context, query dto.ListAddonsQuery) ([]
 
 	pagination := response.NewPaginationMeta(total, query.Page, query.Limit)
 
+	shared.SetCachedCatalog(ctx, cacheKey, shared.CatalogPage[dto.AddonListResponse]{Items: responses, Pagination: pagination})
 	return responses, &pagination, nil
 }
 
@@ -270,57 +374,83 @@ func (s *service) GetDiscountedAddons(ctx context.Context, limit int) ([]dto.Add
 		limit = 10
 	}
 
+	cacheKey := shared.CatalogCacheKey(ctx, "discounted-addons", limit)
+	var cached []dto.AddonListResponse
+	if shared.GetCachedCatalog(ctx, cacheKey, &cached) {
+		return cached, nil
+	}
+
 	addons, err := s.repo.GetDiscountedAddons(ctx, limit)
 	if err != nil {
 		logger.Error("failed to get discounted addons", "error", err)
 		return nil, response.InternalServerError("Failed to get discounted addons", err)
 	}
 
-	return dto.ToAddonListResponses(addons), nil
+	result := dto.ToAddonListResponses(addons)
+	shared.SetCachedCatalog(ctx, cacheKey, result)
+	return result, nil
 }
 
 func (s *service) Search(ctx context.Context, query dto.SearchQuery) (*dto.SearchResponse, error) {
 	query.SetDefaults()
-
-	limitPerType := query.Limit / 2
-	if limitPerType < 5 {
-		limitPerType = 5
-	}
-
-	services, err := s.repo.SearchServices(ctx, query.Query, query.CategorySlug, limitPerType)
-	if err != nil {
-		logger.Error("failed to search services", "error", err, "query", query.Query)
-		return nil, response.InternalServerError("Failed to search", err)
+	if err := query.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
 	}
 
-	addons, err := s.repo.SearchAddons(ctx, query.Query, query.CategorySlug, limitPerType)
+	hits, total, err := s.repo.Search(ctx, query, prefixTSQuery(query.Query))
 	if err != nil {
-		logger.Error("failed to search addons", "error", err, "query", query.Query)
+		logger.Error("failed to search catalog", "error", err, "query", query.Query)
 		return nil, response.InternalServerError("Failed to search", err)
 	}
 
-	var results []dto.SearchResultItem
-
-	for _, svc := range services {
-		results = append(results, dto.ToSearchResultFromService(svc))
-	}
-
-	for _, addon := range addons {
-		results = append(results, dto.ToSearchResultFromAddon(addon))
+	results := make([]dto.SearchResultItem, 0, len(hits))
+	for _, hit := range hits {
+		results = append(results, dto.SearchResultItem{
+			Type:              hit.Type,
+			ID:                hit.ID,
+			Title:             hit.Title,
+			Slug:              hit.Slug,
+			CategorySlug:      hit.CategorySlug,
+			Description:       hit.Description,
+			Image:             hit.Image,
+			Price:             hit.Price,
+			FormattedPrice:    dto.FormatPrice(hit.Price),
+			Duration:          hit.Duration,
+			FormattedDuration: dto.FormatDuration(hit.Duration),
+		})
 	}
 
 	return &dto.SearchResponse{
-		Query:   query.Query,
-		Results: results,
-		Total:   len(results),
+		Query:      query.Query,
+		Results:    results,
+		Total:      total,
+		Pagination: response.NewPaginationMeta(total, query.Page, query.Limit),
 	}, nil
 }
 
+// prefixTSQuery turns free text into a to_tsquery expression that requires
+// every word and matches each as a prefix, so "plumb rep" finds
+// "Plumbing repair" while the customer is still typing.
+func prefixTSQuery(text string) string {
+	text = strings.NewReplacer("'", "", "’", "").Replace(text)
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
+}
+
 func (s *service) CreateOrder(ctx context.Context, customerID string, req dto.CreateOrderRequest) (*dto.OrderCreatedResponse, error) {
 
+	if err := s.resolveCustomerAddress(ctx, customerID, &req.CustomerInfo); err != nil {
+		return nil, err
+	}
 	if err := req.Validate(); err != nil {
 		return nil, response.BadRequest(err.Error())
 	}
+	s.normalizeCustomerAddress(ctx, &req.CustomerInfo)
 
 	activeCount, err := s.repo.CountCustomerActiveOrders(ctx, customerID)
 	if err != nil {
@@ -331,19 +461,56 @@ func (s *service) CreateOrder(ctx context.Context, customerID string, req dto.Cr
 		return nil, response.BadRequest("You have too many active orders. Please wait for some to complete before booking again.")
 	}
 
+	if req.Recurrence != nil {
+		return s.createRecurringOrder(ctx, customerID, req)
+	}
+
+	order, err := s.placeOrder(ctx, customerID, req, nil)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToOrderCreatedResponse(order), nil
+}
+
+// placeOrder prices and books the order and starts the search for a provider.
+// Orders booked for a recurring plan are linked to it.
+func (s *service) placeOrder(ctx context.Context, customerID string, req dto.CreateOrderRequest, plan *models.RecurringOrderPlan) (*models.ServiceOrderNew, error) {
+	if err := s.checkServiceArea(ctx, req.CustomerInfo.Lat, req.CustomerInfo.Lng); err != nil {
+		return nil, err
+	}
+
+	var quote *models.OrderQuote
+	var err error
+	if req.QuoteID != nil {
+		quote, err = s.getConvertibleQuote(ctx, customerID, *req.QuoteID, req.CategorySlug)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	servicesTotal, selectedServices, err := s.validateAndCalculateServices(ctx, req.CategorySlug, req.SelectedServices)
 	if err != nil {
 		return nil, err
 	}
 
-	addonsTotal, selectedAddons, err := s.validateAndCalculateAddons(ctx, req.CategorySlug, req.SelectedAddons)
+	addonsTotal, selectedAddons, err := s.validateAndCalculateAddons(ctx, req.CategorySlug, req.SelectedAddons, selectedServices)
 	if err != nil {
 		return nil, err
 	}
 
 	subtotal := servicesTotal + addonsTotal
-	platformCommission := shared.CalculatePlatformCommission(subtotal)
-	totalPrice := subtotal
+	commissionRate := s.commissionRate(ctx, req.CategorySlug, req.CustomerInfo.Lat, req.CustomerInfo.Lng)
+	platformCommission := shared.CalculatePlatformCommission(subtotal, commissionRate)
+
+	surcharges, surchargesTotal, err := s.priceSurcharges(ctx, req.CategorySlug, subtotal)
+	if err != nil {
+		return nil, err
+	}
+	taxes, taxTotal, err := s.priceTaxes(ctx, req.CustomerInfo.Lat, req.CustomerInfo.Lng, shared.RoundToTwoDecimals(subtotal+surchargesTotal))
+	if err != nil {
+		return nil, err
+	}
+	totalPrice := shared.RoundToTwoDecimals(subtotal + surchargesTotal + taxTotal)
 
 	var preferredTime time.Time
 	if req.BookingInfo.PreferredTime != "" {
@@ -357,16 +524,19 @@ func (s *service) CreateOrder(ctx context.Context, customerID string, req dto.Cr
 		preferredTime = pt
 	}
 
+	address := translit.NormalizeAddress(req.CustomerInfo.Address)
 	order := &models.ServiceOrderNew{
 		OrderNumber: shared.GenerateOrderNumber(),
 		CustomerID:  customerID,
 		CustomerInfo: models.CustomerInfo{
-			Name:    req.CustomerInfo.Name,
-			Phone:   req.CustomerInfo.Phone,
-			Email:   req.CustomerInfo.Email,
-			Address: req.CustomerInfo.Address,
-			Lat:     req.CustomerInfo.Lat,
-			Lng:     req.CustomerInfo.Lng,
+			Name:              req.CustomerInfo.Name,
+			Phone:             req.CustomerInfo.Phone,
+			Email:             req.CustomerInfo.Email,
+			Address:           req.CustomerInfo.Address,
+			Lat:               req.CustomerInfo.Lat,
+			Lng:               req.CustomerInfo.Lng,
+			NormalizedAddress: address.Normalized,
+			AddressScript:     address.Script,
 		},
 		BookingInfo: models.BookingInfo{
 			Day:            req.BookingInfo.GetDayOfWeek(),
@@ -385,7 +555,13 @@ func (s *service) CreateOrder(ctx context.Context, customerID string, req dto.Cr
 		AddonsTotal:        addonsTotal,
 		Subtotal:           subtotal,
 		PlatformCommission: platformCommission,
+		CommissionRate:     commissionRate,
+		SurchargesTotal:    surchargesTotal,
 		TotalPrice:         totalPrice,
+		Currency:           s.currencyAt(ctx, req.CustomerInfo.Lat, req.CustomerInfo.Lng),
+		Surcharges:         surcharges,
+		TaxTotal:           taxTotal,
+		Taxes:              taxes,
 		PaymentInfo: &models.PaymentInfo{
 			Method: req.PaymentMethod,
 			Status: shared.PaymentStatusPending,
@@ -394,15 +570,38 @@ func (s *service) CreateOrder(ctx context.Context, customerID string, req dto.Cr
 		Status:    shared.OrderStatusPending,
 		ExpiresAt: shared.TimePtr(shared.CalculateOrderExpiration()),
 	}
+	if len(req.Sessions) > 0 {
+		order.IsMultiSession = true
+		order.SessionCount = len(req.Sessions)
+	}
+	if quote != nil {
+		order.QuoteID = &quote.ID
+	}
+	if plan != nil {
+		frequency := string(plan.Frequency)
+		order.RecurringPlanID = &plan.ID
+		date := order.BookingInfo.Date
+		order.RecurringDate = &date
+		order.BookingInfo.Frequency = &frequency
+	}
 
 	if err := s.repo.Create(ctx, order); err != nil {
 		logger.Error("failed to create order", "error", err, "customerID", customerID)
 		return nil, response.InternalServerError("Failed to create order", err)
 	}
 
+	if order.IsMultiSession {
+		if err := s.createOrderSessions(ctx, order, req); err != nil {
+			s.repo.Delete(ctx, order.ID)
+			logger.Error("failed to create order sessions", "error", err, "orderID", order.ID)
+			return nil, response.InternalServerError("Failed to create order", err)
+		}
+	}
+
 	if req.PaymentMethod == "wallet" {
 		holdReq := walletdto.HoldFundsRequest{
 			Amount:        totalPrice,
+			Currency:      order.Currency,
 			ReferenceType: "service_order",
 			ReferenceID:   order.ID,
 			HoldDuration:  1800,
@@ -440,6 +639,8 @@ func (s *service) CreateOrder(ctx context.Context, customerID string, req dto.Cr
 	order.Status = shared.OrderStatusSearchingProvider
 	if err := s.repo.Update(ctx, order); err != nil {
 		logger.Error("failed to update order status", "error", err, "orderID", order.ID)
+	} else {
+		livemetrics.OrderSearching(ctx, order.ID)
 	}
 
 	history = models.NewOrderStatusHistory(
@@ -453,9 +654,13 @@ func (s *service) CreateOrder(ctx context.Context, customerID string, req dto.Cr
 	)
 	s.repo.CreateStatusHistory(ctx, history)
 
+	if quote != nil {
+		s.convertQuote(ctx, quote, order)
+	}
+
 	logger.Info("order created", "orderID", order.ID, "orderNumber", order.OrderNumber, "customerID", customerID)
 
-	return dto.ToOrderCreatedResponse(order), nil
+	return order, nil
 }
 
 func (s *service) validateAndCalculateServices(ctx context.Context, categorySlug string, services []dto.SelectedServiceRequest) (float64, models.SelectedServices, error) {
@@ -494,11 +699,90 @@ func (s *service) validateAndCalculateServices(ctx context.Context, categorySlug
 	return shared.RoundToTwoDecimals(total), selectedServices, nil
 }
 
-func (s *service) validateAndCalculateAddons(ctx context.Context, categorySlug string, addons []dto.SelectedAddonRequest) (float64, models.SelectedAddons, error) {
+func (s *service) PreviewOrder(ctx context.Context, req dto.PreviewOrderRequest) (*dto.OrderPreviewResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+	if req.Lat != nil && req.Lng != nil {
+		if err := s.checkServiceArea(ctx, *req.Lat, *req.Lng); err != nil {
+			return nil, err
+		}
+	}
+
+	servicesTotal, selectedServices, err := s.validateAndCalculateServices(ctx, req.CategorySlug, req.SelectedServices)
+	if err != nil {
+		return nil, err
+	}
+
+	addonsTotal, selectedAddons, err := s.validateAndCalculateAddons(ctx, req.CategorySlug, req.SelectedAddons, selectedServices)
+	if err != nil {
+		return nil, err
+	}
+
+	if selectedAddons == nil {
+		selectedAddons = models.SelectedAddons{}
+	}
+
+	subtotal := shared.RoundToTwoDecimals(servicesTotal + addonsTotal)
+
+	surcharges, surchargesTotal, err := s.priceSurcharges(ctx, req.CategorySlug, subtotal)
+	if err != nil {
+		return nil, err
+	}
+	// The preview is only taxed when the client already knows the address.
+	taxes := models.TaxLines{}
+	var taxTotal float64
+	if req.Lat != nil && req.Lng != nil {
+		taxes, taxTotal, err = s.priceTaxes(ctx, *req.Lat, *req.Lng, shared.RoundToTwoDecimals(subtotal+surchargesTotal))
+		if err != nil {
+			return nil, err
+		}
+	}
+	totalPrice := shared.RoundToTwoDecimals(subtotal + surchargesTotal + taxTotal)
+
+	var currencyCode string
+	if req.Lat != nil && req.Lng != nil {
+		currencyCode = s.currencyAt(ctx, *req.Lat, *req.Lng)
+	}
+
+	return &dto.OrderPreviewResponse{
+		CategorySlug:     req.CategorySlug,
+		SelectedServices: selectedServices,
+		SelectedAddons:   selectedAddons,
+		ServicesTotal:    servicesTotal,
+		AddonsTotal:      addonsTotal,
+		Subtotal:         subtotal,
+		Surcharges:       surcharges,
+		SurchargesTotal:  surchargesTotal,
+		Taxes:            pricingdto.ToTaxLines(taxes),
+		TaxTotal:         taxTotal,
+		TaxIncluded:      req.Lat != nil && req.Lng != nil,
+		TotalPrice:       totalPrice,
+		Currency:         currencyCode,
+		FormattedTotal:   dto.FormatPriceValue(totalPrice),
+	}, nil
+}
+
+func (s *service) priceSurcharges(ctx context.Context, categorySlug string, subtotal float64) (models.OrderSurcharges, float64, error) {
+	surcharges, err := s.repo.GetActiveSurcharges(ctx, categorySlug)
+	if err != nil {
+		logger.Error("failed to load category surcharges", "error", err, "category", categorySlug)
+		return nil, 0, response.InternalServerError("Failed to calculate order total", err)
+	}
+	items, total := shared.PriceSurcharges(surcharges, subtotal)
+	return items, total, nil
+}
+
+func (s *service) validateAndCalculateAddons(ctx context.Context, categorySlug string, addons []dto.SelectedAddonRequest, services models.SelectedServices) (float64, models.SelectedAddons, error) {
 	if len(addons) == 0 {
 		return 0, nil, nil
 	}
 
+	compatible, err := s.loadAddonCompatibility(ctx, addons)
+	if err != nil {
+		return 0, nil, response.InternalServerError("Failed to validate addons", err)
+	}
+
 	var total float64
 	var selectedAddons models.SelectedAddons
 
@@ -515,6 +799,10 @@ func (s *service) validateAndCalculateAddons(ctx context.Context, categorySlug s
 			return 0, nil, response.BadRequest(fmt.Sprintf("Addon '%s' does not belong to category '%s'", add.AddonSlug, categorySlug))
 		}
 
+		if allowed, restricted := compatible[addon.AddonSlug]; restricted && !hasCompatibleService(allowed, services) {
+			return 0, nil, response.BadRequest(fmt.Sprintf("Addon '%s' is not compatible with the selected services", add.AddonSlug))
+		}
+
 		subtotal := addon.Price * float64(add.Quantity)
 		total += subtotal
 
@@ -529,8 +817,43 @@ func (s *service) validateAndCalculateAddons(ctx context.Context, categorySlug s
 	return shared.RoundToTwoDecimals(total), selectedAddons, nil
 }
 
+// loadAddonCompatibility returns the allowed services per addon. Addons without
+// rules are absent from the map and fit any service in their category.
+func (s *service) loadAddonCompatibility(ctx context.Context, addons []dto.SelectedAddonRequest) (map[string]map[string]bool, error) {
+	slugs := make([]string, len(addons))
+	for i, add := range addons {
+		slugs[i] = add.AddonSlug
+	}
+
+	rules, err := s.serviceRepo.GetAddonCompatibility(ctx, slugs)
+	if err != nil {
+		return nil, err
+	}
+
+	compatible := make(map[string]map[string]bool)
+	for _, rule := range rules {
+		if compatible[rule.AddonSlug] == nil {
+			compatible[rule.AddonSlug] = make(map[string]bool)
+		}
+		compatible[rule.AddonSlug][rule.ServiceSlug] = true
+	}
+	return compatible, nil
+}
+
+func hasCompatibleService(allowed map[string]bool, services models.SelectedServices) bool {
+	for _, svc := range services {
+		if allowed[svc.ServiceSlug] {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *service) GetOrder(ctx context.Context, customerID, orderID string) (*dto.OrderResponse, error) {
 	order, err := s.repo.GetCustomerOrderByID(ctx, customerID, orderID)
+	if err == gorm.ErrRecordNotFound {
+		order, err = s.repo.GetArchivedCustomerOrderByID(ctx, customerID, orderID)
+	}
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return nil, response.NotFoundError("Order")
@@ -542,6 +865,19 @@ func (s *service) GetOrder(ctx context.Context, customerID, orderID string) (*dt
 	return dto.ToOrderResponse(order), nil
 }
 
+func (s *service) GetOrderFeeBreakdown(ctx context.Context, customerID, orderID string) (*pricingdto.FeeBreakdownResponse, error) {
+	order, err := s.repo.GetCustomerOrderByID(ctx, customerID, orderID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, response.NotFoundError("Order")
+		}
+		logger.Error("failed to get order", "error", err, "orderID", orderID, "customerID", customerID)
+		return nil, response.InternalServerError("Failed to get order", err)
+	}
+
+	return shared.BuildOrderFeeBreakdown(order, pricing.FeeViewerCustomer), nil
+}
+
 func (s *service) ListOrders(ctx context.Context, customerID string, query dto.ListOrdersQuery) ([]dto.OrderListResponse, *response.PaginationMeta, error) {
 	if err := query.Validate(); err != nil {
 		return nil, nil, response.BadRequest(err.Error())
@@ -575,16 +911,16 @@ func (s *service) GetCancellationPreview(ctx context.Context, customerID, orderI
 		return nil, response.BadRequest(fmt.Sprintf("Order cannot be cancelled in '%s' status", order.Status))
 	}
 
-	cancellationFee, refundAmount := shared.CalculateCancellationFee(order.Status, order.TotalPrice)
+	cancellationFee, refundAmount, _ := s.cancellationCharge(ctx, order)
 	var feePercentage float64
-	switch order.Status {
-	case shared.OrderStatusPending, shared.OrderStatusSearchingProvider:
-		feePercentage = shared.CancellationFeeBeforeAcceptance * 100
-	case shared.OrderStatusAssigned, shared.OrderStatusAccepted:
-		feePercentage = shared.CancellationFeeAfterAcceptance * 100
+	if order.TotalPrice > 0 {
+		feePercentage = shared.RoundToTwoDecimals(cancellationFee / order.TotalPrice * 100)
 	}
 
 	message := fmt.Sprintf("Cancellation fee of %.0f%% will be applied.", feePercentage)
+	if cancellationFee == 0 {
+		message = "You can cancel this order free of charge."
+	}
 	if refundAmount > 0 {
 		message += fmt.Sprintf(" You will receive a refund of $%.2f.", refundAmount)
 	}
@@ -618,7 +954,7 @@ func (s *service) CancelOrder(ctx context.Context, customerID, orderID string, r
 		return nil, response.BadRequest(fmt.Sprintf("Order cannot be cancelled in '%s' status", order.Status))
 	}
 
-	cancellationFee, refundAmount := shared.CalculateCancellationFee(order.Status, order.TotalPrice)
+	cancellationFee, refundAmount, outcome := s.cancellationCharge(ctx, order)
 
 	if order.WalletHoldID != nil {
 		if refundAmount > 0 {
@@ -648,6 +984,12 @@ func (s *service) CancelOrder(ctx context.Context, customerID, orderID string, r
 		}
 	}
 
+	if order.IsMultiSession {
+		if err := s.repo.CancelOpenSessions(ctx, order.ID); err != nil {
+			logger.Error("failed to cancel order sessions", "error", err, "orderID", order.ID)
+		}
+	}
+
 	previousStatus := order.Status
 	order.Status = shared.OrderStatusCancelled
 	order.CancellationInfo = &models.CancellationInfo{
@@ -656,6 +998,7 @@ func (s *service) CancelOrder(ctx context.Context, customerID, orderID string, r
 		Reason:          req.Reason,
 		CancellationFee: cancellationFee,
 		RefundAmount:    refundAmount,
+		Policy:          outcome.PolicyName,
 	}
 
 	if order.PaymentInfo != nil {
@@ -664,9 +1007,11 @@ func (s *service) CancelOrder(ctx context.Context, customerID, orderID string, r
 
 	if err := s.repo.Update(ctx, order); err != nil {
 		logger.Error("failed to update order", "error", err, "orderID", order.ID)
-		return nil, response.InternalServerError("Failed to cancel order", err)
+		return nil, shared.OrderUpdateError(err, "Failed to cancel order")
 	}
 
+	s.closeCancelledOrder(ctx, order)
+
 	history := models.NewOrderStatusHistory(
 		order.ID,
 		previousStatus,
@@ -675,14 +1020,17 @@ func (s *service) CancelOrder(ctx context.Context, customerID, orderID string, r
 		shared.RoleCustomer,
 		fmt.Sprintf("Cancelled by customer: %s", req.Reason),
 		models.StatusHistoryMetadata{
-			"cancellationFee": cancellationFee,
-			"refundAmount":    refundAmount,
+			"cancellationFee":    cancellationFee,
+			"refundAmount":       refundAmount,
+			"cancellationPolicy": outcome.PolicyName,
+			"cancellationRule":   outcome.Rule,
 		},
 	)
 	s.repo.CreateStatusHistory(ctx, history)
 
 	logger.Info("order cancelled", "orderID", order.ID, "customerID", customerID,
-		"cancellationFee", cancellationFee, "refundAmount", refundAmount)
+		"cancellationFee", cancellationFee, "refundAmount", refundAmount, "cancellationRule", outcome.Rule)
+	s.publishOrderCancelled(ctx, order, previousStatus)
 
 	return dto.ToOrderResponse(order), nil
 }
@@ -714,7 +1062,11 @@ func (s *service) RateOrder(ctx context.Context, customerID, orderID string, req
 
 	if err := s.repo.Update(ctx, order); err != nil {
 		logger.Error("failed to update order rating", "error", err, "orderID", order.ID)
-		return nil, response.InternalServerError("Failed to submit rating", err)
+		return nil, shared.OrderUpdateError(err, "Failed to submit rating")
+	}
+
+	if order.AssignedProviderID != nil {
+		s.refreshProviderRating(*order.AssignedProviderID, order.CategorySlug)
 	}
 
 	logger.Info("order rated by customer", "orderID", order.ID, "customerID", customerID, "rating", req.Rating)
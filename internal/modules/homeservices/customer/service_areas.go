@@ -0,0 +1,26 @@
+package customer
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+// ServiceAreas knows the operating cities. It is optional; without one orders
+// are taken at any address.
+type ServiceAreas interface {
+	ResolveServiceArea(ctx context.Context, lat, lon float64) (*models.City, error)
+}
+
+func (s *service) SetServiceAreas(areas ServiceAreas) {
+	s.serviceAreas = areas
+}
+
+// checkServiceArea refuses addresses outside every active city.
+func (s *service) checkServiceArea(ctx context.Context, lat, lng float64) error {
+	if s.serviceAreas == nil {
+		return nil
+	}
+	_, err := s.serviceAreas.ResolveServiceArea(ctx, lat, lng)
+	return err
+}
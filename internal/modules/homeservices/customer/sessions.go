@@ -0,0 +1,301 @@
+package customer
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/customer/dto"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+	"github.com/umar5678/go-backend/internal/services/livemetrics"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+func (s *service) createOrderSessions(ctx context.Context, order *models.ServiceOrderNew, req dto.CreateOrderRequest) error {
+	hours, percents := req.SessionHoursAndPercents()
+	milestones := shared.SplitMilestones(order.TotalPrice, hours, percents)
+
+	sessions := make([]*models.ServiceOrderSession, len(req.Sessions))
+	for i, schedule := range req.Sessions {
+		sessions[i] = &models.ServiceOrderSession{
+			OrderID:         order.ID,
+			SessionNumber:   i + 1,
+			ScheduledDate:   schedule.Date,
+			ScheduledTime:   schedule.Time,
+			DurationHours:   schedule.Hours,
+			Status:          models.SessionStatusScheduled,
+			MilestoneAmount: milestones[i],
+		}
+	}
+	return s.repo.CreateSessions(ctx, sessions)
+}
+
+func (s *service) loadMultiSessionOrder(ctx context.Context, customerID, orderID string) (*models.ServiceOrderNew, []*models.ServiceOrderSession, error) {
+	order, err := s.repo.GetCustomerOrderByID(ctx, customerID, orderID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, response.NotFoundError("Order")
+		}
+		return nil, nil, response.InternalServerError("Failed to get order", err)
+	}
+	if !order.IsMultiSession {
+		return nil, nil, response.BadRequest("Order is not a multi-day booking")
+	}
+
+	sessions, err := s.repo.GetOrderSessions(ctx, order.ID)
+	if err != nil {
+		return nil, nil, response.InternalServerError("Failed to get order sessions", err)
+	}
+	return order, sessions, nil
+}
+
+func findSession(sessions []*models.ServiceOrderSession, sessionID string) *models.ServiceOrderSession {
+	for _, session := range sessions {
+		if session.ID == sessionID {
+			return session
+		}
+	}
+	return nil
+}
+
+func (s *service) GetOrderSessions(ctx context.Context, customerID, orderID string) (*shared.OrderSessionsResponse, error) {
+	order, sessions, err := s.loadMultiSessionOrder(ctx, customerID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return shared.ToOrderSessionsResponse(order, sessions), nil
+}
+
+// ApproveSession is the customer's gate between working days. Approving
+// releases the session's milestone to the provider and unlocks the next
+// session; approving the last session completes the order.
+func (s *service) ApproveSession(ctx context.Context, customerID, orderID, sessionID string, req dto.ApproveSessionRequest) (*shared.OrderSessionsResponse, error) {
+	order, sessions, err := s.loadMultiSessionOrder(ctx, customerID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != shared.OrderStatusInProgress || order.AssignedProviderID == nil {
+		return nil, response.BadRequest(fmt.Sprintf("Cannot approve sessions for an order in '%s' status", order.Status))
+	}
+
+	session := findSession(sessions, sessionID)
+	if session == nil {
+		return nil, response.NotFoundError("Session")
+	}
+	if session.Status != models.SessionStatusAwaitingApproval {
+		return nil, response.BadRequest(fmt.Sprintf("Session %d is not awaiting approval", session.SessionNumber))
+	}
+
+	provider, err := s.repo.GetProviderProfile(ctx, *order.AssignedProviderID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to load provider", err)
+	}
+
+	approved := 0
+	var paidOut float64
+	for _, other := range sessions {
+		if other.IsApproved() {
+			approved++
+			paidOut += other.ProviderPayout
+		}
+	}
+	isFinal := approved+1 == len(sessions)
+
+	// Milestones split the full total; surcharges are passed through, so the
+	// provider earns on the service share of each milestone only.
+	milestoneServiceAmount := session.MilestoneAmount
+	if order.TotalPrice > 0 {
+		milestoneServiceAmount = session.MilestoneAmount * order.ServiceAmount() / order.TotalPrice
+	}
+	payout := shared.CalculateProviderEarnings(milestoneServiceAmount, order.CommissionRate)
+	if isFinal {
+		payout = shared.RoundToTwoDecimals(order.ProviderPayout() - paidOut)
+	}
+
+	now := time.Now()
+	session.CustomerApprovedAt = &now
+	session.CustomerFeedback = req.Feedback
+	session.ProviderPayout = payout
+	claimed, err := s.repo.ClaimSessionApproval(ctx, session)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to approve session", err)
+	}
+	if !claimed {
+		return nil, response.ConflictError(fmt.Sprintf("Session %d has already been reviewed", session.SessionNumber))
+	}
+	session.Status = models.SessionStatusApproved
+
+	before := *order
+	if order.PaymentInfo != nil {
+		paymentInfo := *order.PaymentInfo
+		before.PaymentInfo = &paymentInfo
+	}
+
+	approved++
+	if progress := approved * 100 / len(sessions); progress > order.ProgressPercent {
+		order.ProgressPercent = progress
+	}
+	if order.PaymentInfo != nil {
+		order.PaymentInfo.AmountPaid = shared.RoundToTwoDecimals(order.PaymentInfo.AmountPaid + session.MilestoneAmount)
+	}
+
+	previousStatus := order.Status
+	if isFinal {
+		order.Status = shared.OrderStatusCompleted
+		order.ProviderCompletedAt = &now
+		order.CompletedAt = &now
+		order.ProgressPercent = 100
+		if order.PaymentInfo != nil {
+			order.PaymentInfo.Status = shared.PaymentStatusCompleted
+			order.PaymentInfo.AmountPaid = order.TotalPrice
+		}
+	}
+
+	// The milestone is only released once this versioned write wins, so a
+	// concurrent approval cannot pay it a second time.
+	if err := s.repo.Update(ctx, order); err != nil {
+		logger.Error("failed to update order progress", "error", err, "orderID", order.ID)
+		s.releaseSessionApproval(ctx, session)
+		return nil, shared.OrderUpdateError(err, "Failed to approve session")
+	}
+
+	if payout > 0 {
+		credit := &models.PayoutCredit{
+			ReferenceType:   "service_order",
+			ReferenceID:     order.ID,
+			ReferenceNumber: order.OrderNumber,
+			ProviderID:      order.AssignedProviderID,
+			ProviderUserID:  provider.UserID,
+			Amount:          payout,
+			TransactionType: "service_payment",
+			Description:     fmt.Sprintf("Milestone %d of %d for order %s", session.SessionNumber, len(sessions), order.OrderNumber),
+			Metadata: models.JSONBMap{
+				"order_id":       order.ID,
+				"order_number":   order.OrderNumber,
+				"session_id":     session.ID,
+				"session_number": session.SessionNumber,
+				"service":        "homeservice",
+			},
+		}
+		queued, err := s.walletService.CreditProviderPayout(ctx, credit)
+		if err != nil {
+			logger.Error("failed to credit session milestone", "error", err, "orderID", order.ID, "sessionID", session.ID)
+			before.Version = order.Version
+			if restoreErr := s.repo.Update(ctx, &before); restoreErr != nil {
+				logger.Error("failed to restore order after milestone payment failed", "error", restoreErr, "orderID", order.ID)
+			}
+			s.releaseSessionApproval(ctx, session)
+			return nil, response.InternalServerError("Failed to release milestone payment", err)
+		}
+		// An earlier milestone still waiting keeps the order pending.
+		if queued || order.PayoutStatus == nil || *order.PayoutStatus != models.OrderPayoutStatusPending {
+			payoutStatus := models.OrderPayoutStatusPaid
+			if queued {
+				payoutStatus = models.OrderPayoutStatusPending
+			}
+			order.PayoutStatus = &payoutStatus
+		}
+		if credit.Status == models.PayoutCreditStatusAccrued {
+			settlementStatus := models.OrderSettlementStatusUnsettled
+			order.SettlementStatus = &settlementStatus
+		}
+		if err := s.repo.Update(ctx, order); err != nil {
+			logger.Error("failed to record milestone payout status", "error", err, "orderID", order.ID, "payout", payout)
+		}
+	}
+
+	session.MilestonePaidAt = &now
+	if err := s.repo.UpdateSession(ctx, session); err != nil {
+		logger.Error("failed to update session after milestone payment", "error", err, "sessionID", session.ID, "payout", payout)
+	}
+
+	s.repo.CreateStatusHistory(ctx, models.NewOrderStatusHistory(
+		order.ID,
+		previousStatus,
+		previousStatus,
+		&customerID,
+		shared.RoleCustomer,
+		fmt.Sprintf("Session %d of %d approved by customer", session.SessionNumber, len(sessions)),
+		models.StatusHistoryMetadata{
+			"sessionId":       session.ID,
+			"milestoneAmount": session.MilestoneAmount,
+			"providerPayout":  payout,
+		},
+	))
+
+	if isFinal {
+		livemetrics.AddRevenue(ctx, livemetrics.RevenueSourceHomeServices, order.TotalPrice)
+
+		totalPayout := shared.RoundToTwoDecimals(paidOut + payout)
+		if err := s.repo.IncrementProviderCompletedJobs(ctx, provider.ID, order.CategorySlug, totalPayout); err != nil {
+			logger.Error("failed to update provider category stats", "error", err, "providerID", provider.ID)
+		}
+
+		s.repo.CreateStatusHistory(ctx, models.NewOrderStatusHistory(
+			order.ID,
+			previousStatus,
+			shared.OrderStatusCompleted,
+			&customerID,
+			shared.RoleCustomer,
+			"All sessions approved, service completed",
+			models.StatusHistoryMetadata{"providerPayout": totalPayout},
+		))
+		s.publishOrderCompleted(ctx, order)
+	}
+
+	logger.Info("order session approved",
+		"orderID", order.ID,
+		"sessionNumber", session.SessionNumber,
+		"payout", payout,
+		"final", isFinal,
+	)
+
+	return shared.ToOrderSessionsResponse(order, sessions), nil
+}
+
+func (s *service) releaseSessionApproval(ctx context.Context, session *models.ServiceOrderSession) {
+	if err := s.repo.ReleaseSessionApproval(ctx, session.ID); err != nil {
+		logger.Error("failed to release session approval", "error", err, "sessionID", session.ID)
+	}
+}
+
+func (s *service) RequestSessionChanges(ctx context.Context, customerID, orderID, sessionID string, req dto.RequestSessionChangesRequest) (*shared.OrderSessionsResponse, error) {
+	order, sessions, err := s.loadMultiSessionOrder(ctx, customerID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != shared.OrderStatusInProgress {
+		return nil, response.BadRequest(fmt.Sprintf("Cannot review sessions for an order in '%s' status", order.Status))
+	}
+
+	session := findSession(sessions, sessionID)
+	if session == nil {
+		return nil, response.NotFoundError("Session")
+	}
+	if session.Status != models.SessionStatusAwaitingApproval {
+		return nil, response.BadRequest(fmt.Sprintf("Session %d is not awaiting approval", session.SessionNumber))
+	}
+
+	session.Status = models.SessionStatusChangesRequested
+	session.CustomerFeedback = req.Reason
+	if err := s.repo.UpdateSession(ctx, session); err != nil {
+		return nil, response.InternalServerError("Failed to update session", err)
+	}
+
+	s.repo.CreateStatusHistory(ctx, models.NewOrderStatusHistory(
+		order.ID,
+		order.Status,
+		order.Status,
+		&customerID,
+		shared.RoleCustomer,
+		fmt.Sprintf("Changes requested on session %d: %s", session.SessionNumber, req.Reason),
+		models.StatusHistoryMetadata{"sessionId": session.ID},
+	))
+
+	logger.Info("order session changes requested", "orderID", order.ID, "sessionNumber", session.SessionNumber)
+
+	return shared.ToOrderSessionsResponse(order, sessions), nil
+}
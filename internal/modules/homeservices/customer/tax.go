@@ -0,0 +1,36 @@
+package customer
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/models"
+	pricingdto "github.com/umar5678/go-backend/internal/modules/pricing/dto"
+)
+
+// TaxCalculator prices the taxes owed on an order at the service address. It
+// is optional; without one orders are taken without tax.
+type TaxCalculator interface {
+	CalculateTax(ctx context.Context, req pricingdto.CalculateTaxRequest) (*pricingdto.TaxCalculationResponse, error)
+}
+
+func (s *service) SetTaxCalculator(calculator TaxCalculator) {
+	s.taxCalculator = calculator
+}
+
+// priceTaxes taxes the order amount after surcharges.
+func (s *service) priceTaxes(ctx context.Context, lat, lng, taxable float64) (models.TaxLines, float64, error) {
+	if s.taxCalculator == nil {
+		return models.TaxLines{}, 0, nil
+	}
+
+	result, err := s.taxCalculator.CalculateTax(ctx, pricingdto.CalculateTaxRequest{
+		Lat:       lat,
+		Lon:       lng,
+		Amount:    taxable,
+		AppliesTo: models.TaxAppliesToHomeServices,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return result.Lines, result.TaxTotal, nil
+}
@@ -0,0 +1,36 @@
+package customer
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+	"github.com/umar5678/go-backend/internal/services/eventbus"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+func (s *service) SetWebhookPublisher(publisher shared.WebhookPublisher) {
+	s.webhooks = publisher
+}
+
+func (s *service) publishOrderCancelled(ctx context.Context, order *models.ServiceOrderNew, previousStatus string) {
+	if s.webhooks == nil {
+		return
+	}
+	if err := s.webhooks.Publish(ctx, models.WebhookEventOrderCancelled, shared.OrderCancelledWebhook(order, previousStatus)); err != nil {
+		logger.Warn("failed to publish order cancelled webhook", "error", err, "orderID", order.ID)
+	}
+}
+
+func (s *service) SetEventPublisher(publisher shared.EventPublisher) {
+	s.events = publisher
+}
+
+func (s *service) publishOrderCompleted(ctx context.Context, order *models.ServiceOrderNew) {
+	if s.events == nil {
+		return
+	}
+	if err := s.events.Publish(ctx, eventbus.OrderCompleted, order.ID, shared.OrderCompletedEvent(order)); err != nil {
+		logger.Warn("failed to publish order completed event", "error", err, "orderID", order.ID)
+	}
+}
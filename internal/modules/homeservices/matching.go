@@ -0,0 +1,35 @@
+package homeservices
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+	"github.com/umar5678/go-backend/internal/services/eventbus"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+// SetEventPublisher queues provider matching on the event bus so it runs in
+// whichever process consumes it. Without one, matching runs in a goroutine.
+func (s *service) SetEventPublisher(publisher shared.EventPublisher) {
+	s.events = publisher
+}
+
+func (s *service) SetProofChecker(checker shared.ProofChecker) {
+	s.proofs = checker
+}
+
+func (s *service) SetLocationSharing(sharing shared.LocationSharing) {
+	s.locationSharing = sharing
+}
+
+func (s *service) requestProviderMatching(ctx context.Context, orderID string) {
+	if s.events != nil {
+		payload := eventbus.ProviderMatchingRequestedPayload{OrderID: orderID}
+		err := s.events.Publish(ctx, eventbus.ProviderMatchingRequested, orderID, payload)
+		if err == nil {
+			return
+		}
+		logger.Warn("failed to queue provider matching, matching in process", "error", err, "orderID", orderID)
+	}
+	go s.FindAndNotifyNextProvider(orderID)
+}
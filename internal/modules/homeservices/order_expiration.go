@@ -2,45 +2,54 @@ package homeservices
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/umar5678/go-backend/internal/models"
 	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+	"github.com/umar5678/go-backend/internal/modules/wallet"
+	walletdto "github.com/umar5678/go-backend/internal/modules/wallet/dto"
+	"github.com/umar5678/go-backend/internal/services/livemetrics"
 	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+	websocketutil "github.com/umar5678/go-backend/internal/websocket/websocketutils"
 	"gorm.io/gorm"
 )
 
+// expirationBatchSize caps how many service orders one run expires; the
+// rest are picked up by the next run.
+const expirationBatchSize = 200
+
 type OrderExpirationService struct {
-	db *gorm.DB
+	db            *gorm.DB
+	walletService wallet.Service
+	webhooks      shared.WebhookPublisher
+}
+
+func NewOrderExpirationService(db *gorm.DB, walletService wallet.Service) *OrderExpirationService {
+	return &OrderExpirationService{db: db, walletService: walletService}
 }
 
-func NewOrderExpirationService(db *gorm.DB) *OrderExpirationService {
-	return &OrderExpirationService{db: db}
+func (s *OrderExpirationService) SetWebhookPublisher(publisher shared.WebhookPublisher) {
+	s.webhooks = publisher
 }
 
 func (s *OrderExpirationService) ExpireUnacceptedOrders(ctx context.Context) error {
 	logger.Info("Starting order expiration job")
 
-	result := s.db.WithContext(ctx).
-		Model(&models.ServiceOrderNew{}).
-		Where("status IN ?", []string{shared.OrderStatusPending, shared.OrderStatusSearchingProvider}).
-		Where("expires_at IS NOT NULL AND expires_at <= ?", time.Now()).
-		Update("status", shared.OrderStatusCancelled)
-
-	if result.Error != nil {
-		logger.Error("failed to expire service orders", "error", result.Error)
-		return result.Error
-	}
-
-	if result.RowsAffected > 0 {
-		logger.Info("expired service orders", "count", result.RowsAffected)
+	if err := s.expireServiceOrders(ctx); err != nil {
+		return err
 	}
 
-	result = s.db.WithContext(ctx).
+	result := s.db.WithContext(ctx).
 		Model(&models.LaundryOrder{}).
 		Where("status IN ?", []string{"pending", "searching_provider"}).
 		Where("expires_at IS NOT NULL AND expires_at <= ?", time.Now()).
-		Update("status", "cancelled")
+		Updates(map[string]interface{}{
+			"status":  "cancelled",
+			"version": gorm.Expr("version + 1"),
+		})
 
 	if result.Error != nil {
 		logger.Error("failed to expire laundry orders", "error", result.Error)
@@ -53,3 +62,118 @@ func (s *OrderExpirationService) ExpireUnacceptedOrders(ctx context.Context) err
 
 	return nil
 }
+
+// expireServiceOrders cancels service orders past ExpiresAt that no
+// provider was assigned to. Each order is cancelled on its own so that one
+// that a provider accepts in the meantime is left alone.
+func (s *OrderExpirationService) expireServiceOrders(ctx context.Context) error {
+	var orders []*models.ServiceOrderNew
+	err := s.db.WithContext(ctx).
+		Where("status IN ?", []string{shared.OrderStatusPending, shared.OrderStatusSearchingProvider}).
+		Where("assigned_provider_id IS NULL").
+		Where("expires_at IS NOT NULL AND expires_at <= ?", time.Now()).
+		Order("expires_at ASC").
+		Limit(expirationBatchSize).
+		Find(&orders).Error
+	if err != nil {
+		logger.Error("failed to find expired service orders", "error", err)
+		return err
+	}
+
+	expired := 0
+	for _, order := range orders {
+		previousStatus := order.Status
+		if err := s.expireOrder(ctx, order); err != nil {
+			if errors.Is(err, shared.ErrOrderConflict) {
+				logger.Info("service order changed before it could expire", "orderID", order.ID)
+				continue
+			}
+			logger.Error("failed to expire service order", "error", err, "orderID", order.ID)
+			continue
+		}
+		expired++
+
+		livemetrics.OrderNotSearching(ctx, order.ID)
+		s.releaseHold(ctx, order)
+		s.notifyCustomer(order)
+		s.publishExpired(ctx, order, previousStatus)
+	}
+
+	if expired > 0 {
+		logger.Info("expired service orders", "count", expired)
+	}
+	return nil
+}
+
+func (s *OrderExpirationService) expireOrder(ctx context.Context, order *models.ServiceOrderNew) error {
+	previousStatus := order.Status
+	now := time.Now()
+
+	order.Status = shared.OrderStatusCancelled
+	order.CancellationInfo = &models.CancellationInfo{
+		CancelledBy: shared.CancelledBySystem,
+		CancelledAt: now,
+		Reason:      shared.CancellationReasonExpired,
+	}
+	if order.WalletHoldID != nil {
+		order.CancellationInfo.RefundAmount = order.TotalPrice
+		if order.PaymentInfo != nil {
+			order.PaymentInfo.Status = shared.PaymentStatusRefunded
+		}
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := shared.SaveOrderVersioned(tx, order); err != nil {
+			return err
+		}
+		return tx.Create(models.NewOrderStatusHistory(
+			order.ID,
+			previousStatus,
+			shared.OrderStatusCancelled,
+			nil,
+			shared.RoleSystem,
+			"Expired: no provider accepted the order in time",
+			models.StatusHistoryMetadata{
+				"expiresAt": order.ExpiresAt,
+			},
+		)).Error
+	})
+}
+
+// releaseHold gives the customer back the funds held for the order. A hold
+// that already lapsed on its own is fine.
+func (s *OrderExpirationService) releaseHold(ctx context.Context, order *models.ServiceOrderNew) {
+	if order.WalletHoldID == nil || s.walletService == nil {
+		return
+	}
+	err := s.walletService.ReleaseHold(ctx, order.CustomerID, walletdto.ReleaseHoldRequest{HoldID: *order.WalletHoldID})
+	if err == nil {
+		return
+	}
+	if appErr, ok := err.(*response.AppError); ok && appErr.Code == response.CodeWalletHoldInactive {
+		return
+	}
+	logger.Error("failed to release wallet hold for expired order", "error", err, "orderID", order.ID, "holdID", *order.WalletHoldID)
+}
+
+func (s *OrderExpirationService) notifyCustomer(order *models.ServiceOrderNew) {
+	data := map[string]interface{}{
+		"orderId":     order.ID,
+		"orderNumber": order.OrderNumber,
+		"expiresAt":   order.ExpiresAt,
+		"refunded":    order.WalletHoldID != nil,
+		"message":     "No provider was available for your order, so it has been cancelled",
+	}
+	if err := websocketutil.SendToUser(order.CustomerID, websocket.TypeOrderExpired, data); err != nil {
+		logger.Warn("failed to notify customer about expired order", "error", err, "orderID", order.ID)
+	}
+}
+
+func (s *OrderExpirationService) publishExpired(ctx context.Context, order *models.ServiceOrderNew, previousStatus string) {
+	if s.webhooks == nil {
+		return
+	}
+	if err := s.webhooks.Publish(ctx, models.WebhookEventOrderCancelled, shared.OrderCancelledWebhook(order, previousStatus)); err != nil {
+		logger.Warn("failed to publish order cancelled webhook", "error", err, "orderID", order.ID)
+	}
+}
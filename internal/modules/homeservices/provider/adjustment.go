@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/provider/dto"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+)
+
+// ProposeAdjustment asks the customer to approve a change of scope found on
+// site, such as an extra room or a treatment that is no longer needed. The
+// order keeps its items and price until the customer approves.
+func (s *service) ProposeAdjustment(ctx context.Context, providerID, orderID string, req dto.ProposeAdjustmentRequest) (*shared.AdjustmentResponse, error) {
+	order, err := s.loadAssignedOrder(ctx, providerID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := shared.ValidateAdjustable(order); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetPendingAdjustment(ctx, order.ID); err == nil {
+		return nil, response.ConflictError("A scope adjustment for this order is already waiting for the customer")
+	}
+
+	lines := make(models.OrderAdjustmentLines, len(req.Lines))
+	for i, line := range req.Lines {
+		lines[i] = models.OrderAdjustmentLine{
+			Action:   line.Action,
+			ItemType: line.ItemType,
+			Slug:     line.Slug,
+			Title:    line.Title,
+			Price:    line.Price,
+			Quantity: line.Quantity,
+		}
+	}
+	lines, err = shared.PrepareAdjustmentLines(order, lines)
+	if err != nil {
+		return nil, err
+	}
+
+	surcharges, err := s.repo.GetActiveSurcharges(ctx, order.CategorySlug)
+	if err != nil {
+		logger.Error("failed to load category surcharges", "error", err, "category", order.CategorySlug)
+		return nil, response.InternalServerError("Failed to price adjustment", err)
+	}
+	adjustment, err := shared.NewOrderAdjustment(order, providerID, lines, req.Note, surcharges)
+	if err != nil {
+		return nil, err
+	}
+	if adjustment.Difference == 0 {
+		return nil, response.BadRequest("The adjustment does not change the order total")
+	}
+
+	if err := s.repo.CreateAdjustment(ctx, adjustment); err != nil {
+		logger.Error("failed to create scope adjustment", "error", err, "orderID", order.ID)
+		return nil, response.InternalServerError("Failed to propose adjustment", err)
+	}
+
+	s.notifyCustomer(order, websocket.TypeOrderAdjustmentProposed, shared.AdjustmentNotification(order, adjustment))
+
+	logger.Info("order scope adjustment proposed", "orderID", order.ID, "providerID", providerID, "adjustmentID", adjustment.ID,
+		"previousTotal", adjustment.PreviousTotal, "newTotal", adjustment.NewTotal)
+
+	return shared.ToAdjustmentResponse(adjustment), nil
+}
+
+func (s *service) GetOrderAdjustments(ctx context.Context, providerID, orderID string) (*shared.OrderAdjustmentsResponse, error) {
+	order, err := s.loadAssignedOrder(ctx, providerID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	adjustments, err := s.repo.GetOrderAdjustments(ctx, order.ID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get adjustments", err)
+	}
+	return shared.ToOrderAdjustmentsResponse(order, adjustments), nil
+}
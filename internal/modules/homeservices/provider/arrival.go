@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/provider/dto"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+)
+
+// MarkArrived records that the provider has reached the service address. It
+// is the manual override for when GPS cannot place the provider inside the
+// arrival geofence.
+func (s *service) MarkArrived(ctx context.Context, providerID, orderID string) (*dto.ProviderOrderResponse, error) {
+	order, err := s.loadAssignedOrder(ctx, providerID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != shared.OrderStatusAccepted {
+		return nil, response.BadRequest(fmt.Sprintf("Cannot mark arrival for an order in '%s' status", order.Status))
+	}
+	if order.ProviderArrivedAt != nil {
+		return nil, response.BadRequest("Arrival has already been recorded for this order")
+	}
+
+	if err := s.markArrived(ctx, order, providerID, models.ArrivalMethodManual); err != nil {
+		return nil, err
+	}
+	return dto.ToProviderOrderResponse(order, s.addressScript(ctx, providerID)), nil
+}
+
+// MarkArrivedByGeofence is called by tracking when the provider's shared
+// location comes within the geofence around the service address. Orders
+// already marked, or no longer waiting for the provider, are left alone.
+func (s *service) MarkArrivedByGeofence(ctx context.Context, providerID, orderID string) error {
+	order, err := s.loadAssignedOrder(ctx, providerID, orderID)
+	if err != nil {
+		return err
+	}
+	if order.Status != shared.OrderStatusAccepted || order.ProviderArrivedAt != nil {
+		return nil
+	}
+	return s.markArrived(ctx, order, providerID, models.ArrivalMethodGeofence)
+}
+
+func (s *service) markArrived(ctx context.Context, order *models.ServiceOrderNew, providerID, method string) error {
+	now := time.Now()
+	order.ProviderArrivedAt = &now
+	order.ArrivalMethod = &method
+	if err := s.repo.UpdateOrder(ctx, order); err != nil {
+		return shared.OrderUpdateError(err, "Failed to record arrival")
+	}
+
+	s.repo.CreateStatusHistory(ctx, models.NewOrderStatusHistory(
+		order.ID,
+		order.Status,
+		order.Status,
+		&providerID,
+		shared.RoleProvider,
+		"Provider arrived at the service address",
+		models.StatusHistoryMetadata{"arrivalMethod": method},
+	))
+
+	s.notifyCustomer(order, websocket.TypeProviderArrived, map[string]interface{}{
+		"orderId":       order.ID,
+		"orderNumber":   order.OrderNumber,
+		"providerId":    providerID,
+		"arrivedAt":     now,
+		"arrivalMethod": method,
+		"message":       "Your provider has arrived",
+	})
+
+	logger.Info("provider arrived at service address", "orderID", order.ID, "providerID", providerID, "method", method)
+
+	return nil
+}
@@ -0,0 +1,424 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/provider/dto"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+	websocketutil "github.com/umar5678/go-backend/internal/websocket/websocketutils"
+)
+
+// GetOrderCrew returns the crew the lead provider has put together for an
+// order, including members who declined or were removed.
+func (s *service) GetOrderCrew(ctx context.Context, providerID, orderID string) (*dto.OrderCrewResponse, error) {
+	order, err := s.loadAssignedOrder(ctx, providerID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.repo.GetOrderCrew(ctx, order.ID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get crew", err)
+	}
+	return dto.ToOrderCrewResponse(order, members), nil
+}
+
+// InviteCrewMember asks another provider to work a multi-pro order with the
+// lead. The crew can grow to the number of pros the customer booked, and
+// members' shares of the payout must leave the lead a share of their own.
+func (s *service) InviteCrewMember(ctx context.Context, providerID, orderID string, req dto.InviteCrewMemberRequest) (*dto.CrewMemberResponse, error) {
+	order, err := s.loadCrewOrder(ctx, providerID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	pros := order.BookingInfo.QuantityOfPros
+	if pros < 2 {
+		return nil, response.BadRequest("This order was booked for a single pro")
+	}
+	if req.ProviderID == providerID {
+		return nil, response.BadRequest("You are already the lead provider on this order")
+	}
+
+	members, err := s.repo.GetOrderCrew(ctx, order.ID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to invite crew member", err)
+	}
+	open := 0
+	for _, member := range members {
+		if !member.IsOpen() {
+			continue
+		}
+		if member.ProviderID == req.ProviderID {
+			return nil, response.ConflictError("This provider is already on the crew")
+		}
+		open++
+	}
+	if open >= pros-1 {
+		return nil, response.BadRequest(fmt.Sprintf("The crew is full; this order was booked for %d pros", pros))
+	}
+
+	invitee, err := s.repo.GetProvider(ctx, req.ProviderID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, response.NotFoundError("Provider")
+		}
+		return nil, response.InternalServerError("Failed to invite crew member", err)
+	}
+	if !invitee.CanReceiveOrders() {
+		return nil, response.BadRequest("This provider is not available for work")
+	}
+	if _, err := s.repo.GetProviderCategory(ctx, invitee.ID, order.CategorySlug); err != nil {
+		return nil, response.BadRequest("This provider does not offer services in the order's category")
+	}
+
+	percent := shared.RoundToTwoDecimals(100 / float64(pros))
+	if req.PayoutPercent != nil {
+		percent = shared.RoundToTwoDecimals(*req.PayoutPercent)
+	}
+	if err := validateCrewShares(members, "", percent); err != nil {
+		return nil, err
+	}
+
+	member := &models.OrderCrewMember{
+		OrderID:       order.ID,
+		ProviderID:    invitee.ID,
+		InvitedBy:     providerID,
+		PayoutPercent: percent,
+		Status:        models.CrewStatusInvited,
+	}
+	if err := s.repo.CreateCrewMember(ctx, member); err != nil {
+		logger.Error("failed to create crew member", "error", err, "orderID", order.ID, "providerID", invitee.ID)
+		return nil, response.InternalServerError("Failed to invite crew member", err)
+	}
+	member.Provider = invitee
+
+	s.notifyProviderUser(invitee.UserID, websocket.TypeCrewInvitation, crewNotification(order, member))
+
+	logger.Info("crew member invited", "orderID", order.ID, "leadProviderID", providerID,
+		"providerID", invitee.ID, "payoutPercent", percent)
+
+	return dto.ToCrewMemberResponse(member), nil
+}
+
+// UpdateCrewMember changes a member's share of the payout before the job
+// starts.
+func (s *service) UpdateCrewMember(ctx context.Context, providerID, orderID, memberID string, req dto.UpdateCrewMemberRequest) (*dto.CrewMemberResponse, error) {
+	order, member, members, err := s.loadOrderCrewMember(ctx, providerID, orderID, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	percent := shared.RoundToTwoDecimals(req.PayoutPercent)
+	if err := validateCrewShares(members, member.ID, percent); err != nil {
+		return nil, err
+	}
+	member.PayoutPercent = percent
+	if err := s.repo.UpdateCrewMember(ctx, member); err != nil {
+		return nil, response.InternalServerError("Failed to update crew member", err)
+	}
+
+	if member.Provider != nil {
+		s.notifyProviderUser(member.Provider.UserID, websocket.TypeCrewUpdated, crewNotification(order, member))
+	}
+
+	logger.Info("crew member share updated", "orderID", order.ID, "memberID", member.ID, "payoutPercent", percent)
+
+	return dto.ToCrewMemberResponse(member), nil
+}
+
+// RemoveCrewMember takes a member off the crew before the job starts.
+func (s *service) RemoveCrewMember(ctx context.Context, providerID, orderID, memberID string) error {
+	order, member, _, err := s.loadOrderCrewMember(ctx, providerID, orderID, memberID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	member.Status = models.CrewStatusRemoved
+	member.RespondedAt = &now
+	if err := s.repo.UpdateCrewMember(ctx, member); err != nil {
+		return response.InternalServerError("Failed to remove crew member", err)
+	}
+
+	if member.Provider != nil {
+		s.notifyProviderUser(member.Provider.UserID, websocket.TypeCrewUpdated, crewNotification(order, member))
+	}
+
+	logger.Info("crew member removed", "orderID", order.ID, "memberID", member.ID, "providerID", member.ProviderID)
+
+	return nil
+}
+
+// GetCrewAssignments lists the orders the provider has been invited to work
+// as a crew member, newest first.
+func (s *service) GetCrewAssignments(ctx context.Context, providerID string) ([]*dto.CrewAssignmentResponse, error) {
+	members, err := s.repo.GetProviderCrewAssignments(ctx, providerID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get crew assignments", err)
+	}
+	script := s.addressScript(ctx, providerID)
+	assignments := make([]*dto.CrewAssignmentResponse, len(members))
+	for i, member := range members {
+		assignments[i] = dto.ToCrewAssignmentResponse(member, script)
+	}
+	return assignments, nil
+}
+
+func (s *service) AcceptCrewInvite(ctx context.Context, providerID, memberID string) (*dto.CrewAssignmentResponse, error) {
+	return s.answerCrewInvite(ctx, providerID, memberID, models.CrewStatusAccepted)
+}
+
+func (s *service) DeclineCrewInvite(ctx context.Context, providerID, memberID string) (*dto.CrewAssignmentResponse, error) {
+	return s.answerCrewInvite(ctx, providerID, memberID, models.CrewStatusDeclined)
+}
+
+func (s *service) answerCrewInvite(ctx context.Context, providerID, memberID string, status models.OrderCrewStatus) (*dto.CrewAssignmentResponse, error) {
+	member, err := s.loadCrewAssignment(ctx, providerID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if member.Status != models.CrewStatusInvited {
+		return nil, response.BadRequest("This invitation has already been answered")
+	}
+	if member.Order.Status != shared.OrderStatusAccepted {
+		return nil, response.BadRequest("This invitation is no longer open")
+	}
+
+	now := time.Now()
+	member.Status = status
+	member.RespondedAt = &now
+	if err := s.repo.UpdateCrewMember(ctx, member); err != nil {
+		return nil, response.InternalServerError("Failed to answer invitation", err)
+	}
+
+	s.notifyCrewLead(ctx, member)
+
+	logger.Info("crew invitation answered", "orderID", member.OrderID, "memberID", member.ID, "status", status)
+
+	return dto.ToCrewAssignmentResponse(member, s.addressScript(ctx, providerID)), nil
+}
+
+// CheckInCrew records that a member has arrived on site. The lead cannot
+// start the job until every accepted member has checked in.
+func (s *service) CheckInCrew(ctx context.Context, providerID, memberID string) (*dto.CrewAssignmentResponse, error) {
+	member, err := s.loadCrewAssignment(ctx, providerID, memberID)
+	if err != nil {
+		return nil, err
+	}
+	if member.Status != models.CrewStatusAccepted {
+		return nil, response.BadRequest("Only accepted crew members can check in")
+	}
+	if member.Order.Status != shared.OrderStatusAccepted {
+		return nil, response.BadRequest(fmt.Sprintf("Cannot check in to an order in '%s' status", member.Order.Status))
+	}
+	if member.CheckedInAt != nil {
+		return nil, response.BadRequest("You have already checked in")
+	}
+
+	now := time.Now()
+	member.CheckedInAt = &now
+	if err := s.repo.UpdateCrewMember(ctx, member); err != nil {
+		return nil, response.InternalServerError("Failed to check in", err)
+	}
+
+	s.notifyCrewLead(ctx, member)
+
+	logger.Info("crew member checked in", "orderID", member.OrderID, "memberID", member.ID)
+
+	return dto.ToCrewAssignmentResponse(member, s.addressScript(ctx, providerID)), nil
+}
+
+// checkCrewReady blocks the start of a job while accepted crew members are
+// still on their way.
+func (s *service) checkCrewReady(ctx context.Context, order *models.ServiceOrderNew) error {
+	members, err := s.repo.GetOrderCrew(ctx, order.ID)
+	if err != nil {
+		return response.InternalServerError("Failed to check crew", err)
+	}
+	var waiting []string
+	for _, member := range members {
+		if member.Status != models.CrewStatusAccepted || member.CheckedInAt != nil {
+			continue
+		}
+		name := member.ProviderID
+		if member.Provider != nil && member.Provider.User != nil {
+			name = member.Provider.User.Name
+		}
+		waiting = append(waiting, name)
+	}
+	if len(waiting) > 0 {
+		return response.BadRequest("Waiting for crew members to check in: " + strings.Join(waiting, ", "))
+	}
+	return nil
+}
+
+// splitCrewPayout works out each accepted crew member's share of the provider
+// payout and returns what is left for the lead.
+func (s *service) splitCrewPayout(ctx context.Context, order *models.ServiceOrderNew, providerPayout float64) (float64, []*models.OrderCrewMember) {
+	members, err := s.repo.GetOrderCrew(ctx, order.ID)
+	if err != nil {
+		logger.Error("failed to load crew for payout", "error", err, "orderID", order.ID)
+		return providerPayout, nil
+	}
+	leadPayout := providerPayout
+	var paid []*models.OrderCrewMember
+	for _, member := range members {
+		if member.Status != models.CrewStatusAccepted {
+			continue
+		}
+		amount := math.Round(providerPayout*member.PayoutPercent) / 100
+		member.PayoutAmount = &amount
+		leadPayout -= amount
+		paid = append(paid, member)
+	}
+	return shared.RoundToTwoDecimals(leadPayout), paid
+}
+
+// payCrew credits crew members their shares once the order is completed. A
+// failed credit is logged and left for support; the lead has been paid.
+func (s *service) payCrew(ctx context.Context, order *models.ServiceOrderNew, members []*models.OrderCrewMember) {
+	for _, member := range members {
+		if member.Provider == nil || member.PayoutAmount == nil {
+			continue
+		}
+		providerID := member.ProviderID
+		credit := &models.PayoutCredit{
+			ReferenceType:   "service_order",
+			ReferenceID:     order.ID,
+			ReferenceNumber: order.OrderNumber,
+			ProviderID:      &providerID,
+			ProviderUserID:  member.Provider.UserID,
+			Amount:          *member.PayoutAmount,
+			TransactionType: "service_payment",
+			Description:     fmt.Sprintf("Crew payment for order %s", order.OrderNumber),
+			Metadata: models.JSONBMap{
+				"order_id":       order.ID,
+				"order_number":   order.OrderNumber,
+				"service":        "homeservice",
+				"crew_member_id": member.ID,
+				"payout_percent": member.PayoutPercent,
+			},
+		}
+		if _, err := s.walletService.CreditProviderPayout(ctx, credit); err != nil {
+			logger.Error("failed to credit crew member", "error", err, "orderID", order.ID, "memberID", member.ID)
+			continue
+		}
+		if err := s.repo.UpdateCrewMember(ctx, member); err != nil {
+			logger.Error("failed to record crew payout", "error", err, "memberID", member.ID)
+		}
+
+		category, err := s.repo.GetProviderCategory(ctx, providerID, order.CategorySlug)
+		if err == nil && category != nil {
+			category.IncrementCompletedJobs(*member.PayoutAmount)
+			s.repo.UpdateProviderCategory(ctx, category)
+		}
+	}
+}
+
+// loadCrewOrder loads an order whose crew the lead can still change: the job
+// has been accepted but not started.
+func (s *service) loadCrewOrder(ctx context.Context, providerID, orderID string) (*models.ServiceOrderNew, error) {
+	order, err := s.loadAssignedOrder(ctx, providerID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != shared.OrderStatusAccepted {
+		return nil, response.BadRequest(fmt.Sprintf("Cannot change the crew of an order in '%s' status", order.Status))
+	}
+	if order.IsMultiSession {
+		return nil, response.BadRequest("Multi-day bookings do not support crews")
+	}
+	return order, nil
+}
+
+func (s *service) loadOrderCrewMember(ctx context.Context, providerID, orderID, memberID string) (*models.ServiceOrderNew, *models.OrderCrewMember, []*models.OrderCrewMember, error) {
+	order, err := s.loadCrewOrder(ctx, providerID, orderID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	members, err := s.repo.GetOrderCrew(ctx, order.ID)
+	if err != nil {
+		return nil, nil, nil, response.InternalServerError("Failed to get crew", err)
+	}
+	for _, member := range members {
+		if member.ID != memberID {
+			continue
+		}
+		if !member.IsOpen() {
+			return nil, nil, nil, response.BadRequest("This provider is no longer on the crew")
+		}
+		return order, member, members, nil
+	}
+	return nil, nil, nil, response.NotFoundError("Crew member")
+}
+
+func (s *service) loadCrewAssignment(ctx context.Context, providerID, memberID string) (*models.OrderCrewMember, error) {
+	member, err := s.repo.GetCrewMember(ctx, memberID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, response.NotFoundError("Crew invitation")
+		}
+		return nil, response.InternalServerError("Failed to get crew invitation", err)
+	}
+	if member.ProviderID != providerID || member.Order == nil {
+		return nil, response.NotFoundError("Crew invitation")
+	}
+	return member, nil
+}
+
+// validateCrewShares checks that the open members' shares, with memberID's
+// share set to percent, leave the lead part of the payout.
+func validateCrewShares(members []*models.OrderCrewMember, memberID string, percent float64) error {
+	total := percent
+	for _, member := range members {
+		if member.IsOpen() && member.ID != memberID {
+			total += member.PayoutPercent
+		}
+	}
+	if total >= 100 {
+		return response.BadRequest("Crew shares must leave the lead provider part of the payout")
+	}
+	return nil
+}
+
+func (s *service) notifyCrewLead(ctx context.Context, member *models.OrderCrewMember) {
+	if member.Order.AssignedProviderID == nil {
+		return
+	}
+	lead, err := s.repo.GetProvider(ctx, *member.Order.AssignedProviderID)
+	if err != nil {
+		logger.Warn("failed to load crew lead", "error", err, "orderID", member.OrderID)
+		return
+	}
+	s.notifyProviderUser(lead.UserID, websocket.TypeCrewUpdated, crewNotification(member.Order, member))
+}
+
+func (s *service) notifyProviderUser(userID string, messageType websocket.MessageType, data map[string]interface{}) {
+	if err := websocketutil.SendToUser(userID, messageType, data); err != nil {
+		logger.Warn("failed to notify provider", "error", err, "userID", userID, "type", messageType)
+	}
+}
+
+func crewNotification(order *models.ServiceOrderNew, member *models.OrderCrewMember) map[string]interface{} {
+	return map[string]interface{}{
+		"orderId":       order.ID,
+		"orderNumber":   order.OrderNumber,
+		"categorySlug":  order.CategorySlug,
+		"bookingDate":   order.BookingInfo.Date,
+		"bookingTime":   order.BookingInfo.Time,
+		"memberId":      member.ID,
+		"providerId":    member.ProviderID,
+		"status":        member.Status,
+		"payoutPercent": member.PayoutPercent,
+		"checkedInAt":   member.CheckedInAt,
+	}
+}
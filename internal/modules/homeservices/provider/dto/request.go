@@ -2,6 +2,7 @@ package dto
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/umar5678/go-backend/internal/models"
 	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
@@ -30,6 +31,12 @@ type UpdateAvailabilityRequest struct {
 	Longitude   *float64 `json:"longitude" binding:"omitempty,longitude"`
 }
 
+// UpdateAddressScriptRequest selects how customer addresses appear in order
+// payloads: "latin" for the transliterated form, "native" for the text as typed.
+type UpdateAddressScriptRequest struct {
+	AddressScript string `json:"addressScript" binding:"required,oneof=latin native"`
+}
+
 type AddServiceCategoryRequest struct {
 	CategorySlug      string `json:"categorySlug" binding:"required,min=2,max=100"`
 	ExpertiseLevel    string `json:"expertiseLevel" binding:"required,oneof=beginner intermediate expert"`
@@ -79,6 +86,53 @@ type CompleteOrderRequest struct {
 	Notes       string `json:"notes" binding:"omitempty,max=1000"`
 }
 
+type CheckInSessionRequest struct {
+	CustomerPIN string `json:"customerPin" binding:"required,len=4"`
+}
+
+type CheckOutSessionRequest struct {
+	WorkSummary     string `json:"workSummary" binding:"required,min=5,max=2000"`
+	ProgressPercent int    `json:"progressPercent" binding:"min=0,max=100"`
+}
+
+type RescheduleOrderRequest struct {
+	Date   string `json:"date" binding:"required" example:"2024-06-12"`
+	Time   string `json:"time" binding:"required" example:"14:00"`
+	Reason string `json:"reason" binding:"omitempty,max=500"`
+}
+
+type RespondRescheduleRequest struct {
+	Note string `json:"note" binding:"omitempty,max=500"`
+}
+
+// AdjustmentLineRequest adds or removes a quantity of a service or addon.
+// Price is what the provider charges for each added item and is ignored for
+// removals, which are credited at the booked price.
+type AdjustmentLineRequest struct {
+	Action   string  `json:"action" binding:"required,oneof=add remove" example:"add"`
+	ItemType string  `json:"itemType" binding:"required,oneof=service addon" example:"service"`
+	Slug     string  `json:"slug" binding:"required,max=255" example:"extra-room"`
+	Title    string  `json:"title" binding:"omitempty,max=255" example:"Extra room"`
+	Price    float64 `json:"price" binding:"omitempty,gte=0" example:"25"`
+	Quantity int     `json:"quantity" binding:"required,min=1,max=100" example:"1"`
+}
+
+type ProposeAdjustmentRequest struct {
+	Lines []AdjustmentLineRequest `json:"lines" binding:"required,min=1,max=20,dive"`
+	Note  string                  `json:"note" binding:"omitempty,max=1000"`
+}
+
+// InviteCrewMemberRequest invites another provider onto a multi-pro order.
+// PayoutPercent defaults to an equal share of the order's pros.
+type InviteCrewMemberRequest struct {
+	ProviderID    string   `json:"providerId" binding:"required,uuid"`
+	PayoutPercent *float64 `json:"payoutPercent" binding:"omitempty,gt=0,lt=100" example:"50"`
+}
+
+type UpdateCrewMemberRequest struct {
+	PayoutPercent float64 `json:"payoutPercent" binding:"required,gt=0,lt=100" example:"40"`
+}
+
 type RateCustomerRequest struct {
 	Rating int    `json:"rating" binding:"required,min=1,max=5"`
 	Review string `json:"review" binding:"omitempty,max=1000"`
@@ -97,6 +151,12 @@ type ListAvailableOrdersQuery struct {
 	Date         string `form:"date"` 
 	SortBy       string `form:"sortBy" binding:"omitempty,oneof=created_at booking_date distance price"`
 	SortDesc     bool   `form:"sortDesc"`
+
+	// PreferredBy holds the customers who prefer the provider; their orders
+	// are listed first. Orders from other customers are held back for
+	// HeadStart while one of their own preferred providers is free.
+	PreferredBy map[string]bool `form:"-" json:"-"`
+	HeadStart   time.Duration   `form:"-" json:"-"`
 }
 
 func (q *ListAvailableOrdersQuery) SetDefaults() {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/umar5678/go-backend/internal/models"
 	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+	"github.com/umar5678/go-backend/internal/utils/translit"
 )
 
 type ProviderProfileResponse struct {
@@ -18,7 +19,9 @@ type ProviderProfileResponse struct {
 	Bio               string                    `json:"bio,omitempty"`
 	IsVerified        bool                      `json:"isVerified"`
 	IsAvailable       bool                      `json:"isAvailable"`
+	Status            string                    `json:"status"`
 	YearsOfExperience int                       `json:"yearsOfExperience"`
+	AddressScript     string                    `json:"addressScript"`
 	ServiceCategories []ServiceCategoryResponse `json:"serviceCategories"`
 	Statistics        ProviderStatistics        `json:"statistics"`
 	CreatedAt         time.Time                 `json:"createdAt"`
@@ -65,16 +68,21 @@ type AvailableOrderResponse struct {
 	ProviderPayout  float64            `json:"providerPayout"`
 	FormattedPayout string             `json:"formattedPayout"`
 	Distance        *float64           `json:"distance,omitempty"` 
+	IsPreferred     bool               `json:"isPreferred"`
 	CreatedAt       time.Time          `json:"createdAt"`
 	ExpiresAt       *time.Time         `json:"expiresAt,omitempty"`
 }
 
+// OrderCustomerInfo carries the address in the provider's preferred script;
+// OriginalAddress is what the customer typed when it differs.
 type OrderCustomerInfo struct {
-	Name    string  `json:"name"`
-	Phone   string  `json:"phone,omitempty"`
-	Address string  `json:"address"`
-	Lat     float64 `json:"lat"`
-	Lng     float64 `json:"lng"`
+	Name            string  `json:"name"`
+	Phone           string  `json:"phone,omitempty"`
+	Address         string  `json:"address"`
+	OriginalAddress string  `json:"originalAddress,omitempty"`
+	AddressScript   string  `json:"addressScript,omitempty"`
+	Lat             float64 `json:"lat"`
+	Lng             float64 `json:"lng"`
 }
 
 type OrderBookingInfo struct {
@@ -105,28 +113,34 @@ type OrderAddonItem struct {
 }
 
 type ProviderOrderResponse struct {
-	ID              string             `json:"id"`
-	OrderNumber     string             `json:"orderNumber"`
-	CategorySlug    string             `json:"categorySlug"`
-	CategoryTitle   string             `json:"categoryTitle"`
-	CustomerInfo    OrderCustomerInfo  `json:"customerInfo"`
-	BookingInfo     OrderBookingInfo   `json:"bookingInfo"`
-	Services        []OrderServiceItem `json:"services"`
-	Addons          []OrderAddonItem   `json:"addons,omitempty"`
-	SpecialNotes    string             `json:"specialNotes,omitempty"`
-	TotalPrice      float64            `json:"totalPrice"`
-	ProviderPayout  float64            `json:"providerPayout"`
-	FormattedPayout string             `json:"formattedPayout"`
-	Status          OrderStatusInfo    `json:"status"`
-	Rating          *OrderRatingInfo   `json:"rating,omitempty"`
-	CreatedAt       time.Time          `json:"createdAt"`
-	UpdatedAt       time.Time          `json:"updatedAt"`
+	ID               string             `json:"id"`
+	OrderNumber      string             `json:"orderNumber"`
+	CategorySlug     string             `json:"categorySlug"`
+	CategoryTitle    string             `json:"categoryTitle"`
+	CustomerInfo     OrderCustomerInfo  `json:"customerInfo"`
+	BookingInfo      OrderBookingInfo   `json:"bookingInfo"`
+	Services         []OrderServiceItem `json:"services"`
+	Addons           []OrderAddonItem   `json:"addons,omitempty"`
+	SpecialNotes     string             `json:"specialNotes,omitempty"`
+	TotalPrice       float64            `json:"totalPrice"`
+	ProviderPayout   float64            `json:"providerPayout"`
+	FormattedPayout  string             `json:"formattedPayout"`
+	PayoutStatus     string             `json:"payoutStatus,omitempty"`
+	SettlementStatus string             `json:"settlementStatus,omitempty"`
+	Status           OrderStatusInfo    `json:"status"`
+	Rating           *OrderRatingInfo   `json:"rating,omitempty"`
+	IsMultiSession   bool               `json:"isMultiSession"`
+	SessionCount     int                `json:"sessionCount,omitempty"`
+	ProgressPercent  int                `json:"progressPercent,omitempty"`
+	CreatedAt        time.Time          `json:"createdAt"`
+	UpdatedAt        time.Time          `json:"updatedAt"`
 }
 
 type OrderStatusInfo struct {
 	Current       string     `json:"current"`
 	DisplayStatus string     `json:"displayStatus"`
 	AcceptedAt    *time.Time `json:"acceptedAt,omitempty"`
+	ArrivedAt     *time.Time `json:"arrivedAt,omitempty"`
 	StartedAt     *time.Time `json:"startedAt,omitempty"`
 	CompletedAt   *time.Time `json:"completedAt,omitempty"`
 	CanStart      bool       `json:"canStart"`
@@ -144,17 +158,19 @@ type OrderRatingInfo struct {
 }
 
 type ProviderOrderListResponse struct {
-	ID              string           `json:"id"`
-	OrderNumber     string           `json:"orderNumber"`
-	CategorySlug    string           `json:"categorySlug"`
-	CategoryTitle   string           `json:"categoryTitle"`
-	CustomerName    string           `json:"customerName"`
-	BookingInfo     OrderBookingInfo `json:"bookingInfo"`
-	ProviderPayout  float64          `json:"providerPayout"`
-	FormattedPayout string           `json:"formattedPayout"`
-	Status          string           `json:"status"`
-	DisplayStatus   string           `json:"displayStatus"`
-	CreatedAt       time.Time        `json:"createdAt"`
+	ID               string           `json:"id"`
+	OrderNumber      string           `json:"orderNumber"`
+	CategorySlug     string           `json:"categorySlug"`
+	CategoryTitle    string           `json:"categoryTitle"`
+	CustomerName     string           `json:"customerName"`
+	BookingInfo      OrderBookingInfo `json:"bookingInfo"`
+	ProviderPayout   float64          `json:"providerPayout"`
+	FormattedPayout  string           `json:"formattedPayout"`
+	PayoutStatus     string           `json:"payoutStatus,omitempty"`
+	SettlementStatus string           `json:"settlementStatus,omitempty"`
+	Status           string           `json:"status"`
+	DisplayStatus    string           `json:"displayStatus"`
+	CreatedAt        time.Time        `json:"createdAt"`
 }
 
 type EarningsSummaryResponse struct {
@@ -238,10 +254,6 @@ func FormatPrice(price float64) string {
 	return fmt.Sprintf("$%.2f", price)
 }
 
-func CalculateProviderPayout(totalPrice float64) float64 {
-	return shared.RoundToTwoDecimals(totalPrice * (1 - shared.PlatformCommissionRate))
-}
-
 func ToOrderBookingInfo(info models.BookingInfo) OrderBookingInfo {
 	var preferred string
 	if !info.PreferredTime.IsZero() {
@@ -314,20 +326,29 @@ func ToServiceCategoryResponses(categories []*models.ProviderServiceCategory) []
 	return responses
 }
 
-func ToAvailableOrderResponse(order *models.ServiceOrderNew, distance *float64) AvailableOrderResponse {
-	providerPayout := CalculateProviderPayout(order.TotalPrice)
+func ToOrderCustomerInfo(info models.CustomerInfo, preferredScript string) OrderCustomerInfo {
+	customer := OrderCustomerInfo{
+		Name:          info.Name,
+		Address:       translit.ForScript(info.Address, info.NormalizedAddress, preferredScript),
+		AddressScript: info.AddressScript,
+		Lat:           info.Lat,
+		Lng:           info.Lng,
+	}
+	if customer.Address != info.Address {
+		customer.OriginalAddress = info.Address
+	}
+	return customer
+}
+
+func ToAvailableOrderResponse(order *models.ServiceOrderNew, distance *float64, preferredScript string) AvailableOrderResponse {
+	providerPayout := order.ProviderPayout()
 
 	return AvailableOrderResponse{
-		ID:            order.ID,
-		OrderNumber:   order.OrderNumber,
-		CategorySlug:  order.CategorySlug,
-		CategoryTitle: GetCategoryTitle(order.CategorySlug),
-		CustomerInfo: OrderCustomerInfo{
-			Name:    order.CustomerInfo.Name,
-			Address: order.CustomerInfo.Address,
-			Lat:     order.CustomerInfo.Lat,
-			Lng:     order.CustomerInfo.Lng,
-		},
+		ID:              order.ID,
+		OrderNumber:     order.OrderNumber,
+		CategorySlug:    order.CategorySlug,
+		CategoryTitle:   GetCategoryTitle(order.CategorySlug),
+		CustomerInfo:    ToOrderCustomerInfo(order.CustomerInfo, preferredScript),
 		BookingInfo:     ToOrderBookingInfo(order.BookingInfo),
 		Services:        ToOrderServiceItems(order.SelectedServices),
 		Addons:          ToOrderAddonItems(order.SelectedAddons),
@@ -341,21 +362,18 @@ func ToAvailableOrderResponse(order *models.ServiceOrderNew, distance *float64)
 	}
 }
 
-func ToProviderOrderResponse(order *models.ServiceOrderNew) *ProviderOrderResponse {
-	providerPayout := CalculateProviderPayout(order.TotalPrice)
+func ToProviderOrderResponse(order *models.ServiceOrderNew, preferredScript string) *ProviderOrderResponse {
+	providerPayout := order.ProviderPayout()
+
+	customer := ToOrderCustomerInfo(order.CustomerInfo, preferredScript)
+	customer.Phone = order.CustomerInfo.Phone
 
 	response := &ProviderOrderResponse{
-		ID:            order.ID,
-		OrderNumber:   order.OrderNumber,
-		CategorySlug:  order.CategorySlug,
-		CategoryTitle: GetCategoryTitle(order.CategorySlug),
-		CustomerInfo: OrderCustomerInfo{
-			Name:    order.CustomerInfo.Name,
-			Phone:   order.CustomerInfo.Phone, 
-			Address: order.CustomerInfo.Address,
-			Lat:     order.CustomerInfo.Lat,
-			Lng:     order.CustomerInfo.Lng,
-		},
+		ID:              order.ID,
+		OrderNumber:     order.OrderNumber,
+		CategorySlug:    order.CategorySlug,
+		CategoryTitle:   GetCategoryTitle(order.CategorySlug),
+		CustomerInfo:    customer,
 		BookingInfo:     ToOrderBookingInfo(order.BookingInfo),
 		Services:        ToOrderServiceItems(order.SelectedServices),
 		Addons:          ToOrderAddonItems(order.SelectedAddons),
@@ -367,14 +385,26 @@ func ToProviderOrderResponse(order *models.ServiceOrderNew) *ProviderOrderRespon
 			Current:       order.Status,
 			DisplayStatus: GetDisplayStatus(order.Status),
 			AcceptedAt:    order.ProviderAcceptedAt,
+			ArrivedAt:     order.ProviderArrivedAt,
 			StartedAt:     order.ProviderStartedAt,
 			CompletedAt:   order.CompletedAt,
-			CanStart:      order.Status == shared.OrderStatusAccepted,
-			CanComplete:   order.Status == shared.OrderStatusInProgress,
+			CanStart:      order.Status == shared.OrderStatusAccepted && !order.IsMultiSession,
+			CanComplete:   order.Status == shared.OrderStatusInProgress && !order.IsMultiSession,
 			CanRate:       order.Status == shared.OrderStatusCompleted && order.ProviderRating == nil,
 		},
-		CreatedAt: order.CreatedAt,
-		UpdatedAt: order.UpdatedAt,
+		IsMultiSession: order.IsMultiSession,
+		CreatedAt:      order.CreatedAt,
+		UpdatedAt:      order.UpdatedAt,
+	}
+	if order.PayoutStatus != nil {
+		response.PayoutStatus = *order.PayoutStatus
+	}
+	if order.SettlementStatus != nil {
+		response.SettlementStatus = *order.SettlementStatus
+	}
+	if order.IsMultiSession {
+		response.SessionCount = order.SessionCount
+		response.ProgressPercent = order.ProgressPercent
 	}
 
 	if order.Status == shared.OrderStatusCompleted {
@@ -392,9 +422,9 @@ func ToProviderOrderResponse(order *models.ServiceOrderNew) *ProviderOrderRespon
 }
 
 func ToProviderOrderListResponse(order *models.ServiceOrderNew) ProviderOrderListResponse {
-	providerPayout := CalculateProviderPayout(order.TotalPrice)
+	providerPayout := order.ProviderPayout()
 
-	return ProviderOrderListResponse{
+	listResponse := ProviderOrderListResponse{
 		ID:              order.ID,
 		OrderNumber:     order.OrderNumber,
 		CategorySlug:    order.CategorySlug,
@@ -407,6 +437,13 @@ func ToProviderOrderListResponse(order *models.ServiceOrderNew) ProviderOrderLis
 		DisplayStatus:   GetDisplayStatus(order.Status),
 		CreatedAt:       order.CreatedAt,
 	}
+	if order.PayoutStatus != nil {
+		listResponse.PayoutStatus = *order.PayoutStatus
+	}
+	if order.SettlementStatus != nil {
+		listResponse.SettlementStatus = *order.SettlementStatus
+	}
+	return listResponse
 }
 
 func ToProviderOrderListResponses(orders []*models.ServiceOrderNew) []ProviderOrderListResponse {
@@ -416,3 +453,112 @@ func ToProviderOrderListResponses(orders []*models.ServiceOrderNew) []ProviderOr
 	}
 	return responses
 }
+
+type CrewMemberResponse struct {
+	ID            string     `json:"id"`
+	ProviderID    string     `json:"providerId"`
+	ProviderName  string     `json:"providerName"`
+	PayoutPercent float64    `json:"payoutPercent"`
+	Status        string     `json:"status"`
+	RespondedAt   *time.Time `json:"respondedAt,omitempty"`
+	CheckedInAt   *time.Time `json:"checkedInAt,omitempty"`
+	PayoutAmount  *float64   `json:"payoutAmount,omitempty"`
+	CreatedAt     time.Time  `json:"createdAt"`
+}
+
+// OrderCrewResponse is the lead provider's view of the crew on an order.
+// ReadyToStart is false while an accepted member has not checked in.
+type OrderCrewResponse struct {
+	OrderID           string                `json:"orderId"`
+	OrderNumber       string                `json:"orderNumber"`
+	Status            string                `json:"status"`
+	QuantityOfPros    int                   `json:"quantityOfPros"`
+	LeadProviderID    string                `json:"leadProviderId"`
+	LeadPayoutPercent float64               `json:"leadPayoutPercent"`
+	ReadyToStart      bool                  `json:"readyToStart"`
+	Members           []*CrewMemberResponse `json:"members"`
+}
+
+// CrewAssignmentResponse is a crew member's view of an order they were
+// invited to.
+type CrewAssignmentResponse struct {
+	ID            string            `json:"id"`
+	OrderID       string            `json:"orderId"`
+	OrderNumber   string            `json:"orderNumber"`
+	OrderStatus   string            `json:"orderStatus"`
+	CategorySlug  string            `json:"categorySlug"`
+	CategoryTitle string            `json:"categoryTitle"`
+	CustomerInfo  OrderCustomerInfo `json:"customerInfo"`
+	BookingInfo   OrderBookingInfo  `json:"bookingInfo"`
+	PayoutPercent float64           `json:"payoutPercent"`
+	Status        string            `json:"status"`
+	RespondedAt   *time.Time        `json:"respondedAt,omitempty"`
+	CheckedInAt   *time.Time        `json:"checkedInAt,omitempty"`
+	PayoutAmount  *float64          `json:"payoutAmount,omitempty"`
+	CreatedAt     time.Time         `json:"createdAt"`
+}
+
+func ToCrewMemberResponse(member *models.OrderCrewMember) *CrewMemberResponse {
+	resp := &CrewMemberResponse{
+		ID:            member.ID,
+		ProviderID:    member.ProviderID,
+		PayoutPercent: member.PayoutPercent,
+		Status:        string(member.Status),
+		RespondedAt:   member.RespondedAt,
+		CheckedInAt:   member.CheckedInAt,
+		PayoutAmount:  member.PayoutAmount,
+		CreatedAt:     member.CreatedAt,
+	}
+	if member.Provider != nil && member.Provider.User != nil {
+		resp.ProviderName = member.Provider.User.Name
+	}
+	return resp
+}
+
+func ToOrderCrewResponse(order *models.ServiceOrderNew, members []*models.OrderCrewMember) *OrderCrewResponse {
+	resp := &OrderCrewResponse{
+		OrderID:           order.ID,
+		OrderNumber:       order.OrderNumber,
+		Status:            order.Status,
+		QuantityOfPros:    order.BookingInfo.QuantityOfPros,
+		LeadPayoutPercent: 100,
+		ReadyToStart:      true,
+		Members:           make([]*CrewMemberResponse, len(members)),
+	}
+	if order.AssignedProviderID != nil {
+		resp.LeadProviderID = *order.AssignedProviderID
+	}
+	for i, member := range members {
+		resp.Members[i] = ToCrewMemberResponse(member)
+		if member.IsOpen() {
+			resp.LeadPayoutPercent -= member.PayoutPercent
+		}
+		if member.Status == models.CrewStatusAccepted && member.CheckedInAt == nil {
+			resp.ReadyToStart = false
+		}
+	}
+	resp.LeadPayoutPercent = shared.RoundToTwoDecimals(resp.LeadPayoutPercent)
+	return resp
+}
+
+func ToCrewAssignmentResponse(member *models.OrderCrewMember, preferredScript string) *CrewAssignmentResponse {
+	resp := &CrewAssignmentResponse{
+		ID:            member.ID,
+		OrderID:       member.OrderID,
+		PayoutPercent: member.PayoutPercent,
+		Status:        string(member.Status),
+		RespondedAt:   member.RespondedAt,
+		CheckedInAt:   member.CheckedInAt,
+		PayoutAmount:  member.PayoutAmount,
+		CreatedAt:     member.CreatedAt,
+	}
+	if order := member.Order; order != nil {
+		resp.OrderNumber = order.OrderNumber
+		resp.OrderStatus = order.Status
+		resp.CategorySlug = order.CategorySlug
+		resp.CategoryTitle = GetCategoryTitle(order.CategorySlug)
+		resp.CustomerInfo = ToOrderCustomerInfo(order.CustomerInfo, preferredScript)
+		resp.BookingInfo = ToOrderBookingInfo(order.BookingInfo)
+	}
+	return resp
+}
@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+	"github.com/umar5678/go-backend/internal/services/eventbus"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+func (s *service) SetEventPublisher(publisher shared.EventPublisher) {
+	s.events = publisher
+}
+
+func (s *service) SetProofChecker(checker shared.ProofChecker) {
+	s.proofs = checker
+}
+
+func (s *service) SetLocationSharing(sharing shared.LocationSharing) {
+	s.locationSharing = sharing
+}
+
+// stopLocationSharing ends the live location the provider shared on the way
+// to the order once the job has started.
+func (s *service) stopLocationSharing(ctx context.Context, order *models.ServiceOrderNew) {
+	if s.locationSharing != nil {
+		s.locationSharing.StopProviderLocationSharing(ctx, order)
+	}
+}
+
+func (s *service) publishOrderAssigned(ctx context.Context, order *models.ServiceOrderNew, acceptedAt time.Time) {
+	if s.events == nil {
+		return
+	}
+	payload := shared.OrderAssignedEvent(order, nil, shared.RoleProvider, acceptedAt)
+	if err := s.events.Publish(ctx, eventbus.OrderAssigned, order.ID, payload); err != nil {
+		logger.Warn("failed to publish order assigned event", "error", err, "orderID", order.ID)
+	}
+}
+
+func (s *service) publishOrderCompleted(ctx context.Context, order *models.ServiceOrderNew) {
+	if s.events == nil {
+		return
+	}
+	if err := s.events.Publish(ctx, eventbus.OrderCompleted, order.ID, shared.OrderCompletedEvent(order)); err != nil {
+		logger.Warn("failed to publish order completed event", "error", err, "orderID", order.ID)
+	}
+}
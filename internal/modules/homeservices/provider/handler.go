@@ -89,6 +89,90 @@ func (h *Handler) UpdateAvailability(c *gin.Context) {
 	response.Success(c, nil, "Availability updated successfully")
 }
 
+// GetOnboardingStatus godoc
+// @Summary Get onboarding status
+// @Description Required documents, background check and approval status for the current provider
+// @Tags Provider - Onboarding
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=serviceproviders.OnboardingStatus}
+// @Failure 401 {object} response.Response
+// @Router /provider/onboarding [get]
+func (h *Handler) GetOnboardingStatus(c *gin.Context) {
+	providerID, err := h.getProviderIDFromContext(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	status, err := h.service.GetOnboardingStatus(c.Request.Context(), providerID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, status, "Onboarding status retrieved successfully")
+}
+
+// SubmitForReview godoc
+// @Summary Submit onboarding for admin review
+// @Description Submit the provider application once all required documents are uploaded through /documents/upload
+// @Tags Provider - Onboarding
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=serviceproviders.OnboardingStatus}
+// @Failure 400 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /provider/onboarding/submit [post]
+func (h *Handler) SubmitForReview(c *gin.Context) {
+	providerID, err := h.getProviderIDFromContext(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	status, err := h.service.SubmitForReview(c.Request.Context(), providerID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, status, "Application submitted for review")
+}
+
+// UpdateAddressScript godoc
+// @Summary Update address script preference
+// @Description Choose whether customer addresses in order payloads are shown transliterated to Latin or as the customer typed them
+// @Tags Provider - Profile
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.UpdateAddressScriptRequest true "Address script preference"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /provider/address-script [patch]
+func (h *Handler) UpdateAddressScript(c *gin.Context) {
+	providerID, err := h.getProviderIDFromContext(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.UpdateAddressScriptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	if err := h.service.UpdateAddressScript(c.Request.Context(), providerID, req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, nil, "Address script updated successfully")
+}
+
 // GetServiceCategories godoc
 // @Summary Get service categories
 // @Description Get provider's registered service categories. Returns empty list if provider is still in registration process.
@@ -361,6 +445,34 @@ func (h *Handler) GetMyOrderDetail(c *gin.Context) {
 	response.Success(c, order, "Order retrieved successfully")
 }
 
+// GetMyOrderFeeBreakdown godoc
+// @Summary Get my order fee breakdown
+// @Description Get the customer price, platform fee and provider share for an order
+// @Tags Provider - Orders
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /provider/orders/{id}/fee-breakdown [get]
+func (h *Handler) GetMyOrderFeeBreakdown(c *gin.Context) {
+	providerID, err := h.getProviderIDFromContext(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	orderID := c.Param("id")
+
+	breakdown, err := h.service.GetMyOrderFeeBreakdown(c.Request.Context(), providerID, orderID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, breakdown, "Fee breakdown retrieved successfully")
+}
+
 // AcceptOrder godoc
 // @Summary Accept an order
 // @Description Accept an available order
@@ -426,6 +538,33 @@ func (h *Handler) RejectOrder(c *gin.Context) {
 	response.Success(c, nil, "Order rejected successfully")
 }
 
+// MarkArrived godoc
+// @Summary Mark arrival at the service address
+// @Description Record that the provider has reached the customer. Arrival is detected automatically from shared location; use this when GPS is unreliable.
+// @Tags Provider - Orders
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} response.Response{data=dto.ProviderOrderResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /provider/orders/{id}/arrived [post]
+func (h *Handler) MarkArrived(c *gin.Context) {
+	providerID, err := h.getProviderIDFromContext(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	order, err := h.service.MarkArrived(c.Request.Context(), providerID, c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, order, "Arrival recorded")
+}
+
 // StartOrder godoc
 // @Summary Start an order
 // @Description Mark an accepted order as in progress
@@ -461,6 +600,105 @@ func (h *Handler) StartOrder(c *gin.Context) {
 	response.Success(c, order, "Order started successfully")
 }
 
+// GetOrderSessions godoc
+// @Summary Get multi-day order sessions
+// @Description Per-day schedule, approval state and payment milestones of a multi-day order
+// @Tags Provider - Orders
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} response.Response{data=shared.OrderSessionsResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /provider/orders/{id}/sessions [get]
+func (h *Handler) GetOrderSessions(c *gin.Context) {
+	providerID, err := h.getProviderIDFromContext(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	result, err := h.service.GetOrderSessions(c.Request.Context(), providerID, c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Order sessions retrieved successfully")
+}
+
+// CheckInSession godoc
+// @Summary Check in to a session
+// @Description Start the next session of a multi-day order with the customer's PIN. The previous session must be approved by the customer.
+// @Tags Provider - Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param sessionId path string true "Session ID"
+// @Param request body dto.CheckInSessionRequest true "Customer PIN"
+// @Success 200 {object} response.Response{data=shared.OrderSessionsResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /provider/orders/{id}/sessions/{sessionId}/check-in [post]
+func (h *Handler) CheckInSession(c *gin.Context) {
+	providerID, err := h.getProviderIDFromContext(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.CheckInSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	result, err := h.service.CheckInSession(c.Request.Context(), providerID, c.Param("id"), c.Param("sessionId"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Checked in to session")
+}
+
+// CheckOutSession godoc
+// @Summary Check out of a session
+// @Description End the current session with a work summary and overall progress; the customer then approves it or requests changes
+// @Tags Provider - Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param sessionId path string true "Session ID"
+// @Param request body dto.CheckOutSessionRequest true "Work summary"
+// @Success 200 {object} response.Response{data=shared.OrderSessionsResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /provider/orders/{id}/sessions/{sessionId}/check-out [post]
+func (h *Handler) CheckOutSession(c *gin.Context) {
+	providerID, err := h.getProviderIDFromContext(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.CheckOutSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	result, err := h.service.CheckOutSession(c.Request.Context(), providerID, c.Param("id"), c.Param("sessionId"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Checked out of session, awaiting customer approval")
+}
+
 // CompleteOrder godoc
 // @Summary Complete an order
 // @Description Mark an in-progress order as completed
@@ -591,3 +829,430 @@ func (h *Handler) GetEarnings(c *gin.Context) {
 
 	response.Success(c, earnings, "Earnings retrieved successfully")
 }
+
+// ProposeReschedule godoc
+// @Summary Propose a new time for an order
+// @Description Ask the customer to move an assigned order to a new date and time. The order keeps its booking until the customer accepts.
+// @Tags Provider - Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param request body dto.RescheduleOrderRequest true "Proposed date and time"
+// @Success 200 {object} response.Response{data=shared.RescheduleResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /provider/orders/{id}/reschedule [post]
+func (h *Handler) ProposeReschedule(c *gin.Context) {
+	providerID, err := h.getProviderIDFromContext(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.RescheduleOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	result, err := h.service.ProposeReschedule(c.Request.Context(), providerID, c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Reschedule proposal sent to the customer")
+}
+
+// GetOrderReschedules godoc
+// @Summary List an order's reschedules
+// @Description Get the reschedule requests made on an assigned order, newest first
+// @Tags Provider - Orders
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} response.Response{data=shared.OrderReschedulesResponse}
+// @Failure 404 {object} response.Response
+// @Router /provider/orders/{id}/reschedules [get]
+func (h *Handler) GetOrderReschedules(c *gin.Context) {
+	providerID, err := h.getProviderIDFromContext(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	result, err := h.service.GetOrderReschedules(c.Request.Context(), providerID, c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Reschedules retrieved successfully")
+}
+
+// ConfirmReschedule godoc
+// @Summary Confirm a customer's reschedule request
+// @Description Accept the new date and time the customer asked for; the order moves to it
+// @Tags Provider - Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param rescheduleId path string true "Reschedule ID"
+// @Param request body dto.RespondRescheduleRequest false "Optional note"
+// @Success 200 {object} response.Response{data=shared.RescheduleResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /provider/orders/{id}/reschedules/{rescheduleId}/confirm [post]
+func (h *Handler) ConfirmReschedule(c *gin.Context) {
+	providerID, err := h.getProviderIDFromContext(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.RespondRescheduleRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+			return
+		}
+	}
+
+	result, err := h.service.ConfirmReschedule(c.Request.Context(), providerID, c.Param("id"), c.Param("rescheduleId"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Order rescheduled successfully")
+}
+
+// DeclineReschedule godoc
+// @Summary Decline a customer's reschedule request
+// @Description Turn down the customer's requested date and time; the order keeps its current booking
+// @Tags Provider - Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param rescheduleId path string true "Reschedule ID"
+// @Param request body dto.RespondRescheduleRequest false "Optional note"
+// @Success 200 {object} response.Response{data=shared.RescheduleResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /provider/orders/{id}/reschedules/{rescheduleId}/decline [post]
+func (h *Handler) DeclineReschedule(c *gin.Context) {
+	providerID, err := h.getProviderIDFromContext(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.RespondRescheduleRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+			return
+		}
+	}
+
+	result, err := h.service.DeclineReschedule(c.Request.Context(), providerID, c.Param("id"), c.Param("rescheduleId"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Reschedule declined")
+}
+
+// ProposeAdjustment godoc
+// @Summary Propose an on-site scope adjustment
+// @Description Add or remove services and addons found to be needed on site, with the price for each added item. The customer is notified and the order keeps its scope and price until they approve.
+// @Tags Provider - Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param request body dto.ProposeAdjustmentRequest true "Items to add or remove"
+// @Success 200 {object} response.Response{data=shared.AdjustmentResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /provider/orders/{id}/adjustments [post]
+func (h *Handler) ProposeAdjustment(c *gin.Context) {
+	providerID, err := h.getProviderIDFromContext(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.ProposeAdjustmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	result, err := h.service.ProposeAdjustment(c.Request.Context(), providerID, c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Adjustment sent to the customer for approval")
+}
+
+// GetOrderAdjustments godoc
+// @Summary List an order's scope adjustments
+// @Description Get the order's current items and the adjustments proposed for it, newest first
+// @Tags Provider - Orders
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} response.Response{data=shared.OrderAdjustmentsResponse}
+// @Failure 404 {object} response.Response
+// @Router /provider/orders/{id}/adjustments [get]
+func (h *Handler) GetOrderAdjustments(c *gin.Context) {
+	providerID, err := h.getProviderIDFromContext(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	result, err := h.service.GetOrderAdjustments(c.Request.Context(), providerID, c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Adjustments retrieved successfully")
+}
+
+// GetOrderCrew godoc
+// @Summary Get an order's crew
+// @Description Get the providers invited to work a multi-pro order with the lead provider, their payout shares and check-in state
+// @Tags Provider - Orders
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Success 200 {object} response.Response{data=dto.OrderCrewResponse}
+// @Failure 404 {object} response.Response
+// @Router /provider/orders/{id}/crew [get]
+func (h *Handler) GetOrderCrew(c *gin.Context) {
+	providerID, err := h.getProviderIDFromContext(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	result, err := h.service.GetOrderCrew(c.Request.Context(), providerID, c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Crew retrieved successfully")
+}
+
+// InviteCrewMember godoc
+// @Summary Invite a provider to the crew
+// @Description Invite another provider in the order's category to work a multi-pro order. The payout share defaults to an equal split between the booked pros.
+// @Tags Provider - Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param request body dto.InviteCrewMemberRequest true "Provider to invite"
+// @Success 200 {object} response.Response{data=dto.CrewMemberResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /provider/orders/{id}/crew [post]
+func (h *Handler) InviteCrewMember(c *gin.Context) {
+	providerID, err := h.getProviderIDFromContext(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.InviteCrewMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	result, err := h.service.InviteCrewMember(c.Request.Context(), providerID, c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Crew invitation sent")
+}
+
+// UpdateCrewMember godoc
+// @Summary Change a crew member's payout share
+// @Tags Provider - Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param memberId path string true "Crew member ID"
+// @Param request body dto.UpdateCrewMemberRequest true "New payout share"
+// @Success 200 {object} response.Response{data=dto.CrewMemberResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /provider/orders/{id}/crew/{memberId} [patch]
+func (h *Handler) UpdateCrewMember(c *gin.Context) {
+	providerID, err := h.getProviderIDFromContext(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	var req dto.UpdateCrewMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	result, err := h.service.UpdateCrewMember(c.Request.Context(), providerID, c.Param("id"), c.Param("memberId"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Crew member updated")
+}
+
+// RemoveCrewMember godoc
+// @Summary Remove a crew member
+// @Description Take a provider off the crew before the job starts
+// @Tags Provider - Orders
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Order ID"
+// @Param memberId path string true "Crew member ID"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /provider/orders/{id}/crew/{memberId} [delete]
+func (h *Handler) RemoveCrewMember(c *gin.Context) {
+	providerID, err := h.getProviderIDFromContext(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.service.RemoveCrewMember(c.Request.Context(), providerID, c.Param("id"), c.Param("memberId")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, nil, "Crew member removed")
+}
+
+// GetCrewAssignments godoc
+// @Summary List my crew assignments
+// @Description Get the orders the provider has been invited to work as a crew member, newest first
+// @Tags Provider - Crew
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} response.Response{data=[]dto.CrewAssignmentResponse}
+// @Router /provider/crew [get]
+func (h *Handler) GetCrewAssignments(c *gin.Context) {
+	providerID, err := h.getProviderIDFromContext(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	result, err := h.service.GetCrewAssignments(c.Request.Context(), providerID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Crew assignments retrieved successfully")
+}
+
+// AcceptCrewInvite godoc
+// @Summary Accept a crew invitation
+// @Tags Provider - Crew
+// @Produce json
+// @Security BearerAuth
+// @Param memberId path string true "Crew member ID"
+// @Success 200 {object} response.Response{data=dto.CrewAssignmentResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /provider/crew/{memberId}/accept [post]
+func (h *Handler) AcceptCrewInvite(c *gin.Context) {
+	providerID, err := h.getProviderIDFromContext(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	result, err := h.service.AcceptCrewInvite(c.Request.Context(), providerID, c.Param("memberId"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Crew invitation accepted")
+}
+
+// DeclineCrewInvite godoc
+// @Summary Decline a crew invitation
+// @Tags Provider - Crew
+// @Produce json
+// @Security BearerAuth
+// @Param memberId path string true "Crew member ID"
+// @Success 200 {object} response.Response{data=dto.CrewAssignmentResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /provider/crew/{memberId}/decline [post]
+func (h *Handler) DeclineCrewInvite(c *gin.Context) {
+	providerID, err := h.getProviderIDFromContext(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	result, err := h.service.DeclineCrewInvite(c.Request.Context(), providerID, c.Param("memberId"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Crew invitation declined")
+}
+
+// CheckInCrew godoc
+// @Summary Check in to a crew job
+// @Description Record arrival on site. The lead provider can start the job once every accepted crew member has checked in.
+// @Tags Provider - Crew
+// @Produce json
+// @Security BearerAuth
+// @Param memberId path string true "Crew member ID"
+// @Success 200 {object} response.Response{data=dto.CrewAssignmentResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /provider/crew/{memberId}/check-in [post]
+func (h *Handler) CheckInCrew(c *gin.Context) {
+	providerID, err := h.getProviderIDFromContext(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	result, err := h.service.CheckInCrew(c.Request.Context(), providerID, c.Param("memberId"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Checked in")
+}
@@ -0,0 +1,31 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/provider/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+// ConfigurePreferredProviders sets how long a new order is shown only to the
+// customer's preferred providers. Without it orders are never held back.
+func (s *service) ConfigurePreferredProviders(cfg config.FavoritesConfig) {
+	s.preferredHeadStart = cfg.ProviderHeadStart
+}
+
+// applyPreferences lists orders from customers who prefer the provider first
+// and holds back orders reserved for other providers.
+func (s *service) applyPreferences(ctx context.Context, providerID string, query *dto.ListAvailableOrdersQuery) {
+	query.HeadStart = s.preferredHeadStart
+
+	customerIDs, err := s.repo.GetPreferringCustomerIDs(ctx, providerID)
+	if err != nil {
+		logger.Error("failed to get preferring customers", "error", err, "providerID", providerID)
+		return
+	}
+	query.PreferredBy = make(map[string]bool, len(customerIDs))
+	for _, id := range customerIDs {
+		query.PreferredBy[id] = true
+	}
+}
@@ -0,0 +1,29 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+// RatingAggregator keeps customers' stored ratings in step with the ratings
+// providers leave. It is optional; without one they are only corrected by the
+// nightly reconciliation.
+type RatingAggregator interface {
+	RefreshCustomerRating(ctx context.Context, customerID string) error
+}
+
+func (s *service) SetRatingAggregator(aggregator RatingAggregator) {
+	s.ratingAggregator = aggregator
+}
+
+func (s *service) refreshCustomerRating(customerID string) {
+	if s.ratingAggregator == nil {
+		return
+	}
+	go func() {
+		if err := s.ratingAggregator.RefreshCustomerRating(context.Background(), customerID); err != nil {
+			logger.Error("failed to refresh customer rating", "error", err, "customerID", customerID)
+		}
+	}()
+}
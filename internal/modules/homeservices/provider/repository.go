@@ -7,6 +7,7 @@ import (
 
 	"gorm.io/gorm"
 
+	"github.com/umar5678/go-backend/internal/database"
 	"github.com/umar5678/go-backend/internal/models"
 	"github.com/umar5678/go-backend/internal/modules/homeservices/provider/dto"
 	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
@@ -17,6 +18,7 @@ type Repository interface {
 	GetProvider(ctx context.Context, providerID string) (*models.ServiceProviderProfile, error)
 	GetProviderByUserID(ctx context.Context, userID string) (*models.ServiceProviderProfile, error)
 	CreateProvider(ctx context.Context, provider *models.ServiceProviderProfile) error
+	UpdateAddressScript(ctx context.Context, providerID, script string) error
 
 	GetProviderCategories(ctx context.Context, providerID string) ([]*models.ProviderServiceCategory, error)
 	GetProviderCategory(ctx context.Context, providerID, categorySlug string) (*models.ProviderServiceCategory, error)
@@ -26,6 +28,7 @@ type Repository interface {
 	GetProviderCategorySlugs(ctx context.Context, providerID string) ([]string, error)
 
 	GetAvailableOrders(ctx context.Context, providerID string, categorySlugs []string, query dto.ListAvailableOrdersQuery) ([]*models.ServiceOrderNew, int64, error)
+	GetPreferringCustomerIDs(ctx context.Context, providerID string) ([]string, error)
 	GetAvailableOrderByID(ctx context.Context, providerID, orderID string, categorySlugs []string) (*models.ServiceOrderNew, error)
 
 	GetProviderOrders(ctx context.Context, providerID string, query dto.ListMyOrdersQuery) ([]*models.ServiceOrderNew, int64, error)
@@ -42,8 +45,31 @@ type Repository interface {
 
 	CreateStatusHistory(ctx context.Context, history *models.OrderStatusHistory) error
 
+	GetOrderSessions(ctx context.Context, orderID string) ([]*models.ServiceOrderSession, error)
+	UpdateSession(ctx context.Context, session *models.ServiceOrderSession) error
+
 	RecordRejection(ctx context.Context, orderID, providerID, reason string) error
 	HasProviderRejected(ctx context.Context, orderID, providerID string) (bool, error)
+
+	CreateReschedule(ctx context.Context, reschedule *models.OrderReschedule) error
+	UpdateReschedule(ctx context.Context, reschedule *models.OrderReschedule) error
+	GetOrderReschedule(ctx context.Context, orderID, rescheduleID string) (*models.OrderReschedule, error)
+	GetPendingReschedule(ctx context.Context, orderID string) (*models.OrderReschedule, error)
+	GetOrderReschedules(ctx context.Context, orderID string) ([]*models.OrderReschedule, error)
+	WithdrawPendingReschedules(ctx context.Context, orderID string) error
+
+	GetActiveSurcharges(ctx context.Context, categorySlug string) ([]*models.CategorySurcharge, error)
+	CreateAdjustment(ctx context.Context, adjustment *models.OrderAdjustment) error
+	GetPendingAdjustment(ctx context.Context, orderID string) (*models.OrderAdjustment, error)
+	GetOrderAdjustments(ctx context.Context, orderID string) ([]*models.OrderAdjustment, error)
+	WithdrawPendingAdjustments(ctx context.Context, orderID string) error
+
+	CreateCrewMember(ctx context.Context, member *models.OrderCrewMember) error
+	UpdateCrewMember(ctx context.Context, member *models.OrderCrewMember) error
+	GetCrewMember(ctx context.Context, memberID string) (*models.OrderCrewMember, error)
+	GetOrderCrew(ctx context.Context, orderID string) ([]*models.OrderCrewMember, error)
+	GetProviderCrewAssignments(ctx context.Context, providerID string) ([]*models.OrderCrewMember, error)
+	CloseCrew(ctx context.Context, orderID string, statuses []models.OrderCrewStatus) error
 }
 
 type ProviderStats struct {
@@ -94,6 +120,7 @@ func (r *repository) AssignOrderToProvider(ctx context.Context, orderID, provide
 		Updates(map[string]interface{}{
 			"assigned_provider_id": providerID,
 			"status":               shared.OrderStatusAssigned,
+			"version":              gorm.Expr("version + 1"),
 			"updated_at":           now,
 		})
 
@@ -108,6 +135,7 @@ func (r *repository) AssignOrderToProvider(ctx context.Context, orderID, provide
 		Updates(map[string]interface{}{
 			"provider_id": providerID,
 			"status":      shared.OrderStatusAssigned,
+			"version":     gorm.Expr("version + 1"),
 			"updated_at":  now,
 		})
 
@@ -147,6 +175,13 @@ func (r *repository) CreateProvider(ctx context.Context, provider *models.Servic
 	return r.db.WithContext(ctx).Create(provider).Error
 }
 
+func (r *repository) UpdateAddressScript(ctx context.Context, providerID, script string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.ServiceProviderProfile{}).
+		Where("id = ?", providerID).
+		Update("preferred_address_script", script).Error
+}
+
 func (r *repository) GetProviderCategories(ctx context.Context, providerID string) ([]*models.ProviderServiceCategory, error) {
 	var categories []*models.ProviderServiceCategory
 	err := r.db.WithContext(ctx).
@@ -277,16 +312,20 @@ func (r *repository) convertLaundryOrderToServiceOrder(ctx context.Context, orde
 		Subtotal:           laundryOrder.Total,
 		TotalPrice:         totalPrice,
 		PlatformCommission: 0,
+		CommissionRate:     laundryOrder.CommissionRate,
 		AddonsTotal:        0,
 		Status:             laundryOrder.Status,
 		AssignedProviderID: laundryOrder.ProviderID,
 		CreatedAt:          laundryOrder.CreatedAt,
 		UpdatedAt:          laundryOrder.UpdatedAt,
+		Version:            laundryOrder.Version,
 		CustomerInfo: models.CustomerInfo{
-			Name:    customerName,
-			Address: laundryOrder.Address,
-			Lat:     laundryOrder.Latitude,
-			Lng:     laundryOrder.Longitude,
+			Name:              customerName,
+			Address:           laundryOrder.Address,
+			Lat:               laundryOrder.Latitude,
+			Lng:               laundryOrder.Longitude,
+			NormalizedAddress: laundryOrder.NormalizedAddress,
+			AddressScript:     laundryOrder.AddressScript,
 		},
 		BookingInfo: models.BookingInfo{
 			Date: bookingDate,
@@ -303,7 +342,7 @@ func (r *repository) GetAvailableOrders(ctx context.Context, providerID string,
 	var total int64
 
 	var serviceOrders []*models.ServiceOrderNew
-	db := r.db.WithContext(ctx).Model(&models.ServiceOrderNew{}).
+	db := r.db.WithContext(ctx).Scopes(database.ReadReplica).Model(&models.ServiceOrderNew{}).
 		Where("status IN ?", []string{shared.OrderStatusPending, shared.OrderStatusSearchingProvider}).
 		Where("category_slug IN ?", categorySlugs).
 		Where("assigned_provider_id IS NULL").
@@ -321,12 +360,17 @@ func (r *repository) GetAvailableOrders(ctx context.Context, providerID string,
 		db = db.Where("booking_info->>'date' = ?", query.Date)
 	}
 
+	if query.HeadStart > 0 {
+		db = db.Where(heldForPreferredProviderClause("service_orders.customer_id", "service_orders.category_slug"),
+			time.Now().Add(-query.HeadStart), providerID, providerID)
+	}
+
 	if err := db.Order("created_at DESC").Find(&serviceOrders).Error; err != nil {
 		logger.Error("failed to fetch service orders", "error", err)
 	}
 
 	var laundryOrders []*models.LaundryOrder
-	laundryDb := r.db.WithContext(ctx).Model(&models.LaundryOrder{}).
+	laundryDb := r.db.WithContext(ctx).Scopes(database.ReadReplica).Model(&models.LaundryOrder{}).
 		Where("status IN ?", []string{"pending", "searching_provider"}).
 		Where("category_slug IN ?", categorySlugs).
 		Where("provider_id IS NULL").
@@ -338,6 +382,11 @@ func (r *repository) GetAvailableOrders(ctx context.Context, providerID string,
 		laundryDb = laundryDb.Where("category_slug = ?", query.CategorySlug)
 	}
 
+	if query.HeadStart > 0 {
+		laundryDb = laundryDb.Where(heldForPreferredProviderClause("laundry_orders.user_id", "laundry_orders.category_slug"),
+			time.Now().Add(-query.HeadStart), providerID, providerID)
+	}
+
 	if err := laundryDb.Order("created_at DESC").Find(&laundryOrders).Error; err != nil {
 		logger.Error("failed to fetch laundry orders", "error", err)
 	}
@@ -349,13 +398,13 @@ func (r *repository) GetAvailableOrders(ctx context.Context, providerID string,
 		var customer models.User
 		customerName := ""
 		if laundryOrder.UserID != nil {
-			if err := r.db.WithContext(ctx).Where("id = ?", *laundryOrder.UserID).First(&customer).Error; err == nil {
+			if err := r.db.WithContext(ctx).Scopes(database.ReadReplica).Where("id = ?", *laundryOrder.UserID).First(&customer).Error; err == nil {
 				customerName = customer.Name
 			}
 		}
 
 		var items []*models.LaundryOrderItem
-		r.db.WithContext(ctx).Where("order_id = ?", laundryOrder.ID).Find(&items)
+		r.db.WithContext(ctx).Scopes(database.ReadReplica).Where("order_id = ?", laundryOrder.ID).Find(&items)
 
 		selectedServices := make(models.SelectedServices, 0)
 		for _, item := range items {
@@ -391,16 +440,19 @@ func (r *repository) GetAvailableOrders(ctx context.Context, providerID string,
 			Subtotal:           laundryOrder.Total,
 			TotalPrice:         totalPrice,
 			PlatformCommission: 0,
+			CommissionRate:     laundryOrder.CommissionRate,
 			AddonsTotal:        0,
 			Status:             laundryOrder.Status,
 			AssignedProviderID: laundryOrder.ProviderID,
 			CreatedAt:          laundryOrder.CreatedAt,
 			UpdatedAt:          laundryOrder.UpdatedAt,
 			CustomerInfo: models.CustomerInfo{
-				Name:    customerName,
-				Address: laundryOrder.Address,
-				Lat:     laundryOrder.Latitude,
-				Lng:     laundryOrder.Longitude,
+				Name:              customerName,
+				Address:           laundryOrder.Address,
+				Lat:               laundryOrder.Latitude,
+				Lng:               laundryOrder.Longitude,
+				NormalizedAddress: laundryOrder.NormalizedAddress,
+				AddressScript:     laundryOrder.AddressScript,
 			},
 			BookingInfo: models.BookingInfo{
 				Date: bookingDate,
@@ -414,6 +466,12 @@ func (r *repository) GetAvailableOrders(ctx context.Context, providerID string,
 
 	allOrders = append(allOrders, serviceOrders...)
 
+	if len(query.PreferredBy) > 0 {
+		sort.SliceStable(allOrders, func(i, j int) bool {
+			return query.PreferredBy[allOrders[i].CustomerID] && !query.PreferredBy[allOrders[j].CustomerID]
+		})
+	}
+
 	total = int64(len(allOrders))
 
 	orderClause := query.SortBy
@@ -445,6 +503,38 @@ func (r *repository) GetAvailableOrders(ctx context.Context, providerID string,
 	return paginatedOrders, total, nil
 }
 
+// heldForPreferredProviderClause keeps an order away from a provider the
+// customer does not prefer while the order is inside its head start and one
+// of the customer's preferred providers is free to take it. Its arguments are
+// the head start cut-off and the provider's ID, twice.
+func heldForPreferredProviderClause(customerColumn, categoryColumn string) string {
+	return `NOT (
+		created_at > ?
+		AND NOT EXISTS (SELECT 1 FROM preferred_providers pp WHERE pp.customer_id = ` + customerColumn + ` AND pp.provider_id = ?)
+		AND EXISTS (
+			SELECT 1 FROM preferred_providers pp
+			JOIN service_provider_profiles sp ON sp.id = pp.provider_id
+			WHERE pp.customer_id = ` + customerColumn + `
+				AND pp.provider_id <> ?
+				AND sp.status = 'active' AND sp.is_verified AND sp.is_available AND sp.deleted_at IS NULL
+				AND (sp.service_type = ` + categoryColumn + ` OR sp.service_category = ` + categoryColumn + `
+					OR EXISTS (SELECT 1 FROM provider_service_categories psc
+						WHERE psc.provider_id = sp.id AND psc.category_slug = ` + categoryColumn + ` AND psc.is_active))
+				AND NOT EXISTS (SELECT 1 FROM service_orders busy
+					WHERE busy.assigned_provider_id = sp.id AND busy.status IN ('assigned', 'accepted', 'in_progress'))
+		)
+	)`
+}
+
+func (r *repository) GetPreferringCustomerIDs(ctx context.Context, providerID string) ([]string, error) {
+	var customerIDs []string
+	err := r.db.WithContext(ctx).
+		Model(&models.PreferredProvider{}).
+		Where("provider_id = ?", providerID).
+		Pluck("customer_id", &customerIDs).Error
+	return customerIDs, err
+}
+
 func (r *repository) GetAvailableOrderByID(ctx context.Context, providerID, orderID string, categorySlugs []string) (*models.ServiceOrderNew, error) {
 	var order models.ServiceOrderNew
 	err := r.db.WithContext(ctx).
@@ -577,16 +667,19 @@ func (r *repository) GetProviderOrders(ctx context.Context, providerID string, q
 			Subtotal:           laundryOrder.Total,
 			TotalPrice:         totalPrice,
 			PlatformCommission: 0,
+			CommissionRate:     laundryOrder.CommissionRate,
 			AddonsTotal:        0,
 			Status:             laundryOrder.Status,
 			AssignedProviderID: laundryOrder.ProviderID,
 			CreatedAt:          laundryOrder.CreatedAt,
 			UpdatedAt:          laundryOrder.UpdatedAt,
 			CustomerInfo: models.CustomerInfo{
-				Name:    customerName,
-				Address: laundryOrder.Address,
-				Lat:     laundryOrder.Latitude,
-				Lng:     laundryOrder.Longitude,
+				Name:              customerName,
+				Address:           laundryOrder.Address,
+				Lat:               laundryOrder.Latitude,
+				Lng:               laundryOrder.Longitude,
+				NormalizedAddress: laundryOrder.NormalizedAddress,
+				AddressScript:     laundryOrder.AddressScript,
 			},
 
 			BookingInfo: models.BookingInfo{
@@ -725,6 +818,9 @@ func (r *repository) GetOrderByID(ctx context.Context, orderID string) (*models.
 	return nil, err
 }
 
+// UpdateOrder writes a service order, or the status and provider of a
+// laundry order mapped onto one. Either way it returns
+// shared.ErrOrderConflict if the order changed since it was read.
 func (r *repository) UpdateOrder(ctx context.Context, order *models.ServiceOrderNew) error {
 	var laundryOrder models.LaundryOrder
 	err := r.db.WithContext(ctx).Where("id = ?", order.ID).First(&laundryOrder).Error
@@ -733,6 +829,7 @@ func (r *repository) UpdateOrder(ctx context.Context, order *models.ServiceOrder
 	case nil:
 		updates := map[string]interface{}{
 			"status":     order.Status,
+			"version":    order.Version + 1,
 			"updated_at": time.Now(),
 		}
 		if order.AssignedProviderID != nil {
@@ -741,17 +838,21 @@ func (r *repository) UpdateOrder(ctx context.Context, order *models.ServiceOrder
 
 		result := r.db.WithContext(ctx).
 			Model(&models.LaundryOrder{}).
-			Where("id = ?", order.ID).
+			Where("id = ? AND version = ?", order.ID, order.Version).
 			Updates(updates)
 
 		if result.Error != nil {
 			logger.Error("failed to update laundry order", "error", result.Error, "orderID", order.ID)
 			return result.Error
 		}
+		if result.RowsAffected == 0 {
+			return shared.ErrOrderConflict
+		}
 
+		order.Version++
 		return nil
 	case gorm.ErrRecordNotFound:
-		return r.db.WithContext(ctx).Save(order).Error
+		return shared.SaveOrderVersioned(r.db.WithContext(ctx), order)
 	}
 
 	return err
@@ -765,7 +866,7 @@ func (r *repository) GetProviderStatistics(ctx context.Context, providerID strin
 	err := r.db.WithContext(ctx).
 		Model(&models.ServiceOrderNew{}).
 		Where("assigned_provider_id = ? AND status = ?", providerID, shared.OrderStatusCompleted).
-		Select("COUNT(*) as total_completed_jobs, COALESCE(SUM(total_price * 0.9), 0) as total_earnings").
+		Select("COUNT(*) as total_completed_jobs, COALESCE(SUM("+shared.ServiceOrderPayoutSQL+"), 0) as total_earnings").
 		Row().Scan(&serviceCompletedCount, &serviceEarnings)
 	if err != nil {
 		return nil, err
@@ -776,7 +877,7 @@ func (r *repository) GetProviderStatistics(ctx context.Context, providerID strin
 	err = r.db.WithContext(ctx).
 		Model(&models.LaundryOrder{}).
 		Where("provider_id = ? AND status = ?", providerID, shared.OrderStatusCompleted).
-		Select("COUNT(*) as total_completed_jobs, COALESCE(SUM(total * 0.9), 0) as total_earnings").
+		Select("COUNT(*) as total_completed_jobs, COALESCE(SUM("+shared.LaundryOrderPayoutSQL+"), 0) as total_earnings").
 		Row().Scan(&laundryCompletedCount, &laundryEarnings)
 	if err != nil {
 		return nil, err
@@ -807,7 +908,7 @@ func (r *repository) GetProviderStatistics(ctx context.Context, providerID strin
 		Model(&models.ServiceOrderNew{}).
 		Where("assigned_provider_id = ? AND status = ? AND completed_at >= ?",
 			providerID, shared.OrderStatusCompleted, today).
-		Select("COUNT(*) as today_completed_orders, COALESCE(SUM(total_price * 0.9), 0) as today_earnings").
+		Select("COUNT(*) as today_completed_orders, COALESCE(SUM("+shared.ServiceOrderPayoutSQL+"), 0) as today_earnings").
 		Row().Scan(&todayServiceCompleted, &todayServiceEarnings)
 	if err != nil {
 		return nil, err
@@ -819,7 +920,7 @@ func (r *repository) GetProviderStatistics(ctx context.Context, providerID strin
 		Model(&models.LaundryOrder{}).
 		Where("provider_id = ? AND status = ? AND updated_at >= ?",
 			providerID, shared.OrderStatusCompleted, today).
-		Select("COUNT(*) as today_completed_orders, COALESCE(SUM(total * 0.9), 0) as today_earnings").
+		Select("COUNT(*) as today_completed_orders, COALESCE(SUM("+shared.LaundryOrderPayoutSQL+"), 0) as today_earnings").
 		Row().Scan(&todayLaundryCompleted, &todayLaundryEarnings)
 	if err != nil {
 		return nil, err
@@ -838,7 +939,7 @@ func (r *repository) GetProviderEarnings(ctx context.Context, providerID string,
 		Model(&models.ServiceOrderNew{}).
 		Where("assigned_provider_id = ? AND status = ?", providerID, shared.OrderStatusCompleted).
 		Where("completed_at >= ? AND completed_at < ?", fromDate, toDate.AddDate(0, 0, 1)).
-		Select("COALESCE(SUM(total_price * 0.9), 0) as total_earnings, COUNT(*) as total_orders").
+		Select("COALESCE(SUM("+shared.ServiceOrderPayoutSQL+"), 0) as total_earnings, COUNT(*) as total_orders").
 		Row().Scan(&earnings.TotalEarnings, &earnings.TotalOrders)
 	if err != nil {
 		return nil, err
@@ -848,7 +949,7 @@ func (r *repository) GetProviderEarnings(ctx context.Context, providerID string,
 		Model(&models.ServiceOrderNew{}).
 		Where("assigned_provider_id = ? AND status = ?", providerID, shared.OrderStatusCompleted).
 		Where("completed_at >= ? AND completed_at < ?", fromDate, toDate.AddDate(0, 0, 1)).
-		Select("DATE(completed_at) as date, COALESCE(SUM(total_price * 0.9), 0) as earnings, COUNT(*) as order_count").
+		Select("DATE(completed_at) as date, COALESCE(SUM(" + shared.ServiceOrderPayoutSQL + "), 0) as earnings, COUNT(*) as order_count").
 		Group("DATE(completed_at)").
 		Order("date ASC").
 		Rows()
@@ -875,7 +976,7 @@ func (r *repository) GetCategoryEarnings(ctx context.Context, providerID string,
 		Model(&models.ServiceOrderNew{}).
 		Where("assigned_provider_id = ? AND status = ?", providerID, shared.OrderStatusCompleted).
 		Where("completed_at >= ? AND completed_at < ?", fromDate, toDate.AddDate(0, 0, 1)).
-		Select("category_slug, COALESCE(SUM(total_price * 0.9), 0) as earnings, COUNT(*) as order_count").
+		Select("category_slug, COALESCE(SUM(" + shared.ServiceOrderPayoutSQL + "), 0) as earnings, COUNT(*) as order_count").
 		Group("category_slug").
 		Order("earnings DESC").
 		Find(&categoryEarnings).Error
@@ -912,3 +1013,165 @@ func (r *repository) HasProviderRejected(ctx context.Context, orderID, providerI
 
 	return count > 0, nil
 }
+
+func (r *repository) GetOrderSessions(ctx context.Context, orderID string) ([]*models.ServiceOrderSession, error) {
+	var sessions []*models.ServiceOrderSession
+	err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("session_number ASC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+func (r *repository) UpdateSession(ctx context.Context, session *models.ServiceOrderSession) error {
+	return r.db.WithContext(ctx).Save(session).Error
+}
+
+func (r *repository) CreateReschedule(ctx context.Context, reschedule *models.OrderReschedule) error {
+	return r.db.WithContext(ctx).Create(reschedule).Error
+}
+
+func (r *repository) UpdateReschedule(ctx context.Context, reschedule *models.OrderReschedule) error {
+	return r.db.WithContext(ctx).Save(reschedule).Error
+}
+
+func (r *repository) GetOrderReschedule(ctx context.Context, orderID, rescheduleID string) (*models.OrderReschedule, error) {
+	var reschedule models.OrderReschedule
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND order_id = ?", rescheduleID, orderID).
+		First(&reschedule).Error
+	if err != nil {
+		return nil, err
+	}
+	return &reschedule, nil
+}
+
+func (r *repository) GetPendingReschedule(ctx context.Context, orderID string) (*models.OrderReschedule, error) {
+	var reschedule models.OrderReschedule
+	err := r.db.WithContext(ctx).
+		Where("order_id = ? AND status = ?", orderID, models.RescheduleStatusPending).
+		First(&reschedule).Error
+	if err != nil {
+		return nil, err
+	}
+	return &reschedule, nil
+}
+
+func (r *repository) GetOrderReschedules(ctx context.Context, orderID string) ([]*models.OrderReschedule, error) {
+	var reschedules []*models.OrderReschedule
+	err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("created_at DESC").
+		Find(&reschedules).Error
+	return reschedules, err
+}
+
+func (r *repository) WithdrawPendingReschedules(ctx context.Context, orderID string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.OrderReschedule{}).
+		Where("order_id = ? AND status = ?", orderID, models.RescheduleStatusPending).
+		Updates(map[string]interface{}{
+			"status":     models.RescheduleStatusWithdrawn,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+func (r *repository) GetActiveSurcharges(ctx context.Context, categorySlug string) ([]*models.CategorySurcharge, error) {
+	var surcharges []*models.CategorySurcharge
+	err := r.db.WithContext(ctx).
+		Where("category_slug = ? AND is_active = true", categorySlug).
+		Order("code ASC").
+		Find(&surcharges).Error
+	return surcharges, err
+}
+
+func (r *repository) CreateAdjustment(ctx context.Context, adjustment *models.OrderAdjustment) error {
+	return r.db.WithContext(ctx).Create(adjustment).Error
+}
+
+func (r *repository) GetPendingAdjustment(ctx context.Context, orderID string) (*models.OrderAdjustment, error) {
+	var adjustment models.OrderAdjustment
+	err := r.db.WithContext(ctx).
+		Where("order_id = ? AND status = ?", orderID, models.AdjustmentStatusPending).
+		First(&adjustment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &adjustment, nil
+}
+
+func (r *repository) GetOrderAdjustments(ctx context.Context, orderID string) ([]*models.OrderAdjustment, error) {
+	var adjustments []*models.OrderAdjustment
+	err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("created_at DESC").
+		Find(&adjustments).Error
+	return adjustments, err
+}
+
+func (r *repository) WithdrawPendingAdjustments(ctx context.Context, orderID string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.OrderAdjustment{}).
+		Where("order_id = ? AND status = ?", orderID, models.AdjustmentStatusPending).
+		Updates(map[string]interface{}{
+			"status":     models.AdjustmentStatusWithdrawn,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+func (r *repository) CreateCrewMember(ctx context.Context, member *models.OrderCrewMember) error {
+	return r.db.WithContext(ctx).Create(member).Error
+}
+
+func (r *repository) UpdateCrewMember(ctx context.Context, member *models.OrderCrewMember) error {
+	return r.db.WithContext(ctx).
+		Model(member).
+		Select("payout_percent", "status", "responded_at", "checked_in_at", "payout_amount", "updated_at").
+		Updates(member).Error
+}
+
+func (r *repository) GetCrewMember(ctx context.Context, memberID string) (*models.OrderCrewMember, error) {
+	var member models.OrderCrewMember
+	err := r.db.WithContext(ctx).
+		Where("id = ?", memberID).
+		Preload("Provider.User").
+		Preload("Order").
+		First(&member).Error
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+func (r *repository) GetOrderCrew(ctx context.Context, orderID string) ([]*models.OrderCrewMember, error) {
+	var members []*models.OrderCrewMember
+	err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Preload("Provider.User").
+		Order("created_at ASC").
+		Find(&members).Error
+	return members, err
+}
+
+func (r *repository) GetProviderCrewAssignments(ctx context.Context, providerID string) ([]*models.OrderCrewMember, error) {
+	var members []*models.OrderCrewMember
+	err := r.db.WithContext(ctx).
+		Where("provider_id = ?", providerID).
+		Preload("Order").
+		Order("created_at DESC").
+		Limit(100).
+		Find(&members).Error
+	return members, err
+}
+
+// CloseCrew expires the order's crew members that are in one of the given
+// statuses.
+func (r *repository) CloseCrew(ctx context.Context, orderID string, statuses []models.OrderCrewStatus) error {
+	return r.db.WithContext(ctx).
+		Model(&models.OrderCrewMember{}).
+		Where("order_id = ? AND status IN ?", orderID, statuses).
+		Updates(map[string]interface{}{
+			"status":     models.CrewStatusExpired,
+			"updated_at": time.Now(),
+		}).Error
+}
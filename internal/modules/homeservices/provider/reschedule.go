@@ -0,0 +1,166 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/provider/dto"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+	websocketutil "github.com/umar5678/go-backend/internal/websocket/websocketutils"
+	"gorm.io/gorm"
+)
+
+func (s *service) ConfigureRescheduling(cfg config.RescheduleConfig) {
+	s.reschedule = cfg
+}
+
+func (s *service) loadAssignedOrder(ctx context.Context, providerID, orderID string) (*models.ServiceOrderNew, error) {
+	order, err := s.repo.GetProviderOrderByID(ctx, providerID, orderID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, response.NotFoundError("Order")
+		}
+		return nil, response.InternalServerError("Failed to get order", err)
+	}
+	if order.AssignedProviderID == nil || *order.AssignedProviderID != providerID {
+		return nil, response.NotFoundError("Order")
+	}
+	return order, nil
+}
+
+// ProposeReschedule asks the customer to move an assigned booking to a new
+// slot. The order keeps its current slot until the customer accepts.
+func (s *service) ProposeReschedule(ctx context.Context, providerID, orderID string, req dto.RescheduleOrderRequest) (*shared.RescheduleResponse, error) {
+	order, err := s.loadAssignedOrder(ctx, providerID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if err := shared.ValidateReschedule(order, req.Date, req.Time, s.reschedule, time.Now()); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetPendingReschedule(ctx, order.ID); err == nil {
+		return nil, response.ConflictError("A reschedule request for this order is already waiting for an answer")
+	}
+
+	reschedule := shared.NewOrderReschedule(order, shared.RoleProvider, providerID, req.Date, req.Time, req.Reason)
+	if err := s.repo.CreateReschedule(ctx, reschedule); err != nil {
+		logger.Error("failed to create reschedule request", "error", err, "orderID", order.ID)
+		return nil, response.InternalServerError("Failed to propose reschedule", err)
+	}
+
+	s.notifyCustomer(order, websocket.TypeOrderRescheduleRequested, shared.RescheduleNotification(order, reschedule))
+
+	logger.Info("order reschedule proposed", "orderID", order.ID, "providerID", providerID, "rescheduleID", reschedule.ID,
+		"newDate", reschedule.NewDate, "newTime", reschedule.NewTime)
+
+	return shared.ToRescheduleResponse(reschedule), nil
+}
+
+func (s *service) GetOrderReschedules(ctx context.Context, providerID, orderID string) (*shared.OrderReschedulesResponse, error) {
+	order, err := s.loadAssignedOrder(ctx, providerID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	reschedules, err := s.repo.GetOrderReschedules(ctx, order.ID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get reschedules", err)
+	}
+	return shared.ToOrderReschedulesResponse(order, reschedules), nil
+}
+
+// ConfirmReschedule accepts the new slot a customer asked for after the
+// order was assigned.
+func (s *service) ConfirmReschedule(ctx context.Context, providerID, orderID, rescheduleID string, req dto.RespondRescheduleRequest) (*shared.RescheduleResponse, error) {
+	order, reschedule, err := s.loadCustomerRequest(ctx, providerID, orderID, rescheduleID)
+	if err != nil {
+		return nil, err
+	}
+	if err := shared.ValidateReschedule(order, reschedule.NewDate, reschedule.NewTime, s.reschedule, time.Now()); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	shared.ApplyReschedule(order, reschedule)
+	reschedule.ResponseNote = req.Note
+	reschedule.RespondedAt = &now
+
+	if err := s.repo.UpdateOrder(ctx, order); err != nil {
+		logger.Error("failed to reschedule order", "error", err, "orderID", order.ID)
+		return nil, shared.OrderUpdateError(err, "Failed to reschedule order")
+	}
+	if err := s.repo.UpdateReschedule(ctx, reschedule); err != nil {
+		logger.Error("failed to update reschedule", "error", err, "rescheduleID", reschedule.ID)
+	}
+
+	if order.WalletHoldID != nil && order.ProviderAcceptedAt == nil {
+		if until, err := shared.ParseBookingDateTime(order.BookingInfo.Date, order.BookingInfo.Time); err == nil {
+			if err := s.walletService.ExtendHold(ctx, order.CustomerID, *order.WalletHoldID, until); err != nil {
+				logger.Error("failed to extend wallet hold", "error", err, "orderID", order.ID, "holdID", *order.WalletHoldID)
+			}
+		}
+	}
+
+	s.repo.CreateStatusHistory(ctx, shared.RescheduleHistory(order, reschedule, providerID, shared.RoleProvider))
+
+	s.notifyCustomer(order, websocket.TypeOrderRescheduled, shared.RescheduleNotification(order, reschedule))
+
+	logger.Info("order rescheduled", "orderID", order.ID, "rescheduleID", reschedule.ID,
+		"newDate", order.BookingInfo.Date, "newTime", order.BookingInfo.Time, "rescheduleCount", order.RescheduleCount)
+
+	return shared.ToRescheduleResponse(reschedule), nil
+}
+
+// DeclineReschedule turns down a customer's request; the booking keeps its
+// current slot.
+func (s *service) DeclineReschedule(ctx context.Context, providerID, orderID, rescheduleID string, req dto.RespondRescheduleRequest) (*shared.RescheduleResponse, error) {
+	order, reschedule, err := s.loadCustomerRequest(ctx, providerID, orderID, rescheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	reschedule.Status = models.RescheduleStatusDeclined
+	reschedule.ResponseNote = req.Note
+	reschedule.RespondedAt = &now
+	if err := s.repo.UpdateReschedule(ctx, reschedule); err != nil {
+		return nil, response.InternalServerError("Failed to decline reschedule", err)
+	}
+
+	s.notifyCustomer(order, websocket.TypeOrderRescheduleDeclined, shared.RescheduleNotification(order, reschedule))
+
+	logger.Info("order reschedule declined", "orderID", order.ID, "rescheduleID", reschedule.ID, "by", shared.RoleProvider)
+
+	return shared.ToRescheduleResponse(reschedule), nil
+}
+
+func (s *service) loadCustomerRequest(ctx context.Context, providerID, orderID, rescheduleID string) (*models.ServiceOrderNew, *models.OrderReschedule, error) {
+	order, err := s.loadAssignedOrder(ctx, providerID, orderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	reschedule, err := s.repo.GetOrderReschedule(ctx, order.ID, rescheduleID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, response.NotFoundError("Reschedule request")
+		}
+		return nil, nil, response.InternalServerError("Failed to get reschedule request", err)
+	}
+	if reschedule.RequestedBy != shared.RoleCustomer {
+		return nil, nil, response.BadRequest("You cannot answer your own reschedule request")
+	}
+	if reschedule.Status != models.RescheduleStatusPending {
+		return nil, nil, response.BadRequest("This reschedule request has already been answered")
+	}
+	return order, reschedule, nil
+}
+
+func (s *service) notifyCustomer(order *models.ServiceOrderNew, messageType websocket.MessageType, data map[string]interface{}) {
+	if err := websocketutil.SendToUser(order.CustomerID, messageType, data); err != nil {
+		logger.Warn("failed to notify customer", "error", err, "orderID", order.ID, "type", messageType)
+	}
+}
@@ -10,6 +10,10 @@ func RegisterRoutes(router *gin.RouterGroup, handler *Handler, providerAuthMiddl
 	{
 		provider.GET("/profile", handler.GetProfile)
 		provider.PATCH("/availability", handler.UpdateAvailability)
+		provider.PATCH("/address-script", handler.UpdateAddressScript)
+
+		provider.GET("/onboarding", handler.GetOnboardingStatus)
+		provider.POST("/onboarding/submit", handler.SubmitForReview)
 
 		categories := provider.Group("/categories")
 		{
@@ -26,12 +30,39 @@ func RegisterRoutes(router *gin.RouterGroup, handler *Handler, providerAuthMiddl
 
 			orders.GET("", handler.GetMyOrders)
 			orders.GET("/:id", handler.GetMyOrderDetail)
+			orders.GET("/:id/fee-breakdown", handler.GetMyOrderFeeBreakdown)
 
 			orders.POST("/:id/accept", handler.AcceptOrder)
 			orders.POST("/:id/reject", handler.RejectOrder)
+			orders.POST("/:id/arrived", handler.MarkArrived)
 			orders.POST("/:id/start", handler.StartOrder)
 			orders.POST("/:id/complete", handler.CompleteOrder)
 			orders.POST("/:id/rate", handler.RateCustomer)
+
+			orders.GET("/:id/sessions", handler.GetOrderSessions)
+			orders.POST("/:id/sessions/:sessionId/check-in", handler.CheckInSession)
+			orders.POST("/:id/sessions/:sessionId/check-out", handler.CheckOutSession)
+
+			orders.POST("/:id/reschedule", handler.ProposeReschedule)
+			orders.GET("/:id/reschedules", handler.GetOrderReschedules)
+			orders.POST("/:id/reschedules/:rescheduleId/confirm", handler.ConfirmReschedule)
+			orders.POST("/:id/reschedules/:rescheduleId/decline", handler.DeclineReschedule)
+
+			orders.POST("/:id/adjustments", handler.ProposeAdjustment)
+			orders.GET("/:id/adjustments", handler.GetOrderAdjustments)
+
+			orders.GET("/:id/crew", handler.GetOrderCrew)
+			orders.POST("/:id/crew", handler.InviteCrewMember)
+			orders.PATCH("/:id/crew/:memberId", handler.UpdateCrewMember)
+			orders.DELETE("/:id/crew/:memberId", handler.RemoveCrewMember)
+		}
+
+		crew := provider.Group("/crew")
+		{
+			crew.GET("", handler.GetCrewAssignments)
+			crew.POST("/:memberId/accept", handler.AcceptCrewInvite)
+			crew.POST("/:memberId/decline", handler.DeclineCrewInvite)
+			crew.POST("/:memberId/check-in", handler.CheckInCrew)
 		}
 
 		provider.GET("/statistics", handler.GetStatistics)
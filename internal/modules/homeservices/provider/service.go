@@ -8,14 +8,20 @@ import (
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
+	"github.com/umar5678/go-backend/internal/config"
 	"github.com/umar5678/go-backend/internal/models"
 	"github.com/umar5678/go-backend/internal/modules/homeservices/provider/dto"
 	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+	"github.com/umar5678/go-backend/internal/modules/pricing"
+	pricingdto "github.com/umar5678/go-backend/internal/modules/pricing/dto"
 	"github.com/umar5678/go-backend/internal/modules/ridepin"
+	"github.com/umar5678/go-backend/internal/modules/serviceproviders"
 	"github.com/umar5678/go-backend/internal/modules/wallet"
 	walletdto "github.com/umar5678/go-backend/internal/modules/wallet/dto"
+	"github.com/umar5678/go-backend/internal/services/livemetrics"
 	"github.com/umar5678/go-backend/internal/utils/logger"
 	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/utils/translit"
 )
 
 type Service interface {
@@ -24,6 +30,14 @@ type Service interface {
 
 	GetProfile(ctx context.Context, providerID string) (*dto.ProviderProfileResponse, error)
 	UpdateAvailability(ctx context.Context, providerID string, req dto.UpdateAvailabilityRequest) error
+	UpdateAddressScript(ctx context.Context, providerID string, req dto.UpdateAddressScriptRequest) error
+
+	GetOrderSessions(ctx context.Context, providerID, orderID string) (*shared.OrderSessionsResponse, error)
+	CheckInSession(ctx context.Context, providerID, orderID, sessionID string, req dto.CheckInSessionRequest) (*shared.OrderSessionsResponse, error)
+	CheckOutSession(ctx context.Context, providerID, orderID, sessionID string, req dto.CheckOutSessionRequest) (*shared.OrderSessionsResponse, error)
+
+	GetOnboardingStatus(ctx context.Context, providerID string) (*serviceproviders.OnboardingStatus, error)
+	SubmitForReview(ctx context.Context, providerID string) (*serviceproviders.OnboardingStatus, error)
 
 	GetServiceCategories(ctx context.Context, providerID string) ([]dto.ServiceCategoryResponse, error)
 	AddServiceCategory(ctx context.Context, providerID string, req dto.AddServiceCategoryRequest) (*dto.ServiceCategoryResponse, error)
@@ -35,27 +49,63 @@ type Service interface {
 
 	GetMyOrders(ctx context.Context, providerID string, query dto.ListMyOrdersQuery) ([]dto.ProviderOrderListResponse, *response.PaginationMeta, error)
 	GetMyOrderDetail(ctx context.Context, providerID, orderID string) (*dto.ProviderOrderResponse, error)
+	GetMyOrderFeeBreakdown(ctx context.Context, providerID, orderID string) (*pricingdto.FeeBreakdownResponse, error)
 	AcceptOrder(ctx context.Context, providerID, orderID string) (*dto.ProviderOrderResponse, error)
 	RejectOrder(ctx context.Context, providerID, orderID string, req dto.RejectOrderRequest) error
+	MarkArrived(ctx context.Context, providerID, orderID string) (*dto.ProviderOrderResponse, error)
+	MarkArrivedByGeofence(ctx context.Context, providerID, orderID string) error
 	StartOrder(ctx context.Context, providerID, orderID string, req dto.StartOrderRequest) (*dto.ProviderOrderResponse, error)
 	CompleteOrder(ctx context.Context, providerID, orderID string, req dto.CompleteOrderRequest) (*dto.ProviderOrderResponse, error)
 	RateCustomer(ctx context.Context, providerID, orderID string, req dto.RateCustomerRequest) (*dto.ProviderOrderResponse, error)
 
 	GetStatistics(ctx context.Context, providerID string) (*dto.ProviderStatistics, error)
 	GetEarnings(ctx context.Context, providerID string, query dto.EarningsQuery) (*dto.EarningsSummaryResponse, error)
+
+	SetRatingAggregator(aggregator RatingAggregator)
+	SetEventPublisher(publisher shared.EventPublisher)
+	SetProofChecker(checker shared.ProofChecker)
+	SetLocationSharing(sharing shared.LocationSharing)
+	ConfigurePreferredProviders(cfg config.FavoritesConfig)
+	ConfigureRescheduling(cfg config.RescheduleConfig)
+
+	ProposeReschedule(ctx context.Context, providerID, orderID string, req dto.RescheduleOrderRequest) (*shared.RescheduleResponse, error)
+	GetOrderReschedules(ctx context.Context, providerID, orderID string) (*shared.OrderReschedulesResponse, error)
+	ConfirmReschedule(ctx context.Context, providerID, orderID, rescheduleID string, req dto.RespondRescheduleRequest) (*shared.RescheduleResponse, error)
+	DeclineReschedule(ctx context.Context, providerID, orderID, rescheduleID string, req dto.RespondRescheduleRequest) (*shared.RescheduleResponse, error)
+
+	ProposeAdjustment(ctx context.Context, providerID, orderID string, req dto.ProposeAdjustmentRequest) (*shared.AdjustmentResponse, error)
+	GetOrderAdjustments(ctx context.Context, providerID, orderID string) (*shared.OrderAdjustmentsResponse, error)
+
+	GetOrderCrew(ctx context.Context, providerID, orderID string) (*dto.OrderCrewResponse, error)
+	InviteCrewMember(ctx context.Context, providerID, orderID string, req dto.InviteCrewMemberRequest) (*dto.CrewMemberResponse, error)
+	UpdateCrewMember(ctx context.Context, providerID, orderID, memberID string, req dto.UpdateCrewMemberRequest) (*dto.CrewMemberResponse, error)
+	RemoveCrewMember(ctx context.Context, providerID, orderID, memberID string) error
+	GetCrewAssignments(ctx context.Context, providerID string) ([]*dto.CrewAssignmentResponse, error)
+	AcceptCrewInvite(ctx context.Context, providerID, memberID string) (*dto.CrewAssignmentResponse, error)
+	DeclineCrewInvite(ctx context.Context, providerID, memberID string) (*dto.CrewAssignmentResponse, error)
+	CheckInCrew(ctx context.Context, providerID, memberID string) (*dto.CrewAssignmentResponse, error)
 }
 
 type service struct {
 	repo           Repository
 	walletService  wallet.Service
 	ridePINService ridepin.Service
+	onboarding     serviceproviders.Service
+
+	ratingAggregator   RatingAggregator
+	events             shared.EventPublisher
+	proofs             shared.ProofChecker
+	locationSharing    shared.LocationSharing
+	preferredHeadStart time.Duration
+	reschedule         config.RescheduleConfig
 }
 
-func NewService(repo Repository, walletService wallet.Service, ridePINService ridepin.Service) Service {
+func NewService(repo Repository, walletService wallet.Service, ridePINService ridepin.Service, onboarding serviceproviders.Service) Service {
 	return &service{
 		repo:           repo,
 		walletService:  walletService,
 		ridePINService: ridePINService,
+		onboarding:     onboarding,
 	}
 }
 
@@ -139,6 +189,8 @@ func (s *service) GetProfile(ctx context.Context, providerID string) (*dto.Provi
 		Phone:             phone,
 		IsVerified:        provider.IsVerified,
 		IsAvailable:       provider.IsAvailable,
+		Status:            string(provider.Status),
+		AddressScript:     addressScriptOrDefault(provider.PreferredAddressScript),
 		ServiceCategories: dto.ToServiceCategoryResponses(categories),
 		Statistics:        *stats,
 		CreatedAt:         provider.CreatedAt,
@@ -148,10 +200,78 @@ func (s *service) GetProfile(ctx context.Context, providerID string) (*dto.Provi
 }
 
 func (s *service) UpdateAvailability(ctx context.Context, providerID string, req dto.UpdateAvailabilityRequest) error {
+	if req.IsAvailable {
+		if _, err := s.requireApprovedProvider(ctx, providerID); err != nil {
+			return err
+		}
+	}
+
+	livemetrics.ProviderAvailable(ctx, providerID, req.IsAvailable)
 	logger.Info("provider availability updated", "providerID", providerID, "isAvailable", req.IsAvailable)
 	return nil
 }
 
+func (s *service) GetOnboardingStatus(ctx context.Context, providerID string) (*serviceproviders.OnboardingStatus, error) {
+	if providerID == "" {
+		return nil, response.NotFoundError("Service provider profile")
+	}
+	return s.onboarding.GetOnboardingStatus(ctx, providerID)
+}
+
+func (s *service) SubmitForReview(ctx context.Context, providerID string) (*serviceproviders.OnboardingStatus, error) {
+	if providerID == "" {
+		return nil, response.NotFoundError("Service provider profile")
+	}
+	return s.onboarding.SubmitForReview(ctx, providerID)
+}
+
+// requireApprovedProvider blocks providers that are still in onboarding, or
+// were rejected or suspended, from seeing and taking orders.
+func (s *service) requireApprovedProvider(ctx context.Context, providerID string) (*models.ServiceProviderProfile, error) {
+	provider, err := s.repo.GetProvider(ctx, providerID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, response.ForbiddenError("Complete provider onboarding before taking orders")
+		}
+		return nil, response.InternalServerError("Failed to load provider profile", err)
+	}
+
+	if !provider.CanReceiveOrders() {
+		if provider.Status == models.SPStatusPendingApproval || provider.Status == models.SPStatusRejected {
+			return nil, response.ForbiddenError("Your provider account is pending approval")
+		}
+		return nil, response.ForbiddenError("Your provider account is not active")
+	}
+	return provider, nil
+}
+
+func (s *service) UpdateAddressScript(ctx context.Context, providerID string, req dto.UpdateAddressScriptRequest) error {
+	if err := s.repo.UpdateAddressScript(ctx, providerID, req.AddressScript); err != nil {
+		logger.Error("failed to update address script", "error", err, "providerID", providerID)
+		return response.InternalServerError("Failed to update address script", err)
+	}
+
+	logger.Info("provider address script updated", "providerID", providerID, "addressScript", req.AddressScript)
+	return nil
+}
+
+// addressScript returns the provider's preferred script for customer
+// addresses, falling back to Latin when the profile can't be loaded.
+func (s *service) addressScript(ctx context.Context, providerID string) string {
+	provider, err := s.repo.GetProvider(ctx, providerID)
+	if err != nil {
+		return translit.PreferLatin
+	}
+	return addressScriptOrDefault(provider.PreferredAddressScript)
+}
+
+func addressScriptOrDefault(script string) string {
+	if script == "" {
+		return translit.PreferLatin
+	}
+	return script
+}
+
 func (s *service) GetServiceCategories(ctx context.Context, providerID string) ([]dto.ServiceCategoryResponse, error) {
 	categories, err := s.repo.GetProviderCategories(ctx, providerID)
 	if err != nil {
@@ -252,7 +372,12 @@ func (s *service) GetAvailableOrders(ctx context.Context, providerID string, que
 
 	logger.Info("fetched provider category slugs", "providerID", providerID, "categories", categorySlugs)
 
+	addressScript := translit.PreferLatin
 	if provider, perr := s.repo.GetProvider(ctx, providerID); perr == nil && provider != nil {
+		if !provider.CanReceiveOrders() {
+			return nil, nil, response.ForbiddenError("Your provider account is pending approval")
+		}
+		addressScript = addressScriptOrDefault(provider.PreferredAddressScript)
 		logger.Info("fetched provider profile", "providerID", providerID, "serviceType", provider.ServiceType, "serviceCategory", provider.ServiceCategory)
 
 		addIfMissing := func(slice []string, v string) []string {
@@ -287,6 +412,7 @@ func (s *service) GetAvailableOrders(ctx context.Context, providerID string, que
 	}
 
 	query.SetDefaults()
+	s.applyPreferences(ctx, providerID, &query)
 
 	orders, total, err := s.repo.GetAvailableOrders(ctx, providerID, categorySlugs, query)
 	if err != nil {
@@ -296,7 +422,8 @@ func (s *service) GetAvailableOrders(ctx context.Context, providerID string, que
 
 	responses := make([]dto.AvailableOrderResponse, len(orders))
 	for i, order := range orders {
-		responses[i] = dto.ToAvailableOrderResponse(order, nil)
+		responses[i] = dto.ToAvailableOrderResponse(order, nil, addressScript)
+		responses[i].IsPreferred = query.PreferredBy[order.CustomerID]
 	}
 
 	pagination := response.NewPaginationMeta(total, query.Page, query.Limit)
@@ -304,6 +431,10 @@ func (s *service) GetAvailableOrders(ctx context.Context, providerID string, que
 }
 
 func (s *service) GetAvailableOrderDetail(ctx context.Context, providerID, orderID string) (*dto.AvailableOrderResponse, error) {
+	if _, err := s.requireApprovedProvider(ctx, providerID); err != nil {
+		return nil, err
+	}
+
 	categorySlugs, err := s.repo.GetProviderCategorySlugs(ctx, providerID)
 	if err != nil {
 		return nil, response.InternalServerError("Failed to get order", err)
@@ -321,7 +452,7 @@ func (s *service) GetAvailableOrderDetail(ctx context.Context, providerID, order
 		return nil, response.InternalServerError("Failed to get order", err)
 	}
 
-	result := dto.ToAvailableOrderResponse(order, nil)
+	result := dto.ToAvailableOrderResponse(order, nil, s.addressScript(ctx, providerID))
 	return &result, nil
 }
 
@@ -353,10 +484,26 @@ func (s *service) GetMyOrderDetail(ctx context.Context, providerID, orderID stri
 		return nil, response.InternalServerError("Failed to get order", err)
 	}
 
-	return dto.ToProviderOrderResponse(order), nil
+	return dto.ToProviderOrderResponse(order, s.addressScript(ctx, providerID)), nil
+}
+
+func (s *service) GetMyOrderFeeBreakdown(ctx context.Context, providerID, orderID string) (*pricingdto.FeeBreakdownResponse, error) {
+	order, err := s.repo.GetProviderOrderByID(ctx, providerID, orderID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, response.NotFoundError("Order")
+		}
+		return nil, response.InternalServerError("Failed to get order", err)
+	}
+
+	return shared.BuildOrderFeeBreakdown(order, pricing.FeeViewerEarner), nil
 }
 
 func (s *service) AcceptOrder(ctx context.Context, providerID, orderID string) (*dto.ProviderOrderResponse, error) {
+	if _, err := s.requireApprovedProvider(ctx, providerID); err != nil {
+		return nil, err
+	}
+
 	activeCount, err := s.repo.CountProviderActiveOrders(ctx, providerID)
 	if err != nil {
 		return nil, response.InternalServerError("Failed to accept order", err)
@@ -384,13 +531,17 @@ func (s *service) AcceptOrder(ctx context.Context, providerID, orderID string) (
 
 	if err := s.repo.UpdateOrder(ctx, order); err != nil {
 		logger.Error("failed to update order", "error", err, "orderID", orderID)
-		return nil, response.InternalServerError("Failed to accept order", err)
+		return nil, shared.OrderUpdateError(err, "Failed to accept order")
 	}
 
+	livemetrics.OrderNotSearching(ctx, order.ID)
+	s.publishOrderAssigned(ctx, order, now)
+
 	if order.WalletHoldID != nil {
 		captureReq := walletdto.CaptureHoldRequest{
 			HoldID:      *order.WalletHoldID,
 			Amount:      &order.TotalPrice,
+			Currency:    order.Currency,
 			Description: fmt.Sprintf("Payment for order %s", order.OrderNumber),
 		}
 		if _, err := s.walletService.CaptureHold(ctx, order.CustomerID, captureReq); err != nil {
@@ -411,7 +562,7 @@ func (s *service) AcceptOrder(ctx context.Context, providerID, orderID string) (
 
 	logger.Info("order accepted", "orderID", orderID, "providerID", providerID)
 
-	return dto.ToProviderOrderResponse(order), nil
+	return dto.ToProviderOrderResponse(order, s.addressScript(ctx, providerID)), nil
 }
 
 func (s *service) RejectOrder(ctx context.Context, providerID, orderID string, req dto.RejectOrderRequest) error {
@@ -449,7 +600,18 @@ func (s *service) RejectOrder(ctx context.Context, providerID, orderID string, r
 	}
 
 	if err := s.repo.UpdateOrder(ctx, order); err != nil {
-		return response.InternalServerError("Failed to reject order", err)
+		return shared.OrderUpdateError(err, "Failed to reject order")
+	}
+
+	if order.Status == shared.OrderStatusSearchingProvider {
+		livemetrics.OrderSearching(ctx, order.ID)
+
+		if err := s.repo.WithdrawPendingReschedules(ctx, order.ID); err != nil {
+			logger.Error("failed to withdraw reschedule requests", "error", err, "orderID", order.ID)
+		}
+		if err := s.repo.WithdrawPendingAdjustments(ctx, order.ID); err != nil {
+			logger.Error("failed to withdraw scope adjustments", "error", err, "orderID", order.ID)
+		}
 	}
 
 	history := models.NewOrderStatusHistory(
@@ -484,6 +646,12 @@ func (s *service) StartOrder(ctx context.Context, providerID, orderID string, re
 	if order.Status != shared.OrderStatusAccepted {
 		return nil, response.BadRequest(fmt.Sprintf("Cannot start order in '%s' status", order.Status))
 	}
+	if order.IsMultiSession {
+		return nil, response.BadRequest("Multi-day orders are started by checking in to the first session")
+	}
+	if err := s.checkCrewReady(ctx, order); err != nil {
+		return nil, err
+	}
 
 	logger.Info("verifying customer PIN for order start", "orderID", orderID, "customerID", order.CustomerID)
 	if err := s.ridePINService.VerifyRidePIN(ctx, order.CustomerID, req.CustomerPIN); err != nil {
@@ -501,7 +669,12 @@ func (s *service) StartOrder(ctx context.Context, providerID, orderID string, re
 	order.ProviderStartedAt = &now
 
 	if err := s.repo.UpdateOrder(ctx, order); err != nil {
-		return nil, response.InternalServerError("Failed to start order", err)
+		return nil, shared.OrderUpdateError(err, "Failed to start order")
+	}
+	s.stopLocationSharing(ctx, order)
+
+	if err := s.repo.CloseCrew(ctx, order.ID, []models.OrderCrewStatus{models.CrewStatusInvited}); err != nil {
+		logger.Error("failed to expire crew invitations", "error", err, "orderID", order.ID)
 	}
 
 	history := models.NewOrderStatusHistory(
@@ -517,7 +690,7 @@ func (s *service) StartOrder(ctx context.Context, providerID, orderID string, re
 
 	logger.Info("order started", "orderID", orderID, "providerID", providerID)
 
-	return dto.ToProviderOrderResponse(order), nil
+	return dto.ToProviderOrderResponse(order, s.addressScript(ctx, providerID)), nil
 }
 
 func (s *service) CompleteOrder(ctx context.Context, providerID, orderID string, req dto.CompleteOrderRequest) (*dto.ProviderOrderResponse, error) {
@@ -532,6 +705,14 @@ func (s *service) CompleteOrder(ctx context.Context, providerID, orderID string,
 	if order.Status != shared.OrderStatusInProgress {
 		return nil, response.BadRequest(fmt.Sprintf("Cannot complete order in '%s' status", order.Status))
 	}
+	if order.IsMultiSession {
+		return nil, response.BadRequest("Multi-day orders complete when the customer approves the final session")
+	}
+	if s.proofs != nil {
+		if err := s.proofs.CheckProofs(ctx, "service_order", order.ID, order.CategorySlug); err != nil {
+			return nil, err
+		}
+	}
 
 	logger.Info("verifying customer PIN for order completion", "orderID", orderID, "customerID", order.CustomerID)
 	if err := s.ridePINService.VerifyRidePIN(ctx, order.CustomerID, req.CustomerPIN); err != nil {
@@ -543,7 +724,8 @@ func (s *service) CompleteOrder(ctx context.Context, providerID, orderID string,
 
 	logger.Info("customer PIN verified at order completion", "orderID", orderID)
 
-	providerPayout := dto.CalculateProviderPayout(order.TotalPrice)
+	providerPayout := order.ProviderPayout()
+	leadPayout, crew := s.splitCrewPayout(ctx, order, providerPayout)
 
 	provider, err := s.repo.GetProvider(ctx, providerID)
 	if err != nil {
@@ -551,24 +733,12 @@ func (s *service) CompleteOrder(ctx context.Context, providerID, orderID string,
 		return nil, response.InternalServerError("Failed to process payment", err)
 	}
 
-	walletMetadata := map[string]interface{}{
-		"order_id":     order.ID,
-		"order_number": order.OrderNumber,
-		"service":      "homeservice",
+	before := *order
+	if order.PaymentInfo != nil {
+		paymentInfo := *order.PaymentInfo
+		before.PaymentInfo = &paymentInfo
 	}
 
-	if _, err := s.walletService.CreditServiceProviderWallet(
-		ctx,
-		provider.UserID,
-		providerPayout,
-		"service_payment",
-		order.ID,
-		fmt.Sprintf("Payment for order %s", order.OrderNumber),
-		walletMetadata,
-	); err != nil {
-		logger.Error("failed to credit provider wallet", "error", err, "orderID", orderID)
-		return nil, response.InternalServerError("Failed to process payment", err)
-	}
 	now := time.Now()
 	previousStatus := order.Status
 	order.Status = shared.OrderStatusCompleted
@@ -580,19 +750,73 @@ func (s *service) CompleteOrder(ctx context.Context, providerID, orderID string,
 		order.PaymentInfo.AmountPaid = order.TotalPrice
 	}
 
+	// Only the request whose versioned write wins the move to completed goes
+	// on to pay the provider.
 	if err := s.repo.UpdateOrder(ctx, order); err != nil {
-		return nil, response.InternalServerError("Failed to complete order", err)
+		return nil, shared.OrderUpdateError(err, "Failed to complete order")
+	}
+
+	credit := &models.PayoutCredit{
+		ReferenceType:   "service_order",
+		ReferenceID:     order.ID,
+		ReferenceNumber: order.OrderNumber,
+		ProviderID:      &providerID,
+		ProviderUserID:  provider.UserID,
+		Amount:          leadPayout,
+		TransactionType: "service_payment",
+		Description:     fmt.Sprintf("Payment for order %s", order.OrderNumber),
+		Metadata: models.JSONBMap{
+			"order_id":     order.ID,
+			"order_number": order.OrderNumber,
+			"service":      "homeservice",
+		},
+	}
+	queued, err := s.walletService.CreditProviderPayout(ctx, credit)
+	if err != nil {
+		logger.Error("failed to credit provider wallet", "error", err, "orderID", orderID)
+		before.Version = order.Version
+		if restoreErr := s.repo.UpdateOrder(ctx, &before); restoreErr != nil {
+			logger.Error("failed to restore order after payment failed", "error", restoreErr, "orderID", orderID)
+		}
+		return nil, response.InternalServerError("Failed to process payment", err)
+	}
+	payoutStatus := models.OrderPayoutStatusPaid
+	if queued {
+		payoutStatus = models.OrderPayoutStatusPending
+	}
+	order.PayoutStatus = &payoutStatus
+	if credit.Status == models.PayoutCreditStatusAccrued {
+		settlementStatus := models.OrderSettlementStatusUnsettled
+		order.SettlementStatus = &settlementStatus
 	}
+	if err := s.repo.UpdateOrder(ctx, order); err != nil {
+		logger.Error("failed to record order payout status", "error", err, "orderID", orderID, "payout", leadPayout)
+	}
+
+	livemetrics.AddRevenue(ctx, livemetrics.RevenueSourceHomeServices, order.TotalPrice)
+
+	if err := s.repo.WithdrawPendingAdjustments(ctx, order.ID); err != nil {
+		logger.Error("failed to withdraw scope adjustments", "error", err, "orderID", order.ID)
+	}
+	s.payCrew(ctx, order, crew)
 
 	category, err := s.repo.GetProviderCategory(ctx, providerID, order.CategorySlug)
 	if err == nil && category != nil {
-		category.IncrementCompletedJobs(providerPayout)
+		category.IncrementCompletedJobs(leadPayout)
 		s.repo.UpdateProviderCategory(ctx, category)
 	}
 
 	statusMetadata := models.StatusHistoryMetadata{
 		"providerPayout": providerPayout,
 	}
+	if len(crew) > 0 {
+		crewPayouts := make(map[string]float64, len(crew))
+		for _, member := range crew {
+			crewPayouts[member.ProviderID] = *member.PayoutAmount
+		}
+		statusMetadata["leadPayout"] = leadPayout
+		statusMetadata["crewPayouts"] = crewPayouts
+	}
 	if req.Notes != "" {
 		statusMetadata["completionNotes"] = req.Notes
 	}
@@ -606,10 +830,11 @@ func (s *service) CompleteOrder(ctx context.Context, providerID, orderID string,
 		statusMetadata,
 	)
 	s.repo.CreateStatusHistory(ctx, history)
+	s.publishOrderCompleted(ctx, order)
 
 	logger.Info("order completed", "orderID", orderID, "providerID", providerID, "payout", providerPayout)
 
-	return dto.ToProviderOrderResponse(order), nil
+	return dto.ToProviderOrderResponse(order, s.addressScript(ctx, providerID)), nil
 }
 
 func (s *service) RateCustomer(ctx context.Context, providerID, orderID string, req dto.RateCustomerRequest) (*dto.ProviderOrderResponse, error) {
@@ -640,12 +865,14 @@ func (s *service) RateCustomer(ctx context.Context, providerID, orderID string,
 	order.ProviderRatedAt = &now
 
 	if err := s.repo.UpdateOrder(ctx, order); err != nil {
-		return nil, response.InternalServerError("Failed to submit rating", err)
+		return nil, shared.OrderUpdateError(err, "Failed to submit rating")
 	}
 
+	s.refreshCustomerRating(order.CustomerID)
+
 	logger.Info("customer rated", "orderID", orderID, "providerID", providerID, "rating", req.Rating)
 
-	return dto.ToProviderOrderResponse(order), nil
+	return dto.ToProviderOrderResponse(order, s.addressScript(ctx, providerID)), nil
 }
 
 func (s *service) GetStatistics(ctx context.Context, providerID string) (*dto.ProviderStatistics, error) {
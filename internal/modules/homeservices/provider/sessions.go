@@ -0,0 +1,163 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/provider/dto"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+func (s *service) loadMultiSessionOrder(ctx context.Context, providerID, orderID string) (*models.ServiceOrderNew, []*models.ServiceOrderSession, error) {
+	order, err := s.repo.GetProviderOrderByID(ctx, providerID, orderID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, response.NotFoundError("Order")
+		}
+		return nil, nil, response.InternalServerError("Failed to get order", err)
+	}
+	if order.AssignedProviderID == nil || *order.AssignedProviderID != providerID {
+		return nil, nil, response.NotFoundError("Order")
+	}
+	if !order.IsMultiSession {
+		return nil, nil, response.BadRequest("Order is not a multi-day booking")
+	}
+
+	sessions, err := s.repo.GetOrderSessions(ctx, order.ID)
+	if err != nil {
+		return nil, nil, response.InternalServerError("Failed to get order sessions", err)
+	}
+	return order, sessions, nil
+}
+
+func (s *service) GetOrderSessions(ctx context.Context, providerID, orderID string) (*shared.OrderSessionsResponse, error) {
+	order, sessions, err := s.loadMultiSessionOrder(ctx, providerID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	return shared.ToOrderSessionsResponse(order, sessions), nil
+}
+
+// CheckInSession starts a working day. Sessions run strictly in order: the
+// previous session must have been approved by the customer first. Checking in
+// to the first session moves the order to in_progress.
+func (s *service) CheckInSession(ctx context.Context, providerID, orderID, sessionID string, req dto.CheckInSessionRequest) (*shared.OrderSessionsResponse, error) {
+	order, sessions, err := s.loadMultiSessionOrder(ctx, providerID, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != shared.OrderStatusAccepted && order.Status != shared.OrderStatusInProgress {
+		return nil, response.BadRequest(fmt.Sprintf("Cannot check in to an order in '%s' status", order.Status))
+	}
+
+	session := shared.NextOpenSession(sessions)
+	if session == nil || session.ID != sessionID {
+		return nil, response.BadRequest("Only the next session can be checked in to, after the previous one is approved by the customer")
+	}
+	if session.Status != models.SessionStatusScheduled && session.Status != models.SessionStatusChangesRequested {
+		return nil, response.BadRequest(fmt.Sprintf("Session %d is already %s", session.SessionNumber, session.Status))
+	}
+	if time.Now().Format("2006-01-02") < session.ScheduledDate {
+		return nil, response.BadRequest(fmt.Sprintf("Session %d is scheduled for %s", session.SessionNumber, session.ScheduledDate))
+	}
+
+	if err := s.ridePINService.VerifyRidePIN(ctx, order.CustomerID, req.CustomerPIN); err != nil {
+		logger.Warn("invalid customer PIN attempt at session check-in",
+			"orderID", orderID,
+			"sessionNumber", session.SessionNumber)
+		return nil, response.BadRequest("Invalid Customer PIN. Please enter your 4-digit PIN.")
+	}
+
+	now := time.Now()
+	session.Status = models.SessionStatusCheckedIn
+	session.CheckedInAt = &now
+	session.CheckedOutAt = nil
+	if err := s.repo.UpdateSession(ctx, session); err != nil {
+		return nil, response.InternalServerError("Failed to check in", err)
+	}
+
+	previousStatus := order.Status
+	if order.Status == shared.OrderStatusAccepted {
+		order.Status = shared.OrderStatusInProgress
+		order.ProviderStartedAt = &now
+		if err := s.repo.UpdateOrder(ctx, order); err != nil {
+			return nil, shared.OrderUpdateError(err, "Failed to start order")
+		}
+		s.stopLocationSharing(ctx, order)
+	}
+
+	s.repo.CreateStatusHistory(ctx, models.NewOrderStatusHistory(
+		order.ID,
+		previousStatus,
+		order.Status,
+		&providerID,
+		shared.RoleProvider,
+		fmt.Sprintf("Checked in to session %d of %d", session.SessionNumber, len(sessions)),
+		models.StatusHistoryMetadata{"sessionId": session.ID},
+	))
+
+	logger.Info("order session checked in", "orderID", orderID, "providerID", providerID, "sessionNumber", session.SessionNumber)
+
+	return shared.ToOrderSessionsResponse(order, sessions), nil
+}
+
+// CheckOutSession ends a working day and hands it to the customer for
+// approval. ProgressPercent is the provider's estimate for the whole project.
+func (s *service) CheckOutSession(ctx context.Context, providerID, orderID, sessionID string, req dto.CheckOutSessionRequest) (*shared.OrderSessionsResponse, error) {
+	order, sessions, err := s.loadMultiSessionOrder(ctx, providerID, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	var session *models.ServiceOrderSession
+	for _, candidate := range sessions {
+		if candidate.ID == sessionID {
+			session = candidate
+			break
+		}
+	}
+	if session == nil {
+		return nil, response.NotFoundError("Session")
+	}
+	if session.Status != models.SessionStatusCheckedIn {
+		return nil, response.BadRequest(fmt.Sprintf("Session %d is not checked in", session.SessionNumber))
+	}
+
+	now := time.Now()
+	session.Status = models.SessionStatusAwaitingApproval
+	session.CheckedOutAt = &now
+	session.WorkSummary = req.WorkSummary
+	session.ProgressPercent = req.ProgressPercent
+	if err := s.repo.UpdateSession(ctx, session); err != nil {
+		return nil, response.InternalServerError("Failed to check out", err)
+	}
+
+	if req.ProgressPercent > order.ProgressPercent && req.ProgressPercent < 100 {
+		order.ProgressPercent = req.ProgressPercent
+		if err := s.repo.UpdateOrder(ctx, order); err != nil {
+			logger.Error("failed to update order progress", "error", err, "orderID", orderID)
+		}
+	}
+
+	s.repo.CreateStatusHistory(ctx, models.NewOrderStatusHistory(
+		order.ID,
+		order.Status,
+		order.Status,
+		&providerID,
+		shared.RoleProvider,
+		fmt.Sprintf("Checked out of session %d of %d, awaiting customer approval", session.SessionNumber, len(sessions)),
+		models.StatusHistoryMetadata{
+			"sessionId":       session.ID,
+			"progressPercent": req.ProgressPercent,
+		},
+	))
+
+	logger.Info("order session checked out", "orderID", orderID, "providerID", providerID, "sessionNumber", session.SessionNumber)
+
+	return shared.ToOrderSessionsResponse(order, sessions), nil
+}
@@ -344,7 +344,8 @@ func (r *repository) ListProviderOrders(ctx context.Context, providerID string,
 
 func (r *repository) UpdateOrderStatus(ctx context.Context, orderID, status string) error {
 	updates := map[string]interface{}{
-		"status": status,
+		"status":  status,
+		"version": gorm.Expr("version + 1"),
 	}
 
 	switch status {
@@ -372,6 +373,7 @@ func (r *repository) AssignProviderToOrder(ctx context.Context, providerID, orde
 			"assigned_provider_id": providerID,
 			"status":               "assigned",
 			"provider_accepted_at": gorm.Expr("NOW()"),
+			"version":              gorm.Expr("version + 1"),
 		}).Error
 }
 
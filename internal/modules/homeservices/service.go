@@ -12,12 +12,14 @@ import (
 	"github.com/umar5678/go-backend/internal/config"
 	"github.com/umar5678/go-backend/internal/models"
 	homeservicedto "github.com/umar5678/go-backend/internal/modules/homeservices/dto"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
 	notificationsmodule "github.com/umar5678/go-backend/internal/modules/notifications"
 	"github.com/umar5678/go-backend/internal/modules/wallet"
 	walletdto "github.com/umar5678/go-backend/internal/modules/wallet/dto"
 	"github.com/umar5678/go-backend/internal/services/cache"
 	"github.com/umar5678/go-backend/internal/utils/logger"
 	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/utils/translit"
 )
 
 const (
@@ -47,6 +49,9 @@ type Service interface {
 	CompleteOrder(ctx context.Context, providerID, orderID string) error
 
 	FindAndNotifyNextProvider(orderID string)
+	SetEventPublisher(publisher shared.EventPublisher)
+	SetProofChecker(checker shared.ProofChecker)
+	SetLocationSharing(sharing shared.LocationSharing)
 
 	CreateCategory(ctx context.Context, req homeservicedto.CreateCategoryRequest) (*homeservicedto.CategoryWithTabsResponse, error)
 	CreateTab(ctx context.Context, req homeservicedto.CreateTabRequest) (*homeservicedto.ServiceTabResponse, error)
@@ -56,10 +61,13 @@ type Service interface {
 }
 
 type service struct {
-	repo          Repository
-	walletService wallet.Service
-	cfg           *config.Config
-	eventProducer notificationsmodule.EventProducer
+	repo            Repository
+	walletService   wallet.Service
+	cfg             *config.Config
+	eventProducer   notificationsmodule.EventProducer
+	events          shared.EventPublisher
+	proofs          shared.ProofChecker
+	locationSharing shared.LocationSharing
 }
 
 func NewService(repo Repository, walletService wallet.Service, cfg *config.Config) Service {
@@ -76,13 +84,21 @@ func NewServiceWithNotifications(repo Repository, walletService wallet.Service,
 }
 
 func (s *service) ListCategories(ctx context.Context) ([]*homeservicedto.ServiceCategoryResponse, error) {
+	cacheKey := shared.CatalogCacheKey(ctx, "legacy:categories", nil)
+	var cached []*homeservicedto.ServiceCategoryResponse
+	if shared.GetCachedCatalog(ctx, cacheKey, &cached) {
+		return cached, nil
+	}
+
 	categories, err := s.repo.ListCategories(ctx)
 	if err != nil {
 		logger.Error("failed to list categories", "error", err)
 		return nil, response.InternalServerError("Failed to fetch categories", err)
 	}
 
-	return homeservicedto.ToServiceCategoryList(categories), nil
+	result := homeservicedto.ToServiceCategoryList(categories)
+	shared.SetCachedCatalog(ctx, cacheKey, result)
+	return result, nil
 }
 
 func (s *service) GetAllCategorySlugs(ctx context.Context) ([]string, error) {
@@ -110,6 +126,12 @@ func (s *service) GetCategoryWithTabs(ctx context.Context, id uint) (*homeservic
 func (s *service) ListServices(ctx context.Context, query homeservicedto.ListServicesQuery) ([]*homeservicedto.ServiceListResponse, *response.PaginationMeta, error) {
 	query.SetDefaults()
 
+	cacheKey := shared.CatalogCacheKey(ctx, "legacy:services", query)
+	var cached shared.CatalogPage[*homeservicedto.ServiceListResponse]
+	if shared.GetCachedCatalog(ctx, cacheKey, &cached) {
+		return cached.Items, &cached.Pagination, nil
+	}
+
 	services, total, err := s.repo.ListServices(ctx, query)
 	if err != nil {
 		logger.Error("failed to list services", "error", err)
@@ -135,6 +157,7 @@ func (s *service) ListServices(ctx context.Context, query homeservicedto.ListSer
 	}
 
 	pagination := response.NewPaginationMeta(total, query.Page, query.Limit)
+	shared.SetCachedCatalog(ctx, cacheKey, shared.CatalogPage[*homeservicedto.ServiceListResponse]{Items: responses, Pagination: pagination})
 	return responses, &pagination, nil
 }
 
@@ -182,9 +205,9 @@ func (s *service) RegisterProvider(ctx context.Context, userID string, req homes
 		UserID:          userID,
 		ServiceCategory: req.CategorySlug,
 		ServiceType:     req.CategorySlug,
-		Status:          models.SPStatusActive,
-		IsVerified:      true,
-		IsAvailable:     true,
+		Status:          models.SPStatusPendingApproval,
+		IsVerified:      false,
+		IsAvailable:     false,
 	}
 
 	if err := s.repo.CreateProvider(ctx, provider); err != nil {
@@ -308,7 +331,7 @@ func (s *service) CreateOrder(ctx context.Context, userID string, req homeservic
 		}
 	}
 
-	platformFee := subtotal * 0.10
+	platformFee := shared.CalculatePlatformCommission(subtotal, shared.DefaultCommissionRate)
 	totalPrice := subtotal + platformFee
 
 	var holdID *string
@@ -327,17 +350,20 @@ func (s *service) CreateOrder(ctx context.Context, userID string, req homeservic
 		logger.Info("payment hold created for tracking", "holdID", holdResp.ID, "amount", totalPrice)
 	}
 
+	address := translit.NormalizeAddress(req.Address)
 	order := &models.ServiceOrderNew{
 		ID:          uuid.New().String(),
 		OrderNumber: s.generateOrderCode(),
 		CustomerID:  userID,
 		CustomerInfo: models.CustomerInfo{
-			Name:    "",
-			Phone:   "",
-			Email:   "",
-			Address: req.Address,
-			Lat:     req.Latitude,
-			Lng:     req.Longitude,
+			Name:              "",
+			Phone:             "",
+			Email:             "",
+			Address:           req.Address,
+			Lat:               req.Latitude,
+			Lng:               req.Longitude,
+			NormalizedAddress: address.Normalized,
+			AddressScript:     address.Script,
 		},
 		BookingInfo: models.BookingInfo{
 			Date:           serviceDate.Format("2006-01-02"),
@@ -355,6 +381,7 @@ func (s *service) CreateOrder(ctx context.Context, userID string, req homeservic
 		AddonsTotal:        0,
 		Subtotal:           subtotal,
 		PlatformCommission: platformFee,
+		CommissionRate:     shared.DefaultCommissionRate,
 		TotalPrice:         totalPrice,
 		PaymentInfo: &models.PaymentInfo{
 			Method: "cash",
@@ -376,7 +403,7 @@ func (s *service) CreateOrder(ctx context.Context, userID string, req homeservic
 
 	logger.Info("order created", "orderID", order.ID, "userID", userID, "total", totalPrice)
 
-	go s.FindAndNotifyNextProvider(order.ID)
+	s.requestProviderMatching(ctx, order.ID)
 
 	return homeservicedto.ToOrderResponseFromNew(order), nil
 }
@@ -518,7 +545,7 @@ func (s *service) RejectOrder(ctx context.Context, providerID, orderID string) e
 
 	logger.Info("provider rejected order", "providerID", providerID, "orderID", orderID)
 
-	go s.FindAndNotifyNextProvider(orderID)
+	s.requestProviderMatching(ctx, orderID)
 
 	return nil
 }
@@ -541,6 +568,11 @@ func (s *service) StartOrder(ctx context.Context, providerID, orderID string) er
 		return response.InternalServerError("Failed to start order", err)
 	}
 
+	if s.locationSharing != nil {
+		order.Status = "in_progress"
+		s.locationSharing.StopProviderLocationSharing(ctx, order)
+	}
+
 	logger.Info("order started", "providerID", providerID, "orderID", orderID)
 
 	return nil
@@ -560,9 +592,16 @@ func (s *service) CompleteOrder(ctx context.Context, providerID, orderID string)
 		return response.BadRequest("Order must be in progress to complete")
 	}
 
+	if s.proofs != nil {
+		if err := s.proofs.CheckProofs(ctx, "service_order", order.ID, order.CategorySlug); err != nil {
+			return err
+		}
+	}
+
 	if order.WalletHoldID != nil {
 		captureReq := walletdto.CaptureHoldRequest{
 			HoldID:      *order.WalletHoldID,
+			Currency:    order.Currency,
 			Description: fmt.Sprintf("Payment for order %s", order.OrderNumber),
 		}
 		if _, err := s.walletService.CaptureHold(ctx, order.CustomerID, captureReq); err != nil {
@@ -654,6 +693,7 @@ func (s *service) CreateCategory(ctx context.Context, req homeservicedto.CreateC
 		return nil, response.InternalServerError("Failed to create category", err)
 	}
 
+	shared.InvalidateCatalogCache(ctx)
 	logger.Info("category created", "categoryID", category.ID, "name", category.Name)
 
 	return homeservicedto.ToCategoryWithTabsResponse(category), nil
@@ -706,6 +746,7 @@ func (s *service) CreateTab(ctx context.Context, req homeservicedto.CreateTabReq
 		ServicesCount: int(servicesCount),
 	}
 
+	shared.InvalidateCatalogCache(ctx)
 	logger.Info("tab created", "tabID", tab.ID, "name", tab.Name)
 
 	return response, nil
@@ -749,6 +790,7 @@ func (s *service) CreateService(ctx context.Context, req homeservicedto.CreateSe
 		return nil, response.InternalServerError("Failed to create service", err)
 	}
 
+	shared.InvalidateCatalogCache(ctx)
 	logger.Info("service created", "serviceID", service.ID, "name", service.Name)
 
 	completeService, err := s.repo.GetServiceWithOptions(ctx, service.ID)
@@ -802,6 +844,7 @@ func (s *service) UpdateService(ctx context.Context, id uint, req homeservicedto
 		return nil, response.InternalServerError("Failed to update service", err)
 	}
 
+	shared.InvalidateCatalogCache(ctx)
 	logger.Info("service updated", "serviceID", id)
 
 	completeService, err := s.repo.GetServiceWithOptions(ctx, id)
@@ -838,6 +881,7 @@ func (s *service) CreateAddOn(ctx context.Context, req homeservicedto.CreateAddO
 		return nil, response.InternalServerError("Failed to create add-on", err)
 	}
 
+	shared.InvalidateCatalogCache(ctx)
 	logger.Info("add-on created", "addOnID", addOn.ID, "title", addOn.Title)
 
 	return homeservicedto.ToAddOnResponse(addOn), nil
@@ -0,0 +1,287 @@
+package shared
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+// ValidateAdjustable checks that the scope of an order can still be changed:
+// the provider has taken the job and has not finished it. Multi-day bookings
+// are paid session by session and keep their scope.
+func ValidateAdjustable(order *models.ServiceOrderNew) error {
+	if order.Status != OrderStatusAccepted && order.Status != OrderStatusInProgress {
+		return response.BadRequest(fmt.Sprintf("Cannot adjust an order in '%s' status", order.Status))
+	}
+	if order.IsMultiSession {
+		return response.BadRequest("Multi-day bookings cannot be adjusted")
+	}
+	return nil
+}
+
+// PrepareAdjustmentLines checks proposed lines against the order. Removals
+// take the title and price the item was booked at; additions of an item the
+// order already has default to its title.
+func PrepareAdjustmentLines(order *models.ServiceOrderNew, lines models.OrderAdjustmentLines) (models.OrderAdjustmentLines, error) {
+	prepared := make(models.OrderAdjustmentLines, 0, len(lines))
+	for _, line := range lines {
+		title, price, booked := bookedItem(order, line.ItemType, line.Slug)
+		switch line.Action {
+		case models.AdjustmentActionRemove:
+			if !booked {
+				return nil, response.BadRequest(fmt.Sprintf("'%s' is not part of this order", line.Slug))
+			}
+			line.Title, line.Price = title, price
+		case models.AdjustmentActionAdd:
+			if line.Title == "" {
+				if !booked {
+					return nil, response.BadRequest(fmt.Sprintf("A title is required for '%s'", line.Slug))
+				}
+				line.Title = title
+			}
+			if line.Price <= 0 {
+				return nil, response.BadRequest(fmt.Sprintf("A price is required for '%s'", line.Slug))
+			}
+			line.Price = RoundToTwoDecimals(line.Price)
+		default:
+			return nil, response.BadRequest(fmt.Sprintf("Unknown adjustment action '%s'", line.Action))
+		}
+		prepared = append(prepared, line)
+	}
+
+	if _, _, err := ApplyAdjustmentLines(order, prepared); err != nil {
+		return nil, err
+	}
+	return prepared, nil
+}
+
+func bookedItem(order *models.ServiceOrderNew, itemType, slug string) (string, float64, bool) {
+	if itemType == models.AdjustmentItemAddon {
+		for _, addon := range order.SelectedAddons {
+			if addon.AddonSlug == slug {
+				return addon.Title, addon.Price, true
+			}
+		}
+		return "", 0, false
+	}
+	for _, svc := range order.SelectedServices {
+		if svc.ServiceSlug == slug {
+			return svc.Title, svc.Price, true
+		}
+	}
+	return "", 0, false
+}
+
+// ApplyAdjustmentLines returns the order's services and addons with the lines
+// applied. The order itself is not changed.
+func ApplyAdjustmentLines(order *models.ServiceOrderNew, lines models.OrderAdjustmentLines) (models.SelectedServices, models.SelectedAddons, error) {
+	services := append(models.SelectedServices{}, order.SelectedServices...)
+	addons := append(models.SelectedAddons{}, order.SelectedAddons...)
+
+	for _, line := range lines {
+		if line.ItemType == models.AdjustmentItemAddon {
+			i := -1
+			for j := range addons {
+				if addons[j].AddonSlug == line.Slug && addons[j].Price == line.Price {
+					i = j
+					break
+				}
+			}
+			switch {
+			case line.Action == models.AdjustmentActionAdd && i >= 0:
+				addons[i].Quantity += line.Quantity
+			case line.Action == models.AdjustmentActionAdd:
+				addons = append(addons, models.SelectedAddonItem{AddonSlug: line.Slug, Title: line.Title, Price: line.Price, Quantity: line.Quantity})
+			case i < 0 || addons[i].Quantity < line.Quantity:
+				return nil, nil, response.BadRequest(fmt.Sprintf("Cannot remove more '%s' than the order has", line.Slug))
+			case addons[i].Quantity == line.Quantity:
+				addons = append(addons[:i], addons[i+1:]...)
+			default:
+				addons[i].Quantity -= line.Quantity
+			}
+			continue
+		}
+
+		i := -1
+		for j := range services {
+			if services[j].ServiceSlug == line.Slug && services[j].Price == line.Price {
+				i = j
+				break
+			}
+		}
+		switch {
+		case line.Action == models.AdjustmentActionAdd && i >= 0:
+			services[i].Quantity += line.Quantity
+		case line.Action == models.AdjustmentActionAdd:
+			services = append(services, models.SelectedServiceItem{ServiceSlug: line.Slug, Title: line.Title, Price: line.Price, Quantity: line.Quantity})
+		case i < 0 || services[i].Quantity < line.Quantity:
+			return nil, nil, response.BadRequest(fmt.Sprintf("Cannot remove more '%s' than the order has", line.Slug))
+		case services[i].Quantity == line.Quantity:
+			services = append(services[:i], services[i+1:]...)
+		default:
+			services[i].Quantity -= line.Quantity
+		}
+	}
+
+	if len(services) == 0 {
+		return nil, nil, response.BadRequest("At least one service must remain on the order")
+	}
+	return services, addons, nil
+}
+
+// RepriceOrder applies the lines to the order and recalculates its totals.
+// Commission and taxes are charged at the rates the order was booked with;
+// surcharges are priced again from the category's active ones.
+func RepriceOrder(order *models.ServiceOrderNew, lines models.OrderAdjustmentLines, surcharges []*models.CategorySurcharge) error {
+	services, addons, err := ApplyAdjustmentLines(order, lines)
+	if err != nil {
+		return err
+	}
+
+	servicesTotal, addonsTotal := 0.0, 0.0
+	for _, svc := range services {
+		servicesTotal += svc.Price * float64(svc.Quantity)
+	}
+	for _, addon := range addons {
+		addonsTotal += addon.Price * float64(addon.Quantity)
+	}
+
+	order.SelectedServices = services
+	order.SelectedAddons = addons
+	order.ServicesTotal = RoundToTwoDecimals(servicesTotal)
+	order.AddonsTotal = RoundToTwoDecimals(addonsTotal)
+	order.Subtotal = RoundToTwoDecimals(order.ServicesTotal + order.AddonsTotal)
+	order.PlatformCommission = CalculatePlatformCommission(order.Subtotal, order.CommissionRate)
+	order.Surcharges, order.SurchargesTotal = PriceSurcharges(surcharges, order.Subtotal)
+
+	taxable := RoundToTwoDecimals(order.Subtotal + order.SurchargesTotal)
+	taxes := make(models.TaxLines, len(order.Taxes))
+	for i, line := range order.Taxes {
+		line.TaxableAmount = taxable
+		line.Amount = math.Round(taxable*line.Rate) / 100
+		taxes[i] = line
+	}
+	order.Taxes = taxes
+	order.TaxTotal = taxes.Total()
+
+	order.TotalPrice = RoundToTwoDecimals(taxable + order.TaxTotal)
+	return nil
+}
+
+// NewOrderAdjustment prices a proposal against a copy of the order so the
+// customer sees the total they are asked to approve.
+func NewOrderAdjustment(order *models.ServiceOrderNew, providerID string, lines models.OrderAdjustmentLines, note string, surcharges []*models.CategorySurcharge) (*models.OrderAdjustment, error) {
+	preview := *order
+	if err := RepriceOrder(&preview, lines, surcharges); err != nil {
+		return nil, err
+	}
+	return &models.OrderAdjustment{
+		OrderID:       order.ID,
+		ProviderID:    providerID,
+		Lines:         lines,
+		PreviousTotal: order.TotalPrice,
+		NewTotal:      preview.TotalPrice,
+		Difference:    RoundToTwoDecimals(preview.TotalPrice - order.TotalPrice),
+		Status:        models.AdjustmentStatusPending,
+		Note:          note,
+	}, nil
+}
+
+// AdjustmentHistory builds the status history entry for an approved
+// adjustment. The order keeps its status; the entry records the new scope.
+func AdjustmentHistory(order *models.ServiceOrderNew, adjustment *models.OrderAdjustment, actorID, role string) *models.OrderStatusHistory {
+	return models.NewOrderStatusHistory(
+		order.ID,
+		order.Status,
+		order.Status,
+		&actorID,
+		role,
+		fmt.Sprintf("Scope adjusted; total changed from %.2f to %.2f", adjustment.PreviousTotal, adjustment.NewTotal),
+		models.StatusHistoryMetadata{
+			"adjustmentId":  adjustment.ID,
+			"previousTotal": adjustment.PreviousTotal,
+			"newTotal":      adjustment.NewTotal,
+			"difference":    adjustment.Difference,
+		},
+	)
+}
+
+// AdjustmentNotification is the websocket payload sent to either party.
+func AdjustmentNotification(order *models.ServiceOrderNew, adjustment *models.OrderAdjustment) map[string]interface{} {
+	return map[string]interface{}{
+		"orderId":       order.ID,
+		"orderNumber":   order.OrderNumber,
+		"adjustmentId":  adjustment.ID,
+		"status":        adjustment.Status,
+		"lines":         adjustment.Lines,
+		"previousTotal": adjustment.PreviousTotal,
+		"newTotal":      adjustment.NewTotal,
+		"difference":    adjustment.Difference,
+		"currency":      order.Currency,
+		"note":          adjustment.Note,
+		"customerNote":  adjustment.CustomerNote,
+	}
+}
+
+type AdjustmentResponse struct {
+	ID            string                      `json:"id"`
+	OrderID       string                      `json:"orderId"`
+	Lines         models.OrderAdjustmentLines `json:"lines"`
+	PreviousTotal float64                     `json:"previousTotal"`
+	NewTotal      float64                     `json:"newTotal"`
+	Difference    float64                     `json:"difference"`
+	Status        string                      `json:"status"`
+	Note          string                      `json:"note,omitempty"`
+	CustomerNote  string                      `json:"customerNote,omitempty"`
+	RespondedAt   *time.Time                  `json:"respondedAt,omitempty"`
+	CreatedAt     time.Time                   `json:"createdAt"`
+}
+
+// OrderAdjustmentsResponse is the order's current item checklist together
+// with every adjustment proposed for it.
+type OrderAdjustmentsResponse struct {
+	OrderID          string                  `json:"orderId"`
+	OrderNumber      string                  `json:"orderNumber"`
+	Status           string                  `json:"status"`
+	SelectedServices models.SelectedServices `json:"selectedServices"`
+	SelectedAddons   models.SelectedAddons   `json:"selectedAddons"`
+	TotalPrice       float64                 `json:"totalPrice"`
+	Currency         string                  `json:"currency"`
+	Adjustments      []*AdjustmentResponse   `json:"adjustments"`
+}
+
+func ToAdjustmentResponse(adjustment *models.OrderAdjustment) *AdjustmentResponse {
+	return &AdjustmentResponse{
+		ID:            adjustment.ID,
+		OrderID:       adjustment.OrderID,
+		Lines:         adjustment.Lines,
+		PreviousTotal: adjustment.PreviousTotal,
+		NewTotal:      adjustment.NewTotal,
+		Difference:    adjustment.Difference,
+		Status:        string(adjustment.Status),
+		Note:          adjustment.Note,
+		CustomerNote:  adjustment.CustomerNote,
+		RespondedAt:   adjustment.RespondedAt,
+		CreatedAt:     adjustment.CreatedAt,
+	}
+}
+
+func ToOrderAdjustmentsResponse(order *models.ServiceOrderNew, adjustments []*models.OrderAdjustment) *OrderAdjustmentsResponse {
+	resp := &OrderAdjustmentsResponse{
+		OrderID:          order.ID,
+		OrderNumber:      order.OrderNumber,
+		Status:           order.Status,
+		SelectedServices: order.SelectedServices,
+		SelectedAddons:   order.SelectedAddons,
+		TotalPrice:       order.TotalPrice,
+		Currency:         order.Currency,
+		Adjustments:      make([]*AdjustmentResponse, len(adjustments)),
+	}
+	for i, adjustment := range adjustments {
+		resp.Adjustments[i] = ToAdjustmentResponse(adjustment)
+	}
+	return resp
+}
@@ -0,0 +1,60 @@
+package shared
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+// The service catalog (categories, services, add-ons) changes only through
+// admin endpoints, so customer listings are cached and the whole catalog
+// namespace is dropped whenever an admin changes any part of it.
+const (
+	catalogCacheNamespace = "catalog:homeservices"
+	catalogCacheTTL       = 10 * time.Minute
+)
+
+// CatalogPage is the cached form of a paginated catalog listing.
+type CatalogPage[T any] struct {
+	Items      []T                     `json:"items"`
+	Pagination response.PaginationMeta `json:"pagination"`
+}
+
+// CatalogCacheKey builds a cache key for a catalog read. params, typically
+// the list query, is hashed so every filter and page combination gets its own
+// entry.
+func CatalogCacheKey(ctx context.Context, name string, params interface{}) string {
+	key := name
+	if params != nil {
+		raw, _ := json.Marshal(params)
+		sum := sha1.Sum(raw)
+		key += ":" + hex.EncodeToString(sum[:])
+	}
+	return cache.NamespaceKey(ctx, catalogCacheNamespace, key)
+}
+
+// GetCachedCatalog loads a cached catalog read into dest and reports whether
+// it was found.
+func GetCachedCatalog(ctx context.Context, key string, dest interface{}) bool {
+	return cache.GetJSON(ctx, key, dest) == nil
+}
+
+func SetCachedCatalog(ctx context.Context, key string, value interface{}) {
+	if err := cache.SetJSON(ctx, key, value, catalogCacheTTL); err != nil {
+		logger.Warn("failed to cache catalog read", "error", err, "key", key)
+	}
+}
+
+// InvalidateCatalogCache drops every cached catalog read. Call it after any
+// admin change to categories, services, add-ons or their compatibility.
+func InvalidateCatalogCache(ctx context.Context) {
+	if err := cache.InvalidateNamespace(ctx, catalogCacheNamespace); err != nil {
+		logger.Warn("failed to invalidate catalog cache", "error", err)
+	}
+}
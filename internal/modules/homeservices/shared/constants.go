@@ -73,6 +73,10 @@ const (
 	CancelledByProvider = "provider"
 	CancelledByAdmin    = "admin"
 	CancelledBySystem   = "system"
+
+	// CancellationReasonExpired marks orders the expiry job cancelled
+	// because no provider took them before ExpiresAt.
+	CancellationReasonExpired = "expired"
 )
 
 const (
@@ -95,7 +99,9 @@ const (
 )
 
 const (
-	PlatformCommissionRate = 0.10 
+	// DefaultCommissionRate is the platform's cut of an order, in percent,
+	// when no commission rate is configured for its category.
+	DefaultCommissionRate = 10.0
 )
 
 const (
@@ -0,0 +1,57 @@
+package shared
+
+import (
+	"context"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/services/eventbus"
+)
+
+// EventPublisher puts domain events on the event bus. It is satisfied by
+// eventbus.Bus and is optional.
+type EventPublisher interface {
+	Publish(ctx context.Context, eventType, key string, payload interface{}) error
+}
+
+// OrderAssignedEvent is the order.assigned payload for an order that has just
+// been given a provider. previousProviderID may be nil.
+func OrderAssignedEvent(order *models.ServiceOrderNew, previousProviderID *string, assignedBy string, assignedAt time.Time) eventbus.OrderAssignedPayload {
+	payload := eventbus.OrderAssignedPayload{
+		OrderID:      order.ID,
+		OrderNumber:  order.OrderNumber,
+		CustomerID:   order.CustomerID,
+		CategorySlug: order.CategorySlug,
+		Status:       order.Status,
+		TotalPrice:   order.TotalPrice,
+		Currency:     order.Currency,
+		AssignedBy:   assignedBy,
+		AssignedAt:   assignedAt,
+	}
+	if order.AssignedProviderID != nil {
+		payload.ProviderID = *order.AssignedProviderID
+	}
+	if previousProviderID != nil {
+		payload.PreviousProviderID = *previousProviderID
+	}
+	return payload
+}
+
+// OrderCompletedEvent is the order.completed payload for an order that has
+// just been completed.
+func OrderCompletedEvent(order *models.ServiceOrderNew) eventbus.OrderCompletedPayload {
+	payload := eventbus.OrderCompletedPayload{
+		OrderID:      order.ID,
+		OrderNumber:  order.OrderNumber,
+		CustomerID:   order.CustomerID,
+		CategorySlug: order.CategorySlug,
+		TotalPrice:   order.TotalPrice,
+		TaxAmount:    order.TaxTotal,
+		Currency:     order.Currency,
+		CompletedAt:  order.CompletedAt,
+	}
+	if order.AssignedProviderID != nil {
+		payload.ProviderID = *order.AssignedProviderID
+	}
+	return payload
+}
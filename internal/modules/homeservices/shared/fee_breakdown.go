@@ -0,0 +1,38 @@
+package shared
+
+import (
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/pricing"
+	pricingdto "github.com/umar5678/go-backend/internal/modules/pricing/dto"
+)
+
+// BuildOrderFeeBreakdown maps the totals persisted on the order at creation onto
+// the standard fee breakdown. Provider share mirrors the payout made on completion;
+// surcharges and taxes are listed separately and belong to neither party.
+func BuildOrderFeeBreakdown(order *models.ServiceOrderNew, viewer pricing.FeeViewer) *pricingdto.FeeBreakdownResponse {
+	resp := &pricingdto.FeeBreakdownResponse{
+		ReferenceType: "service_order",
+		ReferenceID:   order.ID,
+		EarnerRole:    "provider",
+		Source:        pricing.FeeSourceOrder,
+		IsFinal:       order.Status == OrderStatusCompleted,
+		Currency:      order.Currency,
+		TotalCharged:  RoundToTwoDecimals(order.TotalPrice),
+		CapturedAt:    &order.CreatedAt,
+	}
+
+	for _, svc := range order.SelectedServices {
+		resp.Charges = pricing.AppendFeeItem(resp.Charges, "service:"+svc.ServiceSlug, svc.Title, svc.Price*float64(svc.Quantity))
+	}
+	for _, addon := range order.SelectedAddons {
+		resp.Charges = pricing.AppendFeeItem(resp.Charges, "addon:"+addon.AddonSlug, addon.Title, addon.Price*float64(addon.Quantity))
+	}
+	for _, surcharge := range order.Surcharges {
+		resp.Surcharges = pricing.AppendFeeItem(resp.Surcharges, "surcharge:"+surcharge.Code, surcharge.Name, surcharge.Amount)
+	}
+	resp.Taxes = pricing.AppendTaxItems(resp.Taxes, order.Taxes)
+
+	providerShare := RoundToTwoDecimals(order.ServiceAmount() - order.PlatformCommission)
+
+	return pricing.FinalizeFeeBreakdown(resp, order.PlatformCommission, providerShare, viewer)
+}
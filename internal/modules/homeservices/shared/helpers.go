@@ -6,12 +6,14 @@ import (
 	"time"
 )
 
-func CalculatePlatformCommission(total float64) float64 {
-	return RoundToTwoDecimals(total * PlatformCommissionRate)
+// CalculatePlatformCommission is the platform's cut of total at a commission
+// rate given in percent.
+func CalculatePlatformCommission(total, commissionRate float64) float64 {
+	return RoundToTwoDecimals(total * commissionRate / 100)
 }
 
-func CalculateProviderEarnings(total float64) float64 {
-	commission := CalculatePlatformCommission(total)
+func CalculateProviderEarnings(total, commissionRate float64) float64 {
+	commission := CalculatePlatformCommission(total, commissionRate)
 	return RoundToTwoDecimals(total - commission)
 }
 
@@ -172,3 +174,10 @@ func BoolPtr(b bool) *bool {
 func TimePtr(t time.Time) *time.Time {
 	return &t
 }
+
+// SQL counterparts of models.ServiceOrderNew.ProviderPayout and the laundry
+// payout, for earnings aggregates over service_orders and laundry_orders.
+const (
+	ServiceOrderPayoutSQL = "(total_price - COALESCE(surcharges_total, 0) - COALESCE(tax_total, 0)) * (100 - commission_rate) / 100"
+	LaundryOrderPayoutSQL = "total * (100 - commission_rate) / 100"
+)
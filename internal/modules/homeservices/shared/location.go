@@ -0,0 +1,14 @@
+package shared
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+// LocationSharing ends the live location a provider shares with the
+// customer on the way to an order. It is satisfied by tracking.Service and
+// is optional.
+type LocationSharing interface {
+	StopProviderLocationSharing(ctx context.Context, order *models.ServiceOrderNew)
+}
@@ -0,0 +1,9 @@
+package shared
+
+import "context"
+
+// ProofChecker reports whether an order has the proof of service its
+// category requires. It is satisfied by proofs.Service and is optional.
+type ProofChecker interface {
+	CheckProofs(ctx context.Context, orderType, orderID, categorySlug string) error
+}
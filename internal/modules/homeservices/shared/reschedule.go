@@ -0,0 +1,198 @@
+package shared
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+// MaxRescheduleAheadDays is how far out a booking can be moved.
+const MaxRescheduleAheadDays = 90
+
+// RescheduleRules returns the config with defaults filled in for services
+// that were never configured.
+func RescheduleRules(cfg config.RescheduleConfig) config.RescheduleConfig {
+	if cfg.MinNotice <= 0 {
+		cfg.MinNotice = 12 * time.Hour
+	}
+	if cfg.MaxPerOrder <= 0 {
+		cfg.MaxPerOrder = 2
+	}
+	return cfg
+}
+
+// ValidateReschedule checks that an order may be moved to the given slot.
+// Multi-day bookings are moved session by session and cannot be rescheduled
+// as a whole.
+func ValidateReschedule(order *models.ServiceOrderNew, date, timeStr string, cfg config.RescheduleConfig, now time.Time) error {
+	cfg = RescheduleRules(cfg)
+
+	allowed := false
+	for _, status := range CancelableOrderStatuses() {
+		if order.Status == status {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return response.BadRequest(fmt.Sprintf("Cannot reschedule an order in '%s' status", order.Status))
+	}
+	if order.IsMultiSession {
+		return response.BadRequest("Multi-day bookings cannot be rescheduled")
+	}
+	if order.RescheduleCount >= cfg.MaxPerOrder {
+		return response.BadRequest(fmt.Sprintf("This order has already been rescheduled %d times", order.RescheduleCount))
+	}
+
+	current, err := ParseBookingDateTime(order.BookingInfo.Date, order.BookingInfo.Time)
+	if err == nil && current.Sub(now) < cfg.MinNotice {
+		return response.BadRequest(fmt.Sprintf("Bookings can only be rescheduled up to %s before they start", formatNotice(cfg.MinNotice)))
+	}
+
+	requested, err := ParseBookingDateTime(date, timeStr)
+	if err != nil {
+		return response.BadRequest("Invalid date or time; expected YYYY-MM-DD and HH:MM")
+	}
+	if requested.Sub(now) < cfg.MinNotice {
+		return response.BadRequest(fmt.Sprintf("The new time must be at least %s from now", formatNotice(cfg.MinNotice)))
+	}
+	if requested.After(now.AddDate(0, 0, MaxRescheduleAheadDays)) {
+		return response.BadRequest(fmt.Sprintf("The new time must be within %d days", MaxRescheduleAheadDays))
+	}
+	if date == order.BookingInfo.Date && timeStr == order.BookingInfo.Time {
+		return response.BadRequest("The order is already booked for that time")
+	}
+	return nil
+}
+
+func formatNotice(d time.Duration) string {
+	if d%time.Hour == 0 {
+		return fmt.Sprintf("%d hours", int(d.Hours()))
+	}
+	return d.String()
+}
+
+// NewOrderReschedule records a request to move the order to a new slot.
+func NewOrderReschedule(order *models.ServiceOrderNew, requestedBy, requestedByID, date, timeStr, reason string) *models.OrderReschedule {
+	return &models.OrderReschedule{
+		OrderID:       order.ID,
+		RequestedBy:   requestedBy,
+		RequestedByID: requestedByID,
+		PreviousDate:  order.BookingInfo.Date,
+		PreviousTime:  order.BookingInfo.Time,
+		NewDate:       date,
+		NewTime:       timeStr,
+		Reason:        reason,
+		Status:        models.RescheduleStatusPending,
+	}
+}
+
+// ApplyReschedule moves the order to the reschedule's new slot and marks the
+// reschedule applied.
+func ApplyReschedule(order *models.ServiceOrderNew, reschedule *models.OrderReschedule) {
+	order.BookingInfo.Date = reschedule.NewDate
+	order.BookingInfo.Time = reschedule.NewTime
+	if day, err := GetDayOfWeek(reschedule.NewDate); err == nil {
+		order.BookingInfo.Day = day
+	}
+	order.RescheduleCount++
+
+	reschedule.Status = models.RescheduleStatusApplied
+}
+
+// RescheduleHistory builds the status history entry for an applied
+// reschedule. The order keeps its status; the entry only records the move.
+func RescheduleHistory(order *models.ServiceOrderNew, reschedule *models.OrderReschedule, actorID, role string) *models.OrderStatusHistory {
+	return models.NewOrderStatusHistory(
+		order.ID,
+		order.Status,
+		order.Status,
+		&actorID,
+		role,
+		fmt.Sprintf("Rescheduled from %s %s to %s %s", reschedule.PreviousDate, reschedule.PreviousTime, reschedule.NewDate, reschedule.NewTime),
+		models.StatusHistoryMetadata{
+			"rescheduleId": reschedule.ID,
+			"requestedBy":  reschedule.RequestedBy,
+			"previousDate": reschedule.PreviousDate,
+			"previousTime": reschedule.PreviousTime,
+			"newDate":      reschedule.NewDate,
+			"newTime":      reschedule.NewTime,
+		},
+	)
+}
+
+// RescheduleNotification is the websocket payload sent to either party.
+func RescheduleNotification(order *models.ServiceOrderNew, reschedule *models.OrderReschedule) map[string]interface{} {
+	return map[string]interface{}{
+		"orderId":      order.ID,
+		"orderNumber":  order.OrderNumber,
+		"rescheduleId": reschedule.ID,
+		"requestedBy":  reschedule.RequestedBy,
+		"status":       reschedule.Status,
+		"previousDate": reschedule.PreviousDate,
+		"previousTime": reschedule.PreviousTime,
+		"newDate":      reschedule.NewDate,
+		"newTime":      reschedule.NewTime,
+		"reason":       reschedule.Reason,
+		"responseNote": reschedule.ResponseNote,
+	}
+}
+
+type RescheduleResponse struct {
+	ID           string     `json:"id"`
+	OrderID      string     `json:"orderId"`
+	RequestedBy  string     `json:"requestedBy"`
+	PreviousDate string     `json:"previousDate"`
+	PreviousTime string     `json:"previousTime"`
+	NewDate      string     `json:"newDate"`
+	NewTime      string     `json:"newTime"`
+	Reason       string     `json:"reason,omitempty"`
+	Status       string     `json:"status"`
+	ResponseNote string     `json:"responseNote,omitempty"`
+	RespondedAt  *time.Time `json:"respondedAt,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+}
+
+type OrderReschedulesResponse struct {
+	OrderID         string                `json:"orderId"`
+	OrderNumber     string                `json:"orderNumber"`
+	BookingDate     string                `json:"bookingDate"`
+	BookingTime     string                `json:"bookingTime"`
+	RescheduleCount int                   `json:"rescheduleCount"`
+	Reschedules     []*RescheduleResponse `json:"reschedules"`
+}
+
+func ToRescheduleResponse(reschedule *models.OrderReschedule) *RescheduleResponse {
+	return &RescheduleResponse{
+		ID:           reschedule.ID,
+		OrderID:      reschedule.OrderID,
+		RequestedBy:  reschedule.RequestedBy,
+		PreviousDate: reschedule.PreviousDate,
+		PreviousTime: reschedule.PreviousTime,
+		NewDate:      reschedule.NewDate,
+		NewTime:      reschedule.NewTime,
+		Reason:       reschedule.Reason,
+		Status:       string(reschedule.Status),
+		ResponseNote: reschedule.ResponseNote,
+		RespondedAt:  reschedule.RespondedAt,
+		CreatedAt:    reschedule.CreatedAt,
+	}
+}
+
+func ToOrderReschedulesResponse(order *models.ServiceOrderNew, reschedules []*models.OrderReschedule) *OrderReschedulesResponse {
+	resp := &OrderReschedulesResponse{
+		OrderID:         order.ID,
+		OrderNumber:     order.OrderNumber,
+		BookingDate:     order.BookingInfo.Date,
+		BookingTime:     order.BookingInfo.Time,
+		RescheduleCount: order.RescheduleCount,
+		Reschedules:     make([]*RescheduleResponse, len(reschedules)),
+	}
+	for i, reschedule := range reschedules {
+		resp.Reschedules[i] = ToRescheduleResponse(reschedule)
+	}
+	return resp
+}
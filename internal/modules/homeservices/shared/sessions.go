@@ -0,0 +1,136 @@
+package shared
+
+import (
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+const (
+	MinOrderSessions = 2
+	MaxOrderSessions = 14
+)
+
+// SplitMilestones divides an order total into per-session payment milestones.
+// Explicit percentages are used when given, otherwise the total is split by
+// each session's share of the booked hours. The last milestone absorbs any
+// rounding so the milestones always add up to the total.
+func SplitMilestones(total float64, hours []float64, percents []float64) []float64 {
+	milestones := make([]float64, len(hours))
+	if len(hours) == 0 {
+		return milestones
+	}
+
+	weights := percents
+	if len(weights) != len(hours) {
+		weights = hours
+	}
+
+	var weightSum float64
+	for _, w := range weights {
+		weightSum += w
+	}
+
+	var allocated float64
+	for i := range milestones {
+		if i == len(milestones)-1 {
+			milestones[i] = RoundToTwoDecimals(total - allocated)
+			break
+		}
+		milestones[i] = RoundToTwoDecimals(total * weights[i] / weightSum)
+		allocated += milestones[i]
+	}
+	return milestones
+}
+
+// NextOpenSession returns the first session that has not been approved.
+// Sessions must be ordered by session number.
+func NextOpenSession(sessions []*models.ServiceOrderSession) *models.ServiceOrderSession {
+	for _, session := range sessions {
+		if session.Status != models.SessionStatusApproved && session.Status != models.SessionStatusCancelled {
+			return session
+		}
+	}
+	return nil
+}
+
+type OrderSessionResponse struct {
+	ID                 string     `json:"id"`
+	SessionNumber      int        `json:"sessionNumber"`
+	ScheduledDate      string     `json:"scheduledDate"`
+	ScheduledTime      string     `json:"scheduledTime"`
+	DurationHours      float64    `json:"durationHours"`
+	Status             string     `json:"status"`
+	CheckedInAt        *time.Time `json:"checkedInAt,omitempty"`
+	CheckedOutAt       *time.Time `json:"checkedOutAt,omitempty"`
+	WorkSummary        string     `json:"workSummary,omitempty"`
+	ProgressPercent    int        `json:"progressPercent"`
+	MilestoneAmount    float64    `json:"milestoneAmount"`
+	MilestonePaid      bool       `json:"milestonePaid"`
+	CustomerApprovedAt *time.Time `json:"customerApprovedAt,omitempty"`
+	CustomerFeedback   string     `json:"customerFeedback,omitempty"`
+}
+
+type OrderSessionsResponse struct {
+	OrderID           string                 `json:"orderId"`
+	OrderNumber       string                 `json:"orderNumber"`
+	OrderStatus       string                 `json:"orderStatus"`
+	SessionCount      int                    `json:"sessionCount"`
+	ApprovedSessions  int                    `json:"approvedSessions"`
+	ProgressPercent   int                    `json:"progressPercent"`
+	TotalPrice        float64                `json:"totalPrice"`
+	MilestonesPaid    float64                `json:"milestonesPaid"`
+	MilestonesPending float64                `json:"milestonesPending"`
+	NextSessionID     string                 `json:"nextSessionId,omitempty"`
+	Sessions          []OrderSessionResponse `json:"sessions"`
+}
+
+func ToOrderSessionResponse(session *models.ServiceOrderSession) OrderSessionResponse {
+	return OrderSessionResponse{
+		ID:                 session.ID,
+		SessionNumber:      session.SessionNumber,
+		ScheduledDate:      session.ScheduledDate,
+		ScheduledTime:      session.ScheduledTime,
+		DurationHours:      session.DurationHours,
+		Status:             string(session.Status),
+		CheckedInAt:        session.CheckedInAt,
+		CheckedOutAt:       session.CheckedOutAt,
+		WorkSummary:        session.WorkSummary,
+		ProgressPercent:    session.ProgressPercent,
+		MilestoneAmount:    session.MilestoneAmount,
+		MilestonePaid:      session.MilestonePaidAt != nil,
+		CustomerApprovedAt: session.CustomerApprovedAt,
+		CustomerFeedback:   session.CustomerFeedback,
+	}
+}
+
+func ToOrderSessionsResponse(order *models.ServiceOrderNew, sessions []*models.ServiceOrderSession) *OrderSessionsResponse {
+	resp := &OrderSessionsResponse{
+		OrderID:         order.ID,
+		OrderNumber:     order.OrderNumber,
+		OrderStatus:     order.Status,
+		SessionCount:    len(sessions),
+		ProgressPercent: order.ProgressPercent,
+		TotalPrice:      order.TotalPrice,
+		Sessions:        make([]OrderSessionResponse, 0, len(sessions)),
+	}
+
+	for _, session := range sessions {
+		if session.IsApproved() {
+			resp.ApprovedSessions++
+		}
+		if session.MilestonePaidAt != nil {
+			resp.MilestonesPaid += session.MilestoneAmount
+		} else if session.Status != models.SessionStatusCancelled {
+			resp.MilestonesPending += session.MilestoneAmount
+		}
+		resp.Sessions = append(resp.Sessions, ToOrderSessionResponse(session))
+	}
+	resp.MilestonesPaid = RoundToTwoDecimals(resp.MilestonesPaid)
+	resp.MilestonesPending = RoundToTwoDecimals(resp.MilestonesPending)
+
+	if next := NextOpenSession(sessions); next != nil {
+		resp.NextSessionID = next.ID
+	}
+	return resp
+}
@@ -0,0 +1,25 @@
+package shared
+
+import "github.com/umar5678/go-backend/internal/models"
+
+// PriceSurcharges applies a category's active surcharges to an order subtotal
+// and returns the snapshot stored on the order along with its total.
+func PriceSurcharges(surcharges []*models.CategorySurcharge, subtotal float64) (models.OrderSurcharges, float64) {
+	items := models.OrderSurcharges{}
+	total := 0.0
+	for _, surcharge := range surcharges {
+		amount := RoundToTwoDecimals(surcharge.Calculate(subtotal))
+		if amount <= 0 {
+			continue
+		}
+		items = append(items, models.OrderSurchargeItem{
+			SurchargeID: surcharge.ID,
+			Code:        surcharge.Code,
+			Name:        surcharge.Name,
+			Kind:        surcharge.Kind,
+			Amount:      amount,
+		})
+		total += amount
+	}
+	return items, RoundToTwoDecimals(total)
+}
@@ -0,0 +1,62 @@
+package shared
+
+import (
+	"errors"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrOrderConflict means an order changed between being read and being
+// written: its version no longer matches the one the update was based on.
+var ErrOrderConflict = errors.New("order was modified concurrently")
+
+const orderConflictAttempts = 3
+
+// SaveOrderVersioned writes every column of order, but only if the row is
+// still at order.Version, and bumps the version. It returns ErrOrderConflict
+// when another writer got there first.
+func SaveOrderVersioned(db *gorm.DB, order *models.ServiceOrderNew) error {
+	expected := order.Version
+	order.Version++
+
+	result := db.Model(order).
+		Where("version = ?", expected).
+		Select("*").
+		Omit(clause.Associations, "created_at").
+		Updates(order)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = ErrOrderConflict
+	}
+	if result.Error != nil {
+		order.Version = expected
+	}
+	return result.Error
+}
+
+// RetryOnConflict runs attempt again when it fails with ErrOrderConflict, so
+// it must reload the order each time. When every attempt conflicts the
+// caller gets a 409.
+func RetryOnConflict(attempt func() error) error {
+	var err error
+	for i := 0; i < orderConflictAttempts; i++ {
+		if err = attempt(); !errors.Is(err, ErrOrderConflict) {
+			return err
+		}
+	}
+	return OrderConflictError()
+}
+
+// OrderUpdateError turns a failed order update into the API error for it.
+func OrderUpdateError(err error, message string) *response.AppError {
+	if errors.Is(err, ErrOrderConflict) {
+		return OrderConflictError()
+	}
+	return response.InternalServerError(message, err)
+}
+
+func OrderConflictError() *response.AppError {
+	return response.ConflictError("Order was changed by someone else, please reload it and try again")
+}
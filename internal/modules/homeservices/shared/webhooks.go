@@ -0,0 +1,40 @@
+package shared
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+// WebhookPublisher queues events for external webhook subscribers. It is
+// satisfied by webhooks.Service and is optional.
+type WebhookPublisher interface {
+	Publish(ctx context.Context, eventType string, data map[string]interface{}) error
+}
+
+// OrderCancelledWebhook is the order.cancelled event data for a cancelled
+// order. previousStatus may be empty when it is not known.
+func OrderCancelledWebhook(order *models.ServiceOrderNew, previousStatus string) map[string]interface{} {
+	data := map[string]interface{}{
+		"orderId":      order.ID,
+		"orderNumber":  order.OrderNumber,
+		"customerId":   order.CustomerID,
+		"categorySlug": order.CategorySlug,
+		"totalPrice":   order.TotalPrice,
+		"currency":     order.Currency,
+	}
+	if order.AssignedProviderID != nil {
+		data["providerId"] = *order.AssignedProviderID
+	}
+	if previousStatus != "" {
+		data["previousStatus"] = previousStatus
+	}
+	if info := order.CancellationInfo; info != nil {
+		data["cancelledBy"] = info.CancelledBy
+		data["cancelledAt"] = info.CancelledAt
+		data["reason"] = info.Reason
+		data["cancellationFee"] = info.CancellationFee
+		data["refundAmount"] = info.RefundAmount
+	}
+	return data
+}
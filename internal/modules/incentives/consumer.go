@@ -0,0 +1,8 @@
+package incentives
+
+import "github.com/umar5678/go-backend/internal/services/eventbus"
+
+// Subscribe counts completed rides on the bus towards incentive campaigns.
+func Subscribe(bus eventbus.Bus, service Service) error {
+	return bus.Subscribe("incentives", service.HandleEvent, eventbus.RideCompleted)
+}
@@ -0,0 +1,137 @@
+package dto
+
+import (
+	"errors"
+	"time"
+)
+
+type CreateCampaignRequest struct {
+	Name        string `json:"name" binding:"required,min=3,max=200"`
+	Description string `json:"description" binding:"omitempty,max=5000"`
+	Type        string `json:"type" binding:"required,oneof=quest boost"`
+	// CityID and the zone limit the campaign to rides picked up there.
+	CityID       *string  `json:"cityId" binding:"omitempty,uuid"`
+	ZoneName     string   `json:"zoneName" binding:"omitempty,max=255"`
+	ZoneLat      *float64 `json:"zoneLat" binding:"omitempty,min=-90,max=90"`
+	ZoneLon      *float64 `json:"zoneLon" binding:"omitempty,min=-180,max=180"`
+	ZoneRadiusKm *float64 `json:"zoneRadiusKm" binding:"omitempty,gt=0,max=100"`
+	// VehicleTypeID limits the campaign to rides in that vehicle type.
+	VehicleTypeID *string   `json:"vehicleTypeId" binding:"omitempty,uuid"`
+	StartsAt      time.Time `json:"startsAt" binding:"required"`
+	EndsAt        time.Time `json:"endsAt" binding:"required"`
+	// DailyStart and DailyEnd ("15:04") limit rides to those hours each day
+	// in Timezone, which defaults to the city's.
+	DailyStart *string `json:"dailyStart" binding:"omitempty,datetime=15:04"`
+	DailyEnd   *string `json:"dailyEnd" binding:"omitempty,datetime=15:04"`
+	Timezone   string  `json:"timezone" binding:"omitempty,max=64"`
+	// TargetRides is how many rides a quest needs.
+	TargetRides int `json:"targetRides" binding:"omitempty,min=1,max=1000"`
+	// RewardAmount is the quest bonus, or the boost paid on each ride.
+	RewardAmount float64 `json:"rewardAmount" binding:"required,gt=0"`
+	// MaxRewardsPerDriver caps how many rides a boost pays for per driver.
+	MaxRewardsPerDriver int    `json:"maxRewardsPerDriver" binding:"omitempty,min=1"`
+	Currency            string `json:"currency" binding:"omitempty,len=3"`
+}
+
+func (r *CreateCampaignRequest) Validate() error {
+	if !r.EndsAt.After(r.StartsAt) {
+		return errors.New("endsAt must be after startsAt")
+	}
+	if !r.EndsAt.After(time.Now()) {
+		return errors.New("endsAt must be in the future")
+	}
+	if r.Type == "quest" && r.TargetRides <= 0 {
+		return errors.New("targetRides is required for quests")
+	}
+	if r.Type == "quest" && r.MaxRewardsPerDriver > 0 {
+		return errors.New("maxRewardsPerDriver only applies to boosts")
+	}
+	if (r.ZoneLat == nil) != (r.ZoneLon == nil) || (r.ZoneLat == nil) != (r.ZoneRadiusKm == nil) {
+		return errors.New("zoneLat, zoneLon and zoneRadiusKm must be given together")
+	}
+	if (r.DailyStart == nil) != (r.DailyEnd == nil) {
+		return errors.New("dailyStart and dailyEnd must be given together")
+	}
+	if r.DailyStart != nil && *r.DailyStart == *r.DailyEnd {
+		return errors.New("dailyStart and dailyEnd must differ")
+	}
+	if r.Timezone != "" {
+		if _, err := time.LoadLocation(r.Timezone); err != nil {
+			return errors.New("unknown timezone")
+		}
+	}
+	return nil
+}
+
+// UpdateCampaignRequest changes the fields that are set. What a campaign
+// pays and who it is for cannot change once drivers are working towards it;
+// pause or cancel it and start another instead.
+type UpdateCampaignRequest struct {
+	Name        *string    `json:"name" binding:"omitempty,min=3,max=200"`
+	Description *string    `json:"description" binding:"omitempty,max=5000"`
+	Status      *string    `json:"status" binding:"omitempty,oneof=active paused cancelled"`
+	EndsAt      *time.Time `json:"endsAt"`
+}
+
+func (r *UpdateCampaignRequest) Validate() error {
+	if r.Name == nil && r.Description == nil && r.Status == nil && r.EndsAt == nil {
+		return errors.New("nothing to update")
+	}
+	return nil
+}
+
+type ListCampaignsRequest struct {
+	Type   string `form:"type" binding:"omitempty,oneof=quest boost"`
+	Status string `form:"status" binding:"omitempty,oneof=active paused cancelled"`
+	CityID string `form:"cityId" binding:"omitempty,uuid"`
+	// Running lists only active campaigns inside their window.
+	Running bool `form:"running"`
+	Page    int  `form:"page" binding:"omitempty,min=1"`
+	Limit   int  `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (r *ListCampaignsRequest) SetDefaults() {
+	if r.Page <= 0 {
+		r.Page = 1
+	}
+	if r.Limit <= 0 {
+		r.Limit = 20
+	}
+	if r.Limit > 100 {
+		r.Limit = 100
+	}
+}
+
+type ListProgressRequest struct {
+	Page  int `form:"page" binding:"omitempty,min=1"`
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (r *ListProgressRequest) SetDefaults() {
+	if r.Page <= 0 {
+		r.Page = 1
+	}
+	if r.Limit <= 0 {
+		r.Limit = 20
+	}
+	if r.Limit > 100 {
+		r.Limit = 100
+	}
+}
+
+type ListRewardsRequest struct {
+	Page  int `form:"page" binding:"omitempty,min=1"`
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (r *ListRewardsRequest) SetDefaults() {
+	if r.Page <= 0 {
+		r.Page = 1
+	}
+	if r.Limit <= 0 {
+		r.Limit = 20
+	}
+	if r.Limit > 100 {
+		r.Limit = 100
+	}
+}
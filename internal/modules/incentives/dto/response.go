@@ -0,0 +1,218 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+// CampaignResponse is a campaign as drivers see it, with their progress.
+type CampaignResponse struct {
+	ID                  string     `json:"id"`
+	Name                string     `json:"name"`
+	Description         string     `json:"description"`
+	Type                string     `json:"type"`
+	ZoneName            string     `json:"zoneName,omitempty"`
+	ZoneLat             *float64   `json:"zoneLat,omitempty"`
+	ZoneLon             *float64   `json:"zoneLon,omitempty"`
+	ZoneRadiusKm        *float64   `json:"zoneRadiusKm,omitempty"`
+	VehicleTypeID       *string    `json:"vehicleTypeId,omitempty"`
+	StartsAt            time.Time  `json:"startsAt"`
+	EndsAt              time.Time  `json:"endsAt"`
+	DailyStart          *string    `json:"dailyStart,omitempty"`
+	DailyEnd            *string    `json:"dailyEnd,omitempty"`
+	Timezone            string     `json:"timezone"`
+	TargetRides         int        `json:"targetRides,omitempty"`
+	RewardAmount        float64    `json:"rewardAmount"`
+	MaxRewardsPerDriver int        `json:"maxRewardsPerDriver,omitempty"`
+	Currency            string     `json:"currency"`
+	Progress            *Progress  `json:"progress"`
+	CompletedAt         *time.Time `json:"completedAt,omitempty"`
+}
+
+// Progress is how far a driver has got in a campaign. Remaining is the rides
+// left to finish a quest, or the boosted rides left when a boost is capped.
+type Progress struct {
+	RideCount    int        `json:"rideCount"`
+	Remaining    *int       `json:"remaining,omitempty"`
+	RewardCount  int        `json:"rewardCount"`
+	EarnedAmount float64    `json:"earnedAmount"`
+	Completed    bool       `json:"completed"`
+	LastRideAt   *time.Time `json:"lastRideAt,omitempty"`
+}
+
+// AdminCampaignResponse adds the status and totals across drivers.
+type AdminCampaignResponse struct {
+	ID                  string    `json:"id"`
+	Name                string    `json:"name"`
+	Description         string    `json:"description"`
+	Type                string    `json:"type"`
+	Status              string    `json:"status"`
+	Running             bool      `json:"running"`
+	CityID              *string   `json:"cityId,omitempty"`
+	ZoneName            string    `json:"zoneName,omitempty"`
+	ZoneLat             *float64  `json:"zoneLat,omitempty"`
+	ZoneLon             *float64  `json:"zoneLon,omitempty"`
+	ZoneRadiusKm        *float64  `json:"zoneRadiusKm,omitempty"`
+	VehicleTypeID       *string   `json:"vehicleTypeId,omitempty"`
+	StartsAt            time.Time `json:"startsAt"`
+	EndsAt              time.Time `json:"endsAt"`
+	DailyStart          *string   `json:"dailyStart,omitempty"`
+	DailyEnd            *string   `json:"dailyEnd,omitempty"`
+	Timezone            string    `json:"timezone"`
+	TargetRides         int       `json:"targetRides,omitempty"`
+	RewardAmount        float64   `json:"rewardAmount"`
+	MaxRewardsPerDriver int       `json:"maxRewardsPerDriver,omitempty"`
+	Currency            string    `json:"currency"`
+	Stats               *Stats    `json:"stats,omitempty"`
+	CreatedBy           string    `json:"createdBy"`
+	CreatedAt           time.Time `json:"createdAt"`
+	UpdatedAt           time.Time `json:"updatedAt"`
+}
+
+// Stats sums up a campaign across drivers.
+type Stats struct {
+	Drivers    int64   `json:"drivers"`
+	Rides      int64   `json:"rides"`
+	Completed  int64   `json:"completed"`
+	PaidAmount float64 `json:"paidAmount"`
+	Unpaid     int64   `json:"unpaid"`
+}
+
+// DriverProgressResponse is one driver's standing, for admins.
+type DriverProgressResponse struct {
+	DriverID     string     `json:"driverId"`
+	RideCount    int        `json:"rideCount"`
+	RewardCount  int        `json:"rewardCount"`
+	EarnedAmount float64    `json:"earnedAmount"`
+	CompletedAt  *time.Time `json:"completedAt,omitempty"`
+	LastRideAt   *time.Time `json:"lastRideAt,omitempty"`
+}
+
+type RewardResponse struct {
+	ID           string     `json:"id"`
+	CampaignID   string     `json:"campaignId"`
+	CampaignName string     `json:"campaignName,omitempty"`
+	CampaignType string     `json:"campaignType,omitempty"`
+	RideID       *string    `json:"rideId,omitempty"`
+	Amount       float64    `json:"amount"`
+	Currency     string     `json:"currency"`
+	Status       string     `json:"status"`
+	PaidAt       *time.Time `json:"paidAt,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+}
+
+// ToCampaignResponse shows the driver's progress; p may be nil when they
+// have not done a qualifying ride yet.
+func ToCampaignResponse(c *models.IncentiveCampaign, p *models.IncentiveProgress) *CampaignResponse {
+	resp := &CampaignResponse{
+		ID:                  c.ID,
+		Name:                c.Name,
+		Description:         c.Description,
+		Type:                string(c.Type),
+		ZoneName:            c.ZoneName,
+		ZoneLat:             c.ZoneLat,
+		ZoneLon:             c.ZoneLon,
+		ZoneRadiusKm:        c.ZoneRadiusKm,
+		VehicleTypeID:       c.VehicleTypeID,
+		StartsAt:            c.StartsAt,
+		EndsAt:              c.EndsAt,
+		DailyStart:          c.DailyStart,
+		DailyEnd:            c.DailyEnd,
+		Timezone:            c.Timezone,
+		TargetRides:         c.TargetRides,
+		RewardAmount:        c.RewardAmount,
+		MaxRewardsPerDriver: c.MaxRewardsPerDriver,
+		Currency:            c.Currency,
+		Progress:            ToProgress(c, p),
+	}
+	if p != nil {
+		resp.CompletedAt = p.CompletedAt
+	}
+	return resp
+}
+
+func ToProgress(c *models.IncentiveCampaign, p *models.IncentiveProgress) *Progress {
+	progress := &Progress{}
+	if p != nil {
+		progress.RideCount = p.RideCount
+		progress.RewardCount = p.RewardCount
+		progress.EarnedAmount = p.EarnedAmount
+		progress.Completed = p.CompletedAt != nil
+		progress.LastRideAt = p.LastRideAt
+	}
+
+	var remaining int
+	switch {
+	case c.Type == models.IncentiveQuest:
+		remaining = c.TargetRides - progress.RideCount
+	case c.MaxRewardsPerDriver > 0:
+		remaining = c.MaxRewardsPerDriver - progress.RewardCount
+	default:
+		return progress
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
+	progress.Remaining = &remaining
+	return progress
+}
+
+func ToAdminCampaignResponse(c *models.IncentiveCampaign, stats *Stats, now time.Time) *AdminCampaignResponse {
+	return &AdminCampaignResponse{
+		ID:                  c.ID,
+		Name:                c.Name,
+		Description:         c.Description,
+		Type:                string(c.Type),
+		Status:              string(c.Status),
+		Running:             c.IsRunning(now),
+		CityID:              c.CityID,
+		ZoneName:            c.ZoneName,
+		ZoneLat:             c.ZoneLat,
+		ZoneLon:             c.ZoneLon,
+		ZoneRadiusKm:        c.ZoneRadiusKm,
+		VehicleTypeID:       c.VehicleTypeID,
+		StartsAt:            c.StartsAt,
+		EndsAt:              c.EndsAt,
+		DailyStart:          c.DailyStart,
+		DailyEnd:            c.DailyEnd,
+		Timezone:            c.Timezone,
+		TargetRides:         c.TargetRides,
+		RewardAmount:        c.RewardAmount,
+		MaxRewardsPerDriver: c.MaxRewardsPerDriver,
+		Currency:            c.Currency,
+		Stats:               stats,
+		CreatedBy:           c.CreatedBy,
+		CreatedAt:           c.CreatedAt,
+		UpdatedAt:           c.UpdatedAt,
+	}
+}
+
+func ToDriverProgressResponse(p *models.IncentiveProgress) *DriverProgressResponse {
+	return &DriverProgressResponse{
+		DriverID:     p.DriverID,
+		RideCount:    p.RideCount,
+		RewardCount:  p.RewardCount,
+		EarnedAmount: p.EarnedAmount,
+		CompletedAt:  p.CompletedAt,
+		LastRideAt:   p.LastRideAt,
+	}
+}
+
+func ToRewardResponse(r *models.IncentiveReward) *RewardResponse {
+	resp := &RewardResponse{
+		ID:         r.ID,
+		CampaignID: r.CampaignID,
+		RideID:     r.RideID,
+		Amount:     r.Amount,
+		Currency:   r.Currency,
+		Status:     string(r.Status),
+		PaidAt:     r.PaidAt,
+		CreatedAt:  r.CreatedAt,
+	}
+	if r.Campaign != nil {
+		resp.CampaignName = r.Campaign.Name
+		resp.CampaignType = string(r.Campaign.Type)
+	}
+	return resp
+}
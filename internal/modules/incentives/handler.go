@@ -0,0 +1,220 @@
+package incentives
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/modules/incentives/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// ListIncentives godoc
+// @Summary List my incentives
+// @Description Quests and boosts running in the driver's city, and those they have taken part in, with their progress
+// @Tags incentives
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=[]dto.CampaignResponse}
+// @Router /incentives [get]
+func (h *Handler) ListIncentives(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	items, err := h.service.ListForDriver(c.Request.Context(), userID.(string))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, items, "Incentives retrieved successfully")
+}
+
+// GetIncentive godoc
+// @Summary Get my progress in an incentive
+// @Tags incentives
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Campaign ID"
+// @Success 200 {object} response.Response{data=dto.CampaignResponse}
+// @Failure 404 {object} response.Response
+// @Router /incentives/{id} [get]
+func (h *Handler) GetIncentive(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	campaign, err := h.service.GetForDriver(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, campaign, "Incentive retrieved successfully")
+}
+
+// ListRewards godoc
+// @Summary List my incentive rewards
+// @Description Quest bonuses and ride boosts earned, newest first, and whether they have reached the wallet
+// @Tags incentives
+// @Security BearerAuth
+// @Produce json
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Success 200 {object} response.Response{data=[]dto.RewardResponse}
+// @Router /incentives/rewards [get]
+func (h *Handler) ListRewards(c *gin.Context) {
+	var req dto.ListRewardsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+	req.SetDefaults()
+
+	userID, _ := c.Get("userID")
+
+	items, total, err := h.service.ListRewards(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Paginated(c, items, response.NewPaginationMeta(total, req.Page, req.Limit), "Incentive rewards retrieved successfully")
+}
+
+// CreateCampaign godoc
+// @Summary Create an incentive campaign
+// @Description A quest pays rewardAmount once a driver completes targetRides qualifying rides; a boost pays it on every qualifying ride, up to maxRewardsPerDriver
+// @Tags admin-incentives
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateCampaignRequest true "Campaign details"
+// @Success 200 {object} response.Response{data=dto.AdminCampaignResponse}
+// @Failure 400 {object} response.Response
+// @Router /admin/incentives [post]
+func (h *Handler) CreateCampaign(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req dto.CreateCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	campaign, err := h.service.CreateCampaign(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, campaign, "Incentive campaign created successfully")
+}
+
+// ListCampaigns godoc
+// @Summary List incentive campaigns
+// @Tags admin-incentives
+// @Security BearerAuth
+// @Produce json
+// @Param type query string false "quest or boost"
+// @Param status query string false "active, paused or cancelled"
+// @Param cityId query string false "Filter by city"
+// @Param running query bool false "Only campaigns running now"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Response{data=[]dto.AdminCampaignResponse}
+// @Router /admin/incentives [get]
+func (h *Handler) ListCampaigns(c *gin.Context) {
+	var req dto.ListCampaignsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+	req.SetDefaults()
+
+	items, total, err := h.service.ListCampaigns(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pagination := response.NewPaginationMeta(total, req.Page, req.Limit)
+	response.Paginated(c, items, pagination, "Incentive campaigns retrieved successfully")
+}
+
+// GetCampaign godoc
+// @Summary Get an incentive campaign
+// @Description Includes how many drivers took part, rides counted, quests completed and rewards paid
+// @Tags admin-incentives
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Campaign ID"
+// @Success 200 {object} response.Response{data=dto.AdminCampaignResponse}
+// @Router /admin/incentives/{id} [get]
+func (h *Handler) GetCampaign(c *gin.Context) {
+	campaign, err := h.service.GetCampaign(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, campaign, "Incentive campaign retrieved successfully")
+}
+
+// UpdateCampaign godoc
+// @Summary Update an incentive campaign
+// @Description Renames, pauses, resumes, cancels or moves the end of a campaign. Rewards already earned are still paid
+// @Tags admin-incentives
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Campaign ID"
+// @Param request body dto.UpdateCampaignRequest true "Fields to change"
+// @Success 200 {object} response.Response{data=dto.AdminCampaignResponse}
+// @Router /admin/incentives/{id} [patch]
+func (h *Handler) UpdateCampaign(c *gin.Context) {
+	var req dto.UpdateCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	campaign, err := h.service.UpdateCampaign(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, campaign, "Incentive campaign updated successfully")
+}
+
+// ListCampaignProgress godoc
+// @Summary List drivers' progress in an incentive campaign
+// @Description Drivers with the most qualifying rides first
+// @Tags admin-incentives
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Campaign ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Response{data=[]dto.DriverProgressResponse}
+// @Router /admin/incentives/{id}/progress [get]
+func (h *Handler) ListCampaignProgress(c *gin.Context) {
+	var req dto.ListProgressRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+	req.SetDefaults()
+
+	items, total, err := h.service.ListCampaignProgress(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pagination := response.NewPaginationMeta(total, req.Page, req.Limit)
+	response.Paginated(c, items, pagination, "Incentive progress retrieved successfully")
+}
@@ -0,0 +1,382 @@
+package incentives
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/incentives/dto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type driverPosition struct {
+	Lat float64
+	Lon float64
+}
+
+type Repository interface {
+	Create(ctx context.Context, c *models.IncentiveCampaign) error
+	Update(ctx context.Context, c *models.IncentiveCampaign) error
+	FindByID(ctx context.Context, id string) (*models.IncentiveCampaign, error)
+	List(ctx context.Context, req dto.ListCampaignsRequest, now time.Time) ([]*models.IncentiveCampaign, int64, error)
+	ListRunning(ctx context.Context, now time.Time) ([]*models.IncentiveCampaign, error)
+	Stats(ctx context.Context, campaignID string) (*dto.Stats, error)
+
+	// RecordRide counts a ride towards a campaign and, when it earns one,
+	// creates the reward in the same transaction: every ride of a boost up to
+	// the campaign's cap, or the ride that takes a quest to its target. It
+	// returns nil progress when the ride was already counted.
+	RecordRide(ctx context.Context, c *models.IncentiveCampaign, driverID, rideID string, at time.Time) (*models.IncentiveProgress, *models.IncentiveReward, error)
+
+	GetProgress(ctx context.Context, campaignID, driverID string) (*models.IncentiveProgress, error)
+	ProgressFor(ctx context.Context, driverID string, campaignIDs []string) (map[string]*models.IncentiveProgress, error)
+	ListProgress(ctx context.Context, campaignID string, req dto.ListProgressRequest) ([]*models.IncentiveProgress, int64, error)
+	// ListJoined returns the campaigns the driver has counted rides towards,
+	// newest first, whether or not they are still running.
+	ListJoined(ctx context.Context, driverID string, limit int) ([]*models.IncentiveCampaign, error)
+
+	// ClaimReward marks a reward as being paid, so only one worker credits
+	// it. Claims older than staleBefore are taken to have failed midway and
+	// can be claimed again.
+	ClaimReward(ctx context.Context, id string, now, staleBefore time.Time) (bool, error)
+	MarkRewardPaid(ctx context.Context, id, walletTxnID string, now time.Time) error
+	ReleaseReward(ctx context.Context, id string) error
+	ListUnpaidRewards(ctx context.Context, staleBefore time.Time, limit int) ([]*models.IncentiveReward, error)
+	// FindRewardCredit returns the wallet transaction that paid a reward, or
+	// "" when the wallet was never credited.
+	FindRewardCredit(ctx context.Context, rewardID string, transactionTypes []string) (string, error)
+	ListRewardsForDriver(ctx context.Context, driverID string, req dto.ListRewardsRequest) ([]*models.IncentiveReward, int64, error)
+
+	FindCity(ctx context.Context, id string) (*models.City, error)
+	VehicleTypeExists(ctx context.Context, id string) (bool, error)
+	DriverPosition(ctx context.Context, userID string) (*driverPosition, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Create(ctx context.Context, c *models.IncentiveCampaign) error {
+	return r.db.WithContext(ctx).Create(c).Error
+}
+
+func (r *repository) Update(ctx context.Context, c *models.IncentiveCampaign) error {
+	return r.db.WithContext(ctx).Save(c).Error
+}
+
+func (r *repository) FindByID(ctx context.Context, id string) (*models.IncentiveCampaign, error) {
+	var c models.IncentiveCampaign
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&c).Error; err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *repository) List(ctx context.Context, req dto.ListCampaignsRequest, now time.Time) ([]*models.IncentiveCampaign, int64, error) {
+	var items []*models.IncentiveCampaign
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.IncentiveCampaign{})
+	if req.Type != "" {
+		query = query.Where("type = ?", req.Type)
+	}
+	if req.Status != "" {
+		query = query.Where("status = ?", req.Status)
+	}
+	if req.CityID != "" {
+		query = query.Where("city_id = ?", req.CityID)
+	}
+	if req.Running {
+		query = query.Where("status = ? AND starts_at <= ? AND ends_at > ?", models.IncentiveStatusActive, now, now)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count incentive campaigns: %w", err)
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Order("starts_at DESC").Offset(offset).Limit(req.Limit).Find(&items).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch incentive campaigns: %w", err)
+	}
+	return items, total, nil
+}
+
+func (r *repository) ListRunning(ctx context.Context, now time.Time) ([]*models.IncentiveCampaign, error) {
+	var items []*models.IncentiveCampaign
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND starts_at <= ? AND ends_at > ?", models.IncentiveStatusActive, now, now).
+		Order("ends_at").
+		Find(&items).Error
+	return items, err
+}
+
+func (r *repository) Stats(ctx context.Context, campaignID string) (*dto.Stats, error) {
+	var stats dto.Stats
+	err := r.db.WithContext(ctx).
+		Model(&models.IncentiveProgress{}).
+		Select("COUNT(*) AS drivers, COALESCE(SUM(ride_count), 0) AS rides, COUNT(completed_at) AS completed").
+		Where("campaign_id = ?", campaignID).
+		Scan(&stats).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var rewards struct {
+		PaidAmount float64
+		Unpaid     int64
+	}
+	err = r.db.WithContext(ctx).
+		Model(&models.IncentiveReward{}).
+		Select("COALESCE(SUM(amount) FILTER (WHERE status = ?), 0) AS paid_amount, COUNT(*) FILTER (WHERE status <> ?) AS unpaid",
+			models.IncentiveRewardPaid, models.IncentiveRewardPaid).
+		Where("campaign_id = ?", campaignID).
+		Scan(&rewards).Error
+	if err != nil {
+		return nil, err
+	}
+	stats.PaidAmount = rewards.PaidAmount
+	stats.Unpaid = rewards.Unpaid
+	return &stats, nil
+}
+
+func (r *repository) RecordRide(ctx context.Context, c *models.IncentiveCampaign, driverID, rideID string, at time.Time) (*models.IncentiveProgress, *models.IncentiveReward, error) {
+	var progress *models.IncentiveProgress
+	var reward *models.IncentiveReward
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.IncentiveRide{CampaignID: c.ID, RideID: rideID, DriverID: driverID})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.IncentiveProgress{CampaignID: c.ID, DriverID: driverID}).Error
+		if err != nil {
+			return err
+		}
+
+		var p models.IncentiveProgress
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("campaign_id = ? AND driver_id = ?", c.ID, driverID).
+			First(&p).Error; err != nil {
+			return err
+		}
+		p.RideCount++
+		p.LastRideAt = &at
+
+		var earns bool
+		switch c.Type {
+		case models.IncentiveQuest:
+			earns = p.CompletedAt == nil && p.RideCount >= c.TargetRides
+			if earns {
+				p.CompletedAt = &at
+			}
+		case models.IncentiveBoost:
+			earns = c.MaxRewardsPerDriver <= 0 || p.RewardCount < c.MaxRewardsPerDriver
+		}
+
+		if earns {
+			p.RewardCount++
+			p.EarnedAmount += c.RewardAmount
+			reward = &models.IncentiveReward{
+				CampaignID: c.ID,
+				DriverID:   driverID,
+				Amount:     c.RewardAmount,
+				Currency:   c.Currency,
+				Status:     models.IncentiveRewardPending,
+			}
+			if c.Type == models.IncentiveBoost {
+				reward.RideID = &rideID
+			}
+			if err := tx.Create(reward).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Save(&p).Error; err != nil {
+			return err
+		}
+		progress = &p
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return progress, reward, nil
+}
+
+func (r *repository) GetProgress(ctx context.Context, campaignID, driverID string) (*models.IncentiveProgress, error) {
+	var p models.IncentiveProgress
+	err := r.db.WithContext(ctx).Where("campaign_id = ? AND driver_id = ?", campaignID, driverID).First(&p).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *repository) ProgressFor(ctx context.Context, driverID string, campaignIDs []string) (map[string]*models.IncentiveProgress, error) {
+	result := make(map[string]*models.IncentiveProgress, len(campaignIDs))
+	if len(campaignIDs) == 0 {
+		return result, nil
+	}
+
+	var items []*models.IncentiveProgress
+	err := r.db.WithContext(ctx).
+		Where("driver_id = ? AND campaign_id IN ?", driverID, campaignIDs).
+		Find(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range items {
+		result[p.CampaignID] = p
+	}
+	return result, nil
+}
+
+func (r *repository) ListProgress(ctx context.Context, campaignID string, req dto.ListProgressRequest) ([]*models.IncentiveProgress, int64, error) {
+	var items []*models.IncentiveProgress
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.IncentiveProgress{}).Where("campaign_id = ?", campaignID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count incentive progress: %w", err)
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	if err := query.Order("ride_count DESC, last_ride_at").Offset(offset).Limit(req.Limit).Find(&items).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch incentive progress: %w", err)
+	}
+	return items, total, nil
+}
+
+func (r *repository) ListJoined(ctx context.Context, driverID string, limit int) ([]*models.IncentiveCampaign, error) {
+	var items []*models.IncentiveCampaign
+	err := r.db.WithContext(ctx).
+		Joins("JOIN incentive_progress ON incentive_progress.campaign_id = incentive_campaigns.id").
+		Where("incentive_progress.driver_id = ?", driverID).
+		Order("incentive_campaigns.ends_at DESC").
+		Limit(limit).
+		Find(&items).Error
+	return items, err
+}
+
+func (r *repository) ClaimReward(ctx context.Context, id string, now, staleBefore time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.IncentiveReward{}).
+		Where("id = ? AND (status = ? OR (status = ? AND claimed_at < ?))",
+			id, models.IncentiveRewardPending, models.IncentiveRewardProcessing, staleBefore).
+		Updates(map[string]interface{}{
+			"status":     models.IncentiveRewardProcessing,
+			"claimed_at": now,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *repository) MarkRewardPaid(ctx context.Context, id, walletTxnID string, now time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.IncentiveReward{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":        models.IncentiveRewardPaid,
+			"wallet_txn_id": walletTxnID,
+			"paid_at":       now,
+		}).Error
+}
+
+func (r *repository) ReleaseReward(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.IncentiveReward{}).
+		Where("id = ? AND status = ?", id, models.IncentiveRewardProcessing).
+		Updates(map[string]interface{}{
+			"status":     models.IncentiveRewardPending,
+			"claimed_at": nil,
+		}).Error
+}
+
+func (r *repository) ListUnpaidRewards(ctx context.Context, staleBefore time.Time, limit int) ([]*models.IncentiveReward, error) {
+	var items []*models.IncentiveReward
+	err := r.db.WithContext(ctx).
+		Preload("Campaign", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Where("status = ? OR (status = ? AND claimed_at < ?)",
+			models.IncentiveRewardPending, models.IncentiveRewardProcessing, staleBefore).
+		Order("created_at").
+		Limit(limit).
+		Find(&items).Error
+	return items, err
+}
+
+func (r *repository) FindRewardCredit(ctx context.Context, rewardID string, transactionTypes []string) (string, error) {
+	var ids []string
+	err := r.db.WithContext(ctx).
+		Model(&models.WalletTransaction{}).
+		Where("reference_id = ? AND reference_type IN ?", rewardID, transactionTypes).
+		Limit(1).
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return "", err
+	}
+	return ids[0], nil
+}
+
+func (r *repository) ListRewardsForDriver(ctx context.Context, driverID string, req dto.ListRewardsRequest) ([]*models.IncentiveReward, int64, error) {
+	var items []*models.IncentiveReward
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.IncentiveReward{}).Where("driver_id = ?", driverID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count incentive rewards: %w", err)
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	err := query.
+		Preload("Campaign", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(req.Limit).
+		Find(&items).Error
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch incentive rewards: %w", err)
+	}
+	return items, total, nil
+}
+
+func (r *repository) FindCity(ctx context.Context, id string) (*models.City, error) {
+	var city models.City
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&city).Error; err != nil {
+		return nil, err
+	}
+	return &city, nil
+}
+
+func (r *repository) VehicleTypeExists(ctx context.Context, id string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.VehicleType{}).Where("id = ?", id).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *repository) DriverPosition(ctx context.Context, userID string) (*driverPosition, error) {
+	var pos driverPosition
+	err := r.db.WithContext(ctx).
+		Model(&models.DriverProfile{}).
+		Select("ST_Y(current_location) AS lat, ST_X(current_location) AS lon").
+		Where("user_id = ? AND current_location IS NOT NULL", userID).
+		Take(&pos).Error
+	if err != nil {
+		return nil, err
+	}
+	return &pos, nil
+}
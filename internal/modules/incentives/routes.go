@@ -0,0 +1,28 @@
+package incentives
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/middleware"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
+	incentives := router.Group("/incentives")
+	incentives.Use(authMiddleware)
+	incentives.Use(middleware.RequireDriver())
+	{
+		incentives.GET("", handler.ListIncentives)
+		incentives.GET("/rewards", handler.ListRewards)
+		incentives.GET("/:id", handler.GetIncentive)
+	}
+
+	admin := router.Group("/admin/incentives")
+	admin.Use(authMiddleware)
+	admin.Use(middleware.RequireAdmin())
+	{
+		admin.POST("", handler.CreateCampaign)
+		admin.GET("", handler.ListCampaigns)
+		admin.GET("/:id", handler.GetCampaign)
+		admin.PATCH("/:id", handler.UpdateCampaign)
+		admin.GET("/:id/progress", handler.ListCampaignProgress)
+	}
+}
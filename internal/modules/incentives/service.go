@@ -0,0 +1,551 @@
+package incentives
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/incentives/dto"
+	"github.com/umar5678/go-backend/internal/services/eventbus"
+	"github.com/umar5678/go-backend/internal/services/health"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+	websocketutil "github.com/umar5678/go-backend/internal/websocket/websocketutils"
+	"gorm.io/gorm"
+)
+
+const (
+	questTransactionType = "incentive_bonus"
+	boostTransactionType = "incentive_boost"
+
+	// claimTimeout is how long a reward may stay claimed before the sweeper
+	// takes the payment to have failed and tries again.
+	claimTimeout = 10 * time.Minute
+
+	// joinedLimit caps the finished campaigns listed alongside running ones.
+	joinedLimit = 20
+
+	sweepBatchSize = 200
+)
+
+type WalletService interface {
+	CreditDriverWallet(ctx context.Context, userID string, amount float64, transactionType, referenceID, description string, metadata map[string]interface{}) (*models.WalletTransaction, error)
+}
+
+type Service interface {
+	CreateCampaign(ctx context.Context, adminID string, req dto.CreateCampaignRequest) (*dto.AdminCampaignResponse, error)
+	UpdateCampaign(ctx context.Context, id string, req dto.UpdateCampaignRequest) (*dto.AdminCampaignResponse, error)
+	GetCampaign(ctx context.Context, id string) (*dto.AdminCampaignResponse, error)
+	ListCampaigns(ctx context.Context, req dto.ListCampaignsRequest) ([]*dto.AdminCampaignResponse, int64, error)
+	ListCampaignProgress(ctx context.Context, id string, req dto.ListProgressRequest) ([]*dto.DriverProgressResponse, int64, error)
+
+	// ListForDriver returns the campaigns running where the driver is, and
+	// those they have taken part in, with their progress.
+	ListForDriver(ctx context.Context, driverID string) ([]*dto.CampaignResponse, error)
+	GetForDriver(ctx context.Context, driverID, id string) (*dto.CampaignResponse, error)
+	ListRewards(ctx context.Context, driverID string, req dto.ListRewardsRequest) ([]*dto.RewardResponse, int64, error)
+
+	// HandleEvent counts completed rides towards running campaigns and pays
+	// the rewards they earn.
+	HandleEvent(ctx context.Context, event eventbus.Event) error
+
+	// Sweep pays rewards whose wallet credit failed or was interrupted.
+	Sweep(ctx context.Context) error
+}
+
+type service struct {
+	repo          Repository
+	walletService WalletService
+	cfg           config.IncentivesConfig
+}
+
+func NewService(repo Repository, walletService WalletService, cfg config.IncentivesConfig) Service {
+	return &service{repo: repo, walletService: walletService, cfg: cfg}
+}
+
+func (s *service) CreateCampaign(ctx context.Context, adminID string, req dto.CreateCampaignRequest) (*dto.AdminCampaignResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	timezone := req.Timezone
+	if req.CityID != nil {
+		city, err := s.repo.FindCity(ctx, *req.CityID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, response.NotFoundError("City")
+			}
+			return nil, response.InternalServerError("Failed to fetch city", err)
+		}
+		if timezone == "" {
+			timezone = city.Timezone
+		}
+	}
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	if req.VehicleTypeID != nil {
+		exists, err := s.repo.VehicleTypeExists(ctx, *req.VehicleTypeID)
+		if err != nil {
+			return nil, response.InternalServerError("Failed to fetch vehicle type", err)
+		}
+		if !exists {
+			return nil, response.NotFoundError("Vehicle type")
+		}
+	}
+
+	currency := strings.ToUpper(req.Currency)
+	if currency == "" {
+		currency = s.cfg.Currency
+	}
+
+	c := &models.IncentiveCampaign{
+		Name:                req.Name,
+		Description:         req.Description,
+		Type:                models.IncentiveCampaignType(req.Type),
+		Status:              models.IncentiveStatusActive,
+		CityID:              req.CityID,
+		ZoneName:            req.ZoneName,
+		ZoneLat:             req.ZoneLat,
+		ZoneLon:             req.ZoneLon,
+		ZoneRadiusKm:        req.ZoneRadiusKm,
+		VehicleTypeID:       req.VehicleTypeID,
+		StartsAt:            req.StartsAt,
+		EndsAt:              req.EndsAt,
+		DailyStart:          req.DailyStart,
+		DailyEnd:            req.DailyEnd,
+		Timezone:            timezone,
+		RewardAmount:        req.RewardAmount,
+		MaxRewardsPerDriver: req.MaxRewardsPerDriver,
+		Currency:            currency,
+		CreatedBy:           adminID,
+	}
+	if c.Type == models.IncentiveQuest {
+		c.TargetRides = req.TargetRides
+	}
+
+	if err := s.repo.Create(ctx, c); err != nil {
+		return nil, response.InternalServerError("Failed to create incentive campaign", err)
+	}
+
+	logger.Info("incentive campaign created", "campaignID", c.ID, "type", c.Type, "startsAt", c.StartsAt, "endsAt", c.EndsAt, "adminID", adminID)
+	return dto.ToAdminCampaignResponse(c, nil, time.Now()), nil
+}
+
+func (s *service) UpdateCampaign(ctx context.Context, id string, req dto.UpdateCampaignRequest) (*dto.AdminCampaignResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	c, err := s.findCampaign(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if c.Status == models.IncentiveStatusCancelled && (req.Status == nil || *req.Status != string(models.IncentiveStatusCancelled)) {
+		return nil, response.BadRequest("Cancelled campaigns cannot be changed")
+	}
+	if req.EndsAt != nil {
+		if !c.EndsAt.After(now) {
+			return nil, response.BadRequest("Campaign has already ended")
+		}
+		if !req.EndsAt.After(c.StartsAt) || !req.EndsAt.After(now) {
+			return nil, response.BadRequest("endsAt must be after startsAt and in the future")
+		}
+		c.EndsAt = *req.EndsAt
+	}
+	if req.Name != nil {
+		c.Name = *req.Name
+	}
+	if req.Description != nil {
+		c.Description = *req.Description
+	}
+	if req.Status != nil {
+		c.Status = models.IncentiveCampaignStatus(*req.Status)
+	}
+
+	if err := s.repo.Update(ctx, c); err != nil {
+		return nil, response.InternalServerError("Failed to update incentive campaign", err)
+	}
+
+	logger.Info("incentive campaign updated", "campaignID", c.ID, "status", c.Status, "endsAt", c.EndsAt)
+	return s.toAdminResponse(ctx, c, now), nil
+}
+
+func (s *service) GetCampaign(ctx context.Context, id string) (*dto.AdminCampaignResponse, error) {
+	c, err := s.findCampaign(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.toAdminResponse(ctx, c, time.Now()), nil
+}
+
+func (s *service) ListCampaigns(ctx context.Context, req dto.ListCampaignsRequest) ([]*dto.AdminCampaignResponse, int64, error) {
+	req.SetDefaults()
+
+	now := time.Now()
+	items, total, err := s.repo.List(ctx, req, now)
+	if err != nil {
+		return nil, 0, response.InternalServerError("Failed to fetch incentive campaigns", err)
+	}
+
+	result := make([]*dto.AdminCampaignResponse, 0, len(items))
+	for _, c := range items {
+		result = append(result, dto.ToAdminCampaignResponse(c, nil, now))
+	}
+	return result, total, nil
+}
+
+func (s *service) ListCampaignProgress(ctx context.Context, id string, req dto.ListProgressRequest) ([]*dto.DriverProgressResponse, int64, error) {
+	req.SetDefaults()
+
+	if _, err := s.findCampaign(ctx, id); err != nil {
+		return nil, 0, err
+	}
+	items, total, err := s.repo.ListProgress(ctx, id, req)
+	if err != nil {
+		return nil, 0, response.InternalServerError("Failed to fetch incentive progress", err)
+	}
+
+	result := make([]*dto.DriverProgressResponse, 0, len(items))
+	for _, p := range items {
+		result = append(result, dto.ToDriverProgressResponse(p))
+	}
+	return result, total, nil
+}
+
+func (s *service) ListForDriver(ctx context.Context, driverID string) ([]*dto.CampaignResponse, error) {
+	now := time.Now()
+	running, err := s.repo.ListRunning(ctx, now)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to fetch incentives", err)
+	}
+	joined, err := s.repo.ListJoined(ctx, driverID, joinedLimit)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to fetch incentives", err)
+	}
+
+	// City campaigns are listed to drivers in the city, or to every driver
+	// while their position is unknown.
+	pos, err := s.repo.DriverPosition(ctx, driverID)
+	if err != nil {
+		pos = nil
+	}
+	cities := make(map[string]*models.City)
+
+	seen := make(map[string]bool, len(running)+len(joined))
+	campaigns := make([]*models.IncentiveCampaign, 0, len(running)+len(joined))
+	for _, c := range running {
+		if pos != nil && !s.inCity(ctx, c, pos.Lat, pos.Lon, cities) {
+			continue
+		}
+		seen[c.ID] = true
+		campaigns = append(campaigns, c)
+	}
+	for _, c := range joined {
+		if !seen[c.ID] {
+			seen[c.ID] = true
+			campaigns = append(campaigns, c)
+		}
+	}
+
+	ids := make([]string, len(campaigns))
+	for i, c := range campaigns {
+		ids[i] = c.ID
+	}
+	progress, err := s.repo.ProgressFor(ctx, driverID, ids)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to fetch incentive progress", err)
+	}
+
+	result := make([]*dto.CampaignResponse, 0, len(campaigns))
+	for _, c := range campaigns {
+		result = append(result, dto.ToCampaignResponse(c, progress[c.ID]))
+	}
+	return result, nil
+}
+
+func (s *service) GetForDriver(ctx context.Context, driverID, id string) (*dto.CampaignResponse, error) {
+	c, err := s.findCampaign(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	progress, err := s.repo.GetProgress(ctx, id, driverID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to fetch incentive progress", err)
+	}
+	// Drivers only see campaigns that are running or that they took part in.
+	if progress == nil && !c.IsRunning(time.Now()) {
+		return nil, response.NotFoundError("Incentive")
+	}
+	return dto.ToCampaignResponse(c, progress), nil
+}
+
+func (s *service) ListRewards(ctx context.Context, driverID string, req dto.ListRewardsRequest) ([]*dto.RewardResponse, int64, error) {
+	req.SetDefaults()
+
+	items, total, err := s.repo.ListRewardsForDriver(ctx, driverID, req)
+	if err != nil {
+		return nil, 0, response.InternalServerError("Failed to fetch incentive rewards", err)
+	}
+
+	result := make([]*dto.RewardResponse, 0, len(items))
+	for _, r := range items {
+		result = append(result, dto.ToRewardResponse(r))
+	}
+	return result, total, nil
+}
+
+func (s *service) HandleEvent(ctx context.Context, event eventbus.Event) error {
+	if !s.cfg.Enabled || event.Type != eventbus.RideCompleted {
+		return nil
+	}
+
+	var payload eventbus.RideCompletedPayload
+	if err := event.Decode(&payload); err != nil {
+		return fmt.Errorf("decode %s payload: %w", event.Type, err)
+	}
+	if payload.DriverID == "" {
+		return nil
+	}
+	completedAt := time.Now()
+	if payload.CompletedAt != nil {
+		completedAt = *payload.CompletedAt
+	}
+
+	campaigns, err := s.repo.ListRunning(ctx, completedAt)
+	if err != nil {
+		return err
+	}
+
+	// A failed campaign is left for the redelivered event; rides already
+	// counted are skipped then.
+	var errs []error
+	cities := make(map[string]*models.City)
+	for _, c := range campaigns {
+		if !s.qualifies(ctx, c, &payload, completedAt, cities) {
+			continue
+		}
+		if err := s.recordRide(ctx, c, &payload, completedAt); err != nil {
+			logger.Error("failed to record incentive ride", "error", err, "campaignID", c.ID, "rideID", payload.RideID)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// qualifies reports whether a completed ride counts towards the campaign.
+func (s *service) qualifies(ctx context.Context, c *models.IncentiveCampaign, ride *eventbus.RideCompletedPayload, at time.Time, cities map[string]*models.City) bool {
+	if c.VehicleTypeID != nil && *c.VehicleTypeID != ride.VehicleTypeID {
+		return false
+	}
+	return c.InDailyHours(at) &&
+		c.InZone(ride.PickupLat, ride.PickupLon) &&
+		s.inCity(ctx, c, ride.PickupLat, ride.PickupLon, cities)
+}
+
+// inCity reports whether a location is in the campaign's city, caching the
+// cities it loads.
+func (s *service) inCity(ctx context.Context, c *models.IncentiveCampaign, lat, lon float64, cities map[string]*models.City) bool {
+	if c.CityID == nil {
+		return true
+	}
+	city, ok := cities[*c.CityID]
+	if !ok {
+		var err error
+		city, err = s.repo.FindCity(ctx, *c.CityID)
+		if err != nil {
+			logger.Error("failed to load city for incentive campaign", "error", err, "campaignID", c.ID, "cityID", *c.CityID)
+			city = nil
+		}
+		cities[*c.CityID] = city
+	}
+	return city != nil && city.Contains(lat, lon)
+}
+
+func (s *service) recordRide(ctx context.Context, c *models.IncentiveCampaign, ride *eventbus.RideCompletedPayload, at time.Time) error {
+	progress, reward, err := s.repo.RecordRide(ctx, c, ride.DriverID, ride.RideID, at)
+	if err != nil || progress == nil {
+		return err
+	}
+
+	if err := websocketutil.SendToUser(ride.DriverID, websocket.TypeIncentiveProgress, map[string]interface{}{
+		"campaignId": c.ID,
+		"name":       c.Name,
+		"type":       c.Type,
+		"rideId":     ride.RideID,
+		"progress":   dto.ToProgress(c, progress),
+	}); err != nil {
+		logger.Warn("failed to notify incentive progress", "error", err, "driverID", ride.DriverID)
+	}
+
+	if reward == nil {
+		return nil
+	}
+	logger.Info("incentive reward earned", "campaignID", c.ID, "driverID", ride.DriverID, "rewardID", reward.ID, "amount", reward.Amount)
+
+	// Payment failures are retried by the sweeper, not by redelivering the
+	// event, which would find the ride already counted.
+	if err := s.pay(ctx, c, reward); err != nil {
+		logger.Error("failed to pay incentive reward", "error", err, "rewardID", reward.ID, "driverID", reward.DriverID)
+	}
+	return nil
+}
+
+// pay credits a reward to the driver's wallet once. A claim that went stale
+// may have credited the wallet before failing, so the wallet is checked
+// first.
+func (s *service) pay(ctx context.Context, c *models.IncentiveCampaign, reward *models.IncentiveReward) error {
+	now := time.Now()
+	claimed, err := s.repo.ClaimReward(ctx, reward.ID, now, now.Add(-claimTimeout))
+	if err != nil || !claimed {
+		return err
+	}
+
+	txnID, err := s.repo.FindRewardCredit(ctx, reward.ID, []string{questTransactionType, boostTransactionType})
+	if err != nil {
+		s.release(ctx, reward)
+		return err
+	}
+	if txnID != "" {
+		logger.Warn("incentive reward already credited", "rewardID", reward.ID, "walletTxnID", txnID)
+		return s.repo.MarkRewardPaid(ctx, reward.ID, txnID, now)
+	}
+
+	transactionType := questTransactionType
+	description := fmt.Sprintf("Bonus for completing %s", c.Name)
+	if c.Type == models.IncentiveBoost {
+		transactionType = boostTransactionType
+		description = fmt.Sprintf("%s ride boost", c.Name)
+	}
+	metadata := map[string]interface{}{
+		"campaignId": c.ID,
+		"rewardId":   reward.ID,
+	}
+	if reward.RideID != nil {
+		metadata["rideId"] = *reward.RideID
+	}
+
+	txn, err := s.walletService.CreditDriverWallet(ctx, reward.DriverID, reward.Amount, transactionType, reward.ID, description, metadata)
+	if err != nil {
+		s.release(ctx, reward)
+		return err
+	}
+	if err := s.repo.MarkRewardPaid(ctx, reward.ID, txn.ID, time.Now()); err != nil {
+		// The claim goes stale and the sweeper finds the credit.
+		return err
+	}
+
+	logger.Info("incentive reward paid", "rewardID", reward.ID, "driverID", reward.DriverID, "amount", reward.Amount, "walletTxnID", txn.ID)
+
+	if err := websocketutil.SendToUser(reward.DriverID, websocket.TypeIncentiveRewarded, map[string]interface{}{
+		"campaignId": c.ID,
+		"name":       c.Name,
+		"type":       c.Type,
+		"rewardId":   reward.ID,
+		"rideId":     reward.RideID,
+		"amount":     reward.Amount,
+		"currency":   reward.Currency,
+	}); err != nil {
+		logger.Warn("failed to notify incentive reward", "error", err, "driverID", reward.DriverID)
+	}
+	return nil
+}
+
+// release hands a reward whose payment failed back to the sweeper.
+func (s *service) release(ctx context.Context, reward *models.IncentiveReward) {
+	if err := s.repo.ReleaseReward(ctx, reward.ID); err != nil {
+		logger.Error("failed to release incentive reward", "error", err, "rewardID", reward.ID)
+	}
+}
+
+func (s *service) Sweep(ctx context.Context) error {
+	if !s.cfg.Enabled {
+		return nil
+	}
+
+	rewards, err := s.repo.ListUnpaidRewards(ctx, time.Now().Add(-claimTimeout), sweepBatchSize)
+	if err != nil {
+		return err
+	}
+	paid := 0
+	for _, reward := range rewards {
+		if reward.Campaign == nil {
+			continue
+		}
+		if err := s.pay(ctx, reward.Campaign, reward); err != nil {
+			logger.Error("failed to retry incentive reward", "error", err, "rewardID", reward.ID, "driverID", reward.DriverID)
+			continue
+		}
+		paid++
+	}
+
+	if len(rewards) > 0 {
+		logger.Info("incentive sweep completed", "unpaid", len(rewards), "retried", paid)
+	}
+	return nil
+}
+
+func (s *service) findCampaign(ctx context.Context, id string) (*models.IncentiveCampaign, error) {
+	c, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Incentive campaign")
+		}
+		return nil, response.InternalServerError("Failed to fetch incentive campaign", err)
+	}
+	return c, nil
+}
+
+func (s *service) toAdminResponse(ctx context.Context, c *models.IncentiveCampaign, now time.Time) *dto.AdminCampaignResponse {
+	stats, err := s.repo.Stats(ctx, c.ID)
+	if err != nil {
+		logger.Error("failed to fetch incentive campaign stats", "error", err, "campaignID", c.ID)
+	}
+	return dto.ToAdminCampaignResponse(c, stats, now)
+}
+
+// Sweeper retries unpaid incentive rewards on a fixed interval.
+type Sweeper struct {
+	service  Service
+	interval time.Duration
+}
+
+func NewSweeper(service Service, cfg config.IncentivesConfig) *Sweeper {
+	interval := cfg.SweepInterval
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	return &Sweeper{service: service, interval: interval}
+}
+
+func (w *Sweeper) Start(ctx context.Context) {
+	heartbeat := health.NewHeartbeat("incentive_sweeper", w.interval)
+
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				runCtx, cancel := context.WithTimeout(ctx, time.Minute)
+				if err := w.service.Sweep(runCtx); err != nil {
+					logger.Error("incentive sweep failed", "error", err)
+				}
+				cancel()
+				heartbeat.Beat()
+			}
+		}
+	}()
+
+	logger.Info("incentive sweeper started", "interval", w.interval)
+}
@@ -0,0 +1,111 @@
+package dto
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// FileClaimRequest opens a claim against the trip policy.
+// Claim types: accident, injury, property_damage, vehicle_damage, theft, other.
+type FileClaimRequest struct {
+	ClaimType     string  `json:"claimType" binding:"required,oneof=accident injury property_damage vehicle_damage theft other"`
+	Description   string  `json:"description" binding:"required,min=10,max=2000"`
+	IncidentAt    string  `json:"incidentAt" binding:"omitempty"`
+	AmountClaimed float64 `json:"amountClaimed" binding:"omitempty,min=0"`
+}
+
+func (r *FileClaimRequest) Validate() error {
+	if strings.TrimSpace(r.Description) == "" {
+		return errors.New("description is required")
+	}
+	if r.IncidentAt != "" {
+		if _, err := time.Parse(time.RFC3339, r.IncidentAt); err != nil {
+			return errors.New("incidentAt must be RFC3339")
+		}
+	}
+	return nil
+}
+
+// ReviewClaimRequest moves a claim through review. Allowed transitions:
+// submitted -> under_review/approved/rejected, under_review -> approved/rejected,
+// approved -> paid.
+type ReviewClaimRequest struct {
+	Status         string   `json:"status" binding:"required,oneof=under_review approved rejected paid"`
+	Notes          string   `json:"notes" binding:"omitempty,max=2000"`
+	AmountApproved *float64 `json:"amountApproved" binding:"omitempty,min=0"`
+}
+
+func (r *ReviewClaimRequest) Validate() error {
+	if r.Status == "approved" && r.AmountApproved == nil {
+		return errors.New("amountApproved is required when approving a claim")
+	}
+	if r.Status == "rejected" && strings.TrimSpace(r.Notes) == "" {
+		return errors.New("notes are required when rejecting a claim")
+	}
+	return nil
+}
+
+type ListClaimsRequest struct {
+	Status string `form:"status" binding:"omitempty,oneof=submitted under_review approved rejected paid"`
+	Page   int    `form:"page" binding:"omitempty,min=1"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (r *ListClaimsRequest) SetDefaults() {
+	if r.Page <= 0 {
+		r.Page = 1
+	}
+	if r.Limit <= 0 {
+		r.Limit = 20
+	}
+	if r.Limit > 100 {
+		r.Limit = 100
+	}
+}
+
+type CoverageReportRequest struct {
+	FromDate string `form:"fromDate" binding:"omitempty"`
+	ToDate   string `form:"toDate" binding:"omitempty"`
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	Limit    int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// Range resolves the report window, defaulting to the last 30 days. ToDate is
+// inclusive.
+func (r *CoverageReportRequest) Range() (time.Time, time.Time, error) {
+	to := time.Now()
+	if r.ToDate != "" {
+		parsed, err := time.Parse("2006-01-02", r.ToDate)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("invalid toDate format, expected YYYY-MM-DD")
+		}
+		to = parsed.AddDate(0, 0, 1)
+	}
+
+	from := to.AddDate(0, 0, -30)
+	if r.FromDate != "" {
+		parsed, err := time.Parse("2006-01-02", r.FromDate)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("invalid fromDate format, expected YYYY-MM-DD")
+		}
+		from = parsed
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, errors.New("fromDate must be before toDate")
+	}
+	return from, to, nil
+}
+
+func (r *CoverageReportRequest) SetDefaults() {
+	if r.Page <= 0 {
+		r.Page = 1
+	}
+	if r.Limit <= 0 {
+		r.Limit = 20
+	}
+	if r.Limit > 100 {
+		r.Limit = 100
+	}
+}
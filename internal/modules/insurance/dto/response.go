@@ -0,0 +1,126 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+type PolicyResponse struct {
+	ID                string     `json:"id"`
+	RideID            string     `json:"rideId"`
+	PolicyNumber      string     `json:"policyNumber"`
+	Provider          string     `json:"provider"`
+	ProviderReference string     `json:"providerReference,omitempty"`
+	CoverageAmount    float64    `json:"coverageAmount"`
+	Premium           float64    `json:"premium"`
+	Currency          string     `json:"currency"`
+	Status            string     `json:"status"`
+	CoverageStartAt   time.Time  `json:"coverageStartAt"`
+	CoverageEndAt     *time.Time `json:"coverageEndAt,omitempty"`
+}
+
+type ClaimResponse struct {
+	ID             string          `json:"id"`
+	ClaimNumber    string          `json:"claimNumber"`
+	RideID         string          `json:"rideId"`
+	ClaimantID     string          `json:"claimantId"`
+	ClaimantRole   string          `json:"claimantRole"`
+	ClaimType      string          `json:"claimType"`
+	Description    string          `json:"description"`
+	IncidentAt     time.Time       `json:"incidentAt"`
+	AmountClaimed  float64         `json:"amountClaimed"`
+	AmountApproved *float64        `json:"amountApproved,omitempty"`
+	Status         string          `json:"status"`
+	ReviewNotes    string          `json:"reviewNotes,omitempty"`
+	ReviewedAt     *time.Time      `json:"reviewedAt,omitempty"`
+	Policy         *PolicyResponse `json:"policy,omitempty"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	UpdatedAt      time.Time       `json:"updatedAt"`
+}
+
+type ProviderCoverageResponse struct {
+	Provider     string  `json:"provider"`
+	Policies     int64   `json:"policies"`
+	TotalPremium float64 `json:"totalPremium"`
+}
+
+type ClaimStatusSummary struct {
+	Status         string  `json:"status"`
+	Count          int64   `json:"count"`
+	AmountClaimed  float64 `json:"amountClaimed"`
+	AmountApproved float64 `json:"amountApproved"`
+}
+
+type CoverageReportResponse struct {
+	FromDate       string                     `json:"fromDate"`
+	ToDate         string                     `json:"toDate"`
+	TotalTrips     int64                      `json:"totalTrips"`
+	CoveredTrips   int64                      `json:"coveredTrips"`
+	UncoveredTrips int64                      `json:"uncoveredTrips"`
+	CoverageRate   float64                    `json:"coverageRate"`
+	TotalPremium   float64                    `json:"totalPremium"`
+	TotalCoverage  float64                    `json:"totalCoverage"`
+	ByProvider     []ProviderCoverageResponse `json:"byProvider"`
+	Claims         []ClaimStatusSummary       `json:"claims"`
+}
+
+type UncoveredRideResponse struct {
+	RideID      string     `json:"rideId"`
+	RiderID     string     `json:"riderId"`
+	DriverID    *string    `json:"driverId,omitempty"`
+	Status      string     `json:"status"`
+	StartedAt   *time.Time `json:"startedAt,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+func ToPolicyResponse(p *models.RideInsurancePolicy) *PolicyResponse {
+	if p == nil {
+		return nil
+	}
+	return &PolicyResponse{
+		ID:                p.ID,
+		RideID:            p.RideID,
+		PolicyNumber:      p.PolicyNumber,
+		Provider:          p.Provider,
+		ProviderReference: p.ProviderReference,
+		CoverageAmount:    p.CoverageAmount,
+		Premium:           p.Premium,
+		Currency:          p.Currency,
+		Status:            string(p.Status),
+		CoverageStartAt:   p.CoverageStartAt,
+		CoverageEndAt:     p.CoverageEndAt,
+	}
+}
+
+func ToClaimResponse(c *models.InsuranceClaim) *ClaimResponse {
+	return &ClaimResponse{
+		ID:             c.ID,
+		ClaimNumber:    c.ClaimNumber,
+		RideID:         c.RideID,
+		ClaimantID:     c.ClaimantID,
+		ClaimantRole:   c.ClaimantRole,
+		ClaimType:      c.ClaimType,
+		Description:    c.Description,
+		IncidentAt:     c.IncidentAt,
+		AmountClaimed:  c.AmountClaimed,
+		AmountApproved: c.AmountApproved,
+		Status:         string(c.Status),
+		ReviewNotes:    c.ReviewNotes,
+		ReviewedAt:     c.ReviewedAt,
+		Policy:         ToPolicyResponse(c.Policy),
+		CreatedAt:      c.CreatedAt,
+		UpdatedAt:      c.UpdatedAt,
+	}
+}
+
+func ToUncoveredRideResponse(r *models.Ride) UncoveredRideResponse {
+	return UncoveredRideResponse{
+		RideID:      r.ID,
+		RiderID:     r.RiderID,
+		DriverID:    r.DriverID,
+		Status:      r.Status,
+		StartedAt:   r.StartedAt,
+		CompletedAt: r.CompletedAt,
+	}
+}
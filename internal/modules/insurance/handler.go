@@ -0,0 +1,223 @@
+package insurance
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/modules/insurance/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// GetRidePolicy godoc
+// @Summary Get the insurance policy attached to a trip
+// @Tags insurance
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Ride ID"
+// @Success 200 {object} response.Response{data=dto.PolicyResponse}
+// @Router /rides/{id}/insurance [get]
+func (h *Handler) GetRidePolicy(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+
+	policy, err := h.service.GetRidePolicy(c.Request.Context(), userID.(string), c.Param("id"), role == "admin")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, policy, "Insurance policy retrieved successfully")
+}
+
+// FileClaim godoc
+// @Summary File an insurance claim for a trip
+// @Description The rider or driver of the trip can file a claim within the claim window
+// @Tags insurance
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Ride ID"
+// @Param request body dto.FileClaimRequest true "Claim details"
+// @Success 200 {object} response.Response{data=dto.ClaimResponse}
+// @Router /rides/{id}/insurance/claims [post]
+func (h *Handler) FileClaim(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req dto.FileClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	claim, err := h.service.FileClaim(c.Request.Context(), userID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, claim, "Insurance claim filed successfully")
+}
+
+// ListClaims godoc
+// @Summary List insurance claims
+// @Description Riders and drivers see their own claims, admins see all claims
+// @Tags insurance
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "Filter by status"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Response{data=[]dto.ClaimResponse}
+// @Router /insurance/claims [get]
+func (h *Handler) ListClaims(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+
+	filterUserID := ""
+	if role != "admin" {
+		filterUserID = userID.(string)
+	}
+
+	var req dto.ListClaimsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+	req.SetDefaults()
+
+	claims, total, err := h.service.ListClaims(c.Request.Context(), filterUserID, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pagination := response.NewPaginationMeta(total, req.Page, req.Limit)
+	response.Paginated(c, claims, pagination, "Insurance claims retrieved successfully")
+}
+
+// GetClaim godoc
+// @Summary Get insurance claim
+// @Tags insurance
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Claim ID"
+// @Success 200 {object} response.Response{data=dto.ClaimResponse}
+// @Router /insurance/claims/{id} [get]
+func (h *Handler) GetClaim(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+
+	claim, err := h.service.GetClaim(c.Request.Context(), userID.(string), c.Param("id"), role == "admin")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, claim, "Insurance claim retrieved successfully")
+}
+
+// ReviewClaim godoc
+// @Summary Review an insurance claim (admin)
+// @Tags insurance
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Claim ID"
+// @Param request body dto.ReviewClaimRequest true "Review decision"
+// @Success 200 {object} response.Response{data=dto.ClaimResponse}
+// @Router /insurance/claims/{id}/review [post]
+func (h *Handler) ReviewClaim(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	var req dto.ReviewClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	claim, err := h.service.ReviewClaim(c.Request.Context(), adminID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, claim, "Insurance claim updated successfully")
+}
+
+// AttachPolicy godoc
+// @Summary Issue insurance for a trip that is missing cover (admin)
+// @Tags insurance
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Ride ID"
+// @Success 200 {object} response.Response{data=dto.PolicyResponse}
+// @Router /insurance/rides/{id}/attach [post]
+func (h *Handler) AttachPolicy(c *gin.Context) {
+	policy, err := h.service.AttachPolicyForRide(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, policy, "Insurance policy attached successfully")
+}
+
+// GetCoverageReport godoc
+// @Summary Insurance coverage report (admin)
+// @Tags insurance
+// @Security BearerAuth
+// @Produce json
+// @Param fromDate query string false "From date (YYYY-MM-DD)"
+// @Param toDate query string false "To date (YYYY-MM-DD)"
+// @Success 200 {object} response.Response{data=dto.CoverageReportResponse}
+// @Router /insurance/report [get]
+func (h *Handler) GetCoverageReport(c *gin.Context) {
+	var req dto.CoverageReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+
+	report, err := h.service.GetCoverageReport(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, report, "Coverage report retrieved successfully")
+}
+
+// ListUncoveredRides godoc
+// @Summary List started trips without an insurance policy (admin)
+// @Tags insurance
+// @Security BearerAuth
+// @Produce json
+// @Param fromDate query string false "From date (YYYY-MM-DD)"
+// @Param toDate query string false "To date (YYYY-MM-DD)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Response{data=[]dto.UncoveredRideResponse}
+// @Router /insurance/uncovered-rides [get]
+func (h *Handler) ListUncoveredRides(c *gin.Context) {
+	var req dto.CoverageReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+	req.SetDefaults()
+
+	rides, total, err := h.service.ListUncoveredRides(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pagination := response.NewPaginationMeta(total, req.Page, req.Limit)
+	response.Paginated(c, rides, pagination, "Uncovered rides retrieved successfully")
+}
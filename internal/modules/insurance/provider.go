@@ -0,0 +1,114 @@
+package insurance
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+)
+
+type PolicyRequest struct {
+	RideID         string    `json:"rideId"`
+	RiderID        string    `json:"riderId"`
+	DriverID       string    `json:"driverId"`
+	CoverageAmount float64   `json:"coverageAmount"`
+	Currency       string    `json:"currency"`
+	StartAt        time.Time `json:"startAt"`
+}
+
+type PolicyResult struct {
+	PolicyNumber string  `json:"policyNumber"`
+	Reference    string  `json:"reference"`
+	Premium      float64 `json:"premium"`
+}
+
+// Provider issues trip policies. The internal provider numbers policies under
+// the platform's master policy; an HTTP provider delegates to an insurer API.
+type Provider interface {
+	Name() string
+	IssuePolicy(ctx context.Context, req PolicyRequest) (*PolicyResult, error)
+}
+
+func NewProvider(cfg config.InsuranceConfig) Provider {
+	if cfg.ProviderURL != "" {
+		return &httpProvider{
+			name:    cfg.ProviderName,
+			baseURL: strings.TrimRight(cfg.ProviderURL, "/"),
+			apiKey:  cfg.ProviderAPIKey,
+			client:  &http.Client{Timeout: 10 * time.Second},
+		}
+	}
+	return &internalProvider{prefix: cfg.PolicyPrefix, premium: cfg.PremiumPerTrip}
+}
+
+type internalProvider struct {
+	prefix  string
+	premium float64
+}
+
+func (p *internalProvider) Name() string {
+	return "internal"
+}
+
+func (p *internalProvider) IssuePolicy(ctx context.Context, req PolicyRequest) (*PolicyResult, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	return &PolicyResult{
+		PolicyNumber: fmt.Sprintf("%s-%s-%s", p.prefix, req.StartAt.Format("20060102"), strings.ToUpper(hex.EncodeToString(b))),
+		Premium:      p.premium,
+	}, nil
+}
+
+type httpProvider struct {
+	name    string
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+func (p *httpProvider) Name() string {
+	return p.name
+}
+
+func (p *httpProvider) IssuePolicy(ctx context.Context, req PolicyRequest) (*PolicyResult, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/policies", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("insurance provider returned status %d", resp.StatusCode)
+	}
+
+	var result PolicyResult
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode insurance provider response: %w", err)
+	}
+	if result.PolicyNumber == "" {
+		return nil, fmt.Errorf("insurance provider returned no policy number")
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,226 @@
+package insurance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	FindRideByID(ctx context.Context, rideID string) (*models.Ride, error)
+
+	CreatePolicy(ctx context.Context, policy *models.RideInsurancePolicy) error
+	FindPolicyByRideID(ctx context.Context, rideID string) (*models.RideInsurancePolicy, error)
+	ClosePolicy(ctx context.Context, rideID string, endedAt time.Time) error
+
+	CreateClaim(ctx context.Context, claim *models.InsuranceClaim) error
+	FindClaimByID(ctx context.Context, id string) (*models.InsuranceClaim, error)
+	FindOpenClaim(ctx context.Context, rideID, claimantID string) (*models.InsuranceClaim, error)
+	UpdateClaim(ctx context.Context, claim *models.InsuranceClaim) error
+	ListClaims(ctx context.Context, claimantID, status string, page, limit int) ([]*models.InsuranceClaim, int64, error)
+
+	GetCoverageStats(ctx context.Context, from, to time.Time) (*CoverageStats, error)
+	GetCoverageByProvider(ctx context.Context, from, to time.Time) ([]ProviderCoverage, error)
+	GetClaimStats(ctx context.Context, from, to time.Time) ([]ClaimStatusStats, error)
+	ListUncoveredRides(ctx context.Context, from, to time.Time, page, limit int) ([]*models.Ride, int64, error)
+}
+
+type CoverageStats struct {
+	TotalTrips    int64
+	CoveredTrips  int64
+	TotalPremium  float64
+	TotalCoverage float64
+}
+
+type ProviderCoverage struct {
+	Provider     string
+	Policies     int64
+	TotalPremium float64
+}
+
+type ClaimStatusStats struct {
+	Status         string
+	Count          int64
+	AmountClaimed  float64
+	AmountApproved float64
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) FindRideByID(ctx context.Context, rideID string) (*models.Ride, error) {
+	var ride models.Ride
+	err := r.db.WithContext(ctx).Where("id = ?", rideID).First(&ride).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ride, nil
+}
+
+func (r *repository) CreatePolicy(ctx context.Context, policy *models.RideInsurancePolicy) error {
+	return r.db.WithContext(ctx).Create(policy).Error
+}
+
+func (r *repository) FindPolicyByRideID(ctx context.Context, rideID string) (*models.RideInsurancePolicy, error) {
+	var policy models.RideInsurancePolicy
+	err := r.db.WithContext(ctx).Where("ride_id = ?", rideID).First(&policy).Error
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (r *repository) ClosePolicy(ctx context.Context, rideID string, endedAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.RideInsurancePolicy{}).
+		Where("ride_id = ? AND status = ?", rideID, models.InsurancePolicyActive).
+		Updates(map[string]interface{}{
+			"status":          models.InsurancePolicyClosed,
+			"coverage_end_at": endedAt,
+		}).Error
+}
+
+func (r *repository) CreateClaim(ctx context.Context, claim *models.InsuranceClaim) error {
+	return r.db.WithContext(ctx).Create(claim).Error
+}
+
+func (r *repository) FindClaimByID(ctx context.Context, id string) (*models.InsuranceClaim, error) {
+	var claim models.InsuranceClaim
+	err := r.db.WithContext(ctx).Preload("Policy").Where("id = ?", id).First(&claim).Error
+	if err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
+
+func (r *repository) FindOpenClaim(ctx context.Context, rideID, claimantID string) (*models.InsuranceClaim, error) {
+	var claim models.InsuranceClaim
+	err := r.db.WithContext(ctx).
+		Where("ride_id = ? AND claimant_id = ? AND status IN ?", rideID, claimantID, []models.InsuranceClaimStatus{
+			models.InsuranceClaimSubmitted,
+			models.InsuranceClaimUnderReview,
+		}).
+		First(&claim).Error
+	if err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
+
+func (r *repository) UpdateClaim(ctx context.Context, claim *models.InsuranceClaim) error {
+	return r.db.WithContext(ctx).Omit("Policy").Save(claim).Error
+}
+
+func (r *repository) ListClaims(ctx context.Context, claimantID, status string, page, limit int) ([]*models.InsuranceClaim, int64, error) {
+	var claims []*models.InsuranceClaim
+	var total int64
+
+	base := r.db.WithContext(ctx).Model(&models.InsuranceClaim{})
+	if claimantID != "" {
+		base = base.Where("claimant_id = ?", claimantID)
+	}
+	if status != "" {
+		base = base.Where("status = ?", status)
+	}
+
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count insurance claims: %w", err)
+	}
+
+	if total == 0 {
+		return []*models.InsuranceClaim{}, 0, nil
+	}
+
+	offset := (page - 1) * limit
+	if err := base.
+		Preload("Policy").
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&claims).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch insurance claims: %w", err)
+	}
+
+	return claims, total, nil
+}
+
+// Trips count as covered once they started with a non-voided policy.
+func (r *repository) GetCoverageStats(ctx context.Context, from, to time.Time) (*CoverageStats, error) {
+	var stats CoverageStats
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT
+			COUNT(r.id) AS total_trips,
+			COUNT(p.id) AS covered_trips,
+			COALESCE(SUM(p.premium), 0) AS total_premium,
+			COALESCE(SUM(p.coverage_amount), 0) AS total_coverage
+		FROM rides r
+		LEFT JOIN ride_insurance_policies p ON p.ride_id = r.id AND p.status <> ?
+		WHERE r.started_at IS NOT NULL
+		AND r.started_at >= ? AND r.started_at < ?
+		AND r.deleted_at IS NULL
+	`, models.InsurancePolicyVoided, from, to).Scan(&stats).Error
+	return &stats, err
+}
+
+func (r *repository) GetCoverageByProvider(ctx context.Context, from, to time.Time) ([]ProviderCoverage, error) {
+	var rows []ProviderCoverage
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT p.provider, COUNT(*) AS policies, COALESCE(SUM(p.premium), 0) AS total_premium
+		FROM ride_insurance_policies p
+		WHERE p.status <> ?
+		AND p.coverage_start_at >= ? AND p.coverage_start_at < ?
+		GROUP BY p.provider
+		ORDER BY policies DESC
+	`, models.InsurancePolicyVoided, from, to).Scan(&rows).Error
+	return rows, err
+}
+
+func (r *repository) GetClaimStats(ctx context.Context, from, to time.Time) ([]ClaimStatusStats, error) {
+	var rows []ClaimStatusStats
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT status, COUNT(*) AS count,
+			COALESCE(SUM(amount_claimed), 0) AS amount_claimed,
+			COALESCE(SUM(amount_approved), 0) AS amount_approved
+		FROM insurance_claims
+		WHERE created_at >= ? AND created_at < ?
+		GROUP BY status
+	`, from, to).Scan(&rows).Error
+	return rows, err
+}
+
+func (r *repository) ListUncoveredRides(ctx context.Context, from, to time.Time, page, limit int) ([]*models.Ride, int64, error) {
+	var rides []*models.Ride
+	var total int64
+
+	base := r.db.WithContext(ctx).
+		Model(&models.Ride{}).
+		Where("rides.started_at IS NOT NULL AND rides.started_at >= ? AND rides.started_at < ?", from, to).
+		Where("NOT EXISTS (SELECT 1 FROM ride_insurance_policies p WHERE p.ride_id = rides.id AND p.status <> ?)", models.InsurancePolicyVoided)
+
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count uncovered rides: %w", err)
+	}
+
+	if total == 0 {
+		return []*models.Ride{}, 0, nil
+	}
+
+	offset := (page - 1) * limit
+	if err := base.
+		Order("rides.started_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&rides).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch uncovered rides: %w", err)
+	}
+
+	return rides, total, nil
+}
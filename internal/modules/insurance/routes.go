@@ -0,0 +1,27 @@
+package insurance
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/middleware"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
+	rides := router.Group("/rides")
+	rides.Use(authMiddleware)
+	{
+		rides.GET("/:id/insurance", handler.GetRidePolicy)
+		rides.POST("/:id/insurance/claims", handler.FileClaim)
+	}
+
+	insurance := router.Group("/insurance")
+	insurance.Use(authMiddleware)
+	{
+		insurance.GET("/claims", handler.ListClaims)
+		insurance.GET("/claims/:id", handler.GetClaim)
+
+		insurance.POST("/claims/:id/review", middleware.RequireAdmin(), handler.ReviewClaim)
+		insurance.POST("/rides/:id/attach", middleware.RequireAdmin(), handler.AttachPolicy)
+		insurance.GET("/report", middleware.RequireAdmin(), handler.GetCoverageReport)
+		insurance.GET("/uncovered-rides", middleware.RequireAdmin(), handler.ListUncoveredRides)
+	}
+}
@@ -0,0 +1,467 @@
+package insurance
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/insurance/dto"
+	pricingdto "github.com/umar5678/go-backend/internal/modules/pricing/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+	websocketutil "github.com/umar5678/go-backend/internal/websocket/websocketutils"
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	AttachPolicy(ctx context.Context, ride *models.Ride) (*models.RideInsurancePolicy, error)
+	EndCoverage(ctx context.Context, rideID string, endedAt time.Time) error
+	PolicyReference(ctx context.Context, rideID string) (*pricingdto.InsuranceReference, error)
+
+	GetRidePolicy(ctx context.Context, userID, rideID string, isAdmin bool) (*dto.PolicyResponse, error)
+	FileClaim(ctx context.Context, userID, rideID string, req dto.FileClaimRequest) (*dto.ClaimResponse, error)
+	GetClaim(ctx context.Context, userID, claimID string, isAdmin bool) (*dto.ClaimResponse, error)
+	ListClaims(ctx context.Context, claimantID string, req dto.ListClaimsRequest) ([]*dto.ClaimResponse, int64, error)
+
+	ReviewClaim(ctx context.Context, adminID, claimID string, req dto.ReviewClaimRequest) (*dto.ClaimResponse, error)
+	AttachPolicyForRide(ctx context.Context, rideID string) (*dto.PolicyResponse, error)
+	GetCoverageReport(ctx context.Context, req dto.CoverageReportRequest) (*dto.CoverageReportResponse, error)
+	ListUncoveredRides(ctx context.Context, req dto.CoverageReportRequest) ([]dto.UncoveredRideResponse, int64, error)
+}
+
+var claimTransitions = map[models.InsuranceClaimStatus][]models.InsuranceClaimStatus{
+	models.InsuranceClaimSubmitted:   {models.InsuranceClaimUnderReview, models.InsuranceClaimApproved, models.InsuranceClaimRejected},
+	models.InsuranceClaimUnderReview: {models.InsuranceClaimApproved, models.InsuranceClaimRejected},
+	models.InsuranceClaimApproved:    {models.InsuranceClaimPaid},
+}
+
+type service struct {
+	repo     Repository
+	provider Provider
+	cfg      config.InsuranceConfig
+}
+
+func NewService(repo Repository, provider Provider, cfg config.InsuranceConfig) Service {
+	return &service{
+		repo:     repo,
+		provider: provider,
+		cfg:      cfg,
+	}
+}
+
+// AttachPolicy issues cover for a started ride. It is idempotent so the ride
+// flow and an admin retry can both call it safely.
+func (s *service) AttachPolicy(ctx context.Context, ride *models.Ride) (*models.RideInsurancePolicy, error) {
+	if existing, err := s.repo.FindPolicyByRideID(ctx, ride.ID); err == nil {
+		return existing, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if ride.DriverID == nil {
+		return nil, fmt.Errorf("ride %s has no driver", ride.ID)
+	}
+
+	startAt := time.Now()
+	if ride.StartedAt != nil {
+		startAt = *ride.StartedAt
+	}
+
+	result, err := s.provider.IssuePolicy(ctx, PolicyRequest{
+		RideID:         ride.ID,
+		RiderID:        ride.RiderID,
+		DriverID:       *ride.DriverID,
+		CoverageAmount: s.cfg.CoverageAmount,
+		Currency:       s.cfg.Currency,
+		StartAt:        startAt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("insurance provider %s: %w", s.provider.Name(), err)
+	}
+
+	policy := &models.RideInsurancePolicy{
+		RideID:            ride.ID,
+		RiderID:           ride.RiderID,
+		DriverID:          *ride.DriverID,
+		Provider:          s.provider.Name(),
+		PolicyNumber:      result.PolicyNumber,
+		ProviderReference: result.Reference,
+		CoverageAmount:    s.cfg.CoverageAmount,
+		Premium:           result.Premium,
+		Currency:          s.cfg.Currency,
+		Status:            models.InsurancePolicyActive,
+		CoverageStartAt:   startAt,
+	}
+
+	if err := s.repo.CreatePolicy(ctx, policy); err != nil {
+		return nil, err
+	}
+
+	// The trip may have ended while the provider was issuing cover.
+	if current, err := s.repo.FindRideByID(ctx, ride.ID); err == nil {
+		if endedAt := rideEndedAt(current); endedAt != nil {
+			if err := s.repo.ClosePolicy(ctx, ride.ID, *endedAt); err == nil {
+				policy.Status = models.InsurancePolicyClosed
+				policy.CoverageEndAt = endedAt
+			}
+		}
+	}
+
+	logger.Info("ride insurance attached",
+		"rideID", ride.ID,
+		"policyNumber", policy.PolicyNumber,
+		"provider", policy.Provider,
+	)
+	return policy, nil
+}
+
+func (s *service) EndCoverage(ctx context.Context, rideID string, endedAt time.Time) error {
+	return s.repo.ClosePolicy(ctx, rideID, endedAt)
+}
+
+func (s *service) PolicyReference(ctx context.Context, rideID string) (*pricingdto.InsuranceReference, error) {
+	policy, err := s.repo.FindPolicyByRideID(ctx, rideID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if policy.Status == models.InsurancePolicyVoided {
+		return nil, nil
+	}
+
+	return &pricingdto.InsuranceReference{
+		PolicyNumber:   policy.PolicyNumber,
+		Provider:       policy.Provider,
+		CoverageAmount: policy.CoverageAmount,
+		Currency:       policy.Currency,
+	}, nil
+}
+
+func (s *service) GetRidePolicy(ctx context.Context, userID, rideID string, isAdmin bool) (*dto.PolicyResponse, error) {
+	ride, err := s.repo.FindRideByID(ctx, rideID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Ride")
+		}
+		return nil, response.InternalServerError("Failed to fetch ride", err)
+	}
+
+	if !isAdmin && claimantRole(ride, userID) == "" {
+		return nil, response.ForbiddenError("Not authorized to view this ride")
+	}
+
+	policy, err := s.repo.FindPolicyByRideID(ctx, rideID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Insurance policy")
+		}
+		return nil, response.InternalServerError("Failed to fetch insurance policy", err)
+	}
+
+	return dto.ToPolicyResponse(policy), nil
+}
+
+func (s *service) FileClaim(ctx context.Context, userID, rideID string, req dto.FileClaimRequest) (*dto.ClaimResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	ride, err := s.repo.FindRideByID(ctx, rideID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Ride")
+		}
+		return nil, response.InternalServerError("Failed to fetch ride", err)
+	}
+
+	role := claimantRole(ride, userID)
+	if role == "" {
+		return nil, response.ForbiddenError("You can only file claims for your own rides")
+	}
+
+	policy, err := s.repo.FindPolicyByRideID(ctx, rideID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.BadRequest("This trip is not covered by an insurance policy")
+		}
+		return nil, response.InternalServerError("Failed to fetch insurance policy", err)
+	}
+	if policy.Status == models.InsurancePolicyVoided {
+		return nil, response.BadRequest("The insurance policy for this trip has been voided")
+	}
+
+	coverageEnd := time.Now()
+	if policy.CoverageEndAt != nil {
+		coverageEnd = *policy.CoverageEndAt
+	}
+	if time.Since(coverageEnd) > s.cfg.ClaimWindow {
+		return nil, response.BadRequest("The claim window for this trip has closed")
+	}
+
+	if req.AmountClaimed > policy.CoverageAmount {
+		return nil, response.BadRequest(fmt.Sprintf("Claimed amount exceeds the policy coverage of %.2f", policy.CoverageAmount))
+	}
+
+	incidentAt := policy.CoverageStartAt
+	if req.IncidentAt != "" {
+		incidentAt, _ = time.Parse(time.RFC3339, req.IncidentAt)
+		if incidentAt.Before(policy.CoverageStartAt) || incidentAt.After(coverageEnd) {
+			return nil, response.BadRequest("Incident time must fall within the trip's coverage period")
+		}
+	}
+
+	if existing, err := s.repo.FindOpenClaim(ctx, rideID, userID); err == nil && existing != nil {
+		return nil, response.ConflictError("You already have an open claim for this trip")
+	}
+
+	claimNumber, err := generateClaimNumber()
+	if err != nil {
+		return nil, response.InternalServerError("Failed to file claim", err)
+	}
+
+	claim := &models.InsuranceClaim{
+		ClaimNumber:   claimNumber,
+		PolicyID:      policy.ID,
+		RideID:        rideID,
+		ClaimantID:    userID,
+		ClaimantRole:  role,
+		ClaimType:     req.ClaimType,
+		Description:   strings.TrimSpace(req.Description),
+		IncidentAt:    incidentAt,
+		AmountClaimed: req.AmountClaimed,
+		Status:        models.InsuranceClaimSubmitted,
+	}
+
+	if err := s.repo.CreateClaim(ctx, claim); err != nil {
+		logger.Error("failed to create insurance claim", "error", err, "rideID", rideID)
+		return nil, response.InternalServerError("Failed to file claim", err)
+	}
+	claim.Policy = policy
+
+	logger.Info("insurance claim filed",
+		"claimID", claim.ID,
+		"claimNumber", claim.ClaimNumber,
+		"rideID", rideID,
+		"claimantRole", role,
+	)
+	return dto.ToClaimResponse(claim), nil
+}
+
+func (s *service) GetClaim(ctx context.Context, userID, claimID string, isAdmin bool) (*dto.ClaimResponse, error) {
+	claim, err := s.repo.FindClaimByID(ctx, claimID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Claim")
+		}
+		return nil, response.InternalServerError("Failed to fetch claim", err)
+	}
+
+	if !isAdmin && claim.ClaimantID != userID {
+		return nil, response.NotFoundError("Claim")
+	}
+
+	return dto.ToClaimResponse(claim), nil
+}
+
+func (s *service) ListClaims(ctx context.Context, claimantID string, req dto.ListClaimsRequest) ([]*dto.ClaimResponse, int64, error) {
+	claims, total, err := s.repo.ListClaims(ctx, claimantID, req.Status, req.Page, req.Limit)
+	if err != nil {
+		return nil, 0, response.InternalServerError("Failed to list claims", err)
+	}
+
+	result := make([]*dto.ClaimResponse, len(claims))
+	for i, claim := range claims {
+		result[i] = dto.ToClaimResponse(claim)
+	}
+	return result, total, nil
+}
+
+func (s *service) ReviewClaim(ctx context.Context, adminID, claimID string, req dto.ReviewClaimRequest) (*dto.ClaimResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	claim, err := s.repo.FindClaimByID(ctx, claimID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Claim")
+		}
+		return nil, response.InternalServerError("Failed to fetch claim", err)
+	}
+
+	next := models.InsuranceClaimStatus(req.Status)
+	if !canTransition(claim.Status, next) {
+		return nil, response.BadRequest(fmt.Sprintf("Cannot move claim from %s to %s", claim.Status, next))
+	}
+
+	if req.AmountApproved != nil {
+		if claim.Policy != nil && *req.AmountApproved > claim.Policy.CoverageAmount {
+			return nil, response.BadRequest("Approved amount exceeds the policy coverage")
+		}
+		claim.AmountApproved = req.AmountApproved
+	}
+
+	now := time.Now()
+	claim.Status = next
+	claim.ReviewedBy = &adminID
+	claim.ReviewedAt = &now
+	if req.Notes != "" {
+		claim.ReviewNotes = req.Notes
+	}
+
+	if err := s.repo.UpdateClaim(ctx, claim); err != nil {
+		logger.Error("failed to update insurance claim", "error", err, "claimID", claimID)
+		return nil, response.InternalServerError("Failed to review claim", err)
+	}
+
+	websocketutil.SendToUser(claim.ClaimantID, websocket.TypeInsuranceClaimUpdate, map[string]interface{}{
+		"claimId":     claim.ID,
+		"claimNumber": claim.ClaimNumber,
+		"rideId":      claim.RideID,
+		"status":      claim.Status,
+		"message":     fmt.Sprintf("Your insurance claim %s is now %s", claim.ClaimNumber, strings.ReplaceAll(string(claim.Status), "_", " ")),
+	})
+
+	logger.Info("insurance claim reviewed", "claimID", claim.ID, "status", claim.Status, "adminID", adminID)
+	return dto.ToClaimResponse(claim), nil
+}
+
+func (s *service) AttachPolicyForRide(ctx context.Context, rideID string) (*dto.PolicyResponse, error) {
+	ride, err := s.repo.FindRideByID(ctx, rideID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Ride")
+		}
+		return nil, response.InternalServerError("Failed to fetch ride", err)
+	}
+
+	if ride.StartedAt == nil {
+		return nil, response.BadRequest("Only trips that have started can be insured")
+	}
+
+	policy, err := s.AttachPolicy(ctx, ride)
+	if err != nil {
+		logger.Error("failed to attach ride insurance", "error", err, "rideID", rideID)
+		return nil, response.ServiceUnavailable("Failed to issue insurance policy")
+	}
+
+	return dto.ToPolicyResponse(policy), nil
+}
+
+func (s *service) GetCoverageReport(ctx context.Context, req dto.CoverageReportRequest) (*dto.CoverageReportResponse, error) {
+	from, to, err := req.Range()
+	if err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	stats, err := s.repo.GetCoverageStats(ctx, from, to)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to build coverage report", err)
+	}
+
+	providers, err := s.repo.GetCoverageByProvider(ctx, from, to)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to build coverage report", err)
+	}
+
+	claimStats, err := s.repo.GetClaimStats(ctx, from, to)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to build coverage report", err)
+	}
+
+	report := &dto.CoverageReportResponse{
+		FromDate:       from.Format("2006-01-02"),
+		ToDate:         to.Add(-time.Nanosecond).Format("2006-01-02"),
+		TotalTrips:     stats.TotalTrips,
+		CoveredTrips:   stats.CoveredTrips,
+		UncoveredTrips: stats.TotalTrips - stats.CoveredTrips,
+		TotalPremium:   stats.TotalPremium,
+		TotalCoverage:  stats.TotalCoverage,
+		ByProvider:     make([]dto.ProviderCoverageResponse, len(providers)),
+		Claims:         make([]dto.ClaimStatusSummary, len(claimStats)),
+	}
+	if stats.TotalTrips > 0 {
+		report.CoverageRate = float64(stats.CoveredTrips) / float64(stats.TotalTrips) * 100
+	}
+	for i, p := range providers {
+		report.ByProvider[i] = dto.ProviderCoverageResponse{
+			Provider:     p.Provider,
+			Policies:     p.Policies,
+			TotalPremium: p.TotalPremium,
+		}
+	}
+	for i, c := range claimStats {
+		report.Claims[i] = dto.ClaimStatusSummary{
+			Status:         c.Status,
+			Count:          c.Count,
+			AmountClaimed:  c.AmountClaimed,
+			AmountApproved: c.AmountApproved,
+		}
+	}
+
+	return report, nil
+}
+
+func (s *service) ListUncoveredRides(ctx context.Context, req dto.CoverageReportRequest) ([]dto.UncoveredRideResponse, int64, error) {
+	from, to, err := req.Range()
+	if err != nil {
+		return nil, 0, response.BadRequest(err.Error())
+	}
+
+	rides, total, err := s.repo.ListUncoveredRides(ctx, from, to, req.Page, req.Limit)
+	if err != nil {
+		return nil, 0, response.InternalServerError("Failed to list uncovered rides", err)
+	}
+
+	result := make([]dto.UncoveredRideResponse, len(rides))
+	for i, ride := range rides {
+		result[i] = dto.ToUncoveredRideResponse(ride)
+	}
+	return result, total, nil
+}
+
+func claimantRole(ride *models.Ride, userID string) string {
+	switch {
+	case ride.RiderID == userID:
+		return "rider"
+	case ride.DriverID != nil && *ride.DriverID == userID:
+		return "driver"
+	}
+	return ""
+}
+
+func rideEndedAt(ride *models.Ride) *time.Time {
+	switch {
+	case ride.CompletedAt != nil:
+		return ride.CompletedAt
+	case ride.CancelledAt != nil:
+		return ride.CancelledAt
+	}
+	return nil
+}
+
+func canTransition(from, to models.InsuranceClaimStatus) bool {
+	for _, allowed := range claimTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+func generateClaimNumber() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("CLM-%s-%s", time.Now().Format("20060102"), strings.ToUpper(hex.EncodeToString(b))), nil
+}
@@ -0,0 +1,54 @@
+package laundry
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/laundry/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+// AddressBook looks up a customer's saved addresses, so an order can be
+// placed with an address ID instead of the address and coordinates.
+type AddressBook interface {
+	ResolveAddress(ctx context.Context, userID, addressID string) (*models.SavedLocation, error)
+}
+
+// AddressNormalizer rewrites an address to the geocoder's canonical form
+// for its coordinates, keeping the given address when it cannot.
+type AddressNormalizer interface {
+	NormalizeAddress(ctx context.Context, address string, lat, lon float64) string
+}
+
+func (s *service) SetAddressBook(book AddressBook) {
+	s.addressBook = book
+}
+
+func (s *service) SetAddressNormalizer(normalizer AddressNormalizer) {
+	s.addressNormalizer = normalizer
+}
+
+func (s *service) normalizeOrderAddress(ctx context.Context, req *dto.CreateLaundryOrderRequest) {
+	if s.addressNormalizer == nil {
+		return
+	}
+	req.Address = s.addressNormalizer.NormalizeAddress(ctx, req.Address, req.Lat, req.Lng)
+}
+
+// resolveOrderAddress fills the pickup address from the saved address the
+// request references.
+func (s *service) resolveOrderAddress(ctx context.Context, customerID string, req *dto.CreateLaundryOrderRequest) error {
+	if req.AddressID == nil {
+		return nil
+	}
+	if s.addressBook == nil {
+		return response.BadRequest("Saved addresses are not available, send the address instead")
+	}
+
+	address, err := s.addressBook.ResolveAddress(ctx, customerID, *req.AddressID)
+	if err != nil {
+		return err
+	}
+	req.Address, req.Lat, req.Lng = address.Address, address.Latitude, address.Longitude
+	return nil
+}
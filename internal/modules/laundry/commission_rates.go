@@ -0,0 +1,36 @@
+package laundry
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// defaultCommissionRate is the platform's cut of a laundry order, in percent,
+// when no commission rates are configured.
+const defaultCommissionRate = 10.0
+
+// CommissionRates resolves the platform's cut of an order, in percent. It is
+// satisfied by commissions.Service.
+type CommissionRates interface {
+	ServiceRate(ctx context.Context, categorySlug string, lat, lng float64, at time.Time) float64
+}
+
+func (s *service) SetCommissionRates(commissionRates CommissionRates) {
+	s.commissionRates = commissionRates
+}
+
+// commissionRate is the rate in force now for an order in the category at
+// the given address. Orders keep it for their lifetime.
+func (s *service) commissionRate(ctx context.Context, categorySlug string, lat, lng float64) float64 {
+	if s.commissionRates == nil {
+		return defaultCommissionRate
+	}
+	return s.commissionRates.ServiceRate(ctx, categorySlug, lat, lng, time.Now())
+}
+
+// providerPayout is the provider's share of an order total at the order's
+// commission rate.
+func providerPayout(total, commissionRate float64) float64 {
+	return math.Round(total*(100-commissionRate)) / 100
+}
@@ -2,6 +2,7 @@ package dto
 
 import (
 	"fmt"
+	"time"
 )
 
 type CreateLaundryOrderRequest struct {
@@ -12,9 +13,12 @@ type CreateLaundryOrderRequest struct {
 	IsExpress    bool               `json:"isExpress"`
 	PersonCount   int                `json:"personCount" binding:"required,min=1"`
 	SpecialNotes string             `json:"specialNotes"`
-	Address      string             `json:"address" binding:"required"`
-	Lat          float64            `json:"lat" binding:"required"`
-	Lng          float64            `json:"lng" binding:"required"`
+	Address      string             `json:"address"`
+	Lat          float64            `json:"lat"`
+	Lng          float64            `json:"lng"`
+	// AddressID picks one of the customer's saved addresses instead of
+	// sending address, lat and lng.
+	AddressID    *string            `json:"addressId" binding:"omitempty,uuid"`
 	Tip          *float64           `json:"tip,omitempty"`
 }
 
@@ -40,11 +44,13 @@ func (r *CreateLaundryOrderRequest) Validate() error {
 	if r.PickupTime == "" {
 		return fmt.Errorf("pickupTime is required")
 	}
-	if r.Address == "" {
-		return fmt.Errorf("address is required")
-	}
-	if r.Lat == 0 || r.Lng == 0 {
-		return fmt.Errorf("valid coordinates are required")
+	if r.AddressID == nil {
+		if r.Address == "" {
+			return fmt.Errorf("address is required")
+		}
+		if r.Lat == 0 || r.Lng == 0 {
+			return fmt.Errorf("valid coordinates are required")
+		}
 	}
 
 	for i, item := range r.Items {
@@ -208,6 +214,66 @@ type ResolveIssueRequest struct {
 	CompensationType string   `json:"compensationType" binding:"omitempty,oneof=refund discount re_clean replacement voucher"`
 }
 
+type SubmitRequoteRequest struct {
+	Items []RequoteItemRequest `json:"items" binding:"required,min=1,dive"`
+	Note  string               `json:"note" binding:"omitempty,max=500"`
+}
+
+type RequoteItemRequest struct {
+	ItemID         string  `json:"itemId" binding:"required"`
+	MeasuredWeight float64 `json:"measuredWeight" binding:"required,gt=0"`
+}
+
+func (r *SubmitRequoteRequest) Validate() error {
+	seen := make(map[string]bool, len(r.Items))
+	for i, item := range r.Items {
+		if item.ItemID == "" {
+			return fmt.Errorf("itemId is required for item %d", i+1)
+		}
+		if item.MeasuredWeight <= 0 {
+			return fmt.Errorf("measuredWeight must be greater than 0 for item %d", i+1)
+		}
+		if seen[item.ItemID] {
+			return fmt.Errorf("item %s is listed more than once", item.ItemID)
+		}
+		seen[item.ItemID] = true
+	}
+	return nil
+}
+
+type RespondRequoteRequest struct {
+	Note string `json:"note" binding:"omitempty,max=500"`
+}
+
+// RescheduleDeliveryRequest moves a delivery to a new slot. FailedAttempt is
+// only taken from providers, when they could not hand the laundry over.
+type RescheduleDeliveryRequest struct {
+	ScheduledAt   time.Time `json:"scheduledAt" binding:"required"`
+	Reason        string    `json:"reason" binding:"omitempty,max=500"`
+	FailedAttempt bool      `json:"failedAttempt"`
+}
+
+type ScanItemRequest struct {
+	QRCode  string `json:"qrCode" binding:"required"`
+	Status  string `json:"status" binding:"required,oneof=received washing drying pressing packed delivered"`
+	Station string `json:"station" binding:"omitempty,max=100"`
+	Note    string `json:"note" binding:"omitempty,max=500"`
+}
+
+func (r *ScanItemRequest) Validate() error {
+	if r.QRCode == "" {
+		return fmt.Errorf("qrCode is required")
+	}
+	validStatuses := map[string]bool{
+		"received": true, "washing": true, "drying": true,
+		"pressing": true, "packed": true, "delivered": true,
+	}
+	if !validStatuses[r.Status] {
+		return fmt.Errorf("invalid status: %s", r.Status)
+	}
+	return nil
+}
+
 type OrderService struct {
 	ServiceSlug string  `json:"serviceSlug" binding:"required"`
 	Quantity    int     `json:"quantity" binding:"required,gt=0"`
@@ -34,6 +34,7 @@ type LaundryOrderItemDTO struct {
 	ItemType         string     `json:"itemType"`
 	Quantity         int        `json:"quantity"`
 	Weight           *float64   `json:"weight,omitempty"`
+	MeasuredWeight   *float64   `json:"measuredWeight,omitempty"`
 	QRCode           string     `json:"qrCode"`
 	Status           string     `json:"status"`
 	HasIssue         bool       `json:"hasIssue"`
@@ -403,3 +404,126 @@ func ToLaundryIssueResponses(issues []*models.LaundryIssue) []*LaundryIssueRespo
 	}
 	return responses
 }
+
+type LaundryRequoteResponse struct {
+	ID               string                      `json:"id"`
+	OrderID          string                      `json:"orderId"`
+	Status           string                      `json:"status"`
+	Lines            []models.LaundryRequoteLine `json:"lines"`
+	PreviousTotal    float64                     `json:"previousTotal"`
+	NewTotal         float64                     `json:"newTotal"`
+	Difference       float64                     `json:"difference"`
+	RequiresApproval bool                        `json:"requiresApproval"`
+	Note             string                      `json:"note,omitempty"`
+	CustomerNote     string                      `json:"customerNote,omitempty"`
+	RespondedAt      *time.Time                  `json:"respondedAt,omitempty"`
+	CreatedAt        time.Time                   `json:"createdAt"`
+}
+
+func ToLaundryRequoteResponse(requote *models.LaundryRequote) *LaundryRequoteResponse {
+	return &LaundryRequoteResponse{
+		ID:               requote.ID,
+		OrderID:          requote.OrderID,
+		Status:           string(requote.Status),
+		Lines:            requote.Lines,
+		PreviousTotal:    requote.PreviousTotal,
+		NewTotal:         requote.NewTotal,
+		Difference:       requote.Difference,
+		RequiresApproval: requote.Status == models.LaundryRequoteAwaitingApproval,
+		Note:             requote.Note,
+		CustomerNote:     requote.CustomerNote,
+		RespondedAt:      requote.RespondedAt,
+		CreatedAt:        requote.CreatedAt,
+	}
+}
+
+func ToLaundryRequoteResponses(requotes []*models.LaundryRequote) []*LaundryRequoteResponse {
+	responses := make([]*LaundryRequoteResponse, len(requotes))
+	for i, requote := range requotes {
+		responses[i] = ToLaundryRequoteResponse(requote)
+	}
+	return responses
+}
+
+type LaundryDeliveryRescheduleResponse struct {
+	ID                  string    `json:"id"`
+	OrderID             string    `json:"orderId"`
+	RequestedBy         string    `json:"requestedBy"`
+	PreviousScheduledAt time.Time `json:"previousScheduledAt"`
+	NewScheduledAt      time.Time `json:"newScheduledAt"`
+	Reason              string    `json:"reason,omitempty"`
+	FailedAttempt       bool      `json:"failedAttempt"`
+	Fee                 float64   `json:"fee"`
+	CreatedAt           time.Time `json:"createdAt"`
+}
+
+// DeliveryReschedulesResponse is a delivery's current slot and how it has
+// been moved, newest first.
+type DeliveryReschedulesResponse struct {
+	OrderID              string                               `json:"orderId"`
+	OrderNumber          string                               `json:"orderNumber"`
+	ScheduledAt          time.Time                            `json:"scheduledAt"`
+	Status               string                               `json:"status"`
+	RescheduleCount      int                                  `json:"rescheduleCount"`
+	RemainingReschedules int                                  `json:"remainingReschedules"`
+	FailedAttempts       int                                  `json:"failedAttempts"`
+	Reschedules          []*LaundryDeliveryRescheduleResponse `json:"reschedules"`
+}
+
+func ToLaundryDeliveryRescheduleResponse(reschedule *models.LaundryDeliveryReschedule) *LaundryDeliveryRescheduleResponse {
+	return &LaundryDeliveryRescheduleResponse{
+		ID:                  reschedule.ID,
+		OrderID:             reschedule.OrderID,
+		RequestedBy:         reschedule.RequestedBy,
+		PreviousScheduledAt: reschedule.PreviousScheduledAt,
+		NewScheduledAt:      reschedule.NewScheduledAt,
+		Reason:              reschedule.Reason,
+		FailedAttempt:       reschedule.FailedAttempt,
+		Fee:                 reschedule.Fee,
+		CreatedAt:           reschedule.CreatedAt,
+	}
+}
+
+type LaundryItemScanResponse struct {
+	ID         string    `json:"id"`
+	ItemID     string    `json:"itemId"`
+	QRCode     string    `json:"qrCode"`
+	FromStatus string    `json:"fromStatus"`
+	ToStatus   string    `json:"toStatus"`
+	ScannedBy  string    `json:"scannedBy"`
+	Station    *string   `json:"station,omitempty"`
+	Note       *string   `json:"note,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type ItemScanResultResponse struct {
+	Item *LaundryOrderItemResponse `json:"item"`
+	Scan *LaundryItemScanResponse  `json:"scan"`
+}
+
+type ItemScanHistoryResponse struct {
+	Item  *LaundryOrderItemResponse  `json:"item"`
+	Scans []*LaundryItemScanResponse `json:"scans"`
+}
+
+func ToLaundryItemScanResponse(scan *models.LaundryItemScan) *LaundryItemScanResponse {
+	return &LaundryItemScanResponse{
+		ID:         scan.ID,
+		ItemID:     scan.ItemID,
+		QRCode:     scan.QRCode,
+		FromStatus: scan.FromStatus,
+		ToStatus:   scan.ToStatus,
+		ScannedBy:  scan.ScannedBy,
+		Station:    scan.Station,
+		Note:       scan.Note,
+		CreatedAt:  scan.CreatedAt,
+	}
+}
+
+func ToLaundryItemScanResponses(scans []*models.LaundryItemScan) []*LaundryItemScanResponse {
+	responses := make([]*LaundryItemScanResponse, len(scans))
+	for i, scan := range scans {
+		responses[i] = ToLaundryItemScanResponse(scan)
+	}
+	return responses
+}
@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"fmt"
+	"strings"
+)
+
+type CreateFacilityRequest struct {
+	Name      string  `json:"name" binding:"required,min=2,max=255"`
+	Address   string  `json:"address" binding:"omitempty,max=500"`
+	Latitude  float64 `json:"lat" binding:"omitempty,latitude"`
+	Longitude float64 `json:"lng" binding:"omitempty,longitude"`
+}
+
+func (r *CreateFacilityRequest) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	return nil
+}
+
+type UpdateFacilityRequest struct {
+	Name      *string  `json:"name" binding:"omitempty,min=2,max=255"`
+	Address   *string  `json:"address" binding:"omitempty,max=500"`
+	Latitude  *float64 `json:"lat" binding:"omitempty,latitude"`
+	Longitude *float64 `json:"lng" binding:"omitempty,longitude"`
+	IsActive  *bool    `json:"isActive"`
+}
+
+type CreateWorkstationRequest struct {
+	Name     string `json:"name" binding:"required,max=100"`
+	Type     string `json:"type" binding:"required,oneof=wash dry press pack"`
+	Capacity int    `json:"capacity" binding:"required,min=1,max=1000"`
+}
+
+type UpdateWorkstationRequest struct {
+	Name     *string `json:"name" binding:"omitempty,max=100"`
+	Capacity *int    `json:"capacity" binding:"omitempty,min=1,max=1000"`
+	IsActive *bool   `json:"isActive"`
+}
+
+type AssignOrderFacilityRequest struct {
+	FacilityID string `json:"facilityId" binding:"required,uuid"`
+}
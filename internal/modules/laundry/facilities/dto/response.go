@@ -0,0 +1,94 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+type WorkstationResponse struct {
+	ID          string    `json:"id"`
+	FacilityID  string    `json:"facilityId"`
+	Name        string    `json:"name"`
+	Type        string    `json:"type"`
+	Capacity    int       `json:"capacity"`
+	CurrentLoad int       `json:"currentLoad"`
+	IsActive    bool      `json:"isActive"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type FacilityResponse struct {
+	ID           string                 `json:"id"`
+	ProviderID   string                 `json:"providerId"`
+	Name         string                 `json:"name"`
+	Address      string                 `json:"address"`
+	Lat          float64                `json:"lat"`
+	Lng          float64                `json:"lng"`
+	IsActive     bool                   `json:"isActive"`
+	Workstations []*WorkstationResponse `json:"workstations"`
+	CreatedAt    time.Time              `json:"createdAt"`
+	UpdatedAt    time.Time              `json:"updatedAt"`
+}
+
+// StationStatsResponse is one workstation's throughput over the requested
+// window. Items count once they leave the station.
+type StationStatsResponse struct {
+	WorkstationID       string  `json:"workstationId"`
+	Name                string  `json:"name"`
+	Type                string  `json:"type"`
+	Capacity            int     `json:"capacity"`
+	CurrentLoad         int     `json:"currentLoad"`
+	Utilization         float64 `json:"utilization"`
+	ItemsProcessed      int64   `json:"itemsProcessed"`
+	ItemsPerHour        float64 `json:"itemsPerHour"`
+	AvgMinutesAtStation float64 `json:"avgMinutesAtStation"`
+}
+
+type FacilityStatsResponse struct {
+	FacilityID string                  `json:"facilityId"`
+	From       time.Time               `json:"from"`
+	To         time.Time               `json:"to"`
+	Stations   []*StationStatsResponse `json:"stations"`
+}
+
+type OrderFacilityResponse struct {
+	OrderID      string `json:"orderId"`
+	FacilityID   string `json:"facilityId"`
+	FacilityName string `json:"facilityName"`
+}
+
+func ToWorkstationResponse(station *models.LaundryWorkstation, load int) *WorkstationResponse {
+	return &WorkstationResponse{
+		ID:          station.ID,
+		FacilityID:  station.FacilityID,
+		Name:        station.Name,
+		Type:        string(station.Type),
+		Capacity:    station.Capacity,
+		CurrentLoad: load,
+		IsActive:    station.IsActive,
+		CreatedAt:   station.CreatedAt,
+		UpdatedAt:   station.UpdatedAt,
+	}
+}
+
+// ToFacilityResponse includes the facility's workstations with their current
+// load, keyed by workstation ID.
+func ToFacilityResponse(facility *models.LaundryFacility, loads map[string]int) *FacilityResponse {
+	stations := make([]*WorkstationResponse, len(facility.Workstations))
+	for i, station := range facility.Workstations {
+		stations[i] = ToWorkstationResponse(station, loads[station.ID])
+	}
+	return &FacilityResponse{
+		ID:           facility.ID,
+		ProviderID:   facility.ProviderID,
+		Name:         facility.Name,
+		Address:      facility.Address,
+		Lat:          facility.Latitude,
+		Lng:          facility.Longitude,
+		IsActive:     facility.IsActive,
+		Workstations: stations,
+		CreatedAt:    facility.CreatedAt,
+		UpdatedAt:    facility.UpdatedAt,
+	}
+}
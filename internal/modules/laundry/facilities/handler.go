@@ -0,0 +1,278 @@
+package facilities
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/modules/laundry/facilities/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// CreateFacility - POST /api/v1/laundry/provider/facilities
+// @Summary Create Facility
+// @Description Register a processing facility for the authenticated laundry provider
+// @Tags Provider - Facilities
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateFacilityRequest true "Facility details"
+// @Success 200 {object} dto.FacilityResponse "Facility created"
+// @Router /api/v1/laundry/provider/facilities [post]
+func (h *Handler) CreateFacility(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User ID not found in context"))
+		return
+	}
+
+	var req dto.CreateFacilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	facility, err := h.service.CreateFacility(c, userID.(string), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, facility, "Facility created successfully")
+}
+
+// ListFacilities - GET /api/v1/laundry/provider/facilities
+// @Summary List Facilities
+// @Description List the provider's facilities with their workstations and current load
+// @Tags Provider - Facilities
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {array} dto.FacilityResponse "Facilities"
+// @Router /api/v1/laundry/provider/facilities [get]
+func (h *Handler) ListFacilities(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User ID not found in context"))
+		return
+	}
+
+	facilities, err := h.service.ListFacilities(c, userID.(string))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, facilities, "Facilities retrieved successfully")
+}
+
+// GetFacility - GET /api/v1/laundry/provider/facilities/:id
+// @Summary Get Facility
+// @Description Get a facility with its workstations and current load
+// @Tags Provider - Facilities
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "Facility ID (UUID)"
+// @Success 200 {object} dto.FacilityResponse "Facility"
+// @Router /api/v1/laundry/provider/facilities/{id} [get]
+func (h *Handler) GetFacility(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User ID not found in context"))
+		return
+	}
+
+	facility, err := h.service.GetFacility(c, userID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, facility, "Facility retrieved successfully")
+}
+
+// UpdateFacility - PUT /api/v1/laundry/provider/facilities/:id
+// @Summary Update Facility
+// @Description Update a facility's details or take it out of service
+// @Tags Provider - Facilities
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Facility ID (UUID)"
+// @Param request body dto.UpdateFacilityRequest true "Fields to update"
+// @Success 200 {object} dto.FacilityResponse "Facility updated"
+// @Router /api/v1/laundry/provider/facilities/{id} [put]
+func (h *Handler) UpdateFacility(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User ID not found in context"))
+		return
+	}
+
+	var req dto.UpdateFacilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	facility, err := h.service.UpdateFacility(c, userID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, facility, "Facility updated successfully")
+}
+
+// AddWorkstation - POST /api/v1/laundry/provider/facilities/:id/workstations
+// @Summary Add Workstation
+// @Description Add a wash, dry, press or pack workstation with the number of items it can hold at once
+// @Tags Provider - Facilities
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Facility ID (UUID)"
+// @Param request body dto.CreateWorkstationRequest true "Workstation details"
+// @Success 200 {object} dto.WorkstationResponse "Workstation added"
+// @Router /api/v1/laundry/provider/facilities/{id}/workstations [post]
+func (h *Handler) AddWorkstation(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User ID not found in context"))
+		return
+	}
+
+	var req dto.CreateWorkstationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	station, err := h.service.AddWorkstation(c, userID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, station, "Workstation added successfully")
+}
+
+// UpdateWorkstation - PUT /api/v1/laundry/provider/facilities/:id/workstations/:stationId
+// @Summary Update Workstation
+// @Description Rename a workstation, change its capacity or take it out of service
+// @Tags Provider - Facilities
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Facility ID (UUID)"
+// @Param stationId path string true "Workstation ID (UUID)"
+// @Param request body dto.UpdateWorkstationRequest true "Fields to update"
+// @Success 200 {object} dto.WorkstationResponse "Workstation updated"
+// @Router /api/v1/laundry/provider/facilities/{id}/workstations/{stationId} [put]
+func (h *Handler) UpdateWorkstation(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User ID not found in context"))
+		return
+	}
+
+	var req dto.UpdateWorkstationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	station, err := h.service.UpdateWorkstation(c, userID.(string), c.Param("id"), c.Param("stationId"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, station, "Workstation updated successfully")
+}
+
+// GetFacilityStats - GET /api/v1/laundry/provider/facilities/:id/stats
+// @Summary Get Facility Throughput
+// @Description Per-workstation throughput, average time at station and current utilization. Defaults to the last 24 hours
+// @Tags Provider - Facilities
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "Facility ID (UUID)"
+// @Param from query string false "Window start (RFC3339)"
+// @Param to query string false "Window end (RFC3339)"
+// @Success 200 {object} dto.FacilityStatsResponse "Throughput stats"
+// @Router /api/v1/laundry/provider/facilities/{id}/stats [get]
+func (h *Handler) GetFacilityStats(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User ID not found in context"))
+		return
+	}
+
+	to := time.Now()
+	if v := c.Query("to"); v != "" {
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.Error(response.BadRequest("Invalid to, expected RFC3339"))
+			return
+		}
+		to = parsed
+	}
+	from := to.Add(-24 * time.Hour)
+	if v := c.Query("from"); v != "" {
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.Error(response.BadRequest("Invalid from, expected RFC3339"))
+			return
+		}
+		from = parsed
+	}
+
+	stats, err := h.service.GetFacilityStats(c, userID.(string), c.Param("id"), from, to)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, stats, "Facility stats retrieved successfully")
+}
+
+// AssignOrder - PUT /api/v1/laundry/provider/orders/:id/facility
+// @Summary Assign Order to Facility
+// @Description Send an order to one of the provider's facilities. Its items are then routed through the facility's workstations in wash, dry, press, pack order, subject to station capacity
+// @Tags Provider - Facilities
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID (UUID)"
+// @Param request body dto.AssignOrderFacilityRequest true "Facility"
+// @Success 200 {object} dto.OrderFacilityResponse "Order assigned"
+// @Failure 409 {object} response.Response "Items already being processed elsewhere"
+// @Router /api/v1/laundry/provider/orders/{id}/facility [put]
+func (h *Handler) AssignOrder(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User ID not found in context"))
+		return
+	}
+
+	var req dto.AssignOrderFacilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	result, err := h.service.AssignOrder(c, userID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Order assigned to facility")
+}
@@ -0,0 +1,262 @@
+package facilities
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	errNoStation    = errors.New("facility has no active workstation of this type")
+	errStationsFull = errors.New("all workstations of this type are at capacity")
+)
+
+type Repository interface {
+	GetProviderByUserID(ctx context.Context, userID string) (*models.ServiceProviderProfile, error)
+
+	CreateFacility(ctx context.Context, facility *models.LaundryFacility) error
+	UpdateFacility(ctx context.Context, facility *models.LaundryFacility) error
+	GetFacility(ctx context.Context, facilityID string) (*models.LaundryFacility, error)
+	GetProviderFacilities(ctx context.Context, providerID string) ([]*models.LaundryFacility, error)
+
+	CreateWorkstation(ctx context.Context, station *models.LaundryWorkstation) error
+	UpdateWorkstation(ctx context.Context, station *models.LaundryWorkstation) error
+	GetWorkstation(ctx context.Context, facilityID, stationID string) (*models.LaundryWorkstation, error)
+	GetStationLoads(ctx context.Context, stationIDs []string) (map[string]int, error)
+	GetStationThroughput(ctx context.Context, stationIDs []string, from, to time.Time) (map[string]StationThroughput, error)
+
+	GetOrder(ctx context.Context, orderID string) (*models.LaundryOrder, error)
+	SetOrderFacility(ctx context.Context, orderID, facilityID string) error
+	CountOpenVisitsByOrder(ctx context.Context, orderID string) (int64, error)
+
+	MoveItem(ctx context.Context, facilityID string, item *models.LaundryOrderItem, stationType models.LaundryStationType, at time.Time) (*models.LaundryWorkstation, error)
+}
+
+// StationThroughput is what a workstation got through in a time window.
+type StationThroughput struct {
+	ItemsProcessed int64
+	AvgSeconds     float64
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) GetProviderByUserID(ctx context.Context, userID string) (*models.ServiceProviderProfile, error) {
+	var provider models.ServiceProviderProfile
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		First(&provider).Error
+	if err != nil {
+		return nil, err
+	}
+	return &provider, nil
+}
+
+func (r *repository) CreateFacility(ctx context.Context, facility *models.LaundryFacility) error {
+	return r.db.WithContext(ctx).Create(facility).Error
+}
+
+func (r *repository) UpdateFacility(ctx context.Context, facility *models.LaundryFacility) error {
+	return r.db.WithContext(ctx).Omit("Workstations").Save(facility).Error
+}
+
+func (r *repository) GetFacility(ctx context.Context, facilityID string) (*models.LaundryFacility, error) {
+	var facility models.LaundryFacility
+	err := r.db.WithContext(ctx).
+		Preload("Workstations", func(db *gorm.DB) *gorm.DB {
+			return db.Order("type ASC, name ASC")
+		}).
+		Where("id = ?", facilityID).
+		First(&facility).Error
+	if err != nil {
+		return nil, err
+	}
+	return &facility, nil
+}
+
+func (r *repository) GetProviderFacilities(ctx context.Context, providerID string) ([]*models.LaundryFacility, error) {
+	var facilities []*models.LaundryFacility
+	err := r.db.WithContext(ctx).
+		Preload("Workstations", func(db *gorm.DB) *gorm.DB {
+			return db.Order("type ASC, name ASC")
+		}).
+		Where("provider_id = ?", providerID).
+		Order("created_at ASC").
+		Find(&facilities).Error
+	return facilities, err
+}
+
+func (r *repository) CreateWorkstation(ctx context.Context, station *models.LaundryWorkstation) error {
+	return r.db.WithContext(ctx).Create(station).Error
+}
+
+func (r *repository) UpdateWorkstation(ctx context.Context, station *models.LaundryWorkstation) error {
+	return r.db.WithContext(ctx).Save(station).Error
+}
+
+func (r *repository) GetWorkstation(ctx context.Context, facilityID, stationID string) (*models.LaundryWorkstation, error) {
+	var station models.LaundryWorkstation
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND facility_id = ?", stationID, facilityID).
+		First(&station).Error
+	if err != nil {
+		return nil, err
+	}
+	return &station, nil
+}
+
+func (r *repository) GetStationLoads(ctx context.Context, stationIDs []string) (map[string]int, error) {
+	return stationLoads(r.db.WithContext(ctx), stationIDs)
+}
+
+func stationLoads(db *gorm.DB, stationIDs []string) (map[string]int, error) {
+	loads := make(map[string]int, len(stationIDs))
+	if len(stationIDs) == 0 {
+		return loads, nil
+	}
+
+	var rows []struct {
+		WorkstationID string
+		ItemCount     int
+	}
+	err := db.Model(&models.LaundryStationVisit{}).
+		Select("workstation_id, COUNT(*) AS item_count").
+		Where("workstation_id IN ? AND exited_at IS NULL", stationIDs).
+		Group("workstation_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		loads[row.WorkstationID] = row.ItemCount
+	}
+	return loads, nil
+}
+
+func (r *repository) GetStationThroughput(ctx context.Context, stationIDs []string, from, to time.Time) (map[string]StationThroughput, error) {
+	throughput := make(map[string]StationThroughput, len(stationIDs))
+	if len(stationIDs) == 0 {
+		return throughput, nil
+	}
+
+	var rows []struct {
+		WorkstationID  string
+		ItemsProcessed int64
+		AvgSeconds     float64
+	}
+	err := r.db.WithContext(ctx).
+		Model(&models.LaundryStationVisit{}).
+		Select("workstation_id, COUNT(*) AS items_processed, COALESCE(AVG(EXTRACT(EPOCH FROM (exited_at - entered_at))), 0) AS avg_seconds").
+		Where("workstation_id IN ? AND exited_at >= ? AND exited_at < ?", stationIDs, from, to).
+		Group("workstation_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		throughput[row.WorkstationID] = StationThroughput{ItemsProcessed: row.ItemsProcessed, AvgSeconds: row.AvgSeconds}
+	}
+	return throughput, nil
+}
+
+func (r *repository) GetOrder(ctx context.Context, orderID string) (*models.LaundryOrder, error) {
+	var order models.LaundryOrder
+	err := r.db.WithContext(ctx).
+		Where("id = ?", orderID).
+		First(&order).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *repository) SetOrderFacility(ctx context.Context, orderID, facilityID string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.LaundryOrder{}).
+		Where("id = ?", orderID).
+		Updates(map[string]interface{}{
+			"facility_id": facilityID,
+			"version":     gorm.Expr("version + 1"),
+			"updated_at":  time.Now(),
+		}).Error
+}
+
+func (r *repository) CountOpenVisitsByOrder(ctx context.Context, orderID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.LaundryStationVisit{}).
+		Where("order_id = ? AND exited_at IS NULL", orderID).
+		Count(&count).Error
+	return count, err
+}
+
+// MoveItem takes the item off the station it is at and, when a station type
+// is given, puts it on the least loaded active station of that type in the
+// facility. The stations are locked while their load is counted so two items
+// cannot both take the last free place.
+func (r *repository) MoveItem(ctx context.Context, facilityID string, item *models.LaundryOrderItem, stationType models.LaundryStationType, at time.Time) (*models.LaundryWorkstation, error) {
+	var placed *models.LaundryWorkstation
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.LaundryStationVisit{}).
+			Where("item_id = ? AND exited_at IS NULL", item.ID).
+			Update("exited_at", at).Error; err != nil {
+			return err
+		}
+		if stationType == "" {
+			return nil
+		}
+
+		var stations []*models.LaundryWorkstation
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("facility_id = ? AND type = ? AND is_active = ?", facilityID, stationType, true).
+			Order("name ASC").
+			Find(&stations).Error; err != nil {
+			return err
+		}
+		if len(stations) == 0 {
+			return errNoStation
+		}
+
+		ids := make([]string, len(stations))
+		for i, station := range stations {
+			ids[i] = station.ID
+		}
+		loads, err := stationLoads(tx, ids)
+		if err != nil {
+			return err
+		}
+
+		for _, station := range stations {
+			if loads[station.ID] >= station.Capacity {
+				continue
+			}
+			if placed == nil || loads[station.ID]*placed.Capacity < loads[placed.ID]*station.Capacity {
+				placed = station
+			}
+		}
+		if placed == nil {
+			return errStationsFull
+		}
+
+		return tx.Create(&models.LaundryStationVisit{
+			WorkstationID: placed.ID,
+			ItemID:        item.ID,
+			OrderID:       item.OrderID,
+			EnteredAt:     at,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return placed, nil
+}
@@ -0,0 +1,23 @@
+package facilities
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes adds the facility endpoints to the laundry provider group,
+// which already requires an authenticated service provider.
+func RegisterRoutes(provider *gin.RouterGroup, handler *Handler) {
+	facilities := provider.Group("/facilities")
+	{
+		facilities.POST("", handler.CreateFacility)
+		facilities.GET("", handler.ListFacilities)
+		facilities.GET("/:id", handler.GetFacility)
+		facilities.PUT("/:id", handler.UpdateFacility)
+		facilities.GET("/:id/stats", handler.GetFacilityStats)
+
+		facilities.POST("/:id/workstations", handler.AddWorkstation)
+		facilities.PUT("/:id/workstations/:stationId", handler.UpdateWorkstation)
+	}
+
+	provider.PUT("/orders/:id/facility", handler.AssignOrder)
+}
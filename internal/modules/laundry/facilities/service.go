@@ -0,0 +1,340 @@
+package facilities
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/laundry/facilities/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	CreateFacility(ctx context.Context, userID string, req dto.CreateFacilityRequest) (*dto.FacilityResponse, error)
+	ListFacilities(ctx context.Context, userID string) ([]*dto.FacilityResponse, error)
+	GetFacility(ctx context.Context, userID, facilityID string) (*dto.FacilityResponse, error)
+	UpdateFacility(ctx context.Context, userID, facilityID string, req dto.UpdateFacilityRequest) (*dto.FacilityResponse, error)
+
+	AddWorkstation(ctx context.Context, userID, facilityID string, req dto.CreateWorkstationRequest) (*dto.WorkstationResponse, error)
+	UpdateWorkstation(ctx context.Context, userID, facilityID, stationID string, req dto.UpdateWorkstationRequest) (*dto.WorkstationResponse, error)
+	GetFacilityStats(ctx context.Context, userID, facilityID string, from, to time.Time) (*dto.FacilityStatsResponse, error)
+
+	AssignOrder(ctx context.Context, userID, orderID string, req dto.AssignOrderFacilityRequest) (*dto.OrderFacilityResponse, error)
+
+	// RouteItem moves an item of an order processed at the facility to a
+	// workstation for its new status, or off its station when the status is
+	// not handled at one.
+	RouteItem(ctx context.Context, facilityID string, item *models.LaundryOrderItem, toStatus string) error
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) providerFor(ctx context.Context, userID string) (*models.ServiceProviderProfile, error) {
+	provider, err := s.repo.GetProviderByUserID(ctx, userID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, response.ForbiddenError("Only service providers can manage facilities")
+		}
+		return nil, response.InternalServerError("Failed to get provider profile", err)
+	}
+	return provider, nil
+}
+
+// loadOwnFacility returns the facility if it belongs to the user's provider
+// profile; other providers' facilities are reported as not found.
+func (s *service) loadOwnFacility(ctx context.Context, userID, facilityID string) (*models.LaundryFacility, error) {
+	provider, err := s.providerFor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	facility, err := s.repo.GetFacility(ctx, facilityID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, response.NotFoundError("Facility")
+		}
+		return nil, response.InternalServerError("Failed to get facility", err)
+	}
+	if facility.ProviderID != provider.ID {
+		return nil, response.NotFoundError("Facility")
+	}
+	return facility, nil
+}
+
+func (s *service) toResponse(ctx context.Context, facility *models.LaundryFacility) (*dto.FacilityResponse, error) {
+	ids := make([]string, len(facility.Workstations))
+	for i, station := range facility.Workstations {
+		ids[i] = station.ID
+	}
+	loads, err := s.repo.GetStationLoads(ctx, ids)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get workstation load", err)
+	}
+	return dto.ToFacilityResponse(facility, loads), nil
+}
+
+func (s *service) CreateFacility(ctx context.Context, userID string, req dto.CreateFacilityRequest) (*dto.FacilityResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+	provider, err := s.providerFor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	facility := &models.LaundryFacility{
+		ProviderID: provider.ID,
+		Name:       req.Name,
+		Address:    req.Address,
+		Latitude:   req.Latitude,
+		Longitude:  req.Longitude,
+		IsActive:   true,
+	}
+	if err := s.repo.CreateFacility(ctx, facility); err != nil {
+		logger.Error("failed to create laundry facility", "error", err, "providerID", provider.ID)
+		return nil, response.InternalServerError("Failed to create facility", err)
+	}
+
+	logger.Info("laundry facility created", "facilityID", facility.ID, "providerID", provider.ID)
+	return dto.ToFacilityResponse(facility, nil), nil
+}
+
+func (s *service) ListFacilities(ctx context.Context, userID string) ([]*dto.FacilityResponse, error) {
+	provider, err := s.providerFor(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	facilities, err := s.repo.GetProviderFacilities(ctx, provider.ID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get facilities", err)
+	}
+
+	result := make([]*dto.FacilityResponse, len(facilities))
+	for i, facility := range facilities {
+		if result[i], err = s.toResponse(ctx, facility); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+func (s *service) GetFacility(ctx context.Context, userID, facilityID string) (*dto.FacilityResponse, error) {
+	facility, err := s.loadOwnFacility(ctx, userID, facilityID)
+	if err != nil {
+		return nil, err
+	}
+	return s.toResponse(ctx, facility)
+}
+
+func (s *service) UpdateFacility(ctx context.Context, userID, facilityID string, req dto.UpdateFacilityRequest) (*dto.FacilityResponse, error) {
+	facility, err := s.loadOwnFacility(ctx, userID, facilityID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		facility.Name = *req.Name
+	}
+	if req.Address != nil {
+		facility.Address = *req.Address
+	}
+	if req.Latitude != nil {
+		facility.Latitude = *req.Latitude
+	}
+	if req.Longitude != nil {
+		facility.Longitude = *req.Longitude
+	}
+	if req.IsActive != nil {
+		facility.IsActive = *req.IsActive
+	}
+
+	if err := s.repo.UpdateFacility(ctx, facility); err != nil {
+		return nil, response.InternalServerError("Failed to update facility", err)
+	}
+	return s.toResponse(ctx, facility)
+}
+
+func (s *service) AddWorkstation(ctx context.Context, userID, facilityID string, req dto.CreateWorkstationRequest) (*dto.WorkstationResponse, error) {
+	facility, err := s.loadOwnFacility(ctx, userID, facilityID)
+	if err != nil {
+		return nil, err
+	}
+
+	station := &models.LaundryWorkstation{
+		FacilityID: facility.ID,
+		Name:       req.Name,
+		Type:       models.LaundryStationType(req.Type),
+		Capacity:   req.Capacity,
+		IsActive:   true,
+	}
+	if err := s.repo.CreateWorkstation(ctx, station); err != nil {
+		logger.Error("failed to create workstation", "error", err, "facilityID", facility.ID)
+		return nil, response.InternalServerError("Failed to add workstation", err)
+	}
+
+	logger.Info("laundry workstation added", "facilityID", facility.ID, "workstationID", station.ID, "type", station.Type)
+	return dto.ToWorkstationResponse(station, 0), nil
+}
+
+// UpdateWorkstation changes a station's name, capacity or availability.
+// Lowering the capacity below the current load does not move items off the
+// station; it only stops new ones from being routed there.
+func (s *service) UpdateWorkstation(ctx context.Context, userID, facilityID, stationID string, req dto.UpdateWorkstationRequest) (*dto.WorkstationResponse, error) {
+	facility, err := s.loadOwnFacility(ctx, userID, facilityID)
+	if err != nil {
+		return nil, err
+	}
+	station, err := s.repo.GetWorkstation(ctx, facility.ID, stationID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, response.NotFoundError("Workstation")
+		}
+		return nil, response.InternalServerError("Failed to get workstation", err)
+	}
+
+	if req.Name != nil {
+		station.Name = *req.Name
+	}
+	if req.Capacity != nil {
+		station.Capacity = *req.Capacity
+	}
+	if req.IsActive != nil {
+		station.IsActive = *req.IsActive
+	}
+	if err := s.repo.UpdateWorkstation(ctx, station); err != nil {
+		return nil, response.InternalServerError("Failed to update workstation", err)
+	}
+
+	loads, err := s.repo.GetStationLoads(ctx, []string{station.ID})
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get workstation load", err)
+	}
+	return dto.ToWorkstationResponse(station, loads[station.ID]), nil
+}
+
+func (s *service) GetFacilityStats(ctx context.Context, userID, facilityID string, from, to time.Time) (*dto.FacilityStatsResponse, error) {
+	if !from.Before(to) {
+		return nil, response.BadRequest("from must be before to")
+	}
+	facility, err := s.loadOwnFacility(ctx, userID, facilityID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(facility.Workstations))
+	for i, station := range facility.Workstations {
+		ids[i] = station.ID
+	}
+	loads, err := s.repo.GetStationLoads(ctx, ids)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get workstation load", err)
+	}
+	throughput, err := s.repo.GetStationThroughput(ctx, ids, from, to)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get workstation throughput", err)
+	}
+
+	hours := to.Sub(from).Hours()
+	stats := make([]*dto.StationStatsResponse, len(facility.Workstations))
+	for i, station := range facility.Workstations {
+		done := throughput[station.ID]
+		stats[i] = &dto.StationStatsResponse{
+			WorkstationID:       station.ID,
+			Name:                station.Name,
+			Type:                string(station.Type),
+			Capacity:            station.Capacity,
+			CurrentLoad:         loads[station.ID],
+			Utilization:         round2(float64(loads[station.ID]) / float64(station.Capacity)),
+			ItemsProcessed:      done.ItemsProcessed,
+			ItemsPerHour:        round2(float64(done.ItemsProcessed) / hours),
+			AvgMinutesAtStation: round2(done.AvgSeconds / 60),
+		}
+	}
+
+	return &dto.FacilityStatsResponse{
+		FacilityID: facility.ID,
+		From:       from,
+		To:         to,
+		Stations:   stats,
+	}, nil
+}
+
+// AssignOrder sends an order to one of the provider's facilities. Once items
+// are on the facility's workstations the order can no longer be moved.
+func (s *service) AssignOrder(ctx context.Context, userID, orderID string, req dto.AssignOrderFacilityRequest) (*dto.OrderFacilityResponse, error) {
+	facility, err := s.loadOwnFacility(ctx, userID, req.FacilityID)
+	if err != nil {
+		return nil, err
+	}
+	if !facility.IsActive {
+		return nil, response.BadRequest("Facility is not active")
+	}
+
+	order, err := s.repo.GetOrder(ctx, orderID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, response.NotFoundError("Order")
+		}
+		return nil, response.InternalServerError("Failed to get order", err)
+	}
+	if order.ProviderID == nil || *order.ProviderID != facility.ProviderID {
+		return nil, response.NotFoundError("Order")
+	}
+	if order.Status == "completed" || order.Status == "cancelled" {
+		return nil, response.BadRequest("Cannot assign a " + order.Status + " order to a facility")
+	}
+
+	if order.FacilityID != nil && *order.FacilityID != facility.ID {
+		open, err := s.repo.CountOpenVisitsByOrder(ctx, order.ID)
+		if err != nil {
+			return nil, response.InternalServerError("Failed to check order items", err)
+		}
+		if open > 0 {
+			return nil, response.ConflictError("Order items are already being processed at another facility")
+		}
+	}
+
+	if err := s.repo.SetOrderFacility(ctx, order.ID, facility.ID); err != nil {
+		return nil, response.InternalServerError("Failed to assign facility", err)
+	}
+
+	logger.Info("laundry order assigned to facility", "orderID", order.ID, "facilityID", facility.ID)
+	return &dto.OrderFacilityResponse{
+		OrderID:      order.ID,
+		FacilityID:   facility.ID,
+		FacilityName: facility.Name,
+	}, nil
+}
+
+func (s *service) RouteItem(ctx context.Context, facilityID string, item *models.LaundryOrderItem, toStatus string) error {
+	stationType := models.LaundryStationForItemStatus[toStatus]
+
+	station, err := s.repo.MoveItem(ctx, facilityID, item, stationType, time.Now())
+	switch err {
+	case nil:
+	case errNoStation:
+		return response.BadRequest("The facility has no active " + string(stationType) + " workstation")
+	case errStationsFull:
+		return response.ConflictError("All " + string(stationType) + " workstations are at capacity")
+	default:
+		logger.Error("failed to route laundry item", "error", err, "itemID", item.ID, "facilityID", facilityID)
+		return response.InternalServerError("Failed to route item", err)
+	}
+
+	if station != nil {
+		logger.Info("laundry item routed", "itemID", item.ID, "workstationID", station.ID, "status", toStatus)
+	}
+	return nil
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -1,7 +1,12 @@
 package laundry
 
 import (
+	"fmt"
+	"net/http"
+	"strconv"
+
 	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/models"
 	"github.com/umar5678/go-backend/internal/modules/laundry/dto"
 	"github.com/umar5678/go-backend/internal/utils/response"
 )
@@ -316,6 +321,10 @@ func (h *Handler) UpdateItemStatus(c *gin.Context) {
 
 	item, err := h.service.UpdateItemStatus(c, qrCode, req.Status)
 	if err != nil {
+		if appErr, ok := err.(*response.AppError); ok {
+			c.Error(appErr)
+			return
+		}
 		c.Error(response.InternalServerError("Failed to update item status", err))
 		return
 	}
@@ -392,6 +401,10 @@ func (h *Handler) CompleteDelivery(c *gin.Context) {
 	}
 
 	if err := h.service.CompleteDelivery(c, orderID, &req); err != nil {
+		if appErr, ok := err.(*response.AppError); ok {
+			c.Error(appErr)
+			return
+		}
 		c.Error(response.InternalServerError("Failed to complete delivery", err))
 		return
 	}
@@ -557,3 +570,328 @@ func (h *Handler) ResolveIssue(c *gin.Context) {
 
 	response.Success(c, nil, "Issue resolved successfully")
 }
+
+// SubmitRequote - POST /api/v1/laundry/provider/orders/:id/requote
+// @Summary Submit Re-quote
+// @Description Submit measured weights for order items. The order is repriced and the customer's wallet hold adjusted; increases above the approval threshold wait for the customer
+// @Tags Provider - Items
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID (UUID)"
+// @Param request body dto.SubmitRequoteRequest true "Measured weights"
+// @Success 200 {object} dto.LaundryRequoteResponse "Re-quote created"
+// @Router /api/v1/laundry/provider/orders/{id}/requote [post]
+func (h *Handler) SubmitRequote(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User ID not found in context"))
+		return
+	}
+
+	var req dto.SubmitRequoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	requote, err := h.service.SubmitRequote(c, c.Param("id"), userID.(string), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, requote, "Re-quote submitted successfully", "LAUNDRY_REQUOTE_SUBMITTED")
+}
+
+// GetOrderRequotes - GET /api/v1/laundry/orders/:id/requotes
+// @Summary Get Order Re-quotes
+// @Description List the re-quotes submitted for an order, newest first. Available to the order's customer and its provider
+// @Tags Laundry Orders
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "Order ID (UUID)"
+// @Success 200 {array} dto.LaundryRequoteResponse "Re-quotes"
+// @Router /api/v1/laundry/orders/{id}/requotes [get]
+func (h *Handler) GetOrderRequotes(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User ID not found in context"))
+		return
+	}
+
+	requotes, err := h.service.GetOrderRequotes(c, c.Param("id"), userID.(string))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, requotes, "Re-quotes retrieved successfully")
+}
+
+// ApproveRequote - POST /api/v1/laundry/orders/:id/requotes/:requoteId/approve
+// @Summary Approve Re-quote
+// @Description Accept a price increase that needs the customer's approval. The order total and wallet hold move to the new amount
+// @Tags Laundry Orders
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID (UUID)"
+// @Param requoteId path string true "Re-quote ID (UUID)"
+// @Param request body dto.RespondRequoteRequest false "Optional note"
+// @Success 200 {object} dto.LaundryRequoteResponse "Re-quote applied"
+// @Router /api/v1/laundry/orders/{id}/requotes/{requoteId}/approve [post]
+func (h *Handler) ApproveRequote(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User ID not found in context"))
+		return
+	}
+
+	var req dto.RespondRequoteRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+			return
+		}
+	}
+
+	requote, err := h.service.ApproveRequote(c, c.Param("id"), c.Param("requoteId"), userID.(string), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, requote, "Re-quote approved")
+}
+
+// RejectRequote - POST /api/v1/laundry/orders/:id/requotes/:requoteId/reject
+// @Summary Reject Re-quote
+// @Description Turn down a price increase. The order keeps its booked total
+// @Tags Laundry Orders
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID (UUID)"
+// @Param requoteId path string true "Re-quote ID (UUID)"
+// @Param request body dto.RespondRequoteRequest false "Optional note"
+// @Success 200 {object} dto.LaundryRequoteResponse "Re-quote rejected"
+// @Router /api/v1/laundry/orders/{id}/requotes/{requoteId}/reject [post]
+func (h *Handler) RejectRequote(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User ID not found in context"))
+		return
+	}
+
+	var req dto.RespondRequoteRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+			return
+		}
+	}
+
+	requote, err := h.service.RejectRequote(c, c.Param("id"), c.Param("requoteId"), userID.(string), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, requote, "Re-quote rejected")
+}
+
+// RescheduleDelivery - POST /api/v1/laundry/orders/:id/delivery/reschedule
+// @Summary Reschedule Delivery
+// @Description Move the delivery of an order to a new slot. Allowed until shortly before the delivery is due and a limited number of times per order; the provider is notified
+// @Tags Laundry Orders
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID (UUID)"
+// @Param request body dto.RescheduleDeliveryRequest true "New delivery slot"
+// @Success 200 {object} dto.LaundryDeliveryRescheduleResponse "Delivery rescheduled"
+// @Router /api/v1/laundry/orders/{id}/delivery/reschedule [post]
+func (h *Handler) RescheduleDelivery(c *gin.Context) {
+	h.rescheduleDelivery(c, models.LaundryRescheduleByCustomer)
+}
+
+// ProviderRescheduleDelivery - POST /api/v1/laundry/provider/orders/:id/delivery/reschedule
+// @Summary Reschedule Delivery (Provider)
+// @Description Move the delivery of an order to a new slot. Set failedAttempt when the laundry could not be handed over; failed attempts beyond the free ones add a fee to the order. The customer is notified
+// @Tags Provider - Deliveries
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID (UUID)"
+// @Param request body dto.RescheduleDeliveryRequest true "New delivery slot"
+// @Success 200 {object} dto.LaundryDeliveryRescheduleResponse "Delivery rescheduled"
+// @Router /api/v1/laundry/provider/orders/{id}/delivery/reschedule [post]
+func (h *Handler) ProviderRescheduleDelivery(c *gin.Context) {
+	h.rescheduleDelivery(c, models.LaundryRescheduleByProvider)
+}
+
+func (h *Handler) rescheduleDelivery(c *gin.Context, requestedBy string) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User ID not found in context"))
+		return
+	}
+
+	var req dto.RescheduleDeliveryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	reschedule, err := h.service.RescheduleDelivery(c, c.Param("id"), userID.(string), requestedBy, &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, reschedule, "Delivery rescheduled successfully")
+}
+
+// GetDeliveryReschedules - GET /api/v1/laundry/orders/:id/delivery/reschedules
+// @Summary Get Delivery Reschedules
+// @Description The current delivery slot, how often it has been moved and failed, and each move, newest first. Available to the order's customer and its provider
+// @Tags Laundry Orders
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "Order ID (UUID)"
+// @Success 200 {object} dto.DeliveryReschedulesResponse "Delivery reschedules"
+// @Router /api/v1/laundry/orders/{id}/delivery/reschedules [get]
+func (h *Handler) GetDeliveryReschedules(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User ID not found in context"))
+		return
+	}
+
+	reschedules, err := h.service.GetDeliveryReschedules(c, c.Param("id"), userID.(string))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, reschedules, "Delivery reschedules retrieved successfully")
+}
+
+// ScanItem - POST /api/v1/laundry/provider/scan
+// @Summary Scan Item
+// @Description Scan a garment's QR label at a processing station to move it to that station's status. Items only move forward, processing scans require the order to be at the facility and the delivered scan requires the delivery to be under way
+// @Tags Provider - Items
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.ScanItemRequest true "Scanned code and new status"
+// @Success 200 {object} dto.ItemScanResultResponse "Item updated"
+// @Failure 409 {object} response.Response "Item already in that status"
+// @Router /api/v1/laundry/provider/scan [post]
+func (h *Handler) ScanItem(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User ID not found in context"))
+		return
+	}
+
+	var req dto.ScanItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	result, err := h.service.ScanItem(c, userID.(string), &req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Item scanned successfully")
+}
+
+// GetItemScans - GET /api/v1/laundry/items/:qrCode/scans
+// @Summary Get Item Scan History
+// @Description List every scan of a garment's label, oldest first. Available to the order's customer and its provider
+// @Tags Laundry Orders
+// @Security ApiKeyAuth
+// @Produce json
+// @Param qrCode path string true "Item QR Code (e.g., LDY-abc12345)"
+// @Success 200 {object} dto.ItemScanHistoryResponse "Scan history"
+// @Router /api/v1/laundry/items/{qrCode}/scans [get]
+func (h *Handler) GetItemScans(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User ID not found in context"))
+		return
+	}
+
+	history, err := h.service.GetItemScans(c, c.Param("qrCode"), userID.(string))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, history, "Scan history retrieved successfully")
+}
+
+// GetItemLabel - GET /api/v1/laundry/provider/items/:qrCode/label
+// @Summary Get Item Label
+// @Description Download the QR label of a single garment as a PNG
+// @Tags Provider - Items
+// @Security ApiKeyAuth
+// @Produce png
+// @Param qrCode path string true "Item QR Code (e.g., LDY-abc12345)"
+// @Param size query int false "Image size in pixels (128-1024, default 256)"
+// @Success 200 {file} binary "PNG label"
+// @Router /api/v1/laundry/provider/items/{qrCode}/label [get]
+func (h *Handler) GetItemLabel(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User ID not found in context"))
+		return
+	}
+
+	size, _ := strconv.Atoi(c.Query("size"))
+	label, err := h.service.GetItemLabel(c, c.Param("qrCode"), userID.(string), size)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	sendLabel(c, label)
+}
+
+// GetOrderLabels - GET /api/v1/laundry/provider/orders/:id/labels
+// @Summary Get Order Labels
+// @Description Download printable QR labels for all items of an order, as an A4 PDF sheet (default) or a single PNG
+// @Tags Provider - Items
+// @Security ApiKeyAuth
+// @Produce application/pdf,png
+// @Param id path string true "Order ID (UUID)"
+// @Param format query string false "pdf or png" Enums(pdf, png)
+// @Param size query int false "Label size in pixels for png (128-1024, default 256)"
+// @Success 200 {file} binary "Label sheet"
+// @Router /api/v1/laundry/provider/orders/{id}/labels [get]
+func (h *Handler) GetOrderLabels(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User ID not found in context"))
+		return
+	}
+
+	size, _ := strconv.Atoi(c.Query("size"))
+	label, err := h.service.GetOrderLabels(c, c.Param("id"), userID.(string), c.Query("format"), size)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	sendLabel(c, label)
+}
+
+func sendLabel(c *gin.Context, label *LabelFile) {
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", label.FileName))
+	c.Data(http.StatusOK, label.ContentType, label.Data)
+}
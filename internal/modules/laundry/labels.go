@@ -0,0 +1,149 @@
+package laundry
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+
+	"github.com/skip2/go-qrcode"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/utils/pdf"
+)
+
+const (
+	LabelFormatPNG = "png"
+	LabelFormatPDF = "pdf"
+
+	defaultLabelSize = 256
+	minLabelSize     = 128
+	maxLabelSize     = 1024
+)
+
+// LabelFile is a rendered label or label sheet ready to be sent as a download.
+type LabelFile struct {
+	ContentType string
+	FileName    string
+	Data        []byte
+}
+
+func clampLabelSize(size int) int {
+	if size <= 0 {
+		return defaultLabelSize
+	}
+	if size < minLabelSize {
+		return minLabelSize
+	}
+	if size > maxLabelSize {
+		return maxLabelSize
+	}
+	return size
+}
+
+func itemLabelPNG(item *models.LaundryOrderItem, size int) (*LabelFile, error) {
+	data, err := qrcode.Encode(item.QRCode, qrcode.Medium, clampLabelSize(size))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode QR code: %w", err)
+	}
+	return &LabelFile{
+		ContentType: "image/png",
+		FileName:    item.QRCode + ".png",
+		Data:        data,
+	}, nil
+}
+
+// orderLabelsPNG lays the QR codes of an order's items out on one image, four
+// to a row, in the order the items were added.
+func orderLabelsPNG(order *models.LaundryOrder, items []*models.LaundryOrderItem, size int) (*LabelFile, error) {
+	const perRow = 4
+	size = clampLabelSize(size)
+	gap := size / 8
+
+	rows := (len(items) + perRow - 1) / perRow
+	cols := perRow
+	if len(items) < perRow {
+		cols = len(items)
+	}
+	sheet := image.NewRGBA(image.Rect(0, 0, cols*size+(cols+1)*gap, rows*size+(rows+1)*gap))
+	draw.Draw(sheet, sheet.Bounds(), &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+
+	for i, item := range items {
+		code, err := qrcode.New(item.QRCode, qrcode.Medium)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode QR code %s: %w", item.QRCode, err)
+		}
+		x := gap + (i%perRow)*(size+gap)
+		y := gap + (i/perRow)*(size+gap)
+		draw.Draw(sheet, image.Rect(x, y, x+size, y+size), code.Image(size), image.Point{}, draw.Src)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, sheet); err != nil {
+		return nil, fmt.Errorf("failed to encode label sheet: %w", err)
+	}
+	return &LabelFile{
+		ContentType: "image/png",
+		FileName:    order.OrderNumber + "-labels.png",
+		Data:        buf.Bytes(),
+	}, nil
+}
+
+// Labels are laid out 3 x 6 to an A4 page, sizes in PDF points.
+const (
+	pdfLabelCols   = 3
+	pdfLabelRows   = 6
+	pdfLabelWidth  = 180.0
+	pdfLabelHeight = 130.0
+	pdfQRSize      = 80.0
+)
+
+// orderLabelsPDF renders printable labels for an order's items: the QR code
+// with the order number, item type and code under it, one cell per item on
+// as many pages as needed. QR modules are drawn as filled squares so the
+// document needs no embedded images.
+func orderLabelsPDF(order *models.LaundryOrder, items []*models.LaundryOrderItem) (*LabelFile, error) {
+	perPage := pdfLabelCols * pdfLabelRows
+	marginX := (pdf.PageWidth - pdfLabelCols*pdfLabelWidth) / 2
+	marginY := (pdf.PageHeight - pdfLabelRows*pdfLabelHeight) / 2
+
+	doc := pdf.NewDocument()
+	for i, item := range items {
+		if i > 0 && i%perPage == 0 {
+			doc.NewPage()
+		}
+
+		code, err := qrcode.New(item.QRCode, qrcode.Medium)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode QR code %s: %w", item.QRCode, err)
+		}
+
+		cell := i % perPage
+		x := marginX + float64(cell%pdfLabelCols)*pdfLabelWidth
+		y := marginY + float64(cell/pdfLabelCols)*pdfLabelHeight
+		doc.Rect(x, y, pdfLabelWidth, pdfLabelHeight)
+
+		bitmap := code.Bitmap()
+		module := pdfQRSize / float64(len(bitmap))
+		qrX, qrY := x+(pdfLabelWidth-pdfQRSize)/2, y+8
+		for r, line := range bitmap {
+			for c, dark := range line {
+				if dark {
+					doc.FillRect(qrX+float64(c)*module, qrY+float64(r)*module, module, module)
+				}
+			}
+		}
+
+		textY := qrY + pdfQRSize + 12
+		doc.Text(x+10, textY, 8, true, order.OrderNumber)
+		doc.Text(x+10, textY+10, 8, false, pdf.Truncate(fmt.Sprintf("%s (%d of %d)", item.ItemType, i+1, len(items)), 36))
+		doc.Text(x+10, textY+20, 8, false, item.QRCode)
+	}
+
+	return &LabelFile{
+		ContentType: "application/pdf",
+		FileName:    order.OrderNumber + "-labels.pdf",
+		Data:        doc.Bytes(),
+	}, nil
+}
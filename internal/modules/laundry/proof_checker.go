@@ -0,0 +1,13 @@
+package laundry
+
+import "context"
+
+// ProofChecker reports whether an order has the proof of service its
+// category requires. It is satisfied by proofs.Service.
+type ProofChecker interface {
+	CheckProofs(ctx context.Context, orderType, orderID, categorySlug string) error
+}
+
+func (s *service) SetProofChecker(checker ProofChecker) {
+	s.proofChecker = checker
+}
@@ -29,6 +29,8 @@ type Repository interface {
 	GetDeliveryByOrder(ctx context.Context, orderID string) (*models.LaundryDelivery, error)
 	UpdateDeliveryStatus(ctx context.Context, orderID, status string, deliveredAt *time.Time) error
 	GetDeliveriesByProvider(ctx context.Context, providerID string, statuses []string) ([]*models.LaundryDelivery, error)
+	RescheduleDelivery(ctx context.Context, order *models.LaundryOrder, delivery *models.LaundryDelivery, reschedule *models.LaundryDeliveryReschedule) error
+	GetDeliveryReschedules(ctx context.Context, orderID string) ([]*models.LaundryDeliveryReschedule, error)
 
 	CreateItems(ctx context.Context, items []*models.LaundryOrderItem) error
 	GetOrderItems(ctx context.Context, orderID string) ([]*models.LaundryOrderItem, error)
@@ -43,6 +45,18 @@ type Repository interface {
 	GetServicesWithProducts(ctx context.Context) ([]*models.LaundryServiceCatalog, error)
 	GetServiceProducts(ctx context.Context, serviceSlug string) ([]*models.LaundryServiceProduct, error)
 	GetProductBySlug(ctx context.Context, serviceSlug, productSlug string) (*models.LaundryServiceProduct, error)
+
+	SetOrderWalletHold(ctx context.Context, orderID string, holdID *string) error
+
+	CreateRequote(ctx context.Context, requote *models.LaundryRequote) error
+	UpdateRequote(ctx context.Context, requote *models.LaundryRequote) error
+	GetRequote(ctx context.Context, orderID, requoteID string) (*models.LaundryRequote, error)
+	GetAwaitingRequote(ctx context.Context, orderID string) (*models.LaundryRequote, error)
+	GetOrderRequotes(ctx context.Context, orderID string) ([]*models.LaundryRequote, error)
+	ApplyRequote(ctx context.Context, order *models.LaundryOrder, requote *models.LaundryRequote) error
+
+	RecordItemScan(ctx context.Context, item *models.LaundryOrderItem, scan *models.LaundryItemScan) error
+	GetItemScans(ctx context.Context, itemID string) ([]*models.LaundryItemScan, error)
 }
 
 
@@ -199,6 +213,60 @@ func (r *repository) GetDeliveriesByProvider(ctx context.Context, providerID str
 	return deliveries, err
 }
 
+// RescheduleDelivery moves the delivery to the reschedule's new slot, counts
+// the move and any failed attempt, and records the reschedule. A fee is added
+// to the order total in the same transaction. It returns
+// gorm.ErrRecordNotFound if the delivery was rescheduled in the meantime.
+func (r *repository) RescheduleDelivery(ctx context.Context, order *models.LaundryOrder, delivery *models.LaundryDelivery, reschedule *models.LaundryDeliveryReschedule) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		updates := map[string]interface{}{
+			"scheduled_at":     reschedule.NewScheduledAt,
+			"status":           "scheduled",
+			"arrived_at":       nil,
+			"reschedule_count": gorm.Expr("reschedule_count + 1"),
+			"updated_at":       now,
+		}
+		if reschedule.FailedAttempt {
+			updates["failed_attempts"] = gorm.Expr("failed_attempts + 1")
+		}
+
+		result := tx.Model(&models.LaundryDelivery{}).
+			Where("id = ? AND reschedule_count = ?", delivery.ID, delivery.RescheduleCount).
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if reschedule.Fee > 0 {
+			if err := tx.Model(&models.LaundryOrder{}).
+				Where("id = ?", order.ID).
+				Updates(map[string]interface{}{
+					"total":          order.Total,
+					"wallet_hold_id": order.WalletHoldID,
+					"version":        gorm.Expr("version + 1"),
+					"updated_at":     now,
+				}).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Create(reschedule).Error
+	})
+}
+
+func (r *repository) GetDeliveryReschedules(ctx context.Context, orderID string) ([]*models.LaundryDeliveryReschedule, error) {
+	var reschedules []*models.LaundryDeliveryReschedule
+	err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("created_at DESC").
+		Find(&reschedules).Error
+	return reschedules, err
+}
+
 func (r *repository) CreateItems(ctx context.Context, items []*models.LaundryOrderItem) error {
 	return r.db.WithContext(ctx).Create(&items).Error
 }
@@ -213,9 +281,15 @@ func (r *repository) GetOrderItems(ctx context.Context, orderID string) ([]*mode
 }
 
 func (r *repository) UpdateItemStatus(ctx context.Context, qrCode, status string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.LaundryOrderItem{}).
+		Where("qr_code = ?", qrCode).
+		Updates(itemStatusUpdates(status, time.Now())).Error
+}
+
+func itemStatusUpdates(status string, now time.Time) map[string]interface{} {
 	updates := map[string]interface{}{"status": status}
 
-	now := time.Now()
 	switch status {
 	case "received":
 		updates["received_at"] = now
@@ -224,11 +298,7 @@ func (r *repository) UpdateItemStatus(ctx context.Context, qrCode, status string
 	case "delivered":
 		updates["delivered_at"] = now
 	}
-
-	return r.db.WithContext(ctx).
-		Model(&models.LaundryOrderItem{}).
-		Where("qr_code = ?", qrCode).
-		Updates(updates).Error
+	return updates
 }
 
 func (r *repository) GetItemByQRCode(ctx context.Context, qrCode string) (*models.LaundryOrderItem, error) {
@@ -346,3 +416,112 @@ func (r *repository) GetAvailableOrdersByCategory(ctx context.Context, category
 		Find(&orders).Error
 	return orders, err
 }
+
+func (r *repository) SetOrderWalletHold(ctx context.Context, orderID string, holdID *string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.LaundryOrder{}).
+		Where("id = ?", orderID).
+		Updates(map[string]interface{}{
+			"wallet_hold_id": holdID,
+			"version":        gorm.Expr("version + 1"),
+		}).Error
+}
+
+func (r *repository) CreateRequote(ctx context.Context, requote *models.LaundryRequote) error {
+	return r.db.WithContext(ctx).Create(requote).Error
+}
+
+func (r *repository) UpdateRequote(ctx context.Context, requote *models.LaundryRequote) error {
+	return r.db.WithContext(ctx).Save(requote).Error
+}
+
+func (r *repository) GetRequote(ctx context.Context, orderID, requoteID string) (*models.LaundryRequote, error) {
+	var requote models.LaundryRequote
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND order_id = ?", requoteID, orderID).
+		First(&requote).Error
+	if err != nil {
+		return nil, err
+	}
+	return &requote, nil
+}
+
+func (r *repository) GetAwaitingRequote(ctx context.Context, orderID string) (*models.LaundryRequote, error) {
+	var requote models.LaundryRequote
+	err := r.db.WithContext(ctx).
+		Where("order_id = ? AND status = ?", orderID, models.LaundryRequoteAwaitingApproval).
+		First(&requote).Error
+	if err != nil {
+		return nil, err
+	}
+	return &requote, nil
+}
+
+func (r *repository) GetOrderRequotes(ctx context.Context, orderID string) ([]*models.LaundryRequote, error) {
+	var requotes []*models.LaundryRequote
+	err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("created_at DESC").
+		Find(&requotes).Error
+	return requotes, err
+}
+
+// ApplyRequote writes the re-weighed prices to the order's items and its new
+// total in one transaction, and saves the requote.
+func (r *repository) ApplyRequote(ctx context.Context, order *models.LaundryOrder, requote *models.LaundryRequote) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		for _, line := range requote.Lines {
+			if err := tx.Model(&models.LaundryOrderItem{}).
+				Where("id = ? AND order_id = ?", line.ItemID, order.ID).
+				Updates(map[string]interface{}{
+					"measured_weight": line.MeasuredWeight,
+					"price":           line.NewPrice,
+					"updated_at":      now,
+				}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Model(&models.LaundryOrder{}).
+			Where("id = ?", order.ID).
+			Updates(map[string]interface{}{
+				"total":          order.Total,
+				"wallet_hold_id": order.WalletHoldID,
+				"version":        gorm.Expr("version + 1"),
+				"updated_at":     now,
+			}).Error; err != nil {
+			return err
+		}
+
+		return tx.Save(requote).Error
+	})
+}
+
+// RecordItemScan moves the item to the scanned status and stores the scan in
+// one transaction. The status update only applies while the item is still in
+// the status the scan was validated against, so concurrent scans of the same
+// label cannot both succeed.
+func (r *repository) RecordItemScan(ctx context.Context, item *models.LaundryOrderItem, scan *models.LaundryItemScan) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.LaundryOrderItem{}).
+			Where("id = ? AND status = ?", item.ID, scan.FromStatus).
+			Updates(itemStatusUpdates(scan.ToStatus, scan.CreatedAt))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Create(scan).Error
+	})
+}
+
+func (r *repository) GetItemScans(ctx context.Context, itemID string) ([]*models.LaundryItemScan, error) {
+	var scans []*models.LaundryItemScan
+	err := r.db.WithContext(ctx).
+		Where("item_id = ?", itemID).
+		Order("created_at ASC").
+		Find(&scans).Error
+	return scans, err
+}
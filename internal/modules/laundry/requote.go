@@ -0,0 +1,411 @@
+package laundry
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/laundry/dto"
+	walletdto "github.com/umar5678/go-backend/internal/modules/wallet/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+	websocketutil "github.com/umar5678/go-backend/internal/websocket/websocketutils"
+	"gorm.io/gorm"
+)
+
+const laundryHoldReference = "laundry_order"
+
+// Order statuses in which the items are at the facility: they can be
+// re-weighed and scanned through processing.
+var facilityOrderStatuses = map[string]bool{
+	"pickup_completed": true,
+	"processing":       true,
+}
+
+func (s *service) ConfigureRequotes(cfg config.LaundryRequoteConfig) {
+	s.requotes = cfg
+}
+
+// itemPrice prices a line of a laundry order. Per-kg services charge for the
+// measured weight once there is one and for the booked quantity until then;
+// other services price by the piece and are unaffected by weight.
+func itemPrice(service *models.LaundryServiceCatalog, product *models.LaundryServiceProduct, quantity int, measuredWeight *float64) float64 {
+	price := 0.0
+	if service.PricingUnit == "kg" {
+		units := float64(quantity)
+		if measuredWeight != nil {
+			units = *measuredWeight
+		}
+		if product.Price != nil {
+			price = *product.Price * units
+		}
+	} else {
+		unit := service.BasePrice
+		if product.Price != nil {
+			unit += *product.Price
+		}
+		price = unit * float64(quantity)
+	}
+
+	if product.RequiresSpecialCare {
+		price += product.SpecialCareFee * float64(quantity)
+	}
+	return price
+}
+
+func roundToCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// holdOrderTotal reserves the order total in the customer's wallet until a
+// day after the scheduled delivery, when it is captured. Orders whose hold
+// cannot be placed go ahead without one.
+func (s *service) holdOrderTotal(ctx context.Context, order *models.LaundryOrder, until time.Time) {
+	if order.UserID == nil || order.Total <= 0 {
+		return
+	}
+
+	holdID, err := s.placeHold(ctx, *order.UserID, order.ID, order.Total, until.Add(24*time.Hour))
+	if err != nil {
+		logger.Error("failed to hold laundry order total", "error", err, "orderID", order.ID)
+		return
+	}
+	if err := s.repo.SetOrderWalletHold(ctx, order.ID, &holdID); err != nil {
+		logger.Error("failed to save laundry order hold", "error", err, "orderID", order.ID, "holdID", holdID)
+		return
+	}
+	order.WalletHoldID = &holdID
+}
+
+func (s *service) placeHold(ctx context.Context, customerID, orderID string, amount float64, until time.Time) (string, error) {
+	hold, err := s.walletService.HoldFunds(ctx, customerID, walletdto.HoldFundsRequest{
+		Amount:        amount,
+		ReferenceType: laundryHoldReference,
+		ReferenceID:   orderID,
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := s.walletService.ExtendHold(ctx, customerID, hold.ID, until); err != nil {
+		logger.Warn("failed to extend laundry order hold", "error", err, "orderID", orderID, "holdID", hold.ID)
+	}
+	return hold.ID, nil
+}
+
+// SubmitRequote reprices an order from the weights the facility measured.
+// Decreases and increases up to the configured share of the total are
+// applied at once; larger increases wait for the customer to approve them.
+func (s *service) SubmitRequote(ctx context.Context, orderID, providerUserID string, req *dto.SubmitRequoteRequest) (*dto.LaundryRequoteResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	order, err := s.GetOrderWithDetails(ctx, orderID)
+	if err != nil {
+		return nil, response.NotFoundError("Order")
+	}
+	if !s.isOrderProvider(ctx, order, providerUserID) {
+		return nil, response.ForbiddenError("You are not assigned to this order")
+	}
+	if !facilityOrderStatuses[order.Status] {
+		return nil, response.BadRequest(fmt.Sprintf("Cannot re-weigh an order in '%s' status", order.Status))
+	}
+	if _, err := s.repo.GetAwaitingRequote(ctx, order.ID); err == nil {
+		return nil, response.ConflictError("A re-quote for this order is already waiting for the customer")
+	}
+
+	items, err := s.repo.GetOrderItems(ctx, order.ID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get order items", err)
+	}
+	itemsByID := make(map[string]*models.LaundryOrderItem, len(items))
+	for _, item := range items {
+		itemsByID[item.ID] = item
+	}
+
+	lines := make(models.LaundryRequoteLines, 0, len(req.Items))
+	difference := 0.0
+	for _, measured := range req.Items {
+		item, ok := itemsByID[measured.ItemID]
+		if !ok {
+			return nil, response.BadRequest(fmt.Sprintf("Item %s is not part of this order", measured.ItemID))
+		}
+
+		service, err := s.repo.GetServiceBySlug(ctx, item.ServiceSlug)
+		if err != nil || service == nil {
+			return nil, response.BadRequest(fmt.Sprintf("Service '%s' is no longer available", item.ServiceSlug))
+		}
+		product, err := s.repo.GetProductBySlug(ctx, item.ServiceSlug, item.ProductSlug)
+		if err != nil {
+			return nil, response.BadRequest(fmt.Sprintf("Product '%s' is no longer available", item.ProductSlug))
+		}
+
+		weight := measured.MeasuredWeight
+		newPrice := roundToCents(itemPrice(service, product, item.Quantity, &weight))
+		lines = append(lines, models.LaundryRequoteLine{
+			ItemID:         item.ID,
+			ProductSlug:    item.ProductSlug,
+			Quantity:       item.Quantity,
+			BookedWeight:   item.Weight,
+			MeasuredWeight: weight,
+			PreviousPrice:  item.Price,
+			NewPrice:       newPrice,
+		})
+		difference += newPrice - item.Price
+	}
+
+	requote := &models.LaundryRequote{
+		OrderID:       order.ID,
+		ProviderID:    providerUserID,
+		Lines:         lines,
+		PreviousTotal: order.Total,
+		NewTotal:      roundToCents(order.Total + difference),
+		Difference:    roundToCents(difference),
+		Note:          req.Note,
+	}
+	if requote.NewTotal < 0 {
+		requote.NewTotal = 0
+		requote.Difference = -order.Total
+	}
+
+	if s.needsApproval(requote) {
+		requote.Status = models.LaundryRequoteAwaitingApproval
+		if err := s.repo.CreateRequote(ctx, requote); err != nil {
+			return nil, response.InternalServerError("Failed to save re-quote", err)
+		}
+
+		s.notifyCustomer(order, websocket.TypeLaundryRequoteApprovalRequired, requote)
+		logger.Info("laundry re-quote awaiting approval", "orderID", order.ID, "requoteID", requote.ID,
+			"previousTotal", requote.PreviousTotal, "newTotal", requote.NewTotal)
+
+		return dto.ToLaundryRequoteResponse(requote), nil
+	}
+
+	requote.Status = models.LaundryRequoteApplied
+	if err := s.repo.CreateRequote(ctx, requote); err != nil {
+		return nil, response.InternalServerError("Failed to save re-quote", err)
+	}
+	if err := s.applyRequote(ctx, order, requote); err != nil {
+		requote.Status = models.LaundryRequoteRejected
+		if updateErr := s.repo.UpdateRequote(ctx, requote); updateErr != nil {
+			logger.Error("failed to reject unapplied laundry re-quote", "error", updateErr, "requoteID", requote.ID)
+		}
+		return nil, err
+	}
+
+	s.notifyCustomer(order, websocket.TypeLaundryRequoteApplied, requote)
+	return dto.ToLaundryRequoteResponse(requote), nil
+}
+
+func (s *service) needsApproval(requote *models.LaundryRequote) bool {
+	if requote.Difference <= 0 {
+		return false
+	}
+	percent := s.requotes.ApprovalPercent
+	if percent <= 0 {
+		percent = 10
+	}
+	return requote.Difference > requote.PreviousTotal*percent/100
+}
+
+func (s *service) GetOrderRequotes(ctx context.Context, orderID, userID string) ([]*dto.LaundryRequoteResponse, error) {
+	order, err := s.GetOrderWithDetails(ctx, orderID)
+	if err != nil {
+		return nil, response.NotFoundError("Order")
+	}
+	isCustomer := order.UserID != nil && *order.UserID == userID
+	if !isCustomer && !s.isOrderProvider(ctx, order, userID) {
+		return nil, response.NotFoundError("Order")
+	}
+
+	requotes, err := s.repo.GetOrderRequotes(ctx, order.ID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get re-quotes", err)
+	}
+	return dto.ToLaundryRequoteResponses(requotes), nil
+}
+
+// ApproveRequote accepts an increase that was too large to apply without the
+// customer's consent.
+func (s *service) ApproveRequote(ctx context.Context, orderID, requoteID, customerID string, req *dto.RespondRequoteRequest) (*dto.LaundryRequoteResponse, error) {
+	order, requote, err := s.loadAwaitingRequote(ctx, orderID, requoteID, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	requote.Status = models.LaundryRequoteApplied
+	requote.CustomerNote = req.Note
+	requote.RespondedAt = &now
+	if err := s.applyRequote(ctx, order, requote); err != nil {
+		return nil, err
+	}
+
+	s.notifyProvider(ctx, order, websocket.TypeLaundryRequoteApplied, requote)
+	return dto.ToLaundryRequoteResponse(requote), nil
+}
+
+// RejectRequote turns down an increase; the order keeps its booked total.
+func (s *service) RejectRequote(ctx context.Context, orderID, requoteID, customerID string, req *dto.RespondRequoteRequest) (*dto.LaundryRequoteResponse, error) {
+	order, requote, err := s.loadAwaitingRequote(ctx, orderID, requoteID, customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	requote.Status = models.LaundryRequoteRejected
+	requote.CustomerNote = req.Note
+	requote.RespondedAt = &now
+	if err := s.repo.UpdateRequote(ctx, requote); err != nil {
+		return nil, response.InternalServerError("Failed to reject re-quote", err)
+	}
+
+	s.notifyProvider(ctx, order, websocket.TypeLaundryRequoteRejected, requote)
+	logger.Info("laundry re-quote rejected", "orderID", order.ID, "requoteID", requote.ID)
+
+	return dto.ToLaundryRequoteResponse(requote), nil
+}
+
+func (s *service) loadAwaitingRequote(ctx context.Context, orderID, requoteID, customerID string) (*models.LaundryOrder, *models.LaundryRequote, error) {
+	order, err := s.GetOrderWithDetails(ctx, orderID)
+	if err != nil || order.UserID == nil || *order.UserID != customerID {
+		return nil, nil, response.NotFoundError("Order")
+	}
+
+	requote, err := s.repo.GetRequote(ctx, order.ID, requoteID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, response.NotFoundError("Re-quote")
+		}
+		return nil, nil, response.InternalServerError("Failed to get re-quote", err)
+	}
+	if requote.Status != models.LaundryRequoteAwaitingApproval {
+		return nil, nil, response.BadRequest("This re-quote is not waiting for approval")
+	}
+	return order, requote, nil
+}
+
+// applyRequote moves the order to its new total. The wallet hold is replaced
+// by one for the new total, which charges an increase or gives back a
+// decrease when the hold is captured on delivery. An increase the customer's
+// wallet cannot cover is not applied.
+func (s *service) applyRequote(ctx context.Context, order *models.LaundryOrder, requote *models.LaundryRequote) error {
+	complete, revert := func() {}, func() {}
+	if order.WalletHoldID != nil && order.UserID != nil && requote.Difference != 0 {
+		var err error
+		if complete, revert, err = s.replaceHold(ctx, order, requote.NewTotal); err != nil {
+			return err
+		}
+	}
+
+	order.Total = requote.NewTotal
+	if err := s.repo.ApplyRequote(ctx, order, requote); err != nil {
+		logger.Error("failed to apply laundry re-quote", "error", err, "orderID", order.ID, "requoteID", requote.ID)
+		revert()
+		order.Total = requote.PreviousTotal
+		return response.InternalServerError("Failed to apply re-quote", err)
+	}
+	complete()
+
+	logger.Info("laundry re-quote applied", "orderID", order.ID, "requoteID", requote.ID,
+		"previousTotal", requote.PreviousTotal, "newTotal", requote.NewTotal, "difference", requote.Difference)
+	return nil
+}
+
+// replaceHold points the order at a new hold for amount with the old hold's
+// expiry. The old hold keeps reserving the funds until complete is called
+// once the order is saved; revert releases the new hold and points the order
+// back at the old one. If the new hold cannot be placed the order is left
+// as it was.
+func (s *service) replaceHold(ctx context.Context, order *models.LaundryOrder, amount float64) (complete, revert func(), err error) {
+	customerID := *order.UserID
+	oldHoldID := *order.WalletHoldID
+
+	until := time.Now().Add(72 * time.Hour)
+	if active, err := s.walletService.GetActiveHolds(ctx, customerID); err == nil {
+		for _, hold := range active.Holds {
+			if hold.ID == oldHoldID {
+				until = hold.ExpiresAt
+			}
+		}
+	}
+
+	var newHoldID *string
+	if amount > 0 {
+		holdID, err := s.placeHold(ctx, customerID, order.ID, amount, until)
+		if err != nil {
+			logger.Warn("failed to hold new laundry order total", "error", err, "orderID", order.ID, "amount", amount)
+			return nil, nil, response.BadRequest(fmt.Sprintf("The wallet balance does not cover the new order total of %.2f", amount))
+		}
+		newHoldID = &holdID
+	}
+	order.WalletHoldID = newHoldID
+
+	complete = func() {
+		if err := s.walletService.ReleaseHold(ctx, customerID, walletdto.ReleaseHoldRequest{HoldID: oldHoldID}); err != nil {
+			logger.Error("failed to release replaced laundry order hold", "error", err, "orderID", order.ID, "holdID", oldHoldID)
+		}
+	}
+	revert = func() {
+		order.WalletHoldID = &oldHoldID
+		if newHoldID == nil {
+			return
+		}
+		if err := s.walletService.ReleaseHold(ctx, customerID, walletdto.ReleaseHoldRequest{HoldID: *newHoldID}); err != nil {
+			logger.Error("failed to release new laundry order hold", "error", err, "orderID", order.ID, "holdID", *newHoldID)
+		}
+	}
+	return complete, revert, nil
+}
+
+// isOrderProvider reports whether the user is the provider assigned to the
+// order. Orders store the provider profile ID.
+func (s *service) isOrderProvider(ctx context.Context, order *models.LaundryOrder, userID string) bool {
+	if order.ProviderID == nil {
+		return false
+	}
+	if *order.ProviderID == userID {
+		return true
+	}
+	provider, err := s.repo.GetProviderByID(ctx, *order.ProviderID)
+	return err == nil && provider != nil && provider.UserID == userID
+}
+
+func (s *service) notifyCustomer(order *models.LaundryOrder, messageType websocket.MessageType, requote *models.LaundryRequote) {
+	if order.UserID == nil {
+		return
+	}
+	if err := websocketutil.SendToUser(*order.UserID, messageType, requoteNotification(order, requote)); err != nil {
+		logger.Warn("failed to notify customer of re-quote", "error", err, "orderID", order.ID)
+	}
+}
+
+func (s *service) notifyProvider(ctx context.Context, order *models.LaundryOrder, messageType websocket.MessageType, requote *models.LaundryRequote) {
+	userID := requote.ProviderID
+	if order.ProviderID != nil {
+		if provider, err := s.repo.GetProviderByID(ctx, *order.ProviderID); err == nil && provider != nil {
+			userID = provider.UserID
+		}
+	}
+	if err := websocketutil.SendToUser(userID, messageType, requoteNotification(order, requote)); err != nil {
+		logger.Warn("failed to notify provider of re-quote", "error", err, "orderID", order.ID)
+	}
+}
+
+func requoteNotification(order *models.LaundryOrder, requote *models.LaundryRequote) map[string]interface{} {
+	return map[string]interface{}{
+		"orderId":       order.ID,
+		"orderNumber":   order.OrderNumber,
+		"requoteId":     requote.ID,
+		"status":        requote.Status,
+		"previousTotal": requote.PreviousTotal,
+		"newTotal":      requote.NewTotal,
+		"difference":    requote.Difference,
+		"lines":         requote.Lines,
+		"note":          requote.Note,
+	}
+}
@@ -0,0 +1,255 @@
+package laundry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/laundry/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+	websocketutil "github.com/umar5678/go-backend/internal/websocket/websocketutils"
+	"gorm.io/gorm"
+)
+
+// Delivery statuses in which the delivery has not been handed over yet and
+// can still be moved.
+var reschedulableDeliveryStatuses = map[string]bool{
+	"scheduled": true,
+	"en_route":  true,
+	"arrived":   true,
+}
+
+func (s *service) ConfigureDeliveries(cfg config.LaundryDeliveryConfig) {
+	s.deliveries = cfg
+}
+
+// deliveryRules returns the delivery config with defaults filled in for
+// services that were never configured.
+func (s *service) deliveryRules() config.LaundryDeliveryConfig {
+	cfg := s.deliveries
+	if cfg.MaxReschedules <= 0 {
+		cfg.MaxReschedules = 3
+	}
+	if cfg.MinNotice <= 0 {
+		cfg.MinNotice = 2 * time.Hour
+	}
+	if cfg.MaxAhead <= 0 {
+		cfg.MaxAhead = 14 * 24 * time.Hour
+	}
+	if cfg.SlotMinutes <= 0 {
+		cfg.SlotMinutes = 30
+	}
+	if cfg.SlotEndHour <= cfg.SlotStartHour {
+		cfg.SlotStartHour, cfg.SlotEndHour = 8, 21
+	}
+	return cfg
+}
+
+// RescheduleDelivery moves an order's delivery to a new slot. Customers can
+// move it until shortly before it is due; providers can move it at any point
+// before handing the laundry over, and mark the move as a failed attempt when
+// they could not. Failed attempts beyond the free ones add a fee to the order.
+func (s *service) RescheduleDelivery(ctx context.Context, orderID, userID, requestedBy string, req *dto.RescheduleDeliveryRequest) (*dto.LaundryDeliveryRescheduleResponse, error) {
+	cfg := s.deliveryRules()
+	now := time.Now()
+
+	order, err := s.GetOrderWithDetails(ctx, orderID)
+	if err != nil {
+		return nil, response.NotFoundError("Order")
+	}
+	if requestedBy == models.LaundryRescheduleByProvider {
+		if !s.isOrderProvider(ctx, order, userID) {
+			return nil, response.ForbiddenError("You are not assigned to this order")
+		}
+	} else if order.UserID == nil || *order.UserID != userID {
+		return nil, response.NotFoundError("Order")
+	}
+	if order.Status == "completed" || order.Status == "cancelled" {
+		return nil, response.BadRequest(fmt.Sprintf("Cannot reschedule the delivery of an order in '%s' status", order.Status))
+	}
+
+	delivery, err := s.repo.GetDeliveryByOrder(ctx, order.ID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get delivery", err)
+	}
+	if delivery == nil {
+		return nil, response.NotFoundError("Delivery")
+	}
+	if !reschedulableDeliveryStatuses[delivery.Status] {
+		return nil, response.BadRequest(fmt.Sprintf("Cannot reschedule a delivery in '%s' status", delivery.Status))
+	}
+	if delivery.RescheduleCount >= cfg.MaxReschedules {
+		return nil, response.BadRequest(fmt.Sprintf("This delivery has already been rescheduled %d times; please contact support", delivery.RescheduleCount))
+	}
+
+	failedAttempt := requestedBy == models.LaundryRescheduleByProvider && req.FailedAttempt
+	if failedAttempt && delivery.Status == "scheduled" {
+		return nil, response.BadRequest("A failed attempt can only be recorded once the delivery has been started")
+	}
+	if requestedBy == models.LaundryRescheduleByCustomer {
+		if delivery.Status != "scheduled" {
+			return nil, response.BadRequest("The provider is already on the way; the delivery can no longer be moved")
+		}
+		if delivery.ScheduledAt.Sub(now) < cfg.MinNotice {
+			return nil, response.BadRequest(fmt.Sprintf("Deliveries can only be rescheduled up to %s before they are due", formatNotice(cfg.MinNotice)))
+		}
+	}
+	if err := validateDeliverySlot(req.ScheduledAt, delivery.ScheduledAt, cfg, now); err != nil {
+		return nil, err
+	}
+
+	reschedule := &models.LaundryDeliveryReschedule{
+		OrderID:             order.ID,
+		DeliveryID:          delivery.ID,
+		RequestedBy:         requestedBy,
+		RequestedByID:       userID,
+		PreviousScheduledAt: delivery.ScheduledAt,
+		NewScheduledAt:      req.ScheduledAt,
+		Reason:              req.Reason,
+		FailedAttempt:       failedAttempt,
+	}
+	// The old hold is only released once the reschedule is saved, so the
+	// order is never left without one.
+	complete, revert := func() {}, func() {}
+	if failedAttempt && delivery.FailedAttempts >= cfg.FreeFailedAttempts && cfg.FailedAttemptFee > 0 {
+		reschedule.Fee = roundToCents(cfg.FailedAttemptFee)
+		order.Total = roundToCents(order.Total + reschedule.Fee)
+		if order.WalletHoldID != nil && order.UserID != nil {
+			if complete, revert, err = s.replaceHold(ctx, order, order.Total); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := s.repo.RescheduleDelivery(ctx, order, delivery, reschedule); err != nil {
+		revert()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ConflictError("The delivery was rescheduled in the meantime; please try again")
+		}
+		logger.Error("failed to reschedule laundry delivery", "error", err, "orderID", order.ID)
+		return nil, response.InternalServerError("Failed to reschedule delivery", err)
+	}
+	complete()
+
+	logger.Info("laundry delivery rescheduled", "orderID", order.ID, "requestedBy", requestedBy,
+		"from", reschedule.PreviousScheduledAt, "to", reschedule.NewScheduledAt,
+		"failedAttempt", failedAttempt, "fee", reschedule.Fee)
+
+	s.notifyDeliveryRescheduled(ctx, order, delivery, reschedule)
+	return dto.ToLaundryDeliveryRescheduleResponse(reschedule), nil
+}
+
+// validateDeliverySlot checks that a delivery may be moved to at: a slot
+// boundary within delivery hours, far enough ahead but not too far, and not
+// the slot it already has.
+func validateDeliverySlot(at, current time.Time, cfg config.LaundryDeliveryConfig, now time.Time) error {
+	if at.Sub(now) < cfg.MinNotice {
+		return response.BadRequest(fmt.Sprintf("The new delivery time must be at least %s from now", formatNotice(cfg.MinNotice)))
+	}
+	if at.After(now.Add(cfg.MaxAhead)) {
+		return response.BadRequest(fmt.Sprintf("The new delivery time must be within %d days", int(cfg.MaxAhead.Hours()/24)))
+	}
+
+	slot := at.UTC()
+	minute := slot.Hour()*60 + slot.Minute()
+	if slot.Second() != 0 || slot.Nanosecond() != 0 || minute%cfg.SlotMinutes != 0 {
+		return response.BadRequest(fmt.Sprintf("Deliveries are booked in %d-minute slots", cfg.SlotMinutes))
+	}
+	if minute < cfg.SlotStartHour*60 || minute+cfg.SlotMinutes > cfg.SlotEndHour*60 {
+		return response.BadRequest(fmt.Sprintf("Deliveries run between %02d:00 and %02d:00", cfg.SlotStartHour, cfg.SlotEndHour))
+	}
+	if at.Equal(current) {
+		return response.BadRequest("The delivery is already scheduled for that time")
+	}
+	return nil
+}
+
+func formatNotice(d time.Duration) string {
+	if d%time.Hour == 0 {
+		return fmt.Sprintf("%d hours", int(d.Hours()))
+	}
+	return d.String()
+}
+
+// GetDeliveryReschedules lists how an order's delivery has been moved. It is
+// available to the order's customer and its provider.
+func (s *service) GetDeliveryReschedules(ctx context.Context, orderID, userID string) (*dto.DeliveryReschedulesResponse, error) {
+	order, err := s.GetOrderWithDetails(ctx, orderID)
+	if err != nil {
+		return nil, response.NotFoundError("Order")
+	}
+	isCustomer := order.UserID != nil && *order.UserID == userID
+	if !isCustomer && !s.isOrderProvider(ctx, order, userID) {
+		return nil, response.NotFoundError("Order")
+	}
+
+	delivery, err := s.repo.GetDeliveryByOrder(ctx, order.ID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get delivery", err)
+	}
+	if delivery == nil {
+		return nil, response.NotFoundError("Delivery")
+	}
+
+	reschedules, err := s.repo.GetDeliveryReschedules(ctx, order.ID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get delivery reschedules", err)
+	}
+
+	resp := &dto.DeliveryReschedulesResponse{
+		OrderID:              order.ID,
+		OrderNumber:          order.OrderNumber,
+		ScheduledAt:          delivery.ScheduledAt,
+		Status:               delivery.Status,
+		RescheduleCount:      delivery.RescheduleCount,
+		RemainingReschedules: max(s.deliveryRules().MaxReschedules-delivery.RescheduleCount, 0),
+		FailedAttempts:       delivery.FailedAttempts,
+		Reschedules:          make([]*dto.LaundryDeliveryRescheduleResponse, len(reschedules)),
+	}
+	for i, reschedule := range reschedules {
+		resp.Reschedules[i] = dto.ToLaundryDeliveryRescheduleResponse(reschedule)
+	}
+	return resp, nil
+}
+
+// notifyDeliveryRescheduled tells the other party about the move: the
+// provider when the customer moved the delivery and the customer otherwise.
+func (s *service) notifyDeliveryRescheduled(ctx context.Context, order *models.LaundryOrder, delivery *models.LaundryDelivery, reschedule *models.LaundryDeliveryReschedule) {
+	recipient := ""
+	if reschedule.RequestedBy == models.LaundryRescheduleByCustomer {
+		if order.ProviderID != nil {
+			if provider, err := s.repo.GetProviderByID(ctx, *order.ProviderID); err == nil && provider != nil {
+				recipient = provider.UserID
+			}
+		}
+		if recipient == "" && delivery.ProviderID != nil {
+			recipient = *delivery.ProviderID
+		}
+	} else if order.UserID != nil {
+		recipient = *order.UserID
+	}
+	if recipient == "" {
+		return
+	}
+
+	payload := map[string]interface{}{
+		"orderId":             order.ID,
+		"orderNumber":         order.OrderNumber,
+		"rescheduleId":        reschedule.ID,
+		"requestedBy":         reschedule.RequestedBy,
+		"previousScheduledAt": reschedule.PreviousScheduledAt,
+		"newScheduledAt":      reschedule.NewScheduledAt,
+		"reason":              reschedule.Reason,
+		"failedAttempt":       reschedule.FailedAttempt,
+		"fee":                 reschedule.Fee,
+		"total":               order.Total,
+	}
+	if err := websocketutil.SendToUser(recipient, websocket.TypeLaundryDeliveryRescheduled, payload); err != nil {
+		logger.Warn("failed to notify delivery reschedule", "error", err, "orderID", order.ID)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/umar5678/go-backend/internal/config"
 	"github.com/umar5678/go-backend/internal/middleware"
+	"github.com/umar5678/go-backend/internal/modules/laundry/facilities"
 	notificationsmodule "github.com/umar5678/go-backend/internal/modules/notifications"
 	"github.com/umar5678/go-backend/internal/modules/ridepin"
 	"github.com/umar5678/go-backend/internal/modules/wallet"
@@ -11,12 +12,20 @@ import (
 )
 
 func RegisterRoutes(router *gin.Engine, db *gorm.DB, cfg *config.Config, walletService wallet.Service, ridePinService ridepin.Service) {
-	RegisterRoutesWithNotifications(router, db, cfg, walletService, ridePinService, nil)
+	RegisterRoutesWithNotifications(router, db, cfg, walletService, ridePinService, nil, nil, nil, nil, nil)
 }
 
-func RegisterRoutesWithNotifications(router *gin.Engine, db *gorm.DB, cfg *config.Config, walletService wallet.Service, ridePinService ridepin.Service, eventProducer notificationsmodule.EventProducer) {
+func RegisterRoutesWithNotifications(router *gin.Engine, db *gorm.DB, cfg *config.Config, walletService wallet.Service, ridePinService ridepin.Service, eventProducer notificationsmodule.EventProducer, addressBook AddressBook, addressNormalizer AddressNormalizer, commissionRates CommissionRates, proofChecker ProofChecker) {
 	repo := NewRepository(db)
 	service := NewServiceWithNotifications(repo, db, walletService, ridePinService, eventProducer)
+	service.ConfigureRequotes(cfg.LaundryRequote)
+	service.ConfigureDeliveries(cfg.Deliveries)
+	facilityService := facilities.NewService(facilities.NewRepository(db))
+	service.SetItemRouter(facilityService)
+	service.SetAddressBook(addressBook)
+	service.SetAddressNormalizer(addressNormalizer)
+	service.SetCommissionRates(commissionRates)
+	service.SetProofChecker(proofChecker)
 	handler := NewHandler(service)
 
 	public := router.Group("/api/v1/laundry")
@@ -36,8 +45,16 @@ func RegisterRoutesWithNotifications(router *gin.Engine, db *gorm.DB, cfg *confi
 		customer.POST("/orders/:id/pickup/complete", handler.CompletePickup)
 		customer.POST("/orders/:id/delivery/start", handler.InitiateDelivery)
 		customer.POST("/orders/:id/delivery/complete", handler.CompleteDelivery)
+		customer.POST("/orders/:id/delivery/reschedule", handler.RescheduleDelivery)
+		customer.GET("/orders/:id/delivery/reschedules", handler.GetDeliveryReschedules)
 
 		customer.POST("/orders/:id/issues", handler.ReportIssue)
+
+		customer.GET("/items/:qrCode/scans", handler.GetItemScans)
+
+		customer.GET("/orders/:id/requotes", handler.GetOrderRequotes)
+		customer.POST("/orders/:id/requotes/:requoteId/approve", handler.ApproveRequote)
+		customer.POST("/orders/:id/requotes/:requoteId/reject", handler.RejectRequote)
 	}
 
 	provider := router.Group("/api/v1/laundry/provider")
@@ -56,10 +73,20 @@ func RegisterRoutesWithNotifications(router *gin.Engine, db *gorm.DB, cfg *confi
 
 		provider.POST("/orders/:id/items", handler.AddItems)
 		provider.PATCH("/items/:qrCode/status", handler.UpdateItemStatus)
+		provider.POST("/scan", handler.ScanItem)
+		provider.GET("/items/:qrCode/scans", handler.GetItemScans)
+		provider.GET("/items/:qrCode/label", handler.GetItemLabel)
+		provider.GET("/orders/:id/labels", handler.GetOrderLabels)
+		provider.POST("/orders/:id/requote", handler.SubmitRequote)
+		provider.GET("/orders/:id/requotes", handler.GetOrderRequotes)
 
 		provider.POST("/orders/:id/delivery/start", handler.InitiateDelivery)
 		provider.POST("/orders/:id/delivery/complete", handler.CompleteDelivery)
+		provider.POST("/orders/:id/delivery/reschedule", handler.ProviderRescheduleDelivery)
+		provider.GET("/orders/:id/delivery/reschedules", handler.GetDeliveryReschedules)
 
 		provider.PATCH("/issues/:id", handler.ResolveIssue)
+
+		facilities.RegisterRoutes(provider, facilities.NewHandler(facilityService))
 	}
 }
@@ -0,0 +1,214 @@
+package laundry
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/laundry/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+// itemStatusFlow is the order a garment moves through the facility. Scans
+// may skip steps, since not every item is washed or pressed, but never go
+// back.
+var itemStatusFlow = map[string]int{
+	"pending":   0,
+	"received":  1,
+	"washing":   2,
+	"drying":    3,
+	"pressing":  4,
+	"packed":    5,
+	"delivered": 6,
+}
+
+func (s *service) SetItemRouter(router ItemRouter) {
+	s.itemRouter = router
+}
+
+// routeItem moves an item of a facility-assigned order to the workstation for
+// its new status. Such items only move forward through the stations.
+func (s *service) routeItem(ctx context.Context, item *models.LaundryOrderItem, toStatus string) error {
+	if s.itemRouter == nil {
+		return nil
+	}
+	order, err := s.GetOrderWithDetails(ctx, item.OrderID)
+	if err != nil {
+		return response.NotFoundError("Order")
+	}
+	if order.FacilityID == nil {
+		return nil
+	}
+	if itemStatusFlow[toStatus] <= itemStatusFlow[item.Status] {
+		return response.BadRequest(fmt.Sprintf("Item cannot go from %s to %s at the facility", item.Status, toStatus))
+	}
+	return s.itemRouter.RouteItem(ctx, *order.FacilityID, item, toStatus)
+}
+
+// loadProviderItem finds the item behind a QR code and checks that the user
+// is the provider handling its order.
+func (s *service) loadProviderItem(ctx context.Context, qrCode, providerUserID string) (*models.LaundryOrderItem, *models.LaundryOrder, error) {
+	item, order, err := s.loadItem(ctx, qrCode)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !s.isOrderProvider(ctx, order, providerUserID) {
+		return nil, nil, response.NotFoundError("Item")
+	}
+	return item, order, nil
+}
+
+func (s *service) loadItem(ctx context.Context, qrCode string) (*models.LaundryOrderItem, *models.LaundryOrder, error) {
+	item, err := s.repo.GetItemByQRCode(ctx, strings.TrimSpace(qrCode))
+	if err != nil {
+		return nil, nil, response.InternalServerError("Failed to get item", err)
+	}
+	if item == nil {
+		return nil, nil, response.NotFoundError("Item")
+	}
+	order, err := s.GetOrderWithDetails(ctx, item.OrderID)
+	if err != nil {
+		return nil, nil, response.NotFoundError("Order")
+	}
+	return item, order, nil
+}
+
+// ScanItem moves a garment to the status of the station its label was
+// scanned at. Processing scans need the order to be at the facility and
+// the delivered scan needs the delivery to be under way.
+func (s *service) ScanItem(ctx context.Context, providerUserID string, req *dto.ScanItemRequest) (*dto.ItemScanResultResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	item, order, err := s.loadProviderItem(ctx, req.QRCode, providerUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Status == "delivered" {
+		delivery, err := s.repo.GetDeliveryByOrder(ctx, order.ID)
+		if err != nil || delivery == nil || delivery.Status != "en_route" {
+			return nil, response.BadRequest("Items can only be scanned as delivered once the delivery has started")
+		}
+	} else if !facilityOrderStatuses[order.Status] {
+		return nil, response.BadRequest(fmt.Sprintf("Cannot scan items of an order in '%s' status", order.Status))
+	}
+
+	current, target := itemStatusFlow[item.Status], itemStatusFlow[req.Status]
+	if target == current {
+		return nil, response.ConflictError(fmt.Sprintf("Item is already %s", item.Status))
+	}
+	if target < current {
+		return nil, response.BadRequest(fmt.Sprintf("Item cannot go back from %s to %s", item.Status, req.Status))
+	}
+
+	if err := s.routeItem(ctx, item, req.Status); err != nil {
+		return nil, err
+	}
+
+	scan := &models.LaundryItemScan{
+		ItemID:     item.ID,
+		OrderID:    order.ID,
+		QRCode:     item.QRCode,
+		FromStatus: item.Status,
+		ToStatus:   req.Status,
+		ScannedBy:  providerUserID,
+		CreatedAt:  time.Now(),
+	}
+	if req.Station != "" {
+		scan.Station = &req.Station
+	}
+	if req.Note != "" {
+		scan.Note = &req.Note
+	}
+
+	if err := s.repo.RecordItemScan(ctx, item, scan); err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, response.ConflictError("Item was updated by another scan, scan it again")
+		}
+		logger.Error("failed to record item scan", "error", err, "qrCode", item.QRCode)
+		return nil, response.InternalServerError("Failed to record scan", err)
+	}
+
+	if updated, err := s.repo.GetItemByQRCode(ctx, item.QRCode); err == nil && updated != nil {
+		item = updated
+	}
+
+	logger.Info("laundry item scanned", "orderID", order.ID, "qrCode", item.QRCode,
+		"from", scan.FromStatus, "to", scan.ToStatus, "providerID", providerUserID)
+
+	return &dto.ItemScanResultResponse{
+		Item: dto.ToLaundryOrderItemResponse(item),
+		Scan: dto.ToLaundryItemScanResponse(scan),
+	}, nil
+}
+
+// GetItemScans returns an item's scan history, oldest first. It is open to
+// the order's customer as well as its provider.
+func (s *service) GetItemScans(ctx context.Context, qrCode, userID string) (*dto.ItemScanHistoryResponse, error) {
+	item, order, err := s.loadItem(ctx, qrCode)
+	if err != nil {
+		return nil, err
+	}
+	isCustomer := order.UserID != nil && *order.UserID == userID
+	if !isCustomer && !s.isOrderProvider(ctx, order, userID) {
+		return nil, response.NotFoundError("Item")
+	}
+
+	scans, err := s.repo.GetItemScans(ctx, item.ID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get scan history", err)
+	}
+	return &dto.ItemScanHistoryResponse{
+		Item:  dto.ToLaundryOrderItemResponse(item),
+		Scans: dto.ToLaundryItemScanResponses(scans),
+	}, nil
+}
+
+func (s *service) GetItemLabel(ctx context.Context, qrCode, providerUserID string, size int) (*LabelFile, error) {
+	item, _, err := s.loadProviderItem(ctx, qrCode, providerUserID)
+	if err != nil {
+		return nil, err
+	}
+	label, err := itemLabelPNG(item, size)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to generate label", err)
+	}
+	return label, nil
+}
+
+// GetOrderLabels renders the labels for every item of an order, as a PDF
+// sheet for printing or a single PNG.
+func (s *service) GetOrderLabels(ctx context.Context, orderID, providerUserID, format string, size int) (*LabelFile, error) {
+	order, err := s.GetOrderWithDetails(ctx, orderID)
+	if err != nil || !s.isOrderProvider(ctx, order, providerUserID) {
+		return nil, response.NotFoundError("Order")
+	}
+
+	items, err := s.repo.GetOrderItems(ctx, order.ID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get order items", err)
+	}
+	if len(items) == 0 {
+		return nil, response.BadRequest("Order has no items to label yet")
+	}
+
+	var label *LabelFile
+	switch format {
+	case "", LabelFormatPDF:
+		label, err = orderLabelsPDF(order, items)
+	case LabelFormatPNG:
+		label, err = orderLabelsPNG(order, items, size)
+	default:
+		return nil, response.BadRequest(fmt.Sprintf("Unsupported label format '%s' (valid: pdf, png)", format))
+	}
+	if err != nil {
+		return nil, response.InternalServerError("Failed to generate labels", err)
+	}
+	return label, nil
+}
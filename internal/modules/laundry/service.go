@@ -8,15 +8,34 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/umar5678/go-backend/internal/config"
 	"github.com/umar5678/go-backend/internal/models"
 	"github.com/umar5678/go-backend/internal/modules/laundry/dto"
 	notificationsmodule "github.com/umar5678/go-backend/internal/modules/notifications"
 	"github.com/umar5678/go-backend/internal/modules/ridepin"
 	"github.com/umar5678/go-backend/internal/modules/wallet"
+	walletdto "github.com/umar5678/go-backend/internal/modules/wallet/dto"
+	"github.com/umar5678/go-backend/internal/services/cache"
 	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/utils/translit"
 	"gorm.io/gorm"
 )
 
+// The laundry catalog is maintained through seed migrations and the admin
+// catalog import, which calls InvalidateCatalogCache after writing.
+const (
+	servicesWithProductsCacheKey = "catalog:laundry:services_with_products"
+	catalogCacheTTL              = 10 * time.Minute
+)
+
+// InvalidateCatalogCache drops the cached services-with-products listing.
+func InvalidateCatalogCache(ctx context.Context) {
+	if err := cache.Delete(ctx, servicesWithProductsCacheKey); err != nil {
+		logger.Warn("failed to invalidate laundry catalog cache", "error", err)
+	}
+}
+
 type Service interface {
 	GetServiceCatalog(ctx context.Context) ([]*models.LaundryServiceCatalog, error)
 	GetServicesWithProducts(ctx context.Context) ([]*dto.LaundryServiceDTO, error)
@@ -42,14 +61,48 @@ type Service interface {
 	ReportIssue(ctx context.Context, orderID, customerID, providerID string, req *dto.ReportIssueRequest) (*models.LaundryIssue, error)
 	GetProviderIssues(ctx context.Context, providerID string) ([]*models.LaundryIssue, error)
 	ResolveIssue(ctx context.Context, issueID string, resolution string, refundAmount *float64) error
+
+	SubmitRequote(ctx context.Context, orderID, providerUserID string, req *dto.SubmitRequoteRequest) (*dto.LaundryRequoteResponse, error)
+	GetOrderRequotes(ctx context.Context, orderID, userID string) ([]*dto.LaundryRequoteResponse, error)
+	ApproveRequote(ctx context.Context, orderID, requoteID, customerID string, req *dto.RespondRequoteRequest) (*dto.LaundryRequoteResponse, error)
+	RejectRequote(ctx context.Context, orderID, requoteID, customerID string, req *dto.RespondRequoteRequest) (*dto.LaundryRequoteResponse, error)
+
+	RescheduleDelivery(ctx context.Context, orderID, userID, requestedBy string, req *dto.RescheduleDeliveryRequest) (*dto.LaundryDeliveryRescheduleResponse, error)
+	GetDeliveryReschedules(ctx context.Context, orderID, userID string) (*dto.DeliveryReschedulesResponse, error)
+
+	ScanItem(ctx context.Context, providerUserID string, req *dto.ScanItemRequest) (*dto.ItemScanResultResponse, error)
+	GetItemScans(ctx context.Context, qrCode, userID string) (*dto.ItemScanHistoryResponse, error)
+	GetItemLabel(ctx context.Context, qrCode, providerUserID string, size int) (*LabelFile, error)
+	GetOrderLabels(ctx context.Context, orderID, providerUserID, format string, size int) (*LabelFile, error)
+
+	ConfigureRequotes(cfg config.LaundryRequoteConfig)
+	ConfigureDeliveries(cfg config.LaundryDeliveryConfig)
+	SetItemRouter(router ItemRouter)
+	SetAddressBook(book AddressBook)
+	SetAddressNormalizer(normalizer AddressNormalizer)
+	SetCommissionRates(commissionRates CommissionRates)
+	SetProofChecker(checker ProofChecker)
+}
+
+// ItemRouter places the items of orders assigned to a facility on its
+// workstations as their status changes, enforcing station capacity.
+type ItemRouter interface {
+	RouteItem(ctx context.Context, facilityID string, item *models.LaundryOrderItem, toStatus string) error
 }
 
 type service struct {
-	repo           Repository
-	db             *gorm.DB
-	walletService  wallet.Service
-	ridePINService ridepin.Service
-	eventProducer  notificationsmodule.EventProducer
+	repo              Repository
+	db                *gorm.DB
+	walletService     wallet.Service
+	ridePINService    ridepin.Service
+	eventProducer     notificationsmodule.EventProducer
+	requotes          config.LaundryRequoteConfig
+	deliveries        config.LaundryDeliveryConfig
+	itemRouter        ItemRouter
+	addressBook       AddressBook
+	addressNormalizer AddressNormalizer
+	commissionRates   CommissionRates
+	proofChecker      ProofChecker
 }
 
 func NewService(repo Repository, db *gorm.DB, walletService wallet.Service, ridePINService ridepin.Service) Service {
@@ -71,6 +124,11 @@ func (s *service) GetServiceCatalog(ctx context.Context) ([]*models.LaundryServi
 }
 
 func (s *service) GetServicesWithProducts(ctx context.Context) ([]*dto.LaundryServiceDTO, error) {
+	var cached []*dto.LaundryServiceDTO
+	if err := cache.GetJSON(ctx, servicesWithProductsCacheKey, &cached); err == nil {
+		return cached, nil
+	}
+
 	services, err := s.repo.GetServicesWithProducts(ctx)
 	if err != nil {
 		logger.Error("GetServicesWithProducts: failed to fetch services", "error", err)
@@ -116,6 +174,7 @@ func (s *service) GetServicesWithProducts(ctx context.Context) ([]*dto.LaundrySe
 		result = append(result, serviceDTO)
 	}
 
+	cache.SetJSON(ctx, servicesWithProductsCacheKey, result, catalogCacheTTL)
 	return result, nil
 }
 
@@ -130,6 +189,10 @@ func (s *service) CreateOrder(ctx context.Context, customerID string, req *dto.C
 		return nil, errors.New("request is required")
 	}
 
+	if err := s.resolveOrderAddress(ctx, customerID, req); err != nil {
+		return nil, err
+	}
+
 	if err := req.Validate(); err != nil {
 		logger.Error("CreateOrder: request validation failed",
 			"error", err,
@@ -138,6 +201,7 @@ func (s *service) CreateOrder(ctx context.Context, customerID string, req *dto.C
 		)
 		return nil, fmt.Errorf("validation failed: %w", err)
 	}
+	s.normalizeOrderAddress(ctx, req)
 
 	service, err := s.repo.GetServiceBySlug(ctx, req.ServiceSlug)
 	if err != nil {
@@ -160,25 +224,7 @@ func (s *service) CreateOrder(ctx context.Context, customerID string, req *dto.C
 			return nil, fmt.Errorf("product '%s' not found", item.ProductSlug)
 		}
 
-		itemPrice := 0.0
-
-		if service.PricingUnit == "kg" {
-			if product.Price != nil {
-				itemPrice = *product.Price * float64(item.Quantity)
-			}
-		} else {
-			price := service.BasePrice
-			if product.Price != nil {
-				price += *product.Price
-			}
-			itemPrice = price * float64(item.Quantity)
-		}
-
-		if product.RequiresSpecialCare {
-			itemPrice += product.SpecialCareFee * float64(item.Quantity)
-		}
-
-		totalPrice += itemPrice
+		totalPrice += itemPrice(service, product, item.Quantity, nil)
 	}
 
 	if req.IsExpress {
@@ -204,24 +250,28 @@ func (s *service) CreateOrder(ctx context.Context, customerID string, req *dto.C
 	orderID := uuid.New().String()
 	now := time.Now()
 	expiresAt := now.Add(10 * time.Minute)
+	address := translit.NormalizeAddress(req.Address)
 	order := &models.LaundryOrder{
-		ID:           orderID,
-		OrderNumber:  fmt.Sprintf("LDY-%d", time.Now().Unix()),
-		UserID:       &customerID,
-		CategorySlug: "laundry",
-		Status:       "pending",
-		Address:      req.Address,
-		Latitude:     req.Lat,
-		Longitude:    req.Lng,
-		ServiceDate:  nil,
-		Total:        totalPrice,
-		Tip:          req.Tip,
-		IsExpress:    req.IsExpress,
-		PersonCount:  req.PersonCount,
-		ProviderID:   nil,
-		CreatedAt:    now,
-		UpdatedAt:    now,
-		ExpiresAt:    &expiresAt,
+		ID:                orderID,
+		OrderNumber:       fmt.Sprintf("LDY-%d", time.Now().Unix()),
+		UserID:            &customerID,
+		CategorySlug:      "laundry",
+		Status:            "pending",
+		Address:           req.Address,
+		Latitude:          req.Lat,
+		Longitude:         req.Lng,
+		NormalizedAddress: address.Normalized,
+		AddressScript:     address.Script,
+		ServiceDate:       nil,
+		Total:             totalPrice,
+		Tip:               req.Tip,
+		IsExpress:         req.IsExpress,
+		CommissionRate:    s.commissionRate(ctx, "laundry", req.Lat, req.Lng),
+		PersonCount:       req.PersonCount,
+		ProviderID:        nil,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+		ExpiresAt:         &expiresAt,
 	}
 
 	if err := s.db.WithContext(ctx).Create(order).Error; err != nil {
@@ -244,25 +294,6 @@ func (s *service) CreateOrder(ctx context.Context, customerID string, req *dto.C
 	for i, item := range req.Items {
 		product, _ := s.repo.GetProductBySlug(ctx, req.ServiceSlug, item.ProductSlug)
 
-		itemPrice := 0.0
-		if service.PricingUnit == "kg" {
-
-			if product.Price != nil {
-				itemPrice = *product.Price * float64(item.Quantity)
-			}
-		} else {
-
-			price := service.BasePrice
-			if product.Price != nil {
-				price += *product.Price
-			}
-			itemPrice = price * float64(item.Quantity)
-		}
-
-		if product.RequiresSpecialCare {
-			itemPrice += product.SpecialCareFee * float64(item.Quantity)
-		}
-
 		items[i] = &models.LaundryOrderItem{
 			OrderID:     orderID,
 			ServiceSlug: req.ServiceSlug,
@@ -271,7 +302,7 @@ func (s *service) CreateOrder(ctx context.Context, customerID string, req *dto.C
 			Quantity:    item.Quantity,
 			Weight:      item.Weight,
 			Status:      "pending",
-			Price:       itemPrice,
+			Price:       itemPrice(service, product, item.Quantity, nil),
 			CreatedAt:   time.Now(),
 			UpdatedAt:   time.Now(),
 		}
@@ -367,6 +398,8 @@ func (s *service) CreateOrder(ctx context.Context, customerID string, req *dto.C
 		"turnaroundHours", turnaroundHours,
 	)
 
+	s.holdOrderTotal(ctx, order, deliveryDateTime)
+
 	logger.Info("CreateOrder: order creation completed successfully",
 		"orderID", orderID,
 		"customerID", customerID,
@@ -397,6 +430,7 @@ func (s *service) GetOrder(ctx context.Context, orderID string) (*dto.LaundryOrd
 			ItemType:         item.ItemType,
 			Quantity:         item.Quantity,
 			Weight:           item.Weight,
+			MeasuredWeight:   item.MeasuredWeight,
 			QRCode:           item.QRCode,
 			Status:           item.Status,
 			HasIssue:         item.HasIssue,
@@ -622,6 +656,7 @@ func (s *service) CompletePickup(ctx context.Context, orderID string, req *dto.C
 		Where("id = ?", orderID).
 		Updates(map[string]interface{}{
 			"status":     "pickup_completed",
+			"version":    gorm.Expr("version + 1"),
 			"updated_at": now,
 		}).Error; err != nil {
 		return fmt.Errorf("failed to update order status: %w", err)
@@ -671,7 +706,10 @@ func (s *service) AddItems(ctx context.Context, orderID string, req *dto.AddLaun
 	s.db.WithContext(ctx).
 		Model(&models.LaundryOrder{}).
 		Where("id = ?", orderID).
-		Update("status", "processing")
+		Updates(map[string]interface{}{
+			"status":  "processing",
+			"version": gorm.Expr("version + 1"),
+		})
 
 	return items, nil
 }
@@ -694,11 +732,22 @@ func (s *service) UpdateItemStatus(ctx context.Context, qrCode, status string) (
 		return nil, fmt.Errorf("invalid status: %s (valid: pending, received, washing, drying, pressing, packed, delivered)", status)
 	}
 
+	item, err := s.repo.GetItemByQRCode(ctx, qrCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch item: %w", err)
+	}
+	if item == nil {
+		return nil, fmt.Errorf("item %s not found", qrCode)
+	}
+	if err := s.routeItem(ctx, item, status); err != nil {
+		return nil, err
+	}
+
 	if err := s.repo.UpdateItemStatus(ctx, qrCode, status); err != nil {
 		return nil, fmt.Errorf("failed to update item status: %w", err)
 	}
 
-	item, err := s.repo.GetItemByQRCode(ctx, qrCode)
+	item, err = s.repo.GetItemByQRCode(ctx, qrCode)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch updated item: %w", err)
 	}
@@ -771,6 +820,12 @@ func (s *service) CompleteDelivery(ctx context.Context, orderID string, req *dto
 	customerID := *order.UserID
 	providerID := *order.ProviderID
 
+	if s.proofChecker != nil {
+		if err := s.proofChecker.CheckProofs(ctx, "laundry_order", order.ID, order.CategorySlug); err != nil {
+			return err
+		}
+	}
+
 	// Verify customer PIN
 	logger.Info("verifying customer PIN for delivery completion", "orderID", orderID, "customerID", customerID)
 	if err := s.ridePINService.VerifyRidePIN(ctx, customerID, req.RiderPIN); err != nil {
@@ -782,7 +837,24 @@ func (s *service) CompleteDelivery(ctx context.Context, orderID string, req *dto
 
 	logger.Info("customer PIN verified at delivery completion", "orderID", orderID)
 
+	// Only the request that moves the order to completed captures the
+	// customer's hold and pays the provider.
 	now := time.Now()
+	result := s.db.WithContext(ctx).
+		Model(&models.LaundryOrder{}).
+		Where("id = ? AND version = ? AND status <> ?", orderID, order.Version, "completed").
+		Updates(map[string]interface{}{
+			"status":     "completed",
+			"version":    gorm.Expr("version + 1"),
+			"updated_at": now,
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update order status: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return response.ConflictError("Order was completed or changed in the meantime, please reload it")
+	}
+
 	if err := s.repo.UpdateDeliveryStatus(ctx, orderID, "completed", &now); err != nil {
 		return fmt.Errorf("failed to complete delivery: %w", err)
 	}
@@ -796,34 +868,39 @@ func (s *service) CompleteDelivery(ctx context.Context, orderID string, req *dto
 			"updated_at":   now,
 		})
 
-	if err := s.db.WithContext(ctx).
-		Model(&models.LaundryOrder{}).
-		Where("id = ?", orderID).
-		Updates(map[string]interface{}{
-			"status":     "completed",
-			"updated_at": now,
-		}).Error; err != nil {
-		return fmt.Errorf("failed to update order status: %w", err)
-	}
-
-	providerEarnings := order.Total * 0.90
-	metadata := map[string]interface{}{
-		"order_id":   orderID,
-		"service":    "laundry",
-		"total":      order.Total,
-		"commission": order.Total * 0.10,
+	if order.WalletHoldID != nil {
+		captureReq := walletdto.CaptureHoldRequest{
+			HoldID:      *order.WalletHoldID,
+			Amount:      &order.Total,
+			Description: fmt.Sprintf("Payment for laundry order %s", order.OrderNumber),
+		}
+		if _, err := s.walletService.CaptureHold(ctx, customerID, captureReq); err != nil {
+			logger.Error("failed to capture laundry order hold", "error", err, "orderID", orderID)
+		}
 	}
 
-	if _, err := s.walletService.CreditServiceProviderWallet(
-		ctx,
-		providerID,
-		providerEarnings,
-		"laundry_delivery",
-		orderID,
-		fmt.Sprintf("Laundry delivery payment for order %s", orderID),
-		metadata,
-	); err != nil {
+	providerEarnings := providerPayout(order.Total, order.CommissionRate)
+	metadata := models.JSONBMap{
+		"order_id":        orderID,
+		"service":         "laundry",
+		"total":           order.Total,
+		"commission":      order.Total - providerEarnings,
+		"commission_rate": order.CommissionRate,
+	}
+
+	queued, err := s.walletService.CreditProviderPayout(ctx, &models.PayoutCredit{
+		ReferenceType:   "laundry_order",
+		ReferenceID:     orderID,
+		ProviderUserID:  providerID,
+		Amount:          providerEarnings,
+		TransactionType: "laundry_delivery",
+		Description:     fmt.Sprintf("Laundry delivery payment for order %s", orderID),
+		Metadata:        metadata,
+	})
+	if err != nil {
 		logger.Error("failed to credit provider wallet for laundry delivery", "error", err, "orderID", orderID, "providerID", providerID)
+	} else if queued {
+		logger.Info("laundry delivery payout pending", "orderID", orderID, "providerID", providerID)
 	}
 
 	logger.Info("laundry delivery completed and provider wallet credited",
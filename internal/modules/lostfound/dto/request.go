@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"errors"
+	"strings"
+)
+
+type ReportLostItemRequest struct {
+	ItemDescription string `json:"itemDescription" binding:"required,min=3,max=1000"`
+	ItemCategory    string `json:"itemCategory" binding:"omitempty,oneof=phone wallet bag keys documents electronics clothing other"`
+	ContactNote     string `json:"contactNote" binding:"omitempty,max=500"`
+}
+
+func (r *ReportLostItemRequest) Validate() error {
+	if strings.TrimSpace(r.ItemDescription) == "" {
+		return errors.New("item description is required")
+	}
+	if r.ItemCategory == "" {
+		r.ItemCategory = "other"
+	}
+	return nil
+}
+
+// DriverResponseRequest carries the driver's answer to a lost item notification.
+// Response options: found, not_found.
+type DriverResponseRequest struct {
+	Response  string  `json:"response" binding:"required,oneof=found not_found"`
+	Note      string  `json:"note" binding:"omitempty,max=500"`
+	ReturnFee float64 `json:"returnFee" binding:"omitempty,min=0"`
+}
+
+func (r *DriverResponseRequest) Validate() error {
+	if r.Response == "not_found" && r.ReturnFee > 0 {
+		return errors.New("return fee can only be set when the item was found")
+	}
+	return nil
+}
+
+type ScheduleReturnRequest struct {
+	ReturnAddress string `json:"returnAddress" binding:"required,max=500"`
+	ReturnAt      string `json:"returnAt" binding:"omitempty"`
+}
+
+type SendCaseMessageRequest struct {
+	Content string `json:"content" binding:"required,min=1,max=1000"`
+}
+
+type EscalateCaseRequest struct {
+	Note string `json:"note" binding:"required,max=1000"`
+}
+
+type ResolveCaseRequest struct {
+	Note      string `json:"note" binding:"required,max=1000"`
+	RefundFee bool   `json:"refundFee"`
+}
+
+type ListLostItemsRequest struct {
+	Status string `form:"status" binding:"omitempty,oneof=reported found not_found return_scheduled returned escalated resolved closed"`
+	Page   int    `form:"page" binding:"omitempty,min=1"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (r *ListLostItemsRequest) SetDefaults() {
+	if r.Page <= 0 {
+		r.Page = 1
+	}
+	if r.Limit <= 0 {
+		r.Limit = 20
+	}
+	if r.Limit > 100 {
+		r.Limit = 100
+	}
+}
@@ -0,0 +1,86 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+type LostItemCaseResponse struct {
+	ID               string     `json:"id"`
+	RideID           string     `json:"rideId"`
+	RiderID          string     `json:"riderId"`
+	DriverID         string     `json:"driverId"`
+	ItemDescription  string     `json:"itemDescription"`
+	ItemCategory     string     `json:"itemCategory"`
+	ContactNote      string     `json:"contactNote,omitempty"`
+	Status           string     `json:"status"`
+	DriverResponse   string     `json:"driverResponse,omitempty"`
+	ReturnFee        float64    `json:"returnFee"`
+	ReturnFeeCharged bool       `json:"returnFeeCharged"`
+	ReturnAddress    string     `json:"returnAddress,omitempty"`
+	ReturnAt         *time.Time `json:"returnAt,omitempty"`
+	EscalationNote   string     `json:"escalationNote,omitempty"`
+	EscalatedAt      *time.Time `json:"escalatedAt,omitempty"`
+	ResolutionNote   string     `json:"resolutionNote,omitempty"`
+	DriverNotifiedAt *time.Time `json:"driverNotifiedAt,omitempty"`
+	RespondedAt      *time.Time `json:"respondedAt,omitempty"`
+	ReturnedAt       *time.Time `json:"returnedAt,omitempty"`
+	ClosedAt         *time.Time `json:"closedAt,omitempty"`
+	ResponseOptions  []string   `json:"responseOptions,omitempty"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	UpdatedAt        time.Time  `json:"updatedAt"`
+}
+
+type LostItemMessageResponse struct {
+	ID         string    `json:"id"`
+	CaseID     string    `json:"caseId"`
+	SenderRole string    `json:"senderRole"`
+	IsMine     bool      `json:"isMine"`
+	Content    string    `json:"content"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+func ToLostItemCaseResponse(c *models.LostItemCase) *LostItemCaseResponse {
+	resp := &LostItemCaseResponse{
+		ID:               c.ID,
+		RideID:           c.RideID,
+		RiderID:          c.RiderID,
+		DriverID:         c.DriverID,
+		ItemDescription:  c.ItemDescription,
+		ItemCategory:     c.ItemCategory,
+		ContactNote:      c.ContactNote,
+		Status:           string(c.Status),
+		DriverResponse:   c.DriverResponse,
+		ReturnFee:        c.ReturnFee,
+		ReturnFeeCharged: c.ReturnFeeTxnID != nil,
+		ReturnAddress:    c.ReturnAddress,
+		ReturnAt:         c.ReturnAt,
+		EscalationNote:   c.EscalationNote,
+		EscalatedAt:      c.EscalatedAt,
+		ResolutionNote:   c.ResolutionNote,
+		DriverNotifiedAt: c.DriverNotifiedAt,
+		RespondedAt:      c.RespondedAt,
+		ReturnedAt:       c.ReturnedAt,
+		ClosedAt:         c.ClosedAt,
+		CreatedAt:        c.CreatedAt,
+		UpdatedAt:        c.UpdatedAt,
+	}
+
+	if c.Status == models.LostItemStatusReported {
+		resp.ResponseOptions = []string{"found", "not_found"}
+	}
+
+	return resp
+}
+
+func ToLostItemMessageResponse(m *models.LostItemMessage, viewerID string) *LostItemMessageResponse {
+	return &LostItemMessageResponse{
+		ID:         m.ID,
+		CaseID:     m.CaseID,
+		SenderRole: m.SenderRole,
+		IsMine:     m.SenderID == viewerID,
+		Content:    m.Content,
+		CreatedAt:  m.CreatedAt,
+	}
+}
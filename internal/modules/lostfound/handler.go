@@ -0,0 +1,303 @@
+package lostfound
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/modules/lostfound/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// ReportLostItem godoc
+// @Summary Report an item left in the vehicle after a trip
+// @Tags lost-items
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Ride ID"
+// @Param request body dto.ReportLostItemRequest true "Lost item details"
+// @Success 201 {object} response.Response{data=dto.LostItemCaseResponse}
+// @Router /rides/{id}/lost-item [post]
+func (h *Handler) ReportLostItem(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req dto.ReportLostItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	lostCase, err := h.service.ReportLostItem(c.Request.Context(), userID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, lostCase, "Lost item reported successfully")
+}
+
+// ListCases godoc
+// @Summary List lost item cases
+// @Description Riders and drivers see their own cases, admins see all cases
+// @Tags lost-items
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "Filter by status"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Response{data=[]dto.LostItemCaseResponse}
+// @Router /lost-items [get]
+func (h *Handler) ListCases(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+
+	filterUserID := ""
+	if role != "admin" {
+		filterUserID = userID.(string)
+	}
+
+	var req dto.ListLostItemsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+	req.SetDefaults()
+
+	cases, total, err := h.service.ListCases(c.Request.Context(), filterUserID, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pagination := response.NewPaginationMeta(total, req.Page, req.Limit)
+	response.Paginated(c, cases, pagination, "Lost item cases retrieved successfully")
+}
+
+// GetCase godoc
+// @Summary Get lost item case
+// @Tags lost-items
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Case ID"
+// @Success 200 {object} response.Response{data=dto.LostItemCaseResponse}
+// @Router /lost-items/{id} [get]
+func (h *Handler) GetCase(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+
+	lostCase, err := h.service.GetCase(c.Request.Context(), userID.(string), c.Param("id"), role == "admin")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, lostCase, "Lost item case retrieved successfully")
+}
+
+// RespondToCase godoc
+// @Summary Driver response to a lost item report
+// @Tags lost-items
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Case ID"
+// @Param request body dto.DriverResponseRequest true "Driver response"
+// @Success 200 {object} response.Response{data=dto.LostItemCaseResponse}
+// @Router /lost-items/{id}/respond [post]
+func (h *Handler) RespondToCase(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req dto.DriverResponseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	lostCase, err := h.service.RespondToCase(c.Request.Context(), userID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, lostCase, "Response recorded successfully")
+}
+
+// ScheduleReturn godoc
+// @Summary Schedule the return of a found item
+// @Description Charges the driver's return fee (if any) from the rider wallet
+// @Tags lost-items
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Case ID"
+// @Param request body dto.ScheduleReturnRequest true "Return details"
+// @Success 200 {object} response.Response{data=dto.LostItemCaseResponse}
+// @Router /lost-items/{id}/schedule-return [post]
+func (h *Handler) ScheduleReturn(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req dto.ScheduleReturnRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	lostCase, err := h.service.ScheduleReturn(c.Request.Context(), userID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, lostCase, "Return scheduled successfully")
+}
+
+// MarkReturned godoc
+// @Summary Mark a lost item as returned
+// @Tags lost-items
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Case ID"
+// @Success 200 {object} response.Response{data=dto.LostItemCaseResponse}
+// @Router /lost-items/{id}/returned [post]
+func (h *Handler) MarkReturned(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	lostCase, err := h.service.MarkReturned(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, lostCase, "Item marked as returned")
+}
+
+// CloseCase godoc
+// @Summary Close a lost item case
+// @Tags lost-items
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Case ID"
+// @Success 200 {object} response.Response{data=dto.LostItemCaseResponse}
+// @Router /lost-items/{id}/close [post]
+func (h *Handler) CloseCase(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	lostCase, err := h.service.CloseCase(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, lostCase, "Case closed successfully")
+}
+
+// GetMessages godoc
+// @Summary Get masked rider/driver conversation for a case
+// @Tags lost-items
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Case ID"
+// @Success 200 {object} response.Response{data=[]dto.LostItemMessageResponse}
+// @Router /lost-items/{id}/messages [get]
+func (h *Handler) GetMessages(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+
+	msgs, err := h.service.GetMessages(c.Request.Context(), userID.(string), c.Param("id"), role == "admin")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, msgs, "Messages retrieved successfully")
+}
+
+// SendMessage godoc
+// @Summary Send a masked message to the other party of a case
+// @Tags lost-items
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Case ID"
+// @Param request body dto.SendCaseMessageRequest true "Message"
+// @Success 201 {object} response.Response{data=dto.LostItemMessageResponse}
+// @Router /lost-items/{id}/messages [post]
+func (h *Handler) SendMessage(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req dto.SendCaseMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	msg, err := h.service.SendMessage(c.Request.Context(), userID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, msg, "Message sent successfully")
+}
+
+// EscalateCase godoc
+// @Summary Escalate a lost item case to support
+// @Tags lost-items
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Case ID"
+// @Param request body dto.EscalateCaseRequest true "Escalation note"
+// @Success 200 {object} response.Response{data=dto.LostItemCaseResponse}
+// @Router /lost-items/{id}/escalate [post]
+func (h *Handler) EscalateCase(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req dto.EscalateCaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	lostCase, err := h.service.EscalateCase(c.Request.Context(), userID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, lostCase, "Case escalated successfully")
+}
+
+// ResolveCase godoc
+// @Summary Resolve an escalated lost item case (admin)
+// @Tags lost-items
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Case ID"
+// @Param request body dto.ResolveCaseRequest true "Resolution"
+// @Success 200 {object} response.Response{data=dto.LostItemCaseResponse}
+// @Router /lost-items/{id}/resolve [post]
+func (h *Handler) ResolveCase(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req dto.ResolveCaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	lostCase, err := h.service.ResolveCase(c.Request.Context(), userID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, lostCase, "Case resolved successfully")
+}
@@ -0,0 +1,145 @@
+package lostfound
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	CreateCase(ctx context.Context, c *models.LostItemCase) error
+	FindCaseByID(ctx context.Context, id string) (*models.LostItemCase, error)
+	FindOpenCaseByRide(ctx context.Context, rideID string) (*models.LostItemCase, error)
+	UpdateCase(ctx context.Context, c *models.LostItemCase) error
+	// ClaimReturn schedules the return of a found item, provided the case is
+	// still found, so the return fee is only taken by the caller that wins.
+	ClaimReturn(ctx context.Context, c *models.LostItemCase) (bool, error)
+	ReleaseReturn(ctx context.Context, caseID string) error
+	SetReturnFeeTxn(ctx context.Context, caseID, txnID string) error
+	ListCases(ctx context.Context, userID, status string, page, limit int) ([]*models.LostItemCase, int64, error)
+
+	CreateMessage(ctx context.Context, msg *models.LostItemMessage) error
+	ListMessages(ctx context.Context, caseID string) ([]*models.LostItemMessage, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) CreateCase(ctx context.Context, c *models.LostItemCase) error {
+	return r.db.WithContext(ctx).Create(c).Error
+}
+
+func (r *repository) FindCaseByID(ctx context.Context, id string) (*models.LostItemCase, error) {
+	var c models.LostItemCase
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&c).Error
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *repository) FindOpenCaseByRide(ctx context.Context, rideID string) (*models.LostItemCase, error) {
+	var c models.LostItemCase
+	err := r.db.WithContext(ctx).
+		Where("ride_id = ? AND status NOT IN ?", rideID, []models.LostItemStatus{
+			models.LostItemStatusReturned,
+			models.LostItemStatusResolved,
+			models.LostItemStatusClosed,
+		}).
+		Order("created_at DESC").
+		First(&c).Error
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+func (r *repository) UpdateCase(ctx context.Context, c *models.LostItemCase) error {
+	return r.db.WithContext(ctx).Save(c).Error
+}
+
+func (r *repository) ClaimReturn(ctx context.Context, c *models.LostItemCase) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.LostItemCase{}).
+		Where("id = ? AND status = ?", c.ID, models.LostItemStatusFound).
+		Updates(map[string]interface{}{
+			"status":         models.LostItemStatusReturnScheduled,
+			"return_at":      c.ReturnAt,
+			"return_address": c.ReturnAddress,
+			"updated_at":     time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *repository) ReleaseReturn(ctx context.Context, caseID string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.LostItemCase{}).
+		Where("id = ? AND status = ?", caseID, models.LostItemStatusReturnScheduled).
+		Updates(map[string]interface{}{
+			"status":     models.LostItemStatusFound,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+func (r *repository) SetReturnFeeTxn(ctx context.Context, caseID, txnID string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.LostItemCase{}).
+		Where("id = ?", caseID).
+		Updates(map[string]interface{}{
+			"return_fee_txn_id": txnID,
+			"updated_at":        time.Now(),
+		}).Error
+}
+
+func (r *repository) ListCases(ctx context.Context, userID, status string, page, limit int) ([]*models.LostItemCase, int64, error) {
+	var cases []*models.LostItemCase
+	var total int64
+
+	base := r.db.WithContext(ctx).Model(&models.LostItemCase{})
+	if userID != "" {
+		base = base.Where("rider_id = ? OR driver_id = ?", userID, userID)
+	}
+	if status != "" {
+		base = base.Where("status = ?", status)
+	}
+
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count lost item cases: %w", err)
+	}
+
+	if total == 0 {
+		return []*models.LostItemCase{}, 0, nil
+	}
+
+	offset := (page - 1) * limit
+	if err := base.
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&cases).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch lost item cases: %w", err)
+	}
+
+	return cases, total, nil
+}
+
+func (r *repository) CreateMessage(ctx context.Context, msg *models.LostItemMessage) error {
+	return r.db.WithContext(ctx).Create(msg).Error
+}
+
+func (r *repository) ListMessages(ctx context.Context, caseID string) ([]*models.LostItemMessage, error) {
+	var msgs []*models.LostItemMessage
+	err := r.db.WithContext(ctx).
+		Where("case_id = ?", caseID).
+		Order("created_at ASC").
+		Find(&msgs).Error
+	return msgs, err
+}
@@ -0,0 +1,32 @@
+package lostfound
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/middleware"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
+	rides := router.Group("/rides")
+	rides.Use(authMiddleware)
+	{
+		rides.POST("/:id/lost-item", middleware.RequireRider(), handler.ReportLostItem)
+	}
+
+	lostItems := router.Group("/lost-items")
+	lostItems.Use(authMiddleware)
+	{
+		lostItems.GET("", handler.ListCases)
+		lostItems.GET("/:id", handler.GetCase)
+
+		lostItems.POST("/:id/respond", middleware.RequireDriver(), handler.RespondToCase)
+		lostItems.POST("/:id/schedule-return", middleware.RequireRider(), handler.ScheduleReturn)
+		lostItems.POST("/:id/returned", handler.MarkReturned)
+		lostItems.POST("/:id/close", middleware.RequireRider(), handler.CloseCase)
+
+		lostItems.GET("/:id/messages", handler.GetMessages)
+		lostItems.POST("/:id/messages", handler.SendMessage)
+
+		lostItems.POST("/:id/escalate", handler.EscalateCase)
+		lostItems.POST("/:id/resolve", middleware.RequireAdmin(), handler.ResolveCase)
+	}
+}
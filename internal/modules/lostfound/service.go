@@ -0,0 +1,523 @@
+package lostfound
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/lostfound/dto"
+	notificationsmodule "github.com/umar5678/go-backend/internal/modules/notifications"
+	ridesrepo "github.com/umar5678/go-backend/internal/modules/rides"
+	walletservice "github.com/umar5678/go-backend/internal/modules/wallet"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+	websocketutil "github.com/umar5678/go-backend/internal/websocket/websocketutils"
+	"gorm.io/gorm"
+)
+
+const (
+	// Riders can only open a case for trips completed within this window.
+	reportWindow = 30 * 24 * time.Hour
+
+	maxReturnFee = 2000.0
+)
+
+type Service interface {
+	ReportLostItem(ctx context.Context, riderID, rideID string, req dto.ReportLostItemRequest) (*dto.LostItemCaseResponse, error)
+	GetCase(ctx context.Context, userID, caseID string, isAdmin bool) (*dto.LostItemCaseResponse, error)
+	ListCases(ctx context.Context, userID string, req dto.ListLostItemsRequest) ([]*dto.LostItemCaseResponse, int64, error)
+
+	RespondToCase(ctx context.Context, driverID, caseID string, req dto.DriverResponseRequest) (*dto.LostItemCaseResponse, error)
+	ScheduleReturn(ctx context.Context, riderID, caseID string, req dto.ScheduleReturnRequest) (*dto.LostItemCaseResponse, error)
+	MarkReturned(ctx context.Context, userID, caseID string) (*dto.LostItemCaseResponse, error)
+	CloseCase(ctx context.Context, riderID, caseID string) (*dto.LostItemCaseResponse, error)
+
+	SendMessage(ctx context.Context, userID, caseID string, req dto.SendCaseMessageRequest) (*dto.LostItemMessageResponse, error)
+	GetMessages(ctx context.Context, userID, caseID string, isAdmin bool) ([]*dto.LostItemMessageResponse, error)
+
+	EscalateCase(ctx context.Context, userID, caseID string, req dto.EscalateCaseRequest) (*dto.LostItemCaseResponse, error)
+	ResolveCase(ctx context.Context, adminID, caseID string, req dto.ResolveCaseRequest) (*dto.LostItemCaseResponse, error)
+}
+
+type service struct {
+	repo          Repository
+	ridesRepo     ridesrepo.Repository
+	walletService walletservice.Service
+	eventProducer notificationsmodule.EventProducer
+}
+
+func NewService(repo Repository, ridesRepo ridesrepo.Repository, walletService walletservice.Service) Service {
+	return NewServiceWithNotifications(repo, ridesRepo, walletService, nil)
+}
+
+func NewServiceWithNotifications(
+	repo Repository,
+	ridesRepo ridesrepo.Repository,
+	walletService walletservice.Service,
+	eventProducer notificationsmodule.EventProducer,
+) Service {
+	return &service{
+		repo:          repo,
+		ridesRepo:     ridesRepo,
+		walletService: walletService,
+		eventProducer: eventProducer,
+	}
+}
+
+func (s *service) ReportLostItem(ctx context.Context, riderID, rideID string, req dto.ReportLostItemRequest) (*dto.LostItemCaseResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	ride, err := s.ridesRepo.FindRideByID(ctx, rideID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Ride")
+		}
+		return nil, response.InternalServerError("Failed to fetch ride", err)
+	}
+
+	if ride.RiderID != riderID {
+		return nil, response.ForbiddenError("You can only report items for your own rides")
+	}
+	if ride.Status != "completed" || ride.DriverID == nil {
+		return nil, response.BadRequest("Lost items can only be reported for completed rides")
+	}
+	if ride.CompletedAt != nil && time.Since(*ride.CompletedAt) > reportWindow {
+		return nil, response.BadRequest("This ride is too old to report a lost item")
+	}
+
+	if existing, err := s.repo.FindOpenCaseByRide(ctx, rideID); err == nil && existing != nil {
+		return nil, response.ConflictError("A lost item case is already open for this ride")
+	}
+
+	now := time.Now()
+	lostCase := &models.LostItemCase{
+		RideID:           rideID,
+		RiderID:          riderID,
+		DriverID:         *ride.DriverID,
+		ItemDescription:  req.ItemDescription,
+		ItemCategory:     req.ItemCategory,
+		ContactNote:      req.ContactNote,
+		Status:           models.LostItemStatusReported,
+		DriverNotifiedAt: &now,
+	}
+
+	if err := s.repo.CreateCase(ctx, lostCase); err != nil {
+		logger.Error("failed to create lost item case", "error", err, "rideID", rideID)
+		return nil, response.InternalServerError("Failed to report lost item", err)
+	}
+
+	resp := dto.ToLostItemCaseResponse(lostCase)
+
+	websocketutil.SendToUser(lostCase.DriverID, websocket.TypeLostItemUpdate, map[string]interface{}{
+		"caseId":          lostCase.ID,
+		"rideId":          rideID,
+		"status":          lostCase.Status,
+		"itemDescription": lostCase.ItemDescription,
+		"itemCategory":    lostCase.ItemCategory,
+		"responseOptions": resp.ResponseOptions,
+		"message":         "A rider reported an item left in your vehicle",
+	})
+
+	s.publishCaseEvent(notificationsmodule.EventLostItemReported, lostCase, map[string]interface{}{
+		"itemDescription": lostCase.ItemDescription,
+		"itemCategory":    lostCase.ItemCategory,
+	})
+
+	logger.Info("lost item reported", "caseID", lostCase.ID, "rideID", rideID, "riderID", riderID)
+	return resp, nil
+}
+
+func (s *service) GetCase(ctx context.Context, userID, caseID string, isAdmin bool) (*dto.LostItemCaseResponse, error) {
+	lostCase, err := s.getCaseForParticipant(ctx, userID, caseID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToLostItemCaseResponse(lostCase), nil
+}
+
+func (s *service) ListCases(ctx context.Context, userID string, req dto.ListLostItemsRequest) ([]*dto.LostItemCaseResponse, int64, error) {
+	cases, total, err := s.repo.ListCases(ctx, userID, req.Status, req.Page, req.Limit)
+	if err != nil {
+		logger.Error("failed to list lost item cases", "error", err, "userID", userID)
+		return nil, 0, response.InternalServerError("Failed to fetch lost item cases", err)
+	}
+
+	result := make([]*dto.LostItemCaseResponse, 0, len(cases))
+	for _, c := range cases {
+		result = append(result, dto.ToLostItemCaseResponse(c))
+	}
+	return result, total, nil
+}
+
+func (s *service) RespondToCase(ctx context.Context, driverID, caseID string, req dto.DriverResponseRequest) (*dto.LostItemCaseResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+	if req.ReturnFee > maxReturnFee {
+		return nil, response.BadRequest(fmt.Sprintf("Return fee cannot exceed %.2f", maxReturnFee))
+	}
+
+	lostCase, err := s.getCaseForParticipant(ctx, driverID, caseID, false)
+	if err != nil {
+		return nil, err
+	}
+	if lostCase.DriverID != driverID {
+		return nil, response.ForbiddenError("Only the trip driver can respond to this case")
+	}
+	if lostCase.Status != models.LostItemStatusReported {
+		return nil, response.BadRequest(fmt.Sprintf("Case cannot be answered, current status: %s", lostCase.Status))
+	}
+
+	now := time.Now()
+	lostCase.DriverResponse = req.Note
+	lostCase.RespondedAt = &now
+	if req.Response == "found" {
+		lostCase.Status = models.LostItemStatusFound
+		lostCase.ReturnFee = req.ReturnFee
+	} else {
+		lostCase.Status = models.LostItemStatusNotFound
+	}
+
+	if err := s.repo.UpdateCase(ctx, lostCase); err != nil {
+		return nil, response.InternalServerError("Failed to update lost item case", err)
+	}
+
+	s.notifyParticipant(lostCase, lostCase.RiderID, "The driver responded to your lost item report")
+	s.publishCaseEvent(notificationsmodule.EventLostItemUpdated, lostCase, map[string]interface{}{
+		"returnFee": lostCase.ReturnFee,
+	})
+
+	return dto.ToLostItemCaseResponse(lostCase), nil
+}
+
+func (s *service) ScheduleReturn(ctx context.Context, riderID, caseID string, req dto.ScheduleReturnRequest) (*dto.LostItemCaseResponse, error) {
+	lostCase, err := s.getCaseForParticipant(ctx, riderID, caseID, false)
+	if err != nil {
+		return nil, err
+	}
+	if lostCase.RiderID != riderID {
+		return nil, response.ForbiddenError("Only the rider can schedule the return")
+	}
+	if lostCase.Status != models.LostItemStatusFound {
+		return nil, response.BadRequest("Return can only be scheduled once the driver has found the item")
+	}
+
+	if req.ReturnAt != "" {
+		returnAt, err := time.Parse(time.RFC3339, req.ReturnAt)
+		if err != nil {
+			return nil, response.BadRequest("returnAt must be a valid RFC3339 timestamp")
+		}
+		lostCase.ReturnAt = &returnAt
+	}
+	lostCase.ReturnAddress = req.ReturnAddress
+
+	// The case is moved on before any money moves, so a retried or
+	// concurrent request cannot charge the rider twice.
+	claimed, err := s.repo.ClaimReturn(ctx, lostCase)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to update lost item case", err)
+	}
+	if !claimed {
+		return nil, response.ConflictError("Return has already been scheduled")
+	}
+	lostCase.Status = models.LostItemStatusReturnScheduled
+
+	if lostCase.ReturnFee > 0 && lostCase.ReturnFeeTxnID == nil {
+		metadata := map[string]interface{}{
+			"caseId": lostCase.ID,
+			"rideId": lostCase.RideID,
+		}
+
+		debitTxn, err := s.walletService.DebitWallet(ctx, riderID, lostCase.ReturnFee,
+			"lost_item_return_fee", lostCase.ID, "Lost item return fee", metadata)
+		if err != nil {
+			s.releaseReturn(ctx, lostCase.ID)
+			return nil, err
+		}
+
+		if _, err := s.walletService.CreditDriverWallet(ctx, lostCase.DriverID, lostCase.ReturnFee,
+			"lost_item_return_fee", lostCase.ID, "Lost item return fee", metadata); err != nil {
+			logger.Error("failed to credit driver for lost item return", "error", err, "caseID", lostCase.ID)
+			if _, refundErr := s.walletService.CreditWallet(ctx, riderID, lostCase.ReturnFee,
+				"lost_item_return_fee_refund", lostCase.ID, "Lost item return fee refund", metadata); refundErr != nil {
+				logger.Error("failed to refund rider after driver credit failure", "error", refundErr, "caseID", lostCase.ID)
+			}
+			s.releaseReturn(ctx, lostCase.ID)
+			return nil, response.InternalServerError("Failed to process return fee", err)
+		}
+
+		lostCase.ReturnFeeTxnID = &debitTxn.ID
+		if err := s.repo.SetReturnFeeTxn(ctx, lostCase.ID, debitTxn.ID); err != nil {
+			logger.Error("failed to record lost item return fee", "error", err, "caseID", lostCase.ID, "txnID", debitTxn.ID)
+		}
+	}
+
+	s.notifyParticipant(lostCase, lostCase.DriverID, "The rider scheduled the item return")
+	s.publishCaseEvent(notificationsmodule.EventLostItemUpdated, lostCase, nil)
+
+	return dto.ToLostItemCaseResponse(lostCase), nil
+}
+
+// releaseReturn puts a case whose return fee could not be taken back to
+// found, so the rider can schedule the return again.
+func (s *service) releaseReturn(ctx context.Context, caseID string) {
+	if err := s.repo.ReleaseReturn(ctx, caseID); err != nil {
+		logger.Error("failed to release lost item return", "error", err, "caseID", caseID)
+	}
+}
+
+func (s *service) MarkReturned(ctx context.Context, userID, caseID string) (*dto.LostItemCaseResponse, error) {
+	lostCase, err := s.getCaseForParticipant(ctx, userID, caseID, false)
+	if err != nil {
+		return nil, err
+	}
+	if lostCase.Status != models.LostItemStatusReturnScheduled {
+		return nil, response.BadRequest("Item return has not been scheduled")
+	}
+
+	now := time.Now()
+	lostCase.Status = models.LostItemStatusReturned
+	lostCase.ReturnedAt = &now
+	lostCase.ClosedAt = &now
+
+	if err := s.repo.UpdateCase(ctx, lostCase); err != nil {
+		return nil, response.InternalServerError("Failed to update lost item case", err)
+	}
+
+	s.notifyParticipant(lostCase, s.counterpartOf(lostCase, userID), "The lost item was marked as returned")
+	s.publishCaseEvent(notificationsmodule.EventLostItemUpdated, lostCase, nil)
+
+	return dto.ToLostItemCaseResponse(lostCase), nil
+}
+
+func (s *service) CloseCase(ctx context.Context, riderID, caseID string) (*dto.LostItemCaseResponse, error) {
+	lostCase, err := s.getCaseForParticipant(ctx, riderID, caseID, false)
+	if err != nil {
+		return nil, err
+	}
+	if lostCase.RiderID != riderID {
+		return nil, response.ForbiddenError("Only the rider can close this case")
+	}
+	if !lostCase.IsOpen() {
+		return nil, response.BadRequest("Case is already closed")
+	}
+	if lostCase.Status == models.LostItemStatusReturnScheduled {
+		return nil, response.BadRequest("Case has a scheduled return, mark it returned or escalate instead")
+	}
+
+	now := time.Now()
+	lostCase.Status = models.LostItemStatusClosed
+	lostCase.ClosedAt = &now
+
+	if err := s.repo.UpdateCase(ctx, lostCase); err != nil {
+		return nil, response.InternalServerError("Failed to update lost item case", err)
+	}
+
+	s.publishCaseEvent(notificationsmodule.EventLostItemUpdated, lostCase, nil)
+	return dto.ToLostItemCaseResponse(lostCase), nil
+}
+
+func (s *service) SendMessage(ctx context.Context, userID, caseID string, req dto.SendCaseMessageRequest) (*dto.LostItemMessageResponse, error) {
+	lostCase, err := s.getCaseForParticipant(ctx, userID, caseID, false)
+	if err != nil {
+		return nil, err
+	}
+	if !lostCase.IsOpen() {
+		return nil, response.BadRequest("Case is closed")
+	}
+
+	senderRole := "rider"
+	if lostCase.DriverID == userID {
+		senderRole = "driver"
+	}
+
+	msg := &models.LostItemMessage{
+		CaseID:     lostCase.ID,
+		SenderID:   userID,
+		SenderRole: senderRole,
+		Content:    req.Content,
+	}
+	if err := s.repo.CreateMessage(ctx, msg); err != nil {
+		return nil, response.InternalServerError("Failed to send message", err)
+	}
+
+	// Relay through the case so the counterpart only ever sees the role, never contact details.
+	websocketutil.SendToUser(s.counterpartOf(lostCase, userID), websocket.TypeLostItemMessage, map[string]interface{}{
+		"caseId":     lostCase.ID,
+		"messageId":  msg.ID,
+		"senderRole": senderRole,
+		"content":    msg.Content,
+		"createdAt":  msg.CreatedAt,
+	})
+
+	return dto.ToLostItemMessageResponse(msg, userID), nil
+}
+
+func (s *service) GetMessages(ctx context.Context, userID, caseID string, isAdmin bool) ([]*dto.LostItemMessageResponse, error) {
+	lostCase, err := s.getCaseForParticipant(ctx, userID, caseID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	msgs, err := s.repo.ListMessages(ctx, lostCase.ID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to fetch messages", err)
+	}
+
+	result := make([]*dto.LostItemMessageResponse, 0, len(msgs))
+	for _, m := range msgs {
+		result = append(result, dto.ToLostItemMessageResponse(m, userID))
+	}
+	return result, nil
+}
+
+func (s *service) EscalateCase(ctx context.Context, userID, caseID string, req dto.EscalateCaseRequest) (*dto.LostItemCaseResponse, error) {
+	lostCase, err := s.getCaseForParticipant(ctx, userID, caseID, false)
+	if err != nil {
+		return nil, err
+	}
+	if !lostCase.IsOpen() && lostCase.Status != models.LostItemStatusClosed {
+		return nil, response.BadRequest("Case can no longer be escalated")
+	}
+	if lostCase.Status == models.LostItemStatusEscalated {
+		return nil, response.ConflictError("Case is already escalated")
+	}
+
+	now := time.Now()
+	lostCase.Status = models.LostItemStatusEscalated
+	lostCase.EscalatedBy = &userID
+	lostCase.EscalationNote = req.Note
+	lostCase.EscalatedAt = &now
+	lostCase.ClosedAt = nil
+
+	if err := s.repo.UpdateCase(ctx, lostCase); err != nil {
+		return nil, response.InternalServerError("Failed to escalate lost item case", err)
+	}
+
+	websocketutil.BroadcastToRole("admin", websocket.TypeLostItemUpdate, map[string]interface{}{
+		"caseId":      lostCase.ID,
+		"rideId":      lostCase.RideID,
+		"status":      lostCase.Status,
+		"escalatedBy": userID,
+		"note":        req.Note,
+	})
+	s.notifyParticipant(lostCase, s.counterpartOf(lostCase, userID), "The lost item case was escalated to support")
+	s.publishCaseEvent(notificationsmodule.EventLostItemEscalated, lostCase, map[string]interface{}{
+		"escalatedBy": userID,
+		"note":        req.Note,
+	})
+
+	logger.Warn("lost item case escalated", "caseID", lostCase.ID, "escalatedBy", userID)
+	return dto.ToLostItemCaseResponse(lostCase), nil
+}
+
+func (s *service) ResolveCase(ctx context.Context, adminID, caseID string, req dto.ResolveCaseRequest) (*dto.LostItemCaseResponse, error) {
+	lostCase, err := s.getCaseForParticipant(ctx, adminID, caseID, true)
+	if err != nil {
+		return nil, err
+	}
+	if lostCase.Status == models.LostItemStatusResolved {
+		return nil, response.BadRequest("Case is already resolved")
+	}
+
+	if req.RefundFee && lostCase.ReturnFeeTxnID != nil && lostCase.ReturnFee > 0 {
+		metadata := map[string]interface{}{
+			"caseId":     lostCase.ID,
+			"resolvedBy": adminID,
+		}
+		if _, err := s.walletService.CreditWallet(ctx, lostCase.RiderID, lostCase.ReturnFee,
+			"lost_item_return_fee_refund", lostCase.ID, "Lost item return fee refund", metadata); err != nil {
+			return nil, response.InternalServerError("Failed to refund return fee", err)
+		}
+		if _, err := s.walletService.DebitDriverWallet(ctx, lostCase.DriverID, lostCase.ReturnFee,
+			"lost_item_return_fee_reversal", lostCase.ID, "Lost item return fee reversal", metadata); err != nil {
+			logger.Error("failed to reverse driver return fee", "error", err, "caseID", lostCase.ID)
+		}
+		lostCase.ReturnFeeTxnID = nil
+	}
+
+	now := time.Now()
+	lostCase.Status = models.LostItemStatusResolved
+	lostCase.ResolvedBy = &adminID
+	lostCase.ResolutionNote = req.Note
+	lostCase.ClosedAt = &now
+
+	if err := s.repo.UpdateCase(ctx, lostCase); err != nil {
+		return nil, response.InternalServerError("Failed to resolve lost item case", err)
+	}
+
+	s.notifyParticipant(lostCase, lostCase.RiderID, "Support resolved your lost item case")
+	s.notifyParticipant(lostCase, lostCase.DriverID, "Support resolved the lost item case")
+	s.publishCaseEvent(notificationsmodule.EventLostItemUpdated, lostCase, map[string]interface{}{
+		"resolvedBy": adminID,
+	})
+
+	return dto.ToLostItemCaseResponse(lostCase), nil
+}
+
+func (s *service) getCaseForParticipant(ctx context.Context, userID, caseID string, isAdmin bool) (*models.LostItemCase, error) {
+	if caseID == "" {
+		return nil, response.BadRequest("Case ID is required")
+	}
+
+	lostCase, err := s.repo.FindCaseByID(ctx, caseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Lost item case")
+		}
+		return nil, response.InternalServerError("Failed to fetch lost item case", err)
+	}
+
+	if !isAdmin && lostCase.RiderID != userID && lostCase.DriverID != userID {
+		return nil, response.ForbiddenError("You are not part of this case")
+	}
+
+	return lostCase, nil
+}
+
+func (s *service) counterpartOf(lostCase *models.LostItemCase, userID string) string {
+	if lostCase.RiderID == userID {
+		return lostCase.DriverID
+	}
+	return lostCase.RiderID
+}
+
+func (s *service) notifyParticipant(lostCase *models.LostItemCase, userID, message string) {
+	websocketutil.SendToUser(userID, websocket.TypeLostItemUpdate, map[string]interface{}{
+		"caseId":    lostCase.ID,
+		"rideId":    lostCase.RideID,
+		"status":    lostCase.Status,
+		"returnFee": lostCase.ReturnFee,
+		"message":   message,
+	})
+}
+
+func (s *service) publishCaseEvent(eventType notificationsmodule.EventType, lostCase *models.LostItemCase, data map[string]interface{}) {
+	if s.eventProducer == nil {
+		logger.Debug("event producer not available, skipping lost item event", "eventType", eventType, "caseID", lostCase.ID)
+		return
+	}
+
+	payload := map[string]interface{}{
+		"case_id":   lostCase.ID,
+		"ride_id":   lostCase.RideID,
+		"rider_id":  lostCase.RiderID,
+		"driver_id": lostCase.DriverID,
+		"status":    lostCase.Status,
+		"timestamp": time.Now().UTC(),
+	}
+	for k, v := range data {
+		payload[k] = v
+	}
+
+	go func() {
+		if err := s.eventProducer.PublishEventWithKey(context.Background(), eventType, lostCase.ID, payload); err != nil {
+			logger.Error("failed to publish lost item event", "error", err, "eventType", eventType, "caseID", lostCase.ID)
+		}
+	}()
+}
@@ -0,0 +1,8 @@
+package loyalty
+
+import "github.com/umar5678/go-backend/internal/services/eventbus"
+
+// Subscribe awards loyalty points from completed rides and orders on the bus.
+func Subscribe(bus eventbus.Bus, service Service) error {
+	return bus.Subscribe("loyalty", service.HandleEvent, eventbus.RideCompleted, eventbus.OrderCompleted)
+}
@@ -0,0 +1,23 @@
+package dto
+
+type RedeemPointsRequest struct {
+	Points int64 `json:"points" binding:"required,min=1"`
+}
+
+type ListTransactionsRequest struct {
+	Type  string `form:"type" binding:"omitempty,oneof=earn redeem expire"`
+	Page  int    `form:"page" binding:"omitempty,min=1"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (r *ListTransactionsRequest) SetDefaults() {
+	if r.Page <= 0 {
+		r.Page = 1
+	}
+	if r.Limit <= 0 {
+		r.Limit = 20
+	}
+	if r.Limit > 100 {
+		r.Limit = 100
+	}
+}
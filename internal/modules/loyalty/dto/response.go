@@ -0,0 +1,73 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+// LoyaltySummaryResponse is a customer's points balance, tier and perks, and
+// what it takes to reach the next tier.
+type LoyaltySummaryResponse struct {
+	Balance        int64               `json:"balance"`
+	BalanceValue   float64             `json:"balanceValue"`
+	LifetimePoints int64               `json:"lifetimePoints"`
+	Tier           string              `json:"tier"`
+	TierPoints     int64               `json:"tierPoints"`
+	Perks          models.LoyaltyPerks `json:"perks"`
+
+	// NextTier is empty at the top tier.
+	NextTier         string  `json:"nextTier,omitempty"`
+	PointsToNextTier int64   `json:"pointsToNextTier,omitempty"`
+	TierWindowDays   int     `json:"tierWindowDays"`
+	PointsPerUnit    float64 `json:"pointsPerUnit"`
+	PointValue       float64 `json:"pointValue"`
+	MinRedeemPoints  int64   `json:"minRedeemPoints"`
+
+	// ExpiringPoints lapse by ExpiringBy unless redeemed first.
+	ExpiringPoints int64     `json:"expiringPoints"`
+	ExpiringBy     time.Time `json:"expiringBy"`
+}
+
+type LoyaltyTransactionResponse struct {
+	ID           string     `json:"id"`
+	Type         string     `json:"type"`
+	Points       int64      `json:"points"`
+	SourceType   *string    `json:"sourceType,omitempty"`
+	SourceID     *string    `json:"sourceId,omitempty"`
+	Spend        float64    `json:"spend,omitempty"`
+	WalletAmount float64    `json:"walletAmount,omitempty"`
+	ExpiresAt    *time.Time `json:"expiresAt,omitempty"`
+	Description  string     `json:"description"`
+	CreatedAt    time.Time  `json:"createdAt"`
+}
+
+type RedeemPointsResponse struct {
+	TransactionID string  `json:"transactionId"`
+	Points        int64   `json:"points"`
+	WalletAmount  float64 `json:"walletAmount"`
+	Balance       int64   `json:"balance"`
+}
+
+func ToLoyaltyTransactionResponse(txn *models.LoyaltyTransaction) *LoyaltyTransactionResponse {
+	return &LoyaltyTransactionResponse{
+		ID:           txn.ID,
+		Type:         txn.Type,
+		Points:       txn.Points,
+		SourceType:   txn.SourceType,
+		SourceID:     txn.SourceID,
+		Spend:        txn.Spend,
+		WalletAmount: txn.WalletAmount,
+		ExpiresAt:    txn.ExpiresAt,
+		Description:  txn.Description,
+		CreatedAt:    txn.CreatedAt,
+	}
+}
+
+func ToLoyaltyTransactionResponses(txns []*models.LoyaltyTransaction) []*LoyaltyTransactionResponse {
+	result := make([]*LoyaltyTransactionResponse, 0, len(txns))
+	for _, txn := range txns {
+		result = append(result, ToLoyaltyTransactionResponse(txn))
+	}
+	return result
+}
@@ -0,0 +1,94 @@
+package loyalty
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/modules/loyalty/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// GetSummary godoc
+// @Summary Get my loyalty points and tier
+// @Description Points balance and its wallet value, tier and perks, progress to the next tier and points lapsing in the next 30 days
+// @Tags loyalty
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=dto.LoyaltySummaryResponse}
+// @Router /loyalty/me [get]
+func (h *Handler) GetSummary(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	summary, err := h.service.GetSummary(c.Request.Context(), userID.(string))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, summary, "Loyalty summary retrieved successfully")
+}
+
+// ListTransactions godoc
+// @Summary List my loyalty points history
+// @Description Points earned, redeemed and expired, newest first
+// @Tags loyalty
+// @Security BearerAuth
+// @Produce json
+// @Param type query string false "earn, redeem or expire"
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Success 200 {object} response.Response{data=[]dto.LoyaltyTransactionResponse}
+// @Router /loyalty/transactions [get]
+func (h *Handler) ListTransactions(c *gin.Context) {
+	var req dto.ListTransactionsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+	req.SetDefaults()
+
+	userID, _ := c.Get("userID")
+
+	txns, total, err := h.service.ListTransactions(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Paginated(c, txns, response.NewPaginationMeta(total, req.Page, req.Limit), "Loyalty transactions retrieved successfully")
+}
+
+// RedeemPoints godoc
+// @Summary Redeem loyalty points
+// @Description Turns points into wallet credit. The oldest points are used first
+// @Tags loyalty
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.RedeemPointsRequest true "Points to redeem"
+// @Success 200 {object} response.Response{data=dto.RedeemPointsResponse}
+// @Failure 400 {object} response.Response
+// @Router /loyalty/redeem [post]
+func (h *Handler) RedeemPoints(c *gin.Context) {
+	var req dto.RedeemPointsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+
+	result, err := h.service.RedeemPoints(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Points redeemed successfully")
+}
@@ -0,0 +1,246 @@
+package loyalty
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrInsufficientPoints = errors.New("insufficient loyalty points")
+
+type Repository interface {
+	GetAccount(ctx context.Context, userID string) (*models.LoyaltyAccount, error)
+	UpdateTier(ctx context.Context, userID, tier string, tierPoints int64, at time.Time) error
+	EarnedSince(ctx context.Context, userID string, since time.Time) (int64, error)
+	ListTransactions(ctx context.Context, userID, txnType string, page, limit int) ([]*models.LoyaltyTransaction, int64, error)
+
+	Earn(ctx context.Context, txn *models.LoyaltyTransaction) (bool, error)
+	Redeem(ctx context.Context, txn *models.LoyaltyTransaction) error
+	ReverseRedemption(ctx context.Context, txn *models.LoyaltyTransaction) error
+
+	ExpirablePoints(ctx context.Context, userID string, at time.Time) (int64, error)
+	ExpirePoints(ctx context.Context, userID string, now time.Time) (int64, error)
+	FindUsersWithExpiredPoints(ctx context.Context, now time.Time, limit int) ([]string, error)
+	FindTierReviewsDue(ctx context.Context, before time.Time, limit int) ([]string, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) GetAccount(ctx context.Context, userID string) (*models.LoyaltyAccount, error) {
+	var account models.LoyaltyAccount
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&account).Error
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *repository) UpdateTier(ctx context.Context, userID, tier string, tierPoints int64, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.LoyaltyAccount{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]interface{}{
+			"tier":            tier,
+			"tier_points":     tierPoints,
+			"tier_updated_at": at,
+		}).Error
+}
+
+func (r *repository) EarnedSince(ctx context.Context, userID string, since time.Time) (int64, error) {
+	var points int64
+	err := r.db.WithContext(ctx).
+		Model(&models.LoyaltyTransaction{}).
+		Select("COALESCE(SUM(points), 0)").
+		Where("user_id = ? AND type = ? AND created_at >= ?", userID, models.LoyaltyTransactionEarn, since).
+		Scan(&points).Error
+	return points, err
+}
+
+func (r *repository) ListTransactions(ctx context.Context, userID, txnType string, page, limit int) ([]*models.LoyaltyTransaction, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.LoyaltyTransaction{}).Where("user_id = ?", userID)
+	if txnType != "" {
+		query = query.Where("type = ?", txnType)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var txns []*models.LoyaltyTransaction
+	err := query.
+		Order("created_at DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&txns).Error
+	return txns, total, err
+}
+
+// Earn records points earned and adds them to the account, opening it on the
+// customer's first earn. It reports false without changing anything when the
+// ride or order has already earned points.
+func (r *repository) Earn(ctx context.Context, txn *models.LoyaltyTransaction) (bool, error) {
+	created := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(txn)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		created = true
+
+		account := &models.LoyaltyAccount{
+			UserID:         txn.UserID,
+			Balance:        txn.Points,
+			LifetimePoints: txn.Points,
+			Tier:           models.LoyaltyTierBronze,
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"balance":         gorm.Expr("loyalty_accounts.balance + ?", txn.Points),
+				"lifetime_points": gorm.Expr("loyalty_accounts.lifetime_points + ?", txn.Points),
+				"updated_at":      time.Now(),
+			}),
+		}).Create(account).Error
+	})
+	return created && err == nil, err
+}
+
+// Redeem takes the redeemed points, held as a negative amount on txn, off the
+// account. It returns ErrInsufficientPoints if the balance does not cover
+// them.
+func (r *repository) Redeem(ctx context.Context, txn *models.LoyaltyTransaction) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var account models.LoyaltyAccount
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ?", txn.UserID).
+			First(&account).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInsufficientPoints
+		}
+		if err != nil {
+			return err
+		}
+		if account.Balance < -txn.Points {
+			return ErrInsufficientPoints
+		}
+
+		if err := tx.Create(txn).Error; err != nil {
+			return err
+		}
+		return tx.Model(&account).
+			Update("balance", gorm.Expr("balance + ?", txn.Points)).Error
+	})
+}
+
+// ReverseRedemption puts back the points of a redemption whose wallet credit
+// could not be made.
+func (r *repository) ReverseRedemption(ctx context.Context, txn *models.LoyaltyTransaction) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ? AND type = ?", txn.ID, models.LoyaltyTransactionRedeem).
+			Delete(&models.LoyaltyTransaction{})
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return tx.Model(&models.LoyaltyAccount{}).
+			Where("user_id = ?", txn.UserID).
+			Update("balance", gorm.Expr("balance - ?", txn.Points)).Error
+	})
+}
+
+// expirableSQL is how many of a customer's points have lapsed by a given
+// time: everything earned that is past its date, less what has been redeemed
+// or has already expired. Redemptions use up the oldest points first, so
+// they count against the points that lapse first.
+const expirableSQL = `GREATEST(
+	COALESCE(SUM(points) FILTER (WHERE type = 'earn' AND expires_at <= ?), 0)
+	+ COALESCE(SUM(points) FILTER (WHERE type <> 'earn'), 0), 0)`
+
+func expirablePoints(db *gorm.DB, userID string, at time.Time) (int64, error) {
+	var points int64
+	err := db.Model(&models.LoyaltyTransaction{}).
+		Select(expirableSQL, at).
+		Where("user_id = ?", userID).
+		Scan(&points).Error
+	return points, err
+}
+
+// ExpirablePoints is how many of the customer's current points will have
+// lapsed by at.
+func (r *repository) ExpirablePoints(ctx context.Context, userID string, at time.Time) (int64, error) {
+	return expirablePoints(r.db.WithContext(ctx), userID, at)
+}
+
+// ExpirePoints takes the customer's lapsed points off their balance and
+// returns how many that was.
+func (r *repository) ExpirePoints(ctx context.Context, userID string, now time.Time) (int64, error) {
+	var expired int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var account models.LoyaltyAccount
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ?", userID).
+			First(&account).Error
+		if err != nil {
+			return err
+		}
+
+		points, err := expirablePoints(tx, userID, now)
+		if err != nil || points <= 0 {
+			return err
+		}
+		points = min(points, account.Balance)
+		if points <= 0 {
+			return nil
+		}
+
+		txn := &models.LoyaltyTransaction{
+			UserID:      userID,
+			Type:        models.LoyaltyTransactionExpire,
+			Points:      -points,
+			Description: "Points expired",
+		}
+		if err := tx.Create(txn).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&account).Update("balance", gorm.Expr("balance - ?", points)).Error; err != nil {
+			return err
+		}
+		expired = points
+		return nil
+	})
+	return expired, err
+}
+
+func (r *repository) FindUsersWithExpiredPoints(ctx context.Context, now time.Time, limit int) ([]string, error) {
+	var userIDs []string
+	err := r.db.WithContext(ctx).
+		Model(&models.LoyaltyTransaction{}).
+		Group("user_id").
+		Having(expirableSQL+" > 0", now).
+		Limit(limit).
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// FindTierReviewsDue returns customers above bronze whose tier has not been
+// worked out since before, as points leaving the tier window may drop them.
+func (r *repository) FindTierReviewsDue(ctx context.Context, before time.Time, limit int) ([]string, error) {
+	var userIDs []string
+	err := r.db.WithContext(ctx).
+		Model(&models.LoyaltyAccount{}).
+		Where("tier <> ? AND (tier_updated_at IS NULL OR tier_updated_at < ?)", models.LoyaltyTierBronze, before).
+		Order("tier_updated_at ASC NULLS FIRST").
+		Limit(limit).
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
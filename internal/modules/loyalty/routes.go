@@ -0,0 +1,15 @@
+package loyalty
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
+	loyalty := router.Group("/loyalty")
+	loyalty.Use(authMiddleware)
+	{
+		loyalty.GET("/me", handler.GetSummary)
+		loyalty.GET("/transactions", handler.ListTransactions)
+		loyalty.POST("/redeem", handler.RedeemPoints)
+	}
+}
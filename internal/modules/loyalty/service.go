@@ -0,0 +1,383 @@
+package loyalty
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/loyalty/dto"
+	"github.com/umar5678/go-backend/internal/services/eventbus"
+	"github.com/umar5678/go-backend/internal/services/health"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+	websocketutil "github.com/umar5678/go-backend/internal/websocket/websocketutils"
+	"gorm.io/gorm"
+)
+
+const (
+	redemptionTransactionType = "loyalty_redemption"
+
+	// expiringSoonWindow is how far ahead the summary warns of lapsing points.
+	expiringSoonWindow = 30 * 24 * time.Hour
+
+	// tierReviewAge is how often a tier above bronze is worked out again
+	// while the customer earns nothing.
+	tierReviewAge = 24 * time.Hour
+
+	sweepBatchSize = 200
+)
+
+type WalletService interface {
+	CreditWallet(ctx context.Context, userID string, amount float64, transactionType, referenceID, description string, metadata map[string]interface{}) (*models.WalletTransaction, error)
+}
+
+type Service interface {
+	GetSummary(ctx context.Context, userID string) (*dto.LoyaltySummaryResponse, error)
+	ListTransactions(ctx context.Context, userID string, req dto.ListTransactionsRequest) ([]*dto.LoyaltyTransactionResponse, int64, error)
+	RedeemPoints(ctx context.Context, userID string, req dto.RedeemPointsRequest) (*dto.RedeemPointsResponse, error)
+
+	// Perks returns what the customer's tier unlocks. A customer without an
+	// account, or whose account cannot be read, gets none.
+	Perks(ctx context.Context, userID string) models.LoyaltyPerks
+
+	// HandleEvent awards points for completed rides and orders.
+	HandleEvent(ctx context.Context, event eventbus.Event) error
+
+	// Sweep expires lapsed points and moves customers down a tier once the
+	// points that earned it leave the tier window.
+	Sweep(ctx context.Context) error
+}
+
+type service struct {
+	repo          Repository
+	walletService WalletService
+	cfg           config.LoyaltyConfig
+}
+
+func NewService(repo Repository, walletService WalletService, cfg config.LoyaltyConfig) Service {
+	return &service{repo: repo, walletService: walletService, cfg: cfg}
+}
+
+func (s *service) GetSummary(ctx context.Context, userID string) (*dto.LoyaltySummaryResponse, error) {
+	account, err := s.repo.GetAccount(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		account = &models.LoyaltyAccount{UserID: userID, Tier: models.LoyaltyTierBronze}
+	} else if err != nil {
+		return nil, response.InternalServerError("Failed to get loyalty summary", err)
+	}
+
+	now := time.Now()
+	expiringBy := now.Add(expiringSoonWindow)
+	expiring, err := s.repo.ExpirablePoints(ctx, userID, expiringBy)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get loyalty summary", err)
+	}
+
+	summary := &dto.LoyaltySummaryResponse{
+		Balance:         account.Balance,
+		BalanceValue:    s.pointsValue(account.Balance),
+		LifetimePoints:  account.LifetimePoints,
+		Tier:            account.Tier,
+		TierPoints:      account.TierPoints,
+		Perks:           s.perksFor(account.Tier),
+		TierWindowDays:  int(s.cfg.TierWindow / (24 * time.Hour)),
+		PointsPerUnit:   s.cfg.PointsPerUnit,
+		PointValue:      s.cfg.PointValue,
+		MinRedeemPoints: s.cfg.MinRedeemPoints,
+		ExpiringPoints:  min(expiring, account.Balance),
+		ExpiringBy:      expiringBy,
+	}
+	switch account.Tier {
+	case models.LoyaltyTierBronze:
+		summary.NextTier = models.LoyaltyTierSilver
+		summary.PointsToNextTier = max(s.cfg.SilverPoints-account.TierPoints, 0)
+	case models.LoyaltyTierSilver:
+		summary.NextTier = models.LoyaltyTierGold
+		summary.PointsToNextTier = max(s.cfg.GoldPoints-account.TierPoints, 0)
+	}
+	return summary, nil
+}
+
+func (s *service) ListTransactions(ctx context.Context, userID string, req dto.ListTransactionsRequest) ([]*dto.LoyaltyTransactionResponse, int64, error) {
+	txns, total, err := s.repo.ListTransactions(ctx, userID, req.Type, req.Page, req.Limit)
+	if err != nil {
+		return nil, 0, response.InternalServerError("Failed to list loyalty transactions", err)
+	}
+	return dto.ToLoyaltyTransactionResponses(txns), total, nil
+}
+
+// RedeemPoints turns points into wallet credit. The points are taken first
+// and put back if the wallet cannot be credited.
+func (s *service) RedeemPoints(ctx context.Context, userID string, req dto.RedeemPointsRequest) (*dto.RedeemPointsResponse, error) {
+	if !s.cfg.Enabled {
+		return nil, response.BadRequest("The loyalty program is not available")
+	}
+	if req.Points < s.cfg.MinRedeemPoints {
+		return nil, response.BadRequest(fmt.Sprintf("At least %d points must be redeemed at a time", s.cfg.MinRedeemPoints))
+	}
+
+	amount := s.pointsValue(req.Points)
+	txn := &models.LoyaltyTransaction{
+		UserID:       userID,
+		Type:         models.LoyaltyTransactionRedeem,
+		Points:       -req.Points,
+		WalletAmount: amount,
+		Description:  "Points redeemed for wallet credit",
+	}
+	if err := s.repo.Redeem(ctx, txn); err != nil {
+		if errors.Is(err, ErrInsufficientPoints) {
+			return nil, response.BadRequest("You do not have enough points")
+		}
+		return nil, response.InternalServerError("Failed to redeem points", err)
+	}
+
+	metadata := map[string]interface{}{"loyalty_transaction_id": txn.ID, "points": req.Points}
+	if _, err := s.walletService.CreditWallet(ctx, userID, amount, redemptionTransactionType, txn.ID, fmt.Sprintf("Redeemed %d loyalty points", req.Points), metadata); err != nil {
+		logger.Error("failed to credit loyalty redemption", "error", err, "userID", userID, "transactionID", txn.ID)
+		if err := s.repo.ReverseRedemption(ctx, txn); err != nil {
+			logger.Error("failed to reverse loyalty redemption", "error", err, "userID", userID, "transactionID", txn.ID)
+		}
+		return nil, response.InternalServerError("Failed to redeem points", err)
+	}
+
+	account, err := s.repo.GetAccount(ctx, userID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get loyalty balance", err)
+	}
+
+	logger.Info("loyalty points redeemed", "userID", userID, "points", req.Points, "amount", amount, "transactionID", txn.ID)
+
+	return &dto.RedeemPointsResponse{
+		TransactionID: txn.ID,
+		Points:        req.Points,
+		WalletAmount:  amount,
+		Balance:       account.Balance,
+	}, nil
+}
+
+func (s *service) Perks(ctx context.Context, userID string) models.LoyaltyPerks {
+	if !s.cfg.Enabled {
+		return models.LoyaltyPerks{}
+	}
+	account, err := s.repo.GetAccount(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			logger.Warn("failed to load loyalty tier", "error", err, "userID", userID)
+		}
+		return models.LoyaltyPerks{}
+	}
+	return s.perksFor(account.Tier)
+}
+
+func (s *service) HandleEvent(ctx context.Context, event eventbus.Event) error {
+	if !s.cfg.Enabled {
+		return nil
+	}
+
+	switch event.Type {
+	case eventbus.RideCompleted:
+		var payload eventbus.RideCompletedPayload
+		if err := event.Decode(&payload); err != nil {
+			return fmt.Errorf("decode %s payload: %w", event.Type, err)
+		}
+		// Tax is passed on, not spent with the platform, so it earns nothing.
+		return s.earn(ctx, payload.RiderID, models.LoyaltySourceRide, payload.RideID, payload.Fare-payload.TaxAmount)
+
+	case eventbus.OrderCompleted:
+		var payload eventbus.OrderCompletedPayload
+		if err := event.Decode(&payload); err != nil {
+			return fmt.Errorf("decode %s payload: %w", event.Type, err)
+		}
+		return s.earn(ctx, payload.CustomerID, models.LoyaltySourceOrder, payload.OrderID, payload.TotalPrice-payload.TaxAmount)
+	}
+	return nil
+}
+
+// earn awards points for a completed ride or order once, then works out the
+// customer's tier again.
+func (s *service) earn(ctx context.Context, userID, sourceType, sourceID string, spend float64) error {
+	points := int64(math.Floor(spend * s.cfg.PointsPerUnit))
+	if userID == "" || points <= 0 {
+		return nil
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(s.cfg.PointsExpiry)
+	txn := &models.LoyaltyTransaction{
+		UserID:      userID,
+		Type:        models.LoyaltyTransactionEarn,
+		Points:      points,
+		SourceType:  &sourceType,
+		SourceID:    &sourceID,
+		Spend:       math.Round(spend*100) / 100,
+		ExpiresAt:   &expiresAt,
+		Description: fmt.Sprintf("Points earned on %s", sourceType),
+	}
+	earned, err := s.repo.Earn(ctx, txn)
+	if err != nil || !earned {
+		return err
+	}
+
+	logger.Info("loyalty points earned", "userID", userID, "points", points, "sourceType", sourceType, "sourceID", sourceID)
+
+	account, err := s.repo.GetAccount(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := websocketutil.SendToUser(userID, websocket.TypeLoyaltyPointsEarned, map[string]interface{}{
+		"points":     points,
+		"balance":    account.Balance,
+		"sourceType": sourceType,
+		"sourceId":   sourceID,
+		"expiresAt":  expiresAt,
+	}); err != nil {
+		logger.Warn("failed to notify loyalty points earned", "error", err, "userID", userID)
+	}
+
+	return s.refreshTier(ctx, account, now)
+}
+
+// refreshTier works out the customer's tier from the points they earned
+// within the tier window and tells them if it changed.
+func (s *service) refreshTier(ctx context.Context, account *models.LoyaltyAccount, now time.Time) error {
+	tierPoints, err := s.repo.EarnedSince(ctx, account.UserID, now.Add(-s.cfg.TierWindow))
+	if err != nil {
+		return err
+	}
+	tier := s.tierFor(tierPoints)
+	if err := s.repo.UpdateTier(ctx, account.UserID, tier, tierPoints, now); err != nil {
+		return err
+	}
+	if tier == account.Tier {
+		return nil
+	}
+
+	logger.Info("loyalty tier changed", "userID", account.UserID, "from", account.Tier, "to", tier, "tierPoints", tierPoints)
+
+	if err := websocketutil.SendToUser(account.UserID, websocket.TypeLoyaltyTierChanged, map[string]interface{}{
+		"previousTier": account.Tier,
+		"tier":         tier,
+		"tierPoints":   tierPoints,
+		"perks":        s.perksFor(tier),
+	}); err != nil {
+		logger.Warn("failed to notify loyalty tier change", "error", err, "userID", account.UserID)
+	}
+	return nil
+}
+
+func (s *service) Sweep(ctx context.Context) error {
+	if !s.cfg.Enabled {
+		return nil
+	}
+	now := time.Now()
+
+	lapsed, err := s.repo.FindUsersWithExpiredPoints(ctx, now, sweepBatchSize)
+	if err != nil {
+		return err
+	}
+	expired := 0
+	for _, userID := range lapsed {
+		points, err := s.repo.ExpirePoints(ctx, userID, now)
+		if err != nil {
+			logger.Error("failed to expire loyalty points", "error", err, "userID", userID)
+			continue
+		}
+		if points > 0 {
+			expired++
+			logger.Info("loyalty points expired", "userID", userID, "points", points)
+		}
+	}
+
+	due, err := s.repo.FindTierReviewsDue(ctx, now.Add(-tierReviewAge), sweepBatchSize)
+	if err != nil {
+		return err
+	}
+	for _, userID := range due {
+		account, err := s.repo.GetAccount(ctx, userID)
+		if err != nil {
+			logger.Error("failed to load loyalty account", "error", err, "userID", userID)
+			continue
+		}
+		if err := s.refreshTier(ctx, account, now); err != nil {
+			logger.Error("failed to review loyalty tier", "error", err, "userID", userID)
+		}
+	}
+
+	if expired > 0 || len(due) > 0 {
+		logger.Info("loyalty sweep completed", "expired", expired, "tiersReviewed", len(due))
+	}
+	return nil
+}
+
+func (s *service) tierFor(points int64) string {
+	switch {
+	case points >= s.cfg.GoldPoints:
+		return models.LoyaltyTierGold
+	case points >= s.cfg.SilverPoints:
+		return models.LoyaltyTierSilver
+	default:
+		return models.LoyaltyTierBronze
+	}
+}
+
+func (s *service) perksFor(tier string) models.LoyaltyPerks {
+	switch tier {
+	case models.LoyaltyTierGold:
+		return models.LoyaltyPerks{
+			BookingFeeDiscount: s.cfg.GoldFeeDiscount,
+			PriorityMatching:   s.cfg.GoldPriorityMatching,
+		}
+	case models.LoyaltyTierSilver:
+		return models.LoyaltyPerks{BookingFeeDiscount: s.cfg.SilverFeeDiscount}
+	default:
+		return models.LoyaltyPerks{}
+	}
+}
+
+func (s *service) pointsValue(points int64) float64 {
+	return math.Round(float64(points)*s.cfg.PointValue*100) / 100
+}
+
+// Sweeper runs the loyalty sweep on a fixed interval.
+type Sweeper struct {
+	service  Service
+	interval time.Duration
+}
+
+func NewSweeper(service Service, cfg config.LoyaltyConfig) *Sweeper {
+	interval := cfg.SweepInterval
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &Sweeper{service: service, interval: interval}
+}
+
+func (w *Sweeper) Start(ctx context.Context) {
+	heartbeat := health.NewHeartbeat("loyalty_sweeper", w.interval)
+
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				runCtx, cancel := context.WithTimeout(ctx, time.Minute)
+				if err := w.service.Sweep(runCtx); err != nil {
+					logger.Error("loyalty sweep failed", "error", err)
+				}
+				cancel()
+				heartbeat.Beat()
+			}
+		}
+	}()
+
+	logger.Info("loyalty sweeper started", "interval", w.interval)
+}
@@ -0,0 +1,19 @@
+package dto
+
+type CreateUploadRequest struct {
+	EntityType  string  `json:"entityType" binding:"required,oneof=service addon vehicle document dispute"`
+	EntityID    *string `json:"entityId" binding:"omitempty,uuid"`
+	FileName    string  `json:"fileName" binding:"required,max=255"`
+	ContentType string  `json:"contentType" binding:"required"`
+	Size        int64   `json:"size" binding:"required,min=1"`
+}
+
+type AttachMediaRequest struct {
+	EntityType string `json:"entityType" binding:"required,oneof=service addon vehicle document dispute"`
+	EntityID   string `json:"entityId" binding:"required,uuid"`
+}
+
+type ListMediaQuery struct {
+	EntityType string `form:"entityType" binding:"required,oneof=service addon vehicle document dispute"`
+	EntityID   string `form:"entityId" binding:"required,uuid"`
+}
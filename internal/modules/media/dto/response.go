@@ -0,0 +1,59 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+type MediaResponse struct {
+	ID           string     `json:"id"`
+	EntityType   string     `json:"entityType"`
+	EntityID     *string    `json:"entityId,omitempty"`
+	Status       string     `json:"status"`
+	FileName     string     `json:"fileName"`
+	ContentType  string     `json:"contentType"`
+	Size         int64      `json:"size"`
+	URL          string     `json:"url"`
+	ThumbnailURL *string    `json:"thumbnailUrl,omitempty"`
+	Width        *int       `json:"width,omitempty"`
+	Height       *int       `json:"height,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	CompletedAt  *time.Time `json:"completedAt,omitempty"`
+}
+
+// UploadResponse tells the client where to PUT the file. The request must
+// carry exactly the listed headers, which are covered by the signature.
+type UploadResponse struct {
+	Media     MediaResponse     `json:"media"`
+	UploadURL string            `json:"uploadUrl"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expiresAt"`
+}
+
+func ToMediaResponse(m *models.Media) MediaResponse {
+	return MediaResponse{
+		ID:           m.ID,
+		EntityType:   m.EntityType,
+		EntityID:     m.EntityID,
+		Status:       string(m.Status),
+		FileName:     m.FileName,
+		ContentType:  m.ContentType,
+		Size:         m.Size,
+		URL:          m.URL,
+		ThumbnailURL: m.ThumbnailURL,
+		Width:        m.Width,
+		Height:       m.Height,
+		CreatedAt:    m.CreatedAt,
+		CompletedAt:  m.CompletedAt,
+	}
+}
+
+func ToMediaResponses(media []*models.Media) []MediaResponse {
+	responses := make([]MediaResponse, len(media))
+	for i, m := range media {
+		responses[i] = ToMediaResponse(m)
+	}
+	return responses
+}
@@ -0,0 +1,167 @@
+package media
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/media/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+func caller(c *gin.Context) (string, bool) {
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+	return userID.(string), role == string(models.RoleAdmin)
+}
+
+// CreateUpload godoc
+// @Summary Start a media upload
+// @Description Returns a presigned URL to PUT the file to, with the headers the request must send. Call the complete endpoint once the upload succeeds. Service and addon media are admin only; PDFs are accepted for documents only
+// @Tags media
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateUploadRequest true "Upload details"
+// @Success 200 {object} response.Response{data=dto.UploadResponse}
+// @Router /media/uploads [post]
+func (h *Handler) CreateUpload(c *gin.Context) {
+	userID, isAdmin := caller(c)
+
+	var req dto.CreateUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request: " + err.Error()))
+		return
+	}
+
+	upload, err := h.service.CreateUpload(c.Request.Context(), userID, isAdmin, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, upload, "Upload URL created successfully")
+}
+
+// CompleteUpload godoc
+// @Summary Complete a media upload
+// @Description Verifies the uploaded object and generates a thumbnail for JPEG, PNG and GIF images
+// @Tags media
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Media ID"
+// @Success 200 {object} response.Response{data=dto.MediaResponse}
+// @Router /media/{id}/complete [post]
+func (h *Handler) CompleteUpload(c *gin.Context) {
+	userID, isAdmin := caller(c)
+
+	media, err := h.service.CompleteUpload(c.Request.Context(), userID, isAdmin, c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, media, "Upload completed successfully")
+}
+
+// AttachMedia godoc
+// @Summary Attach media to an entity
+// @Description Media not attached within the orphan window is deleted
+// @Tags media
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Media ID"
+// @Param request body dto.AttachMediaRequest true "Entity"
+// @Success 200 {object} response.Response{data=dto.MediaResponse}
+// @Router /media/{id}/attach [put]
+func (h *Handler) AttachMedia(c *gin.Context) {
+	userID, isAdmin := caller(c)
+
+	var req dto.AttachMediaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request: " + err.Error()))
+		return
+	}
+
+	media, err := h.service.Attach(c.Request.Context(), userID, isAdmin, c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, media, "Media attached successfully")
+}
+
+// ListMedia godoc
+// @Summary List media of an entity
+// @Tags media
+// @Security BearerAuth
+// @Produce json
+// @Param entityType query string true "service, addon, vehicle or document"
+// @Param entityId query string true "Entity ID"
+// @Success 200 {object} response.Response{data=[]dto.MediaResponse}
+// @Router /media [get]
+func (h *Handler) ListMedia(c *gin.Context) {
+	userID, isAdmin := caller(c)
+
+	var query dto.ListMediaQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+
+	media, err := h.service.ListByEntity(c.Request.Context(), userID, isAdmin, query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, media, "Media retrieved successfully")
+}
+
+// GetMedia godoc
+// @Summary Get media
+// @Tags media
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Media ID"
+// @Success 200 {object} response.Response{data=dto.MediaResponse}
+// @Router /media/{id} [get]
+func (h *Handler) GetMedia(c *gin.Context) {
+	userID, isAdmin := caller(c)
+
+	media, err := h.service.GetMedia(c.Request.Context(), userID, isAdmin, c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, media, "Media retrieved successfully")
+}
+
+// DeleteMedia godoc
+// @Summary Delete media
+// @Description The stored files are removed by the cleanup job
+// @Tags media
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Media ID"
+// @Success 200 {object} response.Response
+// @Router /media/{id} [delete]
+func (h *Handler) DeleteMedia(c *gin.Context) {
+	userID, isAdmin := caller(c)
+
+	if err := h.service.DeleteMedia(c.Request.Context(), userID, isAdmin, c.Param("id")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, nil, "Media deleted successfully")
+}
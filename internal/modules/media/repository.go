@@ -0,0 +1,144 @@
+package media
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+type Repository interface {
+	Create(ctx context.Context, media *models.Media) error
+	FindByID(ctx context.Context, id string) (*models.Media, error)
+	ListByEntity(ctx context.Context, entityType, entityID string) ([]*models.Media, error)
+	Update(ctx context.Context, media *models.Media) error
+	Delete(ctx context.Context, id string) error
+
+	EntityExists(ctx context.Context, entityType, entityID string) (bool, error)
+	IsEntityOwner(ctx context.Context, entityType, entityID, userID string) (bool, error)
+
+	FindOrphans(ctx context.Context, cutoff time.Time, limit int) ([]*models.Media, error)
+	Purge(ctx context.Context, id string) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Create(ctx context.Context, media *models.Media) error {
+	return r.db.WithContext(ctx).Create(media).Error
+}
+
+func (r *repository) FindByID(ctx context.Context, id string) (*models.Media, error) {
+	var media models.Media
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&media).Error; err != nil {
+		return nil, err
+	}
+	return &media, nil
+}
+
+func (r *repository) ListByEntity(ctx context.Context, entityType, entityID string) ([]*models.Media, error) {
+	var media []*models.Media
+	err := r.db.WithContext(ctx).
+		Where("entity_type = ? AND entity_id = ? AND status = ?", entityType, entityID, models.MediaStatusReady).
+		Order("created_at ASC").
+		Find(&media).Error
+	return media, err
+}
+
+func (r *repository) Update(ctx context.Context, media *models.Media) error {
+	return r.db.WithContext(ctx).Save(media).Error
+}
+
+// Delete only soft-deletes; the cleanup job removes the stored objects and
+// then the row.
+func (r *repository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.Media{}).Error
+}
+
+// entityTables maps attachable entity types to their tables and whether the
+// table soft-deletes.
+var entityTables = map[string]struct {
+	table       string
+	softDeletes bool
+}{
+	models.MediaEntityService:  {"services", true},
+	models.MediaEntityAddon:    {"addons", true},
+	models.MediaEntityVehicle:  {"vehicles", false},
+	models.MediaEntityDocument: {"documents", false},
+	models.MediaEntityDispute:  {"disputes", false},
+}
+
+func (r *repository) EntityExists(ctx context.Context, entityType, entityID string) (bool, error) {
+	entity, ok := entityTables[entityType]
+	if !ok {
+		return false, nil
+	}
+
+	db := r.db.WithContext(ctx).Table(entity.table).Where("id = ?", entityID)
+	if entity.softDeletes {
+		db = db.Where("deleted_at IS NULL")
+	}
+
+	var count int64
+	err := db.Count(&count).Error
+	return count > 0, err
+}
+
+// IsEntityOwner reports whether userID owns a vehicle (as its driver) or a
+// document, or is a party to a dispute. Catalog entities have no owner.
+func (r *repository) IsEntityOwner(ctx context.Context, entityType, entityID, userID string) (bool, error) {
+	var count int64
+	var err error
+	switch entityType {
+	case models.MediaEntityVehicle:
+		err = r.db.WithContext(ctx).Table("vehicles").
+			Joins("JOIN driver_profiles ON driver_profiles.id = vehicles.driver_id").
+			Where("vehicles.id = ? AND driver_profiles.user_id = ?", entityID, userID).
+			Count(&count).Error
+	case models.MediaEntityDocument:
+		err = r.db.WithContext(ctx).Table("documents").
+			Where("id = ? AND user_id = ?", entityID, userID).
+			Count(&count).Error
+	case models.MediaEntityDispute:
+		err = r.db.WithContext(ctx).Table("disputes").
+			Where("id = ? AND (opened_by = ? OR respondent_id = ?)", entityID, userID, userID).
+			Count(&count).Error
+	}
+	return count > 0, err
+}
+
+// FindOrphans returns media whose objects can be deleted: rows already
+// deleted, uploads never completed or never attached to an entity by
+// cutoff, and media whose entity no longer exists.
+func (r *repository) FindOrphans(ctx context.Context, cutoff time.Time, limit int) ([]*models.Media, error) {
+	var media []*models.Media
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL").
+		Or("created_at < ? AND (status = ? OR entity_id IS NULL)", cutoff, models.MediaStatusPending).
+		Or(`entity_id IS NOT NULL AND NOT EXISTS (
+			SELECT 1 FROM services WHERE media.entity_type = 'service' AND services.id = media.entity_id AND services.deleted_at IS NULL
+			UNION ALL
+			SELECT 1 FROM addons WHERE media.entity_type = 'addon' AND addons.id = media.entity_id AND addons.deleted_at IS NULL
+			UNION ALL
+			SELECT 1 FROM vehicles WHERE media.entity_type = 'vehicle' AND vehicles.id = media.entity_id
+			UNION ALL
+			SELECT 1 FROM documents WHERE media.entity_type = 'document' AND documents.id = media.entity_id
+			UNION ALL
+			SELECT 1 FROM disputes WHERE media.entity_type = 'dispute' AND disputes.id = media.entity_id
+		)`).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&media).Error
+	return media, err
+}
+
+func (r *repository) Purge(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Unscoped().Where("id = ?", id).Delete(&models.Media{}).Error
+}
@@ -0,0 +1,18 @@
+package media
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
+	media := router.Group("/media")
+	media.Use(authMiddleware)
+	{
+		media.POST("/uploads", handler.CreateUpload)
+		media.GET("", handler.ListMedia)
+		media.GET("/:id", handler.GetMedia)
+		media.POST("/:id/complete", handler.CompleteUpload)
+		media.PUT("/:id/attach", handler.AttachMedia)
+		media.DELETE("/:id", handler.DeleteMedia)
+	}
+}
@@ -0,0 +1,332 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/media/dto"
+	"github.com/umar5678/go-backend/internal/services/storage"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+const cleanupBatchSize = 200
+
+// ObjectStore is the object storage the media module uploads to. It is
+// satisfied by storage.Client.
+type ObjectStore interface {
+	PresignPut(key, contentType string, expires time.Duration) string
+	Head(ctx context.Context, key string) (*storage.ObjectInfo, error)
+	Get(ctx context.Context, key string, maxBytes int64) ([]byte, error)
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// extensions lists the accepted upload types. PDFs are only accepted for
+// documents.
+var extensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/webp":      ".webp",
+	"image/gif":       ".gif",
+	"application/pdf": ".pdf",
+}
+
+type Service interface {
+	CreateUpload(ctx context.Context, userID string, isAdmin bool, req dto.CreateUploadRequest) (*dto.UploadResponse, error)
+	CompleteUpload(ctx context.Context, userID string, isAdmin bool, mediaID string) (*dto.MediaResponse, error)
+	Attach(ctx context.Context, userID string, isAdmin bool, mediaID string, req dto.AttachMediaRequest) (*dto.MediaResponse, error)
+	GetMedia(ctx context.Context, userID string, isAdmin bool, mediaID string) (*dto.MediaResponse, error)
+	ListByEntity(ctx context.Context, userID string, isAdmin bool, query dto.ListMediaQuery) ([]dto.MediaResponse, error)
+	DeleteMedia(ctx context.Context, userID string, isAdmin bool, mediaID string) error
+	// CleanupOrphans deletes the stored objects and rows of orphaned media
+	// and returns how many were removed.
+	CleanupOrphans(ctx context.Context) (int, error)
+}
+
+type service struct {
+	repo  Repository
+	store ObjectStore
+	cfg   config.MediaConfig
+}
+
+func NewService(repo Repository, store ObjectStore, cfg config.MediaConfig) Service {
+	return &service{repo: repo, store: store, cfg: cfg}
+}
+
+func (s *service) CreateUpload(ctx context.Context, userID string, isAdmin bool, req dto.CreateUploadRequest) (*dto.UploadResponse, error) {
+	contentType := strings.ToLower(strings.TrimSpace(req.ContentType))
+	ext, ok := extensions[contentType]
+	if !ok || (ext == ".pdf" && req.EntityType != models.MediaEntityDocument && req.EntityType != models.MediaEntityDispute) {
+		return nil, response.BadRequest(fmt.Sprintf("Content type %q is not accepted for %s media", req.ContentType, req.EntityType))
+	}
+	if req.Size > s.cfg.MaxSize {
+		return nil, response.BadRequest(fmt.Sprintf("File is larger than %d MB", s.cfg.MaxSize>>20))
+	}
+	if err := s.authorizeEntity(ctx, userID, isAdmin, req.EntityType, req.EntityID); err != nil {
+		return nil, err
+	}
+
+	id := uuid.New().String()
+	key := path.Join("media", req.EntityType, time.Now().UTC().Format("2006/01"), id+ext)
+	media := &models.Media{
+		ID:          id,
+		UploaderID:  userID,
+		EntityType:  req.EntityType,
+		EntityID:    req.EntityID,
+		Status:      models.MediaStatusPending,
+		FileName:    req.FileName,
+		ContentType: contentType,
+		StorageKey:  key,
+		URL:         s.store.URL(key),
+	}
+	if req.EntityID != nil {
+		now := time.Now()
+		media.AttachedAt = &now
+	}
+	if err := s.repo.Create(ctx, media); err != nil {
+		return nil, response.InternalServerError("Failed to create upload", err)
+	}
+
+	return &dto.UploadResponse{
+		Media:     dto.ToMediaResponse(media),
+		UploadURL: s.store.PresignPut(key, contentType, s.cfg.UploadExpiry),
+		Method:    "PUT",
+		Headers:   map[string]string{"Content-Type": contentType},
+		ExpiresAt: time.Now().Add(s.cfg.UploadExpiry),
+	}, nil
+}
+
+func (s *service) CompleteUpload(ctx context.Context, userID string, isAdmin bool, mediaID string) (*dto.MediaResponse, error) {
+	media, err := s.findOwned(ctx, userID, isAdmin, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	if media.Status == models.MediaStatusReady {
+		result := dto.ToMediaResponse(media)
+		return &result, nil
+	}
+
+	info, err := s.store.Head(ctx, media.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, response.BadRequest("File has not been uploaded yet")
+	}
+	if err != nil {
+		return nil, response.InternalServerError("Failed to check uploaded file", err)
+	}
+	if info.Size > s.cfg.MaxSize {
+		s.discard(ctx, media)
+		return nil, response.BadRequest(fmt.Sprintf("File is larger than %d MB", s.cfg.MaxSize>>20))
+	}
+	media.Size = info.Size
+
+	if thumbnailable[media.ContentType] {
+		if err := s.storeThumbnail(ctx, media); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	media.Status = models.MediaStatusReady
+	media.CompletedAt = &now
+	if err := s.repo.Update(ctx, media); err != nil {
+		return nil, response.InternalServerError("Failed to complete upload", err)
+	}
+
+	result := dto.ToMediaResponse(media)
+	return &result, nil
+}
+
+func (s *service) storeThumbnail(ctx context.Context, media *models.Media) error {
+	data, err := s.store.Get(ctx, media.StorageKey, s.cfg.MaxSize)
+	if err != nil {
+		return response.InternalServerError("Failed to read uploaded file", err)
+	}
+
+	thumb, width, height, err := makeThumbnail(data, s.cfg.ThumbnailSize)
+	if err != nil {
+		s.discard(ctx, media)
+		return response.BadRequest("Uploaded file is not a usable image: " + err.Error())
+	}
+
+	key := strings.TrimSuffix(media.StorageKey, path.Ext(media.StorageKey)) + "_thumb.jpg"
+	if err := s.store.Put(ctx, key, "image/jpeg", thumb); err != nil {
+		return response.InternalServerError("Failed to store thumbnail", err)
+	}
+
+	thumbURL := s.store.URL(key)
+	media.ThumbnailKey = &key
+	media.ThumbnailURL = &thumbURL
+	media.Width = &width
+	media.Height = &height
+	return nil
+}
+
+// discard drops an upload that failed validation; the cleanup job deletes its
+// object.
+func (s *service) discard(ctx context.Context, media *models.Media) {
+	if err := s.repo.Delete(ctx, media.ID); err != nil {
+		logger.Error("failed to discard media", "error", err, "mediaID", media.ID)
+	}
+}
+
+func (s *service) Attach(ctx context.Context, userID string, isAdmin bool, mediaID string, req dto.AttachMediaRequest) (*dto.MediaResponse, error) {
+	media, err := s.findOwned(ctx, userID, isAdmin, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	if req.EntityType != media.EntityType {
+		return nil, response.BadRequest(fmt.Sprintf("Media was uploaded for a %s", media.EntityType))
+	}
+	if err := s.authorizeEntity(ctx, userID, isAdmin, req.EntityType, &req.EntityID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	media.EntityID = &req.EntityID
+	media.AttachedAt = &now
+	if err := s.repo.Update(ctx, media); err != nil {
+		return nil, response.InternalServerError("Failed to attach media", err)
+	}
+
+	result := dto.ToMediaResponse(media)
+	return &result, nil
+}
+
+func (s *service) GetMedia(ctx context.Context, userID string, isAdmin bool, mediaID string) (*dto.MediaResponse, error) {
+	media, err := s.findOwned(ctx, userID, isAdmin, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	result := dto.ToMediaResponse(media)
+	return &result, nil
+}
+
+// ListByEntity is open to everyone for catalog entities; vehicle, document
+// and dispute media are limited to their owner and admins.
+func (s *service) ListByEntity(ctx context.Context, userID string, isAdmin bool, query dto.ListMediaQuery) ([]dto.MediaResponse, error) {
+	if !isAdmin && !isCatalogEntity(query.EntityType) {
+		owner, err := s.repo.IsEntityOwner(ctx, query.EntityType, query.EntityID, userID)
+		if err != nil {
+			return nil, response.InternalServerError("Failed to check entity", err)
+		}
+		if !owner {
+			return nil, response.ForbiddenError("You cannot view media of this " + query.EntityType)
+		}
+	}
+
+	media, err := s.repo.ListByEntity(ctx, query.EntityType, query.EntityID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to list media", err)
+	}
+	return dto.ToMediaResponses(media), nil
+}
+
+func (s *service) DeleteMedia(ctx context.Context, userID string, isAdmin bool, mediaID string) error {
+	if _, err := s.findOwned(ctx, userID, isAdmin, mediaID); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, mediaID); err != nil {
+		return response.InternalServerError("Failed to delete media", err)
+	}
+	return nil
+}
+
+func (s *service) CleanupOrphans(ctx context.Context) (int, error) {
+	orphans, err := s.repo.FindOrphans(ctx, time.Now().Add(-s.cfg.OrphanAfter), cleanupBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, media := range orphans {
+		keys := []string{media.StorageKey}
+		if media.ThumbnailKey != nil {
+			keys = append(keys, *media.ThumbnailKey)
+		}
+
+		failed := false
+		for _, key := range keys {
+			if err := s.store.Delete(ctx, key); err != nil {
+				logger.Error("failed to delete media object", "error", err, "mediaID", media.ID, "key", key)
+				failed = true
+			}
+		}
+		// Keep the row so the next run retries the object deletion.
+		if failed {
+			continue
+		}
+
+		if err := s.repo.Purge(ctx, media.ID); err != nil {
+			logger.Error("failed to purge media", "error", err, "mediaID", media.ID)
+			continue
+		}
+		removed++
+	}
+
+	if removed > 0 {
+		logger.Info("orphaned media cleaned up", "count", removed)
+	}
+	return removed, nil
+}
+
+func (s *service) findOwned(ctx context.Context, userID string, isAdmin bool, mediaID string) (*models.Media, error) {
+	media, err := s.repo.FindByID(ctx, mediaID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Media")
+		}
+		return nil, response.InternalServerError("Failed to get media", err)
+	}
+	if !isAdmin && media.UploaderID != userID {
+		return nil, response.NotFoundError("Media")
+	}
+	return media, nil
+}
+
+func isCatalogEntity(entityType string) bool {
+	return entityType == models.MediaEntityService || entityType == models.MediaEntityAddon
+}
+
+// authorizeEntity checks the caller may attach media to the entity: admins
+// manage catalog media, drivers their vehicle, users their documents and
+// either party its dispute. A nil entityID defers the ownership check to
+// Attach.
+func (s *service) authorizeEntity(ctx context.Context, userID string, isAdmin bool, entityType string, entityID *string) error {
+	if !isAdmin && isCatalogEntity(entityType) {
+		return response.ForbiddenError("Only admins can manage " + entityType + " media")
+	}
+	if entityID == nil {
+		return nil
+	}
+
+	exists, err := s.repo.EntityExists(ctx, entityType, *entityID)
+	if err != nil {
+		return response.InternalServerError("Failed to check entity", err)
+	}
+	if !exists {
+		return response.NotFoundError(strings.ToUpper(entityType[:1]) + entityType[1:])
+	}
+	if isAdmin {
+		return nil
+	}
+
+	owner, err := s.repo.IsEntityOwner(ctx, entityType, *entityID, userID)
+	if err != nil {
+		return response.InternalServerError("Failed to check entity", err)
+	}
+	if !owner {
+		return response.ForbiddenError("You cannot attach media to this " + entityType)
+	}
+	return nil
+}
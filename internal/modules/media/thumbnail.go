@@ -0,0 +1,102 @@
+package media
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+
+	// Register the formats thumbnails can be made from.
+	_ "image/gif"
+	_ "image/png"
+)
+
+// maxSourcePixels guards against decompression bombs: a small file can
+// declare enormous dimensions.
+const maxSourcePixels = 40_000_000
+
+const thumbnailQuality = 80
+
+// thumbnailable lists the image types the standard library can decode. WebP
+// uploads are stored as-is without a thumbnail.
+var thumbnailable = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// makeThumbnail scales the image to fit within size×size, keeping its aspect
+// ratio, and encodes it as JPEG on a white background. It also returns the
+// source dimensions.
+func makeThumbnail(data []byte, size int) (thumb []byte, width, height int, err error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("not a valid image: %w", err)
+	}
+	if cfg.Width*cfg.Height > maxSourcePixels {
+		return nil, 0, 0, fmt.Errorf("image is too large (%dx%d)", cfg.Width, cfg.Height)
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("not a valid image: %w", err)
+	}
+
+	bounds := src.Bounds()
+	width, height = bounds.Dx(), bounds.Dy()
+
+	tw, th := width, height
+	if tw > size || th > size {
+		if tw >= th {
+			tw, th = size, max(1, height*size/width)
+		} else {
+			tw, th = max(1, width*size/height), size
+		}
+	}
+
+	flat := image.NewRGBA(bounds)
+	draw.Draw(flat, bounds, &image.Uniform{C: color.White}, image.Point{}, draw.Src)
+	draw.Draw(flat, bounds, src, bounds.Min, draw.Over)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, downscale(flat, tw, th), &jpeg.Options{Quality: thumbnailQuality}); err != nil {
+		return nil, 0, 0, err
+	}
+	return buf.Bytes(), width, height, nil
+}
+
+// downscale resizes by averaging the source pixels that fall into each
+// destination pixel, which avoids the aliasing of nearest-neighbour sampling.
+func downscale(src *image.RGBA, width, height int) *image.RGBA {
+	bounds := src.Bounds()
+	sw, sh := bounds.Dx(), bounds.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0, y1 := y*sh/height, max((y+1)*sh/height, y*sh/height+1)
+		for x := 0; x < width; x++ {
+			x0, x1 := x*sw/width, max((x+1)*sw/width, x*sw/width+1)
+
+			var r, g, b, n uint32
+			for sy := y0; sy < y1; sy++ {
+				offset := src.PixOffset(bounds.Min.X+x0, bounds.Min.Y+sy)
+				for sx := x0; sx < x1; sx++ {
+					r += uint32(src.Pix[offset])
+					g += uint32(src.Pix[offset+1])
+					b += uint32(src.Pix[offset+2])
+					offset += 4
+					n++
+				}
+			}
+
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = 0xff
+		}
+	}
+	return dst
+}
@@ -28,14 +28,16 @@ type UnregisterPushTokenRequest struct {
 type NotificationController struct {
 	notifService service.NotificationService
 	pushService  service.PushService
+	prefService  service.PreferenceService
 	upgrader     websocket.Upgrader
 	cfg          *config.Config
 }
 
-func NewNotificationController(notifService service.NotificationService, pushService service.PushService, cfg *config.Config) *NotificationController {
+func NewNotificationController(notifService service.NotificationService, pushService service.PushService, prefService service.PreferenceService, cfg *config.Config) *NotificationController {
 	return &NotificationController{
 		notifService: notifService,
 		pushService:  pushService,
+		prefService:  prefService,
 		cfg:          cfg,
 		upgrader: websocket.Upgrader{
 			ReadBufferSize:  1024,
@@ -65,6 +67,9 @@ func (c *NotificationController) RegisterRoutes(rg *gin.RouterGroup, authMiddlew
 		notifications.POST("/push-token", c.RegisterPushToken)
 		notifications.DELETE("/push-token", c.UnregisterPushToken)
 
+		notifications.GET("/preferences", c.GetPreferences)
+		notifications.PUT("/preferences", c.UpdatePreferences)
+
 		notifications.GET("/stats", c.GetPushStats)
 	}
 
@@ -330,6 +335,83 @@ func (c *NotificationController) UnregisterPushToken(ctx *gin.Context) {
 	response.Success(ctx, nil, "Push token unregistered")
 }
 
+// GetPreferences godoc
+// @Summary Get notification preferences
+// @Description Get the channel toggles for each notification category and the quiet hours of the authenticated user
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} response.Response{data=dto.PreferencesResponse}
+// @Failure 401 {object} response.Response
+// @Router /notifications/preferences [get]
+// @Security BearerAuth
+func (c *NotificationController) GetPreferences(ctx *gin.Context) {
+	userIDStr, exists := ctx.Get("userID")
+	if !exists {
+		response.Unauthorized(ctx, "unauthorized")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		response.Unauthorized(ctx, "invalid user id")
+		return
+	}
+
+	result, err := c.prefService.GetPreferences(ctx.Request.Context(), userID)
+	if err != nil {
+		logger.Error("failed to get notification preferences", "error", err, "userID", userID.String())
+		response.InternalError(ctx, "Failed to get notification preferences")
+		return
+	}
+
+	response.Success(ctx, result, "Notification preferences retrieved successfully")
+}
+
+// UpdatePreferences godoc
+// @Summary Update notification preferences
+// @Description Turn push, SMS and email on or off per notification category and set quiet hours. Safety notifications cannot be turned off and are delivered during quiet hours.
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param payload body dto.UpdatePreferencesRequest true "Preference changes"
+// @Success 200 {object} response.Response{data=dto.PreferencesResponse}
+// @Failure 400 {object} response.Response
+// @Failure 401 {object} response.Response
+// @Router /notifications/preferences [put]
+// @Security BearerAuth
+func (c *NotificationController) UpdatePreferences(ctx *gin.Context) {
+	userIDStr, exists := ctx.Get("userID")
+	if !exists {
+		response.Unauthorized(ctx, "unauthorized")
+		return
+	}
+
+	userID, err := uuid.Parse(userIDStr.(string))
+	if err != nil {
+		response.Unauthorized(ctx, "invalid user id")
+		return
+	}
+
+	var req dto.UpdatePreferencesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.SendError(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		response.SendError(ctx, http.StatusBadRequest, err.Error(), nil)
+		return
+	}
+
+	result, err := c.prefService.UpdatePreferences(ctx.Request.Context(), userID, &req)
+	if err != nil {
+		logger.Error("failed to update notification preferences", "error", err, "userID", userID.String())
+		response.InternalError(ctx, "Failed to update notification preferences")
+		return
+	}
+
+	response.Success(ctx, result, "Notification preferences updated successfully")
+}
+
 // SubscribePush godoc
 // @Summary Subscribe to push notifications
 // @Description Establish a WebSocket connection for real-time push notifications
@@ -349,6 +431,10 @@ func (c *NotificationController) SubscribePush(ctx *gin.Context) {
 		ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid or expired token"})
 		return
 	}
+	if claims.IsImpersonation() {
+		ctx.JSON(http.StatusForbidden, gin.H{"error": "impersonation tokens cannot subscribe to push"})
+		return
+	}
 
 	userID, err := uuid.Parse(claims.UserID)
 	if err != nil {
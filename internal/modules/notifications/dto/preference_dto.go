@@ -0,0 +1,109 @@
+package dto
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+type PreferenceDTO struct {
+	Category models.NotificationCategory `json:"category"`
+	Push     bool                        `json:"push"`
+	SMS      bool                        `json:"sms"`
+	Email    bool                        `json:"email"`
+	// Locked categories are always delivered and cannot be changed.
+	Locked bool `json:"locked"`
+}
+
+type QuietHoursDTO struct {
+	Enabled   bool   `json:"enabled"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+	Timezone  string `json:"timezone"`
+}
+
+type PreferencesResponse struct {
+	Preferences []*PreferenceDTO `json:"preferences"`
+	QuietHours  QuietHoursDTO    `json:"quiet_hours"`
+}
+
+type PreferenceUpdate struct {
+	Category models.NotificationCategory `json:"category" binding:"required"`
+	Push     *bool                       `json:"push"`
+	SMS      *bool                       `json:"sms"`
+	Email    *bool                       `json:"email"`
+}
+
+type UpdatePreferencesRequest struct {
+	Preferences []PreferenceUpdate `json:"preferences" binding:"omitempty,dive"`
+	QuietHours  *QuietHoursDTO     `json:"quiet_hours"`
+}
+
+func (r *UpdatePreferencesRequest) Validate() error {
+	if len(r.Preferences) == 0 && r.QuietHours == nil {
+		return fmt.Errorf("nothing to update")
+	}
+	for _, pref := range r.Preferences {
+		if !pref.Category.IsValid() {
+			return fmt.Errorf("unknown notification category '%s'", pref.Category)
+		}
+		if pref.Category.IsCritical() {
+			return fmt.Errorf("%s notifications cannot be turned off", pref.Category)
+		}
+	}
+	if q := r.QuietHours; q != nil {
+		if _, err := time.Parse("15:04", q.StartTime); err != nil {
+			return fmt.Errorf("start_time must be HH:MM")
+		}
+		if _, err := time.Parse("15:04", q.EndTime); err != nil {
+			return fmt.Errorf("end_time must be HH:MM")
+		}
+		if q.StartTime == q.EndTime {
+			return fmt.Errorf("start_time and end_time must differ")
+		}
+		if q.Timezone == "" {
+			q.Timezone = "UTC"
+		}
+		if _, err := time.LoadLocation(q.Timezone); err != nil {
+			return fmt.Errorf("unknown timezone '%s'", q.Timezone)
+		}
+	}
+	return nil
+}
+
+// ToPreferencesResponse fills in every category, using the defaults for
+// those the user has not set.
+func ToPreferencesResponse(prefs []*models.NotificationPreference, quietHours *models.NotificationQuietHours) *PreferencesResponse {
+	byCategory := make(map[models.NotificationCategory]*models.NotificationPreference, len(prefs))
+	for _, pref := range prefs {
+		byCategory[pref.Category] = pref
+	}
+
+	resp := &PreferencesResponse{
+		Preferences: make([]*PreferenceDTO, 0, len(models.NotificationCategories)),
+		QuietHours: QuietHoursDTO{
+			StartTime: "22:00",
+			EndTime:   "07:00",
+			Timezone:  "UTC",
+		},
+	}
+	for _, category := range models.NotificationCategories {
+		item := &PreferenceDTO{Category: category, Push: true, SMS: true, Email: true, Locked: category.IsCritical()}
+		if pref, ok := byCategory[category]; ok && !category.IsCritical() {
+			item.Push = pref.Push
+			item.SMS = pref.SMS
+			item.Email = pref.Email
+		}
+		resp.Preferences = append(resp.Preferences, item)
+	}
+	if quietHours != nil {
+		resp.QuietHours = QuietHoursDTO{
+			Enabled:   quietHours.Enabled,
+			StartTime: quietHours.StartTime,
+			EndTime:   quietHours.EndTime,
+			Timezone:  quietHours.Timezone,
+		}
+	}
+	return resp
+}
@@ -433,7 +433,9 @@ func (h *UserEventHandler) CanHandle(eventType EventType) bool {
 	switch eventType {
 	case EventUserRegistered,
 		EventUserVerified,
-		EventUserSuspended:
+		EventUserSuspended,
+		EventUserBanned,
+		EventUserReinstated:
 		return true
 	default:
 		return false
@@ -480,6 +482,18 @@ func (h *UserEventHandler) Handle(ctx context.Context, event *ConsumedEvent) err
 			"event_type": EventUserSuspended,
 		}
 
+	case EventUserBanned:
+		notificationMsg = "Your account has been banned"
+		metadataMap = map[string]interface{}{
+			"event_type": EventUserBanned,
+		}
+
+	case EventUserReinstated:
+		notificationMsg = "Your account has been reinstated"
+		metadataMap = map[string]interface{}{
+			"event_type": EventUserReinstated,
+		}
+
 	default:
 		logger.Debug("unknown user event type, skipping", "event_type", eventTypeStr)
 		return nil
@@ -36,6 +36,8 @@ func (f *EventHandlerFactory) HandleEvent(ctx context.Context, eventType notific
 		return f.handleRideCompleted(ctx, payload)
 	case notifications.EventRideCancelled:
 		return f.handleRideCancelled(ctx, payload)
+	case notifications.EventRideFareSplitInvited:
+		return f.handleFareSplitInvited(ctx, payload)
 
 	case notifications.EventPaymentProcessed:
 		return f.handlePaymentProcessed(ctx, payload)
@@ -101,7 +103,7 @@ func (f *EventHandlerFactory) handleRideRequested(ctx context.Context, payload [
 		"fare":    fmt.Sprintf("%.2f", event.EstimatedFare),
 	}
 
-	if err := f.pushService.SendPush(ctx, event.RiderID, "Ride Requested", "Your ride request has been sent to nearby drivers", data); err != nil {
+	if err := service.SendLocalizedPush(ctx, f.pushService, event.RiderID, "push.ride_requested", nil, data); err != nil {
 		logger.Error("failed to send ride requested notification", "error", err)
 	}
 
@@ -147,7 +149,7 @@ func (f *EventHandlerFactory) handleRideStarted(ctx context.Context, payload []b
 		"ride_id": event.RideID.String(),
 	}
 
-	if err := f.pushService.SendPush(ctx, event.RiderID, "Ride Started", "Your ride has started", data); err != nil {
+	if err := service.SendLocalizedPush(ctx, f.pushService, event.RiderID, "push.ride_started", nil, data); err != nil {
 		logger.Error("failed to send ride started notification", "error", err)
 	}
 
@@ -178,7 +180,8 @@ func (f *EventHandlerFactory) handleRideCompleted(ctx context.Context, payload [
 		"earning": fmt.Sprintf("%.2f", event.FinalFare),
 	}
 
-	if err := f.pushService.SendPush(ctx, event.DriverID, "Ride Completed", fmt.Sprintf("Ride completed. Your earning: ₹%.2f", event.FinalFare), data); err != nil {
+	params := map[string]interface{}{"amount": fmt.Sprintf("%.2f", event.FinalFare)}
+	if err := service.SendLocalizedPush(ctx, f.pushService, event.DriverID, "push.ride_completed_driver", params, data); err != nil {
 		logger.Error("failed to send ride completed notification to driver", "error", err)
 	}
 
@@ -204,12 +207,18 @@ func (f *EventHandlerFactory) handleRideCancelled(ctx context.Context, payload [
 		"reason":  event.Reason,
 	}
 
-	if err := f.pushService.SendPush(ctx, event.RiderID, "Ride Cancelled", fmt.Sprintf("Your ride has been cancelled. Reason: %s", event.Reason), data); err != nil {
+	params := map[string]interface{}{"reason": event.Reason}
+	if err := service.SendLocalizedPush(ctx, f.pushService, event.RiderID, "push.ride_cancelled_rider", params, data); err != nil {
 		logger.Error("failed to send ride cancelled notification to rider", "error", err)
 	}
 
 	if event.DriverID != nil {
-		if err := f.pushService.SendPush(ctx, *event.DriverID, "Ride Cancelled", fmt.Sprintf("A ride has been cancelled. Reason: %s", event.Reason), data); err != nil {
+		driverData := map[string]interface{}{
+			"type":    "ride_cancelled",
+			"ride_id": event.RideID.String(),
+			"reason":  event.Reason,
+		}
+		if err := service.SendLocalizedPush(ctx, f.pushService, *event.DriverID, "push.ride_cancelled_driver", params, driverData); err != nil {
 			logger.Error("failed to send ride cancelled notification to driver", "error", err)
 		}
 	}
@@ -217,6 +226,38 @@ func (f *EventHandlerFactory) handleRideCancelled(ctx context.Context, payload [
 	return nil
 }
 
+type FareSplitInvitedPayload struct {
+	RideID       uuid.UUID `json:"ride_id"`
+	SplitID      uuid.UUID `json:"split_id"`
+	UserID       uuid.UUID `json:"user_id"`
+	SharePercent float64   `json:"share_percent"`
+	ShareAmount  float64   `json:"share_amount"`
+	Currency     string    `json:"currency"`
+}
+
+func (f *EventHandlerFactory) handleFareSplitInvited(ctx context.Context, payload []byte) error {
+	var event FareSplitInvitedPayload
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal fare split invited event: %w", err)
+	}
+
+	data := map[string]interface{}{
+		"type":     "fare_split_invitation",
+		"ride_id":  event.RideID.String(),
+		"split_id": event.SplitID.String(),
+	}
+
+	params := map[string]interface{}{
+		"amount":   fmt.Sprintf("%.2f", event.ShareAmount),
+		"currency": event.Currency,
+	}
+	if err := service.SendLocalizedPush(ctx, f.pushService, event.UserID, "push.fare_split_invited", params, data); err != nil {
+		logger.Error("failed to send fare split invitation", "error", err)
+	}
+
+	return nil
+}
+
 type PaymentProcessedPayload struct {
 	UserID uuid.UUID `json:"user_id"`
 	Amount float64   `json:"amount"`
@@ -272,7 +313,8 @@ func (f *EventHandlerFactory) handleVehicleRegistered(ctx context.Context, paylo
 		"vehicle_id": event.VehicleID.String(),
 	}
 
-	if err := f.pushService.SendPush(ctx, event.UserID, "Vehicle Registered", fmt.Sprintf("Your %s has been registered successfully", event.VehicleName), data); err != nil {
+	params := map[string]interface{}{"vehicle": event.VehicleName}
+	if err := service.SendLocalizedPush(ctx, f.pushService, event.UserID, "push.vehicle_registered", params, data); err != nil {
 		logger.Error("failed to send vehicle registered notification", "error", err)
 	}
 
@@ -296,8 +338,8 @@ func (f *EventHandlerFactory) handleUserRegistered(ctx context.Context, payload
 		"role": event.Role,
 	}
 
-	greeting := fmt.Sprintf("Welcome to Ghartak, %s!", event.Name)
-	if err := f.pushService.SendPush(ctx, event.UserID, "Welcome!", greeting, data); err != nil {
+	params := map[string]interface{}{"name": event.Name}
+	if err := service.SendLocalizedPush(ctx, f.pushService, event.UserID, "push.user_registered", params, data); err != nil {
 		logger.Error("failed to send welcome notification", "error", err)
 	}
 
@@ -319,7 +361,7 @@ func (f *EventHandlerFactory) handleUserVerified(ctx context.Context, payload []
 		"type": "user_verified",
 	}
 
-	if err := f.pushService.SendPush(ctx, event.UserID, "Account Verified", "Your account has been verified successfully!", data); err != nil {
+	if err := service.SendLocalizedPush(ctx, f.pushService, event.UserID, "push.user_verified", nil, data); err != nil {
 		logger.Error("failed to send account verified notification", "error", err)
 	}
 
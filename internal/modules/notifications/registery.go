@@ -24,6 +24,7 @@ const (
 	EventRideRequestExpired           EventType = "ride.request.expired"
 	EventHighRiskRider                EventType = "ride.high_risk_rider"
 	EventDriverArrived                EventType = "ride.driver.arrived"
+	EventRideFareSplitInvited         EventType = "ride.fare_split.invited"
 
 	EventPaymentProcessed EventType = "payment.processed"
 	EventPaymentFailed    EventType = "payment.failed"
@@ -36,6 +37,8 @@ const (
 	EventUserRegistered EventType = "user.registered"
 	EventUserVerified   EventType = "user.verified"
 	EventUserSuspended  EventType = "user.suspended"
+	EventUserBanned     EventType = "user.banned"
+	EventUserReinstated EventType = "user.reinstated"
 
 	EventSOSAlert     EventType = "sos.alert"
 	EventSOSTriggered EventType = "sos.triggered"
@@ -68,8 +71,11 @@ const (
 	EventFareEstimated       EventType = "pricing.fare.estimated"
 
 	EventServiceProviderApproved EventType = "admin.provider.approved"
+	EventServiceProviderRejected EventType = "admin.provider.rejected"
 	EventUserStatusChanged       EventType = "admin.user.status.changed"
 
+	EventProviderOnboardingSubmitted EventType = "provider.onboarding.submitted"
+
 	EventRidePINGenerated   EventType = "ridepin.generated"
 	EventRidePINRegenerated EventType = "ridepin.regenerated"
 	EventRidePINVerified    EventType = "ridepin.verified"
@@ -91,6 +97,19 @@ const (
 	EventRideAssigned           EventType = "ride.assigned"
 	EventInvalidRidePINAttempt  EventType = "ride.pin.invalid_attempt"
 
+	EventLostItemReported  EventType = "ride.lost_item.reported"
+	EventLostItemUpdated   EventType = "ride.lost_item.updated"
+	EventLostItemEscalated EventType = "ride.lost_item.escalated"
+
+	EventDisputeOpened    EventType = "dispute.opened"
+	EventDisputeResponded EventType = "dispute.responded"
+	EventDisputeResolved  EventType = "dispute.resolved"
+
+	EventOpsTaskCreated   EventType = "admin.task.created"
+	EventOpsTaskAssigned  EventType = "admin.task.assigned"
+	EventOpsTaskUpdated   EventType = "admin.task.updated"
+	EventOpsTaskCommented EventType = "admin.task.commented"
+
 	EventOrderPlaced       EventType = "food:order:placed"
 	EventOrderAccepted     EventType = "food:order:accepted"
 	EventOrderPickedUp     EventType = "food:order:picked_up"
@@ -146,6 +165,7 @@ func (r *EventRegistry) registerDefaultSchemas() {
 		{EventDriverArrived, "ride-events", "rides", "Driver arrived at pickup location", "v1"},
 		{EventInvalidRidePINAttempt, "ride-events", "rides", "Invalid ride PIN attempt", "v1"},
 		{EventRideAssigned, "ride-events", "rides", "Ride assigned to driver", "v1"},
+		{EventRideFareSplitInvited, "ride-events", "rides", "Co-rider invited to split a fare", "v1"},
 
 		{EventPaymentProcessed, "payment-events", "payments", "Payment processed", "v1"},
 		{EventPaymentFailed, "payment-events", "payments", "Payment failed", "v1"},
@@ -158,6 +178,8 @@ func (r *EventRegistry) registerDefaultSchemas() {
 		{EventUserRegistered, "user-events", "auth", "User registered", "v1"},
 		{EventUserVerified, "user-events", "auth", "User verified", "v1"},
 		{EventUserSuspended, "user-events", "auth", "User suspended", "v1"},
+		{EventUserBanned, "user-events", "auth", "User banned", "v1"},
+		{EventUserReinstated, "user-events", "auth", "User reinstated", "v1"},
 		{EventUserVerificationPending, "user-events", "profile", "User verification pending", "v1"},
 
 		{EventSOSAlert, "sos-events", "sos", "SOS alert triggered", "v1"},
@@ -189,6 +211,8 @@ func (r *EventRegistry) registerDefaultSchemas() {
 		{EventFareEstimated, "pricing-events", "pricing", "Fare estimated", "v1"},
 
 		{EventServiceProviderApproved, "admin-events", "admin", "Service provider approved", "v1"},
+		{EventServiceProviderRejected, "admin-events", "admin", "Service provider rejected", "v1"},
+		{EventProviderOnboardingSubmitted, "admin-events", "serviceproviders", "Provider submitted onboarding for review", "v1"},
 		{EventUserStatusChanged, "admin-events", "admin", "User status changed", "v1"},
 
 		{EventRidePINGenerated, "ridepin-events", "ridepin", "RidePin generated", "v1"},
@@ -211,6 +235,19 @@ func (r *EventRegistry) registerDefaultSchemas() {
 
 		{EventRideDestinationChanged, "ride-events", "ride", "Ride destination changed", "v1"},
 
+		{EventLostItemReported, "ride-events", "lostfound", "Lost item reported by rider", "v1"},
+		{EventLostItemUpdated, "ride-events", "lostfound", "Lost item case updated", "v1"},
+		{EventLostItemEscalated, "ride-events", "lostfound", "Lost item case escalated to admin", "v1"},
+
+		{EventDisputeOpened, "dispute-events", "disputes", "Dispute opened against a ride or order", "v1"},
+		{EventDisputeResponded, "dispute-events", "disputes", "Driver or provider responded to a dispute", "v1"},
+		{EventDisputeResolved, "dispute-events", "disputes", "Dispute resolved by admin", "v1"},
+
+		{EventOpsTaskCreated, "admin-events", "tasks", "Ops task created", "v1"},
+		{EventOpsTaskAssigned, "admin-events", "tasks", "Ops task assigned to an admin", "v1"},
+		{EventOpsTaskUpdated, "admin-events", "tasks", "Ops task updated", "v1"},
+		{EventOpsTaskCommented, "admin-events", "tasks", "Comment added to an ops task", "v1"},
+
 		{EventOrderPlaced, "food-order-events", "food", "Order placed", "v1"},
 		{EventOrderAccepted, "food-order-events", "food", "Order accepted by delivery person", "v1"},
 		{EventOrderPickedUp, "food-order-events", "food", "Order picked up from restaurant", "v1"},
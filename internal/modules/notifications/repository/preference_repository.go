@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+type PreferenceRepository interface {
+	GetPreferences(ctx context.Context, userID uuid.UUID) ([]*models.NotificationPreference, error)
+	GetPreference(ctx context.Context, userID uuid.UUID, category models.NotificationCategory) (*models.NotificationPreference, error)
+	UpsertPreferences(ctx context.Context, prefs []*models.NotificationPreference) error
+
+	GetQuietHours(ctx context.Context, userID uuid.UUID) (*models.NotificationQuietHours, error)
+	UpsertQuietHours(ctx context.Context, quietHours *models.NotificationQuietHours) error
+
+	CreateDeferred(ctx context.Context, notification *models.DeferredNotification) error
+	ListDueDeferred(ctx context.Context, now time.Time, limit int) ([]*models.DeferredNotification, error)
+	MarkDeferredDelivered(ctx context.Context, id uuid.UUID) error
+}
+
+type preferenceRepository struct {
+	db *gorm.DB
+}
+
+func NewPreferenceRepository(db *gorm.DB) PreferenceRepository {
+	return &preferenceRepository{db: db}
+}
+
+func (r *preferenceRepository) GetPreferences(ctx context.Context, userID uuid.UUID) ([]*models.NotificationPreference, error) {
+	var prefs []*models.NotificationPreference
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Find(&prefs).Error
+	return prefs, err
+}
+
+// GetPreference returns nil without an error when the user has not set the
+// category.
+func (r *preferenceRepository) GetPreference(ctx context.Context, userID uuid.UUID, category models.NotificationCategory) (*models.NotificationPreference, error) {
+	var pref models.NotificationPreference
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND category = ?", userID, category).
+		First(&pref).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pref, nil
+}
+
+func (r *preferenceRepository) UpsertPreferences(ctx context.Context, prefs []*models.NotificationPreference) error {
+	if len(prefs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "category"}},
+			DoUpdates: clause.AssignmentColumns([]string{"push", "sms", "email", "updated_at"}),
+		}).
+		Create(&prefs).Error
+}
+
+// GetQuietHours returns nil without an error when the user has never set
+// quiet hours.
+func (r *preferenceRepository) GetQuietHours(ctx context.Context, userID uuid.UUID) (*models.NotificationQuietHours, error) {
+	var quietHours models.NotificationQuietHours
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		First(&quietHours).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &quietHours, nil
+}
+
+func (r *preferenceRepository) UpsertQuietHours(ctx context.Context, quietHours *models.NotificationQuietHours) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"enabled", "start_time", "end_time", "timezone", "updated_at"}),
+		}).
+		Create(quietHours).Error
+}
+
+func (r *preferenceRepository) CreateDeferred(ctx context.Context, notification *models.DeferredNotification) error {
+	return r.db.WithContext(ctx).Create(notification).Error
+}
+
+func (r *preferenceRepository) ListDueDeferred(ctx context.Context, now time.Time, limit int) ([]*models.DeferredNotification, error) {
+	var notifications []*models.DeferredNotification
+	err := r.db.WithContext(ctx).
+		Where("delivered_at IS NULL AND deliver_after <= ?", now).
+		Order("deliver_after ASC").
+		Limit(limit).
+		Find(&notifications).Error
+	return notifications, err
+}
+
+func (r *preferenceRepository) MarkDeferredDelivered(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&models.DeferredNotification{}).
+		Where("id = ? AND delivered_at IS NULL", id).
+		Update("delivered_at", time.Now()).Error
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/notifications/repository"
+	"github.com/umar5678/go-backend/internal/services/health"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+const deferredDeliveryBatchSize = 200
+
+// PreferencePushService applies the user's notification preferences to
+// every push before handing it to the wrapped service. Pushes for a category
+// the user turned off are dropped, deferrable ones that arrive in quiet
+// hours are stored and sent when they end, and safety pushes always go out.
+type PreferencePushService struct {
+	PushService
+	prefs PreferenceService
+	repo  repository.PreferenceRepository
+}
+
+func NewPreferencePushService(inner PushService, prefs PreferenceService, repo repository.PreferenceRepository) *PreferencePushService {
+	return &PreferencePushService{PushService: inner, prefs: prefs, repo: repo}
+}
+
+func (s *PreferencePushService) SendPush(ctx context.Context, userID uuid.UUID, title, body string, data map[string]interface{}) error {
+	category := CategoryFor(data)
+	if category.IsCritical() {
+		return s.PushService.SendPush(ctx, userID, title, body, data)
+	}
+
+	if !s.prefs.Allows(ctx, userID, category, models.ChannelPush) {
+		logger.Info("push suppressed by user preference", "userID", userID, "category", category)
+		return nil
+	}
+
+	if category.IsDeferrable() {
+		if endsAt, quiet := s.prefs.QuietHoursEnd(ctx, userID, time.Now()); quiet {
+			err := s.deferPush(ctx, userID, category, title, body, data, endsAt)
+			if err == nil {
+				return nil
+			}
+			logger.Error("failed to defer push, sending now", "error", err, "userID", userID)
+		}
+	}
+
+	return s.PushService.SendPush(ctx, userID, title, body, data)
+}
+
+func (s *PreferencePushService) deferPush(ctx context.Context, userID uuid.UUID, category models.NotificationCategory, title, body string, data map[string]interface{}, deliverAfter time.Time) error {
+	dataBytes, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	notification := &models.DeferredNotification{
+		UserID:       userID,
+		Category:     category,
+		Title:        title,
+		Body:         body,
+		Data:         dataBytes,
+		DeliverAfter: deliverAfter,
+	}
+	if err := s.repo.CreateDeferred(ctx, notification); err != nil {
+		return err
+	}
+	logger.Info("push deferred for quiet hours", "userID", userID, "category", category, "deliverAfter", deliverAfter)
+	return nil
+}
+
+// DeliverDeferred sends the pushes whose quiet hours have ended and returns
+// how many went out. The preference is checked again, since the user may
+// have turned the category off in the meantime. Each push is marked
+// delivered before it is sent so a failing one is not retried forever.
+func (s *PreferencePushService) DeliverDeferred(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		due, err := s.repo.ListDueDeferred(ctx, time.Now(), deferredDeliveryBatchSize)
+		if err != nil {
+			return total, err
+		}
+		for _, notification := range due {
+			if err := s.repo.MarkDeferredDelivered(ctx, notification.ID); err != nil {
+				return total, err
+			}
+			if !s.prefs.Allows(ctx, notification.UserID, notification.Category, models.ChannelPush) {
+				continue
+			}
+
+			data := make(map[string]interface{})
+			if len(notification.Data) > 0 {
+				if err := json.Unmarshal(notification.Data, &data); err != nil {
+					logger.Warn("failed to decode deferred push data", "error", err, "id", notification.ID)
+				}
+			}
+			if err := s.PushService.SendPush(ctx, notification.UserID, notification.Title, notification.Body, data); err != nil {
+				logger.Error("failed to send deferred push", "error", err, "id", notification.ID, "userID", notification.UserID)
+				continue
+			}
+			total++
+		}
+		if len(due) < deferredDeliveryBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// Stats passes through the wrapped service's statistics when it has any.
+func (s *PreferencePushService) Stats() map[string]interface{} {
+	if sp, ok := s.PushService.(interface{ Stats() map[string]interface{} }); ok {
+		return sp.Stats()
+	}
+	return map[string]interface{}{}
+}
+
+// DeferredPushSweeper sends deferred pushes on a fixed interval.
+type DeferredPushSweeper struct {
+	service  *PreferencePushService
+	interval time.Duration
+}
+
+func NewDeferredPushSweeper(service *PreferencePushService, interval time.Duration) *DeferredPushSweeper {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &DeferredPushSweeper{service: service, interval: interval}
+}
+
+func (w *DeferredPushSweeper) Start(ctx context.Context) {
+	heartbeat := health.NewHeartbeat("deferred_push_delivery", w.interval)
+
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				runCtx, cancel := context.WithTimeout(ctx, time.Minute)
+				if sent, err := w.service.DeliverDeferred(runCtx); err != nil {
+					logger.Error("deferred push delivery failed", "error", err)
+				} else if sent > 0 {
+					logger.Info("deferred pushes delivered", "count", sent)
+				}
+				cancel()
+				heartbeat.Beat()
+			}
+		}
+	}()
+
+	logger.Info("deferred push sweeper started", "interval", w.interval)
+}
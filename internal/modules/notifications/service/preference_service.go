@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/notifications/dto"
+	"github.com/umar5678/go-backend/internal/modules/notifications/repository"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+type PreferenceService interface {
+	GetPreferences(ctx context.Context, userID uuid.UUID) (*dto.PreferencesResponse, error)
+	UpdatePreferences(ctx context.Context, userID uuid.UUID, req *dto.UpdatePreferencesRequest) (*dto.PreferencesResponse, error)
+
+	// Allows reports whether the user wants category notifications on
+	// channel. Safety notifications are always allowed.
+	Allows(ctx context.Context, userID uuid.UUID, category models.NotificationCategory, channel models.NotificationChannel) bool
+	// QuietHoursEnd returns when the user's quiet hours end, and false when
+	// they are not in quiet hours at now.
+	QuietHoursEnd(ctx context.Context, userID uuid.UUID, now time.Time) (time.Time, bool)
+}
+
+type preferenceService struct {
+	repo repository.PreferenceRepository
+}
+
+func NewPreferenceService(repo repository.PreferenceRepository) PreferenceService {
+	return &preferenceService{repo: repo}
+}
+
+func (s *preferenceService) GetPreferences(ctx context.Context, userID uuid.UUID) (*dto.PreferencesResponse, error) {
+	prefs, err := s.repo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+	quietHours, err := s.repo.GetQuietHours(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get quiet hours: %w", err)
+	}
+	return dto.ToPreferencesResponse(prefs, quietHours), nil
+}
+
+func (s *preferenceService) UpdatePreferences(ctx context.Context, userID uuid.UUID, req *dto.UpdatePreferencesRequest) (*dto.PreferencesResponse, error) {
+	if len(req.Preferences) > 0 {
+		current, err := s.repo.GetPreferences(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+		}
+		byCategory := make(map[models.NotificationCategory]*models.NotificationPreference, len(current))
+		for _, pref := range current {
+			byCategory[pref.Category] = pref
+		}
+
+		updated := make([]*models.NotificationPreference, 0, len(req.Preferences))
+		for _, change := range req.Preferences {
+			pref, ok := byCategory[change.Category]
+			if !ok {
+				pref = &models.NotificationPreference{UserID: userID, Category: change.Category, Push: true, SMS: true, Email: true}
+				byCategory[change.Category] = pref
+			}
+			if change.Push != nil {
+				pref.Push = *change.Push
+			}
+			if change.SMS != nil {
+				pref.SMS = *change.SMS
+			}
+			if change.Email != nil {
+				pref.Email = *change.Email
+			}
+			pref.UpdatedAt = time.Now()
+			updated = append(updated, pref)
+		}
+
+		if err := s.repo.UpsertPreferences(ctx, updated); err != nil {
+			return nil, fmt.Errorf("failed to save notification preferences: %w", err)
+		}
+	}
+
+	if q := req.QuietHours; q != nil {
+		quietHours := &models.NotificationQuietHours{
+			UserID:    userID,
+			Enabled:   q.Enabled,
+			StartTime: q.StartTime,
+			EndTime:   q.EndTime,
+			Timezone:  q.Timezone,
+			UpdatedAt: time.Now(),
+		}
+		if err := s.repo.UpsertQuietHours(ctx, quietHours); err != nil {
+			return nil, fmt.Errorf("failed to save quiet hours: %w", err)
+		}
+	}
+
+	logger.Info("notification preferences updated", "userID", userID, "categories", len(req.Preferences), "quietHours", req.QuietHours != nil)
+
+	return s.GetPreferences(ctx, userID)
+}
+
+// Allows errs on the side of delivering: a notification is only held back
+// when the user's choice could be read.
+func (s *preferenceService) Allows(ctx context.Context, userID uuid.UUID, category models.NotificationCategory, channel models.NotificationChannel) bool {
+	if category.IsCritical() {
+		return true
+	}
+	pref, err := s.repo.GetPreference(ctx, userID, category)
+	if err != nil {
+		logger.Warn("failed to look up notification preference", "userID", userID, "category", category, "error", err)
+		return true
+	}
+	if pref == nil {
+		return true
+	}
+	return pref.Allows(channel)
+}
+
+func (s *preferenceService) QuietHoursEnd(ctx context.Context, userID uuid.UUID, now time.Time) (time.Time, bool) {
+	quietHours, err := s.repo.GetQuietHours(ctx, userID)
+	if err != nil {
+		logger.Warn("failed to look up quiet hours", "userID", userID, "error", err)
+		return time.Time{}, false
+	}
+	if quietHours == nil {
+		return time.Time{}, false
+	}
+	return quietHours.EndsAt(now)
+}
+
+// categoryRules map fragments of a notification's type, event type or
+// message key to its category. The first match wins, so safety comes first:
+// "ride.high_risk_rider" is a safety alert, not a ride update.
+var categoryRules = []struct {
+	category  models.NotificationCategory
+	fragments []string
+}{
+	{models.NotificationCategorySafety, []string{"sos", "security", "fraud", "high_risk"}},
+	{models.NotificationCategoryMarketing, []string{"promo", "deal", "referral", "marketing", "campaign"}},
+	{models.NotificationCategoryAccount, []string{"user.", "user_", "vehicle", "kyc", "auth.", "verification", "document"}},
+	{models.NotificationCategoryTransactional, []string{"ride", "payment", "refund", "fare_split", "wallet", "order", "delivery"}},
+}
+
+// CategoryFor works out which category a push belongs to from its data. A
+// sender can set data["category"] to choose one outright; anything that
+// matches no rule is informational.
+func CategoryFor(data map[string]interface{}) models.NotificationCategory {
+	if category, ok := data["category"].(string); ok && models.NotificationCategory(category).IsValid() {
+		return models.NotificationCategory(category)
+	}
+
+	var keys []string
+	for _, field := range []string{"event_type", "type", "messageKey"} {
+		if value, ok := data[field]; ok {
+			keys = append(keys, strings.ToLower(fmt.Sprint(value)))
+		}
+	}
+
+	for _, rule := range categoryRules {
+		for _, key := range keys {
+			for _, fragment := range rule.fragments {
+				if strings.Contains(key, fragment) {
+					return rule.category
+				}
+			}
+		}
+	}
+	return models.NotificationCategoryInformational
+}
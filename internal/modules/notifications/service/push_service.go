@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/google/uuid"
+	"github.com/umar5678/go-backend/internal/services/i18n"
 	"github.com/umar5678/go-backend/internal/utils/logger"
 )
 
@@ -23,6 +24,18 @@ type PushService interface {
 	SetWSNotifier(ws WSNotifier)
 }
 
+// SendLocalizedPush sends the push whose title and body are the catalog
+// entries key+".title" and key+".body", in the user's preferred language. The
+// key goes along in data as "messageKey" so clients can render it themselves.
+func SendLocalizedPush(ctx context.Context, svc PushService, userID uuid.UUID, key string, params map[string]interface{}, data map[string]interface{}) error {
+	lang := i18n.UserLanguage(ctx, userID.String())
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	data["messageKey"] = key
+	return svc.SendPush(ctx, userID, i18n.T(lang, key+".title", params), i18n.T(lang, key+".body", params), data)
+}
+
 func SendSecurityAlert(ctx context.Context, svc PushService, userID uuid.UUID, patternID string, riskScore float64) error {
 	data := map[string]interface{}{
 		"type":       "security_alert",
@@ -30,12 +43,12 @@ func SendSecurityAlert(ctx context.Context, svc PushService, userID uuid.UUID, p
 		"risk_score": fmt.Sprintf("%.0f", riskScore),
 	}
 
-	body := "Unusual activity detected on your account"
+	key := "push.security_alert"
 	if riskScore > 80 {
-		body = "High-risk suspicious activity detected on your account"
+		key = "push.security_alert_high"
 	}
 
-	if err := svc.SendPush(ctx, userID, "Security Alert", body, data); err != nil {
+	if err := SendLocalizedPush(ctx, svc, userID, key, nil, data); err != nil {
 		logger.Error("failed to send security alert", "error", err, "userID", userID)
 		return err
 	}
@@ -50,9 +63,9 @@ func SendRideCompleteNotification(ctx context.Context, svc PushService, userID u
 		"fare":    fmt.Sprintf("%.2f", finalFare),
 	}
 
-	body := fmt.Sprintf("Your ride is complete. Total: ₹%.2f", finalFare)
+	params := map[string]interface{}{"amount": fmt.Sprintf("%.2f", finalFare)}
 
-	if err := svc.SendPush(ctx, userID, "Ride Completed", body, data); err != nil {
+	if err := SendLocalizedPush(ctx, svc, userID, "push.ride_completed_rider", params, data); err != nil {
 		logger.Error("failed to send ride complete notification", "error", err, "userID", userID)
 		return err
 	}
@@ -68,9 +81,9 @@ func SendRideAcceptedNotification(ctx context.Context, svc PushService, userID u
 		"eta":         eta,
 	}
 
-	body := fmt.Sprintf("%s accepted your ride. ETA: %d min", driverName, eta)
+	params := map[string]interface{}{"driver": driverName, "eta": eta}
 
-	if err := svc.SendPush(ctx, userID, "Driver Assigned", body, data); err != nil {
+	if err := SendLocalizedPush(ctx, svc, userID, "push.ride_accepted", params, data); err != nil {
 		logger.Error("failed to send ride accepted notification", "error", err, "userID", userID)
 		return err
 	}
@@ -79,9 +92,9 @@ func SendRideAcceptedNotification(ctx context.Context, svc PushService, userID u
 }
 
 func SendPaymentNotification(ctx context.Context, svc PushService, userID uuid.UUID, amount float64, status string) error {
-	title := "Payment Processed"
+	key := "push.payment_processed"
 	if status == "failed" {
-		title = "Payment Failed"
+		key = "push.payment_failed"
 	}
 
 	data := map[string]interface{}{
@@ -90,9 +103,9 @@ func SendPaymentNotification(ctx context.Context, svc PushService, userID uuid.U
 		"status": status,
 	}
 
-	body := fmt.Sprintf("Payment of ₹%.2f %s", amount, status)
+	params := map[string]interface{}{"amount": fmt.Sprintf("%.2f", amount)}
 
-	if err := svc.SendPush(ctx, userID, title, body, data); err != nil {
+	if err := SendLocalizedPush(ctx, svc, userID, key, params, data); err != nil {
 		logger.Error("failed to send payment notification", "error", err, "userID", userID)
 		return err
 	}
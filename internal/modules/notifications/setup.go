@@ -2,6 +2,7 @@ package notifications
 
 import (
 	"context"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -16,9 +17,12 @@ type NotificationSystem struct {
 	consumers           []*KafkaConsumer
 	pushService         service.PushService
 	notificationService service.NotificationService
+	preferenceService   service.PreferenceService
 	db                  *gorm.DB
 }
 
+const deferredPushSweepInterval = time.Minute
+
 func NewNotificationSystem(
 	ctx context.Context,
 	db *gorm.DB,
@@ -31,6 +35,8 @@ func NewNotificationSystem(
 	pushSvc := service.NewLocalPushService(db, notifRepo)
 	pushSvc.SetWSNotifier(wsNotifier)
 	notifSvc := service.NewNotificationService(notifRepo)
+	prefRepo := repository.NewPreferenceRepository(db)
+	prefSvc := service.NewPreferenceService(prefRepo)
 
 	producerConfig := DefaultProducerConfig(kafkaConfig.Brokers)
 	registry := NewEventRegistry()
@@ -62,8 +68,9 @@ func NewNotificationSystem(
 	ns := &NotificationSystem{
 		producer:            producer,
 		consumers:           consumers,
-		pushService:         pushSvc,
+		pushService:         service.NewPreferencePushService(pushSvc, prefSvc, prefRepo),
 		notificationService: notifSvc,
+		preferenceService:   prefSvc,
 		db:                  db,
 	}
 	for _, consumer := range consumers {
@@ -86,7 +93,7 @@ func NewNotificationSystem(
 			)
 		} else {
 			fcmSvc.SetWSNotifier(wsNotifier)
-			ns.pushService = fcmSvc
+			ns.pushService = service.NewPreferencePushService(fcmSvc, prefSvc, prefRepo)
 			logger.Info("using FCM push service")
 		}
 	}
@@ -104,6 +111,9 @@ func (ns *NotificationSystem) Start(ctx context.Context) error {
 			}
 		}(i, consumer)
 	}
+	if sweepable, ok := ns.pushService.(*service.PreferencePushService); ok {
+		service.NewDeferredPushSweeper(sweepable, deferredPushSweepInterval).Start(ctx)
+	}
 	logger.Info("notification system started", "active_consumers", len(ns.consumers))
 	return nil
 }
@@ -133,6 +143,10 @@ func (ns *NotificationSystem) GetNotificationService() service.NotificationServi
 	return ns.notificationService
 }
 
+func (ns *NotificationSystem) GetPreferenceService() service.PreferenceService {
+	return ns.preferenceService
+}
+
 func (ns *NotificationSystem) registerEventHandlers(consumer *KafkaConsumer) {
 	rideHandler := NewRideEventHandler(ns.pushService, ns.db)
 	if err := consumer.Subscribe(rideHandler); err != nil {
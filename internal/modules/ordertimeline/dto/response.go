@@ -0,0 +1,47 @@
+package dto
+
+import "time"
+
+const (
+	VerticalServices = "services"
+	VerticalLaundry  = "laundry"
+)
+
+// Timeline event types group events by what they are about.
+const (
+	EventTypeStatus   = "status"
+	EventTypeProvider = "provider"
+	EventTypePickup   = "pickup"
+	EventTypeDelivery = "delivery"
+	EventTypePayment  = "payment"
+	EventTypeIssue    = "issue"
+)
+
+// OrderTimelineResponse is everything that has happened to a home services or
+// laundry order, oldest first.
+type OrderTimelineResponse struct {
+	OrderID     string                  `json:"orderId"`
+	OrderNumber string                  `json:"orderNumber"`
+	Vertical    string                  `json:"vertical"`
+	Status      string                  `json:"status"`
+	Events      []TimelineEventResponse `json:"events"`
+}
+
+// TimelineEventResponse is one entry on an order's timeline. Event names what
+// happened within its Type, e.g. "picked_up" for a pickup event; Actor is who
+// caused it where that is known.
+type TimelineEventResponse struct {
+	Type        string                 `json:"type"`
+	Event       string                 `json:"event"`
+	Title       string                 `json:"title"`
+	Description string                 `json:"description,omitempty"`
+	Actor       *TimelineActor         `json:"actor,omitempty"`
+	OccurredAt  time.Time              `json:"occurredAt"`
+	Data        map[string]interface{} `json:"data,omitempty"`
+}
+
+type TimelineActor struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	Role string `json:"role"`
+}
@@ -0,0 +1,38 @@
+package ordertimeline
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// GetTimeline godoc
+// @Summary Get order timeline
+// @Description Status changes, provider assignment, laundry pickup and delivery, issues and payments of a home services or laundry order, oldest first
+// @Tags orders
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Order ID"
+// @Success 200 {object} response.Response{data=dto.OrderTimelineResponse}
+// @Failure 404 {object} response.Response
+// @Router /orders/{id}/timeline [get]
+func (h *Handler) GetTimeline(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+	roleName, _ := role.(string)
+
+	timeline, err := h.service.GetTimeline(c.Request.Context(), userID.(string), roleName, c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, timeline, "Order timeline retrieved successfully")
+}
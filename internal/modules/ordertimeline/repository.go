@@ -0,0 +1,132 @@
+package ordertimeline
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	FindServiceOrder(ctx context.Context, orderID string) (*models.ServiceOrderNew, error)
+	ListStatusHistory(ctx context.Context, orderID string) ([]*models.OrderStatusHistory, error)
+
+	FindLaundryOrder(ctx context.Context, orderID string) (*models.LaundryOrder, error)
+	FindLaundryPickup(ctx context.Context, orderID string) (*models.LaundryPickup, error)
+	FindLaundryDelivery(ctx context.Context, orderID string) (*models.LaundryDelivery, error)
+	ListLaundryIssues(ctx context.Context, orderID string) ([]*models.LaundryIssue, error)
+
+	FindProviders(ctx context.Context, providerIDs []string) (map[string]*models.ServiceProviderProfile, error)
+	ListHolds(ctx context.Context, customerID, referenceType, orderID string) ([]*models.WalletHold, error)
+	ListTransactions(ctx context.Context, customerID, referenceType, orderID string) ([]*models.WalletTransaction, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) FindServiceOrder(ctx context.Context, orderID string) (*models.ServiceOrderNew, error) {
+	var order models.ServiceOrderNew
+	err := r.db.WithContext(ctx).Where("id = ?", orderID).First(&order).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *repository) ListStatusHistory(ctx context.Context, orderID string) ([]*models.OrderStatusHistory, error) {
+	var history []*models.OrderStatusHistory
+	err := r.db.WithContext(ctx).
+		Preload("ChangedByUser").
+		Where("order_id = ?", orderID).
+		Order("created_at ASC").
+		Find(&history).Error
+	return history, err
+}
+
+func (r *repository) FindLaundryOrder(ctx context.Context, orderID string) (*models.LaundryOrder, error) {
+	var order models.LaundryOrder
+	err := r.db.WithContext(ctx).Where("id = ?", orderID).First(&order).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *repository) FindLaundryPickup(ctx context.Context, orderID string) (*models.LaundryPickup, error) {
+	var pickup models.LaundryPickup
+	err := r.db.WithContext(ctx).Where("order_id = ?", orderID).First(&pickup).Error
+	if err != nil {
+		return nil, err
+	}
+	return &pickup, nil
+}
+
+func (r *repository) FindLaundryDelivery(ctx context.Context, orderID string) (*models.LaundryDelivery, error) {
+	var delivery models.LaundryDelivery
+	err := r.db.WithContext(ctx).Where("order_id = ?", orderID).First(&delivery).Error
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (r *repository) ListLaundryIssues(ctx context.Context, orderID string) ([]*models.LaundryIssue, error) {
+	var issues []*models.LaundryIssue
+	err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("created_at ASC").
+		Find(&issues).Error
+	return issues, err
+}
+
+// FindProviders loads the given provider profiles with their users, keyed by
+// profile ID.
+func (r *repository) FindProviders(ctx context.Context, providerIDs []string) (map[string]*models.ServiceProviderProfile, error) {
+	providers := make(map[string]*models.ServiceProviderProfile, len(providerIDs))
+	if len(providerIDs) == 0 {
+		return providers, nil
+	}
+
+	var profiles []*models.ServiceProviderProfile
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("id IN ?", providerIDs).
+		Find(&profiles).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, profile := range profiles {
+		providers[profile.ID] = profile
+	}
+	return providers, nil
+}
+
+// ListHolds returns the holds placed on the customer's wallet for the order.
+func (r *repository) ListHolds(ctx context.Context, customerID, referenceType, orderID string) ([]*models.WalletHold, error) {
+	var holds []*models.WalletHold
+	err := r.db.WithContext(ctx).
+		Joins("JOIN wallets ON wallets.id = wallet_holds.wallet_id").
+		Where("wallets.user_id = ?", customerID).
+		Where("wallet_holds.reference_type = ? AND wallet_holds.reference_id = ?", referenceType, orderID).
+		Order("wallet_holds.created_at ASC").
+		Find(&holds).Error
+	return holds, err
+}
+
+// ListTransactions returns the customer's wallet movements for the order.
+// Provider payouts against the same order are left out.
+func (r *repository) ListTransactions(ctx context.Context, customerID, referenceType, orderID string) ([]*models.WalletTransaction, error) {
+	var txns []*models.WalletTransaction
+	err := r.db.WithContext(ctx).
+		Joins("JOIN wallets ON wallets.id = wallet_transactions.wallet_id").
+		Where("wallets.user_id = ?", customerID).
+		Where("wallet_transactions.reference_type = ? AND wallet_transactions.reference_id = ?", referenceType, orderID).
+		Order("wallet_transactions.created_at ASC").
+		Find(&txns).Error
+	return txns, err
+}
@@ -0,0 +1,11 @@
+package ordertimeline
+
+import "github.com/gin-gonic/gin"
+
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
+	orders := router.Group("/orders")
+	orders.Use(authMiddleware)
+	{
+		orders.GET("/:id/timeline", handler.GetTimeline)
+	}
+}
@@ -0,0 +1,447 @@
+package ordertimeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+	"github.com/umar5678/go-backend/internal/modules/ordertimeline/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+const (
+	referenceServiceOrder = "service_order"
+	referenceLaundryOrder = "laundry_order"
+)
+
+type Service interface {
+	GetTimeline(ctx context.Context, userID, role, orderID string) (*dto.OrderTimelineResponse, error)
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+// GetTimeline builds the timeline of a home services or laundry order. The
+// customer, the provider on the order and admins may see it; anyone else is
+// told the order does not exist.
+func (s *service) GetTimeline(ctx context.Context, userID, role, orderID string) (*dto.OrderTimelineResponse, error) {
+	order, err := s.repo.FindServiceOrder(ctx, orderID)
+	if err == nil {
+		return s.serviceOrderTimeline(ctx, userID, role, order)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, response.InternalServerError("Failed to get order", err)
+	}
+
+	laundryOrder, err := s.repo.FindLaundryOrder(ctx, orderID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, response.NotFoundError("Order")
+	}
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get order", err)
+	}
+	return s.laundryOrderTimeline(ctx, userID, role, laundryOrder)
+}
+
+func (s *service) serviceOrderTimeline(ctx context.Context, userID, role string, order *models.ServiceOrderNew) (*dto.OrderTimelineResponse, error) {
+	history, err := s.repo.ListStatusHistory(ctx, order.ID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get order history", err)
+	}
+
+	providerIDs := []string{}
+	if order.AssignedProviderID != nil {
+		providerIDs = append(providerIDs, *order.AssignedProviderID)
+	}
+	for _, entry := range history {
+		if id := historyProviderID(entry); id != "" {
+			providerIDs = append(providerIDs, id)
+		}
+	}
+	providers, err := s.repo.FindProviders(ctx, providerIDs)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get providers", err)
+	}
+	if !canView(userID, role, order.CustomerID, providers[stringValue(order.AssignedProviderID)]) {
+		return nil, response.NotFoundError("Order")
+	}
+
+	timeline := &dto.OrderTimelineResponse{
+		OrderID:     order.ID,
+		OrderNumber: order.OrderNumber,
+		Vertical:    dto.VerticalServices,
+		Status:      order.Status,
+		Events:      []dto.TimelineEventResponse{},
+	}
+
+	if len(history) == 0 || history[0].FromStatus != "" {
+		timeline.Events = append(timeline.Events, dto.TimelineEventResponse{
+			Type:       dto.EventTypeStatus,
+			Event:      "placed",
+			Title:      "Order placed",
+			Actor:      &dto.TimelineActor{ID: order.CustomerID, Role: shared.RoleCustomer},
+			OccurredAt: order.CreatedAt,
+		})
+	}
+
+	for _, entry := range history {
+		event := dto.TimelineEventResponse{
+			Type:        dto.EventTypeStatus,
+			Event:       entry.ToStatus,
+			Title:       serviceStatusTitle(entry.ToStatus),
+			Description: entry.Notes,
+			Actor:       historyActor(entry),
+			OccurredAt:  entry.CreatedAt,
+			Data: map[string]interface{}{
+				"fromStatus": entry.FromStatus,
+				"toStatus":   entry.ToStatus,
+			},
+		}
+		for key, value := range entry.Metadata {
+			event.Data[key] = value
+		}
+
+		if entry.ToStatus == shared.OrderStatusAssigned || entry.ToStatus == shared.OrderStatusAccepted {
+			event.Type = dto.EventTypeProvider
+			providerID := historyProviderID(entry)
+			if providerID == "" {
+				providerID = stringValue(order.AssignedProviderID)
+			}
+			addProvider(event.Data, providers[providerID])
+		}
+		timeline.Events = append(timeline.Events, event)
+	}
+
+	payments, err := s.paymentEvents(ctx, order.CustomerID, referenceServiceOrder, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	timeline.Events = append(timeline.Events, payments...)
+
+	sortEvents(timeline.Events)
+	return timeline, nil
+}
+
+func (s *service) laundryOrderTimeline(ctx context.Context, userID, role string, order *models.LaundryOrder) (*dto.OrderTimelineResponse, error) {
+	pickup, err := s.repo.FindLaundryPickup(ctx, order.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, response.InternalServerError("Failed to get pickup", err)
+	}
+	delivery, err := s.repo.FindLaundryDelivery(ctx, order.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, response.InternalServerError("Failed to get delivery", err)
+	}
+
+	providerIDs := []string{}
+	for _, id := range []*string{order.ProviderID, pickupProviderID(pickup), deliveryProviderID(delivery)} {
+		if id != nil {
+			providerIDs = append(providerIDs, *id)
+		}
+	}
+	providers, err := s.repo.FindProviders(ctx, providerIDs)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get providers", err)
+	}
+	if !canView(userID, role, stringValue(order.UserID), providers[stringValue(order.ProviderID)]) {
+		return nil, response.NotFoundError("Order")
+	}
+
+	issues, err := s.repo.ListLaundryIssues(ctx, order.ID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get order issues", err)
+	}
+
+	timeline := &dto.OrderTimelineResponse{
+		OrderID:     order.ID,
+		OrderNumber: order.OrderNumber,
+		Vertical:    dto.VerticalLaundry,
+		Status:      order.Status,
+		Events: []dto.TimelineEventResponse{{
+			Type:       dto.EventTypeStatus,
+			Event:      "placed",
+			Title:      "Order placed",
+			Actor:      &dto.TimelineActor{ID: stringValue(order.UserID), Role: shared.RoleCustomer},
+			OccurredAt: order.CreatedAt,
+			Data:       map[string]interface{}{"total": order.Total, "isExpress": order.IsExpress},
+		}},
+	}
+
+	if pickup != nil {
+		provider := providers[stringValue(pickup.ProviderID)]
+		timeline.Events = append(timeline.Events,
+			legEvent(dto.EventTypePickup, "scheduled", "Pickup scheduled", pickup.CreatedAt, nil,
+				map[string]interface{}{"scheduledAt": pickup.ScheduledAt}))
+		if pickup.ArrivedAt != nil {
+			timeline.Events = append(timeline.Events,
+				legEvent(dto.EventTypePickup, "arrived", "Provider arrived for pickup", *pickup.ArrivedAt, provider, nil))
+		}
+		if pickup.PickedUpAt != nil {
+			event := legEvent(dto.EventTypePickup, "picked_up", "Laundry picked up", *pickup.PickedUpAt, provider,
+				map[string]interface{}{"bagCount": pickup.BagCount})
+			event.Description = pickup.Notes
+			timeline.Events = append(timeline.Events, event)
+		}
+	}
+
+	if delivery != nil {
+		provider := providers[stringValue(delivery.ProviderID)]
+		timeline.Events = append(timeline.Events,
+			legEvent(dto.EventTypeDelivery, "scheduled", "Delivery scheduled", delivery.CreatedAt, nil,
+				map[string]interface{}{"scheduledAt": delivery.ScheduledAt, "rescheduleCount": delivery.RescheduleCount}))
+		if delivery.ArrivedAt != nil {
+			timeline.Events = append(timeline.Events,
+				legEvent(dto.EventTypeDelivery, "arrived", "Provider arrived for delivery", *delivery.ArrivedAt, provider, nil))
+		}
+		if delivery.DeliveredAt != nil {
+			data := map[string]interface{}{}
+			if delivery.RecipientName != nil {
+				data["recipientName"] = *delivery.RecipientName
+			}
+			event := legEvent(dto.EventTypeDelivery, "delivered", "Laundry delivered", *delivery.DeliveredAt, provider, data)
+			event.Description = delivery.Notes
+			timeline.Events = append(timeline.Events, event)
+		}
+	}
+
+	for _, issue := range issues {
+		timeline.Events = append(timeline.Events, dto.TimelineEventResponse{
+			Type:        dto.EventTypeIssue,
+			Event:       "reported",
+			Title:       "Issue reported",
+			Description: issue.Description,
+			Actor:       &dto.TimelineActor{ID: issue.CustomerID, Role: shared.RoleCustomer},
+			OccurredAt:  issue.CreatedAt,
+			Data: map[string]interface{}{
+				"issueId":   issue.ID,
+				"issueType": issue.IssueType,
+				"priority":  issue.Priority,
+			},
+		})
+		if issue.ResolvedAt != nil {
+			data := map[string]interface{}{"issueId": issue.ID}
+			if issue.RefundAmount != nil {
+				data["refundAmount"] = *issue.RefundAmount
+			}
+			timeline.Events = append(timeline.Events, dto.TimelineEventResponse{
+				Type:        dto.EventTypeIssue,
+				Event:       "resolved",
+				Title:       "Issue resolved",
+				Description: stringValue(issue.Resolution),
+				OccurredAt:  *issue.ResolvedAt,
+				Data:        data,
+			})
+		}
+	}
+
+	payments, err := s.paymentEvents(ctx, stringValue(order.UserID), referenceLaundryOrder, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	timeline.Events = append(timeline.Events, payments...)
+
+	sortEvents(timeline.Events)
+	return timeline, nil
+}
+
+// paymentEvents turns the holds and wallet movements made against an order
+// into timeline events. A captured hold shows only as reserved, since the
+// capture is recorded as its own debit.
+func (s *service) paymentEvents(ctx context.Context, customerID, referenceType, orderID string) ([]dto.TimelineEventResponse, error) {
+	if customerID == "" {
+		return nil, nil
+	}
+
+	holds, err := s.repo.ListHolds(ctx, customerID, referenceType, orderID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get payment holds", err)
+	}
+	txns, err := s.repo.ListTransactions(ctx, customerID, referenceType, orderID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get payments", err)
+	}
+
+	events := make([]dto.TimelineEventResponse, 0, len(holds)+len(txns))
+	for _, hold := range holds {
+		data := map[string]interface{}{
+			"holdId":   hold.ID,
+			"amount":   hold.Amount,
+			"currency": hold.Currency,
+		}
+		events = append(events, dto.TimelineEventResponse{
+			Type:       dto.EventTypePayment,
+			Event:      "reserved",
+			Title:      fmt.Sprintf("%.2f %s reserved", hold.Amount, hold.Currency),
+			OccurredAt: hold.CreatedAt,
+			Data:       data,
+		})
+		if hold.Status == models.TransactionStatusReleased && hold.ReleasedAt != nil {
+			events = append(events, dto.TimelineEventResponse{
+				Type:       dto.EventTypePayment,
+				Event:      "released",
+				Title:      fmt.Sprintf("%.2f %s released", hold.Amount, hold.Currency),
+				OccurredAt: *hold.ReleasedAt,
+				Data:       data,
+			})
+		}
+	}
+
+	for _, txn := range txns {
+		events = append(events, dto.TimelineEventResponse{
+			Type:        dto.EventTypePayment,
+			Event:       paymentEvent(txn.Type),
+			Title:       fmt.Sprintf("%.2f %s %s", txn.Amount, txn.Currency, paymentEvent(txn.Type)),
+			Description: stringValue(txn.Description),
+			OccurredAt:  txn.CreatedAt,
+			Data: map[string]interface{}{
+				"transactionId": txn.ID,
+				"amount":        txn.Amount,
+				"currency":      txn.Currency,
+				"status":        txn.Status,
+				"paymentMethod": txn.PaymentMethod,
+			},
+		})
+	}
+	return events, nil
+}
+
+func paymentEvent(txnType models.TransactionType) string {
+	switch txnType {
+	case models.TransactionTypeDebit:
+		return "charged"
+	case models.TransactionTypeRefund:
+		return "refunded"
+	case models.TransactionTypeCredit:
+		return "credited"
+	default:
+		return string(txnType)
+	}
+}
+
+// canView reports whether the user may see an order's timeline.
+func canView(userID, role, customerID string, provider *models.ServiceProviderProfile) bool {
+	if role == string(models.RoleAdmin) || userID == customerID {
+		return true
+	}
+	return provider != nil && provider.UserID == userID
+}
+
+// historyProviderID is the provider a status change assigned the order to,
+// if the change recorded one.
+func historyProviderID(entry *models.OrderStatusHistory) string {
+	id, _ := entry.Metadata["newProviderId"].(string)
+	return id
+}
+
+func historyActor(entry *models.OrderStatusHistory) *dto.TimelineActor {
+	if entry.ChangedByRole == "" && entry.ChangedBy == nil {
+		return nil
+	}
+	actor := &dto.TimelineActor{ID: stringValue(entry.ChangedBy), Role: entry.ChangedByRole}
+	if entry.ChangedByUser != nil && entry.ChangedByRole != shared.RoleAdmin {
+		actor.Name = entry.ChangedByUser.Name
+	}
+	return actor
+}
+
+func legEvent(eventType, event, title string, at time.Time, provider *models.ServiceProviderProfile, data map[string]interface{}) dto.TimelineEventResponse {
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	addProvider(data, provider)
+
+	result := dto.TimelineEventResponse{
+		Type:       eventType,
+		Event:      event,
+		Title:      title,
+		OccurredAt: at,
+		Data:       data,
+	}
+	if provider != nil {
+		result.Actor = &dto.TimelineActor{ID: provider.UserID, Name: providerName(provider), Role: shared.RoleProvider}
+	}
+	if len(result.Data) == 0 {
+		result.Data = nil
+	}
+	return result
+}
+
+func addProvider(data map[string]interface{}, provider *models.ServiceProviderProfile) {
+	if provider == nil {
+		return
+	}
+	data["providerId"] = provider.ID
+	data["providerName"] = providerName(provider)
+}
+
+func providerName(provider *models.ServiceProviderProfile) string {
+	if provider.BusinessName != nil && *provider.BusinessName != "" {
+		return *provider.BusinessName
+	}
+	if provider.User != nil {
+		return provider.User.Name
+	}
+	return ""
+}
+
+func serviceStatusTitle(status string) string {
+	switch status {
+	case shared.OrderStatusPending:
+		return "Order placed"
+	case shared.OrderStatusSearchingProvider:
+		return "Searching for a provider"
+	case shared.OrderStatusAssigned:
+		return "Provider assigned"
+	case shared.OrderStatusAccepted:
+		return "Provider accepted the order"
+	case shared.OrderStatusInProgress:
+		return "Service started"
+	case shared.OrderStatusCompleted:
+		return "Service completed"
+	case shared.OrderStatusCancelled:
+		return "Order cancelled"
+	case shared.OrderStatusExpired:
+		return "Order expired"
+	default:
+		return "Status changed to " + strings.ReplaceAll(status, "_", " ")
+	}
+}
+
+// sortEvents orders events oldest first, keeping the order they were added
+// in when two happened at the same time.
+func sortEvents(events []dto.TimelineEventResponse) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].OccurredAt.Before(events[j].OccurredAt)
+	})
+}
+
+func pickupProviderID(pickup *models.LaundryPickup) *string {
+	if pickup == nil {
+		return nil
+	}
+	return pickup.ProviderID
+}
+
+func deliveryProviderID(delivery *models.LaundryDelivery) *string {
+	if delivery == nil {
+		return nil
+	}
+	return delivery.ProviderID
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
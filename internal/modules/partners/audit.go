@@ -0,0 +1,48 @@
+package partners
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/audit"
+)
+
+func (s *service) SetAuditLogger(auditLogger audit.Recorder) {
+	s.auditLogger = auditLogger
+}
+
+func (s *service) recordAudit(ctx context.Context, adminID, action, entityType, entityID string, before, after map[string]interface{}, reason string) {
+	if s.auditLogger == nil {
+		return
+	}
+	s.auditLogger.Record(ctx, audit.Entry{
+		ActorID:    adminID,
+		ActorRole:  string(models.RoleAdmin),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     before,
+		After:      after,
+		Reason:     reason,
+	})
+}
+
+func partnerAuditSnapshot(p *models.Partner) map[string]interface{} {
+	return map[string]interface{}{
+		"name":   p.Name,
+		"status": p.Status,
+	}
+}
+
+// keyAuditSnapshot leaves out the secret hash and encrypted secret.
+func keyAuditSnapshot(k *models.PartnerAPIKey) map[string]interface{} {
+	return map[string]interface{}{
+		"partnerId":          k.PartnerID,
+		"keyId":              k.KeyID,
+		"scopes":             []string(k.Scopes),
+		"requireSignature":   k.RequireSignature,
+		"rateLimitPerMinute": k.RateLimitPerMinute,
+		"expiresAt":          k.ExpiresAt,
+		"revokedAt":          k.RevokedAt,
+	}
+}
@@ -0,0 +1,117 @@
+package partners
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const keyIDPrefix = "pk_"
+
+// generateKey returns a new key ID and secret. Partners present them joined
+// as "<keyID>.<secret>".
+func generateKey() (keyID, secret string, err error) {
+	id := make([]byte, 12)
+	if _, err := rand.Read(id); err != nil {
+		return "", "", err
+	}
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", err
+	}
+	return keyIDPrefix + hex.EncodeToString(id), base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func splitKey(key string) (keyID, secret string, ok bool) {
+	keyID, secret, ok = strings.Cut(key, ".")
+	if !ok || !strings.HasPrefix(keyID, keyIDPrefix) || secret == "" {
+		return "", "", false
+	}
+	return keyID, secret, true
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+func secretMatches(secret, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(hash)) == 1
+}
+
+// secretBox encrypts the secrets of signing keys with AES-GCM.
+type secretBox struct {
+	aead cipher.AEAD
+}
+
+// newSecretBox accepts a base64 encoded 32-byte key. An empty key returns nil,
+// which disables signing keys.
+func newSecretBox(encoded string) (*secretBox, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("partner signing key is not valid base64: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("partner signing key must be 32 bytes, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &secretBox{aead: aead}, nil
+}
+
+func (b *secretBox) seal(plaintext string) (string, error) {
+	nonce := make([]byte, b.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := b.aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (b *secretBox) open(encoded string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+	size := b.aead.NonceSize()
+	if len(sealed) < size {
+		return "", errors.New("sealed secret is too short")
+	}
+	plaintext, err := b.aead.Open(nil, sealed[:size], sealed[size:], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// signaturePayload is what a signed request's X-Signature covers: the
+// timestamp, method, request URI and the hex SHA-256 of the body, joined by
+// newlines.
+func signaturePayload(timestamp, method, requestURI string, body []byte) string {
+	sum := sha256.Sum256(body)
+	return strings.Join([]string{timestamp, method, requestURI, hex.EncodeToString(sum[:])}, "\n")
+}
+
+func signatureMatches(secret, payload, signature string) bool {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	expected := hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(strings.ToLower(signature)))
+}
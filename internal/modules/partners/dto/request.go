@@ -0,0 +1,72 @@
+package dto
+
+import (
+	"errors"
+	"time"
+)
+
+type CreatePartnerRequest struct {
+	Name         string `json:"name" binding:"required,min=2,max=255"`
+	ContactEmail string `json:"contactEmail" binding:"omitempty,email"`
+}
+
+type UpdatePartnerStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=active disabled"`
+	Reason string `json:"reason" binding:"omitempty,max=500"`
+}
+
+type ListPartnersRequest struct {
+	Status string `form:"status" binding:"omitempty,oneof=active disabled"`
+	Page   int    `form:"page" binding:"omitempty,min=1"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (r *ListPartnersRequest) SetDefaults() {
+	if r.Page <= 0 {
+		r.Page = 1
+	}
+	if r.Limit <= 0 {
+		r.Limit = 20
+	}
+}
+
+// CreateAPIKeyRequest issues a key. RateLimitPerMinute defaults to the
+// configured partner default.
+type CreateAPIKeyRequest struct {
+	Name               string     `json:"name" binding:"required,min=2,max=100"`
+	Scopes             []string   `json:"scopes" binding:"required,min=1,dive,oneof=rides:read rides:write orders:read orders:write"`
+	RequireSignature   bool       `json:"requireSignature"`
+	RateLimitPerMinute int        `json:"rateLimitPerMinute" binding:"omitempty,min=1,max=100000"`
+	ExpiresAt          *time.Time `json:"expiresAt"`
+}
+
+func (r *CreateAPIKeyRequest) Validate() error {
+	if r.ExpiresAt != nil && !r.ExpiresAt.After(time.Now()) {
+		return errors.New("expiresAt must be in the future")
+	}
+	return nil
+}
+
+type LinkUserRequest struct {
+	UserID      string `json:"userId" binding:"required,uuid"`
+	ExternalRef string `json:"externalRef" binding:"omitempty,max=100"`
+}
+
+type ListUsersRequest struct {
+	Page  int `form:"page" binding:"omitempty,min=1"`
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (r *ListUsersRequest) SetDefaults() {
+	if r.Page <= 0 {
+		r.Page = 1
+	}
+	if r.Limit <= 0 {
+		r.Limit = 20
+	}
+}
+
+type UsageRequest struct {
+	From string `form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To   string `form:"to" binding:"omitempty,datetime=2006-01-02"`
+}
@@ -0,0 +1,102 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+type PartnerResponse struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	ContactEmail string    `json:"contactEmail,omitempty"`
+	Status       string    `json:"status"`
+	CreatedBy    string    `json:"createdBy"`
+	CreatedAt    time.Time `json:"createdAt"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+func ToPartnerResponse(p *models.Partner) *PartnerResponse {
+	return &PartnerResponse{
+		ID:           p.ID,
+		Name:         p.Name,
+		ContactEmail: p.ContactEmail,
+		Status:       string(p.Status),
+		CreatedBy:    p.CreatedBy,
+		CreatedAt:    p.CreatedAt,
+		UpdatedAt:    p.UpdatedAt,
+	}
+}
+
+type APIKeyResponse struct {
+	ID                 string     `json:"id"`
+	PartnerID          string     `json:"partnerId"`
+	Name               string     `json:"name"`
+	KeyID              string     `json:"keyId"`
+	Scopes             []string   `json:"scopes"`
+	RequireSignature   bool       `json:"requireSignature"`
+	RateLimitPerMinute int        `json:"rateLimitPerMinute"`
+	ExpiresAt          *time.Time `json:"expiresAt,omitempty"`
+	LastUsedAt         *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt          *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt          time.Time  `json:"createdAt"`
+}
+
+func ToAPIKeyResponse(k *models.PartnerAPIKey) *APIKeyResponse {
+	return &APIKeyResponse{
+		ID:                 k.ID,
+		PartnerID:          k.PartnerID,
+		Name:               k.Name,
+		KeyID:              k.KeyID,
+		Scopes:             k.Scopes,
+		RequireSignature:   k.RequireSignature,
+		RateLimitPerMinute: k.RateLimitPerMinute,
+		ExpiresAt:          k.ExpiresAt,
+		LastUsedAt:         k.LastUsedAt,
+		RevokedAt:          k.RevokedAt,
+		CreatedAt:          k.CreatedAt,
+	}
+}
+
+// CreatedAPIKeyResponse carries the full key. It is only shown once.
+type CreatedAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+type PartnerUserResponse struct {
+	PartnerID   string    `json:"partnerId"`
+	UserID      string    `json:"userId"`
+	ExternalRef *string   `json:"externalRef,omitempty"`
+	Name        string    `json:"name,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+func ToPartnerUserResponse(u *models.PartnerUser) *PartnerUserResponse {
+	resp := &PartnerUserResponse{
+		PartnerID:   u.PartnerID,
+		UserID:      u.UserID,
+		ExternalRef: u.ExternalRef,
+		CreatedAt:   u.CreatedAt,
+	}
+	if u.User != nil {
+		resp.Name = u.User.Name
+	}
+	return resp
+}
+
+type UsageDay struct {
+	Day         string `json:"day"`
+	APIKeyID    string `json:"apiKeyId"`
+	Route       string `json:"route"`
+	StatusClass string `json:"statusClass"`
+	Requests    int64  `json:"requests"`
+}
+
+type UsageResponse struct {
+	PartnerID     string     `json:"partnerId"`
+	From          string     `json:"from"`
+	To            string     `json:"to"`
+	TotalRequests int64      `json:"totalRequests"`
+	Days          []UsageDay `json:"days"`
+}
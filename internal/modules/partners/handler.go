@@ -0,0 +1,435 @@
+package partners
+
+import (
+	"github.com/gin-gonic/gin"
+	hsdto "github.com/umar5678/go-backend/internal/modules/homeservices/customer/dto"
+	"github.com/umar5678/go-backend/internal/modules/partners/dto"
+	ridesdto "github.com/umar5678/go-backend/internal/modules/rides/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+// partnerUserHeader names the user a partner books for: the partner's
+// external reference for them, or their user ID.
+const partnerUserHeader = "X-Partner-User"
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// CreatePartner godoc
+// @Summary Register an integration partner
+// @Tags admin-partners
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreatePartnerRequest true "Partner"
+// @Success 200 {object} response.Response{data=dto.PartnerResponse}
+// @Router /admin/partners [post]
+func (h *Handler) CreatePartner(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	var req dto.CreatePartnerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	partner, err := h.service.CreatePartner(c.Request.Context(), adminID.(string), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, partner, "Partner created successfully")
+}
+
+// ListPartners godoc
+// @Summary List integration partners
+// @Tags admin-partners
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "Filter by status (active, disabled)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Response{data=[]dto.PartnerResponse}
+// @Router /admin/partners [get]
+func (h *Handler) ListPartners(c *gin.Context) {
+	var req dto.ListPartnersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+	req.SetDefaults()
+
+	partners, total, err := h.service.ListPartners(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pagination := response.NewPaginationMeta(total, req.Page, req.Limit)
+	response.Paginated(c, partners, pagination, "Partners retrieved successfully")
+}
+
+// GetPartner godoc
+// @Summary Get an integration partner
+// @Tags admin-partners
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Partner ID"
+// @Success 200 {object} response.Response{data=dto.PartnerResponse}
+// @Router /admin/partners/{id} [get]
+func (h *Handler) GetPartner(c *gin.Context) {
+	partner, err := h.service.GetPartner(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, partner, "Partner retrieved successfully")
+}
+
+// UpdatePartnerStatus godoc
+// @Summary Enable or disable a partner
+// @Description A disabled partner's keys are rejected until it is enabled again
+// @Tags admin-partners
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Partner ID"
+// @Param request body dto.UpdatePartnerStatusRequest true "Status"
+// @Success 200 {object} response.Response{data=dto.PartnerResponse}
+// @Router /admin/partners/{id}/status [put]
+func (h *Handler) UpdatePartnerStatus(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	var req dto.UpdatePartnerStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	partner, err := h.service.UpdatePartnerStatus(c.Request.Context(), adminID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, partner, "Partner status updated successfully")
+}
+
+// CreateAPIKey godoc
+// @Summary Issue an API key to a partner
+// @Description The full key is only returned in this response. Keys with requireSignature must sign every request with HMAC-SHA256 instead of sending the key
+// @Tags admin-partners
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Partner ID"
+// @Param request body dto.CreateAPIKeyRequest true "API key"
+// @Success 200 {object} response.Response{data=dto.CreatedAPIKeyResponse}
+// @Router /admin/partners/{id}/keys [post]
+func (h *Handler) CreateAPIKey(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	var req dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	key, err := h.service.CreateAPIKey(c.Request.Context(), adminID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, key, "API key created successfully")
+}
+
+// ListAPIKeys godoc
+// @Summary List a partner's API keys
+// @Tags admin-partners
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Partner ID"
+// @Success 200 {object} response.Response{data=[]dto.APIKeyResponse}
+// @Router /admin/partners/{id}/keys [get]
+func (h *Handler) ListAPIKeys(c *gin.Context) {
+	keys, err := h.service.ListAPIKeys(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, keys, "API keys retrieved successfully")
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke a partner API key
+// @Tags admin-partners
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Partner ID"
+// @Param keyId path string true "API key ID"
+// @Success 200 {object} response.Response{data=dto.APIKeyResponse}
+// @Router /admin/partners/{id}/keys/{keyId} [delete]
+func (h *Handler) RevokeAPIKey(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	key, err := h.service.RevokeAPIKey(c.Request.Context(), adminID.(string), c.Param("id"), c.Param("keyId"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, key, "API key revoked successfully")
+}
+
+// LinkUser godoc
+// @Summary Allow a partner to book for a user
+// @Tags admin-partners
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Partner ID"
+// @Param request body dto.LinkUserRequest true "User"
+// @Success 200 {object} response.Response{data=dto.PartnerUserResponse}
+// @Router /admin/partners/{id}/users [post]
+func (h *Handler) LinkUser(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	var req dto.LinkUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	user, err := h.service.LinkUser(c.Request.Context(), adminID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, user, "User linked successfully")
+}
+
+// ListUsers godoc
+// @Summary List users a partner can book for
+// @Tags admin-partners
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Partner ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Response{data=[]dto.PartnerUserResponse}
+// @Router /admin/partners/{id}/users [get]
+func (h *Handler) ListUsers(c *gin.Context) {
+	var req dto.ListUsersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+	req.SetDefaults()
+
+	users, total, err := h.service.ListUsers(c.Request.Context(), c.Param("id"), req.Page, req.Limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pagination := response.NewPaginationMeta(total, req.Page, req.Limit)
+	response.Paginated(c, users, pagination, "Partner users retrieved successfully")
+}
+
+// UnlinkUser godoc
+// @Summary Stop a partner booking for a user
+// @Tags admin-partners
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Partner ID"
+// @Param userId path string true "User ID"
+// @Success 200 {object} response.Response
+// @Router /admin/partners/{id}/users/{userId} [delete]
+func (h *Handler) UnlinkUser(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	if err := h.service.UnlinkUser(c.Request.Context(), adminID.(string), c.Param("id"), c.Param("userId")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, nil, "User unlinked successfully")
+}
+
+// GetUsage godoc
+// @Summary Get a partner's API usage
+// @Description Request counts per day, key, route and status class. Defaults to the last 30 days
+// @Tags admin-partners
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Partner ID"
+// @Param from query string false "First day (YYYY-MM-DD)"
+// @Param to query string false "Last day (YYYY-MM-DD)"
+// @Success 200 {object} response.Response{data=dto.UsageResponse}
+// @Router /admin/partners/{id}/usage [get]
+func (h *Handler) GetUsage(c *gin.Context) {
+	var req dto.UsageRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+
+	usage, err := h.service.GetUsage(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, usage, "Usage retrieved successfully")
+}
+
+// CreateRide godoc
+// @Summary Request a ride for a linked user
+// @Tags partner
+// @Security PartnerAPIKey
+// @Accept json
+// @Produce json
+// @Param X-Partner-User header string true "External reference or user ID of a linked user"
+// @Param request body ridesdto.CreateRideRequest true "Ride request data"
+// @Success 200 {object} response.Response{data=ridesdto.RideResponse}
+// @Router /partner/rides [post]
+func (h *Handler) CreateRide(c *gin.Context) {
+	var req ridesdto.CreateRideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	ride, err := h.service.CreateRide(c.Request.Context(), CallerFromContext(c), c.GetHeader(partnerUserHeader), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, ride, "Ride requested successfully")
+}
+
+// GetRide godoc
+// @Summary Get a ride the partner requested
+// @Tags partner
+// @Security PartnerAPIKey
+// @Produce json
+// @Param id path string true "Ride ID"
+// @Success 200 {object} response.Response{data=ridesdto.RideResponse}
+// @Router /partner/rides/{id} [get]
+func (h *Handler) GetRide(c *gin.Context) {
+	ride, err := h.service.GetRide(c.Request.Context(), CallerFromContext(c), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, ride, "Ride retrieved successfully")
+}
+
+// CancelRide godoc
+// @Summary Cancel a ride the partner requested
+// @Tags partner
+// @Security PartnerAPIKey
+// @Accept json
+// @Produce json
+// @Param id path string true "Ride ID"
+// @Param request body ridesdto.CancelRideRequest false "Reason"
+// @Success 200 {object} response.Response
+// @Router /partner/rides/{id}/cancel [post]
+func (h *Handler) CancelRide(c *gin.Context) {
+	var req ridesdto.CancelRideRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+			return
+		}
+	}
+
+	if err := h.service.CancelRide(c.Request.Context(), CallerFromContext(c), c.Param("id"), req); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, nil, "Ride cancelled successfully")
+}
+
+// CreateOrder godoc
+// @Summary Place a home service order for a linked user
+// @Tags partner
+// @Security PartnerAPIKey
+// @Accept json
+// @Produce json
+// @Param X-Partner-User header string true "External reference or user ID of a linked user"
+// @Param request body hsdto.CreateOrderRequest true "Order details"
+// @Success 200 {object} response.Response{data=hsdto.OrderCreatedResponse}
+// @Router /partner/orders [post]
+func (h *Handler) CreateOrder(c *gin.Context) {
+	var req hsdto.CreateOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	order, err := h.service.CreateOrder(c.Request.Context(), CallerFromContext(c), c.GetHeader(partnerUserHeader), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, order, "Order created successfully")
+}
+
+// GetOrder godoc
+// @Summary Get an order the partner placed
+// @Tags partner
+// @Security PartnerAPIKey
+// @Produce json
+// @Param id path string true "Order ID"
+// @Success 200 {object} response.Response{data=hsdto.OrderResponse}
+// @Router /partner/orders/{id} [get]
+func (h *Handler) GetOrder(c *gin.Context) {
+	order, err := h.service.GetOrder(c.Request.Context(), CallerFromContext(c), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, order, "Order retrieved successfully")
+}
+
+// CancelOrder godoc
+// @Summary Cancel an order the partner placed
+// @Tags partner
+// @Security PartnerAPIKey
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param request body hsdto.CancelOrderRequest true "Reason"
+// @Success 200 {object} response.Response{data=hsdto.OrderResponse}
+// @Router /partner/orders/{id}/cancel [post]
+func (h *Handler) CancelOrder(c *gin.Context) {
+	var req hsdto.CancelOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	order, err := h.service.CancelOrder(c.Request.Context(), CallerFromContext(c), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, order, "Order cancelled successfully")
+}
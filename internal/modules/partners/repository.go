@@ -0,0 +1,237 @@
+package partners
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository interface {
+	CreatePartner(ctx context.Context, p *models.Partner) error
+	FindPartner(ctx context.Context, id string) (*models.Partner, error)
+	UpdatePartner(ctx context.Context, p *models.Partner) error
+	ListPartners(ctx context.Context, status string, page, limit int) ([]*models.Partner, int64, error)
+
+	CreateKey(ctx context.Context, k *models.PartnerAPIKey) error
+	FindKey(ctx context.Context, partnerID, id string) (*models.PartnerAPIKey, error)
+	FindKeyByKeyID(ctx context.Context, keyID string) (*models.PartnerAPIKey, error)
+	UpdateKey(ctx context.Context, k *models.PartnerAPIKey) error
+	ListKeys(ctx context.Context, partnerID string) ([]*models.PartnerAPIKey, error)
+	TouchKey(ctx context.Context, id string, at time.Time) error
+
+	LinkUser(ctx context.Context, u *models.PartnerUser) error
+	UnlinkUser(ctx context.Context, partnerID, userID string) (bool, error)
+	ListUsers(ctx context.Context, partnerID string, page, limit int) ([]*models.PartnerUser, int64, error)
+	// FindLinkedUser resolves ref as the partner's external reference for a
+	// user, or failing that as a user ID.
+	FindLinkedUser(ctx context.Context, partnerID, ref string) (*models.PartnerUser, error)
+	UserExists(ctx context.Context, userID string) (bool, error)
+
+	CreateBooking(ctx context.Context, b *models.PartnerBooking) error
+	FindBooking(ctx context.Context, partnerID, subjectType, subjectID string) (*models.PartnerBooking, error)
+
+	AddUsage(ctx context.Context, rows []*models.PartnerAPIUsage) error
+	ListUsage(ctx context.Context, partnerID string, from, to time.Time) ([]*models.PartnerAPIUsage, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) CreatePartner(ctx context.Context, p *models.Partner) error {
+	return r.db.WithContext(ctx).Create(p).Error
+}
+
+func (r *repository) FindPartner(ctx context.Context, id string) (*models.Partner, error) {
+	var p models.Partner
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&p).Error
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *repository) UpdatePartner(ctx context.Context, p *models.Partner) error {
+	return r.db.WithContext(ctx).Save(p).Error
+}
+
+func (r *repository) ListPartners(ctx context.Context, status string, page, limit int) ([]*models.Partner, int64, error) {
+	var partners []*models.Partner
+	var total int64
+
+	base := r.db.WithContext(ctx).Model(&models.Partner{})
+	if status != "" {
+		base = base.Where("status = ?", status)
+	}
+
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count partners: %w", err)
+	}
+
+	if total == 0 {
+		return []*models.Partner{}, 0, nil
+	}
+
+	offset := (page - 1) * limit
+	if err := base.
+		Order("name ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&partners).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch partners: %w", err)
+	}
+
+	return partners, total, nil
+}
+
+func (r *repository) CreateKey(ctx context.Context, k *models.PartnerAPIKey) error {
+	return r.db.WithContext(ctx).Create(k).Error
+}
+
+func (r *repository) FindKey(ctx context.Context, partnerID, id string) (*models.PartnerAPIKey, error) {
+	var k models.PartnerAPIKey
+	err := r.db.WithContext(ctx).Where("id = ? AND partner_id = ?", id, partnerID).First(&k).Error
+	if err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+func (r *repository) FindKeyByKeyID(ctx context.Context, keyID string) (*models.PartnerAPIKey, error) {
+	var k models.PartnerAPIKey
+	err := r.db.WithContext(ctx).Where("key_id = ?", keyID).First(&k).Error
+	if err != nil {
+		return nil, err
+	}
+	return &k, nil
+}
+
+func (r *repository) UpdateKey(ctx context.Context, k *models.PartnerAPIKey) error {
+	return r.db.WithContext(ctx).Save(k).Error
+}
+
+func (r *repository) ListKeys(ctx context.Context, partnerID string) ([]*models.PartnerAPIKey, error) {
+	var keys []*models.PartnerAPIKey
+	err := r.db.WithContext(ctx).
+		Where("partner_id = ?", partnerID).
+		Order("created_at DESC").
+		Find(&keys).Error
+	return keys, err
+}
+
+func (r *repository) TouchKey(ctx context.Context, id string, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.PartnerAPIKey{}).
+		Where("id = ?", id).
+		UpdateColumn("last_used_at", at).Error
+}
+
+func (r *repository) LinkUser(ctx context.Context, u *models.PartnerUser) error {
+	return r.db.WithContext(ctx).Create(u).Error
+}
+
+func (r *repository) UnlinkUser(ctx context.Context, partnerID, userID string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("partner_id = ? AND user_id = ?", partnerID, userID).
+		Delete(&models.PartnerUser{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *repository) ListUsers(ctx context.Context, partnerID string, page, limit int) ([]*models.PartnerUser, int64, error) {
+	var users []*models.PartnerUser
+	var total int64
+
+	base := r.db.WithContext(ctx).Model(&models.PartnerUser{}).Where("partner_id = ?", partnerID)
+
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count partner users: %w", err)
+	}
+
+	if total == 0 {
+		return []*models.PartnerUser{}, 0, nil
+	}
+
+	offset := (page - 1) * limit
+	if err := base.
+		Preload("User").
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&users).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch partner users: %w", err)
+	}
+
+	return users, total, nil
+}
+
+func (r *repository) FindLinkedUser(ctx context.Context, partnerID, ref string) (*models.PartnerUser, error) {
+	var u models.PartnerUser
+	err := r.db.WithContext(ctx).
+		Where("partner_id = ? AND external_ref = ?", partnerID, ref).
+		First(&u).Error
+	if err == nil {
+		return &u, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	err = r.db.WithContext(ctx).
+		Where("partner_id = ? AND user_id::text = ?", partnerID, ref).
+		First(&u).Error
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+func (r *repository) UserExists(ctx context.Context, userID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", userID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *repository) CreateBooking(ctx context.Context, b *models.PartnerBooking) error {
+	return r.db.WithContext(ctx).Create(b).Error
+}
+
+func (r *repository) FindBooking(ctx context.Context, partnerID, subjectType, subjectID string) (*models.PartnerBooking, error) {
+	var b models.PartnerBooking
+	err := r.db.WithContext(ctx).
+		Where("partner_id = ? AND subject_type = ? AND subject_id = ?", partnerID, subjectType, subjectID).
+		First(&b).Error
+	if err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (r *repository) AddUsage(ctx context.Context, rows []*models.PartnerAPIUsage) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "api_key_id"}, {Name: "day"}, {Name: "route"}, {Name: "status_class"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"requests": gorm.Expr("partner_api_usage.requests + excluded.requests"),
+		}),
+	}).Create(&rows).Error
+}
+
+func (r *repository) ListUsage(ctx context.Context, partnerID string, from, to time.Time) ([]*models.PartnerAPIUsage, error) {
+	var rows []*models.PartnerAPIUsage
+	err := r.db.WithContext(ctx).
+		Where("partner_id = ? AND day BETWEEN ? AND ?", partnerID, from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Order("day ASC, route ASC").
+		Find(&rows).Error
+	return rows, err
+}
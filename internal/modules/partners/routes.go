@@ -0,0 +1,43 @@
+package partners
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/middleware"
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, service Service, recorder *UsageRecorder, authMiddleware gin.HandlerFunc) {
+	admin := router.Group("/admin/partners")
+	admin.Use(authMiddleware)
+	admin.Use(middleware.RequireAdmin())
+	{
+		admin.POST("", handler.CreatePartner)
+		admin.GET("", handler.ListPartners)
+		admin.GET("/:id", handler.GetPartner)
+		admin.PUT("/:id/status", handler.UpdatePartnerStatus)
+
+		admin.POST("/:id/keys", handler.CreateAPIKey)
+		admin.GET("/:id/keys", handler.ListAPIKeys)
+		admin.DELETE("/:id/keys/:keyId", handler.RevokeAPIKey)
+
+		admin.POST("/:id/users", handler.LinkUser)
+		admin.GET("/:id/users", handler.ListUsers)
+		admin.DELETE("/:id/users/:userId", handler.UnlinkUser)
+
+		admin.GET("/:id/usage", handler.GetUsage)
+	}
+
+	partner := router.Group("/partner")
+	partner.Use(Authenticate(service))
+	partner.Use(RecordUsage(recorder))
+	partner.Use(middleware.RateLimitWithPolicy("partner_key", KeyRateLimit))
+	{
+		partner.POST("/rides", RequireScope(models.PartnerScopeRidesWrite), handler.CreateRide)
+		partner.GET("/rides/:id", RequireScope(models.PartnerScopeRidesRead), handler.GetRide)
+		partner.POST("/rides/:id/cancel", RequireScope(models.PartnerScopeRidesWrite), handler.CancelRide)
+
+		partner.POST("/orders", RequireScope(models.PartnerScopeOrdersWrite), handler.CreateOrder)
+		partner.GET("/orders/:id", RequireScope(models.PartnerScopeOrdersRead), handler.GetOrder)
+		partner.POST("/orders/:id/cancel", RequireScope(models.PartnerScopeOrdersWrite), handler.CancelOrder)
+	}
+}
@@ -0,0 +1,582 @@
+package partners
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/audit"
+	hsdto "github.com/umar5678/go-backend/internal/modules/homeservices/customer/dto"
+	"github.com/umar5678/go-backend/internal/modules/partners/dto"
+	ridesdto "github.com/umar5678/go-backend/internal/modules/rides/dto"
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+const (
+	subjectRide  = "ride"
+	subjectOrder = "service_order"
+	// last_used_at is only written when it is older than this, to spare a
+	// write on every request.
+	touchInterval = time.Minute
+	maxUsageDays  = 92
+)
+
+// RideBooker creates and manages rides for a rider. It is satisfied by
+// rides.Service.
+type RideBooker interface {
+	CreateRide(ctx context.Context, riderID string, req ridesdto.CreateRideRequest) (*ridesdto.RideResponse, error)
+	GetRide(ctx context.Context, userID, rideID string) (*ridesdto.RideResponse, error)
+	CancelRide(ctx context.Context, userID, rideID string, req ridesdto.CancelRideRequest) error
+}
+
+// OrderBooker creates and manages home service orders for a customer. It is
+// satisfied by the home services customer service.
+type OrderBooker interface {
+	CreateOrder(ctx context.Context, customerID string, req hsdto.CreateOrderRequest) (*hsdto.OrderCreatedResponse, error)
+	GetOrder(ctx context.Context, customerID, orderID string) (*hsdto.OrderResponse, error)
+	CancelOrder(ctx context.Context, customerID, orderID string, req hsdto.CancelOrderRequest) (*hsdto.OrderResponse, error)
+}
+
+type Service interface {
+	CreatePartner(ctx context.Context, adminID string, req dto.CreatePartnerRequest) (*dto.PartnerResponse, error)
+	GetPartner(ctx context.Context, partnerID string) (*dto.PartnerResponse, error)
+	ListPartners(ctx context.Context, req dto.ListPartnersRequest) ([]*dto.PartnerResponse, int64, error)
+	UpdatePartnerStatus(ctx context.Context, adminID, partnerID string, req dto.UpdatePartnerStatusRequest) (*dto.PartnerResponse, error)
+
+	CreateAPIKey(ctx context.Context, adminID, partnerID string, req dto.CreateAPIKeyRequest) (*dto.CreatedAPIKeyResponse, error)
+	ListAPIKeys(ctx context.Context, partnerID string) ([]*dto.APIKeyResponse, error)
+	RevokeAPIKey(ctx context.Context, adminID, partnerID, keyID string) (*dto.APIKeyResponse, error)
+
+	LinkUser(ctx context.Context, adminID, partnerID string, req dto.LinkUserRequest) (*dto.PartnerUserResponse, error)
+	UnlinkUser(ctx context.Context, adminID, partnerID, userID string) error
+	ListUsers(ctx context.Context, partnerID string, page, limit int) ([]*dto.PartnerUserResponse, int64, error)
+	GetUsage(ctx context.Context, partnerID string, req dto.UsageRequest) (*dto.UsageResponse, error)
+
+	AuthenticateKey(ctx context.Context, key string) (*Caller, error)
+	AuthenticateSignature(ctx context.Context, req SignedRequest) (*Caller, error)
+
+	// The booking methods act for the user the partner names in userRef,
+	// which must be linked to the partner.
+	CreateRide(ctx context.Context, caller *Caller, userRef string, req ridesdto.CreateRideRequest) (*ridesdto.RideResponse, error)
+	GetRide(ctx context.Context, caller *Caller, rideID string) (*ridesdto.RideResponse, error)
+	CancelRide(ctx context.Context, caller *Caller, rideID string, req ridesdto.CancelRideRequest) error
+	CreateOrder(ctx context.Context, caller *Caller, userRef string, req hsdto.CreateOrderRequest) (*hsdto.OrderCreatedResponse, error)
+	GetOrder(ctx context.Context, caller *Caller, orderID string) (*hsdto.OrderResponse, error)
+	CancelOrder(ctx context.Context, caller *Caller, orderID string, req hsdto.CancelOrderRequest) (*hsdto.OrderResponse, error)
+
+	SetRideBooker(booker RideBooker)
+	SetOrderBooker(booker OrderBooker)
+	SetAuditLogger(auditLogger audit.Recorder)
+}
+
+type service struct {
+	repo        Repository
+	cfg         config.PartnersConfig
+	secrets     *secretBox
+	rides       RideBooker
+	orders      OrderBooker
+	auditLogger audit.Recorder
+}
+
+// NewService fails when the configured signing key is malformed. Without a
+// signing key the service works, but keys that must sign cannot be issued.
+func NewService(repo Repository, cfg config.PartnersConfig) (Service, error) {
+	secrets, err := newSecretBox(cfg.SigningKey)
+	if err != nil {
+		return nil, err
+	}
+	return &service{repo: repo, cfg: cfg, secrets: secrets}, nil
+}
+
+func (s *service) SetRideBooker(booker RideBooker) {
+	s.rides = booker
+}
+
+func (s *service) SetOrderBooker(booker OrderBooker) {
+	s.orders = booker
+}
+
+func (s *service) CreatePartner(ctx context.Context, adminID string, req dto.CreatePartnerRequest) (*dto.PartnerResponse, error) {
+	partner := &models.Partner{
+		Name:         strings.TrimSpace(req.Name),
+		ContactEmail: req.ContactEmail,
+		Status:       models.PartnerStatusActive,
+		CreatedBy:    adminID,
+	}
+	if err := s.repo.CreatePartner(ctx, partner); err != nil {
+		logger.Error("failed to create partner", "error", err)
+		return nil, response.InternalServerError("Failed to create partner", err)
+	}
+
+	s.recordAudit(ctx, adminID, "partner.create", "partner", partner.ID, nil, partnerAuditSnapshot(partner), "")
+	return dto.ToPartnerResponse(partner), nil
+}
+
+func (s *service) GetPartner(ctx context.Context, partnerID string) (*dto.PartnerResponse, error) {
+	partner, err := s.findPartner(ctx, partnerID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToPartnerResponse(partner), nil
+}
+
+func (s *service) ListPartners(ctx context.Context, req dto.ListPartnersRequest) ([]*dto.PartnerResponse, int64, error) {
+	partners, total, err := s.repo.ListPartners(ctx, req.Status, req.Page, req.Limit)
+	if err != nil {
+		return nil, 0, response.InternalServerError("Failed to fetch partners", err)
+	}
+
+	result := make([]*dto.PartnerResponse, 0, len(partners))
+	for _, p := range partners {
+		result = append(result, dto.ToPartnerResponse(p))
+	}
+	return result, total, nil
+}
+
+func (s *service) UpdatePartnerStatus(ctx context.Context, adminID, partnerID string, req dto.UpdatePartnerStatusRequest) (*dto.PartnerResponse, error) {
+	partner, err := s.findPartner(ctx, partnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := partnerAuditSnapshot(partner)
+	partner.Status = models.PartnerStatus(req.Status)
+	if err := s.repo.UpdatePartner(ctx, partner); err != nil {
+		return nil, response.InternalServerError("Failed to update partner", err)
+	}
+
+	s.recordAudit(ctx, adminID, "partner.status", "partner", partner.ID, before, partnerAuditSnapshot(partner), req.Reason)
+	return dto.ToPartnerResponse(partner), nil
+}
+
+func (s *service) CreateAPIKey(ctx context.Context, adminID, partnerID string, req dto.CreateAPIKeyRequest) (*dto.CreatedAPIKeyResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+	if req.RequireSignature && s.secrets == nil {
+		return nil, response.BadRequest("Request signing is not configured")
+	}
+	if _, err := s.findPartner(ctx, partnerID); err != nil {
+		return nil, err
+	}
+
+	keyID, secret, err := generateKey()
+	if err != nil {
+		return nil, response.InternalServerError("Failed to generate API key", err)
+	}
+
+	key := &models.PartnerAPIKey{
+		PartnerID:          partnerID,
+		Name:               req.Name,
+		KeyID:              keyID,
+		SecretHash:         hashSecret(secret),
+		Scopes:             dedupeScopes(req.Scopes),
+		RequireSignature:   req.RequireSignature,
+		RateLimitPerMinute: req.RateLimitPerMinute,
+		ExpiresAt:          req.ExpiresAt,
+		CreatedBy:          adminID,
+	}
+	if key.RateLimitPerMinute == 0 {
+		key.RateLimitPerMinute = s.cfg.DefaultRateLimitPerMinute
+	}
+	if req.RequireSignature {
+		sealed, err := s.secrets.seal(secret)
+		if err != nil {
+			return nil, response.InternalServerError("Failed to generate API key", err)
+		}
+		key.EncryptedSecret = &sealed
+	}
+
+	if err := s.repo.CreateKey(ctx, key); err != nil {
+		logger.Error("failed to create partner API key", "error", err, "partnerID", partnerID)
+		return nil, response.InternalServerError("Failed to create API key", err)
+	}
+
+	s.recordAudit(ctx, adminID, "partner_key.create", "partner_api_key", key.ID, nil, keyAuditSnapshot(key), "")
+	return &dto.CreatedAPIKeyResponse{
+		APIKeyResponse: *dto.ToAPIKeyResponse(key),
+		Key:            keyID + "." + secret,
+	}, nil
+}
+
+func (s *service) ListAPIKeys(ctx context.Context, partnerID string) ([]*dto.APIKeyResponse, error) {
+	if _, err := s.findPartner(ctx, partnerID); err != nil {
+		return nil, err
+	}
+	keys, err := s.repo.ListKeys(ctx, partnerID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to fetch API keys", err)
+	}
+
+	result := make([]*dto.APIKeyResponse, 0, len(keys))
+	for _, k := range keys {
+		result = append(result, dto.ToAPIKeyResponse(k))
+	}
+	return result, nil
+}
+
+func (s *service) RevokeAPIKey(ctx context.Context, adminID, partnerID, keyID string) (*dto.APIKeyResponse, error) {
+	key, err := s.repo.FindKey(ctx, partnerID, keyID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("API key")
+		}
+		return nil, response.InternalServerError("Failed to fetch API key", err)
+	}
+	if key.RevokedAt != nil {
+		return nil, response.ConflictError("API key is already revoked")
+	}
+
+	before := keyAuditSnapshot(key)
+	now := time.Now()
+	key.RevokedAt = &now
+	key.RevokedBy = &adminID
+	if err := s.repo.UpdateKey(ctx, key); err != nil {
+		return nil, response.InternalServerError("Failed to revoke API key", err)
+	}
+
+	s.recordAudit(ctx, adminID, "partner_key.revoke", "partner_api_key", key.ID, before, keyAuditSnapshot(key), "")
+	return dto.ToAPIKeyResponse(key), nil
+}
+
+func (s *service) LinkUser(ctx context.Context, adminID, partnerID string, req dto.LinkUserRequest) (*dto.PartnerUserResponse, error) {
+	if _, err := s.findPartner(ctx, partnerID); err != nil {
+		return nil, err
+	}
+	exists, err := s.repo.UserExists(ctx, req.UserID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to fetch user", err)
+	}
+	if !exists {
+		return nil, response.NotFoundError("User")
+	}
+
+	link := &models.PartnerUser{
+		PartnerID: partnerID,
+		UserID:    req.UserID,
+		CreatedBy: adminID,
+	}
+	if ref := strings.TrimSpace(req.ExternalRef); ref != "" {
+		link.ExternalRef = &ref
+	}
+	if err := s.repo.LinkUser(ctx, link); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) || strings.Contains(err.Error(), "duplicate key") {
+			return nil, response.ConflictError("User is already linked to this partner, or the external reference is taken")
+		}
+		return nil, response.InternalServerError("Failed to link user", err)
+	}
+
+	s.recordAudit(ctx, adminID, "partner_user.link", "partner", partnerID, nil,
+		map[string]interface{}{"userId": link.UserID, "externalRef": link.ExternalRef}, "")
+	return dto.ToPartnerUserResponse(link), nil
+}
+
+func (s *service) UnlinkUser(ctx context.Context, adminID, partnerID, userID string) error {
+	removed, err := s.repo.UnlinkUser(ctx, partnerID, userID)
+	if err != nil {
+		return response.InternalServerError("Failed to unlink user", err)
+	}
+	if !removed {
+		return response.NotFoundError("Partner user")
+	}
+
+	s.recordAudit(ctx, adminID, "partner_user.unlink", "partner", partnerID,
+		map[string]interface{}{"userId": userID}, nil, "")
+	return nil
+}
+
+func (s *service) ListUsers(ctx context.Context, partnerID string, page, limit int) ([]*dto.PartnerUserResponse, int64, error) {
+	users, total, err := s.repo.ListUsers(ctx, partnerID, page, limit)
+	if err != nil {
+		return nil, 0, response.InternalServerError("Failed to fetch partner users", err)
+	}
+
+	result := make([]*dto.PartnerUserResponse, 0, len(users))
+	for _, u := range users {
+		result = append(result, dto.ToPartnerUserResponse(u))
+	}
+	return result, total, nil
+}
+
+func (s *service) GetUsage(ctx context.Context, partnerID string, req dto.UsageRequest) (*dto.UsageResponse, error) {
+	if _, err := s.findPartner(ctx, partnerID); err != nil {
+		return nil, err
+	}
+
+	to := time.Now().UTC()
+	if req.To != "" {
+		to, _ = time.Parse("2006-01-02", req.To)
+	}
+	from := to.AddDate(0, 0, -29)
+	if req.From != "" {
+		from, _ = time.Parse("2006-01-02", req.From)
+	}
+	if from.After(to) {
+		return nil, response.BadRequest("from must not be after to")
+	}
+	if to.Sub(from) > maxUsageDays*24*time.Hour {
+		return nil, response.BadRequest(fmt.Sprintf("Usage can be fetched for at most %d days at a time", maxUsageDays))
+	}
+
+	rows, err := s.repo.ListUsage(ctx, partnerID, from, to)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to fetch usage", err)
+	}
+
+	resp := &dto.UsageResponse{
+		PartnerID: partnerID,
+		From:      from.Format("2006-01-02"),
+		To:        to.Format("2006-01-02"),
+		Days:      make([]dto.UsageDay, 0, len(rows)),
+	}
+	for _, row := range rows {
+		resp.TotalRequests += row.Requests
+		resp.Days = append(resp.Days, dto.UsageDay{
+			Day:         row.Day.Format("2006-01-02"),
+			APIKeyID:    row.APIKeyID,
+			Route:       row.Route,
+			StatusClass: row.StatusClass,
+			Requests:    row.Requests,
+		})
+	}
+	return resp, nil
+}
+
+func (s *service) AuthenticateKey(ctx context.Context, key string) (*Caller, error) {
+	keyID, secret, ok := splitKey(key)
+	if !ok {
+		return nil, response.UnauthorizedError("Invalid API key")
+	}
+
+	caller, err := s.loadCaller(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	if !secretMatches(secret, caller.Key.SecretHash) {
+		return nil, response.UnauthorizedError("Invalid API key")
+	}
+	if caller.Key.RequireSignature {
+		return nil, response.UnauthorizedError("This API key must sign its requests")
+	}
+	return caller, nil
+}
+
+func (s *service) AuthenticateSignature(ctx context.Context, req SignedRequest) (*Caller, error) {
+	if req.KeyID == "" || req.Timestamp == "" {
+		return nil, response.UnauthorizedError("Signed requests need X-API-Key-Id and X-Timestamp")
+	}
+	unix, err := strconv.ParseInt(req.Timestamp, 10, 64)
+	if err != nil {
+		return nil, response.UnauthorizedError("Invalid X-Timestamp")
+	}
+	skew := time.Since(time.Unix(unix, 0))
+	if skew > s.cfg.MaxClockSkew || skew < -s.cfg.MaxClockSkew {
+		return nil, response.UnauthorizedError("Request timestamp is outside the allowed window")
+	}
+
+	caller, err := s.loadCaller(ctx, req.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	if caller.Key.EncryptedSecret == nil || s.secrets == nil {
+		return nil, response.UnauthorizedError("This API key cannot sign requests")
+	}
+	secret, err := s.secrets.open(*caller.Key.EncryptedSecret)
+	if err != nil {
+		logger.Error("failed to decrypt partner key secret", "error", err, "keyID", caller.Key.ID)
+		return nil, response.UnauthorizedError("Invalid signature")
+	}
+
+	payload := signaturePayload(req.Timestamp, req.Method, req.RequestURI, req.Body)
+	if !signatureMatches(secret, payload, req.Signature) {
+		return nil, response.UnauthorizedError("Invalid signature")
+	}
+
+	// A signature is only accepted once within the clock skew window.
+	if cache.MainClient != nil {
+		fresh, err := cache.MainClient.SetNX(ctx, "partner:signature:"+req.Signature, caller.Key.ID, 2*s.cfg.MaxClockSkew).Result()
+		if err == nil && !fresh {
+			return nil, response.UnauthorizedError("Request has already been used")
+		}
+	}
+	return caller, nil
+}
+
+func (s *service) loadCaller(ctx context.Context, keyID string) (*Caller, error) {
+	key, err := s.repo.FindKeyByKeyID(ctx, keyID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.UnauthorizedError("Invalid API key")
+		}
+		return nil, response.InternalServerError("Failed to check API key", err)
+	}
+	now := time.Now()
+	if !key.IsUsable(now) {
+		return nil, response.UnauthorizedError("API key has expired or been revoked")
+	}
+
+	partner, err := s.repo.FindPartner(ctx, key.PartnerID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to check API key", err)
+	}
+	if partner.Status != models.PartnerStatusActive {
+		return nil, response.ForbiddenError("Partner account is disabled")
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) > touchInterval {
+		if err := s.repo.TouchKey(ctx, key.ID, now); err != nil {
+			logger.Warn("failed to record API key use", "error", err, "keyID", key.ID)
+		}
+	}
+	return &Caller{Partner: partner, Key: key}, nil
+}
+
+func (s *service) CreateRide(ctx context.Context, caller *Caller, userRef string, req ridesdto.CreateRideRequest) (*ridesdto.RideResponse, error) {
+	if s.rides == nil {
+		return nil, response.ServiceUnavailable("Ride booking is not available")
+	}
+	link, err := s.resolveUser(ctx, caller, userRef)
+	if err != nil {
+		return nil, err
+	}
+
+	ride, err := s.rides.CreateRide(ctx, link.UserID, req)
+	if err != nil {
+		return nil, err
+	}
+	s.recordBooking(ctx, caller, link.UserID, subjectRide, ride.ID)
+	return ride, nil
+}
+
+func (s *service) GetRide(ctx context.Context, caller *Caller, rideID string) (*ridesdto.RideResponse, error) {
+	if s.rides == nil {
+		return nil, response.ServiceUnavailable("Ride booking is not available")
+	}
+	booking, err := s.findBooking(ctx, caller, subjectRide, rideID, "Ride")
+	if err != nil {
+		return nil, err
+	}
+	return s.rides.GetRide(ctx, booking.UserID, rideID)
+}
+
+func (s *service) CancelRide(ctx context.Context, caller *Caller, rideID string, req ridesdto.CancelRideRequest) error {
+	if s.rides == nil {
+		return response.ServiceUnavailable("Ride booking is not available")
+	}
+	booking, err := s.findBooking(ctx, caller, subjectRide, rideID, "Ride")
+	if err != nil {
+		return err
+	}
+	return s.rides.CancelRide(ctx, booking.UserID, rideID, req)
+}
+
+func (s *service) CreateOrder(ctx context.Context, caller *Caller, userRef string, req hsdto.CreateOrderRequest) (*hsdto.OrderCreatedResponse, error) {
+	if s.orders == nil {
+		return nil, response.ServiceUnavailable("Order booking is not available")
+	}
+	link, err := s.resolveUser(ctx, caller, userRef)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := s.orders.CreateOrder(ctx, link.UserID, req)
+	if err != nil {
+		return nil, err
+	}
+	s.recordBooking(ctx, caller, link.UserID, subjectOrder, order.ID)
+	return order, nil
+}
+
+func (s *service) GetOrder(ctx context.Context, caller *Caller, orderID string) (*hsdto.OrderResponse, error) {
+	if s.orders == nil {
+		return nil, response.ServiceUnavailable("Order booking is not available")
+	}
+	booking, err := s.findBooking(ctx, caller, subjectOrder, orderID, "Order")
+	if err != nil {
+		return nil, err
+	}
+	return s.orders.GetOrder(ctx, booking.UserID, orderID)
+}
+
+func (s *service) CancelOrder(ctx context.Context, caller *Caller, orderID string, req hsdto.CancelOrderRequest) (*hsdto.OrderResponse, error) {
+	if s.orders == nil {
+		return nil, response.ServiceUnavailable("Order booking is not available")
+	}
+	booking, err := s.findBooking(ctx, caller, subjectOrder, orderID, "Order")
+	if err != nil {
+		return nil, err
+	}
+	return s.orders.CancelOrder(ctx, booking.UserID, orderID, req)
+}
+
+func (s *service) resolveUser(ctx context.Context, caller *Caller, userRef string) (*models.PartnerUser, error) {
+	userRef = strings.TrimSpace(userRef)
+	if userRef == "" {
+		return nil, response.BadRequest("X-Partner-User header is required")
+	}
+	link, err := s.repo.FindLinkedUser(ctx, caller.Partner.ID, userRef)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.ForbiddenError("User is not linked to this partner")
+		}
+		return nil, response.InternalServerError("Failed to resolve user", err)
+	}
+	return link, nil
+}
+
+func (s *service) findBooking(ctx context.Context, caller *Caller, subjectType, subjectID, resource string) (*models.PartnerBooking, error) {
+	booking, err := s.repo.FindBooking(ctx, caller.Partner.ID, subjectType, subjectID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError(resource)
+		}
+		return nil, response.InternalServerError("Failed to fetch booking", err)
+	}
+	return booking, nil
+}
+
+// recordBooking notes which partner created a ride or order. The booking
+// itself has already succeeded, so a failure here is only logged.
+func (s *service) recordBooking(ctx context.Context, caller *Caller, userID, subjectType, subjectID string) {
+	booking := &models.PartnerBooking{
+		PartnerID:   caller.Partner.ID,
+		APIKeyID:    caller.Key.ID,
+		UserID:      userID,
+		SubjectType: subjectType,
+		SubjectID:   subjectID,
+	}
+	if err := s.repo.CreateBooking(ctx, booking); err != nil {
+		logger.Error("failed to record partner booking", "error", err, "partnerID", caller.Partner.ID, "subjectType", subjectType, "subjectID", subjectID)
+	}
+}
+
+func (s *service) findPartner(ctx context.Context, partnerID string) (*models.Partner, error) {
+	partner, err := s.repo.FindPartner(ctx, partnerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Partner")
+		}
+		return nil, response.InternalServerError("Failed to fetch partner", err)
+	}
+	return partner, nil
+}
+
+func dedupeScopes(scopes []string) []string {
+	seen := make(map[string]bool, len(scopes))
+	result := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		if !seen[scope] {
+			seen[scope] = true
+			result = append(result, scope)
+		}
+	}
+	return result
+}
@@ -0,0 +1,148 @@
+package partners
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/services/health"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type usageKey struct {
+	apiKeyID    string
+	partnerID   string
+	day         string
+	route       string
+	statusClass string
+}
+
+// UsageRecorder counts partner requests in memory and adds them to the usage
+// table periodically, so requests never wait on a counter write. Counts from
+// every instance add up in the table.
+type UsageRecorder struct {
+	repo   Repository
+	mu     sync.Mutex
+	counts map[usageKey]int64
+}
+
+func NewUsageRecorder(repo Repository) *UsageRecorder {
+	return &UsageRecorder{repo: repo, counts: make(map[usageKey]int64)}
+}
+
+func (u *UsageRecorder) Record(key *models.PartnerAPIKey, route string, status int) {
+	if route == "" {
+		route = "unmatched"
+	}
+	k := usageKey{
+		apiKeyID:    key.ID,
+		partnerID:   key.PartnerID,
+		day:         time.Now().UTC().Format("2006-01-02"),
+		route:       route,
+		statusClass: strconv.Itoa(status/100) + "xx",
+	}
+
+	u.mu.Lock()
+	u.counts[k]++
+	u.mu.Unlock()
+}
+
+// Flush writes the counts gathered so far. Counts that fail to write are kept
+// for the next flush.
+func (u *UsageRecorder) Flush(ctx context.Context) error {
+	u.mu.Lock()
+	pending := u.counts
+	u.counts = make(map[usageKey]int64)
+	u.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	rows := make([]*models.PartnerAPIUsage, 0, len(pending))
+	for k, n := range pending {
+		day, _ := time.Parse("2006-01-02", k.day)
+		rows = append(rows, &models.PartnerAPIUsage{
+			APIKeyID:    k.apiKeyID,
+			PartnerID:   k.partnerID,
+			Day:         day,
+			Route:       k.route,
+			StatusClass: k.statusClass,
+			Requests:    n,
+		})
+	}
+
+	if err := u.repo.AddUsage(ctx, rows); err != nil {
+		u.mu.Lock()
+		for k, n := range pending {
+			u.counts[k] += n
+		}
+		u.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func (u *UsageRecorder) Start(ctx context.Context, interval time.Duration) {
+	heartbeat := health.NewHeartbeat("partner_usage_flush", interval)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				flushCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				if err := u.Flush(flushCtx); err != nil {
+					logger.Error("failed to flush partner usage", "error", err)
+				}
+				cancel()
+				return
+			case <-ticker.C:
+				flushCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+				if err := u.Flush(flushCtx); err != nil {
+					logger.Error("failed to flush partner usage", "error", err)
+				}
+				cancel()
+				heartbeat.Beat()
+			}
+		}
+	}()
+
+	logger.Info("partner usage recorder started", "interval", interval)
+}
+
+// RecordUsage counts every authenticated partner request, including those
+// rejected by rate limits and scope checks.
+func RecordUsage(recorder *UsageRecorder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		caller := CallerFromContext(c)
+		if caller == nil {
+			return
+		}
+		recorder.Record(caller.Key, c.FullPath(), responseStatus(c))
+	}
+}
+
+// responseStatus is the status the request will end with. Errors are only
+// rendered by the error handler further out, so it is derived from them when
+// nothing has been written yet.
+func responseStatus(c *gin.Context) int {
+	if c.Writer.Written() || len(c.Errors) == 0 {
+		return c.Writer.Status()
+	}
+	var appErr *response.AppError
+	if errors.As(c.Errors.Last().Err, &appErr) {
+		return appErr.StatusCode
+	}
+	return http.StatusInternalServerError
+}
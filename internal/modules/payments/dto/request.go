@@ -0,0 +1,41 @@
+package dto
+
+type CreateTopUpRequest struct {
+	Amount            float64 `json:"amount" binding:"required,gt=0" example:"500"`
+	PaymentMethodID   string  `json:"paymentMethodId" binding:"omitempty" example:"3f6c2a1e-8d4b-4c1a-9e2f-1b2c3d4e5f60"`
+	SavePaymentMethod bool    `json:"savePaymentMethod" example:"true"`
+	IdempotencyKey    string  `json:"idempotencyKey" binding:"omitempty,max=255" example:"topup-7d1f0c"`
+}
+
+type ListTopUpsQuery struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending succeeded failed canceled"`
+	UserID string `form:"userId" binding:"omitempty,uuid"`
+	Page   int    `form:"page" binding:"omitempty,min=1"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (q *ListTopUpsQuery) SetDefaults() {
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.Limit < 1 {
+		q.Limit = 20
+	}
+}
+
+type ListWebhookEventsQuery struct {
+	Status    string `form:"status" binding:"omitempty,oneof=received processed ignored failed"`
+	EventType string `form:"eventType"`
+	ObjectID  string `form:"objectId"`
+	Page      int    `form:"page" binding:"omitempty,min=1"`
+	Limit     int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (q *ListWebhookEventsQuery) SetDefaults() {
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.Limit < 1 {
+		q.Limit = 20
+	}
+}
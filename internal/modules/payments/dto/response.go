@@ -0,0 +1,61 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+type TopUpResponse struct {
+	ID              string     `json:"id"`
+	Amount          float64    `json:"amount"`
+	Currency        string     `json:"currency"`
+	Status          string     `json:"status"`
+	Gateway         string     `json:"gateway"`
+	PaymentIntentID string     `json:"paymentIntentId"`
+	ClientSecret    string     `json:"clientSecret,omitempty"`
+	PublishableKey  string     `json:"publishableKey,omitempty"`
+	PaymentMethodID string     `json:"paymentMethodId,omitempty"`
+	TransactionID   *string    `json:"transactionId,omitempty"`
+	FailureReason   string     `json:"failureReason,omitempty"`
+	CreditedAt      *time.Time `json:"creditedAt,omitempty"`
+	CreatedAt       time.Time  `json:"createdAt"`
+}
+
+func ToTopUpResponse(topUp *models.WalletTopUp) *TopUpResponse {
+	return &TopUpResponse{
+		ID:              topUp.ID,
+		Amount:          topUp.Amount,
+		Currency:        topUp.Currency,
+		Status:          string(topUp.Status),
+		Gateway:         topUp.Gateway,
+		PaymentIntentID: topUp.PaymentIntentID,
+		PaymentMethodID: topUp.PaymentMethodID,
+		TransactionID:   topUp.TransactionID,
+		FailureReason:   topUp.FailureReason,
+		CreditedAt:      topUp.CreditedAt,
+		CreatedAt:       topUp.CreatedAt,
+	}
+}
+
+type PaymentMethodResponse struct {
+	ID        string    `json:"id"`
+	Brand     string    `json:"brand"`
+	Last4     string    `json:"last4"`
+	ExpMonth  int       `json:"expMonth"`
+	ExpYear   int       `json:"expYear"`
+	IsDefault bool      `json:"isDefault"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func ToPaymentMethodResponse(method *models.SavedPaymentMethod) PaymentMethodResponse {
+	return PaymentMethodResponse{
+		ID:        method.ID,
+		Brand:     method.Brand,
+		Last4:     method.Last4,
+		ExpMonth:  method.ExpMonth,
+		ExpYear:   method.ExpYear,
+		IsDefault: method.IsDefault,
+		CreatedAt: method.CreatedAt,
+	}
+}
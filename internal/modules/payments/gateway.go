@@ -0,0 +1,66 @@
+package payments
+
+import (
+	"context"
+	"math"
+)
+
+// Gateway is the card processor behind wallet top-ups. Stripe is the only
+// implementation today; amounts are in the currency's minor unit.
+type Gateway interface {
+	Name() string
+	CreateCustomer(ctx context.Context, userID, email, name string) (string, error)
+	CreatePaymentIntent(ctx context.Context, req PaymentIntentRequest) (*PaymentIntent, error)
+	GetPaymentIntent(ctx context.Context, id string) (*PaymentIntent, error)
+	GetPaymentMethod(ctx context.Context, id string) (*PaymentMethod, error)
+	DetachPaymentMethod(ctx context.Context, id string) error
+	ParseWebhook(payload []byte, signature string) (*WebhookEvent, error)
+}
+
+type PaymentIntentRequest struct {
+	Amount          int64
+	Currency        string
+	CustomerID      string
+	PaymentMethodID string
+	SaveMethod      bool
+	IdempotencyKey  string
+	Description     string
+	Metadata        map[string]string
+}
+
+const (
+	IntentStatusSucceeded = "succeeded"
+	IntentStatusCanceled  = "canceled"
+	IntentStatusFailed    = "requires_payment_method"
+)
+
+type PaymentIntent struct {
+	ID              string
+	ClientSecret    string
+	Status          string
+	Amount          int64
+	Currency        string
+	CustomerID      string
+	PaymentMethodID string
+	FailureReason   string
+}
+
+type PaymentMethod struct {
+	ID         string
+	CustomerID string
+	Brand      string
+	Last4      string
+	ExpMonth   int
+	ExpYear    int
+}
+
+type WebhookEvent struct {
+	ID            string
+	Type          string
+	ObjectID      string
+	PaymentIntent *PaymentIntent
+}
+
+func toMinorUnits(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
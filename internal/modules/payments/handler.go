@@ -0,0 +1,232 @@
+package payments
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/modules/payments/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// CreateTopUp godoc
+// @Summary Start a wallet top-up
+// @Description Creates a payment intent for the amount. Confirm it on the client with the returned client secret; the wallet is credited once the gateway confirms the payment.
+// @Tags payments
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateTopUpRequest true "Top-up data"
+// @Success 200 {object} response.Response{data=dto.TopUpResponse}
+// @Router /payments/topups [post]
+func (h *Handler) CreateTopUp(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req dto.CreateTopUpRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+	if key := c.GetHeader("Idempotency-Key"); key != "" && req.IdempotencyKey == "" {
+		req.IdempotencyKey = key
+	}
+
+	topUp, err := h.service.CreateTopUp(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, topUp, "Top-up created")
+}
+
+// GetTopUp godoc
+// @Summary Get a wallet top-up
+// @Tags payments
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Top-up ID"
+// @Success 200 {object} response.Response{data=dto.TopUpResponse}
+// @Router /payments/topups/{id} [get]
+func (h *Handler) GetTopUp(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	topUp, err := h.service.GetTopUp(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, topUp, "Top-up retrieved")
+}
+
+// ListTopUps godoc
+// @Summary List my wallet top-ups
+// @Tags payments
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "Status"
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Success 200 {object} response.Response{data=[]dto.TopUpResponse}
+// @Router /payments/topups [get]
+func (h *Handler) ListTopUps(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var query dto.ListTopUpsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+	query.SetDefaults()
+
+	topUps, total, err := h.service.ListTopUps(c.Request.Context(), userID.(string), query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Paginated(c, topUps, response.NewPaginationMeta(total, query.Page, query.Limit), "Top-ups retrieved")
+}
+
+// ListPaymentMethods godoc
+// @Summary List saved payment methods
+// @Tags payments
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=[]dto.PaymentMethodResponse}
+// @Router /payments/methods [get]
+func (h *Handler) ListPaymentMethods(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	methods, err := h.service.ListPaymentMethods(c.Request.Context(), userID.(string))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, methods, "Payment methods retrieved")
+}
+
+// SetDefaultPaymentMethod godoc
+// @Summary Set default payment method
+// @Tags payments
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Payment method ID"
+// @Success 200 {object} response.Response
+// @Router /payments/methods/{id}/default [put]
+func (h *Handler) SetDefaultPaymentMethod(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	if err := h.service.SetDefaultPaymentMethod(c.Request.Context(), userID.(string), c.Param("id")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, nil, "Default payment method updated")
+}
+
+// DeletePaymentMethod godoc
+// @Summary Remove a saved payment method
+// @Tags payments
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Payment method ID"
+// @Success 200 {object} response.Response
+// @Router /payments/methods/{id} [delete]
+func (h *Handler) DeletePaymentMethod(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	if err := h.service.DeletePaymentMethod(c.Request.Context(), userID.(string), c.Param("id")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, nil, "Payment method removed")
+}
+
+// StripeWebhook godoc
+// @Summary Stripe webhook receiver
+// @Description Verifies the Stripe-Signature header and applies payment intent events to wallet top-ups.
+// @Tags payments
+// @Accept json
+// @Produce json
+// @Success 200 {object} response.Response
+// @Router /payments/webhooks/stripe [post]
+func (h *Handler) StripeWebhook(c *gin.Context) {
+	payload, err := c.GetRawData()
+	if err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	if err := h.service.HandleWebhook(c.Request.Context(), payload, c.GetHeader("Stripe-Signature")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, nil, "Webhook processed")
+}
+
+// AdminListTopUps godoc
+// @Summary List wallet top-ups (admin)
+// @Tags payments
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "Status"
+// @Param userId query string false "User ID"
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Success 200 {object} response.Response{data=[]dto.TopUpResponse}
+// @Router /payments/admin/topups [get]
+func (h *Handler) AdminListTopUps(c *gin.Context) {
+	var query dto.ListTopUpsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+	query.SetDefaults()
+
+	topUps, total, err := h.service.AdminListTopUps(c.Request.Context(), query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Paginated(c, topUps, response.NewPaginationMeta(total, query.Page, query.Limit), "Top-ups retrieved")
+}
+
+// AdminListWebhookEvents godoc
+// @Summary List payment webhook events (admin)
+// @Tags payments
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "Status"
+// @Param eventType query string false "Event type"
+// @Param objectId query string false "Gateway object ID"
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Success 200 {object} response.Response{data=[]models.PaymentWebhookEvent}
+// @Router /payments/admin/webhook-events [get]
+func (h *Handler) AdminListWebhookEvents(c *gin.Context) {
+	var query dto.ListWebhookEventsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+	query.SetDefaults()
+
+	events, total, err := h.service.AdminListWebhookEvents(c.Request.Context(), query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Paginated(c, events, response.NewPaginationMeta(total, query.Page, query.Limit), "Webhook events retrieved")
+}
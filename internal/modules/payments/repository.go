@@ -0,0 +1,330 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	walletservice "github.com/umar5678/go-backend/internal/modules/wallet"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrTopUpAlreadyCredited = errors.New("top-up already credited")
+
+type Repository interface {
+	FindUser(ctx context.Context, userID string) (*models.User, error)
+	FindOrCreateWallet(ctx context.Context, userID, currency string) (*models.Wallet, error)
+
+	FindPaymentCustomer(ctx context.Context, userID, gateway string) (*models.PaymentCustomer, error)
+	CreatePaymentCustomer(ctx context.Context, customer *models.PaymentCustomer) error
+
+	CreateTopUp(ctx context.Context, topUp *models.WalletTopUp) error
+	FindTopUpByID(ctx context.Context, id string) (*models.WalletTopUp, error)
+	FindTopUpByIdempotencyKey(ctx context.Context, userID, key string) (*models.WalletTopUp, error)
+	FindTopUpByIntentID(ctx context.Context, intentID string) (*models.WalletTopUp, error)
+	ListTopUps(ctx context.Context, filters map[string]interface{}, page, limit int) ([]*models.WalletTopUp, int64, error)
+	UpdateTopUpStatus(ctx context.Context, id string, status models.TopUpStatus, failureReason string) error
+	CreditTopUp(ctx context.Context, topUpID, paymentMethodID, description string) (*models.WalletTopUp, *models.WalletTransaction, error)
+
+	ListPaymentMethods(ctx context.Context, userID string) ([]*models.SavedPaymentMethod, error)
+	FindPaymentMethod(ctx context.Context, userID, id string) (*models.SavedPaymentMethod, error)
+	FindPaymentMethodByGatewayID(ctx context.Context, userID, paymentMethodID string) (*models.SavedPaymentMethod, error)
+	SavePaymentMethod(ctx context.Context, method *models.SavedPaymentMethod) error
+	DeletePaymentMethod(ctx context.Context, id string) error
+	SetDefaultPaymentMethod(ctx context.Context, userID, id string) error
+
+	FindWebhookEvent(ctx context.Context, eventID string) (*models.PaymentWebhookEvent, error)
+	SaveWebhookEvent(ctx context.Context, event *models.PaymentWebhookEvent) error
+	ListWebhookEvents(ctx context.Context, filters map[string]interface{}, page, limit int) ([]*models.PaymentWebhookEvent, int64, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) FindUser(ctx context.Context, userID string) (*models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).Where("id = ?", userID).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindOrCreateWallet returns the wallet top-ups are credited to, preferring
+// the rider wallet like the wallet module does.
+func (r *repository) FindOrCreateWallet(ctx context.Context, userID, currency string) (*models.Wallet, error) {
+	var wallet models.Wallet
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("CASE WHEN wallet_type = 'rider' THEN 0 ELSE 1 END").
+		First(&wallet).Error
+	if err == nil {
+		return &wallet, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	wallet = models.Wallet{
+		UserID:     userID,
+		WalletType: models.WalletTypeRider,
+		Currency:   currency,
+		IsActive:   true,
+	}
+	if err := r.db.WithContext(ctx).Create(&wallet).Error; err != nil {
+		return nil, err
+	}
+	return &wallet, nil
+}
+
+func (r *repository) FindPaymentCustomer(ctx context.Context, userID, gateway string) (*models.PaymentCustomer, error) {
+	var customer models.PaymentCustomer
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND gateway = ?", userID, gateway).
+		First(&customer).Error
+	if err != nil {
+		return nil, err
+	}
+	return &customer, nil
+}
+
+func (r *repository) CreatePaymentCustomer(ctx context.Context, customer *models.PaymentCustomer) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(customer).Error
+}
+
+func (r *repository) CreateTopUp(ctx context.Context, topUp *models.WalletTopUp) error {
+	return r.db.WithContext(ctx).Create(topUp).Error
+}
+
+func (r *repository) FindTopUpByID(ctx context.Context, id string) (*models.WalletTopUp, error) {
+	var topUp models.WalletTopUp
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&topUp).Error
+	if err != nil {
+		return nil, err
+	}
+	return &topUp, nil
+}
+
+func (r *repository) FindTopUpByIdempotencyKey(ctx context.Context, userID, key string) (*models.WalletTopUp, error) {
+	var topUp models.WalletTopUp
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND idempotency_key = ?", userID, key).
+		First(&topUp).Error
+	if err != nil {
+		return nil, err
+	}
+	return &topUp, nil
+}
+
+func (r *repository) FindTopUpByIntentID(ctx context.Context, intentID string) (*models.WalletTopUp, error) {
+	var topUp models.WalletTopUp
+	err := r.db.WithContext(ctx).Where("payment_intent_id = ?", intentID).First(&topUp).Error
+	if err != nil {
+		return nil, err
+	}
+	return &topUp, nil
+}
+
+func (r *repository) ListTopUps(ctx context.Context, filters map[string]interface{}, page, limit int) ([]*models.WalletTopUp, int64, error) {
+	var topUps []*models.WalletTopUp
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.WalletTopUp{})
+	for key, value := range filters {
+		query = query.Where(key+" = ?", value)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&topUps).Error
+	return topUps, total, err
+}
+
+func (r *repository) UpdateTopUpStatus(ctx context.Context, id string, status models.TopUpStatus, failureReason string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.WalletTopUp{}).
+		Where("id = ? AND status <> ?", id, models.TopUpStatusSucceeded).
+		Updates(map[string]interface{}{
+			"status":         status,
+			"failure_reason": failureReason,
+		}).Error
+}
+
+// CreditTopUp credits the wallet and marks the top-up succeeded in one
+// transaction. The top-up row is locked first, so concurrent webhook
+// deliveries and status polls credit the wallet exactly once.
+func (r *repository) CreditTopUp(ctx context.Context, topUpID, paymentMethodID, description string) (*models.WalletTopUp, *models.WalletTransaction, error) {
+	var topUp models.WalletTopUp
+	var txn *models.WalletTransaction
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", topUpID).
+			First(&topUp).Error; err != nil {
+			return err
+		}
+		if topUp.Status == models.TopUpStatusSucceeded {
+			return ErrTopUpAlreadyCredited
+		}
+
+		var wallet models.Wallet
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", topUp.WalletID).
+			First(&wallet).Error; err != nil {
+			return err
+		}
+
+		referenceType := "topup"
+		balanceBefore := wallet.Balance
+		entry, err := walletservice.NewJournal(string(models.TransactionTypeCredit), referenceType, topUp.ID, description).
+			DebitPlatform(models.LedgerAccountCash, wallet.Currency, topUp.Amount).
+			CreditWallet(&wallet, topUp.Amount).
+			Post(tx)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		txn = &models.WalletTransaction{
+			WalletID:       wallet.ID,
+			Type:           models.TransactionTypeCredit,
+			Amount:         topUp.Amount,
+			Currency:       wallet.Currency,
+			BalanceBefore:  balanceBefore,
+			BalanceAfter:   wallet.Balance,
+			Status:         models.TransactionStatusCompleted,
+			ReferenceType:  &referenceType,
+			ReferenceID:    &topUp.ID,
+			Description:    &description,
+			PaymentMethod:  topUp.Gateway,
+			ProcessedAt:    &now,
+			JournalEntryID: &entry.ID,
+		}
+		if err := tx.Create(txn).Error; err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"status":         models.TopUpStatusSucceeded,
+			"transaction_id": txn.ID,
+			"credited_at":    now,
+			"failure_reason": "",
+		}
+		if paymentMethodID != "" {
+			updates["payment_method_id"] = paymentMethodID
+		}
+		if err := tx.Model(&models.WalletTopUp{}).Where("id = ?", topUp.ID).Updates(updates).Error; err != nil {
+			return err
+		}
+
+		topUp.Status = models.TopUpStatusSucceeded
+		topUp.TransactionID = &txn.ID
+		topUp.CreditedAt = &now
+		if paymentMethodID != "" {
+			topUp.PaymentMethodID = paymentMethodID
+		}
+		return nil
+	})
+	if err != nil {
+		return &topUp, nil, err
+	}
+	return &topUp, txn, nil
+}
+
+func (r *repository) ListPaymentMethods(ctx context.Context, userID string) ([]*models.SavedPaymentMethod, error) {
+	var methods []*models.SavedPaymentMethod
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("is_default DESC, created_at DESC").
+		Find(&methods).Error
+	return methods, err
+}
+
+func (r *repository) FindPaymentMethod(ctx context.Context, userID, id string) (*models.SavedPaymentMethod, error) {
+	var method models.SavedPaymentMethod
+	err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&method).Error
+	if err != nil {
+		return nil, err
+	}
+	return &method, nil
+}
+
+func (r *repository) FindPaymentMethodByGatewayID(ctx context.Context, userID, paymentMethodID string) (*models.SavedPaymentMethod, error) {
+	var method models.SavedPaymentMethod
+	err := r.db.WithContext(ctx).
+		Where("payment_method_id = ? AND user_id = ?", paymentMethodID, userID).
+		First(&method).Error
+	if err != nil {
+		return nil, err
+	}
+	return &method, nil
+}
+
+func (r *repository) SavePaymentMethod(ctx context.Context, method *models.SavedPaymentMethod) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "payment_method_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"brand", "last4", "exp_month", "exp_year"}),
+		}).
+		Create(method).Error
+}
+
+func (r *repository) DeletePaymentMethod(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.SavedPaymentMethod{}).Error
+}
+
+func (r *repository) SetDefaultPaymentMethod(ctx context.Context, userID, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.SavedPaymentMethod{}).
+			Where("user_id = ?", userID).
+			Update("is_default", false).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.SavedPaymentMethod{}).
+			Where("id = ? AND user_id = ?", id, userID).
+			Update("is_default", true).Error
+	})
+}
+
+func (r *repository) FindWebhookEvent(ctx context.Context, eventID string) (*models.PaymentWebhookEvent, error) {
+	var event models.PaymentWebhookEvent
+	err := r.db.WithContext(ctx).Where("event_id = ?", eventID).First(&event).Error
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+func (r *repository) SaveWebhookEvent(ctx context.Context, event *models.PaymentWebhookEvent) error {
+	return r.db.WithContext(ctx).Save(event).Error
+}
+
+func (r *repository) ListWebhookEvents(ctx context.Context, filters map[string]interface{}, page, limit int) ([]*models.PaymentWebhookEvent, int64, error) {
+	var events []*models.PaymentWebhookEvent
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.PaymentWebhookEvent{})
+	for key, value := range filters {
+		query = query.Where(key+" = ?", value)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&events).Error
+	return events, total, err
+}
@@ -0,0 +1,30 @@
+package payments
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/middleware"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
+	// Signed by the gateway, not the user.
+	router.POST("/payments/webhooks/stripe", handler.StripeWebhook)
+
+	payments := router.Group("/payments")
+	payments.Use(authMiddleware)
+	{
+		payments.POST("/topups", handler.CreateTopUp)
+		payments.GET("/topups", handler.ListTopUps)
+		payments.GET("/topups/:id", handler.GetTopUp)
+
+		payments.GET("/methods", handler.ListPaymentMethods)
+		payments.PUT("/methods/:id/default", handler.SetDefaultPaymentMethod)
+		payments.DELETE("/methods/:id", handler.DeletePaymentMethod)
+
+		admin := payments.Group("/admin")
+		admin.Use(middleware.RequireAdmin())
+		{
+			admin.GET("/topups", handler.AdminListTopUps)
+			admin.GET("/webhook-events", handler.AdminListWebhookEvents)
+		}
+	}
+}
@@ -0,0 +1,490 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/notifications"
+	"github.com/umar5678/go-backend/internal/modules/payments/dto"
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+const (
+	webhookStatusReceived  = "received"
+	webhookStatusProcessed = "processed"
+	webhookStatusIgnored   = "ignored"
+	webhookStatusFailed    = "failed"
+)
+
+type Service interface {
+	CreateTopUp(ctx context.Context, userID string, req dto.CreateTopUpRequest) (*dto.TopUpResponse, error)
+	GetTopUp(ctx context.Context, userID, topUpID string) (*dto.TopUpResponse, error)
+	ListTopUps(ctx context.Context, userID string, query dto.ListTopUpsQuery) ([]*dto.TopUpResponse, int64, error)
+
+	ListPaymentMethods(ctx context.Context, userID string) ([]dto.PaymentMethodResponse, error)
+	SetDefaultPaymentMethod(ctx context.Context, userID, methodID string) error
+	DeletePaymentMethod(ctx context.Context, userID, methodID string) error
+
+	HandleWebhook(ctx context.Context, payload []byte, signature string) error
+
+	AdminListTopUps(ctx context.Context, query dto.ListTopUpsQuery) ([]*dto.TopUpResponse, int64, error)
+	AdminListWebhookEvents(ctx context.Context, query dto.ListWebhookEventsQuery) ([]*models.PaymentWebhookEvent, int64, error)
+}
+
+type service struct {
+	repo          Repository
+	gateway       Gateway
+	cfg           config.PaymentsConfig
+	eventProducer notifications.EventProducer
+}
+
+func NewService(repo Repository, gateway Gateway, cfg config.PaymentsConfig, eventProducer notifications.EventProducer) Service {
+	return &service{
+		repo:          repo,
+		gateway:       gateway,
+		cfg:           cfg,
+		eventProducer: eventProducer,
+	}
+}
+
+func (s *service) CreateTopUp(ctx context.Context, userID string, req dto.CreateTopUpRequest) (*dto.TopUpResponse, error) {
+	if req.Amount < s.cfg.MinTopUp || req.Amount > s.cfg.MaxTopUp {
+		return nil, response.BadRequest(fmt.Sprintf("Top-up amount must be between %.2f and %.2f", s.cfg.MinTopUp, s.cfg.MaxTopUp))
+	}
+
+	idempotencyKey := req.IdempotencyKey
+	if idempotencyKey == "" {
+		idempotencyKey = uuid.New().String()
+	} else if existing, err := s.repo.FindTopUpByIdempotencyKey(ctx, userID, idempotencyKey); err == nil {
+		if existing.Amount != req.Amount {
+			return nil, response.ConflictError("Idempotency key was already used for a different amount")
+		}
+		return s.withClientSecret(ctx, existing)
+	}
+
+	wallet, err := s.repo.FindOrCreateWallet(ctx, userID, s.cfg.Currency)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to load wallet", err)
+	}
+	if !wallet.IsActive {
+		return nil, response.BadRequest("Wallet is not active")
+	}
+
+	customerID, err := s.ensureCustomer(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var gatewayMethodID string
+	if req.PaymentMethodID != "" {
+		method, err := s.repo.FindPaymentMethod(ctx, userID, req.PaymentMethodID)
+		if err != nil {
+			return nil, response.NotFoundError("Payment method")
+		}
+		gatewayMethodID = method.PaymentMethodID
+	}
+
+	intent, err := s.gateway.CreatePaymentIntent(ctx, PaymentIntentRequest{
+		Amount:          toMinorUnits(req.Amount),
+		Currency:        wallet.Currency,
+		CustomerID:      customerID,
+		PaymentMethodID: gatewayMethodID,
+		SaveMethod:      req.SavePaymentMethod && gatewayMethodID == "",
+		IdempotencyKey:  "topup-" + userID + "-" + idempotencyKey,
+		Description:     "Wallet top-up",
+		Metadata: map[string]string{
+			"user_id":   userID,
+			"wallet_id": wallet.ID,
+			"purpose":   "wallet_topup",
+		},
+	})
+	if err != nil {
+		logger.Error("failed to create payment intent", "error", err, "userID", userID, "amount", req.Amount)
+		return nil, response.ServiceUnavailable("Payment gateway is unavailable, please try again")
+	}
+
+	topUp := &models.WalletTopUp{
+		UserID:          userID,
+		WalletID:        wallet.ID,
+		Gateway:         s.gateway.Name(),
+		PaymentIntentID: intent.ID,
+		IdempotencyKey:  idempotencyKey,
+		Amount:          req.Amount,
+		Currency:        wallet.Currency,
+		Status:          models.TopUpStatusPending,
+		PaymentMethodID: gatewayMethodID,
+		SaveMethod:      req.SavePaymentMethod,
+	}
+	if err := s.repo.CreateTopUp(ctx, topUp); err != nil {
+		// A concurrent request with the same key won the insert; the gateway
+		// returned the same intent to both, so hand back the stored row.
+		if existing, findErr := s.repo.FindTopUpByIntentID(ctx, intent.ID); findErr == nil {
+			return s.withClientSecret(ctx, existing)
+		}
+		logger.Error("failed to record top-up", "error", err, "userID", userID, "paymentIntentID", intent.ID)
+		return nil, response.InternalServerError("Failed to create top-up", err)
+	}
+
+	logger.Info("wallet top-up created",
+		"topUpID", topUp.ID,
+		"userID", userID,
+		"amount", req.Amount,
+		"paymentIntentID", intent.ID,
+	)
+
+	resp := dto.ToTopUpResponse(topUp)
+	resp.ClientSecret = intent.ClientSecret
+	resp.PublishableKey = s.cfg.StripePublishableKey
+	return resp, nil
+}
+
+func (s *service) withClientSecret(ctx context.Context, topUp *models.WalletTopUp) (*dto.TopUpResponse, error) {
+	resp := dto.ToTopUpResponse(topUp)
+	if topUp.Status != models.TopUpStatusPending {
+		return resp, nil
+	}
+
+	intent, err := s.gateway.GetPaymentIntent(ctx, topUp.PaymentIntentID)
+	if err != nil {
+		logger.Error("failed to fetch payment intent", "error", err, "paymentIntentID", topUp.PaymentIntentID)
+		return nil, response.ServiceUnavailable("Payment gateway is unavailable, please try again")
+	}
+	resp.ClientSecret = intent.ClientSecret
+	resp.PublishableKey = s.cfg.StripePublishableKey
+	return resp, nil
+}
+
+func (s *service) ensureCustomer(ctx context.Context, userID string) (string, error) {
+	if customer, err := s.repo.FindPaymentCustomer(ctx, userID, s.gateway.Name()); err == nil {
+		return customer.CustomerID, nil
+	}
+
+	user, err := s.repo.FindUser(ctx, userID)
+	if err != nil {
+		return "", response.NotFoundError("User")
+	}
+
+	email := ""
+	if user.Email != nil {
+		email = *user.Email
+	}
+	customerID, err := s.gateway.CreateCustomer(ctx, userID, email, user.Name)
+	if err != nil {
+		logger.Error("failed to create gateway customer", "error", err, "userID", userID)
+		return "", response.ServiceUnavailable("Payment gateway is unavailable, please try again")
+	}
+
+	if err := s.repo.CreatePaymentCustomer(ctx, &models.PaymentCustomer{
+		UserID:     userID,
+		Gateway:    s.gateway.Name(),
+		CustomerID: customerID,
+	}); err != nil {
+		logger.Error("failed to store gateway customer", "error", err, "userID", userID)
+	}
+	return customerID, nil
+}
+
+// GetTopUp also reconciles pending top-ups with the gateway, so a client that
+// polls after confirming the payment sees the credit even if the webhook is
+// delayed.
+func (s *service) GetTopUp(ctx context.Context, userID, topUpID string) (*dto.TopUpResponse, error) {
+	topUp, err := s.repo.FindTopUpByID(ctx, topUpID)
+	if err != nil || topUp.UserID != userID {
+		return nil, response.NotFoundError("Top-up")
+	}
+
+	if topUp.Status == models.TopUpStatusPending {
+		intent, err := s.gateway.GetPaymentIntent(ctx, topUp.PaymentIntentID)
+		if err != nil {
+			logger.Warn("failed to reconcile top-up with gateway", "error", err, "topUpID", topUp.ID)
+		} else if updated, err := s.applyIntent(ctx, topUp, intent); err != nil {
+			logger.Error("failed to apply payment intent", "error", err, "topUpID", topUp.ID)
+		} else {
+			topUp = updated
+		}
+	}
+
+	return dto.ToTopUpResponse(topUp), nil
+}
+
+func (s *service) ListTopUps(ctx context.Context, userID string, query dto.ListTopUpsQuery) ([]*dto.TopUpResponse, int64, error) {
+	query.UserID = userID
+	return s.AdminListTopUps(ctx, query)
+}
+
+func (s *service) AdminListTopUps(ctx context.Context, query dto.ListTopUpsQuery) ([]*dto.TopUpResponse, int64, error) {
+	query.SetDefaults()
+
+	filters := make(map[string]interface{})
+	if query.UserID != "" {
+		filters["user_id"] = query.UserID
+	}
+	if query.Status != "" {
+		filters["status"] = query.Status
+	}
+
+	topUps, total, err := s.repo.ListTopUps(ctx, filters, query.Page, query.Limit)
+	if err != nil {
+		return nil, 0, response.InternalServerError("Failed to fetch top-ups", err)
+	}
+
+	result := make([]*dto.TopUpResponse, len(topUps))
+	for i, topUp := range topUps {
+		result[i] = dto.ToTopUpResponse(topUp)
+	}
+	return result, total, nil
+}
+
+func (s *service) AdminListWebhookEvents(ctx context.Context, query dto.ListWebhookEventsQuery) ([]*models.PaymentWebhookEvent, int64, error) {
+	query.SetDefaults()
+
+	filters := make(map[string]interface{})
+	if query.Status != "" {
+		filters["status"] = query.Status
+	}
+	if query.EventType != "" {
+		filters["event_type"] = query.EventType
+	}
+	if query.ObjectID != "" {
+		filters["object_id"] = query.ObjectID
+	}
+
+	events, total, err := s.repo.ListWebhookEvents(ctx, filters, query.Page, query.Limit)
+	if err != nil {
+		return nil, 0, response.InternalServerError("Failed to fetch webhook events", err)
+	}
+	return events, total, nil
+}
+
+func (s *service) ListPaymentMethods(ctx context.Context, userID string) ([]dto.PaymentMethodResponse, error) {
+	methods, err := s.repo.ListPaymentMethods(ctx, userID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to fetch payment methods", err)
+	}
+
+	result := make([]dto.PaymentMethodResponse, len(methods))
+	for i, method := range methods {
+		result[i] = dto.ToPaymentMethodResponse(method)
+	}
+	return result, nil
+}
+
+func (s *service) SetDefaultPaymentMethod(ctx context.Context, userID, methodID string) error {
+	if _, err := s.repo.FindPaymentMethod(ctx, userID, methodID); err != nil {
+		return response.NotFoundError("Payment method")
+	}
+	if err := s.repo.SetDefaultPaymentMethod(ctx, userID, methodID); err != nil {
+		return response.InternalServerError("Failed to update payment method", err)
+	}
+	return nil
+}
+
+func (s *service) DeletePaymentMethod(ctx context.Context, userID, methodID string) error {
+	method, err := s.repo.FindPaymentMethod(ctx, userID, methodID)
+	if err != nil {
+		return response.NotFoundError("Payment method")
+	}
+
+	if err := s.gateway.DetachPaymentMethod(ctx, method.PaymentMethodID); err != nil {
+		logger.Error("failed to detach payment method", "error", err, "paymentMethodID", method.PaymentMethodID)
+		return response.ServiceUnavailable("Payment gateway is unavailable, please try again")
+	}
+
+	if err := s.repo.DeletePaymentMethod(ctx, method.ID); err != nil {
+		return response.InternalServerError("Failed to remove payment method", err)
+	}
+
+	logger.Info("payment method removed", "userID", userID, "methodID", methodID)
+	return nil
+}
+
+// HandleWebhook verifies and applies a gateway event. Events are recorded by
+// ID; a redelivery of an event that was already processed is acknowledged
+// without side effects, while a failed one is retried.
+func (s *service) HandleWebhook(ctx context.Context, payload []byte, signature string) error {
+	event, err := s.gateway.ParseWebhook(payload, signature)
+	if err != nil {
+		logger.Warn("rejected payment webhook", "error", err)
+		return response.BadRequest("Invalid webhook signature")
+	}
+
+	record, err := s.repo.FindWebhookEvent(ctx, event.ID)
+	if err == nil {
+		if record.Status == webhookStatusProcessed || record.Status == webhookStatusIgnored {
+			return nil
+		}
+	} else if errors.Is(err, gorm.ErrRecordNotFound) {
+		record = &models.PaymentWebhookEvent{
+			Gateway:   s.gateway.Name(),
+			EventID:   event.ID,
+			EventType: event.Type,
+			ObjectID:  event.ObjectID,
+			Status:    webhookStatusReceived,
+		}
+	} else {
+		return response.InternalServerError("Failed to record webhook", err)
+	}
+
+	status, procErr := s.processEvent(ctx, event)
+
+	now := time.Now()
+	record.Status = status
+	record.ProcessedAt = &now
+	record.Error = ""
+	if procErr != nil {
+		record.Status = webhookStatusFailed
+		record.Error = procErr.Error()
+	}
+	if err := s.repo.SaveWebhookEvent(ctx, record); err != nil {
+		logger.Error("failed to save webhook event", "error", err, "eventID", event.ID)
+	}
+
+	if procErr != nil {
+		logger.Error("failed to process payment webhook", "error", procErr, "eventID", event.ID, "type", event.Type)
+		return response.InternalServerError("Failed to process webhook", procErr)
+	}
+	return nil
+}
+
+func (s *service) processEvent(ctx context.Context, event *WebhookEvent) (string, error) {
+	switch event.Type {
+	case "payment_intent.succeeded", "payment_intent.payment_failed", "payment_intent.canceled":
+	default:
+		return webhookStatusIgnored, nil
+	}
+	if event.PaymentIntent == nil {
+		return webhookStatusIgnored, nil
+	}
+
+	topUp, err := s.repo.FindTopUpByIntentID(ctx, event.PaymentIntent.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Not a wallet top-up (or created outside this service).
+			return webhookStatusIgnored, nil
+		}
+		return "", err
+	}
+
+	if _, err := s.applyIntent(ctx, topUp, event.PaymentIntent); err != nil {
+		return "", err
+	}
+	return webhookStatusProcessed, nil
+}
+
+// applyIntent moves a top-up to the state reported by the gateway. Success is
+// only credited when the captured amount and currency match the top-up.
+func (s *service) applyIntent(ctx context.Context, topUp *models.WalletTopUp, intent *PaymentIntent) (*models.WalletTopUp, error) {
+	switch intent.Status {
+	case IntentStatusSucceeded:
+		if intent.Amount != toMinorUnits(topUp.Amount) || (intent.Currency != "" && intent.Currency != topUp.Currency) {
+			logger.Error("payment intent does not match top-up",
+				"topUpID", topUp.ID,
+				"intentAmount", intent.Amount,
+				"intentCurrency", intent.Currency,
+				"topUpAmount", topUp.Amount,
+			)
+			return topUp, fmt.Errorf("payment intent %s amount mismatch", intent.ID)
+		}
+
+		credited, txn, err := s.repo.CreditTopUp(ctx, topUp.ID, intent.PaymentMethodID,
+			fmt.Sprintf("Wallet top-up via %s (%s)", topUp.Gateway, intent.ID))
+		if errors.Is(err, ErrTopUpAlreadyCredited) {
+			return credited, nil
+		}
+		if err != nil {
+			return topUp, err
+		}
+
+		cache.Delete(ctx, fmt.Sprintf("wallet:user:%s", credited.UserID))
+		s.publishEvent(ctx, notifications.EventPaymentProcessed, credited)
+		if credited.SaveMethod && credited.PaymentMethodID != "" {
+			s.savePaymentMethod(ctx, credited.UserID, credited.PaymentMethodID)
+		}
+
+		logger.Info("wallet top-up credited",
+			"topUpID", credited.ID,
+			"userID", credited.UserID,
+			"amount", credited.Amount,
+			"transactionID", txn.ID,
+		)
+		return credited, nil
+
+	case IntentStatusCanceled, IntentStatusFailed:
+		if intent.Status == IntentStatusFailed && intent.FailureReason == "" {
+			// A fresh intent also sits in requires_payment_method before the
+			// customer confirms; only a recorded payment error is a failure.
+			return topUp, nil
+		}
+		status := models.TopUpStatusFailed
+		if intent.Status == IntentStatusCanceled {
+			status = models.TopUpStatusCanceled
+		}
+		if err := s.repo.UpdateTopUpStatus(ctx, topUp.ID, status, intent.FailureReason); err != nil {
+			return topUp, err
+		}
+		if topUp.Status != models.TopUpStatusSucceeded {
+			topUp.Status = status
+			topUp.FailureReason = intent.FailureReason
+			s.publishEvent(ctx, notifications.EventPaymentFailed, topUp)
+		}
+		return topUp, nil
+	}
+
+	return topUp, nil
+}
+
+func (s *service) savePaymentMethod(ctx context.Context, userID, paymentMethodID string) {
+	if _, err := s.repo.FindPaymentMethodByGatewayID(ctx, userID, paymentMethodID); err == nil {
+		return
+	}
+
+	method, err := s.gateway.GetPaymentMethod(ctx, paymentMethodID)
+	if err != nil {
+		logger.Warn("failed to fetch payment method details", "error", err, "paymentMethodID", paymentMethodID)
+		return
+	}
+
+	existing, _ := s.repo.ListPaymentMethods(ctx, userID)
+	if err := s.repo.SavePaymentMethod(ctx, &models.SavedPaymentMethod{
+		UserID:          userID,
+		Gateway:         s.gateway.Name(),
+		PaymentMethodID: method.ID,
+		Brand:           method.Brand,
+		Last4:           method.Last4,
+		ExpMonth:        method.ExpMonth,
+		ExpYear:         method.ExpYear,
+		IsDefault:       len(existing) == 0,
+	}); err != nil {
+		logger.Error("failed to save payment method", "error", err, "userID", userID)
+	}
+}
+
+func (s *service) publishEvent(ctx context.Context, eventType notifications.EventType, topUp *models.WalletTopUp) {
+	if s.eventProducer == nil {
+		return
+	}
+
+	payload := map[string]interface{}{
+		"user_id":          topUp.UserID,
+		"amount":           topUp.Amount,
+		"currency":         topUp.Currency,
+		"transaction_type": "topup",
+		"topup_id":         topUp.ID,
+		"status":           topUp.Status,
+		"timestamp":        time.Now().UTC(),
+	}
+
+	go func() {
+		if err := s.eventProducer.PublishEventWithKey(context.Background(), eventType, topUp.UserID, payload); err != nil {
+			logger.Error("failed to publish top-up event", "error", err, "eventType", eventType, "topUpID", topUp.ID)
+		}
+	}()
+}
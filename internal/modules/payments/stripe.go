@@ -0,0 +1,312 @@
+package payments
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+)
+
+var ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+
+// stripeGateway talks to the Stripe REST API directly; requests are
+// form-encoded and authenticated with the secret key.
+type stripeGateway struct {
+	apiURL        string
+	secretKey     string
+	webhookSecret string
+	tolerance     time.Duration
+	client        *http.Client
+}
+
+func NewStripeGateway(cfg config.PaymentsConfig) Gateway {
+	return &stripeGateway{
+		apiURL:        strings.TrimRight(cfg.StripeAPIURL, "/"),
+		secretKey:     cfg.StripeSecretKey,
+		webhookSecret: cfg.StripeWebhookSecret,
+		tolerance:     cfg.WebhookTolerance,
+		client:        &http.Client{Timeout: 15 * time.Second},
+	}
+}
+
+func (g *stripeGateway) Name() string {
+	return "stripe"
+}
+
+type stripeError struct {
+	Error struct {
+		Type    string `json:"type"`
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+type stripePaymentIntent struct {
+	ID               string          `json:"id"`
+	ClientSecret     string          `json:"client_secret"`
+	Status           string          `json:"status"`
+	Amount           int64           `json:"amount"`
+	Currency         string          `json:"currency"`
+	Customer         json.RawMessage `json:"customer"`
+	PaymentMethod    json.RawMessage `json:"payment_method"`
+	LastPaymentError *struct {
+		Message string `json:"message"`
+	} `json:"last_payment_error"`
+}
+
+func (pi *stripePaymentIntent) toPaymentIntent() *PaymentIntent {
+	intent := &PaymentIntent{
+		ID:              pi.ID,
+		ClientSecret:    pi.ClientSecret,
+		Status:          pi.Status,
+		Amount:          pi.Amount,
+		Currency:        strings.ToUpper(pi.Currency),
+		CustomerID:      expandableID(pi.Customer),
+		PaymentMethodID: expandableID(pi.PaymentMethod),
+	}
+	if pi.LastPaymentError != nil {
+		intent.FailureReason = pi.LastPaymentError.Message
+	}
+	return intent
+}
+
+// expandableID reads a Stripe field that is either an ID string or an
+// expanded object.
+func expandableID(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var id string
+	if err := json.Unmarshal(raw, &id); err == nil {
+		return id
+	}
+	var obj struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(raw, &obj); err == nil {
+		return obj.ID
+	}
+	return ""
+}
+
+func (g *stripeGateway) do(ctx context.Context, method, path string, form url.Values, idempotencyKey string, out interface{}) error {
+	var body *strings.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	} else {
+		body = strings.NewReader("")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, g.apiURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(g.secretKey, "")
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr stripeError
+		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err == nil && apiErr.Error.Message != "" {
+			return fmt.Errorf("stripe %s: %s", apiErr.Error.Type, apiErr.Error.Message)
+		}
+		return fmt.Errorf("stripe returned status %d", resp.StatusCode)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode stripe response: %w", err)
+	}
+	return nil
+}
+
+func (g *stripeGateway) CreateCustomer(ctx context.Context, userID, email, name string) (string, error) {
+	form := url.Values{}
+	form.Set("metadata[user_id]", userID)
+	if email != "" {
+		form.Set("email", email)
+	}
+	if name != "" {
+		form.Set("name", name)
+	}
+
+	var customer struct {
+		ID string `json:"id"`
+	}
+	if err := g.do(ctx, http.MethodPost, "/v1/customers", form, "customer-"+userID, &customer); err != nil {
+		return "", err
+	}
+	return customer.ID, nil
+}
+
+func (g *stripeGateway) CreatePaymentIntent(ctx context.Context, req PaymentIntentRequest) (*PaymentIntent, error) {
+	form := url.Values{}
+	form.Set("amount", strconv.FormatInt(req.Amount, 10))
+	form.Set("currency", strings.ToLower(req.Currency))
+	if req.CustomerID != "" {
+		form.Set("customer", req.CustomerID)
+	}
+	if req.PaymentMethodID != "" {
+		form.Set("payment_method", req.PaymentMethodID)
+	} else {
+		form.Set("automatic_payment_methods[enabled]", "true")
+	}
+	if req.SaveMethod {
+		form.Set("setup_future_usage", "off_session")
+	}
+	if req.Description != "" {
+		form.Set("description", req.Description)
+	}
+	for k, v := range req.Metadata {
+		form.Set("metadata["+k+"]", v)
+	}
+
+	var pi stripePaymentIntent
+	if err := g.do(ctx, http.MethodPost, "/v1/payment_intents", form, req.IdempotencyKey, &pi); err != nil {
+		return nil, err
+	}
+	return pi.toPaymentIntent(), nil
+}
+
+func (g *stripeGateway) GetPaymentIntent(ctx context.Context, id string) (*PaymentIntent, error) {
+	var pi stripePaymentIntent
+	if err := g.do(ctx, http.MethodGet, "/v1/payment_intents/"+url.PathEscape(id), nil, "", &pi); err != nil {
+		return nil, err
+	}
+	return pi.toPaymentIntent(), nil
+}
+
+func (g *stripeGateway) GetPaymentMethod(ctx context.Context, id string) (*PaymentMethod, error) {
+	var pm struct {
+		ID       string          `json:"id"`
+		Customer json.RawMessage `json:"customer"`
+		Card     *struct {
+			Brand    string `json:"brand"`
+			Last4    string `json:"last4"`
+			ExpMonth int    `json:"exp_month"`
+			ExpYear  int    `json:"exp_year"`
+		} `json:"card"`
+	}
+	if err := g.do(ctx, http.MethodGet, "/v1/payment_methods/"+url.PathEscape(id), nil, "", &pm); err != nil {
+		return nil, err
+	}
+
+	method := &PaymentMethod{ID: pm.ID, CustomerID: expandableID(pm.Customer)}
+	if pm.Card != nil {
+		method.Brand = pm.Card.Brand
+		method.Last4 = pm.Card.Last4
+		method.ExpMonth = pm.Card.ExpMonth
+		method.ExpYear = pm.Card.ExpYear
+	}
+	return method, nil
+}
+
+func (g *stripeGateway) DetachPaymentMethod(ctx context.Context, id string) error {
+	return g.do(ctx, http.MethodPost, "/v1/payment_methods/"+url.PathEscape(id)+"/detach", url.Values{}, "", nil)
+}
+
+// ParseWebhook verifies the Stripe-Signature header (HMAC-SHA256 over
+// "timestamp.payload") and decodes the event.
+func (g *stripeGateway) ParseWebhook(payload []byte, signature string) (*WebhookEvent, error) {
+	if err := g.verifySignature(payload, signature); err != nil {
+		return nil, err
+	}
+
+	var raw struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+		Data struct {
+			Object json.RawMessage `json:"object"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(payload, &raw); err != nil {
+		return nil, fmt.Errorf("invalid webhook payload: %w", err)
+	}
+
+	event := &WebhookEvent{ID: raw.ID, Type: raw.Type}
+	var object struct {
+		ID     string `json:"id"`
+		Object string `json:"object"`
+	}
+	if err := json.Unmarshal(raw.Data.Object, &object); err == nil {
+		event.ObjectID = object.ID
+		if object.Object == "payment_intent" {
+			var pi stripePaymentIntent
+			if err := json.Unmarshal(raw.Data.Object, &pi); err != nil {
+				return nil, fmt.Errorf("invalid payment intent in webhook: %w", err)
+			}
+			event.PaymentIntent = pi.toPaymentIntent()
+		}
+	}
+	return event, nil
+}
+
+func (g *stripeGateway) verifySignature(payload []byte, header string) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "t":
+			timestamp = kv[1]
+		case "v1":
+			signatures = append(signatures, kv[1])
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return ErrInvalidWebhookSignature
+	}
+
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidWebhookSignature
+	}
+	if g.tolerance > 0 {
+		age := time.Since(time.Unix(ts, 0))
+		if age > g.tolerance || age < -g.tolerance {
+			return ErrInvalidWebhookSignature
+		}
+	}
+
+	mac := hmac.New(sha256.New, []byte(g.webhookSecret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+
+	for _, sig := range signatures {
+		decoded, err := hex.DecodeString(sig)
+		if err != nil {
+			continue
+		}
+		if hmac.Equal(decoded, expected) {
+			return nil
+		}
+	}
+	return ErrInvalidWebhookSignature
+}
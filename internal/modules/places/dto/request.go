@@ -0,0 +1,24 @@
+package dto
+
+import "errors"
+
+type SearchPlacesRequest struct {
+	Query    string   `form:"q" binding:"required,min=2,max=200"`
+	Lat      *float64 `form:"lat" binding:"omitempty,latitude"`
+	Lng      *float64 `form:"lng" binding:"omitempty,longitude"`
+	Limit    int      `form:"limit" binding:"omitempty,min=1,max=10"`
+	Language string   `form:"lang" binding:"omitempty,max=10"`
+}
+
+func (r *SearchPlacesRequest) Validate() error {
+	if (r.Lat == nil) != (r.Lng == nil) {
+		return errors.New("lat and lng must be given together")
+	}
+	return nil
+}
+
+type ReversePlaceRequest struct {
+	Lat      float64 `form:"lat" binding:"required,latitude"`
+	Lng      float64 `form:"lng" binding:"required,longitude"`
+	Language string  `form:"lang" binding:"omitempty,max=10"`
+}
@@ -0,0 +1,31 @@
+package dto
+
+import "github.com/umar5678/go-backend/internal/services/geocoding"
+
+type PlaceResponse struct {
+	PlaceID          string  `json:"placeId"`
+	Name             string  `json:"name,omitempty"`
+	FormattedAddress string  `json:"formattedAddress"`
+	Latitude         float64 `json:"latitude"`
+	Longitude        float64 `json:"longitude"`
+	Source           string  `json:"source"`
+}
+
+func ToPlaceResponse(place *geocoding.Place) *PlaceResponse {
+	return &PlaceResponse{
+		PlaceID:          place.PlaceID,
+		Name:             place.Name,
+		FormattedAddress: place.FormattedAddress,
+		Latitude:         place.Latitude,
+		Longitude:        place.Longitude,
+		Source:           place.Source,
+	}
+}
+
+func ToPlaceResponses(places []geocoding.Place) []*PlaceResponse {
+	result := make([]*PlaceResponse, len(places))
+	for i := range places {
+		result[i] = ToPlaceResponse(&places[i])
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+package places
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/modules/places/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// SearchPlaces godoc
+// @Summary Search places
+// @Description Forward geocoding for address autocomplete. lat and lng bias results towards the user
+// @Tags places
+// @Security BearerAuth
+// @Produce json
+// @Param q query string true "Search text"
+// @Param lat query number false "Latitude to bias results towards"
+// @Param lng query number false "Longitude to bias results towards"
+// @Param limit query int false "Maximum results (1-10, default 5)"
+// @Param lang query string false "Result language"
+// @Success 200 {object} response.Response{data=[]dto.PlaceResponse}
+// @Failure 429 {object} response.Response
+// @Router /places/search [get]
+func (h *Handler) SearchPlaces(c *gin.Context) {
+	var req dto.SearchPlacesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+
+	places, err := h.service.Search(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, places, "Places retrieved successfully")
+}
+
+// ReversePlace godoc
+// @Summary Reverse geocode a location
+// @Description Returns the normalized address at a coordinate
+// @Tags places
+// @Security BearerAuth
+// @Produce json
+// @Param lat query number true "Latitude"
+// @Param lng query number true "Longitude"
+// @Param lang query string false "Result language"
+// @Success 200 {object} response.Response{data=dto.PlaceResponse}
+// @Failure 429 {object} response.Response
+// @Router /places/reverse [get]
+func (h *Handler) ReversePlace(c *gin.Context) {
+	var req dto.ReversePlaceRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+
+	place, err := h.service.Reverse(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, place, "Address retrieved successfully")
+}
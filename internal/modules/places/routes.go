@@ -0,0 +1,22 @@
+package places
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/middleware"
+)
+
+// RegisterRoutes limits each user to requestsPerMinute lookups, shared by
+// both endpoints, since every cache miss is a paid provider call.
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc, requestsPerMinute int) {
+	places := router.Group("/places", authMiddleware, middleware.RateLimitPerMinute(userKey, requestsPerMinute))
+	{
+		places.GET("/search", handler.SearchPlaces)
+		places.GET("/reverse", handler.ReversePlace)
+	}
+}
+
+func userKey(c *gin.Context) string {
+	userID, _ := c.Get("userID")
+	id, _ := userID.(string)
+	return id
+}
@@ -0,0 +1,63 @@
+package places
+
+import (
+	"context"
+	"errors"
+
+	"github.com/umar5678/go-backend/internal/modules/places/dto"
+	"github.com/umar5678/go-backend/internal/services/geocoding"
+	"github.com/umar5678/go-backend/internal/utils/location"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Service interface {
+	Search(ctx context.Context, req dto.SearchPlacesRequest) ([]*dto.PlaceResponse, error)
+	Reverse(ctx context.Context, req dto.ReversePlaceRequest) (*dto.PlaceResponse, error)
+}
+
+type service struct {
+	geocoder *geocoding.Service
+}
+
+func NewService(geocoder *geocoding.Service) Service {
+	return &service{geocoder: geocoder}
+}
+
+// Search returns matching places, or an empty list when nothing matches.
+func (s *service) Search(ctx context.Context, req dto.SearchPlacesRequest) ([]*dto.PlaceResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	query := geocoding.SearchQuery{Text: req.Query, Limit: req.Limit, Language: req.Language}
+	if req.Lat != nil {
+		query.Near = &location.Point{Latitude: *req.Lat, Longitude: *req.Lng}
+	}
+
+	places, err := s.geocoder.Search(ctx, query)
+	if err != nil {
+		if errors.Is(err, geocoding.ErrNoResults) {
+			return []*dto.PlaceResponse{}, nil
+		}
+		return nil, geocodingError(err)
+	}
+	return dto.ToPlaceResponses(places), nil
+}
+
+func (s *service) Reverse(ctx context.Context, req dto.ReversePlaceRequest) (*dto.PlaceResponse, error) {
+	place, err := s.geocoder.Reverse(ctx, req.Lat, req.Lng, req.Language)
+	if err != nil {
+		if errors.Is(err, geocoding.ErrNoResults) {
+			return nil, response.NotFoundError("Address at this location")
+		}
+		return nil, geocodingError(err)
+	}
+	return dto.ToPlaceResponse(place), nil
+}
+
+func geocodingError(err error) error {
+	if errors.Is(err, geocoding.ErrNotConfigured) {
+		return response.ServiceUnavailable("Place search is not available")
+	}
+	return response.ServiceUnavailable("Place search is temporarily unavailable, please try again")
+}
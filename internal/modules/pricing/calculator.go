@@ -4,7 +4,7 @@ import (
 	"math"
 
 	"github.com/umar5678/go-backend/internal/models"
-	"github.com/umar5678/go-backend/internal/utils/location"
+	"github.com/umar5678/go-backend/internal/services/routing"
 )
 
 type FareCalculator struct{}
@@ -13,18 +13,15 @@ func NewFareCalculator() *FareCalculator {
 	return &FareCalculator{}
 }
 
+// CalculateEstimate prices a trip over the given driving route.
 func (c *FareCalculator) CalculateEstimate(
-	pickupLat, pickupLon, dropoffLat, dropoffLon float64,
+	route *routing.Route,
 	vehicleType *models.VehicleType,
 	surgeMultiplier float64,
 ) *models.FareEstimate {
 
-	distanceKm := location.HaversineDistance(pickupLat, pickupLon, dropoffLat, dropoffLon)
-
-	estimatedDistance := distanceKm * 1.2
-
-	averageSpeedKmh := 40.0
-	estimatedDuration := location.CalculateETA(estimatedDistance, averageSpeedKmh)
+	estimatedDistance := route.DistanceKm
+	estimatedDuration := route.DurationSeconds
 
 	const maxEstimatedDurationSeconds = 12 * 60 * 60
 	if estimatedDuration > maxEstimatedDurationSeconds {
@@ -0,0 +1,30 @@
+package dto
+
+import "time"
+
+// FareLock is the fare quoted to a rider, kept for a short while so the ride
+// can be booked at that price. Rides are matched to it by vehicle type and
+// route, so a token cannot be reused for a different trip.
+type FareLock struct {
+	Token           string    `json:"token"`
+	VehicleTypeID   string    `json:"vehicleTypeId"`
+	PickupLat       float64   `json:"pickupLat"`
+	PickupLon       float64   `json:"pickupLon"`
+	DropoffLat      float64   `json:"dropoffLat"`
+	DropoffLon      float64   `json:"dropoffLon"`
+	SubTotal        float64   `json:"subTotal"`
+	SurgeMultiplier float64   `json:"surgeMultiplier"`
+	SurgeAmount     float64   `json:"surgeAmount"`
+	TotalFare       float64   `json:"totalFare"`
+	Currency        string    `json:"currency"`
+	ExpiresAt       time.Time `json:"expiresAt"`
+}
+
+// Matches reports whether the lock was quoted for this vehicle type and route.
+func (l *FareLock) Matches(vehicleTypeID string, pickupLat, pickupLon, dropoffLat, dropoffLon float64) bool {
+	const tolerance = 0.0005 // roughly 50 m
+	near := func(a, b float64) bool { return a-b < tolerance && b-a < tolerance }
+	return l.VehicleTypeID == vehicleTypeID &&
+		near(l.PickupLat, pickupLat) && near(l.PickupLon, pickupLon) &&
+		near(l.DropoffLat, dropoffLat) && near(l.DropoffLon, dropoffLon)
+}
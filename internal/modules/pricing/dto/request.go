@@ -1,6 +1,9 @@
 package dto
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 type FareEstimateRequest struct {
 	PickupLat     float64 `json:"pickupLat" binding:"required,min=-90,max=90"`
@@ -39,6 +42,11 @@ type CalculateActualFareRequest struct {
 	ActualDurationSec int     `json:"actualDurationSec" binding:"required,min=0"`
 	VehicleTypeID     string  `json:"vehicleTypeId" binding:"required,uuid"`
 	SurgeMultiplier   float64 `json:"surgeMultiplier" binding:"omitempty,min=1,max=5"`
+	// Pickup location is optional; when given the response includes taxes.
+	PickupLat *float64 `json:"pickupLat" binding:"omitempty,min=-90,max=90"`
+	PickupLon *float64 `json:"pickupLon" binding:"omitempty,min=-180,max=180"`
+	// RequestedAt picks the commission rate version; it defaults to now.
+	RequestedAt *time.Time `json:"requestedAt"`
 }
 
 type CalculateWaitTimeRequest struct {
@@ -104,3 +112,92 @@ func (r *CreateSurgeZoneRequest) Validate() error {
 	}
 	return nil
 }
+
+// PublicEstimateRequest is the unauthenticated widget request. Leaving out the
+// vehicle type returns an estimate for every active type.
+type PublicEstimateRequest struct {
+	PickupLat     float64 `json:"pickupLat" binding:"required,min=-90,max=90"`
+	PickupLon     float64 `json:"pickupLon" binding:"required,min=-180,max=180"`
+	DropoffLat    float64 `json:"dropoffLat" binding:"required,min=-90,max=90"`
+	DropoffLon    float64 `json:"dropoffLon" binding:"required,min=-180,max=180"`
+	VehicleTypeID string  `json:"vehicleTypeId" binding:"omitempty,uuid"`
+}
+
+// CalculateTaxRequest prices the taxes owed on an amount charged at a location.
+// AppliesTo is "rides" or "home_services".
+type CalculateTaxRequest struct {
+	Lat       float64 `form:"lat" json:"lat" binding:"min=-90,max=90"`
+	Lon       float64 `form:"lon" json:"lon" binding:"min=-180,max=180"`
+	Amount    float64 `form:"amount" json:"amount" binding:"min=0"`
+	AppliesTo string  `form:"appliesTo" json:"appliesTo" binding:"required,oneof=rides home_services"`
+}
+
+type CreateTaxRegionRequest struct {
+	Code      string  `json:"code" binding:"required,max=50"`
+	Name      string  `json:"name" binding:"required,max=255"`
+	CenterLat float64 `json:"centerLat" binding:"min=-90,max=90"`
+	CenterLon float64 `json:"centerLon" binding:"min=-180,max=180"`
+	RadiusKm  float64 `json:"radiusKm" binding:"min=0,max=5000"`
+	IsDefault bool    `json:"isDefault"`
+	IsActive  *bool   `json:"isActive"`
+}
+
+func (r *CreateTaxRegionRequest) Validate() error {
+	if r.Code == "" {
+		return errors.New("code is required")
+	}
+	if r.Name == "" {
+		return errors.New("name is required")
+	}
+	if !r.IsDefault && r.RadiusKm <= 0 {
+		return errors.New("radius must be greater than 0 unless the region is the default")
+	}
+	return nil
+}
+
+type UpdateTaxRegionRequest struct {
+	Name      *string  `json:"name" binding:"omitempty,max=255"`
+	CenterLat *float64 `json:"centerLat" binding:"omitempty,min=-90,max=90"`
+	CenterLon *float64 `json:"centerLon" binding:"omitempty,min=-180,max=180"`
+	RadiusKm  *float64 `json:"radiusKm" binding:"omitempty,min=0,max=5000"`
+	IsDefault *bool    `json:"isDefault"`
+	IsActive  *bool    `json:"isActive"`
+}
+
+type CreateTaxRateRequest struct {
+	Code           string     `json:"code" binding:"required,max=50"`
+	Name           string     `json:"name" binding:"required,max=255"`
+	Rate           float64    `json:"rate" binding:"required,gt=0,max=100"`
+	AppliesTo      string     `json:"appliesTo" binding:"omitempty,oneof=all rides home_services"`
+	EffectiveFrom  *time.Time `json:"effectiveFrom"`
+	EffectiveUntil *time.Time `json:"effectiveUntil"`
+	IsActive       *bool      `json:"isActive"`
+}
+
+func (r *CreateTaxRateRequest) Validate() error {
+	if r.Code == "" {
+		return errors.New("code is required")
+	}
+	if r.Rate <= 0 || r.Rate > 100 {
+		return errors.New("rate must be between 0 and 100")
+	}
+	return ValidateTaxRateWindow(r.EffectiveFrom, r.EffectiveUntil)
+}
+
+type UpdateTaxRateRequest struct {
+	Name           *string    `json:"name" binding:"omitempty,max=255"`
+	Rate           *float64   `json:"rate" binding:"omitempty,gt=0,max=100"`
+	AppliesTo      *string    `json:"appliesTo" binding:"omitempty,oneof=all rides home_services"`
+	EffectiveFrom  *time.Time `json:"effectiveFrom"`
+	EffectiveUntil *time.Time `json:"effectiveUntil"`
+	IsActive       *bool      `json:"isActive"`
+}
+
+// ValidateTaxRateWindow checks that an effective window, when both ends are
+// given, is not empty.
+func ValidateTaxRateWindow(from, until *time.Time) error {
+	if from != nil && until != nil && !until.After(*from) {
+		return errors.New("effectiveUntil must be after effectiveFrom")
+	}
+	return nil
+}
@@ -1,6 +1,10 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
 
 type FareEstimateResponse struct {
 	BaseFare           float64               `json:"baseFare"`
@@ -16,9 +20,16 @@ type FareEstimateResponse struct {
 	CommissionRate     float64               `json:"commissionRate"`
 	EstimatedDistance  float64               `json:"estimatedDistance"`
 	EstimatedDuration  int                   `json:"estimatedDuration"`
+	RouteSource        string                `json:"routeSource,omitempty"`
 	VehicleTypeName    string                `json:"vehicleTypeName"`
 	Currency           string                `json:"currency"`
+	Taxes              []TaxLine             `json:"taxes"`
+	TaxTotal           float64               `json:"taxTotal"`
+	TotalWithTax       float64               `json:"totalWithTax"`
 	SurgeDetails       *SurgeDetailsResponse `json:"surgeDetails,omitempty"`
+	// FareLockToken books the ride at this fare until FareLockExpiresAt.
+	FareLockToken     string     `json:"fareLockToken,omitempty"`
+	FareLockExpiresAt *time.Time `json:"fareLockExpiresAt,omitempty"`
 }
 
 type SurgeZoneResponse struct {
@@ -64,6 +75,9 @@ type FareBreakdownResponse struct {
 	EstimatedDuration int                   `json:"estimatedDuration"`
 	PriceCapped       bool                  `json:"priceCapped"`
 	PlatformAbsorbed  float64               `json:"platformAbsorbed"`
+	Taxes             []TaxLine             `json:"taxes"`
+	TaxTotal          float64               `json:"taxTotal"`
+	TotalWithTax      float64               `json:"totalWithTax"`
 }
 
 type WaitTimeChargeResponse struct {
@@ -113,3 +127,163 @@ type SurgeDetailsResponse struct {
 	ZoneID                string  `json:"zoneId,omitempty"`
 	ZoneName              string  `json:"zoneName,omitempty"`
 }
+
+// FeeLineItem is a single priced component of a fee breakdown.
+type FeeLineItem struct {
+	Code   string  `json:"code"`
+	Label  string  `json:"label"`
+	Amount float64 `json:"amount"`
+}
+
+// FeeBreakdownResponse is the standardized fee transparency structure shared by
+// rides and service orders.
+type FeeBreakdownResponse struct {
+	ReferenceType    string        `json:"referenceType"`
+	ReferenceID      string        `json:"referenceId"`
+	Currency         string        `json:"currency"`
+	Charges          []FeeLineItem `json:"charges"`
+	Surcharges       []FeeLineItem `json:"surcharges"`
+	Discounts        []FeeLineItem `json:"discounts"`
+	Taxes            []FeeLineItem `json:"taxes"`
+	Subtotal         float64       `json:"subtotal"`
+	SurchargesTotal  float64       `json:"surchargesTotal"`
+	DiscountsTotal   float64       `json:"discountsTotal"`
+	TaxesTotal       float64       `json:"taxesTotal"`
+	TotalCharged     float64       `json:"totalCharged"`
+	PlatformFeeLabel string        `json:"platformFeeLabel,omitempty"`
+	PlatformFee      *float64      `json:"platformFee,omitempty"`
+	EarnerRole       string        `json:"earnerRole"`
+	EarnerShare      *float64      `json:"earnerShare,omitempty"`
+	Source           string        `json:"source"`
+	IsFinal          bool          `json:"isFinal"`
+	CapturedAt       *time.Time    `json:"capturedAt,omitempty"`
+
+	Insurance *InsuranceReference `json:"insurance,omitempty"`
+}
+
+// InsuranceReference is the trip policy printed on ride receipts.
+type InsuranceReference struct {
+	PolicyNumber   string  `json:"policyNumber"`
+	Provider       string  `json:"provider"`
+	CoverageAmount float64 `json:"coverageAmount"`
+	Currency       string  `json:"currency"`
+}
+
+// PublicEstimateResponse is deliberately coarse: rounded fare ranges only, no
+// component breakdown and no surge information.
+type PublicEstimateResponse struct {
+	Currency        string                  `json:"currency"`
+	DistanceKm      float64                 `json:"distanceKm"`
+	DurationMinutes int                     `json:"durationMinutes"`
+	Estimates       []PublicVehicleEstimate `json:"estimates"`
+	TaxIncluded     bool                    `json:"taxIncluded"`
+	Disclaimer      string                  `json:"disclaimer"`
+}
+
+type PublicVehicleEstimate struct {
+	VehicleTypeID string  `json:"vehicleTypeId"`
+	VehicleType   string  `json:"vehicleType"`
+	MinFare       float64 `json:"minFare"`
+	MaxFare       float64 `json:"maxFare"`
+}
+
+// TaxLine is one tax charged on an amount. Rate is a percentage.
+type TaxLine struct {
+	Code          string  `json:"code"`
+	Name          string  `json:"name"`
+	Rate          float64 `json:"rate"`
+	TaxableAmount float64 `json:"taxableAmount"`
+	Amount        float64 `json:"amount"`
+}
+
+func ToTaxLines(lines models.TaxLines) []TaxLine {
+	result := make([]TaxLine, 0, len(lines))
+	for _, line := range lines {
+		result = append(result, TaxLine{
+			Code:          line.Code,
+			Name:          line.Name,
+			Rate:          line.Rate,
+			TaxableAmount: line.TaxableAmount,
+			Amount:        line.Amount,
+		})
+	}
+	return result
+}
+
+// TaxCalculationResponse is the tax owed on an amount at a location. Region is
+// empty when no tax region covers the location.
+type TaxCalculationResponse struct {
+	RegionCode    string    `json:"regionCode,omitempty"`
+	RegionName    string    `json:"regionName,omitempty"`
+	AppliesTo     string    `json:"appliesTo"`
+	TaxableAmount float64   `json:"taxableAmount"`
+	Taxes         []TaxLine `json:"taxes"`
+	TaxTotal      float64   `json:"taxTotal"`
+	TotalWithTax  float64   `json:"totalWithTax"`
+
+	Lines models.TaxLines `json:"-"`
+}
+
+type TaxRateResponse struct {
+	ID             string     `json:"id"`
+	RegionID       string     `json:"regionId"`
+	Code           string     `json:"code"`
+	Name           string     `json:"name"`
+	Rate           float64    `json:"rate"`
+	AppliesTo      string     `json:"appliesTo"`
+	IsActive       bool       `json:"isActive"`
+	EffectiveFrom  *time.Time `json:"effectiveFrom,omitempty"`
+	EffectiveUntil *time.Time `json:"effectiveUntil,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+	UpdatedAt      time.Time  `json:"updatedAt"`
+}
+
+func ToTaxRateResponse(rate *models.TaxRate) *TaxRateResponse {
+	return &TaxRateResponse{
+		ID:             rate.ID,
+		RegionID:       rate.RegionID,
+		Code:           rate.Code,
+		Name:           rate.Name,
+		Rate:           rate.Rate,
+		AppliesTo:      rate.AppliesTo,
+		IsActive:       rate.IsActive,
+		EffectiveFrom:  rate.EffectiveFrom,
+		EffectiveUntil: rate.EffectiveUntil,
+		CreatedAt:      rate.CreatedAt,
+		UpdatedAt:      rate.UpdatedAt,
+	}
+}
+
+type TaxRegionResponse struct {
+	ID        string             `json:"id"`
+	Code      string             `json:"code"`
+	Name      string             `json:"name"`
+	CenterLat float64            `json:"centerLat"`
+	CenterLon float64            `json:"centerLon"`
+	RadiusKm  float64            `json:"radiusKm"`
+	IsDefault bool               `json:"isDefault"`
+	IsActive  bool               `json:"isActive"`
+	Rates     []*TaxRateResponse `json:"rates"`
+	CreatedAt time.Time          `json:"createdAt"`
+	UpdatedAt time.Time          `json:"updatedAt"`
+}
+
+func ToTaxRegionResponse(region *models.TaxRegion) *TaxRegionResponse {
+	rates := make([]*TaxRateResponse, 0, len(region.Rates))
+	for i := range region.Rates {
+		rates = append(rates, ToTaxRateResponse(&region.Rates[i]))
+	}
+	return &TaxRegionResponse{
+		ID:        region.ID,
+		Code:      region.Code,
+		Name:      region.Name,
+		CenterLat: region.CenterLat,
+		CenterLon: region.CenterLon,
+		RadiusKm:  region.RadiusKm,
+		IsDefault: region.IsDefault,
+		IsActive:  region.IsActive,
+		Rates:     rates,
+		CreatedAt: region.CreatedAt,
+		UpdatedAt: region.UpdatedAt,
+	}
+}
@@ -0,0 +1,84 @@
+package pricing
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/modules/pricing/dto"
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+var (
+	fareLockMu  sync.RWMutex
+	fareLockCfg = config.FareLockConfig{TTL: 3 * time.Minute}
+)
+
+// ConfigureFareLock sets how long a quoted fare stays bookable. Called once at startup.
+func ConfigureFareLock(cfg config.FareLockConfig) {
+	fareLockMu.Lock()
+	defer fareLockMu.Unlock()
+	fareLockCfg = cfg
+}
+
+func currentFareLock() config.FareLockConfig {
+	fareLockMu.RLock()
+	defer fareLockMu.RUnlock()
+	return fareLockCfg
+}
+
+func fareLockKey(token string) string {
+	return "fare:lock:" + token
+}
+
+// LockFare keeps the quoted fare in Redis and stamps the estimate with the
+// token the rider books with. The estimate is left without a token when the
+// lock cannot be stored, so booking falls back to the fare at that moment.
+func (s *service) LockFare(ctx context.Context, req dto.FareEstimateRequest, estimate *dto.FareEstimateResponse) {
+	ttl := currentFareLock().TTL
+	if ttl <= 0 {
+		return
+	}
+
+	lock := dto.FareLock{
+		Token:           uuid.New().String(),
+		VehicleTypeID:   req.VehicleTypeID,
+		PickupLat:       req.PickupLat,
+		PickupLon:       req.PickupLon,
+		DropoffLat:      req.DropoffLat,
+		DropoffLon:      req.DropoffLon,
+		SubTotal:        estimate.SubTotal,
+		SurgeMultiplier: estimate.SurgeMultiplier,
+		SurgeAmount:     estimate.SurgeAmount,
+		TotalFare:       estimate.TotalFare,
+		Currency:        estimate.Currency,
+		ExpiresAt:       time.Now().Add(ttl),
+	}
+	if err := cache.SetJSON(ctx, fareLockKey(lock.Token), lock, ttl); err != nil {
+		logger.Warn("failed to store fare lock", "error", err, "vehicleTypeID", req.VehicleTypeID)
+		return
+	}
+
+	estimate.FareLockToken = lock.Token
+	estimate.FareLockExpiresAt = &lock.ExpiresAt
+}
+
+// ConsumeFareLock returns the fare locked under token and removes the lock
+// in the same step, so one quote books one ride. It returns nil once the lock
+// has expired or been used.
+func (s *service) ConsumeFareLock(ctx context.Context, token string) *dto.FareLock {
+	if token == "" {
+		return nil
+	}
+	var lock dto.FareLock
+	if err := cache.GetDelJSON(ctx, fareLockKey(token), &lock); err != nil {
+		return nil
+	}
+	if time.Now().After(lock.ExpiresAt) {
+		return nil
+	}
+	return &lock
+}
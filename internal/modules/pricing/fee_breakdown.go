@@ -0,0 +1,205 @@
+package pricing
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/pricing/dto"
+)
+
+// FeeViewer identifies who is asking for a breakdown. Earners and admins always
+// see the platform/earner split; customers see what FeeDisplayConfig allows.
+type FeeViewer string
+
+const (
+	FeeViewerCustomer FeeViewer = "customer"
+	FeeViewerEarner   FeeViewer = "earner"
+	FeeViewerAdmin    FeeViewer = "admin"
+)
+
+const (
+	FeeSourceSnapshot = "snapshot"
+	FeeSourceOrder    = "order"
+	FeeSourceEstimate = "estimate"
+	FeeSourceLegacy   = "legacy"
+)
+
+var (
+	feeDisplayMu sync.RWMutex
+	feeDisplay   = config.FeeDisplayConfig{
+		ShowPlatformFee:  true,
+		PlatformFeeLabel: "Platform fee",
+		Currency:         "INR",
+	}
+)
+
+// ConfigureFeeDisplay sets the process-wide fee display policy. Called once at startup.
+func ConfigureFeeDisplay(cfg config.FeeDisplayConfig) {
+	feeDisplayMu.Lock()
+	defer feeDisplayMu.Unlock()
+	feeDisplay = cfg
+}
+
+func currentFeeDisplay() config.FeeDisplayConfig {
+	feeDisplayMu.RLock()
+	defer feeDisplayMu.RUnlock()
+	return feeDisplay
+}
+
+// FinalizeFeeBreakdown fills in the totals and applies the display policy for the viewer.
+func FinalizeFeeBreakdown(resp *dto.FeeBreakdownResponse, platformFee, earnerShare float64, viewer FeeViewer) *dto.FeeBreakdownResponse {
+	display := currentFeeDisplay()
+
+	if resp.Currency == "" {
+		resp.Currency = display.Currency
+	}
+	resp.Charges = nonNilItems(resp.Charges)
+	resp.Surcharges = nonNilItems(resp.Surcharges)
+	resp.Discounts = nonNilItems(resp.Discounts)
+	resp.Taxes = nonNilItems(resp.Taxes)
+
+	resp.Subtotal = sumItems(resp.Charges)
+	resp.SurchargesTotal = sumItems(resp.Surcharges)
+	resp.DiscountsTotal = sumItems(resp.Discounts)
+	resp.TaxesTotal = sumItems(resp.Taxes)
+
+	fullView := viewer == FeeViewerEarner || viewer == FeeViewerAdmin
+	if fullView || display.ShowPlatformFee {
+		fee := roundMoney(platformFee)
+		resp.PlatformFee = &fee
+		resp.PlatformFeeLabel = display.PlatformFeeLabel
+	}
+	if fullView || display.ShowEarnerShare {
+		share := roundMoney(earnerShare)
+		resp.EarnerShare = &share
+	}
+
+	return resp
+}
+
+// BuildRideFareBreakdown maps a persisted fare snapshot onto the standard breakdown.
+func BuildRideFareBreakdown(ride *models.Ride, snapshot *models.RideFareSnapshot, viewer FeeViewer) *dto.FeeBreakdownResponse {
+	resp := &dto.FeeBreakdownResponse{
+		ReferenceType: "ride",
+		ReferenceID:   ride.ID,
+		Currency:      snapshot.Currency,
+		EarnerRole:    "driver",
+		Source:        FeeSourceSnapshot,
+		IsFinal:       true,
+		TotalCharged:  roundMoney(snapshot.TotalCharged),
+	}
+	capturedAt := snapshot.CreatedAt
+	resp.CapturedAt = &capturedAt
+
+	resp.Charges = AppendFeeItem(resp.Charges, "base_fare", "Base fare", snapshot.BaseFare)
+	resp.Charges = AppendFeeItem(resp.Charges, "distance", "Distance charge", snapshot.DistanceFare)
+	resp.Charges = AppendFeeItem(resp.Charges, "time", "Time charge", snapshot.DurationFare)
+	resp.Charges = AppendFeeItem(resp.Charges, "booking_fee", "Booking fee", snapshot.BookingFee)
+
+	resp.Surcharges = AppendFeeItem(resp.Surcharges, "surge", "Surge", snapshot.SurgeAmount)
+	resp.Surcharges = AppendFeeItem(resp.Surcharges, "wait_time", "Wait time", snapshot.WaitTimeCharge)
+	resp.Surcharges = AppendFeeItem(resp.Surcharges, "destination_change", "Destination change", snapshot.DestinationChangeCharge)
+
+	resp.Discounts = AppendFeeItem(resp.Discounts, "price_cap", "Price cap", snapshot.PriceCapAdjustment)
+	resp.Discounts = AppendFeeItem(resp.Discounts, "promo", "Promo discount", snapshot.PromoDiscount)
+	resp.Discounts = AppendFeeItem(resp.Discounts, "loyalty", "Loyalty discount", snapshot.LoyaltyDiscount)
+
+	if len(snapshot.TaxLines) > 0 {
+		resp.Taxes = AppendTaxItems(resp.Taxes, snapshot.TaxLines)
+	} else {
+		resp.Taxes = AppendFeeItem(resp.Taxes, "tax", "Tax", snapshot.TaxAmount)
+	}
+
+	return FinalizeFeeBreakdown(resp, snapshot.PlatformFee, snapshot.DriverShare, viewer)
+}
+
+// BuildRideFareBreakdownFromRide is used for rides with no snapshot: either not yet
+// completed (estimate) or completed before snapshots were recorded (legacy).
+func BuildRideFareBreakdownFromRide(ride *models.Ride, viewer FeeViewer) *dto.FeeBreakdownResponse {
+	resp := &dto.FeeBreakdownResponse{
+		ReferenceType: "ride",
+		ReferenceID:   ride.ID,
+		EarnerRole:    "driver",
+		Source:        FeeSourceEstimate,
+	}
+
+	fare := ride.EstimatedFare
+	if ride.ActualFare != nil {
+		fare = *ride.ActualFare
+	}
+	resp.Charges = AppendFeeItem(resp.Charges, "fare", "Trip fare", fare)
+
+	if ride.WaitTimeCharge != nil {
+		resp.Surcharges = AppendFeeItem(resp.Surcharges, "wait_time", "Wait time", *ride.WaitTimeCharge)
+	}
+	if ride.DestinationChangeCharge != nil {
+		resp.Surcharges = AppendFeeItem(resp.Surcharges, "destination_change", "Destination change", *ride.DestinationChangeCharge)
+	}
+	if ride.PromoDiscount != nil {
+		resp.Discounts = AppendFeeItem(resp.Discounts, "promo", "Promo discount", *ride.PromoDiscount)
+	}
+
+	total := fare + sumItems(resp.Surcharges) - sumItems(resp.Discounts)
+	if ride.RiderFare != nil {
+		total = *ride.RiderFare
+	}
+	resp.TotalCharged = roundMoney(total)
+
+	driverShare := total
+	if ride.DriverFare != nil {
+		driverShare = *ride.DriverFare
+	}
+	platformFee := math.Max(0, total-driverShare)
+
+	if ride.Status == "completed" {
+		resp.Source = FeeSourceLegacy
+		resp.IsFinal = true
+		resp.CapturedAt = ride.CompletedAt
+	}
+
+	return FinalizeFeeBreakdown(resp, platformFee, driverShare, viewer)
+}
+
+// AppendFeeItem adds a line item to a breakdown section, skipping zero amounts.
+func AppendFeeItem(items []dto.FeeLineItem, code, label string, amount float64) []dto.FeeLineItem {
+	if amount == 0 {
+		return items
+	}
+	return append(items, dto.FeeLineItem{Code: code, Label: label, Amount: roundMoney(amount)})
+}
+
+// AppendTaxItems adds one line per tax charged, labelled with its rate.
+func AppendTaxItems(items []dto.FeeLineItem, lines models.TaxLines) []dto.FeeLineItem {
+	for _, line := range lines {
+		items = AppendFeeItem(items, "tax:"+line.Code, TaxLabel(line.Name, line.Rate), line.Amount)
+	}
+	return items
+}
+
+// TaxLabel formats a tax name with its rate, e.g. "VAT (5%)".
+func TaxLabel(name string, rate float64) string {
+	return fmt.Sprintf("%s (%s%%)", name, strconv.FormatFloat(rate, 'f', -1, 64))
+}
+
+func nonNilItems(items []dto.FeeLineItem) []dto.FeeLineItem {
+	if items == nil {
+		return []dto.FeeLineItem{}
+	}
+	return items
+}
+
+func sumItems(items []dto.FeeLineItem) float64 {
+	total := 0.0
+	for _, item := range items {
+		total += item.Amount
+	}
+	return roundMoney(total)
+}
+
+func roundMoney(v float64) float64 {
+	return math.Round(v*100) / 100
+}
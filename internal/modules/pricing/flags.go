@@ -0,0 +1,29 @@
+package pricing
+
+import "context"
+
+// flagSurgeCap is a platform-wide ceiling on the surge multiplier, applied
+// on top of each city's cap. 0 leaves surge uncapped.
+const flagSurgeCap = "pricing.surge_cap"
+
+// FeatureFlags reads runtime settings. It is satisfied by
+// featureflags.Service and is optional.
+type FeatureFlags interface {
+	Float(ctx context.Context, key string, def float64) float64
+}
+
+func (s *service) SetFeatureFlags(flags FeatureFlags) {
+	s.flags = flags
+}
+
+// capSurge applies the platform-wide surge cap, if one is set.
+func (s *service) capSurge(ctx context.Context, multiplier float64) float64 {
+	if s.flags == nil {
+		return multiplier
+	}
+	limit := s.flags.Float(ctx, flagSurgeCap, 0)
+	if limit >= 1 && multiplier > limit {
+		return limit
+	}
+	return multiplier
+}
@@ -41,6 +41,36 @@ func (h *Handler) GetFareEstimate(c *gin.Context) {
 	response.Success(c, estimate, "Fare estimate calculated successfully")
 }
 
+// GetPublicEstimate godoc
+// @Summary Public fare estimate for the web widget
+// @Description Login-free estimate returning rounded fare ranges per vehicle type. Requires X-API-Key and X-Captcha-Token headers and is rate limited per IP and per key.
+// @Tags pricing
+// @Accept json
+// @Produce json
+// @Param X-API-Key header string true "Widget API key"
+// @Param X-Captcha-Token header string true "Captcha response token"
+// @Param request body dto.PublicEstimateRequest true "Trip coordinates"
+// @Success 200 {object} response.Response{data=dto.PublicEstimateResponse}
+// @Failure 401 {object} response.Response "Invalid API key"
+// @Failure 403 {object} response.Response "Captcha verification failed"
+// @Failure 429 {object} response.Response "Rate limit exceeded"
+// @Router /public/pricing/estimate [post]
+func (h *Handler) GetPublicEstimate(c *gin.Context) {
+	var req dto.PublicEstimateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	estimate, err := h.service.GetPublicEstimate(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, estimate, "Fare estimate calculated successfully")
+}
+
 // GetSurgeMultiplier godoc
 // @Summary Get surge multiplier for a location
 // @Tags pricing
@@ -352,3 +382,184 @@ func (h *Handler) CalculateETA(c *gin.Context) {
 
 	response.Success(c, eta, "ETA calculated successfully")
 }
+
+// CalculateTax godoc
+// @Summary Calculate taxes on an amount at a location
+// @Tags pricing
+// @Security BearerAuth
+// @Produce json
+// @Param lat query number true "Latitude"
+// @Param lon query number true "Longitude"
+// @Param amount query number true "Taxable amount"
+// @Param appliesTo query string true "rides or home_services"
+// @Success 200 {object} response.Response{data=dto.TaxCalculationResponse}
+// @Router /pricing/tax/calculate [get]
+func (h *Handler) CalculateTax(c *gin.Context) {
+	var req dto.CalculateTaxRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+
+	result, err := h.service.CalculateTax(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Tax calculated successfully")
+}
+
+// ListTaxRegions godoc
+// @Summary List tax regions with their rates
+// @Tags pricing - admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=[]dto.TaxRegionResponse}
+// @Router /pricing/tax/regions [get]
+func (h *Handler) ListTaxRegions(c *gin.Context) {
+	regions, err := h.service.ListTaxRegions(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, regions, "Tax regions retrieved successfully")
+}
+
+// CreateTaxRegion godoc
+// @Summary Create a tax region
+// @Tags pricing - admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateTaxRegionRequest true "Tax region details"
+// @Success 200 {object} response.Response{data=dto.TaxRegionResponse}
+// @Router /pricing/tax/regions [post]
+func (h *Handler) CreateTaxRegion(c *gin.Context) {
+	var req dto.CreateTaxRegionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	region, err := h.service.CreateTaxRegion(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, region, "Tax region created successfully")
+}
+
+// UpdateTaxRegion godoc
+// @Summary Update a tax region
+// @Tags pricing - admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Tax region ID"
+// @Param request body dto.UpdateTaxRegionRequest true "Fields to update"
+// @Success 200 {object} response.Response{data=dto.TaxRegionResponse}
+// @Router /pricing/tax/regions/{id} [put]
+func (h *Handler) UpdateTaxRegion(c *gin.Context) {
+	var req dto.UpdateTaxRegionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	region, err := h.service.UpdateTaxRegion(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, region, "Tax region updated successfully")
+}
+
+// DeleteTaxRegion godoc
+// @Summary Delete a tax region and its rates
+// @Tags pricing - admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Tax region ID"
+// @Success 200 {object} response.Response
+// @Router /pricing/tax/regions/{id} [delete]
+func (h *Handler) DeleteTaxRegion(c *gin.Context) {
+	if err := h.service.DeleteTaxRegion(c.Request.Context(), c.Param("id")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, nil, "Tax region deleted successfully")
+}
+
+// CreateTaxRate godoc
+// @Summary Add a tax rate to a region
+// @Tags pricing - admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Tax region ID"
+// @Param request body dto.CreateTaxRateRequest true "Tax rate details"
+// @Success 200 {object} response.Response{data=dto.TaxRateResponse}
+// @Router /pricing/tax/regions/{id}/rates [post]
+func (h *Handler) CreateTaxRate(c *gin.Context) {
+	var req dto.CreateTaxRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	rate, err := h.service.CreateTaxRate(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, rate, "Tax rate created successfully")
+}
+
+// UpdateTaxRate godoc
+// @Summary Update a tax rate
+// @Tags pricing - admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Tax rate ID"
+// @Param request body dto.UpdateTaxRateRequest true "Fields to update"
+// @Success 200 {object} response.Response{data=dto.TaxRateResponse}
+// @Router /pricing/tax/rates/{id} [put]
+func (h *Handler) UpdateTaxRate(c *gin.Context) {
+	var req dto.UpdateTaxRateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	rate, err := h.service.UpdateTaxRate(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, rate, "Tax rate updated successfully")
+}
+
+// DeleteTaxRate godoc
+// @Summary Delete a tax rate
+// @Tags pricing - admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Tax rate ID"
+// @Success 200 {object} response.Response
+// @Router /pricing/tax/rates/{id} [delete]
+func (h *Handler) DeleteTaxRate(c *gin.Context) {
+	if err := h.service.DeleteTaxRate(c.Request.Context(), c.Param("id")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, nil, "Tax rate deleted successfully")
+}
@@ -0,0 +1,158 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/pricing/dto"
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/utils/location"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+// The public widget shares the fare calculator but nothing else with the
+// authenticated estimate: it has its own cache namespace, never records demand
+// and only returns rounded ranges so surge state cannot be read off the output.
+const (
+	publicEstimateSpread     = 0.10
+	publicEstimateCacheTTL   = 2 * time.Minute
+	publicEstimateDisclaimer = "Estimated fare range. Final fare depends on route, traffic and demand at the time of booking."
+)
+
+var (
+	publicEstimateMu  sync.RWMutex
+	publicEstimateCfg = config.PublicEstimateConfig{RoundTo: 5, Currency: "INR"}
+)
+
+// ConfigurePublicEstimate sets the rounding and currency used by the widget. Called once at startup.
+func ConfigurePublicEstimate(cfg config.PublicEstimateConfig) {
+	publicEstimateMu.Lock()
+	defer publicEstimateMu.Unlock()
+	publicEstimateCfg = cfg
+}
+
+func currentPublicEstimate() config.PublicEstimateConfig {
+	publicEstimateMu.RLock()
+	defer publicEstimateMu.RUnlock()
+	return publicEstimateCfg
+}
+
+func (s *service) GetPublicEstimate(ctx context.Context, req dto.PublicEstimateRequest) (*dto.PublicEstimateResponse, error) {
+	distance := location.HaversineDistance(req.PickupLat, req.PickupLon, req.DropoffLat, req.DropoffLon)
+	if distance < 0.5 {
+		return nil, response.BadRequest("Minimum trip distance is 0.5 km")
+	}
+	if distance > 100 {
+		return nil, response.BadRequest("Maximum trip distance is 100 km")
+	}
+
+	// Coordinates are coarsened to ~100m for the cache key so scripted sweeps
+	// mostly hit the cache instead of the surge calculation.
+	cacheKey := fmt.Sprintf("public:estimate:%s:%.3f:%.3f:%.3f:%.3f",
+		req.VehicleTypeID, req.PickupLat, req.PickupLon, req.DropoffLat, req.DropoffLon)
+
+	var cached dto.PublicEstimateResponse
+	if err := cache.GetJSON(ctx, cacheKey, &cached); err == nil {
+		return &cached, nil
+	}
+
+	if s.cities != nil {
+		if _, err := s.cities.ResolveServiceArea(ctx, req.PickupLat, req.PickupLon); err != nil {
+			return nil, err
+		}
+	}
+
+	var vehicleTypes []*models.VehicleType
+	if req.VehicleTypeID != "" {
+		vehicleType, err := s.vehiclesRepo.FindByID(ctx, req.VehicleTypeID)
+		if err != nil || !vehicleType.IsActive {
+			return nil, response.NotFoundError("Vehicle type")
+		}
+		vehicleTypes = append(vehicleTypes, vehicleType)
+	} else {
+		active, err := s.vehiclesRepo.FindActive(ctx)
+		if err != nil {
+			return nil, response.InternalServerError("Failed to load vehicle types", err)
+		}
+		vehicleTypes = active
+	}
+
+	cfg := currentPublicEstimate()
+	route := s.router.Route(ctx, req.PickupLat, req.PickupLon, req.DropoffLat, req.DropoffLon)
+	geohash := fmt.Sprintf("%.1f_%.1f", req.PickupLat, req.PickupLon)
+
+	quoteCurrency := cfg.Currency
+	if s.currencies != nil {
+		quoteCurrency = s.currencies.ForLocation(ctx, req.PickupLat, req.PickupLon)
+	}
+
+	resp := &dto.PublicEstimateResponse{
+		Currency:    quoteCurrency,
+		Estimates:   make([]dto.PublicVehicleEstimate, 0, len(vehicleTypes)),
+		TaxIncluded: true,
+		Disclaimer:  publicEstimateDisclaimer,
+	}
+
+	for _, vehicleType := range vehicleTypes {
+		// Vehicle types the pickup city does not offer are left out, unless
+		// that was the one asked for.
+		fareConfig, err := s.fareConfig(ctx, req.PickupLat, req.PickupLon, vehicleType)
+		if err != nil {
+			if req.VehicleTypeID != "" {
+				return nil, err
+			}
+			continue
+		}
+		vehicleType = fareConfig.VehicleType
+
+		multiplier, _, _, _, err := s.surgeManager.CalculateCombinedSurge(ctx, vehicleType.ID, geohash, req.PickupLat, req.PickupLon)
+		if err != nil {
+			multiplier = 1.0
+		}
+		multiplier = s.capSurge(ctx, fareConfig.CapSurge(multiplier))
+
+		estimate := s.calculator.CalculateEstimate(route, vehicleType, multiplier)
+
+		resp.DistanceKm = math.Round(estimate.EstimatedDistance*10) / 10
+		resp.DurationMinutes = int(math.Ceil(float64(estimate.EstimatedDuration) / 60.0))
+
+		// Ranges are quoted tax inclusive so they match what the rider pays.
+		total := s.estimateTax(ctx, req.PickupLat, req.PickupLon, estimate.TotalFare, models.TaxAppliesToRides).TotalWithTax
+
+		resp.Estimates = append(resp.Estimates, dto.PublicVehicleEstimate{
+			VehicleTypeID: vehicleType.ID,
+			VehicleType:   vehicleType.DisplayName,
+			MinFare:       roundDownTo(total*(1-publicEstimateSpread), cfg.RoundTo),
+			MaxFare:       roundUpTo(total*(1+publicEstimateSpread), cfg.RoundTo),
+		})
+	}
+
+	cache.SetJSON(ctx, cacheKey, resp, publicEstimateCacheTTL)
+
+	logger.Info("public fare estimate served",
+		"vehicleTypes", len(resp.Estimates),
+		"distanceKm", resp.DistanceKm,
+	)
+
+	return resp, nil
+}
+
+func roundDownTo(value, step float64) float64 {
+	if step <= 0 {
+		return math.Floor(value)
+	}
+	return math.Floor(value/step) * step
+}
+
+func roundUpTo(value, step float64) float64 {
+	if step <= 0 {
+		return math.Ceil(value)
+	}
+	return math.Ceil(value/step) * step
+}
@@ -33,6 +33,7 @@ type Repository interface {
 	UpdateETAEstimate(ctx context.Context, eta *models.ETAEstimate) error
 
 	CreateSurgeHistory(ctx context.Context, history *models.SurgeHistory) error
+	CreateSurgeSnapshot(ctx context.Context, snapshot *models.SurgeSnapshot) error
 	GetSurgeHistory(ctx context.Context, rideID string) (*models.SurgeHistory, error)
 
 	FindPriceCappingRule(ctx context.Context, vehicleTypeID string) (*models.PriceCappingRule, error)
@@ -46,6 +47,19 @@ type Repository interface {
 
 	UpdateRideDestination(ctx context.Context, rideID string, lat, lon float64, address string, additionalCharge float64) error
 	UpdateRideWaitTimeCharge(ctx context.Context, rideID string, charge float64) error
+
+	ListTaxRegions(ctx context.Context, activeOnly bool) ([]*models.TaxRegion, error)
+	GetTaxRegion(ctx context.Context, id string) (*models.TaxRegion, error)
+	TaxRegionCodeExists(ctx context.Context, code string) (bool, error)
+	CreateTaxRegion(ctx context.Context, region *models.TaxRegion) error
+	UpdateTaxRegion(ctx context.Context, region *models.TaxRegion) error
+	DeleteTaxRegion(ctx context.Context, id string) error
+	ClearDefaultTaxRegion(ctx context.Context, exceptID string) error
+	GetTaxRate(ctx context.Context, id string) (*models.TaxRate, error)
+	TaxRateCodeExists(ctx context.Context, regionID, code, appliesTo string) (bool, error)
+	CreateTaxRate(ctx context.Context, rate *models.TaxRate) error
+	UpdateTaxRate(ctx context.Context, rate *models.TaxRate) error
+	DeleteTaxRate(ctx context.Context, id string) error
 }
 
 type repository struct {
@@ -260,6 +274,10 @@ func (r *repository) CreateSurgeHistory(ctx context.Context, history *models.Sur
 	return r.db.WithContext(ctx).Create(history).Error
 }
 
+func (r *repository) CreateSurgeSnapshot(ctx context.Context, snapshot *models.SurgeSnapshot) error {
+	return r.db.WithContext(ctx).Create(snapshot).Error
+}
+
 func (r *repository) GetSurgeHistory(ctx context.Context, rideID string) (*models.SurgeHistory, error) {
 	var history models.SurgeHistory
 	err := r.db.WithContext(ctx).
@@ -313,3 +331,91 @@ func (r *repository) GetLatestDemand(ctx context.Context, zoneID, vehicleTypeID
 
 	return &demand, err
 }
+
+func (r *repository) ListTaxRegions(ctx context.Context, activeOnly bool) ([]*models.TaxRegion, error) {
+	var regions []*models.TaxRegion
+
+	db := r.db.WithContext(ctx)
+	if activeOnly {
+		db = db.Where("is_active = ?", true).
+			Preload("Rates", "is_active = ?", true)
+	} else {
+		db = db.Preload("Rates", func(tx *gorm.DB) *gorm.DB {
+			return tx.Order("code ASC")
+		})
+	}
+
+	err := db.Order("is_default ASC, code ASC").Find(&regions).Error
+	return regions, err
+}
+
+func (r *repository) GetTaxRegion(ctx context.Context, id string) (*models.TaxRegion, error) {
+	var region models.TaxRegion
+	err := r.db.WithContext(ctx).
+		Preload("Rates", func(tx *gorm.DB) *gorm.DB {
+			return tx.Order("code ASC")
+		}).
+		Where("id = ?", id).
+		First(&region).Error
+	return &region, err
+}
+
+func (r *repository) TaxRegionCodeExists(ctx context.Context, code string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.TaxRegion{}).
+		Where("code = ?", code).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *repository) CreateTaxRegion(ctx context.Context, region *models.TaxRegion) error {
+	return r.db.WithContext(ctx).Create(region).Error
+}
+
+func (r *repository) UpdateTaxRegion(ctx context.Context, region *models.TaxRegion) error {
+	return r.db.WithContext(ctx).Omit("Rates").Save(region).Error
+}
+
+func (r *repository) DeleteTaxRegion(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("region_id = ?", id).Delete(&models.TaxRate{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", id).Delete(&models.TaxRegion{}).Error
+	})
+}
+
+func (r *repository) ClearDefaultTaxRegion(ctx context.Context, exceptID string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.TaxRegion{}).
+		Where("is_default = ? AND id <> ?", true, exceptID).
+		Update("is_default", false).Error
+}
+
+func (r *repository) GetTaxRate(ctx context.Context, id string) (*models.TaxRate, error) {
+	var rate models.TaxRate
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&rate).Error
+	return &rate, err
+}
+
+func (r *repository) TaxRateCodeExists(ctx context.Context, regionID, code, appliesTo string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.TaxRate{}).
+		Where("region_id = ? AND code = ? AND applies_to = ?", regionID, code, appliesTo).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *repository) CreateTaxRate(ctx context.Context, rate *models.TaxRate) error {
+	return r.db.WithContext(ctx).Create(rate).Error
+}
+
+func (r *repository) UpdateTaxRate(ctx context.Context, rate *models.TaxRate) error {
+	return r.db.WithContext(ctx).Save(rate).Error
+}
+
+func (r *repository) DeleteTaxRate(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.TaxRate{}).Error
+}
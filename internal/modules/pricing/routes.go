@@ -21,5 +21,28 @@ func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gi
 		pricing.POST("/calculate-eta", handler.CalculateETA)
 		pricing.POST("/wait-time", handler.CalculateWaitTimeCharge)
 		pricing.GET("/fare-breakdown", handler.GetFareBreakdown)
+
+		pricing.GET("/tax/calculate", authMiddleware, handler.CalculateTax)
+
+		tax := pricing.Group("/tax", authMiddleware, middleware.RequireAdmin())
+		{
+			tax.GET("/regions", handler.ListTaxRegions)
+			tax.POST("/regions", handler.CreateTaxRegion)
+			tax.PUT("/regions/:id", handler.UpdateTaxRegion)
+			tax.DELETE("/regions/:id", handler.DeleteTaxRegion)
+			tax.POST("/regions/:id/rates", handler.CreateTaxRate)
+			tax.PUT("/rates/:id", handler.UpdateTaxRate)
+			tax.DELETE("/rates/:id", handler.DeleteTaxRate)
+		}
+	}
+}
+
+// RegisterPublicRoutes mounts the login-free estimate used by the marketing
+// widget. guards carry the API key, rate limit and captcha checks.
+func RegisterPublicRoutes(router *gin.RouterGroup, handler *Handler, guards ...gin.HandlerFunc) {
+	public := router.Group("/public/pricing")
+	public.Use(guards...)
+	{
+		public.POST("/estimate", handler.GetPublicEstimate)
 	}
 }
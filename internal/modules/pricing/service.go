@@ -3,14 +3,18 @@ package pricing
 import (
 	"context"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/cities"
+	"github.com/umar5678/go-backend/internal/modules/currency"
 	"github.com/umar5678/go-backend/internal/modules/notifications"
 	"github.com/umar5678/go-backend/internal/modules/pricing/dto"
 	vehiclesrepo "github.com/umar5678/go-backend/internal/modules/vehicles"
 	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/services/routing"
 	"gorm.io/gorm"
 
 	"github.com/umar5678/go-backend/internal/utils/location"
@@ -19,7 +23,11 @@ import (
 )
 
 type Service interface {
+	// GetFareEstimate quotes a trip to the rider and locks the quoted fare.
 	GetFareEstimate(ctx context.Context, req dto.FareEstimateRequest) (*dto.FareEstimateResponse, error)
+	// CalculateFareEstimate prices a trip the same way without issuing a
+	// fare lock, for callers that only need the current fare.
+	CalculateFareEstimate(ctx context.Context, req dto.FareEstimateRequest) (*dto.FareEstimateResponse, error)
 	CalculateActualFare(ctx context.Context, req dto.CalculateActualFareRequest) (*dto.FareEstimateResponse, error)
 	GetSurgeMultiplier(ctx context.Context, lat, lon float64) (float64, error)
 	GetActiveSurgeZones(ctx context.Context) ([]*dto.SurgeZoneResponse, error)
@@ -34,6 +42,32 @@ type Service interface {
 	GetActiveSurgePricingRules(ctx context.Context) ([]*dto.SurgePricingRuleResponse, error)
 	GetCurrentDemand(ctx context.Context, geohash string) (*dto.DemandTrackingResponse, error)
 	CalculateETAEstimate(ctx context.Context, req dto.ETAEstimateRequest) (*dto.ETAEstimateResponse, error)
+
+	LockFare(ctx context.Context, req dto.FareEstimateRequest, estimate *dto.FareEstimateResponse)
+	ConsumeFareLock(ctx context.Context, token string) *dto.FareLock
+
+	GetPublicEstimate(ctx context.Context, req dto.PublicEstimateRequest) (*dto.PublicEstimateResponse, error)
+
+	CalculateTax(ctx context.Context, req dto.CalculateTaxRequest) (*dto.TaxCalculationResponse, error)
+	ListTaxRegions(ctx context.Context) ([]*dto.TaxRegionResponse, error)
+	CreateTaxRegion(ctx context.Context, req dto.CreateTaxRegionRequest) (*dto.TaxRegionResponse, error)
+	UpdateTaxRegion(ctx context.Context, id string, req dto.UpdateTaxRegionRequest) (*dto.TaxRegionResponse, error)
+	DeleteTaxRegion(ctx context.Context, id string) error
+	CreateTaxRate(ctx context.Context, regionID string, req dto.CreateTaxRateRequest) (*dto.TaxRateResponse, error)
+	UpdateTaxRate(ctx context.Context, id string, req dto.UpdateTaxRateRequest) (*dto.TaxRateResponse, error)
+	DeleteTaxRate(ctx context.Context, id string) error
+
+	SetRouter(router *routing.Service)
+	SetCurrencies(currencies currency.Service)
+	SetCities(cities cities.Service)
+	SetCommissionRates(commissionRates CommissionRates)
+	SetFeatureFlags(flags FeatureFlags)
+}
+
+// CommissionRates resolves the platform's cut of a ride fare, in percent. It
+// is satisfied by commissions.Service.
+type CommissionRates interface {
+	RideRate(ctx context.Context, vehicleTypeID string, lat, lon float64, at time.Time) float64
 }
 
 type service struct {
@@ -43,6 +77,11 @@ type service struct {
 	calculator    *FareCalculator
 	surgeManager  *SurgeManager
 	eventProducer notifications.EventProducer
+	router        *routing.Service
+	currencies    currency.Service
+	cities        cities.Service
+	commissions   CommissionRates
+	flags         FeatureFlags
 }
 
 func NewService(repo Repository, db *gorm.DB, vehiclesRepo vehiclesrepo.Repository) Service {
@@ -60,7 +99,65 @@ func NewServiceWithNotifications(repo Repository, db *gorm.DB, vehiclesRepo vehi
 	}
 }
 
+// SetRouter switches trip distances and durations from the straight-line
+// estimate to driving routes.
+func (s *service) SetRouter(router *routing.Service) {
+	s.router = router
+}
+
+// SetCurrencies quotes fares in the pickup city's currency instead of the
+// display default.
+func (s *service) SetCurrencies(currencies currency.Service) {
+	s.currencies = currencies
+}
+
+// currencyAt is the currency fares picked up at a coordinate are quoted in.
+func (s *service) currencyAt(ctx context.Context, lat, lon float64) string {
+	if s.currencies == nil {
+		return currentFeeDisplay().Currency
+	}
+	return s.currencies.ForLocation(ctx, lat, lon)
+}
+
+// SetCities prices rides with the pickup city's rates and surge cap, and
+// refuses estimates for pickups outside every active city.
+func (s *service) SetCities(cities cities.Service) {
+	s.cities = cities
+}
+
+// fareConfig is how a vehicle type is priced at a pickup point.
+func (s *service) fareConfig(ctx context.Context, lat, lon float64, vehicleType *models.VehicleType) (*cities.FareConfig, error) {
+	if s.cities == nil {
+		return &cities.FareConfig{VehicleType: vehicleType}, nil
+	}
+	return s.cities.FareConfig(ctx, lat, lon, vehicleType)
+}
+
+// SetCommissionRates splits fares between driver and platform. Without it
+// drivers are paid the whole fare.
+func (s *service) SetCommissionRates(commissionRates CommissionRates) {
+	s.commissions = commissionRates
+}
+
+// commissionRate is the platform's cut, in percent, of a ride of the vehicle
+// type picked up at a coordinate.
+func (s *service) commissionRate(ctx context.Context, vehicleTypeID string, lat, lon float64, at time.Time) float64 {
+	if s.commissions == nil {
+		return 0
+	}
+	return s.commissions.RideRate(ctx, vehicleTypeID, lat, lon, at)
+}
+
 func (s *service) GetFareEstimate(ctx context.Context, req dto.FareEstimateRequest) (*dto.FareEstimateResponse, error) {
+	estimate, err := s.CalculateFareEstimate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	s.LockFare(ctx, req, estimate)
+	return estimate, nil
+}
+
+func (s *service) CalculateFareEstimate(ctx context.Context, req dto.FareEstimateRequest) (*dto.FareEstimateResponse, error) {
 	if err := req.Validate(); err != nil {
 		return nil, response.BadRequest(err.Error())
 	}
@@ -83,6 +180,12 @@ func (s *service) GetFareEstimate(ctx context.Context, req dto.FareEstimateReque
 		return nil, response.BadRequest("Vehicle type is not available")
 	}
 
+	fareConfig, err := s.fareConfig(ctx, req.PickupLat, req.PickupLon, vehicleType)
+	if err != nil {
+		return nil, err
+	}
+	vehicleType = fareConfig.VehicleType
+
 	geohash := fmt.Sprintf("%.1f_%.1f", req.PickupLat, req.PickupLon)
 	combinedMultiplier, timeMultiplier, demandMultiplier, reason, err := s.surgeManager.CalculateCombinedSurge(ctx, req.VehicleTypeID, geohash, req.PickupLat, req.PickupLon)
 	if err != nil {
@@ -93,14 +196,13 @@ func (s *service) GetFareEstimate(ctx context.Context, req dto.FareEstimateReque
 		reason = "normal"
 	}
 
-	surgeMultiplier := combinedMultiplier
+	surgeMultiplier := s.capSurge(ctx, fareConfig.CapSurge(combinedMultiplier))
 
-	estimate := s.calculator.CalculateEstimate(
-		req.PickupLat, req.PickupLon,
-		req.DropoffLat, req.DropoffLon,
-		vehicleType,
-		surgeMultiplier,
-	)
+	route := s.router.Route(ctx, req.PickupLat, req.PickupLon, req.DropoffLat, req.DropoffLon)
+	estimate := s.calculator.CalculateEstimate(route, vehicleType, surgeMultiplier)
+	tax := s.estimateTax(ctx, req.PickupLat, req.PickupLon, estimate.TotalFare, models.TaxAppliesToRides)
+	commissionRate := s.commissionRate(ctx, req.VehicleTypeID, req.PickupLat, req.PickupLon, time.Now())
+	driverPayout, platformCommission := s.CalculateDriverPayout(estimate.TotalFare, commissionRate)
 
 	fareResponse := &dto.FareEstimateResponse{
 		BaseFare:          estimate.BaseFare,
@@ -113,12 +215,16 @@ func (s *service) GetFareEstimate(ctx context.Context, req dto.FareEstimateReque
 		TotalFare:         estimate.TotalFare,
 		EstimatedDistance: estimate.EstimatedDistance,
 		EstimatedDuration: estimate.EstimatedDuration,
+		RouteSource:       route.Source,
 		VehicleTypeName:   estimate.VehicleTypeName,
-		Currency:          "INR",
+		Currency:          s.currencyAt(ctx, req.PickupLat, req.PickupLon),
+		Taxes:             tax.Taxes,
+		TaxTotal:          tax.TaxTotal,
+		TotalWithTax:      tax.TotalWithTax,
 
-		DriverPayout:       estimate.TotalFare,
-		PlatformCommission: 0,
-		CommissionRate:     0,
+		DriverPayout:       driverPayout,
+		PlatformCommission: platformCommission,
+		CommissionRate:     commissionRate,
 
 		SurgeDetails: &dto.SurgeDetailsResponse{
 			IsActive:              surgeMultiplier > 1.0,
@@ -140,8 +246,9 @@ func (s *service) GetFareEstimate(ctx context.Context, req dto.FareEstimateReque
 		"surge", surgeMultiplier,
 		"surgeReason", reason,
 		"totalFare", estimate.TotalFare,
-		"driverPayout", estimate.TotalFare,
-		"platformCommission", 0,
+		"taxTotal", tax.TaxTotal,
+		"driverPayout", driverPayout,
+		"platformCommission", platformCommission,
 	)
 
 	return fareResponse, nil
@@ -175,6 +282,16 @@ func (s *service) CalculateActualFare(ctx context.Context, req dto.CalculateActu
 		surgeMultiplier = 1.0
 	}
 
+	// The ride has already happened, so a pickup that has since left service
+	// is still charged, at the vehicle type's own rates.
+	if req.PickupLat != nil && req.PickupLon != nil {
+		if fareConfig, err := s.fareConfig(ctx, *req.PickupLat, *req.PickupLon, vehicleType); err == nil {
+			vehicleType = fareConfig.VehicleType
+		} else {
+			logger.Warn("city fare config unavailable for completed ride", "error", err, "vehicleTypeID", vehicleType.ID)
+		}
+	}
+
 	estimate := s.calculator.CalculateActualFare(
 		req.ActualDistanceKm,
 		req.ActualDurationSec,
@@ -182,6 +299,17 @@ func (s *service) CalculateActualFare(ctx context.Context, req dto.CalculateActu
 		surgeMultiplier,
 	)
 
+	commissionAt := time.Now()
+	if req.RequestedAt != nil {
+		commissionAt = *req.RequestedAt
+	}
+	var pickupLat, pickupLon float64
+	if req.PickupLat != nil && req.PickupLon != nil {
+		pickupLat, pickupLon = *req.PickupLat, *req.PickupLon
+	}
+	commissionRate := s.commissionRate(ctx, req.VehicleTypeID, pickupLat, pickupLon, commissionAt)
+	driverPayout, platformCommission := s.CalculateDriverPayout(estimate.TotalFare, commissionRate)
+
 	fareResponse := &dto.FareEstimateResponse{
 		BaseFare:           estimate.BaseFare,
 		DistanceFare:       estimate.DistanceFare,
@@ -191,13 +319,23 @@ func (s *service) CalculateActualFare(ctx context.Context, req dto.CalculateActu
 		SubTotal:           estimate.SubTotal,
 		SurgeAmount:        estimate.SurgeAmount,
 		TotalFare:          estimate.TotalFare,
-		DriverPayout:       estimate.TotalFare,
-		PlatformCommission: 0,
-		CommissionRate:     0,
+		DriverPayout:       driverPayout,
+		PlatformCommission: platformCommission,
+		CommissionRate:     commissionRate,
 		EstimatedDistance:  estimate.EstimatedDistance,
 		EstimatedDuration:  estimate.EstimatedDuration,
 		VehicleTypeName:    estimate.VehicleTypeName,
-		Currency:           "INR",
+		Currency:           currentFeeDisplay().Currency,
+		Taxes:              []dto.TaxLine{},
+		TotalWithTax:       estimate.TotalFare,
+	}
+
+	if req.PickupLat != nil && req.PickupLon != nil {
+		fareResponse.Currency = s.currencyAt(ctx, *req.PickupLat, *req.PickupLon)
+		tax := s.estimateTax(ctx, *req.PickupLat, *req.PickupLon, estimate.TotalFare, models.TaxAppliesToRides)
+		fareResponse.Taxes = tax.Taxes
+		fareResponse.TaxTotal = tax.TaxTotal
+		fareResponse.TotalWithTax = tax.TotalWithTax
 	}
 
 	logger.Info("actual fare calculated",
@@ -209,6 +347,7 @@ func (s *service) CalculateActualFare(ctx context.Context, req dto.CalculateActu
 		"durationFare", estimate.DurationFare,
 		"surge", surgeMultiplier,
 		"totalFare", estimate.TotalFare,
+		"commissionRate", commissionRate,
 	)
 
 	return fareResponse, nil
@@ -340,9 +479,9 @@ func (s *service) GetFareBreakdown(ctx context.Context, req dto.GetFareBreakdown
 		return nil, response.NotFoundError("Vehicle type")
 	}
 
-	distance := location.HaversineDistance(req.PickupLat, req.PickupLon, req.DropoffLat, req.DropoffLon)
-
-	duration := int((distance / 30.0) * 60)
+	route := s.router.Route(ctx, req.PickupLat, req.PickupLon, req.DropoffLat, req.DropoffLon)
+	distance := route.DistanceKm
+	duration := int(math.Round(float64(route.DurationSeconds) / 60.0))
 
 	geohash := fmt.Sprintf("%.1f_%.1f", req.PickupLat, req.PickupLon)
 	combinedMultiplier, timeMultiplier, demandMultiplier, surgeReason, err := s.surgeManager.CalculateCombinedSurge(ctx, req.VehicleTypeID, geohash, req.PickupLat, req.PickupLon)
@@ -465,6 +604,19 @@ func (s *service) GetFareBreakdown(ctx context.Context, req dto.GetFareBreakdown
 		breakdown.PriceCapped = false
 	}
 
+	// Tax is levied on what the customer pays, after any price cap.
+	tax := s.estimateTax(ctx, req.PickupLat, req.PickupLon, breakdown.CustomerPrice, models.TaxAppliesToRides)
+	breakdown.Taxes = tax.Taxes
+	breakdown.TaxTotal = tax.TaxTotal
+	breakdown.TotalWithTax = tax.TotalWithTax
+	for _, line := range tax.Taxes {
+		breakdown.Components = append(breakdown.Components, dto.FareComponent{
+			Name:   TaxLabel(line.Name, line.Rate),
+			Amount: line.Amount,
+			Type:   "tax",
+		})
+	}
+
 	return breakdown, nil
 }
 
@@ -508,7 +660,7 @@ func (s *service) ApplyPriceCapping(ctx context.Context, vehicleTypeID string, c
 }
 
 func (s *service) CalculateDriverPayout(totalFare float64, commissionRate float64) (driverAmount, platformCommission float64) {
-	platformCommission = totalFare * (commissionRate / 100.0)
+	platformCommission = math.Round(totalFare*commissionRate) / 100
 	driverAmount = totalFare - platformCommission
 	return driverAmount, platformCommission
 }
@@ -754,9 +906,8 @@ func (s *service) CalculateETAEstimate(ctx context.Context, req dto.ETAEstimateR
 	if distance < 0.5 {
 		return nil, response.BadRequest("Minimum trip distance is 0.5 km")
 	}
-	estimatedSpeedKmh := 40.0
-	estimatedDurationHours := distance / estimatedSpeedKmh
-	estimatedDurationSeconds := int(estimatedDurationHours * 3600)
+	route := s.router.Route(ctx, req.PickupLat, req.PickupLon, req.DropoffLat, req.DropoffLon)
+	estimatedDurationSeconds := route.DurationSeconds
 
 	estimatedPickupETA := 5 * 60
 
@@ -769,13 +920,13 @@ func (s *service) CalculateETAEstimate(ctx context.Context, req dto.ETAEstimateR
 		PickupLon:           req.PickupLon,
 		DropoffLat:          req.DropoffLat,
 		DropoffLon:          req.DropoffLon,
-		DistanceKm:          distance,
+		DistanceKm:          route.DistanceKm,
 		DurationSeconds:     estimatedDurationSeconds,
 		EstimatedPickupETA:  estimatedPickupETA,
 		EstimatedDropoffETA: estimatedDropoffETA,
 		TrafficCondition:    "normal",
 		TrafficMultiplier:   1.0,
-		Source:              "calculated",
+		Source:              route.Source,
 	}
 
 	if err := s.repo.CreateETAEstimate(ctx, eta); err != nil {
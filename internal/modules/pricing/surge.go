@@ -149,6 +149,8 @@ func (m *SurgeManager) CalculateCombinedSurge(ctx context.Context, vehicleTypeID
 		reason = "demand_based"
 	}
 
+	m.recordSnapshot(vehicleTypeID, geohash, lat, lon, combined, timeSurge, demandSurge, reason)
+
 	return combined, timeSurge, demandSurge, reason, nil
 }
 
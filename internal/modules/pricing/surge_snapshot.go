@@ -0,0 +1,58 @@
+package pricing
+
+import (
+	"context"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+// Surge is recalculated on every quote, so snapshots are sampled: at most one
+// per geohash cell and vehicle type per interval.
+const (
+	surgeSnapshotKeyPrefix = "pricing:surge:snapshot:"
+	surgeSnapshotInterval  = 5 * time.Minute
+)
+
+func (m *SurgeManager) recordSnapshot(vehicleTypeID, geohash string, lat, lon, combined, timeSurge, demandSurge float64, reason string) {
+	if geohash == "" || cache.CacheClient == nil {
+		return
+	}
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error("panic in surge snapshot goroutine", "error", r, "geohash", geohash)
+			}
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		key := surgeSnapshotKeyPrefix + geohash + ":" + vehicleTypeID
+		claimed, err := cache.CacheClient.SetNX(ctx, key, 1, surgeSnapshotInterval).Result()
+		if err != nil || !claimed {
+			return
+		}
+
+		snapshot := &models.SurgeSnapshot{
+			Geohash:          geohash,
+			Lat:              lat,
+			Lon:              lon,
+			Multiplier:       combined,
+			TimeMultiplier:   timeSurge,
+			DemandMultiplier: demandSurge,
+			Reason:           reason,
+			RecordedAt:       time.Now(),
+		}
+		if vehicleTypeID != "" {
+			snapshot.VehicleTypeID = &vehicleTypeID
+		}
+
+		if err := m.repo.CreateSurgeSnapshot(ctx, snapshot); err != nil {
+			logger.Warn("failed to record surge snapshot", "error", err, "geohash", geohash)
+		}
+	}()
+}
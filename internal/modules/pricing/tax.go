@@ -0,0 +1,395 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/pricing/dto"
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/utils/location"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+// Tax regions and rates change rarely and are read on every fare, so the
+// active set is cached and dropped whenever an admin edits it.
+const (
+	taxRegionsCacheKey = "pricing:tax:regions"
+	taxRegionsCacheTTL = 5 * time.Minute
+)
+
+// CalculateTax prices the taxes owed on an amount charged at a location. The
+// smallest region covering the location wins; the default region is used when
+// none does, and no tax is charged when there is no default either.
+func (s *service) CalculateTax(ctx context.Context, req dto.CalculateTaxRequest) (*dto.TaxCalculationResponse, error) {
+	taxable := roundMoney(req.Amount)
+	result := &dto.TaxCalculationResponse{
+		AppliesTo:     req.AppliesTo,
+		TaxableAmount: taxable,
+		Taxes:         []dto.TaxLine{},
+		TotalWithTax:  taxable,
+		Lines:         models.TaxLines{},
+	}
+
+	region, err := s.resolveTaxRegion(ctx, req.Lat, req.Lon)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to calculate tax", err)
+	}
+	if region == nil || taxable <= 0 {
+		return result, nil
+	}
+
+	result.RegionCode = region.Code
+	result.RegionName = region.Name
+
+	now := time.Now()
+	for i := range region.Rates {
+		rate := &region.Rates[i]
+		if !rate.AppliesAt(req.AppliesTo, now) {
+			continue
+		}
+		result.Lines = append(result.Lines, models.TaxLine{
+			Code:          rate.Code,
+			Name:          rate.Name,
+			Rate:          rate.Rate,
+			TaxableAmount: taxable,
+			Amount:        rate.Calculate(taxable),
+			RegionCode:    region.Code,
+		})
+	}
+
+	result.Taxes = dto.ToTaxLines(result.Lines)
+	result.TaxTotal = result.Lines.Total()
+	result.TotalWithTax = roundMoney(taxable + result.TaxTotal)
+
+	return result, nil
+}
+
+// estimateTax is CalculateTax for quotes: a lookup failure is logged and the
+// quote is returned without tax rather than failing the request.
+func (s *service) estimateTax(ctx context.Context, lat, lon, amount float64, product string) *dto.TaxCalculationResponse {
+	result, err := s.CalculateTax(ctx, dto.CalculateTaxRequest{Lat: lat, Lon: lon, Amount: amount, AppliesTo: product})
+	if err != nil {
+		logger.Warn("tax calculation failed, quoting without tax", "error", err, "product", product)
+		return &dto.TaxCalculationResponse{
+			AppliesTo:     product,
+			TaxableAmount: roundMoney(amount),
+			Taxes:         []dto.TaxLine{},
+			TotalWithTax:  roundMoney(amount),
+		}
+	}
+	return result
+}
+
+func (s *service) resolveTaxRegion(ctx context.Context, lat, lon float64) (*models.TaxRegion, error) {
+	regions, err := s.activeTaxRegions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var match, fallback *models.TaxRegion
+	for _, region := range regions {
+		if region.IsDefault {
+			fallback = region
+			continue
+		}
+		if region.RadiusKm <= 0 {
+			continue
+		}
+		if location.HaversineDistance(lat, lon, region.CenterLat, region.CenterLon) > region.RadiusKm {
+			continue
+		}
+		if match == nil || region.RadiusKm < match.RadiusKm {
+			match = region
+		}
+	}
+
+	if match != nil {
+		return match, nil
+	}
+	return fallback, nil
+}
+
+func (s *service) activeTaxRegions(ctx context.Context) ([]*models.TaxRegion, error) {
+	var regions []*models.TaxRegion
+	if err := cache.GetJSON(ctx, taxRegionsCacheKey, &regions); err == nil {
+		return regions, nil
+	}
+
+	regions, err := s.repo.ListTaxRegions(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	cache.SetJSON(ctx, taxRegionsCacheKey, regions, taxRegionsCacheTTL)
+	return regions, nil
+}
+
+func (s *service) invalidateTaxRegions(ctx context.Context) {
+	if err := cache.Delete(ctx, taxRegionsCacheKey); err != nil {
+		logger.Warn("failed to invalidate tax region cache", "error", err)
+	}
+}
+
+func (s *service) ListTaxRegions(ctx context.Context) ([]*dto.TaxRegionResponse, error) {
+	regions, err := s.repo.ListTaxRegions(ctx, false)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to list tax regions", err)
+	}
+
+	result := make([]*dto.TaxRegionResponse, 0, len(regions))
+	for _, region := range regions {
+		result = append(result, dto.ToTaxRegionResponse(region))
+	}
+	return result, nil
+}
+
+func (s *service) CreateTaxRegion(ctx context.Context, req dto.CreateTaxRegionRequest) (*dto.TaxRegionResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	code := strings.ToLower(strings.TrimSpace(req.Code))
+	exists, err := s.repo.TaxRegionCodeExists(ctx, code)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to create tax region", err)
+	}
+	if exists {
+		return nil, response.ConflictError(fmt.Sprintf("Tax region '%s' already exists", code))
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	region := &models.TaxRegion{
+		Code:      code,
+		Name:      strings.TrimSpace(req.Name),
+		CenterLat: req.CenterLat,
+		CenterLon: req.CenterLon,
+		RadiusKm:  req.RadiusKm,
+		IsDefault: req.IsDefault,
+		IsActive:  isActive,
+	}
+
+	if region.IsDefault {
+		if err := s.repo.ClearDefaultTaxRegion(ctx, ""); err != nil {
+			return nil, response.InternalServerError("Failed to create tax region", err)
+		}
+	}
+	if err := s.repo.CreateTaxRegion(ctx, region); err != nil {
+		return nil, response.InternalServerError("Failed to create tax region", err)
+	}
+	// The column defaults to active, so an inactive region needs a second write.
+	if !isActive {
+		region.IsActive = false
+		if err := s.repo.UpdateTaxRegion(ctx, region); err != nil {
+			return nil, response.InternalServerError("Failed to create tax region", err)
+		}
+	}
+
+	s.invalidateTaxRegions(ctx)
+	logger.Info("tax region created", "regionID", region.ID, "code", region.Code, "isDefault", region.IsDefault)
+
+	return dto.ToTaxRegionResponse(region), nil
+}
+
+func (s *service) UpdateTaxRegion(ctx context.Context, id string, req dto.UpdateTaxRegionRequest) (*dto.TaxRegionResponse, error) {
+	region, err := s.getTaxRegion(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		region.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.CenterLat != nil {
+		region.CenterLat = *req.CenterLat
+	}
+	if req.CenterLon != nil {
+		region.CenterLon = *req.CenterLon
+	}
+	if req.RadiusKm != nil {
+		region.RadiusKm = *req.RadiusKm
+	}
+	if req.IsDefault != nil {
+		region.IsDefault = *req.IsDefault
+	}
+	if req.IsActive != nil {
+		region.IsActive = *req.IsActive
+	}
+
+	if !region.IsDefault && region.RadiusKm <= 0 {
+		return nil, response.BadRequest("radius must be greater than 0 unless the region is the default")
+	}
+
+	if region.IsDefault {
+		if err := s.repo.ClearDefaultTaxRegion(ctx, region.ID); err != nil {
+			return nil, response.InternalServerError("Failed to update tax region", err)
+		}
+	}
+	if err := s.repo.UpdateTaxRegion(ctx, region); err != nil {
+		return nil, response.InternalServerError("Failed to update tax region", err)
+	}
+
+	s.invalidateTaxRegions(ctx)
+	logger.Info("tax region updated", "regionID", region.ID, "code", region.Code)
+
+	return dto.ToTaxRegionResponse(region), nil
+}
+
+func (s *service) DeleteTaxRegion(ctx context.Context, id string) error {
+	region, err := s.getTaxRegion(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteTaxRegion(ctx, region.ID); err != nil {
+		return response.InternalServerError("Failed to delete tax region", err)
+	}
+
+	s.invalidateTaxRegions(ctx)
+	logger.Info("tax region deleted", "regionID", region.ID, "code", region.Code)
+
+	return nil
+}
+
+func (s *service) CreateTaxRate(ctx context.Context, regionID string, req dto.CreateTaxRateRequest) (*dto.TaxRateResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	region, err := s.getTaxRegion(ctx, regionID)
+	if err != nil {
+		return nil, err
+	}
+
+	appliesTo := req.AppliesTo
+	if appliesTo == "" {
+		appliesTo = models.TaxAppliesToAll
+	}
+	code := strings.ToLower(strings.TrimSpace(req.Code))
+
+	exists, err := s.repo.TaxRateCodeExists(ctx, region.ID, code, appliesTo)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to create tax rate", err)
+	}
+	if exists {
+		return nil, response.ConflictError(fmt.Sprintf("Tax rate '%s' already exists for %s in this region", code, appliesTo))
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	rate := &models.TaxRate{
+		RegionID:       region.ID,
+		Code:           code,
+		Name:           strings.TrimSpace(req.Name),
+		Rate:           req.Rate,
+		AppliesTo:      appliesTo,
+		IsActive:       isActive,
+		EffectiveFrom:  req.EffectiveFrom,
+		EffectiveUntil: req.EffectiveUntil,
+	}
+
+	if err := s.repo.CreateTaxRate(ctx, rate); err != nil {
+		return nil, response.InternalServerError("Failed to create tax rate", err)
+	}
+	if !isActive {
+		rate.IsActive = false
+		if err := s.repo.UpdateTaxRate(ctx, rate); err != nil {
+			return nil, response.InternalServerError("Failed to create tax rate", err)
+		}
+	}
+
+	s.invalidateTaxRegions(ctx)
+	logger.Info("tax rate created",
+		"rateID", rate.ID,
+		"region", region.Code,
+		"code", rate.Code,
+		"rate", rate.Rate,
+		"appliesTo", rate.AppliesTo,
+	)
+
+	return dto.ToTaxRateResponse(rate), nil
+}
+
+func (s *service) UpdateTaxRate(ctx context.Context, id string, req dto.UpdateTaxRateRequest) (*dto.TaxRateResponse, error) {
+	rate, err := s.repo.GetTaxRate(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Tax rate")
+		}
+		return nil, response.InternalServerError("Failed to load tax rate", err)
+	}
+
+	if req.Name != nil {
+		rate.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.Rate != nil {
+		rate.Rate = *req.Rate
+	}
+	if req.AppliesTo != nil {
+		rate.AppliesTo = *req.AppliesTo
+	}
+	if req.EffectiveFrom != nil {
+		rate.EffectiveFrom = req.EffectiveFrom
+	}
+	if req.EffectiveUntil != nil {
+		rate.EffectiveUntil = req.EffectiveUntil
+	}
+	if req.IsActive != nil {
+		rate.IsActive = *req.IsActive
+	}
+
+	if err := dto.ValidateTaxRateWindow(rate.EffectiveFrom, rate.EffectiveUntil); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	if err := s.repo.UpdateTaxRate(ctx, rate); err != nil {
+		return nil, response.InternalServerError("Failed to update tax rate", err)
+	}
+
+	s.invalidateTaxRegions(ctx)
+	logger.Info("tax rate updated", "rateID", rate.ID, "code", rate.Code, "rate", rate.Rate)
+
+	return dto.ToTaxRateResponse(rate), nil
+}
+
+func (s *service) DeleteTaxRate(ctx context.Context, id string) error {
+	rate, err := s.repo.GetTaxRate(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return response.NotFoundError("Tax rate")
+		}
+		return response.InternalServerError("Failed to load tax rate", err)
+	}
+
+	if err := s.repo.DeleteTaxRate(ctx, rate.ID); err != nil {
+		return response.InternalServerError("Failed to delete tax rate", err)
+	}
+
+	s.invalidateTaxRegions(ctx)
+	logger.Info("tax rate deleted", "rateID", rate.ID, "code", rate.Code)
+
+	return nil
+}
+
+func (s *service) getTaxRegion(ctx context.Context, id string) (*models.TaxRegion, error) {
+	region, err := s.repo.GetTaxRegion(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Tax region")
+		}
+		return nil, response.InternalServerError("Failed to load tax region", err)
+	}
+	return region, nil
+}
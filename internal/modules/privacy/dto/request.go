@@ -0,0 +1,23 @@
+package dto
+
+type RequestDeletionRequest struct {
+	Reason string `json:"reason" binding:"omitempty,max=1000"`
+}
+
+type ListPrivacyRequestsRequest struct {
+	// UserID filters by user; it is only honoured for admins.
+	UserID string `form:"userId" binding:"omitempty,uuid"`
+	Type   string `form:"type" binding:"omitempty,oneof=export deletion"`
+	Status string `form:"status" binding:"omitempty,oneof=pending processing completed failed cancelled"`
+	Page   int    `form:"page" binding:"omitempty,min=1"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (r *ListPrivacyRequestsRequest) SetDefaults() {
+	if r.Page <= 0 {
+		r.Page = 1
+	}
+	if r.Limit <= 0 {
+		r.Limit = 20
+	}
+}
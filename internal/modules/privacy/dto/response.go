@@ -0,0 +1,56 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+type PrivacyRequestResponse struct {
+	ID           string    `json:"id"`
+	UserID       string    `json:"userId"`
+	Type         string    `json:"type"`
+	Status       string    `json:"status"`
+	Reason       string    `json:"reason,omitempty"`
+	ScheduledFor time.Time `json:"scheduledFor"`
+	Attempts     int       `json:"attempts"`
+	LastError    string    `json:"lastError,omitempty"`
+	ArchiveSize  int64     `json:"archiveSize,omitempty"`
+	// Downloadable is true while a completed export's archive is available.
+	Downloadable bool       `json:"downloadable"`
+	ExpiresAt    *time.Time `json:"expiresAt,omitempty"`
+	StartedAt    *time.Time `json:"startedAt,omitempty"`
+	CompletedAt  *time.Time `json:"completedAt,omitempty"`
+	CancelledAt  *time.Time `json:"cancelledAt,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+}
+
+type ExportDownloadResponse struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// ToPrivacyRequestResponse maps a request for its owner; internal failure
+// details are only included for admins.
+func ToPrivacyRequestResponse(r *models.PrivacyRequest, isAdmin bool) *PrivacyRequestResponse {
+	resp := &PrivacyRequestResponse{
+		ID:           r.ID,
+		UserID:       r.UserID,
+		Type:         string(r.Type),
+		Status:       string(r.Status),
+		Reason:       r.Reason,
+		ScheduledFor: r.ScheduledFor,
+		Attempts:     r.Attempts,
+		ArchiveSize:  r.ArchiveSize,
+		Downloadable: r.Status == models.PrivacyRequestCompleted && r.ArchiveKey != nil,
+		ExpiresAt:    r.ExpiresAt,
+		StartedAt:    r.StartedAt,
+		CompletedAt:  r.CompletedAt,
+		CancelledAt:  r.CancelledAt,
+		CreatedAt:    r.CreatedAt,
+	}
+	if isAdmin {
+		resp.LastError = r.LastError
+	}
+	return resp
+}
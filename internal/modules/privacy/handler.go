@@ -0,0 +1,160 @@
+package privacy
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/modules/privacy/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// RequestExport godoc
+// @Summary Request a copy of your data
+// @Description Queues a JSON archive of the profile, rides, orders and wallet transactions. The user is notified when it is ready to download
+// @Tags privacy
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=dto.PrivacyRequestResponse}
+// @Router /privacy/exports [post]
+func (h *Handler) RequestExport(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	req, err := h.service.RequestExport(c.Request.Context(), userID.(string))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, req, "Data export requested successfully")
+}
+
+// DownloadExport godoc
+// @Summary Get a download link for a data export
+// @Description Returns a short-lived link to the export archive
+// @Tags privacy
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Privacy request ID"
+// @Success 200 {object} response.Response{data=dto.ExportDownloadResponse}
+// @Router /privacy/exports/{id}/download [get]
+func (h *Handler) DownloadExport(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	download, err := h.service.GetExportDownload(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, download, "Download link created successfully")
+}
+
+// RequestDeletion godoc
+// @Summary Request deletion of your account
+// @Description Schedules the account for deletion after a grace period during which it can be cancelled. Personal data is then removed and ride and order history anonymized
+// @Tags privacy
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.RequestDeletionRequest false "Reason"
+// @Success 200 {object} response.Response{data=dto.PrivacyRequestResponse}
+// @Router /privacy/deletion [post]
+func (h *Handler) RequestDeletion(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req dto.RequestDeletionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+			return
+		}
+	}
+
+	deletion, err := h.service.RequestDeletion(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, deletion, "Account deletion scheduled successfully")
+}
+
+// CancelDeletion godoc
+// @Summary Cancel a scheduled account deletion
+// @Tags privacy
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=dto.PrivacyRequestResponse}
+// @Router /privacy/deletion [delete]
+func (h *Handler) CancelDeletion(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	deletion, err := h.service.CancelDeletion(c.Request.Context(), userID.(string))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, deletion, "Account deletion cancelled successfully")
+}
+
+// ListRequests godoc
+// @Summary List privacy requests
+// @Description Users see their own requests, admins see all requests and may filter by user
+// @Tags privacy
+// @Security BearerAuth
+// @Produce json
+// @Param userId query string false "Filter by user (admin only)"
+// @Param type query string false "Filter by type (export, deletion)"
+// @Param status query string false "Filter by status"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Response{data=[]dto.PrivacyRequestResponse}
+// @Router /privacy/requests [get]
+func (h *Handler) ListRequests(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+
+	var req dto.ListPrivacyRequestsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+	req.SetDefaults()
+
+	requests, total, err := h.service.ListRequests(c.Request.Context(), userID.(string), role == "admin", req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pagination := response.NewPaginationMeta(total, req.Page, req.Limit)
+	response.Paginated(c, requests, pagination, "Privacy requests retrieved successfully")
+}
+
+// GetRequest godoc
+// @Summary Get a privacy request
+// @Tags privacy
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Privacy request ID"
+// @Success 200 {object} response.Response{data=dto.PrivacyRequestResponse}
+// @Router /privacy/requests/{id} [get]
+func (h *Handler) GetRequest(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+
+	req, err := h.service.GetRequest(c.Request.Context(), userID.(string), c.Param("id"), role == "admin")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, req, "Privacy request retrieved successfully")
+}
@@ -0,0 +1,322 @@
+package privacy
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/archive"
+	"gorm.io/gorm"
+)
+
+// A request left in processing longer than this is assumed to belong to a
+// worker that died and is claimed again.
+const staleProcessingAfter = time.Hour
+
+var (
+	openRideStatuses  = []string{"searching", "accepted", "arrived", "started"}
+	openOrderStatuses = []string{"pending", "searching_provider", "assigned", "accepted", "in_progress"}
+)
+
+type Repository interface {
+	Create(ctx context.Context, r *models.PrivacyRequest) error
+	FindByID(ctx context.Context, id string) (*models.PrivacyRequest, error)
+	FindOpen(ctx context.Context, userID string, reqType models.PrivacyRequestType) (*models.PrivacyRequest, error)
+	Update(ctx context.Context, r *models.PrivacyRequest) error
+	// CancelPending cancels the request unless a worker has already claimed it.
+	CancelPending(ctx context.Context, id string, now time.Time) (bool, error)
+	List(ctx context.Context, userID, reqType, status string, page, limit int) ([]*models.PrivacyRequest, int64, error)
+
+	// ClaimDue marks up to limit due requests as processing and returns them.
+	// Rows locked by another worker are skipped.
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]*models.PrivacyRequest, error)
+	FindExpiredExports(ctx context.Context, now time.Time, limit int) ([]*models.PrivacyRequest, error)
+	ListArchivedExports(ctx context.Context, userID string) ([]*models.PrivacyRequest, error)
+
+	FindUser(ctx context.Context, userID string) (*models.User, error)
+	ListRides(ctx context.Context, userID string) ([]*models.Ride, error)
+	ListOrders(ctx context.Context, userID string) ([]*models.ServiceOrderNew, error)
+	ListWalletTransactions(ctx context.Context, userID string) ([]*models.WalletTransaction, error)
+
+	// CountOpenWork counts rides and orders of the user that are not finished.
+	CountOpenWork(ctx context.Context, userID string) (int64, error)
+	// AnonymizeUser strips personal data from the user, their ride and order
+	// history, live and archived, and deletes data kept only for their
+	// convenience. Financial records are kept for retention.
+	AnonymizeUser(ctx context.Context, userID string) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Create(ctx context.Context, req *models.PrivacyRequest) error {
+	return r.db.WithContext(ctx).Create(req).Error
+}
+
+func (r *repository) FindByID(ctx context.Context, id string) (*models.PrivacyRequest, error) {
+	var req models.PrivacyRequest
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&req).Error
+	if err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func (r *repository) FindOpen(ctx context.Context, userID string, reqType models.PrivacyRequestType) (*models.PrivacyRequest, error) {
+	var req models.PrivacyRequest
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND type = ? AND status IN ?", userID, reqType,
+			[]models.PrivacyRequestStatus{models.PrivacyRequestPending, models.PrivacyRequestProcessing}).
+		First(&req).Error
+	if err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func (r *repository) Update(ctx context.Context, req *models.PrivacyRequest) error {
+	return r.db.WithContext(ctx).Save(req).Error
+}
+
+func (r *repository) CancelPending(ctx context.Context, id string, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.PrivacyRequest{}).
+		Where("id = ? AND status = ?", id, models.PrivacyRequestPending).
+		Updates(map[string]interface{}{
+			"status":       models.PrivacyRequestCancelled,
+			"cancelled_at": now,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *repository) List(ctx context.Context, userID, reqType, status string, page, limit int) ([]*models.PrivacyRequest, int64, error) {
+	var requests []*models.PrivacyRequest
+	var total int64
+
+	base := r.db.WithContext(ctx).Model(&models.PrivacyRequest{})
+	if userID != "" {
+		base = base.Where("user_id = ?", userID)
+	}
+	if reqType != "" {
+		base = base.Where("type = ?", reqType)
+	}
+	if status != "" {
+		base = base.Where("status = ?", status)
+	}
+
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count privacy requests: %w", err)
+	}
+
+	if total == 0 {
+		return []*models.PrivacyRequest{}, 0, nil
+	}
+
+	offset := (page - 1) * limit
+	if err := base.
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&requests).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch privacy requests: %w", err)
+	}
+
+	return requests, total, nil
+}
+
+func (r *repository) ClaimDue(ctx context.Context, now time.Time, limit int) ([]*models.PrivacyRequest, error) {
+	var claimed []*models.PrivacyRequest
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []string
+		err := tx.Raw(`
+			SELECT id FROM privacy_requests
+			WHERE (status = ? AND scheduled_for <= ?)
+			   OR (status = ? AND started_at < ?)
+			ORDER BY scheduled_for
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		`, models.PrivacyRequestPending, now, models.PrivacyRequestProcessing, now.Add(-staleProcessingAfter), limit).
+			Scan(&ids).Error
+		if err != nil {
+			return fmt.Errorf("failed to select due privacy requests: %w", err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		err = tx.Model(&models.PrivacyRequest{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{
+				"status":     models.PrivacyRequestProcessing,
+				"started_at": now,
+				"attempts":   gorm.Expr("attempts + 1"),
+			}).Error
+		if err != nil {
+			return fmt.Errorf("failed to claim privacy requests: %w", err)
+		}
+
+		return tx.Where("id IN ?", ids).Order("scheduled_for").Find(&claimed).Error
+	})
+	return claimed, err
+}
+
+func (r *repository) FindExpiredExports(ctx context.Context, now time.Time, limit int) ([]*models.PrivacyRequest, error) {
+	var requests []*models.PrivacyRequest
+	err := r.db.WithContext(ctx).
+		Where("type = ? AND archive_key IS NOT NULL AND expires_at <= ?", models.PrivacyRequestExport, now).
+		Order("expires_at").
+		Limit(limit).
+		Find(&requests).Error
+	return requests, err
+}
+
+func (r *repository) ListArchivedExports(ctx context.Context, userID string) ([]*models.PrivacyRequest, error) {
+	var requests []*models.PrivacyRequest
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND type = ? AND archive_key IS NOT NULL", userID, models.PrivacyRequestExport).
+		Find(&requests).Error
+	return requests, err
+}
+
+func (r *repository) FindUser(ctx context.Context, userID string) (*models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).Where("id = ?", userID).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *repository) ListRides(ctx context.Context, userID string) ([]*models.Ride, error) {
+	var rides []*models.Ride
+	err := archive.WithArchived(r.db.WithContext(ctx).Model(&models.Ride{}), "rides").
+		Where("rider_id = ? OR driver_id = ?", userID, userID).
+		Order("requested_at DESC").
+		Find(&rides).Error
+	return rides, err
+}
+
+func (r *repository) ListOrders(ctx context.Context, userID string) ([]*models.ServiceOrderNew, error) {
+	var orders []*models.ServiceOrderNew
+	err := archive.WithArchived(r.db.WithContext(ctx).Model(&models.ServiceOrderNew{}), "service_orders").
+		Where("customer_id = ?", userID).
+		Order("created_at DESC").
+		Find(&orders).Error
+	return orders, err
+}
+
+func (r *repository) ListWalletTransactions(ctx context.Context, userID string) ([]*models.WalletTransaction, error) {
+	var transactions []*models.WalletTransaction
+	err := r.db.WithContext(ctx).
+		Where("wallet_id IN (?)", r.db.Model(&models.Wallet{}).Select("id").Where("user_id = ?", userID)).
+		Order("created_at DESC").
+		Find(&transactions).Error
+	return transactions, err
+}
+
+func (r *repository) CountOpenWork(ctx context.Context, userID string) (int64, error) {
+	var rides, orders int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Ride{}).
+		Where("(rider_id = ? OR driver_id = ?) AND status IN ?", userID, userID, openRideStatuses).
+		Count(&rides).Error
+	if err != nil {
+		return 0, err
+	}
+	err = r.db.WithContext(ctx).
+		Model(&models.ServiceOrderNew{}).
+		Where("customer_id = ? AND status IN ?", userID, openOrderStatuses).
+		Count(&orders).Error
+	if err != nil {
+		return 0, err
+	}
+	return rides + orders, nil
+}
+
+func (r *repository) AnonymizeUser(ctx context.Context, userID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Coordinates are rounded to two decimals (about a kilometre) so trip
+		// history still feeds city-level reporting without pinpointing homes.
+		for _, table := range []string{"rides", archive.RidesArchiveTable} {
+			err := tx.Exec(fmt.Sprintf(`
+				UPDATE %s SET
+					pickup_address = '',
+					dropoff_address = '',
+					rider_notes = '',
+					pickup_lat = ROUND(pickup_lat, 2),
+					pickup_lon = ROUND(pickup_lon, 2),
+					dropoff_lat = ROUND(dropoff_lat, 2),
+					dropoff_lon = ROUND(dropoff_lon, 2),
+					pickup_location = ST_SetSRID(ST_MakePoint(ROUND(pickup_lon, 2), ROUND(pickup_lat, 2)), 4326),
+					dropoff_location = ST_SetSRID(ST_MakePoint(ROUND(dropoff_lon, 2), ROUND(dropoff_lat, 2)), 4326)
+				WHERE rider_id = ?
+			`, table), userID).Error
+			if err != nil {
+				return fmt.Errorf("failed to anonymize %s: %w", table, err)
+			}
+		}
+
+		for _, table := range []string{"service_orders", archive.ServiceOrdersArchiveTable} {
+			err := tx.Exec(fmt.Sprintf(`
+				UPDATE %s SET
+					customer_info = jsonb_build_object(
+						'name', 'Deleted user',
+						'phone', '',
+						'email', '',
+						'address', '',
+						'lat', ROUND((customer_info->>'lat')::numeric, 2),
+						'lng', ROUND((customer_info->>'lng')::numeric, 2)
+					),
+					special_notes = '',
+					customer_review = ''
+				WHERE customer_id = ?
+			`, table), userID).Error
+			if err != nil {
+				return fmt.Errorf("failed to anonymize %s: %w", table, err)
+			}
+		}
+
+		deletes := []struct {
+			table  string
+			column string
+		}{
+			{"saved_locations", "user_id"},
+			{"favorite_drivers", "rider_id"},
+			{"preferred_providers", "customer_id"},
+			{"saved_payment_methods", "user_id"},
+			{"push_tokens", "user_id"},
+			{"user_events", "user_id"},
+		}
+		for _, d := range deletes {
+			if err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s = ?", d.table, d.column), userID).Error; err != nil {
+				return fmt.Errorf("failed to delete %s: %w", d.table, err)
+			}
+		}
+
+		err := tx.Model(&models.User{}).
+			Where("id = ?", userID).
+			Updates(map[string]interface{}{
+				"name":                    "Deleted user",
+				"email":                   nil,
+				"phone":                   nil,
+				"gender":                  nil,
+				"dob":                     nil,
+				"password":                nil,
+				"profile_photo_url":       nil,
+				"emergency_contact_name":  "",
+				"emergency_contact_phone": "",
+				"referral_code":           nil,
+				"deleted_at":              time.Now(),
+			}).Error
+		if err != nil {
+			return fmt.Errorf("failed to anonymize user: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,20 @@
+package privacy
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
+	privacy := router.Group("/privacy")
+	privacy.Use(authMiddleware)
+	{
+		privacy.GET("/requests", handler.ListRequests)
+		privacy.GET("/requests/:id", handler.GetRequest)
+
+		privacy.POST("/exports", handler.RequestExport)
+		privacy.GET("/exports/:id/download", handler.DownloadExport)
+
+		privacy.POST("/deletion", handler.RequestDeletion)
+		privacy.DELETE("/deletion", handler.CancelDeletion)
+	}
+}
@@ -0,0 +1,450 @@
+package privacy
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/privacy/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+	websocketutil "github.com/umar5678/go-backend/internal/websocket/websocketutils"
+	"gorm.io/gorm"
+)
+
+const (
+	// Failed requests are retried with a growing delay up to this many times.
+	maxAttempts = 5
+	// A deletion is put off by this much while the user still has a ride or
+	// order in progress.
+	openWorkRetryDelay = time.Hour
+	downloadURLExpiry  = 15 * time.Minute
+	claimBatchSize     = 20
+	purgeBatchSize     = 100
+)
+
+// ObjectStore holds export archives. It is satisfied by storage.Client.
+type ObjectStore interface {
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	Delete(ctx context.Context, key string) error
+	PresignGet(key string, expires time.Duration) string
+}
+
+// SessionRevoker signs a user out everywhere. It is satisfied by auth.Service.
+type SessionRevoker interface {
+	LogoutAll(ctx context.Context, userID string) (int, error)
+}
+
+type Service interface {
+	RequestExport(ctx context.Context, userID string) (*dto.PrivacyRequestResponse, error)
+	GetExportDownload(ctx context.Context, userID, requestID string) (*dto.ExportDownloadResponse, error)
+
+	RequestDeletion(ctx context.Context, userID string, req dto.RequestDeletionRequest) (*dto.PrivacyRequestResponse, error)
+	CancelDeletion(ctx context.Context, userID string) (*dto.PrivacyRequestResponse, error)
+
+	GetRequest(ctx context.Context, userID, requestID string, isAdmin bool) (*dto.PrivacyRequestResponse, error)
+	ListRequests(ctx context.Context, userID string, isAdmin bool, req dto.ListPrivacyRequestsRequest) ([]*dto.PrivacyRequestResponse, int64, error)
+
+	// ProcessDue carries out the requests whose time has come and returns how
+	// many completed.
+	ProcessDue(ctx context.Context) (int, error)
+	// PurgeExpiredExports deletes export archives past their expiry.
+	PurgeExpiredExports(ctx context.Context) (int, error)
+
+	SetSessionRevoker(revoker SessionRevoker)
+}
+
+type service struct {
+	repo     Repository
+	store    ObjectStore
+	sessions SessionRevoker
+	cfg      config.PrivacyConfig
+}
+
+// NewService builds the privacy service. store may be nil when object storage
+// is not configured, in which case exports are unavailable.
+func NewService(repo Repository, store ObjectStore, cfg config.PrivacyConfig) Service {
+	return &service{repo: repo, store: store, cfg: cfg}
+}
+
+func (s *service) SetSessionRevoker(revoker SessionRevoker) {
+	s.sessions = revoker
+}
+
+func (s *service) RequestExport(ctx context.Context, userID string) (*dto.PrivacyRequestResponse, error) {
+	if s.store == nil {
+		return nil, response.ServiceUnavailable("Data exports are not available right now")
+	}
+	if _, err := s.repo.FindOpen(ctx, userID, models.PrivacyRequestDeletion); err == nil {
+		return nil, response.ConflictError("Your account is scheduled for deletion; cancel the deletion to request an export")
+	}
+	if _, err := s.repo.FindOpen(ctx, userID, models.PrivacyRequestExport); err == nil {
+		return nil, response.ConflictError("A data export is already being prepared")
+	}
+
+	req := &models.PrivacyRequest{
+		UserID:       userID,
+		Type:         models.PrivacyRequestExport,
+		Status:       models.PrivacyRequestPending,
+		ScheduledFor: time.Now(),
+	}
+	if err := s.repo.Create(ctx, req); err != nil {
+		logger.Error("failed to create export request", "error", err, "userID", userID)
+		return nil, response.InternalServerError("Failed to request data export", err)
+	}
+
+	logger.Info("data export requested", "requestID", req.ID, "userID", userID)
+	return dto.ToPrivacyRequestResponse(req, false), nil
+}
+
+func (s *service) GetExportDownload(ctx context.Context, userID, requestID string) (*dto.ExportDownloadResponse, error) {
+	if s.store == nil {
+		return nil, response.ServiceUnavailable("Data exports are not available right now")
+	}
+	req, err := s.getRequestForUser(ctx, userID, requestID, false)
+	if err != nil {
+		return nil, err
+	}
+	if req.Type != models.PrivacyRequestExport {
+		return nil, response.BadRequest("Only data exports can be downloaded")
+	}
+	if req.Status != models.PrivacyRequestCompleted {
+		return nil, response.ConflictError("The data export is not ready yet")
+	}
+	if req.ArchiveKey == nil {
+		return nil, response.NewAppError(http.StatusGone, "The data export has expired; request a new one", "EXPORT_EXPIRED", nil, nil)
+	}
+
+	expiry := downloadURLExpiry
+	if req.ExpiresAt != nil {
+		if remaining := time.Until(*req.ExpiresAt); remaining < expiry {
+			expiry = remaining
+		}
+	}
+	return &dto.ExportDownloadResponse{
+		URL:       s.store.PresignGet(*req.ArchiveKey, expiry),
+		ExpiresAt: time.Now().Add(expiry),
+	}, nil
+}
+
+func (s *service) RequestDeletion(ctx context.Context, userID string, req dto.RequestDeletionRequest) (*dto.PrivacyRequestResponse, error) {
+	if _, err := s.repo.FindOpen(ctx, userID, models.PrivacyRequestDeletion); err == nil {
+		return nil, response.ConflictError("Your account is already scheduled for deletion")
+	}
+
+	deletion := &models.PrivacyRequest{
+		UserID:       userID,
+		Type:         models.PrivacyRequestDeletion,
+		Status:       models.PrivacyRequestPending,
+		Reason:       req.Reason,
+		ScheduledFor: time.Now().Add(s.cfg.DeletionGracePeriod),
+	}
+	if err := s.repo.Create(ctx, deletion); err != nil {
+		logger.Error("failed to create deletion request", "error", err, "userID", userID)
+		return nil, response.InternalServerError("Failed to request account deletion", err)
+	}
+
+	logger.Info("account deletion requested", "requestID", deletion.ID, "userID", userID, "scheduledFor", deletion.ScheduledFor)
+	return dto.ToPrivacyRequestResponse(deletion, false), nil
+}
+
+func (s *service) CancelDeletion(ctx context.Context, userID string) (*dto.PrivacyRequestResponse, error) {
+	deletion, err := s.repo.FindOpen(ctx, userID, models.PrivacyRequestDeletion)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Deletion request")
+		}
+		return nil, response.InternalServerError("Failed to fetch deletion request", err)
+	}
+
+	now := time.Now()
+	cancelled, err := s.repo.CancelPending(ctx, deletion.ID, now)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to cancel account deletion", err)
+	}
+	if !cancelled {
+		return nil, response.ConflictError("Your account is already being deleted")
+	}
+	deletion.Status = models.PrivacyRequestCancelled
+	deletion.CancelledAt = &now
+
+	logger.Info("account deletion cancelled", "requestID", deletion.ID, "userID", userID)
+	return dto.ToPrivacyRequestResponse(deletion, false), nil
+}
+
+func (s *service) GetRequest(ctx context.Context, userID, requestID string, isAdmin bool) (*dto.PrivacyRequestResponse, error) {
+	req, err := s.getRequestForUser(ctx, userID, requestID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToPrivacyRequestResponse(req, isAdmin), nil
+}
+
+func (s *service) ListRequests(ctx context.Context, userID string, isAdmin bool, req dto.ListPrivacyRequestsRequest) ([]*dto.PrivacyRequestResponse, int64, error) {
+	filterUserID := userID
+	if isAdmin {
+		filterUserID = req.UserID
+	}
+
+	requests, total, err := s.repo.List(ctx, filterUserID, req.Type, req.Status, req.Page, req.Limit)
+	if err != nil {
+		logger.Error("failed to list privacy requests", "error", err, "userID", userID)
+		return nil, 0, response.InternalServerError("Failed to fetch privacy requests", err)
+	}
+
+	result := make([]*dto.PrivacyRequestResponse, 0, len(requests))
+	for _, r := range requests {
+		result = append(result, dto.ToPrivacyRequestResponse(r, isAdmin))
+	}
+	return result, total, nil
+}
+
+func (s *service) getRequestForUser(ctx context.Context, userID, requestID string, isAdmin bool) (*models.PrivacyRequest, error) {
+	req, err := s.repo.FindByID(ctx, requestID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Privacy request")
+		}
+		return nil, response.InternalServerError("Failed to fetch privacy request", err)
+	}
+	if !isAdmin && req.UserID != userID {
+		return nil, response.NotFoundError("Privacy request")
+	}
+	return req, nil
+}
+
+func (s *service) ProcessDue(ctx context.Context) (int, error) {
+	requests, err := s.repo.ClaimDue(ctx, time.Now(), claimBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	completed := 0
+	for _, req := range requests {
+		var runErr error
+		switch req.Type {
+		case models.PrivacyRequestExport:
+			runErr = s.runExport(ctx, req)
+		case models.PrivacyRequestDeletion:
+			runErr = s.runDeletion(ctx, req)
+		default:
+			runErr = fmt.Errorf("unknown privacy request type %q", req.Type)
+		}
+
+		if errors.Is(runErr, errOpenWork) {
+			s.postpone(ctx, req, openWorkRetryDelay, runErr, false)
+			continue
+		}
+		if runErr != nil {
+			logger.Error("privacy request failed", "error", runErr, "requestID", req.ID, "type", req.Type, "attempt", req.Attempts)
+			s.postpone(ctx, req, time.Duration(req.Attempts*req.Attempts)*time.Minute, runErr, true)
+			continue
+		}
+		completed++
+	}
+
+	if completed > 0 {
+		logger.Info("privacy requests processed", "completed", completed, "claimed", len(requests))
+	}
+	return completed, nil
+}
+
+var errOpenWork = errors.New("user has rides or orders in progress")
+
+// postpone puts a claimed request back in the queue. Counted failures give up
+// once maxAttempts is reached.
+func (s *service) postpone(ctx context.Context, req *models.PrivacyRequest, delay time.Duration, cause error, counted bool) {
+	req.LastError = cause.Error()
+	req.StartedAt = nil
+	req.Status = models.PrivacyRequestPending
+	req.ScheduledFor = time.Now().Add(delay)
+	if !counted {
+		req.Attempts--
+	} else if req.Attempts >= maxAttempts {
+		req.Status = models.PrivacyRequestFailed
+	}
+
+	if err := s.repo.Update(ctx, req); err != nil {
+		logger.Error("failed to requeue privacy request", "error", err, "requestID", req.ID)
+		return
+	}
+	if req.Status == models.PrivacyRequestFailed {
+		s.notifyUser(req, "We could not complete your request. Please contact support.")
+	}
+}
+
+func (s *service) complete(ctx context.Context, req *models.PrivacyRequest) error {
+	now := time.Now()
+	req.Status = models.PrivacyRequestCompleted
+	req.CompletedAt = &now
+	req.LastError = ""
+	return s.repo.Update(ctx, req)
+}
+
+func (s *service) runExport(ctx context.Context, req *models.PrivacyRequest) error {
+	if s.store == nil {
+		return errors.New("object storage is not configured")
+	}
+
+	data, err := s.buildExport(ctx, req.UserID)
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("privacy-exports/%s/%s.zip", req.UserID, req.ID)
+	if err := s.store.Put(ctx, key, "application/zip", data); err != nil {
+		return fmt.Errorf("failed to upload export: %w", err)
+	}
+
+	expiresAt := time.Now().Add(s.cfg.ExportTTL)
+	req.ArchiveKey = &key
+	req.ArchiveSize = int64(len(data))
+	req.ExpiresAt = &expiresAt
+	if err := s.complete(ctx, req); err != nil {
+		if delErr := s.store.Delete(ctx, key); delErr != nil {
+			logger.Warn("failed to remove orphaned export", "error", delErr, "key", key)
+		}
+		return fmt.Errorf("failed to record export: %w", err)
+	}
+
+	logger.Info("data export ready", "requestID", req.ID, "userID", req.UserID, "size", req.ArchiveSize)
+	s.notifyUser(req, "Your data export is ready to download.")
+	return nil
+}
+
+// buildExport collects everything the user can ask for a copy of into a zip
+// of JSON files.
+func (s *service) buildExport(ctx context.Context, userID string) ([]byte, error) {
+	user, err := s.repo.FindUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+	rides, err := s.repo.ListRides(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rides: %w", err)
+	}
+	orders, err := s.repo.ListOrders(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load orders: %w", err)
+	}
+	transactions, err := s.repo.ListWalletTransactions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load wallet transactions: %w", err)
+	}
+
+	files := []struct {
+		name string
+		data interface{}
+	}{
+		{"profile.json", user},
+		{"rides.json", rides},
+		{"orders.json", orders},
+		{"wallet_transactions.json", transactions},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			return nil, err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(f.data); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", f.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *service) runDeletion(ctx context.Context, req *models.PrivacyRequest) error {
+	open, err := s.repo.CountOpenWork(ctx, req.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to check open work: %w", err)
+	}
+	if open > 0 {
+		return errOpenWork
+	}
+
+	if s.store != nil {
+		exports, err := s.repo.ListArchivedExports(ctx, req.UserID)
+		if err != nil {
+			return fmt.Errorf("failed to list exports: %w", err)
+		}
+		for _, export := range exports {
+			if err := s.removeArchive(ctx, export); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := s.repo.AnonymizeUser(ctx, req.UserID); err != nil {
+		return err
+	}
+
+	if s.sessions != nil {
+		if _, err := s.sessions.LogoutAll(ctx, req.UserID); err != nil {
+			logger.Warn("failed to revoke sessions of deleted user", "error", err, "userID", req.UserID)
+		}
+	}
+
+	if err := s.complete(ctx, req); err != nil {
+		return fmt.Errorf("failed to record deletion: %w", err)
+	}
+
+	logger.Info("account deleted", "requestID", req.ID, "userID", req.UserID)
+	return nil
+}
+
+func (s *service) PurgeExpiredExports(ctx context.Context) (int, error) {
+	if s.store == nil {
+		return 0, nil
+	}
+
+	exports, err := s.repo.FindExpiredExports(ctx, time.Now(), purgeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, export := range exports {
+		if err := s.removeArchive(ctx, export); err != nil {
+			logger.Warn("failed to purge expired export", "error", err, "requestID", export.ID)
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+func (s *service) removeArchive(ctx context.Context, export *models.PrivacyRequest) error {
+	if err := s.store.Delete(ctx, *export.ArchiveKey); err != nil {
+		return fmt.Errorf("failed to delete export archive: %w", err)
+	}
+	export.ArchiveKey = nil
+	if err := s.repo.Update(ctx, export); err != nil {
+		return fmt.Errorf("failed to record export removal: %w", err)
+	}
+	return nil
+}
+
+func (s *service) notifyUser(req *models.PrivacyRequest, message string) {
+	websocketutil.SendToUser(req.UserID, websocket.TypePrivacyRequestUpdate, map[string]interface{}{
+		"requestId": req.ID,
+		"type":      req.Type,
+		"status":    req.Status,
+		"message":   message,
+	})
+}
@@ -0,0 +1,49 @@
+package privacy
+
+import (
+	"context"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/services/health"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+// Worker carries out due export and deletion requests and removes expired
+// export archives.
+type Worker struct {
+	service Service
+	cfg     config.PrivacyConfig
+}
+
+func NewWorker(service Service, cfg config.PrivacyConfig) *Worker {
+	return &Worker{service: service, cfg: cfg}
+}
+
+func (w *Worker) Start(ctx context.Context) {
+	heartbeat := health.NewHeartbeat("privacy_requests", w.cfg.WorkerInterval)
+
+	go func() {
+		ticker := time.NewTicker(w.cfg.WorkerInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				runCtx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+				if _, err := w.service.ProcessDue(runCtx); err != nil {
+					logger.Error("privacy worker failed", "error", err)
+				}
+				if _, err := w.service.PurgeExpiredExports(runCtx); err != nil {
+					logger.Error("failed to purge expired exports", "error", err)
+				}
+				cancel()
+				heartbeat.Beat()
+			}
+		}
+	}()
+
+	logger.Info("privacy worker started", "interval", w.cfg.WorkerInterval, "deletionGracePeriod", w.cfg.DeletionGracePeriod)
+}
@@ -18,6 +18,12 @@ type WalletService interface {
 	CreditWallet(ctx context.Context, userID string, amount float64, transactionType, referenceID, description string, metadata map[string]interface{}) (*models.WalletTransaction, error)
 }
 
+// ReferralApplier applies referral codes through the referral program. It is
+// satisfied by referrals.Service.
+type ReferralApplier interface {
+	ApplyCode(ctx context.Context, userID, code, deviceID string) error
+}
+
 type Service interface {
 	UpdateEmergencyContact(ctx context.Context, userID string, req dto.UpdateEmergencyContactRequest) error
 	GenerateReferralCode(ctx context.Context, userID string) (*dto.ReferralInfoResponse, error)
@@ -30,12 +36,15 @@ type Service interface {
 	DeleteLocation(ctx context.Context, userID, locationID string) error
 	SetDefaultLocation(ctx context.Context, userID, locationID string) error
 	GetRecentLocations(ctx context.Context, userID string) ([]*dto.RecentLocationResponse, error)
+
+	SetReferrals(referrals ReferralApplier)
 }
 
 type service struct {
 	repo          Repository
 	walletService WalletService
 	eventProducer notifications.EventProducer
+	referrals     ReferralApplier
 }
 
 func NewService(repo Repository, walletService WalletService) Service {
@@ -46,6 +55,12 @@ func NewServiceWithNotifications(repo Repository, walletService WalletService, e
 	return &service{repo: repo, walletService: walletService, eventProducer: eventProducer}
 }
 
+// SetReferrals hands referral codes applied here to the referral program,
+// which rewards both sides after the user's first trip instead of at once.
+func (s *service) SetReferrals(referrals ReferralApplier) {
+	s.referrals = referrals
+}
+
 func (s *service) UpdateEmergencyContact(ctx context.Context, userID string, req dto.UpdateEmergencyContactRequest) error {
 	if err := req.Validate(); err != nil {
 		return response.BadRequest(err.Error())
@@ -132,6 +147,14 @@ func (s *service) ApplyReferralCode(ctx context.Context, userID string, req dto.
 		return response.BadRequest("Referral code cannot be empty")
 	}
 
+	if s.referrals != nil {
+		if err := s.referrals.ApplyCode(ctx, userID, req.ReferralCode, ""); err != nil {
+			return err
+		}
+		cache.Delete(ctx, fmt.Sprintf("rider:profile:%s", userID))
+		return nil
+	}
+
 	logger.Info("attempting to apply referral code", "code", req.ReferralCode, "userID", userID)
 
 	referrer, err := s.repo.FindUserByReferralCode(ctx, req.ReferralCode)
@@ -27,7 +27,9 @@ type ValidatePromoCodeResponse struct {
 	Valid          bool    `json:"valid"`
 	DiscountAmount float64 `json:"discountAmount"`
 	FinalAmount    float64 `json:"finalAmount"`
-	Message        string  `json:"message,omitempty"`
+	// Code is the error code explaining why an invalid promo was rejected.
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
 }
 
 type ApplyPromoCodeResponse struct {
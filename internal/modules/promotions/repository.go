@@ -120,12 +120,18 @@ func (r *repository) DeductFreeRideCredits(ctx context.Context, userID string, a
 	return r.db.WithContext(ctx).
 		Model(&models.Wallet{}).
 		Where("user_id = ? AND free_ride_credits >= ?", userID, amount).
-		Update("free_ride_credits", gorm.Expr("free_ride_credits - ?", amount)).Error
+		Updates(map[string]interface{}{
+			"free_ride_credits": gorm.Expr("free_ride_credits - ?", amount),
+			"version":           gorm.Expr("version + 1"),
+		}).Error
 }
 
 func (r *repository) AddFreeRideCredits(ctx context.Context, userID string, amount float64) error {
 	return r.db.WithContext(ctx).
 		Model(&models.Wallet{}).
 		Where("user_id = ?", userID).
-		Update("free_ride_credits", gorm.Expr("free_ride_credits + ?", amount)).Error
+		Updates(map[string]interface{}{
+			"free_ride_credits": gorm.Expr("free_ride_credits + ?", amount),
+			"version":           gorm.Expr("version + 1"),
+		}).Error
 }
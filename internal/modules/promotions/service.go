@@ -120,6 +120,7 @@ func (s *service) ValidatePromoCode(ctx context.Context, userID string, req dto.
 	if err != nil {
 		return &dto.ValidatePromoCodeResponse{
 			Valid:   false,
+			Code:    response.CodePromoInvalid,
 			Message: "Invalid or expired promo code",
 		}, nil
 	}
@@ -127,6 +128,7 @@ func (s *service) ValidatePromoCode(ctx context.Context, userID string, req dto.
 	if promo.UsageLimit > 0 && promo.UsageCount >= promo.UsageLimit {
 		return &dto.ValidatePromoCodeResponse{
 			Valid:   false,
+			Code:    response.CodePromoUsageLimit,
 			Message: "Promo code usage limit reached",
 		}, nil
 	}
@@ -135,6 +137,7 @@ func (s *service) ValidatePromoCode(ctx context.Context, userID string, req dto.
 	if userUsageCount >= int64(promo.PerUserLimit) {
 		return &dto.ValidatePromoCodeResponse{
 			Valid:   false,
+			Code:    response.CodePromoAlreadyUsed,
 			Message: "You have already used this promo code",
 		}, nil
 	}
@@ -142,6 +145,7 @@ func (s *service) ValidatePromoCode(ctx context.Context, userID string, req dto.
 	if req.RideAmount < promo.MinRideAmount {
 		return &dto.ValidatePromoCodeResponse{
 			Valid:   false,
+			Code:    response.CodePromoMinimumNotMet,
 			Message: fmt.Sprintf("Minimum ride amount of $%.2f required", promo.MinRideAmount),
 		}, nil
 	}
@@ -169,7 +173,7 @@ func (s *service) ApplyPromoCode(ctx context.Context, userID string, req dto.App
 	}
 
 	if !validation.Valid {
-		return nil, response.BadRequest(validation.Message)
+		return nil, response.CodedError(validation.Code, validation.Message)
 	}
 
 	code := strings.ToUpper(req.Code)
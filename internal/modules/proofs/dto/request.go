@@ -0,0 +1,18 @@
+package dto
+
+// UploadProofRequest attaches a photo or signature, already uploaded through
+// the media API, to a stage of an order. SignedBy is who signed, for
+// signatures.
+type UploadProofRequest struct {
+	Stage    string  `json:"stage" binding:"required,oneof=before_service after_service pickup delivery"`
+	Kind     string  `json:"kind" binding:"required,oneof=photo signature"`
+	URL      string  `json:"url" binding:"required,url,max=1000"`
+	SignedBy *string `json:"signedBy" binding:"omitempty,max=255"`
+	Note     string  `json:"note" binding:"omitempty,max=500"`
+}
+
+type SetProofRequirementRequest struct {
+	PhotoBefore bool `json:"photoBefore"`
+	PhotoAfter  bool `json:"photoAfter"`
+	Signature   bool `json:"signature"`
+}
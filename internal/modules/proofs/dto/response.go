@@ -0,0 +1,68 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+type ProofResponse struct {
+	ID        string    `json:"id"`
+	OrderID   string    `json:"orderId"`
+	Stage     string    `json:"stage"`
+	Kind      string    `json:"kind"`
+	URL       string    `json:"url"`
+	SignedBy  *string   `json:"signedBy,omitempty"`
+	Note      string    `json:"note,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// RequiredProof is one proof an order needs before it can be completed and
+// whether it has been captured.
+type RequiredProof struct {
+	Stage    string `json:"stage"`
+	Kind     string `json:"kind"`
+	Captured bool   `json:"captured"`
+}
+
+// OrderProofsResponse is the proof captured for an order and what its
+// category requires.
+type OrderProofsResponse struct {
+	OrderID      string           `json:"orderId"`
+	OrderType    string           `json:"orderType"`
+	CategorySlug string           `json:"categorySlug"`
+	Required     []RequiredProof  `json:"required"`
+	Complete     bool             `json:"complete"`
+	Proofs       []*ProofResponse `json:"proofs"`
+}
+
+type ProofRequirementResponse struct {
+	CategorySlug string    `json:"categorySlug"`
+	PhotoBefore  bool      `json:"photoBefore"`
+	PhotoAfter   bool      `json:"photoAfter"`
+	Signature    bool      `json:"signature"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+func ToProofResponse(proof *models.ServiceProof) *ProofResponse {
+	return &ProofResponse{
+		ID:        proof.ID,
+		OrderID:   proof.OrderID,
+		Stage:     proof.Stage,
+		Kind:      proof.Kind,
+		URL:       proof.URL,
+		SignedBy:  proof.SignedBy,
+		Note:      proof.Note,
+		CreatedAt: proof.CreatedAt,
+	}
+}
+
+func ToProofRequirementResponse(requirement *models.ProofRequirement) *ProofRequirementResponse {
+	return &ProofRequirementResponse{
+		CategorySlug: requirement.CategorySlug,
+		PhotoBefore:  requirement.PhotoBefore,
+		PhotoAfter:   requirement.PhotoAfter,
+		Signature:    requirement.Signature,
+		UpdatedAt:    requirement.UpdatedAt,
+	}
+}
@@ -0,0 +1,135 @@
+package proofs
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/modules/proofs/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// UploadProof godoc
+// @Summary Add proof of service
+// @Description The order's provider attaches a photo or signature, uploaded through the media API, to a stage of a home services or laundry order. Categories can require proof before the order is completed or delivered
+// @Tags orders
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Order ID"
+// @Param request body dto.UploadProofRequest true "Proof"
+// @Success 200 {object} response.Response{data=dto.ProofResponse}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Router /orders/{id}/proofs [post]
+func (h *Handler) UploadProof(c *gin.Context) {
+	var req dto.UploadProofRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+
+	proof, err := h.service.UploadProof(c.Request.Context(), userID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, proof, "Proof of service saved successfully")
+}
+
+// GetOrderProofs godoc
+// @Summary Get proof of service
+// @Description The proof captured for an order and what its category requires before it can be completed
+// @Tags orders
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Order ID"
+// @Success 200 {object} response.Response{data=dto.OrderProofsResponse}
+// @Failure 404 {object} response.Response
+// @Router /orders/{id}/proofs [get]
+func (h *Handler) GetOrderProofs(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+	roleName, _ := role.(string)
+
+	proofs, err := h.service.GetOrderProofs(c.Request.Context(), userID.(string), roleName, c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, proofs, "Proof of service retrieved successfully")
+}
+
+// ListRequirements godoc
+// @Summary List proof requirements (admin)
+// @Tags admin-proofs
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=[]dto.ProofRequirementResponse}
+// @Router /admin/proof-requirements [get]
+func (h *Handler) ListRequirements(c *gin.Context) {
+	requirements, err := h.service.ListRequirements(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, requirements, "Proof requirements retrieved successfully")
+}
+
+// SetRequirement godoc
+// @Summary Set proof requirement (admin)
+// @Description Set which photos and signature providers must capture before completing orders in a category
+// @Tags admin-proofs
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param category path string true "Category slug"
+// @Param request body dto.SetProofRequirementRequest true "Required proof"
+// @Success 200 {object} response.Response{data=dto.ProofRequirementResponse}
+// @Router /admin/proof-requirements/{category} [put]
+func (h *Handler) SetRequirement(c *gin.Context) {
+	var req dto.SetProofRequirementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	adminID, _ := c.Get("userID")
+
+	requirement, err := h.service.SetRequirement(c.Request.Context(), adminID.(string), c.Param("category"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, requirement, "Proof requirement saved successfully")
+}
+
+// DeleteRequirement godoc
+// @Summary Delete proof requirement (admin)
+// @Description Orders in the category no longer need proof of service
+// @Tags admin-proofs
+// @Security BearerAuth
+// @Produce json
+// @Param category path string true "Category slug"
+// @Success 200 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /admin/proof-requirements/{category} [delete]
+func (h *Handler) DeleteRequirement(c *gin.Context) {
+	if err := h.service.DeleteRequirement(c.Request.Context(), c.Param("category")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, nil, "Proof requirement deleted successfully")
+}
@@ -0,0 +1,140 @@
+package proofs
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository interface {
+	GetRequirement(ctx context.Context, categorySlug string) (*models.ProofRequirement, error)
+	ListRequirements(ctx context.Context) ([]*models.ProofRequirement, error)
+	SaveRequirement(ctx context.Context, requirement *models.ProofRequirement) error
+	DeleteRequirement(ctx context.Context, categorySlug string) (bool, error)
+
+	CreateProof(ctx context.Context, proof *models.ServiceProof) error
+	ListProofs(ctx context.Context, orderType, orderID string) ([]*models.ServiceProof, error)
+
+	FindServiceOrder(ctx context.Context, orderID string) (*models.ServiceOrderNew, error)
+	FindLaundryOrder(ctx context.Context, orderID string) (*models.LaundryOrder, error)
+	FindLaundryPickup(ctx context.Context, orderID string) (*models.LaundryPickup, error)
+	FindLaundryDelivery(ctx context.Context, orderID string) (*models.LaundryDelivery, error)
+	FindProviderUserID(ctx context.Context, providerID string) (string, error)
+	SetLaundryProof(ctx context.Context, orderID string, proof *models.ServiceProof) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) GetRequirement(ctx context.Context, categorySlug string) (*models.ProofRequirement, error) {
+	var requirement models.ProofRequirement
+	err := r.db.WithContext(ctx).Where("category_slug = ?", categorySlug).First(&requirement).Error
+	if err != nil {
+		return nil, err
+	}
+	return &requirement, nil
+}
+
+func (r *repository) ListRequirements(ctx context.Context) ([]*models.ProofRequirement, error) {
+	var requirements []*models.ProofRequirement
+	err := r.db.WithContext(ctx).Order("category_slug ASC").Find(&requirements).Error
+	return requirements, err
+}
+
+func (r *repository) SaveRequirement(ctx context.Context, requirement *models.ProofRequirement) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "category_slug"}},
+		DoUpdates: clause.AssignmentColumns([]string{"photo_before", "photo_after", "signature", "updated_by", "updated_at"}),
+	}).Create(requirement).Error
+}
+
+func (r *repository) DeleteRequirement(ctx context.Context, categorySlug string) (bool, error) {
+	result := r.db.WithContext(ctx).Where("category_slug = ?", categorySlug).Delete(&models.ProofRequirement{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *repository) CreateProof(ctx context.Context, proof *models.ServiceProof) error {
+	return r.db.WithContext(ctx).Create(proof).Error
+}
+
+func (r *repository) ListProofs(ctx context.Context, orderType, orderID string) ([]*models.ServiceProof, error) {
+	var proofs []*models.ServiceProof
+	err := r.db.WithContext(ctx).
+		Where("order_type = ? AND order_id = ?", orderType, orderID).
+		Order("created_at ASC").
+		Find(&proofs).Error
+	return proofs, err
+}
+
+func (r *repository) FindServiceOrder(ctx context.Context, orderID string) (*models.ServiceOrderNew, error) {
+	var order models.ServiceOrderNew
+	err := r.db.WithContext(ctx).Where("id = ?", orderID).First(&order).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *repository) FindLaundryOrder(ctx context.Context, orderID string) (*models.LaundryOrder, error) {
+	var order models.LaundryOrder
+	err := r.db.WithContext(ctx).Where("id = ?", orderID).First(&order).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *repository) FindLaundryPickup(ctx context.Context, orderID string) (*models.LaundryPickup, error) {
+	var pickup models.LaundryPickup
+	err := r.db.WithContext(ctx).Where("order_id = ?", orderID).First(&pickup).Error
+	if err != nil {
+		return nil, err
+	}
+	return &pickup, nil
+}
+
+func (r *repository) FindLaundryDelivery(ctx context.Context, orderID string) (*models.LaundryDelivery, error) {
+	var delivery models.LaundryDelivery
+	err := r.db.WithContext(ctx).Where("order_id = ?", orderID).First(&delivery).Error
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (r *repository) FindProviderUserID(ctx context.Context, providerID string) (string, error) {
+	var profile models.ServiceProviderProfile
+	err := r.db.WithContext(ctx).Select("id", "user_id").Where("id = ?", providerID).First(&profile).Error
+	return profile.UserID, err
+}
+
+// SetLaundryProof copies a laundry proof onto the pickup or delivery it was
+// taken at, so the order's own photo and signature fields stay current.
+func (r *repository) SetLaundryProof(ctx context.Context, orderID string, proof *models.ServiceProof) error {
+	updates := map[string]interface{}{}
+	var model interface{}
+	switch {
+	case proof.Stage == models.ProofStagePickup && proof.Kind == models.ProofKindPhoto:
+		model = &models.LaundryPickup{}
+		updates["photo_url"] = proof.URL
+	case proof.Stage == models.ProofStageDelivery && proof.Kind == models.ProofKindPhoto:
+		model = &models.LaundryDelivery{}
+		updates["photo_url"] = proof.URL
+	case proof.Stage == models.ProofStageDelivery && proof.Kind == models.ProofKindSignature:
+		model = &models.LaundryDelivery{}
+		updates["recipient_signature"] = proof.URL
+		if proof.SignedBy != nil {
+			updates["recipient_name"] = *proof.SignedBy
+		}
+	default:
+		return nil
+	}
+	return r.db.WithContext(ctx).Model(model).Where("order_id = ?", orderID).Updates(updates).Error
+}
@@ -0,0 +1,24 @@
+package proofs
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/middleware"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
+	orders := router.Group("/orders")
+	orders.Use(authMiddleware)
+	{
+		orders.POST("/:id/proofs", handler.UploadProof)
+		orders.GET("/:id/proofs", handler.GetOrderProofs)
+	}
+
+	admin := router.Group("/admin/proof-requirements")
+	admin.Use(authMiddleware)
+	admin.Use(middleware.RequireAdmin())
+	{
+		admin.GET("", handler.ListRequirements)
+		admin.PUT("/:category", handler.SetRequirement)
+		admin.DELETE("/:category", handler.DeleteRequirement)
+	}
+}
@@ -0,0 +1,337 @@
+package proofs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+	"github.com/umar5678/go-backend/internal/modules/proofs/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+// Order types proofs are kept for, matching the wallet reference types.
+const (
+	OrderTypeService = "service_order"
+	OrderTypeLaundry = "laundry_order"
+)
+
+type Service interface {
+	UploadProof(ctx context.Context, userID, orderID string, req dto.UploadProofRequest) (*dto.ProofResponse, error)
+	GetOrderProofs(ctx context.Context, userID, role, orderID string) (*dto.OrderProofsResponse, error)
+
+	ListRequirements(ctx context.Context) ([]*dto.ProofRequirementResponse, error)
+	SetRequirement(ctx context.Context, adminID, categorySlug string, req dto.SetProofRequirementRequest) (*dto.ProofRequirementResponse, error)
+	DeleteRequirement(ctx context.Context, categorySlug string) error
+
+	// CheckProofs returns a bad request naming the proof still missing when
+	// the order's category requires proof it does not have yet.
+	CheckProofs(ctx context.Context, orderType, orderID, categorySlug string) error
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+// proofOrder is a home services or laundry order reduced to what proof
+// capture needs.
+type proofOrder struct {
+	Type           string
+	ID             string
+	CategorySlug   string
+	CustomerID     string
+	ProviderUserID string
+	// Stages are the stages at which proof can be captured right now.
+	Stages map[string]bool
+}
+
+// UploadProof records a photo or signature the order's provider captured.
+// Each stage only accepts proof while the order is at it: before_service
+// until the service is completed and after_service while it is in progress;
+// pickup until the laundry is delivered and delivery once it has been picked
+// up.
+func (s *service) UploadProof(ctx context.Context, userID, orderID string, req dto.UploadProofRequest) (*dto.ProofResponse, error) {
+	order, err := s.loadOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.ProviderUserID == "" || order.ProviderUserID != userID {
+		return nil, response.ForbiddenError("You are not assigned to this order")
+	}
+	if !order.Stages[req.Stage] {
+		return nil, response.BadRequest(fmt.Sprintf("Proof for the %s stage cannot be added to this order now", strings.ReplaceAll(req.Stage, "_", " ")))
+	}
+	if req.Kind == models.ProofKindSignature && !signatureStages[req.Stage] {
+		return nil, response.BadRequest("A signature can only be captured on completion or delivery")
+	}
+
+	proof := &models.ServiceProof{
+		OrderType:  order.Type,
+		OrderID:    order.ID,
+		Stage:      req.Stage,
+		Kind:       req.Kind,
+		URL:        req.URL,
+		SignedBy:   req.SignedBy,
+		Note:       req.Note,
+		UploadedBy: userID,
+	}
+	if err := s.repo.CreateProof(ctx, proof); err != nil {
+		return nil, response.InternalServerError("Failed to save proof", err)
+	}
+	if order.Type == OrderTypeLaundry {
+		if err := s.repo.SetLaundryProof(ctx, order.ID, proof); err != nil {
+			logger.Error("failed to copy proof to laundry order", "error", err, "orderID", order.ID, "proofID", proof.ID)
+		}
+	}
+
+	logger.Info("proof of service captured", "orderID", order.ID, "orderType", order.Type, "stage", proof.Stage, "kind", proof.Kind)
+	return dto.ToProofResponse(proof), nil
+}
+
+// signatureStages are the stages a customer signs off at.
+var signatureStages = map[string]bool{
+	models.ProofStageAfterService: true,
+	models.ProofStageDelivery:     true,
+}
+
+func (s *service) GetOrderProofs(ctx context.Context, userID, role, orderID string) (*dto.OrderProofsResponse, error) {
+	order, err := s.loadOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if role != string(models.RoleAdmin) && userID != order.CustomerID && userID != order.ProviderUserID {
+		return nil, response.NotFoundError("Order")
+	}
+
+	proofs, err := s.repo.ListProofs(ctx, order.Type, order.ID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get proofs", err)
+	}
+	required, err := s.requiredProofs(ctx, order.Type, order.CategorySlug, proofs)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.OrderProofsResponse{
+		OrderID:      order.ID,
+		OrderType:    order.Type,
+		CategorySlug: order.CategorySlug,
+		Required:     required,
+		Complete:     true,
+		Proofs:       make([]*dto.ProofResponse, len(proofs)),
+	}
+	for _, proof := range required {
+		if !proof.Captured {
+			resp.Complete = false
+		}
+	}
+	for i, proof := range proofs {
+		resp.Proofs[i] = dto.ToProofResponse(proof)
+	}
+	return resp, nil
+}
+
+func (s *service) CheckProofs(ctx context.Context, orderType, orderID, categorySlug string) error {
+	proofs, err := s.repo.ListProofs(ctx, orderType, orderID)
+	if err != nil {
+		return response.InternalServerError("Failed to check proof of service", err)
+	}
+	required, err := s.requiredProofs(ctx, orderType, categorySlug, proofs)
+	if err != nil {
+		return err
+	}
+
+	var missing []string
+	for _, proof := range required {
+		if !proof.Captured {
+			missing = append(missing, fmt.Sprintf("%s %s", strings.ReplaceAll(proof.Stage, "_", " "), proof.Kind))
+		}
+	}
+	if len(missing) > 0 {
+		return response.BadRequest("Proof of service is missing: " + strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// requiredProofs lists the proof the category requires of an order of the
+// given type and marks what has been captured.
+func (s *service) requiredProofs(ctx context.Context, orderType, categorySlug string, proofs []*models.ServiceProof) ([]dto.RequiredProof, error) {
+	requirement, err := s.repo.GetRequirement(ctx, categorySlug)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return []dto.RequiredProof{}, nil
+	}
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get proof requirements", err)
+	}
+
+	before, after := models.ProofStageBeforeService, models.ProofStageAfterService
+	if orderType == OrderTypeLaundry {
+		before, after = models.ProofStagePickup, models.ProofStageDelivery
+	}
+
+	required := []dto.RequiredProof{}
+	if requirement.PhotoBefore {
+		required = append(required, dto.RequiredProof{Stage: before, Kind: models.ProofKindPhoto})
+	}
+	if requirement.PhotoAfter {
+		required = append(required, dto.RequiredProof{Stage: after, Kind: models.ProofKindPhoto})
+	}
+	if requirement.Signature {
+		required = append(required, dto.RequiredProof{Stage: after, Kind: models.ProofKindSignature})
+	}
+
+	for i := range required {
+		for _, proof := range proofs {
+			if proof.Stage == required[i].Stage && proof.Kind == required[i].Kind {
+				required[i].Captured = true
+				break
+			}
+		}
+	}
+	return required, nil
+}
+
+func (s *service) ListRequirements(ctx context.Context) ([]*dto.ProofRequirementResponse, error) {
+	requirements, err := s.repo.ListRequirements(ctx)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to list proof requirements", err)
+	}
+	result := make([]*dto.ProofRequirementResponse, len(requirements))
+	for i, requirement := range requirements {
+		result[i] = dto.ToProofRequirementResponse(requirement)
+	}
+	return result, nil
+}
+
+// SetRequirement creates or replaces the proof required for a category. It
+// applies to orders completed from then on, including ones already placed.
+func (s *service) SetRequirement(ctx context.Context, adminID, categorySlug string, req dto.SetProofRequirementRequest) (*dto.ProofRequirementResponse, error) {
+	categorySlug = strings.TrimSpace(categorySlug)
+	if categorySlug == "" {
+		return nil, response.BadRequest("Category is required")
+	}
+
+	requirement := &models.ProofRequirement{
+		CategorySlug: categorySlug,
+		PhotoBefore:  req.PhotoBefore,
+		PhotoAfter:   req.PhotoAfter,
+		Signature:    req.Signature,
+		UpdatedBy:    &adminID,
+	}
+	if err := s.repo.SaveRequirement(ctx, requirement); err != nil {
+		return nil, response.InternalServerError("Failed to save proof requirement", err)
+	}
+
+	logger.Info("proof requirement updated", "category", categorySlug, "adminID", adminID,
+		"photoBefore", req.PhotoBefore, "photoAfter", req.PhotoAfter, "signature", req.Signature)
+	return dto.ToProofRequirementResponse(requirement), nil
+}
+
+func (s *service) DeleteRequirement(ctx context.Context, categorySlug string) error {
+	deleted, err := s.repo.DeleteRequirement(ctx, categorySlug)
+	if err != nil {
+		return response.InternalServerError("Failed to delete proof requirement", err)
+	}
+	if !deleted {
+		return response.NotFoundError("Proof requirement")
+	}
+	return nil
+}
+
+// loadOrder finds the order as a home services order and then as a laundry
+// order.
+func (s *service) loadOrder(ctx context.Context, orderID string) (*proofOrder, error) {
+	order, err := s.repo.FindServiceOrder(ctx, orderID)
+	if err == nil {
+		return s.serviceOrder(ctx, order)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, response.InternalServerError("Failed to get order", err)
+	}
+
+	laundryOrder, err := s.repo.FindLaundryOrder(ctx, orderID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, response.NotFoundError("Order")
+	}
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get order", err)
+	}
+	return s.laundryOrder(ctx, laundryOrder)
+}
+
+func (s *service) serviceOrder(ctx context.Context, order *models.ServiceOrderNew) (*proofOrder, error) {
+	result := &proofOrder{
+		Type:         OrderTypeService,
+		ID:           order.ID,
+		CategorySlug: order.CategorySlug,
+		CustomerID:   order.CustomerID,
+		Stages: map[string]bool{
+			models.ProofStageBeforeService: order.Status == shared.OrderStatusAccepted || order.Status == shared.OrderStatusInProgress,
+			models.ProofStageAfterService:  order.Status == shared.OrderStatusInProgress,
+		},
+	}
+	if order.AssignedProviderID != nil {
+		userID, err := s.repo.FindProviderUserID(ctx, *order.AssignedProviderID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.InternalServerError("Failed to get provider", err)
+		}
+		result.ProviderUserID = userID
+	}
+	return result, nil
+}
+
+func (s *service) laundryOrder(ctx context.Context, order *models.LaundryOrder) (*proofOrder, error) {
+	result := &proofOrder{
+		Type:         OrderTypeLaundry,
+		ID:           order.ID,
+		CategorySlug: order.CategorySlug,
+		Stages:       map[string]bool{},
+	}
+	if order.UserID != nil {
+		result.CustomerID = *order.UserID
+	}
+
+	pickup, err := s.repo.FindLaundryPickup(ctx, order.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, response.InternalServerError("Failed to get pickup", err)
+	}
+	delivery, err := s.repo.FindLaundryDelivery(ctx, order.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, response.InternalServerError("Failed to get delivery", err)
+	}
+
+	// Laundry orders normally store the provider profile, but some carry
+	// the provider's user ID, as pickups and deliveries do.
+	if order.ProviderID != nil {
+		userID, err := s.repo.FindProviderUserID(ctx, *order.ProviderID)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			result.ProviderUserID = *order.ProviderID
+		case err != nil:
+			return nil, response.InternalServerError("Failed to get provider", err)
+		default:
+			result.ProviderUserID = userID
+		}
+	}
+	if result.ProviderUserID == "" && delivery != nil && delivery.ProviderID != nil {
+		result.ProviderUserID = *delivery.ProviderID
+	}
+	if result.ProviderUserID == "" && pickup != nil && pickup.ProviderID != nil {
+		result.ProviderUserID = *pickup.ProviderID
+	}
+
+	active := order.Status != "completed" && order.Status != "cancelled"
+	pickedUp := pickup != nil && pickup.Status == "completed"
+	delivered := delivery != nil && delivery.Status == "completed"
+	result.Stages[models.ProofStagePickup] = active && pickup != nil && !delivered
+	result.Stages[models.ProofStageDelivery] = active && pickedUp && delivery != nil && !delivered
+	return result, nil
+}
@@ -0,0 +1,118 @@
+package ratings
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/modules/ratings/dto"
+	"github.com/umar5678/go-backend/internal/services/health"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+// RefreshProviderRating recomputes a provider's overall rating and, when the
+// order had one, their rating in its category. It is called after a customer
+// rates an order; anything it misses is fixed by the nightly reconciliation.
+func (s *service) RefreshProviderRating(ctx context.Context, providerID, categorySlug string) error {
+	if err := s.repo.RefreshProviderStats(ctx, providerID); err != nil {
+		return err
+	}
+	if categorySlug == "" {
+		return nil
+	}
+	return s.repo.RefreshProviderCategoryStats(ctx, providerID, categorySlug)
+}
+
+// RefreshCustomerRating recomputes a customer's rating after a provider rates
+// one of their orders.
+func (s *service) RefreshCustomerRating(ctx context.Context, customerID string) error {
+	return s.repo.RefreshCustomerStats(ctx, customerID)
+}
+
+func (s *service) GetCustomerRatingStats(ctx context.Context, customerID string) (*dto.CustomerRatingStatsResponse, error) {
+	stats, err := s.repo.GetCustomerStats(ctx, customerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return &dto.CustomerRatingStatsResponse{UserID: customerID}, nil
+		}
+		return nil, response.InternalServerError("Failed to get customer rating", err)
+	}
+	return dto.ToCustomerRatingStatsResponse(stats), nil
+}
+
+// ReconcileRatings recomputes every provider, provider category and customer
+// rating from the orders and corrects those that drifted.
+func (s *service) ReconcileRatings(ctx context.Context) (*dto.RatingReconciliationResponse, error) {
+	started := time.Now()
+	result := &dto.RatingReconciliationResponse{}
+
+	var err error
+	if result.ProvidersCorrected, err = s.repo.ReconcileProviderStats(ctx); err != nil {
+		return nil, response.InternalServerError("Failed to reconcile provider ratings", err)
+	}
+	if result.CategoriesCorrected, err = s.repo.ReconcileProviderCategoryStats(ctx); err != nil {
+		return nil, response.InternalServerError("Failed to reconcile provider category ratings", err)
+	}
+	if result.CustomersCorrected, err = s.repo.ReconcileCustomerStats(ctx); err != nil {
+		return nil, response.InternalServerError("Failed to reconcile customer ratings", err)
+	}
+
+	result.CompletedAt = time.Now()
+	result.DurationMs = result.CompletedAt.Sub(started).Milliseconds()
+
+	logger.Info("ratings reconciled",
+		"providersCorrected", result.ProvidersCorrected,
+		"categoriesCorrected", result.CategoriesCorrected,
+		"customersCorrected", result.CustomersCorrected,
+		"durationMs", result.DurationMs,
+	)
+
+	return result, nil
+}
+
+// RatingReconciler runs ReconcileRatings once a day at the configured UTC hour.
+type RatingReconciler struct {
+	service Service
+	hour    int
+}
+
+func NewRatingReconciler(service Service, cfg config.RatingsConfig) *RatingReconciler {
+	return &RatingReconciler{service: service, hour: cfg.ReconcileHour}
+}
+
+func (w *RatingReconciler) Start(ctx context.Context) {
+	heartbeat := health.NewHeartbeat("rating_reconciler", 24*time.Hour)
+
+	go func() {
+		for {
+			next := nextRunAt(time.Now().UTC(), w.hour)
+			timer := time.NewTimer(time.Until(next))
+
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+				runCtx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+				if _, err := w.service.ReconcileRatings(runCtx); err != nil {
+					logger.Error("rating reconciliation failed", "error", err)
+				}
+				cancel()
+				heartbeat.Beat()
+			}
+		}
+	}()
+
+	logger.Info("rating reconciler started", "hourUTC", w.hour)
+}
+
+func nextRunAt(now time.Time, hour int) time.Time {
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.UTC)
+	if !next.After(now) {
+		next = next.Add(24 * time.Hour)
+	}
+	return next
+}
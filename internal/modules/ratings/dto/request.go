@@ -1,6 +1,11 @@
 package dto
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
 
 type CreateRatingRequest struct {
 	OrderID string  `json:"orderId" binding:"required"`
@@ -8,28 +13,55 @@ type CreateRatingRequest struct {
 	Comment *string `json:"comment" binding:"omitempty,max=500"`
 }
 
+// RateDriverRequest is a rider's rating of their driver. Tags say what the
+// rating was about and come from models.DriverRatingTags.
 type RateDriverRequest struct {
-	RideID  string `json:"rideId" binding:"required,uuid"`
-	Rating  int    `json:"rating" binding:"required,min=1,max=5"`
-	Comment string `json:"comment" binding:"omitempty,max=500"`
+	RideID  string   `json:"rideId" binding:"required,uuid"`
+	Rating  int      `json:"rating" binding:"required,min=1,max=5"`
+	Tags    []string `json:"tags" binding:"omitempty,max=5"`
+	Comment string   `json:"comment" binding:"omitempty,max=500"`
 }
 
 func (r *RateDriverRequest) Validate() error {
 	if r.Rating < 1 || r.Rating > 5 {
 		return errors.New("rating must be between 1 and 5")
 	}
-	return nil
+	return validateTags(r.Tags, models.DriverRatingTags)
 }
 
+// RateRiderRequest is a driver's rating of their rider. Tags come from
+// models.RiderRatingTags.
 type RateRiderRequest struct {
-	RideID  string `json:"rideId" binding:"required,uuid"`
-	Rating  int    `json:"rating" binding:"required,min=1,max=5"`
-	Comment string `json:"comment" binding:"omitempty,max=500"`
+	RideID  string   `json:"rideId" binding:"required,uuid"`
+	Rating  int      `json:"rating" binding:"required,min=1,max=5"`
+	Tags    []string `json:"tags" binding:"omitempty,max=5"`
+	Comment string   `json:"comment" binding:"omitempty,max=500"`
 }
 
 func (r *RateRiderRequest) Validate() error {
 	if r.Rating < 1 || r.Rating > 5 {
 		return errors.New("rating must be between 1 and 5")
 	}
+	return validateTags(r.Tags, models.RiderRatingTags)
+}
+
+func validateTags(tags, allowed []string) error {
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		valid := false
+		for _, a := range allowed {
+			if tag == a {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return fmt.Errorf("unknown tag '%s', expected one of %v", tag, allowed)
+		}
+		if seen[tag] {
+			return fmt.Errorf("tag '%s' is repeated", tag)
+		}
+		seen[tag] = true
+	}
 	return nil
 }
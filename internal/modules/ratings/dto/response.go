@@ -32,6 +32,7 @@ type RatingStatsResponse struct {
 	UserID           string  `json:"userId"`
 	UserType         string  `json:"userType"` // 'driver' or 'rider'
 	Rating           float64 `json:"rating"`
+	RatingCount      int     `json:"ratingCount"`
 	TotalRides       int     `json:"totalRides"`
 	TotalEarnings    float64 `json:"totalEarnings,omitempty"`
 	TotalSpent       float64 `json:"totalSpent,omitempty"`
@@ -47,6 +48,40 @@ type RatingBreakdownResponse struct {
 	OneStar       int     `json:"oneStar"`
 	TotalRatings  int     `json:"totalRatings"`
 	AverageRating float64 `json:"averageRating"`
+	// Tags counts how often each tag was attached to a rating.
+	Tags map[string]int `json:"tags"`
+}
+
+// RatingTagsResponse lists the tags each side may attach to a ride rating.
+type RatingTagsResponse struct {
+	Driver []string `json:"driver"`
+	Rider  []string `json:"rider"`
+}
+
+type RideRatingResponse struct {
+	ID        string    `json:"id"`
+	RideID    string    `json:"rideId"`
+	Direction string    `json:"direction"`
+	Score     int       `json:"score"`
+	Tags      []string  `json:"tags"`
+	Comment   string    `json:"comment,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func ToRideRatingResponse(rating *models.RideRating) *RideRatingResponse {
+	tags := []string(rating.Tags)
+	if tags == nil {
+		tags = []string{}
+	}
+	return &RideRatingResponse{
+		ID:        rating.ID,
+		RideID:    rating.RideID,
+		Direction: string(rating.Direction),
+		Score:     rating.Score,
+		Tags:      tags,
+		Comment:   rating.Comment,
+		CreatedAt: rating.CreatedAt,
+	}
 }
 
 func ToDriverRatingStats(profile *models.DriverProfile) *RatingStatsResponse {
@@ -54,6 +89,7 @@ func ToDriverRatingStats(profile *models.DriverProfile) *RatingStatsResponse {
 		UserID:           profile.UserID,
 		UserType:         "driver",
 		Rating:           profile.Rating,
+		RatingCount:      profile.RatingCount,
 		TotalRides:       profile.TotalTrips,
 		TotalEarnings:    profile.TotalEarnings,
 		CancellationRate: profile.CancellationRate,
@@ -66,8 +102,35 @@ func ToRiderRatingStats(profile *models.RiderProfile) *RatingStatsResponse {
 		UserID:           profile.UserID,
 		UserType:         "rider",
 		Rating:           profile.Rating,
+		RatingCount:      profile.RatingCount,
 		TotalRides:       profile.TotalRides,
 		TotalSpent:       profile.TotalSpent,
 		CancellationRate: profile.CancellationRate,
 	}
 }
+
+type CustomerRatingStatsResponse struct {
+	UserID       string     `json:"userId"`
+	Rating       float64    `json:"rating"`
+	TotalReviews int        `json:"totalReviews"`
+	UpdatedAt    *time.Time `json:"updatedAt,omitempty"`
+}
+
+func ToCustomerRatingStatsResponse(stats *models.CustomerRatingStats) *CustomerRatingStatsResponse {
+	return &CustomerRatingStatsResponse{
+		UserID:       stats.UserID,
+		Rating:       stats.Rating,
+		TotalReviews: stats.TotalReviews,
+		UpdatedAt:    &stats.UpdatedAt,
+	}
+}
+
+// RatingReconciliationResponse counts the stored ratings a reconciliation run
+// found out of date and corrected.
+type RatingReconciliationResponse struct {
+	ProvidersCorrected  int64     `json:"providersCorrected"`
+	CategoriesCorrected int64     `json:"categoriesCorrected"`
+	CustomersCorrected  int64     `json:"customersCorrected"`
+	DurationMs          int64     `json:"durationMs"`
+	CompletedAt         time.Time `json:"completedAt"`
+}
@@ -49,6 +49,7 @@ func (h *Handler) CreateRating(c *gin.Context) {
 
 // RateDriver godoc
 // @Summary Rate driver (Rider)
+// @Description Tags say what the rating was about; see /ratings/tags for the accepted values
 // @Tags ratings
 // @Security BearerAuth
 // @Accept json
@@ -75,6 +76,7 @@ func (h *Handler) RateDriver(c *gin.Context) {
 
 // RateRider godoc
 // @Summary Rate rider (Driver)
+// @Description Tags say what the rating was about; see /ratings/tags for the accepted values
 // @Tags ratings
 // @Security BearerAuth
 // @Accept json
@@ -99,6 +101,18 @@ func (h *Handler) RateRider(c *gin.Context) {
 	response.Success(c, nil, "Rider rated successfully")
 }
 
+// GetRatingTags godoc
+// @Summary List ride rating tags
+// @Description Tags riders may attach when rating a driver, and drivers when rating a rider
+// @Tags ratings
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=dto.RatingTagsResponse}
+// @Router /ratings/tags [get]
+func (h *Handler) GetRatingTags(c *gin.Context) {
+	response.Success(c, h.service.GetRatingTags(), "Rating tags retrieved successfully")
+}
+
 // GetDriverRatingStats godoc
 // @Summary Get driver rating statistics
 // @Tags ratings
@@ -176,3 +190,40 @@ func (h *Handler) GetRiderRatingBreakdown(c *gin.Context) {
 
 	response.Success(c, breakdown, "Rating breakdown retrieved successfully")
 }
+
+// GetCustomerRatingStats godoc
+// @Summary Get a home-service customer's rating
+// @Description Average of the ratings providers gave the customer on completed orders
+// @Tags ratings
+// @Security BearerAuth
+// @Produce json
+// @Param customerId path string true "Customer user ID"
+// @Success 200 {object} response.Response{data=dto.CustomerRatingStatsResponse}
+// @Router /ratings/customer/{customerId}/stats [get]
+func (h *Handler) GetCustomerRatingStats(c *gin.Context) {
+	stats, err := h.service.GetCustomerRatingStats(c.Request.Context(), c.Param("customerId"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, stats, "Customer rating stats retrieved successfully")
+}
+
+// ReconcileRatings godoc
+// @Summary Reconcile stored ratings
+// @Description Recomputes provider, provider category and customer ratings from orders and corrects any that drifted. The same run happens nightly.
+// @Tags ratings - admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=dto.RatingReconciliationResponse}
+// @Router /ratings/admin/reconcile [post]
+func (h *Handler) ReconcileRatings(c *gin.Context) {
+	result, err := h.service.ReconcileRatings(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Ratings reconciled successfully")
+}
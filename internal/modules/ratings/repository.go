@@ -2,7 +2,6 @@ package ratings
 
 import (
 	"context"
-	"time"
 
 	"gorm.io/gorm"
 
@@ -16,21 +15,28 @@ type Repository interface {
 	GetProviderAverageRating(ctx context.Context, providerID string) (float64, error)
 	UpdateProviderRating(ctx context.Context, providerID string, newAverage float64) error
 
-	RateDriver(ctx context.Context, rideID string, rating int, comment string) error
-	RateRider(ctx context.Context, rideID string, rating int, comment string) error
-
-	GetDriverRating(ctx context.Context, driverID string) (float64, error)
-	GetRiderRating(ctx context.Context, riderID string) (float64, error)
+	SaveRideRating(ctx context.Context, rating *models.RideRating) error
+	GetRollingRating(ctx context.Context, rateeID string, direction models.RideRatingDirection, window int) (float64, int, error)
+	GetRatingTagCounts(ctx context.Context, rateeID string, direction models.RideRatingDirection) (map[string]int, error)
 	GetDriverProfile(ctx context.Context, userID string) (*models.DriverProfile, error)
 	GetRiderProfile(ctx context.Context, userID string) (*models.RiderProfile, error)
 
 	CreateRiderProfile(ctx context.Context, profile *models.RiderProfile) error
 
-	UpdateDriverRating(ctx context.Context, driverID string) error
-	UpdateRiderRating(ctx context.Context, riderID string) error
+	UpdateDriverRating(ctx context.Context, driverID string, window int) error
+	UpdateRiderRating(ctx context.Context, riderID string, window int) error
 
 	GetDriverRatingBreakdown(ctx context.Context, driverID string) (map[int]int, error)
 	GetRiderRatingBreakdown(ctx context.Context, riderID string) (map[int]int, error)
+
+	RefreshProviderStats(ctx context.Context, providerID string) error
+	RefreshProviderCategoryStats(ctx context.Context, providerID, categorySlug string) error
+	RefreshCustomerStats(ctx context.Context, customerID string) error
+	GetCustomerStats(ctx context.Context, customerID string) (*models.CustomerRatingStats, error)
+
+	ReconcileProviderStats(ctx context.Context) (int64, error)
+	ReconcileProviderCategoryStats(ctx context.Context) (int64, error)
+	ReconcileCustomerStats(ctx context.Context) (int64, error)
 }
 
 type repository struct {
@@ -78,48 +84,70 @@ func (r *repository) UpdateProviderRating(ctx context.Context, providerID string
 		Update("rating", newAverage).Error
 }
 
-func (r *repository) RateDriver(ctx context.Context, rideID string, rating int, comment string) error {
-	now := time.Now()
-	return r.db.WithContext(ctx).
-		Model(&models.Ride{}).
-		Where("id = ?", rideID).
-		Updates(map[string]interface{}{
-			"driver_rating":         rating,
-			"driver_rating_comment": comment,
-			"driver_rated_at":       now,
-		}).Error
-}
+// SaveRideRating records a rating and copies the score onto the ride row, which
+// is what older clients read.
+func (r *repository) SaveRideRating(ctx context.Context, rating *models.RideRating) error {
+	prefix := "driver"
+	if rating.Direction == models.RideRatingOfRider {
+		prefix = "rider"
+	}
 
-func (r *repository) RateRider(ctx context.Context, rideID string, rating int, comment string) error {
-	now := time.Now()
-	return r.db.WithContext(ctx).
-		Model(&models.Ride{}).
-		Where("id = ?", rideID).
-		Updates(map[string]interface{}{
-			"rider_rating":         rating,
-			"rider_rating_comment": comment,
-			"rider_rated_at":       now,
-		}).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(rating).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Ride{}).
+			Where("id = ?", rating.RideID).
+			Updates(map[string]interface{}{
+				prefix + "_rating":         rating.Score,
+				prefix + "_rating_comment": rating.Comment,
+				prefix + "_rated_at":       rating.CreatedAt,
+			}).Error
+	})
 }
 
-func (r *repository) GetDriverRating(ctx context.Context, driverID string) (float64, error) {
+// GetRollingRating averages the most recent window ratings given to a user,
+// defaulting to 5.0 before their first, and counts all of their ratings.
+func (r *repository) GetRollingRating(ctx context.Context, rateeID string, direction models.RideRatingDirection, window int) (float64, int, error) {
 	var avgRating float64
-	err := r.db.WithContext(ctx).
-		Model(&models.Ride{}).
-		Where("driver_id = ? AND driver_rating IS NOT NULL", driverID).
-		Select("COALESCE(AVG(driver_rating), 5.0)").
-		Scan(&avgRating).Error
-	return avgRating, err
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT COALESCE(AVG(score), 5.0) FROM (
+			SELECT score FROM ride_ratings
+			WHERE ratee_id = ? AND direction = ?
+			ORDER BY created_at DESC
+			LIMIT ?
+		) recent
+	`, rateeID, direction, window).Scan(&avgRating).Error
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var count int64
+	err = r.db.WithContext(ctx).
+		Model(&models.RideRating{}).
+		Where("ratee_id = ? AND direction = ?", rateeID, direction).
+		Count(&count).Error
+	return avgRating, int(count), err
 }
 
-func (r *repository) GetRiderRating(ctx context.Context, riderID string) (float64, error) {
-	var avgRating float64
-	err := r.db.WithContext(ctx).
-		Model(&models.Ride{}).
-		Where("rider_id = ? AND rider_rating IS NOT NULL", riderID).
-		Select("COALESCE(AVG(rider_rating), 5.0)").
-		Scan(&avgRating).Error
-	return avgRating, err
+func (r *repository) GetRatingTagCounts(ctx context.Context, rateeID string, direction models.RideRatingDirection) (map[string]int, error) {
+	var results []struct {
+		Tag   string
+		Count int
+	}
+
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT tag, COUNT(*) AS count
+		FROM ride_ratings, UNNEST(tags) AS tag
+		WHERE ratee_id = ? AND direction = ?
+		GROUP BY tag
+	`, rateeID, direction).Scan(&results).Error
+
+	counts := make(map[string]int, len(results))
+	for _, result := range results {
+		counts[result.Tag] = result.Count
+	}
+	return counts, err
 }
 
 func (r *repository) GetDriverProfile(ctx context.Context, userID string) (*models.DriverProfile, error) {
@@ -142,26 +170,36 @@ func (r *repository) CreateRiderProfile(ctx context.Context, profile *models.Rid
 	return r.db.WithContext(ctx).Create(profile).Error
 }
 
-func (r *repository) UpdateDriverRating(ctx context.Context, driverID string) error {
-	avgRating, _ := r.GetDriverRating(ctx, driverID)
+func (r *repository) UpdateDriverRating(ctx context.Context, driverID string, window int) error {
+	avgRating, count, err := r.GetRollingRating(ctx, driverID, models.RideRatingOfDriver, window)
+	if err != nil {
+		return err
+	}
 
 	return r.db.WithContext(ctx).
 		Model(&models.DriverProfile{}).
 		Where("user_id = ?", driverID).
-		Update("rating", avgRating).Error
+		Updates(map[string]interface{}{
+			"rating":       avgRating,
+			"rating_count": count,
+		}).Error
 }
 
-func (r *repository) UpdateRiderRating(ctx context.Context, riderID string) error {
-	avgRating, _ := r.GetRiderRating(ctx, riderID)
+func (r *repository) UpdateRiderRating(ctx context.Context, riderID string, window int) error {
+	avgRating, count, err := r.GetRollingRating(ctx, riderID, models.RideRatingOfRider, window)
+	if err != nil {
+		return err
+	}
 
 	var profile models.RiderProfile
-	err := r.db.WithContext(ctx).Where("user_id = ?", riderID).First(&profile).Error
+	err = r.db.WithContext(ctx).Where("user_id = ?", riderID).First(&profile).Error
 
 	if err == gorm.ErrRecordNotFound {
 
 		profile = models.RiderProfile{
-			UserID: riderID,
-			Rating: avgRating,
+			UserID:      riderID,
+			Rating:      avgRating,
+			RatingCount: count,
 		}
 		return r.db.WithContext(ctx).Create(&profile).Error
 	}
@@ -169,7 +207,10 @@ func (r *repository) UpdateRiderRating(ctx context.Context, riderID string) erro
 	return r.db.WithContext(ctx).
 		Model(&models.RiderProfile{}).
 		Where("user_id = ?", riderID).
-		Update("rating", avgRating).Error
+		Updates(map[string]interface{}{
+			"rating":       avgRating,
+			"rating_count": count,
+		}).Error
 }
 
 func (r *repository) GetDriverRatingBreakdown(ctx context.Context, driverID string) (map[int]int, error) {
@@ -179,10 +220,10 @@ func (r *repository) GetDriverRatingBreakdown(ctx context.Context, driverID stri
 	}
 
 	err := r.db.WithContext(ctx).
-		Model(&models.Ride{}).
-		Select("driver_rating as rating, COUNT(*) as count").
-		Where("driver_id = ? AND driver_rating IS NOT NULL", driverID).
-		Group("driver_rating").
+		Model(&models.RideRating{}).
+		Select("score as rating, COUNT(*) as count").
+		Where("ratee_id = ? AND direction = ?", driverID, models.RideRatingOfDriver).
+		Group("score").
 		Scan(&results).Error
 
 	breakdown := make(map[int]int)
@@ -204,10 +245,10 @@ func (r *repository) GetRiderRatingBreakdown(ctx context.Context, riderID string
 	}
 
 	err := r.db.WithContext(ctx).
-		Model(&models.Ride{}).
-		Select("rider_rating as rating, COUNT(*) as count").
-		Where("rider_id = ? AND rider_rating IS NOT NULL", riderID).
-		Group("rider_rating").
+		Model(&models.RideRating{}).
+		Select("score as rating, COUNT(*) as count").
+		Where("ratee_id = ? AND direction = ?", riderID, models.RideRatingOfRider).
+		Group("score").
 		Scan(&results).Error
 
 	breakdown := make(map[int]int)
@@ -221,3 +262,125 @@ func (r *repository) GetRiderRatingBreakdown(ctx context.Context, riderID string
 
 	return breakdown, err
 }
+
+// RefreshProviderStats recomputes a home-service provider's rating and review
+// count from the ratings customers left on their completed orders.
+func (r *repository) RefreshProviderStats(ctx context.Context, providerID string) error {
+	return r.db.WithContext(ctx).Exec(`
+		UPDATE service_provider_profiles p
+		SET rating = agg.rating, total_reviews = agg.total, updated_at = NOW()
+		FROM (
+			SELECT COALESCE(ROUND(AVG(customer_rating)::NUMERIC, 2), 0) AS rating, COUNT(customer_rating) AS total
+			FROM service_orders
+			WHERE assigned_provider_id = ? AND customer_rating IS NOT NULL
+		) agg
+		WHERE p.id = ?
+	`, providerID, providerID).Error
+}
+
+func (r *repository) RefreshProviderCategoryStats(ctx context.Context, providerID, categorySlug string) error {
+	return r.db.WithContext(ctx).Exec(`
+		UPDATE provider_service_categories c
+		SET average_rating = agg.rating, total_ratings = agg.total, updated_at = NOW()
+		FROM (
+			SELECT COALESCE(ROUND(AVG(customer_rating)::NUMERIC, 2), 0) AS rating, COUNT(customer_rating) AS total
+			FROM service_orders
+			WHERE assigned_provider_id = ? AND category_slug = ? AND customer_rating IS NOT NULL
+		) agg
+		WHERE c.provider_id = ? AND c.category_slug = ?
+	`, providerID, categorySlug, providerID, categorySlug).Error
+}
+
+func (r *repository) RefreshCustomerStats(ctx context.Context, customerID string) error {
+	return r.db.WithContext(ctx).Exec(`
+		INSERT INTO customer_rating_stats (user_id, rating, total_reviews, updated_at)
+		SELECT ?, COALESCE(ROUND(AVG(provider_rating)::NUMERIC, 2), 0), COUNT(provider_rating), NOW()
+		FROM service_orders
+		WHERE customer_id = ? AND provider_rating IS NOT NULL
+		ON CONFLICT (user_id) DO UPDATE
+		SET rating = EXCLUDED.rating, total_reviews = EXCLUDED.total_reviews, updated_at = EXCLUDED.updated_at
+	`, customerID, customerID).Error
+}
+
+func (r *repository) GetCustomerStats(ctx context.Context, customerID string) (*models.CustomerRatingStats, error) {
+	var stats models.CustomerRatingStats
+	err := r.db.WithContext(ctx).Where("user_id = ?", customerID).First(&stats).Error
+	return &stats, err
+}
+
+// ReconcileProviderStats corrects every provider whose stored rating or review
+// count has drifted from their orders, returning how many were corrected.
+func (r *repository) ReconcileProviderStats(ctx context.Context) (int64, error) {
+	result := r.db.WithContext(ctx).Exec(`
+		UPDATE service_provider_profiles p
+		SET rating = agg.rating, total_reviews = agg.total, updated_at = NOW()
+		FROM (
+			SELECT sp.id,
+				COALESCE(ROUND(AVG(o.customer_rating)::NUMERIC, 2), 0) AS rating,
+				COUNT(o.customer_rating) AS total
+			FROM service_provider_profiles sp
+			LEFT JOIN service_orders o ON o.assigned_provider_id = sp.id AND o.customer_rating IS NOT NULL
+			GROUP BY sp.id
+		) agg
+		WHERE p.id = agg.id
+			AND (p.rating IS DISTINCT FROM agg.rating OR p.total_reviews IS DISTINCT FROM agg.total)
+	`)
+	return result.RowsAffected, result.Error
+}
+
+func (r *repository) ReconcileProviderCategoryStats(ctx context.Context) (int64, error) {
+	result := r.db.WithContext(ctx).Exec(`
+		UPDATE provider_service_categories c
+		SET average_rating = agg.rating, total_ratings = agg.total, updated_at = NOW()
+		FROM (
+			SELECT pc.id,
+				COALESCE(ROUND(AVG(o.customer_rating)::NUMERIC, 2), 0) AS rating,
+				COUNT(o.customer_rating) AS total
+			FROM provider_service_categories pc
+			LEFT JOIN service_orders o ON o.assigned_provider_id = pc.provider_id
+				AND o.category_slug = pc.category_slug
+				AND o.customer_rating IS NOT NULL
+			GROUP BY pc.id
+		) agg
+		WHERE c.id = agg.id
+			AND (c.average_rating IS DISTINCT FROM agg.rating OR c.total_ratings IS DISTINCT FROM agg.total)
+	`)
+	return result.RowsAffected, result.Error
+}
+
+// ReconcileCustomerStats adds stats for customers who have none yet, corrects
+// those that drifted and zeroes those whose rated orders are gone.
+func (r *repository) ReconcileCustomerStats(ctx context.Context) (int64, error) {
+	var corrected int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		upserted := tx.Exec(`
+			INSERT INTO customer_rating_stats (user_id, rating, total_reviews, updated_at)
+			SELECT customer_id, ROUND(AVG(provider_rating)::NUMERIC, 2), COUNT(*), NOW()
+			FROM service_orders
+			WHERE provider_rating IS NOT NULL
+			GROUP BY customer_id
+			ON CONFLICT (user_id) DO UPDATE
+			SET rating = EXCLUDED.rating, total_reviews = EXCLUDED.total_reviews, updated_at = EXCLUDED.updated_at
+			WHERE customer_rating_stats.rating IS DISTINCT FROM EXCLUDED.rating
+				OR customer_rating_stats.total_reviews IS DISTINCT FROM EXCLUDED.total_reviews
+		`)
+		if upserted.Error != nil {
+			return upserted.Error
+		}
+
+		zeroed := tx.Exec(`
+			UPDATE customer_rating_stats s
+			SET rating = 0, total_reviews = 0, updated_at = NOW()
+			WHERE s.total_reviews > 0 AND NOT EXISTS (
+				SELECT 1 FROM service_orders o WHERE o.customer_id = s.user_id AND o.provider_rating IS NOT NULL
+			)
+		`)
+		if zeroed.Error != nil {
+			return zeroed.Error
+		}
+
+		corrected = upserted.RowsAffected + zeroed.RowsAffected
+		return nil
+	})
+	return corrected, err
+}
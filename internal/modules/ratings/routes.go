@@ -2,6 +2,7 @@ package ratings
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/middleware"
 )
 
 func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
@@ -9,6 +10,7 @@ func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gi
 	ratings.Use(authMiddleware)
 	{
 		ratings.POST("", handler.CreateRating)
+		ratings.GET("/tags", handler.GetRatingTags)
 		ratings.GET("/driver/:driverId/stats", handler.GetDriverRatingStats)
 		ratings.GET("/driver/:driverId/breakdown", handler.GetDriverRatingBreakdown)
 
@@ -17,5 +19,11 @@ func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gi
 		ratings.POST("/rider", handler.RateRider)
 		ratings.GET("/rider/:riderId/stats", handler.GetRiderRatingStats)
 		ratings.GET("/rider/:riderId/breakdown", handler.GetRiderRatingBreakdown)
+		ratings.GET("/customer/:customerId/stats", handler.GetCustomerRatingStats)
+	}
+
+	admin := ratings.Group("/admin", middleware.RequireAdmin())
+	{
+		admin.POST("/reconcile", handler.ReconcileRatings)
 	}
 }
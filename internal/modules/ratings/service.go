@@ -6,6 +6,7 @@ import (
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 
+	"github.com/umar5678/go-backend/internal/config"
 	"github.com/umar5678/go-backend/internal/models"
 	"github.com/umar5678/go-backend/internal/modules/homeservices"
 	"github.com/umar5678/go-backend/internal/modules/ratings/dto"
@@ -21,12 +22,25 @@ type Service interface {
 	GetRiderRatingStats(ctx context.Context, riderID string) (*dto.RatingStatsResponse, error)
 	GetDriverRatingBreakdown(ctx context.Context, driverID string) (*dto.RatingBreakdownResponse, error)
 	GetRiderRatingBreakdown(ctx context.Context, riderID string) (*dto.RatingBreakdownResponse, error)
+	GetRatingTags() *dto.RatingTagsResponse
+
+	RefreshProviderRating(ctx context.Context, providerID, categorySlug string) error
+	RefreshCustomerRating(ctx context.Context, customerID string) error
+	GetCustomerRatingStats(ctx context.Context, customerID string) (*dto.CustomerRatingStatsResponse, error)
+	ReconcileRatings(ctx context.Context) (*dto.RatingReconciliationResponse, error)
+
+	ConfigureRideRatings(cfg config.RatingsConfig)
 }
 
+// defaultRollingWindow is how many recent ratings make up a profile's average
+// until ConfigureRideRatings says otherwise.
+const defaultRollingWindow = 100
+
 type service struct {
 	repo             Repository
 	db               *gorm.DB
 	homeServicesRepo homeservices.Repository
+	rollingWindow    int
 }
 
 func NewService(repo Repository, db *gorm.DB, homeServicesRepo homeservices.Repository) Service {
@@ -34,6 +48,20 @@ func NewService(repo Repository, db *gorm.DB, homeServicesRepo homeservices.Repo
 		repo:             repo,
 		homeServicesRepo: homeServicesRepo,
 		db:               db,
+		rollingWindow:    defaultRollingWindow,
+	}
+}
+
+func (s *service) ConfigureRideRatings(cfg config.RatingsConfig) {
+	if cfg.RollingWindow > 0 {
+		s.rollingWindow = cfg.RollingWindow
+	}
+}
+
+func (s *service) GetRatingTags() *dto.RatingTagsResponse {
+	return &dto.RatingTagsResponse{
+		Driver: models.DriverRatingTags,
+		Rider:  models.RiderRatingTags,
 	}
 }
 
@@ -113,17 +141,31 @@ func (s *service) RateDriver(ctx context.Context, riderID string, req dto.RateDr
 		return response.BadRequest("No driver assigned to this ride")
 	}
 
-	if err := s.repo.RateDriver(ctx, req.RideID, req.Rating, req.Comment); err != nil {
+	rating := &models.RideRating{
+		RideID:    ride.ID,
+		Direction: models.RideRatingOfDriver,
+		RaterID:   riderID,
+		RateeID:   *ride.DriverID,
+		Score:     req.Rating,
+		Tags:      req.Tags,
+		Comment:   req.Comment,
+	}
+	if err := s.repo.SaveRideRating(ctx, rating); err != nil {
 		logger.Error("failed to rate driver", "error", err, "rideID", req.RideID)
 		return response.InternalServerError("Failed to rate driver", err)
 	}
 
-	go s.repo.UpdateDriverRating(context.Background(), *ride.DriverID)
+	go func(driverID string) {
+		if err := s.repo.UpdateDriverRating(context.Background(), driverID, s.rollingWindow); err != nil {
+			logger.Error("failed to update driver rating", "error", err, "driverID", driverID)
+		}
+	}(*ride.DriverID)
 
 	logger.Info("driver rated",
 		"rideID", req.RideID,
 		"driverID", *ride.DriverID,
 		"rating", req.Rating,
+		"tags", req.Tags,
 	)
 
 	return nil
@@ -151,17 +193,31 @@ func (s *service) RateRider(ctx context.Context, driverID string, req dto.RateRi
 		return response.BadRequest("You have already rated this rider")
 	}
 
-	if err := s.repo.RateRider(ctx, req.RideID, req.Rating, req.Comment); err != nil {
+	rating := &models.RideRating{
+		RideID:    ride.ID,
+		Direction: models.RideRatingOfRider,
+		RaterID:   driverID,
+		RateeID:   ride.RiderID,
+		Score:     req.Rating,
+		Tags:      req.Tags,
+		Comment:   req.Comment,
+	}
+	if err := s.repo.SaveRideRating(ctx, rating); err != nil {
 		logger.Error("failed to rate rider", "error", err, "rideID", req.RideID)
 		return response.InternalServerError("Failed to rate rider", err)
 	}
 
-	go s.repo.UpdateRiderRating(context.Background(), ride.RiderID)
+	go func(riderID string) {
+		if err := s.repo.UpdateRiderRating(context.Background(), riderID, s.rollingWindow); err != nil {
+			logger.Error("failed to update rider rating", "error", err, "riderID", riderID)
+		}
+	}(ride.RiderID)
 
 	logger.Info("rider rated",
 		"rideID", req.RideID,
 		"riderID", ride.RiderID,
 		"rating", req.Rating,
+		"tags", req.Tags,
 	)
 
 	return nil
@@ -179,10 +235,11 @@ func (s *service) GetDriverRatingStats(ctx context.Context, driverID string) (*d
 func (s *service) GetRiderRatingStats(ctx context.Context, riderID string) (*dto.RatingStatsResponse, error) {
 	profile, err := s.repo.GetRiderProfile(ctx, riderID)
 	if err != nil {
-		rating, _ := s.repo.GetRiderRating(ctx, riderID)
+		rating, count, _ := s.repo.GetRollingRating(ctx, riderID, models.RideRatingOfRider, s.rollingWindow)
 		profile = &models.RiderProfile{
-			UserID: riderID,
-			Rating: rating,
+			UserID:      riderID,
+			Rating:      rating,
+			RatingCount: count,
 		}
 		s.repo.CreateRiderProfile(ctx, profile)
 	}
@@ -195,6 +252,10 @@ func (s *service) GetDriverRatingBreakdown(ctx context.Context, driverID string)
 	if err != nil {
 		return nil, response.InternalServerError("Failed to get rating breakdown", err)
 	}
+	tags, err := s.repo.GetRatingTagCounts(ctx, driverID, models.RideRatingOfDriver)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get rating breakdown", err)
+	}
 
 	totalRatings := 0
 	totalScore := 0
@@ -216,6 +277,7 @@ func (s *service) GetDriverRatingBreakdown(ctx context.Context, driverID string)
 		OneStar:       breakdown[1],
 		TotalRatings:  totalRatings,
 		AverageRating: avgRating,
+		Tags:          tags,
 	}, nil
 }
 
@@ -224,6 +286,10 @@ func (s *service) GetRiderRatingBreakdown(ctx context.Context, riderID string) (
 	if err != nil {
 		return nil, response.InternalServerError("Failed to get rating breakdown", err)
 	}
+	tags, err := s.repo.GetRatingTagCounts(ctx, riderID, models.RideRatingOfRider)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get rating breakdown", err)
+	}
 
 	totalRatings := 0
 	totalScore := 0
@@ -245,5 +311,6 @@ func (s *service) GetRiderRatingBreakdown(ctx context.Context, riderID string) (
 		OneStar:       breakdown[1],
 		TotalRatings:  totalRatings,
 		AverageRating: avgRating,
+		Tags:          tags,
 	}, nil
 }
@@ -0,0 +1,21 @@
+package dto
+
+// EmailReceiptRequest sends the receipt PDF by email. Email defaults to the
+// address on the rider's account.
+type EmailReceiptRequest struct {
+	Email string `json:"email" binding:"omitempty,email,max=255"`
+}
+
+type ListReceiptsRequest struct {
+	Page  int `form:"page" binding:"omitempty,min=1"`
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (r *ListReceiptsRequest) SetDefaults() {
+	if r.Page <= 0 {
+		r.Page = 1
+	}
+	if r.Limit <= 0 {
+		r.Limit = 20
+	}
+}
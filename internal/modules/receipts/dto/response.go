@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+type ReceiptResponse struct {
+	ID              string                   `json:"id"`
+	ReceiptNumber   string                   `json:"receiptNumber"`
+	RideID          string                   `json:"rideId"`
+	DriverName      string                   `json:"driverName"`
+	VehicleType     string                   `json:"vehicleType"`
+	PickupAddress   string                   `json:"pickupAddress"`
+	DropoffAddress  string                   `json:"dropoffAddress"`
+	DistanceKm      float64                  `json:"distanceKm"`
+	DurationMins    int                      `json:"durationMins"`
+	Currency        string                   `json:"currency"`
+	Charges         []models.ReceiptLineItem `json:"charges"`
+	Surcharges      []models.ReceiptLineItem `json:"surcharges"`
+	Discounts       []models.ReceiptLineItem `json:"discounts"`
+	Taxes           []models.ReceiptLineItem `json:"taxes"`
+	Subtotal        float64                  `json:"subtotal"`
+	SurchargesTotal float64                  `json:"surchargesTotal"`
+	DiscountsTotal  float64                  `json:"discountsTotal"`
+	TaxesTotal      float64                  `json:"taxesTotal"`
+	Total           float64                  `json:"total"`
+	PaymentMethod   string                   `json:"paymentMethod"`
+	PolicyNumber    string                   `json:"policyNumber,omitempty"`
+	TripStartedAt   *time.Time               `json:"tripStartedAt,omitempty"`
+	TripCompletedAt *time.Time               `json:"tripCompletedAt,omitempty"`
+	IssuedAt        time.Time                `json:"issuedAt"`
+	EmailedTo       *string                  `json:"emailedTo,omitempty"`
+	EmailedAt       *time.Time               `json:"emailedAt,omitempty"`
+}
+
+func ToReceiptResponse(r *models.RideReceipt) *ReceiptResponse {
+	return &ReceiptResponse{
+		ID:              r.ID,
+		ReceiptNumber:   r.ReceiptNumber,
+		RideID:          r.RideID,
+		DriverName:      r.DriverName,
+		VehicleType:     r.VehicleType,
+		PickupAddress:   r.PickupAddress,
+		DropoffAddress:  r.DropoffAddress,
+		DistanceKm:      r.DistanceKm,
+		DurationMins:    r.DurationMins,
+		Currency:        r.Currency,
+		Charges:         nonNilLines(r.Charges),
+		Surcharges:      nonNilLines(r.Surcharges),
+		Discounts:       nonNilLines(r.Discounts),
+		Taxes:           nonNilLines(r.Taxes),
+		Subtotal:        r.Subtotal,
+		SurchargesTotal: r.SurchargesTotal,
+		DiscountsTotal:  r.DiscountsTotal,
+		TaxesTotal:      r.TaxesTotal,
+		Total:           r.Total,
+		PaymentMethod:   r.PaymentMethod,
+		PolicyNumber:    r.PolicyNumber,
+		TripStartedAt:   r.TripStartedAt,
+		TripCompletedAt: r.TripCompletedAt,
+		IssuedAt:        r.IssuedAt,
+		EmailedTo:       r.EmailedTo,
+		EmailedAt:       r.EmailedAt,
+	}
+}
+
+type EmailReceiptResponse struct {
+	ReceiptNumber string    `json:"receiptNumber"`
+	EmailedTo     string    `json:"emailedTo"`
+	EmailedAt     time.Time `json:"emailedAt"`
+}
+
+func nonNilLines(items models.ReceiptLineItems) []models.ReceiptLineItem {
+	if items == nil {
+		return []models.ReceiptLineItem{}
+	}
+	return items
+}
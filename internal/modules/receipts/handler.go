@@ -0,0 +1,128 @@
+package receipts
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/modules/receipts/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// GetRideReceipt godoc
+// @Summary Get the itemized receipt for a completed ride
+// @Tags receipts
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Ride ID"
+// @Success 200 {object} response.Response{data=dto.ReceiptResponse}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /rides/{id}/receipt [get]
+func (h *Handler) GetRideReceipt(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+
+	receipt, err := h.service.GetRideReceipt(c.Request.Context(), userID.(string), c.Param("id"), role == "admin")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, receipt, "Receipt retrieved successfully")
+}
+
+// DownloadRideReceipt godoc
+// @Summary Download the ride receipt as PDF
+// @Tags receipts
+// @Security BearerAuth
+// @Produce application/pdf
+// @Param id path string true "Ride ID"
+// @Success 200 {file} file
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /rides/{id}/receipt/pdf [get]
+func (h *Handler) DownloadRideReceipt(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+
+	pdf, filename, err := h.service.GetRideReceiptPDF(c.Request.Context(), userID.(string), c.Param("id"), role == "admin")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+// EmailRideReceipt godoc
+// @Summary Email the ride receipt
+// @Description Sends the PDF receipt to the given address, or to the rider's account email
+// @Tags receipts
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Ride ID"
+// @Param request body dto.EmailReceiptRequest false "Recipient"
+// @Success 200 {object} response.Response{data=dto.EmailReceiptResponse}
+// @Failure 400 {object} response.Response
+// @Failure 503 {object} response.Response
+// @Router /rides/{id}/receipt/email [post]
+func (h *Handler) EmailRideReceipt(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req dto.EmailReceiptRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+			return
+		}
+	}
+
+	result, err := h.service.EmailRideReceipt(c.Request.Context(), userID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Receipt sent successfully")
+}
+
+// ListReceipts godoc
+// @Summary List the rider's ride receipts
+// @Tags receipts
+// @Security BearerAuth
+// @Produce json
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Response{data=[]dto.ReceiptResponse}
+// @Router /receipts [get]
+func (h *Handler) ListReceipts(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req dto.ListReceiptsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+	req.SetDefaults()
+
+	receipts, total, err := h.service.ListReceipts(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pagination := response.NewPaginationMeta(total, req.Page, req.Limit)
+	response.Paginated(c, receipts, pagination, "Receipts retrieved successfully")
+}
@@ -0,0 +1,111 @@
+package receipts
+
+import (
+	"fmt"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/utils/pdf"
+)
+
+func renderReceiptPDF(cfg config.ReceiptsConfig, receipt *models.RideReceipt) []byte {
+	const (
+		left  = 50.0
+		right = pdf.PageWidth - 50.0
+	)
+
+	doc := pdf.NewDocument()
+	y := 60.0
+
+	doc.Text(left, y, 18, true, cfg.CompanyName)
+	doc.TextRight(right, y, 14, true, "RIDE RECEIPT")
+	y += 16
+	if cfg.CompanyAddress != "" {
+		doc.Text(left, y, 9, false, cfg.CompanyAddress)
+		y += 12
+	}
+	if cfg.TaxID != "" {
+		doc.Text(left, y, 9, false, "Tax ID: "+cfg.TaxID)
+		y += 12
+	}
+
+	y += 12
+	doc.Text(left, y, 10, false, "Receipt number: "+receipt.ReceiptNumber)
+	doc.TextRight(right, y, 10, false, "Issued: "+receipt.IssuedAt.Format("02 Jan 2006"))
+	y += 14
+	doc.Text(left, y, 10, false, "Ride ID: "+receipt.RideID)
+	y += 20
+	doc.Rule(left, right, y)
+	y += 20
+
+	doc.Text(left, y, 11, true, "Trip details")
+	y += 16
+	details := [][2]string{
+		{"Pickup", receipt.PickupAddress},
+		{"Dropoff", receipt.DropoffAddress},
+		{"Distance", fmt.Sprintf("%.2f km", receipt.DistanceKm)},
+		{"Duration", fmt.Sprintf("%d min", receipt.DurationMins)},
+	}
+	if receipt.TripStartedAt != nil {
+		details = append(details, [2]string{"Started", receipt.TripStartedAt.Format("02 Jan 2006 15:04")})
+	}
+	if receipt.TripCompletedAt != nil {
+		details = append(details, [2]string{"Completed", receipt.TripCompletedAt.Format("02 Jan 2006 15:04")})
+	}
+	if receipt.DriverName != "" {
+		details = append(details, [2]string{"Driver", receipt.DriverName})
+	}
+	if receipt.VehicleType != "" {
+		details = append(details, [2]string{"Vehicle", receipt.VehicleType})
+	}
+	for _, d := range details {
+		doc.Text(left, y, 10, false, d[0])
+		doc.Text(left+90, y, 10, false, pdf.Truncate(d[1], 80))
+		y += 14
+	}
+
+	y += 10
+	doc.Rule(left, right, y)
+	y += 20
+
+	doc.Text(left, y, 11, true, "Fare")
+	y += 16
+	line := func(label string, amount float64, bold bool) {
+		doc.Text(left, y, 10, bold, label)
+		doc.TextRight(right, y, 10, bold, formatMoney(receipt.Currency, amount))
+		y += 14
+	}
+	for _, item := range receipt.Charges {
+		line(item.Label, item.Amount, false)
+	}
+	for _, item := range receipt.Surcharges {
+		line(item.Label, item.Amount, false)
+	}
+	for _, item := range receipt.Discounts {
+		line(item.Label, -item.Amount, false)
+	}
+	for _, item := range receipt.Taxes {
+		line(item.Label, item.Amount, false)
+	}
+
+	y += 4
+	doc.Rule(left, right, y)
+	y += 18
+	line("Total", receipt.Total, true)
+
+	paymentMethod := receipt.PaymentMethod
+	if paymentMethod == "" {
+		paymentMethod = models.RidePaymentWallet
+	}
+	doc.Text(left, y, 10, false, "Paid by "+paymentMethod)
+	y += 14
+	if receipt.PolicyNumber != "" {
+		doc.Text(left, y, 9, false, "Trip insured under policy "+receipt.PolicyNumber)
+		y += 14
+	}
+
+	y += 20
+	doc.Text(left, y, 9, false, "Thank you for riding with "+cfg.CompanyName+".")
+
+	return doc.Bytes()
+}
@@ -0,0 +1,115 @@
+package receipts
+
+import (
+	"context"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository interface {
+	FindRideByID(ctx context.Context, rideID string) (*models.Ride, error)
+	FindFareSnapshotByRideID(ctx context.Context, rideID string) (*models.RideFareSnapshot, error)
+	FindPolicyNumber(ctx context.Context, rideID string) (string, error)
+	FindUserByID(ctx context.Context, userID string) (*models.User, error)
+
+	CreateReceipt(ctx context.Context, receipt *models.RideReceipt) error
+	FindReceiptByRideID(ctx context.Context, rideID string) (*models.RideReceipt, error)
+	ListRiderReceipts(ctx context.Context, riderID string, page, limit int) ([]*models.RideReceipt, int64, error)
+	MarkEmailed(ctx context.Context, receiptID, email string, emailedAt time.Time) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) FindRideByID(ctx context.Context, rideID string) (*models.Ride, error) {
+	var ride models.Ride
+	err := r.db.WithContext(ctx).
+		Preload("Driver").
+		Preload("VehicleType").
+		Where("id = ?", rideID).
+		First(&ride).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ride, nil
+}
+
+func (r *repository) FindFareSnapshotByRideID(ctx context.Context, rideID string) (*models.RideFareSnapshot, error) {
+	var snapshot models.RideFareSnapshot
+	err := r.db.WithContext(ctx).Where("ride_id = ?", rideID).First(&snapshot).Error
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+func (r *repository) FindPolicyNumber(ctx context.Context, rideID string) (string, error) {
+	var policyNumbers []string
+	err := r.db.WithContext(ctx).
+		Model(&models.RideInsurancePolicy{}).
+		Where("ride_id = ?", rideID).
+		Limit(1).
+		Pluck("policy_number", &policyNumbers).Error
+	if err != nil || len(policyNumbers) == 0 {
+		return "", err
+	}
+	return policyNumbers[0], nil
+}
+
+func (r *repository) FindUserByID(ctx context.Context, userID string) (*models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).Where("id = ?", userID).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// CreateReceipt inserts the receipt unless the ride already has one, so the
+// completion hook and a lazy fetch can race safely.
+func (r *repository) CreateReceipt(ctx context.Context, receipt *models.RideReceipt) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "ride_id"}}, DoNothing: true}).
+		Create(receipt).Error
+}
+
+func (r *repository) FindReceiptByRideID(ctx context.Context, rideID string) (*models.RideReceipt, error) {
+	var receipt models.RideReceipt
+	err := r.db.WithContext(ctx).Where("ride_id = ?", rideID).First(&receipt).Error
+	if err != nil {
+		return nil, err
+	}
+	return &receipt, nil
+}
+
+func (r *repository) ListRiderReceipts(ctx context.Context, riderID string, page, limit int) ([]*models.RideReceipt, int64, error) {
+	var receipts []*models.RideReceipt
+	var total int64
+
+	db := r.db.WithContext(ctx).Model(&models.RideReceipt{}).Where("rider_id = ?", riderID)
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := db.Order("issued_at DESC").Offset(offset).Limit(limit).Find(&receipts).Error
+	return receipts, total, err
+}
+
+func (r *repository) MarkEmailed(ctx context.Context, receiptID, email string, emailedAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.RideReceipt{}).
+		Where("id = ?", receiptID).
+		Updates(map[string]interface{}{
+			"emailed_to": email,
+			"emailed_at": emailedAt,
+		}).Error
+}
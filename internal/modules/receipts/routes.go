@@ -0,0 +1,21 @@
+package receipts
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
+	rides := router.Group("/rides")
+	rides.Use(authMiddleware)
+	{
+		rides.GET("/:id/receipt", handler.GetRideReceipt)
+		rides.GET("/:id/receipt/pdf", handler.DownloadRideReceipt)
+		rides.POST("/:id/receipt/email", handler.EmailRideReceipt)
+	}
+
+	receipts := router.Group("/receipts")
+	receipts.Use(authMiddleware)
+	{
+		receipts.GET("", handler.ListReceipts)
+	}
+}
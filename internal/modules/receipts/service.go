@@ -0,0 +1,360 @@
+package receipts
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/emails"
+	"github.com/umar5678/go-backend/internal/modules/pricing"
+	pricingdto "github.com/umar5678/go-backend/internal/modules/pricing/dto"
+	"github.com/umar5678/go-backend/internal/modules/receipts/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+// PreferenceChecker reports whether a user wants notifications of a category
+// on a channel.
+type PreferenceChecker interface {
+	Allows(ctx context.Context, userID uuid.UUID, category models.NotificationCategory, channel models.NotificationChannel) bool
+}
+
+// Mailer delivers receipt emails. It is satisfied by emails.Service; when it
+// is not enabled, receipts are only available in the app.
+type Mailer interface {
+	Enabled() bool
+	Send(ctx context.Context, req emails.SendRequest) (*models.EmailLog, error)
+}
+
+type Service interface {
+	SetPreferenceChecker(checker PreferenceChecker)
+
+	IssueReceipt(ctx context.Context, rideID string) (*models.RideReceipt, error)
+
+	GetRideReceipt(ctx context.Context, userID, rideID string, isAdmin bool) (*dto.ReceiptResponse, error)
+	GetRideReceiptPDF(ctx context.Context, userID, rideID string, isAdmin bool) ([]byte, string, error)
+	EmailRideReceipt(ctx context.Context, userID, rideID string, req dto.EmailReceiptRequest) (*dto.EmailReceiptResponse, error)
+	ListReceipts(ctx context.Context, riderID string, req dto.ListReceiptsRequest) ([]*dto.ReceiptResponse, int64, error)
+}
+
+type service struct {
+	repo   Repository
+	mailer Mailer
+	cfg    config.ReceiptsConfig
+	prefs  PreferenceChecker
+}
+
+func NewService(repo Repository, mailer Mailer, cfg config.ReceiptsConfig) Service {
+	return &service{
+		repo:   repo,
+		mailer: mailer,
+		cfg:    cfg,
+	}
+}
+
+func (s *service) SetPreferenceChecker(checker PreferenceChecker) {
+	s.prefs = checker
+}
+
+// wantsReceiptEmail reports whether the rider's preferences allow receipts to
+// be emailed without them asking. Receipts they request are always sent.
+func (s *service) wantsReceiptEmail(ctx context.Context, userID string) bool {
+	if s.prefs == nil {
+		return true
+	}
+	id, err := uuid.Parse(userID)
+	if err != nil {
+		return true
+	}
+	return s.prefs.Allows(ctx, id, models.NotificationCategoryTransactional, models.ChannelEmail)
+}
+
+// IssueReceipt builds the receipt for a completed ride from its fare snapshot.
+// It is idempotent; a ride only ever has one receipt.
+func (s *service) IssueReceipt(ctx context.Context, rideID string) (*models.RideReceipt, error) {
+	if existing, err := s.repo.FindReceiptByRideID(ctx, rideID); err == nil {
+		return existing, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	ride, err := s.repo.FindRideByID(ctx, rideID)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt, err := s.issue(ctx, ride)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.cfg.EmailOnComplete && s.mailerEnabled() && s.wantsReceiptEmail(ctx, ride.RiderID) {
+		rider, err := s.repo.FindUserByID(ctx, ride.RiderID)
+		if err == nil && rider.Email != nil && *rider.Email != "" {
+			if err := s.sendReceipt(ctx, receipt, *rider.Email, rider.Name); err != nil {
+				logger.Warn("failed to email ride receipt", "error", err, "rideID", rideID)
+			}
+		}
+	}
+
+	return receipt, nil
+}
+
+func (s *service) issue(ctx context.Context, ride *models.Ride) (*models.RideReceipt, error) {
+	if ride.Status != "completed" {
+		return nil, fmt.Errorf("ride %s is not completed", ride.ID)
+	}
+
+	var breakdown *pricingdto.FeeBreakdownResponse
+	snapshot, err := s.repo.FindFareSnapshotByRideID(ctx, ride.ID)
+	switch {
+	case err == nil:
+		breakdown = pricing.BuildRideFareBreakdown(ride, snapshot, pricing.FeeViewerCustomer)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		breakdown = pricing.BuildRideFareBreakdownFromRide(ride, pricing.FeeViewerCustomer)
+	default:
+		return nil, err
+	}
+
+	number, err := s.receiptNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := &models.RideReceipt{
+		ReceiptNumber:   number,
+		RideID:          ride.ID,
+		RiderID:         ride.RiderID,
+		DriverID:        ride.DriverID,
+		VehicleType:     ride.VehicleType.DisplayName,
+		PickupAddress:   ride.PickupAddress,
+		DropoffAddress:  ride.DropoffAddress,
+		DistanceKm:      ride.EstimatedDistance,
+		DurationMins:    ride.EstimatedDuration / 60,
+		Currency:        breakdown.Currency,
+		Charges:         toLineItems(breakdown.Charges),
+		Surcharges:      toLineItems(breakdown.Surcharges),
+		Discounts:       toLineItems(breakdown.Discounts),
+		Taxes:           toLineItems(breakdown.Taxes),
+		Subtotal:        breakdown.Subtotal,
+		SurchargesTotal: breakdown.SurchargesTotal,
+		DiscountsTotal:  breakdown.DiscountsTotal,
+		TaxesTotal:      breakdown.TaxesTotal,
+		Total:           breakdown.TotalCharged,
+		PaymentMethod:   ride.PaymentMethod,
+		TripStartedAt:   ride.StartedAt,
+		TripCompletedAt: ride.CompletedAt,
+		IssuedAt:        time.Now().UTC(),
+	}
+	if ride.Driver != nil {
+		receipt.DriverName = ride.Driver.Name
+	}
+	if ride.ActualDistance != nil {
+		receipt.DistanceKm = *ride.ActualDistance
+	}
+	if ride.ActualDuration != nil {
+		receipt.DurationMins = (*ride.ActualDuration + 30) / 60
+	}
+	if policyNumber, err := s.repo.FindPolicyNumber(ctx, ride.ID); err == nil {
+		receipt.PolicyNumber = policyNumber
+	}
+
+	if err := s.repo.CreateReceipt(ctx, receipt); err != nil {
+		return nil, err
+	}
+
+	// On a lost race the insert was skipped; return the receipt that won.
+	stored, err := s.repo.FindReceiptByRideID(ctx, ride.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("ride receipt issued",
+		"rideID", ride.ID,
+		"receiptNumber", stored.ReceiptNumber,
+		"total", stored.Total,
+	)
+
+	return stored, nil
+}
+
+func (s *service) GetRideReceipt(ctx context.Context, userID, rideID string, isAdmin bool) (*dto.ReceiptResponse, error) {
+	receipt, err := s.loadReceipt(ctx, userID, rideID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToReceiptResponse(receipt), nil
+}
+
+func (s *service) GetRideReceiptPDF(ctx context.Context, userID, rideID string, isAdmin bool) ([]byte, string, error) {
+	receipt, err := s.loadReceipt(ctx, userID, rideID, isAdmin)
+	if err != nil {
+		return nil, "", err
+	}
+	return renderReceiptPDF(s.cfg, receipt), receiptFilename(receipt), nil
+}
+
+func (s *service) EmailRideReceipt(ctx context.Context, userID, rideID string, req dto.EmailReceiptRequest) (*dto.EmailReceiptResponse, error) {
+	if !s.mailerEnabled() {
+		return nil, response.ServiceUnavailable("Email delivery is not configured")
+	}
+
+	receipt, err := s.loadReceipt(ctx, userID, rideID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	rider, err := s.repo.FindUserByID(ctx, userID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to fetch user", err)
+	}
+
+	email := strings.TrimSpace(req.Email)
+	if email == "" && rider.Email != nil {
+		email = *rider.Email
+	}
+	if email == "" {
+		return nil, response.BadRequest("No email address on your account; provide one to send the receipt")
+	}
+
+	if err := s.sendReceipt(ctx, receipt, email, rider.Name); err != nil {
+		logger.Error("failed to email ride receipt", "error", err, "rideID", rideID)
+		return nil, response.ServiceUnavailable("Failed to send receipt email, please try again")
+	}
+
+	return &dto.EmailReceiptResponse{
+		ReceiptNumber: receipt.ReceiptNumber,
+		EmailedTo:     email,
+		EmailedAt:     *receipt.EmailedAt,
+	}, nil
+}
+
+func (s *service) ListReceipts(ctx context.Context, riderID string, req dto.ListReceiptsRequest) ([]*dto.ReceiptResponse, int64, error) {
+	req.SetDefaults()
+
+	receipts, total, err := s.repo.ListRiderReceipts(ctx, riderID, req.Page, req.Limit)
+	if err != nil {
+		return nil, 0, response.InternalServerError("Failed to fetch receipts", err)
+	}
+
+	result := make([]*dto.ReceiptResponse, len(receipts))
+	for i, receipt := range receipts {
+		result[i] = dto.ToReceiptResponse(receipt)
+	}
+	return result, total, nil
+}
+
+// loadReceipt returns the ride's receipt, issuing it on first access for rides
+// that completed before receipts existed or whose completion hook failed.
+func (s *service) loadReceipt(ctx context.Context, userID, rideID string, isAdmin bool) (*models.RideReceipt, error) {
+	receipt, err := s.repo.FindReceiptByRideID(ctx, rideID)
+	if err == nil {
+		if !isAdmin && receipt.RiderID != userID {
+			return nil, response.ForbiddenError("Not authorized to view this receipt")
+		}
+		return receipt, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, response.InternalServerError("Failed to fetch receipt", err)
+	}
+
+	ride, err := s.repo.FindRideByID(ctx, rideID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Ride")
+		}
+		return nil, response.InternalServerError("Failed to fetch ride", err)
+	}
+	if !isAdmin && ride.RiderID != userID {
+		return nil, response.ForbiddenError("Not authorized to view this receipt")
+	}
+	if ride.Status != "completed" {
+		return nil, response.BadRequest("A receipt is available once the ride is completed")
+	}
+
+	receipt, err = s.issue(ctx, ride)
+	if err != nil {
+		logger.Error("failed to issue ride receipt", "error", err, "rideID", rideID)
+		return nil, response.InternalServerError("Failed to generate receipt", err)
+	}
+	return receipt, nil
+}
+
+func (s *service) mailerEnabled() bool {
+	return s.mailer != nil && s.mailer.Enabled()
+}
+
+func (s *service) sendReceipt(ctx context.Context, receipt *models.RideReceipt, email, name string) error {
+	data := map[string]interface{}{
+		"ReceiptNumber": receipt.ReceiptNumber,
+		"From":          receipt.PickupAddress,
+		"To":            receipt.DropoffAddress,
+		"Total":         formatMoney(receipt.Currency, receipt.Total),
+	}
+	if receipt.TripCompletedAt != nil {
+		data["Date"] = receipt.TripCompletedAt.Format("02 Jan 2006 15:04")
+	}
+
+	_, err := s.mailer.Send(ctx, emails.SendRequest{
+		UserID:   receipt.RiderID,
+		To:       email,
+		ToName:   name,
+		Template: emails.TemplateRideReceipt,
+		Data:     data,
+		Attachments: []emails.Attachment{{
+			Filename:    receiptFilename(receipt),
+			ContentType: "application/pdf",
+			Content:     renderReceiptPDF(s.cfg, receipt),
+		}},
+		ReferenceType: "ride_receipt",
+		ReferenceID:   receipt.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	emailedAt := time.Now().UTC()
+	if err := s.repo.MarkEmailed(ctx, receipt.ID, email, emailedAt); err != nil {
+		logger.Warn("failed to record receipt email", "error", err, "receiptID", receipt.ID)
+	}
+	receipt.EmailedTo = &email
+	receipt.EmailedAt = &emailedAt
+
+	logger.Info("ride receipt emailed", "rideID", receipt.RideID, "receiptNumber", receipt.ReceiptNumber)
+	return nil
+}
+
+func (s *service) receiptNumber() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s-%s-%s", s.cfg.NumberPrefix, time.Now().UTC().Format("20060102"), strings.ToUpper(hex.EncodeToString(b))), nil
+}
+
+func toLineItems(items []pricingdto.FeeLineItem) models.ReceiptLineItems {
+	lines := make(models.ReceiptLineItems, len(items))
+	for i, item := range items {
+		lines[i] = models.ReceiptLineItem{Code: item.Code, Label: item.Label, Amount: item.Amount}
+	}
+	return lines
+}
+
+func receiptFilename(receipt *models.RideReceipt) string {
+	return fmt.Sprintf("receipt-%s.pdf", receipt.ReceiptNumber)
+}
+
+func formatMoney(currency string, amount float64) string {
+	if amount < 0 {
+		return fmt.Sprintf("-%s %.2f", currency, -amount)
+	}
+	return fmt.Sprintf("%s %.2f", currency, amount)
+}
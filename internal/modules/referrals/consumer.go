@@ -0,0 +1,8 @@
+package referrals
+
+import "github.com/umar5678/go-backend/internal/services/eventbus"
+
+// Subscribe rewards referrals from completed rides and orders on the bus.
+func Subscribe(bus eventbus.Bus, service Service) error {
+	return bus.Subscribe("referrals", service.HandleEvent, eventbus.RideCompleted, eventbus.OrderCompleted)
+}
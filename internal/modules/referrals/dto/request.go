@@ -0,0 +1,23 @@
+package dto
+
+type ApplyReferralRequest struct {
+	ReferralCode string `json:"referralCode" binding:"required,max=20"`
+}
+
+type ListReferralsRequest struct {
+	Status string `form:"status" binding:"omitempty,oneof=pending rewarded rejected expired"`
+	Page   int    `form:"page" binding:"omitempty,min=1"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (r *ListReferralsRequest) SetDefaults() {
+	if r.Page <= 0 {
+		r.Page = 1
+	}
+	if r.Limit <= 0 {
+		r.Limit = 20
+	}
+	if r.Limit > 100 {
+		r.Limit = 100
+	}
+}
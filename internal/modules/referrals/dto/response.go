@@ -0,0 +1,145 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+// ReferralSummaryResponse is a user's referral code, the rewards on offer and
+// how their referrals have done.
+type ReferralSummaryResponse struct {
+	ReferralCode   string  `json:"referralCode"`
+	ReferrerReward float64 `json:"referrerReward"`
+	RefereeReward  float64 `json:"refereeReward"`
+	QualifyDays    int     `json:"qualifyDays"`
+
+	TotalReferrals    int64   `json:"totalReferrals"`
+	PendingReferrals  int64   `json:"pendingReferrals"`
+	RewardedReferrals int64   `json:"rewardedReferrals"`
+	RejectedReferrals int64   `json:"rejectedReferrals"`
+	ExpiredReferrals  int64   `json:"expiredReferrals"`
+	TotalEarnings     float64 `json:"totalEarnings"`
+
+	// ReferredBy is the referral the user signed up with, if any.
+	ReferredBy *ReferredByResponse `json:"referredBy,omitempty"`
+}
+
+type ReferredByResponse struct {
+	ReferrerName  string     `json:"referrerName"`
+	Code          string     `json:"code"`
+	Status        string     `json:"status"`
+	RefereeReward float64    `json:"refereeReward"`
+	QualifyBy     time.Time  `json:"qualifyBy"`
+	RewardedAt    *time.Time `json:"rewardedAt,omitempty"`
+}
+
+// ReferralResponse is one of the user's referrals. Referees are shown by
+// first name only.
+type ReferralResponse struct {
+	ID              string     `json:"id"`
+	RefereeName     string     `json:"refereeName"`
+	Status          string     `json:"status"`
+	RejectionReason *string    `json:"rejectionReason,omitempty"`
+	QualifyBy       time.Time  `json:"qualifyBy"`
+	ReferrerReward  float64    `json:"referrerReward"`
+	RewardedAt      *time.Time `json:"rewardedAt,omitempty"`
+	CreatedAt       time.Time  `json:"createdAt"`
+}
+
+// AdminReferralResponse shows admins both sides of a referral and what it
+// was rewarded for.
+type AdminReferralResponse struct {
+	ID              string     `json:"id"`
+	ReferrerID      string     `json:"referrerId"`
+	ReferrerName    string     `json:"referrerName,omitempty"`
+	RefereeID       string     `json:"refereeId"`
+	RefereeName     string     `json:"refereeName,omitempty"`
+	Code            string     `json:"code"`
+	Status          string     `json:"status"`
+	RejectionReason *string    `json:"rejectionReason,omitempty"`
+	DeviceID        *string    `json:"deviceId,omitempty"`
+	QualifyBy       time.Time  `json:"qualifyBy"`
+	QualifyingType  *string    `json:"qualifyingType,omitempty"`
+	QualifyingID    *string    `json:"qualifyingId,omitempty"`
+	ReferrerReward  float64    `json:"referrerReward"`
+	RefereeReward   float64    `json:"refereeReward"`
+	RewardedAt      *time.Time `json:"rewardedAt,omitempty"`
+	CreatedAt       time.Time  `json:"createdAt"`
+}
+
+// ReferralStatus is the referral's status as the user sees it: a pending
+// referral whose window has passed shows as expired.
+func ReferralStatus(referral *models.Referral, now time.Time) string {
+	if referral.Status == models.ReferralStatusPending && now.After(referral.QualifyBy) {
+		return models.ReferralStatusExpired
+	}
+	return referral.Status
+}
+
+func ToReferralResponse(referral *models.Referral, now time.Time) *ReferralResponse {
+	resp := &ReferralResponse{
+		ID:              referral.ID,
+		Status:          ReferralStatus(referral, now),
+		RejectionReason: referral.RejectionReason,
+		QualifyBy:       referral.QualifyBy,
+		ReferrerReward:  referral.ReferrerReward,
+		RewardedAt:      referral.RewardedAt,
+		CreatedAt:       referral.CreatedAt,
+	}
+	if referral.Referee != nil {
+		resp.RefereeName = firstName(referral.Referee.Name)
+	}
+	return resp
+}
+
+func ToReferralResponses(referrals []*models.Referral, now time.Time) []*ReferralResponse {
+	result := make([]*ReferralResponse, 0, len(referrals))
+	for _, referral := range referrals {
+		result = append(result, ToReferralResponse(referral, now))
+	}
+	return result
+}
+
+func ToAdminReferralResponse(referral *models.Referral, now time.Time) *AdminReferralResponse {
+	resp := &AdminReferralResponse{
+		ID:              referral.ID,
+		ReferrerID:      referral.ReferrerID,
+		RefereeID:       referral.RefereeID,
+		Code:            referral.Code,
+		Status:          ReferralStatus(referral, now),
+		RejectionReason: referral.RejectionReason,
+		DeviceID:        referral.DeviceID,
+		QualifyBy:       referral.QualifyBy,
+		QualifyingType:  referral.QualifyingType,
+		QualifyingID:    referral.QualifyingID,
+		ReferrerReward:  referral.ReferrerReward,
+		RefereeReward:   referral.RefereeReward,
+		RewardedAt:      referral.RewardedAt,
+		CreatedAt:       referral.CreatedAt,
+	}
+	if referral.Referrer != nil {
+		resp.ReferrerName = referral.Referrer.Name
+	}
+	if referral.Referee != nil {
+		resp.RefereeName = referral.Referee.Name
+	}
+	return resp
+}
+
+func ToAdminReferralResponses(referrals []*models.Referral, now time.Time) []*AdminReferralResponse {
+	result := make([]*AdminReferralResponse, 0, len(referrals))
+	for _, referral := range referrals {
+		result = append(result, ToAdminReferralResponse(referral, now))
+	}
+	return result
+}
+
+func firstName(name string) string {
+	for i, r := range name {
+		if r == ' ' {
+			return name[:i]
+		}
+	}
+	return name
+}
@@ -0,0 +1,123 @@
+package referrals
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/modules/referrals/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// GetSummary godoc
+// @Summary Get my referral summary
+// @Description The user's referral code, the rewards on offer, how their referrals have done and what they earned
+// @Tags referrals
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=dto.ReferralSummaryResponse}
+// @Router /referrals/me [get]
+func (h *Handler) GetSummary(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	summary, err := h.service.GetSummary(c.Request.Context(), userID.(string))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, summary, "Referral summary retrieved successfully")
+}
+
+// ListMyReferrals godoc
+// @Summary List my referrals
+// @Description Users who signed up with the user's code, newest first
+// @Tags referrals
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "pending, rewarded, rejected or expired"
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Success 200 {object} response.Response{data=[]dto.ReferralResponse}
+// @Router /referrals [get]
+func (h *Handler) ListMyReferrals(c *gin.Context) {
+	var req dto.ListReferralsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+	req.SetDefaults()
+
+	userID, _ := c.Get("userID")
+
+	referrals, total, err := h.service.ListMyReferrals(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Paginated(c, referrals, response.NewPaginationMeta(total, req.Page, req.Limit), "Referrals retrieved successfully")
+}
+
+// ApplyCode godoc
+// @Summary Apply a referral code
+// @Description For users who did not enter a code at signup. Both sides are rewarded once the user completes their first ride or order
+// @Tags referrals
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param X-Device-ID header string false "Stable device identifier"
+// @Param request body dto.ApplyReferralRequest true "Referral code"
+// @Success 200 {object} response.Response
+// @Failure 400 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /referrals/apply [post]
+func (h *Handler) ApplyCode(c *gin.Context) {
+	var req dto.ApplyReferralRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	userID, _ := c.Get("userID")
+
+	if err := h.service.ApplyCode(c.Request.Context(), userID.(string), req.ReferralCode, c.GetHeader("X-Device-ID")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, nil, "Referral code applied successfully")
+}
+
+// ListReferrals godoc
+// @Summary List referrals (admin)
+// @Description All referrals with both sides, fraud rejections and what they were rewarded for
+// @Tags admin-referrals
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "pending, rewarded, rejected or expired"
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Success 200 {object} response.Response{data=[]dto.AdminReferralResponse}
+// @Router /referrals/admin [get]
+func (h *Handler) ListReferrals(c *gin.Context) {
+	var req dto.ListReferralsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+	req.SetDefaults()
+
+	referrals, total, err := h.service.ListReferrals(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Paginated(c, referrals, response.NewPaginationMeta(total, req.Page, req.Limit), "Referrals retrieved successfully")
+}
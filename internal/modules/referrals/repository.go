@@ -0,0 +1,279 @@
+package referrals
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+// ReferralStats sums up a referrer's referrals.
+type ReferralStats struct {
+	Total    int64
+	Pending  int64
+	Rewarded int64
+	Rejected int64
+	Expired  int64
+	Earnings float64
+}
+
+type Repository interface {
+	FindUserByReferralCode(ctx context.Context, code string) (*models.User, error)
+	FindUserByID(ctx context.Context, userID string) (*models.User, error)
+	SetReferredBy(ctx context.Context, userID, code string) error
+
+	CreateReferral(ctx context.Context, referral *models.Referral) error
+	GetReferralByReferee(ctx context.Context, refereeID string) (*models.Referral, error)
+	ListReferralsByReferrer(ctx context.Context, referrerID, status string, page, limit int) ([]*models.Referral, int64, error)
+	ListReferrals(ctx context.Context, status string, page, limit int) ([]*models.Referral, int64, error)
+	GetReferrerStats(ctx context.Context, referrerID string) (*ReferralStats, error)
+	CountRewardedReferrals(ctx context.Context, referrerID string) (int64, error)
+
+	ClaimReferral(ctx context.Context, referral *models.Referral) (bool, error)
+	ReleaseReferral(ctx context.Context, referralID string) error
+	ExpireReferral(ctx context.Context, referralID string) error
+	HasRewardTransaction(ctx context.Context, userID, referenceType, referralID string) (bool, error)
+
+	LastDeviceID(ctx context.Context, userID string) (string, error)
+	DeviceUsedByUser(ctx context.Context, deviceID, userID string) (bool, error)
+	DeviceUsedByOtherAccount(ctx context.Context, deviceID, userID string) (bool, error)
+	PhoneHashReferred(ctx context.Context, phoneHash, userID string) (bool, error)
+	HasCompletedTrip(ctx context.Context, userID string) (bool, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) FindUserByReferralCode(ctx context.Context, code string) (*models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).Where("referral_code = ?", code).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *repository) FindUserByID(ctx context.Context, userID string) (*models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).Where("id = ?", userID).First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// SetReferredBy keeps users.referred_by in step with the referral, as the
+// profile's referral stats still count from it.
+func (r *repository) SetReferredBy(ctx context.Context, userID, code string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.User{}).
+		Where("id = ?", userID).
+		Update("referred_by", code).Error
+}
+
+func (r *repository) CreateReferral(ctx context.Context, referral *models.Referral) error {
+	return r.db.WithContext(ctx).Omit("Referrer", "Referee").Create(referral).Error
+}
+
+func (r *repository) GetReferralByReferee(ctx context.Context, refereeID string) (*models.Referral, error) {
+	var referral models.Referral
+	err := r.db.WithContext(ctx).
+		Preload("Referrer").
+		Where("referee_id = ?", refereeID).
+		First(&referral).Error
+	if err != nil {
+		return nil, err
+	}
+	return &referral, nil
+}
+
+func (r *repository) ListReferralsByReferrer(ctx context.Context, referrerID, status string, page, limit int) ([]*models.Referral, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.Referral{}).Where("referrer_id = ?", referrerID)
+	return r.page(withStatus(query, status).Preload("Referee"), page, limit)
+}
+
+func (r *repository) ListReferrals(ctx context.Context, status string, page, limit int) ([]*models.Referral, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.Referral{})
+	return r.page(withStatus(query, status).Preload("Referrer").Preload("Referee"), page, limit)
+}
+
+// withStatus filters by the status users see, where a pending referral past
+// its qualifying window counts as expired.
+func withStatus(query *gorm.DB, status string) *gorm.DB {
+	switch status {
+	case "":
+		return query
+	case models.ReferralStatusPending:
+		return query.Where("status = ? AND qualify_by >= NOW()", status)
+	case models.ReferralStatusExpired:
+		return query.Where("status = ? OR (status = ? AND qualify_by < NOW())", status, models.ReferralStatusPending)
+	default:
+		return query.Where("status = ?", status)
+	}
+}
+
+func (r *repository) page(query *gorm.DB, page, limit int) ([]*models.Referral, int64, error) {
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var referrals []*models.Referral
+	err := query.
+		Order("created_at DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&referrals).Error
+	return referrals, total, err
+}
+
+func (r *repository) GetReferrerStats(ctx context.Context, referrerID string) (*ReferralStats, error) {
+	var stats ReferralStats
+	err := r.db.WithContext(ctx).
+		Model(&models.Referral{}).
+		Select(`COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE status = ? AND qualify_by >= NOW()) AS pending,
+			COUNT(*) FILTER (WHERE status = ?) AS rewarded,
+			COUNT(*) FILTER (WHERE status = ?) AS rejected,
+			COUNT(*) FILTER (WHERE status = ? OR (status = ? AND qualify_by < NOW())) AS expired,
+			COALESCE(SUM(referrer_reward) FILTER (WHERE status = ?), 0) AS earnings`,
+			models.ReferralStatusPending, models.ReferralStatusRewarded, models.ReferralStatusRejected,
+			models.ReferralStatusExpired, models.ReferralStatusPending, models.ReferralStatusRewarded).
+		Where("referrer_id = ?", referrerID).
+		Scan(&stats).Error
+	return &stats, err
+}
+
+func (r *repository) CountRewardedReferrals(ctx context.Context, referrerID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Referral{}).
+		Where("referrer_id = ? AND status = ? AND referrer_reward > 0", referrerID, models.ReferralStatusRewarded).
+		Count(&count).Error
+	return count, err
+}
+
+// ClaimReferral marks a pending referral rewarded with the qualifying trip
+// and amounts set on it. It reports false when the referral was no longer
+// pending, so each referral is rewarded once.
+func (r *repository) ClaimReferral(ctx context.Context, referral *models.Referral) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.Referral{}).
+		Where("id = ? AND status = ?", referral.ID, models.ReferralStatusPending).
+		Updates(map[string]interface{}{
+			"status":          models.ReferralStatusRewarded,
+			"qualifying_type": referral.QualifyingType,
+			"qualifying_id":   referral.QualifyingID,
+			"referrer_reward": referral.ReferrerReward,
+			"referee_reward":  referral.RefereeReward,
+			"rewarded_at":     referral.RewardedAt,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// ReleaseReferral puts a claimed referral back to pending after its rewards
+// could not be paid, so the next delivery of the event tries again.
+func (r *repository) ReleaseReferral(ctx context.Context, referralID string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Referral{}).
+		Where("id = ? AND status = ?", referralID, models.ReferralStatusRewarded).
+		Updates(map[string]interface{}{
+			"status":          models.ReferralStatusPending,
+			"qualifying_type": nil,
+			"qualifying_id":   nil,
+			"rewarded_at":     nil,
+		}).Error
+}
+
+func (r *repository) ExpireReferral(ctx context.Context, referralID string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Referral{}).
+		Where("id = ? AND status = ?", referralID, models.ReferralStatusPending).
+		Update("status", models.ReferralStatusExpired).Error
+}
+
+func (r *repository) HasRewardTransaction(ctx context.Context, userID, referenceType, referralID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Table("wallet_transactions t").
+		Joins("JOIN wallets w ON w.id = t.wallet_id").
+		Where("w.user_id = ? AND t.reference_type = ? AND t.reference_id = ?", userID, referenceType, referralID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// LastDeviceID returns the device the user last signed in from, or "" if
+// they have no sessions.
+func (r *repository) LastDeviceID(ctx context.Context, userID string) (string, error) {
+	var sessions []models.AuthSession
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(1).
+		Find(&sessions).Error
+	if err != nil || len(sessions) == 0 {
+		return "", err
+	}
+	return sessions[0].DeviceID, nil
+}
+
+func (r *repository) DeviceUsedByUser(ctx context.Context, deviceID, userID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.AuthSession{}).
+		Where("device_id = ? AND user_id = ?", deviceID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// DeviceUsedByOtherAccount reports whether another account has signed in
+// from the device or already signed up on it with a referral code.
+func (r *repository) DeviceUsedByOtherAccount(ctx context.Context, deviceID, userID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.AuthSession{}).
+		Where("device_id = ? AND user_id <> ?", deviceID, userID).
+		Count(&count).Error
+	if err != nil || count > 0 {
+		return count > 0, err
+	}
+
+	err = r.db.WithContext(ctx).
+		Model(&models.Referral{}).
+		Where("device_id = ? AND referee_id <> ?", deviceID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *repository) PhoneHashReferred(ctx context.Context, phoneHash, userID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Referral{}).
+		Where("phone_hash = ? AND referee_id <> ?", phoneHash, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// HasCompletedTrip reports whether the user has completed a ride, as rider or
+// driver, or a home service order.
+func (r *repository) HasCompletedTrip(ctx context.Context, userID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.Ride{}).
+		Where("(rider_id = ? OR driver_id = ?) AND status = ?", userID, userID, "completed").
+		Count(&count).Error
+	if err != nil || count > 0 {
+		return count > 0, err
+	}
+
+	err = r.db.WithContext(ctx).
+		Model(&models.ServiceOrderNew{}).
+		Where("customer_id = ? AND status = ?", userID, "completed").
+		Count(&count).Error
+	return count > 0, err
+}
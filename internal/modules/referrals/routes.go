@@ -0,0 +1,21 @@
+package referrals
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/middleware"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
+	referrals := router.Group("/referrals")
+	referrals.Use(authMiddleware)
+	{
+		referrals.GET("/me", handler.GetSummary)
+		referrals.GET("", handler.ListMyReferrals)
+		referrals.POST("/apply", handler.ApplyCode)
+	}
+
+	admin := referrals.Group("/admin", middleware.RequireAdmin())
+	{
+		admin.GET("", handler.ListReferrals)
+	}
+}
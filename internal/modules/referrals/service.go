@@ -0,0 +1,424 @@
+package referrals
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/referrals/dto"
+	"github.com/umar5678/go-backend/internal/services/eventbus"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+	websocketutil "github.com/umar5678/go-backend/internal/websocket/websocketutils"
+	"gorm.io/gorm"
+)
+
+const (
+	// Wallet transaction types of the two rewards, each referencing the
+	// referral, so a referral pays each side once.
+	refereeRewardType  = "referral_bonus"
+	referrerRewardType = "referral_reward"
+
+	qualifyingRide  = "ride"
+	qualifyingOrder = "order"
+)
+
+type WalletService interface {
+	CreditWallet(ctx context.Context, userID string, amount float64, transactionType, referenceID, description string, metadata map[string]interface{}) (*models.WalletTransaction, error)
+}
+
+type Service interface {
+	GetSummary(ctx context.Context, userID string) (*dto.ReferralSummaryResponse, error)
+	ListMyReferrals(ctx context.Context, userID string, req dto.ListReferralsRequest) ([]*dto.ReferralResponse, int64, error)
+	ApplyCode(ctx context.Context, userID, code, deviceID string) error
+	ListReferrals(ctx context.Context, req dto.ListReferralsRequest) ([]*dto.AdminReferralResponse, int64, error)
+
+	// AttributeSignup records the referral code a user signed up with. A code
+	// that cannot be used is logged rather than failing the signup.
+	AttributeSignup(ctx context.Context, user *models.User, code, deviceID string) error
+
+	// HandleEvent rewards a pending referral when its referee completes a
+	// ride or order.
+	HandleEvent(ctx context.Context, event eventbus.Event) error
+}
+
+type service struct {
+	repo          Repository
+	walletService WalletService
+	cfg           config.ReferralConfig
+}
+
+func NewService(repo Repository, walletService WalletService, cfg config.ReferralConfig) Service {
+	return &service{repo: repo, walletService: walletService, cfg: cfg}
+}
+
+func (s *service) GetSummary(ctx context.Context, userID string) (*dto.ReferralSummaryResponse, error) {
+	user, err := s.repo.FindUserByID(ctx, userID)
+	if err != nil {
+		return nil, response.NotFoundError("User")
+	}
+
+	stats, err := s.repo.GetReferrerStats(ctx, userID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get referral summary", err)
+	}
+
+	summary := &dto.ReferralSummaryResponse{
+		ReferrerReward:    s.cfg.ReferrerReward,
+		RefereeReward:     s.cfg.RefereeReward,
+		QualifyDays:       int(s.cfg.QualifyWindow / (24 * time.Hour)),
+		TotalReferrals:    stats.Total,
+		PendingReferrals:  stats.Pending,
+		RewardedReferrals: stats.Rewarded,
+		RejectedReferrals: stats.Rejected,
+		ExpiredReferrals:  stats.Expired,
+		TotalEarnings:     stats.Earnings,
+	}
+	if user.ReferralCode != nil {
+		summary.ReferralCode = *user.ReferralCode
+	}
+
+	referral, err := s.repo.GetReferralByReferee(ctx, userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, response.InternalServerError("Failed to get referral summary", err)
+	}
+	if referral != nil && referral.Status != models.ReferralStatusRejected {
+		summary.ReferredBy = &dto.ReferredByResponse{
+			Code:          referral.Code,
+			Status:        dto.ReferralStatus(referral, time.Now()),
+			RefereeReward: s.cfg.RefereeReward,
+			QualifyBy:     referral.QualifyBy,
+			RewardedAt:    referral.RewardedAt,
+		}
+		if referral.RewardedAt != nil {
+			summary.ReferredBy.RefereeReward = referral.RefereeReward
+		}
+		if referral.Referrer != nil {
+			summary.ReferredBy.ReferrerName = referral.Referrer.Name
+		}
+	}
+
+	return summary, nil
+}
+
+func (s *service) ListMyReferrals(ctx context.Context, userID string, req dto.ListReferralsRequest) ([]*dto.ReferralResponse, int64, error) {
+	referrals, total, err := s.repo.ListReferralsByReferrer(ctx, userID, req.Status, req.Page, req.Limit)
+	if err != nil {
+		return nil, 0, response.InternalServerError("Failed to list referrals", err)
+	}
+	return dto.ToReferralResponses(referrals, time.Now()), total, nil
+}
+
+func (s *service) ListReferrals(ctx context.Context, req dto.ListReferralsRequest) ([]*dto.AdminReferralResponse, int64, error) {
+	referrals, total, err := s.repo.ListReferrals(ctx, req.Status, req.Page, req.Limit)
+	if err != nil {
+		return nil, 0, response.InternalServerError("Failed to list referrals", err)
+	}
+	return dto.ToAdminReferralResponses(referrals, time.Now()), total, nil
+}
+
+// ApplyCode attributes an existing account to a referral code, for users who
+// did not enter one at signup. Without a device ID the one the user last
+// signed in from is checked.
+func (s *service) ApplyCode(ctx context.Context, userID, code, deviceID string) error {
+	if !s.cfg.Enabled {
+		return response.BadRequest("The referral program is not available")
+	}
+
+	user, err := s.repo.FindUserByID(ctx, userID)
+	if err != nil {
+		return response.NotFoundError("User")
+	}
+
+	if deviceID == "" {
+		deviceID, err = s.repo.LastDeviceID(ctx, userID)
+		if err != nil {
+			return response.InternalServerError("Failed to apply referral code", err)
+		}
+	}
+
+	referral, err := s.attribute(ctx, user, code, deviceID)
+	if err != nil {
+		return err
+	}
+	if referral.Status == models.ReferralStatusRejected {
+		return response.BadRequest("This referral code cannot be applied to your account")
+	}
+	return nil
+}
+
+func (s *service) AttributeSignup(ctx context.Context, user *models.User, code, deviceID string) error {
+	if !s.cfg.Enabled || strings.TrimSpace(code) == "" {
+		return nil
+	}
+	_, err := s.attribute(ctx, user, code, deviceID)
+	return err
+}
+
+// attribute records a referral of the user by the code's owner. A code that
+// does not exist, or a user who was already referred, has completed a trip
+// or signed up too long ago, is refused with nothing recorded. A signup that
+// looks like the referrer or an earlier account coming back is recorded as
+// rejected, so the user cannot try again with another code.
+func (s *service) attribute(ctx context.Context, user *models.User, code, deviceID string) (*models.Referral, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+
+	referrer, err := s.repo.FindUserByReferralCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.BadRequest("Invalid referral code")
+		}
+		return nil, response.InternalServerError("Failed to apply referral code", err)
+	}
+	if referrer.ID == user.ID {
+		return nil, response.BadRequest("You cannot use your own referral code")
+	}
+
+	if _, err := s.repo.GetReferralByReferee(ctx, user.ID); err == nil {
+		return nil, response.ConflictError("A referral code has already been applied to your account")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, response.InternalServerError("Failed to apply referral code", err)
+	}
+
+	now := time.Now()
+	if now.After(user.CreatedAt.Add(s.cfg.ApplyWindow)) {
+		return nil, response.BadRequest(fmt.Sprintf("Referral codes can only be applied within %d days of signing up", int(s.cfg.ApplyWindow/(24*time.Hour))))
+	}
+
+	completed, err := s.repo.HasCompletedTrip(ctx, user.ID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to apply referral code", err)
+	}
+	if completed {
+		return nil, response.BadRequest("Referral codes can only be applied before your first trip")
+	}
+
+	referral := &models.Referral{
+		ReferrerID: referrer.ID,
+		RefereeID:  user.ID,
+		Code:       code,
+		Status:     models.ReferralStatusPending,
+		QualifyBy:  now.Add(s.cfg.QualifyWindow),
+	}
+	if deviceID != "" {
+		referral.DeviceID = &deviceID
+	}
+	if user.Phone != nil && *user.Phone != "" {
+		phoneHash := hashPhone(*user.Phone)
+		referral.PhoneHash = &phoneHash
+	}
+
+	reason, err := s.fraudCheck(ctx, referral)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to apply referral code", err)
+	}
+	if reason != "" {
+		referral.Status = models.ReferralStatusRejected
+		referral.RejectionReason = &reason
+	}
+
+	if err := s.repo.CreateReferral(ctx, referral); err != nil {
+		logger.Error("failed to create referral", "error", err, "userID", user.ID, "referrerID", referrer.ID)
+		return nil, response.InternalServerError("Failed to apply referral code", err)
+	}
+
+	if referral.Status == models.ReferralStatusRejected {
+		logger.Warn("referral rejected",
+			"referralID", referral.ID,
+			"userID", user.ID,
+			"referrerID", referrer.ID,
+			"reason", reason,
+		)
+		return referral, nil
+	}
+
+	if err := s.repo.SetReferredBy(ctx, user.ID, code); err != nil {
+		logger.Error("failed to set referred_by", "error", err, "userID", user.ID)
+	}
+
+	logger.Info("referral attributed",
+		"referralID", referral.ID,
+		"userID", user.ID,
+		"referrerID", referrer.ID,
+		"qualifyBy", referral.QualifyBy,
+	)
+	return referral, nil
+}
+
+// fraudCheck returns why the referral should be rejected, or "" if it looks
+// genuine: the referee's device must not have been used by the referrer or
+// another account, and their phone number must not have been referred on an
+// earlier account.
+func (s *service) fraudCheck(ctx context.Context, referral *models.Referral) (string, error) {
+	if referral.DeviceID != nil {
+		self, err := s.repo.DeviceUsedByUser(ctx, *referral.DeviceID, referral.ReferrerID)
+		if err != nil {
+			return "", err
+		}
+		if self {
+			return models.ReferralRejectedSelf, nil
+		}
+
+		reused, err := s.repo.DeviceUsedByOtherAccount(ctx, *referral.DeviceID, referral.RefereeID)
+		if err != nil {
+			return "", err
+		}
+		if reused {
+			return models.ReferralRejectedDeviceReused, nil
+		}
+	}
+
+	if referral.PhoneHash != nil {
+		reused, err := s.repo.PhoneHashReferred(ctx, *referral.PhoneHash, referral.RefereeID)
+		if err != nil {
+			return "", err
+		}
+		if reused {
+			return models.ReferralRejectedPhoneReused, nil
+		}
+	}
+	return "", nil
+}
+
+func hashPhone(phone string) string {
+	sum := sha256.Sum256([]byte(phone))
+	return hex.EncodeToString(sum[:])
+}
+
+func (s *service) HandleEvent(ctx context.Context, event eventbus.Event) error {
+	if !s.cfg.Enabled {
+		return nil
+	}
+
+	switch event.Type {
+	case eventbus.RideCompleted:
+		var payload eventbus.RideCompletedPayload
+		if err := event.Decode(&payload); err != nil {
+			return fmt.Errorf("decode %s payload: %w", event.Type, err)
+		}
+		if err := s.reward(ctx, payload.RiderID, qualifyingRide, payload.RideID); err != nil {
+			return err
+		}
+		if payload.DriverID == "" {
+			return nil
+		}
+		return s.reward(ctx, payload.DriverID, qualifyingRide, payload.RideID)
+
+	case eventbus.OrderCompleted:
+		var payload eventbus.OrderCompletedPayload
+		if err := event.Decode(&payload); err != nil {
+			return fmt.Errorf("decode %s payload: %w", event.Type, err)
+		}
+		return s.reward(ctx, payload.CustomerID, qualifyingOrder, payload.OrderID)
+	}
+	return nil
+}
+
+// reward pays out the user's pending referral for their first completed ride
+// or order. The referral is claimed before either side is credited so that
+// concurrent events pay it once; if a credit fails it is released again and
+// the error returned, so the event is redelivered and credits already made
+// are not repeated.
+func (s *service) reward(ctx context.Context, userID, qualifyingType, qualifyingID string) error {
+	referral, err := s.repo.GetReferralByReferee(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if referral.Status != models.ReferralStatusPending {
+		return nil
+	}
+
+	now := time.Now()
+	if now.After(referral.QualifyBy) {
+		logger.Info("referral expired before first trip", "referralID", referral.ID, "refereeID", userID)
+		return s.repo.ExpireReferral(ctx, referral.ID)
+	}
+
+	rewarded, err := s.repo.CountRewardedReferrals(ctx, referral.ReferrerID)
+	if err != nil {
+		return err
+	}
+
+	referral.QualifyingType = &qualifyingType
+	referral.QualifyingID = &qualifyingID
+	referral.RefereeReward = s.cfg.RefereeReward
+	referral.ReferrerReward = s.cfg.ReferrerReward
+	if s.cfg.MaxRewardsPerUser > 0 && int(rewarded) >= s.cfg.MaxRewardsPerUser {
+		referral.ReferrerReward = 0
+	}
+	referral.RewardedAt = &now
+
+	claimed, err := s.repo.ClaimReferral(ctx, referral)
+	if err != nil || !claimed {
+		return err
+	}
+
+	metadata := map[string]interface{}{
+		"referral_id":     referral.ID,
+		"referral_code":   referral.Code,
+		"qualifying_type": qualifyingType,
+		"qualifying_id":   qualifyingID,
+	}
+	if err := s.credit(ctx, referral.RefereeID, referral.RefereeReward, refereeRewardType, referral.ID, "Referral bonus for your first trip", metadata); err != nil {
+		return s.release(ctx, referral, err)
+	}
+	if err := s.credit(ctx, referral.ReferrerID, referral.ReferrerReward, referrerRewardType, referral.ID, "Referral reward for inviting a friend", metadata); err != nil {
+		return s.release(ctx, referral, err)
+	}
+
+	logger.Info("referral rewarded",
+		"referralID", referral.ID,
+		"referrerID", referral.ReferrerID,
+		"refereeID", referral.RefereeID,
+		"referrerReward", referral.ReferrerReward,
+		"refereeReward", referral.RefereeReward,
+		"qualifyingType", qualifyingType,
+		"qualifyingID", qualifyingID,
+	)
+
+	notify(referral.RefereeID, referral, referral.RefereeReward, "referee")
+	if referral.ReferrerReward > 0 {
+		notify(referral.ReferrerID, referral, referral.ReferrerReward, "referrer")
+	}
+	return nil
+}
+
+// credit pays a reward unless the user's wallet already has it.
+func (s *service) credit(ctx context.Context, userID string, amount float64, transactionType, referralID, description string, metadata map[string]interface{}) error {
+	if amount <= 0 {
+		return nil
+	}
+	paid, err := s.repo.HasRewardTransaction(ctx, userID, transactionType, referralID)
+	if err != nil || paid {
+		return err
+	}
+	_, err = s.walletService.CreditWallet(ctx, userID, amount, transactionType, referralID, description, metadata)
+	return err
+}
+
+func (s *service) release(ctx context.Context, referral *models.Referral, cause error) error {
+	logger.Error("failed to pay referral reward", "error", cause, "referralID", referral.ID)
+	if err := s.repo.ReleaseReferral(ctx, referral.ID); err != nil {
+		logger.Error("failed to release referral", "error", err, "referralID", referral.ID)
+	}
+	return cause
+}
+
+func notify(userID string, referral *models.Referral, amount float64, role string) {
+	websocketutil.SendToUser(userID, websocket.TypeReferralRewarded, map[string]interface{}{
+		"referralId": referral.ID,
+		"role":       role,
+		"amount":     amount,
+		"rewardedAt": referral.RewardedAt,
+	})
+}
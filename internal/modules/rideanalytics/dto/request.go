@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"errors"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// ReportQuery selects rides requested between FromDate and ToDate inclusive,
+// in UTC.
+type ReportQuery struct {
+	FromDate      string `form:"fromDate" binding:"required"`
+	ToDate        string `form:"toDate" binding:"required"`
+	GroupBy       string `form:"groupBy" binding:"omitempty,oneof=hour day week month"`
+	VehicleTypeID string `form:"vehicleTypeId" binding:"omitempty,uuid"`
+}
+
+func (q *ReportQuery) SetDefaults() {
+	if q.GroupBy == "" {
+		q.GroupBy = "day"
+	}
+}
+
+// Validate checks the range and returns it as [from, to).
+func (q *ReportQuery) Validate() (from, to time.Time, err error) {
+	from, err = time.Parse(dateLayout, q.FromDate)
+	if err != nil {
+		return from, to, errors.New("invalid fromDate format")
+	}
+	last, err := time.Parse(dateLayout, q.ToDate)
+	if err != nil {
+		return from, to, errors.New("invalid toDate format")
+	}
+	if from.After(last) {
+		return from, to, errors.New("fromDate cannot be after toDate")
+	}
+	to = last.AddDate(0, 0, 1)
+	if to.Sub(from) > 366*24*time.Hour {
+		return from, to, errors.New("date range cannot exceed 1 year")
+	}
+	if q.GroupBy == "hour" && to.Sub(from) > 31*24*time.Hour {
+		return from, to, errors.New("hourly grouping is limited to 31 days")
+	}
+	return from, to, nil
+}
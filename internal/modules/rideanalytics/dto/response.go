@@ -0,0 +1,75 @@
+package dto
+
+import "time"
+
+type ReportResponse struct {
+	Period        ReportPeriod         `json:"period"`
+	Summary       RideMetrics          `json:"summary"`
+	ByCity        []CityMetrics        `json:"byCity"`
+	ByVehicleType []VehicleTypeMetrics `json:"byVehicleType"`
+	ByTime        []TimeBucketMetrics  `json:"byTime"`
+}
+
+type ReportPeriod struct {
+	FromDate      string `json:"fromDate"`
+	ToDate        string `json:"toDate"`
+	GroupBy       string `json:"groupBy"`
+	VehicleTypeID string `json:"vehicleTypeId,omitempty"`
+}
+
+// RideMetrics are the operational figures for a set of rides. Times are in
+// seconds and rates in percent.
+type RideMetrics struct {
+	RequestedRides   int64   `json:"requestedRides"`
+	CompletedRides   int64   `json:"completedRides"`
+	CancelledRides   int64   `json:"cancelledRides"`
+	CompletionRate   float64 `json:"completionRate"`
+	CancellationRate float64 `json:"cancellationRate"`
+
+	// AvgWaitSeconds is the average time from request to a driver accepting.
+	AvgWaitSeconds float64 `json:"avgWaitSeconds"`
+
+	// Pickup ETA accuracy compares the ETA shown at acceptance with the time
+	// the driver took to arrive. A pickup is on time when the driver arrived
+	// no more than two minutes after the ETA.
+	PickupETASamples         int64   `json:"pickupEtaSamples"`
+	AvgPickupETAErrorSeconds float64 `json:"avgPickupEtaErrorSeconds"`
+	PickupOnTimeRate         float64 `json:"pickupOnTimeRate"`
+
+	GrossFare    float64 `json:"grossFare"`
+	SurgedRides  int64   `json:"surgedRides"`
+	SurgeRevenue float64 `json:"surgeRevenue"`
+
+	// Utilization is the share of drivers' signed-on time spent on a ride,
+	// from acceptance to completion or cancellation. Online time cannot be
+	// attributed to a city, so it is omitted from the city breakdown.
+	OnTripHours       float64  `json:"onTripHours"`
+	OnlineHours       *float64 `json:"onlineHours,omitempty"`
+	DriverUtilization *float64 `json:"driverUtilization,omitempty"`
+}
+
+// CityMetrics groups rides by the city their pickup falls in; rides outside
+// every active city have an empty CityID.
+type CityMetrics struct {
+	CityID   string `json:"cityId,omitempty"`
+	CityName string `json:"cityName"`
+	RideMetrics
+}
+
+type VehicleTypeMetrics struct {
+	VehicleTypeID   string `json:"vehicleTypeId"`
+	VehicleTypeName string `json:"vehicleTypeName"`
+	RideMetrics
+}
+
+type TimeBucketMetrics struct {
+	Start time.Time `json:"start"`
+	RideMetrics
+}
+
+// ExportFile is a rendered report ready to be sent as a download.
+type ExportFile struct {
+	FileName    string
+	ContentType string
+	Data        []byte
+}
@@ -0,0 +1,85 @@
+package rideanalytics
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/modules/rideanalytics/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+var exportHeader = []string{
+	"dimension", "key", "name",
+	"requested_rides", "completed_rides", "cancelled_rides", "completion_rate", "cancellation_rate",
+	"avg_wait_seconds", "pickup_eta_samples", "avg_pickup_eta_error_seconds", "pickup_on_time_rate",
+	"gross_fare", "surged_rides", "surge_revenue",
+	"on_trip_hours", "online_hours", "driver_utilization",
+}
+
+// ExportReport renders the report as one CSV table: the total, then a row per
+// city, vehicle type and time bucket, told apart by the dimension column.
+func (s *service) ExportReport(ctx context.Context, query dto.ReportQuery) (*dto.ExportFile, error) {
+	report, err := s.GetReport(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := [][]string{exportHeader, exportRow("total", "", "", report.Summary)}
+	for _, c := range report.ByCity {
+		rows = append(rows, exportRow("city", c.CityID, c.CityName, c.RideMetrics))
+	}
+	for _, vt := range report.ByVehicleType {
+		rows = append(rows, exportRow("vehicle_type", vt.VehicleTypeID, vt.VehicleTypeName, vt.RideMetrics))
+	}
+	for _, b := range report.ByTime {
+		rows = append(rows, exportRow("time", b.Start.Format(time.RFC3339), "", b.RideMetrics))
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(rows); err != nil {
+		return nil, response.InternalServerError("Failed to render export", err)
+	}
+
+	return &dto.ExportFile{
+		FileName:    fmt.Sprintf("ride_analytics_%s_%s.csv", report.Period.FromDate, report.Period.ToDate),
+		ContentType: "text/csv; charset=utf-8",
+		Data:        buf.Bytes(),
+	}, nil
+}
+
+func exportRow(dimension, key, name string, m dto.RideMetrics) []string {
+	return []string{
+		dimension, key, name,
+		strconv.FormatInt(m.RequestedRides, 10),
+		strconv.FormatInt(m.CompletedRides, 10),
+		strconv.FormatInt(m.CancelledRides, 10),
+		formatFloat(m.CompletionRate),
+		formatFloat(m.CancellationRate),
+		formatFloat(m.AvgWaitSeconds),
+		strconv.FormatInt(m.PickupETASamples, 10),
+		formatFloat(m.AvgPickupETAErrorSeconds),
+		formatFloat(m.PickupOnTimeRate),
+		formatFloat(m.GrossFare),
+		strconv.FormatInt(m.SurgedRides, 10),
+		formatFloat(m.SurgeRevenue),
+		formatFloat(m.OnTripHours),
+		formatOptional(m.OnlineHours),
+		formatOptional(m.DriverUtilization),
+	}
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+func formatOptional(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return formatFloat(*v)
+}
@@ -0,0 +1,77 @@
+package rideanalytics
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/modules/rideanalytics/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// GetReport godoc
+// @Summary Ride analytics
+// @Description Completed and cancelled rides, average wait from request to acceptance, pickup ETA accuracy, surge revenue and driver utilization for rides requested in the range (UTC), in total and broken down by pickup city, vehicle type and time bucket
+// @Tags admin-ride-analytics
+// @Security BearerAuth
+// @Produce json
+// @Param fromDate query string true "From date (YYYY-MM-DD)"
+// @Param toDate query string true "To date (YYYY-MM-DD), inclusive"
+// @Param groupBy query string false "Time bucket, hour is limited to 31 days" Enums(hour, day, week, month)
+// @Param vehicleTypeId query string false "Vehicle type ID"
+// @Success 200 {object} response.Response{data=dto.ReportResponse}
+// @Failure 400 {object} response.Response
+// @Router /admin/rides/analytics [get]
+func (h *Handler) GetReport(c *gin.Context) {
+	var query dto.ReportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+
+	report, err := h.service.GetReport(c.Request.Context(), query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, report, "Ride analytics retrieved successfully")
+}
+
+// ExportReport godoc
+// @Summary Export ride analytics
+// @Description The ride analytics report as CSV, one row for the total and one per city, vehicle type and time bucket
+// @Tags admin-ride-analytics
+// @Security BearerAuth
+// @Produce text/csv
+// @Param fromDate query string true "From date (YYYY-MM-DD)"
+// @Param toDate query string true "To date (YYYY-MM-DD), inclusive"
+// @Param groupBy query string false "Time bucket, hour is limited to 31 days" Enums(hour, day, week, month)
+// @Param vehicleTypeId query string false "Vehicle type ID"
+// @Success 200 {file} file
+// @Failure 400 {object} response.Response
+// @Router /admin/rides/analytics/export [get]
+func (h *Handler) ExportReport(c *gin.Context) {
+	var query dto.ReportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+
+	file, err := h.service.ExportReport(c.Request.Context(), query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.FileName))
+	c.Data(http.StatusOK, file.ContentType, file.Data)
+}
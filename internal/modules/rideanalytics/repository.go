@@ -0,0 +1,211 @@
+package rideanalytics
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/umar5678/go-backend/internal/database"
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+// Grouping set names in the Dimension column of query rows.
+const (
+	dimensionTotal       = "total"
+	dimensionVehicleType = "vehicle_type"
+	dimensionTime        = "time"
+	dimensionCell        = "cell"
+)
+
+// pickupOnTimeGraceSeconds is how late after the ETA a driver may arrive and
+// still count as on time.
+const pickupOnTimeGraceSeconds = 120
+
+// bucketSteps are the interval between time buckets for each groupBy value.
+var bucketSteps = map[string]string{
+	"hour":  "1 hour",
+	"day":   "1 day",
+	"week":  "1 week",
+	"month": "1 month",
+}
+
+// Filter selects rides requested in [From, To), bucketed by GroupBy.
+type Filter struct {
+	From          time.Time
+	To            time.Time
+	GroupBy       string
+	VehicleTypeID string
+}
+
+// MetricsRow holds the ride sums for one group of a grouping set. Only the
+// key columns of its Dimension are set.
+type MetricsRow struct {
+	Dimension     string
+	VehicleTypeID *string
+	Bucket        *time.Time
+	LatCell       *float64
+	LonCell       *float64
+
+	RequestedRides  int64
+	CompletedRides  int64
+	CancelledRides  int64
+	AcceptedRides   int64
+	WaitSeconds     float64
+	EtaSamples      int64
+	EtaErrorSeconds float64
+	EtaOnTime       int64
+	GrossFare       float64
+	SurgedRides     int64
+	SurgeRevenue    float64
+	OnTripSeconds   float64
+}
+
+// OnlineRow holds driver signed-on time for one group of a grouping set.
+type OnlineRow struct {
+	Dimension     string
+	VehicleTypeID *string
+	Bucket        *time.Time
+	OnlineSeconds float64
+}
+
+type Repository interface {
+	// RideMetrics aggregates rides, live and archived, in one pass grouped by
+	// vehicle type, time bucket and pickup cell, plus a grand total. Cells
+	// are 0.01 degree squares (about 1 km) that the service maps to cities.
+	RideMetrics(ctx context.Context, filter Filter) ([]MetricsRow, error)
+	// OnlineTime sums the part of each driver online session inside the
+	// range, grouped by the driver's vehicle type and time bucket.
+	OnlineTime(ctx context.Context, filter Filter) ([]OnlineRow, error)
+	ListActiveCities(ctx context.Context) ([]*models.City, error)
+	VehicleTypeNames(ctx context.Context) (map[string]string, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+const rideColumns = `vehicle_type_id, status, requested_at, accepted_at, arrived_at, completed_at, cancelled_at,
+	pickup_eta_minutes, surge_multiplier, COALESCE(actual_fare, estimated_fare) AS fare, pickup_lat, pickup_lon`
+
+const rideFilter = `requested_at >= @from AND requested_at < @to AND deleted_at IS NULL
+	AND (@vehicle_type_id = '' OR vehicle_type_id::text = @vehicle_type_id)`
+
+const etaMeasured = `pickup_eta_minutes IS NOT NULL AND accepted_at IS NOT NULL AND arrived_at IS NOT NULL`
+
+const rideMetricsSQL = `
+WITH r AS (
+	SELECT ` + rideColumns + ` FROM rides WHERE ` + rideFilter + `
+	UNION ALL
+	SELECT ` + rideColumns + ` FROM rides_archive WHERE ` + rideFilter + `
+), keyed AS (
+	SELECT r.*,
+		date_trunc(@unit, requested_at AT TIME ZONE 'UTC') AS bucket,
+		ROUND(pickup_lat::numeric, 2) AS lat_cell,
+		ROUND(pickup_lon::numeric, 2) AS lon_cell
+	FROM r
+)
+SELECT
+	CASE
+		WHEN GROUPING(vehicle_type_id) = 0 THEN 'vehicle_type'
+		WHEN GROUPING(bucket) = 0 THEN 'time'
+		WHEN GROUPING(lat_cell) = 0 THEN 'cell'
+		ELSE 'total'
+	END AS dimension,
+	vehicle_type_id, bucket, lat_cell, lon_cell,
+	COUNT(*) AS requested_rides,
+	COUNT(*) FILTER (WHERE status = 'completed') AS completed_rides,
+	COUNT(*) FILTER (WHERE status = 'cancelled') AS cancelled_rides,
+	COUNT(*) FILTER (WHERE accepted_at IS NOT NULL) AS accepted_rides,
+	COALESCE(SUM(EXTRACT(EPOCH FROM accepted_at - requested_at)) FILTER (WHERE accepted_at IS NOT NULL), 0) AS wait_seconds,
+	COUNT(*) FILTER (WHERE ` + etaMeasured + `) AS eta_samples,
+	COALESCE(SUM(ABS(EXTRACT(EPOCH FROM arrived_at - accepted_at) - pickup_eta_minutes * 60)) FILTER (WHERE ` + etaMeasured + `), 0) AS eta_error_seconds,
+	COUNT(*) FILTER (WHERE ` + etaMeasured + ` AND EXTRACT(EPOCH FROM arrived_at - accepted_at) <= pickup_eta_minutes * 60 + @grace) AS eta_on_time,
+	COALESCE(SUM(fare) FILTER (WHERE status = 'completed'), 0) AS gross_fare,
+	COUNT(*) FILTER (WHERE status = 'completed' AND surge_multiplier > 1) AS surged_rides,
+	COALESCE(SUM(fare - fare / surge_multiplier) FILTER (WHERE status = 'completed' AND surge_multiplier > 1), 0) AS surge_revenue,
+	COALESCE(SUM(EXTRACT(EPOCH FROM COALESCE(completed_at, cancelled_at) - accepted_at))
+		FILTER (WHERE accepted_at IS NOT NULL AND COALESCE(completed_at, cancelled_at) IS NOT NULL), 0) AS on_trip_seconds
+FROM keyed
+GROUP BY GROUPING SETS ((), (vehicle_type_id), (bucket), (lat_cell, lon_cell))`
+
+func (r *repository) RideMetrics(ctx context.Context, filter Filter) ([]MetricsRow, error) {
+	var rows []MetricsRow
+	err := r.db.WithContext(ctx).Scopes(database.ReadReplica).
+		Raw(rideMetricsSQL, map[string]interface{}{
+			"from":            filter.From,
+			"to":              filter.To,
+			"vehicle_type_id": filter.VehicleTypeID,
+			"unit":            filter.GroupBy,
+			"grace":           pickupOnTimeGraceSeconds,
+		}).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// Buckets are generated in UTC wall time to match date_trunc in
+// rideMetricsSQL, then clipped to the range.
+const onlineTimeSQL = `
+WITH b AS (
+	SELECT g AS bucket,
+		GREATEST(g AT TIME ZONE 'UTC', @from::timestamptz) AS bucket_start,
+		LEAST((g + @step::interval) AT TIME ZONE 'UTC', @to::timestamptz) AS bucket_end
+	FROM generate_series(
+		date_trunc(@unit, @from::timestamptz AT TIME ZONE 'UTC'),
+		(@to::timestamptz AT TIME ZONE 'UTC') - interval '1 microsecond',
+		@step::interval
+	) AS g
+)
+SELECT
+	CASE
+		WHEN GROUPING(v.vehicle_type_id) = 0 THEN 'vehicle_type'
+		WHEN GROUPING(b.bucket) = 0 THEN 'time'
+		ELSE 'total'
+	END AS dimension,
+	v.vehicle_type_id, b.bucket,
+	SUM(EXTRACT(EPOCH FROM LEAST(COALESCE(s.ended_at, NOW()), b.bucket_end) - GREATEST(s.started_at, b.bucket_start))) AS online_seconds
+FROM b
+JOIN driver_online_sessions s ON s.started_at < b.bucket_end AND COALESCE(s.ended_at, NOW()) > b.bucket_start
+LEFT JOIN driver_profiles dp ON dp.id = s.driver_id
+LEFT JOIN vehicles v ON v.id = dp.active_vehicle_id
+WHERE @vehicle_type_id = '' OR v.vehicle_type_id::text = @vehicle_type_id
+GROUP BY GROUPING SETS ((), (v.vehicle_type_id), (b.bucket))`
+
+func (r *repository) OnlineTime(ctx context.Context, filter Filter) ([]OnlineRow, error) {
+	var rows []OnlineRow
+	err := r.db.WithContext(ctx).Scopes(database.ReadReplica).
+		Raw(onlineTimeSQL, map[string]interface{}{
+			"from":            filter.From,
+			"to":              filter.To,
+			"vehicle_type_id": filter.VehicleTypeID,
+			"unit":            filter.GroupBy,
+			"step":            bucketSteps[filter.GroupBy],
+		}).
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *repository) ListActiveCities(ctx context.Context) ([]*models.City, error) {
+	var cities []*models.City
+	err := r.db.WithContext(ctx).
+		Where("is_active = ?", true).
+		Order("name ASC").
+		Find(&cities).Error
+	return cities, err
+}
+
+func (r *repository) VehicleTypeNames(ctx context.Context) (map[string]string, error) {
+	var vehicleTypes []models.VehicleType
+	if err := r.db.WithContext(ctx).Select("id", "display_name").Find(&vehicleTypes).Error; err != nil {
+		return nil, err
+	}
+	names := make(map[string]string, len(vehicleTypes))
+	for _, vt := range vehicleTypes {
+		names[vt.ID] = vt.DisplayName
+	}
+	return names, nil
+}
@@ -0,0 +1,16 @@
+package rideanalytics
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/middleware"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
+	analytics := router.Group("/admin/rides/analytics")
+	analytics.Use(authMiddleware)
+	analytics.Use(middleware.RequireAdmin())
+	{
+		analytics.GET("", handler.GetReport)
+		analytics.GET("/export", handler.ExportReport)
+	}
+}
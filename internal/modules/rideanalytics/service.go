@@ -0,0 +1,294 @@
+package rideanalytics
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/rideanalytics/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+const outsideServiceAreas = "Outside service areas"
+
+type Service interface {
+	GetReport(ctx context.Context, query dto.ReportQuery) (*dto.ReportResponse, error)
+	ExportReport(ctx context.Context, query dto.ReportQuery) (*dto.ExportFile, error)
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) GetReport(ctx context.Context, query dto.ReportQuery) (*dto.ReportResponse, error) {
+	query.SetDefaults()
+	from, to, err := query.Validate()
+	if err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+	filter := Filter{From: from, To: to, GroupBy: query.GroupBy, VehicleTypeID: query.VehicleTypeID}
+
+	rows, err := s.repo.RideMetrics(ctx, filter)
+	if err != nil {
+		logger.Error("failed to aggregate ride metrics", "error", err)
+		return nil, response.InternalServerError("Failed to get ride analytics", err)
+	}
+
+	// Utilization is secondary; without online time the rest still stands.
+	online := newOnlineTotals()
+	if onlineRows, err := s.repo.OnlineTime(ctx, filter); err != nil {
+		logger.Warn("failed to aggregate driver online time", "error", err)
+	} else {
+		online.add(onlineRows)
+	}
+
+	cities, err := s.repo.ListActiveCities(ctx)
+	if err != nil {
+		logger.Warn("failed to load cities for ride analytics", "error", err)
+	}
+	vehicleTypeNames, err := s.repo.VehicleTypeNames(ctx)
+	if err != nil {
+		logger.Warn("failed to load vehicle type names for ride analytics", "error", err)
+	}
+
+	report := &dto.ReportResponse{
+		Period: dto.ReportPeriod{
+			FromDate:      query.FromDate,
+			ToDate:        query.ToDate,
+			GroupBy:       query.GroupBy,
+			VehicleTypeID: query.VehicleTypeID,
+		},
+		ByCity:        []dto.CityMetrics{},
+		ByVehicleType: []dto.VehicleTypeMetrics{},
+	}
+
+	byBucket := map[time.Time]rideSums{}
+	byCity := map[string]*rideSums{}
+	cityNames := map[string]string{}
+
+	for _, row := range rows {
+		sums := rideSums(row)
+		switch row.Dimension {
+		case dimensionTotal:
+			report.Summary = sums.metrics(online.total)
+		case dimensionVehicleType:
+			id := deref(row.VehicleTypeID)
+			report.ByVehicleType = append(report.ByVehicleType, dto.VehicleTypeMetrics{
+				VehicleTypeID:   id,
+				VehicleTypeName: vehicleTypeNames[id],
+				RideMetrics:     sums.metrics(online.forVehicleType(id)),
+			})
+		case dimensionTime:
+			if row.Bucket != nil {
+				byBucket[row.Bucket.UTC()] = sums
+			}
+		case dimensionCell:
+			if row.LatCell == nil || row.LonCell == nil {
+				continue
+			}
+			id, name := "", outsideServiceAreas
+			if city := cityAt(cities, *row.LatCell, *row.LonCell); city != nil {
+				id, name = city.ID, city.Name
+			}
+			if byCity[id] == nil {
+				byCity[id] = &rideSums{}
+				cityNames[id] = name
+			}
+			byCity[id].add(sums)
+		}
+	}
+
+	for id, sums := range byCity {
+		report.ByCity = append(report.ByCity, dto.CityMetrics{
+			CityID:      id,
+			CityName:    cityNames[id],
+			RideMetrics: sums.metrics(nil),
+		})
+	}
+	sort.Slice(report.ByCity, func(i, j int) bool {
+		return report.ByCity[i].RequestedRides > report.ByCity[j].RequestedRides
+	})
+	sort.Slice(report.ByVehicleType, func(i, j int) bool {
+		return report.ByVehicleType[i].RequestedRides > report.ByVehicleType[j].RequestedRides
+	})
+
+	for _, start := range bucketStarts(from, to, query.GroupBy) {
+		sums := byBucket[start]
+		report.ByTime = append(report.ByTime, dto.TimeBucketMetrics{
+			Start:       start,
+			RideMetrics: sums.metrics(online.forBucket(start)),
+		})
+	}
+
+	return report, nil
+}
+
+// rideSums mirrors MetricsRow so groups can be added before averages and
+// rates are taken.
+type rideSums MetricsRow
+
+func (s *rideSums) add(o rideSums) {
+	s.RequestedRides += o.RequestedRides
+	s.CompletedRides += o.CompletedRides
+	s.CancelledRides += o.CancelledRides
+	s.AcceptedRides += o.AcceptedRides
+	s.WaitSeconds += o.WaitSeconds
+	s.EtaSamples += o.EtaSamples
+	s.EtaErrorSeconds += o.EtaErrorSeconds
+	s.EtaOnTime += o.EtaOnTime
+	s.GrossFare += o.GrossFare
+	s.SurgedRides += o.SurgedRides
+	s.SurgeRevenue += o.SurgeRevenue
+	s.OnTripSeconds += o.OnTripSeconds
+}
+
+// metrics derives the reported figures. onlineSeconds is nil when online time
+// is unknown for the group, which leaves utilization out.
+func (s rideSums) metrics(onlineSeconds *float64) dto.RideMetrics {
+	m := dto.RideMetrics{
+		RequestedRides:   s.RequestedRides,
+		CompletedRides:   s.CompletedRides,
+		CancelledRides:   s.CancelledRides,
+		CompletionRate:   percent(float64(s.CompletedRides), float64(s.RequestedRides)),
+		CancellationRate: percent(float64(s.CancelledRides), float64(s.RequestedRides)),
+		PickupETASamples: s.EtaSamples,
+		PickupOnTimeRate: percent(float64(s.EtaOnTime), float64(s.EtaSamples)),
+		GrossFare:        s.GrossFare,
+		SurgedRides:      s.SurgedRides,
+		SurgeRevenue:     s.SurgeRevenue,
+		OnTripHours:      s.OnTripSeconds / 3600,
+	}
+	if s.AcceptedRides > 0 {
+		m.AvgWaitSeconds = s.WaitSeconds / float64(s.AcceptedRides)
+	}
+	if s.EtaSamples > 0 {
+		m.AvgPickupETAErrorSeconds = s.EtaErrorSeconds / float64(s.EtaSamples)
+	}
+	if onlineSeconds != nil {
+		hours := *onlineSeconds / 3600
+		utilization := percent(s.OnTripSeconds, *onlineSeconds)
+		if utilization > 100 {
+			utilization = 100
+		}
+		m.OnlineHours = &hours
+		m.DriverUtilization = &utilization
+	}
+	return m
+}
+
+// onlineTotals indexes online seconds by grouping set. The total is nil when
+// online time could not be loaded.
+type onlineTotals struct {
+	total         *float64
+	byVehicleType map[string]*float64
+	byBucket      map[time.Time]*float64
+}
+
+func newOnlineTotals() *onlineTotals {
+	return &onlineTotals{
+		byVehicleType: map[string]*float64{},
+		byBucket:      map[time.Time]*float64{},
+	}
+}
+
+func (o *onlineTotals) add(rows []OnlineRow) {
+	zero := 0.0
+	o.total = &zero
+	for _, row := range rows {
+		seconds := row.OnlineSeconds
+		switch row.Dimension {
+		case dimensionTotal:
+			o.total = &seconds
+		case dimensionVehicleType:
+			o.byVehicleType[deref(row.VehicleTypeID)] = &seconds
+		case dimensionTime:
+			if row.Bucket != nil {
+				o.byBucket[row.Bucket.UTC()] = &seconds
+			}
+		}
+	}
+}
+
+// forVehicleType returns the vehicle type's online seconds: zero when none
+// of its drivers were on, nil when online time is unknown.
+func (o *onlineTotals) forVehicleType(id string) *float64 {
+	if seconds, ok := o.byVehicleType[id]; ok || o.total == nil {
+		return seconds
+	}
+	zero := 0.0
+	return &zero
+}
+
+func (o *onlineTotals) forBucket(start time.Time) *float64 {
+	if seconds, ok := o.byBucket[start]; ok || o.total == nil {
+		return seconds
+	}
+	zero := 0.0
+	return &zero
+}
+
+// bucketStarts lists the start of every time bucket overlapping [from, to),
+// truncated the way date_trunc does in UTC, so empty buckets still appear.
+func bucketStarts(from, to time.Time, groupBy string) []time.Time {
+	start := from.UTC()
+	switch groupBy {
+	case "hour":
+		start = start.Truncate(time.Hour)
+	case "week":
+		offset := (int(start.Weekday()) + 6) % 7
+		start = time.Date(start.Year(), start.Month(), start.Day()-offset, 0, 0, 0, 0, time.UTC)
+	case "month":
+		start = time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	}
+
+	var starts []time.Time
+	for t := start; t.Before(to); t = nextBucket(t, groupBy) {
+		starts = append(starts, t)
+	}
+	return starts
+}
+
+func nextBucket(t time.Time, groupBy string) time.Time {
+	switch groupBy {
+	case "hour":
+		return t.Add(time.Hour)
+	case "week":
+		return t.AddDate(0, 0, 7)
+	case "month":
+		return t.AddDate(0, 1, 0)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+// cityAt returns the first active city whose boundary holds the point.
+func cityAt(cities []*models.City, lat, lon float64) *models.City {
+	for _, city := range cities {
+		if city.Contains(lat, lon) {
+			return city
+		}
+	}
+	return nil
+}
+
+func percent(part, whole float64) float64 {
+	if whole == 0 {
+		return 0
+	}
+	return part / whole * 100
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,64 @@
+package rides
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/rides/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+// AddressBook looks up a rider's saved addresses, so a ride can be requested
+// with an address ID instead of the address and coordinates.
+type AddressBook interface {
+	ResolveAddress(ctx context.Context, userID, addressID string) (*models.SavedLocation, error)
+}
+
+// AddressNormalizer rewrites an address to the geocoder's canonical form
+// for its coordinates, keeping the given address when it cannot.
+type AddressNormalizer interface {
+	NormalizeAddress(ctx context.Context, address string, lat, lon float64) string
+}
+
+func (s *service) SetAddressBook(book AddressBook) {
+	s.addressBook = book
+}
+
+func (s *service) SetAddressNormalizer(normalizer AddressNormalizer) {
+	s.addressNormalizer = normalizer
+}
+
+func (s *service) normalizeRideAddresses(ctx context.Context, req *dto.CreateRideRequest) {
+	if s.addressNormalizer == nil {
+		return
+	}
+	req.PickupAddress = s.addressNormalizer.NormalizeAddress(ctx, req.PickupAddress, req.PickupLat, req.PickupLon)
+	req.DropoffAddress = s.addressNormalizer.NormalizeAddress(ctx, req.DropoffAddress, req.DropoffLat, req.DropoffLon)
+}
+
+// resolveRideAddresses fills the pickup and dropoff from the saved addresses
+// the request references, overriding any raw address sent with them.
+func (s *service) resolveRideAddresses(ctx context.Context, riderID string, req *dto.CreateRideRequest) error {
+	if req.PickupAddressID == nil && req.DropoffAddressID == nil {
+		return nil
+	}
+	if s.addressBook == nil {
+		return response.BadRequest("Saved addresses are not available, send the address instead")
+	}
+
+	if req.PickupAddressID != nil {
+		address, err := s.addressBook.ResolveAddress(ctx, riderID, *req.PickupAddressID)
+		if err != nil {
+			return err
+		}
+		req.PickupLat, req.PickupLon, req.PickupAddress = address.Latitude, address.Longitude, address.Address
+	}
+	if req.DropoffAddressID != nil {
+		address, err := s.addressBook.ResolveAddress(ctx, riderID, *req.DropoffAddressID)
+		if err != nil {
+			return err
+		}
+		req.DropoffLat, req.DropoffLon, req.DropoffAddress = address.Latitude, address.Longitude, address.Address
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package rides
+
+import (
+	"context"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/cancellation"
+)
+
+// CancellationPolicies prices cancellations. Without one the built-in default
+// policy applies.
+type CancellationPolicies interface {
+	Evaluate(ctx context.Context, c cancellation.Cancellation) *cancellation.Outcome
+}
+
+func (s *service) SetCancellationPolicies(policies CancellationPolicies) {
+	s.cancellationPolicies = policies
+}
+
+// cancellationOutcome prices cancelling the ride in its current status.
+// Percentage fees are taken from the estimated fare.
+func (s *service) cancellationOutcome(ctx context.Context, ride *models.Ride, byRider bool, at time.Time) *cancellation.Outcome {
+	c := cancellation.Cancellation{
+		Product:         models.CancellationProductRides,
+		VehicleTypeID:   ride.VehicleTypeID,
+		Lat:             ride.PickupLat,
+		Lon:             ride.PickupLon,
+		Status:          ride.Status,
+		Total:           ride.EstimatedFare,
+		ByCustomer:      byRider,
+		RequestedAt:     ride.RequestedAt,
+		AcceptedAt:      ride.AcceptedAt,
+		ArrivedAt:       ride.ArrivedAt,
+		AwaitingArrival: ride.Status == "accepted",
+		At:              at,
+	}
+
+	if s.cancellationPolicies == nil {
+		return cancellation.ApplyDefault(c)
+	}
+	return s.cancellationPolicies.Evaluate(ctx, c)
+}
@@ -0,0 +1,152 @@
+package rides
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/rides/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+const cashReportDefaultDays = 30
+
+// ensureCashRideEligible keeps cash rides away from drivers who already owe the
+// platform more than their negative-balance limit. The limit is the driver's
+// MinBalanceThreshold, the same one the wallet uses to restrict accounts.
+func (s *service) ensureCashRideEligible(ctx context.Context, driver *models.DriverProfile) error {
+	if driver.IsRestricted {
+		return response.ForbiddenError("Cash rides are unavailable until your outstanding balance is settled")
+	}
+
+	wallet, err := s.repo.FindDriverWallet(ctx, driver.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return response.InternalServerError("Failed to check driver balance", err)
+	}
+
+	if wallet.Balance <= driver.MinBalanceThreshold {
+		return response.ForbiddenError("Cash rides are unavailable until your outstanding balance is settled")
+	}
+	return nil
+}
+
+// settleCashRide records the cash the driver collected and moves the platform's
+// share through the driver wallet: the commission is debited (the balance may go
+// negative down to the driver's limit), and a platform-funded discount such as a
+// promo is credited back since the driver collected less than their share.
+func (s *service) settleCashRide(ctx context.Context, ride *models.Ride, driver *models.DriverProfile, collected, driverShare float64) {
+	commission := math.Round((collected-driverShare)*100) / 100
+
+	var txnID *string
+	switch {
+	case commission > 0:
+		rate := 0.0
+		if collected > 0 {
+			rate = commission / collected * 100
+		}
+		txn, err := s.walletService.DeductCommission(ctx, driver.UserID, commission, rate, ride.ID)
+		if err != nil {
+			logger.Error("failed to deduct cash ride commission", "error", err, "rideID", ride.ID, "commission", commission)
+		} else {
+			txnID = &txn.ID
+		}
+	case commission < 0:
+		txn, err := s.walletService.CreditDriverWallet(
+			ctx,
+			driver.UserID,
+			-commission,
+			"cash_ride_adjustment",
+			ride.ID,
+			fmt.Sprintf("Platform-funded discount on cash ride %s", ride.ID),
+			map[string]interface{}{"cash_collected": collected, "driver_share": driverShare},
+		)
+		if err != nil {
+			logger.Error("failed to credit cash ride adjustment", "error", err, "rideID", ride.ID, "amount", -commission)
+		} else {
+			txnID = &txn.ID
+		}
+	}
+
+	if err := s.repo.UpdateCashSettlement(ctx, ride.ID, collected, commission, txnID); err != nil {
+		logger.Error("failed to record cash settlement", "error", err, "rideID", ride.ID)
+	}
+	ride.CashCollected = &collected
+	ride.CashCommission = &commission
+	ride.CashCommissionTxnID = txnID
+
+	logger.Info("cash ride settled",
+		"rideID", ride.ID,
+		"driverID", driver.ID,
+		"cashCollected", collected,
+		"driverShare", driverShare,
+		"commission", commission,
+	)
+}
+
+func (s *service) GetDriverCashSummary(ctx context.Context, userID string, req dto.DriverCashReportRequest) (*dto.DriverCashSummaryResponse, error) {
+	driver, err := s.driversRepo.FindDriverByUserID(ctx, userID)
+	if err != nil {
+		return nil, response.NotFoundError("Driver profile")
+	}
+
+	from, to, err := req.Period(cashReportDefaultDays)
+	if err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	totals, err := s.repo.GetDriverCashTotals(ctx, userID, from, to)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to load cash totals", err)
+	}
+
+	balance := 0.0
+	currency := ""
+	if wallet, err := s.repo.FindDriverWallet(ctx, userID); err == nil {
+		balance = wallet.Balance
+		currency = wallet.Currency
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, response.InternalServerError("Failed to load driver wallet", err)
+	}
+
+	return &dto.DriverCashSummaryResponse{
+		From:                 from,
+		To:                   to,
+		CashRides:            totals.Rides,
+		CashCollected:        totals.CashCollected,
+		Commission:           totals.Commission,
+		DriverEarnings:       totals.CashCollected - totals.Commission,
+		WalletBalance:        balance,
+		Currency:             currency,
+		AmountOwed:           math.Max(0, -balance),
+		NegativeBalanceLimit: driver.MinBalanceThreshold,
+		RemainingCashCredit:  math.Max(0, balance-driver.MinBalanceThreshold),
+		IsRestricted:         driver.IsRestricted,
+	}, nil
+}
+
+func (s *service) ListDriverCashRides(ctx context.Context, userID string, req dto.DriverCashReportRequest) ([]*dto.CashRideResponse, int64, error) {
+	req.SetDefaults()
+
+	from, to, err := req.Period(cashReportDefaultDays)
+	if err != nil {
+		return nil, 0, response.BadRequest(err.Error())
+	}
+
+	rides, total, err := s.repo.ListDriverCashRides(ctx, userID, from, to, req.Page, req.Limit)
+	if err != nil {
+		return nil, 0, response.InternalServerError("Failed to load cash rides", err)
+	}
+
+	result := make([]*dto.CashRideResponse, len(rides))
+	for i, ride := range rides {
+		result[i] = dto.ToCashRideResponse(ride)
+	}
+	return result, total, nil
+}
@@ -0,0 +1,379 @@
+package rides
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/umar5678/go-backend/internal/models"
+	walletdto "github.com/umar5678/go-backend/internal/modules/wallet/dto"
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/services/health"
+	"github.com/umar5678/go-backend/internal/services/livemetrics"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+// dispatchQueueKey is a sorted set of ride IDs scored by the unix time at
+// which matching should (re)start. It holds scheduled rides until they are
+// due and rides handed off by an instance that shut down mid-match, so any
+// instance can pick them up.
+const (
+	dispatchQueueKey   = "rides:dispatch:queue"
+	dispatchPollEvery  = time.Second
+	dispatchClaimBatch = 20
+)
+
+// dispatcher tracks the matching goroutines of this instance so shutdown can
+// wait for them, or interrupt them and hand their rides to another instance.
+type dispatcher struct {
+	mu       sync.Mutex
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	draining bool
+	inFlight int
+	polling  context.CancelFunc
+	pollDone chan struct{}
+}
+
+func (d *dispatcher) context() context.Context {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.ctx == nil {
+		d.ctx, d.cancel = context.WithCancel(context.Background())
+	}
+	return d.ctx
+}
+
+func (d *dispatcher) done() {
+	d.mu.Lock()
+	d.inFlight--
+	d.mu.Unlock()
+	d.wg.Done()
+}
+
+// DispatchStats describes the dispatch queue and this instance's matching.
+type DispatchStats struct {
+	// Queued counts rides waiting in the shared queue, scheduled or handed off.
+	Queued int64 `json:"queued"`
+	// Due counts queued rides whose time has come but that no instance has
+	// claimed yet; OverdueSeconds is how long the oldest has waited.
+	Due            int64 `json:"due"`
+	OverdueSeconds int64 `json:"overdueSeconds"`
+	InFlight       int   `json:"inFlight"`
+	Draining       bool  `json:"draining"`
+}
+
+func (s *service) DispatchStats(ctx context.Context) (*DispatchStats, error) {
+	d := &s.dispatch
+	d.mu.Lock()
+	stats := &DispatchStats{InFlight: d.inFlight, Draining: d.draining}
+	d.mu.Unlock()
+
+	if cache.MainClient == nil {
+		return stats, nil
+	}
+	now := time.Now().Unix()
+	pipe := cache.MainClient.Pipeline()
+	queued := pipe.ZCard(ctx, dispatchQueueKey)
+	due := pipe.ZCount(ctx, dispatchQueueKey, "-inf", strconv.FormatInt(now, 10))
+	oldest := pipe.ZRangeWithScores(ctx, dispatchQueueKey, 0, 0)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return stats, err
+	}
+
+	stats.Queued = queued.Val()
+	stats.Due = due.Val()
+	if entries := oldest.Val(); len(entries) > 0 && int64(entries[0].Score) < now {
+		stats.OverdueSeconds = now - int64(entries[0].Score)
+	}
+	return stats, nil
+}
+
+// StartDispatch starts polling the dispatch queue for scheduled rides that
+// are due and rides handed off by other instances.
+func (s *service) StartDispatch(ctx context.Context) {
+	d := &s.dispatch
+	dispatchCtx := d.context()
+
+	d.mu.Lock()
+	if d.polling != nil || d.draining {
+		d.mu.Unlock()
+		return
+	}
+	ctx, d.polling = context.WithCancel(ctx)
+	d.pollDone = make(chan struct{})
+	d.mu.Unlock()
+
+	heartbeat := health.NewHeartbeat("ride_dispatch", dispatchPollEvery)
+
+	go func() {
+		defer close(d.pollDone)
+		ticker := time.NewTicker(dispatchPollEvery)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-dispatchCtx.Done():
+				return
+			case <-ticker.C:
+				s.claimDueRides(ctx)
+				heartbeat.Beat()
+			}
+		}
+	}()
+	logger.Info("ride dispatch started")
+}
+
+// DrainDispatch stops this instance from starting new matches and waits for
+// the ones in flight until ctx is done. Matches still running then are
+// interrupted and their rides requeued for another instance.
+func (s *service) DrainDispatch(ctx context.Context) error {
+	d := &s.dispatch
+	d.context()
+
+	d.mu.Lock()
+	d.draining = true
+	polling, pollDone := d.polling, d.pollDone
+	d.mu.Unlock()
+
+	if polling != nil {
+		polling()
+		<-pollDone
+	}
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		logger.Info("ride dispatch drained")
+		return nil
+	case <-ctx.Done():
+	}
+
+	logger.Warn("ride dispatch drain timed out, handing off in-flight matches")
+	d.cancel()
+	<-done
+	return ctx.Err()
+}
+
+// startMatching runs fn for rideID on a tracked goroutine. While draining the
+// ride is queued for another instance instead.
+func (s *service) startMatching(rideID string, fn func(ctx context.Context)) {
+	d := &s.dispatch
+	ctx := d.context()
+
+	d.mu.Lock()
+	if d.draining {
+		d.mu.Unlock()
+		s.enqueueDispatch(context.Background(), rideID, time.Now())
+		return
+	}
+	d.wg.Add(1)
+	d.inFlight++
+	d.mu.Unlock()
+
+	go func() {
+		defer d.done()
+		fn(ctx)
+	}()
+}
+
+// track runs fn on a tracked goroutine that is not tied to a single ride's
+// matching, such as applying a batch assignment. fn is not interrupted by a
+// drain that times out.
+func (s *service) track(fn func()) {
+	d := &s.dispatch
+
+	d.mu.Lock()
+	d.wg.Add(1)
+	d.inFlight++
+	d.mu.Unlock()
+
+	go func() {
+		defer d.done()
+		fn()
+	}()
+}
+
+// handedOff reports whether matching stopped because this instance is
+// shutting down, requeueing the ride if so.
+func (s *service) handedOff(ctx context.Context, rideID string) bool {
+	if ctx.Err() == nil {
+		return false
+	}
+	s.enqueueDispatch(context.Background(), rideID, time.Now())
+	logger.Info("ride matching handed off", "rideID", rideID)
+	return true
+}
+
+func (s *service) enqueueDispatch(ctx context.Context, rideID string, at time.Time) {
+	if cache.MainClient == nil {
+		logger.Error("cannot queue ride for dispatch without redis", "rideID", rideID)
+		return
+	}
+	err := cache.MainClient.ZAdd(ctx, dispatchQueueKey, redis.Z{
+		Score:  float64(at.Unix()),
+		Member: rideID,
+	}).Err()
+	if err != nil {
+		logger.Error("failed to queue ride for dispatch", "rideID", rideID, "error", err)
+	}
+}
+
+func (s *service) claimDueRides(ctx context.Context) {
+	if cache.MainClient == nil {
+		return
+	}
+	rideIDs, err := cache.MainClient.ZRangeByScore(ctx, dispatchQueueKey, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(time.Now().Unix(), 10),
+		Count: dispatchClaimBatch,
+	}).Result()
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Warn("failed to read ride dispatch queue", "error", err)
+		}
+		return
+	}
+
+	for _, rideID := range rideIDs {
+		// Only the instance whose ZREM removes the entry runs the match.
+		removed, err := cache.MainClient.ZRem(ctx, dispatchQueueKey, rideID).Result()
+		if err != nil || removed == 0 {
+			continue
+		}
+		s.startMatching(rideID, func(ctx context.Context) {
+			s.resumeMatching(ctx, rideID)
+		})
+	}
+}
+
+// resumeMatching picks a queued ride back up according to its status.
+func (s *service) resumeMatching(ctx context.Context, rideID string) {
+	ride, err := s.repo.FindRideByID(ctx, rideID)
+	if err != nil {
+		logger.Error("failed to fetch queued ride", "rideID", rideID, "error", err)
+		s.handedOff(ctx, rideID)
+		return
+	}
+
+	switch ride.Status {
+	case "scheduled":
+		s.activateScheduledRide(ctx, ride)
+	case "searching":
+		s.matchRide(ctx, ride)
+	default:
+		logger.Info("queued ride no longer needs matching", "rideID", rideID, "status", ride.Status)
+	}
+}
+
+// activateScheduledRide moves a due scheduled ride to searching and matches it.
+func (s *service) activateScheduledRide(ctx context.Context, ride *models.Ride) {
+	if err := s.repo.UpdateRideStatus(ctx, ride.ID, "searching"); err != nil {
+		logger.Error("failed to update scheduled ride status to searching", "rideID", ride.ID, "error", err)
+		s.handedOff(ctx, ride.ID)
+		return
+	}
+	ride.Status = "searching"
+	livemetrics.RideActive(ctx, ride.ID)
+	logger.Info("scheduled ride activated, starting driver matching", "rideID", ride.ID)
+
+	s.matchRide(ctx, ride)
+}
+
+// matchRide offers a searching ride to drivers, through the batching service
+// when batch matching is on for the rider and sequentially otherwise. A ride nobody accepts is
+// cancelled and its hold released, unless matching was interrupted by
+// shutdown, in which case it is handed off.
+func (s *service) matchRide(ctx context.Context, ride *models.Ride) {
+	if s.batchMatching(ctx, ride.RiderID) {
+		batchID, err := s.batchingService.AddRequestToBatch(ctx, struct {
+			RideID        string
+			RiderID       string
+			PickupLat     float64
+			PickupLon     float64
+			DropoffLat    float64
+			DropoffLon    float64
+			PickupGeohash string
+			VehicleTypeID string
+			RequestedAt   time.Time
+		}{
+			RideID:        ride.ID,
+			RiderID:       ride.RiderID,
+			PickupLat:     ride.PickupLat,
+			PickupLon:     ride.PickupLon,
+			DropoffLat:    ride.DropoffLat,
+			DropoffLon:    ride.DropoffLon,
+			VehicleTypeID: ride.VehicleTypeID,
+			RequestedAt:   time.Now(),
+		})
+
+		if err == nil {
+			logger.Info("Request added to batch for intelligent matching",
+				"batchID", batchID,
+				"rideID", ride.ID,
+				"riderID", ride.RiderID,
+				"vehicleType", ride.VehicleTypeID,
+			)
+
+			if result, err := s.batchingService.ProcessBatch(ctx, batchID); err != nil {
+				logger.Error("Failed to process batch",
+					"batchID", batchID,
+					"error", err,
+				)
+			} else {
+				s.processMatchingResult(ctx, result)
+			}
+		} else {
+			logger.Warn("Failed to add request to batch, falling back to sequential matching",
+				"error", err,
+				"rideID", ride.ID,
+			)
+		}
+	}
+
+	err := s.FindDriverForRide(ctx, ride.ID)
+	if err == nil {
+		return
+	}
+	if s.handedOff(ctx, ride.ID) {
+		return
+	}
+	logger.Error("failed to find driver", "error", err, "rideID", ride.ID)
+
+	currentRide, statusErr := s.repo.FindRideByID(ctx, ride.ID)
+	if statusErr != nil {
+		logger.Error("failed to fetch ride status", "error", statusErr, "rideID", ride.ID)
+		return
+	}
+
+	if currentRide.Status != "searching" {
+		logger.Info("ride already accepted by driver, not canceling",
+			"rideID", ride.ID,
+			"currentStatus", currentRide.Status,
+			"driverID", currentRide.DriverID,
+		)
+		return
+	}
+
+	if err := s.repo.UpdateRideStatus(ctx, ride.ID, "cancelled"); err != nil {
+		logger.Error("failed to update ride status", "error", err, "rideID", ride.ID)
+	}
+	livemetrics.RideEnded(ctx, ride.ID)
+
+	if ride.WalletHoldID != nil {
+		if err := s.walletService.ReleaseHold(ctx, ride.RiderID, walletdto.ReleaseHoldRequest{HoldID: *ride.WalletHoldID}); err != nil {
+			logger.Error("failed to release hold", "error", err, "rideID", ride.ID)
+		}
+	}
+
+	s.wsHelper.SendRideStatusToBoth(ctx, ride.RiderID, "", ride.ID, "cancelled", "No drivers are currently active in you area.")
+}
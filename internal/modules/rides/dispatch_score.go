@@ -0,0 +1,143 @@
+package rides
+
+import (
+	"context"
+	"math"
+	"sort"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/rides/dto"
+	trackingdto "github.com/umar5678/go-backend/internal/modules/tracking/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+// The weights of the dispatch score are feature flags so admins can tune
+// them at runtime. Setting them all to 0 contacts drivers nearest first.
+const (
+	flagDispatchDistanceWeight     = "rides.dispatch.distance_weight"
+	flagDispatchAcceptanceWeight   = "rides.dispatch.acceptance_weight"
+	flagDispatchCancellationWeight = "rides.dispatch.cancellation_weight"
+	flagDispatchRatingWeight       = "rides.dispatch.rating_weight"
+)
+
+// dispatchStatsWindow is how many recent ride requests and rides a driver's
+// acceptance and cancellation rates are taken over.
+const dispatchStatsWindow = 100
+
+var defaultDispatchWeights = dto.DispatchWeights{
+	Distance:     0.4,
+	Acceptance:   0.25,
+	Cancellation: 0.15,
+	Rating:       0.2,
+}
+
+func (s *service) dispatchWeights(ctx context.Context) dto.DispatchWeights {
+	weights := defaultDispatchWeights
+	if s.flags == nil {
+		return weights
+	}
+	weight := func(key string, def float64) float64 {
+		return math.Max(0, s.flags.Float(ctx, key, def))
+	}
+	weights.Distance = weight(flagDispatchDistanceWeight, weights.Distance)
+	weights.Acceptance = weight(flagDispatchAcceptanceWeight, weights.Acceptance)
+	weights.Cancellation = weight(flagDispatchCancellationWeight, weights.Cancellation)
+	weights.Rating = weight(flagDispatchRatingWeight, weights.Rating)
+	return weights
+}
+
+// dispatchScore rates a candidate between 0 and 1: close by, quick to
+// accept, rarely cancelling and well rated scores high.
+func dispatchScore(weights dto.DispatchWeights, driver *models.DriverProfile, distanceKm, radiusKm float64) dto.DispatchScoreBreakdown {
+	clamp := func(v float64) float64 { return math.Max(0, math.Min(1, v)) }
+
+	breakdown := dto.DispatchScoreBreakdown{
+		Acceptance:   clamp(driver.AcceptanceRate / 100),
+		Cancellation: clamp(1 - driver.CancellationRate/100),
+		Rating:       clamp(driver.Rating / 5),
+	}
+	if radiusKm > 0 {
+		breakdown.Distance = clamp(1 - distanceKm/radiusKm)
+	}
+
+	total := weights.Distance + weights.Acceptance + weights.Cancellation + weights.Rating
+	if total > 0 {
+		breakdown.Score = (weights.Distance*breakdown.Distance +
+			weights.Acceptance*breakdown.Acceptance +
+			weights.Cancellation*breakdown.Cancellation +
+			weights.Rating*breakdown.Rating) / total
+	}
+	return breakdown
+}
+
+// rankByDispatchScore orders the drivers found within radiusKm by their
+// dispatch score, best first. Drivers with the same score stay nearest
+// first.
+func (s *service) rankByDispatchScore(ctx context.Context, rideID string, drivers []trackingdto.DriverLocationResponse, radiusKm float64) {
+	if len(drivers) < 2 {
+		return
+	}
+	weights := s.dispatchWeights(ctx)
+	if weights.Distance+weights.Acceptance+weights.Cancellation+weights.Rating == 0 {
+		return
+	}
+
+	ids := make([]string, len(drivers))
+	for i, driver := range drivers {
+		ids[i] = driver.DriverID
+	}
+	profiles, err := s.repo.FindDriverDispatchStats(ctx, ids)
+	if err != nil {
+		logger.Warn("failed to load driver dispatch stats, keeping distance order", "error", err, "rideID", rideID)
+		return
+	}
+
+	scores := make(map[string]float64, len(drivers))
+	for _, driver := range drivers {
+		profile, ok := profiles[driver.DriverID]
+		if !ok {
+			continue
+		}
+		scores[driver.DriverID] = dispatchScore(weights, profile, driver.Distance, radiusKm).Score
+	}
+	sort.SliceStable(drivers, func(i, j int) bool {
+		return scores[drivers[i].DriverID] > scores[drivers[j].DriverID]
+	})
+}
+
+// refreshDispatchStats recomputes a driver's acceptance and cancellation
+// rates after they answered a ride request or finished a ride.
+func (s *service) refreshDispatchStats(driverProfileID string) {
+	go func() {
+		if err := s.repo.RefreshDriverDispatchStats(context.Background(), driverProfileID, dispatchStatsWindow); err != nil {
+			logger.Warn("failed to refresh driver dispatch stats", "error", err, "driverID", driverProfileID)
+		}
+	}()
+}
+
+// GetDriverDispatchScore shows how a driver ranks in matching: their rates,
+// the current weights and their score for a pickup at the edge of the
+// search radius.
+func (s *service) GetDriverDispatchScore(ctx context.Context, driverID string) (*dto.DriverDispatchScoreResponse, error) {
+	profiles, err := s.repo.FindDriverDispatchStats(ctx, []string{driverID})
+	if err != nil {
+		return nil, response.InternalServerError("Failed to fetch driver", err)
+	}
+	driver, ok := profiles[driverID]
+	if !ok {
+		return nil, response.NotFoundError("Driver")
+	}
+
+	weights := s.dispatchWeights(ctx)
+	return &dto.DriverDispatchScoreResponse{
+		DriverID:         driver.ID,
+		AcceptanceRate:   driver.AcceptanceRate,
+		CancellationRate: driver.CancellationRate,
+		Rating:           driver.Rating,
+		Weights:          weights,
+		// Distance counts for nothing at the edge of the radius, so this is
+		// the part of the score the driver earns by their record.
+		Score: dispatchScore(weights, driver, 1, 1),
+	}, nil
+}
@@ -0,0 +1,159 @@
+package rides
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	trackingdto "github.com/umar5678/go-backend/internal/modules/tracking/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+const (
+	defaultDispatchBatchSize    = 3
+	defaultDispatchOfferTimeout = 10 * time.Second
+
+	// Broadcast offers the ride to every driver found, so it searches for
+	// more of them than the other modes need.
+	dispatchCandidateLimit          = 15
+	broadcastDispatchCandidateLimit = 50
+
+	// offerGrace lets an offer's own expiry run before the wave gives up on
+	// it, so the driver's missed offer counts against their acceptance rate.
+	offerGrace = 2 * time.Second
+)
+
+// dispatchStrategy splits the drivers found for a ride, best first, into the
+// waves they are offered the ride in. A wave is offered at once and the next
+// one only after every driver in it has declined or let the offer expire.
+type dispatchStrategy interface {
+	Waves(drivers []trackingdto.DriverLocationResponse) [][]trackingdto.DriverLocationResponse
+}
+
+// batchStrategy offers the ride to size drivers at a time. Sequential
+// dispatch is a batch of one.
+type batchStrategy struct {
+	size int
+}
+
+func (b batchStrategy) Waves(drivers []trackingdto.DriverLocationResponse) [][]trackingdto.DriverLocationResponse {
+	waves := make([][]trackingdto.DriverLocationResponse, 0, (len(drivers)+b.size-1)/b.size)
+	for start := 0; start < len(drivers); start += b.size {
+		waves = append(waves, drivers[start:min(start+b.size, len(drivers))])
+	}
+	return waves
+}
+
+// broadcastStrategy offers the ride to every driver found at once.
+type broadcastStrategy struct{}
+
+func (broadcastStrategy) Waves(drivers []trackingdto.DriverLocationResponse) [][]trackingdto.DriverLocationResponse {
+	if len(drivers) == 0 {
+		return nil
+	}
+	return [][]trackingdto.DriverLocationResponse{drivers}
+}
+
+func newDispatchStrategy(settings models.DispatchSettings) dispatchStrategy {
+	switch settings.Mode {
+	case models.DispatchModeSequential:
+		return batchStrategy{size: 1}
+	case models.DispatchModeBroadcast:
+		return broadcastStrategy{}
+	default:
+		return batchStrategy{size: settings.BatchSize}
+	}
+}
+
+func (s *service) ConfigureDispatch(cfg config.DispatchConfig) {
+	s.dispatchCfg = &cfg
+}
+
+// dispatchSettings works out how a ride is offered to drivers: the pickup
+// city's settings first, then the platform's, then the built-in defaults.
+func (s *service) dispatchSettings(ctx context.Context, ride *models.Ride) models.DispatchSettings {
+	var settings models.DispatchSettings
+	if s.serviceAreas != nil {
+		settings = s.serviceAreas.DispatchSettings(ctx, ride.PickupLat, ride.PickupLon, ride.VehicleTypeID)
+	}
+
+	if cfg := s.dispatchCfg; cfg != nil {
+		if settings.Mode == "" {
+			settings.Mode = cfg.Mode
+		}
+		if settings.BatchSize <= 0 {
+			settings.BatchSize = cfg.BatchSize
+		}
+		if settings.OfferTimeout <= 0 {
+			settings.OfferTimeout = cfg.VehicleOfferTimeouts[ride.VehicleType.Name]
+		}
+		if settings.OfferTimeout <= 0 {
+			settings.OfferTimeout = cfg.OfferTimeout
+		}
+	}
+
+	if settings.Mode == "" {
+		settings.Mode = models.DispatchModeBatch
+	}
+	if settings.BatchSize <= 0 {
+		settings.BatchSize = defaultDispatchBatchSize
+	}
+	if settings.OfferTimeout <= 0 {
+		settings.OfferTimeout = defaultDispatchOfferTimeout
+	}
+	return settings
+}
+
+func dispatchCandidates(settings models.DispatchSettings) int {
+	if settings.Mode == models.DispatchModeBroadcast {
+		return broadcastDispatchCandidateLimit
+	}
+	return dispatchCandidateLimit
+}
+
+// offerWave offers the ride to a wave of drivers and returns the driver
+// profile ID of the one who accepted, or "" once every offer has been
+// declined or expired. Offers still open when a driver accepts are
+// withdrawn. A driver who cannot be reached does not hold up the others.
+// Offers that meet a driver's auto-accept rules are accepted for them.
+func (s *service) offerWave(ctx context.Context, ride *models.Ride, drivers []trackingdto.DriverLocationResponse, offerTimeout time.Duration, prefs map[string]*models.DriverDispatchPreferences) string {
+	waveCtx, cancel := context.WithTimeout(ctx, offerTimeout+offerGrace)
+	defer cancel()
+
+	resultChan := make(chan string, len(drivers))
+	errorChan := make(chan error, len(drivers))
+
+	var wg sync.WaitGroup
+	for _, driver := range drivers {
+		wg.Add(1)
+		go func(driver trackingdto.DriverLocationResponse) {
+			defer wg.Done()
+			autoAccept := prefs[driver.DriverID].AutoAccepts(driver.Distance, ride.EstimatedFare)
+			s.sendRideRequestToDriver(waveCtx, ride, driver, offerTimeout, autoAccept, resultChan, errorChan)
+		}(driver)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	for {
+		select {
+		case driverID := <-resultChan:
+			return driverID
+		case err := <-errorChan:
+			logger.Warn("failed to offer ride to driver", "error", err, "rideID", ride.ID)
+		case <-done:
+			select {
+			case driverID := <-resultChan:
+				return driverID
+			default:
+				return ""
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package rides
+
+import (
+	"context"
+	"sort"
+
+	"github.com/umar5678/go-backend/internal/models"
+	trackingdto "github.com/umar5678/go-backend/internal/modules/tracking/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+// driverPreferences loads the dispatch preferences of the drivers found for
+// a ride. Matching goes on without them if they cannot be read.
+func (s *service) driverPreferences(ctx context.Context, rideID string, drivers []trackingdto.DriverLocationResponse) map[string]*models.DriverDispatchPreferences {
+	ids := make([]string, len(drivers))
+	for i, driver := range drivers {
+		ids[i] = driver.DriverID
+	}
+	prefs, err := s.driversRepo.FindDispatchPreferences(ctx, ids)
+	if err != nil {
+		logger.Warn("failed to load driver dispatch preferences", "error", err, "rideID", rideID)
+		return nil
+	}
+	return prefs
+}
+
+// preferDriversInPreferredAreas moves drivers who prefer to work where the
+// ride is picked up to the front of the list, keeping the order within each
+// group.
+func preferDriversInPreferredAreas(ride *models.Ride, drivers []trackingdto.DriverLocationResponse, prefs map[string]*models.DriverDispatchPreferences) {
+	if len(drivers) < 2 || len(prefs) == 0 {
+		return
+	}
+	preferred := make(map[string]bool, len(prefs))
+	for driverID, p := range prefs {
+		preferred[driverID] = p.Prefers(ride.PickupLat, ride.PickupLon)
+	}
+	sort.SliceStable(drivers, func(i, j int) bool {
+		return preferred[drivers[i].DriverID] && !preferred[drivers[j].DriverID]
+	})
+}
+
+// autoAcceptRide accepts an offer on behalf of a driver whose auto-accept
+// rules it meets. If the ride can no longer be accepted, for example because
+// the driver went offline, the offer stays open for them to answer.
+func (s *service) autoAcceptRide(ctx context.Context, ride *models.Ride, driver *models.DriverProfile, requestID string) {
+	if _, err := s.AcceptRide(ctx, driver.UserID, ride.ID); err != nil {
+		logger.Info("ride could not be auto-accepted",
+			"error", err,
+			"rideID", ride.ID,
+			"driverID", driver.ID,
+			"requestID", requestID,
+		)
+		return
+	}
+	logger.Info("ride auto-accepted for driver",
+		"rideID", ride.ID,
+		"driverID", driver.ID,
+		"requestID", requestID,
+	)
+}
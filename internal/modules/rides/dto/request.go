@@ -6,22 +6,35 @@ import (
 )
 
 type CreateRideRequest struct {
-	PickupLat       float64 `json:"pickupLat" binding:"required,min=-90,max=90"`
-	PickupLon       float64 `json:"pickupLon" binding:"required,min=-180,max=180"`
-	PickupAddress   string  `json:"pickupAddress" binding:"required,max=500"`
-	DropoffLat      float64 `json:"dropoffLat" binding:"required,min=-90,max=90"`
-	DropoffLon      float64 `json:"dropoffLon" binding:"required,min=-180,max=180"`
-	DropoffAddress  string  `json:"dropoffAddress" binding:"required,max=500"`
-	SavedLocationID *string `json:"savedLocationId" binding:"omitempty,uuid"`
-	UseSavedAs      string  `json:"useSavedAs" binding:"omitempty,oneof=pickup dropoff"`
-	VehicleTypeID   string  `json:"vehicleTypeId" binding:"required,uuid"`
-	RiderNotes      string  `json:"riderNotes" binding:"omitempty,max=500"`
-	PromoCode       string  `json:"promoCode" binding:"omitempty,min=3,max=50"`
-	IsScheduled     bool    `json:"isScheduled" binding:"omitempty"`
-	ScheduledAt string `json:"scheduledAt" binding:"omitempty"`
+	PickupLat        float64 `json:"pickupLat" binding:"omitempty,min=-90,max=90"`
+	PickupLon        float64 `json:"pickupLon" binding:"omitempty,min=-180,max=180"`
+	PickupAddress    string  `json:"pickupAddress" binding:"omitempty,max=500"`
+	DropoffLat       float64 `json:"dropoffLat" binding:"omitempty,min=-90,max=90"`
+	DropoffLon       float64 `json:"dropoffLon" binding:"omitempty,min=-180,max=180"`
+	DropoffAddress   string  `json:"dropoffAddress" binding:"omitempty,max=500"`
+	PickupAddressID  *string `json:"pickupAddressId" binding:"omitempty,uuid"`
+	DropoffAddressID *string `json:"dropoffAddressId" binding:"omitempty,uuid"`
+	SavedLocationID  *string `json:"savedLocationId" binding:"omitempty,uuid"`
+	UseSavedAs       string  `json:"useSavedAs" binding:"omitempty,oneof=pickup dropoff"`
+	VehicleTypeID    string  `json:"vehicleTypeId" binding:"required,uuid"`
+	RiderNotes       string  `json:"riderNotes" binding:"omitempty,max=500"`
+	PromoCode        string  `json:"promoCode" binding:"omitempty,min=3,max=50"`
+	IsScheduled      bool    `json:"isScheduled" binding:"omitempty"`
+	PaymentMethod    string  `json:"paymentMethod" binding:"omitempty,oneof=wallet cash"`
+	ScheduledAt      string  `json:"scheduledAt" binding:"omitempty"`
+	Passengers       int     `json:"passengers" binding:"omitempty,min=1,max=20"`
+	Luggage          int     `json:"luggage" binding:"omitempty,min=0,max=20"`
+	// FareLockToken is the token from the fare estimate the rider accepted.
+	FareLockToken string `json:"fareLockToken" binding:"omitempty,max=64"`
 }
 
 func (r *CreateRideRequest) Validate() error {
+	if r.PickupLat == 0 && r.PickupLon == 0 {
+		return errors.New("pickup location is required")
+	}
+	if r.DropoffLat == 0 && r.DropoffLon == 0 {
+		return errors.New("dropoff location is required")
+	}
 	if r.PickupLat == r.DropoffLat && r.PickupLon == r.DropoffLon {
 		return errors.New("pickup and dropoff locations must be different")
 	}
@@ -98,3 +111,98 @@ type VehicleDetailsRequest struct {
 	DropoffAddress string  `json:"dropoffAddress" binding:"required,max=500"`
 	RadiusKm       float64 `json:"radiusKm" binding:"omitempty,min=0.1,max=50"`
 }
+
+// DriverCashReportRequest selects a period of completed cash rides. Dates are
+// YYYY-MM-DD and inclusive; the default is the last 30 days.
+type DriverCashReportRequest struct {
+	From  string `form:"from" binding:"omitempty"`
+	To    string `form:"to" binding:"omitempty"`
+	Page  int    `form:"page" binding:"omitempty,min=1"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (r *DriverCashReportRequest) SetDefaults() {
+	if r.Page == 0 {
+		r.Page = 1
+	}
+	if r.Limit == 0 {
+		r.Limit = 20
+	}
+}
+
+// Period returns the half-open [from, to) range covered by the request.
+func (r *DriverCashReportRequest) Period(defaultDays int) (time.Time, time.Time, error) {
+	now := time.Now()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+	if r.To != "" {
+		t, err := time.ParseInLocation("2006-01-02", r.To, now.Location())
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("to must be a date in YYYY-MM-DD format")
+		}
+		to = t.AddDate(0, 0, 1)
+	}
+
+	from := to.AddDate(0, 0, -defaultDays)
+	if r.From != "" {
+		t, err := time.ParseInLocation("2006-01-02", r.From, now.Location())
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("from must be a date in YYYY-MM-DD format")
+		}
+		from = t
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, errors.New("from must not be after to")
+	}
+	return from, to, nil
+}
+
+// ListTripVerificationsRequest filters trip verifications for admin review.
+// The default is rides still flagged.
+type ListTripVerificationsRequest struct {
+	Status string `form:"status" binding:"omitempty,oneof=verified flagged insufficient_data reviewed"`
+	Page   int    `form:"page" binding:"omitempty,min=1"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (r *ListTripVerificationsRequest) SetDefaults() {
+	if r.Status == "" {
+		r.Status = "flagged"
+	}
+	if r.Page == 0 {
+		r.Page = 1
+	}
+	if r.Limit == 0 {
+		r.Limit = 20
+	}
+}
+
+type ReviewTripVerificationRequest struct {
+	Note string `json:"note" binding:"required,max=1000"`
+}
+
+// ReportNoShowRequest is the evidence a driver sends when the rider never
+// came out: a note, photos of the pickup and where the driver is waiting.
+type ReportNoShowRequest struct {
+	Note      string   `json:"note" binding:"omitempty,max=1000"`
+	PhotoURLs []string `json:"photoUrls" binding:"omitempty,max=5,dive,url"`
+	Latitude  *float64 `json:"latitude" binding:"omitempty,min=-90,max=90"`
+	Longitude *float64 `json:"longitude" binding:"omitempty,min=-180,max=180"`
+}
+
+func (r *ReportNoShowRequest) Validate() error {
+	if (r.Latitude == nil) != (r.Longitude == nil) {
+		return errors.New("latitude and longitude must be sent together")
+	}
+	if r.Note == "" && len(r.PhotoURLs) == 0 && r.Latitude == nil {
+		return errors.New("a note, a photo or the current location is required as evidence")
+	}
+	return nil
+}
+
+// InviteFareSplitRequest invites a co-rider, found by phone number, to pay
+// SharePercent of the fare.
+type InviteFareSplitRequest struct {
+	Phone        string  `json:"phone" binding:"required,min=7,max=20"`
+	SharePercent float64 `json:"sharePercent" binding:"required,gt=0,lt=100"`
+}
@@ -1,10 +1,13 @@
 package dto
 
 import (
+	"math"
+	"strings"
 	"time"
 
 	"github.com/umar5678/go-backend/internal/models"
 	authdto "github.com/umar5678/go-backend/internal/modules/auth/dto"
+	pricingdto "github.com/umar5678/go-backend/internal/modules/pricing/dto"
 	vehicledto "github.com/umar5678/go-backend/internal/modules/vehicles/dto"
 )
 
@@ -30,6 +33,7 @@ type RideResponse struct {
 	EstimatedDistance float64 `json:"estimatedDistance"`
 	EstimatedDuration int     `json:"estimatedDuration"`
 	EstimatedFare     float64 `json:"estimatedFare"`
+	Currency          string  `json:"currency,omitempty"`
 
 	ActualDistance *float64 `json:"actualDistance,omitempty"`
 	ActualDuration *int     `json:"actualDuration,omitempty"`
@@ -40,6 +44,10 @@ type RideResponse struct {
 	DriverFare *float64 `json:"driverFare,omitempty"`
 	RiderFare  *float64 `json:"riderFare,omitempty"`
 
+	PaymentMethod  string   `json:"paymentMethod"`
+	CashCollected  *float64 `json:"cashCollected,omitempty"`
+	CashCommission *float64 `json:"cashCommission,omitempty"`
+
 	SurgeMultiplier    float64 `json:"surgeMultiplier"`
 	RiderNotes         string  `json:"riderNotes,omitempty"`
 	CancellationReason string  `json:"cancellationReason,omitempty"`
@@ -62,6 +70,9 @@ type RideResponse struct {
 	UpdatedAt time.Time `json:"updatedAt"`
 
 	DriverLocation *LocationDTO `json:"driverLocation,omitempty"`
+
+	Route      *RideRouteDTO  `json:"route,omitempty"`
+	Navigation *NavigationDTO `json:"navigation,omitempty"`
 }
 
 type RideListResponse struct {
@@ -70,6 +81,7 @@ type RideListResponse struct {
 	PickupAddress  string    `json:"pickupAddress"`
 	DropoffAddress string    `json:"dropoffAddress"`
 	EstimatedFare  float64   `json:"estimatedFare"`
+	Currency       string    `json:"currency,omitempty"`
 	RequestedAt    time.Time `json:"requestedAt"`
 }
 
@@ -95,6 +107,7 @@ func ToRideResponse(ride *models.Ride) *RideResponse {
 		EstimatedDistance:  ride.EstimatedDistance,
 		EstimatedDuration:  ride.EstimatedDuration,
 		EstimatedFare:      ride.EstimatedFare,
+		Currency:           ride.Currency,
 		ActualDistance:     ride.ActualDistance,
 		ActualDuration:     ride.ActualDuration,
 		ActualFare:         ride.ActualFare,
@@ -108,6 +121,9 @@ func ToRideResponse(ride *models.Ride) *RideResponse {
 		WaitTimeCharge:     ride.WaitTimeCharge,
 		DriverFare:         ride.DriverFare,
 		RiderFare:          ride.RiderFare,
+		PaymentMethod:      ride.PaymentMethod,
+		CashCollected:      ride.CashCollected,
+		CashCommission:     ride.CashCommission,
 		RequestedAt:        ride.RequestedAt,
 		AcceptedAt:         ride.AcceptedAt,
 		ArrivedAt:          ride.ArrivedAt,
@@ -138,6 +154,7 @@ func ToRideListResponse(ride *models.Ride) *RideListResponse {
 		PickupAddress:  ride.PickupAddress,
 		DropoffAddress: ride.DropoffAddress,
 		EstimatedFare:  ride.EstimatedFare,
+		Currency:       ride.Currency,
 		RequestedAt:    ride.RequestedAt,
 	}
 }
@@ -147,6 +164,28 @@ type LocationDTO struct {
 	Longitude float64 `json:"longitude"`
 }
 
+// RideRouteDTO is the pickup to dropoff route. Polyline uses Google's encoded
+// polyline format; Source is the routing engine or "estimate".
+type RideRouteDTO struct {
+	Polyline        string  `json:"polyline"`
+	DistanceKm      float64 `json:"distanceKm"`
+	DurationSeconds int     `json:"durationSeconds"`
+	Source          string  `json:"source"`
+}
+
+type NavigationLinksDTO struct {
+	GoogleMaps string `json:"googleMaps"`
+	Waze       string `json:"waze"`
+	AppleMaps  string `json:"appleMaps"`
+}
+
+// NavigationDTO holds deep links for both legs of the trip. ToPickup starts
+// from the driver's location, ToDropoff from the pickup.
+type NavigationDTO struct {
+	ToPickup  NavigationLinksDTO `json:"toPickup"`
+	ToDropoff NavigationLinksDTO `json:"toDropoff"`
+}
+
 type AvailableCarResponse struct {
 	ID                 string    `json:"id"`
 	DriverID           string    `json:"driverId"`
@@ -278,3 +317,338 @@ func getVehicleName(profile *models.DriverProfile) string {
 	}
 	return profile.Vehicle.Make
 }
+
+type DriverCashSummaryResponse struct {
+	From           time.Time `json:"from"`
+	To             time.Time `json:"to"`
+	CashRides      int       `json:"cashRides"`
+	CashCollected  float64   `json:"cashCollected"`
+	Commission     float64   `json:"commission"`
+	DriverEarnings float64   `json:"driverEarnings"`
+
+	WalletBalance        float64 `json:"walletBalance"`
+	Currency             string  `json:"currency,omitempty"`
+	AmountOwed           float64 `json:"amountOwed"`
+	NegativeBalanceLimit float64 `json:"negativeBalanceLimit"`
+	RemainingCashCredit  float64 `json:"remainingCashCredit"`
+	IsRestricted         bool    `json:"isRestricted"`
+}
+
+type CashRideResponse struct {
+	ID             string     `json:"id"`
+	PickupAddress  string     `json:"pickupAddress"`
+	DropoffAddress string     `json:"dropoffAddress"`
+	CashCollected  float64    `json:"cashCollected"`
+	Commission     float64    `json:"commission"`
+	DriverFare     float64    `json:"driverFare"`
+	CompletedAt    *time.Time `json:"completedAt,omitempty"`
+}
+
+func ToCashRideResponse(ride *models.Ride) *CashRideResponse {
+	resp := &CashRideResponse{
+		ID:             ride.ID,
+		PickupAddress:  ride.PickupAddress,
+		DropoffAddress: ride.DropoffAddress,
+		CompletedAt:    ride.CompletedAt,
+	}
+	if ride.CashCollected != nil {
+		resp.CashCollected = *ride.CashCollected
+	}
+	if ride.CashCommission != nil {
+		resp.Commission = *ride.CashCommission
+	}
+	if ride.DriverFare != nil {
+		resp.DriverFare = *ride.DriverFare
+	}
+	return resp
+}
+
+type TripVerificationResponse struct {
+	ID                   string     `json:"id"`
+	RideID               string     `json:"rideId"`
+	DriverID             string     `json:"driverId"`
+	ReportedDistanceKm   float64    `json:"reportedDistanceKm"`
+	TrackedDistanceKm    float64    `json:"trackedDistanceKm"`
+	DistanceDeviationPct float64    `json:"distanceDeviationPct"`
+	ReportedDurationSec  int        `json:"reportedDurationSec"`
+	TrackedDurationSec   int        `json:"trackedDurationSec"`
+	DurationDeviationPct float64    `json:"durationDeviationPct"`
+	TrackPointCount      int        `json:"trackPointCount"`
+	Status               string     `json:"status"`
+	Reasons              []string   `json:"reasons"`
+	ReviewedBy           *string    `json:"reviewedBy,omitempty"`
+	ReviewNote           string     `json:"reviewNote,omitempty"`
+	ReviewedAt           *time.Time `json:"reviewedAt,omitempty"`
+	CreatedAt            time.Time  `json:"createdAt"`
+}
+
+func ToTripVerificationResponse(v *models.RideTripVerification) *TripVerificationResponse {
+	reasons := []string(v.Reasons)
+	if reasons == nil {
+		reasons = []string{}
+	}
+	return &TripVerificationResponse{
+		ID:                   v.ID,
+		RideID:               v.RideID,
+		DriverID:             v.DriverID,
+		ReportedDistanceKm:   v.ReportedDistanceKm,
+		TrackedDistanceKm:    v.TrackedDistanceKm,
+		DistanceDeviationPct: v.DistanceDeviationPct,
+		ReportedDurationSec:  v.ReportedDurationSec,
+		TrackedDurationSec:   v.TrackedDurationSec,
+		DurationDeviationPct: v.DurationDeviationPct,
+		TrackPointCount:      v.TrackPointCount,
+		Status:               string(v.Status),
+		Reasons:              reasons,
+		ReviewedBy:           v.ReviewedBy,
+		ReviewNote:           v.ReviewNote,
+		ReviewedAt:           v.ReviewedAt,
+		CreatedAt:            v.CreatedAt,
+	}
+}
+
+type TripShareLinkResponse struct {
+	RideID    string    `json:"rideId"`
+	Token     string    `json:"token"`
+	URL       string    `json:"url"`
+	LiveURL   string    `json:"liveUrl"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type SharedTripLocation struct {
+	Latitude  float64   `json:"latitude"`
+	Longitude float64   `json:"longitude"`
+	Heading   int       `json:"heading"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type SharedTripDriver struct {
+	FirstName       string   `json:"firstName"`
+	ProfilePhotoURL *string  `json:"profilePhotoUrl,omitempty"`
+	Rating          *float64 `json:"rating,omitempty"`
+}
+
+type SharedTripVehicle struct {
+	Make         string `json:"make"`
+	Model        string `json:"model"`
+	Color        string `json:"color"`
+	LicensePlate string `json:"licensePlate"`
+}
+
+// SharedTripResponse is the public view of a shared trip. It deliberately
+// leaves out phone numbers, fares and IDs other than the ride's own.
+type SharedTripResponse struct {
+	RideID         string              `json:"rideId"`
+	Status         string              `json:"status"`
+	IsActive       bool                `json:"isActive"`
+	RiderFirstName string              `json:"riderFirstName"`
+	PickupAddress  string              `json:"pickupAddress"`
+	PickupLat      float64             `json:"pickupLat"`
+	PickupLon      float64             `json:"pickupLon"`
+	DropoffAddress string              `json:"dropoffAddress"`
+	DropoffLat     float64             `json:"dropoffLat"`
+	DropoffLon     float64             `json:"dropoffLon"`
+	Driver         *SharedTripDriver   `json:"driver,omitempty"`
+	Vehicle        *SharedTripVehicle  `json:"vehicle,omitempty"`
+	DriverLocation *SharedTripLocation `json:"driverLocation,omitempty"`
+
+	EstimatedDuration int        `json:"estimatedDuration"`
+	AcceptedAt        *time.Time `json:"acceptedAt,omitempty"`
+	ArrivedAt         *time.Time `json:"arrivedAt,omitempty"`
+	StartedAt         *time.Time `json:"startedAt,omitempty"`
+	CompletedAt       *time.Time `json:"completedAt,omitempty"`
+	CancelledAt       *time.Time `json:"cancelledAt,omitempty"`
+
+	ExpiresAt             time.Time `json:"expiresAt"`
+	UpdateIntervalSeconds int       `json:"updateIntervalSeconds"`
+}
+
+func ToSharedTripResponse(ride *models.Ride) *SharedTripResponse {
+	resp := &SharedTripResponse{
+		RideID:            ride.ID,
+		Status:            ride.Status,
+		RiderFirstName:    firstName(ride.Rider.Name),
+		PickupAddress:     ride.PickupAddress,
+		PickupLat:         ride.PickupLat,
+		PickupLon:         ride.PickupLon,
+		DropoffAddress:    ride.DropoffAddress,
+		DropoffLat:        ride.DropoffLat,
+		DropoffLon:        ride.DropoffLon,
+		EstimatedDuration: ride.EstimatedDuration,
+		AcceptedAt:        ride.AcceptedAt,
+		ArrivedAt:         ride.ArrivedAt,
+		StartedAt:         ride.StartedAt,
+		CompletedAt:       ride.CompletedAt,
+		CancelledAt:       ride.CancelledAt,
+	}
+
+	if ride.Driver != nil {
+		resp.Driver = &SharedTripDriver{
+			FirstName:       firstName(ride.Driver.Name),
+			ProfilePhotoURL: ride.Driver.ProfilePhotoURL,
+		}
+		if ride.DriverProfile != nil {
+			rating := ride.DriverProfile.Rating
+			resp.Driver.Rating = &rating
+		}
+	}
+	if ride.DriverProfile != nil && ride.DriverProfile.Vehicle != nil {
+		v := ride.DriverProfile.Vehicle
+		resp.Vehicle = &SharedTripVehicle{
+			Make:         v.Make,
+			Model:        v.Model,
+			Color:        v.Color,
+			LicensePlate: v.LicensePlate,
+		}
+	}
+
+	return resp
+}
+
+// DispatchWeights are how much each part of the dispatch score counts.
+// They are relative to each other and need not add up to 1.
+type DispatchWeights struct {
+	Distance     float64 `json:"distance"`
+	Acceptance   float64 `json:"acceptance"`
+	Cancellation float64 `json:"cancellation"`
+	Rating       float64 `json:"rating"`
+}
+
+// DispatchScoreBreakdown holds each part of a dispatch score between 0 and
+// 1, and the weighted score.
+type DispatchScoreBreakdown struct {
+	Distance     float64 `json:"distance"`
+	Acceptance   float64 `json:"acceptance"`
+	Cancellation float64 `json:"cancellation"`
+	Rating       float64 `json:"rating"`
+	Score        float64 `json:"score"`
+}
+
+type DriverDispatchScoreResponse struct {
+	DriverID         string                 `json:"driverId"`
+	AcceptanceRate   float64                `json:"acceptanceRate"`
+	CancellationRate float64                `json:"cancellationRate"`
+	Rating           float64                `json:"rating"`
+	Weights          DispatchWeights        `json:"weights"`
+	Score            DispatchScoreBreakdown `json:"score"`
+}
+
+func firstName(name string) string {
+	if fields := strings.Fields(name); len(fields) > 0 {
+		return fields[0]
+	}
+	return ""
+}
+
+type NoShowReportResponse struct {
+	ID             string    `json:"id"`
+	RideID         string    `json:"rideId"`
+	AbsentParty    string    `json:"absentParty"`
+	Note           string    `json:"note,omitempty"`
+	PhotoURLs      []string  `json:"photoUrls"`
+	DistanceMeters *float64  `json:"distanceMeters,omitempty"`
+	WaitedMinutes  int       `json:"waitedMinutes"`
+	RiderFee       float64   `json:"riderFee"`
+	PolicyName     string    `json:"policyName,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+func ToNoShowReportResponse(report *models.NoShowReport) *NoShowReportResponse {
+	photos := []string(report.PhotoURLs)
+	if photos == nil {
+		photos = []string{}
+	}
+	return &NoShowReportResponse{
+		ID:             report.ID,
+		RideID:         report.ReferenceID,
+		AbsentParty:    report.AbsentParty,
+		Note:           report.Note,
+		PhotoURLs:      photos,
+		DistanceMeters: report.DistanceMeters,
+		WaitedMinutes:  report.WaitedMinutes,
+		RiderFee:       report.CustomerFee,
+		PolicyName:     report.PolicyName,
+		CreatedAt:      report.CreatedAt,
+	}
+}
+
+type FareSplitParticipantResponse struct {
+	ID              string     `json:"id"`
+	UserID          string     `json:"userId"`
+	Name            string     `json:"name,omitempty"`
+	SharePercent    float64    `json:"sharePercent"`
+	Status          string     `json:"status"`
+	HoldAmount      *float64   `json:"holdAmount,omitempty"`
+	ChargedAmount   *float64   `json:"chargedAmount,omitempty"`
+	PaidByOrganizer bool       `json:"paidByOrganizer"`
+	RespondedAt     *time.Time `json:"respondedAt,omitempty"`
+	CreatedAt       time.Time  `json:"createdAt"`
+}
+
+// FareSplitResponse is how a ride's fare is shared. OrganizerSharePercent is
+// what the rider who booked the ride pays with the open invitations counted.
+type FareSplitResponse struct {
+	RideID                string                          `json:"rideId"`
+	OrganizerID           string                          `json:"organizerId"`
+	EstimatedFare         float64                         `json:"estimatedFare"`
+	Currency              string                          `json:"currency"`
+	OrganizerSharePercent float64                         `json:"organizerSharePercent"`
+	Participants          []*FareSplitParticipantResponse `json:"participants"`
+}
+
+func ToFareSplitParticipantResponse(split *models.RideFareSplit) *FareSplitParticipantResponse {
+	resp := &FareSplitParticipantResponse{
+		ID:              split.ID,
+		UserID:          split.UserID,
+		SharePercent:    split.SharePercent,
+		Status:          string(split.Status),
+		HoldAmount:      split.HoldAmount,
+		ChargedAmount:   split.ChargedAmount,
+		PaidByOrganizer: split.PaidByOrganizer,
+		RespondedAt:     split.RespondedAt,
+		CreatedAt:       split.CreatedAt,
+	}
+	if split.User != nil {
+		resp.Name = firstName(split.User.Name)
+	}
+	return resp
+}
+
+func ToFareSplitResponse(ride *models.Ride, splits []*models.RideFareSplit) *FareSplitResponse {
+	resp := &FareSplitResponse{
+		RideID:                ride.ID,
+		OrganizerID:           ride.RiderID,
+		EstimatedFare:         ride.EstimatedFare,
+		Currency:              ride.Currency,
+		OrganizerSharePercent: 100,
+		Participants:          make([]*FareSplitParticipantResponse, 0, len(splits)),
+	}
+	for _, split := range splits {
+		if split.IsOpen() || split.Status == models.FareSplitStatusCharged {
+			resp.OrganizerSharePercent -= split.SharePercent
+		}
+		resp.Participants = append(resp.Participants, ToFareSplitParticipantResponse(split))
+	}
+	resp.OrganizerSharePercent = math.Round(resp.OrganizerSharePercent*100) / 100
+	return resp
+}
+
+// Reasons a ride booking is answered with a new quote.
+const (
+	FareRequoteLockMissing  = "fare_lock_missing"
+	FareRequoteLockExpired  = "fare_lock_expired"
+	FareRequoteFareIncrease = "fare_increased"
+	FareRequoteSurgeChanged = "surge_changed"
+)
+
+// FareRequoteResponse is the data of a RIDE_FARE_CHANGED error: the fare the
+// rider was quoted cannot be honoured any more. Booking again with
+// Quote.FareLockToken accepts the new fare.
+type FareRequoteResponse struct {
+	Reason        string                           `json:"reason"`
+	PreviousFare  *float64                         `json:"previousFare,omitempty"`
+	PreviousSurge *float64                         `json:"previousSurge,omitempty"`
+	NewFare       float64                          `json:"newFare"`
+	NewSurge      float64                          `json:"newSurge"`
+	Quote         *pricingdto.FareEstimateResponse `json:"quote"`
+}
@@ -0,0 +1,22 @@
+package rides
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/services/routing"
+)
+
+func (s *service) SetRouter(router *routing.Service) {
+	s.router = router
+}
+
+// pickupETAMinutes is the driving time from the driver to the pickup, rounded
+// up to whole minutes and never less than one.
+func (s *service) pickupETAMinutes(ctx context.Context, driverLat, driverLon, pickupLat, pickupLon float64) int {
+	route := s.router.Route(ctx, driverLat, driverLon, pickupLat, pickupLon)
+	minutes := (route.DurationSeconds + 59) / 60
+	if minutes < 1 {
+		minutes = 1
+	}
+	return minutes
+}
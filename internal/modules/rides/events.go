@@ -0,0 +1,42 @@
+package rides
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/services/eventbus"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+// EventPublisher puts domain events on the event bus. It is satisfied by
+// eventbus.Bus and is optional.
+type EventPublisher interface {
+	Publish(ctx context.Context, eventType, key string, payload interface{}) error
+}
+
+func (s *service) SetEventPublisher(publisher EventPublisher) {
+	s.events = publisher
+}
+
+func (s *service) publishRideCompleted(ctx context.Context, ride *models.Ride, driverUserID string, fare, taxAmount float64) {
+	if s.events == nil {
+		return
+	}
+
+	payload := eventbus.RideCompletedPayload{
+		RideID:        ride.ID,
+		RiderID:       ride.RiderID,
+		DriverID:      driverUserID,
+		VehicleTypeID: ride.VehicleTypeID,
+		PickupLat:     ride.PickupLat,
+		PickupLon:     ride.PickupLon,
+		Fare:          fare,
+		TaxAmount:     taxAmount,
+		Currency:      ride.Currency,
+		PaymentMethod: ride.PaymentMethod,
+		CompletedAt:   ride.CompletedAt,
+	}
+	if err := s.events.Publish(ctx, eventbus.RideCompleted, ride.ID, payload); err != nil {
+		logger.Warn("failed to publish ride completed event", "error", err, "rideID", ride.ID)
+	}
+}
@@ -0,0 +1,91 @@
+package rides
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/config"
+	pricingdto "github.com/umar5678/go-backend/internal/modules/pricing/dto"
+	"github.com/umar5678/go-backend/internal/modules/rides/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+func (s *service) ConfigureFareLock(cfg config.FareLockConfig) {
+	s.fareLock = &cfg
+}
+
+// applyFareLock holds the booking to the fare the rider was quoted. quote is
+// the fare worked out now; when the rider's lock is still good and the fare
+// has not moved materially, quote is set back to the locked fare and true is
+// returned. The lock is used up either way. Otherwise the booking is refused
+// with a RIDE_FARE_CHANGED error carrying quote, locked so that its token
+// books the ride at the new fare. Without a lock config, or when locks
+// cannot be stored, the booking goes ahead at the current fare.
+func (s *service) applyFareLock(ctx context.Context, req dto.CreateRideRequest, fareReq pricingdto.FareEstimateRequest, quote *pricingdto.FareEstimateResponse) (bool, error) {
+	if s.fareLock == nil || s.fareLock.TTL <= 0 {
+		return false, nil
+	}
+
+	if req.FareLockToken == "" {
+		if !s.fareLock.Required {
+			return false, nil
+		}
+		return s.requoteFare(ctx, fareReq, dto.FareRequoteLockMissing, nil, quote)
+	}
+
+	lock := s.pricingService.ConsumeFareLock(ctx, req.FareLockToken)
+	if lock == nil {
+		return s.requoteFare(ctx, fareReq, dto.FareRequoteLockExpired, nil, quote)
+	}
+	if !lock.Matches(req.VehicleTypeID, req.PickupLat, req.PickupLon, req.DropoffLat, req.DropoffLon) {
+		return false, response.BadRequest("The fare quote is for a different trip")
+	}
+
+	// A fare that went down is simply charged; only increases are re-quoted.
+	if s.fareLock.MaxSurgeChange > 0 && quote.SurgeMultiplier-lock.SurgeMultiplier >= s.fareLock.MaxSurgeChange {
+		return s.requoteFare(ctx, fareReq, dto.FareRequoteSurgeChanged, lock, quote)
+	}
+	if lock.TotalFare > 0 && (quote.TotalFare-lock.TotalFare)/lock.TotalFare*100 > s.fareLock.MaxIncreasePercent {
+		return s.requoteFare(ctx, fareReq, dto.FareRequoteFareIncrease, lock, quote)
+	}
+
+	if quote.TotalFare > lock.TotalFare {
+		logger.Info("honouring locked fare",
+			"vehicleTypeID", req.VehicleTypeID,
+			"lockedFare", lock.TotalFare,
+			"currentFare", quote.TotalFare,
+			"lockedSurge", lock.SurgeMultiplier,
+			"currentSurge", quote.SurgeMultiplier,
+		)
+		quote.SubTotal = lock.SubTotal
+		quote.SurgeMultiplier = lock.SurgeMultiplier
+		quote.SurgeAmount = lock.SurgeAmount
+		quote.TotalFare = lock.TotalFare
+	}
+	return true, nil
+}
+
+// requoteFare locks quote and refuses the booking with it. If the lock
+// cannot be stored there is no token to book with, so the booking goes
+// ahead at the current fare instead.
+func (s *service) requoteFare(ctx context.Context, fareReq pricingdto.FareEstimateRequest, reason string, lock *pricingdto.FareLock, quote *pricingdto.FareEstimateResponse) (bool, error) {
+	s.pricingService.LockFare(ctx, fareReq, quote)
+	if quote.FareLockToken == "" {
+		return false, nil
+	}
+	return false, fareRequote(reason, lock, quote)
+}
+
+func fareRequote(reason string, lock *pricingdto.FareLock, quote *pricingdto.FareEstimateResponse) error {
+	requote := dto.FareRequoteResponse{
+		Reason:   reason,
+		NewFare:  quote.TotalFare,
+		NewSurge: quote.SurgeMultiplier,
+		Quote:    quote,
+	}
+	if lock != nil {
+		requote.PreviousFare = &lock.TotalFare
+		requote.PreviousSurge = &lock.SurgeMultiplier
+	}
+	return response.CodedError(response.CodeRideFareChanged, "").WithData(requote)
+}
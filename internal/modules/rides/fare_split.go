@@ -0,0 +1,403 @@
+package rides
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	notificationsmodule "github.com/umar5678/go-backend/internal/modules/notifications"
+	"github.com/umar5678/go-backend/internal/modules/rides/dto"
+	walletdto "github.com/umar5678/go-backend/internal/modules/wallet/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+	websocketutil "github.com/umar5678/go-backend/internal/websocket/websocketutils"
+	"gorm.io/gorm"
+)
+
+// maxFareSplitParticipants caps the co-riders sharing one ride's fare.
+const maxFareSplitParticipants = 4
+
+// fareSplitHoldSeconds matches the hold placed on the organizer's wallet
+// when the ride is requested.
+const fareSplitHoldSeconds = 1800
+
+// fareSplitStatuses are the ride statuses in which co-riders can join.
+// Scheduled rides have no hold yet, so they are split once they activate.
+var fareSplitStatuses = map[string]bool{
+	"searching": true,
+	"accepted":  true,
+	"arrived":   true,
+	"started":   true,
+}
+
+// GetFareSplit shows how the fare is shared to the organizer and to anyone
+// invited to the split.
+func (s *service) GetFareSplit(ctx context.Context, userID, rideID string) (*dto.FareSplitResponse, error) {
+	ride, err := s.repo.FindRideByID(ctx, rideID)
+	if err != nil {
+		return nil, response.NotFoundError("Ride")
+	}
+	splits, err := s.repo.ListFareSplits(ctx, ride.ID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get fare split", err)
+	}
+
+	if ride.RiderID != userID {
+		invited := false
+		for _, split := range splits {
+			if split.UserID == userID {
+				invited = true
+				break
+			}
+		}
+		if !invited {
+			return nil, response.NotFoundError("Ride")
+		}
+	}
+	return dto.ToFareSplitResponse(ride, splits), nil
+}
+
+// InviteFareSplit invites a co-rider to pay part of the fare. The organizer
+// keeps paying for the whole ride until the co-rider accepts and their
+// share is held.
+func (s *service) InviteFareSplit(ctx context.Context, riderID, rideID string, req dto.InviteFareSplitRequest) (*dto.FareSplitResponse, error) {
+	ride, err := s.loadSplitRide(ctx, rideID)
+	if err != nil {
+		return nil, err
+	}
+	if ride.RiderID != riderID {
+		return nil, response.ForbiddenError("Only the rider who booked the ride can split its fare")
+	}
+
+	invitee, err := s.repo.FindUserByPhone(ctx, req.Phone)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, response.NotFoundError("Rider with this phone number")
+		}
+		return nil, response.InternalServerError("Failed to find rider", err)
+	}
+	if invitee.ID == riderID {
+		return nil, response.BadRequest("You cannot invite yourself to split the fare")
+	}
+	if invitee.Role != models.RoleRider || invitee.Status != models.StatusActive {
+		return nil, response.BadRequest("Only active riders can be invited to split the fare")
+	}
+
+	splits, err := s.repo.ListFareSplits(ctx, ride.ID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to get fare split", err)
+	}
+	open := 0
+	shared := req.SharePercent
+	for _, split := range splits {
+		if !split.IsOpen() {
+			continue
+		}
+		if split.UserID == invitee.ID {
+			return nil, response.ConflictError("This rider is already sharing the fare")
+		}
+		open++
+		shared += split.SharePercent
+	}
+	if open >= maxFareSplitParticipants {
+		return nil, response.BadRequest(fmt.Sprintf("A fare can be split with at most %d co-riders", maxFareSplitParticipants))
+	}
+	if shared >= 100 {
+		return nil, response.BadRequest("The shares must leave part of the fare to the organizer")
+	}
+
+	split := &models.RideFareSplit{
+		RideID:       ride.ID,
+		UserID:       invitee.ID,
+		InvitedBy:    riderID,
+		SharePercent: req.SharePercent,
+		Status:       models.FareSplitStatusInvited,
+	}
+	if err := s.repo.CreateFareSplit(ctx, split); err != nil {
+		logger.Error("failed to create fare split", "error", err, "rideID", ride.ID)
+		return nil, response.InternalServerError("Failed to invite co-rider", err)
+	}
+	split.User = invitee
+
+	shareAmount := fareShare(ride.EstimatedFare, split.SharePercent)
+	if err := websocketutil.SendToUser(invitee.ID, websocket.TypeFareSplitInvitation, map[string]interface{}{
+		"rideId":         ride.ID,
+		"splitId":        split.ID,
+		"sharePercent":   split.SharePercent,
+		"estimatedShare": shareAmount,
+		"currency":       ride.Currency,
+		"pickupAddress":  ride.PickupAddress,
+		"dropoffAddress": ride.DropoffAddress,
+		"timestamp":      time.Now().UTC(),
+	}); err != nil {
+		logger.Warn("failed to send fare split invitation", "error", err, "rideID", ride.ID, "userID", invitee.ID)
+	}
+	s.publishFareSplitInvited(ride, split, shareAmount)
+
+	logger.Info("fare split invitation sent", "rideID", ride.ID, "splitID", split.ID,
+		"organizerID", riderID, "inviteeID", invitee.ID, "sharePercent", split.SharePercent)
+
+	return dto.ToFareSplitResponse(ride, append(splits, split)), nil
+}
+
+// RemoveFareSplit takes a co-rider off the split. A share already held is
+// released and the organizer pays it again.
+func (s *service) RemoveFareSplit(ctx context.Context, riderID, rideID, splitID string) (*dto.FareSplitResponse, error) {
+	ride, err := s.loadSplitRide(ctx, rideID)
+	if err != nil {
+		return nil, err
+	}
+	if ride.RiderID != riderID {
+		return nil, response.ForbiddenError("Only the rider who booked the ride can change the fare split")
+	}
+	split, err := s.repo.FindFareSplit(ctx, ride.ID, splitID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, response.NotFoundError("Fare split")
+		}
+		return nil, response.InternalServerError("Failed to get fare split", err)
+	}
+	if !split.IsOpen() {
+		return nil, response.BadRequest("This co-rider is no longer sharing the fare")
+	}
+
+	s.releaseSplitHold(ctx, split)
+	split.Status = models.FareSplitStatusRemoved
+	if err := s.repo.UpdateFareSplit(ctx, split); err != nil {
+		return nil, response.InternalServerError("Failed to remove co-rider", err)
+	}
+
+	s.notifyFareSplit(split.UserID, ride, split, "You were removed from the fare split")
+
+	logger.Info("fare split co-rider removed", "rideID", ride.ID, "splitID", split.ID, "userID", split.UserID)
+
+	return s.GetFareSplit(ctx, riderID, ride.ID)
+}
+
+// AcceptFareSplit holds the co-rider's share of the estimated fare. If the
+// hold cannot be placed the invitation stays open and the organizer keeps
+// paying for the whole ride.
+func (s *service) AcceptFareSplit(ctx context.Context, userID, rideID string) (*dto.FareSplitResponse, error) {
+	ride, split, err := s.loadFareSplitInvitation(ctx, userID, rideID)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := fareShare(ride.EstimatedFare, split.SharePercent)
+	hold, err := s.walletService.HoldFunds(ctx, userID, walletdto.HoldFundsRequest{
+		Amount:        amount,
+		Currency:      ride.Currency,
+		ReferenceType: "ride_split",
+		ReferenceID:   ride.ID,
+		HoldDuration:  fareSplitHoldSeconds,
+	})
+	if err != nil {
+		logger.Warn("failed to hold fare split share", "error", err, "rideID", ride.ID, "userID", userID, "amount", amount)
+		s.notifyFareSplit(ride.RiderID, ride, split, "Your co-rider could not pay their share; you will be charged for it")
+		return nil, err
+	}
+
+	now := time.Now()
+	split.Status = models.FareSplitStatusAccepted
+	split.HoldID = &hold.ID
+	split.HoldAmount = &amount
+	split.RespondedAt = &now
+	if err := s.repo.UpdateFareSplit(ctx, split); err != nil {
+		s.releaseSplitHold(ctx, split)
+		return nil, response.InternalServerError("Failed to accept fare split", err)
+	}
+
+	s.notifyFareSplit(ride.RiderID, ride, split, "Your co-rider accepted the fare split")
+
+	logger.Info("fare split accepted", "rideID", ride.ID, "splitID", split.ID, "userID", userID, "holdAmount", amount)
+
+	return s.GetFareSplit(ctx, userID, ride.ID)
+}
+
+// DeclineFareSplit turns down an invitation; the organizer keeps the share.
+func (s *service) DeclineFareSplit(ctx context.Context, userID, rideID string) (*dto.FareSplitResponse, error) {
+	ride, split, err := s.loadFareSplitInvitation(ctx, userID, rideID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	split.Status = models.FareSplitStatusDeclined
+	split.RespondedAt = &now
+	if err := s.repo.UpdateFareSplit(ctx, split); err != nil {
+		return nil, response.InternalServerError("Failed to decline fare split", err)
+	}
+
+	s.notifyFareSplit(ride.RiderID, ride, split, "Your co-rider declined the fare split")
+
+	logger.Info("fare split declined", "rideID", ride.ID, "splitID", split.ID, "userID", userID)
+
+	return s.GetFareSplit(ctx, userID, ride.ID)
+}
+
+// settleFareSplit captures each co-rider's share of the final fare and
+// returns what is left for the organizer. A share that cannot be captured
+// falls back to the organizer, whose hold covers the whole fare. Shares
+// already charged are not captured again if the ride is settled twice.
+func (s *service) settleFareSplit(ctx context.Context, ride *models.Ride, fare float64) float64 {
+	splits, err := s.repo.ListFareSplits(ctx, ride.ID)
+	if err != nil {
+		logger.Error("failed to load fare split, charging organizer", "error", err, "rideID", ride.ID)
+		return fare
+	}
+
+	organizerAmount := fare
+	for _, split := range splits {
+		if split.Status == models.FareSplitStatusInvited {
+			split.Status = models.FareSplitStatusExpired
+			if err := s.repo.UpdateFareSplit(ctx, split); err != nil {
+				logger.Error("failed to expire fare split invitation", "error", err, "splitID", split.ID)
+			}
+			continue
+		}
+		// A ride settled again keeps the shares already captured.
+		if split.Status == models.FareSplitStatusCharged && split.ChargedAmount != nil {
+			organizerAmount -= *split.ChargedAmount
+			continue
+		}
+		if split.Status != models.FareSplitStatusAccepted || split.HoldID == nil || split.PaidByOrganizer {
+			continue
+		}
+
+		share := fareShare(fare, split.SharePercent)
+		_, err := s.walletService.CaptureHold(ctx, split.UserID, walletdto.CaptureHoldRequest{
+			HoldID:      *split.HoldID,
+			Amount:      &share,
+			Currency:    ride.Currency,
+			Description: fmt.Sprintf("Fare share for ride %s", ride.ID),
+		})
+		if err != nil {
+			logger.Warn("failed to capture fare split share, charging organizer", "error", err,
+				"rideID", ride.ID, "splitID", split.ID, "amount", share)
+			s.releaseSplitHold(ctx, split)
+			split.PaidByOrganizer = true
+			s.notifyFareSplit(ride.RiderID, ride, split, "Your co-rider's share could not be charged; you paid it instead")
+		} else {
+			split.Status = models.FareSplitStatusCharged
+			split.ChargedAmount = &share
+			organizerAmount -= share
+			s.notifyFareSplit(split.UserID, ride, split, "Your share of the fare was charged")
+		}
+		if err := s.repo.UpdateFareSplit(ctx, split); err != nil {
+			logger.Error("failed to record fare split charge", "error", err, "splitID", split.ID)
+		}
+	}
+
+	organizerAmount = math.Round(organizerAmount*100) / 100
+	logger.Info("fare split settled", "rideID", ride.ID, "fare", fare, "organizerAmount", organizerAmount)
+	return organizerAmount
+}
+
+// releaseFareSplit frees every co-rider's hold when the ride is cancelled.
+// Cancellation fees stay with the organizer.
+func (s *service) releaseFareSplit(ctx context.Context, ride *models.Ride) {
+	splits, err := s.repo.ListFareSplits(ctx, ride.ID)
+	if err != nil {
+		logger.Error("failed to load fare split", "error", err, "rideID", ride.ID)
+		return
+	}
+	for _, split := range splits {
+		if !split.IsOpen() {
+			continue
+		}
+		s.releaseSplitHold(ctx, split)
+		split.Status = models.FareSplitStatusExpired
+		if err := s.repo.UpdateFareSplit(ctx, split); err != nil {
+			logger.Error("failed to expire fare split", "error", err, "splitID", split.ID)
+		}
+		s.notifyFareSplit(split.UserID, ride, split, "The ride was cancelled and your share was released")
+	}
+}
+
+func (s *service) releaseSplitHold(ctx context.Context, split *models.RideFareSplit) {
+	if split.HoldID == nil {
+		return
+	}
+	if err := s.walletService.ReleaseHold(ctx, split.UserID, walletdto.ReleaseHoldRequest{HoldID: *split.HoldID}); err != nil {
+		logger.Error("failed to release fare split hold", "error", err, "splitID", split.ID, "holdID", *split.HoldID)
+	}
+}
+
+// loadSplitRide loads a ride whose fare can still be split: a wallet ride
+// with the organizer's hold in place that has not ended.
+func (s *service) loadSplitRide(ctx context.Context, rideID string) (*models.Ride, error) {
+	ride, err := s.repo.FindRideByID(ctx, rideID)
+	if err != nil {
+		return nil, response.NotFoundError("Ride")
+	}
+	if !fareSplitStatuses[ride.Status] {
+		return nil, response.BadRequest(fmt.Sprintf("The fare cannot be split while the ride is %s", ride.Status))
+	}
+	if ride.PaymentMethod == models.RidePaymentCash || ride.WalletHoldID == nil {
+		return nil, response.BadRequest("Only rides paid from the wallet can be split")
+	}
+	return ride, nil
+}
+
+func (s *service) loadFareSplitInvitation(ctx context.Context, userID, rideID string) (*models.Ride, *models.RideFareSplit, error) {
+	ride, err := s.loadSplitRide(ctx, rideID)
+	if err != nil {
+		return nil, nil, err
+	}
+	split, err := s.repo.FindOpenFareSplitForUser(ctx, ride.ID, userID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil, response.NotFoundError("Fare split invitation")
+		}
+		return nil, nil, response.InternalServerError("Failed to get fare split", err)
+	}
+	if split.Status != models.FareSplitStatusInvited {
+		return nil, nil, response.BadRequest("This invitation has already been answered")
+	}
+	return ride, split, nil
+}
+
+func (s *service) notifyFareSplit(userID string, ride *models.Ride, split *models.RideFareSplit, message string) {
+	if err := websocketutil.SendToUser(userID, websocket.TypeFareSplitUpdated, map[string]interface{}{
+		"rideId":          ride.ID,
+		"splitId":         split.ID,
+		"userId":          split.UserID,
+		"status":          split.Status,
+		"sharePercent":    split.SharePercent,
+		"chargedAmount":   split.ChargedAmount,
+		"paidByOrganizer": split.PaidByOrganizer,
+		"message":         message,
+		"timestamp":       time.Now().UTC(),
+	}); err != nil {
+		logger.Warn("failed to send fare split update", "error", err, "rideID", ride.ID, "userID", userID)
+	}
+}
+
+// publishFareSplitInvited sends the invitation as a push notification too,
+// for co-riders who do not have the app open.
+func (s *service) publishFareSplitInvited(ride *models.Ride, split *models.RideFareSplit, shareAmount float64) {
+	if s.eventProducer == nil {
+		return
+	}
+	payload := map[string]interface{}{
+		"ride_id":       ride.ID,
+		"split_id":      split.ID,
+		"user_id":       split.UserID,
+		"organizer_id":  ride.RiderID,
+		"share_percent": split.SharePercent,
+		"share_amount":  shareAmount,
+		"currency":      ride.Currency,
+	}
+	go func() {
+		if err := s.eventProducer.PublishEventWithKey(context.Background(), notificationsmodule.EventRideFareSplitInvited, ride.ID, payload); err != nil {
+			logger.Error("failed to publish fare split invitation", "error", err, "rideID", ride.ID)
+		}
+	}()
+}
+
+func fareShare(fare, percent float64) float64 {
+	return math.Round(fare*percent) / 100
+}
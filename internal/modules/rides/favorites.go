@@ -0,0 +1,34 @@
+package rides
+
+import (
+	"context"
+	"sort"
+
+	trackingdto "github.com/umar5678/go-backend/internal/modules/tracking/dto"
+)
+
+// FavoriteDrivers knows which drivers a rider has marked as favorites, by
+// driver profile ID. Without one drivers are contacted nearest first.
+type FavoriteDrivers interface {
+	FavoriteDriverProfileIDs(ctx context.Context, riderID string) map[string]bool
+}
+
+func (s *service) SetFavoriteDrivers(favorites FavoriteDrivers) {
+	s.favoriteDrivers = favorites
+}
+
+// preferFavoriteDrivers moves the rider's favorite drivers to the front of the
+// nearby list, keeping the distance order within each group, so they are
+// among the first asked to take the ride.
+func (s *service) preferFavoriteDrivers(ctx context.Context, riderID string, drivers []trackingdto.DriverLocationResponse) {
+	if s.favoriteDrivers == nil || len(drivers) < 2 {
+		return
+	}
+	favorites := s.favoriteDrivers.FavoriteDriverProfileIDs(ctx, riderID)
+	if len(favorites) == 0 {
+		return
+	}
+	sort.SliceStable(drivers, func(i, j int) bool {
+		return favorites[drivers[i].DriverID] && !favorites[drivers[j].DriverID]
+	})
+}
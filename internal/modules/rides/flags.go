@@ -0,0 +1,29 @@
+package rides
+
+import "context"
+
+// flagBatchMatching routes new ride requests through the batching service
+// before sequential matching. It supports a percentage rollout by rider.
+const flagBatchMatching = "rides.batch_matching"
+
+// FeatureFlags reads runtime settings. It is satisfied by
+// featureflags.Service and is optional.
+type FeatureFlags interface {
+	BoolFor(ctx context.Context, key, subject string, def bool) bool
+	Float(ctx context.Context, key string, def float64) float64
+}
+
+func (s *service) SetFeatureFlags(flags FeatureFlags) {
+	s.flags = flags
+}
+
+// batchMatching reports whether a rider's ride is matched in batches.
+func (s *service) batchMatching(ctx context.Context, riderID string) bool {
+	if s.batchingService == nil {
+		return false
+	}
+	if s.flags == nil {
+		return true
+	}
+	return s.flags.BoolFor(ctx, flagBatchMatching, riderID, true)
+}
@@ -3,6 +3,7 @@ package rides
 import (
 	"context"
 	"encoding/json"
+	"net/http"
 	"sync"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/gorilla/websocket"
 	"github.com/umar5678/go-backend/internal/modules/messages"
 	"github.com/umar5678/go-backend/internal/modules/rides/dto"
+	sosdto "github.com/umar5678/go-backend/internal/modules/sos/dto"
 	"github.com/umar5678/go-backend/internal/utils/logger"
 	"github.com/umar5678/go-backend/internal/utils/response"
 )
@@ -30,6 +32,7 @@ func NewHandler(service Service) *Handler {
 // @Produce json
 // @Param request body dto.CreateRideRequest true "Ride request data"
 // @Success 201 {object} response.Response{data=dto.RideResponse}
+// @Failure 409 {object} response.Response{data=dto.FareRequoteResponse} "Fare changed; book again with the new quote's fareLockToken"
 // @Router /rides [post]
 func (h *Handler) CreateRide(c *gin.Context) {
 	userID, _ := c.Get("userID")
@@ -89,6 +92,30 @@ func (h *Handler) GetRide(c *gin.Context) {
 	response.Success(c, ride, "Ride retrieved successfully")
 }
 
+// GetFareBreakdown godoc
+// @Summary Get ride fare breakdown
+// @Description Platform fee, driver share, taxes and surcharges from the ride's persisted pricing snapshot
+// @Tags rides
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Ride ID"
+// @Success 200 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /rides/{id}/fare-breakdown [get]
+func (h *Handler) GetFareBreakdown(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	role, _ := c.Get("role")
+
+	breakdown, err := h.service.GetFareBreakdown(c.Request.Context(), userID.(string), c.Param("id"), role == "admin")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, breakdown, "Fare breakdown retrieved successfully")
+}
+
 // ListRides godoc
 // @Summary List user's rides
 // @Tags rides
@@ -194,6 +221,36 @@ func (h *Handler) MarkArrived(c *gin.Context) {
 	response.Success(c, ride, "Marked as arrived")
 }
 
+// ReportRiderNoShow godoc
+// @Summary Report that the rider did not show up (Driver)
+// @Description Cancels a ride the driver has waited at the pickup for past the no-show time, charging the rider the no-show fee.
+// @Tags rides
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Ride ID"
+// @Param request body dto.ReportNoShowRequest true "No-show evidence"
+// @Success 200 {object} response.Response{data=dto.NoShowReportResponse}
+// @Router /rides/{id}/no-show [post]
+func (h *Handler) ReportRiderNoShow(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	rideID := c.Param("id")
+
+	var req dto.ReportNoShowRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	report, err := h.service.ReportRiderNoShow(c.Request.Context(), userID.(string), rideID, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, report, "Rider no-show reported")
+}
+
 // StartRide godoc
 // @Summary Start the ride (Driver)
 // @Tags rides
@@ -286,46 +343,279 @@ func (h *Handler) CancelRide(c *gin.Context) {
 }
 
 // TriggerSOS godoc
-// @Summary Trigger SOS alert during active ride (Rider)
+// @Summary Trigger SOS during an active ride (Rider or Driver)
+// @Description Opens a safety incident with both parties' last known locations and alerts the safety team. /rides/{id}/emergency is kept as an alias.
 // @Tags rides
 // @Security BearerAuth
 // @Accept json
 // @Produce json
 // @Param id path string true "Ride ID"
-// @Param request body map[string]interface{} true "Location data with latitude and longitude"
-// @Success 200 {object} response.Response
-// @Router /rides/{id}/emergency [post]
+// @Param request body sosdto.TriggerRideSOSRequest true "Current location"
+// @Success 200 {object} response.Response{data=sosdto.SOSAlertResponse}
+// @Router /rides/{id}/sos [post]
 func (h *Handler) TriggerSOS(c *gin.Context) {
 	userID, _ := c.Get("userID")
-	rideID := c.Param("id")
 
-	var req map[string]interface{}
+	var req sosdto.TriggerRideSOSRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.Error(response.BadRequest("Invalid request body"))
 		return
 	}
 
-	var latitude, longitude float64
-	if lat, ok := req["latitude"].(float64); ok {
-		latitude = lat
-	} else {
-		c.Error(response.BadRequest("Latitude is required"))
+	alert, err := h.service.TriggerSOS(c.Request.Context(), userID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	if lon, ok := req["longitude"].(float64); ok {
-		longitude = lon
-	} else {
-		c.Error(response.BadRequest("Longitude is required"))
+	response.Success(c, alert, "SOS alert triggered - Help is on the way")
+}
+
+// ShareTrip godoc
+// @Summary Share a live trip (Rider)
+// @Description Returns a signed, expiring link a friend can open without an account to follow the trip
+// @Tags rides
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Ride ID"
+// @Success 200 {object} response.Response{data=dto.TripShareLinkResponse}
+// @Failure 400 {object} response.Response
+// @Failure 403 {object} response.Response
+// @Failure 503 {object} response.Response
+// @Router /rides/{id}/share [post]
+func (h *Handler) ShareTrip(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	link, err := h.service.ShareTrip(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	if err := h.service.TriggerSOS(c.Request.Context(), userID.(string), rideID, latitude, longitude); err != nil {
+	response.Success(c, link, "Trip share link created")
+}
+
+// GetFareSplit godoc
+// @Summary Get how a ride's fare is split
+// @Description Available to the rider who booked the ride and to co-riders invited to the split
+// @Tags rides
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Ride ID"
+// @Success 200 {object} response.Response{data=dto.FareSplitResponse}
+// @Failure 404 {object} response.Response
+// @Router /rides/{id}/split [get]
+func (h *Handler) GetFareSplit(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	split, err := h.service.GetFareSplit(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
 		c.Error(err)
 		return
 	}
 
-	response.Success(c, nil, "SOS alert triggered - Help is on the way")
+	response.Success(c, split, "Fare split retrieved")
+}
+
+// InviteFareSplit godoc
+// @Summary Invite a co-rider to split the fare (Rider)
+// @Description The co-rider is found by phone number and pays sharePercent of the fare once they accept
+// @Tags rides
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Ride ID"
+// @Param request body dto.InviteFareSplitRequest true "Co-rider and share"
+// @Success 200 {object} response.Response{data=dto.FareSplitResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /rides/{id}/split [post]
+func (h *Handler) InviteFareSplit(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req dto.InviteFareSplitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	split, err := h.service.InviteFareSplit(c.Request.Context(), userID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, split, "Co-rider invited to split the fare")
+}
+
+// RemoveFareSplit godoc
+// @Summary Remove a co-rider from the fare split (Rider)
+// @Tags rides
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Ride ID"
+// @Param splitId path string true "Fare split ID"
+// @Success 200 {object} response.Response{data=dto.FareSplitResponse}
+// @Failure 404 {object} response.Response
+// @Router /rides/{id}/split/{splitId} [delete]
+func (h *Handler) RemoveFareSplit(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	split, err := h.service.RemoveFareSplit(c.Request.Context(), userID.(string), c.Param("id"), c.Param("splitId"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, split, "Co-rider removed from the fare split")
+}
+
+// AcceptFareSplit godoc
+// @Summary Accept an invitation to split a ride's fare
+// @Description Holds the co-rider's share of the estimated fare in their wallet
+// @Tags rides
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Ride ID"
+// @Success 200 {object} response.Response{data=dto.FareSplitResponse}
+// @Failure 400 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /rides/{id}/split/accept [post]
+func (h *Handler) AcceptFareSplit(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	split, err := h.service.AcceptFareSplit(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, split, "Fare split accepted")
+}
+
+// DeclineFareSplit godoc
+// @Summary Decline an invitation to split a ride's fare
+// @Tags rides
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Ride ID"
+// @Success 200 {object} response.Response{data=dto.FareSplitResponse}
+// @Failure 404 {object} response.Response
+// @Router /rides/{id}/split/decline [post]
+func (h *Handler) DeclineFareSplit(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	split, err := h.service.DeclineFareSplit(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, split, "Fare split declined")
+}
+
+// GetSharedTrip godoc
+// @Summary Follow a shared trip
+// @Description Returns the trip status, driver and vehicle, and the driver's latest location for a shared link. Poll every updateIntervalSeconds, or use the live endpoint.
+// @Tags rides
+// @Produce json
+// @Param token path string true "Share token"
+// @Success 200 {object} response.Response{data=dto.SharedTripResponse}
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /trips/shared/{token} [get]
+func (h *Handler) GetSharedTrip(c *gin.Context) {
+	trip, err := h.service.GetSharedTrip(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, trip, "Shared trip retrieved successfully")
+}
+
+var sharedTripUpgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	// Shared links are opened from any page, including the marketing site.
+	CheckOrigin: func(r *http.Request) bool {
+		return true
+	},
+}
+
+// StreamSharedTrip godoc
+// @Summary Follow a shared trip live
+// @Description Upgrades to a WebSocket that pushes a shared_trip_update message with the shared trip every update interval, and closes once the trip ends or the link expires
+// @Tags rides
+// @Param token path string true "Share token"
+// @Success 101 {string} string "Switching Protocols"
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /trips/shared/{token}/live [get]
+func (h *Handler) StreamSharedTrip(c *gin.Context) {
+	token := c.Param("token")
+
+	// Bad links are rejected before upgrading so clients get a normal error.
+	trip, err := h.service.GetSharedTrip(c.Request.Context(), token)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	conn, err := sharedTripUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		logger.Error("shared trip websocket upgrade failed", "error", err, "rideID", trip.RideID)
+		return
+	}
+	defer conn.Close()
+
+	// Viewers only listen; reading is just how a closed connection is noticed.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	interval := time.Duration(trip.UpdateIntervalSeconds) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+		if err := conn.WriteJSON(gin.H{"type": "shared_trip_update", "data": trip}); err != nil {
+			return
+		}
+		if !trip.IsActive {
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseNormalClosure, "trip ended"),
+				time.Now().Add(time.Second))
+			return
+		}
+
+		select {
+		case <-closed:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		trip, err = h.service.GetSharedTrip(ctx, token)
+		cancel()
+		if err != nil {
+			conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "trip link is no longer valid"),
+				time.Now().Add(time.Second))
+			return
+		}
+	}
 }
 
 // GetAvailableCars godoc
@@ -574,3 +864,138 @@ func (rm *RideMessageManager) HandleConnection(conn *websocket.Conn, rideID, use
 		conn.SetReadDeadline(time.Now().Add(60 * time.Second))
 	}
 }
+
+// GetDriverCashSummary godoc
+// @Summary Cash collected and commission owed (Driver)
+// @Description Totals for completed cash rides in the period plus the driver's wallet position against their negative-balance limit
+// @Tags rides
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "Start date (YYYY-MM-DD)"
+// @Param to query string false "End date (YYYY-MM-DD)"
+// @Success 200 {object} response.Response{data=dto.DriverCashSummaryResponse}
+// @Router /rides/driver/cash-summary [get]
+func (h *Handler) GetDriverCashSummary(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req dto.DriverCashReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+
+	summary, err := h.service.GetDriverCashSummary(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, summary, "Cash summary retrieved successfully")
+}
+
+// ListDriverCashRides godoc
+// @Summary List completed cash rides (Driver)
+// @Tags rides
+// @Security BearerAuth
+// @Produce json
+// @Param from query string false "Start date (YYYY-MM-DD)"
+// @Param to query string false "End date (YYYY-MM-DD)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Response{data=[]dto.CashRideResponse}
+// @Router /rides/driver/cash-rides [get]
+func (h *Handler) ListDriverCashRides(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req dto.DriverCashReportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+	req.SetDefaults()
+
+	rides, total, err := h.service.ListDriverCashRides(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pagination := response.NewPaginationMeta(total, req.Page, req.Limit)
+	response.Paginated(c, rides, pagination, "Cash rides retrieved successfully")
+}
+
+// ListTripVerifications godoc
+// @Summary List ride trip verifications (admin)
+// @Description Completed rides whose reported distance or duration was checked against tracking data. Defaults to flagged rides awaiting review.
+// @Tags rides
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "verified, flagged, insufficient_data or reviewed (default flagged)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Response{data=[]dto.TripVerificationResponse}
+// @Router /rides/admin/trip-verifications [get]
+func (h *Handler) ListTripVerifications(c *gin.Context) {
+	var req dto.ListTripVerificationsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+	req.SetDefaults()
+
+	verifications, total, err := h.service.ListTripVerifications(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pagination := response.NewPaginationMeta(total, req.Page, req.Limit)
+	response.Paginated(c, verifications, pagination, "Trip verifications retrieved successfully")
+}
+
+// ReviewTripVerification godoc
+// @Summary Mark a flagged ride as reviewed (admin)
+// @Tags rides
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Trip verification ID"
+// @Param request body dto.ReviewTripVerificationRequest true "Review note"
+// @Success 200 {object} response.Response{data=dto.TripVerificationResponse}
+// @Router /rides/admin/trip-verifications/{id}/review [post]
+func (h *Handler) ReviewTripVerification(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	var req dto.ReviewTripVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest(err.Error()))
+		return
+	}
+
+	verification, err := h.service.ReviewTripVerification(c.Request.Context(), adminID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, verification, "Trip verification reviewed successfully")
+}
+
+// GetDriverDispatchScore godoc
+// @Summary Show how a driver ranks in ride matching (admin)
+// @Description The driver's acceptance rate, cancellation rate and rating, the dispatch weights set through the rides.dispatch.* feature flags, and the resulting score.
+// @Tags rides
+// @Security BearerAuth
+// @Produce json
+// @Param driverId path string true "Driver profile ID"
+// @Success 200 {object} response.Response{data=dto.DriverDispatchScoreResponse}
+// @Router /rides/admin/drivers/{driverId}/dispatch-score [get]
+func (h *Handler) GetDriverDispatchScore(c *gin.Context) {
+	score, err := h.service.GetDriverDispatchScore(c.Request.Context(), c.Param("driverId"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, score, "Driver dispatch score retrieved successfully")
+}
@@ -0,0 +1,64 @@
+package rides
+
+import (
+	"context"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	pricingdto "github.com/umar5678/go-backend/internal/modules/pricing/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+// RideInsurer issues and closes per-trip cover. It is optional; rides run
+// uninsured when no insurer is set.
+type RideInsurer interface {
+	AttachPolicy(ctx context.Context, ride *models.Ride) (*models.RideInsurancePolicy, error)
+	EndCoverage(ctx context.Context, rideID string, endedAt time.Time) error
+	PolicyReference(ctx context.Context, rideID string) (*pricingdto.InsuranceReference, error)
+}
+
+func (s *service) SetInsurer(insurer RideInsurer) {
+	s.insurer = insurer
+}
+
+// attachInsurance issues the policy off the request path so a slow insurer
+// never blocks the trip from starting. Missed policies show up in the admin
+// uncovered-rides report and can be attached from there.
+func (s *service) attachInsurance(ride *models.Ride) {
+	if s.insurer == nil {
+		return
+	}
+
+	rideCopy := *ride
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if _, err := s.insurer.AttachPolicy(ctx, &rideCopy); err != nil {
+			logger.Warn("failed to attach ride insurance", "error", err, "rideID", rideCopy.ID)
+		}
+	}()
+}
+
+func (s *service) endInsuranceCoverage(ctx context.Context, rideID string, endedAt time.Time) {
+	if s.insurer == nil {
+		return
+	}
+
+	if err := s.insurer.EndCoverage(ctx, rideID, endedAt); err != nil {
+		logger.Warn("failed to end ride insurance coverage", "error", err, "rideID", rideID)
+	}
+}
+
+func (s *service) insuranceReference(ctx context.Context, rideID string) *pricingdto.InsuranceReference {
+	if s.insurer == nil {
+		return nil
+	}
+
+	ref, err := s.insurer.PolicyReference(ctx, rideID)
+	if err != nil {
+		logger.Warn("failed to load ride insurance reference", "error", err, "rideID", rideID)
+		return nil
+	}
+	return ref
+}
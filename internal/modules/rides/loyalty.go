@@ -0,0 +1,48 @@
+package rides
+
+import (
+	"context"
+	"math"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+// LoyaltyPerks knows what a rider's loyalty tier unlocks. Without one no
+// rider gets a booking fee discount or priority matching.
+type LoyaltyPerks interface {
+	Perks(ctx context.Context, userID string) models.LoyaltyPerks
+}
+
+func (s *service) SetLoyalty(loyalty LoyaltyPerks) {
+	s.loyalty = loyalty
+}
+
+func (s *service) loyaltyPerks(ctx context.Context, riderID string) models.LoyaltyPerks {
+	if s.loyalty == nil {
+		return models.LoyaltyPerks{}
+	}
+	return s.loyalty.Perks(ctx, riderID)
+}
+
+// prioritizeDispatch offers the rides of riders with priority matching to
+// twice as many drivers per wave, so they are matched sooner. Sequential
+// dispatch becomes batches of two.
+func prioritizeDispatch(settings *models.DispatchSettings, perks models.LoyaltyPerks) {
+	if !perks.PriorityMatching || settings.Mode == models.DispatchModeBroadcast {
+		return
+	}
+	if settings.Mode == models.DispatchModeSequential {
+		settings.Mode = models.DispatchModeBatch
+		settings.BatchSize = 1
+	}
+	settings.BatchSize *= 2
+}
+
+// loyaltyDiscount is the part of the booking fee the rider's tier takes off.
+// The platform funds it, so the driver's share is unchanged.
+func loyaltyDiscount(bookingFee float64, perks models.LoyaltyPerks) float64 {
+	if bookingFee <= 0 || perks.BookingFeeDiscount <= 0 {
+		return 0
+	}
+	return math.Round(bookingFee*min(perks.BookingFeeDiscount, 100)) / 100
+}
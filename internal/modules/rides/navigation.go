@@ -0,0 +1,52 @@
+package rides
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/rides/dto"
+	"github.com/umar5678/go-backend/internal/services/routing"
+	"github.com/umar5678/go-backend/internal/utils/location"
+)
+
+// rideRoute is the pickup to dropoff route, so driver apps can draw the trip
+// without calling a mapping API themselves.
+func (s *service) rideRoute(ctx context.Context, ride *models.Ride) *dto.RideRouteDTO {
+	route := s.router.Route(ctx, ride.PickupLat, ride.PickupLon, ride.DropoffLat, ride.DropoffLon)
+	return &dto.RideRouteDTO{
+		Polyline:        route.Polyline,
+		DistanceKm:      route.DistanceKm,
+		DurationSeconds: route.DurationSeconds,
+		Source:          route.Source,
+	}
+}
+
+// rideNavigation builds navigation deep links for the trip. driverLocation
+// may be nil, in which case the pickup leg starts wherever the device is.
+func rideNavigation(ride *models.Ride, driverLocation *location.Point) *dto.NavigationDTO {
+	pickup := location.Point{Latitude: ride.PickupLat, Longitude: ride.PickupLon}
+	dropoff := location.Point{Latitude: ride.DropoffLat, Longitude: ride.DropoffLon}
+	return &dto.NavigationDTO{
+		ToPickup:  toNavigationLinksDTO(routing.NavigationTo(driverLocation, pickup)),
+		ToDropoff: toNavigationLinksDTO(routing.NavigationTo(&pickup, dropoff)),
+	}
+}
+
+func toNavigationLinksDTO(links routing.NavigationLinks) dto.NavigationLinksDTO {
+	return dto.NavigationLinksDTO{
+		GoogleMaps: links.GoogleMaps,
+		Waze:       links.Waze,
+		AppleMaps:  links.AppleMaps,
+	}
+}
+
+// withRoute adds the route and navigation links to a ride response.
+func (s *service) withRoute(ctx context.Context, resp *dto.RideResponse, ride *models.Ride) *dto.RideResponse {
+	var driverLocation *location.Point
+	if resp.DriverLocation != nil {
+		driverLocation = &location.Point{Latitude: resp.DriverLocation.Latitude, Longitude: resp.DriverLocation.Longitude}
+	}
+	resp.Route = s.rideRoute(ctx, ride)
+	resp.Navigation = rideNavigation(ride, driverLocation)
+	return resp
+}
@@ -0,0 +1,79 @@
+package rides
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/cancellation"
+	"github.com/umar5678/go-backend/internal/modules/rides/dto"
+	"github.com/umar5678/go-backend/internal/utils/location"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+// ReportRiderNoShow lets the driver close a ride the rider never came out
+// for. It is accepted once the driver has waited at the pickup for the
+// cancellation policy's rider no-show time; the ride is then cancelled as a
+// rider no-show, so the no-show fee is captured from the rider's hold and
+// passed to the driver, and the driver's evidence is kept with the outcome.
+func (s *service) ReportRiderNoShow(ctx context.Context, driverID, rideID string, req dto.ReportNoShowRequest) (*dto.NoShowReportResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	ride, err := s.repo.FindRideByID(ctx, rideID)
+	if err != nil {
+		return nil, response.NotFoundError("Ride")
+	}
+	if ride.DriverID == nil || *ride.DriverID != driverID {
+		return nil, response.ForbiddenError("Only the ride's driver can report a rider no-show")
+	}
+	if ride.Status != "arrived" || ride.ArrivedAt == nil {
+		return nil, response.BadRequest("A rider no-show can only be reported while waiting at the pickup")
+	}
+
+	now := time.Now()
+	outcome := s.cancellationOutcome(ctx, ride, false, now)
+	if outcome.Rule != cancellation.RuleRiderNoShow {
+		return nil, response.BadRequest("The rider still has time to reach the pickup")
+	}
+
+	report := &models.NoShowReport{
+		Product:       models.CancellationProductRides,
+		ReferenceID:   ride.ID,
+		ReportedBy:    driverID,
+		AbsentParty:   models.NoShowPartyRider,
+		Note:          req.Note,
+		PhotoURLs:     req.PhotoURLs,
+		Latitude:      req.Latitude,
+		Longitude:     req.Longitude,
+		WaitedMinutes: int(now.Sub(*ride.ArrivedAt).Minutes()),
+		CustomerFee:   outcome.CustomerFee,
+		PolicyName:    outcome.PolicyName,
+	}
+	if req.Latitude != nil {
+		distance := location.HaversineDistance(*req.Latitude, *req.Longitude, ride.PickupLat, ride.PickupLon) * 1000
+		distance = math.Round(distance*100) / 100
+		report.DistanceMeters = &distance
+	}
+
+	if err := s.CancelRide(ctx, driverID, ride.ID, dto.CancelRideRequest{Reason: cancellation.RuleRiderNoShow}); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateNoShowReport(ctx, report); err != nil {
+		logger.Error("failed to record rider no-show", "error", err, "rideID", ride.ID)
+	}
+
+	logger.Info("rider no-show reported",
+		"rideID", ride.ID,
+		"driverUserID", driverID,
+		"waitedMinutes", report.WaitedMinutes,
+		"riderFee", report.CustomerFee,
+		"cancellationPolicy", report.PolicyName,
+	)
+
+	return dto.ToNoShowReportResponse(report), nil
+}
@@ -0,0 +1,42 @@
+package rides
+
+import (
+	"context"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+// RideReceiptIssuer issues the rider's receipt when a trip completes. It is
+// optional; without one riders only get the completion event.
+type RideReceiptIssuer interface {
+	IssueReceipt(ctx context.Context, rideID string) (*models.RideReceipt, error)
+}
+
+func (s *service) SetReceiptIssuer(issuer RideReceiptIssuer) {
+	s.receiptIssuer = issuer
+}
+
+// issueReceipt runs off the request path; a missed receipt is generated the
+// first time the rider asks for it.
+func (s *service) issueReceipt(rideID string) {
+	if s.receiptIssuer == nil {
+		return
+	}
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.Error("panic in receipt goroutine", "error", r, "rideID", rideID)
+			}
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if _, err := s.receiptIssuer.IssueReceipt(ctx, rideID); err != nil {
+			logger.Warn("failed to issue ride receipt", "error", err, "rideID", rideID)
+		}
+	}()
+}
@@ -6,14 +6,17 @@ import (
 	"time"
 
 	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/archive"
 	"gorm.io/gorm"
 )
 
 type Repository interface {
 	CreateRide(ctx context.Context, ride *models.Ride) error
 	FindRideByID(ctx context.Context, id string) (*models.Ride, error)
+	FindArchivedRideByID(ctx context.Context, id string) (*models.Ride, error)
 	UpdateRide(ctx context.Context, ride *models.Ride) error
 	UpdateRideStatus(ctx context.Context, rideID, status string) error
+	MarkRideArrived(ctx context.Context, rideID, method string) (bool, error)
 	ListRides(ctx context.Context, userID string, filters map[string]interface{}, page, limit int) ([]*models.Ride, int64, error)
 
 	CreateRideRequest(ctx context.Context, request *models.RideRequest) error
@@ -31,6 +34,40 @@ type Repository interface {
 
 	GetRiderStats(ctx context.Context, riderID string) (totalRides int, totalSpent float64, err error)
 	GetDriverStats(ctx context.Context, driverID string) (totalTrips int, totalEarnings float64, err error)
+
+	CreateFareSnapshot(ctx context.Context, snapshot *models.RideFareSnapshot) error
+	FindFareSnapshotByRideID(ctx context.Context, rideID string) (*models.RideFareSnapshot, error)
+
+	GetDriverCashTotals(ctx context.Context, driverUserID string, from, to time.Time) (*DriverCashTotals, error)
+	ListDriverCashRides(ctx context.Context, driverUserID string, from, to time.Time, page, limit int) ([]*models.Ride, int64, error)
+	FindDriverWallet(ctx context.Context, driverUserID string) (*models.Wallet, error)
+	UpdateCashSettlement(ctx context.Context, rideID string, collected, commission float64, txnID *string) error
+	SetPickupETA(ctx context.Context, rideID string, minutes int) error
+
+	FindRideTrackPoints(ctx context.Context, rideID string, since time.Time) ([]*models.RideTrackPoint, error)
+	CreateTripVerification(ctx context.Context, verification *models.RideTripVerification) error
+	FindTripVerificationByID(ctx context.Context, id string) (*models.RideTripVerification, error)
+	ListTripVerifications(ctx context.Context, status string, page, limit int) ([]*models.RideTripVerification, int64, error)
+	UpdateTripVerification(ctx context.Context, verification *models.RideTripVerification) error
+
+	FindDriverDispatchStats(ctx context.Context, driverIDs []string) (map[string]*models.DriverProfile, error)
+	RefreshDriverDispatchStats(ctx context.Context, driverID string, window int) error
+
+	CreateNoShowReport(ctx context.Context, report *models.NoShowReport) error
+
+	FindUserByPhone(ctx context.Context, phone string) (*models.User, error)
+	CreateFareSplit(ctx context.Context, split *models.RideFareSplit) error
+	UpdateFareSplit(ctx context.Context, split *models.RideFareSplit) error
+	FindFareSplit(ctx context.Context, rideID, splitID string) (*models.RideFareSplit, error)
+	FindOpenFareSplitForUser(ctx context.Context, rideID, userID string) (*models.RideFareSplit, error)
+	ListFareSplits(ctx context.Context, rideID string) ([]*models.RideFareSplit, error)
+}
+
+// DriverCashTotals aggregates completed cash rides for a driver over a period.
+type DriverCashTotals struct {
+	Rides         int
+	CashCollected float64
+	Commission    float64
 }
 
 type repository struct {
@@ -51,19 +88,22 @@ func (r *repository) CreateRide(ctx context.Context, ride *models.Ride) error {
 			pickup_location, pickup_lat, pickup_lon, pickup_address,
 			dropoff_location, dropoff_lat, dropoff_lon, dropoff_address,
 			estimated_distance, estimated_duration, estimated_fare,
-			surge_multiplier, wallet_hold_id, rider_notes, requested_at, scheduled_at
+			surge_multiplier, wallet_hold_id, rider_notes, requested_at, scheduled_at,
+			payment_method
 		) VALUES (
 			?, ?, ?, ?,
 			ST_GeomFromText(?, 4326), ?, ?, ?,
 			ST_GeomFromText(?, 4326), ?, ?, ?,
 			?, ?, ?,
-			?, ?, ?, ?, ?
+			?, ?, ?, ?, ?,
+			?
 		)
 	`, ride.ID, ride.RiderID, ride.VehicleTypeID, ride.Status,
 		pickupPoint, ride.PickupLat, ride.PickupLon, ride.PickupAddress,
 		dropoffPoint, ride.DropoffLat, ride.DropoffLon, ride.DropoffAddress,
 		ride.EstimatedDistance, ride.EstimatedDuration, ride.EstimatedFare,
 		ride.SurgeMultiplier, ride.WalletHoldID, ride.RiderNotes, ride.RequestedAt, ride.ScheduledAt,
+		ride.PaymentMethod,
 	).Error
 }
 
@@ -79,6 +119,21 @@ func (r *repository) FindRideByID(ctx context.Context, id string) (*models.Ride,
 	return &ride, err
 }
 
+// FindArchivedRideByID looks the ride up in the archive only. It backs
+// read-only history views for rides the archive job has moved out.
+func (r *repository) FindArchivedRideByID(ctx context.Context, id string) (*models.Ride, error) {
+	var ride models.Ride
+	err := r.db.WithContext(ctx).
+		Table(archive.RidesArchiveTable).
+		Preload("Rider").
+		Preload("Driver").
+		Preload("DriverProfile.Vehicle").
+		Preload("VehicleType").
+		Where("id = ?", id).
+		First(&ride).Error
+	return &ride, err
+}
+
 func (r *repository) UpdateRide(ctx context.Context, ride *models.Ride) error {
 	return r.db.WithContext(ctx).Save(ride).Error
 }
@@ -108,11 +163,75 @@ func (r *repository) UpdateRideStatus(ctx context.Context, rideID, status string
 		Updates(updates).Error
 }
 
+// MarkRideArrived moves an accepted ride to arrived. It reports false if the
+// ride was no longer accepted, e.g. because the driver already marked it.
+func (r *repository) MarkRideArrived(ctx context.Context, rideID, method string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.Ride{}).
+		Where("id = ? AND status = ?", rideID, "accepted").
+		Updates(map[string]interface{}{
+			"status":         "arrived",
+			"arrived_at":     time.Now(),
+			"arrival_method": method,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *repository) CreateNoShowReport(ctx context.Context, report *models.NoShowReport) error {
+	return r.db.WithContext(ctx).Create(report).Error
+}
+
+func (r *repository) FindUserByPhone(ctx context.Context, phone string) (*models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).Where("phone = ?", phone).First(&user).Error
+	return &user, err
+}
+
+func (r *repository) CreateFareSplit(ctx context.Context, split *models.RideFareSplit) error {
+	return r.db.WithContext(ctx).Create(split).Error
+}
+
+func (r *repository) UpdateFareSplit(ctx context.Context, split *models.RideFareSplit) error {
+	return r.db.WithContext(ctx).
+		Model(split).
+		Select("status", "hold_id", "hold_amount", "charged_amount", "paid_by_organizer", "responded_at", "updated_at").
+		Updates(split).Error
+}
+
+func (r *repository) FindFareSplit(ctx context.Context, rideID, splitID string) (*models.RideFareSplit, error) {
+	var split models.RideFareSplit
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("id = ? AND ride_id = ?", splitID, rideID).
+		First(&split).Error
+	return &split, err
+}
+
+func (r *repository) FindOpenFareSplitForUser(ctx context.Context, rideID, userID string) (*models.RideFareSplit, error) {
+	var split models.RideFareSplit
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("ride_id = ? AND user_id = ? AND status IN ?", rideID, userID,
+			[]models.FareSplitStatus{models.FareSplitStatusInvited, models.FareSplitStatusAccepted}).
+		First(&split).Error
+	return &split, err
+}
+
+func (r *repository) ListFareSplits(ctx context.Context, rideID string) ([]*models.RideFareSplit, error) {
+	var splits []*models.RideFareSplit
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("ride_id = ?", rideID).
+		Order("created_at ASC").
+		Find(&splits).Error
+	return splits, err
+}
+
 func (r *repository) ListRides(ctx context.Context, userID string, filters map[string]interface{}, page, limit int) ([]*models.Ride, int64, error) {
 	var rides []*models.Ride
 	var total int64
 
-	query := r.db.WithContext(ctx).Model(&models.Ride{})
+	query := archive.WithArchived(r.db.WithContext(ctx).Model(&models.Ride{}), "rides")
 
 	if role, ok := filters["role"].(string); ok {
 		switch role {
@@ -291,10 +410,163 @@ func (r *repository) GetDriverStats(ctx context.Context, driverID string) (total
 
 	err = r.db.WithContext(ctx).
 		Model(&models.Ride{}).
-		Select("COUNT(*) as total_trips, COALESCE(SUM(actual_fare * 0.80), 0) as total_earnings").
+		Select("COUNT(*) as total_trips, COALESCE(SUM(driver_fare), 0) as total_earnings").
 		Where("driver_id = ?", driverID).
 		Where("status = ?", "completed").
 		Scan(&stats).Error
 
 	return stats.TotalTrips, stats.TotalEarnings, err
 }
+
+func (r *repository) CreateFareSnapshot(ctx context.Context, snapshot *models.RideFareSnapshot) error {
+	return r.db.WithContext(ctx).Create(snapshot).Error
+}
+
+func (r *repository) FindFareSnapshotByRideID(ctx context.Context, rideID string) (*models.RideFareSnapshot, error) {
+	var snapshot models.RideFareSnapshot
+	err := r.db.WithContext(ctx).Where("ride_id = ?", rideID).First(&snapshot).Error
+	return &snapshot, err
+}
+
+func (r *repository) GetDriverCashTotals(ctx context.Context, driverUserID string, from, to time.Time) (*DriverCashTotals, error) {
+	var totals DriverCashTotals
+	err := r.db.WithContext(ctx).
+		Model(&models.Ride{}).
+		Select("COUNT(*) AS rides, COALESCE(SUM(cash_collected), 0) AS cash_collected, COALESCE(SUM(cash_commission), 0) AS commission").
+		Where("driver_id = ? AND payment_method = ? AND status = ?", driverUserID, models.RidePaymentCash, "completed").
+		Where("completed_at >= ? AND completed_at < ?", from, to).
+		Scan(&totals).Error
+	return &totals, err
+}
+
+func (r *repository) ListDriverCashRides(ctx context.Context, driverUserID string, from, to time.Time, page, limit int) ([]*models.Ride, int64, error) {
+	var rides []*models.Ride
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.Ride{}).
+		Where("driver_id = ? AND payment_method = ? AND status = ?", driverUserID, models.RidePaymentCash, "completed").
+		Where("completed_at >= ? AND completed_at < ?", from, to)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Order("completed_at DESC").Offset(offset).Limit(limit).Find(&rides).Error
+	return rides, total, err
+}
+
+func (r *repository) FindDriverWallet(ctx context.Context, driverUserID string) (*models.Wallet, error) {
+	var wallet models.Wallet
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND wallet_type = ?", driverUserID, models.WalletTypeDriver).
+		First(&wallet).Error
+	return &wallet, err
+}
+
+func (r *repository) UpdateCashSettlement(ctx context.Context, rideID string, collected, commission float64, txnID *string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Ride{}).
+		Where("id = ?", rideID).
+		Updates(map[string]interface{}{
+			"cash_collected":         collected,
+			"cash_commission":        commission,
+			"cash_commission_txn_id": txnID,
+		}).Error
+}
+
+func (r *repository) SetPickupETA(ctx context.Context, rideID string, minutes int) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Ride{}).
+		Where("id = ?", rideID).
+		Update("pickup_eta_minutes", minutes).Error
+}
+
+func (r *repository) FindRideTrackPoints(ctx context.Context, rideID string, since time.Time) ([]*models.RideTrackPoint, error) {
+	var points []*models.RideTrackPoint
+	err := r.db.WithContext(ctx).
+		Where("ride_id = ? AND recorded_at >= ?", rideID, since).
+		Order("recorded_at ASC").
+		Find(&points).Error
+	return points, err
+}
+
+func (r *repository) CreateTripVerification(ctx context.Context, verification *models.RideTripVerification) error {
+	return r.db.WithContext(ctx).Create(verification).Error
+}
+
+func (r *repository) FindTripVerificationByID(ctx context.Context, id string) (*models.RideTripVerification, error) {
+	var verification models.RideTripVerification
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&verification).Error
+	return &verification, err
+}
+
+func (r *repository) ListTripVerifications(ctx context.Context, status string, page, limit int) ([]*models.RideTripVerification, int64, error) {
+	var verifications []*models.RideTripVerification
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.RideTripVerification{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&verifications).Error
+	return verifications, total, err
+}
+
+func (r *repository) UpdateTripVerification(ctx context.Context, verification *models.RideTripVerification) error {
+	return r.db.WithContext(ctx).Save(verification).Error
+}
+
+// FindDriverDispatchStats loads the rating and rates matching ranks drivers
+// by, keyed by driver profile ID.
+func (r *repository) FindDriverDispatchStats(ctx context.Context, driverIDs []string) (map[string]*models.DriverProfile, error) {
+	var drivers []*models.DriverProfile
+	err := r.db.WithContext(ctx).
+		Select("id", "user_id", "rating", "acceptance_rate", "cancellation_rate").
+		Where("id IN ?", driverIDs).
+		Find(&drivers).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*models.DriverProfile, len(drivers))
+	for _, driver := range drivers {
+		result[driver.ID] = driver
+	}
+	return result, nil
+}
+
+// RefreshDriverDispatchStats recomputes a driver's acceptance rate over their
+// last window answered ride requests and their cancellation rate over their
+// last window accepted rides. A driver without any keeps the defaults of a
+// new driver.
+func (r *repository) RefreshDriverDispatchStats(ctx context.Context, driverID string, window int) error {
+	return r.db.WithContext(ctx).Exec(`
+		UPDATE driver_profiles dp SET
+			acceptance_rate = COALESCE((
+				SELECT ROUND(100.0 * COUNT(*) FILTER (WHERE rr.status = 'accepted') / NULLIF(COUNT(*), 0), 2)
+				FROM (
+					SELECT status FROM ride_requests
+					WHERE driver_id = dp.id AND status IN ('accepted', 'rejected', 'expired')
+					ORDER BY sent_at DESC
+					LIMIT ?
+				) rr
+			), 100),
+			cancellation_rate = COALESCE((
+				SELECT ROUND(100.0 * COUNT(*) FILTER (WHERE r.status = 'cancelled' AND r.cancelled_by = 'driver') / NULLIF(COUNT(*), 0), 2)
+				FROM (
+					SELECT status, cancelled_by FROM rides
+					WHERE driver_id = dp.user_id AND accepted_at IS NOT NULL AND status IN ('completed', 'cancelled')
+					ORDER BY accepted_at DESC
+					LIMIT ?
+				) r
+			), 0)
+		WHERE dp.id = ?
+	`, window, window, driverID).Error
+}
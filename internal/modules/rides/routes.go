@@ -2,24 +2,49 @@ package rides
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/middleware"
 )
 
 func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
+	// Shared trip links are opened by people without an account.
+	shared := router.Group("/trips/shared")
+	{
+		shared.GET("/:token", handler.GetSharedTrip)
+		shared.GET("/:token/live", handler.StreamSharedTrip)
+	}
+
 	rides := router.Group("/rides")
 	rides.Use(authMiddleware)
 	{
 		rides.POST("", handler.CreateRide)
 		rides.GET("", handler.ListRides)
 		rides.GET("/:id", handler.GetRide)
+		rides.GET("/:id/fare-breakdown", handler.GetFareBreakdown)
 		rides.POST("/:id/cancel", handler.CancelRide)
+		rides.POST("/:id/sos", handler.TriggerSOS)
 		rides.POST("/:id/emergency", handler.TriggerSOS)
+		rides.POST("/:id/share", middleware.RequireRole("rider"), handler.ShareTrip)
+		rides.GET("/:id/split", handler.GetFareSplit)
+		rides.POST("/:id/split", middleware.RequireRole("rider"), handler.InviteFareSplit)
+		rides.DELETE("/:id/split/:splitId", middleware.RequireRole("rider"), handler.RemoveFareSplit)
+		rides.POST("/:id/split/accept", middleware.RequireRole("rider"), handler.AcceptFareSplit)
+		rides.POST("/:id/split/decline", middleware.RequireRole("rider"), handler.DeclineFareSplit)
 		rides.POST("/available-cars", handler.GetAvailableCars)
 		rides.POST("/vehicles-with-details", handler.GetVehiclesWithDetails)
 
 		rides.POST("/:id/accept", handler.AcceptRide)
 		rides.POST("/:id/reject", handler.RejectRide)
 		rides.POST("/:id/arrived", handler.MarkArrived)
+		rides.POST("/:id/no-show", middleware.RequireRole("driver"), handler.ReportRiderNoShow)
 		rides.POST("/:id/start", handler.StartRide)
 		rides.POST("/:id/complete", handler.CompleteRide)
+
+		rides.GET("/driver/cash-summary", middleware.RequireRole("driver"), handler.GetDriverCashSummary)
+		rides.GET("/driver/cash-rides", middleware.RequireRole("driver"), handler.ListDriverCashRides)
+
+		admin := rides.Group("/admin", middleware.RequireAdmin())
+		admin.GET("/trip-verifications", handler.ListTripVerifications)
+		admin.POST("/trip-verifications/:id/review", handler.ReviewTripVerification)
+		admin.GET("/drivers/:driverId/dispatch-score", handler.GetDriverDispatchScore)
 	}
 }
@@ -9,10 +9,12 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/umar5678/go-backend/internal/config"
 	"github.com/umar5678/go-backend/internal/models"
 	adminrepo "github.com/umar5678/go-backend/internal/modules/admin"
 	batchingservice "github.com/umar5678/go-backend/internal/modules/batching"
 	batchingdto "github.com/umar5678/go-backend/internal/modules/batching/dto"
+	"github.com/umar5678/go-backend/internal/modules/cancellation"
 	driversrepo "github.com/umar5678/go-backend/internal/modules/drivers"
 	fraudservice "github.com/umar5678/go-backend/internal/modules/fraud"
 	notificationsmodule "github.com/umar5678/go-backend/internal/modules/notifications"
@@ -33,11 +35,15 @@ import (
 	walletservice "github.com/umar5678/go-backend/internal/modules/wallet"
 	walletdto "github.com/umar5678/go-backend/internal/modules/wallet/dto"
 	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/services/livemetrics"
+	"github.com/umar5678/go-backend/internal/services/routing"
+	"github.com/umar5678/go-backend/internal/services/tracing"
 	"github.com/umar5678/go-backend/internal/utils/location"
 	"github.com/umar5678/go-backend/internal/utils/logger"
 	"github.com/umar5678/go-backend/internal/utils/response"
 	"github.com/umar5678/go-backend/internal/websocket"
 	websocketutil "github.com/umar5678/go-backend/internal/websocket/websocketutils"
+	"go.opentelemetry.io/otel/attribute"
 	"gorm.io/gorm"
 )
 
@@ -54,13 +60,50 @@ type Service interface {
 	AcceptRide(ctx context.Context, driverID, rideID string) (*dto.RideResponse, error)
 	RejectRide(ctx context.Context, driverID, rideID string, req dto.RejectRideRequest) error
 	MarkArrived(ctx context.Context, driverID, rideID string) (*dto.RideResponse, error)
+	MarkArrivedByGeofence(ctx context.Context, rideID string) error
+	ReportRiderNoShow(ctx context.Context, driverID, rideID string, req dto.ReportNoShowRequest) (*dto.NoShowReportResponse, error)
 	StartRide(ctx context.Context, driverID, rideID string, req dto.StartRideRequest) (*dto.RideResponse, error)
 	CompleteRide(ctx context.Context, driverID, rideID string, req dto.CompleteRideRequest) (*dto.RideResponse, error)
+	GetFareBreakdown(ctx context.Context, userID, rideID string, isAdmin bool) (*pricingdto.FeeBreakdownResponse, error)
 
-	TriggerSOS(ctx context.Context, riderID, rideID string, latitude, longitude float64) error
+	TriggerSOS(ctx context.Context, userID, rideID string, req sosdto.TriggerRideSOSRequest) (*sosdto.SOSAlertResponse, error)
+
+	GetDriverCashSummary(ctx context.Context, userID string, req dto.DriverCashReportRequest) (*dto.DriverCashSummaryResponse, error)
+	ListDriverCashRides(ctx context.Context, userID string, req dto.DriverCashReportRequest) ([]*dto.CashRideResponse, int64, error)
+
+	ListTripVerifications(ctx context.Context, req dto.ListTripVerificationsRequest) ([]*dto.TripVerificationResponse, int64, error)
+	ReviewTripVerification(ctx context.Context, adminID, id string, req dto.ReviewTripVerificationRequest) (*dto.TripVerificationResponse, error)
+
+	ShareTrip(ctx context.Context, riderID, rideID string) (*dto.TripShareLinkResponse, error)
+	GetFareSplit(ctx context.Context, userID, rideID string) (*dto.FareSplitResponse, error)
+	InviteFareSplit(ctx context.Context, riderID, rideID string, req dto.InviteFareSplitRequest) (*dto.FareSplitResponse, error)
+	RemoveFareSplit(ctx context.Context, riderID, rideID, splitID string) (*dto.FareSplitResponse, error)
+	AcceptFareSplit(ctx context.Context, userID, rideID string) (*dto.FareSplitResponse, error)
+	DeclineFareSplit(ctx context.Context, userID, rideID string) (*dto.FareSplitResponse, error)
+	GetSharedTrip(ctx context.Context, token string) (*dto.SharedTripResponse, error)
 
 	FindDriverForRide(ctx context.Context, rideID string) error
 	ProcessRideRequestTimeout(ctx context.Context, requestID string) error
+	StartDispatch(ctx context.Context)
+	DrainDispatch(ctx context.Context) error
+	DispatchStats(ctx context.Context) (*DispatchStats, error)
+	GetDriverDispatchScore(ctx context.Context, driverID string) (*dto.DriverDispatchScoreResponse, error)
+
+	SetInsurer(insurer RideInsurer)
+	SetReceiptIssuer(issuer RideReceiptIssuer)
+	SetRouter(router *routing.Service)
+	ConfigureTripVerification(cfg config.TripVerificationConfig)
+	ConfigureTripSharing(cfg config.TripSharingConfig)
+	ConfigureDispatch(cfg config.DispatchConfig)
+	ConfigureFareLock(cfg config.FareLockConfig)
+	SetCancellationPolicies(policies CancellationPolicies)
+	SetServiceAreas(areas ServiceAreas)
+	SetFavoriteDrivers(favorites FavoriteDrivers)
+	SetLoyalty(loyalty LoyaltyPerks)
+	SetAddressBook(book AddressBook)
+	SetAddressNormalizer(normalizer AddressNormalizer)
+	SetEventPublisher(publisher EventPublisher)
+	SetFeatureFlags(flags FeatureFlags)
 }
 
 type service struct {
@@ -80,6 +123,23 @@ type service struct {
 	batchingService   batchingservice.Service
 	wsHelper          *RideWebSocketHelper
 	eventProducer     notificationsmodule.EventProducer
+	insurer           RideInsurer
+	receiptIssuer     RideReceiptIssuer
+	router            *routing.Service
+	tripCheck         *config.TripVerificationConfig
+	tripSharing       *config.TripSharingConfig
+	dispatchCfg       *config.DispatchConfig
+	fareLock          *config.FareLockConfig
+
+	cancellationPolicies CancellationPolicies
+	serviceAreas         ServiceAreas
+	favoriteDrivers      FavoriteDrivers
+	loyalty              LoyaltyPerks
+	addressBook          AddressBook
+	addressNormalizer    AddressNormalizer
+	events               EventPublisher
+	flags                FeatureFlags
+	dispatch             dispatcher
 }
 
 func NewService(
@@ -181,9 +241,13 @@ func NewServiceWithNotifications(
 }
 
 func (s *service) CreateRide(ctx context.Context, riderID string, req dto.CreateRideRequest) (*dto.RideResponse, error) {
+	if err := s.resolveRideAddresses(ctx, riderID, &req); err != nil {
+		return nil, err
+	}
 	if err := req.Validate(); err != nil {
 		return nil, response.BadRequest(err.Error())
 	}
+	s.normalizeRideAddresses(ctx, &req)
 
 	user, err := s.adminRepo.FindUserByID(ctx, riderID)
 	if err == nil && user.EmergencyContactPhone == "" {
@@ -195,10 +259,10 @@ func (s *service) CreateRide(ctx context.Context, riderID string, req dto.Create
 	if req.ScheduledAt != "" {
 		t, err := time.Parse(time.RFC3339, req.ScheduledAt)
 		if err != nil {
-			return nil, response.BadRequest("scheduledAt must be a valid RFC3339 timestamp")
+			return nil, response.CodedError(response.CodeScheduledAtInvalid, "scheduledAt must be a valid RFC3339 timestamp")
 		}
 		if !t.After(time.Now()) {
-			return nil, response.BadRequest("scheduledAt must be a future time")
+			return nil, response.CodedError(response.CodeScheduledAtInvalid, "scheduledAt must be a future time")
 		}
 		scheduledAtPtr = &t
 		isScheduled = true
@@ -234,6 +298,10 @@ func (s *service) CreateRide(ctx context.Context, riderID string, req dto.Create
 		}
 	}
 
+	if err := s.checkVehicleType(ctx, req); err != nil {
+		return nil, err
+	}
+
 	fareReq := pricingdto.FareEstimateRequest{
 		PickupLat:     req.PickupLat,
 		PickupLon:     req.PickupLon,
@@ -242,7 +310,13 @@ func (s *service) CreateRide(ctx context.Context, riderID string, req dto.Create
 		VehicleTypeID: req.VehicleTypeID,
 	}
 
-	fareEstimate, err := s.pricingService.GetFareEstimate(ctx, fareReq)
+	fareEstimate, err := s.pricingService.CalculateFareEstimate(ctx, fareReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// A locked fare is what the rider agreed to, so surge is not raised on top of it.
+	fareLocked, err := s.applyFareLock(ctx, req, fareReq, fareEstimate)
 	if err != nil {
 		return nil, err
 	}
@@ -251,7 +325,7 @@ func (s *service) CreateRide(ctx context.Context, riderID string, req dto.Create
 	surgeCalc, err := s.pricingService.CalculateCombinedSurge(ctx, req.VehicleTypeID, geohash, req.PickupLat, req.PickupLon)
 	if err != nil {
 		logger.Warn("failed to calculate combined surge, using basic surge", "error", err)
-	} else if surgeCalc != nil && surgeCalc.AppliedMultiplier > fareEstimate.SurgeMultiplier {
+	} else if !fareLocked && surgeCalc != nil && surgeCalc.AppliedMultiplier > fareEstimate.SurgeMultiplier {
 		fareEstimate.SurgeMultiplier = surgeCalc.AppliedMultiplier
 		fareEstimate.SurgeAmount = (fareEstimate.SubTotal) * (surgeCalc.AppliedMultiplier - 1.0)
 		fareEstimate.TotalFare = fareEstimate.SubTotal + fareEstimate.SurgeAmount
@@ -306,6 +380,18 @@ func (s *service) CreateRide(ctx context.Context, riderID string, req dto.Create
 		}
 	}
 
+	// The hold covers tax as well, so it is sized to what the rider will be charged.
+	if tax, err := s.pricingService.CalculateTax(ctx, pricingdto.CalculateTaxRequest{
+		Lat:       req.PickupLat,
+		Lon:       req.PickupLon,
+		Amount:    finalAmount,
+		AppliesTo: models.TaxAppliesToRides,
+	}); err != nil {
+		logger.Warn("failed to estimate ride tax", "error", err, "riderID", riderID)
+	} else {
+		finalAmount = tax.TotalWithTax
+	}
+
 	walletInfo, err := s.walletService.GetWallet(ctx, riderID)
 	if err != nil {
 		logger.Warn("could not get free credits info", "error", err, "riderID", riderID)
@@ -319,10 +405,18 @@ func (s *service) CreateRide(ctx context.Context, riderID string, req dto.Create
 
 	rideID := uuid.New().String()
 
+	paymentMethod := req.PaymentMethod
+	if paymentMethod == "" {
+		paymentMethod = models.RidePaymentWallet
+	}
+
 	var holdID *string
-	if !isScheduled && finalAmount > 0 {
+	if paymentMethod == models.RidePaymentCash {
+		logger.Info("cash ride - skipping wallet hold", "rideID", rideID, "amount", finalAmount)
+	} else if !isScheduled && finalAmount > 0 {
 		holdReq := walletdto.HoldFundsRequest{
 			Amount:        finalAmount,
+			Currency:      fareEstimate.Currency,
 			ReferenceType: "ride",
 			ReferenceID:   rideID,
 			HoldDuration:  1800,
@@ -359,8 +453,10 @@ func (s *service) CreateRide(ctx context.Context, riderID string, req dto.Create
 		EstimatedDistance: fareEstimate.EstimatedDistance,
 		EstimatedDuration: fareEstimate.EstimatedDuration,
 		EstimatedFare:     finalAmount,
+		Currency:          fareEstimate.Currency,
 		SurgeMultiplier:   fareEstimate.SurgeMultiplier,
 		WalletHoldID:      holdID,
+		PaymentMethod:     paymentMethod,
 		ScheduledAt:       scheduledAtPtr,
 		IsScheduled:       isScheduled,
 		RiderNotes:        req.RiderNotes,
@@ -377,6 +473,10 @@ func (s *service) CreateRide(ctx context.Context, riderID string, req dto.Create
 		return nil, response.InternalServerError("Failed to create ride", err)
 	}
 
+	if !isScheduled {
+		livemetrics.RideActive(ctx, rideID)
+	}
+
 	cacheKey := fmt.Sprintf("ride:active:%s", rideID)
 	if isScheduled {
 		cacheKey = fmt.Sprintf("ride:scheduled:%s", rideID)
@@ -387,148 +487,12 @@ func (s *service) CreateRide(ctx context.Context, riderID string, req dto.Create
 	}
 
 	if isScheduled && scheduledAtPtr != nil {
-		go func(rideID string, scheduledTime time.Time) {
-			bgCtx := context.Background()
-			wait := time.Until(scheduledTime)
-			if wait > 0 {
-				logger.Info("ride scheduled for future matching", "rideID", rideID, "waitSeconds", int(wait.Seconds()))
-				time.Sleep(wait)
-			}
-
-			if err := s.repo.UpdateRideStatus(bgCtx, rideID, "searching"); err != nil {
-				logger.Error("failed to update scheduled ride status to searching", "rideID", rideID, "error", err)
-				return
-			}
-			logger.Info("scheduled ride activated, starting driver matching", "rideID", rideID)
-
-			if s.batchingService != nil {
-				ride, err := s.repo.FindRideByID(bgCtx, rideID)
-				if err != nil {
-					logger.Error("failed to fetch ride for scheduled matching", "rideID", rideID, "error", err)
-					return
-				}
-
-				batchID, err := s.batchingService.AddRequestToBatch(bgCtx, struct {
-					RideID        string
-					RiderID       string
-					PickupLat     float64
-					PickupLon     float64
-					DropoffLat    float64
-					DropoffLon    float64
-					PickupGeohash string
-					VehicleTypeID string
-					RequestedAt   time.Time
-				}{
-					RideID:        ride.ID,
-					RiderID:       ride.RiderID,
-					PickupLat:     ride.PickupLat,
-					PickupLon:     ride.PickupLon,
-					DropoffLat:    ride.DropoffLat,
-					DropoffLon:    ride.DropoffLon,
-					VehicleTypeID: ride.VehicleTypeID,
-					RequestedAt:   time.Now(),
-				})
-
-				if err == nil {
-					logger.Info("scheduled ride added to batch for matching",
-						"batchID", batchID,
-						"rideID", rideID,
-					)
-					if result, err := s.batchingService.ProcessBatch(bgCtx, batchID); err != nil {
-						logger.Error("failed to process scheduled batch", "batchID", batchID, "error", err)
-					} else {
-						s.processMatchingResult(bgCtx, result)
-					}
-				}
-			} else {
-				if err := s.FindDriverForRide(bgCtx, rideID); err != nil {
-					logger.Error("failed to find driver for scheduled ride", "rideID", rideID, "error", err)
-				}
-			}
-		}(rideID, *scheduledAtPtr)
+		logger.Info("ride scheduled for future matching", "rideID", rideID, "scheduledAt", *scheduledAtPtr)
+		s.enqueueDispatch(ctx, rideID, *scheduledAtPtr)
 	} else {
-		go func() {
-			bgCtx := context.Background()
-
-			if s.batchingService != nil {
-				batchID, err := s.batchingService.AddRequestToBatch(bgCtx, struct {
-					RideID        string
-					RiderID       string
-					PickupLat     float64
-					PickupLon     float64
-					DropoffLat    float64
-					DropoffLon    float64
-					PickupGeohash string
-					VehicleTypeID string
-					RequestedAt   time.Time
-				}{
-					RideID:        ride.ID,
-					RiderID:       ride.RiderID,
-					PickupLat:     ride.PickupLat,
-					PickupLon:     ride.PickupLon,
-					DropoffLat:    ride.DropoffLat,
-					DropoffLon:    ride.DropoffLon,
-					VehicleTypeID: ride.VehicleTypeID,
-					RequestedAt:   time.Now(),
-				})
-
-				if err == nil {
-					logger.Info("Request added to batch for intelligent matching",
-						"batchID", batchID,
-						"rideID", ride.ID,
-						"riderID", ride.RiderID,
-						"vehicleType", ride.VehicleTypeID,
-					)
-
-					if result, err := s.batchingService.ProcessBatch(bgCtx, batchID); err != nil {
-						logger.Error("Failed to process batch",
-							"batchID", batchID,
-							"error", err,
-						)
-					} else {
-						s.processMatchingResult(bgCtx, result)
-					}
-				}
-
-				if err != nil {
-					logger.Warn("Failed to add request to batch, falling back to sequential matching",
-						"error", err,
-						"rideID", ride.ID,
-					)
-				}
-			}
-
-			if err := s.FindDriverForRide(bgCtx, rideID); err != nil {
-				logger.Error("failed to find driver", "error", err, "rideID", rideID)
-
-				currentRide, statusErr := s.repo.FindRideByID(bgCtx, rideID)
-				if statusErr != nil {
-					logger.Error("failed to fetch ride status", "error", statusErr, "rideID", rideID)
-					return
-				}
-
-				if currentRide.Status != "searching" {
-					logger.Info("ride already accepted by driver, not canceling",
-						"rideID", rideID,
-						"currentStatus", currentRide.Status,
-						"driverID", currentRide.DriverID,
-					)
-					return
-				}
-
-				if err := s.repo.UpdateRideStatus(bgCtx, rideID, "cancelled"); err != nil {
-					logger.Error("failed to update ride status", "error", err, "rideID", rideID)
-				}
-
-				if holdID != nil {
-					if err := s.walletService.ReleaseHold(bgCtx, riderID, walletdto.ReleaseHoldRequest{HoldID: *holdID}); err != nil {
-						logger.Error("failed to release hold", "error", err, "rideID", rideID)
-					}
-				}
-
-				s.wsHelper.SendRideStatusToBoth(bgCtx, riderID, "", rideID, "cancelled", "No drivers are currently active in you area.")
-			}
-		}()
+		s.startMatching(rideID, func(ctx context.Context) {
+			s.matchRide(ctx, ride)
+		})
 	}
 
 	if !isScheduled {
@@ -576,7 +540,8 @@ func (s *service) processMatchingResult(ctx context.Context, result *batchingdto
 			"eta", assignment.ETA,
 		)
 
-		go func(assign batchingdto.OptimalAssignment) {
+		assign := assignment
+		s.track(func() {
 			bgCtx := context.Background()
 
 			ride, err := s.repo.FindRideByID(bgCtx, assign.RideID)
@@ -608,25 +573,32 @@ func (s *service) processMatchingResult(ctx context.Context, result *batchingdto
 				"riderID", ride.RiderID,
 			)
 
+			route := s.rideRoute(bgCtx, ride)
+
 			s.wsHelper.SendRideAccepted(ride.RiderID, map[string]interface{}{
 				"rideId":    assign.RideID,
 				"driverId":  assign.DriverID,
 				"eta":       assign.ETA,
 				"distance":  assign.Distance,
+				"route":     route,
 				"timestamp": time.Now().Format(time.RFC3339),
 			})
 
 			s.wsHelper.SendRideRequest(assign.DriverID, map[string]interface{}{
-				"rideId":     assign.RideID,
-				"riderId":    ride.RiderID,
-				"pickupLat":  ride.PickupLat,
-				"pickupLon":  ride.PickupLon,
-				"pickupAddr": ride.PickupAddress,
-				"distance":   assign.Distance,
-				"timestamp":  time.Now().Format(time.RFC3339),
+				"rideId":      assign.RideID,
+				"riderId":     ride.RiderID,
+				"pickupLat":   ride.PickupLat,
+				"pickupLon":   ride.PickupLon,
+				"pickupAddr":  ride.PickupAddress,
+				"dropoffLat":  ride.DropoffLat,
+				"dropoffLon":  ride.DropoffLon,
+				"dropoffAddr": ride.DropoffAddress,
+				"distance":    assign.Distance,
+				"route":       route,
+				"navigation":  rideNavigation(ride, nil),
+				"timestamp":   time.Now().Format(time.RFC3339),
 			})
-
-		}(assignment)
+		})
 	}
 
 	if len(result.UnmatchedIDs) > 0 {
@@ -640,15 +612,15 @@ func (s *service) processMatchingResult(ctx context.Context, result *batchingdto
 				"rideID", rideID,
 			)
 
-			go func(rid string) {
-				bgCtx := context.Background()
-				if err := s.FindDriverForRide(bgCtx, rid); err != nil {
+			rid := rideID
+			s.startMatching(rid, func(ctx context.Context) {
+				if err := s.FindDriverForRide(ctx, rid); err != nil && !s.handedOff(ctx, rid) {
 					logger.Error("Sequential matching also failed",
 						"rideID", rid,
 						"error", err,
 					)
 				}
-			}(rideID)
+			})
 		}
 	}
 }
@@ -657,7 +629,10 @@ func ptr(t time.Time) *time.Time {
 	return &t
 }
 
-func (s *service) FindDriverForRide(ctx context.Context, rideID string) error {
+func (s *service) FindDriverForRide(ctx context.Context, rideID string) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "rides.FindDriverForRide", attribute.String("ride.id", rideID))
+	defer func() { tracing.End(span, err) }()
+
 	ride, err := s.repo.FindRideByID(ctx, rideID)
 	if err != nil {
 		return err
@@ -678,33 +653,40 @@ func (s *service) FindDriverForRide(ctx context.Context, rideID string) error {
 		)
 	}
 
-	radii := []float64{3.0, 5.0, 8.0}
+	// Lower-rated riders are only offered to drivers at the outer radii.
+	radii := s.matchingRadii(ctx, ride.PickupLat, ride.PickupLon)
 	if riderRating < 3.5 {
-		radii = []float64{8.0}
+		radii = radii[len(radii)-1:]
 		logger.Warn("low-rated rider search reduced",
 			"rideID", rideID,
 			"riderRating", riderRating,
-			"searchRadius", "8km only",
+			"searchRadiiKm", radii,
 		)
-	} else if riderRating < 4.0 {
-		radii = []float64{5.0, 8.0}
+	} else if riderRating < 4.0 && len(radii) > 1 {
+		radii = radii[1:]
 		logger.Info("medium-rated rider search radius adjusted",
 			"rideID", rideID,
 			"riderRating", riderRating,
-			"searchRadius", "5-8km",
+			"searchRadiiKm", radii,
 		)
 	}
 
+	settings := s.dispatchSettings(ctx, ride)
+	prioritizeDispatch(&settings, s.loyaltyPerks(ctx, ride.RiderID))
+
 	var nearbyDrivers *trackingdto.NearbyDriversResponse
+	var searchRadius float64
 
 	for _, radius := range radii {
+		searchRadius = radius
 		nearbyReq := trackingdto.FindNearbyDriversRequest{
-			Latitude:      ride.PickupLat,
-			Longitude:     ride.PickupLon,
-			RadiusKm:      radius,
-			VehicleTypeID: ride.VehicleTypeID,
-			Limit:         15,
-			OnlyAvailable: true,
+			Latitude:        ride.PickupLat,
+			Longitude:       ride.PickupLon,
+			RadiusKm:        radius,
+			VehicleTypeID:   ride.VehicleTypeID,
+			Limit:           dispatchCandidates(settings),
+			OnlyAvailable:   true,
+			ExcludeLowRated: true,
 		}
 
 		nearbyDrivers, err = s.trackingService.FindNearbyDrivers(ctx, nearbyReq)
@@ -755,23 +737,36 @@ func (s *service) FindDriverForRide(ctx context.Context, rideID string) error {
 		"riderRating", riderRating,
 	)
 
-	maxConcurrentRequests := 3
-	timeout := 30 * time.Second
+	s.rankByDispatchScore(ctx, rideID, nearbyDrivers.Drivers, searchRadius)
+	prefs := s.driverPreferences(ctx, rideID, nearbyDrivers.Drivers)
+	preferDriversInPreferredAreas(ride, nearbyDrivers.Drivers, prefs)
+	s.preferFavoriteDrivers(ctx, ride.RiderID, nearbyDrivers.Drivers)
 
-	resultChan := make(chan string, 1)
-	errorChan := make(chan error, 1)
-	ctxWithTimeout, cancel := context.WithTimeout(ctx, timeout)
-	defer cancel()
+	contacted := 0
+	for i, wave := range newDispatchStrategy(settings).Waves(nearbyDrivers.Drivers) {
+		logger.Info("offering ride to drivers",
+			"rideID", rideID,
+			"dispatchMode", settings.Mode,
+			"wave", i+1,
+			"drivers", len(wave),
+			"offerTimeoutSeconds", settings.OfferTimeout.Seconds(),
+		)
+		contacted += len(wave)
 
-	driversToContact := min(maxConcurrentRequests, nearbyDrivers.Count)
-	for i := 0; i < driversToContact; i++ {
-		driver := nearbyDrivers.Drivers[i]
-		go s.sendRideRequestToDriver(ctxWithTimeout, ride, driver, resultChan, errorChan)
-	}
+		acceptedDriverID := s.offerWave(ctx, ride, wave, settings.OfferTimeout, prefs)
+		if acceptedDriverID == "" {
+			// A driver may have accepted, by hand or automatically, or the
+			// rider cancelled while the wave was out.
+			currentRide, err := s.repo.FindRideByID(ctx, rideID)
+			if err != nil {
+				return err
+			}
+			if currentRide.Status != "searching" {
+				return nil
+			}
+			continue
+		}
 
-	select {
-	case acceptedDriverID := <-resultChan:
-		cancel()
 		driver, err := s.driversRepo.FindDriverByID(ctx, acceptedDriverID)
 		if err != nil {
 			logger.Error("failed to fetch driver details for ride assignment",
@@ -786,33 +781,33 @@ func (s *service) FindDriverForRide(ctx context.Context, rideID string) error {
 			"driverName", driver.User.Name,
 		)
 		return s.assignDriverToRide(ctx, rideID, driver.UserID, acceptedDriverID)
-
-	case <-ctxWithTimeout.Done():
-		logger.Error("no driver accepted ride request (timeout)",
-			"rideID", rideID,
-			"timeoutSeconds", timeout.Seconds(),
-			"driversContacted", driversToContact,
-		)
-		return errors.New("no driver accepted the ride request")
-
-	case err := <-errorChan:
-		logger.Error("error during driver search",
-			"rideID", rideID,
-			"error", err,
-		)
-		return err
 	}
+
+	logger.Error("no driver accepted ride request",
+		"rideID", rideID,
+		"dispatchMode", settings.Mode,
+		"driversContacted", contacted,
+	)
+	return errors.New("no driver accepted the ride request")
 }
 
 func (s *service) sendRideRequestToDriver(
 	ctx context.Context,
 	ride *models.Ride,
 	driver trackingdto.DriverLocationResponse,
+	offerTimeout time.Duration,
+	autoAccept bool,
 	resultChan chan<- string,
 	errorChan chan<- error,
 ) {
+	ctx, span := tracing.StartSpan(ctx, "rides.sendRideRequestToDriver",
+		attribute.String("ride.id", ride.ID),
+		attribute.String("driver.id", driver.DriverID),
+	)
+	defer span.End()
+
 	requestID := uuid.New().String()
-	expiresAt := time.Now().Add(10 * time.Second)
+	expiresAt := time.Now().Add(offerTimeout)
 
 	driverDetails, err := s.driversRepo.FindDriverByID(ctx, driver.DriverID)
 	if err != nil {
@@ -861,8 +856,14 @@ func (s *service) sendRideRequestToDriver(
 		"estimatedFare": ride.EstimatedFare,
 		"distance":      driver.Distance,
 		"eta":           driver.ETA,
-		"expiresIn":     10,
+		"expiresIn":     int(offerTimeout.Seconds()),
+		"autoAccept":    autoAccept,
 		"riderNotes":    ride.RiderNotes,
+		"route":         s.rideRoute(ctx, ride),
+		"navigation": rideNavigation(ride, &location.Point{
+			Latitude:  driver.Location.Latitude,
+			Longitude: driver.Location.Longitude,
+		}),
 	}
 
 	if err := s.wsHelper.SendRideRequest(userIDForWebSocket, rideDetails); err != nil {
@@ -891,6 +892,10 @@ func (s *service) sendRideRequestToDriver(
 		"eta":        driver.ETA,
 	})
 
+	if autoAccept {
+		s.autoAcceptRide(ctx, ride, driverDetails, requestID)
+	}
+
 	ticker := time.NewTicker(1 * time.Second)
 	defer ticker.Stop()
 
@@ -971,6 +976,7 @@ func (s *service) sendRideRequestToDriver(
 
 			if time.Now().After(expiresAt) {
 				s.repo.UpdateRideRequestStatus(ctx, requestID, "expired", nil)
+				s.refreshDispatchStats(driver.DriverID)
 				logger.Info("ride request expired due to timeout",
 					"requestID", requestID,
 					"driverID", driver.DriverID,
@@ -1002,11 +1008,11 @@ func (s *service) AcceptRide(ctx context.Context, userID, rideID string) (*dto.R
 	}
 
 	if rideRequest.Status != "pending" {
-		return nil, response.BadRequest("Ride request is no longer available")
+		return nil, response.CodedError(response.CodeRideNotAvailable, "Ride request is no longer available")
 	}
 
 	if time.Now().After(rideRequest.ExpiresAt) {
-		return nil, response.BadRequest("Ride request has expired")
+		return nil, response.CodedError(response.CodeRideRequestExpired, "")
 	}
 
 	ride, err := s.repo.FindRideByID(ctx, rideID)
@@ -1015,11 +1021,26 @@ func (s *service) AcceptRide(ctx context.Context, userID, rideID string) (*dto.R
 	}
 
 	if ride.Status != "searching" {
-		return nil, response.BadRequest("Ride is no longer available")
+		return nil, response.CodedError(response.CodeRideNotAvailable, "")
 	}
 
 	if driver.Status != "online" {
-		return nil, response.BadRequest("Driver must be online to accept rides")
+		return nil, response.CodedError(response.CodeDriverOffline, "")
+	}
+
+	if driver.Vehicle != nil && driver.Vehicle.InspectionLapsed(time.Now()) {
+		return nil, response.CodedError(response.CodeDriverInspectionLapsed, "Your vehicle inspection has lapsed. Submit a new inspection to accept rides")
+	}
+
+	// The driver may have switched vehicles since the ride was offered.
+	if driver.Vehicle != nil && driver.Vehicle.VehicleTypeID != ride.VehicleTypeID {
+		return nil, response.CodedError(response.CodeRideNotAvailable, "This ride needs a different vehicle type from your active vehicle")
+	}
+
+	if ride.PaymentMethod == models.RidePaymentCash {
+		if err := s.ensureCashRideEligible(ctx, driver); err != nil {
+			return nil, err
+		}
 	}
 
 	riderRiskScore, _ := s.fraudService.CheckUserRiskScore(ctx, ride.RiderID)
@@ -1044,6 +1065,8 @@ func (s *service) AcceptRide(ctx context.Context, userID, rideID string) (*dto.R
 		return nil, err
 	}
 
+	s.refreshDispatchStats(driverID)
+
 	logger.Info("ride successfully accepted by driver",
 		"rideID", rideID,
 		"driverID", driverID,
@@ -1104,12 +1127,13 @@ func (s *service) RejectRide(ctx context.Context, userID, rideID string, req dto
 	}
 
 	if rideRequest.Status != "pending" {
-		return response.BadRequest("Ride request is no longer available")
+		return response.CodedError(response.CodeRideNotAvailable, "Ride request is no longer available")
 	}
 
 	if err := s.repo.UpdateRideRequestStatus(ctx, rideRequest.ID, "rejected", &req.Reason); err != nil {
 		return response.InternalServerError("Failed to reject ride", err)
 	}
+	s.refreshDispatchStats(driverID)
 
 	logger.Info("ride rejected by driver",
 		"rideID", rideID,
@@ -1155,18 +1179,88 @@ func (s *service) MarkArrived(ctx context.Context, userID, rideID string) (*dto.
 	}
 
 	if ride.Status != "accepted" {
-		return nil, response.BadRequest("Invalid ride status")
+		return nil, response.CodedError(response.CodeRideInvalidStatus, "")
+	}
+
+	arrived, err := s.markArrived(ctx, ride, driver, models.ArrivalMethodManual)
+	if err != nil {
+		return nil, err
+	}
+	if !arrived {
+		return nil, response.CodedError(response.CodeRideInvalidStatus, "")
+	}
+
+	freshRide, err := s.repo.FindRideByID(ctx, rideID)
+	if err != nil {
+		logger.Error("failed to fetch fresh ride data for response", "error", err, "rideID", rideID)
+		if ride != nil {
+			return dto.ToRideResponse(ride), nil
+		}
+		return nil, response.InternalServerError("Failed to fetch ride data", err)
+	}
+
+	if freshRide == nil {
+		logger.Error("fetched ride is nil", "rideID", rideID)
+		return dto.ToRideResponse(ride), nil
+	}
+
+	rideCacheKey := fmt.Sprintf("ride:active:%s", rideID)
+	if err := cache.Delete(ctx, rideCacheKey); err != nil {
+		logger.Warn("failed to clear ride cache after status update", "error", err, "rideCacheKey", rideCacheKey)
+	}
+
+	return dto.ToRideResponse(freshRide), nil
+}
+
+// MarkArrivedByGeofence moves a ride to arrived when tracking sees its driver
+// inside the pickup geofence. Rides no longer waiting for the driver are
+// left alone.
+func (s *service) MarkArrivedByGeofence(ctx context.Context, rideID string) error {
+	ride, err := s.repo.FindRideByID(ctx, rideID)
+	if err != nil {
+		return err
+	}
+	if ride.Status != "accepted" || ride.DriverID == nil {
+		return nil
+	}
+	driver, err := s.driversRepo.FindDriverByUserID(ctx, *ride.DriverID)
+	if err != nil {
+		return err
+	}
+	arrived, err := s.markArrived(ctx, ride, driver, models.ArrivalMethodGeofence)
+	if err != nil || !arrived {
+		return err
+	}
+
+	rideCacheKey := fmt.Sprintf("ride:active:%s", rideID)
+	if err := cache.Delete(ctx, rideCacheKey); err != nil {
+		logger.Warn("failed to clear ride cache after status update", "error", err, "rideCacheKey", rideCacheKey)
 	}
+	return nil
+}
 
-	if err := s.repo.UpdateRideStatus(ctx, rideID, "arrived"); err != nil {
-		return nil, response.InternalServerError("Failed to update status", err)
+// markArrived moves an accepted ride to arrived and tells the rider. It
+// reports false if the ride had already left the accepted status.
+func (s *service) markArrived(ctx context.Context, ride *models.Ride, driver *models.DriverProfile, method string) (bool, error) {
+	rideID := ride.ID
+	driverID := driver.ID
+	userID := driver.UserID
+
+	arrived, err := s.repo.MarkRideArrived(ctx, rideID, method)
+	if err != nil {
+		return false, response.InternalServerError("Failed to update status", err)
+	}
+	if !arrived {
+		return false, nil
 	}
 
 	if err := websocketutil.SendRideStatusUpdate(ride.RiderID, userID, map[string]interface{}{
-		"rideId":    rideID,
-		"status":    "arrived",
-		"message":   "Your driver has arrived at the pickup location",
-		"timestamp": time.Now().UTC(),
+		"rideId":        rideID,
+		"status":        "arrived",
+		"message":       "Your driver has arrived at the pickup location",
+		"messageKey":    "ws.ride.driver_arrived",
+		"timestamp":     time.Now().UTC(),
+		"arrivalMethod": method,
 	}); err != nil {
 		logger.Warn("failed to notify rider and driver of arrival", "error", err, "rideID", rideID)
 	}
@@ -1177,6 +1271,7 @@ func (s *service) MarkArrived(ctx context.Context, userID, rideID string) (*dto.
 		"userID", userID,
 		"driverName", driver.User.Name,
 		"riderID", ride.RiderID,
+		"arrivalMethod", method,
 	)
 
 	s.publishRideEvent(ctx, notificationsmodule.EventDriverArrived, rideID, ride.RiderID, driverID, map[string]interface{}{})
@@ -1188,26 +1283,7 @@ func (s *service) MarkArrived(ctx context.Context, userID, rideID string) (*dto.
 		*ride.WaitTimeCharge += 1.0
 	})
 
-	freshRide, err := s.repo.FindRideByID(ctx, rideID)
-	if err != nil {
-		logger.Error("failed to fetch fresh ride data for response", "error", err, "rideID", rideID)
-		if ride != nil {
-			return dto.ToRideResponse(ride), nil
-		}
-		return nil, response.InternalServerError("Failed to fetch ride data", err)
-	}
-
-	if freshRide == nil {
-		logger.Error("fetched ride is nil", "rideID", rideID)
-		return dto.ToRideResponse(ride), nil
-	}
-
-	rideCacheKey := fmt.Sprintf("ride:active:%s", rideID)
-	if err := cache.Delete(ctx, rideCacheKey); err != nil {
-		logger.Warn("failed to clear ride cache after status update", "error", err, "rideCacheKey", rideCacheKey)
-	}
-
-	return dto.ToRideResponse(freshRide), nil
+	return true, nil
 }
 
 func (s *service) StartRide(ctx context.Context, userID, rideID string, req dto.StartRideRequest) (*dto.RideResponse, error) {
@@ -1237,7 +1313,7 @@ func (s *service) StartRide(ctx context.Context, userID, rideID string, req dto.
 
 	if ride.Status != "accepted" && ride.Status != "arrived" {
 		logger.Warn("invalid ride status for start", "rideID", rideID, "status", ride.Status)
-		return nil, response.BadRequest("Ride must be accepted or arrived to start")
+		return nil, response.CodedError(response.CodeRideInvalidStatus, "Ride must be accepted or arrived to start")
 	}
 
 	logger.Info("verifying ride PIN", "rideID", rideID, "riderID", ride.RiderID)
@@ -1248,7 +1324,7 @@ func (s *service) StartRide(ctx context.Context, userID, rideID string, req dto.
 			"riderID", ride.RiderID)
 
 		s.publishRideEvent(ctx, notificationsmodule.EventInvalidRidePINAttempt, rideID, ride.RiderID, driverID, map[string]interface{}{})
-		return nil, response.BadRequest("Invalid Rider PIN. Please ask the rider for their 4-digit Ride PIN.")
+		return nil, response.CodedError(response.CodeRideInvalidPIN, "Invalid Rider PIN. Please ask the rider for their 4-digit Ride PIN.")
 	}
 
 	logger.Info("ride PIN verified at start", "rideID", rideID)
@@ -1279,6 +1355,8 @@ func (s *service) StartRide(ctx context.Context, userID, rideID string, req dto.
 	}
 	logger.Info("ride status updated", "rideID", rideID)
 
+	s.attachInsurance(ride)
+
 	logger.Info("updating driver status", "driverID", driverID)
 	if err := s.driversRepo.UpdateDriverStatus(ctx, driverID, "on_trip"); err != nil {
 		logger.Warn("failed to update driver status", "error", err, "driverID", driverID)
@@ -1298,10 +1376,11 @@ func (s *service) StartRide(ctx context.Context, userID, rideID string, req dto.
 
 	logger.Info("sending websocket notification", "rideID", rideID)
 	if err := websocketutil.SendRideStatusUpdate(ride.RiderID, driverUserID, map[string]interface{}{
-		"rideId":    rideID,
-		"status":    "started",
-		"message":   "Your ride has started",
-		"timestamp": time.Now().UTC(),
+		"rideId":     rideID,
+		"status":     "started",
+		"message":    "Your ride has started",
+		"messageKey": "ws.ride.started",
+		"timestamp":  time.Now().UTC(),
 	}); err != nil {
 		logger.Warn("failed to send websocket notification", "error", err, "rideID", rideID)
 	}
@@ -1356,7 +1435,7 @@ func (s *service) CompleteRide(ctx context.Context, userID, rideID string, req d
 	}
 
 	if ride.Status != "started" {
-		return nil, response.BadRequest("Ride must be started first")
+		return nil, response.CodedError(response.CodeRideInvalidStatus, "Ride must be started first")
 	}
 
 	distanceToDropoff := location.HaversineDistance(req.DriverLat, req.DriverLon, ride.DropoffLat, ride.DropoffLon)
@@ -1366,7 +1445,7 @@ func (s *service) CompleteRide(ctx context.Context, userID, rideID string, req d
 
 	if distanceKm > maxCompletionRadiusKm+epsilon {
 		logger.Warn("driver outside completion radius", "rideID", rideID, "distanceKm", distanceKm, "maxRadiusKm", maxCompletionRadiusKm)
-		return nil, response.BadRequest(fmt.Sprintf("You must be within 100 meters of the destination. Current distance: %.0f meters", distanceKm*1000))
+		return nil, response.CodedError(response.CodeRideTooFarFromDropoff, fmt.Sprintf("You must be within 100 meters of the destination. Current distance: %.0f meters", distanceKm*1000))
 	}
 
 	logger.Info("driver verified within 100 meter radius", "rideID", rideID, "distanceKm", distanceKm)
@@ -1398,6 +1477,9 @@ func (s *service) CompleteRide(ctx context.Context, userID, rideID string, req d
 		ActualDurationSec: req.ActualDuration,
 		VehicleTypeID:     ride.VehicleTypeID,
 		SurgeMultiplier:   ride.SurgeMultiplier,
+		PickupLat:         &ride.PickupLat,
+		PickupLon:         &ride.PickupLon,
+		RequestedAt:       &ride.RequestedAt,
 	}
 
 	actualFareResp, err := s.pricingService.CalculateActualFare(ctx, actualFareReq)
@@ -1424,6 +1506,13 @@ func (s *service) CompleteRide(ctx context.Context, userID, rideID string, req d
 
 	Fare := cappingResp.CustomerPrice
 	DriverFareAmount := cappingResp.DriverEarning
+	platformFee := cappingResp.PlatformFee
+	// A capped fare keeps the earnings fixed by the capping rule; otherwise the
+	// commission rate splits the fare.
+	if !cappingResp.PriceCapped {
+		DriverFareAmount = actualFareResp.DriverPayout
+		platformFee = actualFareResp.PlatformCommission
+	}
 
 	if cappingResp.PriceCapped {
 		logger.Info("ride fare capped due to price ceiling",
@@ -1456,8 +1545,36 @@ func (s *service) CompleteRide(ctx context.Context, userID, rideID string, req d
 		)
 	}
 
+	discount := loyaltyDiscount(actualFareResp.BookingFee, s.loyaltyPerks(ctx, ride.RiderID))
+	if discount > 0 {
+		Fare -= discount
+		platformFee -= discount
+		logger.Info("loyalty discount applied to ride",
+			"rideID", rideID,
+			"bookingFee", actualFareResp.BookingFee,
+			"loyaltyDiscount", discount,
+		)
+	}
+
 	actualFare := Fare
 
+	var taxLines models.TaxLines
+	tax, err := s.pricingService.CalculateTax(ctx, pricingdto.CalculateTaxRequest{
+		Lat:       ride.PickupLat,
+		Lon:       ride.PickupLon,
+		Amount:    actualFare,
+		AppliesTo: models.TaxAppliesToRides,
+	})
+	if err != nil {
+		logger.Error("failed to calculate ride tax", "error", err, "rideID", rideID)
+	} else {
+		taxLines = tax.Lines
+	}
+	taxAmount := taxLines.Total()
+	// Tax is collected from the rider and owed by the platform; it never
+	// reaches the driver's share.
+	riderFare := math.Round((actualFare+taxAmount)*100) / 100
+
 	if s.sosService != nil {
 		activeSOS, _ := s.sosService.GetActiveSOS(ctx, ride.RiderID)
 		if activeSOS != nil {
@@ -1473,7 +1590,7 @@ func (s *service) CompleteRide(ctx context.Context, userID, rideID string, req d
 	ride.ActualDuration = &req.ActualDuration
 	ride.ActualFare = &actualFareResp.TotalFare
 	ride.DriverFare = &DriverFareAmount
-	ride.RiderFare = &actualFare
+	ride.RiderFare = &riderFare
 	ride.Status = "completed"
 	completedAt := time.Now()
 	ride.CompletedAt = &completedAt
@@ -1482,10 +1599,57 @@ func (s *service) CompleteRide(ctx context.Context, userID, rideID string, req d
 		return nil, response.InternalServerError("Failed to complete ride", err)
 	}
 
+	s.verifyTrip(ctx, ride, req, completedAt)
+	s.refreshDispatchStats(driverID)
+
+	livemetrics.RideEnded(ctx, rideID)
+	s.endInsuranceCoverage(ctx, rideID, completedAt)
+	livemetrics.AddRevenue(ctx, livemetrics.RevenueSourceRides, riderFare)
+
+	fareCurrency := actualFareResp.Currency
+	if ride.Currency != "" {
+		fareCurrency = ride.Currency
+	}
+
+	snapshot := &models.RideFareSnapshot{
+		RideID:             rideID,
+		VehicleTypeID:      ride.VehicleTypeID,
+		Currency:           fareCurrency,
+		BaseFare:           actualFareResp.BaseFare,
+		DistanceFare:       actualFareResp.DistanceFare,
+		DurationFare:       actualFareResp.DurationFare,
+		BookingFee:         actualFareResp.BookingFee,
+		SurgeMultiplier:    actualFareResp.SurgeMultiplier,
+		SurgeAmount:        actualFareResp.SurgeAmount,
+		PriceCapAdjustment: cappingResp.PlatformAbsorbed,
+		TaxAmount:          taxAmount,
+		TaxLines:           taxLines,
+		TotalCharged:       riderFare,
+		DriverShare:        DriverFareAmount,
+		PlatformFee:        platformFee,
+		PlatformAbsorbed:   cappingResp.PlatformAbsorbed,
+		PriceCapped:        cappingResp.PriceCapped,
+	}
+	if ride.WaitTimeCharge != nil {
+		snapshot.WaitTimeCharge = *ride.WaitTimeCharge
+	}
+	if ride.DestinationChangeCharge != nil {
+		snapshot.DestinationChangeCharge = *ride.DestinationChangeCharge
+	}
+	if ride.PromoDiscount != nil {
+		snapshot.PromoDiscount = *ride.PromoDiscount
+	}
+	snapshot.LoyaltyDiscount = discount
+	if err := s.repo.CreateFareSnapshot(ctx, snapshot); err != nil {
+		logger.Error("failed to persist fare snapshot", "error", err, "rideID", rideID)
+	}
+
 	if ride.WalletHoldID != nil {
+		organizerFare := s.settleFareSplit(ctx, ride, riderFare)
 		captureReq := walletdto.CaptureHoldRequest{
-			HoldID: *ride.WalletHoldID,
-			Amount: ride.RiderFare,
+			HoldID:   *ride.WalletHoldID,
+			Amount:   &organizerFare,
+			Currency: ride.Currency,
 			Description: fmt.Sprintf("Ride payment for %s (Distance: %.2f km, Duration: %.0f min)",
 				rideID,
 				req.ActualDistance,
@@ -1495,7 +1659,7 @@ func (s *service) CompleteRide(ctx context.Context, userID, rideID string, req d
 
 		_, err := s.walletService.CaptureHold(ctx, ride.RiderID, captureReq)
 		if err != nil {
-			logger.Error("failed to capture hold", "error", err, "rideID", rideID, "amount", ride.RiderFare)
+			logger.Error("failed to capture hold", "error", err, "rideID", rideID, "amount", organizerFare)
 			return nil, response.InternalServerError("Failed to process payment", err)
 		}
 	}
@@ -1513,27 +1677,35 @@ func (s *service) CompleteRide(ctx context.Context, userID, rideID string, req d
 		}
 	}
 
-	driverEarnings := actualFareResp.DriverPayout
+	driverEarnings := DriverFareAmount
 
 	logger.Info("ride payout breakdown",
 		"rideID", rideID,
 		"totalFare", actualFare,
 		"driverEarnings", driverEarnings,
-		"platformCommission", 0,
-		"commissionRate", 0,
+		"platformCommission", platformFee,
+		"commissionRate", actualFareResp.CommissionRate,
 	)
 
-	_, err = s.walletService.CreditDriverWallet(
-		ctx,
-		driver.UserID,
-		actualFare,
-		"ride_earnings",
-		rideID,
-		fmt.Sprintf("Cash earned from ride %s", rideID),
-		map[string]interface{}{"total_fare": actualFare},
-	)
-	if err != nil {
-		logger.Error("failed to credit driver wallet", "error", err, "rideID", rideID)
+	if ride.PaymentMethod == models.RidePaymentCash {
+		s.settleCashRide(ctx, ride, driver, riderFare, DriverFareAmount)
+	} else {
+		_, err = s.walletService.CreditDriverWallet(
+			ctx,
+			driver.UserID,
+			driverEarnings,
+			"ride_earnings",
+			rideID,
+			fmt.Sprintf("Cash earned from ride %s", rideID),
+			map[string]interface{}{
+				"total_fare":      actualFare,
+				"platform_fee":    platformFee,
+				"commission_rate": actualFareResp.CommissionRate,
+			},
+		)
+		if err != nil {
+			logger.Error("failed to credit driver wallet", "error", err, "rideID", rideID)
+		}
 	}
 
 	s.driversRepo.IncrementTrips(ctx, driverID)
@@ -1561,27 +1733,33 @@ func (s *service) CompleteRide(ctx context.Context, userID, rideID string, req d
 
 	if err := websocketutil.SendToUser(ride.RiderID, websocket.TypeRideCompleted, map[string]interface{}{
 		"rideId":     rideID,
-		"actualFare": actualFare,
+		"actualFare": riderFare,
+		"taxAmount":  taxAmount,
 		"message":    "Your ride is complete. Thank you for riding with us!",
+		"messageKey": "ws.ride.completed_rider",
 		"timestamp":  time.Now().UTC(),
 	}); err != nil {
 		logger.Warn("failed to notify rider of completion", "error", err, "rideID", rideID)
 	}
 
 	if err := websocketutil.SendToUser(driverUserID, websocket.TypeRideCompleted, map[string]interface{}{
-		"rideId":    rideID,
-		"earnings":  driverEarnings,
-		"message":   "Ride completed successfully",
-		"timestamp": time.Now().UTC(),
+		"rideId":     rideID,
+		"earnings":   driverEarnings,
+		"message":    "Ride completed successfully",
+		"messageKey": "ws.ride.completed_driver",
+		"timestamp":  time.Now().UTC(),
 	}); err != nil {
 		logger.Warn("failed to notify driver of completion", "error", err, "rideID", rideID)
 	}
 
 	s.publishRideEvent(ctx, notificationsmodule.EventRideCompleted, rideID, ride.RiderID, driverUserID, map[string]interface{}{
 		"status":   "completed",
-		"fare":     actualFare,
+		"fare":     riderFare,
 		"earnings": driverEarnings,
 	})
+	s.publishRideCompleted(ctx, ride, driverUserID, riderFare, taxAmount)
+
+	s.issueReceipt(rideID)
 
 	go func() {
 		defer func() {
@@ -1636,15 +1814,17 @@ func (s *service) promptRatings(ctx context.Context, riderID, driverUserID, ride
 	time.Sleep(5 * time.Second)
 
 	if err := websocketutil.SendToUser(riderID, websocket.TypeRatingPrompt, map[string]interface{}{
-		"rideId":  rideID,
-		"message": "How was your ride? Please rate your driver",
+		"rideId":     rideID,
+		"message":    "How was your ride? Please rate your driver",
+		"messageKey": "ws.ride.rate_driver",
 	}); err != nil {
 		logger.Warn("failed to send rating prompt to rider", "error", err, "rideID", rideID, "riderID", riderID)
 	}
 
 	if err := websocketutil.SendToUser(driverUserID, websocket.TypeRatingPrompt, map[string]interface{}{
-		"rideId":  rideID,
-		"message": "Please rate your rider",
+		"rideId":     rideID,
+		"message":    "Please rate your rider",
+		"messageKey": "ws.ride.rate_rider",
 	}); err != nil {
 		logger.Warn("failed to send rating prompt to driver", "error", err, "rideID", rideID, "driverUserID", driverUserID)
 	}
@@ -1660,7 +1840,7 @@ func (s *service) assignDriverToRide(ctx context.Context, rideID, userID, driver
 			"rideID", rideID,
 			"userID", userID,
 		)
-		return response.BadRequest("Ride already accepted by another driver")
+		return response.CodedError(response.CodeRideAlreadyAccepted, "")
 	}
 
 	go func() {
@@ -1717,10 +1897,9 @@ func (s *service) assignDriverToRide(ctx context.Context, rideID, userID, driver
 		driverLat = driverLocation.Latitude
 		driverLon = driverLocation.Longitude
 
-		distance := calculateDistance(driverLat, driverLon, ride.PickupLat, ride.PickupLon)
-		calculatedETA = int((distance / 30.0) * 60)
-		if calculatedETA < 1 {
-			calculatedETA = 1
+		calculatedETA = s.pickupETAMinutes(ctx, driverLat, driverLon, ride.PickupLat, ride.PickupLon)
+		if err := s.repo.SetPickupETA(ctx, rideID, calculatedETA); err != nil {
+			logger.Warn("failed to record pickup ETA", "error", err, "rideID", rideID)
 		}
 	} else {
 		driverLat = ride.PickupLat
@@ -1753,8 +1932,10 @@ func (s *service) assignDriverToRide(ctx context.Context, rideID, userID, driver
 			"latitude":  driverLat,
 			"longitude": driverLon,
 		},
-		"message": "Driver is on the way!",
-		"eta":     calculatedETA,
+		"message":    "Driver is on the way!",
+		"messageKey": "ws.ride.driver_on_the_way",
+		"eta":        calculatedETA,
+		"route":      s.rideRoute(ctx, ride),
 	}
 
 	if err := s.wsHelper.SendRideAccepted(ride.RiderID, rideDetails); err != nil {
@@ -1956,16 +2137,21 @@ func (s *service) GetAvailableCars(ctx context.Context, riderID string, req dto.
 
 func (s *service) GetVehiclesWithDetails(ctx context.Context, riderID string, req dto.VehicleDetailsRequest) (*dto.VehiclesWithDetailsListResponse, error) {
 
-	tripDistance := location.HaversineDistance(req.PickupLat, req.PickupLon, req.DropoffLat, req.DropoffLon)
-	if tripDistance < 0.5 {
-		return nil, response.BadRequest("Minimum trip distance is 0.5 km")
+	straightDistance := location.HaversineDistance(req.PickupLat, req.PickupLon, req.DropoffLat, req.DropoffLon)
+	if straightDistance < 0.5 {
+		return nil, response.CodedError(response.CodeRideDistanceOutOfRange, "Minimum trip distance is 0.5 km")
 	}
-	if tripDistance > 100 {
-		return nil, response.BadRequest("Maximum trip distance is 100 km")
+	if straightDistance > 100 {
+		return nil, response.CodedError(response.CodeRideDistanceOutOfRange, "Maximum trip distance is 100 km")
 	}
 
+	route := s.router.Route(ctx, req.PickupLat, req.PickupLon, req.DropoffLat, req.DropoffLon)
+	tripDistance := route.DistanceKm
+	tripDurationSeconds := route.DurationSeconds
+
+	// Pickup ETAs for the whole list stay on the straight-line estimate; only
+	// the assigned driver's ETA is worth a routing call.
 	const avgSpeedKmh = 40.0
-	tripDurationSeconds := location.CalculateETA(tripDistance, avgSpeedKmh)
 
 	drivers, err := s.driversRepo.FindNearbyDrivers(ctx, req.PickupLat, req.PickupLon, req.RadiusKm, "")
 	if err != nil {
@@ -2079,7 +2265,7 @@ func (s *service) GetVehiclesWithDetails(ctx context.Context, riderID string, re
 			DropoffLon:    req.DropoffLon,
 			VehicleTypeID: vehicle.VehicleTypeID,
 		}
-		fareResp, err := s.pricingService.GetFareEstimate(ctx, fareReq)
+		fareResp, err := s.pricingService.CalculateFareEstimate(ctx, fareReq)
 		if err != nil {
 			logger.Warn("failed to get fare estimate", "error", err, "vehicleTypeID", vehicle.VehicleTypeID)
 			estimatedFare := vehicle.VehicleType.BaseFare + (tripDistance*vehicle.VehicleType.PerKmRate)*surgeResp.AppliedMultiplier
@@ -2210,6 +2396,41 @@ func parseDriverLocation(geom *string) (float64, float64, error) {
 	return lat, lon, nil
 }
 
+func (s *service) GetFareBreakdown(ctx context.Context, userID, rideID string, isAdmin bool) (*pricingdto.FeeBreakdownResponse, error) {
+	ride, err := s.repo.FindRideByID(ctx, rideID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Ride")
+		}
+		return nil, response.InternalServerError("Failed to fetch ride", err)
+	}
+
+	viewer := pricingservice.FeeViewerAdmin
+	switch {
+	case isAdmin:
+	case ride.DriverID != nil && *ride.DriverID == userID:
+		viewer = pricingservice.FeeViewerEarner
+	case ride.RiderID == userID:
+		viewer = pricingservice.FeeViewerCustomer
+	default:
+		return nil, response.ForbiddenError("Not authorized to view this ride")
+	}
+
+	snapshot, err := s.repo.FindFareSnapshotByRideID(ctx, rideID)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.InternalServerError("Failed to fetch fare snapshot", err)
+		}
+		breakdown := pricingservice.BuildRideFareBreakdownFromRide(ride, viewer)
+		breakdown.Insurance = s.insuranceReference(ctx, rideID)
+		return breakdown, nil
+	}
+
+	breakdown := pricingservice.BuildRideFareBreakdown(ride, snapshot, viewer)
+	breakdown.Insurance = s.insuranceReference(ctx, rideID)
+	return breakdown, nil
+}
+
 func (s *service) GetRide(ctx context.Context, userID, rideID string) (*dto.RideResponse, error) {
 	cacheKey := fmt.Sprintf("ride:active:%s", rideID)
 	var cached models.Ride
@@ -2229,11 +2450,14 @@ func (s *service) GetRide(ctx context.Context, userID, rideID string) (*dto.Ride
 				}
 			}
 
-			return response, nil
+			return s.withRoute(ctx, response, &cached), nil
 		}
 	}
 
 	ride, err := s.repo.FindRideByID(ctx, rideID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ride, err = s.repo.FindArchivedRideByID(ctx, rideID)
+	}
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, response.NotFoundError("Ride")
@@ -2257,7 +2481,7 @@ func (s *service) GetRide(ctx context.Context, userID, rideID string) (*dto.Ride
 		}
 	}
 
-	return response, nil
+	return s.withRoute(ctx, response, ride), nil
 }
 
 func (s *service) GetActiveRide(ctx context.Context, userID, role string) (*dto.RideResponse, error) {
@@ -2357,14 +2581,12 @@ func (s *service) CancelRide(ctx context.Context, userID, rideID string, req dto
 	}
 
 	if ride.Status == "completed" {
-		return response.BadRequest("Cannot cancel a completed ride")
+		return response.CodedError(response.CodeRideAlreadyCompleted, "")
 	}
 	if ride.Status == "cancelled" {
-		return response.BadRequest("Ride was already cancelled")
+		return response.CodedError(response.CodeRideAlreadyCancelled, "")
 	}
 
-	var riderCancellationFee float64
-	var driverPenalty float64
 	cancelledBy := "rider"
 	originalStatus := ride.Status
 
@@ -2372,36 +2594,29 @@ func (s *service) CancelRide(ctx context.Context, userID, rideID string, req dto
 		cancelledBy = "driver"
 	}
 
-	switch ride.Status {
-	case "searching":
-		riderCancellationFee = 0.0
-		driverPenalty = 0.0
-	case "accepted", "arrived":
-		if isRider {
-			riderCancellationFee = 2.0
-		} else {
-			driverPenalty = 3.0
-		}
-	case "started":
-		if isRider {
-			riderCancellationFee = 5.0
-			driverPenalty = 0.0
-		} else {
-			driverPenalty = 10.0
-			riderCancellationFee = 0.0
-		}
-	}
+	cancelledAt := time.Now()
+	outcome := s.cancellationOutcome(ctx, ride, isRider, cancelledAt)
+	riderCancellationFee := outcome.CustomerFee
+	driverPenalty := outcome.DriverPenalty
 
 	ride.Status = "cancelled"
 	ride.CancellationReason = req.Reason
 	ride.CancelledBy = &cancelledBy
-	ride.CancelledAt = &time.Time{}
-	*ride.CancelledAt = time.Now()
+	ride.CancelledAt = &cancelledAt
 
 	if err := s.repo.UpdateRide(ctx, ride); err != nil {
 		return response.InternalServerError("Failed to cancel ride", err)
 	}
 
+	livemetrics.RideEnded(ctx, rideID)
+	if isDriver && driverProfileID != "" {
+		s.refreshDispatchStats(driverProfileID)
+	}
+	s.releaseFareSplit(ctx, ride)
+	if ride.StartedAt != nil {
+		s.endInsuranceCoverage(ctx, rideID, *ride.CancelledAt)
+	}
+
 	logger.Info("ride cancellation initiated",
 		"rideID", rideID,
 		"cancelledBy", cancelledBy,
@@ -2412,6 +2627,8 @@ func (s *service) CancelRide(ctx context.Context, userID, rideID string, req dto
 		"rideStatus", ride.Status,
 		"riderCancellationFee", riderCancellationFee,
 		"driverPenalty", driverPenalty,
+		"cancellationPolicy", outcome.PolicyName,
+		"cancellationRule", outcome.Rule,
 	)
 
 	var driver *models.DriverProfile
@@ -2435,6 +2652,7 @@ func (s *service) CancelRide(ctx context.Context, userID, rideID string, req dto
 			if _, err := s.walletService.CaptureHold(ctx, ride.RiderID, walletdto.CaptureHoldRequest{
 				HoldID:      *ride.WalletHoldID,
 				Amount:      &riderCancellationFee,
+				Currency:    ride.Currency,
 				Description: "Cancellation fee for ongoing ride",
 			}); err != nil {
 				logger.Error("failed to capture ongoing ride cancellation fee", "error", err, "rideID", rideID)
@@ -2518,6 +2736,7 @@ func (s *service) CancelRide(ctx context.Context, userID, rideID string, req dto
 			if _, err := s.walletService.CaptureHold(ctx, ride.RiderID, walletdto.CaptureHoldRequest{
 				HoldID:      *ride.WalletHoldID,
 				Amount:      &riderCancellationFee,
+				Currency:    ride.Currency,
 				Description: "Cancellation fee",
 			}); err != nil {
 				logger.Error("failed to capture cancellation fee", "error", err, "rideID", rideID)
@@ -2551,11 +2770,15 @@ func (s *service) CancelRide(ctx context.Context, userID, rideID string, req dto
 			}); err != nil {
 				logger.Error("failed to release hold", "error", err, "rideID", rideID)
 			}
+			penaltyReason := "cancelled_accepted_ride"
+			if outcome.Rule == cancellation.RuleDriverNoShow {
+				penaltyReason = "driver_no_show"
+			}
 			_, err := s.walletService.DeductPenalty(
 				ctx,
 				driver.UserID,
 				driverPenalty,
-				"cancelled_accepted_ride",
+				penaltyReason,
 				rideID,
 			)
 			if err != nil {
@@ -2618,6 +2841,7 @@ func (s *service) CancelRide(ctx context.Context, userID, rideID string, req dto
 		websocketutil.SendToUser(driver.UserID, websocket.TypeRideCancelled, map[string]interface{}{
 			"rideId":       rideID,
 			"message":      "Ride was cancelled by rider",
+			"messageKey":   "ws.ride.cancelled_by_rider",
 			"reason":       req.Reason,
 			"compensation": riderCancellationFee,
 			"timestamp":    time.Now().UTC(),
@@ -2686,86 +2910,54 @@ func (s *service) CancelRide(ctx context.Context, userID, rideID string, req dto
 	return nil
 }
 
-func (s *service) TriggerSOS(ctx context.Context, riderID, rideID string, latitude, longitude float64) error {
-	ride, err := s.repo.FindRideByID(ctx, rideID)
-	if err != nil {
-		return response.NotFoundError("Ride")
-	}
-
-	if ride.RiderID != riderID {
-		return response.ForbiddenError("Not authorized to trigger SOS for this ride")
-	}
-
-	if ride.Status != "started" && ride.Status != "accepted" && ride.Status != "arrived" {
-		return response.BadRequest("SOS can only be triggered during an active ride")
-	}
-
+// TriggerSOS raises a safety incident for the rider or driver of an active
+// ride. The SOS service alerts admins and emergency contacts; the ride event
+// lets the notification pipeline follow up.
+func (s *service) TriggerSOS(ctx context.Context, userID, rideID string, req sosdto.TriggerRideSOSRequest) (*sosdto.SOSAlertResponse, error) {
 	if s.sosService == nil {
 		logger.Warn("SOS service not initialized, logging emergency only",
-			"riderID", riderID,
+			"userID", userID,
 			"rideID", rideID,
-			"location", map[string]float64{"lat": latitude, "lon": longitude},
+			"location", map[string]float64{"lat": req.Latitude, "lon": req.Longitude},
 		)
-		websocketutil.BroadcastToAll(websocket.TypeSOSAlert, map[string]interface{}{
+		websocketutil.BroadcastToRole("admin", websocket.TypeSOSAlert, map[string]interface{}{
 			"type":      "sos_emergency",
 			"rideId":    rideID,
-			"riderId":   riderID,
-			"latitude":  latitude,
-			"longitude": longitude,
+			"userId":    userID,
+			"latitude":  req.Latitude,
+			"longitude": req.Longitude,
 			"timestamp": time.Now().UTC(),
 		})
-		return nil
+		return nil, nil
 	}
 
-	sosReq := sosdto.TriggerSOSRequest{
-		RideID:    &rideID,
-		Latitude:  latitude,
-		Longitude: longitude,
+	alert, err := s.sosService.TriggerRideSOS(ctx, userID, rideID, req)
+	if err != nil {
+		return nil, err
 	}
 
-	alert, err := s.sosService.TriggerSOS(ctx, riderID, sosReq)
+	ride, err := s.repo.FindRideByID(ctx, rideID)
 	if err != nil {
-		logger.Error("failed to trigger SOS",
-			"error", err,
-			"riderID", riderID,
-			"rideID", rideID,
-		)
-		return response.InternalServerError("Failed to trigger SOS", err)
+		logger.Warn("failed to load ride for SOS event", "error", err, "rideID", rideID)
+		return alert, nil
+	}
+	driverID := ""
+	if ride.DriverID != nil {
+		driverID = *ride.DriverID
 	}
 
-	websocketutil.BroadcastToAll(websocket.TypeSOSAlert, map[string]interface{}{
-		"alertId":   alert.ID,
-		"rideId":    rideID,
-		"riderId":   riderID,
-		"latitude":  latitude,
-		"longitude": longitude,
-		"severity":  "critical",
-		"timestamp": time.Now().UTC(),
-	})
-
-	logger.Warn("SOS ALERT TRIGGERED",
-		"alertId", alert.ID,
-		"riderID", riderID,
-		"rideID", rideID,
-		"latitude", latitude,
-		"longitude", longitude,
-	)
-
-	s.publishRideEvent(ctx, notificationsmodule.EventSOSTriggered, rideID, riderID, "", map[string]interface{}{
-		"alertId":   alert.ID,
-		"rideId":    rideID,
-		"riderId":   riderID,
-		"latitude":  latitude,
-		"longitude": longitude,
-		"severity":  "critical",
-		"timestamp": time.Now().UTC(),
+	s.publishRideEvent(ctx, notificationsmodule.EventSOSTriggered, rideID, ride.RiderID, driverID, map[string]interface{}{
+		"alertId":      alert.ID,
+		"rideId":       rideID,
+		"reporterId":   userID,
+		"reporterRole": alert.ReporterRole,
+		"latitude":     req.Latitude,
+		"longitude":    req.Longitude,
+		"severity":     alert.Severity,
+		"timestamp":    time.Now().UTC(),
 	})
 
-	return nil
-}
-
-func calculateDistance(lat1, lon1, lat2, lon2 float64) float64 {
-	return location.HaversineDistance(lat1, lon1, lat2, lon2)
+	return alert, nil
 }
 
 func (s *service) publishRideEvent(ctx context.Context, eventType notificationsmodule.EventType, rideID, riderID, driverID string, additionalData map[string]interface{}) {
@@ -0,0 +1,56 @@
+package rides
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/rides/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+// defaultMatchingRadii are searched, in km, in cities that do not set their own.
+var defaultMatchingRadii = []float64{3.0, 5.0, 8.0}
+
+// ServiceAreas knows the operating cities. Without one every pickup uses the
+// default search radii and the platform's dispatch settings, and vehicle
+// types are only checked by pricing.
+type ServiceAreas interface {
+	MatchingRadii(ctx context.Context, lat, lon float64) []float64
+	DispatchSettings(ctx context.Context, lat, lon float64, vehicleTypeID string) models.DispatchSettings
+	VehicleCapacity(ctx context.Context, lat, lon float64, vehicleTypeID string) (seats, luggage int, err error)
+}
+
+func (s *service) SetServiceAreas(areas ServiceAreas) {
+	s.serviceAreas = areas
+}
+
+func (s *service) matchingRadii(ctx context.Context, lat, lon float64) []float64 {
+	if s.serviceAreas != nil {
+		if radii := s.serviceAreas.MatchingRadii(ctx, lat, lon); len(radii) > 0 {
+			return radii
+		}
+	}
+	return defaultMatchingRadii
+}
+
+// checkVehicleType makes sure the requested vehicle type is offered in the
+// pickup city and can take the rider's party and bags.
+func (s *service) checkVehicleType(ctx context.Context, req dto.CreateRideRequest) error {
+	if s.serviceAreas == nil {
+		return nil
+	}
+	seats, luggage, err := s.serviceAreas.VehicleCapacity(ctx, req.PickupLat, req.PickupLon, req.VehicleTypeID)
+	if err != nil {
+		return err
+	}
+	if req.Passengers > seats {
+		return response.CodedError(response.CodeRideOverCapacity,
+			fmt.Sprintf("This vehicle type takes at most %d passengers", seats))
+	}
+	if req.Luggage > luggage {
+		return response.CodedError(response.CodeRideOverCapacity,
+			fmt.Sprintf("This vehicle type takes at most %d bags", luggage))
+	}
+	return nil
+}
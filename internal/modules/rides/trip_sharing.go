@@ -0,0 +1,167 @@
+package rides
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/rides/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+// Rides can be shared from the moment they are requested until they end.
+var shareableRideStatuses = map[string]bool{
+	"searching": true,
+	"waiting":   true,
+	"accepted":  true,
+	"arrived":   true,
+	"started":   true,
+}
+
+// The driver is only placed on the map once they are on their way.
+var trackableRideStatuses = map[string]bool{
+	"accepted": true,
+	"arrived":  true,
+	"started":  true,
+}
+
+func (s *service) ConfigureTripSharing(cfg config.TripSharingConfig) {
+	s.tripSharing = &cfg
+}
+
+// ShareTrip signs a link that lets anyone holding it follow the rider's trip
+// without logging in. Links are not stored; each one stops working when it
+// expires.
+func (s *service) ShareTrip(ctx context.Context, riderID, rideID string) (*dto.TripShareLinkResponse, error) {
+	if s.tripSharing == nil || s.tripSharing.SigningSecret == "" {
+		return nil, response.ServiceUnavailable("Trip sharing is not configured")
+	}
+
+	ride, err := s.repo.FindRideByID(ctx, rideID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Ride")
+		}
+		return nil, response.InternalServerError("Failed to fetch ride", err)
+	}
+	if ride.RiderID != riderID {
+		return nil, response.ForbiddenError("Only the rider can share this trip")
+	}
+	if !shareableRideStatuses[ride.Status] {
+		return nil, response.BadRequest("Only active rides can be shared")
+	}
+
+	expiresAt := time.Now().Add(s.tripSharing.LinkTTL).Truncate(time.Second)
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	token := fmt.Sprintf("%s.%s.%s", ride.ID, expires, s.signTripShare(ride.ID, expires))
+
+	logger.Info("trip share link created", "rideID", ride.ID, "riderID", riderID, "expiresAt", expiresAt)
+
+	base := s.tripSharing.PublicBaseURL + "/api/v1/trips/shared/" + token
+	return &dto.TripShareLinkResponse{
+		RideID:    ride.ID,
+		Token:     token,
+		URL:       base,
+		LiveURL:   base + "/live",
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// GetSharedTrip returns what a friend following a shared link may see: where
+// the trip is going, who is driving and where they are now. Contact details,
+// fares and the rider's PIN are never included.
+func (s *service) GetSharedTrip(ctx context.Context, token string) (*dto.SharedTripResponse, error) {
+	if s.tripSharing == nil || s.tripSharing.SigningSecret == "" {
+		return nil, response.ServiceUnavailable("Trip sharing is not configured")
+	}
+
+	rideID, expiresAt, ok := s.parseTripShareToken(token)
+	if !ok {
+		return nil, response.ForbiddenError("Trip link is invalid or has expired")
+	}
+
+	ride, err := s.repo.FindRideByID(ctx, rideID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Trip")
+		}
+		return nil, response.InternalServerError("Failed to fetch trip", err)
+	}
+
+	resp := dto.ToSharedTripResponse(ride)
+	resp.IsActive = shareableRideStatuses[ride.Status]
+	resp.ExpiresAt = expiresAt
+	resp.UpdateIntervalSeconds = int(s.tripSharing.UpdateInterval.Seconds())
+
+	if trackableRideStatuses[ride.Status] {
+		resp.DriverLocation = s.sharedDriverLocation(ctx, ride)
+	}
+
+	return resp, nil
+}
+
+// sharedDriverLocation looks the driver up under their user ID and then their
+// profile ID, since location updates are keyed by either.
+func (s *service) sharedDriverLocation(ctx context.Context, ride *models.Ride) *dto.SharedTripLocation {
+	if ride.DriverID == nil {
+		return nil
+	}
+
+	ids := []string{*ride.DriverID}
+	if ride.DriverProfile != nil && ride.DriverProfile.ID != "" {
+		ids = append(ids, ride.DriverProfile.ID)
+	}
+
+	for _, id := range ids {
+		loc, err := s.trackingService.GetDriverLocation(ctx, id)
+		if err != nil || loc == nil {
+			continue
+		}
+		return &dto.SharedTripLocation{
+			Latitude:  loc.Latitude,
+			Longitude: loc.Longitude,
+			Heading:   loc.Heading,
+			UpdatedAt: loc.Timestamp,
+		}
+	}
+	return nil
+}
+
+func (s *service) signTripShare(rideID, expires string) string {
+	mac := hmac.New(sha256.New, []byte(s.tripSharing.SigningSecret))
+	mac.Write([]byte("trip:" + rideID + ":" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *service) parseTripShareToken(token string) (string, time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", time.Time{}, false
+	}
+	rideID, expires, signature := parts[0], parts[1], parts[2]
+
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > unix {
+		return "", time.Time{}, false
+	}
+
+	given, err := hex.DecodeString(signature)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	expected, _ := hex.DecodeString(s.signTripShare(rideID, expires))
+	if !hmac.Equal(given, expected) {
+		return "", time.Time{}, false
+	}
+	return rideID, time.Unix(unix, 0), true
+}
@@ -0,0 +1,201 @@
+package rides
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/rides/dto"
+	"github.com/umar5678/go-backend/internal/utils/location"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+	websocketutil "github.com/umar5678/go-backend/internal/websocket/websocketutils"
+	"gorm.io/gorm"
+)
+
+const (
+	// Fixes worse than this are too coarse to measure a route with.
+	maxTrackPointAccuracyM = 100
+	// A jump between two fixes faster than this is a GPS glitch, not driving.
+	maxTrackSpeedKmh = 200
+)
+
+func (s *service) ConfigureTripVerification(cfg config.TripVerificationConfig) {
+	s.tripCheck = &cfg
+}
+
+// verifyTrip measures the ride from its track points and start time and
+// records how far the driver's reported distance and duration are from it.
+// The fare is still charged on the reported values; a ride out of tolerance
+// is flagged for an admin to review instead.
+func (s *service) verifyTrip(ctx context.Context, ride *models.Ride, req dto.CompleteRideRequest, completedAt time.Time) {
+	if s.tripCheck == nil || ride.StartedAt == nil || ride.DriverID == nil {
+		return
+	}
+	cfg := s.tripCheck
+
+	points, err := s.repo.FindRideTrackPoints(ctx, ride.ID, *ride.StartedAt)
+	if err != nil {
+		logger.Error("failed to load ride track points", "error", err, "rideID", ride.ID)
+		return
+	}
+
+	trackedDuration := int(completedAt.Sub(*ride.StartedAt).Seconds())
+	verification := &models.RideTripVerification{
+		RideID:               ride.ID,
+		DriverID:             *ride.DriverID,
+		ReportedDistanceKm:   req.ActualDistance,
+		ReportedDurationSec:  req.ActualDuration,
+		TrackedDurationSec:   trackedDuration,
+		TrackPointCount:      len(points),
+		DurationDeviationPct: deviationPct(float64(req.ActualDuration), float64(trackedDuration)),
+		Reasons:              pq.StringArray{},
+	}
+
+	if outOfTolerance(float64(req.ActualDuration), float64(trackedDuration), cfg.DurationTolerancePct, cfg.DurationTolerance.Seconds()) {
+		verification.Reasons = append(verification.Reasons, "duration_mismatch")
+	}
+
+	distanceMeasured := len(points) >= cfg.MinTrackPoints
+	if distanceMeasured {
+		tracked := trackedDistanceKm(ride, points, req.DriverLat, req.DriverLon)
+		verification.TrackedDistanceKm = math.Round(tracked*100) / 100
+		verification.DistanceDeviationPct = deviationPct(req.ActualDistance, tracked)
+		if outOfTolerance(req.ActualDistance, tracked, cfg.DistanceTolerancePct, cfg.DistanceToleranceKm) {
+			verification.Reasons = append(verification.Reasons, "distance_mismatch")
+		}
+	}
+
+	switch {
+	case len(verification.Reasons) > 0:
+		verification.Status = models.TripVerificationFlagged
+	case !distanceMeasured:
+		verification.Status = models.TripVerificationInsufficient
+	default:
+		verification.Status = models.TripVerificationVerified
+	}
+
+	if err := s.repo.CreateTripVerification(ctx, verification); err != nil {
+		logger.Error("failed to record trip verification", "error", err, "rideID", ride.ID)
+		return
+	}
+
+	if verification.Status != models.TripVerificationFlagged {
+		return
+	}
+
+	logger.Warn("ride flagged for trip review",
+		"rideID", ride.ID,
+		"driverID", verification.DriverID,
+		"reasons", []string(verification.Reasons),
+		"reportedDistanceKm", verification.ReportedDistanceKm,
+		"trackedDistanceKm", verification.TrackedDistanceKm,
+		"reportedDurationSec", verification.ReportedDurationSec,
+		"trackedDurationSec", verification.TrackedDurationSec,
+	)
+
+	if err := websocketutil.BroadcastToRole("admin", websocket.TypeRideTripFlagged, map[string]interface{}{
+		"verificationId": verification.ID,
+		"rideId":         ride.ID,
+		"driverId":       verification.DriverID,
+		"reasons":        []string(verification.Reasons),
+		"timestamp":      time.Now().UTC(),
+	}); err != nil {
+		logger.Warn("failed to notify admins of flagged ride", "error", err, "rideID", ride.ID)
+	}
+}
+
+func (s *service) ListTripVerifications(ctx context.Context, req dto.ListTripVerificationsRequest) ([]*dto.TripVerificationResponse, int64, error) {
+	verifications, total, err := s.repo.ListTripVerifications(ctx, req.Status, req.Page, req.Limit)
+	if err != nil {
+		return nil, 0, response.InternalServerError("Failed to list trip verifications", err)
+	}
+
+	result := make([]*dto.TripVerificationResponse, 0, len(verifications))
+	for _, v := range verifications {
+		result = append(result, dto.ToTripVerificationResponse(v))
+	}
+	return result, total, nil
+}
+
+// ReviewTripVerification closes a flagged ride after an admin has looked at it.
+// Any fare correction is made separately; the note records the outcome.
+func (s *service) ReviewTripVerification(ctx context.Context, adminID, id string, req dto.ReviewTripVerificationRequest) (*dto.TripVerificationResponse, error) {
+	verification, err := s.repo.FindTripVerificationByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Trip verification")
+		}
+		return nil, response.InternalServerError("Failed to get trip verification", err)
+	}
+
+	if verification.Status != models.TripVerificationFlagged {
+		return nil, response.BadRequest("Only flagged rides can be reviewed")
+	}
+
+	now := time.Now()
+	verification.Status = models.TripVerificationReviewed
+	verification.ReviewedBy = &adminID
+	verification.ReviewNote = req.Note
+	verification.ReviewedAt = &now
+
+	if err := s.repo.UpdateTripVerification(ctx, verification); err != nil {
+		return nil, response.InternalServerError("Failed to review trip verification", err)
+	}
+
+	logger.Info("flagged ride reviewed", "verificationID", id, "rideID", verification.RideID, "adminID", adminID)
+
+	return dto.ToTripVerificationResponse(verification), nil
+}
+
+// trackedDistanceKm follows the ride from the pickup through its usable track
+// points to where the driver completed it.
+func trackedDistanceKm(ride *models.Ride, points []*models.RideTrackPoint, endLat, endLon float64) float64 {
+	total := 0.0
+	lastLat, lastLon := ride.PickupLat, ride.PickupLon
+	var lastAt time.Time
+
+	for _, p := range points {
+		if p.Accuracy > maxTrackPointAccuracyM {
+			continue
+		}
+		leg := location.HaversineDistance(lastLat, lastLon, p.Latitude, p.Longitude)
+		if !lastAt.IsZero() {
+			if hours := p.RecordedAt.Sub(lastAt).Hours(); hours > 0 && leg/hours > maxTrackSpeedKmh {
+				continue
+			}
+		}
+		total += leg
+		lastLat, lastLon, lastAt = p.Latitude, p.Longitude, p.RecordedAt
+	}
+
+	return total + location.HaversineDistance(lastLat, lastLon, endLat, endLon)
+}
+
+// outOfTolerance reports whether reported strays from measured by more than
+// both the percentage and the absolute allowance.
+func outOfTolerance(reported, measured, tolerancePct, toleranceAbs float64) bool {
+	diff := math.Abs(reported - measured)
+	if diff <= toleranceAbs {
+		return false
+	}
+	if measured <= 0 {
+		return true
+	}
+	return diff/measured*100 > tolerancePct
+}
+
+func deviationPct(reported, measured float64) float64 {
+	if measured <= 0 {
+		if reported > 0 {
+			return 100
+		}
+		return 0
+	}
+	return math.Round((reported-measured)/measured*10000) / 100
+}
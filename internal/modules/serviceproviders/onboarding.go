@@ -0,0 +1,101 @@
+package serviceproviders
+
+import (
+	"strings"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+// OnboardingRequirement is a document a provider must upload before submitting
+// for review. Any of DocumentTypes satisfies it.
+type OnboardingRequirement struct {
+	Key           string
+	Label         string
+	DocumentTypes []string
+}
+
+var RequiredOnboardingDocuments = []OnboardingRequirement{
+	{Key: "id_document", Label: "Government ID", DocumentTypes: []string{"aadhaar", "aadhaar-card"}},
+	{Key: "profile_photo", Label: "Profile photo", DocumentTypes: []string{"profile-photo", "profile_photo"}},
+}
+
+type RequirementStatus struct {
+	Key             string `json:"key"`
+	Label           string `json:"label"`
+	DocumentID      string `json:"documentId,omitempty"`
+	Status          string `json:"status"`
+	RejectionReason string `json:"rejectionReason,omitempty"`
+}
+
+type OnboardingStatus struct {
+	ProviderID            string              `json:"providerId"`
+	Status                string              `json:"status"`
+	IsVerified            bool                `json:"isVerified"`
+	BackgroundCheckStatus string              `json:"backgroundCheckStatus"`
+	Requirements          []RequirementStatus `json:"requirements"`
+	SubmittedForReviewAt  *time.Time          `json:"submittedForReviewAt,omitempty"`
+	ReviewedAt            *time.Time          `json:"reviewedAt,omitempty"`
+	RejectionReason       string              `json:"rejectionReason,omitempty"`
+	CanSubmit             bool                `json:"canSubmit"`
+	ReadyForApproval      bool                `json:"readyForApproval"`
+}
+
+// EvaluateOnboarding matches the provider's latest upload of each required
+// document type. docs must be ordered newest first.
+func EvaluateOnboarding(profile *models.ServiceProviderProfile, docs []*models.Document) *OnboardingStatus {
+	status := &OnboardingStatus{
+		ProviderID:            profile.ID,
+		Status:                string(profile.Status),
+		IsVerified:            profile.IsVerified,
+		BackgroundCheckStatus: string(profile.BackgroundCheckStatus),
+		Requirements:          make([]RequirementStatus, 0, len(RequiredOnboardingDocuments)),
+		SubmittedForReviewAt:  profile.SubmittedForReviewAt,
+		ReviewedAt:            profile.ReviewedAt,
+		RejectionReason:       profile.RejectionReason,
+	}
+	if status.BackgroundCheckStatus == "" {
+		status.BackgroundCheckStatus = string(models.BackgroundCheckNotStarted)
+	}
+
+	allUploaded, allVerified := true, true
+	for _, req := range RequiredOnboardingDocuments {
+		reqStatus := RequirementStatus{Key: req.Key, Label: req.Label, Status: "missing"}
+		if doc := latestDocument(docs, req.DocumentTypes); doc != nil {
+			reqStatus.DocumentID = doc.ID
+			reqStatus.Status = doc.Status
+			reqStatus.RejectionReason = doc.RejectionReason
+		}
+
+		switch reqStatus.Status {
+		case "missing", "rejected":
+			allUploaded = false
+			allVerified = false
+		case "verified":
+		default:
+			allVerified = false
+		}
+		status.Requirements = append(status.Requirements, reqStatus)
+	}
+
+	reviewable := profile.Status == models.SPStatusPendingApproval || profile.Status == models.SPStatusRejected
+	status.CanSubmit = reviewable && allUploaded &&
+		(profile.SubmittedForReviewAt == nil || profile.Status == models.SPStatusRejected)
+	status.ReadyForApproval = profile.Status == models.SPStatusPendingApproval &&
+		profile.SubmittedForReviewAt != nil &&
+		allVerified &&
+		profile.BackgroundCheckStatus == models.BackgroundCheckClear
+
+	return status
+}
+
+func latestDocument(docs []*models.Document, types []string) *models.Document {
+	for _, doc := range docs {
+		for _, t := range types {
+			if strings.EqualFold(doc.DocumentType, t) {
+				return doc
+			}
+		}
+	}
+	return nil
+}
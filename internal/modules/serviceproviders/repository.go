@@ -14,6 +14,8 @@ type Repository interface {
 	Update(ctx context.Context, profile *models.ServiceProviderProfile) error
 	List(ctx context.Context, filters map[string]interface{}, page, limit int) ([]*models.ServiceProviderProfile, int64, error)
 	UpdateStatus(ctx context.Context, id string, status models.ServiceProviderStatus) error
+	UpdateOnboarding(ctx context.Context, id string, updates map[string]interface{}) error
+	FindDocuments(ctx context.Context, providerID, userID string) ([]*models.Document, error)
 }
 
 type repository struct {
@@ -74,3 +76,21 @@ func (r *repository) UpdateStatus(ctx context.Context, id string, status models.
 		Where("id = ?", id).
 		Update("status", status).Error
 }
+
+func (r *repository) UpdateOnboarding(ctx context.Context, id string, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).
+		Model(&models.ServiceProviderProfile{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
+
+// FindDocuments returns the provider's documents newest first, including ones
+// uploaded before the provider profile existed.
+func (r *repository) FindDocuments(ctx context.Context, providerID, userID string) ([]*models.Document, error) {
+	var docs []*models.Document
+	err := r.db.WithContext(ctx).
+		Where("service_provider_id = ? OR (service_provider_id IS NULL AND user_id = ?)", providerID, userID).
+		Order("created_at DESC").
+		Find(&docs).Error
+	return docs, err
+}
@@ -2,8 +2,10 @@ package serviceproviders
 
 import (
 	"context"
+	"time"
 
 	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/notifications"
 	"github.com/umar5678/go-backend/internal/utils/logger"
 	"github.com/umar5678/go-backend/internal/utils/response"
 )
@@ -13,14 +15,25 @@ type Service interface {
 	GetProfile(ctx context.Context, userID string) (*models.ServiceProviderProfile, error)
 	UpdateProfile(ctx context.Context, userID string, updates map[string]interface{}) (*models.ServiceProviderProfile, error)
 	List(ctx context.Context, filters map[string]interface{}, page, limit int) ([]*models.ServiceProviderProfile, int64, error)
+
+	GetOnboardingStatus(ctx context.Context, providerID string) (*OnboardingStatus, error)
+	SubmitForReview(ctx context.Context, providerID string) (*OnboardingStatus, error)
 }
 
 type service struct {
-	repo Repository
+	repo          Repository
+	eventProducer notifications.EventProducer
 }
 
 func NewService(repo Repository) Service {
-	return &service{repo: repo}
+	return NewServiceWithNotifications(repo, nil)
+}
+
+func NewServiceWithNotifications(repo Repository, eventProducer notifications.EventProducer) Service {
+	return &service{
+		repo:          repo,
+		eventProducer: eventProducer,
+	}
 }
 
 func (s *service) CreateProfile(ctx context.Context, userID, serviceCategory string) (*models.ServiceProviderProfile, error) {
@@ -88,3 +101,86 @@ func (s *service) List(ctx context.Context, filters map[string]interface{}, page
 
 	return s.repo.List(ctx, filters, page, limit)
 }
+
+func (s *service) GetOnboardingStatus(ctx context.Context, providerID string) (*OnboardingStatus, error) {
+	profile, err := s.repo.FindByID(ctx, providerID)
+	if err != nil {
+		return nil, response.NotFoundError("Service provider profile")
+	}
+
+	docs, err := s.repo.FindDocuments(ctx, profile.ID, profile.UserID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to load provider documents", err)
+	}
+
+	return EvaluateOnboarding(profile, docs), nil
+}
+
+// SubmitForReview queues the provider for admin review once every required
+// document is uploaded. Rejected providers can resubmit after fixing them.
+func (s *service) SubmitForReview(ctx context.Context, providerID string) (*OnboardingStatus, error) {
+	profile, err := s.repo.FindByID(ctx, providerID)
+	if err != nil {
+		return nil, response.NotFoundError("Service provider profile")
+	}
+
+	docs, err := s.repo.FindDocuments(ctx, profile.ID, profile.UserID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to load provider documents", err)
+	}
+
+	status := EvaluateOnboarding(profile, docs)
+	if !status.CanSubmit {
+		switch {
+		case profile.Status != models.SPStatusPendingApproval && profile.Status != models.SPStatusRejected:
+			return nil, response.BadRequest("Your provider account is not awaiting review")
+		case profile.SubmittedForReviewAt != nil && profile.Status == models.SPStatusPendingApproval:
+			return nil, response.ConflictError("Your application is already under review")
+		default:
+			return nil, response.BadRequest("Upload all required documents before submitting for review")
+		}
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{
+		"status":                  models.SPStatusPendingApproval,
+		"submitted_for_review_at": now,
+		"rejection_reason":        "",
+	}
+	if profile.BackgroundCheckStatus == models.BackgroundCheckNotStarted || profile.BackgroundCheckStatus == "" {
+		updates["background_check_status"] = models.BackgroundCheckPending
+		updates["background_check_updated_at"] = now
+		profile.BackgroundCheckStatus = models.BackgroundCheckPending
+		profile.BackgroundCheckUpdatedAt = &now
+	}
+
+	if err := s.repo.UpdateOnboarding(ctx, providerID, updates); err != nil {
+		logger.Error("failed to submit provider for review", "error", err, "providerID", providerID)
+		return nil, response.InternalServerError("Failed to submit for review", err)
+	}
+
+	profile.Status = models.SPStatusPendingApproval
+	profile.SubmittedForReviewAt = &now
+	profile.RejectionReason = ""
+
+	s.publishEvent(ctx, notifications.EventProviderOnboardingSubmitted, map[string]interface{}{
+		"provider_id": providerID,
+		"user_id":     profile.UserID,
+		"timestamp":   now,
+	})
+
+	logger.Info("provider submitted for review", "providerID", providerID, "userID", profile.UserID)
+	return EvaluateOnboarding(profile, docs), nil
+}
+
+func (s *service) publishEvent(ctx context.Context, eventType notifications.EventType, data map[string]interface{}) {
+	if s.eventProducer == nil {
+		return
+	}
+
+	go func() {
+		if err := s.eventProducer.PublishEvent(ctx, eventType, data); err != nil {
+			logger.Error("failed to publish service provider event", "error", err, "eventType", eventType)
+		}
+	}()
+}
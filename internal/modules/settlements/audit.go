@@ -0,0 +1,37 @@
+package settlements
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/audit"
+)
+
+func (s *service) SetAuditLogger(auditLogger audit.Recorder) {
+	s.auditLogger = auditLogger
+}
+
+func (s *service) auditSettlement(ctx context.Context, adminID, action string, before, after *models.ProviderSettlement, reason string) {
+	if s.auditLogger == nil {
+		return
+	}
+	s.auditLogger.Record(ctx, audit.Entry{
+		ActorID:    adminID,
+		ActorRole:  string(models.RoleAdmin),
+		Action:     action,
+		Category:   models.AuditCategoryFinancial,
+		EntityType: "provider_settlement",
+		EntityID:   after.ID,
+		Before:     settlementSnapshot(before),
+		After:      settlementSnapshot(after),
+		Reason:     reason,
+	})
+}
+
+func settlementSnapshot(settlement *models.ProviderSettlement) map[string]interface{} {
+	return map[string]interface{}{
+		"status":      settlement.Status,
+		"totalAmount": settlement.TotalAmount,
+		"itemCount":   settlement.ItemCount,
+	}
+}
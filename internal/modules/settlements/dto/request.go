@@ -0,0 +1,35 @@
+package dto
+
+import "time"
+
+type ListSettlementsQuery struct {
+	Page           int    `form:"page" binding:"omitempty,min=1"`
+	Limit          int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Status         string `form:"status" binding:"omitempty,oneof=pending_approval approved paid rejected"`
+	ProviderUserID string `form:"providerUserId" binding:"omitempty,uuid"`
+}
+
+func (q *ListSettlementsQuery) SetDefaults() {
+	if q.Page == 0 {
+		q.Page = 1
+	}
+	if q.Limit == 0 {
+		q.Limit = 20
+	}
+}
+
+type ApproveSettlementRequest struct {
+	Note string `json:"note" binding:"max=500"`
+}
+
+// RejectSettlementRequest sends the settlement's payouts back to accrue; they
+// are picked up again by the next weekly run.
+type RejectSettlementRequest struct {
+	Note string `json:"note" binding:"required,min=3,max=500"`
+}
+
+// GenerateSettlementsRequest closes the week (Monday to Monday, UTC) ending
+// on or before PeriodEnd, or the last full week when it is not given.
+type GenerateSettlementsRequest struct {
+	PeriodEnd *time.Time `json:"periodEnd"`
+}
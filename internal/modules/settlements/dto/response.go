@@ -0,0 +1,85 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+type SettlementResponse struct {
+	ID             string                  `json:"id"`
+	ProviderUserID string                  `json:"providerUserId"`
+	ProviderID     *string                 `json:"providerId,omitempty"`
+	PeriodStart    time.Time               `json:"periodStart"`
+	PeriodEnd      time.Time               `json:"periodEnd"`
+	ItemCount      int                     `json:"itemCount"`
+	TotalAmount    float64                 `json:"totalAmount"`
+	Status         models.SettlementStatus `json:"status"`
+	ReviewedBy     *string                 `json:"reviewedBy,omitempty"`
+	ReviewNote     string                  `json:"reviewNote,omitempty"`
+	ReviewedAt     *time.Time              `json:"reviewedAt,omitempty"`
+	PaidAt         *time.Time              `json:"paidAt,omitempty"`
+	CreatedAt      time.Time               `json:"createdAt"`
+	// Items is only filled in on the detail endpoints.
+	Items []SettlementItemResponse `json:"items,omitempty"`
+}
+
+type SettlementItemResponse struct {
+	ID              string    `json:"id"`
+	ReferenceType   string    `json:"referenceType"`
+	ReferenceID     string    `json:"referenceId"`
+	ReferenceNumber string    `json:"referenceNumber,omitempty"`
+	Amount          float64   `json:"amount"`
+	Description     string    `json:"description"`
+	EarnedAt        time.Time `json:"earnedAt"`
+}
+
+// AccruedPayoutsResponse is what a provider has earned that is not in a
+// settlement yet.
+type AccruedPayoutsResponse struct {
+	ItemCount   int     `json:"itemCount"`
+	TotalAmount float64 `json:"totalAmount"`
+	MinPayout   float64 `json:"minPayout"`
+}
+
+type GenerateSettlementsResponse struct {
+	PeriodStart time.Time `json:"periodStart"`
+	PeriodEnd   time.Time `json:"periodEnd"`
+	Created     int       `json:"created"`
+}
+
+type ExportFile struct {
+	FileName    string
+	ContentType string
+	Data        []byte
+}
+
+func ToSettlementResponse(settlement *models.ProviderSettlement) *SettlementResponse {
+	resp := &SettlementResponse{
+		ID:             settlement.ID,
+		ProviderUserID: settlement.ProviderUserID,
+		ProviderID:     settlement.ProviderID,
+		PeriodStart:    settlement.PeriodStart,
+		PeriodEnd:      settlement.PeriodEnd,
+		ItemCount:      settlement.ItemCount,
+		TotalAmount:    settlement.TotalAmount,
+		Status:         settlement.Status,
+		ReviewedBy:     settlement.ReviewedBy,
+		ReviewNote:     settlement.ReviewNote,
+		ReviewedAt:     settlement.ReviewedAt,
+		PaidAt:         settlement.PaidAt,
+		CreatedAt:      settlement.CreatedAt,
+	}
+	for _, item := range settlement.Items {
+		resp.Items = append(resp.Items, SettlementItemResponse{
+			ID:              item.ID,
+			ReferenceType:   item.ReferenceType,
+			ReferenceID:     item.ReferenceID,
+			ReferenceNumber: item.ReferenceNumber,
+			Amount:          item.Amount,
+			Description:     item.Description,
+			EarnedAt:        item.EarnedAt,
+		})
+	}
+	return resp
+}
@@ -0,0 +1,252 @@
+package settlements
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/modules/settlements/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// ListSettlements godoc
+// @Summary List provider settlements (admin)
+// @Description Weekly provider settlements, newest period first
+// @Tags admin-settlements
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "pending_approval, approved, paid or rejected"
+// @Param providerUserId query string false "Provider user ID"
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Success 200 {object} response.Response{data=[]dto.SettlementResponse}
+// @Router /admin/settlements [get]
+func (h *Handler) ListSettlements(c *gin.Context) {
+	var query dto.ListSettlementsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+	query.SetDefaults()
+
+	settlements, total, err := h.service.ListSettlements(c.Request.Context(), query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Paginated(c, settlements, response.NewPaginationMeta(total, query.Page, query.Limit), "Settlements retrieved")
+}
+
+// GetSettlement godoc
+// @Summary Get a provider settlement with its order payouts (admin)
+// @Tags admin-settlements
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Settlement ID"
+// @Success 200 {object} response.Response{data=dto.SettlementResponse}
+// @Failure 404 {object} response.Response
+// @Router /admin/settlements/{id} [get]
+func (h *Handler) GetSettlement(c *gin.Context) {
+	settlement, err := h.service.GetSettlement(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, settlement, "Settlement retrieved")
+}
+
+// ExportSettlement godoc
+// @Summary Download a settlement report (admin)
+// @Description The settlement's order payouts and total as CSV
+// @Tags admin-settlements
+// @Security BearerAuth
+// @Produce text/csv
+// @Param id path string true "Settlement ID"
+// @Success 200 {file} file
+// @Failure 404 {object} response.Response
+// @Router /admin/settlements/{id}/report [get]
+func (h *Handler) ExportSettlement(c *gin.Context) {
+	file, err := h.service.ExportSettlement(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.FileName))
+	c.Data(http.StatusOK, file.ContentType, file.Data)
+}
+
+// GenerateSettlements godoc
+// @Summary Generate settlements for a week (admin)
+// @Description Closes the week (Monday to Monday, UTC) ending on or before periodEnd, or the last full week, for every provider whose unsettled payouts reach the minimum. Weeks already settled are skipped.
+// @Tags admin-settlements
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.GenerateSettlementsRequest false "Optional period end"
+// @Success 200 {object} response.Response{data=dto.GenerateSettlementsResponse}
+// @Failure 400 {object} response.Response
+// @Router /admin/settlements/generate [post]
+func (h *Handler) GenerateSettlements(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	var req dto.GenerateSettlementsRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(response.BadRequest("Invalid request body"))
+			return
+		}
+	}
+
+	result, err := h.service.GenerateForPeriod(c.Request.Context(), adminID.(string), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Settlements generated")
+}
+
+// ApproveSettlement godoc
+// @Summary Approve a settlement and credit the provider (admin)
+// @Description Credits the settlement total to the provider's wallet. If the credit fails it is retried in the background and the settlement stays approved until it lands.
+// @Tags admin-settlements
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Settlement ID"
+// @Param request body dto.ApproveSettlementRequest false "Optional note"
+// @Success 200 {object} response.Response{data=dto.SettlementResponse}
+// @Failure 400 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /admin/settlements/{id}/approve [post]
+func (h *Handler) ApproveSettlement(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	var req dto.ApproveSettlementRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(response.BadRequest("Invalid request body"))
+			return
+		}
+	}
+
+	settlement, err := h.service.ApproveSettlement(c.Request.Context(), adminID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, settlement, "Settlement approved")
+}
+
+// RejectSettlement godoc
+// @Summary Reject a settlement (admin)
+// @Description Releases the settlement's order payouts; they are settled again with the next weekly run.
+// @Tags admin-settlements
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Settlement ID"
+// @Param request body dto.RejectSettlementRequest true "Reason"
+// @Success 200 {object} response.Response{data=dto.SettlementResponse}
+// @Failure 400 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /admin/settlements/{id}/reject [post]
+func (h *Handler) RejectSettlement(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	var req dto.RejectSettlementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	settlement, err := h.service.RejectSettlement(c.Request.Context(), adminID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, settlement, "Settlement rejected")
+}
+
+// ListMySettlements godoc
+// @Summary List my settlements
+// @Tags provider-settlements
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "pending_approval, approved, paid or rejected"
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Success 200 {object} response.Response{data=[]dto.SettlementResponse}
+// @Router /provider/settlements [get]
+func (h *Handler) ListMySettlements(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var query dto.ListSettlementsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+	query.SetDefaults()
+
+	settlements, total, err := h.service.ListProviderSettlements(c.Request.Context(), userID.(string), query)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Paginated(c, settlements, response.NewPaginationMeta(total, query.Page, query.Limit), "Settlements retrieved")
+}
+
+// GetMyAccruedPayouts godoc
+// @Summary Get my payouts waiting for settlement
+// @Description Order payouts not in a settlement yet, and the minimum a week's total must reach to be settled
+// @Tags provider-settlements
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=dto.AccruedPayoutsResponse}
+// @Router /provider/settlements/accrued [get]
+func (h *Handler) GetMyAccruedPayouts(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	accrued, err := h.service.GetAccruedPayouts(c.Request.Context(), userID.(string))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, accrued, "Accrued payouts retrieved")
+}
+
+// GetMySettlement godoc
+// @Summary Get one of my settlements with its order payouts
+// @Tags provider-settlements
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Settlement ID"
+// @Success 200 {object} response.Response{data=dto.SettlementResponse}
+// @Failure 404 {object} response.Response
+// @Router /provider/settlements/{id} [get]
+func (h *Handler) GetMySettlement(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	settlement, err := h.service.GetProviderSettlement(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, settlement, "Settlement retrieved")
+}
@@ -0,0 +1,51 @@
+package settlements
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/modules/settlements/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+// ExportSettlement renders a settlement report as CSV: one row per order
+// payout, then a total row.
+func (s *service) ExportSettlement(ctx context.Context, id string) (*dto.ExportFile, error) {
+	settlement, err := s.findSettlement(ctx, id, true)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := [][]string{{"earned_at", "reference_type", "reference_id", "reference_number", "description", "amount"}}
+	for _, item := range settlement.Items {
+		rows = append(rows, []string{
+			item.EarnedAt.UTC().Format(time.RFC3339),
+			item.ReferenceType,
+			item.ReferenceID,
+			item.ReferenceNumber,
+			item.Description,
+			formatAmount(item.Amount),
+		})
+	}
+	rows = append(rows, []string{"", "", "", "", "total", formatAmount(settlement.TotalAmount)})
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(rows); err != nil {
+		return nil, response.InternalServerError("Failed to render settlement report", err)
+	}
+
+	return &dto.ExportFile{
+		FileName:    fmt.Sprintf("%s.csv", settlementNumber(settlement)),
+		ContentType: "text/csv; charset=utf-8",
+		Data:        buf.Bytes(),
+	}, nil
+}
+
+func formatAmount(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
@@ -0,0 +1,308 @@
+package settlements
+
+import (
+	"context"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/settlements/dto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// orderTables lists the reference types whose table carries a
+// settlement_status column shown to the provider.
+var orderTables = map[string]string{
+	"service_order": "service_orders",
+	"laundry_order": "laundry_orders",
+}
+
+// ProviderTotal sums a provider's payouts that are not in a settlement yet.
+type ProviderTotal struct {
+	ProviderUserID string
+	ProviderID     *string
+	ItemCount      int
+	TotalAmount    float64
+}
+
+type Repository interface {
+	// CreateItem stores an accrued payout and marks its order unsettled.
+	CreateItem(ctx context.Context, item *models.SettlementItem) error
+	// UnsettledTotals groups the payouts earned before the given time that
+	// are not in a settlement, per provider.
+	UnsettledTotals(ctx context.Context, before time.Time) ([]ProviderTotal, error)
+	UnsettledTotal(ctx context.Context, providerUserID string) (*ProviderTotal, error)
+	// CreateSettlement inserts the settlement and moves the provider's
+	// unsettled payouts earned before its period end into it. It reports
+	// false when the provider already has a settlement for the period.
+	CreateSettlement(ctx context.Context, settlement *models.ProviderSettlement) (bool, error)
+
+	FindByID(ctx context.Context, id string, withItems bool) (*models.ProviderSettlement, error)
+	List(ctx context.Context, query dto.ListSettlementsQuery) ([]*models.ProviderSettlement, int64, error)
+	ListByStatus(ctx context.Context, status models.SettlementStatus, limit int) ([]*models.ProviderSettlement, error)
+
+	// Transition saves the settlement's review fields and new status as long
+	// as it is still in from, and reports whether it was.
+	Transition(ctx context.Context, settlement *models.ProviderSettlement, from models.SettlementStatus) (bool, error)
+	// Reject is Transition for rejections; the items are released so the
+	// next run settles them again.
+	Reject(ctx context.Context, settlement *models.ProviderSettlement) (bool, error)
+	// MarkPaid records the payment and settles the orders that have nothing
+	// left outside a paid settlement.
+	MarkPaid(ctx context.Context, settlement *models.ProviderSettlement) error
+	// PayoutCredited reports whether the settlement's wallet credit has
+	// landed or was resolved by hand.
+	PayoutCredited(ctx context.Context, settlementID string) (bool, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) CreateItem(ctx context.Context, item *models.SettlementItem) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(item).Error; err != nil {
+			return err
+		}
+		return setOrderStatus(tx, item.ReferenceType, []string{item.ReferenceID}, models.OrderSettlementStatusUnsettled)
+	})
+}
+
+func (r *repository) UnsettledTotals(ctx context.Context, before time.Time) ([]ProviderTotal, error) {
+	var totals []ProviderTotal
+	err := r.db.WithContext(ctx).
+		Model(&models.SettlementItem{}).
+		Select("provider_user_id, (ARRAY_AGG(provider_id) FILTER (WHERE provider_id IS NOT NULL))[1] AS provider_id, COUNT(*) AS item_count, COALESCE(SUM(amount), 0) AS total_amount").
+		Where("settlement_id IS NULL AND earned_at < ?", before).
+		Group("provider_user_id").
+		Scan(&totals).Error
+	return totals, err
+}
+
+func (r *repository) UnsettledTotal(ctx context.Context, providerUserID string) (*ProviderTotal, error) {
+	total := &ProviderTotal{ProviderUserID: providerUserID}
+	err := r.db.WithContext(ctx).
+		Model(&models.SettlementItem{}).
+		Select("COUNT(*) AS item_count, COALESCE(SUM(amount), 0) AS total_amount").
+		Where("settlement_id IS NULL AND provider_user_id = ?", providerUserID).
+		Scan(total).Error
+	return total, err
+}
+
+func (r *repository) CreateSettlement(ctx context.Context, settlement *models.ProviderSettlement) (bool, error) {
+	created := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(settlement)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+
+		var items []models.SettlementItem
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("provider_user_id = ? AND settlement_id IS NULL AND earned_at < ?", settlement.ProviderUserID, settlement.PeriodEnd).
+			Find(&items).Error
+		if err != nil {
+			return err
+		}
+
+		ids := make([]string, len(items))
+		refs := map[string][]string{}
+		settlement.ItemCount = len(items)
+		settlement.TotalAmount = 0
+		for i, item := range items {
+			ids[i] = item.ID
+			refs[item.ReferenceType] = append(refs[item.ReferenceType], item.ReferenceID)
+			settlement.TotalAmount += item.Amount
+		}
+
+		if len(ids) > 0 {
+			if err := tx.Model(&models.SettlementItem{}).Where("id IN ?", ids).Update("settlement_id", settlement.ID).Error; err != nil {
+				return err
+			}
+		}
+		for refType, refIDs := range refs {
+			if err := setOrderStatus(tx, refType, refIDs, models.OrderSettlementStatusBatched); err != nil {
+				return err
+			}
+		}
+
+		created = true
+		return tx.Model(settlement).Updates(map[string]interface{}{
+			"item_count":   settlement.ItemCount,
+			"total_amount": settlement.TotalAmount,
+		}).Error
+	})
+	return created, err
+}
+
+func (r *repository) FindByID(ctx context.Context, id string, withItems bool) (*models.ProviderSettlement, error) {
+	db := r.db.WithContext(ctx)
+	if withItems {
+		db = db.Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("earned_at ASC")
+		})
+	}
+
+	var settlement models.ProviderSettlement
+	err := db.Where("id = ?", id).First(&settlement).Error
+	return &settlement, err
+}
+
+func (r *repository) List(ctx context.Context, query dto.ListSettlementsQuery) ([]*models.ProviderSettlement, int64, error) {
+	db := r.db.WithContext(ctx).Model(&models.ProviderSettlement{})
+	if query.Status != "" {
+		db = db.Where("status = ?", query.Status)
+	}
+	if query.ProviderUserID != "" {
+		db = db.Where("provider_user_id = ?", query.ProviderUserID)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var settlements []*models.ProviderSettlement
+	err := db.Order("period_end DESC, total_amount DESC").
+		Offset((query.Page - 1) * query.Limit).
+		Limit(query.Limit).
+		Find(&settlements).Error
+	return settlements, total, err
+}
+
+func (r *repository) ListByStatus(ctx context.Context, status models.SettlementStatus, limit int) ([]*models.ProviderSettlement, error) {
+	var settlements []*models.ProviderSettlement
+	err := r.db.WithContext(ctx).
+		Where("status = ?", status).
+		Order("updated_at ASC").
+		Limit(limit).
+		Find(&settlements).Error
+	return settlements, err
+}
+
+func (r *repository) Transition(ctx context.Context, settlement *models.ProviderSettlement, from models.SettlementStatus) (bool, error) {
+	return transition(r.db.WithContext(ctx), settlement, from)
+}
+
+func (r *repository) Reject(ctx context.Context, settlement *models.ProviderSettlement) (bool, error) {
+	rejected := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ok, err := transition(tx, settlement, models.SettlementStatusPendingApproval)
+		if err != nil || !ok {
+			return err
+		}
+
+		refs, err := itemReferences(tx, settlement.ID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&models.SettlementItem{}).Where("settlement_id = ?", settlement.ID).Update("settlement_id", nil).Error; err != nil {
+			return err
+		}
+		for refType, refIDs := range refs {
+			if err := setOrderStatus(tx, refType, refIDs, models.OrderSettlementStatusUnsettled); err != nil {
+				return err
+			}
+		}
+
+		rejected = true
+		return nil
+	})
+	return rejected, err
+}
+
+func (r *repository) MarkPaid(ctx context.Context, settlement *models.ProviderSettlement) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(settlement).Updates(map[string]interface{}{
+			"status":  settlement.Status,
+			"paid_at": settlement.PaidAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		refs, err := itemReferences(tx, settlement.ID)
+		if err != nil {
+			return err
+		}
+		for refType, refIDs := range refs {
+			table, ok := orderTables[refType]
+			if !ok {
+				continue
+			}
+			// A multi-session order may still have milestones waiting in a
+			// later settlement.
+			updates := map[string]interface{}{"settlement_status": models.OrderSettlementStatusSettled}
+			if refType == "service_order" {
+				updates["payout_status"] = models.OrderPayoutStatusPaid
+			}
+			err := tx.Table(table).
+				Where("id IN ?", refIDs).
+				Where(`NOT EXISTS (
+					SELECT 1 FROM settlement_items i
+					LEFT JOIN provider_settlements s ON s.id = i.settlement_id
+					WHERE i.reference_type = ? AND i.reference_id = `+table+`.id
+						AND (s.id IS NULL OR s.status <> ?))`, refType, models.SettlementStatusPaid).
+				Updates(updates).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *repository) PayoutCredited(ctx context.Context, settlementID string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.WalletTransaction{}).
+		Where("reference_type = ? AND reference_id = ?", settlementTxnType, settlementID).
+		Count(&count).Error
+	if err != nil || count > 0 {
+		return count > 0, err
+	}
+
+	// Resolved by hand, e.g. paid by bank transfer, so no wallet transaction.
+	err = r.db.WithContext(ctx).
+		Model(&models.PayoutCredit{}).
+		Where("reference_type = ? AND reference_id = ? AND status = ?", models.PayoutReferenceSettlement, settlementID, models.PayoutCreditStatusResolved).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func transition(db *gorm.DB, settlement *models.ProviderSettlement, from models.SettlementStatus) (bool, error) {
+	result := db.Model(&models.ProviderSettlement{}).
+		Where("id = ? AND status = ?", settlement.ID, from).
+		Updates(map[string]interface{}{
+			"status":      settlement.Status,
+			"reviewed_by": settlement.ReviewedBy,
+			"review_note": settlement.ReviewNote,
+			"reviewed_at": settlement.ReviewedAt,
+			"updated_at":  time.Now(),
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func itemReferences(db *gorm.DB, settlementID string) (map[string][]string, error) {
+	var items []models.SettlementItem
+	if err := db.Select("reference_type", "reference_id").Where("settlement_id = ?", settlementID).Find(&items).Error; err != nil {
+		return nil, err
+	}
+	refs := map[string][]string{}
+	for _, item := range items {
+		refs[item.ReferenceType] = append(refs[item.ReferenceType], item.ReferenceID)
+	}
+	return refs, nil
+}
+
+func setOrderStatus(db *gorm.DB, refType string, refIDs []string, status string) error {
+	table, ok := orderTables[refType]
+	if !ok || len(refIDs) == 0 {
+		return nil
+	}
+	return db.Table(table).Where("id IN ?", refIDs).Update("settlement_status", status).Error
+}
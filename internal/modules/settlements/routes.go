@@ -0,0 +1,29 @@
+package settlements
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/middleware"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
+	admin := router.Group("/admin/settlements")
+	admin.Use(authMiddleware)
+	admin.Use(middleware.RequireAdmin())
+	{
+		admin.GET("", handler.ListSettlements)
+		admin.POST("/generate", handler.GenerateSettlements)
+		admin.GET("/:id", handler.GetSettlement)
+		admin.GET("/:id/report", handler.ExportSettlement)
+		admin.POST("/:id/approve", handler.ApproveSettlement)
+		admin.POST("/:id/reject", handler.RejectSettlement)
+	}
+
+	provider := router.Group("/provider/settlements")
+	provider.Use(authMiddleware)
+	provider.Use(middleware.RequireServiceProvider())
+	{
+		provider.GET("", handler.ListMySettlements)
+		provider.GET("/accrued", handler.GetMyAccruedPayouts)
+		provider.GET("/:id", handler.GetMySettlement)
+	}
+}
@@ -0,0 +1,389 @@
+package settlements
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/audit"
+	"github.com/umar5678/go-backend/internal/modules/emails"
+	"github.com/umar5678/go-backend/internal/modules/settlements/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+const (
+	settlementPeriod     = 7 * 24 * time.Hour
+	reconcileBatchSize   = 50
+	settlementTxnType    = "provider_settlement"
+	settlementNumberDate = "20060102"
+)
+
+// Payouts credits provider wallets. It is satisfied by wallet.Service.
+type Payouts interface {
+	CreditProviderPayout(ctx context.Context, credit *models.PayoutCredit) (queued bool, err error)
+}
+
+type Service interface {
+	// AccruePayout holds an order payout for the provider's next settlement.
+	AccruePayout(ctx context.Context, credit *models.PayoutCredit) error
+	// GenerateSettlements closes the last full week (Monday to Monday, UTC)
+	// before now, creating a settlement for every provider whose unsettled
+	// payouts reach the minimum. Running it again for the same week is a
+	// no-op.
+	GenerateSettlements(ctx context.Context, now time.Time) (int, error)
+	// GenerateForPeriod runs GenerateSettlements on an admin's request, for
+	// a week that has already ended.
+	GenerateForPeriod(ctx context.Context, adminID string, req dto.GenerateSettlementsRequest) (*dto.GenerateSettlementsResponse, error)
+	// ReconcilePayments marks approved settlements paid once their queued
+	// wallet credit has gone through.
+	ReconcilePayments(ctx context.Context) error
+
+	ListSettlements(ctx context.Context, query dto.ListSettlementsQuery) ([]*dto.SettlementResponse, int64, error)
+	GetSettlement(ctx context.Context, id string) (*dto.SettlementResponse, error)
+	ApproveSettlement(ctx context.Context, adminID, id string, req dto.ApproveSettlementRequest) (*dto.SettlementResponse, error)
+	RejectSettlement(ctx context.Context, adminID, id string, req dto.RejectSettlementRequest) (*dto.SettlementResponse, error)
+	ExportSettlement(ctx context.Context, id string) (*dto.ExportFile, error)
+
+	ListProviderSettlements(ctx context.Context, providerUserID string, query dto.ListSettlementsQuery) ([]*dto.SettlementResponse, int64, error)
+	GetProviderSettlement(ctx context.Context, providerUserID, id string) (*dto.SettlementResponse, error)
+	GetAccruedPayouts(ctx context.Context, providerUserID string) (*dto.AccruedPayoutsResponse, error)
+
+	SetAuditLogger(auditLogger audit.Recorder)
+	SetMailer(mailer emails.Mailer)
+}
+
+type service struct {
+	repo        Repository
+	payouts     Payouts
+	cfg         config.SettlementConfig
+	auditLogger audit.Recorder
+	mailer      emails.Mailer
+}
+
+func NewService(repo Repository, payouts Payouts, cfg config.SettlementConfig) Service {
+	return &service{repo: repo, payouts: payouts, cfg: cfg}
+}
+
+func (s *service) AccruePayout(ctx context.Context, credit *models.PayoutCredit) error {
+	item := &models.SettlementItem{
+		ReferenceType:   credit.ReferenceType,
+		ReferenceID:     credit.ReferenceID,
+		ReferenceNumber: credit.ReferenceNumber,
+		ProviderID:      credit.ProviderID,
+		ProviderUserID:  credit.ProviderUserID,
+		Amount:          credit.Amount,
+		TransactionType: credit.TransactionType,
+		Description:     credit.Description,
+		Metadata:        credit.Metadata,
+		EarnedAt:        time.Now(),
+	}
+	if err := s.repo.CreateItem(ctx, item); err != nil {
+		return err
+	}
+
+	logger.Info("provider payout accrued for settlement",
+		"settlementItemID", item.ID,
+		"referenceType", item.ReferenceType,
+		"referenceID", item.ReferenceID,
+		"providerUserID", item.ProviderUserID,
+		"amount", item.Amount)
+	return nil
+}
+
+func (s *service) GenerateSettlements(ctx context.Context, now time.Time) (int, error) {
+	periodEnd := weekStart(now)
+	periodStart := periodEnd.Add(-settlementPeriod)
+
+	totals, err := s.repo.UnsettledTotals(ctx, periodEnd)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, total := range totals {
+		if total.TotalAmount < s.cfg.MinPayout {
+			logger.Debug("provider payouts below settlement minimum, carried over",
+				"providerUserID", total.ProviderUserID,
+				"amount", total.TotalAmount,
+				"minPayout", s.cfg.MinPayout)
+			continue
+		}
+
+		settlement := &models.ProviderSettlement{
+			ProviderUserID: total.ProviderUserID,
+			ProviderID:     total.ProviderID,
+			PeriodStart:    periodStart,
+			PeriodEnd:      periodEnd,
+			Status:         models.SettlementStatusPendingApproval,
+		}
+		ok, err := s.repo.CreateSettlement(ctx, settlement)
+		if err != nil {
+			logger.Error("failed to create provider settlement", "error", err, "providerUserID", total.ProviderUserID, "periodEnd", periodEnd)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		created++
+		logger.Info("provider settlement created",
+			"settlementID", settlement.ID,
+			"providerUserID", settlement.ProviderUserID,
+			"periodStart", periodStart,
+			"itemCount", settlement.ItemCount,
+			"amount", settlement.TotalAmount)
+	}
+
+	return created, nil
+}
+
+func (s *service) GenerateForPeriod(ctx context.Context, adminID string, req dto.GenerateSettlementsRequest) (*dto.GenerateSettlementsResponse, error) {
+	now := time.Now()
+	at := now
+	if req.PeriodEnd != nil {
+		if req.PeriodEnd.After(now) {
+			return nil, response.BadRequest("periodEnd cannot be in the future")
+		}
+		at = *req.PeriodEnd
+	}
+
+	created, err := s.GenerateSettlements(ctx, at)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to generate settlements", err)
+	}
+
+	periodEnd := weekStart(at)
+	result := &dto.GenerateSettlementsResponse{
+		PeriodStart: periodEnd.Add(-settlementPeriod),
+		PeriodEnd:   periodEnd,
+		Created:     created,
+	}
+
+	logger.Info("provider settlements generated by admin", "adminID", adminID, "periodEnd", periodEnd, "created", created)
+
+	if s.auditLogger != nil {
+		s.auditLogger.Record(ctx, audit.Entry{
+			ActorID:    adminID,
+			ActorRole:  string(models.RoleAdmin),
+			Action:     "settlement.generate",
+			Category:   models.AuditCategoryFinancial,
+			EntityType: "provider_settlement_period",
+			EntityID:   result.PeriodStart.Format("2006-01-02"),
+			After: map[string]interface{}{
+				"periodStart": result.PeriodStart,
+				"periodEnd":   result.PeriodEnd,
+				"created":     created,
+			},
+		})
+	}
+	return result, nil
+}
+
+func (s *service) ReconcilePayments(ctx context.Context) error {
+	settlements, err := s.repo.ListByStatus(ctx, models.SettlementStatusApproved, reconcileBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, settlement := range settlements {
+		credited, err := s.repo.PayoutCredited(ctx, settlement.ID)
+		if err != nil {
+			logger.Warn("failed to check settlement payout", "error", err, "settlementID", settlement.ID)
+			continue
+		}
+		if credited {
+			s.markPaid(ctx, settlement)
+		}
+	}
+	return nil
+}
+
+func (s *service) ListSettlements(ctx context.Context, query dto.ListSettlementsQuery) ([]*dto.SettlementResponse, int64, error) {
+	query.SetDefaults()
+
+	settlements, total, err := s.repo.List(ctx, query)
+	if err != nil {
+		return nil, 0, response.InternalServerError("Failed to fetch settlements", err)
+	}
+
+	result := make([]*dto.SettlementResponse, len(settlements))
+	for i, settlement := range settlements {
+		result[i] = dto.ToSettlementResponse(settlement)
+	}
+	return result, total, nil
+}
+
+func (s *service) GetSettlement(ctx context.Context, id string) (*dto.SettlementResponse, error) {
+	settlement, err := s.findSettlement(ctx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToSettlementResponse(settlement), nil
+}
+
+// ApproveSettlement credits the settlement total to the provider's wallet.
+// If the credit fails it is queued like any other payout and the settlement
+// stays approved until ReconcilePayments sees it land.
+func (s *service) ApproveSettlement(ctx context.Context, adminID, id string, req dto.ApproveSettlementRequest) (*dto.SettlementResponse, error) {
+	settlement, err := s.findSettlement(ctx, id, false)
+	if err != nil {
+		return nil, err
+	}
+	if settlement.Status != models.SettlementStatusPendingApproval {
+		return nil, response.BadRequest(fmt.Sprintf("Settlement is already %s", settlement.Status))
+	}
+
+	before := *settlement
+	now := time.Now()
+	settlement.Status = models.SettlementStatusApproved
+	settlement.ReviewedBy = &adminID
+	settlement.ReviewNote = req.Note
+	settlement.ReviewedAt = &now
+
+	ok, err := s.repo.Transition(ctx, settlement, models.SettlementStatusPendingApproval)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to approve settlement", err)
+	}
+	if !ok {
+		return nil, response.ConflictError("Settlement was reviewed by someone else")
+	}
+
+	queued, err := s.payouts.CreditProviderPayout(ctx, &models.PayoutCredit{
+		ReferenceType:   models.PayoutReferenceSettlement,
+		ReferenceID:     settlement.ID,
+		ReferenceNumber: settlementNumber(settlement),
+		ProviderID:      settlement.ProviderID,
+		ProviderUserID:  settlement.ProviderUserID,
+		Amount:          settlement.TotalAmount,
+		TransactionType: settlementTxnType,
+		Description:     fmt.Sprintf("Settlement for week of %s", settlement.PeriodStart.Format("2 Jan 2006")),
+		Metadata: models.JSONBMap{
+			"settlement_id": settlement.ID,
+			"period_start":  settlement.PeriodStart,
+			"period_end":    settlement.PeriodEnd,
+			"item_count":    settlement.ItemCount,
+		},
+	})
+	if err != nil {
+		// Nothing was paid or queued, so the settlement goes back for review.
+		settlement.Status = models.SettlementStatusPendingApproval
+		settlement.ReviewedBy = before.ReviewedBy
+		settlement.ReviewNote = before.ReviewNote
+		settlement.ReviewedAt = before.ReviewedAt
+		if _, revertErr := s.repo.Transition(ctx, settlement, models.SettlementStatusApproved); revertErr != nil {
+			logger.Error("failed to revert settlement approval", "error", revertErr, "settlementID", settlement.ID)
+		}
+		return nil, response.InternalServerError("Failed to pay settlement", err)
+	}
+
+	if !queued {
+		s.markPaid(ctx, settlement)
+	}
+
+	logger.Info("provider settlement approved",
+		"settlementID", settlement.ID,
+		"adminID", adminID,
+		"amount", settlement.TotalAmount,
+		"queued", queued)
+
+	s.auditSettlement(ctx, adminID, "settlement.approve", &before, settlement, req.Note)
+	return dto.ToSettlementResponse(settlement), nil
+}
+
+func (s *service) RejectSettlement(ctx context.Context, adminID, id string, req dto.RejectSettlementRequest) (*dto.SettlementResponse, error) {
+	settlement, err := s.findSettlement(ctx, id, false)
+	if err != nil {
+		return nil, err
+	}
+	if settlement.Status != models.SettlementStatusPendingApproval {
+		return nil, response.BadRequest(fmt.Sprintf("Settlement is already %s", settlement.Status))
+	}
+
+	before := *settlement
+	now := time.Now()
+	settlement.Status = models.SettlementStatusRejected
+	settlement.ReviewedBy = &adminID
+	settlement.ReviewNote = req.Note
+	settlement.ReviewedAt = &now
+
+	ok, err := s.repo.Reject(ctx, settlement)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to reject settlement", err)
+	}
+	if !ok {
+		return nil, response.ConflictError("Settlement was reviewed by someone else")
+	}
+
+	logger.Info("provider settlement rejected", "settlementID", settlement.ID, "adminID", adminID, "amount", settlement.TotalAmount)
+
+	s.auditSettlement(ctx, adminID, "settlement.reject", &before, settlement, req.Note)
+	return dto.ToSettlementResponse(settlement), nil
+}
+
+func (s *service) ListProviderSettlements(ctx context.Context, providerUserID string, query dto.ListSettlementsQuery) ([]*dto.SettlementResponse, int64, error) {
+	query.ProviderUserID = providerUserID
+	return s.ListSettlements(ctx, query)
+}
+
+func (s *service) GetProviderSettlement(ctx context.Context, providerUserID, id string) (*dto.SettlementResponse, error) {
+	settlement, err := s.findSettlement(ctx, id, true)
+	if err != nil {
+		return nil, err
+	}
+	if settlement.ProviderUserID != providerUserID {
+		return nil, response.NotFoundError("Settlement")
+	}
+	return dto.ToSettlementResponse(settlement), nil
+}
+
+func (s *service) GetAccruedPayouts(ctx context.Context, providerUserID string) (*dto.AccruedPayoutsResponse, error) {
+	total, err := s.repo.UnsettledTotal(ctx, providerUserID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to fetch accrued payouts", err)
+	}
+	return &dto.AccruedPayoutsResponse{
+		ItemCount:   total.ItemCount,
+		TotalAmount: total.TotalAmount,
+		MinPayout:   s.cfg.MinPayout,
+	}, nil
+}
+
+func (s *service) findSettlement(ctx context.Context, id string, withItems bool) (*models.ProviderSettlement, error) {
+	settlement, err := s.repo.FindByID(ctx, id, withItems)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Settlement")
+		}
+		return nil, response.InternalServerError("Failed to fetch settlement", err)
+	}
+	return settlement, nil
+}
+
+func (s *service) markPaid(ctx context.Context, settlement *models.ProviderSettlement) {
+	now := time.Now()
+	settlement.Status = models.SettlementStatusPaid
+	settlement.PaidAt = &now
+	if err := s.repo.MarkPaid(ctx, settlement); err != nil {
+		// The money has moved; the next reconcile run tries again.
+		logger.Error("settlement paid but not marked", "error", err, "settlementID", settlement.ID)
+		return
+	}
+	logger.Info("provider settlement paid", "settlementID", settlement.ID, "amount", settlement.TotalAmount)
+	s.emailStatement(settlement)
+}
+
+// weekStart is the Monday 00:00 UTC at or before t.
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, time.UTC)
+}
+
+func settlementNumber(settlement *models.ProviderSettlement) string {
+	return fmt.Sprintf("STL-%s-%s", settlement.PeriodStart.Format(settlementNumberDate), settlement.ID[:8])
+}
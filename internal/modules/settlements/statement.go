@@ -0,0 +1,56 @@
+package settlements
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/emails"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+func (s *service) SetMailer(mailer emails.Mailer) {
+	s.mailer = mailer
+}
+
+// emailStatement sends the provider their payout statement with the
+// settlement report attached. It runs in the background; a failed email does
+// not undo the payout.
+func (s *service) emailStatement(settlement *models.ProviderSettlement) {
+	if s.mailer == nil || !s.mailer.Enabled() {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		report, err := s.ExportSettlement(ctx, settlement.ID)
+		if err != nil {
+			logger.Error("failed to render payout statement", "error", err, "settlementID", settlement.ID)
+			return
+		}
+
+		_, err = s.mailer.SendToUser(ctx, settlement.ProviderUserID, emails.SendRequest{
+			Template: emails.TemplatePayoutStatement,
+			Data: map[string]interface{}{
+				"SettlementNumber": settlementNumber(settlement),
+				"PeriodStart":      settlement.PeriodStart.Format("Jan 2, 2006"),
+				"PeriodEnd":        settlement.PeriodEnd.AddDate(0, 0, -1).Format("Jan 2, 2006"),
+				"ItemCount":        settlement.ItemCount,
+				"Total":            formatAmount(settlement.TotalAmount),
+			},
+			Attachments: []emails.Attachment{{
+				Filename:    report.FileName,
+				ContentType: report.ContentType,
+				Content:     report.Data,
+			}},
+			Category:      models.NotificationCategoryTransactional,
+			ReferenceType: "provider_settlement",
+			ReferenceID:   settlement.ID,
+		})
+		if err != nil && !errors.Is(err, emails.ErrOptedOut) && !errors.Is(err, emails.ErrNoAddress) {
+			logger.Error("failed to email payout statement", "error", err, "settlementID", settlement.ID)
+		}
+	}()
+}
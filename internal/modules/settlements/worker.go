@@ -0,0 +1,54 @@
+package settlements
+
+import (
+	"context"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/services/health"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+// Worker closes each week's settlements shortly after the week ends and
+// marks approved settlements paid as their credits land. It runs on a short
+// interval since both steps are cheap no-ops most of the time.
+type Worker struct {
+	service  Service
+	interval time.Duration
+}
+
+func NewWorker(service Service, cfg config.SettlementConfig) *Worker {
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &Worker{service: service, interval: interval}
+}
+
+func (w *Worker) Start(ctx context.Context) {
+	heartbeat := health.NewHeartbeat("provider_settlements", w.interval)
+
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				runCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+				if _, err := w.service.GenerateSettlements(runCtx, time.Now()); err != nil {
+					logger.Error("settlement generation failed", "error", err)
+				}
+				if err := w.service.ReconcilePayments(runCtx); err != nil {
+					logger.Error("settlement reconciliation failed", "error", err)
+				}
+				cancel()
+				heartbeat.Beat()
+			}
+		}
+	}()
+
+	logger.Info("settlement worker started", "interval", w.interval)
+}
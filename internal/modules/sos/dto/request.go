@@ -12,14 +12,30 @@ type TriggerSOSRequest struct {
 }
 
 func (r *TriggerSOSRequest) Validate() error {
-	if r.Latitude == 0 && r.Longitude == 0 {
+	return validateLocation(r.Latitude, r.Longitude)
+}
+
+// TriggerRideSOSRequest is sent by the rider or driver of a ride with the
+// device's current location.
+type TriggerRideSOSRequest struct {
+	Latitude  float64 `json:"latitude" binding:"required"`
+	Longitude float64 `json:"longitude" binding:"required"`
+	Note      string  `json:"note" binding:"omitempty,max=500"`
+}
+
+func (r *TriggerRideSOSRequest) Validate() error {
+	return validateLocation(r.Latitude, r.Longitude)
+}
+
+func validateLocation(latitude, longitude float64) error {
+	if latitude == 0 && longitude == 0 {
 		return errors.New("valid location is required")
 	}
-	if r.Latitude < -90 || r.Latitude > 90 {
-		return fmt.Errorf("latitude must be between -90 and 90, got %f", r.Latitude)
+	if latitude < -90 || latitude > 90 {
+		return fmt.Errorf("latitude must be between -90 and 90, got %f", latitude)
 	}
-	if r.Longitude < -180 || r.Longitude > 180 {
-		return fmt.Errorf("longitude must be between -180 and 180, got %f", r.Longitude)
+	if longitude < -180 || longitude > 180 {
+		return fmt.Errorf("longitude must be between -180 and 180, got %f", longitude)
 	}
 	return nil
 }
@@ -29,7 +45,7 @@ type ResolveSOSRequest struct {
 }
 
 type ListSOSRequest struct {
-	Status string `form:"status" binding:"omitempty,oneof=active resolved cancelled"`
+	Status string `form:"status" binding:"omitempty,oneof=active acknowledged responding resolved false_alarm cancelled"`
 	Page   int    `form:"page" binding:"omitempty,min=1"`
 	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
 }
@@ -49,4 +65,26 @@ func (r *ListSOSRequest) SetDefaults() {
 type UpdateSOSLocationRequest struct {
 	Latitude  float64 `json:"latitude" binding:"required"`
 	Longitude float64 `json:"longitude" binding:"required"`
-}
\ No newline at end of file
+}
+type ListIncidentsRequest struct {
+	Status   string `form:"status" binding:"omitempty,oneof=active acknowledged responding resolved false_alarm cancelled"`
+	Severity string `form:"severity" binding:"omitempty,oneof=low medium high critical"`
+	RideID   string `form:"rideId" binding:"omitempty,uuid"`
+	UserID   string `form:"userId" binding:"omitempty,uuid"`
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	Limit    int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (r *ListIncidentsRequest) SetDefaults() {
+	if r.Page <= 0 {
+		r.Page = 1
+	}
+	if r.Limit <= 0 {
+		r.Limit = 20
+	}
+}
+
+type UpdateIncidentStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=acknowledged responding resolved false_alarm"`
+	Note   string `json:"note" binding:"omitempty,max=1000"`
+}
@@ -20,9 +20,30 @@ type SOSAlertResponse struct {
     ResolvedAt                *time.Time            `json:"resolvedAt,omitempty"`
     ResolvedBy                *string               `json:"resolvedBy,omitempty"`
     Notes                     string                `json:"notes,omitempty"`
+    Severity                  string                `json:"severity"`
+    ReporterRole              string                `json:"reporterRole,omitempty"`
+    RiderLocation             *LocationSnapshot     `json:"riderLocation,omitempty"`
+    DriverLocation            *LocationSnapshot     `json:"driverLocation,omitempty"`
+    AcknowledgedBy            *string               `json:"acknowledgedBy,omitempty"`
+    AcknowledgedAt            *time.Time            `json:"acknowledgedAt,omitempty"`
     CreatedAt                 time.Time             `json:"createdAt"`
 }
 
+// LocationSnapshot is where a party to the ride was last seen when the
+// incident was raised.
+type LocationSnapshot struct {
+    Latitude   float64    `json:"latitude"`
+    Longitude  float64    `json:"longitude"`
+    Source     string     `json:"source,omitempty"`
+    RecordedAt *time.Time `json:"recordedAt,omitempty"`
+}
+
+// IncidentResponse is the admin view of an SOS incident with its timeline.
+type IncidentResponse struct {
+    *SOSAlertResponse
+    Timeline []*models.SOSIncidentEvent `json:"timeline"`
+}
+
 type SOSAlertListResponse struct {
     ID        string    `json:"id"`
     UserID    string    `json:"userId"`
@@ -46,9 +67,28 @@ func ToSOSAlertResponse(alert *models.SOSAlert) *SOSAlertResponse {
         ResolvedAt:                alert.ResolvedAt,
         ResolvedBy:                alert.ResolvedBy,
         Notes:                     alert.Notes,
+        Severity:                  alert.Severity,
+        ReporterRole:              alert.ReporterRole,
+        AcknowledgedBy:            alert.AcknowledgedBy,
+        AcknowledgedAt:            alert.AcknowledgedAt,
         CreatedAt:                 alert.CreatedAt,
     }
 
+    if alert.RiderLatitude != nil && alert.RiderLongitude != nil {
+        resp.RiderLocation = &LocationSnapshot{
+            Latitude:  *alert.RiderLatitude,
+            Longitude: *alert.RiderLongitude,
+            Source:    alert.RiderLocationSource,
+        }
+    }
+    if alert.DriverLatitude != nil && alert.DriverLongitude != nil {
+        resp.DriverLocation = &LocationSnapshot{
+            Latitude:   *alert.DriverLatitude,
+            Longitude:  *alert.DriverLongitude,
+            RecordedAt: alert.DriverLocationAt,
+        }
+    }
+
     if alert.User.ID != "" {
         resp.User = authdto.ToUserResponse(&alert.User)
     }
@@ -56,6 +96,16 @@ func ToSOSAlertResponse(alert *models.SOSAlert) *SOSAlertResponse {
     return resp
 }
 
+func ToIncidentResponse(alert *models.SOSAlert, timeline []*models.SOSIncidentEvent) *IncidentResponse {
+    if timeline == nil {
+        timeline = []*models.SOSIncidentEvent{}
+    }
+    return &IncidentResponse{
+        SOSAlertResponse: ToSOSAlertResponse(alert),
+        Timeline:         timeline,
+    }
+}
+
 func ToSOSAlertListResponse(alert *models.SOSAlert) *SOSAlertListResponse {
     return &SOSAlertListResponse{
         ID:        alert.ID,
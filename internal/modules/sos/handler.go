@@ -250,3 +250,81 @@ func (h *Handler) UpdateSOSLocation(c *gin.Context) {
 
 	response.Success(c, alert, "SOS location updated and broadcast to admin")
 }
+
+// ListIncidents godoc
+// @Summary List SOS incidents (Admin)
+// @Tags sos
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "Filter by status"
+// @Param severity query string false "Filter by severity"
+// @Param rideId query string false "Filter by ride"
+// @Param userId query string false "Filter by reporting user"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Response{data=[]dto.SOSAlertResponse}
+// @Router /sos/admin/incidents [get]
+func (h *Handler) ListIncidents(c *gin.Context) {
+	var req dto.ListIncidentsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+	req.SetDefaults()
+
+	incidents, total, err := h.service.ListIncidents(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pagination := response.NewPaginationMeta(total, req.Page, req.Limit)
+	response.Paginated(c, incidents, pagination, "SOS incidents retrieved successfully")
+}
+
+// GetIncident godoc
+// @Summary Get an SOS incident with its timeline (Admin)
+// @Tags sos
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Alert ID"
+// @Success 200 {object} response.Response{data=dto.IncidentResponse}
+// @Router /sos/admin/incidents/{id} [get]
+func (h *Handler) GetIncident(c *gin.Context) {
+	incident, err := h.service.GetIncident(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, incident, "SOS incident retrieved successfully")
+}
+
+// UpdateIncidentStatus godoc
+// @Summary Move an SOS incident through the response workflow (Admin)
+// @Description active -> acknowledged -> responding -> resolved or false_alarm
+// @Tags sos
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Alert ID"
+// @Param request body dto.UpdateIncidentStatusRequest true "New status"
+// @Success 200 {object} response.Response{data=dto.IncidentResponse}
+// @Router /sos/admin/incidents/{id}/status [post]
+func (h *Handler) UpdateIncidentStatus(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	var req dto.UpdateIncidentStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	incident, err := h.service.UpdateIncidentStatus(c.Request.Context(), adminID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, incident, "SOS incident updated successfully")
+}
@@ -0,0 +1,164 @@
+package sos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/sos/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+	websocketutil "github.com/umar5678/go-backend/internal/websocket/websocketutils"
+	"gorm.io/gorm"
+)
+
+func (s *service) ListIncidents(ctx context.Context, req dto.ListIncidentsRequest) ([]*dto.SOSAlertResponse, int64, error) {
+	filter := IncidentFilter{
+		Status:   req.Status,
+		Severity: req.Severity,
+		RideID:   req.RideID,
+		UserID:   req.UserID,
+	}
+
+	alerts, total, err := s.repo.ListIncidents(ctx, filter, req.Page, req.Limit)
+	if err != nil {
+		logger.Error("failed to list SOS incidents", "error", err)
+		return nil, 0, response.InternalServerError("Failed to fetch SOS incidents", err)
+	}
+
+	result := make([]*dto.SOSAlertResponse, 0, len(alerts))
+	for _, alert := range alerts {
+		result = append(result, dto.ToSOSAlertResponse(alert))
+	}
+	return result, total, nil
+}
+
+func (s *service) GetIncident(ctx context.Context, alertID string) (*dto.IncidentResponse, error) {
+	alert, err := s.repo.FindByID(ctx, alertID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("SOS incident")
+		}
+		return nil, response.InternalServerError("Failed to fetch SOS incident", err)
+	}
+
+	timeline, err := s.repo.ListEvents(ctx, alertID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to fetch SOS incident timeline", err)
+	}
+
+	return dto.ToIncidentResponse(alert, timeline), nil
+}
+
+// UpdateIncidentStatus moves an incident along the admin workflow. The first
+// admin to act on an incident is recorded as having acknowledged it.
+func (s *service) UpdateIncidentStatus(ctx context.Context, adminID, alertID string, req dto.UpdateIncidentStatusRequest) (*dto.IncidentResponse, error) {
+	alert, err := s.repo.FindByID(ctx, alertID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("SOS incident")
+		}
+		return nil, response.InternalServerError("Failed to fetch SOS incident", err)
+	}
+
+	if !canTransition(alert.Status, req.Status) {
+		return nil, response.BadRequest(fmt.Sprintf("Cannot move incident from %s to %s", alert.Status, req.Status))
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{"status": req.Status}
+	if alert.AcknowledgedAt == nil {
+		updates["acknowledged_by"] = adminID
+		updates["acknowledged_at"] = now
+	}
+	if req.Status == models.SOSStatusResolved || req.Status == models.SOSStatusFalseAlarm {
+		updates["resolved_by"] = adminID
+		updates["resolved_at"] = now
+		if req.Note != "" {
+			updates["notes"] = req.Note
+		}
+	}
+
+	if err := s.repo.UpdateStatus(ctx, alertID, alert.Status, updates); err != nil {
+		logger.Warn("failed to update SOS incident status", "error", err, "alertID", alertID)
+		return nil, response.ConflictError("Incident was updated by someone else, please reload it")
+	}
+
+	s.recordEvent(ctx, alertID, &adminID, alert.Status, req.Status, req.Note)
+
+	logger.Info("SOS incident status updated",
+		"alertID", alertID,
+		"adminID", adminID,
+		"from", alert.Status,
+		"to", req.Status,
+	)
+
+	incident, err := s.GetIncident(ctx, alertID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Status == models.SOSStatusResolved {
+		go NotifySOSResolved(context.Background(), alert, adminID)
+	} else {
+		payload := map[string]interface{}{
+			"alertId":   alertID,
+			"status":    req.Status,
+			"updatedBy": adminID,
+			"timestamp": now.UTC(),
+		}
+		websocketutil.SendToUser(alert.UserID, websocket.TypeSOSStatus, payload)
+		websocketutil.BroadcastToRole("admin", websocket.TypeSOSStatus, payload)
+	}
+
+	return incident, nil
+}
+
+func canTransition(from, to string) bool {
+	for _, allowed := range incidentTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// recordEvent adds an entry to the incident timeline. The timeline is for
+// review only, so a failed write is logged rather than failing the action.
+func (s *service) recordEvent(ctx context.Context, alertID string, actorID *string, from, to, note string) {
+	event := &models.SOSIncidentEvent{
+		AlertID:    alertID,
+		ActorID:    actorID,
+		FromStatus: from,
+		ToStatus:   to,
+		Note:       note,
+	}
+	if err := s.repo.CreateEvent(ctx, event); err != nil {
+		logger.Error("failed to record SOS incident event", "error", err, "alertID", alertID, "status", to)
+	}
+}
+
+func (s *service) textSafetyTeam(ctx context.Context, alert *models.SOSAlert) {
+	if s.sms == nil || len(s.safetyTeamPhones) == 0 {
+		return
+	}
+
+	reporter := alert.ReporterRole
+	if reporter == "" {
+		reporter = "user"
+	}
+	message := fmt.Sprintf("SOS: %s %s raised an alert. Location: https://maps.google.com/?q=%f,%f",
+		reporter, alert.User.Name, alert.Latitude, alert.Longitude)
+	if alert.RideID != nil {
+		message += "\nRide ID: " + *alert.RideID
+	}
+
+	for _, phone := range s.safetyTeamPhones {
+		if err := s.sms.Send(ctx, phone, message); err != nil {
+			logger.Error("failed to text safety team", "error", err, "alertID", alert.ID)
+		}
+	}
+}
@@ -20,6 +20,28 @@ type Repository interface {
 	UpdateLocation(ctx context.Context, alertID string, latitude, longitude float64) error
 	MarkEmergencyContactsNotified(ctx context.Context, alertID string) error
 	MarkSafetyTeamNotified(ctx context.Context, alertID string) error
+
+	FindRide(ctx context.Context, rideID string) (*models.Ride, error)
+	LatestRideTrackPoint(ctx context.Context, rideID string) (*models.RideTrackPoint, error)
+	GetDriverPosition(ctx context.Context, driverUserID string) (*DriverPosition, error)
+
+	ListIncidents(ctx context.Context, filter IncidentFilter, page, limit int) ([]*models.SOSAlert, int64, error)
+	UpdateStatus(ctx context.Context, alertID, fromStatus string, updates map[string]interface{}) error
+	CreateEvent(ctx context.Context, event *models.SOSIncidentEvent) error
+	ListEvents(ctx context.Context, alertID string) ([]*models.SOSIncidentEvent, error)
+}
+
+type IncidentFilter struct {
+	Status   string
+	Severity string
+	RideID   string
+	UserID   string
+}
+
+type DriverPosition struct {
+	Lat       float64
+	Lon       float64
+	UpdatedAt time.Time
 }
 
 type repository struct {
@@ -60,7 +82,7 @@ func (r *repository) FindActiveByUserID(ctx context.Context, userID string) (*mo
 	err := r.db.WithContext(ctx).
 		Preload("User").
 		Preload("Ride").
-		Where("user_id = ? AND status = ?", userID, "active").
+		Where("user_id = ? AND status IN ?", userID, models.SOSOpenStatuses).
 		Order("created_at DESC").
 		First(&alert).Error
 	if err != nil {
@@ -108,7 +130,7 @@ func (r *repository) Resolve(ctx context.Context, alertID, resolvedBy, notes str
 	now := time.Now()
 	result := r.db.WithContext(ctx).
 		Model(&models.SOSAlert{}).
-		Where("id = ? AND status = ?", alertID, "active").
+		Where("id = ? AND status IN ?", alertID, models.SOSOpenStatuses).
 		Updates(map[string]interface{}{
 			"status":      "resolved",
 			"resolved_at": now,
@@ -128,7 +150,7 @@ func (r *repository) Resolve(ctx context.Context, alertID, resolvedBy, notes str
 func (r *repository) Cancel(ctx context.Context, alertID string) error {
 	result := r.db.WithContext(ctx).
 		Model(&models.SOSAlert{}).
-		Where("id = ? AND status = ?", alertID, "active").
+		Where("id = ? AND status IN ?", alertID, models.SOSOpenStatuses).
 		Update("status", "cancelled")
 
 	if result.Error != nil {
@@ -143,7 +165,7 @@ func (r *repository) Cancel(ctx context.Context, alertID string) error {
 func (r *repository) UpdateLocation(ctx context.Context, alertID string, latitude, longitude float64) error {
 	result := r.db.WithContext(ctx).
 		Model(&models.SOSAlert{}).
-		Where("id = ? AND status = ?", alertID, "active").
+		Where("id = ? AND status IN ?", alertID, models.SOSOpenStatuses).
 		Updates(map[string]interface{}{
 			"latitude":  latitude,
 			"longitude": longitude,
@@ -172,3 +194,108 @@ func (r *repository) MarkSafetyTeamNotified(ctx context.Context, alertID string)
 		Where("id = ?", alertID).
 		Update("safety_team_notified_at", now).Error
 }
+
+func (r *repository) FindRide(ctx context.Context, rideID string) (*models.Ride, error) {
+	var ride models.Ride
+	err := r.db.WithContext(ctx).Where("id = ?", rideID).First(&ride).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ride, nil
+}
+
+func (r *repository) LatestRideTrackPoint(ctx context.Context, rideID string) (*models.RideTrackPoint, error) {
+	var point models.RideTrackPoint
+	err := r.db.WithContext(ctx).
+		Where("ride_id = ?", rideID).
+		Order("recorded_at DESC").
+		First(&point).Error
+	if err != nil {
+		return nil, err
+	}
+	return &point, nil
+}
+
+// GetDriverPosition returns the driver's current position from their
+// profile, or nil if none has been reported.
+func (r *repository) GetDriverPosition(ctx context.Context, driverUserID string) (*DriverPosition, error) {
+	var position DriverPosition
+	result := r.db.WithContext(ctx).
+		Model(&models.DriverProfile{}).
+		Select("ST_Y(current_location) AS lat, ST_X(current_location) AS lon, updated_at").
+		Where("user_id = ? AND current_location IS NOT NULL", driverUserID).
+		Scan(&position)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &position, nil
+}
+
+func (r *repository) ListIncidents(ctx context.Context, filter IncidentFilter, page, limit int) ([]*models.SOSAlert, int64, error) {
+	var alerts []*models.SOSAlert
+	var total int64
+
+	base := r.db.WithContext(ctx).Model(&models.SOSAlert{})
+	if filter.Status != "" {
+		base = base.Where("status = ?", filter.Status)
+	}
+	if filter.Severity != "" {
+		base = base.Where("severity = ?", filter.Severity)
+	}
+	if filter.RideID != "" {
+		base = base.Where("ride_id = ?", filter.RideID)
+	}
+	if filter.UserID != "" {
+		base = base.Where("user_id = ?", filter.UserID)
+	}
+
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count SOS incidents: %w", err)
+	}
+
+	offset := (page - 1) * limit
+	if err := base.
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Preload("User").
+		Preload("Ride").
+		Find(&alerts).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch SOS incidents: %w", err)
+	}
+
+	return alerts, total, nil
+}
+
+// UpdateStatus applies a workflow transition only if the incident is still
+// in fromStatus, so two admins acting at once cannot both move it.
+func (r *repository) UpdateStatus(ctx context.Context, alertID, fromStatus string, updates map[string]interface{}) error {
+	result := r.db.WithContext(ctx).
+		Model(&models.SOSAlert{}).
+		Where("id = ? AND status = ?", alertID, fromStatus).
+		Updates(updates)
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.New("alert status has changed")
+	}
+	return nil
+}
+
+func (r *repository) CreateEvent(ctx context.Context, event *models.SOSIncidentEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+func (r *repository) ListEvents(ctx context.Context, alertID string) ([]*models.SOSIncidentEvent, error) {
+	var events []*models.SOSIncidentEvent
+	err := r.db.WithContext(ctx).
+		Where("alert_id = ?", alertID).
+		Order("created_at ASC").
+		Find(&events).Error
+	return events, err
+}
@@ -2,6 +2,7 @@ package sos
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/middleware"
 )
 
 func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
@@ -17,5 +18,10 @@ func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gi
 		sos.POST("/:id/location", handler.UpdateSOSLocation)
 		
 		sos.POST("/trigger", handler.TriggerSOS)
+
+		admin := sos.Group("/admin", middleware.RequireAdmin())
+		admin.GET("/incidents", handler.ListIncidents)
+		admin.GET("/incidents/:id", handler.GetIncident)
+		admin.POST("/incidents/:id/status", handler.UpdateIncidentStatus)
 	}
 }
@@ -24,12 +24,29 @@ type Service interface {
 	ResolveSOS(ctx context.Context, userID, alertID string, req dto.ResolveSOSRequest, isAdmin bool) (*dto.SOSAlertResponse, error)
 	CancelSOS(ctx context.Context, userID, alertID string, isAdmin bool) (*dto.SOSAlertResponse, error)
 	UpdateSOSLocation(ctx context.Context, userID, alertID string, latitude, longitude float64) (*dto.SOSAlertResponse, error)
+
+	TriggerRideSOS(ctx context.Context, userID, rideID string, req dto.TriggerRideSOSRequest) (*dto.SOSAlertResponse, error)
+	ListIncidents(ctx context.Context, req dto.ListIncidentsRequest) ([]*dto.SOSAlertResponse, int64, error)
+	GetIncident(ctx context.Context, alertID string) (*dto.IncidentResponse, error)
+	UpdateIncidentStatus(ctx context.Context, adminID, alertID string, req dto.UpdateIncidentStatusRequest) (*dto.IncidentResponse, error)
+
+	SetSMSSender(sender SMSSender, safetyTeamPhones []string)
+}
+
+// incidentTransitions is the admin workflow. An incident can be closed from
+// any open status, since the first responder may already know the outcome.
+var incidentTransitions = map[string][]string{
+	models.SOSStatusActive:       {models.SOSStatusAcknowledged, models.SOSStatusResponding, models.SOSStatusResolved, models.SOSStatusFalseAlarm},
+	models.SOSStatusAcknowledged: {models.SOSStatusResponding, models.SOSStatusResolved, models.SOSStatusFalseAlarm},
+	models.SOSStatusResponding:   {models.SOSStatusResolved, models.SOSStatusFalseAlarm},
 }
 
 type service struct {
-	repo          Repository
-	userDB        *gorm.DB
-	eventProducer notificationsmodule.EventProducer
+	repo             Repository
+	userDB           *gorm.DB
+	eventProducer    notificationsmodule.EventProducer
+	sms              SMSSender
+	safetyTeamPhones []string
 }
 
 func NewService(repo Repository, db *gorm.DB) Service {
@@ -44,6 +61,11 @@ func NewServiceWithNotifications(repo Repository, db *gorm.DB, eventProducer not
 	}
 }
 
+func (s *service) SetSMSSender(sender SMSSender, safetyTeamPhones []string) {
+	s.sms = sender
+	s.safetyTeamPhones = safetyTeamPhones
+}
+
 func (s *service) TriggerSOS(ctx context.Context, userID string, req dto.TriggerSOSRequest) (*dto.SOSAlertResponse, error) {
 	if userID == "" {
 		return nil, response.BadRequest("User ID is required")
@@ -53,31 +75,118 @@ func (s *service) TriggerSOS(ctx context.Context, userID string, req dto.Trigger
 		return nil, response.BadRequest(err.Error())
 	}
 
-	existingAlert, err := s.repo.FindActiveByUserID(ctx, userID)
-	if err == nil && existingAlert != nil && existingAlert.Status == "active" {
-		return nil, response.BadRequest("You already have an active SOS alert")
+	alert := &models.SOSAlert{
+		UserID:    userID,
+		RideID:    req.RideID,
+		AlertType: "manual",
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+	}
+
+	return s.raiseIncident(ctx, alert, "")
+}
+
+// TriggerRideSOS raises an incident for the rider or driver of an active
+// ride, recording where both of them were last seen.
+func (s *service) TriggerRideSOS(ctx context.Context, userID, rideID string, req dto.TriggerRideSOSRequest) (*dto.SOSAlertResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	ride, err := s.repo.FindRide(ctx, rideID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Ride")
+		}
+		return nil, response.InternalServerError("Failed to fetch ride", err)
+	}
+
+	var role string
+	switch {
+	case ride.RiderID == userID:
+		role = "rider"
+	case ride.DriverID != nil && *ride.DriverID == userID:
+		role = "driver"
+	default:
+		return nil, response.ForbiddenError("Not authorized to trigger SOS for this ride")
+	}
+
+	if ride.Status != "accepted" && ride.Status != "arrived" && ride.Status != "started" {
+		return nil, response.BadRequest("SOS can only be triggered during an active ride")
 	}
 
-	now := time.Now()
 	alert := &models.SOSAlert{
-		UserID:      userID,
-		RideID:      req.RideID,
-		AlertType:   "manual",
-		Latitude:    req.Latitude,
-		Longitude:   req.Longitude,
-		Status:      "active",
-		Severity:    "critical",
-		TriggeredAt: now,
+		UserID:       userID,
+		RideID:       &ride.ID,
+		AlertType:    "ride",
+		Latitude:     req.Latitude,
+		Longitude:    req.Longitude,
+		ReporterRole: role,
 	}
+	s.captureLocations(ctx, alert, ride)
+
+	return s.raiseIncident(ctx, alert, req.Note)
+}
+
+// captureLocations fills in the last known location of each party. The
+// reporter's device gives their own; the driver's comes from the ride's
+// track or their profile, and a rider who did not report is taken to be in
+// the vehicle once the ride has started and at the pickup before that.
+func (s *service) captureLocations(ctx context.Context, alert *models.SOSAlert, ride *models.Ride) {
+	now := time.Now()
+
+	if alert.ReporterRole == "driver" {
+		alert.DriverLatitude, alert.DriverLongitude = &alert.Latitude, &alert.Longitude
+		alert.DriverLocationAt = &now
+	} else if point, err := s.repo.LatestRideTrackPoint(ctx, ride.ID); err == nil {
+		alert.DriverLatitude, alert.DriverLongitude = &point.Latitude, &point.Longitude
+		alert.DriverLocationAt = &point.RecordedAt
+	} else if ride.DriverID != nil {
+		position, err := s.repo.GetDriverPosition(ctx, *ride.DriverID)
+		if err != nil {
+			logger.Warn("failed to get driver position for SOS", "error", err, "rideID", ride.ID)
+		} else if position != nil {
+			alert.DriverLatitude, alert.DriverLongitude = &position.Lat, &position.Lon
+			alert.DriverLocationAt = &position.UpdatedAt
+		}
+	}
+
+	switch {
+	case alert.ReporterRole == "rider":
+		alert.RiderLatitude, alert.RiderLongitude = &alert.Latitude, &alert.Longitude
+		alert.RiderLocationSource = "reported"
+	case ride.Status == "started" && alert.DriverLatitude != nil:
+		alert.RiderLatitude, alert.RiderLongitude = alert.DriverLatitude, alert.DriverLongitude
+		alert.RiderLocationSource = "in_vehicle"
+	default:
+		alert.RiderLatitude, alert.RiderLongitude = &ride.PickupLat, &ride.PickupLon
+		alert.RiderLocationSource = "pickup"
+	}
+}
+
+// raiseIncident opens an incident and alerts emergency contacts and the
+// safety team. A user can only have one open incident at a time.
+func (s *service) raiseIncident(ctx context.Context, alert *models.SOSAlert, note string) (*dto.SOSAlertResponse, error) {
+	existingAlert, err := s.repo.FindActiveByUserID(ctx, alert.UserID)
+	if err == nil && existingAlert != nil && existingAlert.IsOpen() {
+		return nil, response.BadRequest("You already have an active SOS alert")
+	}
+
+	alert.Status = models.SOSStatusActive
+	alert.Severity = "critical"
+	alert.TriggeredAt = time.Now()
+	alert.Notes = note
 
 	if err := s.repo.Create(ctx, alert); err != nil {
-		logger.Error("failed to create SOS alert", "error", err, "userID", userID)
+		logger.Error("failed to create SOS alert", "error", err, "userID", alert.UserID)
 		return nil, response.InternalServerError("Failed to trigger SOS", err)
 	}
 
+	s.recordEvent(ctx, alert.ID, &alert.UserID, "", models.SOSStatusActive, note)
+
 	alert, err = s.repo.FindByID(ctx, alert.ID)
 	if err != nil {
-		logger.Error("failed to fetch created SOS alert", "error", err, "alertID", alert.ID)
+		logger.Error("failed to fetch created SOS alert", "error", err)
 		return nil, response.InternalServerError("Failed to fetch created alert", err)
 	}
 
@@ -86,17 +195,18 @@ func (s *service) TriggerSOS(ctx context.Context, userID string, req dto.Trigger
 
 	logger.Warn("SOS ALERT TRIGGERED",
 		"alertID", alert.ID,
-		"userID", userID,
-		"rideID", req.RideID,
-		"location", fmt.Sprintf("%f,%f", req.Latitude, req.Longitude),
+		"userID", alert.UserID,
+		"rideID", alert.RideID,
+		"reporterRole", alert.ReporterRole,
+		"location", fmt.Sprintf("%f,%f", alert.Latitude, alert.Longitude),
 	)
 
 	// Publish SOS alert event - use background context to ensure notification reaches admins
-	s.publishSOSEvent(context.Background(), notificationsmodule.EventSOSAlert, alert.ID, userID, map[string]interface{}{
-		"rideID":    req.RideID,
-		"latitude":  req.Latitude,
-		"longitude": req.Longitude,
-		"severity":  "critical",
+	s.publishSOSEvent(context.Background(), notificationsmodule.EventSOSAlert, alert.ID, alert.UserID, map[string]interface{}{
+		"rideID":    alert.RideID,
+		"latitude":  alert.Latitude,
+		"longitude": alert.Longitude,
+		"severity":  alert.Severity,
 	})
 
 	return dto.ToSOSAlertResponse(alert), nil
@@ -174,7 +284,7 @@ func (s *service) ResolveSOS(ctx context.Context, userID, alertID string, req dt
 		return nil, response.ForbiddenError("You can only resolve your own alerts")
 	}
 
-	if alert.Status != "active" {
+	if !alert.IsOpen() {
 		return nil, response.BadRequest(fmt.Sprintf("Alert cannot be resolved, current status: %s", alert.Status))
 	}
 
@@ -182,6 +292,7 @@ func (s *service) ResolveSOS(ctx context.Context, userID, alertID string, req dt
 		logger.Error("failed to resolve SOS alert", "error", err, "alertID", alertID)
 		return nil, response.InternalServerError("Failed to resolve SOS alert", err)
 	}
+	s.recordEvent(ctx, alertID, &userID, alert.Status, models.SOSStatusResolved, req.Notes)
 
 	updatedAlert, err := s.repo.FindByID(ctx, alertID)
 	if err != nil {
@@ -213,7 +324,7 @@ func (s *service) CancelSOS(ctx context.Context, userID, alertID string, isAdmin
 		return nil, response.ForbiddenError("You can only cancel your own alerts")
 	}
 
-	if alert.Status != "active" {
+	if !alert.IsOpen() {
 		return nil, response.BadRequest(fmt.Sprintf("Alert cannot be cancelled, current status: %s", alert.Status))
 	}
 
@@ -221,6 +332,7 @@ func (s *service) CancelSOS(ctx context.Context, userID, alertID string, isAdmin
 		logger.Error("failed to cancel SOS alert", "error", err, "alertID", alertID)
 		return nil, response.InternalServerError("Failed to cancel SOS alert", err)
 	}
+	s.recordEvent(ctx, alertID, &userID, alert.Status, models.SOSStatusCancelled, "")
 
 	updatedAlert, err := s.repo.FindByID(ctx, alertID)
 	if err != nil {
@@ -249,7 +361,7 @@ func (s *service) UpdateSOSLocation(ctx context.Context, userID, alertID string,
 		return nil, response.ForbiddenError("You can only update your own alerts")
 	}
 
-	if alert.Status != "active" {
+	if !alert.IsOpen() {
 		return nil, response.BadRequest("Alert is not active")
 	}
 
@@ -309,6 +421,12 @@ func (s *service) notifyEmergencyContacts(ctx context.Context, alert *models.SOS
 		"message", message,
 	)
 
+	if s.sms != nil {
+		if err := s.sms.Send(ctx, user.EmergencyContactPhone, message); err != nil {
+			logger.Error("failed to text emergency contact", "error", err, "alertID", alert.ID)
+		}
+	}
+
 	websocketutil.SendToUser(user.ID, websocket.TypeSOSAlert, map[string]interface{}{
 		"alertId":  alert.ID,
 		"userName": user.Name,
@@ -346,16 +464,22 @@ func (s *service) notifySafetyTeam(ctx context.Context, alert *models.SOSAlert)
 		"timestamp": alert.CreatedAt,
 	})
 
+	snapshot := dto.ToSOSAlertResponse(alert)
 	websocketutil.BroadcastToRole("admin", websocket.TypeSOSAlert, map[string]interface{}{
-		"type":      "sos_alert",
-		"alertId":   alert.ID,
-		"userId":    alert.UserID,
-		"rideId":    alert.RideID,
-		"latitude":  alert.Latitude,
-		"longitude": alert.Longitude,
-		"timestamp": alert.CreatedAt,
+		"type":           "sos_alert",
+		"alertId":        alert.ID,
+		"userId":         alert.UserID,
+		"rideId":         alert.RideID,
+		"reporterRole":   alert.ReporterRole,
+		"latitude":       alert.Latitude,
+		"longitude":      alert.Longitude,
+		"riderLocation":  snapshot.RiderLocation,
+		"driverLocation": snapshot.DriverLocation,
+		"timestamp":      alert.CreatedAt,
 	})
 
+	s.textSafetyTeam(ctx, alert)
+
 	logger.Warn("SAFETY TEAM NOTIFIED - SOS ALERT BROADCAST",
 		"alertID", alert.ID,
 		"userID", alert.UserID,
@@ -0,0 +1,74 @@
+package sos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+)
+
+// SMSSender delivers SOS text messages to emergency contacts and the safety
+// team.
+type SMSSender interface {
+	Send(ctx context.Context, to, body string) error
+}
+
+// NewSMSSender returns nil when SOS SMS is disabled.
+func NewSMSSender(cfg config.SafetyConfig) SMSSender {
+	if !cfg.SMSEnabled {
+		return nil
+	}
+	return &twilioSMS{
+		apiURL:     strings.TrimRight(cfg.TwilioAPIURL, "/"),
+		accountSID: cfg.TwilioAccountSID,
+		authToken:  cfg.TwilioAuthToken,
+		from:       cfg.SMSFrom,
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type twilioSMS struct {
+	apiURL     string
+	accountSID string
+	authToken  string
+	from       string
+	client     *http.Client
+}
+
+func (t *twilioSMS) Send(ctx context.Context, to, body string) error {
+	form := url.Values{}
+	form.Set("To", to)
+	form.Set("From", t.from)
+	form.Set("Body", body)
+
+	endpoint := t.apiURL + "/2010-04-01/Accounts/" + url.PathEscape(t.accountSID) + "/Messages.json"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(t.accountSID, t.authToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&apiErr); err == nil && apiErr.Message != "" {
+			return fmt.Errorf("twilio %d: %s", apiErr.Code, apiErr.Message)
+		}
+		return fmt.Errorf("twilio returned status %d", resp.StatusCode)
+	}
+	return nil
+}
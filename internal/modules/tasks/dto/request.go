@@ -0,0 +1,105 @@
+package dto
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+type CreateTaskRequest struct {
+	Title       string `json:"title" binding:"required,min=3,max=200"`
+	Description string `json:"description" binding:"omitempty,max=5000"`
+	Kind        string `json:"kind" binding:"omitempty,oneof=general dispute_follow_up onboarding investigation"`
+	// EntityType and EntityID point the task at what it is about; give both
+	// or neither.
+	EntityType string     `json:"entityType" binding:"omitempty,oneof=ride service_order provider driver user dispute"`
+	EntityID   string     `json:"entityId" binding:"omitempty,uuid"`
+	Priority   string     `json:"priority" binding:"omitempty,oneof=low normal high urgent"`
+	AssigneeID string     `json:"assigneeId" binding:"omitempty,uuid"`
+	DueAt      *time.Time `json:"dueAt"`
+	// Checklist is the task's steps, in order.
+	Checklist  []string `json:"checklist" binding:"omitempty,max=50,dive,min=1,max=200"`
+	WatcherIDs []string `json:"watcherIds" binding:"omitempty,max=20,dive,uuid"`
+}
+
+func (r *CreateTaskRequest) Validate() error {
+	if strings.TrimSpace(r.Title) == "" {
+		return errors.New("title is required")
+	}
+	if (r.EntityType == "") != (r.EntityID == "") {
+		return errors.New("entityType and entityId must be given together")
+	}
+	if r.Kind == "" {
+		r.Kind = "general"
+	}
+	if r.Priority == "" {
+		r.Priority = "normal"
+	}
+	return nil
+}
+
+// UpdateTaskRequest changes the fields that are set. ClearDueAt removes the
+// due date.
+type UpdateTaskRequest struct {
+	Title       *string    `json:"title" binding:"omitempty,min=3,max=200"`
+	Description *string    `json:"description" binding:"omitempty,max=5000"`
+	Status      *string    `json:"status" binding:"omitempty,oneof=open in_progress blocked done cancelled"`
+	Priority    *string    `json:"priority" binding:"omitempty,oneof=low normal high urgent"`
+	DueAt       *time.Time `json:"dueAt"`
+	ClearDueAt  bool       `json:"clearDueAt"`
+}
+
+func (r *UpdateTaskRequest) Validate() error {
+	if r.DueAt != nil && r.ClearDueAt {
+		return errors.New("dueAt and clearDueAt cannot be used together")
+	}
+	if r.Title == nil && r.Description == nil && r.Status == nil && r.Priority == nil && r.DueAt == nil && !r.ClearDueAt {
+		return errors.New("nothing to update")
+	}
+	return nil
+}
+
+// AssignTaskRequest hands the task to an admin; an empty AssigneeID
+// unassigns it.
+type AssignTaskRequest struct {
+	AssigneeID string `json:"assigneeId" binding:"omitempty,uuid"`
+}
+
+type AddChecklistItemRequest struct {
+	Title string `json:"title" binding:"required,min=1,max=200"`
+}
+
+type UpdateChecklistItemRequest struct {
+	Done bool `json:"done"`
+}
+
+type AddCommentRequest struct {
+	Body string `json:"body" binding:"required,min=1,max=5000"`
+}
+
+type ListTasksRequest struct {
+	Status     string `form:"status" binding:"omitempty,oneof=open in_progress blocked done cancelled"`
+	Priority   string `form:"priority" binding:"omitempty,oneof=low normal high urgent"`
+	Kind       string `form:"kind" binding:"omitempty,oneof=general dispute_follow_up onboarding investigation"`
+	AssigneeID string `form:"assigneeId" binding:"omitempty,uuid"`
+	EntityType string `form:"entityType" binding:"omitempty,oneof=ride service_order provider driver user dispute"`
+	EntityID   string `form:"entityId" binding:"omitempty,uuid"`
+	// Mine lists the tasks assigned to or watched by the caller.
+	Mine bool `form:"mine"`
+	// Overdue lists open tasks past their due date.
+	Overdue bool `form:"overdue"`
+	Page    int  `form:"page" binding:"omitempty,min=1"`
+	Limit   int  `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (r *ListTasksRequest) SetDefaults() {
+	if r.Page <= 0 {
+		r.Page = 1
+	}
+	if r.Limit <= 0 {
+		r.Limit = 20
+	}
+	if r.Limit > 100 {
+		r.Limit = 100
+	}
+}
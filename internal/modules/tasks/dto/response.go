@@ -0,0 +1,110 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+type TaskResponse struct {
+	ID             string                   `json:"id"`
+	Title          string                   `json:"title"`
+	Description    string                   `json:"description,omitempty"`
+	Kind           string                   `json:"kind"`
+	EntityType     *string                  `json:"entityType,omitempty"`
+	EntityID       *string                  `json:"entityId,omitempty"`
+	Status         string                   `json:"status"`
+	Priority       string                   `json:"priority"`
+	AssigneeID     *string                  `json:"assigneeId,omitempty"`
+	CreatedBy      string                   `json:"createdBy"`
+	DueAt          *time.Time               `json:"dueAt,omitempty"`
+	Overdue        bool                     `json:"overdue"`
+	CompletedBy    *string                  `json:"completedBy,omitempty"`
+	CompletedAt    *time.Time               `json:"completedAt,omitempty"`
+	ChecklistTotal int                      `json:"checklistTotal"`
+	ChecklistDone  int                      `json:"checklistDone"`
+	Checklist      []*ChecklistItemResponse `json:"checklist,omitempty"`
+	WatcherIDs     []string                 `json:"watcherIds,omitempty"`
+	CreatedAt      time.Time                `json:"createdAt"`
+	UpdatedAt      time.Time                `json:"updatedAt"`
+}
+
+type ChecklistItemResponse struct {
+	ID       string     `json:"id"`
+	Position int        `json:"position"`
+	Title    string     `json:"title"`
+	Done     bool       `json:"done"`
+	DoneBy   *string    `json:"doneBy,omitempty"`
+	DoneAt   *time.Time `json:"doneAt,omitempty"`
+}
+
+type CommentResponse struct {
+	ID         string    `json:"id"`
+	TaskID     string    `json:"taskId"`
+	AuthorID   string    `json:"authorId"`
+	AuthorName string    `json:"authorName,omitempty"`
+	Body       string    `json:"body"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// ToTaskResponse includes the checklist and watchers when they were loaded
+// with the task.
+func ToTaskResponse(t *models.OpsTask) *TaskResponse {
+	resp := &TaskResponse{
+		ID:             t.ID,
+		Title:          t.Title,
+		Description:    t.Description,
+		Kind:           t.Kind,
+		EntityType:     t.EntityType,
+		EntityID:       t.EntityID,
+		Status:         string(t.Status),
+		Priority:       string(t.Priority),
+		AssigneeID:     t.AssigneeID,
+		CreatedBy:      t.CreatedBy,
+		DueAt:          t.DueAt,
+		Overdue:        t.IsOverdue(time.Now()),
+		CompletedBy:    t.CompletedBy,
+		CompletedAt:    t.CompletedAt,
+		ChecklistTotal: len(t.Checklist),
+		CreatedAt:      t.CreatedAt,
+		UpdatedAt:      t.UpdatedAt,
+	}
+
+	for i := range t.Checklist {
+		item := &t.Checklist[i]
+		if item.Done {
+			resp.ChecklistDone++
+		}
+		resp.Checklist = append(resp.Checklist, ToChecklistItemResponse(item))
+	}
+	for _, w := range t.Watchers {
+		resp.WatcherIDs = append(resp.WatcherIDs, w.UserID)
+	}
+
+	return resp
+}
+
+func ToChecklistItemResponse(item *models.OpsTaskChecklistItem) *ChecklistItemResponse {
+	return &ChecklistItemResponse{
+		ID:       item.ID,
+		Position: item.Position,
+		Title:    item.Title,
+		Done:     item.Done,
+		DoneBy:   item.DoneBy,
+		DoneAt:   item.DoneAt,
+	}
+}
+
+func ToCommentResponse(c *models.OpsTaskComment) *CommentResponse {
+	resp := &CommentResponse{
+		ID:        c.ID,
+		TaskID:    c.TaskID,
+		AuthorID:  c.AuthorID,
+		Body:      c.Body,
+		CreatedAt: c.CreatedAt,
+	}
+	if c.Author != nil {
+		resp.AuthorName = c.Author.Name
+	}
+	return resp
+}
@@ -0,0 +1,301 @@
+package tasks
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/modules/tasks/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// CreateTask godoc
+// @Summary Create an ops task
+// @Description Creates a task for the operations team, optionally linked to a ride, order, account or dispute and with a checklist
+// @Tags admin-tasks
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateTaskRequest true "Task details"
+// @Success 200 {object} response.Response{data=dto.TaskResponse}
+// @Router /admin/tasks [post]
+func (h *Handler) CreateTask(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req dto.CreateTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	task, err := h.service.CreateTask(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, task, "Task created successfully")
+}
+
+// ListTasks godoc
+// @Summary List ops tasks
+// @Description Urgent tasks come first, then the earliest due
+// @Tags admin-tasks
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "Filter by status"
+// @Param priority query string false "Filter by priority"
+// @Param kind query string false "Filter by kind"
+// @Param assigneeId query string false "Filter by assignee"
+// @Param entityType query string false "Filter by linked entity type"
+// @Param entityId query string false "Filter by linked entity ID"
+// @Param mine query bool false "Only tasks assigned to or watched by the caller"
+// @Param overdue query bool false "Only open tasks past their due date"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Response{data=[]dto.TaskResponse}
+// @Router /admin/tasks [get]
+func (h *Handler) ListTasks(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req dto.ListTasksRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+	req.SetDefaults()
+
+	tasks, total, err := h.service.ListTasks(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pagination := response.NewPaginationMeta(total, req.Page, req.Limit)
+	response.Paginated(c, tasks, pagination, "Tasks retrieved successfully")
+}
+
+// GetTask godoc
+// @Summary Get an ops task
+// @Tags admin-tasks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} response.Response{data=dto.TaskResponse}
+// @Router /admin/tasks/{id} [get]
+func (h *Handler) GetTask(c *gin.Context) {
+	task, err := h.service.GetTask(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, task, "Task retrieved successfully")
+}
+
+// UpdateTask godoc
+// @Summary Update an ops task
+// @Description Edits the task or changes its status; a task cannot be marked done while checklist items are open
+// @Tags admin-tasks
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param request body dto.UpdateTaskRequest true "Fields to change"
+// @Success 200 {object} response.Response{data=dto.TaskResponse}
+// @Router /admin/tasks/{id} [patch]
+func (h *Handler) UpdateTask(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req dto.UpdateTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	task, err := h.service.UpdateTask(c.Request.Context(), userID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, task, "Task updated successfully")
+}
+
+// AssignTask godoc
+// @Summary Assign an ops task
+// @Description Assigns the task to an admin, or unassigns it when assigneeId is empty
+// @Tags admin-tasks
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param request body dto.AssignTaskRequest true "Assignee"
+// @Success 200 {object} response.Response{data=dto.TaskResponse}
+// @Router /admin/tasks/{id}/assign [post]
+func (h *Handler) AssignTask(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req dto.AssignTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	task, err := h.service.AssignTask(c.Request.Context(), userID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, task, "Task assigned successfully")
+}
+
+// AddChecklistItem godoc
+// @Summary Add a checklist item to an ops task
+// @Tags admin-tasks
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param request body dto.AddChecklistItemRequest true "Checklist item"
+// @Success 200 {object} response.Response{data=dto.TaskResponse}
+// @Router /admin/tasks/{id}/checklist [post]
+func (h *Handler) AddChecklistItem(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req dto.AddChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	task, err := h.service.AddChecklistItem(c.Request.Context(), userID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, task, "Checklist item added successfully")
+}
+
+// UpdateChecklistItem godoc
+// @Summary Tick off or reopen a checklist item
+// @Tags admin-tasks
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param itemId path string true "Checklist item ID"
+// @Param request body dto.UpdateChecklistItemRequest true "Item state"
+// @Success 200 {object} response.Response{data=dto.TaskResponse}
+// @Router /admin/tasks/{id}/checklist/{itemId} [patch]
+func (h *Handler) UpdateChecklistItem(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req dto.UpdateChecklistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	task, err := h.service.UpdateChecklistItem(c.Request.Context(), userID.(string), c.Param("id"), c.Param("itemId"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, task, "Checklist item updated successfully")
+}
+
+// ListComments godoc
+// @Summary List comments on an ops task
+// @Tags admin-tasks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} response.Response{data=[]dto.CommentResponse}
+// @Router /admin/tasks/{id}/comments [get]
+func (h *Handler) ListComments(c *gin.Context) {
+	comments, err := h.service.ListComments(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, comments, "Comments retrieved successfully")
+}
+
+// AddComment godoc
+// @Summary Comment on an ops task
+// @Description Watchers and the assignee are notified; the author starts watching the task
+// @Tags admin-tasks
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param request body dto.AddCommentRequest true "Comment"
+// @Success 200 {object} response.Response{data=dto.CommentResponse}
+// @Router /admin/tasks/{id}/comments [post]
+func (h *Handler) AddComment(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req dto.AddCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	comment, err := h.service.AddComment(c.Request.Context(), userID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, comment, "Comment added successfully")
+}
+
+// WatchTask godoc
+// @Summary Watch an ops task
+// @Tags admin-tasks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} response.Response{data=dto.TaskResponse}
+// @Router /admin/tasks/{id}/watch [post]
+func (h *Handler) WatchTask(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	task, err := h.service.WatchTask(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, task, "Watching task")
+}
+
+// UnwatchTask godoc
+// @Summary Stop watching an ops task
+// @Tags admin-tasks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} response.Response{data=dto.TaskResponse}
+// @Router /admin/tasks/{id}/watch [delete]
+func (h *Handler) UnwatchTask(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	task, err := h.service.UnwatchTask(c.Request.Context(), userID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, task, "Stopped watching task")
+}
@@ -0,0 +1,208 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/tasks/dto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository interface {
+	CreateTask(ctx context.Context, task *models.OpsTask) error
+	FindTaskByID(ctx context.Context, id string) (*models.OpsTask, error)
+	UpdateTask(ctx context.Context, task *models.OpsTask) error
+	ListTasks(ctx context.Context, viewerID string, req dto.ListTasksRequest) ([]*models.OpsTask, int64, error)
+
+	AddChecklistItem(ctx context.Context, item *models.OpsTaskChecklistItem) error
+	FindChecklistItem(ctx context.Context, taskID, itemID string) (*models.OpsTaskChecklistItem, error)
+	UpdateChecklistItem(ctx context.Context, item *models.OpsTaskChecklistItem) error
+	NextChecklistPosition(ctx context.Context, taskID string) (int, error)
+
+	CreateComment(ctx context.Context, comment *models.OpsTaskComment) error
+	ListComments(ctx context.Context, taskID string) ([]*models.OpsTaskComment, error)
+
+	AddWatchers(ctx context.Context, taskID string, userIDs ...string) error
+	RemoveWatcher(ctx context.Context, taskID, userID string) error
+	ListWatcherIDs(ctx context.Context, taskID string) ([]string, error)
+
+	CountAdmins(ctx context.Context, userIDs []string) (int64, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// CreateTask stores the task together with its checklist and watchers.
+func (r *repository) CreateTask(ctx context.Context, task *models.OpsTask) error {
+	return r.db.WithContext(ctx).Create(task).Error
+}
+
+func (r *repository) FindTaskByID(ctx context.Context, id string) (*models.OpsTask, error) {
+	var task models.OpsTask
+	err := r.db.WithContext(ctx).
+		Preload("Checklist", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC, created_at ASC")
+		}).
+		Preload("Watchers").
+		Where("id = ?", id).
+		First(&task).Error
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+func (r *repository) UpdateTask(ctx context.Context, task *models.OpsTask) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(task).Error
+}
+
+// ListTasks puts urgent work first and, within a priority, the earliest due
+// date first. viewerID is only used for the Mine filter.
+func (r *repository) ListTasks(ctx context.Context, viewerID string, req dto.ListTasksRequest) ([]*models.OpsTask, int64, error) {
+	var tasks []*models.OpsTask
+	var total int64
+
+	base := r.db.WithContext(ctx).Model(&models.OpsTask{})
+	if req.Status != "" {
+		base = base.Where("status = ?", req.Status)
+	}
+	if req.Priority != "" {
+		base = base.Where("priority = ?", req.Priority)
+	}
+	if req.Kind != "" {
+		base = base.Where("kind = ?", req.Kind)
+	}
+	if req.AssigneeID != "" {
+		base = base.Where("assignee_id = ?", req.AssigneeID)
+	}
+	if req.EntityType != "" {
+		base = base.Where("entity_type = ?", req.EntityType)
+	}
+	if req.EntityID != "" {
+		base = base.Where("entity_id = ?", req.EntityID)
+	}
+	if req.Mine {
+		base = base.Where("assignee_id = ? OR id IN (?)", viewerID,
+			r.db.Model(&models.OpsTaskWatcher{}).Select("task_id").Where("user_id = ?", viewerID))
+	}
+	if req.Overdue {
+		base = base.Where("due_at < ? AND status NOT IN ?", time.Now(), []models.OpsTaskStatus{
+			models.OpsTaskStatusDone,
+			models.OpsTaskStatusCancelled,
+		})
+	}
+
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count ops tasks: %w", err)
+	}
+
+	if total == 0 {
+		return []*models.OpsTask{}, 0, nil
+	}
+
+	offset := (req.Page - 1) * req.Limit
+	if err := base.
+		Preload("Checklist", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC, created_at ASC")
+		}).
+		Order("CASE priority WHEN 'urgent' THEN 0 WHEN 'high' THEN 1 WHEN 'normal' THEN 2 ELSE 3 END").
+		Order("due_at ASC NULLS LAST").
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(req.Limit).
+		Find(&tasks).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch ops tasks: %w", err)
+	}
+
+	return tasks, total, nil
+}
+
+func (r *repository) AddChecklistItem(ctx context.Context, item *models.OpsTaskChecklistItem) error {
+	return r.db.WithContext(ctx).Create(item).Error
+}
+
+func (r *repository) FindChecklistItem(ctx context.Context, taskID, itemID string) (*models.OpsTaskChecklistItem, error) {
+	var item models.OpsTaskChecklistItem
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND task_id = ?", itemID, taskID).
+		First(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *repository) UpdateChecklistItem(ctx context.Context, item *models.OpsTaskChecklistItem) error {
+	return r.db.WithContext(ctx).Save(item).Error
+}
+
+func (r *repository) NextChecklistPosition(ctx context.Context, taskID string) (int, error) {
+	var next int
+	err := r.db.WithContext(ctx).
+		Model(&models.OpsTaskChecklistItem{}).
+		Where("task_id = ?", taskID).
+		Select("COALESCE(MAX(position), -1) + 1").
+		Scan(&next).Error
+	return next, err
+}
+
+func (r *repository) CreateComment(ctx context.Context, comment *models.OpsTaskComment) error {
+	return r.db.WithContext(ctx).Create(comment).Error
+}
+
+func (r *repository) ListComments(ctx context.Context, taskID string) ([]*models.OpsTaskComment, error) {
+	var comments []*models.OpsTaskComment
+	err := r.db.WithContext(ctx).
+		Preload("Author").
+		Where("task_id = ?", taskID).
+		Order("created_at ASC").
+		Find(&comments).Error
+	return comments, err
+}
+
+// AddWatchers ignores users already watching the task.
+func (r *repository) AddWatchers(ctx context.Context, taskID string, userIDs ...string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	watchers := make([]models.OpsTaskWatcher, 0, len(userIDs))
+	for _, userID := range userIDs {
+		watchers = append(watchers, models.OpsTaskWatcher{TaskID: taskID, UserID: userID})
+	}
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&watchers).Error
+}
+
+func (r *repository) RemoveWatcher(ctx context.Context, taskID, userID string) error {
+	return r.db.WithContext(ctx).
+		Where("task_id = ? AND user_id = ?", taskID, userID).
+		Delete(&models.OpsTaskWatcher{}).Error
+}
+
+func (r *repository) ListWatcherIDs(ctx context.Context, taskID string) ([]string, error) {
+	var ids []string
+	err := r.db.WithContext(ctx).
+		Model(&models.OpsTaskWatcher{}).
+		Where("task_id = ?", taskID).
+		Pluck("user_id", &ids).Error
+	return ids, err
+}
+
+// CountAdmins counts how many of userIDs are active admin accounts.
+func (r *repository) CountAdmins(ctx context.Context, userIDs []string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.User{}).
+		Where("id IN ? AND role = ? AND status = ?", userIDs, models.RoleAdmin, models.StatusActive).
+		Count(&count).Error
+	return count, err
+}
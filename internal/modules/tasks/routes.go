@@ -0,0 +1,28 @@
+package tasks
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/middleware"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
+	tasks := router.Group("/admin/tasks")
+	tasks.Use(authMiddleware)
+	tasks.Use(middleware.RequireAdmin())
+	{
+		tasks.POST("", handler.CreateTask)
+		tasks.GET("", handler.ListTasks)
+		tasks.GET("/:id", handler.GetTask)
+		tasks.PATCH("/:id", handler.UpdateTask)
+		tasks.POST("/:id/assign", handler.AssignTask)
+
+		tasks.POST("/:id/checklist", handler.AddChecklistItem)
+		tasks.PATCH("/:id/checklist/:itemId", handler.UpdateChecklistItem)
+
+		tasks.GET("/:id/comments", handler.ListComments)
+		tasks.POST("/:id/comments", handler.AddComment)
+
+		tasks.POST("/:id/watch", handler.WatchTask)
+		tasks.DELETE("/:id/watch", handler.UnwatchTask)
+	}
+}
@@ -0,0 +1,496 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	notificationsmodule "github.com/umar5678/go-backend/internal/modules/notifications"
+	"github.com/umar5678/go-backend/internal/modules/tasks/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+	websocketutil "github.com/umar5678/go-backend/internal/websocket/websocketutils"
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	CreateTask(ctx context.Context, adminID string, req dto.CreateTaskRequest) (*dto.TaskResponse, error)
+	GetTask(ctx context.Context, taskID string) (*dto.TaskResponse, error)
+	ListTasks(ctx context.Context, adminID string, req dto.ListTasksRequest) ([]*dto.TaskResponse, int64, error)
+	UpdateTask(ctx context.Context, adminID, taskID string, req dto.UpdateTaskRequest) (*dto.TaskResponse, error)
+	AssignTask(ctx context.Context, adminID, taskID string, req dto.AssignTaskRequest) (*dto.TaskResponse, error)
+
+	AddChecklistItem(ctx context.Context, adminID, taskID string, req dto.AddChecklistItemRequest) (*dto.TaskResponse, error)
+	UpdateChecklistItem(ctx context.Context, adminID, taskID, itemID string, req dto.UpdateChecklistItemRequest) (*dto.TaskResponse, error)
+
+	AddComment(ctx context.Context, adminID, taskID string, req dto.AddCommentRequest) (*dto.CommentResponse, error)
+	ListComments(ctx context.Context, taskID string) ([]*dto.CommentResponse, error)
+
+	WatchTask(ctx context.Context, adminID, taskID string) (*dto.TaskResponse, error)
+	UnwatchTask(ctx context.Context, adminID, taskID string) (*dto.TaskResponse, error)
+}
+
+type service struct {
+	repo          Repository
+	eventProducer notificationsmodule.EventProducer
+}
+
+func NewService(repo Repository) Service {
+	return NewServiceWithNotifications(repo, nil)
+}
+
+func NewServiceWithNotifications(repo Repository, eventProducer notificationsmodule.EventProducer) Service {
+	return &service{
+		repo:          repo,
+		eventProducer: eventProducer,
+	}
+}
+
+// CreateTask opens a task. The creator and the assignee watch it from the
+// start, along with any other admins listed in the request.
+func (s *service) CreateTask(ctx context.Context, adminID string, req dto.CreateTaskRequest) (*dto.TaskResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	watcherIDs := uniqueIDs(append([]string{adminID, req.AssigneeID}, req.WatcherIDs...))
+	if err := s.checkAdmins(ctx, watcherIDs); err != nil {
+		return nil, err
+	}
+
+	task := &models.OpsTask{
+		Title:       strings.TrimSpace(req.Title),
+		Description: req.Description,
+		Kind:        req.Kind,
+		Status:      models.OpsTaskStatusOpen,
+		Priority:    models.OpsTaskPriority(req.Priority),
+		CreatedBy:   adminID,
+		DueAt:       req.DueAt,
+	}
+	if req.EntityType != "" {
+		task.EntityType = &req.EntityType
+		task.EntityID = &req.EntityID
+	}
+	if req.AssigneeID != "" {
+		task.AssigneeID = &req.AssigneeID
+	}
+	for i, title := range req.Checklist {
+		task.Checklist = append(task.Checklist, models.OpsTaskChecklistItem{
+			Position: i,
+			Title:    strings.TrimSpace(title),
+		})
+	}
+	for _, userID := range watcherIDs {
+		task.Watchers = append(task.Watchers, models.OpsTaskWatcher{UserID: userID})
+	}
+
+	if err := s.repo.CreateTask(ctx, task); err != nil {
+		logger.Error("failed to create ops task", "error", err, "adminID", adminID)
+		return nil, response.InternalServerError("Failed to create task", err)
+	}
+
+	if task.AssigneeID != nil && *task.AssigneeID != adminID {
+		s.notifyUser(task, *task.AssigneeID, "assigned", "A task was assigned to you")
+	}
+	s.publishTaskEvent(notificationsmodule.EventOpsTaskCreated, task, adminID, nil)
+
+	logger.Info("ops task created", "taskID", task.ID, "kind", task.Kind, "adminID", adminID)
+	return dto.ToTaskResponse(task), nil
+}
+
+func (s *service) GetTask(ctx context.Context, taskID string) (*dto.TaskResponse, error) {
+	task, err := s.getTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToTaskResponse(task), nil
+}
+
+func (s *service) ListTasks(ctx context.Context, adminID string, req dto.ListTasksRequest) ([]*dto.TaskResponse, int64, error) {
+	tasks, total, err := s.repo.ListTasks(ctx, adminID, req)
+	if err != nil {
+		return nil, 0, response.InternalServerError("Failed to fetch tasks", err)
+	}
+
+	result := make([]*dto.TaskResponse, 0, len(tasks))
+	for _, task := range tasks {
+		result = append(result, dto.ToTaskResponse(task))
+	}
+	return result, total, nil
+}
+
+// UpdateTask edits the task and moves it through its statuses. A task with
+// unfinished checklist items cannot be marked done, and reopening a finished
+// task clears who completed it.
+func (s *service) UpdateTask(ctx context.Context, adminID, taskID string, req dto.UpdateTaskRequest) (*dto.TaskResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	task, err := s.getTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []string
+	if req.Title != nil {
+		task.Title = strings.TrimSpace(*req.Title)
+		changes = append(changes, "title")
+	}
+	if req.Description != nil {
+		task.Description = *req.Description
+		changes = append(changes, "description")
+	}
+	if req.Priority != nil && models.OpsTaskPriority(*req.Priority) != task.Priority {
+		task.Priority = models.OpsTaskPriority(*req.Priority)
+		changes = append(changes, "priority")
+	}
+	if req.DueAt != nil {
+		task.DueAt = req.DueAt
+		changes = append(changes, "dueAt")
+	} else if req.ClearDueAt && task.DueAt != nil {
+		task.DueAt = nil
+		changes = append(changes, "dueAt")
+	}
+	if req.Status != nil && models.OpsTaskStatus(*req.Status) != task.Status {
+		if err := s.changeStatus(task, adminID, models.OpsTaskStatus(*req.Status)); err != nil {
+			return nil, err
+		}
+		changes = append(changes, "status")
+	}
+
+	if len(changes) == 0 {
+		return dto.ToTaskResponse(task), nil
+	}
+
+	if err := s.repo.UpdateTask(ctx, task); err != nil {
+		logger.Error("failed to update ops task", "error", err, "taskID", task.ID)
+		return nil, response.InternalServerError("Failed to update task", err)
+	}
+
+	s.notifyWatchers(ctx, task, adminID, "updated", "Task updated: "+strings.Join(changes, ", "))
+	s.publishTaskEvent(notificationsmodule.EventOpsTaskUpdated, task, adminID, map[string]interface{}{
+		"changes": changes,
+	})
+
+	return dto.ToTaskResponse(task), nil
+}
+
+func (s *service) changeStatus(task *models.OpsTask, adminID string, status models.OpsTaskStatus) error {
+	if status == models.OpsTaskStatusDone {
+		for _, item := range task.Checklist {
+			if !item.Done {
+				return response.BadRequest("Finish the checklist before completing the task")
+			}
+		}
+		now := time.Now()
+		task.CompletedBy = &adminID
+		task.CompletedAt = &now
+	} else {
+		task.CompletedBy = nil
+		task.CompletedAt = nil
+	}
+	task.Status = status
+	return nil
+}
+
+// AssignTask hands the task to another admin, who starts watching it.
+func (s *service) AssignTask(ctx context.Context, adminID, taskID string, req dto.AssignTaskRequest) (*dto.TaskResponse, error) {
+	task, err := s.getOpenTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.AssigneeID == "" {
+		if task.AssigneeID == nil {
+			return dto.ToTaskResponse(task), nil
+		}
+		task.AssigneeID = nil
+	} else {
+		if task.AssigneeID != nil && *task.AssigneeID == req.AssigneeID {
+			return dto.ToTaskResponse(task), nil
+		}
+		if err := s.checkAdmins(ctx, []string{req.AssigneeID}); err != nil {
+			return nil, err
+		}
+		task.AssigneeID = &req.AssigneeID
+	}
+
+	if err := s.repo.UpdateTask(ctx, task); err != nil {
+		logger.Error("failed to assign ops task", "error", err, "taskID", task.ID)
+		return nil, response.InternalServerError("Failed to assign task", err)
+	}
+
+	message := "Task unassigned"
+	if task.AssigneeID != nil {
+		if err := s.repo.AddWatchers(ctx, task.ID, *task.AssigneeID); err != nil {
+			logger.Error("failed to add assignee as task watcher", "error", err, "taskID", task.ID)
+		}
+		if *task.AssigneeID != adminID {
+			s.notifyUser(task, *task.AssigneeID, "assigned", "A task was assigned to you")
+		}
+		message = "Task reassigned"
+	}
+	s.notifyWatchers(ctx, task, adminID, "assigned", message)
+	s.publishTaskEvent(notificationsmodule.EventOpsTaskAssigned, task, adminID, nil)
+
+	return s.GetTask(ctx, task.ID)
+}
+
+func (s *service) AddChecklistItem(ctx context.Context, adminID, taskID string, req dto.AddChecklistItemRequest) (*dto.TaskResponse, error) {
+	task, err := s.getOpenTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	position, err := s.repo.NextChecklistPosition(ctx, task.ID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to add checklist item", err)
+	}
+
+	item := &models.OpsTaskChecklistItem{
+		TaskID:   task.ID,
+		Position: position,
+		Title:    strings.TrimSpace(req.Title),
+	}
+	if err := s.repo.AddChecklistItem(ctx, item); err != nil {
+		logger.Error("failed to add checklist item", "error", err, "taskID", task.ID)
+		return nil, response.InternalServerError("Failed to add checklist item", err)
+	}
+
+	s.notifyWatchers(ctx, task, adminID, "checklist", "Checklist item added: "+item.Title)
+	return s.GetTask(ctx, task.ID)
+}
+
+// UpdateChecklistItem ticks a step off or reopens it.
+func (s *service) UpdateChecklistItem(ctx context.Context, adminID, taskID, itemID string, req dto.UpdateChecklistItemRequest) (*dto.TaskResponse, error) {
+	task, err := s.getOpenTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := s.repo.FindChecklistItem(ctx, task.ID, itemID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Checklist item")
+		}
+		return nil, response.InternalServerError("Failed to fetch checklist item", err)
+	}
+	if item.Done == req.Done {
+		return dto.ToTaskResponse(task), nil
+	}
+
+	item.Done = req.Done
+	message := "Checklist item reopened: " + item.Title
+	if req.Done {
+		now := time.Now()
+		item.DoneBy = &adminID
+		item.DoneAt = &now
+		message = "Checklist item done: " + item.Title
+	} else {
+		item.DoneBy = nil
+		item.DoneAt = nil
+	}
+
+	if err := s.repo.UpdateChecklistItem(ctx, item); err != nil {
+		logger.Error("failed to update checklist item", "error", err, "taskID", task.ID, "itemID", item.ID)
+		return nil, response.InternalServerError("Failed to update checklist item", err)
+	}
+
+	s.notifyWatchers(ctx, task, adminID, "checklist", message)
+	return s.GetTask(ctx, task.ID)
+}
+
+// AddComment posts to the task's discussion. Commenting on a task makes the
+// author a watcher.
+func (s *service) AddComment(ctx context.Context, adminID, taskID string, req dto.AddCommentRequest) (*dto.CommentResponse, error) {
+	if strings.TrimSpace(req.Body) == "" {
+		return nil, response.BadRequest("Comment cannot be empty")
+	}
+
+	task, err := s.getTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &models.OpsTaskComment{
+		TaskID:   task.ID,
+		AuthorID: adminID,
+		Body:     strings.TrimSpace(req.Body),
+	}
+	if err := s.repo.CreateComment(ctx, comment); err != nil {
+		logger.Error("failed to add task comment", "error", err, "taskID", task.ID)
+		return nil, response.InternalServerError("Failed to add comment", err)
+	}
+
+	if err := s.repo.AddWatchers(ctx, task.ID, adminID); err != nil {
+		logger.Error("failed to add commenter as task watcher", "error", err, "taskID", task.ID)
+	}
+
+	s.notifyWatchers(ctx, task, adminID, "comment", "New comment on task")
+	s.publishTaskEvent(notificationsmodule.EventOpsTaskCommented, task, adminID, map[string]interface{}{
+		"comment_id": comment.ID,
+	})
+
+	return dto.ToCommentResponse(comment), nil
+}
+
+func (s *service) ListComments(ctx context.Context, taskID string) ([]*dto.CommentResponse, error) {
+	task, err := s.getTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := s.repo.ListComments(ctx, task.ID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to fetch comments", err)
+	}
+
+	result := make([]*dto.CommentResponse, 0, len(comments))
+	for _, comment := range comments {
+		result = append(result, dto.ToCommentResponse(comment))
+	}
+	return result, nil
+}
+
+func (s *service) WatchTask(ctx context.Context, adminID, taskID string) (*dto.TaskResponse, error) {
+	task, err := s.getTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.AddWatchers(ctx, task.ID, adminID); err != nil {
+		return nil, response.InternalServerError("Failed to watch task", err)
+	}
+	return s.GetTask(ctx, task.ID)
+}
+
+func (s *service) UnwatchTask(ctx context.Context, adminID, taskID string) (*dto.TaskResponse, error) {
+	task, err := s.getTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.RemoveWatcher(ctx, task.ID, adminID); err != nil {
+		return nil, response.InternalServerError("Failed to unwatch task", err)
+	}
+	return s.GetTask(ctx, task.ID)
+}
+
+func (s *service) getTask(ctx context.Context, taskID string) (*models.OpsTask, error) {
+	if taskID == "" {
+		return nil, response.BadRequest("Task ID is required")
+	}
+
+	task, err := s.repo.FindTaskByID(ctx, taskID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Task")
+		}
+		return nil, response.InternalServerError("Failed to fetch task", err)
+	}
+	return task, nil
+}
+
+// getOpenTask loads a task that can still be worked on; done and cancelled
+// tasks have to be reopened first.
+func (s *service) getOpenTask(ctx context.Context, taskID string) (*models.OpsTask, error) {
+	task, err := s.getTask(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if !task.IsOpen() {
+		return nil, response.BadRequest("The task is closed; reopen it first")
+	}
+	return task, nil
+}
+
+// checkAdmins makes sure every user a task is assigned to or watched by is
+// an active admin.
+func (s *service) checkAdmins(ctx context.Context, userIDs []string) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	count, err := s.repo.CountAdmins(ctx, userIDs)
+	if err != nil {
+		return response.InternalServerError("Failed to check task members", err)
+	}
+	if count != int64(len(userIDs)) {
+		return response.BadRequest("Tasks can only be assigned to or watched by active admins")
+	}
+	return nil
+}
+
+// notifyWatchers tells the task's watchers and assignee about a change,
+// leaving out the admin who made it.
+func (s *service) notifyWatchers(ctx context.Context, task *models.OpsTask, actorID, event, message string) {
+	watcherIDs, err := s.repo.ListWatcherIDs(ctx, task.ID)
+	if err != nil {
+		logger.Error("failed to load task watchers", "error", err, "taskID", task.ID)
+		return
+	}
+	if task.AssigneeID != nil {
+		watcherIDs = append(watcherIDs, *task.AssigneeID)
+	}
+
+	for _, userID := range uniqueIDs(watcherIDs) {
+		if userID == actorID {
+			continue
+		}
+		s.notifyUser(task, userID, event, message)
+	}
+}
+
+func (s *service) notifyUser(task *models.OpsTask, userID, event, message string) {
+	websocketutil.SendToUser(userID, websocket.TypeOpsTaskUpdate, map[string]interface{}{
+		"taskId":   task.ID,
+		"title":    task.Title,
+		"status":   task.Status,
+		"priority": task.Priority,
+		"event":    event,
+		"message":  message,
+	})
+}
+
+func (s *service) publishTaskEvent(eventType notificationsmodule.EventType, task *models.OpsTask, actorID string, data map[string]interface{}) {
+	if s.eventProducer == nil {
+		logger.Debug("event producer not available, skipping task event", "eventType", eventType, "taskID", task.ID)
+		return
+	}
+
+	payload := map[string]interface{}{
+		"task_id":     task.ID,
+		"kind":        task.Kind,
+		"entity_type": task.EntityType,
+		"entity_id":   task.EntityID,
+		"status":      task.Status,
+		"priority":    task.Priority,
+		"assignee_id": task.AssigneeID,
+		"actor_id":    actorID,
+		"timestamp":   time.Now().UTC(),
+	}
+	for k, v := range data {
+		payload[k] = v
+	}
+
+	go func() {
+		if err := s.eventProducer.PublishEventWithKey(context.Background(), eventType, task.ID, payload); err != nil {
+			logger.Error("failed to publish task event", "error", err, "eventType", eventType, "taskID", task.ID)
+		}
+	}()
+}
+
+// uniqueIDs drops empty and repeated IDs, keeping the first occurrence.
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, id)
+	}
+	return result
+}
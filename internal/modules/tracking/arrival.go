@@ -0,0 +1,63 @@
+package tracking
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/modules/tracking/dto"
+	"github.com/umar5678/go-backend/internal/utils/location"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+// RideArrivalMarker moves a ride to arrived when its driver reaches the
+// pickup. It is satisfied by rides.Service and is optional.
+type RideArrivalMarker interface {
+	MarkArrivedByGeofence(ctx context.Context, rideID string) error
+}
+
+// OrderArrivalMarker records a provider's arrival at a home services order.
+// It is satisfied by the home services provider service and is optional.
+type OrderArrivalMarker interface {
+	MarkArrivedByGeofence(ctx context.Context, providerID, orderID string) error
+}
+
+// ConfigureArrival sets the geofences used to detect arrivals. Without it
+// arrivals are only marked by hand.
+func (s *service) ConfigureArrival(cfg config.ArrivalConfig) {
+	s.arrival = cfg
+}
+
+func (s *service) SetArrivalMarkers(rides RideArrivalMarker, orders OrderArrivalMarker) {
+	s.rideArrivals = rides
+	s.orderArrivals = orders
+}
+
+// withinGeofence reports whether a location fix is accurate enough to trust
+// and lies within radiusMeters of the target.
+func (s *service) withinGeofence(req dto.UpdateLocationRequest, lat, lng, radiusMeters float64) bool {
+	if !s.arrival.Enabled || (lat == 0 && lng == 0) {
+		return false
+	}
+	if req.Accuracy > s.arrival.MaxAccuracyMeters {
+		return false
+	}
+	return location.HaversineDistance(req.Latitude, req.Longitude, lat, lng)*1000 <= radiusMeters
+}
+
+// detectRideArrival marks the ride arrived once the driver is inside the
+// geofence around the pickup.
+func (s *service) detectRideArrival(ctx context.Context, rideID string, req dto.UpdateLocationRequest) {
+	if s.rideArrivals == nil || !s.arrival.Enabled {
+		return
+	}
+	ride, err := s.repo.GetRidePickup(ctx, rideID)
+	if err != nil || ride.Status != "accepted" {
+		return
+	}
+	if !s.withinGeofence(req, ride.PickupLat, ride.PickupLon, s.arrival.DriverRadiusMeters) {
+		return
+	}
+	if err := s.rideArrivals.MarkArrivedByGeofence(ctx, rideID); err != nil {
+		logger.Warn("failed to mark ride arrived from geofence", "error", err, "rideID", rideID)
+	}
+}
@@ -36,6 +36,10 @@ type FindNearbyDriversRequest struct {
 	VehicleTypeID string  `form:"vehicleTypeId" binding:"omitempty,uuid"`
 	Limit         int     `form:"limit" binding:"omitempty,min=1,max=50"`
 	OnlyAvailable bool    `form:"onlyAvailable"` // Filter only available drivers (not on active ride)
+
+	// ExcludeLowRated leaves out drivers rated below the matching floor. It is
+	// set by dispatch, never from the query string.
+	ExcludeLowRated bool `form:"-" json:"-"`
 }
 
 func (r *FindNearbyDriversRequest) SetDefaults() {
@@ -42,3 +42,15 @@ type PolylineStreamEvent struct {
 	Polyline  string    `json:"polyline"`
 	Timestamp time.Time `json:"timestamp"`
 }
+
+// ProviderLocationResponse is the live location a home services provider
+// shares with the customer while on the way to an order. Sharing is false
+// once the provider has stopped or the job has started.
+type ProviderLocationResponse struct {
+	OrderID    string            `json:"orderId"`
+	ProviderID string            `json:"providerId"`
+	Sharing    bool              `json:"sharing"`
+	Location   *LocationResponse `json:"location,omitempty"`
+	DistanceKm float64           `json:"distanceKm,omitempty"`
+	ETASeconds int               `json:"etaSeconds,omitempty"`
+}
@@ -178,3 +178,63 @@ func (h *Handler) FindNearbyDrivers(c *gin.Context) {
 
 	response.Success(c, result, "Nearby drivers found")
 }
+
+// ShareProviderLocation godoc
+// @Summary Share provider location for a home services order (called by provider app)
+// @Description Send the provider's location while on the way to an accepted order. The customer receives it over WebSocket with an ETA. Once the job starts sharing stops and the response has sharing set to false.
+// @Tags tracking
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param orderId path string true "Order ID"
+// @Param request body dto.UpdateLocationRequest true "Location data"
+// @Success 200 {object} response.Response{data=dto.ProviderLocationResponse}
+// @Failure 403 {object} response.Response
+// @Failure 404 {object} response.Response
+// @Router /tracking/service-orders/{orderId}/location [post]
+func (h *Handler) ShareProviderLocation(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User not authenticated"))
+		return
+	}
+
+	var req dto.UpdateLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	result, err := h.service.ShareProviderLocation(c.Request.Context(), userID.(string), c.Param("orderId"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Location shared successfully")
+}
+
+// GetOrderProviderLocation godoc
+// @Summary Get the provider's live location for a home services order
+// @Tags tracking
+// @Security BearerAuth
+// @Produce json
+// @Param orderId path string true "Order ID"
+// @Success 200 {object} response.Response{data=dto.ProviderLocationResponse}
+// @Failure 404 {object} response.Response
+// @Router /tracking/service-orders/{orderId}/provider-location [get]
+func (h *Handler) GetOrderProviderLocation(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.Error(response.UnauthorizedError("User not authenticated"))
+		return
+	}
+
+	result, err := h.service.GetOrderProviderLocation(c.Request.Context(), userID.(string), c.Param("orderId"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, result, "Provider location retrieved successfully")
+}
@@ -0,0 +1,160 @@
+package tracking
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+	"github.com/umar5678/go-backend/internal/modules/tracking/dto"
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/utils/location"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+	websocketutil "github.com/umar5678/go-backend/internal/websocket/websocketutils"
+)
+
+// providerLocationTTL is how long a shared location is shown to the customer
+// without a fresh update from the provider app.
+const providerLocationTTL = 2 * time.Minute
+
+func providerLocationKey(orderID string) string {
+	return fmt.Sprintf("service_order:provider_location:%s", orderID)
+}
+
+// ShareProviderLocation records the location of a provider on the way to an
+// accepted home services order and pushes it to the customer with an ETA.
+// Once the job has started sharing stops and the response says so.
+func (s *service) ShareProviderLocation(ctx context.Context, userID, orderID string, req dto.UpdateLocationRequest) (*dto.ProviderLocationResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+	if err := location.ValidateCoordinates(req.Latitude, req.Longitude); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+
+	providerID, err := s.repo.GetServiceProviderID(ctx, userID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, response.ForbiddenError("Only service providers can share their location")
+		}
+		return nil, response.InternalServerError("Failed to share location", err)
+	}
+	order, err := s.loadServiceOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.AssignedProviderID == nil || *order.AssignedProviderID != providerID {
+		return nil, response.NotFoundError("Order")
+	}
+
+	result := &dto.ProviderLocationResponse{OrderID: order.ID, ProviderID: providerID}
+	if order.Status != shared.OrderStatusAccepted {
+		s.StopProviderLocationSharing(ctx, order)
+		return result, nil
+	}
+
+	result.Sharing = true
+	result.Location = &dto.LocationResponse{
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+		Heading:   req.Heading,
+		Speed:     req.Speed,
+		Accuracy:  req.Accuracy,
+		Timestamp: time.Now(),
+	}
+	if order.CustomerInfo.Lat != 0 || order.CustomerInfo.Lng != 0 {
+		result.DistanceKm = location.HaversineDistance(req.Latitude, req.Longitude, order.CustomerInfo.Lat, order.CustomerInfo.Lng)
+		result.ETASeconds = location.CalculateETA(result.DistanceKm, req.Speed)
+	}
+
+	if order.ProviderArrivedAt == nil && s.orderArrivals != nil &&
+		s.withinGeofence(req, order.CustomerInfo.Lat, order.CustomerInfo.Lng, s.arrival.ProviderRadiusMeters) {
+		if err := s.orderArrivals.MarkArrivedByGeofence(ctx, providerID, order.ID); err != nil {
+			logger.Warn("failed to mark provider arrived from geofence", "error", err, "orderID", order.ID)
+		}
+	}
+
+	if err := cache.SetJSON(ctx, providerLocationKey(order.ID), result, providerLocationTTL); err != nil {
+		logger.Error("failed to cache provider location", "error", err, "orderID", order.ID)
+	}
+
+	if err := websocketutil.SendToUser(order.CustomerID, websocket.TypeProviderLocation, map[string]interface{}{
+		"orderId":     order.ID,
+		"orderNumber": order.OrderNumber,
+		"providerId":  providerID,
+		"location":    result.Location,
+		"distanceKm":  result.DistanceKm,
+		"etaSeconds":  result.ETASeconds,
+	}); err != nil {
+		logger.Warn("failed to send provider location", "error", err, "orderID", order.ID)
+	}
+
+	logger.Debug("provider location shared", "orderID", order.ID, "providerID", providerID,
+		"lat", req.Latitude, "lng", req.Longitude, "etaSeconds", result.ETASeconds)
+
+	return result, nil
+}
+
+// GetOrderProviderLocation returns the provider's last shared location for
+// one of the customer's orders.
+func (s *service) GetOrderProviderLocation(ctx context.Context, customerID, orderID string) (*dto.ProviderLocationResponse, error) {
+	order, err := s.loadServiceOrder(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order.CustomerID != customerID {
+		return nil, response.NotFoundError("Order")
+	}
+
+	result := &dto.ProviderLocationResponse{OrderID: order.ID}
+	if order.AssignedProviderID != nil {
+		result.ProviderID = *order.AssignedProviderID
+	}
+	if order.Status != shared.OrderStatusAccepted {
+		return result, nil
+	}
+
+	var cached dto.ProviderLocationResponse
+	if err := cache.GetJSON(ctx, providerLocationKey(order.ID), &cached); err != nil || !cached.Sharing {
+		return result, nil
+	}
+	return &cached, nil
+}
+
+// StopProviderLocationSharing clears the provider's shared location for an
+// order and tells the customer the provider is no longer being tracked.
+func (s *service) StopProviderLocationSharing(ctx context.Context, order *models.ServiceOrderNew) {
+	key := providerLocationKey(order.ID)
+	if sharing, err := cache.Exists(ctx, key); err != nil || !sharing {
+		return
+	}
+	if err := cache.Delete(ctx, key); err != nil {
+		logger.Error("failed to clear provider location", "error", err, "orderID", order.ID)
+	}
+
+	if err := websocketutil.SendToUser(order.CustomerID, websocket.TypeProviderLocationStopped, map[string]interface{}{
+		"orderId":     order.ID,
+		"orderNumber": order.OrderNumber,
+		"status":      order.Status,
+	}); err != nil {
+		logger.Warn("failed to send provider location stop", "error", err, "orderID", order.ID)
+	}
+
+	logger.Info("provider location sharing stopped", "orderID", order.ID, "status", order.Status)
+}
+
+func (s *service) loadServiceOrder(ctx context.Context, orderID string) (*models.ServiceOrderNew, error) {
+	order, err := s.repo.GetServiceOrder(ctx, orderID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, response.NotFoundError("Order")
+		}
+		return nil, response.InternalServerError("Failed to get order", err)
+	}
+	return order, nil
+}
@@ -17,6 +17,11 @@ type Repository interface {
 	GetLocationHistory(ctx context.Context, driverID string, from, to time.Time, limit int) ([]*models.DriverLocation, error)
 	FindNearbyDrivers(ctx context.Context, lat, lon, radiusKm float64, vehicleTypeID string, limit int) ([]*models.DriverProfile, error)
 	BatchSaveLocations(ctx context.Context, locations []*models.DriverLocation) error
+	SaveRideTrackPoint(ctx context.Context, point *models.RideTrackPoint) error
+
+	GetServiceProviderID(ctx context.Context, userID string) (string, error)
+	GetServiceOrder(ctx context.Context, orderID string) (*models.ServiceOrderNew, error)
+	GetRidePickup(ctx context.Context, rideID string) (*models.Ride, error)
 
 	GetDB() *gorm.DB
 }
@@ -84,11 +89,13 @@ func (r *repository) FindNearbyDrivers(ctx context.Context, lat, lon, radiusKm f
 		Where("status = ?", "online").
 		Where("is_verified = ?", true).
 		Where("current_location IS NOT NULL").
+		// Vehicles blocked over a lapsed or overdue inspection get no rides.
+		Where("NOT EXISTS (SELECT 1 FROM vehicles iv WHERE iv.id = driver_profiles.active_vehicle_id AND (iv.inspection_blocked OR iv.inspection_valid_until < NOW()))").
 		Where("ST_DWithin(current_location::geography, ST_GeomFromText(?, 4326)::geography, ?)",
 			locationStr, radiusMeters)
 
 	if vehicleTypeID != "" {
-		query = query.Joins("JOIN vehicles ON vehicles.driver_id = driver_profiles.id").
+		query = query.Joins("JOIN vehicles ON vehicles.id = driver_profiles.active_vehicle_id").
 			Where("vehicles.vehicle_type_id = ?", vehicleTypeID).
 			Where("vehicles.is_active = ?", true)
 	}
@@ -109,3 +116,39 @@ func (r *repository) BatchSaveLocations(ctx context.Context, locations []*models
 
 	return r.db.WithContext(ctx).CreateInBatches(locations, 100).Error
 }
+
+func (r *repository) SaveRideTrackPoint(ctx context.Context, point *models.RideTrackPoint) error {
+	return r.db.WithContext(ctx).Create(point).Error
+}
+
+func (r *repository) GetServiceProviderID(ctx context.Context, userID string) (string, error) {
+	var profile models.ServiceProviderProfile
+	err := r.db.WithContext(ctx).
+		Select("id").
+		Where("user_id = ?", userID).
+		First(&profile).Error
+	return profile.ID, err
+}
+
+func (r *repository) GetServiceOrder(ctx context.Context, orderID string) (*models.ServiceOrderNew, error) {
+	var order models.ServiceOrderNew
+	err := r.db.WithContext(ctx).
+		Where("id = ?", orderID).
+		First(&order).Error
+	if err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *repository) GetRidePickup(ctx context.Context, rideID string) (*models.Ride, error) {
+	var ride models.Ride
+	err := r.db.WithContext(ctx).
+		Select("id", "status", "pickup_lat", "pickup_lon").
+		Where("id = ?", rideID).
+		First(&ride).Error
+	if err != nil {
+		return nil, err
+	}
+	return &ride, nil
+}
@@ -9,5 +9,8 @@ func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gi
 
 		tracking.GET("/driver/:driverId", handler.GetDriverLocation)
 		tracking.GET("/nearby", handler.FindNearbyDrivers)
+
+		tracking.POST("/service-orders/:orderId/location", authMiddleware, handler.ShareProviderLocation)
+		tracking.GET("/service-orders/:orderId/provider-location", authMiddleware, handler.GetOrderProviderLocation)
 	}
 }
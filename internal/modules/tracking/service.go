@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/umar5678/go-backend/internal/config"
 	"github.com/umar5678/go-backend/internal/models"
 	notificationsmodule "github.com/umar5678/go-backend/internal/modules/notifications"
 	"github.com/umar5678/go-backend/internal/modules/tracking/dto"
@@ -23,11 +24,23 @@ type Service interface {
 	GetDriverProfileID(ctx context.Context, userID string) (string, error)
 	GetDriverActiveRide(ctx context.Context, driverID string) (rideID, riderID string, err error)
 	UpdateDriverLocationWithStreaming(ctx context.Context, driverID string, req dto.UpdateLocationRequest, activeRideID, riderID string) error
+
+	ShareProviderLocation(ctx context.Context, userID, orderID string, req dto.UpdateLocationRequest) (*dto.ProviderLocationResponse, error)
+	GetOrderProviderLocation(ctx context.Context, customerID, orderID string) (*dto.ProviderLocationResponse, error)
+	StopProviderLocationSharing(ctx context.Context, order *models.ServiceOrderNew)
+
+	ConfigureRatings(cfg config.RatingsConfig)
+	ConfigureArrival(cfg config.ArrivalConfig)
+	SetArrivalMarkers(rides RideArrivalMarker, orders OrderArrivalMarker)
 }
 
 type service struct {
 	repo          Repository
 	eventProducer notificationsmodule.EventProducer
+	ratings       config.RatingsConfig
+	arrival       config.ArrivalConfig
+	rideArrivals  RideArrivalMarker
+	orderArrivals OrderArrivalMarker
 }
 
 func NewService(repo Repository) Service {
@@ -41,6 +54,20 @@ func NewServiceWithNotifications(repo Repository, eventProducer notificationsmod
 	}
 }
 
+// ConfigureRatings sets the rating floor applied to requests that exclude
+// low-rated drivers. Without it no driver is excluded.
+func (s *service) ConfigureRatings(cfg config.RatingsConfig) {
+	s.ratings = cfg
+}
+
+// isLowRated reports whether a driver has enough ratings to be judged and
+// averages below the floor.
+func (s *service) isLowRated(driver *models.DriverProfile) bool {
+	return s.ratings.MinDriverRating > 0 &&
+		driver.RatingCount >= s.ratings.MinRatingsForMatching &&
+		driver.Rating < s.ratings.MinDriverRating
+}
+
 func (s *service) UpdateDriverLocation(ctx context.Context, driverID string, req dto.UpdateLocationRequest) error {
 	if err := req.Validate(); err != nil {
 		return response.BadRequest(err.Error())
@@ -271,6 +298,23 @@ func (s *service) UpdateDriverLocationWithStreaming(ctx context.Context, driverI
 		logger.Error("========================= Empty activeRideID, cannot stream")
 		return nil
 	}
+
+	point := &models.RideTrackPoint{
+		RideID:     activeRideID,
+		DriverID:   driverID,
+		Latitude:   req.Latitude,
+		Longitude:  req.Longitude,
+		Speed:      req.Speed,
+		Accuracy:   req.Accuracy,
+		RecordedAt: time.Now(),
+	}
+	go func() {
+		if err := s.repo.SaveRideTrackPoint(context.Background(), point); err != nil {
+			logger.Error("failed to save ride track point", "error", err, "rideID", activeRideID, "driverID", driverID)
+		}
+	}()
+	s.detectRideArrival(ctx, activeRideID, req)
+
 	if riderID == "" {
 		logger.Error("========================  Empty riderID, cannot stream")
 		return nil
@@ -384,6 +428,11 @@ func (s *service) FindNearbyDrivers(ctx context.Context, req dto.FindNearbyDrive
 	driverResponses := make([]dto.DriverLocationResponse, 0, len(drivers))
 
 	for _, driver := range drivers {
+		if req.ExcludeLowRated && s.isLowRated(driver) {
+			logger.Debug("skipping low-rated driver", "driverID", driver.ID, "rating", driver.Rating)
+			continue
+		}
+
 		if req.OnlyAvailable {
 			isAvailable, err := s.isDriverAvailable(ctx, driver.ID)
 			if err != nil || !isAvailable {
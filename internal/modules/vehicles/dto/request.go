@@ -1 +1,69 @@
 package dto
+
+import "time"
+
+// SubmitInspectionRequest holds the form fields sent alongside the files of a
+// multipart inspection submission.
+type SubmitInspectionRequest struct {
+	InspectedAt *time.Time `form:"inspectedAt" time_format:"2006-01-02"`
+	OdometerKm  *int       `form:"odometerKm" binding:"omitempty,min=0"`
+	Notes       string     `form:"notes" binding:"omitempty,max=1000"`
+}
+
+type ScheduleInspectionRequest struct {
+	DueAt time.Time `json:"dueAt" binding:"required"`
+	Notes string    `json:"notes" binding:"omitempty,max=1000"`
+}
+
+// ReviewInspectionRequest approves or rejects a submitted inspection. An
+// approval lasts for the configured validity unless ValidUntil says otherwise;
+// a rejection needs a note telling the driver what to fix.
+type ReviewInspectionRequest struct {
+	Decision   string     `json:"decision" binding:"required,oneof=approve reject"`
+	Note       string     `json:"note" binding:"omitempty,max=1000"`
+	ValidUntil *time.Time `json:"validUntil"`
+}
+
+// ListInspectionsRequest filters inspections for admin review. The default is
+// inspections submitted and waiting for a decision.
+type ListInspectionsRequest struct {
+	Status    string `form:"status" binding:"omitempty,oneof=scheduled submitted approved rejected overdue"`
+	VehicleID string `form:"vehicleId" binding:"omitempty,uuid"`
+	DriverID  string `form:"driverId" binding:"omitempty,uuid"`
+	Page      int    `form:"page" binding:"omitempty,min=1"`
+	Limit     int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (r *ListInspectionsRequest) SetDefaults() {
+	if r.Status == "" {
+		r.Status = "submitted"
+	}
+	if r.Page == 0 {
+		r.Page = 1
+	}
+	if r.Limit == 0 {
+		r.Limit = 20
+	}
+}
+
+type CreateMaintenanceRecordRequest struct {
+	ServiceType string    `json:"serviceType" binding:"required,max=50"`
+	Description string    `json:"description" binding:"omitempty,max=1000"`
+	OdometerKm  *int      `json:"odometerKm" binding:"omitempty,min=0"`
+	Cost        float64   `json:"cost" binding:"omitempty,min=0"`
+	PerformedAt time.Time `json:"performedAt" binding:"required"`
+}
+
+type ListMaintenanceRecordsRequest struct {
+	Page  int `form:"page" binding:"omitempty,min=1"`
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (r *ListMaintenanceRecordsRequest) SetDefaults() {
+	if r.Page == 0 {
+		r.Page = 1
+	}
+	if r.Limit == 0 {
+		r.Limit = 20
+	}
+}
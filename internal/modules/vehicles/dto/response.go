@@ -7,33 +7,137 @@ import (
 )
 
 type VehicleTypeResponse struct {
-	ID            string    `json:"id"`
-	Name          string    `json:"name"`
-	DisplayName   string    `json:"displayName"`
-	BaseFare      float64   `json:"baseFare"`
-	PerKmRate     float64   `json:"perKmRate"`
-	PerMinuteRate float64   `json:"perMinuteRate"`
-	BookingFee    float64   `json:"bookingFee"`
-	Capacity      int       `json:"capacity"`
-	Description   string    `json:"description"`
-	IsActive      bool      `json:"isActive"`
-	IconURL       string    `json:"iconUrl"`
-	CreatedAt     time.Time `json:"createdAt"`
+	ID              string    `json:"id"`
+	Name            string    `json:"name"`
+	DisplayName     string    `json:"displayName"`
+	BaseFare        float64   `json:"baseFare"`
+	PerKmRate       float64   `json:"perKmRate"`
+	PerMinuteRate   float64   `json:"perMinuteRate"`
+	BookingFee      float64   `json:"bookingFee"`
+	Capacity        int       `json:"capacity"`
+	LuggageCapacity int       `json:"luggageCapacity"`
+	Description     string    `json:"description"`
+	IsActive        bool      `json:"isActive"`
+	IconURL         string    `json:"iconUrl"`
+	CreatedAt       time.Time `json:"createdAt"`
 }
 
 func ToVehicleTypeResponse(vt *models.VehicleType) *VehicleTypeResponse {
 	return &VehicleTypeResponse{
-		ID:            vt.ID,
-		Name:          vt.Name,
-		DisplayName:   vt.DisplayName,
-		BaseFare:      vt.BaseFare,
-		PerKmRate:     vt.PerKmRate,
-		PerMinuteRate: vt.PerMinuteRate,
-		BookingFee:    vt.BookingFee,
-		Capacity:      vt.Capacity,
-		Description:   vt.Description,
-		IsActive:      vt.IsActive,
-		IconURL:       vt.IconURL,
-		CreatedAt:     vt.CreatedAt,
+		ID:              vt.ID,
+		Name:            vt.Name,
+		DisplayName:     vt.DisplayName,
+		BaseFare:        vt.BaseFare,
+		PerKmRate:       vt.PerKmRate,
+		PerMinuteRate:   vt.PerMinuteRate,
+		BookingFee:      vt.BookingFee,
+		Capacity:        vt.Capacity,
+		LuggageCapacity: vt.LuggageCapacity,
+		Description:     vt.Description,
+		IsActive:        vt.IsActive,
+		IconURL:         vt.IconURL,
+		CreatedAt:       vt.CreatedAt,
+	}
+}
+
+type InspectionFileResponse struct {
+	ID        string    `json:"id"`
+	Kind      string    `json:"kind"`
+	FileName  string    `json:"fileName"`
+	FileURL   string    `json:"fileUrl"`
+	FileSize  int64     `json:"fileSize"`
+	MimeType  string    `json:"mimeType"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type InspectionResponse struct {
+	ID           string                    `json:"id"`
+	VehicleID    string                    `json:"vehicleId"`
+	DriverID     string                    `json:"driverId"`
+	LicensePlate string                    `json:"licensePlate,omitempty"`
+	Status       string                    `json:"status"`
+	DueAt        *time.Time                `json:"dueAt,omitempty"`
+	InspectedAt  *time.Time                `json:"inspectedAt,omitempty"`
+	SubmittedAt  *time.Time                `json:"submittedAt,omitempty"`
+	OdometerKm   *int                      `json:"odometerKm,omitempty"`
+	Notes        string                    `json:"notes,omitempty"`
+	ValidUntil   *time.Time                `json:"validUntil,omitempty"`
+	ReviewedBy   *string                   `json:"reviewedBy,omitempty"`
+	ReviewNote   string                    `json:"reviewNote,omitempty"`
+	ReviewedAt   *time.Time                `json:"reviewedAt,omitempty"`
+	Files        []*InspectionFileResponse `json:"files"`
+	CreatedAt    time.Time                 `json:"createdAt"`
+}
+
+func ToInspectionResponse(inspection *models.VehicleInspection) *InspectionResponse {
+	resp := &InspectionResponse{
+		ID:          inspection.ID,
+		VehicleID:   inspection.VehicleID,
+		DriverID:    inspection.DriverID,
+		Status:      string(inspection.Status),
+		DueAt:       inspection.DueAt,
+		InspectedAt: inspection.InspectedAt,
+		SubmittedAt: inspection.SubmittedAt,
+		OdometerKm:  inspection.OdometerKm,
+		Notes:       inspection.Notes,
+		ValidUntil:  inspection.ValidUntil,
+		ReviewedBy:  inspection.ReviewedBy,
+		ReviewNote:  inspection.ReviewNote,
+		ReviewedAt:  inspection.ReviewedAt,
+		Files:       make([]*InspectionFileResponse, 0, len(inspection.Files)),
+		CreatedAt:   inspection.CreatedAt,
+	}
+	if inspection.Vehicle != nil {
+		resp.LicensePlate = inspection.Vehicle.LicensePlate
+	}
+	for _, f := range inspection.Files {
+		resp.Files = append(resp.Files, &InspectionFileResponse{
+			ID:        f.ID,
+			Kind:      f.Kind,
+			FileName:  f.FileName,
+			FileURL:   f.FileURL,
+			FileSize:  f.FileSize,
+			MimeType:  f.MimeType,
+			CreatedAt: f.CreatedAt,
+		})
+	}
+	return resp
+}
+
+// VehicleInspectionStatusResponse is what a driver sees about their vehicle's
+// inspections: whether it may take rides, what is due next and past results.
+type VehicleInspectionStatusResponse struct {
+	VehicleID            string                `json:"vehicleId"`
+	LicensePlate         string                `json:"licensePlate"`
+	InspectionValidUntil *time.Time            `json:"inspectionValidUntil,omitempty"`
+	Blocked              bool                  `json:"blocked"`
+	BlockedAt            *time.Time            `json:"blockedAt,omitempty"`
+	Current              *InspectionResponse   `json:"current,omitempty"`
+	History              []*InspectionResponse `json:"history"`
+}
+
+type MaintenanceRecordResponse struct {
+	ID          string    `json:"id"`
+	VehicleID   string    `json:"vehicleId"`
+	DriverID    string    `json:"driverId"`
+	ServiceType string    `json:"serviceType"`
+	Description string    `json:"description,omitempty"`
+	OdometerKm  *int      `json:"odometerKm,omitempty"`
+	Cost        float64   `json:"cost"`
+	PerformedAt time.Time `json:"performedAt"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+func ToMaintenanceRecordResponse(record *models.VehicleMaintenanceRecord) *MaintenanceRecordResponse {
+	return &MaintenanceRecordResponse{
+		ID:          record.ID,
+		VehicleID:   record.VehicleID,
+		DriverID:    record.DriverID,
+		ServiceType: record.ServiceType,
+		Description: record.Description,
+		OdometerKm:  record.OdometerKm,
+		Cost:        record.Cost,
+		PerformedAt: record.PerformedAt,
+		CreatedAt:   record.CreatedAt,
 	}
 }
@@ -2,7 +2,7 @@ package vehicles
 
 import (
 	"github.com/gin-gonic/gin"
-	_ "github.com/umar5678/go-backend/internal/modules/vehicles/dto"
+	"github.com/umar5678/go-backend/internal/modules/vehicles/dto"
 	"github.com/umar5678/go-backend/internal/utils/response"
 )
 
@@ -64,3 +64,256 @@ func (h *Handler) GetVehicleTypeByID(c *gin.Context) {
 
 	response.Success(c, vehicleType, "Vehicle type retrieved successfully")
 }
+
+// GetMyInspections godoc
+// @Summary Get my vehicle's inspections (Driver)
+// @Description Whether the vehicle may take rides, the inspection currently due or under review, and past inspections
+// @Tags vehicles
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=dto.VehicleInspectionStatusResponse}
+// @Router /vehicles/me/inspections [get]
+func (h *Handler) GetMyInspections(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	status, err := h.service.GetMyInspections(c.Request.Context(), userID.(string))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, status, "Vehicle inspections retrieved successfully")
+}
+
+// SubmitInspection godoc
+// @Summary Submit a vehicle inspection (Driver)
+// @Description Uploads the inspection certificate and photos for admin review. Completes the inspection that is due, if any.
+// @Tags vehicles
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param files formData file true "Inspection certificate and photos (repeat the field for several files)"
+// @Param inspectedAt formData string false "Date of the inspection (YYYY-MM-DD, default today)"
+// @Param odometerKm formData int false "Odometer reading in km"
+// @Param notes formData string false "Notes for the reviewer"
+// @Success 200 {object} response.Response{data=dto.InspectionResponse}
+// @Failure 400 {object} response.Response
+// @Failure 409 {object} response.Response
+// @Router /vehicles/me/inspections [post]
+func (h *Handler) SubmitInspection(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req dto.SubmitInspectionRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.Error(response.BadRequest("Invalid form fields"))
+		return
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		c.Error(response.BadRequest("Inspection files are required"))
+		return
+	}
+
+	inspection, err := h.service.SubmitInspection(c.Request.Context(), userID.(string), req, form.File["files"])
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, inspection, "Inspection submitted for review")
+}
+
+// LogMaintenance godoc
+// @Summary Log vehicle maintenance (Driver)
+// @Tags vehicles
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateMaintenanceRecordRequest true "Maintenance details"
+// @Success 200 {object} response.Response{data=dto.MaintenanceRecordResponse}
+// @Router /vehicles/me/maintenance [post]
+func (h *Handler) LogMaintenance(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req dto.CreateMaintenanceRecordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	record, err := h.service.LogMaintenance(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, record, "Maintenance record saved")
+}
+
+// ListMyMaintenance godoc
+// @Summary List my vehicle's maintenance records (Driver)
+// @Tags vehicles
+// @Security BearerAuth
+// @Produce json
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Response{data=[]dto.MaintenanceRecordResponse}
+// @Router /vehicles/me/maintenance [get]
+func (h *Handler) ListMyMaintenance(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req dto.ListMaintenanceRecordsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+	req.SetDefaults()
+
+	records, total, err := h.service.ListMyMaintenance(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pagination := response.NewPaginationMeta(total, req.Page, req.Limit)
+	response.Paginated(c, records, pagination, "Maintenance records retrieved successfully")
+}
+
+// ListInspections godoc
+// @Summary List vehicle inspections (admin)
+// @Description Defaults to inspections submitted and waiting for review, oldest first
+// @Tags vehicles
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "scheduled, submitted, approved, rejected or overdue (default submitted)"
+// @Param vehicleId query string false "Vehicle ID"
+// @Param driverId query string false "Driver profile ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Response{data=[]dto.InspectionResponse}
+// @Router /vehicles/admin/inspections [get]
+func (h *Handler) ListInspections(c *gin.Context) {
+	var req dto.ListInspectionsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+	req.SetDefaults()
+
+	inspections, total, err := h.service.ListInspections(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pagination := response.NewPaginationMeta(total, req.Page, req.Limit)
+	response.Paginated(c, inspections, pagination, "Inspections retrieved successfully")
+}
+
+// GetInspection godoc
+// @Summary Get a vehicle inspection (admin)
+// @Tags vehicles
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Inspection ID"
+// @Success 200 {object} response.Response{data=dto.InspectionResponse}
+// @Failure 404 {object} response.Response
+// @Router /vehicles/admin/inspections/{id} [get]
+func (h *Handler) GetInspection(c *gin.Context) {
+	inspection, err := h.service.GetInspection(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, inspection, "Inspection retrieved successfully")
+}
+
+// ReviewInspection godoc
+// @Summary Approve or reject a vehicle inspection (admin)
+// @Description Approval unblocks the vehicle and schedules the next periodic inspection; rejection requires a note for the driver
+// @Tags vehicles
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Inspection ID"
+// @Param request body dto.ReviewInspectionRequest true "Decision"
+// @Success 200 {object} response.Response{data=dto.InspectionResponse}
+// @Failure 400 {object} response.Response
+// @Router /vehicles/admin/inspections/{id}/review [post]
+func (h *Handler) ReviewInspection(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	var req dto.ReviewInspectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	inspection, err := h.service.ReviewInspection(c.Request.Context(), adminID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, inspection, "Inspection reviewed successfully")
+}
+
+// ScheduleInspection godoc
+// @Summary Schedule an inspection for a vehicle (admin)
+// @Description The vehicle is blocked from rides if no inspection is submitted by the due date
+// @Tags vehicles
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Vehicle ID"
+// @Param request body dto.ScheduleInspectionRequest true "Due date"
+// @Success 200 {object} response.Response{data=dto.InspectionResponse}
+// @Failure 409 {object} response.Response
+// @Router /vehicles/admin/vehicles/{id}/inspections [post]
+func (h *Handler) ScheduleInspection(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	var req dto.ScheduleInspectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	inspection, err := h.service.ScheduleInspection(c.Request.Context(), adminID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, inspection, "Inspection scheduled successfully")
+}
+
+// ListVehicleMaintenance godoc
+// @Summary List a vehicle's maintenance records (admin)
+// @Tags vehicles
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Vehicle ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Response{data=[]dto.MaintenanceRecordResponse}
+// @Router /vehicles/admin/vehicles/{id}/maintenance [get]
+func (h *Handler) ListVehicleMaintenance(c *gin.Context) {
+	var req dto.ListMaintenanceRecordsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+	req.SetDefaults()
+
+	records, total, err := h.service.ListVehicleMaintenance(c.Request.Context(), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pagination := response.NewPaginationMeta(total, req.Page, req.Limit)
+	response.Paginated(c, records, pagination, "Maintenance records retrieved successfully")
+}
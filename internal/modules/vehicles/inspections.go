@@ -0,0 +1,550 @@
+package vehicles
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"strings"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/vehicles/dto"
+	"github.com/umar5678/go-backend/internal/services/health"
+	imagekit "github.com/umar5678/go-backend/internal/services/imagekit"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+	websocketutil "github.com/umar5678/go-backend/internal/websocket/websocketutils"
+	"gorm.io/gorm"
+)
+
+const inspectionHistoryLimit = 10
+
+// ConfigureInspections enables vehicle inspections. Uploads go to ImageKit,
+// so the whole config is needed rather than just the inspection settings.
+func (s *service) ConfigureInspections(cfg *config.Config) {
+	s.cfg = cfg
+}
+
+func (s *service) GetMyInspections(ctx context.Context, userID string) (*dto.VehicleInspectionStatusResponse, error) {
+	vehicle, err := s.getDriverVehicle(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	inspections, err := s.repo.ListVehicleInspections(ctx, vehicle.ID, inspectionHistoryLimit)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to fetch inspections", err)
+	}
+
+	resp := &dto.VehicleInspectionStatusResponse{
+		VehicleID:            vehicle.ID,
+		LicensePlate:         vehicle.LicensePlate,
+		InspectionValidUntil: vehicle.InspectionValidUntil,
+		Blocked:              vehicle.InspectionLapsed(time.Now()),
+		BlockedAt:            vehicle.InspectionBlockedAt,
+		History:              make([]*dto.InspectionResponse, 0, len(inspections)),
+	}
+	for _, inspection := range inspections {
+		item := dto.ToInspectionResponse(inspection)
+		item.LicensePlate = vehicle.LicensePlate
+		if resp.Current == nil && inspection.Status != models.VehicleInspectionApproved {
+			resp.Current = item
+			continue
+		}
+		resp.History = append(resp.History, item)
+	}
+
+	return resp, nil
+}
+
+// SubmitInspection uploads the driver's inspection certificate and photos.
+// The submission completes the inspection that was scheduled, overdue or
+// rejected, or starts a new one when nothing was due.
+func (s *service) SubmitInspection(ctx context.Context, userID string, req dto.SubmitInspectionRequest, files []*multipart.FileHeader) (*dto.InspectionResponse, error) {
+	if s.cfg == nil {
+		return nil, response.ServiceUnavailable("Vehicle inspections are not configured")
+	}
+	if len(files) == 0 {
+		return nil, response.BadRequest("At least one inspection document or photo is required")
+	}
+	if len(files) > s.cfg.Inspections.MaxFiles {
+		return nil, response.BadRequest(fmt.Sprintf("At most %d files can be uploaded per inspection", s.cfg.Inspections.MaxFiles))
+	}
+
+	now := time.Now()
+	inspectedAt := now
+	if req.InspectedAt != nil {
+		if req.InspectedAt.After(now) {
+			return nil, response.BadRequest("Inspection date cannot be in the future")
+		}
+		inspectedAt = *req.InspectedAt
+	}
+
+	allowedMimes := imagekit.AllowedDocumentMimeTypes()
+	for _, file := range files {
+		if file.Size > s.cfg.Upload.ImageKit.DocumentsMaxSize {
+			return nil, response.BadRequest(fmt.Sprintf("%s exceeds the maximum size of %d bytes", file.Filename, s.cfg.Upload.ImageKit.DocumentsMaxSize))
+		}
+		if !isAllowedMime(file.Header.Get("Content-Type"), allowedMimes) {
+			return nil, response.BadRequest(fmt.Sprintf("Invalid file type for %s. Allowed types: %v", file.Filename, allowedMimes))
+		}
+	}
+
+	driver, err := s.repo.FindDriverWithVehicle(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Driver profile")
+		}
+		return nil, response.InternalServerError("Failed to fetch driver profile", err)
+	}
+	if driver.Vehicle == nil {
+		return nil, response.BadRequest("Register a vehicle before submitting an inspection")
+	}
+	vehicle := driver.Vehicle
+
+	inspection, err := s.repo.FindOpenInspection(ctx, vehicle.ID)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		inspection = &models.VehicleInspection{VehicleID: vehicle.ID, DriverID: driver.ID}
+	case err != nil:
+		return nil, response.InternalServerError("Failed to fetch inspection", err)
+	case inspection.Status == models.VehicleInspectionSubmitted:
+		return nil, response.ConflictError("An inspection is already waiting for review")
+	}
+
+	uploaded := make([]*models.VehicleInspectionFile, 0, len(files))
+	for _, file := range files {
+		result, err := imagekit.UploadDocumentToImageKit(s.cfg, file, "vehicle-inspection", driver.User.Name)
+		if err != nil {
+			s.discardUploads(uploaded)
+			logger.Error("failed to upload inspection file", "error", err, "vehicleID", vehicle.ID, "fileName", file.Filename)
+			return nil, response.InternalServerError("Failed to upload inspection file", err)
+		}
+
+		mimeType := file.Header.Get("Content-Type")
+		kind := models.VehicleInspectionFilePhoto
+		if !strings.HasPrefix(mimeType, "image/") {
+			kind = models.VehicleInspectionFileDocument
+		}
+		uploaded = append(uploaded, &models.VehicleInspectionFile{
+			Kind:           kind,
+			FileName:       file.Filename,
+			FileURL:        result.URL,
+			FileSize:       file.Size,
+			MimeType:       mimeType,
+			ImageKitFileID: result.FileID,
+		})
+	}
+
+	inspection.Status = models.VehicleInspectionSubmitted
+	inspection.InspectedAt = &inspectedAt
+	inspection.SubmittedAt = &now
+	inspection.OdometerKm = req.OdometerKm
+	inspection.Notes = req.Notes
+	inspection.ReviewNote = ""
+
+	if err := s.repo.SaveSubmission(ctx, inspection, uploaded); err != nil {
+		s.discardUploads(uploaded)
+		return nil, response.InternalServerError("Failed to submit inspection", err)
+	}
+
+	logger.Info("vehicle inspection submitted",
+		"inspectionID", inspection.ID,
+		"vehicleID", vehicle.ID,
+		"driverID", driver.ID,
+		"files", len(uploaded),
+	)
+
+	if err := websocketutil.BroadcastToRole("admin", websocket.TypeVehicleInspectionUpdate, map[string]interface{}{
+		"inspectionId": inspection.ID,
+		"vehicleId":    vehicle.ID,
+		"licensePlate": vehicle.LicensePlate,
+		"status":       inspection.Status,
+		"timestamp":    now.UTC(),
+	}); err != nil {
+		logger.Warn("failed to notify admins of inspection submission", "error", err, "inspectionID", inspection.ID)
+	}
+
+	inspection, err = s.repo.FindInspectionByID(ctx, inspection.ID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to fetch inspection", err)
+	}
+	return dto.ToInspectionResponse(inspection), nil
+}
+
+func (s *service) ListInspections(ctx context.Context, req dto.ListInspectionsRequest) ([]*dto.InspectionResponse, int64, error) {
+	inspections, total, err := s.repo.ListInspections(ctx, req.Status, req.VehicleID, req.DriverID, req.Page, req.Limit)
+	if err != nil {
+		return nil, 0, response.InternalServerError("Failed to list inspections", err)
+	}
+
+	result := make([]*dto.InspectionResponse, 0, len(inspections))
+	for _, inspection := range inspections {
+		result = append(result, dto.ToInspectionResponse(inspection))
+	}
+	return result, total, nil
+}
+
+func (s *service) GetInspection(ctx context.Context, id string) (*dto.InspectionResponse, error) {
+	inspection, err := s.getInspection(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToInspectionResponse(inspection), nil
+}
+
+// ScheduleInspection asks the driver to have the vehicle inspected by dueAt.
+// If nothing is submitted by then the vehicle is blocked from rides.
+func (s *service) ScheduleInspection(ctx context.Context, adminID, vehicleID string, req dto.ScheduleInspectionRequest) (*dto.InspectionResponse, error) {
+	if !req.DueAt.After(time.Now()) {
+		return nil, response.BadRequest("Due date must be in the future")
+	}
+
+	vehicle, err := s.repo.FindVehicleByID(ctx, vehicleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Vehicle")
+		}
+		return nil, response.InternalServerError("Failed to fetch vehicle", err)
+	}
+
+	if _, err := s.repo.FindOpenInspection(ctx, vehicle.ID); err == nil {
+		return nil, response.ConflictError("Vehicle already has an inspection in progress")
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, response.InternalServerError("Failed to fetch inspection", err)
+	}
+
+	dueAt := req.DueAt
+	inspection := &models.VehicleInspection{
+		VehicleID: vehicle.ID,
+		DriverID:  vehicle.DriverID,
+		Status:    models.VehicleInspectionScheduled,
+		DueAt:     &dueAt,
+		Notes:     req.Notes,
+	}
+	if err := s.repo.CreateInspection(ctx, inspection); err != nil {
+		return nil, response.InternalServerError("Failed to schedule inspection", err)
+	}
+	inspection.Vehicle = vehicle
+
+	logger.Info("vehicle inspection scheduled", "inspectionID", inspection.ID, "vehicleID", vehicle.ID, "dueAt", dueAt, "adminID", adminID)
+
+	s.notifyDriver(ctx, vehicle.DriverID, websocket.TypeVehicleInspectionDue, map[string]interface{}{
+		"inspectionId": inspection.ID,
+		"vehicleId":    vehicle.ID,
+		"dueAt":        dueAt,
+	})
+
+	return dto.ToInspectionResponse(inspection), nil
+}
+
+// ReviewInspection approves or rejects a submitted inspection. Approving
+// unblocks the vehicle and schedules the next periodic inspection for when
+// this one runs out; rejecting leaves the driver to resubmit before the due
+// date.
+func (s *service) ReviewInspection(ctx context.Context, adminID, id string, req dto.ReviewInspectionRequest) (*dto.InspectionResponse, error) {
+	inspection, err := s.getInspection(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if inspection.Status != models.VehicleInspectionSubmitted {
+		return nil, response.BadRequest("Only submitted inspections can be reviewed")
+	}
+
+	now := time.Now()
+	inspection.ReviewedBy = &adminID
+	inspection.ReviewedAt = &now
+	inspection.ReviewNote = req.Note
+
+	if req.Decision == "reject" {
+		if strings.TrimSpace(req.Note) == "" {
+			return nil, response.BadRequest("A note is required when rejecting an inspection")
+		}
+		inspection.Status = models.VehicleInspectionRejected
+		if err := s.repo.UpdateInspection(ctx, inspection); err != nil {
+			return nil, response.InternalServerError("Failed to review inspection", err)
+		}
+	} else {
+		validUntil, err := s.inspectionValidUntil(inspection, req, now)
+		if err != nil {
+			return nil, err
+		}
+		inspection.Status = models.VehicleInspectionApproved
+		inspection.ValidUntil = &validUntil
+
+		next := &models.VehicleInspection{
+			VehicleID: inspection.VehicleID,
+			DriverID:  inspection.DriverID,
+			Status:    models.VehicleInspectionScheduled,
+			DueAt:     &validUntil,
+		}
+		if err := s.repo.ApproveInspection(ctx, inspection, next); err != nil {
+			return nil, response.InternalServerError("Failed to review inspection", err)
+		}
+	}
+
+	logger.Info("vehicle inspection reviewed",
+		"inspectionID", inspection.ID,
+		"vehicleID", inspection.VehicleID,
+		"status", inspection.Status,
+		"adminID", adminID,
+	)
+
+	s.notifyDriver(ctx, inspection.DriverID, websocket.TypeVehicleInspectionUpdate, map[string]interface{}{
+		"inspectionId": inspection.ID,
+		"vehicleId":    inspection.VehicleID,
+		"status":       inspection.Status,
+		"note":         inspection.ReviewNote,
+		"validUntil":   inspection.ValidUntil,
+	})
+
+	return dto.ToInspectionResponse(inspection), nil
+}
+
+func (s *service) LogMaintenance(ctx context.Context, userID string, req dto.CreateMaintenanceRecordRequest) (*dto.MaintenanceRecordResponse, error) {
+	if req.PerformedAt.After(time.Now()) {
+		return nil, response.BadRequest("Maintenance date cannot be in the future")
+	}
+
+	vehicle, err := s.getDriverVehicle(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &models.VehicleMaintenanceRecord{
+		VehicleID:   vehicle.ID,
+		DriverID:    vehicle.DriverID,
+		ServiceType: strings.TrimSpace(req.ServiceType),
+		Description: req.Description,
+		OdometerKm:  req.OdometerKm,
+		Cost:        req.Cost,
+		PerformedAt: req.PerformedAt,
+	}
+	if err := s.repo.CreateMaintenanceRecord(ctx, record); err != nil {
+		return nil, response.InternalServerError("Failed to save maintenance record", err)
+	}
+
+	return dto.ToMaintenanceRecordResponse(record), nil
+}
+
+func (s *service) ListMyMaintenance(ctx context.Context, userID string, req dto.ListMaintenanceRecordsRequest) ([]*dto.MaintenanceRecordResponse, int64, error) {
+	vehicle, err := s.getDriverVehicle(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.listMaintenance(ctx, vehicle.ID, req)
+}
+
+func (s *service) ListVehicleMaintenance(ctx context.Context, vehicleID string, req dto.ListMaintenanceRecordsRequest) ([]*dto.MaintenanceRecordResponse, int64, error) {
+	if _, err := s.repo.FindVehicleByID(ctx, vehicleID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, 0, response.NotFoundError("Vehicle")
+		}
+		return nil, 0, response.InternalServerError("Failed to fetch vehicle", err)
+	}
+	return s.listMaintenance(ctx, vehicleID, req)
+}
+
+// SweepInspections reminds drivers of inspections coming due, marks missed
+// ones overdue and blocks vehicles that may no longer take rides.
+func (s *service) SweepInspections(ctx context.Context) error {
+	if s.cfg == nil {
+		return nil
+	}
+	now := time.Now()
+
+	due, err := s.repo.FindInspectionsDueForReminder(ctx, now.Add(s.cfg.Inspections.ReminderLead))
+	if err != nil {
+		return err
+	}
+	for _, inspection := range due {
+		s.notifyDriver(ctx, inspection.DriverID, websocket.TypeVehicleInspectionDue, map[string]interface{}{
+			"inspectionId": inspection.ID,
+			"vehicleId":    inspection.VehicleID,
+			"dueAt":        inspection.DueAt,
+		})
+		if err := s.repo.MarkInspectionReminded(ctx, inspection.ID, now); err != nil {
+			logger.Warn("failed to mark inspection reminded", "error", err, "inspectionID", inspection.ID)
+		}
+	}
+
+	overdue, err := s.repo.FindOverdueInspections(ctx, now)
+	if err != nil {
+		return err
+	}
+	for _, inspection := range overdue {
+		moved, err := s.repo.MarkInspectionOverdue(ctx, inspection.ID, inspection.Status)
+		if err != nil {
+			logger.Error("failed to mark inspection overdue", "error", err, "inspectionID", inspection.ID)
+			continue
+		}
+		if moved {
+			s.blockVehicle(ctx, inspection.VehicleID, inspection.DriverID, "inspection_overdue", now)
+		}
+	}
+
+	lapsed, err := s.repo.FindLapsedVehicles(ctx, now)
+	if err != nil {
+		return err
+	}
+	for _, vehicle := range lapsed {
+		s.blockVehicle(ctx, vehicle.ID, vehicle.DriverID, "inspection_expired", now)
+	}
+
+	if len(due) > 0 || len(overdue) > 0 || len(lapsed) > 0 {
+		logger.Info("vehicle inspection sweep completed", "reminded", len(due), "overdue", len(overdue), "lapsed", len(lapsed))
+	}
+	return nil
+}
+
+func (s *service) blockVehicle(ctx context.Context, vehicleID, driverID, reason string, at time.Time) {
+	blocked, err := s.repo.BlockVehicle(ctx, vehicleID, at)
+	if err != nil {
+		logger.Error("failed to block vehicle", "error", err, "vehicleID", vehicleID, "reason", reason)
+		return
+	}
+	if !blocked {
+		return
+	}
+
+	logger.Warn("vehicle blocked from rides", "vehicleID", vehicleID, "driverID", driverID, "reason", reason)
+
+	s.notifyDriver(ctx, driverID, websocket.TypeVehicleInspectionBlocked, map[string]interface{}{
+		"vehicleId": vehicleID,
+		"reason":    reason,
+		"blockedAt": at.UTC(),
+	})
+}
+
+func (s *service) notifyDriver(ctx context.Context, driverProfileID string, messageType websocket.MessageType, data map[string]interface{}) {
+	userID, err := s.repo.FindDriverUserID(ctx, driverProfileID)
+	if err != nil || userID == "" {
+		logger.Warn("failed to resolve driver for inspection notification", "error", err, "driverID", driverProfileID)
+		return
+	}
+	if err := websocketutil.SendToUser(userID, messageType, data); err != nil {
+		logger.Warn("failed to notify driver about inspection", "error", err, "userID", userID, "type", messageType)
+	}
+}
+
+func (s *service) inspectionValidUntil(inspection *models.VehicleInspection, req dto.ReviewInspectionRequest, now time.Time) (time.Time, error) {
+	if req.ValidUntil != nil {
+		if !req.ValidUntil.After(now) {
+			return time.Time{}, response.BadRequest("Valid until must be in the future")
+		}
+		return *req.ValidUntil, nil
+	}
+
+	from := now
+	if inspection.InspectedAt != nil {
+		from = *inspection.InspectedAt
+	}
+	validity := 180 * 24 * time.Hour
+	if s.cfg != nil && s.cfg.Inspections.Validity > 0 {
+		validity = s.cfg.Inspections.Validity
+	}
+	validUntil := from.Add(validity)
+	if !validUntil.After(now) {
+		return time.Time{}, response.BadRequest("Inspection is too old to approve")
+	}
+	return validUntil, nil
+}
+
+func (s *service) listMaintenance(ctx context.Context, vehicleID string, req dto.ListMaintenanceRecordsRequest) ([]*dto.MaintenanceRecordResponse, int64, error) {
+	records, total, err := s.repo.ListMaintenanceRecords(ctx, vehicleID, req.Page, req.Limit)
+	if err != nil {
+		return nil, 0, response.InternalServerError("Failed to list maintenance records", err)
+	}
+
+	result := make([]*dto.MaintenanceRecordResponse, 0, len(records))
+	for _, record := range records {
+		result = append(result, dto.ToMaintenanceRecordResponse(record))
+	}
+	return result, total, nil
+}
+
+func (s *service) getDriverVehicle(ctx context.Context, userID string) (*models.Vehicle, error) {
+	driver, err := s.repo.FindDriverWithVehicle(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Driver profile")
+		}
+		return nil, response.InternalServerError("Failed to fetch driver profile", err)
+	}
+	if driver.Vehicle == nil {
+		return nil, response.NotFoundError("Vehicle")
+	}
+	return driver.Vehicle, nil
+}
+
+func (s *service) getInspection(ctx context.Context, id string) (*models.VehicleInspection, error) {
+	inspection, err := s.repo.FindInspectionByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Inspection")
+		}
+		return nil, response.InternalServerError("Failed to fetch inspection", err)
+	}
+	return inspection, nil
+}
+
+// discardUploads removes files already sent to ImageKit when the submission
+// they belong to cannot be saved.
+func (s *service) discardUploads(files []*models.VehicleInspectionFile) {
+	for _, f := range files {
+		if err := imagekit.DeleteFileFromImageKit(s.cfg, f.ImageKitFileID); err != nil {
+			logger.Warn("failed to delete orphaned inspection file", "error", err, "fileID", f.ImageKitFileID)
+		}
+	}
+}
+
+func isAllowedMime(mimeType string, allowed []string) bool {
+	for _, m := range allowed {
+		if m == mimeType {
+			return true
+		}
+	}
+	return false
+}
+
+// InspectionSweeper runs the inspection sweep on a fixed interval.
+type InspectionSweeper struct {
+	service  Service
+	interval time.Duration
+}
+
+func NewInspectionSweeper(service Service, cfg config.VehicleInspectionConfig) *InspectionSweeper {
+	interval := cfg.SweepInterval
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &InspectionSweeper{service: service, interval: interval}
+}
+
+func (w *InspectionSweeper) Start(ctx context.Context) {
+	heartbeat := health.NewHeartbeat("inspection_sweeper", w.interval)
+
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				runCtx, cancel := context.WithTimeout(ctx, time.Minute)
+				if err := w.service.SweepInspections(runCtx); err != nil {
+					logger.Error("vehicle inspection sweep failed", "error", err)
+				}
+				cancel()
+				heartbeat.Beat()
+			}
+		}
+	}()
+
+	logger.Info("vehicle inspection sweeper started", "interval", w.interval)
+}
@@ -2,6 +2,7 @@ package vehicles
 
 import (
 	"context"
+	"time"
 
 	"github.com/umar5678/go-backend/internal/models"
 	"gorm.io/gorm"
@@ -12,6 +13,29 @@ type Repository interface {
 	FindActive(ctx context.Context) ([]*models.VehicleType, error)
 	FindByID(ctx context.Context, id string) (*models.VehicleType, error)
 	FindByName(ctx context.Context, name string) (*models.VehicleType, error)
+
+	FindDriverWithVehicle(ctx context.Context, userID string) (*models.DriverProfile, error)
+	FindVehicleByID(ctx context.Context, id string) (*models.Vehicle, error)
+	FindDriverUserID(ctx context.Context, driverProfileID string) (string, error)
+
+	FindInspectionByID(ctx context.Context, id string) (*models.VehicleInspection, error)
+	FindOpenInspection(ctx context.Context, vehicleID string) (*models.VehicleInspection, error)
+	ListVehicleInspections(ctx context.Context, vehicleID string, limit int) ([]*models.VehicleInspection, error)
+	ListInspections(ctx context.Context, status, vehicleID, driverID string, page, limit int) ([]*models.VehicleInspection, int64, error)
+	CreateInspection(ctx context.Context, inspection *models.VehicleInspection) error
+	UpdateInspection(ctx context.Context, inspection *models.VehicleInspection) error
+	SaveSubmission(ctx context.Context, inspection *models.VehicleInspection, files []*models.VehicleInspectionFile) error
+	ApproveInspection(ctx context.Context, inspection *models.VehicleInspection, next *models.VehicleInspection) error
+
+	FindInspectionsDueForReminder(ctx context.Context, dueBefore time.Time) ([]*models.VehicleInspection, error)
+	MarkInspectionReminded(ctx context.Context, id string, at time.Time) error
+	FindOverdueInspections(ctx context.Context, now time.Time) ([]*models.VehicleInspection, error)
+	MarkInspectionOverdue(ctx context.Context, id string, fromStatus models.VehicleInspectionStatus) (bool, error)
+	FindLapsedVehicles(ctx context.Context, now time.Time) ([]*models.Vehicle, error)
+	BlockVehicle(ctx context.Context, vehicleID string, at time.Time) (bool, error)
+
+	CreateMaintenanceRecord(ctx context.Context, record *models.VehicleMaintenanceRecord) error
+	ListMaintenanceRecords(ctx context.Context, vehicleID string, page, limit int) ([]*models.VehicleMaintenanceRecord, int64, error)
 }
 
 type repository struct {
@@ -54,3 +78,224 @@ func (r *repository) FindByName(ctx context.Context, name string) (*models.Vehic
 		First(&vehicleType).Error
 	return &vehicleType, err
 }
+
+func (r *repository) FindDriverWithVehicle(ctx context.Context, userID string) (*models.DriverProfile, error) {
+	var driver models.DriverProfile
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Preload("Vehicle").
+		Where("user_id = ?", userID).
+		First(&driver).Error
+	return &driver, err
+}
+
+func (r *repository) FindVehicleByID(ctx context.Context, id string) (*models.Vehicle, error) {
+	var vehicle models.Vehicle
+	err := r.db.WithContext(ctx).
+		Where("id = ?", id).
+		First(&vehicle).Error
+	return &vehicle, err
+}
+
+func (r *repository) FindDriverUserID(ctx context.Context, driverProfileID string) (string, error) {
+	var userID string
+	err := r.db.WithContext(ctx).
+		Model(&models.DriverProfile{}).
+		Where("id = ?", driverProfileID).
+		Pluck("user_id", &userID).Error
+	return userID, err
+}
+
+func (r *repository) FindInspectionByID(ctx context.Context, id string) (*models.VehicleInspection, error) {
+	var inspection models.VehicleInspection
+	err := r.db.WithContext(ctx).
+		Preload("Vehicle").
+		Preload("Files").
+		Where("id = ?", id).
+		First(&inspection).Error
+	return &inspection, err
+}
+
+// FindOpenInspection returns the vehicle's inspection that is still waiting
+// on the driver or on review. There is at most one.
+func (r *repository) FindOpenInspection(ctx context.Context, vehicleID string) (*models.VehicleInspection, error) {
+	statuses := append([]models.VehicleInspectionStatus{models.VehicleInspectionSubmitted}, models.VehicleInspectionOpenStatuses...)
+
+	var inspection models.VehicleInspection
+	err := r.db.WithContext(ctx).
+		Preload("Files").
+		Where("vehicle_id = ? AND status IN ?", vehicleID, statuses).
+		Order("created_at DESC").
+		First(&inspection).Error
+	return &inspection, err
+}
+
+func (r *repository) ListVehicleInspections(ctx context.Context, vehicleID string, limit int) ([]*models.VehicleInspection, error) {
+	var inspections []*models.VehicleInspection
+	err := r.db.WithContext(ctx).
+		Preload("Files").
+		Where("vehicle_id = ?", vehicleID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&inspections).Error
+	return inspections, err
+}
+
+func (r *repository) ListInspections(ctx context.Context, status, vehicleID, driverID string, page, limit int) ([]*models.VehicleInspection, int64, error) {
+	query := r.db.WithContext(ctx).Model(&models.VehicleInspection{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if vehicleID != "" {
+		query = query.Where("vehicle_id = ?", vehicleID)
+	}
+	if driverID != "" {
+		query = query.Where("driver_id = ?", driverID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var inspections []*models.VehicleInspection
+	err := query.
+		Preload("Vehicle").
+		Preload("Files").
+		Order("COALESCE(submitted_at, due_at, created_at) ASC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&inspections).Error
+	return inspections, total, err
+}
+
+func (r *repository) CreateInspection(ctx context.Context, inspection *models.VehicleInspection) error {
+	return r.db.WithContext(ctx).Create(inspection).Error
+}
+
+func (r *repository) UpdateInspection(ctx context.Context, inspection *models.VehicleInspection) error {
+	return r.db.WithContext(ctx).Omit("Vehicle", "Files").Save(inspection).Error
+}
+
+// SaveSubmission stores a driver's submission with its uploaded files,
+// creating the inspection first when none was scheduled.
+func (r *repository) SaveSubmission(ctx context.Context, inspection *models.VehicleInspection, files []*models.VehicleInspectionFile) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if inspection.ID == "" {
+			if err := tx.Omit("Vehicle", "Files").Create(inspection).Error; err != nil {
+				return err
+			}
+		} else if err := tx.Omit("Vehicle", "Files").Save(inspection).Error; err != nil {
+			return err
+		}
+
+		for _, f := range files {
+			f.InspectionID = inspection.ID
+		}
+		if len(files) > 0 {
+			return tx.Create(&files).Error
+		}
+		return nil
+	})
+}
+
+// ApproveInspection records the approval, puts the vehicle back on the road
+// until the new expiry and schedules the next periodic inspection.
+func (r *repository) ApproveInspection(ctx context.Context, inspection *models.VehicleInspection, next *models.VehicleInspection) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Vehicle", "Files").Save(inspection).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.Vehicle{}).
+			Where("id = ?", inspection.VehicleID).
+			Updates(map[string]interface{}{
+				"inspection_valid_until": inspection.ValidUntil,
+				"inspection_blocked":     false,
+				"inspection_blocked_at":  nil,
+			}).Error; err != nil {
+			return err
+		}
+
+		if next == nil {
+			return nil
+		}
+		return tx.Create(next).Error
+	})
+}
+
+func (r *repository) FindInspectionsDueForReminder(ctx context.Context, dueBefore time.Time) ([]*models.VehicleInspection, error) {
+	var inspections []*models.VehicleInspection
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND reminded_at IS NULL AND due_at IS NOT NULL AND due_at <= ?", models.VehicleInspectionScheduled, dueBefore).
+		Find(&inspections).Error
+	return inspections, err
+}
+
+func (r *repository) MarkInspectionReminded(ctx context.Context, id string, at time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.VehicleInspection{}).
+		Where("id = ?", id).
+		Update("reminded_at", at).Error
+}
+
+func (r *repository) FindOverdueInspections(ctx context.Context, now time.Time) ([]*models.VehicleInspection, error) {
+	var inspections []*models.VehicleInspection
+	err := r.db.WithContext(ctx).
+		Where("status IN ? AND due_at IS NOT NULL AND due_at < ?",
+			[]models.VehicleInspectionStatus{models.VehicleInspectionScheduled, models.VehicleInspectionRejected}, now).
+		Find(&inspections).Error
+	return inspections, err
+}
+
+// MarkInspectionOverdue only moves an inspection still in fromStatus, so a
+// submission that lands during the sweep is not overwritten.
+func (r *repository) MarkInspectionOverdue(ctx context.Context, id string, fromStatus models.VehicleInspectionStatus) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.VehicleInspection{}).
+		Where("id = ? AND status = ?", id, fromStatus).
+		Update("status", models.VehicleInspectionOverdue)
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *repository) FindLapsedVehicles(ctx context.Context, now time.Time) ([]*models.Vehicle, error) {
+	var vehicles []*models.Vehicle
+	err := r.db.WithContext(ctx).
+		Where("inspection_blocked = ? AND inspection_valid_until IS NOT NULL AND inspection_valid_until < ?", false, now).
+		Find(&vehicles).Error
+	return vehicles, err
+}
+
+func (r *repository) BlockVehicle(ctx context.Context, vehicleID string, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.Vehicle{}).
+		Where("id = ? AND inspection_blocked = ?", vehicleID, false).
+		Updates(map[string]interface{}{
+			"inspection_blocked":    true,
+			"inspection_blocked_at": at,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *repository) CreateMaintenanceRecord(ctx context.Context, record *models.VehicleMaintenanceRecord) error {
+	return r.db.WithContext(ctx).Create(record).Error
+}
+
+func (r *repository) ListMaintenanceRecords(ctx context.Context, vehicleID string, page, limit int) ([]*models.VehicleMaintenanceRecord, int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&models.VehicleMaintenanceRecord{}).
+		Where("vehicle_id = ?", vehicleID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var records []*models.VehicleMaintenanceRecord
+	err := query.
+		Order("performed_at DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&records).Error
+	return records, total, err
+}
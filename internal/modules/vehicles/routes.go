@@ -2,13 +2,31 @@ package vehicles
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/middleware"
 )
 
-func RegisterRoutes(router *gin.RouterGroup, handler *Handler) {
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
 	vehicles := router.Group("/vehicles")
 	{
 		vehicles.GET("/types", handler.GetAllVehicleTypes)
 		vehicles.GET("/types/active", handler.GetActiveVehicleTypes)
 		vehicles.GET("/types/:id", handler.GetVehicleTypeByID)
 	}
+
+	driver := vehicles.Group("/me", authMiddleware, middleware.RequireRole("driver"))
+	{
+		driver.GET("/inspections", handler.GetMyInspections)
+		driver.POST("/inspections", handler.SubmitInspection)
+		driver.GET("/maintenance", handler.ListMyMaintenance)
+		driver.POST("/maintenance", handler.LogMaintenance)
+	}
+
+	admin := vehicles.Group("/admin", authMiddleware, middleware.RequireAdmin())
+	{
+		admin.GET("/inspections", handler.ListInspections)
+		admin.GET("/inspections/:id", handler.GetInspection)
+		admin.POST("/inspections/:id/review", handler.ReviewInspection)
+		admin.POST("/vehicles/:id/inspections", handler.ScheduleInspection)
+		admin.GET("/vehicles/:id/maintenance", handler.ListVehicleMaintenance)
+	}
 }
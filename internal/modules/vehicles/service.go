@@ -3,8 +3,10 @@ package vehicles
 import (
 	"context"
 	"fmt"
+	"mime/multipart"
 	"time"
 
+	"github.com/umar5678/go-backend/internal/config"
 	"github.com/umar5678/go-backend/internal/modules/notifications"
 	"github.com/umar5678/go-backend/internal/modules/vehicles/dto"
 	"github.com/umar5678/go-backend/internal/services/cache"
@@ -16,11 +18,26 @@ type Service interface {
 	GetAllVehicleTypes(ctx context.Context) ([]*dto.VehicleTypeResponse, error)
 	GetActiveVehicleTypes(ctx context.Context) ([]*dto.VehicleTypeResponse, error)
 	GetVehicleTypeByID(ctx context.Context, id string) (*dto.VehicleTypeResponse, error)
+
+	GetMyInspections(ctx context.Context, userID string) (*dto.VehicleInspectionStatusResponse, error)
+	SubmitInspection(ctx context.Context, userID string, req dto.SubmitInspectionRequest, files []*multipart.FileHeader) (*dto.InspectionResponse, error)
+	LogMaintenance(ctx context.Context, userID string, req dto.CreateMaintenanceRecordRequest) (*dto.MaintenanceRecordResponse, error)
+	ListMyMaintenance(ctx context.Context, userID string, req dto.ListMaintenanceRecordsRequest) ([]*dto.MaintenanceRecordResponse, int64, error)
+
+	ListInspections(ctx context.Context, req dto.ListInspectionsRequest) ([]*dto.InspectionResponse, int64, error)
+	GetInspection(ctx context.Context, id string) (*dto.InspectionResponse, error)
+	ScheduleInspection(ctx context.Context, adminID, vehicleID string, req dto.ScheduleInspectionRequest) (*dto.InspectionResponse, error)
+	ReviewInspection(ctx context.Context, adminID, id string, req dto.ReviewInspectionRequest) (*dto.InspectionResponse, error)
+	ListVehicleMaintenance(ctx context.Context, vehicleID string, req dto.ListMaintenanceRecordsRequest) ([]*dto.MaintenanceRecordResponse, int64, error)
+
+	SweepInspections(ctx context.Context) error
+	ConfigureInspections(cfg *config.Config)
 }
 
 type service struct {
 	repo          Repository
 	eventProducer notifications.EventProducer
+	cfg           *config.Config
 }
 
 func NewService(repo Repository) Service {
@@ -0,0 +1,66 @@
+package wallet
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/audit"
+)
+
+// SetAuditLogger records admin interventions on money. Wallet transactions
+// themselves are audited by audit.MoneyMovementPlugin.
+func (s *service) SetAuditLogger(auditLogger audit.Recorder) {
+	s.auditLogger = auditLogger
+}
+
+func (s *service) auditPayoutCredit(ctx context.Context, adminID, action string, before, after *models.PayoutCredit, reason string) {
+	if s.auditLogger == nil {
+		return
+	}
+	s.auditLogger.Record(ctx, audit.Entry{
+		ActorID:    adminID,
+		ActorRole:  string(models.RoleAdmin),
+		Action:     action,
+		Category:   models.AuditCategoryFinancial,
+		EntityType: "payout_credit",
+		EntityID:   after.ID,
+		Before:     payoutCreditSnapshot(before),
+		After:      payoutCreditSnapshot(after),
+		Reason:     reason,
+	})
+}
+
+func payoutCreditSnapshot(credit *models.PayoutCredit) map[string]interface{} {
+	return map[string]interface{}{
+		"status":   credit.Status,
+		"amount":   credit.Amount,
+		"attempts": credit.Attempts,
+	}
+}
+
+func (s *service) auditDriverCredit(ctx context.Context, adminID, action, driverID string, before, after map[string]interface{}, reason string) {
+	if s.auditLogger == nil {
+		return
+	}
+	s.auditLogger.Record(ctx, audit.Entry{
+		ActorID:    adminID,
+		ActorRole:  string(models.RoleAdmin),
+		Action:     action,
+		Category:   models.AuditCategoryFinancial,
+		EntityType: "driver_profile",
+		EntityID:   driverID,
+		Before:     before,
+		After:      after,
+		Reason:     reason,
+	})
+}
+
+func driverCreditSnapshot(driver *models.DriverProfile) map[string]interface{} {
+	return map[string]interface{}{
+		"creditTier":          driver.CreditTier,
+		"creditLimitOverride": driver.CreditLimitOverride,
+		"minBalanceThreshold": driver.MinBalanceThreshold,
+		"creditOverrideUntil": driver.CreditOverrideUntil,
+		"isRestricted":        driver.IsRestricted,
+	}
+}
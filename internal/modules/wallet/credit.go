@@ -0,0 +1,318 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/wallet/dto"
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+const repaymentSummaryDays = 30
+
+func (s *service) ConfigureDriverCredit(cfg config.DriverCreditConfig) {
+	s.driverCredit = cfg
+}
+
+// creditLimit is how much the driver may owe: an admin's override, or else
+// the limit of their tier. Without configured tiers the stored threshold
+// stands.
+func (s *service) creditLimit(driver *models.DriverProfile) float64 {
+	if driver.CreditLimitOverride != nil {
+		return *driver.CreditLimitOverride
+	}
+	if len(s.driverCredit.TierLimits) == 0 {
+		return -driver.MinBalanceThreshold
+	}
+	return s.driverCredit.Limit(driver.CreditTier)
+}
+
+// SyncDriverCreditLimits brings every driver's stored threshold in line with
+// the configured tier limits, so that a changed limit applies to drivers who
+// have not been touched since.
+func (s *service) SyncDriverCreditLimits(ctx context.Context) error {
+	if len(s.driverCredit.TierLimits) == 0 {
+		return nil
+	}
+	updated, err := s.repo.SyncCreditThresholds(ctx, s.driverCredit)
+	if err != nil {
+		return err
+	}
+	if updated > 0 {
+		logger.Info("driver credit limits synced with tiers", "drivers", updated)
+	}
+	return nil
+}
+
+func (s *service) ListDriverCreditTiers(ctx context.Context) []dto.DriverCreditTierResponse {
+	tiers := make([]dto.DriverCreditTierResponse, 0, len(s.driverCredit.TierLimits))
+	for tier, limit := range s.driverCredit.TierLimits {
+		tiers = append(tiers, dto.DriverCreditTierResponse{
+			Tier:    tier,
+			Limit:   limit,
+			Default: tier == s.driverCredit.DefaultTier,
+		})
+	}
+	sort.Slice(tiers, func(i, j int) bool { return tiers[i].Limit < tiers[j].Limit })
+	return tiers
+}
+
+func (s *service) GetDriverCredit(ctx context.Context, driverID string) (*dto.DriverCreditResponse, error) {
+	driver, err := s.findDriver(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+	return s.driverCreditResponse(ctx, driver)
+}
+
+// UpdateDriverCredit changes a driver's tier or limit and re-applies the
+// restriction rules against the new limit straight away.
+func (s *service) UpdateDriverCredit(ctx context.Context, adminID, driverID string, req dto.UpdateDriverCreditRequest) (*dto.DriverCreditResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+	driver, err := s.findDriver(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Tier != nil {
+		if _, ok := s.driverCredit.TierLimits[*req.Tier]; !ok {
+			return nil, response.BadRequest(fmt.Sprintf("Unknown credit tier '%s'", *req.Tier))
+		}
+	}
+
+	before := driverCreditSnapshot(driver)
+	if req.Tier != nil {
+		driver.CreditTier = *req.Tier
+	}
+	if req.LimitOverride != nil {
+		driver.CreditLimitOverride = req.LimitOverride
+	}
+	if req.ClearLimitOverride {
+		driver.CreditLimitOverride = nil
+	}
+	driver.MinBalanceThreshold = -s.creditLimit(driver)
+
+	if err := s.repo.UpdateDriverProfile(ctx, driver.ID, map[string]interface{}{
+		"credit_tier":           driver.CreditTier,
+		"credit_limit_override": driver.CreditLimitOverride,
+		"min_balance_threshold": driver.MinBalanceThreshold,
+	}); err != nil {
+		return nil, response.InternalServerError("Failed to update credit limit", err)
+	}
+	s.invalidateDriverProfile(ctx, driver)
+
+	// A higher limit lets a restricted driver back on the road at once; a
+	// lower one restricts them if they already owe more.
+	if err := s.reapplyCreditLimit(ctx, driver); err != nil {
+		return nil, err
+	}
+
+	logger.Info("driver credit limit updated",
+		"driverID", driver.ID, "adminID", adminID,
+		"tier", driver.CreditTier, "limit", -driver.MinBalanceThreshold)
+	s.auditDriverCredit(ctx, adminID, "driver_credit.update", driver.ID, before, driverCreditSnapshot(driver), req.Reason)
+
+	return s.GetDriverCredit(ctx, driver.ID)
+}
+
+// SetDriverCreditOverride lets a driver go online until the given time
+// whatever they owe, lifting any restriction for that long.
+func (s *service) SetDriverCreditOverride(ctx context.Context, adminID, driverID string, req dto.DriverCreditOverrideRequest) (*dto.DriverCreditResponse, error) {
+	if !req.Until.After(time.Now()) {
+		return nil, response.BadRequest("until must be in the future")
+	}
+	driver, err := s.findDriver(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := driverCreditSnapshot(driver)
+	driver.CreditOverrideUntil = &req.Until
+	driver.CreditOverrideReason = &req.Reason
+	if err := s.repo.UpdateDriverProfile(ctx, driver.ID, map[string]interface{}{
+		"credit_override_until":  driver.CreditOverrideUntil,
+		"credit_override_reason": driver.CreditOverrideReason,
+	}); err != nil {
+		return nil, response.InternalServerError("Failed to set credit override", err)
+	}
+	s.invalidateDriverProfile(ctx, driver)
+
+	if driver.IsRestricted {
+		if err := s.UnrestrictDriverAccount(ctx, driver.ID); err != nil {
+			return nil, err
+		}
+		driver.IsRestricted = false
+	}
+
+	logger.Info("driver credit override set", "driverID", driver.ID, "adminID", adminID, "until", req.Until)
+	s.auditDriverCredit(ctx, adminID, "driver_credit.override", driver.ID, before, driverCreditSnapshot(driver), req.Reason)
+
+	return s.GetDriverCredit(ctx, driver.ID)
+}
+
+// ClearDriverCreditOverride ends an override early; a driver still over
+// their limit is restricted again.
+func (s *service) ClearDriverCreditOverride(ctx context.Context, adminID, driverID string) (*dto.DriverCreditResponse, error) {
+	driver, err := s.findDriver(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+	if driver.CreditOverrideUntil == nil {
+		return nil, response.BadRequest("Driver has no credit override")
+	}
+
+	before := driverCreditSnapshot(driver)
+	driver.CreditOverrideUntil = nil
+	driver.CreditOverrideReason = nil
+	if err := s.repo.UpdateDriverProfile(ctx, driver.ID, map[string]interface{}{
+		"credit_override_until":  nil,
+		"credit_override_reason": nil,
+	}); err != nil {
+		return nil, response.InternalServerError("Failed to clear credit override", err)
+	}
+	s.invalidateDriverProfile(ctx, driver)
+
+	if _, _, err := s.CheckAndEnforceAccountRestriction(ctx, driver.ID); err != nil {
+		return nil, err
+	}
+
+	logger.Info("driver credit override cleared", "driverID", driver.ID, "adminID", adminID)
+	s.auditDriverCredit(ctx, adminID, "driver_credit.clear_override", driver.ID, before, driverCreditSnapshot(driver), "")
+
+	return s.GetDriverCredit(ctx, driver.ID)
+}
+
+// ListDebtRepayments lists what paid down a driver's negative balance,
+// newest first. driverID may be the profile ID or the user ID.
+func (s *service) ListDebtRepayments(ctx context.Context, driverID string, req dto.ListDebtRepaymentsRequest) ([]*dto.DebtRepaymentResponse, int64, error) {
+	req.SetDefaults()
+
+	driver, err := s.findDriver(ctx, driverID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	repayments, total, err := s.repo.ListDebtRepayments(ctx, driver.UserID, req.Page, req.Limit)
+	if err != nil {
+		return nil, 0, response.InternalServerError("Failed to fetch debt repayments", err)
+	}
+
+	result := make([]*dto.DebtRepaymentResponse, len(repayments))
+	for i, repayment := range repayments {
+		result[i] = dto.ToDebtRepaymentResponse(repayment)
+	}
+	return result, total, nil
+}
+
+func (s *service) findDriver(ctx context.Context, driverID string) (*models.DriverProfile, error) {
+	driver, err := s.repo.FindDriverProfile(ctx, driverID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Driver")
+		}
+		return nil, response.InternalServerError("Failed to fetch driver profile", err)
+	}
+	return driver, nil
+}
+
+// reapplyCreditLimit restricts a driver who owes more than their limit and
+// lifts the restriction of one who no longer does.
+func (s *service) reapplyCreditLimit(ctx context.Context, driver *models.DriverProfile) error {
+	wallet, err := s.repo.FindWalletByUserID(ctx, driver.UserID, models.WalletTypeDriver)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return response.InternalServerError("Failed to fetch wallet", err)
+	}
+
+	if driver.IsRestricted && wallet.Balance >= driver.MinBalanceThreshold {
+		if err := s.UnrestrictDriverAccount(ctx, driver.ID); err != nil {
+			return err
+		}
+		driver.IsRestricted = false
+		return nil
+	}
+	_, _, err = s.CheckAndEnforceAccountRestriction(ctx, driver.ID)
+	return err
+}
+
+func (s *service) driverCreditResponse(ctx context.Context, driver *models.DriverProfile) (*dto.DriverCreditResponse, error) {
+	limit := s.creditLimit(driver)
+	result := &dto.DriverCreditResponse{
+		DriverID:          driver.ID,
+		UserID:            driver.UserID,
+		Tier:              driver.CreditTier,
+		TierLimit:         s.driverCredit.Limit(driver.CreditTier),
+		LimitOverride:     driver.CreditLimitOverride,
+		CreditLimit:       limit,
+		Currency:          s.defaultCurrency(),
+		IsRestricted:      driver.IsRestricted,
+		RestrictionReason: driver.RestrictionReason,
+		RestrictedAt:      driver.RestrictedAt,
+		OverrideUntil:     driver.CreditOverrideUntil,
+		OverrideReason:    driver.CreditOverrideReason,
+		OverrideActive:    driver.CreditOverrideActive(time.Now()),
+	}
+
+	wallet, err := s.repo.FindWalletByUserID(ctx, driver.UserID, models.WalletTypeDriver)
+	switch {
+	case err == nil:
+		result.Balance = wallet.Balance
+		result.Currency = wallet.Currency
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, response.InternalServerError("Failed to fetch wallet", err)
+	}
+
+	result.Debt = roundAmount(math.Max(0, -result.Balance))
+	result.AvailableCredit = roundAmount(math.Max(0, limit-result.Debt))
+	// A restricted driver goes back online once the balance is non-negative.
+	if driver.IsRestricted && !result.OverrideActive {
+		result.AmountNeededToGoLive = result.Debt
+	}
+
+	since := time.Now().AddDate(0, 0, -repaymentSummaryDays)
+	repaid, lastAt, err := s.repo.SumDebtRepayments(ctx, driver.UserID, since)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to fetch debt repayments", err)
+	}
+	result.RepaidLast30Days = roundAmount(repaid)
+	result.LastRepaymentAt = lastAt
+
+	return result, nil
+}
+
+// recordDebtRepayment notes how much of a credit to a driver wallet paid
+// down its negative balance. It runs in the transaction that posted txn.
+func recordDebtRepayment(tx *gorm.DB, wallet *models.Wallet, txn *models.WalletTransaction) error {
+	if wallet.WalletType != models.WalletTypeDriver || txn.Type != models.TransactionTypeCredit || txn.BalanceBefore >= 0 {
+		return nil
+	}
+
+	debtBefore := -txn.BalanceBefore
+	debtAfter := math.Max(0, -txn.BalanceAfter)
+	return tx.Create(&models.DriverDebtRepayment{
+		UserID:        wallet.UserID,
+		WalletID:      wallet.ID,
+		TransactionID: txn.ID,
+		Amount:        roundAmount(debtBefore - debtAfter),
+		DebtBefore:    roundAmount(debtBefore),
+		DebtAfter:     roundAmount(debtAfter),
+		Source:        derefString(txn.ReferenceType),
+		Currency:      wallet.Currency,
+	}).Error
+}
+
+func (s *service) invalidateDriverProfile(ctx context.Context, driver *models.DriverProfile) {
+	cache.Delete(ctx, fmt.Sprintf("driver:profile:%s", driver.UserID))
+}
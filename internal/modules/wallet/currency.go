@@ -0,0 +1,61 @@
+package wallet
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/currency"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+// fallbackCurrency is what wallets were opened in before currencies were
+// configurable.
+const fallbackCurrency = "INR"
+
+// SetCurrencies lets wallets open in the platform's default currency and
+// accept amounts in other currencies.
+func (s *service) SetCurrencies(currencies currency.Service) {
+	s.currencies = currencies
+}
+
+// defaultCurrency is the currency new wallets are opened in.
+func (s *service) defaultCurrency() string {
+	if s.currencies == nil {
+		return fallbackCurrency
+	}
+	return s.currencies.DefaultCurrency()
+}
+
+// toWalletCurrency converts an amount given in code into the wallet's
+// currency. An empty code means the amount is already in it.
+func (s *service) toWalletCurrency(ctx context.Context, wallet *models.Wallet, amount float64, code string) (*currency.Conversion, error) {
+	code = currency.Normalize(code)
+	if code == "" || code == wallet.Currency {
+		return &currency.Conversion{
+			Amount:           amount,
+			Currency:         wallet.Currency,
+			OriginalAmount:   amount,
+			OriginalCurrency: wallet.Currency,
+			Rate:             1,
+		}, nil
+	}
+	if s.currencies == nil {
+		return nil, response.BadRequest("Amounts must be given in " + wallet.Currency)
+	}
+	return s.currencies.Convert(ctx, amount, code, wallet.Currency)
+}
+
+// recordConversion notes on the transaction what was actually sent when it
+// was converted into the wallet's currency.
+func recordConversion(txn *models.WalletTransaction, conversion *currency.Conversion) {
+	txn.Currency = conversion.Currency
+	if !conversion.Converted() {
+		return
+	}
+	originalAmount := conversion.OriginalAmount
+	originalCurrency := conversion.OriginalCurrency
+	rate := conversion.Rate
+	txn.OriginalAmount = &originalAmount
+	txn.OriginalCurrency = &originalCurrency
+	txn.ExchangeRate = &rate
+}
@@ -2,12 +2,16 @@ package dto
 
 import (
 	"errors"
+	"time"
 
 	"github.com/umar5678/go-backend/internal/models"
 )
 
+// Amounts sent in another currency than the wallet's are converted at the
+// current rate; Currency defaults to the wallet's own.
 type AddFundsRequest struct {
 	Amount      float64 `json:"amount" binding:"required,gt=0"`
+	Currency    string  `json:"currency" binding:"omitempty,len=3"`
 	Description string  `json:"description" binding:"omitempty"`
 }
 
@@ -23,6 +27,7 @@ func (r *AddFundsRequest) Validate() error {
 
 type WithdrawFundsRequest struct {
 	Amount      float64 `json:"amount" binding:"required,gt=0"`
+	Currency    string  `json:"currency" binding:"omitempty,len=3"`
 	Description string  `json:"description" binding:"omitempty"`
 }
 
@@ -36,6 +41,7 @@ func (r *WithdrawFundsRequest) Validate() error {
 type TransferFundsRequest struct {
 	RecipientID string  `json:"recipientId" binding:"required,uuid"`
 	Amount      float64 `json:"amount" binding:"required,gt=0"`
+	Currency    string  `json:"currency" binding:"omitempty,len=3"`
 	Description string  `json:"description" binding:"omitempty"`
 }
 
@@ -51,6 +57,7 @@ func (r *TransferFundsRequest) Validate() error {
 
 type HoldFundsRequest struct {
     Amount        float64 `json:"amount" binding:"required,min=0.5"`
+    Currency      string  `json:"currency" binding:"omitempty,len=3"`
     ReferenceType string  `json:"referenceType" binding:"required"`
     ReferenceID   string  `json:"referenceId" binding:"required"`
     HoldDuration  int     `json:"holdDuration" binding:"omitempty,min=60,max=3600"` // seconds
@@ -70,40 +77,74 @@ type ReleaseHoldRequest struct {
 type CaptureHoldRequest struct {
     HoldID      string   `json:"holdId" binding:"required,uuid"`
     Amount      *float64 `json:"amount" binding:"omitempty,min=0.5"`
+    Currency    string   `json:"currency" binding:"omitempty,len=3"`
     Description string   `json:"description" binding:"omitempty,max=500"`
 }
 
-type TransactionHistoryRequest struct {
-    Page      int    `form:"page" binding:"omitempty,min=1"`
-    Limit     int    `form:"limit" binding:"omitempty,min=1,max=100"`
-    Type      string `form:"type" binding:"omitempty,oneof=credit debit"`
-    StartDate string `form:"startDate" binding:"omitempty"`
-    EndDate   string `form:"endDate" binding:"omitempty"`
+// ListTransactionsRequest pages through a wallet's transactions, newest
+// first. Pass nextCursor back as cursor to get the following page; keyset
+// paging stays fast however long the history gets.
+type ListTransactionsRequest struct {
+	Limit         int                      `form:"limit" binding:"omitempty,min=1,max=100"`
+	Cursor        string                   `form:"cursor" binding:"omitempty,max=100"`
+	WalletType    models.WalletType        `form:"walletType" binding:"omitempty,oneof=rider driver service_provider"`
+	Type          models.TransactionType   `form:"type" binding:"omitempty,oneof=credit debit refund hold release transfer"`
+	Status        models.TransactionStatus `form:"status" binding:"omitempty,oneof=pending completed failed cancelled held released"`
+	ReferenceType string                   `form:"referenceType" binding:"omitempty,max=50"`
+	ReferenceID   string                   `form:"referenceId" binding:"omitempty,max=50"`
+	FromDate      string                   `form:"fromDate" binding:"omitempty,datetime=2006-01-02"`
+	ToDate        string                   `form:"toDate" binding:"omitempty,datetime=2006-01-02"`
 }
 
-func (r *TransactionHistoryRequest) SetDefaults() {
-    if r.Page == 0 {
-        r.Page = 1
-    }
-    if r.Limit == 0 {
-        r.Limit = 20
-    }
+func (r *ListTransactionsRequest) SetDefaults() {
+	if r.Limit == 0 {
+		r.Limit = 20
+	}
 }
 
-type ListTransactionsRequest struct {
-	Page   int                      `form:"page" binding:"omitempty,min=1"`
-	Limit  int                      `form:"limit" binding:"omitempty,min=1,max=100"`
-	Type   models.TransactionType   `form:"type" binding:"omitempty"`
-	Status models.TransactionStatus `form:"status" binding:"omitempty"`
+// Range returns the date filter as [from, to) in UTC; toDate is inclusive.
+func (r *ListTransactionsRequest) Range() (from, to *time.Time, err error) {
+	if r.FromDate != "" {
+		t, err := time.Parse("2006-01-02", r.FromDate)
+		if err != nil {
+			return nil, nil, errors.New("fromDate must be YYYY-MM-DD")
+		}
+		from = &t
+	}
+	if r.ToDate != "" {
+		t, err := time.Parse("2006-01-02", r.ToDate)
+		if err != nil {
+			return nil, nil, errors.New("toDate must be YYYY-MM-DD")
+		}
+		t = t.AddDate(0, 0, 1)
+		to = &t
+	}
+	if from != nil && to != nil && !from.Before(*to) {
+		return nil, nil, errors.New("fromDate must not be after toDate")
+	}
+	return from, to, nil
 }
 
-func (r *ListTransactionsRequest) SetDefaults() {
-	if r.Page == 0 {
-		r.Page = 1
+// StatementRequest asks for one calendar month (UTC) of a wallet.
+type StatementRequest struct {
+	Month      string            `form:"month" binding:"required,datetime=2006-01"`
+	Format     string            `form:"format" binding:"omitempty,oneof=json csv pdf"`
+	WalletType models.WalletType `form:"walletType" binding:"omitempty,oneof=rider driver service_provider"`
+}
+
+func (r *StatementRequest) SetDefaults() {
+	if r.Format == "" {
+		r.Format = "json"
 	}
-	if r.Limit == 0 {
-		r.Limit = 20
+}
+
+// Period returns the month as [start, end) in UTC.
+func (r *StatementRequest) Period() (start, end time.Time, err error) {
+	start, err = time.Parse("2006-01", r.Month)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.New("month must be YYYY-MM")
 	}
+	return start, start.AddDate(0, 1, 0), nil
 }
 
 type CashCollectionRequest struct {
@@ -129,3 +170,66 @@ func (r *CashPaymentRequest) Validate() error {
     }
     return nil
 }
+
+type ListPayoutCreditsRequest struct {
+	Page          int    `form:"page" binding:"omitempty,min=1"`
+	Limit         int    `form:"limit" binding:"omitempty,min=1,max=100"`
+	Status        string `form:"status" binding:"omitempty,oneof=pending credited stuck resolved"`
+	ReferenceType string `form:"referenceType" binding:"omitempty,max=50"`
+}
+
+func (r *ListPayoutCreditsRequest) SetDefaults() {
+	if r.Page == 0 {
+		r.Page = 1
+	}
+	if r.Limit == 0 {
+		r.Limit = 20
+	}
+	if r.Status == "" {
+		r.Status = string(models.PayoutCreditStatusStuck)
+	}
+}
+
+type ResolvePayoutCreditRequest struct {
+	Note string `json:"note" binding:"required,min=3,max=500"`
+}
+
+// UpdateDriverCreditRequest moves a driver to another credit tier or pins
+// their limit. ClearLimitOverride puts them back on their tier's limit.
+type UpdateDriverCreditRequest struct {
+	Tier               *string  `json:"tier" binding:"omitempty,min=1,max=30"`
+	LimitOverride      *float64 `json:"limitOverride" binding:"omitempty,min=0"`
+	ClearLimitOverride bool     `json:"clearLimitOverride"`
+	Reason             string   `json:"reason" binding:"required,min=3,max=255"`
+}
+
+func (r *UpdateDriverCreditRequest) Validate() error {
+	if r.Tier == nil && r.LimitOverride == nil && !r.ClearLimitOverride {
+		return errors.New("tier, limitOverride or clearLimitOverride is required")
+	}
+	if r.LimitOverride != nil && r.ClearLimitOverride {
+		return errors.New("limitOverride and clearLimitOverride cannot be combined")
+	}
+	return nil
+}
+
+// DriverCreditOverrideRequest lets a driver go online despite their debt
+// until Until.
+type DriverCreditOverrideRequest struct {
+	Until  time.Time `json:"until" binding:"required"`
+	Reason string    `json:"reason" binding:"required,min=3,max=255"`
+}
+
+type ListDebtRepaymentsRequest struct {
+	Page  int `form:"page" binding:"omitempty,min=1"`
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (r *ListDebtRepaymentsRequest) SetDefaults() {
+	if r.Page == 0 {
+		r.Page = 1
+	}
+	if r.Limit == 0 {
+		r.Limit = 20
+	}
+}
@@ -27,6 +27,7 @@ type TransactionResponse struct {
 	WalletID      string                   `json:"walletId"`
 	Type          models.TransactionType   `json:"type"`
 	Amount        float64                  `json:"amount"`
+	Currency      string                   `json:"currency"`
 	BalanceBefore float64                  `json:"balanceBefore"`
 	BalanceAfter  float64                  `json:"balanceAfter"`
 	Status        models.TransactionStatus `json:"status"`
@@ -36,12 +37,61 @@ type TransactionResponse struct {
 	Metadata      map[string]interface{}   `json:"metadata,omitempty"`
 	ProcessedAt   *time.Time               `json:"processedAt,omitempty"`
 	CreatedAt     time.Time                `json:"createdAt"`
+
+	OriginalAmount   *float64 `json:"originalAmount,omitempty"`
+	OriginalCurrency *string  `json:"originalCurrency,omitempty"`
+	ExchangeRate     *float64 `json:"exchangeRate,omitempty"`
+
+	JournalEntryID *string `json:"journalEntryId,omitempty"`
+}
+
+type TransactionPageResponse struct {
+	Transactions []*TransactionResponse `json:"transactions"`
+	// NextCursor is empty on the last page.
+	NextCursor string `json:"nextCursor,omitempty"`
+	HasMore    bool   `json:"hasMore"`
+}
+
+// StatementResponse is a month of a wallet's completed transactions, oldest
+// first. Balances are recomputed from the ledger, so the closing balance can
+// be checked against the opening balance and the lines.
+type StatementResponse struct {
+	WalletID       string            `json:"walletId"`
+	WalletType     models.WalletType `json:"walletType"`
+	Currency       string            `json:"currency"`
+	Month          string            `json:"month"`
+	PeriodStart    time.Time         `json:"periodStart"`
+	PeriodEnd      time.Time         `json:"periodEnd"`
+	OpeningBalance float64           `json:"openingBalance"`
+	TotalCredits   float64           `json:"totalCredits"`
+	TotalDebits    float64           `json:"totalDebits"`
+	ClosingBalance float64           `json:"closingBalance"`
+	Lines          []StatementLine   `json:"lines"`
+}
+
+type StatementLine struct {
+	TransactionID string                 `json:"transactionId"`
+	Date          time.Time              `json:"date"`
+	Type          models.TransactionType `json:"type"`
+	ReferenceType string                 `json:"referenceType,omitempty"`
+	ReferenceID   string                 `json:"referenceId,omitempty"`
+	Description   string                 `json:"description,omitempty"`
+	Credit        float64                `json:"credit"`
+	Debit         float64                `json:"debit"`
+	Balance       float64                `json:"balance"`
+}
+
+type StatementFile struct {
+	FileName    string
+	ContentType string
+	Data        []byte
 }
 
 type HoldResponse struct {
 	ID            string                   `json:"id"`
 	WalletID      string                   `json:"walletId"`
 	Amount        float64                  `json:"amount"`
+	Currency      string                   `json:"currency"`
 	ReferenceType string                   `json:"referenceType"`
 	ReferenceID   string                   `json:"referenceId"`
 	Status        models.TransactionStatus `json:"status"`
@@ -50,6 +100,30 @@ type HoldResponse struct {
 	CreatedAt     time.Time                `json:"createdAt"`
 }
 
+// ActiveHoldResponse explains one pending amount on the customer's balance.
+type ActiveHoldResponse struct {
+	ID               string    `json:"id"`
+	Amount           float64   `json:"amount"`
+	ReferenceType    string    `json:"referenceType"`
+	ReferenceID      string    `json:"referenceId"`
+	ReferenceStatus  string    `json:"referenceStatus,omitempty"`
+	Description      string    `json:"description"`
+	ExpiresAt        time.Time `json:"expiresAt"`
+	ExpiresInSeconds int64     `json:"expiresInSeconds"`
+	Releasable       bool      `json:"releasable"`
+	ReleaseReason    string    `json:"releaseReason,omitempty"`
+	CreatedAt        time.Time `json:"createdAt"`
+}
+
+type WalletHoldsResponse struct {
+	WalletID         string                `json:"walletId"`
+	Currency         string                `json:"currency"`
+	Balance          float64               `json:"balance"`
+	AvailableBalance float64               `json:"availableBalance"`
+	TotalHeld        float64               `json:"totalHeld"`
+	Holds            []*ActiveHoldResponse `json:"holds"`
+}
+
 type WalletBalanceResponse struct {
 	WalletID         string    `json:"walletId"`
 	Balance          float64   `json:"balance"`
@@ -87,6 +161,7 @@ func ToTransactionResponse(tx *models.WalletTransaction) *TransactionResponse {
 		WalletID:      tx.WalletID,
 		Type:          tx.Type,
 		Amount:        tx.Amount,
+		Currency:      tx.Currency,
 		BalanceBefore: tx.BalanceBefore,
 		BalanceAfter:  tx.BalanceAfter,
 		Status:        tx.Status,
@@ -96,6 +171,12 @@ func ToTransactionResponse(tx *models.WalletTransaction) *TransactionResponse {
 		Metadata:      tx.Metadata,
 		ProcessedAt:   tx.ProcessedAt,
 		CreatedAt:     tx.CreatedAt,
+
+		OriginalAmount:   tx.OriginalAmount,
+		OriginalCurrency: tx.OriginalCurrency,
+		ExchangeRate:     tx.ExchangeRate,
+
+		JournalEntryID: tx.JournalEntryID,
 	}
 }
 
@@ -104,6 +185,7 @@ func ToHoldResponse(hold *models.WalletHold) *HoldResponse {
 		ID:            hold.ID,
 		WalletID:      hold.WalletID,
 		Amount:        hold.Amount,
+		Currency:      hold.Currency,
 		ReferenceType: hold.ReferenceType,
 		ReferenceID:   hold.ReferenceID,
 		Status:        hold.Status,
@@ -112,3 +194,160 @@ func ToHoldResponse(hold *models.WalletHold) *HoldResponse {
 		CreatedAt:     hold.CreatedAt,
 	}
 }
+
+type PayoutCreditResponse struct {
+	ID              string                    `json:"id"`
+	ReferenceType   string                    `json:"referenceType"`
+	ReferenceID     string                    `json:"referenceId"`
+	ReferenceNumber string                    `json:"referenceNumber,omitempty"`
+	ProviderID      *string                   `json:"providerId,omitempty"`
+	ProviderUserID  string                    `json:"providerUserId"`
+	Amount          float64                   `json:"amount"`
+	Description     string                    `json:"description"`
+	Status          models.PayoutCreditStatus `json:"status"`
+	Attempts        int                       `json:"attempts"`
+	LastError       string                    `json:"lastError,omitempty"`
+	NextAttemptAt   *time.Time                `json:"nextAttemptAt,omitempty"`
+	AlertedAt       *time.Time                `json:"alertedAt,omitempty"`
+	TransactionID   *string                   `json:"transactionId,omitempty"`
+	CreditedAt      *time.Time                `json:"creditedAt,omitempty"`
+	ResolvedBy      *string                   `json:"resolvedBy,omitempty"`
+	ResolutionNote  string                    `json:"resolutionNote,omitempty"`
+	ResolvedAt      *time.Time                `json:"resolvedAt,omitempty"`
+	CreatedAt       time.Time                 `json:"createdAt"`
+}
+
+func ToPayoutCreditResponse(credit *models.PayoutCredit) *PayoutCreditResponse {
+	return &PayoutCreditResponse{
+		ID:              credit.ID,
+		ReferenceType:   credit.ReferenceType,
+		ReferenceID:     credit.ReferenceID,
+		ReferenceNumber: credit.ReferenceNumber,
+		ProviderID:      credit.ProviderID,
+		ProviderUserID:  credit.ProviderUserID,
+		Amount:          credit.Amount,
+		Description:     credit.Description,
+		Status:          credit.Status,
+		Attempts:        credit.Attempts,
+		LastError:       credit.LastError,
+		NextAttemptAt:   credit.NextAttemptAt,
+		AlertedAt:       credit.AlertedAt,
+		TransactionID:   credit.TransactionID,
+		CreditedAt:      credit.CreditedAt,
+		ResolvedBy:      credit.ResolvedBy,
+		ResolutionNote:  credit.ResolutionNote,
+		ResolvedAt:      credit.ResolvedAt,
+		CreatedAt:       credit.CreatedAt,
+	}
+}
+
+type JournalEntryResponse struct {
+	ID            string                  `json:"id"`
+	Kind          string                  `json:"kind"`
+	ReferenceType string                  `json:"referenceType,omitempty"`
+	ReferenceID   string                  `json:"referenceId,omitempty"`
+	Description   string                  `json:"description,omitempty"`
+	CreatedAt     time.Time               `json:"createdAt"`
+	Postings      []LedgerPostingResponse `json:"postings"`
+}
+
+// LedgerPostingResponse is one line of a journal entry; Amount is positive
+// for a debit and negative for a credit.
+type LedgerPostingResponse struct {
+	AccountID   string                   `json:"accountId"`
+	AccountCode string                   `json:"accountCode"`
+	AccountKind models.LedgerAccountKind `json:"accountKind"`
+	Amount      float64                  `json:"amount"`
+	Currency    string                   `json:"currency"`
+}
+
+// LedgerReconciliationResponse lists where the ledger disagrees with itself
+// or with the wallet balances derived from it, up to a limit per check.
+type LedgerReconciliationResponse struct {
+	CheckedAt         time.Time         `json:"checkedAt"`
+	Clean             bool              `json:"clean"`
+	UnbalancedEntries []UnbalancedEntry `json:"unbalancedEntries"`
+	AccountDrifts     []AccountDrift    `json:"accountDrifts"`
+	WalletDrifts      []WalletDrift     `json:"walletDrifts"`
+}
+
+// UnbalancedEntry is a journal entry whose postings in Currency do not sum
+// to zero.
+type UnbalancedEntry struct {
+	EntryID   string  `json:"entryId"`
+	Currency  string  `json:"currency"`
+	Imbalance float64 `json:"imbalance"`
+}
+
+// AccountDrift is an account whose stored balance differs from the sum of
+// its postings.
+type AccountDrift struct {
+	AccountID     string  `json:"accountId"`
+	Code          string  `json:"code"`
+	Balance       float64 `json:"balance"`
+	PostedBalance float64 `json:"postedBalance"`
+}
+
+// WalletDrift is a wallet whose balance or held balance differs from its
+// ledger accounts.
+type WalletDrift struct {
+	WalletID          string  `json:"walletId"`
+	UserID            string  `json:"userId"`
+	Balance           float64 `json:"balance"`
+	LedgerBalance     float64 `json:"ledgerBalance"`
+	HeldBalance       float64 `json:"heldBalance"`
+	LedgerHeldBalance float64 `json:"ledgerHeldBalance"`
+}
+
+// DriverCreditResponse is where a driver stands against their credit limit.
+type DriverCreditResponse struct {
+	DriverID             string     `json:"driverId"`
+	UserID               string     `json:"userId"`
+	Tier                 string     `json:"tier"`
+	TierLimit            float64    `json:"tierLimit"`
+	LimitOverride        *float64   `json:"limitOverride,omitempty"`
+	CreditLimit          float64    `json:"creditLimit"`
+	Balance              float64    `json:"balance"`
+	Debt                 float64    `json:"debt"`
+	AvailableCredit      float64    `json:"availableCredit"`
+	Currency             string     `json:"currency"`
+	IsRestricted         bool       `json:"isRestricted"`
+	RestrictionReason    *string    `json:"restrictionReason,omitempty"`
+	RestrictedAt         *time.Time `json:"restrictedAt,omitempty"`
+	OverrideUntil        *time.Time `json:"overrideUntil,omitempty"`
+	OverrideReason       *string    `json:"overrideReason,omitempty"`
+	OverrideActive       bool       `json:"overrideActive"`
+	RepaidLast30Days     float64    `json:"repaidLast30Days"`
+	LastRepaymentAt      *time.Time `json:"lastRepaymentAt,omitempty"`
+	AmountNeededToGoLive float64    `json:"amountNeededToGoLive"`
+}
+
+type DriverCreditTierResponse struct {
+	Tier    string  `json:"tier"`
+	Limit   float64 `json:"limit"`
+	Default bool    `json:"default"`
+}
+
+type DebtRepaymentResponse struct {
+	ID            string    `json:"id"`
+	TransactionID string    `json:"transactionId"`
+	Amount        float64   `json:"amount"`
+	DebtBefore    float64   `json:"debtBefore"`
+	DebtAfter     float64   `json:"debtAfter"`
+	Source        string    `json:"source"`
+	Currency      string    `json:"currency"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+func ToDebtRepaymentResponse(repayment *models.DriverDebtRepayment) *DebtRepaymentResponse {
+	return &DebtRepaymentResponse{
+		ID:            repayment.ID,
+		TransactionID: repayment.TransactionID,
+		Amount:        repayment.Amount,
+		DebtBefore:    repayment.DebtBefore,
+		DebtAfter:     repayment.DebtAfter,
+		Source:        repayment.Source,
+		Currency:      repayment.Currency,
+		CreatedAt:     repayment.CreatedAt,
+	}
+}
@@ -1,6 +1,9 @@
 package wallet
 
 import (
+	"fmt"
+	"net/http"
+
 	"github.com/gin-gonic/gin"
 	"github.com/umar5678/go-backend/internal/modules/wallet/dto"
 	"github.com/umar5678/go-backend/internal/utils/response"
@@ -79,35 +82,6 @@ func (h *Handler) AddFunds(c *gin.Context) {
 	response.Success(c, transaction, "Funds added successfully")
 }
 
-// GetTransactionHistory godoc
-// @Summary Get transaction history
-// @Description Get wallet transaction history with pagination
-// @Tags wallet
-// @Security BearerAuth
-// @Param page query int false "Page number"
-// @Param limit query int false "Items per page"
-// @Param type query string false "Transaction type (credit/debit)"
-// @Success 200 {object} response.Response{data=[]dto.TransactionResponse}
-// @Router /wallet/transactions [get]
-func (h *Handler) GetTransactionHistory(c *gin.Context) {
-    userID, _ := c.Get("userID")
-
-    var req dto.TransactionHistoryRequest
-    if err := c.ShouldBindQuery(&req); err != nil {
-        c.Error(response.BadRequest("Invalid query parameters"))
-        return
-    }
-
-    transactions, total, err := h.service.GetTransactionHistory(c.Request.Context(), userID.(string), req)
-    if err != nil {
-        c.Error(err)
-        return
-    }
-
-    pagination := response.NewPaginationMeta(total, req.Page, req.Limit)
-    response.Paginated(c, transactions, pagination, "Transactions retrieved")
-}
-
 // GetTransaction godoc
 // @Summary Get transaction details
 // @Tags wallet
@@ -240,14 +214,20 @@ func (h *Handler) TransferFunds(c *gin.Context) {
 
 // ListTransactions godoc
 // @Summary List wallet transactions
+// @Description Newest first. Pass the returned nextCursor to fetch the next page.
 // @Tags wallet
 // @Security BearerAuth
 // @Produce json
-// @Param page query int false "Page number"
-// @Param limit query int false "Items per page"
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Items per page (max 100)"
+// @Param walletType query string false "Wallet type"
 // @Param type query string false "Transaction type"
 // @Param status query string false "Transaction status"
-// @Success 200 {object} response.Response{data=[]dto.TransactionResponse}
+// @Param referenceType query string false "Reference type"
+// @Param referenceId query string false "Reference ID"
+// @Param fromDate query string false "From date (YYYY-MM-DD)"
+// @Param toDate query string false "To date (YYYY-MM-DD, inclusive)"
+// @Success 200 {object} response.Response{data=dto.TransactionPageResponse}
 // @Router /wallet/transactions [get]
 func (h *Handler) ListTransactions(c *gin.Context) {
 	userID, _ := c.Get("userID")
@@ -258,14 +238,56 @@ func (h *Handler) ListTransactions(c *gin.Context) {
 		return
 	}
 
-	transactions, total, err := h.service.ListTransactions(c.Request.Context(), userID.(string), req)
+	page, err := h.service.ListTransactions(c.Request.Context(), userID.(string), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, page, "Transactions retrieved successfully")
+}
+
+// GetStatement godoc
+// @Summary Get monthly wallet statement
+// @Description Opening balance, every completed transaction with its running balance, and the closing balance for a month. format=csv or format=pdf downloads it.
+// @Tags wallet
+// @Security BearerAuth
+// @Produce json
+// @Produce text/csv
+// @Produce application/pdf
+// @Param month query string true "Month (YYYY-MM)"
+// @Param format query string false "json, csv or pdf"
+// @Param walletType query string false "Wallet type"
+// @Success 200 {object} response.Response{data=dto.StatementResponse}
+// @Router /wallet/statement [get]
+func (h *Handler) GetStatement(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	var req dto.StatementRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+	req.SetDefaults()
+
+	if req.Format == "json" {
+		statement, err := h.service.GetStatement(c.Request.Context(), userID.(string), req)
+		if err != nil {
+			c.Error(err)
+			return
+		}
+		response.Success(c, statement, "Statement retrieved successfully")
+		return
+	}
+
+	file, err := h.service.ExportStatement(c.Request.Context(), userID.(string), req)
 	if err != nil {
 		c.Error(err)
 		return
 	}
 
-	pagination := response.NewPaginationMeta(total, req.Page, req.Limit)
-	response.Paginated(c, transactions, pagination, "Transactions retrieved successfully")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.FileName))
+	c.Data(http.StatusOK, file.ContentType, file.Data)
 }
 
 // HoldFunds godoc
@@ -295,6 +317,26 @@ func (h *Handler) HoldFunds(c *gin.Context) {
 	response.Success(c, hold, "Funds held successfully")
 }
 
+// GetActiveHolds godoc
+// @Summary List active holds
+// @Description Lists the amounts currently reserved on the wallet, what they are reserved for, when they expire and whether they can be released
+// @Tags wallet
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=dto.WalletHoldsResponse}
+// @Router /wallet/holds [get]
+func (h *Handler) GetActiveHolds(c *gin.Context) {
+	userID, _ := c.Get("userID")
+
+	holds, err := h.service.GetActiveHolds(c.Request.Context(), userID.(string))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, holds, "Holds retrieved successfully")
+}
+
 // ReleaseHold godoc
 // @Summary Release a hold
 // @Tags wallet
@@ -347,3 +389,266 @@ func (h *Handler) CaptureHold(c *gin.Context) {
 
 	response.Success(c, transaction, "Hold captured successfully")
 }
+
+// ListPayoutCredits godoc
+// @Summary List queued provider payout credits (admin)
+// @Description Provider wallet credits that failed at completion. Defaults to stuck credits that exhausted their retries.
+// @Tags wallet
+// @Security BearerAuth
+// @Produce json
+// @Param status query string false "pending, credited, stuck or resolved (default stuck)"
+// @Param referenceType query string false "Reference type, e.g. service_order"
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Success 200 {object} response.Response{data=[]dto.PayoutCreditResponse}
+// @Router /wallet/admin/payout-credits [get]
+func (h *Handler) ListPayoutCredits(c *gin.Context) {
+	var req dto.ListPayoutCreditsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+	req.SetDefaults()
+
+	credits, total, err := h.service.ListPayoutCredits(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Paginated(c, credits, response.NewPaginationMeta(total, req.Page, req.Limit), "Payout credits retrieved")
+}
+
+// RetryPayoutCredit godoc
+// @Summary Retry a queued payout credit now (admin)
+// @Tags wallet
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Payout credit ID"
+// @Success 200 {object} response.Response{data=dto.PayoutCreditResponse}
+// @Router /wallet/admin/payout-credits/{id}/retry [post]
+func (h *Handler) RetryPayoutCredit(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	credit, err := h.service.RetryPayoutCredit(c.Request.Context(), adminID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, credit, "Payout credit retried")
+}
+
+// ResolvePayoutCredit godoc
+// @Summary Mark a payout credit as settled outside the wallet (admin)
+// @Tags wallet
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Payout credit ID"
+// @Param request body dto.ResolvePayoutCreditRequest true "Resolution note"
+// @Success 200 {object} response.Response{data=dto.PayoutCreditResponse}
+// @Router /wallet/admin/payout-credits/{id}/resolve [post]
+func (h *Handler) ResolvePayoutCredit(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	var req dto.ResolvePayoutCreditRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	credit, err := h.service.ResolvePayoutCredit(c.Request.Context(), adminID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, credit, "Payout credit resolved")
+}
+
+// ReconcileLedger godoc
+// @Summary Check the wallet ledger for drift (admin)
+// @Description Lists unbalanced journal entries, accounts whose balance differs from their postings and wallets whose balances differ from their accounts.
+// @Tags wallet
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=dto.LedgerReconciliationResponse}
+// @Router /wallet/admin/ledger/reconciliation [get]
+func (h *Handler) ReconcileLedger(c *gin.Context) {
+	report, err := h.service.ReconcileLedger(c.Request.Context())
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, report, "Ledger reconciled")
+}
+
+// GetJournalEntry godoc
+// @Summary Get a journal entry with its postings (admin)
+// @Tags wallet
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Journal entry ID"
+// @Success 200 {object} response.Response{data=dto.JournalEntryResponse}
+// @Router /wallet/admin/ledger/entries/{id} [get]
+func (h *Handler) GetJournalEntry(c *gin.Context) {
+	entry, err := h.service.GetJournalEntry(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, entry, "Journal entry retrieved")
+}
+
+// ListDriverCreditTiers godoc
+// @Summary List driver credit tiers and their limits (admin)
+// @Tags wallet
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} response.Response{data=[]dto.DriverCreditTierResponse}
+// @Router /wallet/admin/driver-credit-tiers [get]
+func (h *Handler) ListDriverCreditTiers(c *gin.Context) {
+	response.Success(c, h.service.ListDriverCreditTiers(c.Request.Context()), "Credit tiers retrieved")
+}
+
+// GetDriverCredit godoc
+// @Summary Get a driver's credit limit, debt and restriction (admin)
+// @Tags wallet
+// @Security BearerAuth
+// @Produce json
+// @Param driverId path string true "Driver profile or user ID"
+// @Success 200 {object} response.Response{data=dto.DriverCreditResponse}
+// @Router /wallet/admin/drivers/{driverId}/credit [get]
+func (h *Handler) GetDriverCredit(c *gin.Context) {
+	credit, err := h.service.GetDriverCredit(c.Request.Context(), c.Param("driverId"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, credit, "Driver credit retrieved")
+}
+
+// UpdateDriverCredit godoc
+// @Summary Change a driver's credit tier or limit (admin)
+// @Tags wallet
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param driverId path string true "Driver profile or user ID"
+// @Param request body dto.UpdateDriverCreditRequest true "Tier or limit"
+// @Success 200 {object} response.Response{data=dto.DriverCreditResponse}
+// @Router /wallet/admin/drivers/{driverId}/credit [put]
+func (h *Handler) UpdateDriverCredit(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	var req dto.UpdateDriverCreditRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	credit, err := h.service.UpdateDriverCredit(c.Request.Context(), adminID.(string), c.Param("driverId"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, credit, "Driver credit updated")
+}
+
+// SetDriverCreditOverride godoc
+// @Summary Let a driver go online despite their debt until a given time (admin)
+// @Tags wallet
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param driverId path string true "Driver profile or user ID"
+// @Param request body dto.DriverCreditOverrideRequest true "Override"
+// @Success 200 {object} response.Response{data=dto.DriverCreditResponse}
+// @Router /wallet/admin/drivers/{driverId}/credit/override [post]
+func (h *Handler) SetDriverCreditOverride(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	var req dto.DriverCreditOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body"))
+		return
+	}
+
+	credit, err := h.service.SetDriverCreditOverride(c.Request.Context(), adminID.(string), c.Param("driverId"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, credit, "Credit override set")
+}
+
+// ClearDriverCreditOverride godoc
+// @Summary End a driver's credit override (admin)
+// @Tags wallet
+// @Security BearerAuth
+// @Produce json
+// @Param driverId path string true "Driver profile or user ID"
+// @Success 200 {object} response.Response{data=dto.DriverCreditResponse}
+// @Router /wallet/admin/drivers/{driverId}/credit/override [delete]
+func (h *Handler) ClearDriverCreditOverride(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	credit, err := h.service.ClearDriverCreditOverride(c.Request.Context(), adminID.(string), c.Param("driverId"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, credit, "Credit override cleared")
+}
+
+// ListDriverDebtRepayments godoc
+// @Summary List what paid down a driver's negative balance (admin)
+// @Tags wallet
+// @Security BearerAuth
+// @Produce json
+// @Param driverId path string true "Driver profile or user ID"
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Success 200 {object} response.Response{data=[]dto.DebtRepaymentResponse}
+// @Router /wallet/admin/drivers/{driverId}/debt-repayments [get]
+func (h *Handler) ListDriverDebtRepayments(c *gin.Context) {
+	h.listDebtRepayments(c, c.Param("driverId"))
+}
+
+// ListDebtRepayments godoc
+// @Summary List what paid down my negative balance (driver)
+// @Tags wallet
+// @Security BearerAuth
+// @Produce json
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Success 200 {object} response.Response{data=[]dto.DebtRepaymentResponse}
+// @Router /wallet/debt-repayments [get]
+func (h *Handler) ListDebtRepayments(c *gin.Context) {
+	userID, _ := c.Get("userID")
+	h.listDebtRepayments(c, userID.(string))
+}
+
+func (h *Handler) listDebtRepayments(c *gin.Context, driverID string) {
+	var req dto.ListDebtRepaymentsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters"))
+		return
+	}
+	req.SetDefaults()
+
+	repayments, total, err := h.service.ListDebtRepayments(c.Request.Context(), driverID, req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Paginated(c, repayments, response.NewPaginationMeta(total, req.Page, req.Limit), "Debt repayments retrieved")
+}
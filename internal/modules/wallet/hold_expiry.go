@@ -0,0 +1,75 @@
+package wallet
+
+import (
+	"context"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/services/health"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/websocket"
+)
+
+const holdExpiryBatchSize = 200
+
+// ReleaseExpiredHolds releases holds whose expiry has passed, so funds
+// reserved for a ride or order that never settled stop counting against the
+// customer, and returns how many were released.
+func (s *service) ReleaseExpiredHolds(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		holds, err := s.repo.ReleaseExpiredHolds(ctx, time.Now(), holdExpiryBatchSize)
+		if err != nil {
+			return total, err
+		}
+		for _, hold := range holds {
+			logger.Info("expired hold released", "holdID", hold.ID, "amount", hold.Amount, "walletID", hold.WalletID)
+			s.invalidateWalletCache(ctx, hold.Wallet.UserID)
+			s.notifyHold(hold.Wallet.UserID, websocket.TypeWalletHoldReleased, hold, map[string]interface{}{
+				"message": holdMessage("released", hold.Amount, hold.ReferenceType),
+				"reason":  "expired",
+			})
+		}
+		total += len(holds)
+		if len(holds) < holdExpiryBatchSize {
+			return total, nil
+		}
+	}
+}
+
+// HoldExpirySweeper releases expired holds on a fixed interval.
+type HoldExpirySweeper struct {
+	service  Service
+	interval time.Duration
+}
+
+func NewHoldExpirySweeper(service Service, interval time.Duration) *HoldExpirySweeper {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &HoldExpirySweeper{service: service, interval: interval}
+}
+
+func (w *HoldExpirySweeper) Start(ctx context.Context) {
+	heartbeat := health.NewHeartbeat("wallet_hold_expiry", w.interval)
+
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				runCtx, cancel := context.WithTimeout(ctx, time.Minute)
+				if _, err := w.service.ReleaseExpiredHolds(runCtx); err != nil {
+					logger.Error("hold expiry run failed", "error", err)
+				}
+				cancel()
+				heartbeat.Beat()
+			}
+		}
+	}()
+
+	logger.Info("hold expiry sweeper started", "interval", w.interval)
+}
@@ -0,0 +1,242 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/wallet/dto"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+	websocketutil "github.com/umar5678/go-backend/internal/websocket/websocketutils"
+	"gorm.io/gorm"
+)
+
+// HoldFunds marks new holds "active"; holds written before that used the
+// enum default "held". Both still reserve funds.
+var activeHoldStatuses = []models.TransactionStatus{"active", models.TransactionStatusHeld}
+
+var errHoldInactive = errors.New("hold is no longer active")
+
+// versionConflictAttempts is how often an update that lost a race on a row
+// version is retried before the conflict is reported.
+const versionConflictAttempts = 3
+
+// settleHold ends an active hold: captured goes to the clearing account and
+// the rest of the hold back to the wallet's available funds. The wallet must
+// be locked by tx and is updated in place. It returns errHoldInactive when the
+// hold was released or captured in the meantime.
+func settleHold(tx *gorm.DB, wallet *models.Wallet, hold *models.WalletHold, captured float64, description string) (*models.JournalEntry, error) {
+	now := time.Now()
+	status := models.TransactionStatusReleased
+	kind := models.TransactionTypeRelease
+	updates := map[string]interface{}{
+		"released_at": now,
+		"version":     gorm.Expr("version + 1"),
+	}
+	if captured > 0 {
+		status, kind = "captured", "capture"
+		updates["amount"] = captured
+	}
+	updates["status"] = status
+
+	result := tx.Model(&models.WalletHold{}).
+		Where("id = ? AND status IN ?", hold.ID, activeHoldStatuses).
+		Updates(updates)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, errHoldInactive
+	}
+
+	journal := NewJournal(string(kind), hold.ReferenceType, hold.ReferenceID, description).
+		DebitHeld(wallet, hold.Amount)
+	if captured > 0 {
+		journal.CreditPlatform(models.LedgerAccountClearing, wallet.Currency, captured)
+	}
+	entry, err := journal.CreditWallet(wallet, hold.Amount-captured).Post(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	hold.Status = status
+	hold.ReleasedAt = &now
+	hold.Version++
+	if captured > 0 {
+		hold.Amount = captured
+	}
+	return entry, nil
+}
+
+// GetActiveHolds lists what is reserved against the customer's balance and
+// whether each hold can be let go: a hold is releasable once it has expired
+// or the ride or order it was placed for was cancelled or no longer exists.
+func (s *service) GetActiveHolds(ctx context.Context, userID string) (*dto.WalletHoldsResponse, error) {
+	wallet, err := s.repo.FindWalletByUserID(ctx, userID, models.WalletTypeRider)
+	if err != nil {
+		return nil, response.NotFoundError("Wallet")
+	}
+
+	holds, err := s.repo.FindActiveHoldsByWallet(ctx, wallet.ID)
+	if err != nil {
+		logger.Error("failed to fetch active holds", "error", err, "walletID", wallet.ID)
+		return nil, response.InternalServerError("Failed to fetch holds", err)
+	}
+
+	refIDs := make(map[string][]string)
+	for _, hold := range holds {
+		refIDs[hold.ReferenceType] = append(refIDs[hold.ReferenceType], hold.ReferenceID)
+	}
+	refStatuses := make(map[string]map[string]string)
+	for refType, ids := range refIDs {
+		statuses, err := s.repo.FindReferenceStatuses(ctx, refType, ids)
+		if err != nil {
+			logger.Warn("failed to fetch hold reference statuses", "error", err, "referenceType", refType)
+			continue
+		}
+		refStatuses[refType] = statuses
+	}
+
+	now := time.Now()
+	result := &dto.WalletHoldsResponse{
+		WalletID:         wallet.ID,
+		Currency:         wallet.Currency,
+		Balance:          wallet.Balance,
+		AvailableBalance: wallet.GetAvailableBalance(),
+		Holds:            make([]*dto.ActiveHoldResponse, 0, len(holds)),
+	}
+
+	for _, hold := range holds {
+		item := &dto.ActiveHoldResponse{
+			ID:            hold.ID,
+			Amount:        hold.Amount,
+			ReferenceType: hold.ReferenceType,
+			ReferenceID:   hold.ReferenceID,
+			Description:   holdDescription(hold.ReferenceType),
+			ExpiresAt:     hold.ExpiresAt,
+			CreatedAt:     hold.CreatedAt,
+		}
+		if remaining := hold.ExpiresAt.Sub(now); remaining > 0 {
+			item.ExpiresInSeconds = int64(remaining.Seconds())
+		}
+
+		statuses, known := refStatuses[hold.ReferenceType]
+		status, found := statuses[hold.ReferenceID]
+		item.ReferenceStatus = status
+
+		switch {
+		case !now.Before(hold.ExpiresAt):
+			item.Releasable, item.ReleaseReason = true, "expired"
+		case status == "cancelled":
+			item.Releasable, item.ReleaseReason = true, "reference_cancelled"
+		case known && !found:
+			item.Releasable, item.ReleaseReason = true, "reference_not_found"
+		}
+
+		result.TotalHeld += hold.Amount
+		result.Holds = append(result.Holds, item)
+	}
+
+	return result, nil
+}
+
+func holdDescription(referenceType string) string {
+	switch referenceType {
+	case "ride":
+		return "Reserved for your ride fare"
+	case "service_order":
+		return "Reserved for your service order"
+	case "laundry_order":
+		return "Reserved for your laundry order"
+	default:
+		return "Reserved for a pending payment"
+	}
+}
+
+// ExtendHold keeps an active hold until at least the given time, for example
+// when the booking it pays for is moved to a later date. Holds are never
+// shortened. If the hold changes while it is being extended, it is reloaded
+// and the extension tried again.
+func (s *service) ExtendHold(ctx context.Context, userID, holdID string, until time.Time) error {
+	for attempt := 1; ; attempt++ {
+		err := s.extendHold(ctx, userID, holdID, until)
+		if !errors.Is(err, errVersionConflict) {
+			return err
+		}
+		if attempt == versionConflictAttempts {
+			return response.ConflictError("Hold was changed by another request, please try again")
+		}
+	}
+}
+
+func (s *service) extendHold(ctx context.Context, userID, holdID string, until time.Time) error {
+	hold, err := s.repo.FindHoldByID(ctx, holdID)
+	if err != nil {
+		return response.NotFoundError("Hold")
+	}
+
+	wallet, err := s.repo.FindWalletByID(ctx, hold.WalletID)
+	if err != nil || wallet.UserID != userID {
+		return response.ForbiddenError("Not authorized to extend this hold")
+	}
+
+	active := false
+	for _, status := range activeHoldStatuses {
+		if hold.Status == status {
+			active = true
+			break
+		}
+	}
+	if !active {
+		return response.BadRequest("Hold is no longer active")
+	}
+	if !until.After(hold.ExpiresAt) {
+		return nil
+	}
+
+	previous := hold.ExpiresAt
+	hold.ExpiresAt = until
+	if err := s.repo.UpdateHold(ctx, hold); err != nil {
+		if errors.Is(err, errVersionConflict) {
+			return err
+		}
+		return response.InternalServerError("Failed to extend hold", err)
+	}
+
+	logger.Info("hold extended", "holdID", hold.ID, "from", previous, "until", until, "userID", userID)
+	return nil
+}
+
+// notifyHold pushes a hold change to the customer's app so it can explain
+// why the available balance moved.
+func (s *service) notifyHold(userID string, messageType websocket.MessageType, hold *models.WalletHold, data map[string]interface{}) {
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	data["holdId"] = hold.ID
+	data["amount"] = hold.Amount
+	data["referenceType"] = hold.ReferenceType
+	data["referenceId"] = hold.ReferenceID
+	data["expiresAt"] = hold.ExpiresAt
+
+	if err := websocketutil.SendToUser(userID, messageType, data); err != nil {
+		logger.Warn("failed to push wallet hold event", "error", err, "holdID", hold.ID, "type", messageType)
+	}
+}
+
+func holdMessage(action string, amount float64, referenceType string) string {
+	switch referenceType {
+	case "ride":
+		return fmt.Sprintf("%.2f %s for your ride", amount, action)
+	case "service_order":
+		return fmt.Sprintf("%.2f %s for your service order", amount, action)
+	case "laundry_order":
+		return fmt.Sprintf("%.2f %s for your laundry order", amount, action)
+	default:
+		return fmt.Sprintf("%.2f %s", amount, action)
+	}
+}
@@ -0,0 +1,276 @@
+package wallet
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var errInsufficientFunds = errors.New("insufficient wallet funds")
+
+// Journal builds one double-entry movement. Wallet balances are projections
+// of the ledger: they only change through Post, which writes the entry and
+// moves the balances of the accounts and wallets it touches in the same
+// database transaction.
+type Journal struct {
+	entry models.JournalEntry
+	lines []journalLine
+}
+
+type journalLine struct {
+	account models.LedgerAccount
+	wallet  *models.Wallet
+	amount  float64 // debits positive, credits negative
+}
+
+func NewJournal(kind, referenceType, referenceID, description string) *Journal {
+	return &Journal{entry: models.JournalEntry{
+		Kind:          kind,
+		ReferenceType: referenceType,
+		ReferenceID:   referenceID,
+		Description:   description,
+	}}
+}
+
+// CreditWallet adds amount to the wallet's available funds.
+func (j *Journal) CreditWallet(wallet *models.Wallet, amount float64) *Journal {
+	return j.walletLine(wallet, models.LedgerAccountWalletAvailable, -amount)
+}
+
+// DebitWallet takes amount from the wallet's available funds.
+func (j *Journal) DebitWallet(wallet *models.Wallet, amount float64) *Journal {
+	return j.walletLine(wallet, models.LedgerAccountWalletAvailable, amount)
+}
+
+// Hold moves amount from the wallet's available funds to its held funds.
+func (j *Journal) Hold(wallet *models.Wallet, amount float64) *Journal {
+	j.walletLine(wallet, models.LedgerAccountWalletAvailable, amount)
+	return j.walletLine(wallet, models.LedgerAccountWalletHeld, -amount)
+}
+
+// DebitHeld takes amount from the wallet's held funds.
+func (j *Journal) DebitHeld(wallet *models.Wallet, amount float64) *Journal {
+	return j.walletLine(wallet, models.LedgerAccountWalletHeld, amount)
+}
+
+func (j *Journal) DebitPlatform(kind models.LedgerAccountKind, currency string, amount float64) *Journal {
+	return j.platformLine(kind, currency, amount)
+}
+
+func (j *Journal) CreditPlatform(kind models.LedgerAccountKind, currency string, amount float64) *Journal {
+	return j.platformLine(kind, currency, -amount)
+}
+
+func (j *Journal) walletLine(wallet *models.Wallet, kind models.LedgerAccountKind, amount float64) *Journal {
+	j.lines = append(j.lines, journalLine{
+		account: models.LedgerAccount{
+			Code:          walletAccountCode(wallet.ID, kind),
+			Kind:          kind,
+			NormalBalance: models.NormalBalanceCredit,
+			WalletID:      &wallet.ID,
+			Currency:      wallet.Currency,
+		},
+		wallet: wallet,
+		amount: amount,
+	})
+	return j
+}
+
+func (j *Journal) platformLine(kind models.LedgerAccountKind, currency string, amount float64) *Journal {
+	j.lines = append(j.lines, journalLine{
+		account: models.LedgerAccount{
+			Code:          platformAccountCode(kind, currency),
+			Kind:          kind,
+			NormalBalance: platformNormalBalance(kind),
+			Currency:      currency,
+		},
+		amount: amount,
+	})
+	return j
+}
+
+// Post writes the entry and its postings and applies them to the account and
+// wallet balances, updating the wallets passed to the builder in place. Run
+// it inside a transaction that holds row locks on those wallets.
+func (j *Journal) Post(tx *gorm.DB) (*models.JournalEntry, error) {
+	if err := j.validate(); err != nil {
+		return nil, err
+	}
+
+	accounts := make(map[string]*models.LedgerAccount)
+	for _, line := range j.lines {
+		if _, ok := accounts[line.account.Code]; ok {
+			continue
+		}
+		account := line.account
+		if err := ensureLedgerAccount(tx, &account); err != nil {
+			return nil, err
+		}
+		accounts[account.Code] = &account
+	}
+
+	if err := tx.Omit("Postings").Create(&j.entry).Error; err != nil {
+		return nil, err
+	}
+
+	postings := make([]models.LedgerPosting, 0, len(j.lines))
+	accountChanges := make(map[string]float64)
+	type walletChange struct {
+		wallet    *models.Wallet
+		available float64
+		held      float64
+	}
+	walletChanges := make(map[string]*walletChange)
+
+	for _, line := range j.lines {
+		if line.amount == 0 {
+			continue
+		}
+		account := accounts[line.account.Code]
+		postings = append(postings, models.LedgerPosting{
+			EntryID:   j.entry.ID,
+			AccountID: account.ID,
+			Amount:    roundAmount(line.amount),
+			Currency:  account.Currency,
+		})
+		change := account.BalanceChange(line.amount)
+		accountChanges[account.ID] += change
+
+		if line.wallet == nil {
+			continue
+		}
+		wc := walletChanges[line.wallet.ID]
+		if wc == nil {
+			wc = &walletChange{wallet: line.wallet}
+			walletChanges[line.wallet.ID] = wc
+		}
+		if account.Kind == models.LedgerAccountWalletHeld {
+			wc.held += change
+		} else {
+			wc.available += change
+		}
+	}
+
+	if err := tx.Create(&postings).Error; err != nil {
+		return nil, err
+	}
+	j.entry.Postings = postings
+
+	now := time.Now()
+	for accountID, change := range accountChanges {
+		if err := tx.Model(&models.LedgerAccount{}).
+			Where("id = ?", accountID).
+			Updates(map[string]interface{}{
+				"balance":    gorm.Expr("balance + ?", roundAmount(change)),
+				"updated_at": now,
+			}).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	// The wallet's balance is everything it holds, available or not.
+	for walletID, wc := range walletChanges {
+		balance := roundAmount(wc.available + wc.held)
+		held := roundAmount(wc.held)
+		if err := tx.Model(&models.Wallet{}).
+			Where("id = ?", walletID).
+			Updates(map[string]interface{}{
+				"balance":      gorm.Expr("balance + ?", balance),
+				"held_balance": gorm.Expr("held_balance + ?", held),
+				"version":      gorm.Expr("version + 1"),
+				"updated_at":   now,
+			}).Error; err != nil {
+			return nil, err
+		}
+		wc.wallet.Balance = roundAmount(wc.wallet.Balance + balance)
+		wc.wallet.HeldBalance = roundAmount(wc.wallet.HeldBalance + held)
+		wc.wallet.Version++
+	}
+
+	return &j.entry, nil
+}
+
+// validate checks that the entry moves money and that its postings sum to
+// zero in every currency.
+func (j *Journal) validate() error {
+	totals := make(map[string]float64)
+	moved := false
+	for _, line := range j.lines {
+		if math.IsNaN(line.amount) || math.IsInf(line.amount, 0) {
+			return fmt.Errorf("journal %s: invalid amount", j.entry.Kind)
+		}
+		if line.amount != 0 {
+			moved = true
+		}
+		totals[line.account.Currency] += line.amount
+	}
+	if !moved {
+		return fmt.Errorf("journal %s: no amounts to post", j.entry.Kind)
+	}
+	for currency, total := range totals {
+		if roundAmount(total) != 0 {
+			return fmt.Errorf("journal %s: unbalanced by %.2f %s", j.entry.Kind, total, currency)
+		}
+	}
+	return nil
+}
+
+// ensureLedgerAccount loads the account with the given code, creating it on
+// first use.
+func ensureLedgerAccount(tx *gorm.DB, account *models.LedgerAccount) error {
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "code"}},
+		DoNothing: true,
+	}).Create(account).Error; err != nil {
+		return err
+	}
+	return tx.Where("code = ?", account.Code).First(account).Error
+}
+
+// lockWallet re-reads a wallet under a row lock, so a balance check and the
+// entry posted after it see the same balance.
+func lockWallet(tx *gorm.DB, walletID string) (*models.Wallet, error) {
+	var wallet models.Wallet
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", walletID).
+		First(&wallet).Error
+	return &wallet, err
+}
+
+func walletAccountCode(walletID string, kind models.LedgerAccountKind) string {
+	if kind == models.LedgerAccountWalletHeld {
+		return "wallet:" + walletID + ":held"
+	}
+	return "wallet:" + walletID + ":available"
+}
+
+func platformAccountCode(kind models.LedgerAccountKind, currency string) string {
+	return fmt.Sprintf("platform:%s:%s", kind, currency)
+}
+
+// platformNormalBalance is debit for cash, which the platform holds, and
+// credit for the rest, which it owes or has earned.
+func platformNormalBalance(kind models.LedgerAccountKind) models.NormalBalance {
+	if kind == models.LedgerAccountCash {
+		return models.NormalBalanceDebit
+	}
+	return models.NormalBalanceCredit
+}
+
+// counterAccount is the platform account that a wallet debit or credit for
+// the given reference type is booked against.
+func counterAccount(referenceType string) models.LedgerAccountKind {
+	switch referenceType {
+	case "topup", "withdrawal", "cash_collection", "cash_settlement":
+		return models.LedgerAccountCash
+	case "ride_commission", "driver_penalty", "subscription_fee":
+		return models.LedgerAccountRevenue
+	default:
+		return models.LedgerAccountClearing
+	}
+}
@@ -0,0 +1,343 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/wallet/dto"
+	"github.com/umar5678/go-backend/internal/utils/pdf"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+func (s *service) ListTransactions(ctx context.Context, userID string, req dto.ListTransactionsRequest) (*dto.TransactionPageResponse, error) {
+	req.SetDefaults()
+
+	from, to, err := req.Range()
+	if err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+	var after *TransactionCursor
+	if req.Cursor != "" {
+		if after, err = decodeTransactionCursor(req.Cursor); err != nil {
+			return nil, response.BadRequest("cursor must be a nextCursor returned by a previous call")
+		}
+	}
+
+	wallet, err := s.userWallet(ctx, userID, req.WalletType)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := TransactionFilter{
+		Type:          req.Type,
+		Status:        req.Status,
+		ReferenceType: req.ReferenceType,
+		ReferenceID:   req.ReferenceID,
+		From:          from,
+		To:            to,
+	}
+
+	// Fetch one extra row to know whether another page follows.
+	transactions, err := s.repo.ListTransactions(ctx, wallet.ID, filter, after, req.Limit+1)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to fetch transactions", err)
+	}
+
+	hasMore := len(transactions) > req.Limit
+	if hasMore {
+		transactions = transactions[:req.Limit]
+	}
+
+	page := &dto.TransactionPageResponse{
+		Transactions: make([]*dto.TransactionResponse, len(transactions)),
+		HasMore:      hasMore,
+	}
+	for i, txn := range transactions {
+		page.Transactions[i] = dto.ToTransactionResponse(txn)
+	}
+	if hasMore {
+		page.NextCursor = encodeTransactionCursor(transactions[len(transactions)-1])
+	}
+
+	return page, nil
+}
+
+// GetStatement rebuilds a month of the wallet from its ledger: the opening
+// balance is the sum of every earlier completed transaction and each line
+// carries the running balance after it.
+func (s *service) GetStatement(ctx context.Context, userID string, req dto.StatementRequest) (*dto.StatementResponse, error) {
+	req.SetDefaults()
+
+	start, end, err := req.Period()
+	if err != nil {
+		return nil, response.BadRequest(err.Error())
+	}
+	if start.After(time.Now()) {
+		return nil, response.BadRequest("month must not be in the future")
+	}
+
+	wallet, err := s.userWallet(ctx, userID, req.WalletType)
+	if err != nil {
+		return nil, err
+	}
+
+	opening, err := s.repo.LedgerBalance(ctx, wallet.ID, start)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to compute opening balance", err)
+	}
+	transactions, err := s.repo.ListCompletedTransactions(ctx, wallet.ID, start, end)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to fetch transactions", err)
+	}
+
+	statement := &dto.StatementResponse{
+		WalletID:       wallet.ID,
+		WalletType:     wallet.WalletType,
+		Currency:       wallet.Currency,
+		Month:          req.Month,
+		PeriodStart:    start,
+		PeriodEnd:      end,
+		OpeningBalance: roundAmount(opening),
+		Lines:          make([]dto.StatementLine, 0, len(transactions)),
+	}
+
+	balance := opening
+	for _, txn := range transactions {
+		amount := ledgerAmount(txn)
+		balance += amount
+
+		line := dto.StatementLine{
+			TransactionID: txn.ID,
+			Date:          txn.CreatedAt,
+			Type:          txn.Type,
+			ReferenceType: derefString(txn.ReferenceType),
+			ReferenceID:   derefString(txn.ReferenceID),
+			Description:   derefString(txn.Description),
+			Balance:       roundAmount(balance),
+		}
+		if amount >= 0 {
+			line.Credit = amount
+			statement.TotalCredits += amount
+		} else {
+			line.Debit = -amount
+			statement.TotalDebits -= amount
+		}
+		statement.Lines = append(statement.Lines, line)
+	}
+	statement.TotalCredits = roundAmount(statement.TotalCredits)
+	statement.TotalDebits = roundAmount(statement.TotalDebits)
+	statement.ClosingBalance = roundAmount(balance)
+
+	return statement, nil
+}
+
+func (s *service) ExportStatement(ctx context.Context, userID string, req dto.StatementRequest) (*dto.StatementFile, error) {
+	req.SetDefaults()
+
+	statement, err := s.GetStatement(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	fileName := fmt.Sprintf("wallet_statement_%s", statement.Month)
+	switch req.Format {
+	case "pdf":
+		return &dto.StatementFile{
+			FileName:    fileName + ".pdf",
+			ContentType: "application/pdf",
+			Data:        renderStatementPDF(statement),
+		}, nil
+	default:
+		data, err := renderStatementCSV(statement)
+		if err != nil {
+			return nil, response.InternalServerError("Failed to render statement", err)
+		}
+		return &dto.StatementFile{
+			FileName:    fileName + ".csv",
+			ContentType: "text/csv; charset=utf-8",
+			Data:        data,
+		}, nil
+	}
+}
+
+func (s *service) userWallet(ctx context.Context, userID string, walletType models.WalletType) (*models.Wallet, error) {
+	wallet, err := s.repo.FindUserWallet(ctx, userID, walletType)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Wallet")
+		}
+		return nil, response.InternalServerError("Failed to fetch wallet", err)
+	}
+	return wallet, nil
+}
+
+// ledgerAmount is the signed effect of a transaction on the balance. It must
+// agree with ledgerAmountSQL.
+func ledgerAmount(txn *models.WalletTransaction) float64 {
+	refType := derefString(txn.ReferenceType)
+	switch {
+	case txn.Type == models.TransactionTypeCredit, txn.Type == models.TransactionTypeRefund,
+		txn.Type == models.TransactionTypeTransfer && refType == "transfer_in":
+		return txn.Amount
+	case txn.Type == models.TransactionTypeDebit,
+		txn.Type == models.TransactionTypeTransfer && refType == "transfer_out":
+		return -txn.Amount
+	default:
+		return 0
+	}
+}
+
+func encodeTransactionCursor(txn *models.WalletTransaction) string {
+	raw := txn.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + txn.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeTransactionCursor(cursor string) (*TransactionCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return nil, errors.New("malformed cursor")
+	}
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	return &TransactionCursor{CreatedAt: t, ID: id}, nil
+}
+
+func renderStatementCSV(statement *dto.StatementResponse) ([]byte, error) {
+	rows := [][]string{
+		{"date", "transaction_id", "type", "reference_type", "reference_id", "description", "credit", "debit", "balance"},
+		{statement.PeriodStart.Format(time.RFC3339), "", "", "", "", "Opening balance", "", "", formatStatementAmount(statement.OpeningBalance)},
+	}
+	for _, line := range statement.Lines {
+		rows = append(rows, []string{
+			line.Date.UTC().Format(time.RFC3339),
+			line.TransactionID,
+			string(line.Type),
+			line.ReferenceType,
+			line.ReferenceID,
+			line.Description,
+			formatStatementAmount(line.Credit),
+			formatStatementAmount(line.Debit),
+			formatStatementAmount(line.Balance),
+		})
+	}
+	rows = append(rows, []string{
+		statement.PeriodEnd.Format(time.RFC3339), "", "", "", "", "Closing balance",
+		formatStatementAmount(statement.TotalCredits),
+		formatStatementAmount(statement.TotalDebits),
+		formatStatementAmount(statement.ClosingBalance),
+	})
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.WriteAll(rows); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func renderStatementPDF(statement *dto.StatementResponse) []byte {
+	const (
+		left       = 50.0
+		right      = pdf.PageWidth - 50.0
+		bottom     = pdf.PageHeight - 60.0
+		colDesc    = left + 70
+		colCredit  = right - 150
+		colDebit   = right - 75
+		lineHeight = 14.0
+	)
+
+	doc := pdf.NewDocument()
+	y := 60.0
+
+	money := func(amount float64) string {
+		return fmt.Sprintf("%s %.2f", statement.Currency, amount)
+	}
+	header := func() {
+		doc.Text(left, y, 9, true, "Date")
+		doc.Text(colDesc, y, 9, true, "Description")
+		doc.TextRight(colCredit, y, 9, true, "Credit")
+		doc.TextRight(colDebit, y, 9, true, "Debit")
+		doc.TextRight(right, y, 9, true, "Balance")
+		y += 6
+		doc.Rule(left, right, y)
+		y += lineHeight
+	}
+
+	doc.Text(left, y, 18, true, "Wallet statement")
+	doc.TextRight(right, y, 12, false, statement.PeriodStart.Format("January 2006"))
+	y += 18
+	doc.Text(left, y, 9, false, "Wallet "+statement.WalletID+" ("+string(statement.WalletType)+")")
+	y += 24
+
+	summary := [][2]string{
+		{"Opening balance", money(statement.OpeningBalance)},
+		{"Total credits", money(statement.TotalCredits)},
+		{"Total debits", money(statement.TotalDebits)},
+		{"Closing balance", money(statement.ClosingBalance)},
+	}
+	for _, row := range summary {
+		doc.Text(left, y, 10, false, row[0])
+		doc.TextRight(left+250, y, 10, false, row[1])
+		y += lineHeight
+	}
+	y += 16
+
+	header()
+	if len(statement.Lines) == 0 {
+		doc.Text(left, y, 10, false, "No transactions this month.")
+	}
+	for _, line := range statement.Lines {
+		if y > bottom {
+			doc.NewPage()
+			y = 60
+			header()
+		}
+		description := line.Description
+		if description == "" {
+			description = strings.ReplaceAll(line.ReferenceType, "_", " ")
+		}
+		doc.Text(left, y, 9, false, line.Date.UTC().Format("02 Jan 15:04"))
+		doc.Text(colDesc, y, 9, false, pdf.Truncate(description, 40))
+		if line.Credit > 0 {
+			doc.TextRight(colCredit, y, 9, false, formatStatementAmount(line.Credit))
+		}
+		if line.Debit > 0 {
+			doc.TextRight(colDebit, y, 9, false, formatStatementAmount(line.Debit))
+		}
+		doc.TextRight(right, y, 9, false, formatStatementAmount(line.Balance))
+		y += lineHeight
+	}
+
+	return doc.Bytes()
+}
+
+func formatStatementAmount(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+func roundAmount(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
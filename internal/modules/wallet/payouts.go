@@ -0,0 +1,348 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/wallet/dto"
+	"github.com/umar5678/go-backend/internal/services/health"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+	websocketutil "github.com/umar5678/go-backend/internal/websocket/websocketutils"
+	"gorm.io/gorm"
+)
+
+const (
+	payoutRetryBatchSize = 50
+	payoutRetryLease     = 5 * time.Minute
+)
+
+func (s *service) ConfigurePayoutRetry(cfg config.PayoutRetryConfig) {
+	s.payoutRetry = cfg
+}
+
+// PayoutAccruer holds order payouts for a later settlement instead of
+// crediting them right away. It is satisfied by settlements.Service.
+type PayoutAccruer interface {
+	AccruePayout(ctx context.Context, credit *models.PayoutCredit) error
+}
+
+func (s *service) SetPayoutAccruer(accruer PayoutAccruer) {
+	s.payoutAccruer = accruer
+}
+
+// CreditProviderPayout credits a provider for completed work. If the credit
+// fails it is queued for retry instead of being lost, and queued reports true
+// so the caller can show the payout as pending; err is only returned when the
+// credit could not be queued either. With settlements on, order payouts are
+// accrued for the weekly settlement instead, reported as queued with the
+// credit's status set to accrued.
+func (s *service) CreditProviderPayout(ctx context.Context, credit *models.PayoutCredit) (queued bool, err error) {
+	if credit.Metadata == nil {
+		credit.Metadata = models.JSONBMap{}
+	}
+
+	if s.payoutAccruer != nil && credit.ReferenceType != models.PayoutReferenceSettlement {
+		accrueErr := s.payoutAccruer.AccruePayout(ctx, credit)
+		if accrueErr == nil {
+			credit.Status = models.PayoutCreditStatusAccrued
+			return true, nil
+		}
+		// Paying now beats leaving the provider with nothing on record.
+		logger.Error("failed to accrue provider payout, crediting directly",
+			"error", accrueErr,
+			"referenceType", credit.ReferenceType,
+			"referenceID", credit.ReferenceID,
+			"amount", credit.Amount)
+	}
+
+	txn, creditErr := s.CreditServiceProviderWallet(ctx, credit.ProviderUserID, credit.Amount,
+		credit.TransactionType, credit.ReferenceID, credit.Description, credit.Metadata)
+	if creditErr == nil {
+		logger.Info("provider payout credited", "referenceID", credit.ReferenceID, "transactionID", txn.ID, "amount", credit.Amount)
+		return false, nil
+	}
+
+	now := time.Now()
+	next := now.Add(s.payoutBackoff(1))
+	credit.Status = models.PayoutCreditStatusPending
+	credit.Attempts = 1
+	credit.LastError = creditErr.Error()
+	credit.NextAttemptAt = &next
+
+	if err := s.repo.CreatePayoutCredit(ctx, credit); err != nil {
+		logger.Error("failed to queue provider payout credit",
+			"error", err,
+			"creditError", creditErr,
+			"referenceType", credit.ReferenceType,
+			"referenceID", credit.ReferenceID,
+			"providerUserID", credit.ProviderUserID,
+			"amount", credit.Amount)
+		return false, creditErr
+	}
+
+	logger.Warn("provider payout credit failed, queued for retry",
+		"error", creditErr,
+		"payoutCreditID", credit.ID,
+		"referenceType", credit.ReferenceType,
+		"referenceID", credit.ReferenceID,
+		"amount", credit.Amount,
+		"nextAttemptAt", next)
+
+	return true, nil
+}
+
+// ProcessPayoutCredits retries the queued credits that are due.
+func (s *service) ProcessPayoutCredits(ctx context.Context) error {
+	now := time.Now()
+	credits, err := s.repo.FindDuePayoutCredits(ctx, now, payoutRetryBatchSize)
+	if err != nil {
+		return err
+	}
+
+	for _, credit := range credits {
+		claimed, err := s.repo.ClaimPayoutCredit(ctx, credit.ID, now, now.Add(payoutRetryLease))
+		if err != nil {
+			logger.Warn("failed to claim payout credit", "error", err, "payoutCreditID", credit.ID)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+		s.attemptPayoutCredit(ctx, credit)
+	}
+
+	return nil
+}
+
+func (s *service) ListPayoutCredits(ctx context.Context, req dto.ListPayoutCreditsRequest) ([]*dto.PayoutCreditResponse, int64, error) {
+	req.SetDefaults()
+
+	filters := map[string]interface{}{
+		"status":        req.Status,
+		"referenceType": req.ReferenceType,
+	}
+
+	credits, total, err := s.repo.ListPayoutCredits(ctx, filters, req.Page, req.Limit)
+	if err != nil {
+		return nil, 0, response.InternalServerError("Failed to fetch payout credits", err)
+	}
+
+	result := make([]*dto.PayoutCreditResponse, len(credits))
+	for i, credit := range credits {
+		result[i] = dto.ToPayoutCreditResponse(credit)
+	}
+
+	return result, total, nil
+}
+
+// RetryPayoutCredit runs one more attempt right away, typically after the
+// cause of a stuck credit has been fixed.
+func (s *service) RetryPayoutCredit(ctx context.Context, adminID, creditID string) (*dto.PayoutCreditResponse, error) {
+	credit, err := s.getOpenPayoutCredit(ctx, creditID)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("payout credit retried by admin", "payoutCreditID", credit.ID, "adminID", adminID, "attempts", credit.Attempts)
+
+	before := *credit
+	s.attemptPayoutCredit(ctx, credit)
+	s.auditPayoutCredit(ctx, adminID, "payout_credit.retry", &before, credit, "")
+	return dto.ToPayoutCreditResponse(credit), nil
+}
+
+// ResolvePayoutCredit closes a credit that was settled outside the wallet,
+// e.g. paid out by bank transfer.
+func (s *service) ResolvePayoutCredit(ctx context.Context, adminID, creditID string, req dto.ResolvePayoutCreditRequest) (*dto.PayoutCreditResponse, error) {
+	credit, err := s.getOpenPayoutCredit(ctx, creditID)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *credit
+	now := time.Now()
+	credit.Status = models.PayoutCreditStatusResolved
+	credit.ResolvedBy = &adminID
+	credit.ResolutionNote = req.Note
+	credit.ResolvedAt = &now
+	credit.NextAttemptAt = nil
+
+	if err := s.repo.UpdatePayoutCredit(ctx, credit); err != nil {
+		return nil, response.InternalServerError("Failed to resolve payout credit", err)
+	}
+
+	s.settleReferencePayout(ctx, credit)
+
+	logger.Info("payout credit resolved manually", "payoutCreditID", credit.ID, "adminID", adminID, "amount", credit.Amount)
+
+	s.auditPayoutCredit(ctx, adminID, "payout_credit.resolve", &before, credit, req.Note)
+	return dto.ToPayoutCreditResponse(credit), nil
+}
+
+func (s *service) getOpenPayoutCredit(ctx context.Context, creditID string) (*models.PayoutCredit, error) {
+	credit, err := s.repo.FindPayoutCreditByID(ctx, creditID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Payout credit")
+		}
+		return nil, response.InternalServerError("Failed to fetch payout credit", err)
+	}
+	if credit.Status != models.PayoutCreditStatusPending && credit.Status != models.PayoutCreditStatusStuck {
+		return nil, response.BadRequest(fmt.Sprintf("Payout credit is already %s", credit.Status))
+	}
+	return credit, nil
+}
+
+func (s *service) attemptPayoutCredit(ctx context.Context, credit *models.PayoutCredit) {
+	credit.Attempts++
+	now := time.Now()
+
+	txn, err := s.CreditServiceProviderWallet(ctx, credit.ProviderUserID, credit.Amount,
+		credit.TransactionType, credit.ReferenceID, credit.Description, credit.Metadata)
+	if err == nil {
+		credit.Status = models.PayoutCreditStatusCredited
+		credit.TransactionID = &txn.ID
+		credit.CreditedAt = &now
+		credit.NextAttemptAt = nil
+		credit.LastError = ""
+		if err := s.repo.UpdatePayoutCredit(ctx, credit); err != nil {
+			// The money has moved; a retry of this row would pay twice.
+			logger.Error("payout credited but queue entry not updated", "error", err, "payoutCreditID", credit.ID, "transactionID", txn.ID)
+			return
+		}
+		s.settleReferencePayout(ctx, credit)
+		logger.Info("queued payout credit succeeded", "payoutCreditID", credit.ID, "attempts", credit.Attempts, "transactionID", txn.ID)
+		return
+	}
+
+	credit.LastError = err.Error()
+	maxAttempts := s.payoutRetry.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 5
+	}
+
+	if credit.Attempts >= maxAttempts {
+		credit.Status = models.PayoutCreditStatusStuck
+		credit.NextAttemptAt = nil
+		if credit.AlertedAt == nil {
+			credit.AlertedAt = &now
+			s.alertStuckPayoutCredit(credit)
+		}
+	} else {
+		credit.Status = models.PayoutCreditStatusPending
+		next := now.Add(s.payoutBackoff(credit.Attempts))
+		credit.NextAttemptAt = &next
+	}
+
+	if err := s.repo.UpdatePayoutCredit(ctx, credit); err != nil {
+		logger.Error("failed to update payout credit", "error", err, "payoutCreditID", credit.ID)
+	}
+
+	logger.Warn("queued payout credit failed",
+		"error", credit.LastError,
+		"payoutCreditID", credit.ID,
+		"attempts", credit.Attempts,
+		"status", credit.Status)
+}
+
+// settleReferencePayout marks the order paid once none of its credits are
+// still waiting.
+func (s *service) settleReferencePayout(ctx context.Context, credit *models.PayoutCredit) {
+	open, err := s.repo.CountOpenPayoutCredits(ctx, credit.ReferenceType, credit.ReferenceID)
+	if err != nil {
+		logger.Warn("failed to count open payout credits", "error", err, "referenceID", credit.ReferenceID)
+		return
+	}
+	if open > 0 {
+		return
+	}
+	if err := s.repo.SetReferencePayoutStatus(ctx, credit.ReferenceType, credit.ReferenceID, models.OrderPayoutStatusPaid); err != nil {
+		logger.Warn("failed to update payout status", "error", err, "referenceType", credit.ReferenceType, "referenceID", credit.ReferenceID)
+	}
+}
+
+func (s *service) alertStuckPayoutCredit(credit *models.PayoutCredit) {
+	logger.Error("provider payout credit stuck after retries",
+		"payoutCreditID", credit.ID,
+		"referenceType", credit.ReferenceType,
+		"referenceID", credit.ReferenceID,
+		"providerUserID", credit.ProviderUserID,
+		"amount", credit.Amount,
+		"attempts", credit.Attempts,
+		"lastError", credit.LastError)
+
+	websocketutil.BroadcastToRole("admin", websocket.TypePayoutCreditStuck, map[string]interface{}{
+		"payoutCreditId":  credit.ID,
+		"referenceType":   credit.ReferenceType,
+		"referenceId":     credit.ReferenceID,
+		"referenceNumber": credit.ReferenceNumber,
+		"providerUserId":  credit.ProviderUserID,
+		"amount":          credit.Amount,
+		"attempts":        credit.Attempts,
+		"lastError":       credit.LastError,
+		"message":         fmt.Sprintf("Payout of %.2f for %s could not be credited after %d attempts", credit.Amount, credit.ReferenceNumber, credit.Attempts),
+	})
+}
+
+func (s *service) payoutBackoff(attempts int) time.Duration {
+	backoff := s.payoutRetry.InitialBackoff
+	if backoff <= 0 {
+		backoff = time.Minute
+	}
+	maxBackoff := s.payoutRetry.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = time.Hour
+	}
+	for i := 1; i < attempts && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	return backoff
+}
+
+// PayoutRetryWorker drains the payout credit queue on a fixed interval.
+type PayoutRetryWorker struct {
+	service  Service
+	interval time.Duration
+}
+
+func NewPayoutRetryWorker(service Service, cfg config.PayoutRetryConfig) *PayoutRetryWorker {
+	interval := cfg.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	return &PayoutRetryWorker{service: service, interval: interval}
+}
+
+func (w *PayoutRetryWorker) Start(ctx context.Context) {
+	heartbeat := health.NewHeartbeat("payout_retry", w.interval)
+
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				runCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+				if err := w.service.ProcessPayoutCredits(runCtx); err != nil {
+					logger.Error("payout retry run failed", "error", err)
+				}
+				cancel()
+				heartbeat.Beat()
+			}
+		}
+	}()
+
+	logger.Info("payout retry worker started", "interval", w.interval)
+}
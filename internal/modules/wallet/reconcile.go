@@ -0,0 +1,150 @@
+package wallet
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/wallet/dto"
+	"github.com/umar5678/go-backend/internal/services/health"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+const ledgerDriftLimit = 100
+
+// ReconcileLedger checks that every journal entry balances, that every
+// account's balance is the sum of its postings and that every wallet's
+// balances match its accounts. Anything that drifted is logged and reported.
+func (s *service) ReconcileLedger(ctx context.Context) (*dto.LedgerReconciliationResponse, error) {
+	report := &dto.LedgerReconciliationResponse{CheckedAt: time.Now()}
+
+	var err error
+	if report.UnbalancedEntries, err = s.repo.UnbalancedEntries(ctx, ledgerDriftLimit); err != nil {
+		return nil, response.InternalServerError("Failed to check journal entries", err)
+	}
+	if report.AccountDrifts, err = s.repo.AccountDrifts(ctx, ledgerDriftLimit); err != nil {
+		return nil, response.InternalServerError("Failed to check ledger accounts", err)
+	}
+	if report.WalletDrifts, err = s.repo.WalletDrifts(ctx, ledgerDriftLimit); err != nil {
+		return nil, response.InternalServerError("Failed to check wallet balances", err)
+	}
+
+	for _, entry := range report.UnbalancedEntries {
+		logger.Error("ledger drift: unbalanced journal entry",
+			"entryID", entry.EntryID, "currency", entry.Currency, "imbalance", entry.Imbalance)
+	}
+	for _, account := range report.AccountDrifts {
+		logger.Error("ledger drift: account balance differs from postings",
+			"accountID", account.AccountID, "code", account.Code,
+			"balance", account.Balance, "postedBalance", account.PostedBalance)
+	}
+	for _, wallet := range report.WalletDrifts {
+		logger.Error("ledger drift: wallet balance differs from ledger",
+			"walletID", wallet.WalletID, "userID", wallet.UserID,
+			"balance", wallet.Balance, "ledgerBalance", wallet.LedgerBalance,
+			"heldBalance", wallet.HeldBalance, "ledgerHeldBalance", wallet.LedgerHeldBalance)
+	}
+
+	report.Clean = len(report.UnbalancedEntries) == 0 && len(report.AccountDrifts) == 0 && len(report.WalletDrifts) == 0
+	if report.UnbalancedEntries == nil {
+		report.UnbalancedEntries = []dto.UnbalancedEntry{}
+	}
+	if report.AccountDrifts == nil {
+		report.AccountDrifts = []dto.AccountDrift{}
+	}
+	if report.WalletDrifts == nil {
+		report.WalletDrifts = []dto.WalletDrift{}
+	}
+	return report, nil
+}
+
+func (s *service) GetJournalEntry(ctx context.Context, entryID string) (*dto.JournalEntryResponse, error) {
+	entry, err := s.repo.FindJournalEntry(ctx, entryID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Journal entry")
+		}
+		return nil, response.InternalServerError("Failed to fetch journal entry", err)
+	}
+
+	ids := make([]string, len(entry.Postings))
+	for i, posting := range entry.Postings {
+		ids[i] = posting.AccountID
+	}
+	accounts, err := s.repo.FindLedgerAccounts(ctx, ids)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to fetch ledger accounts", err)
+	}
+	byID := make(map[string]*models.LedgerAccount, len(accounts))
+	for _, account := range accounts {
+		byID[account.ID] = account
+	}
+
+	result := &dto.JournalEntryResponse{
+		ID:            entry.ID,
+		Kind:          entry.Kind,
+		ReferenceType: entry.ReferenceType,
+		ReferenceID:   entry.ReferenceID,
+		Description:   entry.Description,
+		CreatedAt:     entry.CreatedAt,
+		Postings:      make([]dto.LedgerPostingResponse, len(entry.Postings)),
+	}
+	for i, posting := range entry.Postings {
+		line := dto.LedgerPostingResponse{
+			AccountID: posting.AccountID,
+			Amount:    posting.Amount,
+			Currency:  posting.Currency,
+		}
+		if account := byID[posting.AccountID]; account != nil {
+			line.AccountCode = account.Code
+			line.AccountKind = account.Kind
+		}
+		result.Postings[i] = line
+	}
+	return result, nil
+}
+
+// LedgerReconciler runs ReconcileLedger on a fixed interval so drift shows up
+// in the logs without anyone asking for it.
+type LedgerReconciler struct {
+	service  Service
+	interval time.Duration
+}
+
+func NewLedgerReconciler(service Service, interval time.Duration) *LedgerReconciler {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &LedgerReconciler{service: service, interval: interval}
+}
+
+func (w *LedgerReconciler) Start(ctx context.Context) {
+	heartbeat := health.NewHeartbeat("wallet_ledger_reconcile", w.interval)
+
+	go func() {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				runCtx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+				report, err := w.service.ReconcileLedger(runCtx)
+				if err != nil {
+					logger.Error("ledger reconciliation failed", "error", err)
+				} else if report.Clean {
+					logger.Info("ledger reconciled without drift")
+				}
+				cancel()
+				heartbeat.Beat()
+			}
+		}
+	}()
+
+	logger.Info("ledger reconciler started", "interval", w.interval)
+}
@@ -2,12 +2,23 @@ package wallet
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/umar5678/go-backend/internal/config"
 	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/wallet/dto"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// errVersionConflict means a wallet or hold was written by someone else
+// between being read and being updated.
+var errVersionConflict = errors.New("row was modified concurrently")
+
 type Repository interface {
 	CreateWallet(ctx context.Context, wallet *models.Wallet) error
 	FindWalletByID(ctx context.Context, id string) (*models.Wallet, error)
@@ -16,13 +27,51 @@ type Repository interface {
 
 	CreateTransaction(ctx context.Context, tx *models.WalletTransaction) error
 	FindTransactionByID(ctx context.Context, id string) (*models.WalletTransaction, error)
-	ListTransactions(ctx context.Context, walletID string, filters map[string]interface{}, page, limit int) ([]*models.WalletTransaction, int64, error)
+	// ListTransactions returns up to limit transactions newest first,
+	// starting after the cursor when one is given.
+	ListTransactions(ctx context.Context, walletID string, filter TransactionFilter, after *TransactionCursor, limit int) ([]*models.WalletTransaction, error)
+	// ListCompletedTransactions returns the completed transactions in
+	// [from, to), oldest first.
+	ListCompletedTransactions(ctx context.Context, walletID string, from, to time.Time) ([]*models.WalletTransaction, error)
+	// LedgerBalance sums the signed completed transactions before the given
+	// time.
+	LedgerBalance(ctx context.Context, walletID string, before time.Time) (float64, error)
+	// FindUserWallet returns the user's wallet of the given type, or their
+	// oldest wallet when walletType is empty.
+	FindUserWallet(ctx context.Context, userID string, walletType models.WalletType) (*models.Wallet, error)
 
 	CreateHold(ctx context.Context, hold *models.WalletHold) error
 	FindHoldByID(ctx context.Context, id string) (*models.WalletHold, error)
 	FindHoldsByReference(ctx context.Context, refType, refID string) ([]*models.WalletHold, error)
 	UpdateHold(ctx context.Context, hold *models.WalletHold) error
-	ReleaseExpiredHolds(ctx context.Context) error
+	FindActiveHoldsByWallet(ctx context.Context, walletID string) ([]*models.WalletHold, error)
+	FindReferenceStatuses(ctx context.Context, refType string, refIDs []string) (map[string]string, error)
+	ReleaseExpiredHolds(ctx context.Context, now time.Time, limit int) ([]*models.WalletHold, error)
+
+	FindJournalEntry(ctx context.Context, id string) (*models.JournalEntry, error)
+	FindLedgerAccounts(ctx context.Context, ids []string) ([]*models.LedgerAccount, error)
+	// The reconciliation checks below each return at most limit rows.
+	UnbalancedEntries(ctx context.Context, limit int) ([]dto.UnbalancedEntry, error)
+	AccountDrifts(ctx context.Context, limit int) ([]dto.AccountDrift, error)
+	WalletDrifts(ctx context.Context, limit int) ([]dto.WalletDrift, error)
+
+	CreatePayoutCredit(ctx context.Context, credit *models.PayoutCredit) error
+	FindPayoutCreditByID(ctx context.Context, id string) (*models.PayoutCredit, error)
+	UpdatePayoutCredit(ctx context.Context, credit *models.PayoutCredit) error
+	ListPayoutCredits(ctx context.Context, filters map[string]interface{}, page, limit int) ([]*models.PayoutCredit, int64, error)
+	FindDuePayoutCredits(ctx context.Context, now time.Time, limit int) ([]*models.PayoutCredit, error)
+	ClaimPayoutCredit(ctx context.Context, id string, now, leaseUntil time.Time) (bool, error)
+	CountOpenPayoutCredits(ctx context.Context, refType, refID string) (int64, error)
+	SetReferencePayoutStatus(ctx context.Context, refType, refID, status string) error
+
+	// FindDriverProfile accepts the driver profile ID or the driver's user ID.
+	FindDriverProfile(ctx context.Context, driverID string) (*models.DriverProfile, error)
+	UpdateDriverProfile(ctx context.Context, driverID string, updates map[string]interface{}) error
+	// SyncCreditThresholds sets min_balance_threshold from each driver's tier
+	// for drivers without a limit override.
+	SyncCreditThresholds(ctx context.Context, cfg config.DriverCreditConfig) (int64, error)
+	ListDebtRepayments(ctx context.Context, userID string, page, limit int) ([]*models.DriverDebtRepayment, int64, error)
+	SumDebtRepayments(ctx context.Context, userID string, since time.Time) (float64, *time.Time, error)
 }
 
 type repository struct {
@@ -68,8 +117,11 @@ func (r *repository) FindWalletByUserID(ctx context.Context, userID string, wall
 	return &wallet, err
 }
 
+// UpdateWallet saves the wallet's settings; its balances only move through
+// Journal.Post. It returns errVersionConflict if the wallet changed since it
+// was read.
 func (r *repository) UpdateWallet(ctx context.Context, wallet *models.Wallet) error {
-	return r.db.WithContext(ctx).Save(wallet).Error
+	return saveVersioned(r.db.WithContext(ctx), wallet, &wallet.Version, "balance", "held_balance")
 }
 
 func (r *repository) CreateTransaction(ctx context.Context, tx *models.WalletTransaction) error {
@@ -85,30 +137,92 @@ func (r *repository) FindTransactionByID(ctx context.Context, id string) (*model
 	return &tx, err
 }
 
-func (r *repository) ListTransactions(ctx context.Context, walletID string, filters map[string]interface{}, page, limit int) ([]*models.WalletTransaction, int64, error) {
-	var transactions []*models.WalletTransaction
-	var total int64
+// TransactionFilter narrows a wallet's transactions; zero fields match all.
+// The range is [From, To).
+type TransactionFilter struct {
+	Type          models.TransactionType
+	Status        models.TransactionStatus
+	ReferenceType string
+	ReferenceID   string
+	From          *time.Time
+	To            *time.Time
+}
 
-	query := r.db.WithContext(ctx).Model(&models.WalletTransaction{}).
-		Where("wallet_id = ?", walletID)
+// TransactionCursor is the last transaction of the previous page.
+type TransactionCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
 
-	if txType, ok := filters["type"].(models.TransactionType); ok && txType != "" {
-		query = query.Where("transaction_type = ?", txType)
+// ledgerAmountSQL is the signed effect of a transaction on the balance.
+// Holds and releases only move the held balance. It must agree with
+// ledgerAmount.
+const ledgerAmountSQL = `CASE
+	WHEN type IN ('credit', 'refund') OR (type = 'transfer' AND reference_type = 'transfer_in') THEN amount
+	WHEN type = 'debit' OR (type = 'transfer' AND reference_type = 'transfer_out') THEN -amount
+	ELSE 0 END`
+
+func (r *repository) ListTransactions(ctx context.Context, walletID string, filter TransactionFilter, after *TransactionCursor, limit int) ([]*models.WalletTransaction, error) {
+	query := r.db.WithContext(ctx).Where("wallet_id = ?", walletID)
+
+	if filter.Type != "" {
+		query = query.Where("type = ?", filter.Type)
 	}
-	if status, ok := filters["status"].(models.TransactionStatus); ok && status != "" {
-		query = query.Where("status = ?", status)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.ReferenceType != "" {
+		query = query.Where("reference_type = ?", filter.ReferenceType)
+	}
+	if filter.ReferenceID != "" {
+		query = query.Where("reference_id = ?", filter.ReferenceID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	if after != nil {
+		query = query.Where("(created_at, id) < (?, ?)", after.CreatedAt, after.ID)
 	}
 
-	query.Count(&total)
-
-	offset := (page - 1) * limit
+	var transactions []*models.WalletTransaction
 	err := query.
-		Order("created_at DESC").
-		Offset(offset).
+		Order("created_at DESC, id DESC").
 		Limit(limit).
 		Find(&transactions).Error
+	return transactions, err
+}
 
-	return transactions, total, err
+func (r *repository) ListCompletedTransactions(ctx context.Context, walletID string, from, to time.Time) ([]*models.WalletTransaction, error) {
+	var transactions []*models.WalletTransaction
+	err := r.db.WithContext(ctx).
+		Where("wallet_id = ? AND status = ? AND created_at >= ? AND created_at < ?", walletID, models.TransactionStatusCompleted, from, to).
+		Order("created_at ASC, id ASC").
+		Find(&transactions).Error
+	return transactions, err
+}
+
+func (r *repository) LedgerBalance(ctx context.Context, walletID string, before time.Time) (float64, error) {
+	var balance float64
+	err := r.db.WithContext(ctx).
+		Model(&models.WalletTransaction{}).
+		Select("COALESCE(SUM("+ledgerAmountSQL+"), 0)").
+		Where("wallet_id = ? AND status = ? AND created_at < ?", walletID, models.TransactionStatusCompleted, before).
+		Scan(&balance).Error
+	return balance, err
+}
+
+func (r *repository) FindUserWallet(ctx context.Context, userID string, walletType models.WalletType) (*models.Wallet, error) {
+	query := r.db.WithContext(ctx).Preload("User").Where("user_id = ?", userID)
+	if walletType != "" {
+		query = query.Where("wallet_type = ?", walletType)
+	}
+
+	var wallet models.Wallet
+	err := query.Order("created_at ASC").First(&wallet).Error
+	return &wallet, err
 }
 
 func (r *repository) CreateHold(ctx context.Context, hold *models.WalletHold) error {
@@ -132,48 +246,331 @@ func (r *repository) FindHoldsByReference(ctx context.Context, refType, refID st
 	return holds, err
 }
 
+// UpdateHold returns errVersionConflict if the hold changed since it was
+// read, for example because it was captured or released meanwhile.
 func (r *repository) UpdateHold(ctx context.Context, hold *models.WalletHold) error {
-	return r.db.WithContext(ctx).Save(hold).Error
+	return saveVersioned(r.db.WithContext(ctx), hold, &hold.Version)
 }
 
-func (r *repository) ReleaseExpiredHolds(ctx context.Context) error {
-	now := time.Now()
-	var expiredHolds []*models.WalletHold
+// saveVersioned writes every column of model except omit, but only if the
+// row is still at *version, and bumps the version.
+func saveVersioned(db *gorm.DB, model interface{}, version *int64, omit ...string) error {
+	expected := *version
+	*version = expected + 1
+
+	result := db.Model(model).
+		Where("version = ?", expected).
+		Select("*").
+		Omit(append(omit, clause.Associations, "created_at")...).
+		Updates(model)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = errVersionConflict
+	}
+	if result.Error != nil {
+		*version = expected
+	}
+	return result.Error
+}
 
+func (r *repository) FindActiveHoldsByWallet(ctx context.Context, walletID string) ([]*models.WalletHold, error) {
+	var holds []*models.WalletHold
 	err := r.db.WithContext(ctx).
-		Where("status = ? AND expires_at < ?", models.TransactionStatusHeld, now).
-		Find(&expiredHolds).Error
-	if err != nil {
-		return err
+		Where("wallet_id = ? AND status IN ?", walletID, activeHoldStatuses).
+		Order("created_at DESC").
+		Find(&holds).Error
+	return holds, err
+}
+
+// holdReferenceTables maps a hold's reference type to the table that tracks
+// the ride or order the funds were held for.
+var holdReferenceTables = map[string]string{
+	"ride":          "rides",
+	"service_order": "service_orders",
+	"laundry_order": "laundry_orders",
+}
+
+func (r *repository) FindReferenceStatuses(ctx context.Context, refType string, refIDs []string) (map[string]string, error) {
+	statuses := make(map[string]string)
+	table, ok := holdReferenceTables[refType]
+	if !ok || len(refIDs) == 0 {
+		return statuses, nil
 	}
 
-	for _, hold := range expiredHolds {
-		var wallet models.Wallet
-		if err := r.db.WithContext(ctx).Where("id = ?", hold.WalletID).First(&wallet).Error; err != nil {
-			continue
-		}
+	var rows []struct {
+		ID     string
+		Status string
+	}
+	if err := r.db.WithContext(ctx).
+		Table(table).
+		Select("id, status").
+		Where("id IN ?", refIDs).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		statuses[row.ID] = row.Status
+	}
+	return statuses, nil
+}
 
-		wallet.HeldBalance -= hold.Amount
+// ReleaseExpiredHolds releases up to limit active holds that expired before
+// now, posting each release to the ledger, and returns them with their
+// wallets. Each hold is released in its own transaction that locks the
+// wallet first, the same order the other hold paths take.
+func (r *repository) ReleaseExpiredHolds(ctx context.Context, now time.Time, limit int) ([]*models.WalletHold, error) {
+	var holds []*models.WalletHold
+	if err := r.db.WithContext(ctx).
+		Where("status IN ? AND expires_at <= ?", activeHoldStatuses, now).
+		Order("expires_at").
+		Limit(limit).
+		Find(&holds).Error; err != nil {
+		return nil, err
+	}
 
+	released := make([]*models.WalletHold, 0, len(holds))
+	for _, hold := range holds {
 		err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-			if err := tx.Save(&wallet).Error; err != nil {
+			wallet, err := lockWallet(tx, hold.WalletID)
+			if err != nil {
 				return err
 			}
-
-			now := time.Now()
-			hold.Status = models.TransactionStatusReleased
-			hold.ReleasedAt = &now
-			if err := tx.Save(hold).Error; err != nil {
+			if _, err := settleHold(tx, wallet, hold, 0, "Hold expired"); err != nil {
 				return err
 			}
-
+			hold.Wallet = *wallet
 			return nil
 		})
-
-		if err != nil {
+		if errors.Is(err, errHoldInactive) {
 			continue
 		}
+		if err != nil {
+			return released, err
+		}
+		released = append(released, hold)
 	}
+	return released, nil
+}
 
-	return nil
+func (r *repository) FindJournalEntry(ctx context.Context, id string) (*models.JournalEntry, error) {
+	var entry models.JournalEntry
+	err := r.db.WithContext(ctx).
+		Preload("Postings", func(db *gorm.DB) *gorm.DB {
+			return db.Order("amount DESC")
+		}).
+		Where("id = ?", id).
+		First(&entry).Error
+	return &entry, err
+}
+
+func (r *repository) FindLedgerAccounts(ctx context.Context, ids []string) ([]*models.LedgerAccount, error) {
+	var accounts []*models.LedgerAccount
+	if len(ids) == 0 {
+		return accounts, nil
+	}
+	err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&accounts).Error
+	return accounts, err
+}
+
+func (r *repository) UnbalancedEntries(ctx context.Context, limit int) ([]dto.UnbalancedEntry, error) {
+	var rows []dto.UnbalancedEntry
+	err := r.db.WithContext(ctx).
+		Model(&models.LedgerPosting{}).
+		Select("entry_id, currency, SUM(amount) AS imbalance").
+		Group("entry_id, currency").
+		Having("SUM(amount) <> 0").
+		Limit(limit).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// postedBalanceSQL is an account's balance derived from its postings, in the
+// account's normal direction.
+const postedBalanceSQL = `COALESCE(p.total, 0) * CASE WHEN a.normal_balance = 'credit' THEN -1 ELSE 1 END`
+
+func (r *repository) AccountDrifts(ctx context.Context, limit int) ([]dto.AccountDrift, error) {
+	var rows []dto.AccountDrift
+	err := r.db.WithContext(ctx).
+		Table("ledger_accounts a").
+		Select("a.id AS account_id, a.code, a.balance, " + postedBalanceSQL + " AS posted_balance").
+		Joins("LEFT JOIN (SELECT account_id, SUM(amount) AS total FROM ledger_postings GROUP BY account_id) p ON p.account_id = a.id").
+		Where("a.balance <> " + postedBalanceSQL).
+		Limit(limit).
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *repository) WalletDrifts(ctx context.Context, limit int) ([]dto.WalletDrift, error) {
+	const (
+		ledgerBalance = "COALESCE(SUM(a.balance), 0)"
+		ledgerHeld    = "COALESCE(SUM(a.balance) FILTER (WHERE a.kind = 'wallet_held'), 0)"
+	)
+	var rows []dto.WalletDrift
+	err := r.db.WithContext(ctx).
+		Table("wallets w").
+		Select("w.id AS wallet_id, w.user_id, w.balance, w.held_balance, " +
+			ledgerBalance + " AS ledger_balance, " + ledgerHeld + " AS ledger_held_balance").
+		Joins("LEFT JOIN ledger_accounts a ON a.wallet_id = w.id").
+		Group("w.id").
+		Having("w.balance <> " + ledgerBalance + " OR w.held_balance <> " + ledgerHeld).
+		Limit(limit).
+		Scan(&rows).Error
+	return rows, err
+}
+
+func (r *repository) CreatePayoutCredit(ctx context.Context, credit *models.PayoutCredit) error {
+	return r.db.WithContext(ctx).Create(credit).Error
+}
+
+func (r *repository) FindPayoutCreditByID(ctx context.Context, id string) (*models.PayoutCredit, error) {
+	var credit models.PayoutCredit
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&credit).Error
+	return &credit, err
+}
+
+func (r *repository) UpdatePayoutCredit(ctx context.Context, credit *models.PayoutCredit) error {
+	return r.db.WithContext(ctx).Save(credit).Error
+}
+
+func (r *repository) ListPayoutCredits(ctx context.Context, filters map[string]interface{}, page, limit int) ([]*models.PayoutCredit, int64, error) {
+	var credits []*models.PayoutCredit
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.PayoutCredit{})
+	if status, ok := filters["status"].(string); ok && status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if refType, ok := filters["referenceType"].(string); ok && refType != "" {
+		query = query.Where("reference_type = ?", refType)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err := query.
+		Order("created_at ASC").
+		Offset(offset).
+		Limit(limit).
+		Find(&credits).Error
+
+	return credits, total, err
+}
+
+func (r *repository) FindDuePayoutCredits(ctx context.Context, now time.Time, limit int) ([]*models.PayoutCredit, error) {
+	var credits []*models.PayoutCredit
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", models.PayoutCreditStatusPending, now).
+		Order("next_attempt_at ASC").
+		Limit(limit).
+		Find(&credits).Error
+	return credits, err
+}
+
+// ClaimPayoutCredit pushes a due credit's next attempt out to leaseUntil so
+// that only one worker picks it up; it reports false if another got there first.
+func (r *repository) ClaimPayoutCredit(ctx context.Context, id string, now, leaseUntil time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.PayoutCredit{}).
+		Where("id = ? AND status = ? AND next_attempt_at <= ?", id, models.PayoutCreditStatusPending, now).
+		Update("next_attempt_at", leaseUntil)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *repository) CountOpenPayoutCredits(ctx context.Context, refType, refID string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&models.PayoutCredit{}).
+		Where("reference_type = ? AND reference_id = ? AND status IN ?", refType, refID,
+			[]models.PayoutCreditStatus{models.PayoutCreditStatusPending, models.PayoutCreditStatusStuck}).
+		Count(&count).Error
+	return count, err
+}
+
+// payoutReferenceTables lists the reference types whose table carries a
+// payout_status column shown to the provider.
+var payoutReferenceTables = map[string]string{
+	"service_order": "service_orders",
+}
+
+func (r *repository) SetReferencePayoutStatus(ctx context.Context, refType, refID, status string) error {
+	table, ok := payoutReferenceTables[refType]
+	if !ok {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Table(table).
+		Where("id = ?", refID).
+		Update("payout_status", status).Error
+}
+
+func (r *repository) FindDriverProfile(ctx context.Context, driverID string) (*models.DriverProfile, error) {
+	var driver models.DriverProfile
+	err := r.db.WithContext(ctx).
+		Where("id = ? OR user_id = ?", driverID, driverID).
+		First(&driver).Error
+	return &driver, err
+}
+
+func (r *repository) UpdateDriverProfile(ctx context.Context, driverID string, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).
+		Model(&models.DriverProfile{}).
+		Where("id = ?", driverID).
+		Updates(updates).Error
+}
+
+func (r *repository) SyncCreditThresholds(ctx context.Context, cfg config.DriverCreditConfig) (int64, error) {
+	tiers := make([]string, 0, len(cfg.TierLimits))
+	for tier := range cfg.TierLimits {
+		tiers = append(tiers, tier)
+	}
+	sort.Strings(tiers)
+
+	var cases strings.Builder
+	args := make([]interface{}, 0, 2*len(tiers)+1)
+	for _, tier := range tiers {
+		cases.WriteString(" WHEN ? THEN ?")
+		args = append(args, tier, -cfg.TierLimits[tier])
+	}
+	args = append(args, -cfg.Limit(cfg.DefaultTier))
+	threshold := fmt.Sprintf("(CASE credit_tier%s ELSE ? END)", cases.String())
+
+	result := r.db.WithContext(ctx).Exec(`
+		UPDATE driver_profiles
+		SET min_balance_threshold = `+threshold+`, updated_at = NOW()
+		WHERE credit_limit_override IS NULL AND deleted_at IS NULL
+		  AND min_balance_threshold IS DISTINCT FROM `+threshold,
+		append(args, args...)...)
+	return result.RowsAffected, result.Error
+}
+
+func (r *repository) ListDebtRepayments(ctx context.Context, userID string, page, limit int) ([]*models.DriverDebtRepayment, int64, error) {
+	var repayments []*models.DriverDebtRepayment
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&models.DriverDebtRepayment{}).Where("user_id = ?", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.
+		Order("created_at DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&repayments).Error
+	return repayments, total, err
+}
+
+func (r *repository) SumDebtRepayments(ctx context.Context, userID string, since time.Time) (float64, *time.Time, error) {
+	var row struct {
+		Total  float64
+		LastAt *time.Time
+	}
+	err := r.db.WithContext(ctx).
+		Model(&models.DriverDebtRepayment{}).
+		Select("COALESCE(SUM(amount), 0) AS total, MAX(created_at) AS last_at").
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Scan(&row).Error
+	return row.Total, row.LastAt, err
 }
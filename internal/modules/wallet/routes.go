@@ -16,14 +16,35 @@ func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gi
 		wallet.POST("/withdraw", handler.WithdrawFunds)
 		wallet.POST("/transfer", handler.TransferFunds)
 
+		wallet.GET("/holds", handler.GetActiveHolds)
 		wallet.POST("/hold", handler.HoldFunds)
 		wallet.POST("/hold/release", handler.ReleaseHold)
 		wallet.POST("/hold/capture", handler.CaptureHold)
 
-		wallet.GET("/transactions", handler.GetTransactionHistory)
+		wallet.GET("/transactions", handler.ListTransactions)
 		wallet.GET("/transactions/:id", handler.GetTransaction)
+		wallet.GET("/statement", handler.GetStatement)
 
 		wallet.POST("/cash/collect", middleware.RequireRole("driver"), handler.RecordCashCollection)
 		wallet.POST("/cash/settle", middleware.RequireRole("driver"), handler.RecordCashPayment)
+		wallet.GET("/debt-repayments", middleware.RequireRole("driver"), handler.ListDebtRepayments)
+
+		admin := wallet.Group("/admin")
+		admin.Use(middleware.RequireAdmin())
+		{
+			admin.GET("/payout-credits", handler.ListPayoutCredits)
+			admin.POST("/payout-credits/:id/retry", handler.RetryPayoutCredit)
+			admin.POST("/payout-credits/:id/resolve", handler.ResolvePayoutCredit)
+
+			admin.GET("/ledger/reconciliation", handler.ReconcileLedger)
+			admin.GET("/ledger/entries/:id", handler.GetJournalEntry)
+
+			admin.GET("/driver-credit-tiers", handler.ListDriverCreditTiers)
+			admin.GET("/drivers/:driverId/credit", handler.GetDriverCredit)
+			admin.PUT("/drivers/:driverId/credit", handler.UpdateDriverCredit)
+			admin.POST("/drivers/:driverId/credit/override", handler.SetDriverCreditOverride)
+			admin.DELETE("/drivers/:driverId/credit/override", handler.ClearDriverCreditOverride)
+			admin.GET("/drivers/:driverId/debt-repayments", handler.ListDriverDebtRepayments)
+		}
 	}
 }
@@ -6,12 +6,18 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/umar5678/go-backend/internal/config"
 	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/audit"
+	"github.com/umar5678/go-backend/internal/modules/currency"
 	notificationsmodule "github.com/umar5678/go-backend/internal/modules/notifications"
 	"github.com/umar5678/go-backend/internal/modules/wallet/dto"
 	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/services/tracing"
 	"github.com/umar5678/go-backend/internal/utils/logger"
 	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+	"go.opentelemetry.io/otel/attribute"
 	"gorm.io/gorm"
 )
 
@@ -22,13 +28,17 @@ type Service interface {
 	AddFunds(ctx context.Context, userID string, req dto.AddFundsRequest) (*dto.TransactionResponse, error)
 	WithdrawFunds(ctx context.Context, userID string, req dto.WithdrawFundsRequest) (*dto.TransactionResponse, error)
 
-	GetTransactionHistory(ctx context.Context, userID string, req dto.TransactionHistoryRequest) ([]*dto.TransactionResponse, int64, error)
 	GetTransaction(ctx context.Context, userID string, transactionID string) (*dto.TransactionResponse, error)
-	ListTransactions(ctx context.Context, userID string, req dto.ListTransactionsRequest) ([]*dto.TransactionResponse, int64, error)
+	ListTransactions(ctx context.Context, userID string, req dto.ListTransactionsRequest) (*dto.TransactionPageResponse, error)
+	GetStatement(ctx context.Context, userID string, req dto.StatementRequest) (*dto.StatementResponse, error)
+	ExportStatement(ctx context.Context, userID string, req dto.StatementRequest) (*dto.StatementFile, error)
 
 	HoldFunds(ctx context.Context, userID string, req dto.HoldFundsRequest) (*dto.HoldResponse, error)
 	ReleaseHold(ctx context.Context, userID string, req dto.ReleaseHoldRequest) error
 	CaptureHold(ctx context.Context, userID string, req dto.CaptureHoldRequest) (*dto.TransactionResponse, error)
+	GetActiveHolds(ctx context.Context, userID string) (*dto.WalletHoldsResponse, error)
+	ExtendHold(ctx context.Context, userID, holdID string, until time.Time) error
+	ReleaseExpiredHolds(ctx context.Context) (int, error)
 
 	DebitWallet(ctx context.Context, userID string, amount float64, transactionType, referenceID, description string, metadata map[string]interface{}) (*models.WalletTransaction, error)
 	CreditWallet(ctx context.Context, userID string, amount float64, transactionType, referenceID, description string, metadata map[string]interface{}) (*models.WalletTransaction, error)
@@ -46,14 +56,41 @@ type Service interface {
 	UnrestrictDriverAccount(ctx context.Context, driverID string) error
 	RecordBalanceAudit(ctx context.Context, driverID, userID string, previousBalance, newBalance float64, action, reason string) error
 
+	ConfigureDriverCredit(cfg config.DriverCreditConfig)
+	SyncDriverCreditLimits(ctx context.Context) error
+	ListDriverCreditTiers(ctx context.Context) []dto.DriverCreditTierResponse
+	GetDriverCredit(ctx context.Context, driverID string) (*dto.DriverCreditResponse, error)
+	UpdateDriverCredit(ctx context.Context, adminID, driverID string, req dto.UpdateDriverCreditRequest) (*dto.DriverCreditResponse, error)
+	SetDriverCreditOverride(ctx context.Context, adminID, driverID string, req dto.DriverCreditOverrideRequest) (*dto.DriverCreditResponse, error)
+	ClearDriverCreditOverride(ctx context.Context, adminID, driverID string) (*dto.DriverCreditResponse, error)
+	ListDebtRepayments(ctx context.Context, driverID string, req dto.ListDebtRepaymentsRequest) ([]*dto.DebtRepaymentResponse, int64, error)
+
 	RecordCashCollection(ctx context.Context, userID string, req dto.CashCollectionRequest) (*dto.TransactionResponse, error)
 	RecordCashPayment(ctx context.Context, userID string, req dto.CashPaymentRequest) (*dto.TransactionResponse, error)
+
+	ReconcileLedger(ctx context.Context) (*dto.LedgerReconciliationResponse, error)
+	GetJournalEntry(ctx context.Context, entryID string) (*dto.JournalEntryResponse, error)
+
+	ConfigurePayoutRetry(cfg config.PayoutRetryConfig)
+	SetPayoutAccruer(accruer PayoutAccruer)
+	SetCurrencies(currencies currency.Service)
+	SetAuditLogger(auditLogger audit.Recorder)
+	CreditProviderPayout(ctx context.Context, credit *models.PayoutCredit) (queued bool, err error)
+	ProcessPayoutCredits(ctx context.Context) error
+	ListPayoutCredits(ctx context.Context, req dto.ListPayoutCreditsRequest) ([]*dto.PayoutCreditResponse, int64, error)
+	RetryPayoutCredit(ctx context.Context, adminID, creditID string) (*dto.PayoutCreditResponse, error)
+	ResolvePayoutCredit(ctx context.Context, adminID, creditID string, req dto.ResolvePayoutCreditRequest) (*dto.PayoutCreditResponse, error)
 }
 
 type service struct {
 	repo          Repository
 	db            *gorm.DB
 	eventProducer notificationsmodule.EventProducer
+	payoutRetry   config.PayoutRetryConfig
+	payoutAccruer PayoutAccruer
+	currencies    currency.Service
+	auditLogger   audit.Recorder
+	driverCredit  config.DriverCreditConfig
 }
 
 func NewService(repo Repository, db *gorm.DB) Service {
@@ -101,7 +138,7 @@ func (s *service) GetBalance(ctx context.Context, userID string) (*dto.WalletBal
 				WalletType:  models.WalletTypeRider,
 				Balance:     0,
 				HeldBalance: 0,
-				Currency:    "INR",
+				Currency:    s.defaultCurrency(),
 			}
 			if err := s.repo.CreateWallet(ctx, wallet); err != nil {
 				return nil, response.InternalServerError("Failed to create wallet", err)
@@ -121,7 +158,10 @@ func (s *service) GetBalance(ctx context.Context, userID string) (*dto.WalletBal
 	}, nil
 }
 
-func (s *service) AddFunds(ctx context.Context, userID string, req dto.AddFundsRequest) (*dto.TransactionResponse, error) {
+func (s *service) AddFunds(ctx context.Context, userID string, req dto.AddFundsRequest) (_ *dto.TransactionResponse, err error) {
+	ctx, span := tracing.StartSpan(ctx, "wallet.AddFunds", attribute.String("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+
 	if err := req.Validate(); err != nil {
 		return nil, response.BadRequest(err.Error())
 	}
@@ -137,39 +177,22 @@ func (s *service) AddFunds(ctx context.Context, userID string, req dto.AddFundsR
 	}
 
 	if !wallet.IsActive {
-		return nil, response.BadRequest("Wallet is not active")
+		return nil, response.CodedError(response.CodeWalletInactive, "")
 	}
 
-	var transaction *models.WalletTransaction
-	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		balanceBefore := wallet.Balance
-
-		wallet.Balance += req.Amount
-
-		if err := tx.Save(wallet).Error; err != nil {
-			return err
-		}
-
-		now := time.Now()
-		transaction = &models.WalletTransaction{
-			WalletID:      wallet.ID,
-			Type:          models.TransactionTypeCredit,
-			Amount:        req.Amount,
-			BalanceBefore: balanceBefore,
-			BalanceAfter:  wallet.Balance,
-			Status:        models.TransactionStatusCompleted,
-			ReferenceType: stringPtr("topup"),
-			Description:   stringPtr(req.Description),
-			ProcessedAt:   &now,
-		}
-
-		if err := tx.Create(transaction).Error; err != nil {
-			return err
-		}
+	conversion, err := s.toWalletCurrency(ctx, wallet, req.Amount, req.Currency)
+	if err != nil {
+		return nil, err
+	}
 
-		return nil
+	transaction, err := s.postMovement(ctx, walletMovement{
+		walletID:      wallet.ID,
+		txType:        models.TransactionTypeCredit,
+		amount:        conversion.Amount,
+		referenceType: "topup",
+		description:   req.Description,
+		conversion:    conversion,
 	})
-
 	if err != nil {
 		logger.Error("failed to add funds", "error", err, "userID", userID)
 		return nil, response.InternalServerError("Failed to add funds", err)
@@ -177,12 +200,15 @@ func (s *service) AddFunds(ctx context.Context, userID string, req dto.AddFundsR
 
 	s.invalidateWalletCache(ctx, userID)
 
-	logger.Info("funds added", "userID", userID, "amount", req.Amount, "txID", transaction.ID)
+	logger.Info("funds added", "userID", userID, "amount", transaction.Amount, "currency", transaction.Currency, "txID", transaction.ID)
 
 	return dto.ToTransactionResponse(transaction), nil
 }
 
-func (s *service) WithdrawFunds(ctx context.Context, userID string, req dto.WithdrawFundsRequest) (*dto.TransactionResponse, error) {
+func (s *service) WithdrawFunds(ctx context.Context, userID string, req dto.WithdrawFundsRequest) (_ *dto.TransactionResponse, err error) {
+	ctx, span := tracing.StartSpan(ctx, "wallet.WithdrawFunds", attribute.String("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+
 	if err := req.Validate(); err != nil {
 		return nil, response.BadRequest(err.Error())
 	}
@@ -198,43 +224,26 @@ func (s *service) WithdrawFunds(ctx context.Context, userID string, req dto.With
 	}
 
 	if !wallet.IsActive {
-		return nil, response.BadRequest("Wallet is not active")
+		return nil, response.CodedError(response.CodeWalletInactive, "")
 	}
 
-	if wallet.GetAvailableBalance() < req.Amount {
-		return nil, response.BadRequest("Insufficient balance")
+	conversion, err := s.toWalletCurrency(ctx, wallet, req.Amount, req.Currency)
+	if err != nil {
+		return nil, err
 	}
 
-	var transaction *models.WalletTransaction
-	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		balanceBefore := wallet.Balance
-
-		wallet.Balance -= req.Amount
-
-		if err := tx.Save(wallet).Error; err != nil {
-			return err
-		}
-
-		now := time.Now()
-		transaction = &models.WalletTransaction{
-			WalletID:      wallet.ID,
-			Type:          models.TransactionTypeDebit,
-			Amount:        req.Amount,
-			BalanceBefore: balanceBefore,
-			BalanceAfter:  wallet.Balance,
-			Status:        models.TransactionStatusCompleted,
-			ReferenceType: stringPtr("withdrawal"),
-			Description:   stringPtr(req.Description),
-			ProcessedAt:   &now,
-		}
-
-		if err := tx.Create(transaction).Error; err != nil {
-			return err
-		}
-
-		return nil
+	transaction, err := s.postMovement(ctx, walletMovement{
+		walletID:      wallet.ID,
+		txType:        models.TransactionTypeDebit,
+		amount:        conversion.Amount,
+		referenceType: "withdrawal",
+		description:   req.Description,
+		conversion:    conversion,
+		requireFunds:  true,
 	})
-
+	if errors.Is(err, errInsufficientFunds) {
+		return nil, response.CodedError(response.CodeWalletInsufficientFunds, "")
+	}
 	if err != nil {
 		logger.Error("failed to withdraw funds", "error", err, "userID", userID)
 		return nil, response.InternalServerError("Failed to withdraw funds", err)
@@ -242,18 +251,21 @@ func (s *service) WithdrawFunds(ctx context.Context, userID string, req dto.With
 
 	s.invalidateWalletCache(ctx, userID)
 
-	logger.Info("funds withdrawn", "userID", userID, "amount", req.Amount, "txID", transaction.ID)
+	logger.Info("funds withdrawn", "userID", userID, "amount", transaction.Amount, "currency", transaction.Currency, "txID", transaction.ID)
 
 	return dto.ToTransactionResponse(transaction), nil
 }
 
-func (s *service) TransferFunds(ctx context.Context, senderID string, req dto.TransferFundsRequest) (*dto.TransactionResponse, error) {
+func (s *service) TransferFunds(ctx context.Context, senderID string, req dto.TransferFundsRequest) (_ *dto.TransactionResponse, err error) {
+	ctx, span := tracing.StartSpan(ctx, "wallet.TransferFunds", attribute.String("user.id", senderID))
+	defer func() { tracing.End(span, err) }()
+
 	if err := req.Validate(); err != nil {
 		return nil, response.BadRequest(err.Error())
 	}
 
 	if senderID == req.RecipientID {
-		return nil, response.BadRequest("Cannot transfer to yourself")
+		return nil, response.CodedError(response.CodeWalletSelfTransfer, "")
 	}
 
 	senderWalletResp, err := s.GetWallet(ctx, senderID)
@@ -276,26 +288,59 @@ func (s *service) TransferFunds(ctx context.Context, senderID string, req dto.Tr
 		return nil, response.NotFoundError("Recipient wallet")
 	}
 
-	if senderWallet.GetAvailableBalance() < req.Amount {
-		return nil, response.BadRequest("Insufficient balance")
+	// The sender is debited in their currency and the recipient credited in
+	// theirs, each converted from whatever the transfer was given in.
+	sent, err := s.toWalletCurrency(ctx, senderWallet, req.Amount, req.Currency)
+	if err != nil {
+		return nil, err
+	}
+	received, err := s.toWalletCurrency(ctx, recipientWallet, req.Amount, sent.OriginalCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	if senderWallet.GetAvailableBalance() < sent.Amount {
+		return nil, response.CodedError(response.CodeWalletInsufficientFunds, "")
 	}
 
 	if !senderWallet.IsActive || !recipientWallet.IsActive {
-		return nil, response.BadRequest("One or both wallets are not active")
+		return nil, response.CodedError(response.CodeWalletInactive, "One or both wallets are not active")
 	}
 
 	var senderTx *models.WalletTransaction
 	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Lock in a fixed order so opposite transfers cannot deadlock.
+		first, second := senderWallet.ID, recipientWallet.ID
+		if second < first {
+			first, second = second, first
+		}
+		locked := make(map[string]*models.Wallet, 2)
+		for _, id := range []string{first, second} {
+			wallet, err := lockWallet(tx, id)
+			if err != nil {
+				return err
+			}
+			locked[id] = wallet
+		}
+		sender, recipient := locked[senderWallet.ID], locked[recipientWallet.ID]
+		if sender.GetAvailableBalance() < sent.Amount {
+			return errInsufficientFunds
+		}
 
-		senderBalanceBefore := senderWallet.Balance
-		senderWallet.Balance -= req.Amount
-		if err := tx.Save(senderWallet).Error; err != nil {
-			return err
+		journal := NewJournal(string(models.TransactionTypeTransfer), "transfer", req.RecipientID, req.Description).
+			DebitWallet(sender, sent.Amount)
+		if sender.Currency == recipient.Currency {
+			journal.CreditWallet(recipient, received.Amount)
+		} else {
+			journal.CreditPlatform(models.LedgerAccountExchange, sender.Currency, sent.Amount).
+				DebitPlatform(models.LedgerAccountExchange, recipient.Currency, received.Amount).
+				CreditWallet(recipient, received.Amount)
 		}
 
-		recipientBalanceBefore := recipientWallet.Balance
-		recipientWallet.Balance += req.Amount
-		if err := tx.Save(recipientWallet).Error; err != nil {
+		senderBalanceBefore := sender.Balance
+		recipientBalanceBefore := recipient.Balance
+		entry, err := journal.Post(tx)
+		if err != nil {
 			return err
 		}
 
@@ -304,9 +349,9 @@ func (s *service) TransferFunds(ctx context.Context, senderID string, req dto.Tr
 		senderTx = &models.WalletTransaction{
 			WalletID:      senderWallet.ID,
 			Type:          models.TransactionTypeTransfer,
-			Amount:        req.Amount,
+			Amount:        sent.Amount,
 			BalanceBefore: senderBalanceBefore,
-			BalanceAfter:  senderWallet.Balance,
+			BalanceAfter:  sender.Balance,
 			Status:        models.TransactionStatusCompleted,
 			ReferenceType: stringPtr("transfer_out"),
 			ReferenceID:   &req.RecipientID,
@@ -314,8 +359,10 @@ func (s *service) TransferFunds(ctx context.Context, senderID string, req dto.Tr
 			Metadata: map[string]interface{}{
 				"recipientId": req.RecipientID,
 			},
-			ProcessedAt: &now,
+			ProcessedAt:    &now,
+			JournalEntryID: &entry.ID,
 		}
+		recordConversion(senderTx, sent)
 		if err := tx.Create(senderTx).Error; err != nil {
 			return err
 		}
@@ -323,9 +370,9 @@ func (s *service) TransferFunds(ctx context.Context, senderID string, req dto.Tr
 		recipientTx := &models.WalletTransaction{
 			WalletID:      recipientWallet.ID,
 			Type:          models.TransactionTypeTransfer,
-			Amount:        req.Amount,
+			Amount:        received.Amount,
 			BalanceBefore: recipientBalanceBefore,
-			BalanceAfter:  recipientWallet.Balance,
+			BalanceAfter:  recipient.Balance,
 			Status:        models.TransactionStatusCompleted,
 			ReferenceType: stringPtr("transfer_in"),
 			ReferenceID:   &senderID,
@@ -333,8 +380,10 @@ func (s *service) TransferFunds(ctx context.Context, senderID string, req dto.Tr
 			Metadata: map[string]interface{}{
 				"senderId": senderID,
 			},
-			ProcessedAt: &now,
+			ProcessedAt:    &now,
+			JournalEntryID: &entry.ID,
 		}
+		recordConversion(recipientTx, received)
 		if err := tx.Create(recipientTx).Error; err != nil {
 			return err
 		}
@@ -342,6 +391,9 @@ func (s *service) TransferFunds(ctx context.Context, senderID string, req dto.Tr
 		return nil
 	})
 
+	if errors.Is(err, errInsufficientFunds) {
+		return nil, response.CodedError(response.CodeWalletInsufficientFunds, "")
+	}
 	if err != nil {
 		logger.Error("failed to transfer funds", "error", err, "senderID", senderID)
 		return nil, response.InternalServerError("Failed to transfer funds", err)
@@ -350,12 +402,15 @@ func (s *service) TransferFunds(ctx context.Context, senderID string, req dto.Tr
 	s.invalidateWalletCache(ctx, senderID)
 	s.invalidateWalletCache(ctx, req.RecipientID)
 
-	logger.Info("funds transferred", "senderID", senderID, "recipientID", req.RecipientID, "amount", req.Amount)
+	logger.Info("funds transferred", "senderID", senderID, "recipientID", req.RecipientID, "amount", sent.Amount, "currency", sent.Currency)
 
 	return dto.ToTransactionResponse(senderTx), nil
 }
 
-func (s *service) HoldFunds(ctx context.Context, userID string, req dto.HoldFundsRequest) (*dto.HoldResponse, error) {
+func (s *service) HoldFunds(ctx context.Context, userID string, req dto.HoldFundsRequest) (_ *dto.HoldResponse, err error) {
+	ctx, span := tracing.StartSpan(ctx, "wallet.HoldFunds", attribute.String("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+
 	if err := req.Validate(); err != nil {
 		return nil, response.BadRequest(err.Error())
 	}
@@ -367,7 +422,7 @@ func (s *service) HoldFunds(ctx context.Context, userID string, req dto.HoldFund
 				UserID:     userID,
 				WalletType: models.WalletTypeRider,
 				Balance:    0,
-				Currency:   "INR",
+				Currency:   s.defaultCurrency(),
 			}
 			if err := s.repo.CreateWallet(ctx, wallet); err != nil {
 				return nil, response.InternalServerError("Failed to create wallet", err)
@@ -377,33 +432,62 @@ func (s *service) HoldFunds(ctx context.Context, userID string, req dto.HoldFund
 		}
 	}
 
+	conversion, err := s.toWalletCurrency(ctx, wallet, req.Amount, req.Currency)
+	if err != nil {
+		return nil, err
+	}
+
 	hold := &models.WalletHold{
 		WalletID:      wallet.ID,
-		Amount:        req.Amount,
+		Amount:        conversion.Amount,
+		Currency:      conversion.Currency,
 		ReferenceType: req.ReferenceType,
 		ReferenceID:   req.ReferenceID,
 		Status:        "active",
 		ExpiresAt:     time.Now().Add(time.Duration(req.HoldDuration) * time.Second),
 	}
 
-	if err := s.repo.CreateHold(ctx, hold); err != nil {
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		locked, err := lockWallet(tx, wallet.ID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(hold).Error; err != nil {
+			return err
+		}
+		_, err = NewJournal(string(models.TransactionTypeHold), hold.ReferenceType, hold.ReferenceID, "").
+			Hold(locked, hold.Amount).
+			Post(tx)
+		return err
+	})
+	if err != nil {
 		return nil, response.InternalServerError("Failed to create hold", err)
 	}
+	s.invalidateWalletCache(ctx, userID)
 
 	logger.Info("hold created for cash ride",
 		"userID", userID,
-		"amount", req.Amount,
+		"amount", hold.Amount,
+		"currency", hold.Currency,
 		"holdID", hold.ID,
 		"reference", req.ReferenceID)
 
+	s.notifyHold(userID, websocket.TypeWalletHoldCreated, hold, map[string]interface{}{
+		"message": holdMessage("reserved", hold.Amount, hold.ReferenceType),
+	})
+
 	return &dto.HoldResponse{
 		ID:        hold.ID,
-		Amount:    req.Amount,
+		Amount:    hold.Amount,
+		Currency:  hold.Currency,
 		ExpiresAt: hold.ExpiresAt,
 	}, nil
 }
 
-func (s *service) ReleaseHold(ctx context.Context, userID string, req dto.ReleaseHoldRequest) error {
+func (s *service) ReleaseHold(ctx context.Context, userID string, req dto.ReleaseHoldRequest) (err error) {
+	ctx, span := tracing.StartSpan(ctx, "wallet.ReleaseHold", attribute.String("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+
 	hold, err := s.repo.FindHoldByID(ctx, req.HoldID)
 	if err != nil {
 		return response.NotFoundError("Hold")
@@ -415,23 +499,38 @@ func (s *service) ReleaseHold(ctx context.Context, userID string, req dto.Releas
 	}
 
 	if hold.Status != "active" {
-		return response.BadRequest("Hold is no longer active")
+		return response.CodedError(response.CodeWalletHoldInactive, "")
 	}
 
-	hold.Status = "released"
-	now := time.Now()
-	hold.ReleasedAt = &now
-
-	if err := s.repo.UpdateHold(ctx, hold); err != nil {
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		locked, err := lockWallet(tx, wallet.ID)
+		if err != nil {
+			return err
+		}
+		_, err = settleHold(tx, locked, hold, 0, "")
+		return err
+	})
+	if errors.Is(err, errHoldInactive) {
+		return response.CodedError(response.CodeWalletHoldInactive, "")
+	}
+	if err != nil {
 		return response.InternalServerError("Failed to release hold", err)
 	}
+	s.invalidateWalletCache(ctx, userID)
 
 	logger.Info("hold released", "holdID", hold.ID, "amount", hold.Amount, "userID", userID)
 
+	s.notifyHold(userID, websocket.TypeWalletHoldReleased, hold, map[string]interface{}{
+		"message": holdMessage("released", hold.Amount, hold.ReferenceType),
+	})
+
 	return nil
 }
 
-func (s *service) CaptureHold(ctx context.Context, userID string, req dto.CaptureHoldRequest) (*dto.TransactionResponse, error) {
+func (s *service) CaptureHold(ctx context.Context, userID string, req dto.CaptureHoldRequest) (_ *dto.TransactionResponse, err error) {
+	ctx, span := tracing.StartSpan(ctx, "wallet.CaptureHold", attribute.String("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+
 	hold, err := s.repo.FindHoldByID(ctx, req.HoldID)
 	if err != nil {
 		return nil, response.NotFoundError("Hold")
@@ -443,38 +542,62 @@ func (s *service) CaptureHold(ctx context.Context, userID string, req dto.Captur
 	}
 
 	if hold.Status != "active" {
-		return nil, response.BadRequest("Hold is no longer active")
+		return nil, response.CodedError(response.CodeWalletHoldInactive, "")
 	}
 
+	heldAmount := hold.Amount
 	captureAmount := hold.Amount
-	if req.Amount != nil && *req.Amount <= hold.Amount {
-		captureAmount = *req.Amount
+	var conversion *currency.Conversion
+	if req.Amount != nil {
+		conversion, err = s.toWalletCurrency(ctx, wallet, *req.Amount, req.Currency)
+		if err != nil {
+			return nil, err
+		}
+		if conversion.Amount <= hold.Amount {
+			captureAmount = conversion.Amount
+		}
 	}
 
-	txn := &models.WalletTransaction{
-		WalletID:      wallet.ID,
-		Amount:        captureAmount,
-		Type:          "debit",
-		Status:        "completed",
-		ReferenceType: &hold.ReferenceType,
-		ReferenceID:   &hold.ReferenceID,
-		Description:   &req.Description,
-		PaymentMethod: "cash",
-		BalanceAfter:  wallet.Balance,
-	}
+	var txn *models.WalletTransaction
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		locked, err := lockWallet(tx, wallet.ID)
+		if err != nil {
+			return err
+		}
+		balanceBefore := locked.Balance
+		entry, err := settleHold(tx, locked, hold, captureAmount, req.Description)
+		if err != nil {
+			return err
+		}
 
-	if err := s.repo.CreateTransaction(ctx, txn); err != nil {
-		return nil, response.InternalServerError("Failed to create transaction", err)
+		now := time.Now()
+		txn = &models.WalletTransaction{
+			WalletID:       wallet.ID,
+			Amount:         captureAmount,
+			Currency:       wallet.Currency,
+			Type:           models.TransactionTypeDebit,
+			Status:         models.TransactionStatusCompleted,
+			ReferenceType:  &hold.ReferenceType,
+			ReferenceID:    &hold.ReferenceID,
+			Description:    &req.Description,
+			PaymentMethod:  "cash",
+			BalanceBefore:  balanceBefore,
+			BalanceAfter:   locked.Balance,
+			ProcessedAt:    &now,
+			JournalEntryID: &entry.ID,
+		}
+		if conversion != nil && captureAmount == conversion.Amount {
+			recordConversion(txn, conversion)
+		}
+		return tx.Create(txn).Error
+	})
+	if errors.Is(err, errHoldInactive) {
+		return nil, response.CodedError(response.CodeWalletHoldInactive, "")
 	}
-
-	hold.Status = "captured"
-	now := time.Now()
-	hold.CreatedAt = now
-	hold.Amount = captureAmount
-
-	if err := s.repo.UpdateHold(ctx, hold); err != nil {
-		logger.Error("failed to update hold status", "error", err, "holdID", hold.ID)
+	if err != nil {
+		return nil, response.InternalServerError("Failed to capture hold", err)
 	}
+	s.invalidateWalletCache(ctx, userID)
 
 	logger.Info("hold captured (cash payment)",
 		"holdID", hold.ID,
@@ -482,6 +605,14 @@ func (s *service) CaptureHold(ctx context.Context, userID string, req dto.Captur
 		"userID", userID,
 		"transactionID", txn.ID)
 
+	s.notifyHold(userID, websocket.TypeWalletHoldCaptured, hold, map[string]interface{}{
+		"heldAmount":     heldAmount,
+		"capturedAmount": captureAmount,
+		"releasedAmount": heldAmount - captureAmount,
+		"transactionId":  txn.ID,
+		"message":        holdMessage("charged", captureAmount, hold.ReferenceType),
+	})
+
 	return dto.ToTransactionResponse(txn), nil
 }
 
@@ -499,35 +630,6 @@ func (s *service) GetHoldsByReference(ctx context.Context, refType, refID string
 	return result, nil
 }
 
-func (s *service) ListTransactions(ctx context.Context, userID string, req dto.ListTransactionsRequest) ([]*dto.TransactionResponse, int64, error) {
-	req.SetDefaults()
-
-	walletResp, err := s.GetWallet(ctx, userID)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	filters := make(map[string]interface{})
-	if req.Type != "" {
-		filters["type"] = req.Type
-	}
-	if req.Status != "" {
-		filters["status"] = req.Status
-	}
-
-	transactions, total, err := s.repo.ListTransactions(ctx, walletResp.ID, filters, req.Page, req.Limit)
-	if err != nil {
-		return nil, 0, response.InternalServerError("Failed to fetch transactions", err)
-	}
-
-	result := make([]*dto.TransactionResponse, len(transactions))
-	for i, tx := range transactions {
-		result[i] = dto.ToTransactionResponse(tx)
-	}
-
-	return result, total, nil
-}
-
 func (s *service) GetTransaction(ctx context.Context, userID string, transactionID string) (*dto.TransactionResponse, error) {
 	txn, err := s.repo.FindTransactionByID(ctx, transactionID)
 	if err != nil {
@@ -544,7 +646,10 @@ func (s *service) GetTransaction(ctx context.Context, userID string, transaction
 	return dto.ToTransactionResponse(txn), nil
 }
 
-func (s *service) DebitWallet(ctx context.Context, userID string, amount float64, refType, refID, description string, metadata map[string]interface{}) (*models.WalletTransaction, error) {
+func (s *service) DebitWallet(ctx context.Context, userID string, amount float64, refType, refID, description string, metadata map[string]interface{}) (_ *models.WalletTransaction, err error) {
+	ctx, span := tracing.StartSpan(ctx, "wallet.DebitWallet", attribute.String("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+
 	walletResp, err := s.GetWallet(ctx, userID)
 	if err != nil {
 		return nil, err
@@ -555,37 +660,19 @@ func (s *service) DebitWallet(ctx context.Context, userID string, amount float64
 		return nil, err
 	}
 
-	if wallet.GetAvailableBalance() < amount {
-		return nil, response.BadRequest("Insufficient balance")
-	}
-
-	var transaction *models.WalletTransaction
-	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		balanceBefore := wallet.Balance
-		wallet.Balance -= amount
-
-		if err := tx.Save(wallet).Error; err != nil {
-			return err
-		}
-
-		now := time.Now()
-		transaction = &models.WalletTransaction{
-			WalletID:      wallet.ID,
-			Type:          models.TransactionTypeDebit,
-			Amount:        amount,
-			BalanceBefore: balanceBefore,
-			BalanceAfter:  wallet.Balance,
-			Status:        models.TransactionStatusCompleted,
-			ReferenceType: &refType,
-			ReferenceID:   &refID,
-			Description:   &description,
-			Metadata:      metadata,
-			ProcessedAt:   &now,
-		}
-
-		return tx.Create(transaction).Error
+	transaction, err := s.postMovement(ctx, walletMovement{
+		walletID:      wallet.ID,
+		txType:        models.TransactionTypeDebit,
+		amount:        amount,
+		referenceType: refType,
+		referenceID:   &refID,
+		description:   description,
+		metadata:      metadata,
+		requireFunds:  true,
 	})
-
+	if errors.Is(err, errInsufficientFunds) {
+		return nil, response.CodedError(response.CodeWalletInsufficientFunds, "")
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -594,14 +681,17 @@ func (s *service) DebitWallet(ctx context.Context, userID string, amount float64
 	return transaction, nil
 }
 
-func (s *service) CreditWallet(ctx context.Context, userID string, amount float64, transactionType, referenceID, description string, metadata map[string]interface{}) (*models.WalletTransaction, error) {
+func (s *service) CreditWallet(ctx context.Context, userID string, amount float64, transactionType, referenceID, description string, metadata map[string]interface{}) (_ *models.WalletTransaction, err error) {
+	ctx, span := tracing.StartSpan(ctx, "wallet.CreditWallet", attribute.String("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+
 	wallet, err := s.repo.FindWalletByUserID(ctx, userID, models.WalletTypeRider)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			wallet = &models.Wallet{
 				UserID:     userID,
 				Balance:    0,
-				Currency:   "INR",
+				Currency:   s.defaultCurrency(),
 				WalletType: models.WalletTypeRider, // Set wallet type to rider
 			}
 			if err := s.repo.CreateWallet(ctx, wallet); err != nil {
@@ -612,25 +702,19 @@ func (s *service) CreditWallet(ctx context.Context, userID string, amount float6
 		}
 	}
 
-	txn := &models.WalletTransaction{
-		WalletID:      wallet.ID,
-		Amount:        amount,
-		Type:          "credit",
-		Status:        "completed",
-		ReferenceType: &transactionType,
-		ReferenceID:   &referenceID,
-		Description:   &description,
-		BalanceAfter:  wallet.Balance + amount,
-	}
-
-	if err := s.repo.CreateTransaction(ctx, txn); err != nil {
-		return nil, response.InternalServerError("Failed to create transaction", err)
-	}
-
-	wallet.Balance += amount
-	if err := s.repo.UpdateWallet(ctx, wallet); err != nil {
-		return nil, response.InternalServerError("Failed to update wallet", err)
+	txn, err := s.postMovement(ctx, walletMovement{
+		walletID:      wallet.ID,
+		txType:        models.TransactionTypeCredit,
+		amount:        amount,
+		referenceType: transactionType,
+		referenceID:   &referenceID,
+		description:   description,
+		metadata:      metadata,
+	})
+	if err != nil {
+		return nil, response.InternalServerError("Failed to credit wallet", err)
 	}
+	s.invalidateWalletCache(ctx, userID)
 
 	logger.Info("wallet credited", "userID", userID, "amount", amount, "transactionID", txn.ID, "type", transactionType)
 
@@ -640,14 +724,17 @@ func (s *service) CreditWallet(ctx context.Context, userID string, amount float6
 	return txn, nil
 }
 
-func (s *service) CreditDriverWallet(ctx context.Context, userID string, amount float64, transactionType, referenceID, description string, metadata map[string]interface{}) (*models.WalletTransaction, error) {
+func (s *service) CreditDriverWallet(ctx context.Context, userID string, amount float64, transactionType, referenceID, description string, metadata map[string]interface{}) (_ *models.WalletTransaction, err error) {
+	ctx, span := tracing.StartSpan(ctx, "wallet.CreditDriverWallet", attribute.String("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+
 	wallet, err := s.repo.FindWalletByUserID(ctx, userID, models.WalletTypeDriver)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			wallet = &models.Wallet{
 				UserID:     userID,
 				Balance:    0,
-				Currency:   "INR",
+				Currency:   s.defaultCurrency(),
 				WalletType: models.WalletTypeDriver,
 			}
 			if err := s.repo.CreateWallet(ctx, wallet); err != nil {
@@ -658,24 +745,27 @@ func (s *service) CreditDriverWallet(ctx context.Context, userID string, amount
 		}
 	}
 
-	txn := &models.WalletTransaction{
-		WalletID:      wallet.ID,
-		Amount:        amount,
-		Type:          "credit",
-		Status:        "completed",
-		ReferenceType: &transactionType,
-		ReferenceID:   &referenceID,
-		Description:   &description,
-		BalanceAfter:  wallet.Balance + amount,
-	}
-
-	if err := s.repo.CreateTransaction(ctx, txn); err != nil {
-		return nil, response.InternalServerError("Failed to create transaction", err)
+	txn, err := s.postMovement(ctx, walletMovement{
+		walletID:      wallet.ID,
+		txType:        models.TransactionTypeCredit,
+		amount:        amount,
+		referenceType: transactionType,
+		referenceID:   &referenceID,
+		description:   description,
+		metadata:      metadata,
+	})
+	if err != nil {
+		return nil, response.InternalServerError("Failed to credit wallet", err)
 	}
+	s.invalidateWalletCache(ctx, userID)
 
-	wallet.Balance += amount
-	if err := s.repo.UpdateWallet(ctx, wallet); err != nil {
-		return nil, response.InternalServerError("Failed to update wallet", err)
+	// A credit that pays down debt may be what lifts a restriction.
+	if txn.BalanceBefore < 0 {
+		go func() {
+			if _, _, err := s.CheckAndEnforceAccountRestriction(context.Background(), userID); err != nil {
+				logger.Error("failed to check account restriction", "error", err, "userID", userID)
+			}
+		}()
 	}
 
 	logger.Info("driver wallet credited", "userID", userID, "amount", amount, "transactionID", txn.ID, "type", transactionType)
@@ -683,14 +773,17 @@ func (s *service) CreditDriverWallet(ctx context.Context, userID string, amount
 	return txn, nil
 }
 
-func (s *service) CreditServiceProviderWallet(ctx context.Context, userID string, amount float64, transactionType, referenceID, description string, metadata map[string]interface{}) (*models.WalletTransaction, error) {
+func (s *service) CreditServiceProviderWallet(ctx context.Context, userID string, amount float64, transactionType, referenceID, description string, metadata map[string]interface{}) (_ *models.WalletTransaction, err error) {
+	ctx, span := tracing.StartSpan(ctx, "wallet.CreditServiceProviderWallet", attribute.String("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+
 	wallet, err := s.repo.FindWalletByUserID(ctx, userID, models.WalletTypeServiceProvider)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			wallet = &models.Wallet{
 				UserID:     userID,
 				Balance:    0,
-				Currency:   "INR",
+				Currency:   s.defaultCurrency(),
 				WalletType: models.WalletTypeServiceProvider,
 			}
 			if err := s.repo.CreateWallet(ctx, wallet); err != nil {
@@ -701,39 +794,36 @@ func (s *service) CreditServiceProviderWallet(ctx context.Context, userID string
 		}
 	}
 
-	txn := &models.WalletTransaction{
-		WalletID:      wallet.ID,
-		Amount:        amount,
-		Type:          "credit",
-		Status:        "completed",
-		ReferenceType: &transactionType,
-		ReferenceID:   &referenceID,
-		Description:   &description,
-		BalanceAfter:  wallet.Balance + amount,
-	}
-
-	if err := s.repo.CreateTransaction(ctx, txn); err != nil {
-		return nil, response.InternalServerError("Failed to create transaction", err)
-	}
-
-	wallet.Balance += amount
-	if err := s.repo.UpdateWallet(ctx, wallet); err != nil {
-		return nil, response.InternalServerError("Failed to update wallet", err)
+	txn, err := s.postMovement(ctx, walletMovement{
+		walletID:      wallet.ID,
+		txType:        models.TransactionTypeCredit,
+		amount:        amount,
+		referenceType: transactionType,
+		referenceID:   &referenceID,
+		description:   description,
+		metadata:      metadata,
+	})
+	if err != nil {
+		return nil, response.InternalServerError("Failed to credit wallet", err)
 	}
+	s.invalidateWalletCache(ctx, userID)
 
 	logger.Info("service provider wallet credited", "userID", userID, "amount", amount, "transactionID", txn.ID, "type", transactionType)
 
 	return txn, nil
 }
 
-func (s *service) DebitDriverWallet(ctx context.Context, driverID string, amount float64, reason, referenceID, description string, metadata map[string]interface{}) (*models.WalletTransaction, error) {
+func (s *service) DebitDriverWallet(ctx context.Context, driverID string, amount float64, reason, referenceID, description string, metadata map[string]interface{}) (_ *models.WalletTransaction, err error) {
+	ctx, span := tracing.StartSpan(ctx, "wallet.DebitDriverWallet", attribute.String("driver.id", driverID))
+	defer func() { tracing.End(span, err) }()
+
 	wallet, err := s.repo.FindWalletByUserID(ctx, driverID, models.WalletTypeDriver)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			wallet = &models.Wallet{
 				UserID:     driverID,
 				Balance:    0,
-				Currency:   "INR",
+				Currency:   s.defaultCurrency(),
 				WalletType: models.WalletTypeDriver,
 			}
 			if err := s.repo.CreateWallet(ctx, wallet); err != nil {
@@ -753,33 +843,16 @@ func (s *service) DebitDriverWallet(ctx context.Context, driverID string, amount
 			"reason", reason)
 	}
 
-	var transaction *models.WalletTransaction
-	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		balanceBefore := wallet.Balance
-		wallet.Balance -= amount
-
-		if err := tx.Save(wallet).Error; err != nil {
-			return err
-		}
-
-		now := time.Now()
-		transaction = &models.WalletTransaction{
-			WalletID:      wallet.ID,
-			Type:          models.TransactionTypeDebit,
-			Amount:        amount,
-			BalanceBefore: balanceBefore,
-			BalanceAfter:  wallet.Balance,
-			Status:        models.TransactionStatusCompleted,
-			ReferenceType: &reason,
-			ReferenceID:   &referenceID,
-			Description:   &description,
-			Metadata:      metadata,
-			ProcessedAt:   &now,
-		}
-
-		return tx.Create(transaction).Error
+	// Drivers may go negative; the restriction check below deals with it.
+	transaction, err := s.postMovement(ctx, walletMovement{
+		walletID:      wallet.ID,
+		txType:        models.TransactionTypeDebit,
+		amount:        amount,
+		referenceType: reason,
+		referenceID:   &referenceID,
+		description:   description,
+		metadata:      metadata,
 	})
-
 	if err != nil {
 		return nil, response.InternalServerError("Failed to debit driver wallet", err)
 	}
@@ -790,19 +863,23 @@ func (s *service) DebitDriverWallet(ctx context.Context, driverID string, amount
 		"amount", amount,
 		"reason", reason,
 		"referenceID", referenceID,
-		"newBalance", wallet.Balance)
+		"newBalance", transaction.BalanceAfter)
+	// Callers pass either the driver profile ID or the driver's user ID (the
+	// wallet is keyed by the latter); audit and restriction need the profile.
 	var driver models.DriverProfile
-	if err := s.db.WithContext(ctx).Where("id = ?", driverID).First(&driver).Error; err == nil {
-		_ = s.RecordBalanceAudit(ctx, driverID, driver.UserID, wallet.Balance+amount, wallet.Balance, reason, description)
+	if err := s.db.WithContext(ctx).Where("id = ? OR user_id = ?", driverID, driverID).First(&driver).Error; err != nil {
+		logger.Warn("driver profile not found for debit audit", "error", err, "driverID", driverID)
+		return transaction, nil
 	}
+	_ = s.RecordBalanceAudit(ctx, driver.ID, driver.UserID, transaction.BalanceBefore, transaction.BalanceAfter, reason, description)
 
 	go func() {
 		bgCtx := context.Background()
-		if isRestricted, restrictReason, err := s.CheckAndEnforceAccountRestriction(bgCtx, driverID); err != nil {
-			logger.Error("failed to check account restriction", "error", err, "driverID", driverID)
+		if isRestricted, restrictReason, err := s.CheckAndEnforceAccountRestriction(bgCtx, driver.ID); err != nil {
+			logger.Error("failed to check account restriction", "error", err, "driverID", driver.ID)
 		} else if isRestricted {
 			logger.Warn("driver account restricted due to negative balance",
-				"driverID", driverID,
+				"driverID", driver.ID,
 				"reason", restrictReason)
 		}
 	}()
@@ -877,40 +954,13 @@ func (s *service) ValidateDriverWalletBalance(ctx context.Context, driverID stri
 			"driverID", driverID,
 			"required", amount,
 			"available", availableBalance)
-		return availableBalance, response.BadRequest(
+		return availableBalance, response.CodedError(response.CodeWalletInsufficientFunds,
 			fmt.Sprintf("Insufficient wallet balance. Required: $%.2f, Available: $%.2f", amount, availableBalance))
 	}
 
 	return availableBalance, nil
 }
 
-func (s *service) GetTransactionHistory(ctx context.Context, userID string, req dto.TransactionHistoryRequest) ([]*dto.TransactionResponse, int64, error) {
-	req.SetDefaults()
-
-	filters := make(map[string]interface{})
-	if req.Type != "" {
-		filters["type"] = req.Type
-	}
-	if req.StartDate != "" {
-		filters["startDate"] = req.StartDate
-	}
-	if req.EndDate != "" {
-		filters["endDate"] = req.EndDate
-	}
-
-	transactions, total, err := s.repo.ListTransactions(ctx, userID, filters, req.Page, req.Limit)
-	if err != nil {
-		return nil, 0, response.InternalServerError("Failed to fetch transactions", err)
-	}
-
-	result := make([]*dto.TransactionResponse, len(transactions))
-	for i, txn := range transactions {
-		result[i] = dto.ToTransactionResponse(txn)
-	}
-
-	return result, total, nil
-}
-
 func (s *service) RecordCashCollection(ctx context.Context, userID string, req dto.CashCollectionRequest) (*dto.TransactionResponse, error) {
 	if err := req.Validate(); err != nil {
 		return nil, response.BadRequest(err.Error())
@@ -923,7 +973,7 @@ func (s *service) RecordCashCollection(ctx context.Context, userID string, req d
 				UserID:     userID,
 				WalletType: models.WalletTypeDriver,
 				Balance:    0,
-				Currency:   "INR",
+				Currency:   s.defaultCurrency(),
 			}
 			if err := s.repo.CreateWallet(ctx, wallet); err != nil {
 				return nil, response.InternalServerError("Failed to create wallet", err)
@@ -933,26 +983,19 @@ func (s *service) RecordCashCollection(ctx context.Context, userID string, req d
 		}
 	}
 
-	txn := &models.WalletTransaction{
-		WalletID:      wallet.ID,
-		Amount:        req.Amount,
-		Type:          "credit",
-		Status:        "completed",
-		ReferenceType: stringPtr("cash_collection"),
-		ReferenceID:   &req.RideID,
-		Description:   stringPtr(fmt.Sprintf("Cash collected from ride %s", req.RideID)),
-		PaymentMethod: "cash",
-		BalanceAfter:  wallet.Balance + req.Amount,
-	}
-
-	if err := s.repo.CreateTransaction(ctx, txn); err != nil {
+	txn, err := s.postMovement(ctx, walletMovement{
+		walletID:      wallet.ID,
+		txType:        models.TransactionTypeCredit,
+		amount:        req.Amount,
+		referenceType: "cash_collection",
+		referenceID:   &req.RideID,
+		description:   fmt.Sprintf("Cash collected from ride %s", req.RideID),
+		paymentMethod: "cash",
+	})
+	if err != nil {
 		return nil, response.InternalServerError("Failed to record cash collection", err)
 	}
-
-	wallet.Balance += req.Amount
-	if err := s.repo.UpdateWallet(ctx, wallet); err != nil {
-		logger.Error("failed to update wallet balance", "error", err, "walletID", wallet.ID)
-	}
+	s.invalidateWalletCache(ctx, userID)
 
 	logger.Info("cash collection recorded",
 		"driverID", userID,
@@ -973,30 +1016,23 @@ func (s *service) RecordCashPayment(ctx context.Context, userID string, req dto.
 		return nil, response.NotFoundError("Wallet")
 	}
 
-	if req.Amount > wallet.Balance {
-		return nil, response.BadRequest(fmt.Sprintf("Insufficient balance. Current: $%.2f", wallet.Balance))
-	}
-
-	txn := &models.WalletTransaction{
-		WalletID:      wallet.ID,
-		Amount:        req.Amount,
-		Type:          "debit",
-		Status:        "completed",
-		ReferenceType: stringPtr("cash_settlement"),
-		ReferenceID:   &req.SettlementID,
-		Description:   stringPtr(fmt.Sprintf("Cash settlement to company - %s", req.SettlementID)),
-		PaymentMethod: "cash",
-		BalanceAfter:  wallet.Balance - req.Amount,
+	txn, err := s.postMovement(ctx, walletMovement{
+		walletID:      wallet.ID,
+		txType:        models.TransactionTypeDebit,
+		amount:        req.Amount,
+		referenceType: "cash_settlement",
+		referenceID:   &req.SettlementID,
+		description:   fmt.Sprintf("Cash settlement to company - %s", req.SettlementID),
+		paymentMethod: "cash",
+		requireFunds:  true,
+	})
+	if errors.Is(err, errInsufficientFunds) {
+		return nil, response.CodedError(response.CodeWalletInsufficientFunds, fmt.Sprintf("Insufficient balance. Current: $%.2f", wallet.GetAvailableBalance()))
 	}
-
-	if err := s.repo.CreateTransaction(ctx, txn); err != nil {
+	if err != nil {
 		return nil, response.InternalServerError("Failed to record cash payment", err)
 	}
-
-	wallet.Balance -= req.Amount
-	if err := s.repo.UpdateWallet(ctx, wallet); err != nil {
-		logger.Error("failed to update wallet balance", "error", err, "walletID", wallet.ID)
-	}
+	s.invalidateWalletCache(ctx, userID)
 
 	logger.Info("cash settlement recorded",
 		"driverID", userID,
@@ -1007,15 +1043,37 @@ func (s *service) RecordCashPayment(ctx context.Context, userID string, req dto.
 	return dto.ToTransactionResponse(txn), nil
 }
 
+// CheckAndEnforceAccountRestriction restricts a driver who owes more than
+// their credit limit and lifts the restriction once the balance is back to
+// zero. driverID may be the profile ID or the user ID. An active admin
+// override keeps the driver unrestricted whatever they owe.
 func (s *service) CheckAndEnforceAccountRestriction(ctx context.Context, driverID string) (bool, string, error) {
 	var driver models.DriverProfile
-	if err := s.db.WithContext(ctx).Where("id = ?", driverID).First(&driver).Error; err != nil {
+	if err := s.db.WithContext(ctx).Where("id = ? OR user_id = ?", driverID, driverID).First(&driver).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return false, "", response.NotFoundError("Driver")
 		}
 		return false, "", response.InternalServerError("Failed to fetch driver profile", err)
 	}
 
+	if threshold := -s.creditLimit(&driver); threshold != driver.MinBalanceThreshold {
+		if err := s.repo.UpdateDriverProfile(ctx, driver.ID, map[string]interface{}{
+			"min_balance_threshold": threshold,
+		}); err != nil {
+			return false, "", response.InternalServerError("Failed to update credit limit", err)
+		}
+		driver.MinBalanceThreshold = threshold
+	}
+
+	if driver.CreditOverrideActive(time.Now()) {
+		if driver.IsRestricted {
+			if err := s.UnrestrictDriverAccount(ctx, driver.ID); err != nil {
+				logger.Error("failed to unrestrict driver account", "error", err, "driverID", driver.ID)
+			}
+		}
+		return false, "", nil
+	}
+
 	wallet, err := s.repo.FindWalletByUserID(ctx, driver.UserID, models.WalletTypeDriver)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -1028,8 +1086,8 @@ func (s *service) CheckAndEnforceAccountRestriction(ctx context.Context, driverI
 		reason := fmt.Sprintf("Negative balance: $%.2f (threshold: $%.2f)", wallet.Balance, driver.MinBalanceThreshold)
 
 		if !driver.IsRestricted {
-			if err := s.RestrictDriverAccount(ctx, driverID, reason); err != nil {
-				logger.Error("failed to restrict driver account", "error", err, "driverID", driverID)
+			if err := s.RestrictDriverAccount(ctx, driver.ID, reason); err != nil {
+				logger.Error("failed to restrict driver account", "error", err, "driverID", driver.ID)
 			}
 		}
 
@@ -1037,12 +1095,18 @@ func (s *service) CheckAndEnforceAccountRestriction(ctx context.Context, driverI
 	}
 
 	if driver.IsRestricted && wallet.Balance >= 0 {
-		if err := s.UnrestrictDriverAccount(ctx, driverID); err != nil {
-			logger.Error("failed to unrestrict driver account", "error", err, "driverID", driverID)
+		if err := s.UnrestrictDriverAccount(ctx, driver.ID); err != nil {
+			logger.Error("failed to unrestrict driver account", "error", err, "driverID", driver.ID)
+		} else {
+			driver.IsRestricted = false
 		}
 	}
 
-	return driver.IsRestricted, "", nil
+	var reason string
+	if driver.IsRestricted {
+		reason = derefString(driver.RestrictionReason)
+	}
+	return driver.IsRestricted, reason, nil
 }
 
 func (s *service) RestrictDriverAccount(ctx context.Context, driverID string, reason string) error {
@@ -1138,6 +1202,81 @@ func (s *service) RecordBalanceAudit(ctx context.Context, driverID, userID strin
 	return nil
 }
 
+// walletMovement is a debit or credit of a wallet's available funds, booked
+// against the platform account for its reference type.
+type walletMovement struct {
+	walletID      string
+	txType        models.TransactionType
+	amount        float64
+	referenceType string
+	referenceID   *string
+	description   string
+	paymentMethod string
+	metadata      map[string]interface{}
+	conversion    *currency.Conversion
+	// requireFunds refuses a debit that the available balance does not cover.
+	requireFunds bool
+}
+
+// postMovement posts the movement's journal entry and records it as a wallet
+// transaction, under a lock on the wallet.
+func (s *service) postMovement(ctx context.Context, m walletMovement) (*models.WalletTransaction, error) {
+	var txn *models.WalletTransaction
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		wallet, err := lockWallet(tx, m.walletID)
+		if err != nil {
+			return err
+		}
+		if m.requireFunds && wallet.GetAvailableBalance() < m.amount {
+			return errInsufficientFunds
+		}
+
+		referenceID := ""
+		if m.referenceID != nil {
+			referenceID = *m.referenceID
+		}
+		journal := NewJournal(string(m.txType), m.referenceType, referenceID, m.description)
+		counter := counterAccount(m.referenceType)
+		if m.txType == models.TransactionTypeDebit {
+			journal.DebitWallet(wallet, m.amount).CreditPlatform(counter, wallet.Currency, m.amount)
+		} else {
+			journal.DebitPlatform(counter, wallet.Currency, m.amount).CreditWallet(wallet, m.amount)
+		}
+
+		balanceBefore := wallet.Balance
+		entry, err := journal.Post(tx)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		txn = &models.WalletTransaction{
+			WalletID:       wallet.ID,
+			Currency:       wallet.Currency,
+			Type:           m.txType,
+			Amount:         m.amount,
+			BalanceBefore:  balanceBefore,
+			BalanceAfter:   wallet.Balance,
+			Status:         models.TransactionStatusCompleted,
+			ReferenceType:  &m.referenceType,
+			ReferenceID:    m.referenceID,
+			Description:    &m.description,
+			PaymentMethod:  m.paymentMethod,
+			Metadata:       m.metadata,
+			ProcessedAt:    &now,
+			JournalEntryID: &entry.ID,
+		}
+		if m.conversion != nil {
+			recordConversion(txn, m.conversion)
+		}
+		if err := tx.Create(txn).Error; err != nil {
+			return err
+		}
+		return recordDebtRepayment(tx, wallet, txn)
+	})
+	return txn, err
+}
+
 func (s *service) invalidateWalletCache(ctx context.Context, userID string) {
 	cache.Delete(ctx, fmt.Sprintf("wallet:user:%s", userID))
 }
@@ -0,0 +1,37 @@
+package webhooks
+
+import (
+	"context"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/audit"
+)
+
+func (s *service) SetAuditLogger(auditLogger audit.Recorder) {
+	s.auditLogger = auditLogger
+}
+
+func (s *service) recordAudit(ctx context.Context, adminID, action, subscriptionID string, before, after map[string]interface{}) {
+	if s.auditLogger == nil {
+		return
+	}
+	s.auditLogger.Record(ctx, audit.Entry{
+		ActorID:    adminID,
+		ActorRole:  string(models.RoleAdmin),
+		Action:     action,
+		EntityType: "webhook_subscription",
+		EntityID:   subscriptionID,
+		Before:     before,
+		After:      after,
+	})
+}
+
+// subscriptionAuditSnapshot leaves out the signing secret.
+func subscriptionAuditSnapshot(sub *models.WebhookSubscription) map[string]interface{} {
+	return map[string]interface{}{
+		"name":       sub.Name,
+		"url":        sub.URL,
+		"eventTypes": []string(sub.EventTypes),
+		"active":     sub.Active,
+	}
+}
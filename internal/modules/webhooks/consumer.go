@@ -0,0 +1,31 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/umar5678/go-backend/internal/services/eventbus"
+)
+
+// busEventTypes are the domain events forwarded to webhook subscribers under
+// the same event type.
+var busEventTypes = []string{eventbus.RideCompleted, eventbus.OrderAssigned}
+
+// Subscribe forwards domain events from the bus to webhook subscribers.
+func Subscribe(bus eventbus.Bus, service Service) error {
+	return bus.Subscribe("webhooks", service.HandleEvent, busEventTypes...)
+}
+
+func (s *service) HandleEvent(ctx context.Context, event eventbus.Event) error {
+	var data map[string]interface{}
+	if err := event.Decode(&data); err != nil {
+		return fmt.Errorf("decode %s payload: %w", event.Type, err)
+	}
+	_, err := s.repo.Enqueue(ctx, Event{
+		ID:         event.ID,
+		Type:       event.Type,
+		OccurredAt: event.OccurredAt,
+		Data:       data,
+	})
+	return err
+}
@@ -0,0 +1,175 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+const (
+	// maxConcurrentDeliveries bounds the requests in flight per batch.
+	maxConcurrentDeliveries = 8
+	maxErrorBodyBytes       = 512
+)
+
+// sender posts events to subscriber URLs. Redirects are not followed; a
+// subscriber that moved must have its URL updated.
+type sender struct {
+	client *http.Client
+}
+
+func newSender(timeout time.Duration) *sender {
+	return &sender{client: &http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+// signature is the hex HMAC-SHA256, keyed with the subscription secret, of
+// "<timestamp>.<body>". Receivers should recompute it and reject old
+// timestamps.
+func signature(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// send returns the response status, or 0 when no response was received.
+func (s *sender) send(ctx context.Context, d *models.WebhookDelivery) (int, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"id":         d.EventID,
+		"type":       d.EventType,
+		"occurredAt": d.OccurredAt.UTC(),
+		"data":       d.Payload,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.Subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "supr-webhooks/1.0")
+	req.Header.Set("X-Webhook-Id", d.EventID)
+	req.Header.Set("X-Webhook-Event", d.EventType)
+	req.Header.Set("X-Webhook-Delivery", d.ID)
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Signature", signature(d.Subscription.Secret, timestamp, body))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+		return resp.StatusCode, fmt.Errorf("HTTP %d: %s", resp.StatusCode, bytes.TrimSpace(snippet))
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBodyBytes))
+	return resp.StatusCode, nil
+}
+
+func (s *service) DeliverDue(ctx context.Context) (int, error) {
+	// A claimed delivery is left alone for twice the request timeout, after
+	// which another worker may retry it if this one died mid-attempt.
+	deliveries, err := s.repo.ClaimDue(ctx, time.Now(), 2*s.cfg.RequestTimeout, s.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, maxConcurrentDeliveries)
+	for _, d := range deliveries {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(d *models.WebhookDelivery) {
+			defer func() {
+				if r := recover(); r != nil {
+					logger.Error("panic in webhook delivery", "error", r, "deliveryID", d.ID)
+				}
+				<-slots
+				wg.Done()
+			}()
+			s.deliver(ctx, d)
+		}(d)
+	}
+	wg.Wait()
+
+	return len(deliveries), nil
+}
+
+func (s *service) deliver(ctx context.Context, d *models.WebhookDelivery) {
+	now := time.Now()
+
+	// Deliveries are only queued for active subscriptions, but one may have
+	// been paused since. Dead-letter them so they can be retried once it is
+	// active again.
+	if d.Subscription == nil || !d.Subscription.Active {
+		if err := s.repo.MarkFailed(ctx, d.ID, now, nil, "subscription is inactive", now, true); err != nil {
+			logger.Error("failed to update webhook delivery", "error", err, "deliveryID", d.ID)
+		}
+		return
+	}
+
+	status, sendErr := s.sender.send(ctx, d)
+	if sendErr == nil {
+		if err := s.repo.MarkDelivered(ctx, d.ID, time.Now(), status); err != nil {
+			logger.Error("failed to update webhook delivery", "error", err, "deliveryID", d.ID)
+		}
+		return
+	}
+
+	attempt := d.Attempts + 1
+	dead := attempt >= s.cfg.MaxAttempts
+	var statusCode *int
+	if status != 0 {
+		statusCode = &status
+	}
+	if err := s.repo.MarkFailed(ctx, d.ID, time.Now(), statusCode, sendErr.Error(), time.Now().Add(s.backoff(attempt)), dead); err != nil {
+		logger.Error("failed to update webhook delivery", "error", err, "deliveryID", d.ID)
+	}
+
+	if dead {
+		logger.Warn("webhook delivery moved to dead letters",
+			"deliveryID", d.ID,
+			"subscriptionID", d.SubscriptionID,
+			"eventType", d.EventType,
+			"attempts", attempt,
+			"error", sendErr,
+		)
+	}
+}
+
+// backoff doubles from InitialBackoff with each attempt, up to MaxBackoff,
+// with up to 10% jitter so failed deliveries do not retry in lockstep.
+func (s *service) backoff(attempt int) time.Duration {
+	wait := s.cfg.InitialBackoff
+	for i := 1; i < attempt && wait < s.cfg.MaxBackoff; i++ {
+		wait *= 2
+	}
+	if wait > s.cfg.MaxBackoff {
+		wait = s.cfg.MaxBackoff
+	}
+	return wait + time.Duration(rand.Int63n(int64(wait)/10+1))
+}
@@ -0,0 +1,47 @@
+package dto
+
+type CreateSubscriptionRequest struct {
+	Name        string   `json:"name" binding:"required,min=2,max=100"`
+	URL         string   `json:"url" binding:"required,url,max=2048"`
+	EventTypes  []string `json:"eventTypes" binding:"required,min=1,dive,oneof=ride.completed order.assigned order.cancelled wallet.transaction.created"`
+	Description string   `json:"description" binding:"omitempty,max=500"`
+}
+
+// UpdateSubscriptionRequest changes only the fields that are set.
+type UpdateSubscriptionRequest struct {
+	Name        *string  `json:"name" binding:"omitempty,min=2,max=100"`
+	URL         *string  `json:"url" binding:"omitempty,url,max=2048"`
+	EventTypes  []string `json:"eventTypes" binding:"omitempty,min=1,dive,oneof=ride.completed order.assigned order.cancelled wallet.transaction.created"`
+	Active      *bool    `json:"active"`
+	Description *string  `json:"description" binding:"omitempty,max=500"`
+}
+
+type ListSubscriptionsRequest struct {
+	Page  int `form:"page" binding:"omitempty,min=1"`
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (r *ListSubscriptionsRequest) SetDefaults() {
+	if r.Page <= 0 {
+		r.Page = 1
+	}
+	if r.Limit <= 0 {
+		r.Limit = 20
+	}
+}
+
+type ListDeliveriesRequest struct {
+	SubscriptionID string `form:"subscriptionId" binding:"omitempty,uuid"`
+	Status         string `form:"status" binding:"omitempty,oneof=pending delivered dead"`
+	Page           int    `form:"page" binding:"omitempty,min=1"`
+	Limit          int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+func (r *ListDeliveriesRequest) SetDefaults() {
+	if r.Page <= 0 {
+		r.Page = 1
+	}
+	if r.Limit <= 0 {
+		r.Limit = 20
+	}
+}
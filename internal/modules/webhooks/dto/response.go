@@ -0,0 +1,80 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+)
+
+type SubscriptionResponse struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	URL         string    `json:"url"`
+	EventTypes  []string  `json:"eventTypes"`
+	Active      bool      `json:"active"`
+	Description string    `json:"description,omitempty"`
+	CreatedBy   string    `json:"createdBy"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+func ToSubscriptionResponse(sub *models.WebhookSubscription) *SubscriptionResponse {
+	return &SubscriptionResponse{
+		ID:          sub.ID,
+		Name:        sub.Name,
+		URL:         sub.URL,
+		EventTypes:  sub.EventTypes,
+		Active:      sub.Active,
+		Description: sub.Description,
+		CreatedBy:   sub.CreatedBy,
+		CreatedAt:   sub.CreatedAt,
+		UpdatedAt:   sub.UpdatedAt,
+	}
+}
+
+// SubscriptionSecretResponse carries the signing secret. It is only returned
+// when a subscription is created or its secret rotated.
+type SubscriptionSecretResponse struct {
+	SubscriptionResponse
+	Secret string `json:"secret"`
+}
+
+type DeliveryResponse struct {
+	ID             string                 `json:"id"`
+	SubscriptionID string                 `json:"subscriptionId"`
+	EventID        string                 `json:"eventId"`
+	EventType      string                 `json:"eventType"`
+	Payload        map[string]interface{} `json:"payload"`
+	OccurredAt     time.Time              `json:"occurredAt"`
+	Status         string                 `json:"status"`
+	Attempts       int                    `json:"attempts"`
+	NextAttemptAt  *time.Time             `json:"nextAttemptAt,omitempty"`
+	LastAttemptAt  *time.Time             `json:"lastAttemptAt,omitempty"`
+	LastStatusCode *int                   `json:"lastStatusCode,omitempty"`
+	LastError      string                 `json:"lastError,omitempty"`
+	DeliveredAt    *time.Time             `json:"deliveredAt,omitempty"`
+	CreatedAt      time.Time              `json:"createdAt"`
+}
+
+func ToDeliveryResponse(d *models.WebhookDelivery) *DeliveryResponse {
+	resp := &DeliveryResponse{
+		ID:             d.ID,
+		SubscriptionID: d.SubscriptionID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Payload:        d.Payload,
+		OccurredAt:     d.OccurredAt,
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		LastAttemptAt:  d.LastAttemptAt,
+		LastStatusCode: d.LastStatusCode,
+		LastError:      d.LastError,
+		DeliveredAt:    d.DeliveredAt,
+		CreatedAt:      d.CreatedAt,
+	}
+	if d.Status == models.WebhookDeliveryPending {
+		next := d.NextAttemptAt
+		resp.NextAttemptAt = &next
+	}
+	return resp
+}
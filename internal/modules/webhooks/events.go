@@ -0,0 +1,26 @@
+package webhooks
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event is something subscriptions can be told about. Receivers get it as
+// {"id", "type", "occurredAt", "data"}; a retried delivery carries the same
+// ID.
+type Event struct {
+	ID         string
+	Type       string
+	OccurredAt time.Time
+	Data       map[string]interface{}
+}
+
+func NewEvent(eventType string, data map[string]interface{}) Event {
+	return Event{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}
+}
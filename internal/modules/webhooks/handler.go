@@ -0,0 +1,236 @@
+package webhooks
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/webhooks/dto"
+	"github.com/umar5678/go-backend/internal/utils/response"
+)
+
+type Handler struct {
+	service Service
+}
+
+func NewHandler(service Service) *Handler {
+	return &Handler{service: service}
+}
+
+// CreateSubscription godoc
+// @Summary Create a webhook subscription
+// @Description Events are POSTed to the URL as JSON with an X-Webhook-Signature header: the hex HMAC-SHA256 of "<X-Webhook-Timestamp>.<body>" keyed with the secret. The secret is only returned here and when rotated. Delivery is at least once; use the event ID to drop duplicates
+// @Tags admin-webhooks
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateSubscriptionRequest true "Subscription"
+// @Success 200 {object} response.Response{data=dto.SubscriptionSecretResponse}
+// @Router /admin/webhooks [post]
+func (h *Handler) CreateSubscription(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	var req dto.CreateSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	sub, err := h.service.CreateSubscription(c.Request.Context(), adminID.(string), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, sub, "Webhook subscription created successfully")
+}
+
+// ListSubscriptions godoc
+// @Summary List webhook subscriptions
+// @Tags admin-webhooks
+// @Security BearerAuth
+// @Produce json
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Response{data=[]dto.SubscriptionResponse}
+// @Router /admin/webhooks [get]
+func (h *Handler) ListSubscriptions(c *gin.Context) {
+	var req dto.ListSubscriptionsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+	req.SetDefaults()
+
+	subs, total, err := h.service.ListSubscriptions(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pagination := response.NewPaginationMeta(total, req.Page, req.Limit)
+	response.Paginated(c, subs, pagination, "Webhook subscriptions retrieved successfully")
+}
+
+// GetSubscription godoc
+// @Summary Get a webhook subscription
+// @Tags admin-webhooks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {object} response.Response{data=dto.SubscriptionResponse}
+// @Router /admin/webhooks/{id} [get]
+func (h *Handler) GetSubscription(c *gin.Context) {
+	sub, err := h.service.GetSubscription(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, sub, "Webhook subscription retrieved successfully")
+}
+
+// UpdateSubscription godoc
+// @Summary Update a webhook subscription
+// @Description Only the fields given are changed. Setting active to false pauses deliveries
+// @Tags admin-webhooks
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param request body dto.UpdateSubscriptionRequest true "Changes"
+// @Success 200 {object} response.Response{data=dto.SubscriptionResponse}
+// @Router /admin/webhooks/{id} [put]
+func (h *Handler) UpdateSubscription(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	var req dto.UpdateSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(response.BadRequest("Invalid request body: " + err.Error()))
+		return
+	}
+
+	sub, err := h.service.UpdateSubscription(c.Request.Context(), adminID.(string), c.Param("id"), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, sub, "Webhook subscription updated successfully")
+}
+
+// DeleteSubscription godoc
+// @Summary Delete a webhook subscription
+// @Description Pending and dead deliveries for the subscription are deleted with it
+// @Tags admin-webhooks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {object} response.Response
+// @Router /admin/webhooks/{id} [delete]
+func (h *Handler) DeleteSubscription(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	if err := h.service.DeleteSubscription(c.Request.Context(), adminID.(string), c.Param("id")); err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, nil, "Webhook subscription deleted successfully")
+}
+
+// RotateSecret godoc
+// @Summary Rotate a webhook subscription's signing secret
+// @Description The old secret stops being used immediately
+// @Tags admin-webhooks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {object} response.Response{data=dto.SubscriptionSecretResponse}
+// @Router /admin/webhooks/{id}/rotate-secret [post]
+func (h *Handler) RotateSecret(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	sub, err := h.service.RotateSecret(c.Request.Context(), adminID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, sub, "Signing secret rotated successfully")
+}
+
+// ListDeliveries godoc
+// @Summary List webhook deliveries
+// @Tags admin-webhooks
+// @Security BearerAuth
+// @Produce json
+// @Param subscriptionId query string false "Subscription ID"
+// @Param status query string false "Filter by status (pending, delivered, dead)"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Response{data=[]dto.DeliveryResponse}
+// @Router /admin/webhooks/deliveries [get]
+func (h *Handler) ListDeliveries(c *gin.Context) {
+	var req dto.ListDeliveriesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+	req.SetDefaults()
+
+	h.listDeliveries(c, req, "Webhook deliveries retrieved successfully")
+}
+
+// ListDeadLetters godoc
+// @Summary List dead-lettered webhook deliveries
+// @Description Deliveries that ran out of attempts, most recent first. They can be retried once the receiver is fixed
+// @Tags admin-webhooks
+// @Security BearerAuth
+// @Produce json
+// @Param subscriptionId query string false "Subscription ID"
+// @Param page query int false "Page number"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} response.Response{data=[]dto.DeliveryResponse}
+// @Router /admin/webhooks/dead-letters [get]
+func (h *Handler) ListDeadLetters(c *gin.Context) {
+	var req dto.ListDeliveriesRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(response.BadRequest("Invalid query parameters: " + err.Error()))
+		return
+	}
+	req.SetDefaults()
+	req.Status = string(models.WebhookDeliveryDead)
+
+	h.listDeliveries(c, req, "Dead letters retrieved successfully")
+}
+
+func (h *Handler) listDeliveries(c *gin.Context, req dto.ListDeliveriesRequest, message string) {
+	deliveries, total, err := h.service.ListDeliveries(c.Request.Context(), req)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	pagination := response.NewPaginationMeta(total, req.Page, req.Limit)
+	response.Paginated(c, deliveries, pagination, message)
+}
+
+// RetryDelivery godoc
+// @Summary Retry a dead-lettered webhook delivery
+// @Description Queues the delivery again with a fresh set of attempts
+// @Tags admin-webhooks
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "Delivery ID"
+// @Success 200 {object} response.Response{data=dto.DeliveryResponse}
+// @Router /admin/webhooks/deliveries/{id}/retry [post]
+func (h *Handler) RetryDelivery(c *gin.Context) {
+	adminID, _ := c.Get("userID")
+
+	delivery, err := h.service.RetryDelivery(c.Request.Context(), adminID.(string), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	response.Success(c, delivery, "Webhook delivery queued for retry")
+}
@@ -0,0 +1,97 @@
+package webhooks
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+const (
+	walletTransactionCallback  = "webhooks:wallet_transaction"
+	walletTransactionSavePoint = "webhooks_wallet_transaction"
+)
+
+// WalletTransactionPlugin queues a wallet.transaction.created event for every
+// wallet transaction inserted through GORM. Like the money movement audit, the
+// deliveries are written on the same connection, so subscribers never hear of
+// a transaction that was rolled back.
+type WalletTransactionPlugin struct{}
+
+func NewWalletTransactionPlugin() *WalletTransactionPlugin {
+	return &WalletTransactionPlugin{}
+}
+
+func (p *WalletTransactionPlugin) Name() string {
+	return "webhooks_wallet_transaction"
+}
+
+func (p *WalletTransactionPlugin) Initialize(db *gorm.DB) error {
+	return db.Callback().Create().After("gorm:create").Register(walletTransactionCallback, p.afterCreate)
+}
+
+func (p *WalletTransactionPlugin) afterCreate(db *gorm.DB) {
+	if db.Error != nil || db.Statement.Schema == nil || db.Statement.Schema.Table != (models.WalletTransaction{}).TableName() {
+		return
+	}
+
+	var transactions []*models.WalletTransaction
+	switch dest := db.Statement.Dest.(type) {
+	case *models.WalletTransaction:
+		transactions = append(transactions, dest)
+	case []*models.WalletTransaction:
+		transactions = dest
+	case *[]*models.WalletTransaction:
+		transactions = *dest
+	case *[]models.WalletTransaction:
+		for i := range *dest {
+			transactions = append(transactions, &(*dest)[i])
+		}
+	default:
+		return
+	}
+
+	session := db.Session(&gorm.Session{NewDB: true})
+	_, inTx := db.Statement.ConnPool.(gorm.TxCommitter)
+	for _, txn := range transactions {
+		event := NewEvent(models.WebhookEventWalletTransactionCreated, walletTransactionData(txn))
+
+		// A failed insert must not abort the surrounding transaction and take
+		// the wallet movement down with it.
+		if inTx {
+			if err := session.SavePoint(walletTransactionSavePoint).Error; err != nil {
+				logger.Error("failed to queue wallet transaction webhook", "error", err, "transactionID", txn.ID)
+				continue
+			}
+		}
+		if _, err := enqueue(session, event); err != nil {
+			logger.Error("failed to queue wallet transaction webhook", "error", err, "transactionID", txn.ID)
+			if inTx {
+				session.RollbackTo(walletTransactionSavePoint)
+			}
+		}
+	}
+}
+
+func walletTransactionData(txn *models.WalletTransaction) map[string]interface{} {
+	data := map[string]interface{}{
+		"transactionId": txn.ID,
+		"walletId":      txn.WalletID,
+		"type":          txn.Type,
+		"amount":        txn.Amount,
+		"currency":      txn.Currency,
+		"balanceAfter":  txn.BalanceAfter,
+		"status":        txn.Status,
+		"createdAt":     txn.CreatedAt,
+	}
+	if txn.ReferenceType != nil {
+		data["referenceType"] = *txn.ReferenceType
+	}
+	if txn.ReferenceID != nil {
+		data["referenceId"] = *txn.ReferenceID
+	}
+	if txn.Description != nil {
+		data["description"] = *txn.Description
+	}
+	return data
+}
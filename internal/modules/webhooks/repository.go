@@ -0,0 +1,228 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) error
+	FindSubscription(ctx context.Context, id string) (*models.WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, sub *models.WebhookSubscription) error
+	DeleteSubscription(ctx context.Context, id string) (bool, error)
+	ListSubscriptions(ctx context.Context, page, limit int) ([]*models.WebhookSubscription, int64, error)
+
+	// Enqueue adds a delivery of the event for every active subscription to
+	// its type and returns how many were added.
+	Enqueue(ctx context.Context, event Event) (int64, error)
+	// ClaimDue returns up to limit due deliveries and pushes their next
+	// attempt back by lease, so no other worker picks them up meanwhile.
+	// Rows locked by another worker are skipped.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*models.WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, id string, at time.Time, statusCode int) error
+	MarkFailed(ctx context.Context, id string, at time.Time, statusCode *int, lastError string, nextAttemptAt time.Time, dead bool) error
+
+	FindDelivery(ctx context.Context, id string) (*models.WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, subscriptionID string, status models.WebhookDeliveryStatus, page, limit int) ([]*models.WebhookDelivery, int64, error)
+	// Requeue makes a dead delivery due again with a fresh set of attempts.
+	Requeue(ctx context.Context, id string, now time.Time) (bool, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) CreateSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	return r.db.WithContext(ctx).Create(sub).Error
+}
+
+func (r *repository) FindSubscription(ctx context.Context, id string) (*models.WebhookSubscription, error) {
+	var sub models.WebhookSubscription
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&sub).Error
+	if err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+func (r *repository) UpdateSubscription(ctx context.Context, sub *models.WebhookSubscription) error {
+	return r.db.WithContext(ctx).Save(sub).Error
+}
+
+func (r *repository) DeleteSubscription(ctx context.Context, id string) (bool, error) {
+	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&models.WebhookSubscription{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *repository) ListSubscriptions(ctx context.Context, page, limit int) ([]*models.WebhookSubscription, int64, error) {
+	var subs []*models.WebhookSubscription
+	var total int64
+
+	base := r.db.WithContext(ctx).Model(&models.WebhookSubscription{})
+
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count webhook subscriptions: %w", err)
+	}
+
+	if total == 0 {
+		return []*models.WebhookSubscription{}, 0, nil
+	}
+
+	offset := (page - 1) * limit
+	if err := base.
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&subs).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch webhook subscriptions: %w", err)
+	}
+
+	return subs, total, nil
+}
+
+func (r *repository) Enqueue(ctx context.Context, event Event) (int64, error) {
+	return enqueue(r.db.WithContext(ctx), event)
+}
+
+// enqueue fans the event out to subscriptions in a single statement, so it
+// can run on whatever connection db is bound to, including an open
+// transaction.
+func enqueue(db *gorm.DB, event Event) (int64, error) {
+	payload, err := json.Marshal(event.Data)
+	if err != nil {
+		return 0, err
+	}
+	result := db.Exec(`
+		INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload, occurred_at, next_attempt_at)
+		SELECT id, ?::uuid, ?, ?::jsonb, ?::timestamptz, ?::timestamptz
+		FROM webhook_subscriptions
+		WHERE active AND event_types @> ARRAY[?]::text[]
+		ON CONFLICT (subscription_id, event_id) DO NOTHING
+	`, event.ID, event.Type, string(payload), event.OccurredAt, event.OccurredAt, event.Type)
+	return result.RowsAffected, result.Error
+}
+
+func (r *repository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*models.WebhookDelivery, error) {
+	var claimed []*models.WebhookDelivery
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ids []string
+		err := tx.Raw(`
+			SELECT id FROM webhook_deliveries
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		`, models.WebhookDeliveryPending, now, limit).
+			Scan(&ids).Error
+		if err != nil {
+			return fmt.Errorf("failed to select due webhook deliveries: %w", err)
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		err = tx.Model(&models.WebhookDelivery{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+		if err != nil {
+			return fmt.Errorf("failed to claim webhook deliveries: %w", err)
+		}
+
+		return tx.Preload("Subscription").Where("id IN ?", ids).Order("occurred_at").Find(&claimed).Error
+	})
+	return claimed, err
+}
+
+func (r *repository) MarkDelivered(ctx context.Context, id string, at time.Time, statusCode int) error {
+	return r.db.WithContext(ctx).
+		Model(&models.WebhookDelivery{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":           models.WebhookDeliveryDelivered,
+			"attempts":         gorm.Expr("attempts + 1"),
+			"last_attempt_at":  at,
+			"last_status_code": statusCode,
+			"last_error":       "",
+			"delivered_at":     at,
+		}).Error
+}
+
+func (r *repository) MarkFailed(ctx context.Context, id string, at time.Time, statusCode *int, lastError string, nextAttemptAt time.Time, dead bool) error {
+	status := models.WebhookDeliveryPending
+	if dead {
+		status = models.WebhookDeliveryDead
+	}
+	return r.db.WithContext(ctx).
+		Model(&models.WebhookDelivery{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":           status,
+			"attempts":         gorm.Expr("attempts + 1"),
+			"last_attempt_at":  at,
+			"last_status_code": statusCode,
+			"last_error":       lastError,
+			"next_attempt_at":  nextAttemptAt,
+		}).Error
+}
+
+func (r *repository) FindDelivery(ctx context.Context, id string) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := r.db.WithContext(ctx).Where("id = ?", id).First(&delivery).Error
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (r *repository) ListDeliveries(ctx context.Context, subscriptionID string, status models.WebhookDeliveryStatus, page, limit int) ([]*models.WebhookDelivery, int64, error) {
+	var deliveries []*models.WebhookDelivery
+	var total int64
+
+	base := r.db.WithContext(ctx).Model(&models.WebhookDelivery{})
+	if subscriptionID != "" {
+		base = base.Where("subscription_id = ?", subscriptionID)
+	}
+	if status != "" {
+		base = base.Where("status = ?", status)
+	}
+
+	if err := base.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+
+	if total == 0 {
+		return []*models.WebhookDelivery{}, 0, nil
+	}
+
+	offset := (page - 1) * limit
+	if err := base.
+		Order("updated_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&deliveries).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch webhook deliveries: %w", err)
+	}
+
+	return deliveries, total, nil
+}
+
+func (r *repository) Requeue(ctx context.Context, id string, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.WebhookDelivery{}).
+		Where("id = ? AND status = ?", id, models.WebhookDeliveryDead).
+		Updates(map[string]interface{}{
+			"status":          models.WebhookDeliveryPending,
+			"attempts":        0,
+			"next_attempt_at": now,
+		})
+	return result.RowsAffected > 0, result.Error
+}
@@ -0,0 +1,25 @@
+package webhooks
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/middleware"
+)
+
+func RegisterRoutes(router *gin.RouterGroup, handler *Handler, authMiddleware gin.HandlerFunc) {
+	webhooks := router.Group("/admin/webhooks")
+	webhooks.Use(authMiddleware)
+	webhooks.Use(middleware.RequireAdmin())
+	{
+		webhooks.POST("", handler.CreateSubscription)
+		webhooks.GET("", handler.ListSubscriptions)
+
+		webhooks.GET("/deliveries", handler.ListDeliveries)
+		webhooks.GET("/dead-letters", handler.ListDeadLetters)
+		webhooks.POST("/deliveries/:id/retry", handler.RetryDelivery)
+
+		webhooks.GET("/:id", handler.GetSubscription)
+		webhooks.PUT("/:id", handler.UpdateSubscription)
+		webhooks.DELETE("/:id", handler.DeleteSubscription)
+		webhooks.POST("/:id/rotate-secret", handler.RotateSecret)
+	}
+}
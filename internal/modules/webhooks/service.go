@@ -0,0 +1,268 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/modules/audit"
+	"github.com/umar5678/go-backend/internal/modules/webhooks/dto"
+	"github.com/umar5678/go-backend/internal/services/eventbus"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"gorm.io/gorm"
+)
+
+const secretPrefix = "whsec_"
+
+type Service interface {
+	CreateSubscription(ctx context.Context, adminID string, req dto.CreateSubscriptionRequest) (*dto.SubscriptionSecretResponse, error)
+	GetSubscription(ctx context.Context, id string) (*dto.SubscriptionResponse, error)
+	ListSubscriptions(ctx context.Context, req dto.ListSubscriptionsRequest) ([]*dto.SubscriptionResponse, int64, error)
+	UpdateSubscription(ctx context.Context, adminID, id string, req dto.UpdateSubscriptionRequest) (*dto.SubscriptionResponse, error)
+	DeleteSubscription(ctx context.Context, adminID, id string) error
+	RotateSecret(ctx context.Context, adminID, id string) (*dto.SubscriptionSecretResponse, error)
+
+	ListDeliveries(ctx context.Context, req dto.ListDeliveriesRequest) ([]*dto.DeliveryResponse, int64, error)
+	RetryDelivery(ctx context.Context, adminID, id string) (*dto.DeliveryResponse, error)
+
+	// Publish queues the event for every active subscription to it.
+	Publish(ctx context.Context, eventType string, data map[string]interface{}) error
+	// HandleEvent queues a domain event from the event bus, keeping its ID so
+	// a redelivered event is queued once.
+	HandleEvent(ctx context.Context, event eventbus.Event) error
+	// DeliverDue attempts one batch of due deliveries and returns how many
+	// were attempted.
+	DeliverDue(ctx context.Context) (int, error)
+
+	SetAuditLogger(auditLogger audit.Recorder)
+}
+
+type service struct {
+	repo        Repository
+	cfg         config.WebhooksConfig
+	sender      *sender
+	auditLogger audit.Recorder
+}
+
+func NewService(repo Repository, cfg config.WebhooksConfig) Service {
+	return &service{repo: repo, cfg: cfg, sender: newSender(cfg.RequestTimeout)}
+}
+
+func (s *service) CreateSubscription(ctx context.Context, adminID string, req dto.CreateSubscriptionRequest) (*dto.SubscriptionSecretResponse, error) {
+	if err := s.validateURL(req.URL); err != nil {
+		return nil, err
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, response.InternalServerError("Failed to generate signing secret", err)
+	}
+
+	sub := &models.WebhookSubscription{
+		Name:        strings.TrimSpace(req.Name),
+		URL:         req.URL,
+		Secret:      secret,
+		EventTypes:  dedupe(req.EventTypes),
+		Active:      true,
+		Description: req.Description,
+		CreatedBy:   adminID,
+	}
+	if err := s.repo.CreateSubscription(ctx, sub); err != nil {
+		logger.Error("failed to create webhook subscription", "error", err)
+		return nil, response.InternalServerError("Failed to create webhook subscription", err)
+	}
+
+	s.recordAudit(ctx, adminID, "webhook.create", sub.ID, nil, subscriptionAuditSnapshot(sub))
+	return &dto.SubscriptionSecretResponse{
+		SubscriptionResponse: *dto.ToSubscriptionResponse(sub),
+		Secret:               secret,
+	}, nil
+}
+
+func (s *service) GetSubscription(ctx context.Context, id string) (*dto.SubscriptionResponse, error) {
+	sub, err := s.findSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return dto.ToSubscriptionResponse(sub), nil
+}
+
+func (s *service) ListSubscriptions(ctx context.Context, req dto.ListSubscriptionsRequest) ([]*dto.SubscriptionResponse, int64, error) {
+	subs, total, err := s.repo.ListSubscriptions(ctx, req.Page, req.Limit)
+	if err != nil {
+		return nil, 0, response.InternalServerError("Failed to fetch webhook subscriptions", err)
+	}
+
+	result := make([]*dto.SubscriptionResponse, 0, len(subs))
+	for _, sub := range subs {
+		result = append(result, dto.ToSubscriptionResponse(sub))
+	}
+	return result, total, nil
+}
+
+func (s *service) UpdateSubscription(ctx context.Context, adminID, id string, req dto.UpdateSubscriptionRequest) (*dto.SubscriptionResponse, error) {
+	sub, err := s.findSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := subscriptionAuditSnapshot(sub)
+
+	if req.Name != nil {
+		sub.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.URL != nil {
+		if err := s.validateURL(*req.URL); err != nil {
+			return nil, err
+		}
+		sub.URL = *req.URL
+	}
+	if len(req.EventTypes) > 0 {
+		sub.EventTypes = dedupe(req.EventTypes)
+	}
+	if req.Active != nil {
+		sub.Active = *req.Active
+	}
+	if req.Description != nil {
+		sub.Description = *req.Description
+	}
+
+	if err := s.repo.UpdateSubscription(ctx, sub); err != nil {
+		return nil, response.InternalServerError("Failed to update webhook subscription", err)
+	}
+
+	s.recordAudit(ctx, adminID, "webhook.update", sub.ID, before, subscriptionAuditSnapshot(sub))
+	return dto.ToSubscriptionResponse(sub), nil
+}
+
+func (s *service) DeleteSubscription(ctx context.Context, adminID, id string) error {
+	sub, err := s.findSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	deleted, err := s.repo.DeleteSubscription(ctx, id)
+	if err != nil {
+		return response.InternalServerError("Failed to delete webhook subscription", err)
+	}
+	if !deleted {
+		return response.NotFoundError("Webhook subscription")
+	}
+
+	s.recordAudit(ctx, adminID, "webhook.delete", id, subscriptionAuditSnapshot(sub), nil)
+	return nil
+}
+
+func (s *service) RotateSecret(ctx context.Context, adminID, id string) (*dto.SubscriptionSecretResponse, error) {
+	sub, err := s.findSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, response.InternalServerError("Failed to generate signing secret", err)
+	}
+	sub.Secret = secret
+	if err := s.repo.UpdateSubscription(ctx, sub); err != nil {
+		return nil, response.InternalServerError("Failed to rotate signing secret", err)
+	}
+
+	s.recordAudit(ctx, adminID, "webhook.rotate_secret", sub.ID, nil, nil)
+	return &dto.SubscriptionSecretResponse{
+		SubscriptionResponse: *dto.ToSubscriptionResponse(sub),
+		Secret:               secret,
+	}, nil
+}
+
+func (s *service) ListDeliveries(ctx context.Context, req dto.ListDeliveriesRequest) ([]*dto.DeliveryResponse, int64, error) {
+	deliveries, total, err := s.repo.ListDeliveries(ctx, req.SubscriptionID, models.WebhookDeliveryStatus(req.Status), req.Page, req.Limit)
+	if err != nil {
+		return nil, 0, response.InternalServerError("Failed to fetch webhook deliveries", err)
+	}
+
+	result := make([]*dto.DeliveryResponse, 0, len(deliveries))
+	for _, d := range deliveries {
+		result = append(result, dto.ToDeliveryResponse(d))
+	}
+	return result, total, nil
+}
+
+func (s *service) RetryDelivery(ctx context.Context, adminID, id string) (*dto.DeliveryResponse, error) {
+	requeued, err := s.repo.Requeue(ctx, id, time.Now())
+	if err != nil {
+		return nil, response.InternalServerError("Failed to retry webhook delivery", err)
+	}
+
+	delivery, err := s.repo.FindDelivery(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Webhook delivery")
+		}
+		return nil, response.InternalServerError("Failed to fetch webhook delivery", err)
+	}
+	if !requeued {
+		return nil, response.ConflictError("Only dead deliveries can be retried")
+	}
+
+	s.recordAudit(ctx, adminID, "webhook.retry_delivery", delivery.SubscriptionID, nil,
+		map[string]interface{}{"deliveryId": delivery.ID, "eventId": delivery.EventID, "eventType": delivery.EventType})
+	return dto.ToDeliveryResponse(delivery), nil
+}
+
+func (s *service) Publish(ctx context.Context, eventType string, data map[string]interface{}) error {
+	event := NewEvent(eventType, data)
+	if _, err := s.repo.Enqueue(ctx, event); err != nil {
+		logger.Error("failed to queue webhook event", "error", err, "eventType", eventType, "eventID", event.ID)
+		return err
+	}
+	return nil
+}
+
+func (s *service) findSubscription(ctx context.Context, id string) (*models.WebhookSubscription, error) {
+	sub, err := s.repo.FindSubscription(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, response.NotFoundError("Webhook subscription")
+		}
+		return nil, response.InternalServerError("Failed to fetch webhook subscription", err)
+	}
+	return sub, nil
+}
+
+func (s *service) validateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return response.BadRequest("Invalid webhook URL")
+	}
+	if u.Scheme != "https" && !(s.cfg.AllowInsecureURLs && u.Scheme == "http") {
+		return response.BadRequest("Webhook URL must use https")
+	}
+	return nil
+}
+
+func generateSecret() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return secretPrefix + base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func dedupe(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,54 @@
+package webhooks
+
+import (
+	"context"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/services/health"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+// maxBatchesPerTick lets the worker catch up on a backlog without starving
+// the next tick.
+const maxBatchesPerTick = 20
+
+// Worker sends due webhook deliveries.
+type Worker struct {
+	service Service
+	cfg     config.WebhooksConfig
+}
+
+func NewWorker(service Service, cfg config.WebhooksConfig) *Worker {
+	return &Worker{service: service, cfg: cfg}
+}
+
+func (w *Worker) Start(ctx context.Context) {
+	heartbeat := health.NewHeartbeat("webhook_delivery", w.cfg.WorkerInterval)
+
+	go func() {
+		ticker := time.NewTicker(w.cfg.WorkerInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				for i := 0; i < maxBatchesPerTick; i++ {
+					attempted, err := w.service.DeliverDue(ctx)
+					if err != nil {
+						logger.Error("webhook worker failed", "error", err)
+						break
+					}
+					if attempted < w.cfg.BatchSize {
+						break
+					}
+				}
+				heartbeat.Beat()
+			}
+		}
+	}()
+
+	logger.Info("webhook worker started", "interval", w.cfg.WorkerInterval, "maxAttempts", w.cfg.MaxAttempts)
+}
@@ -0,0 +1,28 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+)
+
+// A namespace groups cached results that are invalidated together, such as
+// every page and filter combination of a catalog listing. Keys embed the
+// namespace's current version, so bumping the version orphans all of them
+// at once; the stale entries age out on their own TTL.
+const namespaceVersionPrefix = "ns:version:"
+
+// NamespaceKey returns key scoped to the current version of namespace. If the
+// version cannot be read the key falls back to version 0, which at worst
+// serves an entry cached before the last invalidation until its TTL expires.
+func NamespaceKey(ctx context.Context, namespace, key string) string {
+	version, err := CacheClient.Get(ctx, namespaceVersionPrefix+namespace).Int64()
+	if err != nil {
+		version = 0
+	}
+	return fmt.Sprintf("%s:v%d:%s", namespace, version, key)
+}
+
+// InvalidateNamespace drops every entry cached under namespace.
+func InvalidateNamespace(ctx context.Context, namespace string) error {
+	return CacheClient.Incr(ctx, namespaceVersionPrefix+namespace).Err()
+}
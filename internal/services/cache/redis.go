@@ -139,6 +139,16 @@ func GetJSON(ctx context.Context, key string, dest interface{}) error {
 	return json.Unmarshal(data, dest)
 }
 
+// GetDelJSON reads key and deletes it in one step, so only one caller ever
+// gets the value.
+func GetDelJSON(ctx context.Context, key string, dest interface{}) error {
+	data, err := CacheClient.GetDel(ctx, key).Bytes()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dest)
+}
+
 func Delete(ctx context.Context, key string) error {
 	return CacheClient.Del(ctx, key).Err()
 }
@@ -165,4 +165,68 @@ func RefreshPresence(ctx context.Context, userID string, ttl time.Duration) erro
 
 	SessionClient.Expire(ctx, key, ttl)
 	return SessionClient.Expire(ctx, devicesKey, ttl).Err()
-}
\ No newline at end of file
+}
+// Revoked login sessions are remembered for as long as a token issued under
+// them could still be presented, so access tokens die with their session.
+func MarkAuthSessionRevoked(ctx context.Context, sessionID string, ttl time.Duration) error {
+	return CacheClient.Set(ctx, fmt.Sprintf("auth:session:revoked:%s", sessionID), "1", ttl).Err()
+}
+
+func IsAuthSessionRevoked(ctx context.Context, sessionID string) bool {
+	if sessionID == "" || CacheClient == nil {
+		return false
+	}
+	n, err := CacheClient.Exists(ctx, fmt.Sprintf("auth:session:revoked:%s", sessionID)).Result()
+	return err == nil && n > 0
+}
+
+// UserBlock marks a suspended or banned account so the auth middleware can
+// turn its requests away without a database lookup. Until is nil for bans and
+// open-ended suspensions.
+type UserBlock struct {
+	Kind   string     `json:"kind"`
+	Reason string     `json:"reason"`
+	Until  *time.Time `json:"until,omitempty"`
+}
+
+func userBlockKey(userID string) string {
+	return fmt.Sprintf("auth:user:blocked:%s", userID)
+}
+
+// MarkUserBlocked stores the block until it runs out; blocks without an end
+// are kept until ClearUserBlocked is called.
+func MarkUserBlocked(ctx context.Context, userID string, block UserBlock) error {
+	var ttl time.Duration
+	if block.Until != nil {
+		ttl = time.Until(*block.Until)
+		if ttl <= 0 {
+			return ClearUserBlocked(ctx, userID)
+		}
+	}
+
+	data, err := json.Marshal(block)
+	if err != nil {
+		return err
+	}
+	return CacheClient.Set(ctx, userBlockKey(userID), data, ttl).Err()
+}
+
+func GetUserBlock(ctx context.Context, userID string) (*UserBlock, bool) {
+	if userID == "" || CacheClient == nil {
+		return nil, false
+	}
+	data, err := CacheClient.Get(ctx, userBlockKey(userID)).Bytes()
+	if err != nil {
+		return nil, false
+	}
+
+	var block UserBlock
+	if err := json.Unmarshal(data, &block); err != nil {
+		return nil, false
+	}
+	return &block, true
+}
+
+func ClearUserBlocked(ctx context.Context, userID string) error {
+	return CacheClient.Del(ctx, userBlockKey(userID)).Err()
+}
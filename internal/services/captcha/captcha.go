@@ -0,0 +1,89 @@
+package captcha
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/services/cache"
+)
+
+// A solved captcha is accepted for a short window so one widget session can
+// ask for several estimates without re-solving on every keystroke.
+const (
+	verifiedKeyPrefix = "captcha:verified:"
+	verifiedTTL       = 5 * time.Minute
+)
+
+// Verifier checks tokens against a siteverify-compatible endpoint
+// (reCAPTCHA, hCaptcha and Turnstile all accept the same form).
+type Verifier struct {
+	secret    string
+	verifyURL string
+	client    *http.Client
+}
+
+func NewVerifier(secret, verifyURL string) *Verifier {
+	return &Verifier{
+		secret:    secret,
+		verifyURL: verifyURL,
+		client:    &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+type verifyResponse struct {
+	Success    bool     `json:"success"`
+	ErrorCodes []string `json:"error-codes"`
+}
+
+func (v *Verifier) Verify(ctx context.Context, token, remoteIP string) (bool, error) {
+	if token == "" {
+		return false, nil
+	}
+
+	sum := sha256.Sum256([]byte(token))
+	verifiedKey := verifiedKeyPrefix + hex.EncodeToString(sum[:])
+	if ok, err := cache.Exists(ctx, verifiedKey); err == nil && ok {
+		return true, nil
+	}
+
+	form := url.Values{}
+	form.Set("secret", v.secret)
+	form.Set("response", token)
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("captcha verify returned status %d", resp.StatusCode)
+	}
+
+	var result verifyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, err
+	}
+
+	if result.Success {
+		cache.Set(ctx, verifiedKey, "1", verifiedTTL)
+	}
+
+	return result.Success, nil
+}
@@ -0,0 +1,164 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+const (
+	DriverMemory = "memory"
+	DriverRedis  = "redis"
+	DriverKafka  = "kafka"
+)
+
+var (
+	ErrStarted        = errors.New("event bus already started")
+	ErrClosed         = errors.New("event bus closed")
+	ErrNoEventTypes   = errors.New("subscription needs at least one event type")
+	ErrNoGroup        = errors.New("subscription needs a consumer group")
+	ErrDuplicateGroup = errors.New("consumer group already subscribed")
+)
+
+// Event is a domain event as it travels on the bus. Key orders events: the
+// Kafka driver partitions by it, so events sharing a key are consumed in the
+// order they were published.
+type Event struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Key        string          `json:"key,omitempty"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	Payload    json.RawMessage `json:"payload"`
+}
+
+// Decode unmarshals the payload into v.
+func (e Event) Decode(v interface{}) error {
+	return json.Unmarshal(e.Payload, v)
+}
+
+// Handler consumes an event. A returned error gets the event redelivered to
+// the same handler, up to the configured number of retries.
+type Handler func(ctx context.Context, event Event) error
+
+// Bus carries domain events from the modules that publish them to the
+// modules that react to them. Each consumer group receives every event of
+// the types it subscribed to, independently of other groups; with the Redis
+// and Kafka drivers the processes sharing a group share its events.
+//
+// Subscriptions are registered before Start. Close flushes what the bus still
+// holds for delivery, giving up when ctx is done.
+type Bus interface {
+	Publish(ctx context.Context, eventType, key string, payload interface{}) error
+	Subscribe(group string, handler Handler, eventTypes ...string) error
+	Start(ctx context.Context) error
+	Close(ctx context.Context) error
+}
+
+// New returns the bus selected by cfg.Driver.
+func New(cfg config.EventBusConfig, kafka config.KafkaConfig) (Bus, error) {
+	switch cfg.Driver {
+	case DriverMemory, "":
+		return NewMemoryBus(cfg), nil
+	case DriverRedis:
+		return NewRedisBus(cfg)
+	case DriverKafka:
+		return NewKafkaBus(cfg, kafka.Brokers)
+	default:
+		return nil, fmt.Errorf("unknown event bus driver %q", cfg.Driver)
+	}
+}
+
+func newEvent(eventType, key string, payload interface{}) (Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, fmt.Errorf("marshal %s payload: %w", eventType, err)
+	}
+	return Event{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		Key:        key,
+		OccurredAt: time.Now().UTC(),
+		Payload:    data,
+	}, nil
+}
+
+type subscription struct {
+	group      string
+	handler    Handler
+	eventTypes []string
+}
+
+func (s *subscription) wants(eventType string) bool {
+	for _, t := range s.eventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// subscriptions holds the registrations shared by every driver.
+type subscriptions struct {
+	list    []*subscription
+	started bool
+}
+
+func (s *subscriptions) add(group string, handler Handler, eventTypes []string) error {
+	if s.started {
+		return ErrStarted
+	}
+	if group == "" {
+		return ErrNoGroup
+	}
+	if len(eventTypes) == 0 {
+		return ErrNoEventTypes
+	}
+	for _, existing := range s.list {
+		if existing.group == group {
+			return fmt.Errorf("%w: %s", ErrDuplicateGroup, group)
+		}
+	}
+	s.list = append(s.list, &subscription{group: group, handler: handler, eventTypes: eventTypes})
+	return nil
+}
+
+// dispatch runs the handler, retrying with doubling backoff. It gives up
+// after MaxRetries retries, or when ctx is done, and returns the last error.
+func dispatch(ctx context.Context, cfg config.EventBusConfig, sub *subscription, event Event) error {
+	backoff := cfg.RetryBackoff
+	var err error
+	for attempt := 0; attempt <= cfg.MaxRetries; attempt++ {
+		if err = callHandler(ctx, sub.handler, event); err == nil {
+			return nil
+		}
+		if attempt == cfg.MaxRetries {
+			break
+		}
+		logger.Warn("event handler failed, retrying",
+			"group", sub.group, "eventType", event.Type, "eventID", event.ID, "attempt", attempt+1, "error", err)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	logger.Error("event handler gave up",
+		"group", sub.group, "eventType", event.Type, "eventID", event.ID, "error", err)
+	return err
+}
+
+func callHandler(ctx context.Context, handler Handler, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("event handler panicked: %v", r)
+		}
+	}()
+	return handler(ctx, event)
+}
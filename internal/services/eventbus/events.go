@@ -0,0 +1,67 @@
+package eventbus
+
+import "time"
+
+// Domain event types. Producers publish them with the payload struct of the
+// same name; consumers decode Event.Payload into it.
+const (
+	RideCompleted  = "ride.completed"
+	OrderAssigned  = "order.assigned"
+	OrderCompleted = "order.completed"
+
+	// ProviderMatchingRequested asks for a provider to be found for an
+	// order. It is work rather than news, so exactly one group consumes it.
+	ProviderMatchingRequested = "order.provider_matching_requested"
+)
+
+// RideCompletedPayload is published once a ride is completed and its fare
+// settled. It is keyed by ride ID.
+type RideCompletedPayload struct {
+	RideID        string     `json:"rideId"`
+	RiderID       string     `json:"riderId"`
+	DriverID      string     `json:"driverId"`
+	VehicleTypeID string     `json:"vehicleTypeId"`
+	PickupLat     float64    `json:"pickupLat"`
+	PickupLon     float64    `json:"pickupLon"`
+	Fare          float64    `json:"fare"`
+	TaxAmount     float64    `json:"taxAmount"`
+	Currency      string     `json:"currency"`
+	PaymentMethod string     `json:"paymentMethod"`
+	CompletedAt   *time.Time `json:"completedAt"`
+}
+
+// OrderAssignedPayload is published when a home service order gets a
+// provider, whether the provider accepted it or an admin assigned it. It is
+// keyed by order ID.
+type OrderAssignedPayload struct {
+	OrderID            string    `json:"orderId"`
+	OrderNumber        string    `json:"orderNumber"`
+	CustomerID         string    `json:"customerId"`
+	ProviderID         string    `json:"providerId"`
+	PreviousProviderID string    `json:"previousProviderId,omitempty"`
+	CategorySlug       string    `json:"categorySlug"`
+	Status             string    `json:"status"`
+	TotalPrice         float64   `json:"totalPrice"`
+	Currency           string    `json:"currency"`
+	AssignedBy         string    `json:"assignedBy"`
+	AssignedAt         time.Time `json:"assignedAt"`
+}
+
+// OrderCompletedPayload is published when a home service order is completed
+// and its provider paid. It is keyed by order ID.
+type OrderCompletedPayload struct {
+	OrderID      string     `json:"orderId"`
+	OrderNumber  string     `json:"orderNumber"`
+	CustomerID   string     `json:"customerId"`
+	ProviderID   string     `json:"providerId"`
+	CategorySlug string     `json:"categorySlug"`
+	TotalPrice   float64    `json:"totalPrice"`
+	TaxAmount    float64    `json:"taxAmount"`
+	Currency     string     `json:"currency"`
+	CompletedAt  *time.Time `json:"completedAt"`
+}
+
+// ProviderMatchingRequestedPayload is keyed by order ID.
+type ProviderMatchingRequestedPayload struct {
+	OrderID string `json:"orderId"`
+}
@@ -0,0 +1,173 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+// KafkaBus publishes each event type to the topic "<prefix>.<type>" as a
+// JSON-encoded Event keyed by Event.Key. Each subscription is a Kafka
+// consumer group named "<prefix>.<group>"; offsets are committed once the
+// handler succeeds or its retries are exhausted.
+type KafkaBus struct {
+	cfg     config.EventBusConfig
+	brokers []string
+
+	writersMu sync.Mutex
+	writers   map[string]*kafka.Writer
+
+	mu      sync.Mutex
+	subs    subscriptions
+	readers []*kafka.Reader
+	wg      sync.WaitGroup
+	cancel  context.CancelFunc
+}
+
+func NewKafkaBus(cfg config.EventBusConfig, brokers []string) (*KafkaBus, error) {
+	if len(brokers) == 0 {
+		return nil, errors.New("kafka event bus needs at least one broker")
+	}
+	return &KafkaBus{cfg: cfg, brokers: brokers, writers: make(map[string]*kafka.Writer)}, nil
+}
+
+func (b *KafkaBus) topic(eventType string) string {
+	return b.cfg.Prefix + "." + eventType
+}
+
+func (b *KafkaBus) writer(topic string) *kafka.Writer {
+	b.writersMu.Lock()
+	defer b.writersMu.Unlock()
+
+	if writer, ok := b.writers[topic]; ok {
+		return writer
+	}
+	writer := &kafka.Writer{
+		Addr:                   kafka.TCP(b.brokers...),
+		Topic:                  topic,
+		Balancer:               &kafka.Hash{},
+		RequiredAcks:           kafka.RequireAll,
+		BatchTimeout:           10 * time.Millisecond,
+		AllowAutoTopicCreation: true,
+	}
+	b.writers[topic] = writer
+	return writer
+}
+
+func (b *KafkaBus) Publish(ctx context.Context, eventType, key string, payload interface{}) error {
+	event, err := newEvent(eventType, key, payload)
+	if err != nil {
+		return err
+	}
+	value, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal %s: %w", eventType, err)
+	}
+
+	message := kafka.Message{Key: []byte(key), Value: value}
+	if err := b.writer(b.topic(eventType)).WriteMessages(ctx, message); err != nil {
+		return fmt.Errorf("publish %s: %w", eventType, err)
+	}
+	return nil
+}
+
+func (b *KafkaBus) Subscribe(group string, handler Handler, eventTypes ...string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.subs.add(group, handler, eventTypes)
+}
+
+func (b *KafkaBus) Start(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subs.started {
+		return ErrStarted
+	}
+	b.subs.started = true
+
+	ctx, b.cancel = context.WithCancel(ctx)
+	for _, sub := range b.subs.list {
+		topics := make([]string, 0, len(sub.eventTypes))
+		for _, eventType := range sub.eventTypes {
+			topics = append(topics, b.topic(eventType))
+		}
+		reader := kafka.NewReader(kafka.ReaderConfig{
+			Brokers:     b.brokers,
+			GroupID:     b.cfg.Prefix + "." + sub.group,
+			GroupTopics: topics,
+			MaxWait:     500 * time.Millisecond,
+			StartOffset: kafka.LastOffset,
+		})
+		b.readers = append(b.readers, reader)
+
+		b.wg.Add(1)
+		go b.consume(ctx, sub, reader)
+	}
+	return nil
+}
+
+func (b *KafkaBus) consume(ctx context.Context, sub *subscription, reader *kafka.Reader) {
+	defer b.wg.Done()
+
+	for {
+		message, err := reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Error("failed to fetch event", "group", sub.group, "error", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		var event Event
+		if err := json.Unmarshal(message.Value, &event); err != nil {
+			logger.Error("dropping malformed event", "topic", message.Topic, "offset", message.Offset, "error", err)
+		} else {
+			dispatch(ctx, b.cfg, sub, event)
+			if ctx.Err() != nil {
+				// Not committed, so the group redelivers it.
+				return
+			}
+		}
+
+		if err := reader.CommitMessages(ctx, message); err != nil && ctx.Err() == nil {
+			logger.Warn("failed to commit event offset", "topic", message.Topic, "offset", message.Offset, "error", err)
+		}
+	}
+}
+
+// Close stops the consumers and closes the writers, which flushes any batch
+// they still buffer.
+func (b *KafkaBus) Close(ctx context.Context) error {
+	if b.cancel != nil {
+		b.cancel()
+	}
+	b.wg.Wait()
+
+	var errs []error
+	for _, reader := range b.readers {
+		if err := reader.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	b.writersMu.Lock()
+	for _, writer := range b.writers {
+		if err := writer.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	b.writersMu.Unlock()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,139 @@
+package eventbus
+
+import (
+	"context"
+	"sync"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+const memoryBufferSize = 1024
+
+// MemoryBus delivers events within the process. Every consumer group has a
+// buffered queue and a goroutine draining it, so a slow consumer does not
+// hold up the others; Publish only blocks when a group's queue is full.
+// Close delivers what is still queued until its context is done; events
+// left after that, or queued when the process dies, are lost.
+type MemoryBus struct {
+	cfg    config.EventBusConfig
+	mu     sync.RWMutex
+	subs   subscriptions
+	queues map[*subscription]chan Event
+	closed bool
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+}
+
+func NewMemoryBus(cfg config.EventBusConfig) *MemoryBus {
+	return &MemoryBus{cfg: cfg, queues: make(map[*subscription]chan Event)}
+}
+
+func (b *MemoryBus) Publish(ctx context.Context, eventType, key string, payload interface{}) error {
+	event, err := newEvent(eventType, key, payload)
+	if err != nil {
+		return err
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return ErrClosed
+	}
+	for _, sub := range b.subs.list {
+		if !sub.wants(eventType) {
+			continue
+		}
+		select {
+		case b.queues[sub] <- event:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (b *MemoryBus) Subscribe(group string, handler Handler, eventTypes ...string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err := b.subs.add(group, handler, eventTypes); err != nil {
+		return err
+	}
+	sub := b.subs.list[len(b.subs.list)-1]
+	b.queues[sub] = make(chan Event, memoryBufferSize)
+	return nil
+}
+
+func (b *MemoryBus) Start(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subs.started {
+		return ErrStarted
+	}
+	b.subs.started = true
+
+	ctx, b.cancel = context.WithCancel(ctx)
+	for _, sub := range b.subs.list {
+		b.wg.Add(1)
+		go b.consume(ctx, sub, b.queues[sub])
+	}
+	return nil
+}
+
+func (b *MemoryBus) consume(ctx context.Context, sub *subscription, queue <-chan Event) {
+	defer b.wg.Done()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-queue:
+			if !ok || ctx.Err() != nil {
+				return
+			}
+			dispatch(ctx, b.cfg, sub, event)
+		}
+	}
+}
+
+// Close stops accepting events and waits for the queued ones to be handled,
+// or for ctx to be done.
+func (b *MemoryBus) Close(ctx context.Context) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	for _, queue := range b.queues {
+		close(queue)
+	}
+	b.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	if b.cancel != nil {
+		b.cancel()
+	}
+	<-done
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	dropped := 0
+	for _, queue := range b.queues {
+		dropped += len(queue)
+	}
+	if dropped > 0 {
+		logger.Warn("event bus closed with events still queued", "dropped", dropped)
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,228 @@
+package eventbus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+const (
+	redisReadCount = 50
+	redisReadBlock = 2 * time.Second
+	// Entries read by a consumer that stopped acknowledging them are taken
+	// over by another member of the group once they have been idle this long.
+	redisClaimIdle     = 5 * time.Minute
+	redisClaimInterval = time.Minute
+)
+
+// RedisBus keeps one stream per event type, named "<prefix>:<type>", and one
+// Redis consumer group per subscription. Entries are acknowledged once the
+// handler succeeds or its retries are exhausted; entries left unacknowledged
+// by a crashed process are reclaimed by the group's other consumers.
+type RedisBus struct {
+	cfg    config.EventBusConfig
+	client *redis.Client
+	mu     sync.Mutex
+	subs   subscriptions
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+}
+
+func NewRedisBus(cfg config.EventBusConfig) (*RedisBus, error) {
+	if cache.MainClient == nil {
+		return nil, errors.New("redis event bus needs a redis connection")
+	}
+	return &RedisBus{cfg: cfg, client: cache.MainClient}, nil
+}
+
+func (b *RedisBus) stream(eventType string) string {
+	return b.cfg.Prefix + ":" + eventType
+}
+
+func (b *RedisBus) Publish(ctx context.Context, eventType, key string, payload interface{}) error {
+	event, err := newEvent(eventType, key, payload)
+	if err != nil {
+		return err
+	}
+
+	args := &redis.XAddArgs{
+		Stream: b.stream(eventType),
+		Values: map[string]interface{}{
+			"id":         event.ID,
+			"type":       event.Type,
+			"key":        event.Key,
+			"occurredAt": event.OccurredAt.Format(time.RFC3339Nano),
+			"payload":    string(event.Payload),
+		},
+	}
+	if b.cfg.StreamMaxLen > 0 {
+		args.MaxLen = b.cfg.StreamMaxLen
+		args.Approx = true
+	}
+	if err := b.client.XAdd(ctx, args).Err(); err != nil {
+		return fmt.Errorf("publish %s: %w", eventType, err)
+	}
+	return nil
+}
+
+func (b *RedisBus) Subscribe(group string, handler Handler, eventTypes ...string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.subs.add(group, handler, eventTypes)
+}
+
+func (b *RedisBus) Start(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subs.started {
+		return ErrStarted
+	}
+
+	group := func(sub *subscription) string { return b.cfg.Prefix + ":" + sub.group }
+	for _, sub := range b.subs.list {
+		for _, eventType := range sub.eventTypes {
+			// "$" starts a new group at the end of the stream: events published
+			// before the first subscription are not replayed.
+			err := b.client.XGroupCreateMkStream(ctx, b.stream(eventType), group(sub), "$").Err()
+			if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+				return fmt.Errorf("create consumer group %s on %s: %w", sub.group, eventType, err)
+			}
+		}
+	}
+	b.subs.started = true
+
+	ctx, b.cancel = context.WithCancel(ctx)
+	for _, sub := range b.subs.list {
+		b.wg.Add(1)
+		go b.consume(ctx, sub, group(sub))
+	}
+	return nil
+}
+
+func (b *RedisBus) consume(ctx context.Context, sub *subscription, group string) {
+	defer b.wg.Done()
+
+	streams := make([]string, 0, len(sub.eventTypes)*2)
+	for _, eventType := range sub.eventTypes {
+		streams = append(streams, b.stream(eventType))
+	}
+	for range sub.eventTypes {
+		streams = append(streams, ">")
+	}
+
+	lastClaim := time.Time{}
+	for ctx.Err() == nil {
+		if time.Since(lastClaim) >= redisClaimInterval {
+			b.reclaim(ctx, sub, group)
+			lastClaim = time.Now()
+		}
+
+		result, err := b.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    group,
+			Consumer: b.cfg.ConsumerName,
+			Streams:  streams,
+			Count:    redisReadCount,
+			Block:    redisReadBlock,
+		}).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) || ctx.Err() != nil {
+				continue
+			}
+			logger.Error("failed to read event stream", "group", sub.group, "error", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		for _, stream := range result {
+			for _, message := range stream.Messages {
+				b.handle(ctx, sub, group, stream.Stream, message)
+			}
+		}
+	}
+}
+
+func (b *RedisBus) reclaim(ctx context.Context, sub *subscription, group string) {
+	for _, eventType := range sub.eventTypes {
+		stream := b.stream(eventType)
+		messages, _, err := b.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   stream,
+			Group:    group,
+			Consumer: b.cfg.ConsumerName,
+			MinIdle:  redisClaimIdle,
+			Start:    "0-0",
+			Count:    redisReadCount,
+		}).Result()
+		if err != nil {
+			if ctx.Err() == nil {
+				logger.Warn("failed to reclaim pending events", "group", sub.group, "stream", stream, "error", err)
+			}
+			continue
+		}
+		for _, message := range messages {
+			b.handle(ctx, sub, group, stream, message)
+		}
+	}
+}
+
+func (b *RedisBus) handle(ctx context.Context, sub *subscription, group, stream string, message redis.XMessage) {
+	event, err := decodeRedisMessage(message)
+	if err != nil {
+		logger.Error("dropping malformed event", "stream", stream, "messageID", message.ID, "error", err)
+	} else {
+		dispatch(ctx, b.cfg, sub, event)
+		if ctx.Err() != nil {
+			// Left pending so another consumer, or this one after a restart,
+			// picks it up.
+			return
+		}
+	}
+	if err := b.client.XAck(ctx, stream, group, message.ID).Err(); err != nil {
+		logger.Warn("failed to acknowledge event", "stream", stream, "messageID", message.ID, "error", err)
+	}
+}
+
+func decodeRedisMessage(message redis.XMessage) (Event, error) {
+	field := func(name string) string {
+		value, _ := message.Values[name].(string)
+		return value
+	}
+
+	event := Event{
+		ID:      field("id"),
+		Type:    field("type"),
+		Key:     field("key"),
+		Payload: json.RawMessage(field("payload")),
+	}
+	if event.Type == "" || len(event.Payload) == 0 {
+		return Event{}, errors.New("missing type or payload")
+	}
+	occurredAt, err := time.Parse(time.RFC3339Nano, field("occurredAt"))
+	if err != nil {
+		return Event{}, fmt.Errorf("parse occurredAt: %w", err)
+	}
+	event.OccurredAt = occurredAt
+	return event, nil
+}
+
+// Close stops the consumers. Published events are already durable in their
+// streams and entries being handled are left pending for redelivery, so there
+// is nothing to flush.
+func (b *RedisBus) Close(ctx context.Context) error {
+	if b.cancel != nil {
+		b.cancel()
+	}
+	b.wg.Wait()
+	return nil
+}
@@ -0,0 +1,208 @@
+package geocoding
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/utils/location"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+const (
+	ProviderNone      = "none"
+	ProviderGoogle    = "google"
+	ProviderMapbox    = "mapbox"
+	ProviderNominatim = "nominatim"
+
+	searchCacheKeyPrefix  = "geocoding:search:"
+	reverseCacheKeyPrefix = "geocoding:reverse:"
+
+	DefaultSearchLimit = 5
+	MaxSearchLimit     = 10
+)
+
+var (
+	ErrNotConfigured = errors.New("geocoding is not configured")
+	ErrNoResults     = errors.New("no places found")
+)
+
+// Place is a geocoding match. FormattedAddress is the provider's canonical
+// address string, which is what addresses are normalized to.
+type Place struct {
+	PlaceID          string  `json:"placeId"`
+	Name             string  `json:"name,omitempty"`
+	FormattedAddress string  `json:"formattedAddress"`
+	Latitude         float64 `json:"latitude"`
+	Longitude        float64 `json:"longitude"`
+	Source           string  `json:"source"`
+}
+
+// SearchQuery is a forward geocoding lookup. Near, when set, biases results
+// towards that point.
+type SearchQuery struct {
+	Text     string
+	Near     *location.Point
+	Limit    int
+	Language string
+}
+
+// Provider is a geocoding backend.
+type Provider interface {
+	Name() string
+	Search(ctx context.Context, query SearchQuery, countries []string) ([]Place, error)
+	Reverse(ctx context.Context, point location.Point, language string) (*Place, error)
+}
+
+// Service geocodes through the configured provider and caches the results.
+// Without a provider every lookup fails with ErrNotConfigured, so a nil
+// *Service is usable too.
+type Service struct {
+	provider  Provider
+	countries []string
+	language  string
+	cacheTTL  time.Duration
+}
+
+func NewService(cfg config.GeocodingConfig) *Service {
+	client := &http.Client{Timeout: cfg.Timeout}
+
+	var provider Provider
+	switch cfg.Provider {
+	case ProviderGoogle:
+		provider = NewGoogleProvider(cfg.GoogleAPIKey, cfg.GoogleAPIURL, client)
+	case ProviderMapbox:
+		provider = NewMapboxProvider(cfg.MapboxToken, cfg.MapboxURL, client)
+	case ProviderNominatim:
+		provider = NewNominatimProvider(cfg.NominatimURL, cfg.UserAgent, client)
+	}
+
+	return &Service{
+		provider:  provider,
+		countries: cfg.Countries,
+		language:  cfg.Language,
+		cacheTTL:  cfg.CacheTTL,
+	}
+}
+
+func (s *Service) Enabled() bool {
+	return s != nil && s.provider != nil
+}
+
+// Search returns the places matching a free-text query, best match first.
+func (s *Service) Search(ctx context.Context, query SearchQuery) ([]Place, error) {
+	if !s.Enabled() {
+		return nil, ErrNotConfigured
+	}
+
+	query.Text = strings.TrimSpace(query.Text)
+	if query.Limit <= 0 {
+		query.Limit = DefaultSearchLimit
+	}
+	if query.Limit > MaxSearchLimit {
+		query.Limit = MaxSearchLimit
+	}
+	if query.Language == "" {
+		query.Language = s.language
+	}
+
+	key := s.searchCacheKey(query)
+	var cached []Place
+	if err := cache.GetJSON(ctx, key, &cached); err == nil && cached != nil {
+		return cached, nil
+	}
+
+	places, err := s.provider.Search(ctx, query, s.countries)
+	if err != nil {
+		if !errors.Is(err, ErrNoResults) {
+			logger.Warn("geocoding search failed", "error", err, "provider", s.provider.Name())
+		}
+		return nil, err
+	}
+
+	s.store(ctx, key, places)
+	return places, nil
+}
+
+// Reverse returns the address at a coordinate.
+func (s *Service) Reverse(ctx context.Context, lat, lon float64, language string) (*Place, error) {
+	if !s.Enabled() {
+		return nil, ErrNotConfigured
+	}
+	if language == "" {
+		language = s.language
+	}
+
+	point := location.Point{Latitude: lat, Longitude: lon}
+	key := fmt.Sprintf("%s%s:%s:%.4f,%.4f", reverseCacheKeyPrefix, s.provider.Name(), language, lat, lon)
+
+	var cached Place
+	if err := cache.GetJSON(ctx, key, &cached); err == nil && cached.FormattedAddress != "" {
+		return &cached, nil
+	}
+
+	place, err := s.provider.Reverse(ctx, point, language)
+	if err != nil {
+		if !errors.Is(err, ErrNoResults) {
+			logger.Warn("reverse geocoding failed", "error", err, "provider", s.provider.Name())
+		}
+		return nil, err
+	}
+
+	s.store(ctx, key, place)
+	return place, nil
+}
+
+// Geocode returns the best match for an address.
+func (s *Service) Geocode(ctx context.Context, address string) (*Place, error) {
+	places, err := s.Search(ctx, SearchQuery{Text: address, Limit: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(places) == 0 {
+		return nil, ErrNoResults
+	}
+	return &places[0], nil
+}
+
+// NormalizeAddress returns the provider's address string for a coordinate, so
+// orders and rides store consistent addresses whatever the client sent. The
+// given address is kept when the lookup fails.
+func (s *Service) NormalizeAddress(ctx context.Context, address string, lat, lon float64) string {
+	if !s.Enabled() {
+		return address
+	}
+	place, err := s.Reverse(ctx, lat, lon, "")
+	if err != nil || place.FormattedAddress == "" {
+		return address
+	}
+	return place.FormattedAddress
+}
+
+func (s *Service) store(ctx context.Context, key string, value interface{}) {
+	if s.cacheTTL <= 0 {
+		return
+	}
+	if err := cache.SetJSON(ctx, key, value, s.cacheTTL); err != nil {
+		logger.Warn("failed to cache geocoding result", "error", err, "provider", s.provider.Name())
+	}
+}
+
+// searchCacheKey hashes the normalized query so keys stay short, and rounds
+// the bias point to two decimals (about 1 km) so nearby users share entries.
+func (s *Service) searchCacheKey(query SearchQuery) string {
+	near := "-"
+	if query.Near != nil {
+		near = fmt.Sprintf("%.2f,%.2f", query.Near.Latitude, query.Near.Longitude)
+	}
+	sum := sha1.Sum([]byte(strings.ToLower(query.Text)))
+	return fmt.Sprintf("%s%s:%s:%s:%d:%s", searchCacheKeyPrefix, s.provider.Name(),
+		query.Language, near, query.Limit, hex.EncodeToString(sum[:]))
+}
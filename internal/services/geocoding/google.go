@@ -0,0 +1,132 @@
+package geocoding
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/umar5678/go-backend/internal/utils/location"
+)
+
+// GoogleProvider queries the Google Geocoding API.
+type GoogleProvider struct {
+	apiKey  string
+	baseURL string
+	client  *http.Client
+}
+
+func NewGoogleProvider(apiKey, baseURL string, client *http.Client) *GoogleProvider {
+	if baseURL == "" {
+		baseURL = "https://maps.googleapis.com"
+	}
+	return &GoogleProvider{apiKey: apiKey, baseURL: strings.TrimSuffix(baseURL, "/"), client: client}
+}
+
+func (p *GoogleProvider) Name() string {
+	return ProviderGoogle
+}
+
+type googleGeocodeResponse struct {
+	Status       string `json:"status"`
+	ErrorMessage string `json:"error_message"`
+	Results      []struct {
+		PlaceID          string `json:"place_id"`
+		FormattedAddress string `json:"formatted_address"`
+		Geometry         struct {
+			Location struct {
+				Lat float64 `json:"lat"`
+				Lng float64 `json:"lng"`
+			} `json:"location"`
+		} `json:"geometry"`
+	} `json:"results"`
+}
+
+func (p *GoogleProvider) Search(ctx context.Context, query SearchQuery, countries []string) ([]Place, error) {
+	params := url.Values{}
+	params.Set("address", query.Text)
+	if query.Near != nil {
+		// Bias towards a box of about 50 km around the point.
+		params.Set("bounds", fmt.Sprintf("%f,%f|%f,%f",
+			query.Near.Latitude-0.25, query.Near.Longitude-0.25,
+			query.Near.Latitude+0.25, query.Near.Longitude+0.25))
+	}
+	if len(countries) > 0 {
+		components := make([]string, len(countries))
+		for i, country := range countries {
+			components[i] = "country:" + country
+		}
+		params.Set("components", strings.Join(components, "|"))
+	}
+
+	places, err := p.geocode(ctx, params, query.Language)
+	if err != nil {
+		return nil, err
+	}
+	if len(places) > query.Limit {
+		places = places[:query.Limit]
+	}
+	return places, nil
+}
+
+func (p *GoogleProvider) Reverse(ctx context.Context, point location.Point, language string) (*Place, error) {
+	params := url.Values{}
+	params.Set("latlng", fmt.Sprintf("%f,%f", point.Latitude, point.Longitude))
+
+	places, err := p.geocode(ctx, params, language)
+	if err != nil {
+		return nil, err
+	}
+	return &places[0], nil
+}
+
+func (p *GoogleProvider) geocode(ctx context.Context, params url.Values, language string) ([]Place, error) {
+	if p.apiKey == "" {
+		return nil, errors.New("google geocoding api key is not configured")
+	}
+	if language != "" {
+		params.Set("language", language)
+	}
+	params.Set("key", p.apiKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/maps/api/geocode/json?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("google geocoding returned status %d", resp.StatusCode)
+	}
+
+	var result googleGeocodeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if result.Status == "ZERO_RESULTS" || (result.Status == "OK" && len(result.Results) == 0) {
+		return nil, ErrNoResults
+	}
+	if result.Status != "OK" {
+		return nil, fmt.Errorf("google geocoding returned %s: %s", result.Status, result.ErrorMessage)
+	}
+
+	places := make([]Place, len(result.Results))
+	for i, r := range result.Results {
+		places[i] = Place{
+			PlaceID:          r.PlaceID,
+			FormattedAddress: r.FormattedAddress,
+			Latitude:         r.Geometry.Location.Lat,
+			Longitude:        r.Geometry.Location.Lng,
+			Source:           ProviderGoogle,
+		}
+	}
+	return places, nil
+}
@@ -0,0 +1,118 @@
+package geocoding
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/umar5678/go-backend/internal/utils/location"
+)
+
+// MapboxProvider queries the Mapbox Geocoding API.
+type MapboxProvider struct {
+	token   string
+	baseURL string
+	client  *http.Client
+}
+
+func NewMapboxProvider(token, baseURL string, client *http.Client) *MapboxProvider {
+	if baseURL == "" {
+		baseURL = "https://api.mapbox.com"
+	}
+	return &MapboxProvider{token: token, baseURL: strings.TrimSuffix(baseURL, "/"), client: client}
+}
+
+func (p *MapboxProvider) Name() string {
+	return ProviderMapbox
+}
+
+type mapboxResponse struct {
+	Message  string `json:"message"`
+	Features []struct {
+		ID        string    `json:"id"`
+		Text      string    `json:"text"`
+		PlaceName string    `json:"place_name"`
+		Center    []float64 `json:"center"`
+	} `json:"features"`
+}
+
+func (p *MapboxProvider) Search(ctx context.Context, query SearchQuery, countries []string) ([]Place, error) {
+	params := url.Values{}
+	params.Set("limit", strconv.Itoa(query.Limit))
+	params.Set("autocomplete", "true")
+	if query.Near != nil {
+		// Mapbox takes coordinates as lon,lat.
+		params.Set("proximity", fmt.Sprintf("%f,%f", query.Near.Longitude, query.Near.Latitude))
+	}
+	if len(countries) > 0 {
+		params.Set("country", strings.Join(countries, ","))
+	}
+	return p.geocode(ctx, url.PathEscape(query.Text), params, query.Language)
+}
+
+func (p *MapboxProvider) Reverse(ctx context.Context, point location.Point, language string) (*Place, error) {
+	params := url.Values{}
+	params.Set("limit", "1")
+	places, err := p.geocode(ctx, fmt.Sprintf("%f,%f", point.Longitude, point.Latitude), params, language)
+	if err != nil {
+		return nil, err
+	}
+	return &places[0], nil
+}
+
+func (p *MapboxProvider) geocode(ctx context.Context, search string, params url.Values, language string) ([]Place, error) {
+	if p.token == "" {
+		return nil, errors.New("mapbox access token is not configured")
+	}
+	if language != "" {
+		params.Set("language", language)
+	}
+	params.Set("access_token", p.token)
+
+	endpoint := fmt.Sprintf("%s/geocoding/v5/mapbox.places/%s.json?%s", p.baseURL, search, params.Encode())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result mapboxResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("mapbox geocoding returned status %d: %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("mapbox geocoding returned status %d: %s", resp.StatusCode, result.Message)
+	}
+	if len(result.Features) == 0 {
+		return nil, ErrNoResults
+	}
+
+	places := make([]Place, 0, len(result.Features))
+	for _, f := range result.Features {
+		if len(f.Center) != 2 {
+			continue
+		}
+		places = append(places, Place{
+			PlaceID:          f.ID,
+			Name:             f.Text,
+			FormattedAddress: f.PlaceName,
+			Latitude:         f.Center[1],
+			Longitude:        f.Center[0],
+			Source:           ProviderMapbox,
+		})
+	}
+	if len(places) == 0 {
+		return nil, ErrNoResults
+	}
+	return places, nil
+}
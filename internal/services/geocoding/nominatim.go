@@ -0,0 +1,144 @@
+package geocoding
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/umar5678/go-backend/internal/utils/location"
+)
+
+// NominatimProvider queries an OpenStreetMap Nominatim server. The public
+// server requires an identifying User-Agent and allows one request a second,
+// so production use should point at a self-hosted instance.
+type NominatimProvider struct {
+	baseURL   string
+	userAgent string
+	client    *http.Client
+}
+
+func NewNominatimProvider(baseURL, userAgent string, client *http.Client) *NominatimProvider {
+	if baseURL == "" {
+		baseURL = "https://nominatim.openstreetmap.org"
+	}
+	if userAgent == "" {
+		userAgent = "go-backend"
+	}
+	return &NominatimProvider{baseURL: strings.TrimSuffix(baseURL, "/"), userAgent: userAgent, client: client}
+}
+
+func (p *NominatimProvider) Name() string {
+	return ProviderNominatim
+}
+
+type nominatimPlace struct {
+	PlaceID     int64  `json:"place_id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	Lat         string `json:"lat"`
+	Lon         string `json:"lon"`
+	Error       string `json:"error"`
+}
+
+func (p nominatimPlace) toPlace() (Place, error) {
+	lat, err := strconv.ParseFloat(p.Lat, 64)
+	if err != nil {
+		return Place{}, fmt.Errorf("invalid latitude %q: %w", p.Lat, err)
+	}
+	lon, err := strconv.ParseFloat(p.Lon, 64)
+	if err != nil {
+		return Place{}, fmt.Errorf("invalid longitude %q: %w", p.Lon, err)
+	}
+	return Place{
+		PlaceID:          strconv.FormatInt(p.PlaceID, 10),
+		Name:             p.Name,
+		FormattedAddress: p.DisplayName,
+		Latitude:         lat,
+		Longitude:        lon,
+		Source:           ProviderNominatim,
+	}, nil
+}
+
+func (p *NominatimProvider) Search(ctx context.Context, query SearchQuery, countries []string) ([]Place, error) {
+	params := url.Values{}
+	params.Set("q", query.Text)
+	params.Set("limit", strconv.Itoa(query.Limit))
+	if query.Near != nil {
+		// viewbox is left,top,right,bottom; without bounded=1 it only biases.
+		params.Set("viewbox", fmt.Sprintf("%f,%f,%f,%f",
+			query.Near.Longitude-0.25, query.Near.Latitude+0.25,
+			query.Near.Longitude+0.25, query.Near.Latitude-0.25))
+	}
+	if len(countries) > 0 {
+		params.Set("countrycodes", strings.Join(countries, ","))
+	}
+
+	var results []nominatimPlace
+	if err := p.get(ctx, "/search", params, query.Language, &results); err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, ErrNoResults
+	}
+
+	places := make([]Place, 0, len(results))
+	for _, r := range results {
+		place, err := r.toPlace()
+		if err != nil {
+			continue
+		}
+		places = append(places, place)
+	}
+	if len(places) == 0 {
+		return nil, ErrNoResults
+	}
+	return places, nil
+}
+
+func (p *NominatimProvider) Reverse(ctx context.Context, point location.Point, language string) (*Place, error) {
+	params := url.Values{}
+	params.Set("lat", strconv.FormatFloat(point.Latitude, 'f', 6, 64))
+	params.Set("lon", strconv.FormatFloat(point.Longitude, 'f', 6, 64))
+
+	var result nominatimPlace
+	if err := p.get(ctx, "/reverse", params, language, &result); err != nil {
+		return nil, err
+	}
+	if result.Error != "" || result.DisplayName == "" {
+		return nil, ErrNoResults
+	}
+
+	place, err := result.toPlace()
+	if err != nil {
+		return nil, err
+	}
+	return &place, nil
+}
+
+func (p *NominatimProvider) get(ctx context.Context, path string, params url.Values, language string, dest interface{}) error {
+	params.Set("format", "jsonv2")
+	if language != "" {
+		params.Set("accept-language", language)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", p.userAgent)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("nominatim returned status %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(dest)
+}
@@ -0,0 +1,55 @@
+package health
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"github.com/umar5678/go-backend/internal/services/cache"
+)
+
+// Database pings Postgres and reports the connection pool.
+func Database(db *gorm.DB) Check {
+	return Check{
+		Name:     "database",
+		Critical: true,
+		Run: func(ctx context.Context) (interface{}, error) {
+			if db == nil {
+				return nil, errors.New("not connected")
+			}
+			sqlDB, err := db.DB()
+			if err != nil {
+				return nil, err
+			}
+			if err := sqlDB.PingContext(ctx); err != nil {
+				return nil, err
+			}
+			stats := sqlDB.Stats()
+			return map[string]interface{}{
+				"openConnections": stats.OpenConnections,
+				"inUse":           stats.InUse,
+				"idle":            stats.Idle,
+				"maxOpen":         stats.MaxOpenConnections,
+				"waitCount":       stats.WaitCount,
+			}, nil
+		},
+	}
+}
+
+// Redis pings the main and pub/sub clients.
+func Redis() Check {
+	return Check{
+		Name:     "redis",
+		Critical: true,
+		Run: func(ctx context.Context) (interface{}, error) {
+			if cache.MainClient == nil || cache.PubSubClient == nil {
+				return nil, errors.New("not connected")
+			}
+			if err := cache.HealthCheck(ctx); err != nil {
+				return nil, err
+			}
+			return nil, nil
+		},
+	}
+}
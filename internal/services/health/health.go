@@ -0,0 +1,220 @@
+// Package health serves the liveness, readiness and diagnostic endpoints of
+// the API and worker processes.
+//
+// Liveness only says the process is up and serving; it never looks at
+// dependencies, so an outage of Postgres or Redis does not get every pod
+// restarted. Readiness runs the critical checks and fails while the process
+// is draining, so the load balancer stops sending traffic. The details
+// endpoint runs every check and includes the startup report.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/startup"
+)
+
+type Status string
+
+const (
+	StatusUp       Status = "up"
+	StatusDegraded Status = "degraded"
+	StatusDown     Status = "down"
+	StatusDraining Status = "draining"
+)
+
+const checkTimeout = 2 * time.Second
+
+// ErrDegraded marks a check result as degraded rather than down: the
+// dependency works but something about it needs attention.
+var ErrDegraded = errors.New("degraded")
+
+// CheckFunc probes a dependency. It returns details worth showing, which may
+// be nil, and an error when the dependency is unusable; wrap ErrDegraded for
+// partial failures.
+type CheckFunc func(ctx context.Context) (interface{}, error)
+
+type Check struct {
+	Name string
+	// Critical checks gate readiness. The others only show in the details.
+	Critical bool
+	Run      CheckFunc
+}
+
+type Result struct {
+	Status    Status      `json:"status"`
+	Critical  bool        `json:"critical"`
+	LatencyMs int64       `json:"latencyMs"`
+	Error     string      `json:"error,omitempty"`
+	Details   interface{} `json:"details,omitempty"`
+}
+
+type Report struct {
+	Status  Status            `json:"status"`
+	Time    time.Time         `json:"time"`
+	Uptime  string            `json:"uptime"`
+	Checks  map[string]Result `json:"checks,omitempty"`
+	Startup *startup.Report   `json:"startup,omitempty"`
+}
+
+// Checker runs the registered checks for the health endpoints.
+type Checker struct {
+	orchestrator *startup.Orchestrator
+	startedAt    time.Time
+	draining     atomic.Bool
+
+	mu     sync.RWMutex
+	checks []Check
+}
+
+func NewChecker(orchestrator *startup.Orchestrator) *Checker {
+	return &Checker{orchestrator: orchestrator, startedAt: time.Now()}
+}
+
+func (c *Checker) Add(check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, check)
+}
+
+// Drain fails readiness from now on, ahead of a graceful shutdown.
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+func (c *Checker) Draining() bool {
+	return c.draining.Load()
+}
+
+// Run executes the checks concurrently, only the critical ones when
+// criticalOnly is set, and sums them up.
+func (c *Checker) Run(ctx context.Context, criticalOnly bool) Report {
+	c.mu.RLock()
+	checks := make([]Check, 0, len(c.checks))
+	for _, check := range c.checks {
+		if check.Critical || !criticalOnly {
+			checks = append(checks, check)
+		}
+	}
+	c.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			results[i] = runCheck(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := Report{
+		Status: StatusUp,
+		Time:   time.Now().UTC(),
+		Uptime: time.Since(c.startedAt).Round(time.Second).String(),
+		Checks: make(map[string]Result, len(checks)),
+	}
+	for i, check := range checks {
+		result := results[i]
+		report.Checks[check.Name] = result
+		switch {
+		case result.Status == StatusDown && check.Critical:
+			report.Status = StatusDown
+		case result.Status != StatusUp && report.Status == StatusUp:
+			report.Status = StatusDegraded
+		}
+	}
+
+	if c.orchestrator != nil {
+		startupReport := c.orchestrator.Report()
+		report.Startup = &startupReport
+		if startupReport.Status == startup.StatusFailed {
+			report.Status = StatusDown
+		}
+	}
+	if c.Draining() {
+		report.Status = StatusDraining
+	}
+	return report
+}
+
+func runCheck(ctx context.Context, check Check) (result Result) {
+	started := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			result = Result{Status: StatusDown, Error: "check panicked"}
+		}
+		result.Critical = check.Critical
+		result.LatencyMs = time.Since(started).Milliseconds()
+	}()
+
+	details, err := check.Run(ctx)
+	result = Result{Status: StatusUp, Details: details}
+	if err != nil {
+		result.Status = StatusDown
+		if errors.Is(err, ErrDegraded) {
+			result.Status = StatusDegraded
+		}
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// LiveHandler answers the liveness probe without touching any dependency.
+func (c *Checker) LiveHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, Report{
+			Status: StatusUp,
+			Time:   time.Now().UTC(),
+			Uptime: time.Since(c.startedAt).Round(time.Second).String(),
+		})
+	})
+}
+
+// ReadyHandler answers the readiness probe: 503 while draining, after a
+// failed startup or when a critical check is down.
+func (c *Checker) ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if c.Draining() {
+			writeJSON(w, http.StatusServiceUnavailable, Report{Status: StatusDraining, Time: time.Now().UTC()})
+			return
+		}
+
+		report := c.Run(r.Context(), true)
+		report.Startup = nil
+		writeJSON(w, statusCode(report), report)
+	})
+}
+
+// DetailsHandler runs every check. Non-critical failures show as degraded
+// without failing the response.
+func (c *Checker) DetailsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := c.Run(r.Context(), false)
+		writeJSON(w, statusCode(report), report)
+	})
+}
+
+func statusCode(report Report) int {
+	if report.Status == StatusDown || report.Status == StatusDraining {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,98 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// A loop is stale once it has missed this many beats.
+const staleAfterBeats = 3
+
+// Heartbeat records that a background loop is still making progress. Loops
+// beat after each run, so one stuck in its work goes stale as well as one
+// that died.
+type Heartbeat struct {
+	name     string
+	interval time.Duration
+}
+
+type beat struct {
+	interval time.Duration
+	last     time.Time
+	runs     int64
+}
+
+var heartbeats = struct {
+	mu    sync.RWMutex
+	beats map[string]*beat
+}{beats: make(map[string]*beat)}
+
+// NewHeartbeat registers a loop that runs every interval. It counts as fresh
+// until its first interval has passed.
+func NewHeartbeat(name string, interval time.Duration) *Heartbeat {
+	heartbeats.mu.Lock()
+	defer heartbeats.mu.Unlock()
+	heartbeats.beats[name] = &beat{interval: interval, last: time.Now()}
+	return &Heartbeat{name: name, interval: interval}
+}
+
+func (h *Heartbeat) Beat() {
+	heartbeats.mu.Lock()
+	defer heartbeats.mu.Unlock()
+	if b, ok := heartbeats.beats[h.name]; ok {
+		b.last = time.Now()
+		b.runs++
+	}
+}
+
+type LoopStatus struct {
+	Name       string    `json:"name"`
+	Interval   string    `json:"interval"`
+	LastBeatAt time.Time `json:"lastBeatAt"`
+	Runs       int64     `json:"runs"`
+	Stale      bool      `json:"stale"`
+}
+
+// Loops reports every registered loop of this process, by name.
+func Loops() []LoopStatus {
+	heartbeats.mu.RLock()
+	defer heartbeats.mu.RUnlock()
+
+	now := time.Now()
+	loops := make([]LoopStatus, 0, len(heartbeats.beats))
+	for name, b := range heartbeats.beats {
+		loops = append(loops, LoopStatus{
+			Name:       name,
+			Interval:   b.interval.String(),
+			LastBeatAt: b.last.UTC(),
+			Runs:       b.runs,
+			Stale:      now.Sub(b.last) > staleAfterBeats*b.interval,
+		})
+	}
+	sort.Slice(loops, func(i, j int) bool { return loops[i].Name < loops[j].Name })
+	return loops
+}
+
+// Heartbeats reports the background loops of this process, degraded when
+// any has gone stale.
+func Heartbeats() Check {
+	return Check{
+		Name: "workers",
+		Run: func(ctx context.Context) (interface{}, error) {
+			loops := Loops()
+			var stale []string
+			for _, loop := range loops {
+				if loop.Stale {
+					stale = append(stale, loop.Name)
+				}
+			}
+			if len(stale) > 0 {
+				return loops, fmt.Errorf("%w: stale loops %v", ErrDegraded, stale)
+			}
+			return loops, nil
+		},
+	}
+}
@@ -0,0 +1,125 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+const (
+	presenceKeyPrefix = "health:process:"
+	presenceInterval  = 15 * time.Second
+)
+
+// ProcessReport is what a process announces about itself in Redis, so the
+// health of one process type can be checked from another.
+type ProcessReport struct {
+	Role       string       `json:"role"`
+	Instance   string       `json:"instance"`
+	StartedAt  time.Time    `json:"startedAt"`
+	ReportedAt time.Time    `json:"reportedAt"`
+	Loops      []LoopStatus `json:"loops"`
+}
+
+// Announce publishes this process's loops under its role and instance name
+// until ctx is done. The entry expires when the process stops announcing.
+func Announce(ctx context.Context, role, instance string) {
+	key := presenceKeyPrefix + role + ":" + instance
+	startedAt := time.Now().UTC()
+
+	announce := func() {
+		if cache.MainClient == nil {
+			return
+		}
+		payload, err := json.Marshal(ProcessReport{
+			Role:       role,
+			Instance:   instance,
+			StartedAt:  startedAt,
+			ReportedAt: time.Now().UTC(),
+			Loops:      Loops(),
+		})
+		if err != nil {
+			return
+		}
+		if err := cache.MainClient.Set(ctx, key, payload, staleAfterBeats*presenceInterval).Err(); err != nil && ctx.Err() == nil {
+			logger.Warn("failed to announce process health", "role", role, "error", err)
+		}
+	}
+
+	go func() {
+		announce()
+		ticker := time.NewTicker(presenceInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				if cache.MainClient != nil {
+					cache.MainClient.Del(context.Background(), key)
+				}
+				return
+			case <-ticker.C:
+				announce()
+			}
+		}
+	}()
+}
+
+// Processes reports the processes of a role that announced themselves
+// recently. It is down when none did and they are required, and degraded
+// when one of them has a stale loop.
+func Processes(role string, required bool) Check {
+	return Check{
+		Name: role + "_processes",
+		Run: func(ctx context.Context) (interface{}, error) {
+			if cache.MainClient == nil {
+				return nil, fmt.Errorf("%w: redis not connected", ErrDegraded)
+			}
+
+			var keys []string
+			iter := cache.MainClient.Scan(ctx, 0, presenceKeyPrefix+role+":*", 100).Iterator()
+			for iter.Next(ctx) {
+				keys = append(keys, iter.Val())
+			}
+			if err := iter.Err(); err != nil {
+				return nil, err
+			}
+
+			reports := make([]ProcessReport, 0, len(keys))
+			stale := false
+			if len(keys) > 0 {
+				values, err := cache.MainClient.MGet(ctx, keys...).Result()
+				if err != nil {
+					return nil, err
+				}
+				for _, value := range values {
+					raw, ok := value.(string)
+					if !ok {
+						continue
+					}
+					var report ProcessReport
+					if json.Unmarshal([]byte(raw), &report) != nil {
+						continue
+					}
+					for _, loop := range report.Loops {
+						stale = stale || loop.Stale
+					}
+					reports = append(reports, report)
+				}
+			}
+
+			switch {
+			case len(reports) == 0 && required:
+				return reports, fmt.Errorf("no %s process reporting", role)
+			case len(reports) == 0:
+				return reports, nil
+			case stale:
+				return reports, fmt.Errorf("%w: a %s process has stale loops", ErrDegraded, role)
+			}
+			return reports, nil
+		},
+	}
+}
@@ -0,0 +1,131 @@
+// Package i18n translates user-facing messages. Catalogs are flat JSON maps
+// from message key to template, one file per language under locales/;
+// templates take named parameters written as {name}. Keys missing from a
+// language fall back to English, then to the key itself.
+//
+// API responses use the language negotiated from Accept-Language. Pushes and
+// WebSocket messages, which have no request, use the recipient's preferred
+// language from their profile.
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const Default = "en"
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+var catalogs = map[string]map[string]string{}
+
+func init() {
+	entries, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(fmt.Sprintf("i18n: read locales: %v", err))
+	}
+	for _, entry := range entries {
+		data, err := localeFiles.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			panic(fmt.Sprintf("i18n: read %s: %v", entry.Name(), err))
+		}
+		messages := map[string]string{}
+		if err := json.Unmarshal(data, &messages); err != nil {
+			panic(fmt.Sprintf("i18n: parse %s: %v", entry.Name(), err))
+		}
+		catalogs[strings.TrimSuffix(entry.Name(), ".json")] = messages
+	}
+	if _, ok := catalogs[Default]; !ok {
+		panic("i18n: missing default catalog " + Default)
+	}
+}
+
+// Supported lists the languages with a catalog.
+func Supported() []string {
+	languages := make([]string, 0, len(catalogs))
+	for lang := range catalogs {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+func IsSupported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Has reports whether lang itself, not the fallback, translates key.
+func Has(lang, key string) bool {
+	_, ok := catalogs[lang][key]
+	return ok
+}
+
+// T translates key into lang, filling {name} placeholders from params.
+func T(lang, key string, params map[string]interface{}) string {
+	template, ok := catalogs[lang][key]
+	if !ok {
+		template, ok = catalogs[Default][key]
+	}
+	if !ok {
+		return key
+	}
+	if len(params) == 0 {
+		return template
+	}
+	pairs := make([]string, 0, len(params)*2)
+	for name, value := range params {
+		pairs = append(pairs, "{"+name+"}", fmt.Sprint(value))
+	}
+	return strings.NewReplacer(pairs...).Replace(template)
+}
+
+// Negotiate picks the supported language the Accept-Language header prefers,
+// matching on the primary subtag so "ar-SA" selects "ar". It returns Default
+// when nothing matches.
+func Negotiate(header string) string {
+	best, bestQ := Default, 0.0
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		lang, _, _ := strings.Cut(tag, "-")
+		if q > bestQ && IsSupported(lang) {
+			best, bestQ = lang, q
+		}
+	}
+	return best
+}
+
+type contextKey struct{}
+
+// WithLanguage returns a context carrying lang for FromContext.
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, contextKey{}, lang)
+}
+
+// FromContext returns the request's negotiated language, or Default.
+func FromContext(ctx context.Context) string {
+	if lang, ok := ctx.Value(contextKey{}).(string); ok && lang != "" {
+		return lang
+	}
+	return Default
+}
@@ -0,0 +1,65 @@
+package i18n
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+const (
+	userLanguageKeyPrefix = "i18n:user:"
+	userLanguageTTL       = 24 * time.Hour
+)
+
+var db *gorm.DB
+
+// Initialize gives UserLanguage access to user profiles. Until it is called
+// every user gets Default.
+func Initialize(database *gorm.DB) {
+	db = database
+}
+
+// UserLanguage returns the language a user chose on their profile, cached in
+// Redis. It falls back to Default when the user has none or it cannot be
+// looked up, so a notification is never dropped for want of a language.
+func UserLanguage(ctx context.Context, userID string) string {
+	key := userLanguageKeyPrefix + userID
+	if cache.CacheClient != nil {
+		if lang, err := cache.Get(ctx, key); err == nil && IsSupported(lang) {
+			return lang
+		}
+	}
+	if db == nil || userID == "" {
+		return Default
+	}
+
+	var lang string
+	err := db.WithContext(ctx).Table("users").Select("language").Where("id = ?", userID).Scan(&lang).Error
+	if err != nil {
+		logger.Warn("failed to look up user language", "userID", userID, "error", err)
+		return Default
+	}
+	if !IsSupported(lang) {
+		lang = Default
+	}
+	if cache.CacheClient != nil {
+		if err := cache.Set(ctx, key, lang, userLanguageTTL); err != nil {
+			logger.Debug("failed to cache user language", "userID", userID, "error", err)
+		}
+	}
+	return lang
+}
+
+// ForgetUser drops the cached language after the user changes it.
+func ForgetUser(ctx context.Context, userID string) {
+	if cache.CacheClient == nil {
+		return
+	}
+	if err := cache.Delete(ctx, userLanguageKeyPrefix+userID); err != nil {
+		logger.Warn("failed to clear cached user language", "userID", userID, "error", err)
+	}
+}
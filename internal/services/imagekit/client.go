@@ -51,6 +51,8 @@ func GetDocumentFolder(documentType string) string {
 		return "documents/trade-license/"
 	case "profile-photo", "profile_photo":
 		return "documents/profile-photos/"
+	case "vehicle-inspection":
+		return "documents/vehicle-inspections/"
 	default:
 		return "documents/misc/"
 	}
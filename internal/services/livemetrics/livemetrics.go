@@ -0,0 +1,187 @@
+package livemetrics
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+// Live operational counters for the admin dashboard. Active rides and searching
+// orders are Redis sets of IDs so repeated or out-of-order updates stay idempotent;
+// online drivers/providers reuse the presence sets on the session client.
+const (
+	ActiveRidesKey     = "metrics:live:rides:active"
+	SearchingOrdersKey = "metrics:live:orders:searching"
+	OnlineDriversKey   = "drivers:online"
+	OnlineProvidersKey = "providers:online"
+
+	RevenueSourceRides        = "rides"
+	RevenueSourceHomeServices = "homeservices"
+
+	revenueKeyTTL = 48 * time.Hour
+)
+
+type Snapshot struct {
+	ActiveRides         int64     `json:"activeRides"`
+	SearchingOrders     int64     `json:"searchingOrders"`
+	OnlineDrivers       int64     `json:"onlineDrivers"`
+	OnlineProviders     int64     `json:"onlineProviders"`
+	TodayRevenue        float64   `json:"todayRevenue"`
+	TodayRideRevenue    float64   `json:"todayRideRevenue"`
+	TodayServiceRevenue float64   `json:"todayServiceRevenue"`
+	Timestamp           time.Time `json:"timestamp"`
+}
+
+// ToMap converts the snapshot into websocket message data.
+func (s *Snapshot) ToMap() map[string]interface{} {
+	return map[string]interface{}{
+		"activeRides":         s.ActiveRides,
+		"searchingOrders":     s.SearchingOrders,
+		"onlineDrivers":       s.OnlineDrivers,
+		"onlineProviders":     s.OnlineProviders,
+		"todayRevenue":        s.TodayRevenue,
+		"todayRideRevenue":    s.TodayRideRevenue,
+		"todayServiceRevenue": s.TodayServiceRevenue,
+		"timestamp":           s.Timestamp,
+	}
+}
+
+func revenueKey(day time.Time) string {
+	return fmt.Sprintf("metrics:live:revenue:%s", day.UTC().Format("2006-01-02"))
+}
+
+func RideActive(ctx context.Context, rideID string) {
+	addMember(ctx, cache.MainClient, ActiveRidesKey, rideID)
+}
+
+func RideEnded(ctx context.Context, rideID string) {
+	removeMember(ctx, cache.MainClient, ActiveRidesKey, rideID)
+}
+
+func OrderSearching(ctx context.Context, orderID string) {
+	addMember(ctx, cache.MainClient, SearchingOrdersKey, orderID)
+}
+
+func OrderNotSearching(ctx context.Context, orderID string) {
+	removeMember(ctx, cache.MainClient, SearchingOrdersKey, orderID)
+}
+
+func ProviderAvailable(ctx context.Context, providerID string, available bool) {
+	if available {
+		addMember(ctx, cache.SessionClient, OnlineProvidersKey, providerID)
+		return
+	}
+	removeMember(ctx, cache.SessionClient, OnlineProvidersKey, providerID)
+}
+
+// AddRevenue adds a completed payment to today's (UTC) revenue for the given source.
+func AddRevenue(ctx context.Context, source string, amount float64) {
+	if cache.MainClient == nil || amount == 0 {
+		return
+	}
+	key := revenueKey(time.Now())
+	pipe := cache.MainClient.TxPipeline()
+	pipe.HIncrByFloat(ctx, key, source, amount)
+	pipe.Expire(ctx, key, revenueKeyTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Warn("failed to record live revenue", "error", err, "source", source, "amount", amount)
+	}
+}
+
+// ReplaceSet overwrites a metric set with the given IDs. Used to reconcile drift
+// against the database.
+func ReplaceSet(ctx context.Context, key string, ids []string) error {
+	if cache.MainClient == nil {
+		return nil
+	}
+	pipe := cache.MainClient.TxPipeline()
+	pipe.Del(ctx, key)
+	if len(ids) > 0 {
+		members := make([]interface{}, len(ids))
+		for i, id := range ids {
+			members[i] = id
+		}
+		pipe.SAdd(ctx, key, members...)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// SetRevenue overwrites today's revenue for a source, used when reconciling.
+func SetRevenue(ctx context.Context, source string, amount float64) error {
+	if cache.MainClient == nil {
+		return nil
+	}
+	key := revenueKey(time.Now())
+	pipe := cache.MainClient.TxPipeline()
+	pipe.HSet(ctx, key, source, amount)
+	pipe.Expire(ctx, key, revenueKeyTTL)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func GetSnapshot(ctx context.Context) (*Snapshot, error) {
+	snapshot := &Snapshot{Timestamp: time.Now().UTC()}
+	if cache.MainClient == nil || cache.SessionClient == nil {
+		return snapshot, nil
+	}
+
+	mainPipe := cache.MainClient.Pipeline()
+	activeRides := mainPipe.SCard(ctx, ActiveRidesKey)
+	searchingOrders := mainPipe.SCard(ctx, SearchingOrdersKey)
+	revenue := mainPipe.HGetAll(ctx, revenueKey(snapshot.Timestamp))
+	if _, err := mainPipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to read live metrics: %w", err)
+	}
+
+	sessionPipe := cache.SessionClient.Pipeline()
+	onlineDrivers := sessionPipe.SCard(ctx, OnlineDriversKey)
+	onlineProviders := sessionPipe.SCard(ctx, OnlineProvidersKey)
+	if _, err := sessionPipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to read presence metrics: %w", err)
+	}
+
+	snapshot.ActiveRides = activeRides.Val()
+	snapshot.SearchingOrders = searchingOrders.Val()
+	snapshot.OnlineDrivers = onlineDrivers.Val()
+	snapshot.OnlineProviders = onlineProviders.Val()
+
+	for source, value := range revenue.Val() {
+		amount, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			continue
+		}
+		switch source {
+		case RevenueSourceRides:
+			snapshot.TodayRideRevenue = amount
+		case RevenueSourceHomeServices:
+			snapshot.TodayServiceRevenue = amount
+		}
+		snapshot.TodayRevenue += amount
+	}
+
+	return snapshot, nil
+}
+
+func addMember(ctx context.Context, client *redis.Client, key, member string) {
+	if client == nil {
+		return
+	}
+	if err := client.SAdd(ctx, key, member).Err(); err != nil {
+		logger.Warn("failed to update live metric", "error", err, "key", key, "member", member)
+	}
+}
+
+func removeMember(ctx context.Context, client *redis.Client, key, member string) {
+	if client == nil {
+		return
+	}
+	if err := client.SRem(ctx, key, member).Err(); err != nil {
+		logger.Warn("failed to update live metric", "error", err, "key", key, "member", member)
+	}
+}
@@ -0,0 +1,111 @@
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/umar5678/go-backend/internal/utils/location"
+)
+
+// GoogleProvider queries the Google Directions API. Departure is always "now"
+// so durations include current traffic.
+type GoogleProvider struct {
+	apiKey  string
+	baseURL string
+	client  *http.Client
+}
+
+func NewGoogleProvider(apiKey, baseURL string, client *http.Client) *GoogleProvider {
+	if baseURL == "" {
+		baseURL = "https://maps.googleapis.com"
+	}
+	return &GoogleProvider{apiKey: apiKey, baseURL: strings.TrimSuffix(baseURL, "/"), client: client}
+}
+
+func (p *GoogleProvider) Name() string {
+	return ProviderGoogle
+}
+
+type googleValue struct {
+	Value float64 `json:"value"`
+}
+
+type googleDirectionsResponse struct {
+	Status       string `json:"status"`
+	ErrorMessage string `json:"error_message"`
+	Routes       []struct {
+		OverviewPolyline struct {
+			Points string `json:"points"`
+		} `json:"overview_polyline"`
+		Legs []struct {
+			Distance          googleValue  `json:"distance"`
+			Duration          googleValue  `json:"duration"`
+			DurationInTraffic *googleValue `json:"duration_in_traffic"`
+		} `json:"legs"`
+	} `json:"routes"`
+}
+
+func (p *GoogleProvider) Route(ctx context.Context, from, to location.Point) (*Route, error) {
+	if p.apiKey == "" {
+		return nil, errors.New("google directions api key is not configured")
+	}
+
+	query := url.Values{}
+	query.Set("origin", fmt.Sprintf("%f,%f", from.Latitude, from.Longitude))
+	query.Set("destination", fmt.Sprintf("%f,%f", to.Latitude, to.Longitude))
+	query.Set("mode", "driving")
+	query.Set("departure_time", "now")
+	query.Set("key", p.apiKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/maps/api/directions/json?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("google directions returned status %d", resp.StatusCode)
+	}
+
+	var result googleDirectionsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if result.Status == "ZERO_RESULTS" || result.Status == "NOT_FOUND" {
+		return nil, ErrNoRoute
+	}
+	if result.Status != "OK" {
+		return nil, fmt.Errorf("google directions returned %s: %s", result.Status, result.ErrorMessage)
+	}
+	if len(result.Routes) == 0 || len(result.Routes[0].Legs) == 0 {
+		return nil, ErrNoRoute
+	}
+
+	var distance, duration float64
+	for _, leg := range result.Routes[0].Legs {
+		distance += leg.Distance.Value
+		if leg.DurationInTraffic != nil {
+			duration += leg.DurationInTraffic.Value
+		} else {
+			duration += leg.Duration.Value
+		}
+	}
+
+	return &Route{
+		DistanceKm:      math.Round(distance/10) / 100,
+		DurationSeconds: int(math.Round(duration)),
+		Polyline:        result.Routes[0].OverviewPolyline.Points,
+		Source:          ProviderGoogle,
+	}, nil
+}
@@ -0,0 +1,46 @@
+package routing
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/umar5678/go-backend/internal/utils/location"
+)
+
+// NavigationLinks open turn-by-turn driving directions in the common
+// navigation apps.
+type NavigationLinks struct {
+	GoogleMaps string `json:"googleMaps"`
+	Waze       string `json:"waze"`
+	AppleMaps  string `json:"appleMaps"`
+}
+
+// NavigationTo builds deep links to drive to destination. Without an origin
+// the apps start from the device's current location.
+func NavigationTo(origin *location.Point, destination location.Point) NavigationLinks {
+	dest := formatPoint(destination)
+
+	google := url.Values{}
+	google.Set("api", "1")
+	google.Set("destination", dest)
+	google.Set("travelmode", "driving")
+
+	apple := url.Values{}
+	apple.Set("daddr", dest)
+	apple.Set("dirflg", "d")
+
+	if origin != nil {
+		google.Set("origin", formatPoint(*origin))
+		apple.Set("saddr", formatPoint(*origin))
+	}
+
+	return NavigationLinks{
+		GoogleMaps: "https://www.google.com/maps/dir/?" + google.Encode(),
+		Waze:       fmt.Sprintf("https://waze.com/ul?ll=%s&navigate=yes", url.QueryEscape(dest)),
+		AppleMaps:  "https://maps.apple.com/?" + apple.Encode(),
+	}
+}
+
+func formatPoint(p location.Point) string {
+	return fmt.Sprintf("%.6f,%.6f", p.Latitude, p.Longitude)
+}
@@ -0,0 +1,75 @@
+package routing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/umar5678/go-backend/internal/utils/location"
+)
+
+// OSRMProvider queries the route service of an OSRM server.
+type OSRMProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewOSRMProvider(baseURL string, client *http.Client) *OSRMProvider {
+	if baseURL == "" {
+		baseURL = "https://router.project-osrm.org"
+	}
+	return &OSRMProvider{baseURL: strings.TrimSuffix(baseURL, "/"), client: client}
+}
+
+func (p *OSRMProvider) Name() string {
+	return ProviderOSRM
+}
+
+type osrmResponse struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Routes  []struct {
+		Distance float64 `json:"distance"`
+		Duration float64 `json:"duration"`
+		Geometry string  `json:"geometry"`
+	} `json:"routes"`
+}
+
+func (p *OSRMProvider) Route(ctx context.Context, from, to location.Point) (*Route, error) {
+	// OSRM takes coordinates as lon,lat.
+	url := fmt.Sprintf("%s/route/v1/driving/%f,%f;%f,%f?overview=simplified&geometries=polyline",
+		p.baseURL, from.Longitude, from.Latitude, to.Longitude, to.Latitude)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result osrmResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("osrm returned status %d: %w", resp.StatusCode, err)
+	}
+	if result.Code != "Ok" {
+		return nil, fmt.Errorf("osrm returned %s: %s", result.Code, result.Message)
+	}
+	if len(result.Routes) == 0 {
+		return nil, ErrNoRoute
+	}
+
+	route := result.Routes[0]
+	return &Route{
+		DistanceKm:      math.Round(route.Distance/10) / 100,
+		DurationSeconds: int(math.Round(route.Duration)),
+		Polyline:        route.Geometry,
+		Source:          ProviderOSRM,
+	}, nil
+}
@@ -0,0 +1,239 @@
+// Package storage talks to S3-compatible object storage. Google Cloud Storage
+// is reached through its S3 interoperability API using HMAC keys, so one
+// client covers AWS S3, GCS and self-hosted stores such as MinIO.
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+)
+
+var ErrNotFound = errors.New("object not found")
+
+// ObjectInfo is what a HEAD request reports about a stored object.
+type ObjectInfo struct {
+	Size        int64
+	ContentType string
+}
+
+type Client struct {
+	bucket    string
+	region    string
+	accessKey string
+	secretKey string
+	// base is the bucket's root URL; object keys are appended to its path.
+	base      *url.URL
+	publicURL string
+	client    *http.Client
+}
+
+func NewClient(cfg config.UploadConfig) (*Client, error) {
+	s3 := cfg.S3
+	if s3.Bucket == "" || s3.AccessKey == "" || s3.SecretKey == "" {
+		return nil, fmt.Errorf("storage: bucket and access keys are required")
+	}
+
+	region := s3.Region
+	var base string
+	switch {
+	case s3.Endpoint != "":
+		// Custom endpoints (MinIO and friends) expect path-style addressing.
+		base = strings.TrimRight(s3.Endpoint, "/") + "/" + s3.Bucket
+	case cfg.Provider == "gcs":
+		base = "https://storage.googleapis.com/" + s3.Bucket
+		if region == "" {
+			region = "auto"
+		}
+	default:
+		if region == "" {
+			region = "us-east-1"
+		}
+		base = fmt.Sprintf("https://%s.s3.%s.amazonaws.com", s3.Bucket, region)
+	}
+
+	parsed, err := url.Parse(base)
+	if err != nil {
+		return nil, fmt.Errorf("storage: invalid endpoint: %w", err)
+	}
+
+	publicURL := strings.TrimRight(s3.PublicURL, "/")
+	if publicURL == "" {
+		publicURL = strings.TrimRight(parsed.String(), "/")
+	}
+
+	return &Client{
+		bucket:    s3.Bucket,
+		region:    region,
+		accessKey: s3.AccessKey,
+		secretKey: s3.SecretKey,
+		base:      parsed,
+		publicURL: publicURL,
+		client:    &http.Client{Timeout: 30 * time.Second},
+	}, nil
+}
+
+// URL is the public address of an object, served through the CDN when one is
+// configured.
+func (c *Client) URL(key string) string {
+	return c.publicURL + "/" + escapeKey(key)
+}
+
+// PresignPut returns a URL the caller can PUT the object to directly. The
+// upload must send the same Content-Type, which is part of the signature.
+func (c *Client) PresignPut(key, contentType string, expires time.Duration) string {
+	u := c.objectURL(key)
+	now := time.Now().UTC()
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", signingAlgorithm)
+	query.Set("X-Amz-Credential", c.accessKey+"/"+c.scope(now))
+	query.Set("X-Amz-Date", now.Format(amzDateFormat))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "content-type;host")
+	u.RawQuery = canonicalQuery(query)
+
+	headers := http.Header{}
+	headers.Set("Content-Type", contentType)
+	headers.Set("Host", u.Host)
+
+	signature := c.sign(now, http.MethodPut, u, headers, unsignedPayload)
+	u.RawQuery += "&X-Amz-Signature=" + signature
+	return u.String()
+}
+
+// PresignGet returns a URL the caller can download a private object from
+// until it expires.
+func (c *Client) PresignGet(key string, expires time.Duration) string {
+	u := c.objectURL(key)
+	now := time.Now().UTC()
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", signingAlgorithm)
+	query.Set("X-Amz-Credential", c.accessKey+"/"+c.scope(now))
+	query.Set("X-Amz-Date", now.Format(amzDateFormat))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+	u.RawQuery = canonicalQuery(query)
+
+	headers := http.Header{}
+	headers.Set("Host", u.Host)
+
+	signature := c.sign(now, http.MethodGet, u, headers, unsignedPayload)
+	u.RawQuery += "&X-Amz-Signature=" + signature
+	return u.String()
+}
+
+func (c *Client) Head(ctx context.Context, key string) (*ObjectInfo, error) {
+	resp, err := c.do(ctx, http.MethodHead, key, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+
+	return &ObjectInfo{
+		Size:        resp.ContentLength,
+		ContentType: resp.Header.Get("Content-Type"),
+	}, nil
+}
+
+// Get reads an object, refusing anything larger than maxBytes.
+func (c *Client) Get(ctx context.Context, key string, maxBytes int64) ([]byte, error) {
+	resp, err := c.do(ctx, http.MethodGet, key, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("storage: read %s: %w", key, err)
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, fmt.Errorf("storage: %s is larger than %d bytes", key, maxBytes)
+	}
+	return data, nil
+}
+
+func (c *Client) Put(ctx context.Context, key, contentType string, data []byte) error {
+	resp, err := c.do(ctx, http.MethodPut, key, contentType, data)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Delete removes an object. Deleting a missing object is not an error.
+func (c *Client) Delete(ctx context.Context, key string) error {
+	resp, err := c.do(ctx, http.MethodDelete, key, "", nil)
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (c *Client) do(ctx context.Context, method, key, contentType string, body []byte) (*http.Response, error) {
+	u := c.objectURL(key)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body == nil {
+		req.Body = http.NoBody
+		req.ContentLength = 0
+	}
+
+	sum := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(sum[:])
+	now := time.Now().UTC()
+
+	req.Header.Set("Host", u.Host)
+	req.Header.Set("X-Amz-Date", now.Format(amzDateFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	signature := c.sign(now, method, u, req.Header, payloadHash)
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		signingAlgorithm, c.accessKey, c.scope(now), signedHeaders(req.Header), signature))
+	req.Header.Del("Host")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("storage: %s %s: %w", method, key, err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("storage: %s %s returned %d: %s", method, key, resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return resp, nil
+}
+
+func (c *Client) objectURL(key string) *url.URL {
+	u := *c.base
+	u.Path = strings.TrimRight(c.base.Path, "/") + "/" + key
+	u.RawPath = strings.TrimRight(c.base.EscapedPath(), "/") + "/" + escapeKey(key)
+	return &u
+}
@@ -0,0 +1,390 @@
+package startup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/config"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusOK      Status = "ok"
+	StatusFailed  Status = "failed"
+	StatusSkipped Status = "skipped"
+
+	// Overall report states.
+	StatusDegraded Status = "degraded"
+)
+
+// Component is one piece of startup wiring. Start is retried with backoff when
+// Retry is set, which is meant for network dependencies that may come up after
+// the server does. Stop, when given, runs on shutdown in reverse start order.
+type Component struct {
+	Name      string
+	DependsOn []string
+	Required  bool
+	Retry     bool
+	Start     func(ctx context.Context) error
+	Stop      func() error
+}
+
+type ComponentResult struct {
+	Name       string     `json:"name"`
+	Required   bool       `json:"required"`
+	DependsOn  []string   `json:"dependsOn,omitempty"`
+	Status     Status     `json:"status"`
+	Attempts   int        `json:"attempts"`
+	Duration   string     `json:"duration"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+type Report struct {
+	Status      Status            `json:"status"`
+	StartedAt   time.Time         `json:"startedAt"`
+	CompletedAt *time.Time        `json:"completedAt,omitempty"`
+	Duration    string            `json:"duration,omitempty"`
+	Components  []ComponentResult `json:"components"`
+}
+
+// Orchestrator starts components in dependency order and records the outcome
+// of each one. A component whose dependency failed is skipped rather than
+// started against a broken prerequisite.
+type Orchestrator struct {
+	cfg        config.StartupConfig
+	components []Component
+	index      map[string]int
+
+	mu      sync.RWMutex
+	report  Report
+	started []Component
+}
+
+func NewOrchestrator(cfg config.StartupConfig) *Orchestrator {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 1
+	}
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = time.Second
+	}
+	if cfg.MaxBackoff < cfg.InitialBackoff {
+		cfg.MaxBackoff = cfg.InitialBackoff
+	}
+
+	return &Orchestrator{
+		cfg:   cfg,
+		index: make(map[string]int),
+	}
+}
+
+// Add registers a component. Config overrides for required and optional
+// components are applied here so callers only state the defaults.
+func (o *Orchestrator) Add(c Component) {
+	for _, name := range o.cfg.RequiredComponents {
+		if strings.TrimSpace(name) == c.Name {
+			c.Required = true
+		}
+	}
+	for _, name := range o.cfg.OptionalComponents {
+		if strings.TrimSpace(name) == c.Name {
+			c.Required = false
+		}
+	}
+
+	o.index[c.Name] = len(o.components)
+	o.components = append(o.components, c)
+}
+
+// Run starts every component. It returns an error only when a required
+// component could not be started; optional failures leave the report degraded.
+func (o *Orchestrator) Run(ctx context.Context) error {
+	order, err := o.resolveOrder()
+	if err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	o.report = Report{
+		Status:     StatusPending,
+		StartedAt:  time.Now().UTC(),
+		Components: make([]ComponentResult, len(o.components)),
+	}
+	for i, c := range o.components {
+		o.report.Components[i] = ComponentResult{
+			Name:      c.Name,
+			Required:  c.Required,
+			DependsOn: c.DependsOn,
+			Status:    StatusPending,
+		}
+	}
+	o.mu.Unlock()
+
+	var requiredErr error
+	for _, i := range order {
+		c := o.components[i]
+
+		if failed := o.failedDependency(c); failed != "" {
+			o.finish(i, StatusSkipped, 0, 0, fmt.Errorf("dependency %s did not start", failed))
+			logger.Warn("startup component skipped",
+				"component", c.Name,
+				"dependency", failed,
+				"required", c.Required,
+			)
+			if c.Required && requiredErr == nil {
+				requiredErr = fmt.Errorf("required component %s skipped: dependency %s did not start", c.Name, failed)
+			}
+			continue
+		}
+
+		o.markStarted(i)
+		start := time.Now()
+		attempts, err := o.startWithRetry(ctx, c)
+		if err != nil {
+			o.finish(i, StatusFailed, attempts, time.Since(start), err)
+			if c.Required {
+				logger.Error("required startup component failed", "component", c.Name, "attempts", attempts, "error", err)
+				if requiredErr == nil {
+					requiredErr = fmt.Errorf("required component %s failed: %w", c.Name, err)
+				}
+				// Nothing after a failed required component can be trusted
+				// to start, so stop here and report.
+				break
+			}
+			logger.Warn("optional startup component failed, continuing degraded", "component", c.Name, "attempts", attempts, "error", err)
+			continue
+		}
+
+		o.finish(i, StatusOK, attempts, time.Since(start), nil)
+		o.mu.Lock()
+		o.started = append(o.started, c)
+		o.mu.Unlock()
+	}
+
+	o.complete(requiredErr)
+	o.logReport()
+
+	return requiredErr
+}
+
+// Shutdown stops started components in reverse order.
+func (o *Orchestrator) Shutdown() {
+	o.mu.Lock()
+	started := o.started
+	o.started = nil
+	o.mu.Unlock()
+
+	for i := len(started) - 1; i >= 0; i-- {
+		c := started[i]
+		if c.Stop == nil {
+			continue
+		}
+		if err := c.Stop(); err != nil {
+			logger.Error("error stopping component", "component", c.Name, "error", err)
+		}
+	}
+}
+
+// Report returns a copy of the latest startup report.
+func (o *Orchestrator) Report() Report {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	report := o.report
+	report.Components = append([]ComponentResult(nil), o.report.Components...)
+	return report
+}
+
+// Started reports whether the named component came up successfully.
+func (o *Orchestrator) Started(name string) bool {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	i, ok := o.index[name]
+	return ok && i < len(o.report.Components) && o.report.Components[i].Status == StatusOK
+}
+
+func (o *Orchestrator) startWithRetry(ctx context.Context, c Component) (int, error) {
+	maxAttempts := 1
+	if c.Retry {
+		maxAttempts = o.cfg.MaxAttempts
+	}
+
+	backoff := o.cfg.InitialBackoff
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if err = c.Start(ctx); err == nil {
+			if attempt > 1 {
+				logger.Info("startup component recovered after retry", "component", c.Name, "attempts", attempt)
+			}
+			return attempt, nil
+		}
+
+		if attempt == maxAttempts {
+			return attempt, err
+		}
+
+		logger.Warn("startup component failed, retrying",
+			"component", c.Name,
+			"attempt", attempt,
+			"maxAttempts", maxAttempts,
+			"retryIn", backoff.String(),
+			"error", err,
+		)
+
+		select {
+		case <-ctx.Done():
+			return attempt, errors.Join(err, ctx.Err())
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > o.cfg.MaxBackoff {
+			backoff = o.cfg.MaxBackoff
+		}
+	}
+
+	return maxAttempts, err
+}
+
+// resolveOrder sorts components so each runs after its dependencies, keeping
+// registration order where there is no constraint.
+func (o *Orchestrator) resolveOrder() ([]int, error) {
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+
+	state := make([]int, len(o.components))
+	order := make([]int, 0, len(o.components))
+
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("startup dependency cycle at %s", o.components[i].Name)
+		}
+
+		state[i] = visiting
+		for _, dep := range o.components[i].DependsOn {
+			j, ok := o.index[dep]
+			if !ok {
+				return fmt.Errorf("startup component %s depends on unknown component %s", o.components[i].Name, dep)
+			}
+			if err := visit(j); err != nil {
+				return err
+			}
+		}
+		state[i] = done
+		order = append(order, i)
+		return nil
+	}
+
+	for i := range o.components {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
+
+func (o *Orchestrator) failedDependency(c Component) string {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	for _, dep := range c.DependsOn {
+		if o.report.Components[o.index[dep]].Status != StatusOK {
+			return dep
+		}
+	}
+	return ""
+}
+
+func (o *Orchestrator) markStarted(i int) {
+	now := time.Now().UTC()
+
+	o.mu.Lock()
+	o.report.Components[i].StartedAt = &now
+	o.mu.Unlock()
+}
+
+func (o *Orchestrator) finish(i int, status Status, attempts int, d time.Duration, err error) {
+	now := time.Now().UTC()
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	result := &o.report.Components[i]
+	result.Status = status
+	result.Attempts = attempts
+	result.Duration = d.Round(time.Millisecond).String()
+	result.FinishedAt = &now
+	if err != nil {
+		result.Error = err.Error()
+	}
+}
+
+func (o *Orchestrator) complete(requiredErr error) {
+	now := time.Now().UTC()
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.report.CompletedAt = &now
+	for i := range o.report.Components {
+		if o.report.Components[i].Status == StatusPending {
+			o.report.Components[i].Status = StatusSkipped
+			o.report.Components[i].Error = "startup aborted"
+		}
+	}
+	o.report.Duration = now.Sub(o.report.StartedAt).Round(time.Millisecond).String()
+
+	switch {
+	case requiredErr != nil:
+		o.report.Status = StatusFailed
+	default:
+		o.report.Status = StatusOK
+		for _, r := range o.report.Components {
+			if r.Status != StatusOK {
+				o.report.Status = StatusDegraded
+				break
+			}
+		}
+	}
+}
+
+func (o *Orchestrator) logReport() {
+	report := o.Report()
+
+	for _, r := range report.Components {
+		fields := []interface{}{
+			"component", r.Name,
+			"status", r.Status,
+			"required", r.Required,
+			"attempts", r.Attempts,
+			"duration", r.Duration,
+		}
+		if r.Error != "" {
+			fields = append(fields, "error", r.Error)
+		}
+		logger.Info("startup component", fields...)
+	}
+
+	logger.Info("startup report",
+		"status", report.Status,
+		"duration", report.Duration,
+		"components", len(report.Components),
+	)
+}
@@ -5,21 +5,75 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+// ScopeWrite lets an impersonation token call endpoints that change data.
+const ScopeWrite = "write"
+
 type Claims struct {
-	UserID string `json:"userId"`
-	Role   string `json:"role"`
+	UserID    string `json:"userId"`
+	Role      string `json:"role"`
+	SessionID string `json:"sid,omitempty"`
+	// ImpersonatorID is the admin acting as UserID; it is only set on
+	// impersonation tokens, whose Scopes say what beyond reading is allowed.
+	ImpersonatorID string   `json:"imp,omitempty"`
+	Scopes         []string `json:"scp,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// IsImpersonation reports whether the token was issued to an admin acting as
+// the user.
+func (c *Claims) IsImpersonation() bool {
+	return c.ImpersonatorID != ""
+}
+
+// HasScope reports whether the token was granted scope.
+func (c *Claims) HasScope(scope string) bool {
+	for _, s := range c.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
 // GenerateToken creates a JWT token with proper validation and issuer claim
 func GenerateToken(userID, role, secret, issuer string, expiry time.Duration) (string, error) {
+	return GenerateSessionToken(userID, role, "", secret, issuer, expiry)
+}
+
+// GenerateSessionToken creates a JWT bound to a login session so it can be
+// revoked with that session
+func GenerateSessionToken(userID, role, sessionID, secret, issuer string, expiry time.Duration) (string, error) {
+	return generateToken(Claims{UserID: userID, Role: role, SessionID: sessionID}, secret, issuer, expiry)
+}
+
+// GenerateImpersonationToken creates a token that lets impersonatorID act as
+// userID. It is bound to the impersonation session so ending the session
+// revokes it.
+func GenerateImpersonationToken(userID, role, impersonatorID, sessionID string, scopes []string, secret, issuer string, expiry time.Duration) (string, error) {
+	if impersonatorID == "" {
+		return "", errors.New("impersonatorID cannot be empty")
+	}
+	if sessionID == "" {
+		return "", errors.New("impersonation sessionID cannot be empty")
+	}
+	return generateToken(Claims{
+		UserID:         userID,
+		Role:           role,
+		SessionID:      sessionID,
+		ImpersonatorID: impersonatorID,
+		Scopes:         scopes,
+	}, secret, issuer, expiry)
+}
+
+func generateToken(claims Claims, secret, issuer string, expiry time.Duration) (string, error) {
 	// Validate inputs before token generation
-	if userID == "" {
+	if claims.UserID == "" {
 		return "", errors.New("userID cannot be empty")
 	}
-	if role == "" {
+	if claims.Role == "" {
 		return "", errors.New("role cannot be empty")
 	}
 	if secret == "" {
@@ -35,15 +89,12 @@ func GenerateToken(userID, role, secret, issuer string, expiry time.Duration) (s
 		return "", errors.New("JWT issuer cannot be empty")
 	}
 
-	claims := Claims{
-		UserID: userID,
-		Role:   role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			NotBefore: jwt.NewNumericDate(time.Now()),
-			Issuer:    issuer,
-		},
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		ID:        uuid.New().String(),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiry)),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		NotBefore: jwt.NewNumericDate(time.Now()),
+		Issuer:    issuer,
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
@@ -0,0 +1,38 @@
+package location
+
+// PointInPolygon reports whether a coordinate lies inside a polygon given as a
+// ring of points; the last point joins back to the first. Points on an edge
+// may fall either way.
+func PointInPolygon(lat, lon float64, polygon []Point) bool {
+	if len(polygon) < 3 {
+		return false
+	}
+
+	inside := false
+	j := len(polygon) - 1
+	for i := range polygon {
+		pi, pj := polygon[i], polygon[j]
+		if (pi.Latitude > lat) != (pj.Latitude > lat) &&
+			lon < (pj.Longitude-pi.Longitude)*(lat-pi.Latitude)/(pj.Latitude-pi.Latitude)+pi.Longitude {
+			inside = !inside
+		}
+		j = i
+	}
+	return inside
+}
+
+// PolygonCentroid averages a polygon's vertices. It is close enough to the
+// centre for the city-sized shapes it is used on.
+func PolygonCentroid(polygon []Point) Point {
+	if len(polygon) == 0 {
+		return Point{}
+	}
+
+	var lat, lon float64
+	for _, p := range polygon {
+		lat += p.Latitude
+		lon += p.Longitude
+	}
+	n := float64(len(polygon))
+	return Point{Latitude: lat / n, Longitude: lon / n}
+}
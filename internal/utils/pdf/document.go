@@ -0,0 +1,156 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Document is a minimal PDF writer for receipts, quotes and labels. It only
+// supports the standard Helvetica faces, text, rules and rectangles, which is
+// all those documents need and keeps us off a third-party PDF dependency.
+type Document struct {
+	width   float64
+	height  float64
+	pages   []*bytes.Buffer
+	content *bytes.Buffer
+}
+
+const (
+	PageWidth  = 595.0 // A4 in points
+	PageHeight = 842.0
+)
+
+func NewDocument() *Document {
+	d := &Document{width: PageWidth, height: PageHeight}
+	d.NewPage()
+	return d
+}
+
+// NewPage starts a new page; everything drawn afterwards goes on it.
+func (d *Document) NewPage() {
+	d.content = &bytes.Buffer{}
+	d.pages = append(d.pages, d.content)
+}
+
+// Text draws a line of text with its baseline at (x, y), measured from the top
+// left of the page.
+func (d *Document) Text(x, y, size float64, bold bool, text string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(d.content, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, d.height-y, escape(text))
+}
+
+// TextRight draws text that ends at x.
+func (d *Document) TextRight(x, y, size float64, bold bool, text string) {
+	d.Text(x-TextWidth(text, size, bold), y, size, bold, text)
+}
+
+func (d *Document) Rule(x1, x2, y float64) {
+	fmt.Fprintf(d.content, "0.5 w %.2f %.2f m %.2f %.2f l S\n", x1, d.height-y, x2, d.height-y)
+}
+
+// Rect outlines a rectangle whose top left corner is at (x, y), in light grey
+// so it can serve as a cut line.
+func (d *Document) Rect(x, y, w, h float64) {
+	fmt.Fprintf(d.content, "0.5 w 0.8 G %.2f %.2f %.2f %.2f re S 0 G\n", x, d.height-y-h, w, h)
+}
+
+// FillRect paints a solid black rectangle whose top left corner is at (x, y).
+func (d *Document) FillRect(x, y, w, h float64) {
+	fmt.Fprintf(d.content, "%.3f %.3f %.3f %.3f re f\n", x, d.height-y-h, w, h)
+}
+
+func (d *Document) Bytes() []byte {
+	// Objects 1-4 are the catalog, page tree and the two fonts; each page is
+	// then a page object followed by its content stream.
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+i*2)
+	}
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>",
+	}
+	for i, page := range d.pages {
+		stream := page.Bytes()
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", d.width, d.height, 6+i*2),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(stream), stream),
+		)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	return buf.Bytes()
+}
+
+// escape escapes PDF string delimiters and replaces anything outside
+// printable ASCII, which the standard fonts cannot show without embedding.
+func escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			b.WriteRune('\\')
+			b.WriteRune(r)
+		case r < 32 || r > 126:
+			b.WriteRune('?')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// TextWidth approximates the rendered width using Helvetica glyph widths
+// for the characters that appear in amounts, and an average for the rest.
+func TextWidth(s string, size float64, bold bool) float64 {
+	units := 0
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			units += 556
+		case r == '.' || r == ',' || r == ' ':
+			units += 278
+		case r == '-':
+			units += 333
+		case r >= 'A' && r <= 'Z':
+			units += 667
+		default:
+			units += 556
+		}
+	}
+	width := float64(units) * size / 1000
+	if bold {
+		width *= 1.05
+	}
+	return width
+}
+
+// Truncate shortens s to max runes, marking the cut with an ellipsis.
+func Truncate(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max-3]) + "..."
+}
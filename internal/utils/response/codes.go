@@ -0,0 +1,167 @@
+package response
+
+import (
+	"net/http"
+	"sort"
+)
+
+// Error codes returned in the "code" field of the error envelope. Clients
+// branch on these rather than on the message, which is for humans and may
+// change. Codes are never renamed or reused once shipped.
+const (
+	CodeBadRequest         = "BAD_REQUEST"
+	CodeUnauthorized       = "UNAUTHORIZED"
+	CodeForbidden          = "FORBIDDEN"
+	CodeNotFound           = "NOT_FOUND"
+	CodeConflict           = "CONFLICT"
+	CodeValidation         = "VALIDATION_ERROR"
+	CodeInvalidField       = "INVALID_FIELD"
+	CodeInternal           = "INTERNAL_ERROR"
+	CodeRateLimitExceeded  = "RATE_LIMIT_EXCEEDED"
+	CodeServiceUnavailable = "SERVICE_UNAVAILABLE"
+
+	CodeAuthRequired       = "AUTH_REQUIRED"
+	CodeInvalidCredentials = "AUTH_INVALID_CREDENTIALS"
+	CodeTokenInvalid       = "AUTH_TOKEN_INVALID"
+	CodeTokenRevoked       = "AUTH_TOKEN_REVOKED"
+	CodeSessionRevoked     = "AUTH_SESSION_REVOKED"
+	CodeSessionExpired     = "AUTH_SESSION_EXPIRED"
+	CodeRefreshTokenReused = "AUTH_REFRESH_TOKEN_REUSED"
+	CodeAccountInactive    = "ACCOUNT_INACTIVE"
+	CodeEmailInUse         = "ACCOUNT_EMAIL_IN_USE"
+	CodePhoneInUse         = "ACCOUNT_PHONE_IN_USE"
+	CodeInsufficientRole   = "AUTH_INSUFFICIENT_PERMISSIONS"
+	CodeCaptchaFailed      = "CAPTCHA_FAILED"
+	CodeCaptchaUnavailable = "CAPTCHA_UNAVAILABLE"
+
+	CodeImpersonationReadOnly = "AUTH_IMPERSONATION_READ_ONLY"
+
+	CodeRideNotAvailable       = "RIDE_NOT_AVAILABLE"
+	CodeRideAlreadyAccepted    = "RIDE_ALREADY_ACCEPTED"
+	CodeRideRequestExpired     = "RIDE_REQUEST_EXPIRED"
+	CodeRideInvalidStatus      = "RIDE_INVALID_STATUS"
+	CodeRideAlreadyCancelled   = "RIDE_ALREADY_CANCELLED"
+	CodeRideAlreadyCompleted   = "RIDE_ALREADY_COMPLETED"
+	CodeRideInvalidPIN         = "RIDE_INVALID_PIN"
+	CodeRideTooFarFromDropoff  = "RIDE_TOO_FAR_FROM_DROPOFF"
+	CodeRideDistanceOutOfRange = "RIDE_DISTANCE_OUT_OF_RANGE"
+	CodeDriverOffline          = "DRIVER_OFFLINE"
+	CodeDriverInspectionLapsed = "DRIVER_INSPECTION_LAPSED"
+	CodeDriverCreditExceeded   = "DRIVER_CREDIT_LIMIT_EXCEEDED"
+	CodeScheduledAtInvalid     = "RIDE_SCHEDULED_AT_INVALID"
+	CodeRideFareChanged        = "RIDE_FARE_CHANGED"
+	CodeVehicleTypeUnavailable = "RIDE_VEHICLE_TYPE_UNAVAILABLE"
+	CodeRideOverCapacity       = "RIDE_OVER_CAPACITY"
+
+	CodeWalletInsufficientFunds = "WALLET_INSUFFICIENT_FUNDS"
+	CodeWalletInactive          = "WALLET_INACTIVE"
+	CodeWalletSelfTransfer      = "WALLET_SELF_TRANSFER"
+	CodeWalletHoldInactive      = "WALLET_HOLD_INACTIVE"
+
+	CodePromoInvalid       = "PROMO_INVALID"
+	CodePromoUsageLimit    = "PROMO_USAGE_LIMIT_REACHED"
+	CodePromoAlreadyUsed   = "PROMO_ALREADY_USED"
+	CodePromoMinimumNotMet = "PROMO_MINIMUM_NOT_MET"
+)
+
+// CodeInfo describes a catalog entry. MessageKey is the localization key
+// clients look up to show the error in the user's language; Status is the
+// HTTP status the code is returned with.
+type CodeInfo struct {
+	Code       string `json:"code"`
+	Status     int    `json:"status"`
+	MessageKey string `json:"messageKey"`
+	Message    string `json:"message"`
+}
+
+var catalog = map[string]CodeInfo{}
+
+func register(code string, status int, messageKey, message string) {
+	catalog[code] = CodeInfo{Code: code, Status: status, MessageKey: messageKey, Message: message}
+}
+
+func init() {
+	register(CodeBadRequest, http.StatusBadRequest, "errors.bad_request", "Bad request")
+	register(CodeUnauthorized, http.StatusUnauthorized, "errors.unauthorized", "Unauthorized")
+	register(CodeForbidden, http.StatusForbidden, "errors.forbidden", "Forbidden")
+	register(CodeNotFound, http.StatusNotFound, "errors.not_found", "Resource not found")
+	register(CodeConflict, http.StatusConflict, "errors.conflict", "Resource conflict")
+	register(CodeValidation, http.StatusUnprocessableEntity, "errors.validation", "Validation failed")
+	register(CodeInvalidField, http.StatusUnprocessableEntity, "errors.invalid_field", "Invalid field")
+	register(CodeInternal, http.StatusInternalServerError, "errors.internal", "Internal server error")
+	register(CodeRateLimitExceeded, http.StatusTooManyRequests, "errors.rate_limit_exceeded", "Too many requests")
+	register(CodeServiceUnavailable, http.StatusServiceUnavailable, "errors.service_unavailable", "Service unavailable")
+
+	register(CodeAuthRequired, http.StatusUnauthorized, "errors.auth.required", "Authentication required")
+	register(CodeInvalidCredentials, http.StatusUnauthorized, "errors.auth.invalid_credentials", "Invalid credentials")
+	register(CodeTokenInvalid, http.StatusUnauthorized, "errors.auth.token_invalid", "Invalid or expired token")
+	register(CodeTokenRevoked, http.StatusUnauthorized, "errors.auth.token_revoked", "Token has been revoked")
+	register(CodeSessionRevoked, http.StatusUnauthorized, "errors.auth.session_revoked", "Session has been revoked")
+	register(CodeSessionExpired, http.StatusUnauthorized, "errors.auth.session_expired", "Session has expired")
+	register(CodeRefreshTokenReused, http.StatusUnauthorized, "errors.auth.refresh_token_reused", "Refresh token has already been used; please sign in again")
+	register(CodeAccountInactive, http.StatusForbidden, "errors.account.inactive", "Account is not active")
+	register(CodeEmailInUse, http.StatusConflict, "errors.account.email_in_use", "Email already in use")
+	register(CodePhoneInUse, http.StatusConflict, "errors.account.phone_in_use", "Phone number already in use")
+	register(CodeInsufficientRole, http.StatusForbidden, "errors.auth.insufficient_permissions", "Insufficient permissions")
+	register(CodeImpersonationReadOnly, http.StatusForbidden, "errors.auth.impersonation_read_only", "This action is not allowed while impersonating a user")
+	register(CodeCaptchaFailed, http.StatusForbidden, "errors.captcha.failed", "Captcha verification failed")
+	register(CodeCaptchaUnavailable, http.StatusServiceUnavailable, "errors.captcha.unavailable", "Captcha verification unavailable")
+
+	register(CodeRideNotAvailable, http.StatusBadRequest, "errors.ride.not_available", "Ride is no longer available")
+	register(CodeRideAlreadyAccepted, http.StatusBadRequest, "errors.ride.already_accepted", "Ride already accepted by another driver")
+	register(CodeRideRequestExpired, http.StatusBadRequest, "errors.ride.request_expired", "Ride request has expired")
+	register(CodeRideInvalidStatus, http.StatusBadRequest, "errors.ride.invalid_status", "Invalid ride status")
+	register(CodeRideAlreadyCancelled, http.StatusBadRequest, "errors.ride.already_cancelled", "Ride was already cancelled")
+	register(CodeRideAlreadyCompleted, http.StatusBadRequest, "errors.ride.already_completed", "Cannot cancel a completed ride")
+	register(CodeRideInvalidPIN, http.StatusBadRequest, "errors.ride.invalid_pin", "Invalid Rider PIN")
+	register(CodeRideTooFarFromDropoff, http.StatusBadRequest, "errors.ride.too_far_from_dropoff", "You must be within 100 meters of the destination")
+	register(CodeRideDistanceOutOfRange, http.StatusBadRequest, "errors.ride.distance_out_of_range", "Trip distance is out of range")
+	register(CodeDriverOffline, http.StatusBadRequest, "errors.driver.offline", "Driver must be online to accept rides")
+	register(CodeDriverInspectionLapsed, http.StatusForbidden, "errors.driver.inspection_lapsed", "Your vehicle inspection has lapsed")
+	register(CodeDriverCreditExceeded, http.StatusForbidden, "errors.driver.credit_limit_exceeded", "Your wallet debt exceeds your credit limit")
+	register(CodeScheduledAtInvalid, http.StatusBadRequest, "errors.ride.scheduled_at_invalid", "scheduledAt must be a valid future RFC3339 timestamp")
+	register(CodeRideFareChanged, http.StatusConflict, "errors.ride.fare_changed", "The fare has changed; confirm the new quote to book the ride")
+	register(CodeVehicleTypeUnavailable, http.StatusBadRequest, "errors.ride.vehicle_type_unavailable", "This vehicle type is not available here")
+	register(CodeRideOverCapacity, http.StatusBadRequest, "errors.ride.over_capacity", "Too many passengers or bags for this vehicle type")
+
+	register(CodeWalletInsufficientFunds, http.StatusBadRequest, "errors.wallet.insufficient_funds", "Insufficient balance")
+	register(CodeWalletInactive, http.StatusBadRequest, "errors.wallet.inactive", "Wallet is not active")
+	register(CodeWalletSelfTransfer, http.StatusBadRequest, "errors.wallet.self_transfer", "Cannot transfer to yourself")
+	register(CodeWalletHoldInactive, http.StatusBadRequest, "errors.wallet.hold_inactive", "Hold is no longer active")
+
+	register(CodePromoInvalid, http.StatusBadRequest, "errors.promo.invalid", "Invalid or expired promo code")
+	register(CodePromoUsageLimit, http.StatusBadRequest, "errors.promo.usage_limit_reached", "Promo code usage limit reached")
+	register(CodePromoAlreadyUsed, http.StatusBadRequest, "errors.promo.already_used", "You have already used this promo code")
+	register(CodePromoMinimumNotMet, http.StatusBadRequest, "errors.promo.minimum_not_met", "Minimum ride amount not met")
+}
+
+// LookupCode returns the catalog entry for code.
+func LookupCode(code string) (CodeInfo, bool) {
+	info, ok := catalog[code]
+	return info, ok
+}
+
+// Catalog lists every registered code, for clients building their
+// translations.
+func Catalog() []CodeInfo {
+	codes := make([]CodeInfo, 0, len(catalog))
+	for _, info := range catalog {
+		codes = append(codes, info)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i].Code < codes[j].Code })
+	return codes
+}
+
+// CodedError builds an error from its catalog entry. An empty message uses
+// the catalog's default.
+func CodedError(code, message string, errors ...ErrorDetail) *AppError {
+	info, ok := catalog[code]
+	if !ok {
+		info = catalog[CodeInternal]
+		info.Code = code
+	}
+	if message == "" {
+		message = info.Message
+	}
+	return NewAppError(info.Status, message, code, errors, nil)
+}
@@ -19,6 +19,9 @@ type AppError struct {
 	Code       string
 	Errors     []ErrorDetail
 	Internal   error
+	// Data goes out with the error for clients that can recover from it, such
+	// as a fresh quote to confirm when a fare has changed.
+	Data interface{}
 }
 
 func (e *AppError) Error() string {
@@ -38,29 +41,35 @@ func NewAppError(statusCode int, message, code string, errors []ErrorDetail, int
 	}
 }
 
+// WithData attaches data the client needs to recover from the error.
+func (e *AppError) WithData(data interface{}) *AppError {
+	e.Data = data
+	return e
+}
+
 func (e *AppError) ToResponse(c *gin.Context) {
-	SendError(c, e.StatusCode, e.Message, e.Errors, e.Code)
+	sendError(c, e.StatusCode, e.Message, e.Errors, e.Data, e.Code)
 }
 
 func BadRequest(message string, errors ...ErrorDetail) *AppError {
 	if message == "" {
 		message = "Bad request"
 	}
-	return NewAppError(http.StatusBadRequest, message, "BAD_REQUEST", errors, nil)
+	return NewAppError(http.StatusBadRequest, message, CodeBadRequest, errors, nil)
 }
 
 func UnauthorizedError(message string) *AppError {
 	if message == "" {
 		message = "Unauthorized"
 	}
-	return NewAppError(http.StatusUnauthorized, message, "UNAUTHORIZED", nil, nil)
+	return NewAppError(http.StatusUnauthorized, message, CodeUnauthorized, nil, nil)
 }
 
 func ForbiddenError(message string) *AppError {
 	if message == "" {
 		message = "Forbidden"
 	}
-	return NewAppError(http.StatusForbidden, message, "FORBIDDEN", nil, nil)
+	return NewAppError(http.StatusForbidden, message, CodeForbidden, nil, nil)
 }
 
 func NotFoundError(resource string) *AppError {
@@ -68,49 +77,49 @@ func NotFoundError(resource string) *AppError {
 	if resource != "" {
 		message = fmt.Sprintf("%s not found", resource)
 	}
-	return NewAppError(http.StatusNotFound, message, "NOT_FOUND", nil, nil)
+	return NewAppError(http.StatusNotFound, message, CodeNotFound, nil, nil)
 }
 
 func ConflictError(message string) *AppError {
 	if message == "" {
 		message = "Resource conflict"
 	}
-	return NewAppError(http.StatusConflict, message, "CONFLICT", nil, nil)
+	return NewAppError(http.StatusConflict, message, CodeConflict, nil, nil)
 }
 
 func NewValidationAppError(message string, errors []ErrorDetail) *AppError {
 	if message == "" {
 		message = "Validation failed"
 	}
-	return NewAppError(http.StatusUnprocessableEntity, message, "VALIDATION_ERROR", errors, nil)
+	return NewAppError(http.StatusUnprocessableEntity, message, CodeValidation, errors, nil)
 }
 
 func InternalServerError(message string, internal error) *AppError {
 	if message == "" {
 		message = "Internal server error"
 	}
-	return NewAppError(http.StatusInternalServerError, message, "INTERNAL_ERROR", nil, internal)
+	return NewAppError(http.StatusInternalServerError, message, CodeInternal, nil, internal)
 }
 
 func TooManyRequests(message string) *AppError {
 	if message == "" {
 		message = "Too many requests"
 	}
-	return NewAppError(http.StatusTooManyRequests, message, "RATE_LIMIT_EXCEEDED", nil, nil)
+	return NewAppError(http.StatusTooManyRequests, message, CodeRateLimitExceeded, nil, nil)
 }
 
 func ServiceUnavailable(message string) *AppError {
 	if message == "" {
 		message = "Service unavailable"
 	}
-	return NewAppError(http.StatusServiceUnavailable, message, "SERVICE_UNAVAILABLE", nil, nil)
+	return NewAppError(http.StatusServiceUnavailable, message, CodeServiceUnavailable, nil, nil)
 }
 
 func NewValidationErrorDetail(field, message string) ErrorDetail {
 	return ErrorDetail{
 		Field:   field,
 		Message: message,
-		Code:    "INVALID_FIELD",
+		Code:    CodeInvalidField,
 	}
 }
 
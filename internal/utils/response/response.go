@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/services/i18n"
 	"github.com/umar5678/go-backend/internal/utils/logger"
 )
 
@@ -14,6 +15,8 @@ type Response struct {
 	Errors  []ErrorDetail `json:"errors,omitempty"`
 	Meta    Meta          `json:"meta"`
 	Code    string        `json:"code,omitempty"`
+	// MessageKey is the localization key of Code, set on errors only.
+	MessageKey string `json:"messageKey,omitempty"`
 }
 
 type Meta struct {
@@ -53,38 +56,53 @@ func Success(c *gin.Context, data interface{}, message string, code ...string) {
 }
 
 func SendError(c *gin.Context, statusCode int, message string, errors []ErrorDetail, code ...string) {
+	var errorCode string
+	if len(code) > 0 {
+		errorCode = code[0]
+	}
+	sendError(c, statusCode, message, errors, nil, errorCode)
+}
+
+func sendError(c *gin.Context, statusCode int, message string, errors []ErrorDetail, data interface{}, code string) {
 	resp := Response{
 		Success: false,
 		Message: message,
+		Data:    data,
 		Errors:  errors,
 		Meta:    extractMeta(c),
 	}
 
-	if len(code) > 0 {
-		resp.Code = code[0]
+	if code != "" {
+		resp.Code = code
+		if info, ok := LookupCode(resp.Code); ok {
+			resp.MessageKey = info.MessageKey
+			if lang := c.GetString("language"); lang != "" && lang != i18n.Default && i18n.Has(lang, info.MessageKey) {
+				resp.Message = i18n.T(lang, info.MessageKey, nil)
+			}
+		}
 	}
 
 	c.JSON(statusCode, resp)
 }
 
 func ValidationError(c *gin.Context, errors []ErrorDetail) {
-	SendError(c, 422, "Validation failed", errors, "VALIDATION_ERROR")
+	SendError(c, 422, "Validation failed", errors, CodeValidation)
 }
 
 func NotFound(c *gin.Context, message string) {
-	SendError(c, 404, message, nil, "NOT_FOUND")
+	SendError(c, 404, message, nil, CodeNotFound)
 }
 
 func Unauthorized(c *gin.Context, message string) {
-	SendError(c, 401, message, nil, "UNAUTHORIZED")
+	SendError(c, 401, message, nil, CodeUnauthorized)
 }
 
 func Forbidden(c *gin.Context, message string) {
-	SendError(c, 403, message, nil, "FORBIDDEN")
+	SendError(c, 403, message, nil, CodeForbidden)
 }
 
 func InternalError(c *gin.Context, message string) {
-	SendError(c, 500, message, nil, "INTERNAL_ERROR")
+	SendError(c, 500, message, nil, CodeInternal)
 }
 
 func extractMeta(c *gin.Context) Meta {
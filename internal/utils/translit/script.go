@@ -0,0 +1,113 @@
+package translit
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	ScriptLatin      = "latin"
+	ScriptArabic     = "arabic"
+	ScriptDevanagari = "devanagari"
+	ScriptCyrillic   = "cyrillic"
+	ScriptMixed      = "mixed"
+)
+
+// Address script preferences a provider can choose for order payloads.
+const (
+	PreferLatin  = "latin"
+	PreferNative = "native"
+)
+
+func scriptOf(r rune) string {
+	switch {
+	case unicode.Is(unicode.Arabic, r):
+		return ScriptArabic
+	case unicode.Is(unicode.Devanagari, r):
+		return ScriptDevanagari
+	case unicode.Is(unicode.Cyrillic, r):
+		return ScriptCyrillic
+	case unicode.Is(unicode.Latin, r):
+		return ScriptLatin
+	}
+	return ""
+}
+
+// DetectScript returns the script of the letters in s, ScriptMixed when more
+// than one script is present, or an empty string when s has no letters.
+func DetectScript(s string) string {
+	detected := ""
+	for _, r := range s {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		script := scriptOf(r)
+		if script == "" {
+			continue
+		}
+		if detected == "" {
+			detected = script
+		} else if detected != script {
+			return ScriptMixed
+		}
+	}
+	return detected
+}
+
+// NormalizedAddress holds an address as entered alongside its Latin-script form.
+type NormalizedAddress struct {
+	Original   string
+	Normalized string
+	Script     string
+}
+
+// NormalizeAddress cleans up spacing and punctuation and transliterates any
+// Arabic, Devanagari or Cyrillic text to Latin so navigation apps can search it.
+func NormalizeAddress(address string) NormalizedAddress {
+	cleaned := cleanAddress(address)
+	return NormalizedAddress{
+		Original:   address,
+		Normalized: cleanAddress(ToLatin(cleaned)),
+		Script:     DetectScript(cleaned),
+	}
+}
+
+// ForScript picks the address variant matching a provider's script preference.
+// Orders created before normalization only carry the original text.
+func ForScript(original, normalized, preferred string) string {
+	if preferred == PreferNative || normalized == "" {
+		return original
+	}
+	return normalized
+}
+
+var addressPunctuation = map[rune]rune{
+	'،': ',',
+	'؛': ';',
+	'۔': '.',
+	'।': '.',
+	'٫': '.',
+}
+
+func cleanAddress(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\u200b' || r == '\u200c' || r == '\u200d' || r == '\ufeff' || r == '\u0640':
+			continue
+		case unicode.IsSpace(r):
+			b.WriteRune(' ')
+			continue
+		}
+		if p, ok := addressPunctuation[r]; ok {
+			r = p
+		}
+		b.WriteRune(r)
+	}
+
+	fields := strings.Fields(b.String())
+	cleaned := strings.Join(fields, " ")
+	cleaned = strings.ReplaceAll(cleaned, " ,", ",")
+	cleaned = strings.ReplaceAll(cleaned, ",", ", ")
+	return strings.Join(strings.Fields(cleaned), " ")
+}
@@ -0,0 +1,190 @@
+package translit
+
+import (
+	"strings"
+	"unicode"
+)
+
+var arabicToLatin = map[rune]string{
+	'ا': "a", 'آ': "aa", 'أ': "a", 'إ': "i", 'ب': "b", 'پ': "p", 'ت': "t", 'ٹ': "t",
+	'ث': "s", 'ج': "j", 'چ': "ch", 'ح': "h", 'خ': "kh", 'د': "d", 'ڈ': "d", 'ذ': "z",
+	'ر': "r", 'ڑ': "r", 'ز': "z", 'ژ': "zh", 'س': "s", 'ش': "sh", 'ص': "s", 'ض': "z",
+	'ط': "t", 'ظ': "z", 'ع': "a", 'غ': "gh", 'ف': "f", 'ق': "q", 'ک': "k", 'ك': "k",
+	'گ': "g", 'ل': "l", 'م': "m", 'ن': "n", 'ں': "n", 'ہ': "h", 'ه': "h", 'ھ': "h",
+	'ة': "a", 'ء': "", 'ے': "e", 'ۓ': "e", 'ؤ': "o", 'ئ': "y",
+	'َ': "a", 'ِ': "i", 'ُ': "u", 'ً': "an", 'ٍ': "in", 'ٌ': "un", 'ّ': "", 'ْ': "",
+}
+
+var devanagariConsonants = map[rune]string{
+	'क': "k", 'ख': "kh", 'ग': "g", 'घ': "gh", 'ङ': "n", 'च': "ch", 'छ': "chh", 'ज': "j",
+	'झ': "jh", 'ञ': "n", 'ट': "t", 'ठ': "th", 'ड': "d", 'ढ': "dh", 'ण': "n", 'त': "t",
+	'थ': "th", 'द': "d", 'ध': "dh", 'न': "n", 'प': "p", 'फ': "ph", 'ब': "b", 'भ': "bh",
+	'म': "m", 'य': "y", 'र': "r", 'ल': "l", 'व': "v", 'श': "sh", 'ष': "sh", 'स': "s",
+	'ह': "h", 'ळ': "l", '\u0958': "q", '\u0959': "kh", '\u095a': "gh", '\u095b': "z", '\u095c': "r", '\u095d': "rh",
+	'\u095e': "f", '\u095f': "y",
+}
+
+var devanagariVowels = map[rune]string{
+	'अ': "a", 'आ': "aa", 'इ': "i", 'ई': "ee", 'उ': "u", 'ऊ': "oo", 'ऋ': "ri", 'ए': "e",
+	'ऐ': "ai", 'ओ': "o", 'औ': "au",
+	'ं': "n", 'ँ': "n", 'ः': "h",
+}
+
+var devanagariMatras = map[rune]string{
+	'ा': "aa", 'ि': "i", 'ी': "ee", 'ु': "u", 'ू': "oo", 'ृ': "ri", 'े': "e", 'ै': "ai",
+	'ो': "o", 'ौ': "au", '्': "",
+}
+
+var cyrillicToLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya", 'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g",
+}
+
+// ToLatin transliterates Arabic/Urdu, Devanagari and Cyrillic text to Latin.
+// Latin text, digits and punctuation pass through, with Arabic-Indic and
+// Devanagari digits folded to ASCII. Transliterated words are title-cased.
+func ToLatin(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		if d, ok := asciiDigit(r); ok {
+			b.WriteRune(d)
+			i++
+			continue
+		}
+
+		script := scriptOf(r)
+		if script == "" || script == ScriptLatin {
+			b.WriteRune(r)
+			i++
+			continue
+		}
+
+		j := i
+		for j < len(runes) && scriptOf(runes[j]) == script && !isDigit(runes[j]) {
+			j++
+		}
+
+		var word string
+		switch script {
+		case ScriptArabic:
+			word = arabicWord(runes[i:j])
+		case ScriptDevanagari:
+			word = devanagariWord(runes[i:j])
+		case ScriptCyrillic:
+			word = cyrillicWord(runes[i:j])
+		}
+		b.WriteString(titleCase(word))
+		i = j
+	}
+
+	return b.String()
+}
+
+func arabicWord(runes []rune) string {
+	var b strings.Builder
+	for i, r := range runes {
+		switch r {
+		case 'و':
+			if i == 0 {
+				b.WriteString("w")
+			} else {
+				b.WriteString("o")
+			}
+		case 'ی', 'ي', 'ى':
+			if i == 0 {
+				b.WriteString("y")
+			} else {
+				b.WriteString("i")
+			}
+		default:
+			if latin, ok := arabicToLatin[r]; ok {
+				b.WriteString(latin)
+			}
+		}
+	}
+	return b.String()
+}
+
+// Consonant plus nukta pairs are folded to their precomposed forms first.
+var devanagariNukta = map[rune]rune{
+	'क': '\u0958', 'ख': '\u0959', 'ग': '\u095a', 'ज': '\u095b',
+	'ड': '\u095c', 'ढ': '\u095d', 'फ': '\u095e', 'य': '\u095f',
+}
+
+// devanagariWord adds the inherent "a" after consonants unless a matra or
+// virama follows, and drops it at the end of the word.
+func devanagariWord(word []rune) string {
+	runes := make([]rune, 0, len(word))
+	for _, r := range word {
+		if r == '\u093c' && len(runes) > 0 {
+			if composed, ok := devanagariNukta[runes[len(runes)-1]]; ok {
+				runes[len(runes)-1] = composed
+			}
+			continue
+		}
+		runes = append(runes, r)
+	}
+
+	var b strings.Builder
+	for i, r := range runes {
+		if c, ok := devanagariConsonants[r]; ok {
+			b.WriteString(c)
+			if i+1 < len(runes) {
+				if _, matra := devanagariMatras[runes[i+1]]; !matra {
+					b.WriteString("a")
+				}
+			}
+			continue
+		}
+		if v, ok := devanagariVowels[r]; ok {
+			b.WriteString(v)
+			continue
+		}
+		if m, ok := devanagariMatras[r]; ok {
+			b.WriteString(m)
+		}
+	}
+	return b.String()
+}
+
+func cyrillicWord(runes []rune) string {
+	var b strings.Builder
+	for _, r := range runes {
+		if latin, ok := cyrillicToLatin[unicode.ToLower(r)]; ok {
+			b.WriteString(latin)
+		}
+	}
+	return b.String()
+}
+
+func titleCase(word string) string {
+	if word == "" {
+		return word
+	}
+	runes := []rune(word)
+	runes[0] = unicode.ToUpper(runes[0])
+	return string(runes)
+}
+
+func isDigit(r rune) bool {
+	_, ok := asciiDigit(r)
+	return ok
+}
+
+func asciiDigit(r rune) (rune, bool) {
+	switch {
+	case r >= '٠' && r <= '٩':
+		return '0' + (r - '٠'), true
+	case r >= '۰' && r <= '۹':
+		return '0' + (r - '۰'), true
+	case r >= '०' && r <= '९':
+		return '0' + (r - '०'), true
+	}
+	return 0, false
+}
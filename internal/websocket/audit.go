@@ -0,0 +1,397 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+// The message audit keeps a short, sampled history of what the server tried to
+// deliver to each user and what happened to it, so support can answer "the app
+// never got the event". Entries live in per-user Redis ring buffers and are
+// redacted before they are stored.
+const (
+	auditMessagesKeyPrefix    = "ws:audit:messages:"
+	auditConnectionsKeyPrefix = "ws:audit:connections:"
+	auditForcedKey            = "ws:audit:forced"
+
+	auditQueueSize      = 1024
+	auditForcedRefresh  = 15 * time.Second
+	auditRedactedValue  = "[redacted]"
+	auditCoordPrecision = 100 // two decimals, roughly 1 km
+)
+
+type AuditOutcome string
+
+const (
+	AuditQueued       AuditOutcome = "queued"
+	AuditDropped      AuditOutcome = "dropped_buffer_full"
+	AuditNoConnection AuditOutcome = "no_local_connection"
+	AuditWritten      AuditOutcome = "written"
+	AuditWriteFailed  AuditOutcome = "write_failed"
+)
+
+type ConnectionEventType string
+
+const (
+	ConnEventConnected    ConnectionEventType = "connected"
+	ConnEventReconnected  ConnectionEventType = "reconnected"
+	ConnEventDisconnected ConnectionEventType = "disconnected"
+	ConnEventWriteFailed  ConnectionEventType = "write_failed"
+)
+
+var defaultAuditRedactFields = []string{
+	"phone", "phoneNumber", "email", "otp", "pin", "ridePin",
+	"token", "accessToken", "refreshToken", "reconnectToken", "ticket",
+	"password", "secret", "cardNumber", "cvv",
+	"address", "pickupAddress", "dropoffAddress", "homeAddress",
+}
+
+var auditCoordinateFields = map[string]bool{
+	"lat": true, "lng": true, "lon": true, "latitude": true, "longitude": true,
+}
+
+type AuditConfig struct {
+	Enabled      bool
+	SampleRate   float64
+	MaxEntries   int
+	TTL          time.Duration
+	RedactFields []string
+}
+
+type AuditedMessage struct {
+	ID        string                 `json:"id"`
+	Type      MessageType            `json:"type"`
+	MessageID string                 `json:"messageId,omitempty"`
+	ClientID  string                 `json:"clientId,omitempty"`
+	Outcome   AuditOutcome           `json:"outcome"`
+	Detail    string                 `json:"detail,omitempty"`
+	Payload   map[string]interface{} `json:"payload,omitempty"`
+	SentAt    time.Time              `json:"sentAt"`
+	At        time.Time              `json:"at"`
+}
+
+type ConnectionEvent struct {
+	Event     ConnectionEventType `json:"event"`
+	ClientID  string              `json:"clientId"`
+	Role      string              `json:"role,omitempty"`
+	UserAgent string              `json:"userAgent,omitempty"`
+	Detail    string              `json:"detail,omitempty"`
+	At        time.Time           `json:"at"`
+}
+
+type auditEntry struct {
+	key     string
+	payload interface{}
+}
+
+// MessageAuditor records entries off the hot path: callers enqueue and a single
+// writer drains to Redis. When the queue is full entries are dropped rather
+// than slowing delivery.
+type MessageAuditor struct {
+	cfg    AuditConfig
+	redact map[string]bool
+	queue  chan auditEntry
+
+	forcedMu sync.RWMutex
+	forced   map[string]time.Time
+}
+
+func NewMessageAuditor(cfg AuditConfig) *MessageAuditor {
+	if cfg.MaxEntries <= 0 {
+		cfg.MaxEntries = 200
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = 24 * time.Hour
+	}
+	if len(cfg.RedactFields) == 0 {
+		cfg.RedactFields = defaultAuditRedactFields
+	}
+
+	redact := make(map[string]bool, len(cfg.RedactFields))
+	for _, field := range cfg.RedactFields {
+		redact[strings.ToLower(strings.TrimSpace(field))] = true
+	}
+
+	return &MessageAuditor{
+		cfg:    cfg,
+		redact: redact,
+		queue:  make(chan auditEntry, auditQueueSize),
+		forced: make(map[string]time.Time),
+	}
+}
+
+func (a *MessageAuditor) Enabled() bool {
+	return a != nil && a.cfg.Enabled
+}
+
+func (a *MessageAuditor) Run(ctx context.Context) {
+	if !a.Enabled() {
+		return
+	}
+
+	a.refreshForced(ctx)
+	ticker := time.NewTicker(auditForcedRefresh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			a.refreshForced(ctx)
+		case entry := <-a.queue:
+			a.write(ctx, entry)
+		}
+	}
+}
+
+// RecordMessage logs a delivery outcome for a sampled message. The sampling
+// decision is derived from the message itself so every stage of one message
+// is either recorded or skipped together.
+func (a *MessageAuditor) RecordMessage(userID, clientID string, msg *Message, outcome AuditOutcome, detail string) {
+	if !a.Enabled() || userID == "" || msg == nil {
+		return
+	}
+	if !a.isForced(userID) && !a.sampled(userID, msg) {
+		return
+	}
+
+	a.enqueue(auditMessagesKeyPrefix+userID, AuditedMessage{
+		ID:        uuid.NewString(),
+		Type:      msg.Type,
+		MessageID: msg.MessageID,
+		ClientID:  clientID,
+		Outcome:   outcome,
+		Detail:    detail,
+		Payload:   a.redactMap(msg.Data),
+		SentAt:    msg.Timestamp,
+		At:        time.Now().UTC(),
+	})
+}
+
+// RecordConnection logs a connection lifecycle event. These are low volume and
+// always recorded while the audit is enabled.
+func (a *MessageAuditor) RecordConnection(client *Client, event ConnectionEventType, detail string) {
+	if !a.Enabled() || client == nil {
+		return
+	}
+
+	a.enqueue(auditConnectionsKeyPrefix+client.UserID, ConnectionEvent{
+		Event:     event,
+		ClientID:  client.ID,
+		Role:      string(client.Role),
+		UserAgent: client.UserAgent,
+		Detail:    detail,
+		At:        time.Now().UTC(),
+	})
+}
+
+func (a *MessageAuditor) GetUserLog(ctx context.Context, userID string, limit int) ([]AuditedMessage, []ConnectionEvent, error) {
+	if limit <= 0 || limit > a.cfg.MaxEntries {
+		limit = a.cfg.MaxEntries
+	}
+
+	rawMessages, err := cache.CacheClient.LRange(ctx, auditMessagesKeyPrefix+userID, 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, nil, err
+	}
+	rawEvents, err := cache.CacheClient.LRange(ctx, auditConnectionsKeyPrefix+userID, 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	messages := make([]AuditedMessage, 0, len(rawMessages))
+	for _, raw := range rawMessages {
+		var m AuditedMessage
+		if err := json.Unmarshal([]byte(raw), &m); err == nil {
+			messages = append(messages, m)
+		}
+	}
+	events := make([]ConnectionEvent, 0, len(rawEvents))
+	for _, raw := range rawEvents {
+		var e ConnectionEvent
+		if err := json.Unmarshal([]byte(raw), &e); err == nil {
+			events = append(events, e)
+		}
+	}
+
+	return messages, events, nil
+}
+
+// ForceCapture records every message for the user until the window ends,
+// regardless of the sample rate.
+func (a *MessageAuditor) ForceCapture(ctx context.Context, userID string, d time.Duration) (time.Time, error) {
+	until := time.Now().UTC().Add(d)
+	if err := cache.CacheClient.HSet(ctx, auditForcedKey, userID, until.Unix()).Err(); err != nil {
+		return time.Time{}, err
+	}
+
+	a.forcedMu.Lock()
+	a.forced[userID] = until
+	a.forcedMu.Unlock()
+
+	return until, nil
+}
+
+func (a *MessageAuditor) StopCapture(ctx context.Context, userID string) error {
+	if err := cache.CacheClient.HDel(ctx, auditForcedKey, userID).Err(); err != nil {
+		return err
+	}
+
+	a.forcedMu.Lock()
+	delete(a.forced, userID)
+	a.forcedMu.Unlock()
+
+	return nil
+}
+
+func (a *MessageAuditor) ForcedUntil(userID string) *time.Time {
+	a.forcedMu.RLock()
+	defer a.forcedMu.RUnlock()
+
+	until, ok := a.forced[userID]
+	if !ok || time.Now().After(until) {
+		return nil
+	}
+	return &until
+}
+
+func (a *MessageAuditor) SampleRate() float64 {
+	return a.cfg.SampleRate
+}
+
+func (a *MessageAuditor) enqueue(key string, payload interface{}) {
+	select {
+	case a.queue <- auditEntry{key: key, payload: payload}:
+	default:
+		logger.Debug("websocket audit queue full, entry dropped", "key", key)
+	}
+}
+
+func (a *MessageAuditor) write(ctx context.Context, entry auditEntry) {
+	data, err := json.Marshal(entry.payload)
+	if err != nil {
+		return
+	}
+
+	pipe := cache.CacheClient.Pipeline()
+	pipe.LPush(ctx, entry.key, data)
+	pipe.LTrim(ctx, entry.key, 0, int64(a.cfg.MaxEntries-1))
+	pipe.Expire(ctx, entry.key, a.cfg.TTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		logger.Warn("failed to write websocket audit entry", "error", err, "key", entry.key)
+	}
+}
+
+// refreshForced syncs the force-capture list from Redis so a window opened on
+// one instance applies on all of them. Expired entries are cleaned up here.
+func (a *MessageAuditor) refreshForced(ctx context.Context) {
+	values, err := cache.CacheClient.HGetAll(ctx, auditForcedKey).Result()
+	if err != nil {
+		logger.Warn("failed to load websocket audit capture list", "error", err)
+		return
+	}
+
+	now := time.Now()
+	forced := make(map[string]time.Time, len(values))
+	for userID, raw := range values {
+		unix, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			continue
+		}
+		until := time.Unix(unix, 0)
+		if now.After(until) {
+			cache.CacheClient.HDel(ctx, auditForcedKey, userID)
+			continue
+		}
+		forced[userID] = until
+	}
+
+	a.forcedMu.Lock()
+	a.forced = forced
+	a.forcedMu.Unlock()
+}
+
+func (a *MessageAuditor) isForced(userID string) bool {
+	return a.ForcedUntil(userID) != nil
+}
+
+func (a *MessageAuditor) sampled(userID string, msg *Message) bool {
+	if a.cfg.SampleRate >= 1 {
+		return true
+	}
+	if a.cfg.SampleRate <= 0 {
+		return false
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(userID))
+	h.Write([]byte(msg.Type))
+	h.Write([]byte(msg.MessageID))
+	h.Write([]byte(msg.Timestamp.Format(time.RFC3339Nano)))
+	return float64(h.Sum32()%10000) < a.cfg.SampleRate*10000
+}
+
+func (a *MessageAuditor) redactMap(data map[string]interface{}) map[string]interface{} {
+	if data == nil {
+		return nil
+	}
+	out := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		out[k] = a.redactValue(k, v)
+	}
+	return out
+}
+
+func (a *MessageAuditor) redactValue(key string, v interface{}) interface{} {
+	lower := strings.ToLower(key)
+	if a.redact[lower] {
+		return auditRedactedValue
+	}
+	if auditCoordinateFields[lower] {
+		if f, ok := v.(float64); ok {
+			return math.Round(f*auditCoordPrecision) / auditCoordPrecision
+		}
+	}
+
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return a.redactMap(val)
+	case []interface{}:
+		items := make([]interface{}, len(val))
+		for i, item := range val {
+			items[i] = a.redactValue("", item)
+		}
+		return items
+	case string, float64, float32, int, int64, int32, bool, nil:
+		return val
+	default:
+		// Structs and typed slices are normalised through JSON so their
+		// fields are redacted the same way as plain maps.
+		raw, err := json.Marshal(val)
+		if err != nil {
+			return nil
+		}
+		var generic interface{}
+		if err := json.Unmarshal(raw, &generic); err != nil {
+			return nil
+		}
+		if m, ok := generic.(map[string]interface{}); ok {
+			return a.redactMap(m)
+		}
+		if items, ok := generic.([]interface{}); ok {
+			return a.redactValue(key, items)
+		}
+		return generic
+	}
+}
@@ -0,0 +1,81 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+// Revoking a login session must drop the sockets it opened on every instance,
+// so revocations are fanned out over pub/sub rather than handled locally.
+const (
+	authSessionRevokedChannel = "websocket:auth_session_revoked"
+	CloseSessionRevoked       = 4001
+)
+
+type authSessionRevocation struct {
+	UserID        string `json:"userId"`
+	AuthSessionID string `json:"authSessionId,omitempty"`
+}
+
+// RevokeAuthSession disconnects the user's sockets opened under the given login
+// session. An empty authSessionID disconnects all of the user's sockets.
+func RevokeAuthSession(ctx context.Context, userID, authSessionID string) error {
+	return cache.PublishMessage(ctx, authSessionRevokedChannel, authSessionRevocation{
+		UserID:        userID,
+		AuthSessionID: authSessionID,
+	})
+}
+
+func (h *Hub) listenAuthSessionRevocations(ctx context.Context) {
+	pubsub := cache.SubscribeChannel(ctx, authSessionRevokedChannel)
+	defer pubsub.Close()
+
+	for {
+		msg, err := pubsub.ReceiveMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			logger.Error("auth session revocation receive error", "error", err)
+			continue
+		}
+
+		var rev authSessionRevocation
+		if err := json.Unmarshal([]byte(msg.Payload), &rev); err != nil {
+			logger.Error("failed to unmarshal auth session revocation", "error", err)
+			continue
+		}
+
+		h.disconnectAuthSession(rev.UserID, rev.AuthSessionID)
+	}
+}
+
+func (h *Hub) disconnectAuthSession(userID, authSessionID string) {
+	h.mu.RLock()
+	targets := make([]*Client, 0, len(h.clients[userID]))
+	for _, client := range h.clients[userID] {
+		if authSessionID == "" || client.AuthSessionID == authSessionID {
+			targets = append(targets, client)
+		}
+	}
+	h.mu.RUnlock()
+
+	closeMsg := websocket.FormatCloseMessage(CloseSessionRevoked, "session revoked")
+	for _, client := range targets {
+		client.conn.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(writeWait))
+		client.conn.Close()
+	}
+
+	if len(targets) > 0 {
+		logger.Info("websocket connections closed for revoked session",
+			"userID", userID,
+			"authSessionID", authSessionID,
+			"count", len(targets),
+		)
+	}
+}
@@ -25,6 +25,7 @@ type Client struct {
 	UserID         string
 	UserAgent      string
 	Role           models.UserRole
+	AuthSessionID  string
 	hub            *Hub
 	manager        *Manager
 	conn           *websocket.Conn
@@ -128,6 +129,8 @@ func (c *Client) WritePump() {
 			}
 
 			if err := c.conn.WriteJSON(message); err != nil {
+				c.hub.auditor.RecordMessage(c.UserID, c.ID, message, AuditWriteFailed, err.Error())
+				c.hub.auditor.RecordConnection(c, ConnEventWriteFailed, err.Error())
 				logger.Error("websocket write error",
 					"error", err,
 					"userID", c.UserID,
@@ -137,6 +140,7 @@ func (c *Client) WritePump() {
 				)
 				return
 			}
+			c.hub.auditor.RecordMessage(c.UserID, c.ID, message, AuditWritten, "")
 
 			if message.RequireAck && message.MessageID != "" {
 				c.pendingAcks[message.MessageID] = message
@@ -55,11 +55,17 @@ func (cl *ClientLifecycle) OnClientConnect(client *Client) error {
 		"role", client.Role,
 	)
 
-	msg := NewMessage(TypeConnectionAck, map[string]interface{}{
+	ackData := map[string]interface{}{
 		"sessionId": session.SessionID,
 		"clientId":  client.ID,
 		"role":      client.Role,
-	})
+	}
+	if ticket := cl.issueResumeTicket(client, session.SessionID); ticket != nil {
+		ackData["resumeTicket"] = ticket.Ticket
+		ackData["resumeTicketExpiresAt"] = ticket.ExpiresAt
+	}
+
+	msg := NewMessage(TypeConnectionAck, ackData)
 	msg.RequireAck = false
 
 	select {
@@ -72,8 +78,10 @@ func (cl *ClientLifecycle) OnClientConnect(client *Client) error {
 }
 
 // OnClientDisconnect handles disconnection of a client
-// Returns the session ID if the session should be kept alive for reconnection
-func (cl *ClientLifecycle) OnClientDisconnect(client *Client) string {
+// Returns the session ID if the session should be kept alive for reconnection.
+// While the server drains the driver keeps its online status, since it is
+// expected to reconnect to another instance.
+func (cl *ClientLifecycle) OnClientDisconnect(client *Client, draining bool) string {
 	client.mu.RLock()
 	sessionID := client.reconnectToken
 	client.mu.RUnlock()
@@ -90,7 +98,7 @@ func (cl *ClientLifecycle) OnClientDisconnect(client *Client) string {
 	}
 
 	// Mark driver offline when last connection is lost (will be called from hub after checking device count)
-	if client.Role == models.RoleDriver && cl.db != nil {
+	if client.Role == models.RoleDriver && cl.db != nil && !draining {
 		if err := cl.markDriverOffline(client.UserID); err != nil {
 			logger.Warn("failed to mark driver offline in database", "error", err, "driverId", client.UserID)
 		}
@@ -124,9 +132,39 @@ func (cl *ClientLifecycle) OnClientReconnect(client *Client, sessionID string) e
 		"reconnectionCount", session.ReconnectionCount,
 	)
 
+	// Rotate: the ticket used for this resume is spent, hand out the next one.
+	if ticket := cl.issueResumeTicket(client, session.SessionID); ticket != nil {
+		msg := NewMessage(TypeResumeTicket, map[string]interface{}{
+			"sessionId":    session.SessionID,
+			"resumeTicket": ticket.Ticket,
+			"expiresAt":    ticket.ExpiresAt,
+		})
+		msg.RequireAck = false
+
+		select {
+		case client.send <- msg:
+		default:
+			logger.Warn("failed to send resume ticket", "clientId", client.ID)
+		}
+	}
+
 	return nil
 }
 
+func (cl *ClientLifecycle) issueResumeTicket(client *Client, sessionID string) *ResumeTicket {
+	if !ResumeTicketEligible(client.Role) {
+		return nil
+	}
+
+	ticket, err := IssueResumeTicket(client.ctx, client.UserID, client.Role, sessionID, client.AuthSessionID, cl.sessionManager.resumeTicketTTL)
+	if err != nil {
+		logger.Warn("failed to issue resume ticket", "error", err, "userId", client.UserID, "sessionId", sessionID)
+		return nil
+	}
+
+	return ticket
+}
+
 func (cl *ClientLifecycle) GetSessionInfo(sessionID string) (*SessionState, error) {
 	return cl.sessionManager.GetSession(sessionID)
 }
@@ -0,0 +1,106 @@
+package websocket
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+const (
+	// Clients spread their reconnects over this window so a restart does not
+	// bring every socket back to the remaining instances at once.
+	drainReconnectJitter = 5 * time.Second
+	drainFlushPoll       = 100 * time.Millisecond
+	drainCloseWait       = time.Second
+)
+
+// Drain prepares the hub for shutdown: new connections are refused, every
+// client is told to reconnect (with its session, and a fresh resume ticket
+// where eligible) and, once its queued messages are written or ctx is done,
+// is closed with a "service restart" close frame. Drivers are not marked
+// offline while draining since they are expected back on another instance.
+func (m *Manager) Drain(ctx context.Context) {
+	h := m.hub
+
+	h.mu.Lock()
+	h.draining = true
+	clients := make([]*Client, 0, h.getTotalConnectionsUnsafe())
+	for _, userClients := range h.clients {
+		clients = append(clients, userClients...)
+	}
+	h.mu.Unlock()
+
+	logger.Info("draining websocket connections", "connections", len(clients))
+
+	for _, client := range clients {
+		client.mu.RLock()
+		sessionID := client.reconnectToken
+		client.mu.RUnlock()
+
+		data := map[string]interface{}{
+			"reconnectAfterMs": rand.Int63n(drainReconnectJitter.Milliseconds()),
+		}
+		if sessionID != "" {
+			data["sessionId"] = sessionID
+			if h.clientLifecycle != nil {
+				if ticket := h.clientLifecycle.issueResumeTicket(client, sessionID); ticket != nil {
+					data["resumeTicket"] = ticket.Ticket
+					data["resumeTicketExpiresAt"] = ticket.ExpiresAt
+				}
+			}
+		}
+
+		msg := NewMessage(TypeServerRestarting, data)
+		msg.RequireAck = false
+		select {
+		case client.send <- msg:
+		default:
+			logger.Warn("failed to send restart notice", "clientId", client.ID, "userId", client.UserID)
+		}
+	}
+
+	ticker := time.NewTicker(drainFlushPoll)
+	defer ticker.Stop()
+	for !sendBuffersEmpty(clients) {
+		select {
+		case <-ctx.Done():
+			logger.Warn("websocket drain timed out with messages still queued")
+			closeForRestart(clients)
+			return
+		case <-ticker.C:
+		}
+	}
+
+	closeForRestart(clients)
+	logger.Info("websocket connections drained", "connections", len(clients))
+}
+
+func (h *Hub) isDraining() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.draining
+}
+
+func sendBuffersEmpty(clients []*Client) bool {
+	for _, client := range clients {
+		if len(client.send) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// closeForRestart starts the close handshake; the read pump sees the peer's
+// reply and unregisters the client as usual.
+func closeForRestart(clients []*Client) {
+	message := websocket.FormatCloseMessage(websocket.CloseServiceRestart, "server restarting")
+	deadline := time.Now().Add(drainCloseWait)
+	for _, client := range clients {
+		if err := client.conn.WriteControl(websocket.CloseMessage, message, deadline); err != nil {
+			logger.Debug("failed to send close frame", "clientId", client.ID, "error", err)
+		}
+	}
+}
@@ -1,14 +1,51 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/services/livemetrics"
+	"github.com/umar5678/go-backend/internal/utils/logger"
 	"github.com/umar5678/go-backend/internal/websocket"
 	"github.com/umar5678/go-backend/internal/websocket/websocketutils"
 )
 
-func RegisterAllHandlers(manager *websocket.Manager) {
+// RegisterAllHandlers registers every handler group. A group that fails does
+// not stop the others; the returned error names each group that failed so the
+// startup report can show a partial registration.
+func RegisterAllHandlers(manager *websocket.Manager) error {
+	groups := []struct {
+		name     string
+		register func(*websocket.Manager)
+	}{
+		{"ride", RegisterRideHandlers},
+		{"admin_support", RegisterAdminSupportHandlers},
+		{"admin_metrics", RegisterAdminMetricsHandlers},
+	}
+
+	var errs []error
+	for _, g := range groups {
+		if err := registerGroup(manager, g.name, g.register); err != nil {
+			logger.Error("websocket handler group failed to register", "group", g.name, "error", err)
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func registerGroup(manager *websocket.Manager, name string, register func(*websocket.Manager)) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%s handlers: %v", name, r)
+		}
+	}()
 
-	RegisterRideHandlers(manager)
-	RegisterAdminSupportHandlers(manager)
+	register(manager)
+	return nil
 }
 
 // Register handlers for admin support chat and SOS location updates
@@ -33,3 +70,29 @@ func HandleSOSAlert(client *websocket.Client, msg *websocket.Message) error {
 	sosActive, _ := msg.Data["sosActive"].(bool)
 	return websocketutils.SendSOSLocationUpdate(userID, location, sosActive)
 }
+
+// Register handlers for the admin live dashboard
+func RegisterAdminMetricsHandlers(manager *websocket.Manager) {
+	manager.RegisterHandler(websocket.TypeAdminLiveMetricsRequest, HandleAdminLiveMetricsRequest)
+}
+
+// Handler that sends the current live counters to an admin on demand, e.g. right
+// after connecting, instead of waiting for the next periodic push
+func HandleAdminLiveMetricsRequest(client *websocket.Client, msg *websocket.Message) error {
+	if client.Role != models.RoleAdmin {
+		return client.SendError("live metrics are only available to admins", msg.RequestID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	snapshot, err := livemetrics.GetSnapshot(ctx)
+	if err != nil {
+		return err
+	}
+
+	reply := websocket.NewMessage(websocket.TypeAdminLiveMetrics, snapshot.ToMap())
+	reply.RequestID = msg.RequestID
+	client.Manager().Hub().SendToUser(client.UserID, reply)
+	return nil
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/umar5678/go-backend/internal/modules/homeservices/customer/dto"
+	"github.com/umar5678/go-backend/internal/modules/homeservices/shared"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+	"github.com/umar5678/go-backend/internal/utils/response"
+	"github.com/umar5678/go-backend/internal/websocket"
+)
+
+// OrderAdjustmentResponder answers scope adjustments for the customer. It is
+// satisfied by the home services customer service.
+type OrderAdjustmentResponder interface {
+	ApproveAdjustment(ctx context.Context, customerID, orderID, adjustmentID string, req dto.RespondAdjustmentRequest) (*shared.AdjustmentResponse, error)
+	RejectAdjustment(ctx context.Context, customerID, orderID, adjustmentID string, req dto.RespondAdjustmentRequest) (*shared.AdjustmentResponse, error)
+}
+
+type OrderAdjustmentHandler struct {
+	responder OrderAdjustmentResponder
+}
+
+func RegisterOrderAdjustmentHandlers(manager *websocket.Manager, responder OrderAdjustmentResponder) {
+	handler := &OrderAdjustmentHandler{responder: responder}
+
+	manager.RegisterHandler(websocket.TypeOrderAdjustmentRespond, handler.HandleRespond)
+
+	logger.Info("order adjustment websocket handlers registered")
+}
+
+// HandleRespond lets the customer approve or reject an adjustment straight
+// from the prompt in the app. The sender gets an ack with the outcome; the
+// provider is notified the same way as for the REST endpoints.
+func (h *OrderAdjustmentHandler) HandleRespond(client *websocket.Client, msg *websocket.Message) error {
+	orderID, _ := msg.Data["orderId"].(string)
+	adjustmentID, _ := msg.Data["adjustmentId"].(string)
+	approve, ok := msg.Data["approve"].(bool)
+	note, _ := msg.Data["note"].(string)
+	if orderID == "" || adjustmentID == "" || !ok {
+		return client.SendError("orderId, adjustmentId and approve are required", msg.RequestID)
+	}
+	if len(note) > 500 {
+		return client.SendError("note must be at most 500 characters", msg.RequestID)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	respond := h.responder.RejectAdjustment
+	if approve {
+		respond = h.responder.ApproveAdjustment
+	}
+	result, err := respond(ctx, client.UserID, orderID, adjustmentID, dto.RespondAdjustmentRequest{Note: note})
+	if err != nil {
+		logger.Warn("failed to answer order adjustment", "error", err, "orderID", orderID, "adjustmentID", adjustmentID)
+		var appErr *response.AppError
+		if errors.As(err, &appErr) {
+			return client.SendError(appErr.Message, msg.RequestID)
+		}
+		return client.SendError("failed to answer order adjustment", msg.RequestID)
+	}
+
+	return client.SendAck(msg.RequestID, map[string]interface{}{
+		"orderId":      result.OrderID,
+		"adjustmentId": result.ID,
+		"status":       result.Status,
+		"newTotal":     result.NewTotal,
+	})
+}
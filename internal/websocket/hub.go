@@ -3,6 +3,7 @@ package websocket
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"sync"
 	"time"
 
@@ -11,6 +12,13 @@ import (
 	"github.com/umar5678/go-backend/internal/utils/logger"
 )
 
+// EventRecorder persists replayable events so clients coming back from a long
+// offline period can catch up over HTTP. It returns the event's replay cursor,
+// or an empty string when the message type is not recorded.
+type EventRecorder interface {
+	Record(ctx context.Context, userID string, msg *Message) (string, error)
+}
+
 type Hub struct {
 	clients           map[string][]*Client
 	drivers           map[string][]*Client
@@ -19,6 +27,10 @@ type Hub struct {
 	safetyTeamClients []*Client
 	clientLifecycle   *ClientLifecycle
 	sessionManager    *SessionManager
+	auditor           *MessageAuditor
+	offlineQueue      *OfflineQueue
+	eventRecorder     EventRecorder
+	draining          bool
 	mu                sync.RWMutex
 	register          chan *Client
 	unregister        chan *Client
@@ -46,9 +58,22 @@ func (h *Hub) SetSessionManager(sessionManager *SessionManager) {
 	h.sessionManager = sessionManager
 }
 
+func (h *Hub) SetAuditor(auditor *MessageAuditor) {
+	h.auditor = auditor
+}
+
+func (h *Hub) SetOfflineQueue(queue *OfflineQueue) {
+	h.offlineQueue = queue
+}
+
+func (h *Hub) SetEventRecorder(recorder EventRecorder) {
+	h.eventRecorder = recorder
+}
+
 func (h *Hub) Run(ctx context.Context) {
 	pubsub := cache.SubscribeChannel(ctx, "websocket:broadcast")
 	defer pubsub.Close()
+	go h.listenAuthSessionRevocations(ctx)
 	go func() {
 		for {
 			select {
@@ -133,6 +158,7 @@ func (h *Hub) registerClient(client *Client) {
 	}
 
 	deviceCount := len(h.clients[client.UserID])
+	h.auditor.RecordConnection(client, ConnEventConnected, fmt.Sprintf("devices=%d", deviceCount))
 
 	logger.Info("WebSocket client registered",
 		"userID", client.UserID,
@@ -181,6 +207,83 @@ func (h *Hub) registerClient(client *Client) {
 		"timestamp": time.Now().UTC(),
 	})
 	client.send <- ackMsg
+
+	h.redeliverPending(client)
+}
+
+func (h *Hub) recordEvent(userID string, msg *Message) {
+	if h.eventRecorder == nil || userID == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), offlineQueueTimeout)
+	defer cancel()
+
+	cursor, err := h.eventRecorder.Record(ctx, userID, msg)
+	if err != nil {
+		logger.Warn("failed to record event for replay",
+			"error", err,
+			"userID", userID,
+			"messageType", msg.Type,
+		)
+		return
+	}
+	msg.Cursor = cursor
+}
+
+// queueForRedelivery holds critical messages until the user acks them. It runs
+// before delivery so the message carries its ID and ack flag to every device.
+func (h *Hub) queueForRedelivery(userID string, msg *Message) {
+	if !h.offlineQueue.Enabled() || !IsCritical(msg.Type) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), offlineQueueTimeout)
+	defer cancel()
+
+	if err := h.offlineQueue.Enqueue(ctx, userID, msg); err != nil {
+		logger.Warn("failed to queue message for redelivery",
+			"error", err,
+			"userID", userID,
+			"messageType", msg.Type,
+		)
+	}
+}
+
+func (h *Hub) redeliverPending(client *Client) {
+	if !h.offlineQueue.Enabled() {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), offlineQueueTimeout)
+	defer cancel()
+
+	pending, err := h.offlineQueue.Pending(ctx, client.UserID)
+	if err != nil {
+		logger.Warn("failed to load pending messages", "error", err, "userID", client.UserID)
+		return
+	}
+
+	delivered := 0
+	for _, msg := range pending {
+		msg.Redelivered = true
+		select {
+		case client.send <- msg:
+			delivered++
+			h.auditor.RecordMessage(client.UserID, client.ID, msg, AuditQueued, "redelivered")
+		default:
+			h.auditor.RecordMessage(client.UserID, client.ID, msg, AuditDropped, "redelivery")
+		}
+	}
+
+	if len(pending) > 0 {
+		logger.Info("redelivered pending messages",
+			"userID", client.UserID,
+			"clientID", client.ID,
+			"pending", len(pending),
+			"delivered", delivered,
+		)
+	}
 }
 
 func (h *Hub) DebugInfo() map[string]interface{} {
@@ -226,6 +329,7 @@ func (h *Hub) unregisterClient(client *Client) {
 			if c.ID == client.ID {
 				h.clients[client.UserID] = append(clients[:i], clients[i+1:]...)
 				close(c.send)
+				h.auditor.RecordConnection(client, ConnEventDisconnected, fmt.Sprintf("devices=%d", len(h.clients[client.UserID])))
 
 				logger.Debug("Client found and removed from slice",
 					"userID", client.UserID,
@@ -256,7 +360,7 @@ func (h *Hub) unregisterClient(client *Client) {
 
 			// Call lifecycle handler only for complete disconnect
 			if h.clientLifecycle != nil {
-				h.clientLifecycle.OnClientDisconnect(client)
+				h.clientLifecycle.OnClientDisconnect(client, h.draining)
 			}
 
 			ctx := context.Background()
@@ -343,6 +447,7 @@ func (h *Hub) broadcastMessage(message *Message) {
 				select {
 				case client.send <- message:
 					successCount++
+					h.auditor.RecordMessage(client.UserID, client.ID, message, AuditQueued, "")
 					logger.Debug("Message queued to client",
 						"userID", client.UserID,
 						"clientID", client.ID,
@@ -351,6 +456,7 @@ func (h *Hub) broadcastMessage(message *Message) {
 						"queueSize", len(client.send),
 					)
 				default:
+					h.auditor.RecordMessage(client.UserID, client.ID, message, AuditDropped, fmt.Sprintf("buffer=%d", cap(client.send)))
 					logger.Warn("Client send buffer full - message dropped",
 						"userID", client.UserID,
 						"clientID", client.ID,
@@ -369,6 +475,9 @@ func (h *Hub) broadcastMessage(message *Message) {
 				"failedDeliveries", len(clients)-successCount,
 			)
 		} else {
+			// Another instance may still hold the user's socket; this only
+			// records that the message had nowhere to go on this one.
+			h.auditor.RecordMessage(message.TargetUserID, "", message, AuditNoConnection, "")
 			logger.Warn("Target user not connected",
 				"targetUserID", message.TargetUserID,
 				"messageType", message.Type,
@@ -484,6 +593,8 @@ func (h *Hub) SendToUser(userID string, msg *Message) {
 	)
 
 	msg.TargetUserID = userID
+	h.recordEvent(userID, msg)
+	h.queueForRedelivery(userID, msg)
 	h.broadcast <- msg
 
 	logger.Debug("Message queued for broadcast",
@@ -495,6 +606,9 @@ func (h *Hub) SendToUser(userID string, msg *Message) {
 }
 
 func (h *Hub) SendToDriver(driverID string, msg *Message) {
+	h.recordEvent(driverID, msg)
+	h.queueForRedelivery(driverID, msg)
+
 	h.mu.RLock()
 	clients, exists := h.drivers[driverID]
 	h.mu.RUnlock()
@@ -522,6 +636,9 @@ func (h *Hub) SendToDriver(driverID string, msg *Message) {
 }
 
 func (h *Hub) SendToRider(riderID string, msg *Message) {
+	h.recordEvent(riderID, msg)
+	h.queueForRedelivery(riderID, msg)
+
 	h.mu.RLock()
 	clients, exists := h.riders[riderID]
 	h.mu.RUnlock()
@@ -583,6 +700,12 @@ func (h *Hub) GetConnectedRiders() int {
 	return len(h.riders)
 }
 
+func (h *Hub) GetConnectedAdmins() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.adminClients)
+}
+
 func (h *Hub) BroadcastToAll(msg *Message) {
 	logger.Info("BroadcastToAll called",
 		"messageType", msg.Type,
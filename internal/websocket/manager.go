@@ -3,6 +3,7 @@ package websocket
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -23,6 +24,8 @@ type Manager struct {
 	reconnectionHandler  *ReconnectionHandler
 	reliableMessageQueue *ReliableMessageQueue
 	connectionMonitor    *ConnectionMonitor
+	auditor              *MessageAuditor
+	offlineQueue         *OfflineQueue
 	ctx                  context.Context
 	cancel               context.CancelFunc
 	wg                   sync.WaitGroup
@@ -40,6 +43,15 @@ type Config struct {
 	PersistenceMode     string        // "rdb", "aof", or "both"
 	RDBSnapshotInterval time.Duration // Interval for RDB snapshots
 	AOFSyncPolicy       string        // "always", "everysec", or "no"
+	ResumeTicketTTL     time.Duration
+	AuditEnabled        bool
+	AuditSampleRate     float64
+	AuditMaxEntries     int
+	AuditTTL            time.Duration
+	AuditRedactFields   []string
+	OfflineQueueEnabled    bool
+	OfflineQueueTTL        time.Duration
+	OfflineQueueMaxPerUser int
 }
 
 type EventHandler func(client *Client, msg *Message) error
@@ -61,6 +73,7 @@ func NewManager(cfg *Config, db *gorm.DB) *Manager {
 	}
 
 	m.sessionManager = NewSessionManager(ctx)
+	m.sessionManager.SetResumeTicketTTL(cfg.ResumeTicketTTL)
 
 	// Initialize connection monitor with database for role validation
 	m.connectionMonitor = NewConnectionMonitorWithDB(
@@ -79,6 +92,22 @@ func NewManager(cfg *Config, db *gorm.DB) *Manager {
 	m.hub.SetClientLifecycle(clientLifecycle)
 	m.hub.SetSessionManager(m.sessionManager)
 
+	m.auditor = NewMessageAuditor(AuditConfig{
+		Enabled:      cfg.AuditEnabled,
+		SampleRate:   cfg.AuditSampleRate,
+		MaxEntries:   cfg.AuditMaxEntries,
+		TTL:          cfg.AuditTTL,
+		RedactFields: cfg.AuditRedactFields,
+	})
+	m.hub.SetAuditor(m.auditor)
+
+	m.offlineQueue = NewOfflineQueue(OfflineQueueConfig{
+		Enabled:    cfg.OfflineQueueEnabled,
+		TTL:        cfg.OfflineQueueTTL,
+		MaxPerUser: cfg.OfflineQueueMaxPerUser,
+	})
+	m.hub.SetOfflineQueue(m.offlineQueue)
+
 	m.registerDefaultHandlers()
 
 	return m
@@ -108,6 +137,14 @@ func (m *Manager) Start() error {
 		m.collectMetrics()
 	}()
 
+	if m.auditor.Enabled() {
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			m.auditor.Run(m.ctx)
+		}()
+	}
+
 	if m.config.PersistenceEnabled && (m.config.PersistenceMode == "rdb" || m.config.PersistenceMode == "both") {
 		m.wg.Add(1)
 		go func() {
@@ -171,6 +208,7 @@ func (m *Manager) registerDefaultHandlers() {
 	// Reconnection handlers
 	m.RegisterHandler(TypeReconnect, m.handleReconnect)
 	m.RegisterHandler(TypeMessageSyncAck, m.handleSyncAck)
+	m.RegisterHandler(TypeAck, m.handleMessageAck)
 }
 
 func (m *Manager) handlePing(client *Client, msg *Message) error {
@@ -258,6 +296,42 @@ func (m *Manager) handleSyncAck(client *Client, msg *Message) error {
 	return m.reconnectionHandler.HandleSyncAck(client, msg.Data)
 }
 
+// handleMessageAck accepts either a single messageId or a messageIds list and
+// clears them from the client's retry set and the user's offline queue.
+func (m *Manager) handleMessageAck(client *Client, msg *Message) error {
+	var messageIDs []string
+	if id, ok := msg.Data["messageId"].(string); ok && id != "" {
+		messageIDs = append(messageIDs, id)
+	}
+	if ids, ok := msg.Data["messageIds"].([]interface{}); ok {
+		for _, raw := range ids {
+			if id, ok := raw.(string); ok && id != "" {
+				messageIDs = append(messageIDs, id)
+			}
+		}
+	}
+	if len(messageIDs) == 0 {
+		return client.SendError("messageId or messageIds required", msg.RequestID)
+	}
+
+	for _, id := range messageIDs {
+		client.handleAck(&Message{Data: map[string]interface{}{"messageId": id}})
+	}
+
+	ctx, cancel := context.WithTimeout(m.ctx, offlineQueueTimeout)
+	defer cancel()
+	if err := m.offlineQueue.Ack(ctx, client.UserID, messageIDs); err != nil {
+		logger.Warn("failed to ack offline messages", "error", err, "userID", client.UserID)
+	}
+
+	if msg.RequestID == "" {
+		return nil
+	}
+	return client.SendAck(msg.RequestID, map[string]interface{}{
+		"acknowledged": messageIDs,
+	})
+}
+
 // SessionManager returns the session manager for integration with other services
 func (m *Manager) SessionManager() *SessionManager {
 	return m.sessionManager
@@ -316,6 +390,23 @@ func (m *Manager) GetStats() Stats {
 	}
 }
 
+// HealthCheck reports the connections held by this instance. It fails once
+// the manager has been shut down.
+func (m *Manager) HealthCheck(ctx context.Context) (interface{}, error) {
+	if m.ctx.Err() != nil {
+		return nil, errors.New("websocket manager stopped")
+	}
+	stats := m.GetStats()
+	return map[string]interface{}{
+		"connectedUsers":   stats.ConnectedUsers,
+		"totalConnections": stats.TotalConnections,
+		"drivers":          m.hub.GetConnectedDrivers(),
+		"riders":           m.hub.GetConnectedRiders(),
+		"admins":           m.hub.GetConnectedAdmins(),
+		"draining":         m.hub.isDraining(),
+	}, nil
+}
+
 func (m *Manager) SendNotification(userID string, notification interface{}) error {
 	msg := NewTargetedMessage(TypeNotification, userID, map[string]interface{}{
 		"notification": notification,
@@ -35,6 +35,58 @@ const (
 	TypeSOSAlert     = "sos_alert"
 	TypeSOSResolved  = "sos_resolved"
 	TypeSOSEscalated = "sos_escalated"
+	TypeSOSStatus    = "sos_status"
+
+	TypeProviderOnboardingUpdate MessageType = "provider_onboarding_update"
+
+	TypeOrderRescheduleRequested MessageType = "order_reschedule_requested"
+	TypeOrderRescheduled         MessageType = "order_rescheduled"
+	TypeOrderRescheduleDeclined  MessageType = "order_reschedule_declined"
+	TypeOrderAdjustmentProposed  MessageType = "order_adjustment_proposed"
+	TypeOrderAdjustmentApproved  MessageType = "order_adjustment_approved"
+	TypeOrderAdjustmentRejected  MessageType = "order_adjustment_rejected"
+	// TypeOrderAdjustmentRespond is sent by the customer app to approve or
+	// reject a proposed adjustment.
+	TypeOrderAdjustmentRespond   MessageType = "order_adjustment_respond"
+	TypeCrewInvitation           MessageType = "crew_invitation"
+	TypeCrewUpdated              MessageType = "crew_updated"
+	TypeProviderLocation         MessageType = "provider_location"
+	TypeProviderLocationStopped  MessageType = "provider_location_stopped"
+	TypeProviderArrived          MessageType = "provider_arrived"
+	TypeProviderNoShowReported   MessageType = "provider_no_show_reported"
+	TypeFareSplitInvitation      MessageType = "fare_split_invitation"
+	TypeFareSplitUpdated         MessageType = "fare_split_updated"
+	TypeOrderAssigned            MessageType = "order_assigned"
+	TypeOrderExpired             MessageType = "order_expired"
+
+	TypeLaundryRequoteApprovalRequired MessageType = "laundry_requote_approval_required"
+	TypeLaundryRequoteApplied          MessageType = "laundry_requote_applied"
+	TypeLaundryRequoteRejected         MessageType = "laundry_requote_rejected"
+	TypeLaundryDeliveryRescheduled     MessageType = "laundry_delivery_rescheduled"
+
+	TypeVehicleInspectionDue     MessageType = "vehicle_inspection_due"
+	TypeVehicleInspectionBlocked MessageType = "vehicle_inspection_blocked"
+	TypeVehicleInspectionUpdate  MessageType = "vehicle_inspection_update"
+
+	TypeDisputeUpdate MessageType = "dispute_update"
+
+	TypePrivacyRequestUpdate MessageType = "privacy_request_update"
+
+	TypeReferralRewarded MessageType = "referral_rewarded"
+
+	TypeLoyaltyPointsEarned MessageType = "loyalty_points_earned"
+	TypeLoyaltyTierChanged  MessageType = "loyalty_tier_changed"
+
+	TypeAdminLiveMetrics        MessageType = "admin_live_metrics"
+	TypeAdminLiveMetricsRequest MessageType = "admin_live_metrics_request"
+
+	TypeOpsTaskUpdate MessageType = "ops_task_update"
+
+	TypeAnnouncement          MessageType = "announcement"
+	TypeAnnouncementWithdrawn MessageType = "announcement_withdrawn"
+
+	TypeIncentiveProgress MessageType = "incentive_progress"
+	TypeIncentiveRewarded MessageType = "incentive_rewarded"
 
 	TypeSystemMessage MessageType = "system"
 	TypeError         MessageType = "error"
@@ -47,7 +99,10 @@ const (
 	TypeMessageSync    MessageType = "message_sync"
 	TypeMessageSyncAck MessageType = "message_sync_ack"
 	TypeSessionExpired MessageType = "session_expired"
+	TypeResumeTicket   MessageType = "resume_ticket"
 	TypeSyncComplete   MessageType = "sync_complete"
+
+	TypeServerRestarting MessageType = "server_restarting"
 )
 
 type Message struct {
@@ -59,6 +114,8 @@ type Message struct {
 	RequireAck   bool                   `json:"requireAck,omitempty"`
 	RetryCount   int                    `json:"-"`
 	MessageID    string                 `json:"messageId,omitempty"`
+	Redelivered  bool                   `json:"redelivered,omitempty"`
+	Cursor       string                 `json:"cursor,omitempty"`
 }
 
 func NewMessage(msgType MessageType, data map[string]interface{}) *Message {
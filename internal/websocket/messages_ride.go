@@ -8,6 +8,8 @@ const (
 	TypeRideRejected MessageType = "ride_rejected"
 	TypeRideLocation MessageType = "ride_location"
 
+	TypeRideTripFlagged MessageType = "ride_trip_flagged"
+
 	TypeDriverAvailable   MessageType = "driver_available"
 	TypeDriverUnavailable MessageType = "driver_unavailable"
 	TypeDriverLocation    MessageType = "driver_location"
@@ -15,6 +17,17 @@ const (
 	TypePaymentInitiated MessageType = "payment_initiated"
 	TypePaymentCompleted MessageType = "payment_completed"
 	TypePaymentFailed    MessageType = "payment_failed"
+
+	TypeWalletHoldCreated  MessageType = "wallet_hold_created"
+	TypeWalletHoldCaptured MessageType = "wallet_hold_captured"
+	TypeWalletHoldReleased MessageType = "wallet_hold_released"
+
+	TypePayoutCreditStuck MessageType = "payout_credit_stuck"
+
+	TypeLostItemUpdate  MessageType = "lost_item_update"
+	TypeLostItemMessage MessageType = "lost_item_message"
+
+	TypeInsuranceClaimUpdate MessageType = "insurance_claim_update"
 )
 
 func NewRideMessage(msgType MessageType, rideID string, data map[string]interface{}) *Message {
@@ -5,12 +5,42 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/umar5678/go-backend/internal/services/cache"
 	"github.com/umar5678/go-backend/internal/utils/jwt"
 	"github.com/umar5678/go-backend/internal/utils/logger"
 )
 
 func AuthMiddleware(jwtSecret, issuer string) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		// A resume ticket stands in for the JWT on a fast reconnect; it is
+		// consumed here and pins the connection to its original session.
+		if resumeTicket := c.Query("resume_ticket"); resumeTicket != "" {
+			ticket, err := ConsumeResumeTicket(c.Request.Context(), resumeTicket)
+			if err != nil {
+				logger.Warn("websocket resume ticket rejected",
+					"error", err.Error(),
+					"remote_addr", c.Request.RemoteAddr,
+				)
+				c.JSON(http.StatusUnauthorized, gin.H{
+					"error": "invalid or expired resume ticket",
+				})
+				c.Abort()
+				return
+			}
+
+			if rejectBlockedUser(c, ticket.UserID) {
+				return
+			}
+
+			c.Set("userID", ticket.UserID)
+			c.Set("role", string(ticket.Role))
+			c.Set("resumeSessionID", ticket.SessionID)
+			c.Set("authSessionID", ticket.AuthSessionID)
+
+			c.Next()
+			return
+		}
+
 		token := c.Query("token")
 
 		if token == "" {
@@ -41,9 +71,50 @@ func AuthMiddleware(jwtSecret, issuer string) gin.HandlerFunc {
 			return
 		}
 
+		if cache.IsAuthSessionRevoked(c.Request.Context(), claims.SessionID) {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"error": "session has been revoked",
+			})
+			c.Abort()
+			return
+		}
+
+		if rejectBlockedUser(c, claims.UserID) {
+			return
+		}
+
+		// Impersonation is for looking at the API as the user; a socket
+		// would take over their live messages and presence.
+		if claims.IsImpersonation() {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error": "impersonation tokens cannot open a websocket",
+			})
+			c.Abort()
+			return
+		}
+
 		c.Set("userID", claims.UserID)
 		c.Set("role", claims.Role)
+		c.Set("authSessionID", claims.SessionID)
 
 		c.Next()
 	}
 }
+
+// rejectBlockedUser refuses the upgrade for suspended and banned accounts.
+func rejectBlockedUser(c *gin.Context, userID string) bool {
+	block, blocked := cache.GetUserBlock(c.Request.Context(), userID)
+	if !blocked {
+		return false
+	}
+
+	message := "account suspended"
+	if block.Kind == "ban" {
+		message = "account banned"
+	}
+	c.JSON(http.StatusForbidden, gin.H{
+		"error": message,
+	})
+	c.Abort()
+	return true
+}
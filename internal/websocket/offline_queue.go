@@ -0,0 +1,187 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+// The offline queue keeps a copy of critical events per user until the client
+// acknowledges them, so a driver whose socket drops for a few seconds still
+// sees the ride offer when it reconnects. Message IDs live in a sorted set
+// scored by enqueue time and payloads in a hash, both under the user's key.
+const (
+	offlineQueueKeyPrefix    = "ws:offline:"
+	offlinePayloadsKeyPrefix = "ws:offline:msg:"
+
+	offlineQueueTimeout = 2 * time.Second
+)
+
+var criticalMessageTypes = map[MessageType]bool{
+	TypeRideRequest:   true,
+	TypeOrderAssigned: true,
+	TypeRideCancelled: true,
+}
+
+type OfflineQueueConfig struct {
+	Enabled    bool
+	TTL        time.Duration
+	MaxPerUser int
+}
+
+type OfflineQueue struct {
+	cfg OfflineQueueConfig
+}
+
+func NewOfflineQueue(cfg OfflineQueueConfig) *OfflineQueue {
+	if cfg.TTL <= 0 {
+		cfg.TTL = 2 * time.Minute
+	}
+	if cfg.MaxPerUser <= 0 {
+		cfg.MaxPerUser = 50
+	}
+	return &OfflineQueue{cfg: cfg}
+}
+
+func (q *OfflineQueue) Enabled() bool {
+	return q != nil && q.cfg.Enabled
+}
+
+// IsCritical reports whether messages of this type are held for redelivery.
+func IsCritical(msgType MessageType) bool {
+	return criticalMessageTypes[msgType]
+}
+
+// Enqueue stores a critical message for the user and marks it as requiring an
+// ack. Non-critical messages are left untouched.
+func (q *OfflineQueue) Enqueue(ctx context.Context, userID string, msg *Message) error {
+	if !q.Enabled() || userID == "" || !IsCritical(msg.Type) {
+		return nil
+	}
+
+	if msg.MessageID == "" {
+		msg.MessageID = uuid.NewString()
+	}
+	msg.RequireAck = true
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal offline message: %w", err)
+	}
+
+	queueKey := offlineQueueKeyPrefix + userID
+	payloadKey := offlinePayloadsKeyPrefix + userID
+
+	pipe := cache.MainClient.TxPipeline()
+	pipe.ZAdd(ctx, queueKey, cache.Z{
+		Score:  float64(time.Now().UnixMilli()),
+		Member: msg.MessageID,
+	})
+	pipe.HSet(ctx, payloadKey, msg.MessageID, payload)
+	pipe.Expire(ctx, queueKey, q.cfg.TTL)
+	pipe.Expire(ctx, payloadKey, q.cfg.TTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to enqueue offline message: %w", err)
+	}
+
+	// Keep only the newest MaxPerUser entries.
+	overflow, err := cache.MainClient.ZRange(ctx, queueKey, 0, int64(-q.cfg.MaxPerUser-1)).Result()
+	if err == nil && len(overflow) > 0 {
+		q.remove(ctx, userID, overflow)
+	}
+
+	return nil
+}
+
+// Pending returns the user's unacknowledged messages that are still within the
+// TTL, oldest first. Expired entries are removed on the way.
+func (q *OfflineQueue) Pending(ctx context.Context, userID string) ([]*Message, error) {
+	if !q.Enabled() {
+		return nil, nil
+	}
+
+	queueKey := offlineQueueKeyPrefix + userID
+	entries, err := cache.MainClient.ZRangeWithScores(ctx, queueKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read offline queue: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+
+	cutoff := float64(time.Now().Add(-q.cfg.TTL).UnixMilli())
+	live := make([]string, 0, len(entries))
+	var expired []string
+	for _, entry := range entries {
+		id, ok := entry.Member.(string)
+		if !ok {
+			continue
+		}
+		if entry.Score < cutoff {
+			expired = append(expired, id)
+			continue
+		}
+		live = append(live, id)
+	}
+	if len(expired) > 0 {
+		q.remove(ctx, userID, expired)
+	}
+	if len(live) == 0 {
+		return nil, nil
+	}
+
+	payloads, err := cache.MainClient.HMGet(ctx, offlinePayloadsKeyPrefix+userID, live...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read offline payloads: %w", err)
+	}
+
+	messages := make([]*Message, 0, len(payloads))
+	var missing []string
+	for i, raw := range payloads {
+		data, ok := raw.(string)
+		if !ok {
+			missing = append(missing, live[i])
+			continue
+		}
+		var msg Message
+		if err := json.Unmarshal([]byte(data), &msg); err != nil {
+			logger.Warn("dropping unreadable offline message", "error", err, "userID", userID, "messageID", live[i])
+			missing = append(missing, live[i])
+			continue
+		}
+		messages = append(messages, &msg)
+	}
+	if len(missing) > 0 {
+		q.remove(ctx, userID, missing)
+	}
+
+	return messages, nil
+}
+
+// Ack drops acknowledged messages from the user's queue.
+func (q *OfflineQueue) Ack(ctx context.Context, userID string, messageIDs []string) error {
+	if !q.Enabled() || len(messageIDs) == 0 {
+		return nil
+	}
+	return q.remove(ctx, userID, messageIDs)
+}
+
+func (q *OfflineQueue) remove(ctx context.Context, userID string, messageIDs []string) error {
+	members := make([]interface{}, len(messageIDs))
+	for i, id := range messageIDs {
+		members[i] = id
+	}
+
+	pipe := cache.MainClient.TxPipeline()
+	pipe.ZRem(ctx, offlineQueueKeyPrefix+userID, members...)
+	pipe.HDel(ctx, offlinePayloadsKeyPrefix+userID, messageIDs...)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to remove offline messages: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package websocket
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/umar5678/go-backend/internal/models"
+	"github.com/umar5678/go-backend/internal/services/cache"
+	"github.com/umar5678/go-backend/internal/utils/logger"
+)
+
+// Resume tickets let driver and provider apps reopen a dropped socket without
+// re-running the JWT/refresh flow. A ticket is opaque, single use, short lived
+// and bound to the session it was issued for; every successful resume rotates it.
+const (
+	resumeTicketKeyPrefix     = "ws:resume:ticket:"
+	resumeTicketUserKeyPrefix = "ws:resume:user:"
+	DefaultResumeTicketTTL    = 2 * time.Minute
+)
+
+var ErrResumeTicketInvalid = errors.New("resume ticket invalid or expired")
+
+type ResumeTicket struct {
+	Ticket        string          `json:"-"`
+	UserID        string          `json:"userId"`
+	Role          models.UserRole `json:"role"`
+	SessionID     string          `json:"sessionId"`
+	AuthSessionID string          `json:"authSessionId,omitempty"`
+	IssuedAt      time.Time       `json:"issuedAt"`
+	ExpiresAt     time.Time       `json:"expiresAt"`
+}
+
+// ResumeTicketEligible reports whether clients of the given role receive resume tickets.
+func ResumeTicketEligible(role models.UserRole) bool {
+	return role == models.RoleDriver || role == models.RoleServiceProvider
+}
+
+func IssueResumeTicket(ctx context.Context, userID string, role models.UserRole, sessionID, authSessionID string, ttl time.Duration) (*ResumeTicket, error) {
+	if ttl <= 0 {
+		ttl = DefaultResumeTicketTTL
+	}
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	ticket := &ResumeTicket{
+		Ticket:        base64.RawURLEncoding.EncodeToString(b),
+		UserID:        userID,
+		Role:          role,
+		SessionID:     sessionID,
+		AuthSessionID: authSessionID,
+		IssuedAt:      now,
+		ExpiresAt:     now.Add(ttl),
+	}
+
+	payload, err := json.Marshal(ticket)
+	if err != nil {
+		return nil, err
+	}
+
+	userKey := resumeTicketUserKeyPrefix + userID
+	pipe := cache.CacheClient.TxPipeline()
+	pipe.Set(ctx, resumeTicketKeyPrefix+ticket.Ticket, payload, ttl)
+	pipe.HSet(ctx, userKey, ticket.Ticket, sessionID)
+	pipe.Expire(ctx, userKey, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+
+	return ticket, nil
+}
+
+// ConsumeResumeTicket redeems a ticket exactly once. The ticket is rejected if the
+// session it was bound to has expired or now belongs to someone else, or if the
+// login it was issued under has been revoked.
+func ConsumeResumeTicket(ctx context.Context, token string) (*ResumeTicket, error) {
+	if token == "" {
+		return nil, ErrResumeTicketInvalid
+	}
+
+	val, err := cache.CacheClient.GetDel(ctx, resumeTicketKeyPrefix+token).Result()
+	if err == redis.Nil {
+		return nil, ErrResumeTicketInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ticket := &ResumeTicket{}
+	if err := json.Unmarshal([]byte(val), ticket); err != nil {
+		return nil, ErrResumeTicketInvalid
+	}
+	ticket.Ticket = token
+
+	cache.CacheClient.HDel(ctx, resumeTicketUserKeyPrefix+ticket.UserID, token)
+
+	if cache.IsAuthSessionRevoked(ctx, ticket.AuthSessionID) {
+		return nil, ErrResumeTicketInvalid
+	}
+
+	sessionVal, err := cache.Get(ctx, sessionKeyPrefix+ticket.SessionID)
+	if err != nil || sessionVal == "" {
+		return nil, ErrResumeTicketInvalid
+	}
+
+	session := &SessionState{}
+	if err := json.Unmarshal([]byte(sessionVal), session); err != nil || session.UserID != ticket.UserID {
+		return nil, ErrResumeTicketInvalid
+	}
+
+	return ticket, nil
+}
+
+// RevokeResumeTickets drops the user's outstanding tickets. An empty sessionID
+// revokes every ticket the user holds.
+func RevokeResumeTickets(ctx context.Context, userID, sessionID string) (int, error) {
+	userKey := resumeTicketUserKeyPrefix + userID
+
+	tickets, err := cache.CacheClient.HGetAll(ctx, userKey).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	revoked := 0
+	for token, boundSession := range tickets {
+		if sessionID != "" && boundSession != sessionID {
+			continue
+		}
+		if err := cache.CacheClient.Del(ctx, resumeTicketKeyPrefix+token).Err(); err != nil {
+			logger.Warn("failed to revoke resume ticket", "error", err, "userId", userID)
+			continue
+		}
+		cache.CacheClient.HDel(ctx, userKey, token)
+		revoked++
+	}
+
+	if revoked > 0 {
+		logger.Info("resume tickets revoked",
+			"userId", userID,
+			"sessionId", sessionID,
+			"count", revoked,
+		)
+	}
+
+	return revoked, nil
+}
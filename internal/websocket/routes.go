@@ -29,11 +29,23 @@ func RegisterRoutes(router *gin.Engine, cfg *config.Config, server *Server) {
 			server.HandleSendToUser(),
 		)
 
+		ws.POST("/resume-tickets/revoke",
+			middleware.Auth(cfg),
+			server.HandleRevokeResumeTickets(),
+		)
+
 		ws.POST("/broadcast",
 			middleware.Auth(cfg),
 			middleware.RequireRole("admin"),
 			server.HandleBroadcast(),
 		)
+
+		audit := ws.Group("/audit/users/:userId", middleware.Auth(cfg), middleware.RequireRole("admin"))
+		{
+			audit.GET("", server.HandleGetUserAudit())
+			audit.POST("/capture", server.HandleStartAuditCapture())
+			audit.DELETE("/capture", server.HandleStopAuditCapture())
+		}
 	}
 
 	if cfg.App.Environment == "development" {
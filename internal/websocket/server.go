@@ -1,7 +1,9 @@
 package websocket
 
 import (
+	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -59,6 +61,9 @@ func (s *Server) HandleConnection() gin.HandlerFunc {
 		)
 
 		reconnectToken := c.Query("reconnect_token")
+		if resumeSessionID := c.GetString("resumeSessionID"); resumeSessionID != "" {
+			reconnectToken = resumeSessionID
+		}
 		var isReconnect bool
 		var previousSession *SessionState
 
@@ -81,6 +86,12 @@ func (s *Server) HandleConnection() gin.HandlerFunc {
 			}
 		}
 
+		if s.manager.hub.isDraining() {
+			c.Header("Retry-After", "1")
+			c.JSON(http.StatusServiceUnavailable, response.ServiceUnavailable("Server is restarting, reconnect shortly"))
+			return
+		}
+
 		conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 		if err != nil {
 			logger.Error("websocket upgrade failed", "error", err, "userID", userIDStr)
@@ -89,6 +100,7 @@ func (s *Server) HandleConnection() gin.HandlerFunc {
 
 		client := NewClient(s.manager.hub, conn, userIDStr, c.Request.UserAgent(), userRole)
 		client.manager = s.manager
+		client.AuthSessionID = c.GetString("authSessionID")
 
 		s.manager.hub.register <- client
 
@@ -98,6 +110,7 @@ func (s *Server) HandleConnection() gin.HandlerFunc {
 				"userId", userIDStr,
 				"clientId", client.ID,
 			)
+			s.manager.auditor.RecordConnection(client, ConnEventReconnected, fmt.Sprintf("reconnections=%d", previousSession.ReconnectionCount))
 
 			clientLifecycle := NewClientLifecycle(s.manager.sessionManager, s.manager.messageStore)
 			if err := clientLifecycle.OnClientReconnect(client, reconnectToken); err != nil {
@@ -220,6 +233,40 @@ func (s *Server) HandleUserPresence() gin.HandlerFunc {
 	}
 }
 
+// HandleRevokeResumeTickets lets a client invalidate its outstanding resume
+// tickets, either for one session or for all of them.
+func (s *Server) HandleRevokeResumeTickets() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("userID")
+		if userID == "" {
+			c.JSON(http.StatusUnauthorized, response.UnauthorizedError("Unauthorized"))
+			return
+		}
+
+		var req struct {
+			SessionID string `json:"sessionId"`
+		}
+		if c.Request.ContentLength > 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, response.BadRequest("invalid request"))
+				return
+			}
+		}
+
+		revoked, err := RevokeResumeTickets(c.Request.Context(), userID, req.SessionID)
+		if err != nil {
+			logger.Error("failed to revoke resume tickets", "error", err, "userID", userID)
+			c.JSON(http.StatusInternalServerError, response.InternalServerError("Failed to revoke resume tickets", err))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"revoked": revoked,
+		})
+	}
+}
+
 func (s *Server) HandleBroadcast() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		role, _ := c.Get("role")
@@ -280,3 +327,93 @@ func (s *Server) HandleSendToUser() gin.HandlerFunc {
 		})
 	}
 }
+
+// HandleGetUserAudit returns the sampled delivery log and recent connection
+// events for a user so support can trace whether an event reached the app.
+func (s *Server) HandleGetUserAudit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !s.manager.auditor.Enabled() {
+			c.JSON(http.StatusServiceUnavailable, response.ServiceUnavailable("websocket audit is disabled"))
+			return
+		}
+
+		userID := c.Param("userId")
+		limit, _ := strconv.Atoi(c.Query("limit"))
+
+		messages, events, err := s.manager.auditor.GetUserLog(c.Request.Context(), userID, limit)
+		if err != nil {
+			logger.Error("failed to load websocket audit log", "error", err, "userID", userID)
+			c.JSON(http.StatusInternalServerError, response.InternalServerError("Failed to load audit log", err))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"userId":      userID,
+			"online":      s.manager.hub.IsUserConnected(userID),
+			"sampleRate":  s.manager.auditor.SampleRate(),
+			"forcedUntil": s.manager.auditor.ForcedUntil(userID),
+			"messages":    messages,
+			"connections": events,
+		})
+	}
+}
+
+// HandleStartAuditCapture records every message for a user for a limited
+// window, bypassing the sample rate while an issue is being reproduced.
+func (s *Server) HandleStartAuditCapture() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !s.manager.auditor.Enabled() {
+			c.JSON(http.StatusServiceUnavailable, response.ServiceUnavailable("websocket audit is disabled"))
+			return
+		}
+
+		var req struct {
+			Minutes int `json:"minutes" binding:"required,min=1,max=1440"`
+		}
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, response.BadRequest("minutes must be between 1 and 1440"))
+			return
+		}
+
+		userID := c.Param("userId")
+		until, err := s.manager.auditor.ForceCapture(c.Request.Context(), userID, time.Duration(req.Minutes)*time.Minute)
+		if err != nil {
+			logger.Error("failed to start websocket audit capture", "error", err, "userID", userID)
+			c.JSON(http.StatusInternalServerError, response.InternalServerError("Failed to start capture", err))
+			return
+		}
+
+		logger.Info("websocket audit capture started",
+			"userID", userID,
+			"until", until,
+			"adminID", c.GetString("userID"),
+		)
+
+		c.JSON(http.StatusOK, gin.H{
+			"success":     true,
+			"userId":      userID,
+			"forcedUntil": until,
+		})
+	}
+}
+
+func (s *Server) HandleStopAuditCapture() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !s.manager.auditor.Enabled() {
+			c.JSON(http.StatusServiceUnavailable, response.ServiceUnavailable("websocket audit is disabled"))
+			return
+		}
+
+		userID := c.Param("userId")
+		if err := s.manager.auditor.StopCapture(c.Request.Context(), userID); err != nil {
+			logger.Error("failed to stop websocket audit capture", "error", err, "userID", userID)
+			c.JSON(http.StatusInternalServerError, response.InternalServerError("Failed to stop capture", err))
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"userId":  userID,
+		})
+	}
+}
@@ -37,6 +37,8 @@ type SessionManager struct {
 	activeSessions map[string]*SessionState
 	sessionMutex   map[string]*sessionLock
 	mu             *sync.RWMutex
+
+	resumeTicketTTL time.Duration
 }
 
 type sessionLock struct {
@@ -49,6 +51,14 @@ func NewSessionManager(ctx context.Context) *SessionManager {
 		activeSessions: make(map[string]*SessionState),
 		sessionMutex:   make(map[string]*sessionLock),
 		mu:             &sync.RWMutex{},
+
+		resumeTicketTTL: DefaultResumeTicketTTL,
+	}
+}
+
+func (sm *SessionManager) SetResumeTicketTTL(ttl time.Duration) {
+	if ttl > 0 {
+		sm.resumeTicketTTL = ttl
 	}
 }
 
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/umar5678/go-backend/internal/modules/admin_support_chat"
+	"github.com/umar5678/go-backend/internal/services/i18n"
 	"github.com/umar5678/go-backend/internal/utils/logger"
 	"github.com/umar5678/go-backend/internal/websocket"
 )
@@ -54,6 +55,13 @@ func IsUserOnline(userID string) bool {
 	return wsManager.Hub().IsUserConnected(userID)
 }
 
+func GetConnectedAdmins() int {
+	if wsManager == nil {
+		return 0
+	}
+	return wsManager.Hub().GetConnectedAdmins()
+}
+
 func GetOnlineUsers() (int, int) {
 	if wsManager == nil {
 		return 0, 0
@@ -167,6 +175,7 @@ func SendToUser(userID string, messageType websocket.MessageType, data map[strin
 	if data == nil {
 		data = make(map[string]interface{})
 	}
+	data = localize(userID, data)
 
 	msg := websocket.NewTargetedMessage(messageType, userID, data)
 
@@ -185,6 +194,28 @@ func SendToUser(userID string, messageType websocket.MessageType, data map[strin
 	return nil
 }
 
+// localize replaces "message" with the translation of "messageKey", if the
+// payload has one, in the recipient's language, filling placeholders from
+// "messageParams". The payload is copied since callers send one map to
+// several users.
+func localize(userID string, data map[string]interface{}) map[string]interface{} {
+	key, ok := data["messageKey"].(string)
+	if !ok || key == "" {
+		return data
+	}
+	params, _ := data["messageParams"].(map[string]interface{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	localized := make(map[string]interface{}, len(data))
+	for k, v := range data {
+		localized[k] = v
+	}
+	localized["message"] = i18n.T(i18n.UserLanguage(ctx, userID), key, params)
+	return localized
+}
+
 func SendRideRequest(driverID string, rideDetails map[string]interface{}) error {
 	return SendToUser(driverID, websocket.TypeRideRequest, rideDetails)
 }
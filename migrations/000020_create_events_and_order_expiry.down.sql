@@ -4,3 +4,9 @@ DROP INDEX IF EXISTS idx_laundry_orders_status_expires;
 
 ALTER TABLE laundry_orders
 DROP COLUMN IF EXISTS expires_at;
+
+-- Drop processed_events table
+DROP TABLE IF EXISTS processed_events CASCADE;
+
+-- Drop events table
+DROP TABLE IF EXISTS events CASCADE;
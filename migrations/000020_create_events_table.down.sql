@@ -1,5 +0,0 @@
--- Drop processed_events table
-DROP TABLE IF EXISTS processed_events CASCADE;
-
--- Drop events table
-DROP TABLE IF EXISTS events CASCADE;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS lost_item_messages CASCADE;
+DROP TABLE IF EXISTS lost_item_cases CASCADE;
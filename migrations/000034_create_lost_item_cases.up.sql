@@ -0,0 +1,54 @@
+-- Lost and found cases raised by riders after a completed trip
+CREATE TABLE IF NOT EXISTS lost_item_cases (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    ride_id UUID NOT NULL,
+    rider_id UUID NOT NULL,
+    driver_id UUID NOT NULL,
+    item_description TEXT NOT NULL,
+    item_category VARCHAR(50),
+    contact_note TEXT,
+    status VARCHAR(30) NOT NULL DEFAULT 'reported'
+        CHECK (status IN ('reported', 'found', 'not_found', 'return_scheduled', 'returned', 'escalated', 'resolved', 'closed')),
+    driver_response TEXT,
+    return_fee DECIMAL(10,2) DEFAULT 0,
+    return_fee_txn_id UUID,
+    return_address TEXT,
+    return_at TIMESTAMP,
+    escalated_by UUID,
+    escalation_note TEXT,
+    escalated_at TIMESTAMP,
+    resolved_by UUID,
+    resolution_note TEXT,
+    driver_notified_at TIMESTAMP,
+    responded_at TIMESTAMP,
+    returned_at TIMESTAMP,
+    closed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    deleted_at TIMESTAMP,
+
+    CONSTRAINT fk_lost_item_cases_ride FOREIGN KEY (ride_id) REFERENCES rides(id) ON DELETE CASCADE,
+    CONSTRAINT fk_lost_item_cases_rider FOREIGN KEY (rider_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_lost_item_cases_driver FOREIGN KEY (driver_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_lost_item_cases_ride_id ON lost_item_cases(ride_id);
+CREATE INDEX idx_lost_item_cases_rider_id ON lost_item_cases(rider_id);
+CREATE INDEX idx_lost_item_cases_driver_id ON lost_item_cases(driver_id);
+CREATE INDEX idx_lost_item_cases_status ON lost_item_cases(status);
+CREATE INDEX idx_lost_item_cases_deleted_at ON lost_item_cases(deleted_at);
+
+-- Masked rider <-> driver conversation attached to a case
+CREATE TABLE IF NOT EXISTS lost_item_messages (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    case_id UUID NOT NULL,
+    sender_id UUID NOT NULL,
+    sender_role VARCHAR(20) NOT NULL,
+    content TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    CONSTRAINT fk_lost_item_messages_case FOREIGN KEY (case_id) REFERENCES lost_item_cases(id) ON DELETE CASCADE,
+    CONSTRAINT fk_lost_item_messages_sender FOREIGN KEY (sender_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_lost_item_messages_case_id ON lost_item_messages(case_id, created_at);
@@ -0,0 +1,28 @@
+-- Pricing components captured when a ride is completed, used for fee transparency
+CREATE TABLE IF NOT EXISTS ride_fare_snapshots (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    ride_id UUID NOT NULL,
+    vehicle_type_id UUID NOT NULL,
+    currency VARCHAR(10) NOT NULL DEFAULT 'INR',
+    base_fare DECIMAL(10,2) DEFAULT 0,
+    distance_fare DECIMAL(10,2) DEFAULT 0,
+    duration_fare DECIMAL(10,2) DEFAULT 0,
+    booking_fee DECIMAL(10,2) DEFAULT 0,
+    surge_multiplier DECIMAL(4,2) DEFAULT 1.0,
+    surge_amount DECIMAL(10,2) DEFAULT 0,
+    wait_time_charge DECIMAL(10,2) DEFAULT 0,
+    destination_change_charge DECIMAL(10,2) DEFAULT 0,
+    price_cap_adjustment DECIMAL(10,2) DEFAULT 0,
+    promo_discount DECIMAL(10,2) DEFAULT 0,
+    tax_amount DECIMAL(10,2) DEFAULT 0,
+    total_charged DECIMAL(10,2) NOT NULL,
+    driver_share DECIMAL(10,2) NOT NULL,
+    platform_fee DECIMAL(10,2) DEFAULT 0,
+    platform_absorbed DECIMAL(10,2) DEFAULT 0,
+    price_capped BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    CONSTRAINT fk_ride_fare_snapshots_ride FOREIGN KEY (ride_id) REFERENCES rides(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ride_fare_snapshots_ride_id ON ride_fare_snapshots(ride_id);
@@ -0,0 +1,23 @@
+-- Signed-in devices with rotating refresh tokens
+CREATE TABLE IF NOT EXISTS auth_sessions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    device_id VARCHAR(255) NOT NULL,
+    device_name VARCHAR(255),
+    user_agent TEXT,
+    ip_address VARCHAR(64),
+    refresh_token_hash VARCHAR(64) NOT NULL,
+    rotation_count INTEGER DEFAULT 0,
+    expires_at TIMESTAMP NOT NULL,
+    last_used_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    revoked_reason VARCHAR(50),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    CONSTRAINT fk_auth_sessions_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_auth_sessions_refresh_token_hash ON auth_sessions(refresh_token_hash);
+CREATE INDEX IF NOT EXISTS idx_auth_sessions_user_id ON auth_sessions(user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_auth_sessions_active_device ON auth_sessions(user_id, device_id) WHERE revoked_at IS NULL;
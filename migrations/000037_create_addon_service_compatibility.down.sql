@@ -0,0 +1 @@
+DROP TABLE IF EXISTS addon_service_compatibility CASCADE;
@@ -0,0 +1,12 @@
+-- Addon to service compatibility; addons with no rows stay compatible with the whole category
+CREATE TABLE IF NOT EXISTS addon_service_compatibility (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    addon_slug VARCHAR(255) NOT NULL,
+    service_slug VARCHAR(255) NOT NULL,
+    category_slug VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_addon_service_compat ON addon_service_compatibility(addon_slug, service_slug);
+CREATE INDEX IF NOT EXISTS idx_addon_service_compat_service ON addon_service_compatibility(service_slug);
+CREATE INDEX IF NOT EXISTS idx_addon_service_compat_category ON addon_service_compatibility(category_slug);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS insurance_claims CASCADE;
+DROP TABLE IF EXISTS ride_insurance_policies CASCADE;
@@ -0,0 +1,54 @@
+-- Per-trip insurance policies and the claims raised against them
+CREATE TABLE IF NOT EXISTS ride_insurance_policies (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    ride_id UUID NOT NULL,
+    rider_id UUID NOT NULL,
+    driver_id UUID NOT NULL,
+    provider VARCHAR(100) NOT NULL,
+    policy_number VARCHAR(100) NOT NULL,
+    provider_reference VARCHAR(255),
+    coverage_amount DECIMAL(12,2) NOT NULL,
+    premium DECIMAL(10,2) DEFAULT 0,
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    coverage_start_at TIMESTAMP NOT NULL,
+    coverage_end_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    CONSTRAINT fk_ride_insurance_policies_ride FOREIGN KEY (ride_id) REFERENCES rides(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ride_insurance_policies_ride_id ON ride_insurance_policies(ride_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ride_insurance_policies_policy_number ON ride_insurance_policies(policy_number);
+CREATE INDEX IF NOT EXISTS idx_ride_insurance_policies_rider_id ON ride_insurance_policies(rider_id);
+CREATE INDEX IF NOT EXISTS idx_ride_insurance_policies_driver_id ON ride_insurance_policies(driver_id);
+CREATE INDEX IF NOT EXISTS idx_ride_insurance_policies_status ON ride_insurance_policies(status);
+
+CREATE TABLE IF NOT EXISTS insurance_claims (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    claim_number VARCHAR(50) NOT NULL,
+    policy_id UUID NOT NULL,
+    ride_id UUID NOT NULL,
+    claimant_id UUID NOT NULL,
+    claimant_role VARCHAR(20) NOT NULL,
+    claim_type VARCHAR(30) NOT NULL,
+    description TEXT NOT NULL,
+    incident_at TIMESTAMP NOT NULL,
+    amount_claimed DECIMAL(12,2) DEFAULT 0,
+    amount_approved DECIMAL(12,2),
+    status VARCHAR(20) NOT NULL DEFAULT 'submitted',
+    reviewed_by UUID,
+    review_notes TEXT,
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    CONSTRAINT fk_insurance_claims_policy FOREIGN KEY (policy_id) REFERENCES ride_insurance_policies(id) ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_insurance_claims_claim_number ON insurance_claims(claim_number);
+CREATE INDEX IF NOT EXISTS idx_insurance_claims_policy_id ON insurance_claims(policy_id);
+CREATE INDEX IF NOT EXISTS idx_insurance_claims_ride_id ON insurance_claims(ride_id);
+CREATE INDEX IF NOT EXISTS idx_insurance_claims_claimant_id ON insurance_claims(claimant_id);
+CREATE INDEX IF NOT EXISTS idx_insurance_claims_status ON insurance_claims(status);
@@ -0,0 +1,5 @@
+ALTER TABLE service_provider_profiles DROP COLUMN IF EXISTS preferred_address_script;
+
+ALTER TABLE laundry_orders
+    DROP COLUMN IF EXISTS address_script,
+    DROP COLUMN IF EXISTS normalized_address;
@@ -0,0 +1,6 @@
+ALTER TABLE laundry_orders
+    ADD COLUMN IF NOT EXISTS normalized_address TEXT,
+    ADD COLUMN IF NOT EXISTS address_script VARCHAR(20);
+
+ALTER TABLE service_provider_profiles
+    ADD COLUMN IF NOT EXISTS preferred_address_script VARCHAR(20) NOT NULL DEFAULT 'latin';
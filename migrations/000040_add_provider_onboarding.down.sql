@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_service_provider_profiles_status_submitted;
+
+ALTER TABLE service_provider_profiles
+    DROP COLUMN IF EXISTS rejection_reason,
+    DROP COLUMN IF EXISTS reviewed_at,
+    DROP COLUMN IF EXISTS reviewed_by,
+    DROP COLUMN IF EXISTS submitted_for_review_at,
+    DROP COLUMN IF EXISTS background_check_updated_at,
+    DROP COLUMN IF EXISTS background_check_reference,
+    DROP COLUMN IF EXISTS background_check_status;
@@ -0,0 +1,17 @@
+ALTER TABLE service_provider_profiles
+    ADD COLUMN IF NOT EXISTS background_check_status VARCHAR(30) NOT NULL DEFAULT 'not_started',
+    ADD COLUMN IF NOT EXISTS background_check_reference VARCHAR(255),
+    ADD COLUMN IF NOT EXISTS background_check_updated_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS submitted_for_review_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS reviewed_by UUID,
+    ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS rejection_reason TEXT;
+
+-- Providers that were auto-approved before the onboarding pipeline existed keep
+-- their access; record them as already cleared.
+UPDATE service_provider_profiles
+SET background_check_status = 'clear'
+WHERE status = 'active' AND is_verified = true;
+
+CREATE INDEX IF NOT EXISTS idx_service_provider_profiles_status_submitted
+    ON service_provider_profiles (status, submitted_for_review_at);
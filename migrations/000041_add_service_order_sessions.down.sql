@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS service_order_sessions;
+
+ALTER TABLE service_orders
+    DROP COLUMN IF EXISTS is_multi_session,
+    DROP COLUMN IF EXISTS session_count,
+    DROP COLUMN IF EXISTS progress_percent;
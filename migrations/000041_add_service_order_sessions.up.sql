@@ -0,0 +1,29 @@
+ALTER TABLE service_orders
+    ADD COLUMN IF NOT EXISTS is_multi_session BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS session_count INT NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS progress_percent INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS service_order_sessions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES service_orders(id) ON DELETE CASCADE,
+    session_number INT NOT NULL,
+    scheduled_date VARCHAR(10) NOT NULL,
+    scheduled_time VARCHAR(5) NOT NULL,
+    duration_hours DECIMAL(4,1) NOT NULL,
+    status VARCHAR(30) NOT NULL DEFAULT 'scheduled',
+    checked_in_at TIMESTAMP,
+    checked_out_at TIMESTAMP,
+    work_summary TEXT,
+    progress_percent INT DEFAULT 0,
+    milestone_amount DECIMAL(10,2) NOT NULL,
+    provider_payout DECIMAL(10,2) DEFAULT 0,
+    milestone_paid_at TIMESTAMP,
+    customer_approved_at TIMESTAMP,
+    customer_feedback TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_service_order_sessions_order_id ON service_order_sessions (order_id);
+CREATE INDEX IF NOT EXISTS idx_service_order_sessions_status ON service_order_sessions (status);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_order_session_number ON service_order_sessions (order_id, session_number);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS payment_webhook_events;
+DROP TABLE IF EXISTS saved_payment_methods;
+DROP TABLE IF EXISTS payment_customers;
+DROP TABLE IF EXISTS wallet_topups;
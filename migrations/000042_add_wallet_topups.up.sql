@@ -0,0 +1,66 @@
+CREATE TABLE IF NOT EXISTS wallet_topups (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    wallet_id UUID NOT NULL REFERENCES wallets(id) ON DELETE CASCADE,
+    gateway VARCHAR(30) NOT NULL,
+    payment_intent_id VARCHAR(255),
+    idempotency_key VARCHAR(255) NOT NULL,
+    amount DECIMAL(12,2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    payment_method_id VARCHAR(255),
+    save_method BOOLEAN DEFAULT false,
+    transaction_id UUID,
+    failure_reason TEXT,
+    credited_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_wallet_topups_payment_intent_id ON wallet_topups (payment_intent_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_wallet_topups_user_idempotency ON wallet_topups (user_id, idempotency_key);
+CREATE INDEX IF NOT EXISTS idx_wallet_topups_user_id ON wallet_topups (user_id);
+CREATE INDEX IF NOT EXISTS idx_wallet_topups_wallet_id ON wallet_topups (wallet_id);
+CREATE INDEX IF NOT EXISTS idx_wallet_topups_status ON wallet_topups (status);
+
+CREATE TABLE IF NOT EXISTS payment_customers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    gateway VARCHAR(30) NOT NULL,
+    customer_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payment_customers_user_gateway ON payment_customers (user_id, gateway);
+
+CREATE TABLE IF NOT EXISTS saved_payment_methods (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    gateway VARCHAR(30) NOT NULL,
+    payment_method_id VARCHAR(255) NOT NULL,
+    brand VARCHAR(30),
+    last4 VARCHAR(4),
+    exp_month INT,
+    exp_year INT,
+    is_default BOOLEAN DEFAULT false,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_saved_payment_methods_payment_method_id ON saved_payment_methods (payment_method_id);
+CREATE INDEX IF NOT EXISTS idx_saved_payment_methods_user_id ON saved_payment_methods (user_id);
+
+CREATE TABLE IF NOT EXISTS payment_webhook_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    gateway VARCHAR(30) NOT NULL,
+    event_id VARCHAR(255) NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    object_id VARCHAR(255),
+    status VARCHAR(20) NOT NULL,
+    error TEXT,
+    processed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payment_webhook_events_event_id ON payment_webhook_events (event_id);
+CREATE INDEX IF NOT EXISTS idx_payment_webhook_events_event_type ON payment_webhook_events (event_type);
+CREATE INDEX IF NOT EXISTS idx_payment_webhook_events_object_id ON payment_webhook_events (object_id);
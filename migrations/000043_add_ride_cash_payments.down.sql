@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_rides_cash_driver_completed;
+DROP INDEX IF EXISTS idx_rides_payment_method;
+
+ALTER TABLE rides
+    DROP COLUMN IF EXISTS payment_method,
+    DROP COLUMN IF EXISTS cash_collected,
+    DROP COLUMN IF EXISTS cash_commission,
+    DROP COLUMN IF EXISTS cash_commission_txn_id;
@@ -0,0 +1,8 @@
+ALTER TABLE rides
+    ADD COLUMN IF NOT EXISTS payment_method VARCHAR(20) NOT NULL DEFAULT 'wallet',
+    ADD COLUMN IF NOT EXISTS cash_collected DECIMAL(10,2),
+    ADD COLUMN IF NOT EXISTS cash_commission DECIMAL(10,2),
+    ADD COLUMN IF NOT EXISTS cash_commission_txn_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_rides_payment_method ON rides (payment_method);
+CREATE INDEX IF NOT EXISTS idx_rides_cash_driver_completed ON rides (driver_id, completed_at) WHERE payment_method = 'cash' AND status = 'completed';
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS service_order_surcharges;
+
+ALTER TABLE service_orders
+    DROP COLUMN IF EXISTS surcharges_total,
+    DROP COLUMN IF EXISTS surcharges;
+
+DROP TABLE IF EXISTS category_surcharges;
@@ -0,0 +1,44 @@
+CREATE TABLE IF NOT EXISTS category_surcharges (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    category_slug VARCHAR(255) NOT NULL,
+    code VARCHAR(50) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    kind VARCHAR(30) NOT NULL DEFAULT 'other',
+    type VARCHAR(20) NOT NULL DEFAULT 'fixed',
+    amount DECIMAL(10,2) NOT NULL,
+    min_amount DECIMAL(10,2),
+    max_amount DECIMAL(10,2),
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_category_surcharge_code ON category_surcharges (category_slug, code) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_category_surcharges_is_active ON category_surcharges (is_active);
+CREATE INDEX IF NOT EXISTS idx_category_surcharges_deleted_at ON category_surcharges (deleted_at);
+
+ALTER TABLE service_orders
+    ADD COLUMN IF NOT EXISTS surcharges_total DECIMAL(10,2) DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS surcharges JSONB;
+
+CREATE TABLE IF NOT EXISTS service_order_surcharges (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES service_orders(id) ON DELETE CASCADE,
+    surcharge_id UUID NOT NULL,
+    category_slug VARCHAR(255) NOT NULL,
+    code VARCHAR(50) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    kind VARCHAR(30) NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    remitted_at TIMESTAMP,
+    remitted_by UUID,
+    remittance_ref VARCHAR(100),
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_service_order_surcharges_order_id ON service_order_surcharges (order_id);
+CREATE INDEX IF NOT EXISTS idx_service_order_surcharges_surcharge_id ON service_order_surcharges (surcharge_id);
+CREATE INDEX IF NOT EXISTS idx_service_order_surcharges_category_slug ON service_order_surcharges (category_slug);
+CREATE INDEX IF NOT EXISTS idx_service_order_surcharges_remitted_at ON service_order_surcharges (remitted_at);
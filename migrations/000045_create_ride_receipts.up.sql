@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS ride_receipts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    receipt_number VARCHAR(50) NOT NULL UNIQUE,
+    ride_id UUID NOT NULL UNIQUE REFERENCES rides(id) ON DELETE CASCADE,
+    rider_id UUID NOT NULL,
+    driver_id UUID,
+    driver_name VARCHAR(255),
+    vehicle_type VARCHAR(100),
+    pickup_address TEXT,
+    dropoff_address TEXT,
+    distance_km DECIMAL(10,2),
+    duration_mins INTEGER,
+    currency VARCHAR(10) NOT NULL,
+    charges JSONB,
+    surcharges JSONB,
+    discounts JSONB,
+    taxes JSONB,
+    subtotal DECIMAL(10,2),
+    surcharges_total DECIMAL(10,2),
+    discounts_total DECIMAL(10,2),
+    taxes_total DECIMAL(10,2),
+    total DECIMAL(10,2) NOT NULL,
+    payment_method VARCHAR(20),
+    policy_number VARCHAR(100),
+    trip_started_at TIMESTAMP,
+    trip_completed_at TIMESTAMP,
+    issued_at TIMESTAMP NOT NULL,
+    emailed_to VARCHAR(255),
+    emailed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ride_receipts_rider_id ON ride_receipts (rider_id);
@@ -0,0 +1,9 @@
+ALTER TABLE service_orders
+    DROP COLUMN IF EXISTS taxes,
+    DROP COLUMN IF EXISTS tax_total;
+
+ALTER TABLE ride_fare_snapshots
+    DROP COLUMN IF EXISTS tax_lines;
+
+DROP TABLE IF EXISTS tax_rates;
+DROP TABLE IF EXISTS tax_regions;
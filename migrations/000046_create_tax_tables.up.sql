@@ -0,0 +1,45 @@
+CREATE TABLE IF NOT EXISTS tax_regions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code VARCHAR(50) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    center_lat DECIMAL(10,8) DEFAULT 0,
+    center_lon DECIMAL(11,8) DEFAULT 0,
+    radius_km DECIMAL(8,2) DEFAULT 0,
+    is_default BOOLEAN DEFAULT false,
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tax_regions_code ON tax_regions (code) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tax_regions_default ON tax_regions (is_default) WHERE is_default AND deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_tax_regions_is_active ON tax_regions (is_active);
+CREATE INDEX IF NOT EXISTS idx_tax_regions_deleted_at ON tax_regions (deleted_at);
+
+CREATE TABLE IF NOT EXISTS tax_rates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    region_id UUID NOT NULL REFERENCES tax_regions(id) ON DELETE CASCADE,
+    code VARCHAR(50) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    rate DECIMAL(6,3) NOT NULL,
+    applies_to VARCHAR(30) NOT NULL DEFAULT 'all',
+    is_active BOOLEAN DEFAULT true,
+    effective_from TIMESTAMP,
+    effective_until TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tax_rates_region_code ON tax_rates (region_id, code, applies_to) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_tax_rates_region_id ON tax_rates (region_id);
+CREATE INDEX IF NOT EXISTS idx_tax_rates_is_active ON tax_rates (is_active);
+CREATE INDEX IF NOT EXISTS idx_tax_rates_deleted_at ON tax_rates (deleted_at);
+
+ALTER TABLE ride_fare_snapshots
+    ADD COLUMN IF NOT EXISTS tax_lines JSONB;
+
+ALTER TABLE service_orders
+    ADD COLUMN IF NOT EXISTS tax_total DECIMAL(10,2) DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS taxes JSONB;
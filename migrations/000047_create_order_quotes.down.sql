@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_service_orders_quote_id;
+
+ALTER TABLE service_orders
+    DROP COLUMN IF EXISTS quote_id;
+
+DROP TABLE IF EXISTS order_quotes;
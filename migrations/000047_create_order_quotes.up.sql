@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS order_quotes (
+    id UUID PRIMARY KEY,
+    quote_number VARCHAR(50) NOT NULL UNIQUE,
+    customer_id UUID NOT NULL,
+    category_slug VARCHAR(255) NOT NULL,
+    selected_services JSONB NOT NULL,
+    selected_addons JSONB,
+    surcharges JSONB,
+    taxes JSONB,
+    services_total DECIMAL(10,2) NOT NULL,
+    addons_total DECIMAL(10,2) DEFAULT 0,
+    subtotal DECIMAL(10,2) NOT NULL,
+    surcharges_total DECIMAL(10,2) DEFAULT 0,
+    tax_total DECIMAL(10,2) DEFAULT 0,
+    total_price DECIMAL(10,2) NOT NULL,
+    tax_included BOOLEAN DEFAULT FALSE,
+    terms TEXT[],
+    status VARCHAR(20) NOT NULL DEFAULT 'issued',
+    valid_until TIMESTAMP NOT NULL,
+    converted_order_id UUID,
+    converted_at TIMESTAMP,
+    share_count INT DEFAULT 0,
+    download_count INT DEFAULT 0,
+    last_accessed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_quotes_customer_id ON order_quotes (customer_id);
+CREATE INDEX IF NOT EXISTS idx_order_quotes_status ON order_quotes (status);
+
+ALTER TABLE service_orders
+    ADD COLUMN IF NOT EXISTS quote_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_service_orders_quote_id ON service_orders (quote_id);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS surge_snapshots;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS surge_snapshots (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    geohash VARCHAR(20) NOT NULL,
+    vehicle_type_id UUID,
+    lat DECIMAL(10,8),
+    lon DECIMAL(11,8),
+    multiplier DECIMAL(4,2) NOT NULL,
+    time_multiplier DECIMAL(4,2),
+    demand_multiplier DECIMAL(4,2),
+    reason VARCHAR(50),
+    recorded_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_surge_snapshots_geohash_recorded ON surge_snapshots (geohash, recorded_at);
@@ -0,0 +1,4 @@
+ALTER TABLE service_orders
+    DROP COLUMN IF EXISTS payout_status;
+
+DROP TABLE IF EXISTS payout_credits;
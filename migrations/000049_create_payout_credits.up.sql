@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS payout_credits (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    reference_type VARCHAR(50) NOT NULL,
+    reference_id UUID NOT NULL,
+    reference_number VARCHAR(50),
+    provider_id UUID,
+    provider_user_id UUID NOT NULL,
+    amount DECIMAL(12,2) NOT NULL,
+    transaction_type VARCHAR(50) NOT NULL,
+    description TEXT,
+    metadata JSONB NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    next_attempt_at TIMESTAMP,
+    alerted_at TIMESTAMP,
+    transaction_id UUID,
+    credited_at TIMESTAMP,
+    resolved_by UUID,
+    resolution_note TEXT,
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_payout_credits_status_next_attempt ON payout_credits (status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_payout_credits_reference ON payout_credits (reference_type, reference_id);
+CREATE INDEX IF NOT EXISTS idx_payout_credits_provider_user_id ON payout_credits (provider_user_id);
+
+ALTER TABLE service_orders
+    ADD COLUMN IF NOT EXISTS payout_status VARCHAR(20);
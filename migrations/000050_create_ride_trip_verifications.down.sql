@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS ride_trip_verifications;
+DROP TABLE IF EXISTS ride_track_points;
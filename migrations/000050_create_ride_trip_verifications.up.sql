@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS ride_track_points (
+    id BIGSERIAL PRIMARY KEY,
+    ride_id UUID NOT NULL REFERENCES rides(id) ON DELETE CASCADE,
+    driver_id UUID NOT NULL,
+    latitude DECIMAL(10,8) NOT NULL,
+    longitude DECIMAL(11,8) NOT NULL,
+    speed DECIMAL(6,2) DEFAULT 0,
+    accuracy DECIMAL(6,2) DEFAULT 0,
+    recorded_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_ride_track_points_ride_recorded ON ride_track_points (ride_id, recorded_at);
+
+CREATE TABLE IF NOT EXISTS ride_trip_verifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    ride_id UUID NOT NULL UNIQUE REFERENCES rides(id) ON DELETE CASCADE,
+    driver_id UUID NOT NULL,
+    reported_distance_km DECIMAL(10,2) NOT NULL,
+    reported_duration_sec INT NOT NULL,
+    tracked_distance_km DECIMAL(10,2) NOT NULL DEFAULT 0,
+    tracked_duration_sec INT NOT NULL DEFAULT 0,
+    track_point_count INT NOT NULL DEFAULT 0,
+    distance_deviation_pct DECIMAL(8,2) DEFAULT 0,
+    duration_deviation_pct DECIMAL(8,2) DEFAULT 0,
+    status VARCHAR(20) NOT NULL,
+    reasons TEXT[] NOT NULL DEFAULT '{}',
+    reviewed_by UUID,
+    review_note TEXT,
+    reviewed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ride_trip_verifications_status ON ride_trip_verifications (status, created_at);
+CREATE INDEX IF NOT EXISTS idx_ride_trip_verifications_driver_id ON ride_trip_verifications (driver_id);
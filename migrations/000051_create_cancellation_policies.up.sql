@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS cancellation_policies (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    product VARCHAR(30) NOT NULL,
+    vehicle_type_id UUID REFERENCES vehicle_types(id) ON DELETE CASCADE,
+    category_slug VARCHAR(255),
+    city VARCHAR(100),
+    center_lat DECIMAL(10,8) DEFAULT 0,
+    center_lon DECIMAL(11,8) DEFAULT 0,
+    radius_km DECIMAL(8,2) DEFAULT 0,
+    free_window_seconds INT NOT NULL DEFAULT 0,
+    customer_fees JSONB NOT NULL DEFAULT '{}',
+    driver_penalties JSONB NOT NULL DEFAULT '{}',
+    driver_no_show_minutes INT NOT NULL DEFAULT 0,
+    driver_no_show_penalty JSONB NOT NULL DEFAULT '{}',
+    rider_no_show_minutes INT NOT NULL DEFAULT 0,
+    rider_no_show_fee JSONB NOT NULL DEFAULT '{}',
+    priority INT NOT NULL DEFAULT 0,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_cancellation_policies_product_active ON cancellation_policies (product, is_active) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_cancellation_policies_deleted_at ON cancellation_policies (deleted_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS masked_call_events;
+DROP TABLE IF EXISTS masked_call_sessions;
@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS masked_call_sessions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    reference_type VARCHAR(20) NOT NULL,
+    reference_id UUID NOT NULL,
+    provider VARCHAR(30) NOT NULL,
+    provider_session_id VARCHAR(100),
+    customer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    partner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    customer_participant_id VARCHAR(100),
+    partner_participant_id VARCHAR(100),
+    customer_proxy VARCHAR(20),
+    partner_proxy VARCHAR(20),
+    status VARCHAR(20) NOT NULL,
+    failure_reason TEXT,
+    expires_at TIMESTAMP NOT NULL,
+    closed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_masked_call_sessions_reference ON masked_call_sessions (reference_type, reference_id);
+CREATE INDEX IF NOT EXISTS idx_masked_call_sessions_provider_session_id ON masked_call_sessions (provider_session_id);
+CREATE INDEX IF NOT EXISTS idx_masked_call_sessions_customer_id ON masked_call_sessions (customer_id);
+CREATE INDEX IF NOT EXISTS idx_masked_call_sessions_partner_id ON masked_call_sessions (partner_id);
+CREATE INDEX IF NOT EXISTS idx_masked_call_sessions_status ON masked_call_sessions (status);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_masked_call_sessions_one_active ON masked_call_sessions (reference_type, reference_id) WHERE status = 'active';
+
+CREATE TABLE IF NOT EXISTS masked_call_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    session_id UUID NOT NULL REFERENCES masked_call_sessions(id) ON DELETE CASCADE,
+    provider_event_id VARCHAR(100) NOT NULL,
+    caller_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    caller_role VARCHAR(20),
+    status VARCHAR(30) NOT NULL,
+    duration_sec INT NOT NULL DEFAULT 0,
+    occurred_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_masked_call_events_session_id ON masked_call_events (session_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_masked_call_events_provider_event ON masked_call_events (provider_event_id, status);
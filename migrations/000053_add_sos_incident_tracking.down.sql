@@ -0,0 +1,12 @@
+DROP TABLE IF EXISTS sos_incident_events;
+
+ALTER TABLE sos_alerts
+    DROP COLUMN IF EXISTS acknowledged_at,
+    DROP COLUMN IF EXISTS acknowledged_by,
+    DROP COLUMN IF EXISTS driver_location_at,
+    DROP COLUMN IF EXISTS driver_longitude,
+    DROP COLUMN IF EXISTS driver_latitude,
+    DROP COLUMN IF EXISTS rider_location_source,
+    DROP COLUMN IF EXISTS rider_longitude,
+    DROP COLUMN IF EXISTS rider_latitude,
+    DROP COLUMN IF EXISTS reporter_role;
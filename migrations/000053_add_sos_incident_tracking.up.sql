@@ -0,0 +1,22 @@
+ALTER TABLE sos_alerts
+    ADD COLUMN IF NOT EXISTS reporter_role VARCHAR(20),
+    ADD COLUMN IF NOT EXISTS rider_latitude DECIMAL(10, 8),
+    ADD COLUMN IF NOT EXISTS rider_longitude DECIMAL(11, 8),
+    ADD COLUMN IF NOT EXISTS rider_location_source VARCHAR(20),
+    ADD COLUMN IF NOT EXISTS driver_latitude DECIMAL(10, 8),
+    ADD COLUMN IF NOT EXISTS driver_longitude DECIMAL(11, 8),
+    ADD COLUMN IF NOT EXISTS driver_location_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS acknowledged_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN IF NOT EXISTS acknowledged_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE IF NOT EXISTS sos_incident_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    alert_id UUID NOT NULL REFERENCES sos_alerts(id) ON DELETE CASCADE,
+    actor_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    from_status VARCHAR(50),
+    to_status VARCHAR(50) NOT NULL,
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_sos_incident_events_alert_id ON sos_incident_events (alert_id, created_at);
@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS vehicle_maintenance_records;
+DROP TABLE IF EXISTS vehicle_inspection_files;
+DROP TABLE IF EXISTS vehicle_inspections;
+
+DROP INDEX IF EXISTS idx_vehicles_inspection_valid_until;
+DROP INDEX IF EXISTS idx_vehicles_inspection_blocked;
+
+ALTER TABLE vehicles
+    DROP COLUMN IF EXISTS inspection_blocked_at,
+    DROP COLUMN IF EXISTS inspection_blocked,
+    DROP COLUMN IF EXISTS inspection_valid_until;
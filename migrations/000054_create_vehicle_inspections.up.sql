@@ -0,0 +1,62 @@
+ALTER TABLE vehicles
+    ADD COLUMN IF NOT EXISTS inspection_valid_until TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS inspection_blocked BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS inspection_blocked_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_vehicles_inspection_blocked ON vehicles (inspection_blocked);
+CREATE INDEX IF NOT EXISTS idx_vehicles_inspection_valid_until ON vehicles (inspection_valid_until);
+
+CREATE TABLE IF NOT EXISTS vehicle_inspections (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    vehicle_id UUID NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    driver_id UUID NOT NULL REFERENCES driver_profiles(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL,
+    due_at TIMESTAMP WITH TIME ZONE,
+    reminded_at TIMESTAMP WITH TIME ZONE,
+    inspected_at TIMESTAMP WITH TIME ZONE,
+    submitted_at TIMESTAMP WITH TIME ZONE,
+    odometer_km INT,
+    notes TEXT,
+    valid_until TIMESTAMP WITH TIME ZONE,
+    reviewed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    review_note TEXT,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_vehicle_inspections_vehicle_id ON vehicle_inspections (vehicle_id);
+CREATE INDEX IF NOT EXISTS idx_vehicle_inspections_driver_id ON vehicle_inspections (driver_id);
+CREATE INDEX IF NOT EXISTS idx_vehicle_inspections_status ON vehicle_inspections (status);
+CREATE INDEX IF NOT EXISTS idx_vehicle_inspections_due_at ON vehicle_inspections (due_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_vehicle_inspections_one_open ON vehicle_inspections (vehicle_id)
+    WHERE status IN ('scheduled', 'submitted', 'rejected', 'overdue');
+
+CREATE TABLE IF NOT EXISTS vehicle_inspection_files (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    inspection_id UUID NOT NULL REFERENCES vehicle_inspections(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    file_url VARCHAR(1000) NOT NULL,
+    file_size BIGINT,
+    mime_type VARCHAR(50),
+    image_kit_file_id VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_vehicle_inspection_files_inspection_id ON vehicle_inspection_files (inspection_id);
+
+CREATE TABLE IF NOT EXISTS vehicle_maintenance_records (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    vehicle_id UUID NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    driver_id UUID NOT NULL REFERENCES driver_profiles(id) ON DELETE CASCADE,
+    service_type VARCHAR(50) NOT NULL,
+    description TEXT,
+    odometer_km INT,
+    cost DECIMAL(10, 2) DEFAULT 0,
+    performed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_vehicle_maintenance_records_vehicle_id ON vehicle_maintenance_records (vehicle_id);
+CREATE INDEX IF NOT EXISTS idx_vehicle_maintenance_records_driver_id ON vehicle_maintenance_records (driver_id);
@@ -0,0 +1,20 @@
+ALTER TABLE service_orders
+    DROP COLUMN IF EXISTS currency;
+
+ALTER TABLE rides
+    DROP COLUMN IF EXISTS currency;
+
+ALTER TABLE wallet_holds
+    DROP COLUMN IF EXISTS currency;
+
+ALTER TABLE wallet_transactions
+    DROP COLUMN IF EXISTS exchange_rate,
+    DROP COLUMN IF EXISTS original_currency,
+    DROP COLUMN IF EXISTS original_amount,
+    DROP COLUMN IF EXISTS currency;
+
+DROP TABLE IF EXISTS exchange_rates;
+
+DROP INDEX IF EXISTS idx_city_currencies_deleted_at;
+DROP INDEX IF EXISTS idx_city_currencies_is_active;
+DROP TABLE IF EXISTS city_currencies;
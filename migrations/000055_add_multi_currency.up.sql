@@ -0,0 +1,60 @@
+CREATE TABLE IF NOT EXISTS city_currencies (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    city VARCHAR(100) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    center_lat DECIMAL(10,8) NOT NULL,
+    center_lon DECIMAL(11,8) NOT NULL,
+    radius_km DECIMAL(8,2) NOT NULL,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_city_currencies_is_active ON city_currencies (is_active);
+CREATE INDEX IF NOT EXISTS idx_city_currencies_deleted_at ON city_currencies (deleted_at);
+
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    base_currency VARCHAR(3) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    rate DECIMAL(18,8) NOT NULL,
+    source VARCHAR(30) NOT NULL,
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (base_currency, currency)
+);
+
+-- Transactions and holds record the currency they were booked in, which is
+-- always the wallet's. Amounts sent in another currency keep the original.
+ALTER TABLE wallet_transactions
+    ADD COLUMN IF NOT EXISTS currency VARCHAR(3),
+    ADD COLUMN IF NOT EXISTS original_amount DECIMAL(12,2),
+    ADD COLUMN IF NOT EXISTS original_currency VARCHAR(3),
+    ADD COLUMN IF NOT EXISTS exchange_rate DECIMAL(18,8);
+
+UPDATE wallet_transactions t SET currency = w.currency
+FROM wallets w
+WHERE t.currency IS NULL AND w.id = t.wallet_id;
+
+ALTER TABLE wallet_holds
+    ADD COLUMN IF NOT EXISTS currency VARCHAR(3);
+
+UPDATE wallet_holds h SET currency = w.currency
+FROM wallets w
+WHERE h.currency IS NULL AND w.id = h.wallet_id;
+
+-- Rides and orders placed before this were charged in the customer's wallet
+-- currency.
+ALTER TABLE rides
+    ADD COLUMN IF NOT EXISTS currency VARCHAR(3);
+
+UPDATE rides r SET currency = w.currency
+FROM wallets w
+WHERE r.currency IS NULL AND w.user_id = r.rider_id AND w.wallet_type = 'rider';
+
+ALTER TABLE service_orders
+    ADD COLUMN IF NOT EXISTS currency VARCHAR(3);
+
+UPDATE service_orders o SET currency = w.currency
+FROM wallets w
+WHERE o.currency IS NULL AND w.user_id = o.customer_id AND w.wallet_type = 'rider';
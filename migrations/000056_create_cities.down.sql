@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS city_vehicle_types;
+DROP TABLE IF EXISTS cities;
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS cities (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code VARCHAR(50) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    timezone VARCHAR(64),
+    boundary JSONB NOT NULL,
+    fare_multiplier DECIMAL(5,2) NOT NULL DEFAULT 1,
+    max_surge_multiplier DECIMAL(4,2) NOT NULL DEFAULT 0,
+    matching_radii_km JSONB,
+    is_active BOOLEAN DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cities_code ON cities (code) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_cities_is_active ON cities (is_active);
+CREATE INDEX IF NOT EXISTS idx_cities_deleted_at ON cities (deleted_at);
+
+CREATE TABLE IF NOT EXISTS city_vehicle_types (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    city_id UUID NOT NULL REFERENCES cities(id) ON DELETE CASCADE,
+    vehicle_type_id UUID NOT NULL REFERENCES vehicle_types(id) ON DELETE CASCADE,
+    is_available BOOLEAN NOT NULL DEFAULT TRUE,
+    base_fare DECIMAL(10,2),
+    per_km_rate DECIMAL(10,2),
+    per_minute_rate DECIMAL(10,2),
+    booking_fee DECIMAL(10,2),
+    max_surge_multiplier DECIMAL(4,2),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT idx_city_vehicle_type UNIQUE (city_id, vehicle_type_id)
+);
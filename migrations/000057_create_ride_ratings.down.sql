@@ -0,0 +1,3 @@
+ALTER TABLE rider_profiles DROP COLUMN IF EXISTS rating_count;
+ALTER TABLE driver_profiles DROP COLUMN IF EXISTS rating_count;
+DROP TABLE IF EXISTS ride_ratings;
@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS ride_ratings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    ride_id UUID NOT NULL REFERENCES rides(id) ON DELETE CASCADE,
+    direction VARCHAR(20) NOT NULL,
+    rater_id UUID NOT NULL,
+    ratee_id UUID NOT NULL,
+    score INT NOT NULL CHECK (score BETWEEN 1 AND 5),
+    tags TEXT[] NOT NULL DEFAULT '{}',
+    comment TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT idx_ride_rating_direction UNIQUE (ride_id, direction)
+);
+
+CREATE INDEX IF NOT EXISTS idx_ride_ratings_ratee_id ON ride_ratings (ratee_id, direction, created_at DESC);
+
+-- Ratings given before this table existed live only on the ride row.
+INSERT INTO ride_ratings (ride_id, direction, rater_id, ratee_id, score, comment, created_at)
+SELECT id, 'rider_to_driver', rider_id, driver_id, driver_rating::INT, driver_rating_comment,
+       COALESCE(driver_rated_at, updated_at)
+FROM rides
+WHERE driver_rating IS NOT NULL AND driver_id IS NOT NULL
+ON CONFLICT (ride_id, direction) DO NOTHING;
+
+INSERT INTO ride_ratings (ride_id, direction, rater_id, ratee_id, score, comment, created_at)
+SELECT id, 'driver_to_rider', driver_id, rider_id, rider_rating::INT, rider_rating_comment,
+       COALESCE(rider_rated_at, updated_at)
+FROM rides
+WHERE rider_rating IS NOT NULL AND driver_id IS NOT NULL
+ON CONFLICT (ride_id, direction) DO NOTHING;
+
+ALTER TABLE driver_profiles ADD COLUMN IF NOT EXISTS rating_count INT DEFAULT 0;
+ALTER TABLE rider_profiles ADD COLUMN IF NOT EXISTS rating_count INT NOT NULL DEFAULT 0;
+
+UPDATE driver_profiles p SET rating_count = c.count
+FROM (SELECT ratee_id, COUNT(*) AS count FROM ride_ratings WHERE direction = 'rider_to_driver' GROUP BY ratee_id) c
+WHERE p.user_id = c.ratee_id;
+
+UPDATE rider_profiles p SET rating_count = c.count
+FROM (SELECT ratee_id, COUNT(*) AS count FROM ride_ratings WHERE direction = 'driver_to_rider' GROUP BY ratee_id) c
+WHERE p.user_id = c.ratee_id;
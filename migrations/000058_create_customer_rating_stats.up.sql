@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS customer_rating_stats (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    rating DECIMAL(3,2) NOT NULL DEFAULT 0,
+    total_reviews INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+INSERT INTO customer_rating_stats (user_id, rating, total_reviews, updated_at)
+SELECT customer_id, ROUND(AVG(provider_rating)::NUMERIC, 2), COUNT(*), NOW()
+FROM service_orders
+WHERE provider_rating IS NOT NULL
+GROUP BY customer_id
+ON CONFLICT (user_id) DO NOTHING;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS preferred_providers;
+DROP TABLE IF EXISTS favorite_drivers;
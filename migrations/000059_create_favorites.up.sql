@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS favorite_drivers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    rider_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    driver_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    note VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT idx_favorite_driver UNIQUE (rider_id, driver_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_favorite_drivers_driver_id ON favorite_drivers (driver_id);
+
+CREATE TABLE IF NOT EXISTS preferred_providers (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    customer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider_id UUID NOT NULL REFERENCES service_provider_profiles(id) ON DELETE CASCADE,
+    note VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CONSTRAINT idx_preferred_provider UNIQUE (customer_id, provider_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_preferred_providers_provider_id ON preferred_providers (provider_id);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_service_orders_recurring_plan_id;
+ALTER TABLE service_orders DROP COLUMN IF EXISTS recurring_plan_id;
+DROP TABLE IF EXISTS recurring_order_plans;
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS recurring_order_plans (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    customer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    frequency VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    template JSONB NOT NULL,
+    start_date VARCHAR(10) NOT NULL,
+    end_date VARCHAR(10),
+    max_occurrences INTEGER,
+    next_occurrence INTEGER NOT NULL DEFAULT 1,
+    next_date VARCHAR(10) NOT NULL,
+    skipped_dates TEXT[] NOT NULL DEFAULT '{}',
+    orders_created INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    paused_at TIMESTAMP WITH TIME ZONE,
+    cancelled_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_recurring_order_plans_customer_id ON recurring_order_plans (customer_id);
+CREATE INDEX IF NOT EXISTS idx_recurring_order_plans_due ON recurring_order_plans (status, next_date);
+
+ALTER TABLE service_orders ADD COLUMN IF NOT EXISTS recurring_plan_id UUID REFERENCES recurring_order_plans(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_service_orders_recurring_plan_id ON service_orders (recurring_plan_id);
@@ -0,0 +1,2 @@
+ALTER TABLE service_orders DROP COLUMN IF EXISTS reschedule_count;
+DROP TABLE IF EXISTS order_reschedules;
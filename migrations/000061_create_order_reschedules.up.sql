@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS order_reschedules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES service_orders(id) ON DELETE CASCADE,
+    requested_by VARCHAR(20) NOT NULL,
+    requested_by_id UUID NOT NULL,
+    previous_date VARCHAR(10) NOT NULL,
+    previous_time VARCHAR(5) NOT NULL,
+    new_date VARCHAR(10) NOT NULL,
+    new_time VARCHAR(5) NOT NULL,
+    reason TEXT,
+    status VARCHAR(20) NOT NULL,
+    response_note TEXT,
+    responded_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_reschedules_order_id ON order_reschedules (order_id);
+CREATE INDEX IF NOT EXISTS idx_order_reschedules_status ON order_reschedules (status);
+-- At most one request per order waits for an answer.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_order_reschedules_one_pending ON order_reschedules (order_id) WHERE status = 'pending';
+
+ALTER TABLE service_orders ADD COLUMN IF NOT EXISTS reschedule_count INTEGER NOT NULL DEFAULT 0;
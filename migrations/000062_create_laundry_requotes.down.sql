@@ -0,0 +1,3 @@
+ALTER TABLE laundry_orders DROP COLUMN IF EXISTS wallet_hold_id;
+ALTER TABLE laundry_order_items DROP COLUMN IF EXISTS measured_weight;
+DROP TABLE IF EXISTS laundry_requotes;
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS laundry_requotes (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES laundry_orders(id) ON DELETE CASCADE,
+    provider_id UUID NOT NULL,
+    lines JSONB NOT NULL,
+    previous_total DECIMAL(10,2) NOT NULL,
+    new_total DECIMAL(10,2) NOT NULL,
+    difference DECIMAL(10,2) NOT NULL,
+    status VARCHAR(30) NOT NULL,
+    note TEXT,
+    customer_note TEXT,
+    responded_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_laundry_requotes_order_id ON laundry_requotes (order_id);
+CREATE INDEX IF NOT EXISTS idx_laundry_requotes_status ON laundry_requotes (status);
+-- At most one re-quote per order waits for the customer.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_laundry_requotes_one_awaiting ON laundry_requotes (order_id) WHERE status = 'awaiting_approval';
+
+ALTER TABLE laundry_order_items ADD COLUMN IF NOT EXISTS measured_weight DECIMAL(8,3);
+ALTER TABLE laundry_orders ADD COLUMN IF NOT EXISTS wallet_hold_id UUID;
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS laundry_item_scans (
+    id UUID PRIMARY KEY,
+    item_id UUID NOT NULL REFERENCES laundry_order_items(id) ON DELETE CASCADE,
+    order_id UUID NOT NULL REFERENCES laundry_orders(id) ON DELETE CASCADE,
+    qr_code VARCHAR(255) NOT NULL,
+    from_status VARCHAR(50) NOT NULL,
+    to_status VARCHAR(50) NOT NULL,
+    scanned_by UUID NOT NULL,
+    station VARCHAR(100),
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_laundry_item_scans_item_id ON laundry_item_scans (item_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_laundry_item_scans_order_id ON laundry_item_scans (order_id);
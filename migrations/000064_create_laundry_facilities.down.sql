@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_laundry_orders_facility_id;
+ALTER TABLE laundry_orders DROP COLUMN IF EXISTS facility_id;
+DROP TABLE IF EXISTS laundry_station_visits;
+DROP TABLE IF EXISTS laundry_workstations;
+DROP TABLE IF EXISTS laundry_facilities;
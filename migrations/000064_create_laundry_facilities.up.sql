@@ -0,0 +1,43 @@
+CREATE TABLE IF NOT EXISTS laundry_facilities (
+    id UUID PRIMARY KEY,
+    provider_id UUID NOT NULL REFERENCES service_provider_profiles(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    address TEXT,
+    latitude DECIMAL(10,8),
+    longitude DECIMAL(11,8),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_laundry_facilities_provider_id ON laundry_facilities (provider_id);
+
+CREATE TABLE IF NOT EXISTS laundry_workstations (
+    id UUID PRIMARY KEY,
+    facility_id UUID NOT NULL REFERENCES laundry_facilities(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('wash', 'dry', 'press', 'pack')),
+    capacity INTEGER NOT NULL CHECK (capacity > 0),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_laundry_workstations_facility_type ON laundry_workstations (facility_id, type);
+
+CREATE TABLE IF NOT EXISTS laundry_station_visits (
+    id UUID PRIMARY KEY,
+    workstation_id UUID NOT NULL REFERENCES laundry_workstations(id) ON DELETE CASCADE,
+    item_id UUID NOT NULL REFERENCES laundry_order_items(id) ON DELETE CASCADE,
+    order_id UUID NOT NULL REFERENCES laundry_orders(id) ON DELETE CASCADE,
+    entered_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    exited_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_laundry_station_visits_workstation ON laundry_station_visits (workstation_id, exited_at);
+CREATE INDEX IF NOT EXISTS idx_laundry_station_visits_order_id ON laundry_station_visits (order_id);
+-- An item is at most at one station at a time.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_laundry_station_visits_one_open ON laundry_station_visits (item_id) WHERE exited_at IS NULL;
+
+ALTER TABLE laundry_orders ADD COLUMN IF NOT EXISTS facility_id UUID REFERENCES laundry_facilities(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_laundry_orders_facility_id ON laundry_orders (facility_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_saved_locations_user_label;
+ALTER TABLE saved_locations DROP COLUMN IF EXISTS geocoded;
+ALTER TABLE saved_locations DROP COLUMN IF EXISTS instructions;
+ALTER TABLE saved_locations DROP COLUMN IF EXISTS details;
@@ -0,0 +1,5 @@
+ALTER TABLE saved_locations ADD COLUMN IF NOT EXISTS details VARCHAR(255);
+ALTER TABLE saved_locations ADD COLUMN IF NOT EXISTS instructions TEXT;
+ALTER TABLE saved_locations ADD COLUMN IF NOT EXISTS geocoded BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_saved_locations_user_label ON saved_locations (user_id, label) WHERE deleted_at IS NULL;
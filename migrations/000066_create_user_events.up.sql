@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS user_events (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL,
+    type VARCHAR(64) NOT NULL,
+    message_id VARCHAR(64),
+    payload JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_events_user_id_id ON user_events (user_id, id);
+CREATE INDEX IF NOT EXISTS idx_user_events_created_at ON user_events (created_at);
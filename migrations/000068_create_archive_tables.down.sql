@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS archived_related_rows;
+DROP TABLE IF EXISTS service_orders_archive;
+DROP TABLE IF EXISTS rides_archive;
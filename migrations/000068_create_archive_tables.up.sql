@@ -0,0 +1,33 @@
+-- Archive copies of finished rides and orders. Columns mirror the live tables
+-- at the time of the migration; the archival job copies the columns both
+-- tables share, so later additions to the live tables do not break it.
+CREATE TABLE IF NOT EXISTS rides_archive (LIKE rides INCLUDING DEFAULTS);
+ALTER TABLE rides_archive ADD PRIMARY KEY (id);
+ALTER TABLE rides_archive ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+
+CREATE INDEX IF NOT EXISTS idx_rides_archive_rider_id ON rides_archive (rider_id, requested_at DESC);
+CREATE INDEX IF NOT EXISTS idx_rides_archive_driver_id ON rides_archive (driver_id, requested_at DESC);
+CREATE INDEX IF NOT EXISTS idx_rides_archive_archived_at ON rides_archive (archived_at);
+
+CREATE TABLE IF NOT EXISTS service_orders_archive (LIKE service_orders INCLUDING DEFAULTS);
+ALTER TABLE service_orders_archive ADD PRIMARY KEY (id);
+ALTER TABLE service_orders_archive ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW();
+
+CREATE INDEX IF NOT EXISTS idx_service_orders_archive_customer_id ON service_orders_archive (customer_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_service_orders_archive_provider_id ON service_orders_archive (assigned_provider_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_service_orders_archive_order_number ON service_orders_archive (order_number);
+CREATE INDEX IF NOT EXISTS idx_service_orders_archive_archived_at ON service_orders_archive (archived_at);
+
+-- Rows in other tables that point at an archived ride or order. Most of them
+-- would be removed by ON DELETE CASCADE when the parent leaves the live table,
+-- so they are kept here as JSON, keyed by the archived parent.
+CREATE TABLE IF NOT EXISTS archived_related_rows (
+    id BIGSERIAL PRIMARY KEY,
+    parent_table VARCHAR(64) NOT NULL,
+    parent_id UUID NOT NULL,
+    source_table VARCHAR(64) NOT NULL,
+    row_data JSONB NOT NULL,
+    archived_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_archived_related_rows_parent ON archived_related_rows (parent_table, parent_id);
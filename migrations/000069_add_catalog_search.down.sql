@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_addons_search_vector;
+DROP INDEX IF EXISTS idx_services_search_vector;
+
+ALTER TABLE addons DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE services DROP COLUMN IF EXISTS search_vector;
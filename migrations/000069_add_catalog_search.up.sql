@@ -0,0 +1,22 @@
+-- Full-text search over the home services catalog. Titles carry the most
+-- weight, then the category, then descriptions. pg_trgm provides the fuzzy
+-- title match used for typo tolerance.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE services
+    ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('english', replace(category_slug, '-', ' ')), 'B') ||
+        setweight(to_tsvector('english', coalesce(long_title, '') || ' ' || coalesce(description, '')), 'C') ||
+        setweight(to_tsvector('english', coalesce(long_description, '') || ' ' || coalesce(highlights, '')), 'D')
+    ) STORED;
+
+ALTER TABLE addons
+    ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('english', replace(category_slug, '-', ' ')), 'B') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'C')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_services_search_vector ON services USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_addons_search_vector ON addons USING GIN (search_vector);
@@ -0,0 +1,26 @@
+CREATE TABLE IF NOT EXISTS media (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    uploader_id UUID NOT NULL,
+    entity_type VARCHAR(30) NOT NULL CHECK (entity_type IN ('service', 'addon', 'vehicle', 'document')),
+    entity_id UUID,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'ready')),
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL DEFAULT 0,
+    storage_key VARCHAR(500) NOT NULL UNIQUE,
+    thumbnail_key VARCHAR(500),
+    url VARCHAR(1000) NOT NULL,
+    thumbnail_url VARCHAR(1000),
+    width INTEGER,
+    height INTEGER,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    attached_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_media_entity ON media (entity_type, entity_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_media_uploader_id ON media (uploader_id);
+CREATE INDEX IF NOT EXISTS idx_media_deleted_at ON media (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_media_created_at ON media (created_at);
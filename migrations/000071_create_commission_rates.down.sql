@@ -0,0 +1,4 @@
+ALTER TABLE laundry_orders DROP COLUMN IF EXISTS commission_rate;
+ALTER TABLE service_orders DROP COLUMN IF EXISTS commission_rate;
+
+DROP TABLE IF EXISTS commission_rates;
@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS commission_rates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('ride', 'homeservice')),
+    vehicle_type_id UUID REFERENCES vehicle_types(id) ON DELETE CASCADE,
+    category_slug VARCHAR(100),
+    city_id UUID REFERENCES cities(id) ON DELETE CASCADE,
+    rate DECIMAL(5,2) NOT NULL CHECK (rate >= 0 AND rate <= 100),
+    effective_from TIMESTAMP WITH TIME ZONE NOT NULL,
+    note TEXT,
+    created_by UUID,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (kind = 'ride' OR vehicle_type_id IS NULL),
+    CHECK (kind = 'homeservice' OR category_slug IS NULL)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_commission_rates_version ON commission_rates (
+    kind,
+    COALESCE(vehicle_type_id, '00000000-0000-0000-0000-000000000000'),
+    COALESCE(category_slug, ''),
+    COALESCE(city_id, '00000000-0000-0000-0000-000000000000'),
+    effective_from
+);
+CREATE INDEX IF NOT EXISTS idx_commission_rates_lookup ON commission_rates (kind, effective_from DESC);
+
+-- Defaults matching the rates previously hardcoded: drivers keep 80% of the
+-- fare and providers 90% of the service amount.
+INSERT INTO commission_rates (kind, rate, effective_from, note)
+VALUES
+    ('ride', 20, '2000-01-01T00:00:00Z', 'Platform default'),
+    ('homeservice', 10, '2000-01-01T00:00:00Z', 'Platform default')
+ON CONFLICT DO NOTHING;
+
+-- Orders keep the rate in force when they were placed.
+ALTER TABLE service_orders ADD COLUMN IF NOT EXISTS commission_rate DECIMAL(5,2) NOT NULL DEFAULT 10;
+ALTER TABLE laundry_orders ADD COLUMN IF NOT EXISTS commission_rate DECIMAL(5,2) NOT NULL DEFAULT 10;
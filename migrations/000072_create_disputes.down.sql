@@ -0,0 +1,6 @@
+DELETE FROM media WHERE entity_type = 'dispute';
+ALTER TABLE media DROP CONSTRAINT IF EXISTS media_entity_type_check;
+ALTER TABLE media ADD CONSTRAINT media_entity_type_check
+    CHECK (entity_type IN ('service', 'addon', 'vehicle', 'document'));
+
+DROP TABLE IF EXISTS disputes;
@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS disputes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    subject_type VARCHAR(20) NOT NULL CHECK (subject_type IN ('ride', 'service_order')),
+    subject_id UUID NOT NULL,
+    reference VARCHAR(50),
+    opened_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    respondent_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    reason VARCHAR(30) NOT NULL,
+    description TEXT NOT NULL,
+    amount_paid DECIMAL(10,2) NOT NULL DEFAULT 0,
+    currency VARCHAR(3),
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'responded', 'resolved', 'withdrawn')),
+    response TEXT,
+    responded_at TIMESTAMP WITH TIME ZONE,
+    outcome VARCHAR(20) CHECK (outcome IN ('refund', 'partial_refund', 'rejected')),
+    refund_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    refund_txn_id UUID,
+    resolved_by UUID,
+    resolution_note TEXT,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    withdrawn_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- A ride or order can be disputed once; a withdrawn dispute can be reopened.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_disputes_subject ON disputes (subject_type, subject_id) WHERE status <> 'withdrawn';
+CREATE INDEX IF NOT EXISTS idx_disputes_opened_by ON disputes (opened_by);
+CREATE INDEX IF NOT EXISTS idx_disputes_respondent_id ON disputes (respondent_id);
+CREATE INDEX IF NOT EXISTS idx_disputes_status ON disputes (status, created_at);
+
+-- Dispute evidence is uploaded through the media module.
+ALTER TABLE media DROP CONSTRAINT IF EXISTS media_entity_type_check;
+ALTER TABLE media ADD CONSTRAINT media_entity_type_check
+    CHECK (entity_type IN ('service', 'addon', 'vehicle', 'document', 'dispute'));
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS account_suspensions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('suspension', 'ban')),
+    reason TEXT NOT NULL,
+    previous_status VARCHAR(30) NOT NULL,
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    ends_at TIMESTAMP WITH TIME ZONE,
+    created_by UUID NOT NULL,
+    lifted_at TIMESTAMP WITH TIME ZONE,
+    lifted_by UUID,
+    lift_reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (kind = 'suspension' OR ends_at IS NULL),
+    CHECK (ends_at IS NULL OR ends_at > starts_at)
+);
+
+-- A user has at most one suspension or ban in force at a time.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_account_suspensions_active ON account_suspensions (user_id) WHERE lifted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_account_suspensions_user_id ON account_suspensions (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_account_suspensions_ends_at ON account_suspensions (ends_at) WHERE lifted_at IS NULL AND ends_at IS NOT NULL;
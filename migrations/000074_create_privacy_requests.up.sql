@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS privacy_requests (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('export', 'deletion')),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'processing', 'completed', 'failed', 'cancelled')),
+    reason TEXT,
+    scheduled_for TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    archive_key VARCHAR(500),
+    archive_size BIGINT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    cancelled_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- A user has at most one export and one deletion in flight at a time.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_privacy_requests_open ON privacy_requests (user_id, type) WHERE status IN ('pending', 'processing');
+CREATE INDEX IF NOT EXISTS idx_privacy_requests_user_id ON privacy_requests (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_privacy_requests_due ON privacy_requests (scheduled_for) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_privacy_requests_expires_at ON privacy_requests (expires_at) WHERE archive_key IS NOT NULL;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS partner_api_usage;
+DROP TABLE IF EXISTS partner_bookings;
+DROP TABLE IF EXISTS partner_users;
+DROP TABLE IF EXISTS partner_api_keys;
+DROP TABLE IF EXISTS partners;
@@ -0,0 +1,67 @@
+CREATE TABLE IF NOT EXISTS partners (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(255) NOT NULL,
+    contact_email VARCHAR(255),
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'disabled')),
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS partner_api_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    partner_id UUID NOT NULL REFERENCES partners(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    key_id VARCHAR(40) NOT NULL UNIQUE,
+    secret_hash VARCHAR(64) NOT NULL,
+    encrypted_secret TEXT,
+    scopes TEXT[] NOT NULL DEFAULT '{}',
+    require_signature BOOLEAN NOT NULL DEFAULT FALSE,
+    rate_limit_per_minute INT NOT NULL CHECK (rate_limit_per_minute > 0),
+    expires_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    revoked_by UUID,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (NOT require_signature OR encrypted_secret IS NOT NULL)
+);
+
+CREATE INDEX IF NOT EXISTS idx_partner_api_keys_partner_id ON partner_api_keys (partner_id);
+
+CREATE TABLE IF NOT EXISTS partner_users (
+    partner_id UUID NOT NULL REFERENCES partners(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    external_ref VARCHAR(100),
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (partner_id, user_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_partner_users_external_ref ON partner_users (partner_id, external_ref) WHERE external_ref IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS partner_bookings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    partner_id UUID NOT NULL REFERENCES partners(id) ON DELETE CASCADE,
+    api_key_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    subject_type VARCHAR(20) NOT NULL CHECK (subject_type IN ('ride', 'service_order')),
+    subject_id UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_partner_bookings_subject ON partner_bookings (subject_type, subject_id);
+CREATE INDEX IF NOT EXISTS idx_partner_bookings_partner_id ON partner_bookings (partner_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS partner_api_usage (
+    api_key_id UUID NOT NULL,
+    day DATE NOT NULL,
+    route VARCHAR(200) NOT NULL,
+    status_class VARCHAR(3) NOT NULL,
+    partner_id UUID NOT NULL,
+    requests BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (api_key_id, day, route, status_class)
+);
+
+CREATE INDEX IF NOT EXISTS idx_partner_api_usage_partner_day ON partner_api_usage (partner_id, day);
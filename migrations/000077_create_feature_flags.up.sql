@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS feature_flags (
+    key VARCHAR(64) PRIMARY KEY,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('boolean', 'number', 'string')),
+    value JSONB NOT NULL,
+    rollout_percent INT NOT NULL DEFAULT 100 CHECK (rollout_percent BETWEEN 0 AND 100),
+    description TEXT NOT NULL DEFAULT '',
+    updated_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- The flags the services read, seeded with their built-in defaults so they
+-- show up in the admin API.
+INSERT INTO feature_flags (key, type, value, description) VALUES
+    ('rides.batch_matching', 'boolean', 'true', 'Match ride requests in batches before falling back to sequential matching. Supports a percentage rollout by rider.'),
+    ('pricing.surge_cap', 'number', '0', 'Platform-wide ceiling on the surge multiplier, applied after the city cap. 0 disables it.')
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1,6 @@
+DROP TRIGGER IF EXISTS track_driver_online_session ON driver_profiles;
+DROP FUNCTION IF EXISTS track_driver_online_session();
+DROP TABLE IF EXISTS driver_online_sessions;
+
+ALTER TABLE rides_archive DROP COLUMN IF EXISTS pickup_eta_minutes;
+ALTER TABLE rides DROP COLUMN IF EXISTS pickup_eta_minutes;
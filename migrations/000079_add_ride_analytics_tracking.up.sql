@@ -0,0 +1,39 @@
+-- The pickup ETA the rider was shown when a driver accepted, to measure how
+-- accurate it was against arrived_at.
+ALTER TABLE rides ADD COLUMN IF NOT EXISTS pickup_eta_minutes INTEGER;
+ALTER TABLE rides_archive ADD COLUMN IF NOT EXISTS pickup_eta_minutes INTEGER;
+
+-- Spans during which a driver was signed on (any status but offline), for
+-- driver utilization. Maintained by a trigger so every path that changes a
+-- driver's status is covered.
+CREATE TABLE IF NOT EXISTS driver_online_sessions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    driver_id UUID NOT NULL REFERENCES driver_profiles(id) ON DELETE CASCADE,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    ended_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_driver_online_sessions_open ON driver_online_sessions (driver_id) WHERE ended_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_driver_online_sessions_span ON driver_online_sessions (started_at, ended_at);
+
+CREATE OR REPLACE FUNCTION track_driver_online_session()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF COALESCE(NEW.status, 'offline') = 'offline' THEN
+        UPDATE driver_online_sessions SET ended_at = NOW()
+        WHERE driver_id = NEW.id AND ended_at IS NULL;
+    ELSIF COALESCE(OLD.status, 'offline') = 'offline' THEN
+        INSERT INTO driver_online_sessions (driver_id) VALUES (NEW.id);
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS track_driver_online_session ON driver_profiles;
+CREATE TRIGGER track_driver_online_session AFTER UPDATE OF status ON driver_profiles
+    FOR EACH ROW WHEN (OLD.status IS DISTINCT FROM NEW.status)
+    EXECUTE FUNCTION track_driver_online_session();
+
+INSERT INTO driver_online_sessions (driver_id)
+SELECT id FROM driver_profiles
+WHERE COALESCE(status, 'offline') <> 'offline' AND deleted_at IS NULL;
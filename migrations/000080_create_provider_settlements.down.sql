@@ -0,0 +1,8 @@
+ALTER TABLE laundry_orders
+    DROP COLUMN IF EXISTS settlement_status;
+
+ALTER TABLE service_orders
+    DROP COLUMN IF EXISTS settlement_status;
+
+DROP TABLE IF EXISTS settlement_items;
+DROP TABLE IF EXISTS provider_settlements;
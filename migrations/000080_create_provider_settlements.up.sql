@@ -0,0 +1,45 @@
+CREATE TABLE IF NOT EXISTS provider_settlements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    provider_user_id UUID NOT NULL,
+    provider_id UUID,
+    period_start TIMESTAMP NOT NULL,
+    period_end TIMESTAMP NOT NULL,
+    item_count INT NOT NULL DEFAULT 0,
+    total_amount DECIMAL(12,2) NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending_approval',
+    reviewed_by UUID,
+    review_note TEXT,
+    reviewed_at TIMESTAMP,
+    paid_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_provider_settlements_provider_period ON provider_settlements (provider_user_id, period_end);
+CREATE INDEX IF NOT EXISTS idx_provider_settlements_status ON provider_settlements (status);
+
+CREATE TABLE IF NOT EXISTS settlement_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    settlement_id UUID REFERENCES provider_settlements(id) ON DELETE SET NULL,
+    reference_type VARCHAR(50) NOT NULL,
+    reference_id UUID NOT NULL,
+    reference_number VARCHAR(50),
+    provider_id UUID,
+    provider_user_id UUID NOT NULL,
+    amount DECIMAL(12,2) NOT NULL,
+    transaction_type VARCHAR(50) NOT NULL,
+    description TEXT,
+    metadata JSONB NOT NULL DEFAULT '{}',
+    earned_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_settlement_items_unsettled ON settlement_items (provider_user_id, earned_at) WHERE settlement_id IS NULL;
+CREATE INDEX IF NOT EXISTS idx_settlement_items_settlement_id ON settlement_items (settlement_id);
+CREATE INDEX IF NOT EXISTS idx_settlement_items_reference ON settlement_items (reference_type, reference_id);
+
+ALTER TABLE service_orders
+    ADD COLUMN IF NOT EXISTS settlement_status VARCHAR(20);
+
+ALTER TABLE laundry_orders
+    ADD COLUMN IF NOT EXISTS settlement_status VARCHAR(20);
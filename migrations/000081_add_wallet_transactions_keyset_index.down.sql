@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_wallet_transactions_wallet_created;
@@ -0,0 +1,6 @@
+ALTER TABLE wallet_transactions
+    DROP COLUMN IF EXISTS journal_entry_id;
+
+DROP TABLE IF EXISTS ledger_postings;
+DROP TABLE IF EXISTS journal_entries;
+DROP TABLE IF EXISTS ledger_accounts;
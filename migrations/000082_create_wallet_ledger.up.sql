@@ -0,0 +1,91 @@
+CREATE TABLE IF NOT EXISTS ledger_accounts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code VARCHAR(100) NOT NULL,
+    kind VARCHAR(30) NOT NULL,
+    normal_balance VARCHAR(10) NOT NULL,
+    wallet_id UUID REFERENCES wallets(id) ON DELETE SET NULL,
+    currency VARCHAR(3) NOT NULL,
+    balance DECIMAL(14,2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ledger_accounts_code ON ledger_accounts (code);
+CREATE INDEX IF NOT EXISTS idx_ledger_accounts_wallet_id ON ledger_accounts (wallet_id);
+
+CREATE TABLE IF NOT EXISTS journal_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    kind VARCHAR(50) NOT NULL,
+    reference_type VARCHAR(50),
+    reference_id VARCHAR(100),
+    description TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_journal_entries_kind ON journal_entries (kind);
+CREATE INDEX IF NOT EXISTS idx_journal_entries_reference ON journal_entries (reference_type, reference_id);
+CREATE INDEX IF NOT EXISTS idx_journal_entries_created_at ON journal_entries (created_at);
+
+CREATE TABLE IF NOT EXISTS ledger_postings (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    entry_id UUID NOT NULL REFERENCES journal_entries(id) ON DELETE RESTRICT,
+    account_id UUID NOT NULL REFERENCES ledger_accounts(id) ON DELETE RESTRICT,
+    amount DECIMAL(14,2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_postings_entry_id ON ledger_postings (entry_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_postings_account_id ON ledger_postings (account_id);
+
+ALTER TABLE wallet_transactions
+    ADD COLUMN IF NOT EXISTS journal_entry_id UUID REFERENCES journal_entries(id);
+
+-- Holds never moved held_balance before the ledger. Count the open ones so
+-- that capturing or releasing them draws on the held account.
+UPDATE wallets w
+SET held_balance = COALESCE((
+    SELECT SUM(h.amount) FROM wallet_holds h
+    WHERE h.wallet_id = w.id AND h.status::text IN ('active', 'held')
+), 0);
+
+-- Carry the current wallet balances into the ledger as opening entries
+-- balanced against an opening account per currency.
+INSERT INTO ledger_accounts (code, kind, normal_balance, wallet_id, currency)
+SELECT 'wallet:' || id || ':available', 'wallet_available', 'credit', id, currency FROM wallets
+UNION ALL
+SELECT 'wallet:' || id || ':held', 'wallet_held', 'credit', id, currency FROM wallets
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO ledger_accounts (code, kind, normal_balance, currency)
+SELECT DISTINCT 'platform:opening:' || currency, 'opening', 'credit', currency FROM wallets
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO journal_entries (kind, reference_type, reference_id, description)
+SELECT 'opening_balance', 'wallet', id, 'Balance carried over to the ledger'
+FROM wallets
+WHERE balance <> 0 OR held_balance <> 0;
+
+INSERT INTO ledger_postings (entry_id, account_id, amount, currency)
+SELECT e.id, a.id, -(w.balance - w.held_balance), w.currency
+FROM journal_entries e
+JOIN wallets w ON e.reference_id = w.id::text
+JOIN ledger_accounts a ON a.code = 'wallet:' || w.id || ':available'
+WHERE e.kind = 'opening_balance' AND w.balance - w.held_balance <> 0
+UNION ALL
+SELECT e.id, a.id, -w.held_balance, w.currency
+FROM journal_entries e
+JOIN wallets w ON e.reference_id = w.id::text
+JOIN ledger_accounts a ON a.code = 'wallet:' || w.id || ':held'
+WHERE e.kind = 'opening_balance' AND w.held_balance <> 0
+UNION ALL
+SELECT e.id, a.id, w.balance, w.currency
+FROM journal_entries e
+JOIN wallets w ON e.reference_id = w.id::text
+JOIN ledger_accounts a ON a.code = 'platform:opening:' || w.currency
+WHERE e.kind = 'opening_balance' AND w.balance <> 0;
+
+UPDATE ledger_accounts a
+SET balance = CASE WHEN a.normal_balance = 'credit' THEN -p.total ELSE p.total END
+FROM (SELECT account_id, SUM(amount) AS total FROM ledger_postings GROUP BY account_id) p
+WHERE p.account_id = a.id;
@@ -0,0 +1,4 @@
+ALTER TABLE laundry_orders DROP COLUMN IF EXISTS version;
+ALTER TABLE service_orders DROP COLUMN IF EXISTS version;
+ALTER TABLE wallet_holds DROP COLUMN IF EXISTS version;
+ALTER TABLE wallets DROP COLUMN IF EXISTS version;
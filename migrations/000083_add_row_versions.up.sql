@@ -0,0 +1,6 @@
+-- Row versions for optimistic locking: every update bumps version and only
+-- applies if the row is still at the version it was read with.
+ALTER TABLE wallets ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE wallet_holds ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE service_orders ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE laundry_orders ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 0;
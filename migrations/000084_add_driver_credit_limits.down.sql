@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS driver_debt_repayments;
+
+ALTER TABLE driver_profiles DROP COLUMN IF EXISTS credit_override_reason;
+ALTER TABLE driver_profiles DROP COLUMN IF EXISTS credit_override_until;
+ALTER TABLE driver_profiles DROP COLUMN IF EXISTS credit_limit_override;
+ALTER TABLE driver_profiles DROP COLUMN IF EXISTS credit_tier;
@@ -0,0 +1,20 @@
+ALTER TABLE driver_profiles ADD COLUMN IF NOT EXISTS credit_tier VARCHAR(30) NOT NULL DEFAULT 'standard';
+ALTER TABLE driver_profiles ADD COLUMN IF NOT EXISTS credit_limit_override DECIMAL(10,2);
+ALTER TABLE driver_profiles ADD COLUMN IF NOT EXISTS credit_override_until TIMESTAMP;
+ALTER TABLE driver_profiles ADD COLUMN IF NOT EXISTS credit_override_reason VARCHAR(255);
+
+CREATE TABLE IF NOT EXISTS driver_debt_repayments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    wallet_id UUID NOT NULL REFERENCES wallets(id) ON DELETE CASCADE,
+    transaction_id UUID NOT NULL REFERENCES wallet_transactions(id) ON DELETE CASCADE,
+    amount DECIMAL(12,2) NOT NULL,
+    debt_before DECIMAL(12,2) NOT NULL,
+    debt_after DECIMAL(12,2) NOT NULL,
+    source VARCHAR(50) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_driver_debt_repayments_transaction ON driver_debt_repayments (transaction_id);
+CREATE INDEX IF NOT EXISTS idx_driver_debt_repayments_user_created ON driver_debt_repayments (user_id, created_at DESC);
@@ -0,0 +1,9 @@
+DELETE FROM feature_flags WHERE key IN (
+    'rides.dispatch.distance_weight',
+    'rides.dispatch.acceptance_weight',
+    'rides.dispatch.cancellation_weight',
+    'rides.dispatch.rating_weight'
+);
+
+DROP INDEX IF EXISTS idx_rides_driver_accepted;
+DROP INDEX IF EXISTS idx_ride_requests_driver_sent;
@@ -0,0 +1,13 @@
+-- Acceptance and cancellation rates are recomputed from a driver's most
+-- recent ride requests and rides.
+CREATE INDEX IF NOT EXISTS idx_ride_requests_driver_sent ON ride_requests (driver_id, sent_at DESC);
+CREATE INDEX IF NOT EXISTS idx_rides_driver_accepted ON rides (driver_id, accepted_at DESC) WHERE accepted_at IS NOT NULL;
+
+-- The weights of the dispatch score ride matching orders drivers by,
+-- seeded with the built-in defaults so they show up in the admin API.
+INSERT INTO feature_flags (key, type, value, description) VALUES
+    ('rides.dispatch.distance_weight', 'number', '0.4', 'Weight of closeness to the pickup in the driver dispatch score. Set every rides.dispatch weight to 0 to contact drivers nearest first.'),
+    ('rides.dispatch.acceptance_weight', 'number', '0.25', 'Weight of the driver''s acceptance rate in the dispatch score.'),
+    ('rides.dispatch.cancellation_weight', 'number', '0.15', 'Weight of how rarely the driver cancels accepted rides in the dispatch score.'),
+    ('rides.dispatch.rating_weight', 'number', '0.2', 'Weight of the driver''s rating in the dispatch score.')
+ON CONFLICT (key) DO NOTHING;
@@ -0,0 +1,5 @@
+ALTER TABLE city_vehicle_types DROP COLUMN IF EXISTS offer_timeout_sec;
+
+ALTER TABLE cities
+    DROP COLUMN IF EXISTS dispatch_batch_size,
+    DROP COLUMN IF EXISTS dispatch_mode;
@@ -0,0 +1,9 @@
+ALTER TABLE cities
+    ADD COLUMN IF NOT EXISTS dispatch_mode VARCHAR(20)
+        CHECK (dispatch_mode IN ('sequential', 'batch', 'broadcast')),
+    ADD COLUMN IF NOT EXISTS dispatch_batch_size INT
+        CHECK (dispatch_batch_size > 0);
+
+ALTER TABLE city_vehicle_types
+    ADD COLUMN IF NOT EXISTS offer_timeout_sec INT
+        CHECK (offer_timeout_sec > 0);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS driver_dispatch_preferences (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    driver_id UUID NOT NULL REFERENCES driver_profiles(id) ON DELETE CASCADE,
+    auto_accept_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    auto_accept_max_pickup_km DECIMAL(5,2) CHECK (auto_accept_max_pickup_km > 0),
+    auto_accept_min_fare DECIMAL(10,2) CHECK (auto_accept_min_fare >= 0),
+    preferred_areas JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_driver_dispatch_preferences_driver ON driver_dispatch_preferences (driver_id);
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS referrals (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    referrer_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    referee_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code VARCHAR(20) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'rewarded', 'rejected', 'expired')),
+    rejection_reason VARCHAR(50),
+    device_id VARCHAR(255),
+    phone_hash VARCHAR(64),
+    qualify_by TIMESTAMP WITH TIME ZONE NOT NULL,
+    qualifying_type VARCHAR(20),
+    qualifying_id UUID,
+    referrer_reward DECIMAL(10,2) NOT NULL DEFAULT 0,
+    referee_reward DECIMAL(10,2) NOT NULL DEFAULT 0,
+    rewarded_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    CHECK (referrer_id <> referee_id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_referrals_referee ON referrals (referee_id);
+CREATE INDEX IF NOT EXISTS idx_referrals_referrer ON referrals (referrer_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_referrals_status ON referrals (status);
+CREATE INDEX IF NOT EXISTS idx_referrals_device ON referrals (device_id) WHERE device_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_referrals_phone_hash ON referrals (phone_hash) WHERE phone_hash IS NOT NULL;
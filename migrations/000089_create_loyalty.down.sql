@@ -0,0 +1,3 @@
+ALTER TABLE ride_fare_snapshots DROP COLUMN IF EXISTS loyalty_discount;
+DROP TABLE IF EXISTS loyalty_transactions;
+DROP TABLE IF EXISTS loyalty_accounts;
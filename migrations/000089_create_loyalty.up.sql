@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS loyalty_accounts (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    balance BIGINT NOT NULL DEFAULT 0 CHECK (balance >= 0),
+    lifetime_points BIGINT NOT NULL DEFAULT 0,
+    tier VARCHAR(20) NOT NULL DEFAULT 'bronze' CHECK (tier IN ('bronze', 'silver', 'gold')),
+    tier_points BIGINT NOT NULL DEFAULT 0,
+    tier_updated_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_loyalty_accounts_tier ON loyalty_accounts (tier, tier_updated_at) WHERE tier <> 'bronze';
+
+CREATE TABLE IF NOT EXISTS loyalty_transactions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('earn', 'redeem', 'expire')),
+    points BIGINT NOT NULL,
+    source_type VARCHAR(20),
+    source_id UUID,
+    spend DECIMAL(10,2) NOT NULL DEFAULT 0,
+    wallet_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_loyalty_transactions_user ON loyalty_transactions (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_loyalty_transactions_expiry ON loyalty_transactions (expires_at) WHERE type = 'earn';
+-- A ride or order earns points once, however often its completion is delivered.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_loyalty_transactions_source ON loyalty_transactions (type, source_type, source_id) WHERE source_id IS NOT NULL;
+
+ALTER TABLE ride_fare_snapshots ADD COLUMN IF NOT EXISTS loyalty_discount DECIMAL(10,2) DEFAULT 0;
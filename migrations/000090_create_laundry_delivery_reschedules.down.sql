@@ -0,0 +1,2 @@
+ALTER TABLE laundry_deliveries DROP COLUMN IF EXISTS failed_attempts;
+DROP TABLE IF EXISTS laundry_delivery_reschedules;
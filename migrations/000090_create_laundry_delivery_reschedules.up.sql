@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS laundry_delivery_reschedules (
+    id UUID PRIMARY KEY,
+    order_id UUID NOT NULL REFERENCES laundry_orders(id) ON DELETE CASCADE,
+    delivery_id UUID NOT NULL REFERENCES laundry_deliveries(id) ON DELETE CASCADE,
+    requested_by VARCHAR(20) NOT NULL,
+    requested_by_id UUID NOT NULL,
+    previous_scheduled_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    new_scheduled_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    reason TEXT,
+    failed_attempt BOOLEAN NOT NULL DEFAULT FALSE,
+    fee DECIMAL(10,2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_laundry_delivery_reschedules_order_id ON laundry_delivery_reschedules (order_id);
+
+ALTER TABLE laundry_deliveries ADD COLUMN IF NOT EXISTS failed_attempts INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS service_proofs;
+DROP TABLE IF EXISTS proof_requirements;
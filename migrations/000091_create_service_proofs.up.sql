@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS proof_requirements (
+    category_slug VARCHAR(255) PRIMARY KEY,
+    photo_before BOOLEAN NOT NULL DEFAULT FALSE,
+    photo_after BOOLEAN NOT NULL DEFAULT FALSE,
+    signature BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_by UUID,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS service_proofs (
+    id UUID PRIMARY KEY,
+    order_type VARCHAR(20) NOT NULL,
+    order_id UUID NOT NULL,
+    stage VARCHAR(20) NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    url VARCHAR(1000) NOT NULL,
+    signed_by VARCHAR(255),
+    note TEXT,
+    uploaded_by UUID NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_service_proofs_order ON service_proofs (order_type, order_id);
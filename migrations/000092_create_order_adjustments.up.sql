@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS order_adjustments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES service_orders(id) ON DELETE CASCADE,
+    provider_id UUID NOT NULL,
+    lines JSONB NOT NULL,
+    previous_total DECIMAL(10,2) NOT NULL,
+    new_total DECIMAL(10,2) NOT NULL,
+    difference DECIMAL(10,2) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    note TEXT,
+    customer_note TEXT,
+    responded_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_adjustments_order_id ON order_adjustments (order_id);
+CREATE INDEX IF NOT EXISTS idx_order_adjustments_status ON order_adjustments (status);
+-- At most one adjustment per order waits for the customer.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_order_adjustments_one_pending ON order_adjustments (order_id) WHERE status = 'pending';
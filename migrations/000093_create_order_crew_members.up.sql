@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS order_crew_members (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES service_orders(id) ON DELETE CASCADE,
+    provider_id UUID NOT NULL REFERENCES service_provider_profiles(id) ON DELETE CASCADE,
+    invited_by UUID NOT NULL,
+    payout_percent DECIMAL(5,2) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    responded_at TIMESTAMP WITH TIME ZONE,
+    checked_in_at TIMESTAMP WITH TIME ZONE,
+    payout_amount DECIMAL(10,2),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_crew_members_order_id ON order_crew_members (order_id);
+CREATE INDEX IF NOT EXISTS idx_order_crew_members_provider_id ON order_crew_members (provider_id, status);
+-- A provider holds at most one open seat on an order's crew.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_order_crew_members_one_open ON order_crew_members (order_id, provider_id) WHERE status IN ('invited', 'accepted');
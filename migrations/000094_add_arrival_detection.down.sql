@@ -0,0 +1,4 @@
+ALTER TABLE service_orders DROP COLUMN IF EXISTS arrival_method;
+ALTER TABLE service_orders DROP COLUMN IF EXISTS provider_arrived_at;
+
+ALTER TABLE rides DROP COLUMN IF EXISTS arrival_method;
@@ -0,0 +1,4 @@
+ALTER TABLE rides ADD COLUMN IF NOT EXISTS arrival_method VARCHAR(20);
+
+ALTER TABLE service_orders ADD COLUMN IF NOT EXISTS provider_arrived_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE service_orders ADD COLUMN IF NOT EXISTS arrival_method VARCHAR(20);
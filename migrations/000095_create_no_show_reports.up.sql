@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS no_show_reports (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    product VARCHAR(20) NOT NULL,
+    reference_id UUID NOT NULL,
+    reported_by UUID NOT NULL,
+    absent_party VARCHAR(20) NOT NULL,
+    note TEXT,
+    photo_urls TEXT[] NOT NULL DEFAULT '{}',
+    latitude DECIMAL(10,8),
+    longitude DECIMAL(11,8),
+    distance_meters DECIMAL(10,2),
+    waited_minutes INTEGER NOT NULL DEFAULT 0,
+    customer_fee DECIMAL(10,2) NOT NULL DEFAULT 0,
+    provider_penalty DECIMAL(10,2) NOT NULL DEFAULT 0,
+    refund_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    policy_name VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+-- A ride or order can only be closed as a no-show once.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_no_show_reports_reference ON no_show_reports (product, reference_id);
+CREATE INDEX IF NOT EXISTS idx_no_show_reports_reported_by ON no_show_reports (reported_by, created_at DESC);
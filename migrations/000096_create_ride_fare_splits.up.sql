@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS ride_fare_splits (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    ride_id UUID NOT NULL REFERENCES rides(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    invited_by UUID NOT NULL,
+    share_percent DECIMAL(5,2) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    hold_id UUID,
+    hold_amount DECIMAL(10,2),
+    charged_amount DECIMAL(10,2),
+    paid_by_organizer BOOLEAN NOT NULL DEFAULT FALSE,
+    responded_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_ride_fare_splits_ride_id ON ride_fare_splits (ride_id);
+CREATE INDEX IF NOT EXISTS idx_ride_fare_splits_user_id ON ride_fare_splits (user_id, status);
+-- A co-rider holds at most one open share of a ride's fare.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ride_fare_splits_one_open ON ride_fare_splits (ride_id, user_id) WHERE status IN ('invited', 'accepted');
@@ -0,0 +1,7 @@
+ALTER TABLE city_vehicle_types
+    DROP COLUMN IF EXISTS luggage_capacity,
+    DROP COLUMN IF EXISTS seat_capacity;
+
+ALTER TABLE cities DROP COLUMN IF EXISTS restrict_vehicle_types;
+
+ALTER TABLE vehicle_types DROP COLUMN IF EXISTS luggage_capacity;
@@ -0,0 +1,21 @@
+ALTER TABLE vehicle_types
+    ADD COLUMN IF NOT EXISTS luggage_capacity INT NOT NULL DEFAULT 0
+        CHECK (luggage_capacity >= 0);
+
+UPDATE vehicle_types SET luggage_capacity = CASE name
+    WHEN 'auto' THEN 1
+    WHEN 'cab_economy' THEN 2
+    WHEN 'cab_sedan' THEN 3
+    WHEN 'cab_premium' THEN 3
+    WHEN 'cab_xl' THEN 4
+    ELSE 0
+END;
+
+ALTER TABLE cities
+    ADD COLUMN IF NOT EXISTS restrict_vehicle_types BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE city_vehicle_types
+    ADD COLUMN IF NOT EXISTS seat_capacity INT
+        CHECK (seat_capacity > 0),
+    ADD COLUMN IF NOT EXISTS luggage_capacity INT
+        CHECK (luggage_capacity >= 0);
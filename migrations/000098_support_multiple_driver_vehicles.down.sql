@@ -0,0 +1,5 @@
+ALTER TABLE driver_profiles DROP COLUMN IF EXISTS active_vehicle_id;
+
+-- Fails while any driver still has more than one vehicle.
+DROP INDEX IF EXISTS idx_vehicles_driver_id;
+ALTER TABLE vehicles ADD CONSTRAINT vehicles_driver_id_key UNIQUE (driver_id);
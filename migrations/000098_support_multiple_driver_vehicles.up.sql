@@ -0,0 +1,13 @@
+ALTER TABLE vehicles DROP CONSTRAINT IF EXISTS vehicles_driver_id_key;
+CREATE INDEX IF NOT EXISTS idx_vehicles_driver_id ON vehicles (driver_id);
+
+ALTER TABLE driver_profiles
+    ADD COLUMN IF NOT EXISTS active_vehicle_id UUID
+        REFERENCES vehicles(id) ON DELETE SET NULL;
+
+UPDATE driver_profiles dp
+SET active_vehicle_id = v.id
+FROM vehicles v
+WHERE v.driver_id = dp.id
+  AND v.deleted_at IS NULL
+  AND dp.active_vehicle_id IS NULL;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS ops_task_watchers CASCADE;
+DROP TABLE IF EXISTS ops_task_comments CASCADE;
+DROP TABLE IF EXISTS ops_task_checklist_items CASCADE;
+DROP TABLE IF EXISTS ops_tasks CASCADE;
@@ -0,0 +1,74 @@
+-- Follow-up tasks and checklists worked by the operations team
+CREATE TABLE IF NOT EXISTS ops_tasks (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    title VARCHAR(200) NOT NULL,
+    description TEXT,
+    kind VARCHAR(30) NOT NULL DEFAULT 'general'
+        CHECK (kind IN ('general', 'dispute_follow_up', 'onboarding', 'investigation')),
+    entity_type VARCHAR(30)
+        CHECK (entity_type IN ('ride', 'service_order', 'provider', 'driver', 'user', 'dispute')),
+    entity_id UUID,
+    status VARCHAR(20) NOT NULL DEFAULT 'open'
+        CHECK (status IN ('open', 'in_progress', 'blocked', 'done', 'cancelled')),
+    priority VARCHAR(10) NOT NULL DEFAULT 'normal'
+        CHECK (priority IN ('low', 'normal', 'high', 'urgent')),
+    assignee_id UUID,
+    created_by UUID NOT NULL,
+    due_at TIMESTAMP,
+    completed_by UUID,
+    completed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    deleted_at TIMESTAMP,
+
+    CONSTRAINT chk_ops_tasks_entity CHECK ((entity_type IS NULL) = (entity_id IS NULL)),
+    CONSTRAINT fk_ops_tasks_assignee FOREIGN KEY (assignee_id) REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT fk_ops_tasks_created_by FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_ops_tasks_entity ON ops_tasks(entity_type, entity_id);
+CREATE INDEX idx_ops_tasks_assignee_id ON ops_tasks(assignee_id, status);
+CREATE INDEX idx_ops_tasks_status_due ON ops_tasks(status, due_at);
+CREATE INDEX idx_ops_tasks_deleted_at ON ops_tasks(deleted_at);
+
+CREATE TABLE IF NOT EXISTS ops_task_checklist_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    task_id UUID NOT NULL,
+    position INT NOT NULL DEFAULT 0,
+    title VARCHAR(200) NOT NULL,
+    done BOOLEAN NOT NULL DEFAULT FALSE,
+    done_by UUID,
+    done_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    CONSTRAINT fk_ops_task_checklist_items_task FOREIGN KEY (task_id) REFERENCES ops_tasks(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_ops_task_checklist_items_task_id ON ops_task_checklist_items(task_id, position);
+
+CREATE TABLE IF NOT EXISTS ops_task_comments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    task_id UUID NOT NULL,
+    author_id UUID NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    CONSTRAINT fk_ops_task_comments_task FOREIGN KEY (task_id) REFERENCES ops_tasks(id) ON DELETE CASCADE,
+    CONSTRAINT fk_ops_task_comments_author FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_ops_task_comments_task_id ON ops_task_comments(task_id, created_at);
+
+-- Admins told about changes to a task
+CREATE TABLE IF NOT EXISTS ops_task_watchers (
+    task_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (task_id, user_id),
+    CONSTRAINT fk_ops_task_watchers_task FOREIGN KEY (task_id) REFERENCES ops_tasks(id) ON DELETE CASCADE,
+    CONSTRAINT fk_ops_task_watchers_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_ops_task_watchers_user_id ON ops_task_watchers(user_id);
@@ -0,0 +1,19 @@
+-- Support admins acting as a user through a short-lived token
+CREATE TABLE IF NOT EXISTS impersonation_sessions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    admin_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    reason TEXT NOT NULL,
+    allow_writes BOOLEAN NOT NULL DEFAULT FALSE,
+    ip_address VARCHAR(45),
+    expires_at TIMESTAMP NOT NULL,
+    ended_at TIMESTAMP,
+    ended_by UUID,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    CONSTRAINT fk_impersonation_sessions_admin FOREIGN KEY (admin_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_impersonation_sessions_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_impersonation_sessions_admin_id ON impersonation_sessions(admin_id, created_at);
+CREATE INDEX idx_impersonation_sessions_user_id ON impersonation_sessions(user_id, created_at);
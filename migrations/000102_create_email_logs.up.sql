@@ -0,0 +1,25 @@
+-- Every transactional email sent, with the delivery status reported back by
+-- the provider
+CREATE TABLE IF NOT EXISTS email_logs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID,
+    to_email VARCHAR(255) NOT NULL,
+    template VARCHAR(50) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    provider VARCHAR(20) NOT NULL,
+    provider_message_id VARCHAR(255),
+    status VARCHAR(20) NOT NULL DEFAULT 'queued',
+    error TEXT,
+    reference_type VARCHAR(50),
+    reference_id VARCHAR(100),
+    sent_at TIMESTAMP,
+    status_updated_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    CONSTRAINT fk_email_logs_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE INDEX idx_email_logs_user_id ON email_logs(user_id, created_at);
+CREATE INDEX idx_email_logs_status ON email_logs(status, created_at);
+CREATE INDEX idx_email_logs_provider_message_id ON email_logs(provider_message_id);
@@ -0,0 +1,44 @@
+-- In-app announcements and banners targeted by audience, city and provider
+-- category, and which users have read them
+CREATE TABLE IF NOT EXISTS announcements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    title VARCHAR(200) NOT NULL,
+    body TEXT NOT NULL,
+    placement VARCHAR(20) NOT NULL DEFAULT 'feed'
+        CHECK (placement IN ('feed', 'banner')),
+    priority INT NOT NULL DEFAULT 0,
+    image_url VARCHAR(500),
+    action_url VARCHAR(500),
+    audience VARCHAR(20) NOT NULL DEFAULT 'all'
+        CHECK (audience IN ('all', 'riders', 'drivers', 'providers')),
+    city_id UUID,
+    service_category VARCHAR(100),
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP,
+    published_at TIMESTAMP,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    deleted_at TIMESTAMP,
+
+    CONSTRAINT chk_announcements_window CHECK (ends_at IS NULL OR ends_at > starts_at),
+    CONSTRAINT chk_announcements_category CHECK (service_category IS NULL OR audience = 'providers'),
+    CONSTRAINT fk_announcements_city FOREIGN KEY (city_id) REFERENCES cities(id) ON DELETE CASCADE,
+    CONSTRAINT fk_announcements_created_by FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_announcements_window ON announcements(starts_at, ends_at) WHERE deleted_at IS NULL;
+CREATE INDEX idx_announcements_unpublished ON announcements(starts_at) WHERE published_at IS NULL AND deleted_at IS NULL;
+CREATE INDEX idx_announcements_deleted_at ON announcements(deleted_at);
+
+CREATE TABLE IF NOT EXISTS announcement_reads (
+    announcement_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    read_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (announcement_id, user_id),
+    CONSTRAINT fk_announcement_reads_announcement FOREIGN KEY (announcement_id) REFERENCES announcements(id) ON DELETE CASCADE,
+    CONSTRAINT fk_announcement_reads_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_announcement_reads_user_id ON announcement_reads(user_id);
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS incentive_rewards CASCADE;
+DROP TABLE IF EXISTS incentive_rides CASCADE;
+DROP TABLE IF EXISTS incentive_progress CASCADE;
+DROP TABLE IF EXISTS incentive_campaigns CASCADE;
@@ -0,0 +1,96 @@
+-- Driver incentive campaigns: quests paying a bonus for a number of rides
+-- and boosts paying extra on each qualifying ride, with per-driver progress
+-- and the rewards owed
+CREATE TABLE IF NOT EXISTS incentive_campaigns (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(200) NOT NULL,
+    description TEXT,
+    type VARCHAR(10) NOT NULL CHECK (type IN ('quest', 'boost')),
+    status VARCHAR(20) NOT NULL DEFAULT 'active'
+        CHECK (status IN ('active', 'paused', 'cancelled')),
+    city_id UUID,
+    zone_name VARCHAR(255),
+    zone_lat DECIMAL(10,8),
+    zone_lon DECIMAL(11,8),
+    zone_radius_km DECIMAL(6,2),
+    vehicle_type_id UUID,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    daily_start VARCHAR(5),
+    daily_end VARCHAR(5),
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    target_rides INT NOT NULL DEFAULT 0,
+    reward_amount DECIMAL(10,2) NOT NULL,
+    max_rewards_per_driver INT NOT NULL DEFAULT 0,
+    currency VARCHAR(3) NOT NULL,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    deleted_at TIMESTAMP,
+
+    CONSTRAINT chk_incentive_campaigns_window CHECK (ends_at > starts_at),
+    CONSTRAINT chk_incentive_campaigns_reward CHECK (reward_amount > 0),
+    CONSTRAINT chk_incentive_campaigns_target CHECK (type <> 'quest' OR target_rides > 0),
+    CONSTRAINT chk_incentive_campaigns_zone CHECK ((zone_lat IS NULL) = (zone_lon IS NULL) AND (zone_lat IS NULL) = (zone_radius_km IS NULL)),
+    CONSTRAINT chk_incentive_campaigns_hours CHECK ((daily_start IS NULL) = (daily_end IS NULL)),
+    CONSTRAINT fk_incentive_campaigns_city FOREIGN KEY (city_id) REFERENCES cities(id) ON DELETE SET NULL,
+    CONSTRAINT fk_incentive_campaigns_vehicle_type FOREIGN KEY (vehicle_type_id) REFERENCES vehicle_types(id) ON DELETE SET NULL,
+    CONSTRAINT fk_incentive_campaigns_created_by FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_incentive_campaigns_window ON incentive_campaigns(status, starts_at, ends_at) WHERE deleted_at IS NULL;
+CREATE INDEX idx_incentive_campaigns_deleted_at ON incentive_campaigns(deleted_at);
+
+CREATE TABLE IF NOT EXISTS incentive_progress (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    campaign_id UUID NOT NULL,
+    driver_id UUID NOT NULL,
+    ride_count INT NOT NULL DEFAULT 0,
+    reward_count INT NOT NULL DEFAULT 0,
+    earned_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    completed_at TIMESTAMP,
+    last_ride_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    CONSTRAINT uq_incentive_progress_driver UNIQUE (campaign_id, driver_id),
+    CONSTRAINT fk_incentive_progress_campaign FOREIGN KEY (campaign_id) REFERENCES incentive_campaigns(id) ON DELETE CASCADE,
+    CONSTRAINT fk_incentive_progress_driver FOREIGN KEY (driver_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_incentive_progress_driver_id ON incentive_progress(driver_id);
+
+CREATE TABLE IF NOT EXISTS incentive_rides (
+    campaign_id UUID NOT NULL,
+    ride_id UUID NOT NULL,
+    driver_id UUID NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    PRIMARY KEY (campaign_id, ride_id),
+    CONSTRAINT fk_incentive_rides_campaign FOREIGN KEY (campaign_id) REFERENCES incentive_campaigns(id) ON DELETE CASCADE,
+    CONSTRAINT fk_incentive_rides_driver FOREIGN KEY (driver_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_incentive_rides_driver_id ON incentive_rides(campaign_id, driver_id);
+
+CREATE TABLE IF NOT EXISTS incentive_rewards (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    campaign_id UUID NOT NULL,
+    driver_id UUID NOT NULL,
+    ride_id UUID,
+    amount DECIMAL(10,2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'processing', 'paid')),
+    claimed_at TIMESTAMP,
+    wallet_txn_id UUID,
+    paid_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    CONSTRAINT fk_incentive_rewards_campaign FOREIGN KEY (campaign_id) REFERENCES incentive_campaigns(id) ON DELETE CASCADE,
+    CONSTRAINT fk_incentive_rewards_driver FOREIGN KEY (driver_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_incentive_rewards_driver_id ON incentive_rewards(driver_id, created_at);
+CREATE INDEX idx_incentive_rewards_unpaid ON incentive_rewards(status, claimed_at) WHERE status <> 'paid';
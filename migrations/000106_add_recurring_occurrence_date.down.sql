@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS uq_service_orders_recurring_occurrence;
+ALTER TABLE service_orders DROP COLUMN IF EXISTS recurring_date;
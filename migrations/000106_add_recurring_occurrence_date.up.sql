@@ -0,0 +1,7 @@
+-- The occurrence a recurring order was booked for. It is not moved when the
+-- order is rescheduled, so each occurrence of a plan is booked at most once.
+ALTER TABLE service_orders ADD COLUMN IF NOT EXISTS recurring_date VARCHAR(10);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_service_orders_recurring_occurrence
+    ON service_orders (recurring_plan_id, recurring_date)
+    WHERE recurring_plan_id IS NOT NULL AND recurring_date IS NOT NULL;
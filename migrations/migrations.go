@@ -0,0 +1,12 @@
+// Package migrations embeds the versioned SQL migrations so the API and the
+// migrate command know which schema version they were built against.
+package migrations
+
+import "embed"
+
+// FS holds every file in this directory. Only NNNNNN_name.up.sql and
+// NNNNNN_name.down.sql files are treated as migrations; seed scripts such as
+// insert_surge_pricing_rules.sql are ignored.
+//
+//go:embed *.sql
+var FS embed.FS